@@ -0,0 +1,56 @@
+#!/bin/bash
+# CI entrypoint for the authorization benchmarks. Fails when allocs/op of a
+# BenchmarkAuthorize case grows more than BENCH_TOLERANCE percent over
+# pkg/authz/testdata/bench-baseline.txt, and keeps the CPU and memory profiles.
+
+set -euo pipefail
+
+cd "$(dirname "${BASH_SOURCE[0]}")/.."
+
+BENCH_DIR="${BENCH_DIR:-bench-authz}"
+BENCH_TOLERANCE="${BENCH_TOLERANCE:-10}"
+BASELINE=pkg/authz/testdata/bench-baseline.txt
+
+make bench-authz BENCH_DIR="${BENCH_DIR}"
+
+go tool pprof -top -nodecount=25 "${BENCH_DIR}/authz.test" "${BENCH_DIR}/cpu.pprof"
+go tool pprof -top -nodecount=25 -sample_index=alloc_space "${BENCH_DIR}/authz.test" "${BENCH_DIR}/mem.pprof"
+
+if [[ -n "${ARTIFACT_DIR:-}" ]]; then
+    cp -r "${BENCH_DIR}" "${ARTIFACT_DIR}/" 2>/dev/null || true
+fi
+
+# Benchmark lines end "... <n> allocs/op"; strip the -GOMAXPROCS suffix from
+# the name so they line up with the baseline
+awk -v tolerance="${BENCH_TOLERANCE}" '
+    FNR == NR {
+        if ($0 !~ /^#/ && NF == 2) baseline[$1] = $2
+        next
+    }
+    /allocs\/op$/ {
+        name = $1
+        sub(/-[0-9]+$/, "", name)
+        got = $(NF - 1)
+        if (!(name in baseline)) {
+            printf "%s: %d allocs/op (no baseline)\n", name, got
+            next
+        }
+        seen[name] = 1
+        limit = baseline[name] * (1 + tolerance / 100)
+        if (got > limit) {
+            printf "FAIL %s: %d allocs/op, baseline %d\n", name, got, baseline[name]
+            failed = 1
+        } else {
+            printf "ok   %s: %d allocs/op, baseline %d\n", name, got, baseline[name]
+        }
+    }
+    END {
+        for (name in baseline) {
+            if (!(name in seen)) {
+                printf "FAIL %s: in baseline but not run\n", name
+                failed = 1
+            }
+        }
+        exit failed
+    }
+' "${BASELINE}" "${BENCH_DIR}/bench.txt"
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// checkConfigCmd takes the serve flags, which are added to it once they are
+// all registered
+var checkConfigCmd = &cobra.Command{
+	Use:   "check-config",
+	Short: "Validate the serve configuration without starting the server",
+	Long: "Builds the configuration serve would run with from the same flags, config file and environment variables and " +
+		"reports every problem found at once. Exits non-zero when the configuration is invalid.",
+	RunE:         runCheckConfig,
+	SilenceUsage: true,
+}
+
+func runCheckConfig(cmd *cobra.Command, args []string) error {
+	// Only errors are worth logging, and away from the report
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	if _, err := newConfigReloader(cmd.Flags()); err != nil {
+		return err
+	}
+	if _, err := serveConfig(logger); err != nil {
+		return err
+	}
+	fmt.Fprintln(cmd.OutOrStdout(), "configuration is valid")
+	return nil
+}
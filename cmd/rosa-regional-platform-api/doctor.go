@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/doctor"
+)
+
+var (
+	doctorTimeout     time.Duration
+	doctorPolicyStore string
+	doctorNoColor     bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check every dependency with real calls and report which work",
+	Long: "Exercises each dependency the API relies on: writes, reads back and deletes a canary item in DynamoDB, " +
+		"looks up a policy store in Amazon Verified Permissions, lists Maestro consumers and connects to Maestro over gRPC. " +
+		"Exits non-zero when any check fails.",
+	RunE: runDoctor,
+}
+
+func init() {
+	doctorCmd.Flags().StringVar(&maestroURL, "maestro-url", "http://maestro:8000", "Maestro service base URL")
+	doctorCmd.Flags().StringVar(&maestroGRPCURL, "maestro-grpc-url", "maestro-grpc.maestro-server:8090", "Maestro gRPC service base URL")
+	doctorCmd.Flags().StringVar(&dynamodbRegion, "dynamodb-region", "", "AWS region for DynamoDB and AVP (defaults to us-east-1)")
+	doctorCmd.Flags().StringVar(&dynamodbPrefix, "dynamodb-prefix", "rosa", "Prefix for DynamoDB table names")
+	doctorCmd.Flags().StringVar(&doctorPolicyStore, "policy-store-id", "", "AVP policy store to look up (defaults to the first found among the accounts)")
+	doctorCmd.Flags().DurationVar(&doctorTimeout, "timeout", 10*time.Second, "Timeout for each check")
+	doctorCmd.Flags().BoolVar(&doctorNoColor, "no-color", false, "Do not color the report (also disabled by NO_COLOR or when stdout is not a terminal)")
+
+	rootCmd.AddCommand(doctorCmd)
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	// Only failures of the clients themselves are worth logging, and away
+	// from the report
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	cfg := config.NewConfig()
+	if dynamodbRegion != "" {
+		cfg.Authz.AWSRegion = dynamodbRegion
+	}
+	cfg.Authz.AccountsTableName = dynamodbPrefix + "-authz-accounts"
+	cfg.Authz.DynamoDBEndpoint = os.Getenv("DYNAMODB_ENDPOINT")
+	cfg.Authz.CedarAgentEndpoint = os.Getenv("CEDAR_AGENT_ENDPOINT")
+	cfg.Maestro.BaseURL = maestroURL
+	cfg.Maestro.GRPCBaseURL = maestroGRPCURL
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	checks := []doctor.Check{
+		doctor.Maestro(maestro.NewClient(cfg.Maestro, logger)),
+		doctor.GRPC(cfg.Maestro.GRPCBaseURL),
+	}
+
+	dynamoClient, err := client.NewDynamoDBClient(ctx, cfg.Authz.AWSRegion, cfg.Authz.DynamoDBEndpoint)
+	if err != nil {
+		checks = append(checks, failedCheck("dynamodb", err))
+	} else {
+		checks = append(checks, doctor.DynamoDBCanary(cfg.Authz.AccountsTableName, "accountId", dynamoClient))
+	}
+
+	var avpClient client.AVPClient
+	var avpErr error
+	if cfg.Authz.CedarAgentEndpoint != "" {
+		avpClient = client.NewMockAVPClient(cfg.Authz.CedarAgentEndpoint, logger)
+	} else {
+		avpClient, avpErr = client.NewAVPClient(ctx, cfg.Authz.AWSRegion)
+	}
+	switch {
+	case avpErr != nil:
+		checks = append(checks, failedCheck("avp", avpErr))
+	case dynamoClient != nil:
+		accounts := store.NewAccountStore(cfg.Authz.AccountsTableName, dynamoClient, logger)
+		checks = append(checks, doctor.PolicyStore(doctorPolicyStore, accounts, avpClient))
+	default:
+		checks = append(checks, doctor.PolicyStore(doctorPolicyStore, nil, avpClient))
+	}
+
+	results := doctor.Run(ctx, checks, doctorTimeout)
+	doctor.Report(cmd.OutOrStdout(), results, useColor())
+	if doctor.Failed(results) {
+		return fmt.Errorf("one or more dependency checks failed")
+	}
+	return nil
+}
+
+// failedCheck reports a dependency whose client could not be created
+func failedCheck(name string, err error) doctor.Check {
+	return doctor.Check{
+		Name: name,
+		Run: func(ctx context.Context) (string, error) {
+			return "", fmt.Errorf("failed to create client: %w", err)
+		},
+	}
+}
+
+// useColor reports whether the report is written to a terminal that has not
+// opted out of color
+func useColor() bool {
+	if doctorNoColor || os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	info, err := os.Stdout.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
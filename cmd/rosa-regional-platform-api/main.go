@@ -4,15 +4,29 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net/url"
 	"os"
 	"os/signal"
+	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/api/resource"
 
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/bootstrap"
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
 	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/errtrack"
+	"github.com/openshift/rosa-regional-platform-api/pkg/loadshed"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/opensearch"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+	"github.com/openshift/rosa-regional-platform-api/pkg/runtimetune"
+	"github.com/openshift/rosa-regional-platform-api/pkg/secretsource"
 	"github.com/openshift/rosa-regional-platform-api/pkg/server"
 )
 
@@ -22,13 +36,185 @@ var (
 	logFormat       string
 	maestroURL      string
 	maestroGRPCURL  string
+	maestroVersion  time.Duration
+	maestroSecURL   string
+	maestroSecGRPC  string
+	maestroActive   string
+	failoverEvery   time.Duration
+	failoverAfter   int
 	hyperfleetURL   string
 	allowedAccounts string
 	dynamodbRegion  string
 	dynamodbPrefix  string
 	apiPort         int
+	basePath        string
+	externalURL     string
+	serviceRegion   string
+	devMode         bool
 	healthPort      int
 	metricsPort     int
+	profile         string
+	mgmtRegistry    bool
+	mgmtCacheTTL    time.Duration
+	mgmtCacheStale  time.Duration
+	mgmtDrain       bool
+	mgmtCaps        bool
+	agentCompat     string
+	canaryCluster   string
+	canaryNamespace string
+	canaryInterval  time.Duration
+	canaryTimeout   time.Duration
+	meteringSink    string
+	meteringTarget  string
+	meteringBatch   int
+	meteringFlush   time.Duration
+	meteringBuffer  int
+	kafkaBrokers    string
+	kafkaTopics     string
+	kafkaTopic      string
+	eventSource     string
+	eventEncoding   string
+	schemaRegistry  string
+	kafkaTLS        bool
+	kafkaMSKIAM     bool
+	eventBuffer     int
+	captureFile     string
+	captureAccts    string
+	captureBodies   string
+	mirrorTarget    string
+	mirrorPercent   float64
+	mirrorDryRuns   bool
+	mirrorTimeout   time.Duration
+	mirrorInFlight  int
+	mgmtDrainWait   time.Duration
+	rbSummaryTTL    time.Duration
+	notifications   bool
+	notifySender    string
+	leaderElection  bool
+	leaseDuration   time.Duration
+	rateLimit       int
+	rateWindow      time.Duration
+	rateBackend     string
+	defaultPlan     string
+	planCacheTTL    time.Duration
+	cacheBackend    string
+	redisAddrs      string
+	redisCluster    bool
+	redisUsername   string
+	redisTLS        bool
+	authzStreams    bool
+	streamsPoll     time.Duration
+	bootstrapFile   string
+	workKMSKeyID    string
+	workSecretRefs  bool
+	workSecretPlat  string
+	workMetadata    bool
+	workSchedules   bool
+	workSchedInt    time.Duration
+	workHistory     bool
+	historyEvery    time.Duration
+	historyKeep     time.Duration
+	workMaxManifest int
+	workMaxBytes    int
+	workMaxMessage  int
+	workMaxChunks   int
+	workMaxInFlight int
+	workMaxQueued   int
+	workClusterRate int
+	workClusterWin  time.Duration
+	workChartRegs   string
+	workChartBytes  int
+	workChartPull   time.Duration
+	workPayloadRegs string
+	workPayloadBkts string
+	workPayloadMax  int64
+	workPayloadTime time.Duration
+	workPayloadSign string
+	workPayloadRoot string
+	requiredCluster string
+	requiredWork    string
+	statusErrRate   float64
+	statusLag       time.Duration
+	backupBucket    string
+	backupInterval  time.Duration
+	backupRetention time.Duration
+	purgeKey        string
+	opResultTTL     time.Duration
+	identityRC      bool
+	identityAcctHdr string
+	identityARNHdr  string
+	identityAcctKey string
+	identityARNKey  string
+	identitySecHdr  string
+	replayWindow    time.Duration
+	replayCacheSize int
+	trustedProxies  string
+	superAdmins     string
+	pageTenant      int
+	pageTenantMax   int
+	pagePlatform    int
+	pagePlatformMax int
+	pageRuns        int
+	pageRunsMax     int
+	pageAudit       int
+	pageAuditMax    int
+	degradedStart   bool
+	degradedMode    string
+	crossAccounts   string
+	maxMembers      int
+	memberPartners  string
+	patterns        bool
+	patternMatch    time.Duration
+	guardrailStore  string
+	waitVisible     bool
+	visibleTimeout  time.Duration
+	deletionRetain  time.Duration
+	apiKeyCacheTTL  time.Duration
+	apiKeyRefresh   time.Duration
+	onboardWorkers  int
+	onboardQueue    int
+	onboardRate     float64
+	shadowMode      bool
+	bypassRules     string
+	decisionStats   bool
+	decisionFlush   time.Duration
+	decisionRetain  time.Duration
+	decisionAudit   bool
+	auditRetain     time.Duration
+	auditQueryRate  int
+	auditOSURL      string
+	auditOSIndex    string
+	auditOSRollover string
+	auditOSService  string
+	groupTagsCtx    bool
+	approvalOps     string
+	approvalExpiry  time.Duration
+	cedarNamespace  string
+	requestTimeout  time.Duration
+	warmupTimeout   time.Duration
+	authzBudget     time.Duration
+	slowDefault     time.Duration
+	slowTenant      time.Duration
+	slowPlatform    time.Duration
+	slowRuns        time.Duration
+	slowAuthz       time.Duration
+	sloPeriod       time.Duration
+	sloAvailability float64
+	sloLatency      float64
+	shedRules       string
+	shedQueueLoad   float64
+	shedComponents  string
+	topAccounts     int
+	accountRanking  time.Duration
+	sentryEnv       string
+	sentryLevel     string
+	secretRefresh   time.Duration
+	configFile      string
+	maxProcs        int
+	gcPercent       int
+	memoryLimit     string
+	memoryRatio     float64
+	memoryBallast   string
 )
 
 func main() {
@@ -56,17 +242,198 @@ func init() {
 	serveCmd.Flags().StringVar(&maestroURL, "maestro-url", "http://maestro:8000", "Maestro service base URL")
 	serveCmd.Flags().StringVar(&allowedAccounts, "allowed-accounts", "", "Comma-separated list of allowed AWS account IDs")
 	serveCmd.Flags().StringVar(&maestroGRPCURL, "maestro-grpc-url", "maestro-grpc.maestro-server:8090", "Maestro gRPC service base URL")
+	serveCmd.Flags().DurationVar(&maestroVersion, "maestro-version-check-interval", 5*time.Minute, "How often Maestro's API version and optional capabilities are detected (0 disables detection and the features gated on it)")
+	serveCmd.Flags().StringVar(&maestroSecURL, "maestro-secondary-url", "", "Base URL of a secondary Maestro calls can be switched to (empty configures none)")
+	serveCmd.Flags().StringVar(&maestroSecGRPC, "maestro-secondary-grpc-url", "", "gRPC address of the secondary Maestro")
+	serveCmd.Flags().StringVar(&maestroActive, "maestro-active-endpoint", "primary", "Maestro endpoint calls go to: primary or secondary")
+	serveCmd.Flags().DurationVar(&failoverEvery, "maestro-failover-check-interval", 10*time.Second, "How often each Maestro endpoint's health is checked (0 disables automatic failover)")
+	serveCmd.Flags().IntVar(&failoverAfter, "maestro-failover-threshold", 3, "Consecutive failed checks of the active Maestro endpoint that switch calls to a healthy one")
 	serveCmd.Flags().StringVar(&hyperfleetURL, "hyperfleet-url", "http://hyperfleet-api.hyperfleet-system:8000", "Hyperfleet service base URL")
 	serveCmd.Flags().StringVar(&dynamodbRegion, "dynamodb-region", "", "AWS region for DynamoDB (defaults to us-east-1)")
 	serveCmd.Flags().StringVar(&dynamodbPrefix, "dynamodb-prefix", "rosa", "Prefix for DynamoDB table names (default: rosa)")
 	serveCmd.Flags().IntVar(&apiPort, "api-port", 8000, "API server port")
+	serveCmd.Flags().StringVar(&basePath, "base-path", "", "External path prefix the API is served under, such as an API Gateway stage (/prod); stripped before routing and included in hrefs")
+	serveCmd.Flags().StringVar(&serviceRegion, "region", "", "AWS region this deployment serves; accounts pinned to another region may not submit work through it (empty skips the check)")
+	serveCmd.Flags().StringVar(&externalURL, "external-url", "", "Absolute URL clients reach the API at, including any stage or custom domain path (https://api.example.com); makes generated hrefs and page links absolute")
+	serveCmd.Flags().BoolVar(&devMode, "dev", false, "Local development mode: take caller identity from basic auth or the accountId/callerArn query parameters (never enable in a deployment)")
 	serveCmd.Flags().IntVar(&healthPort, "health-port", 8080, "Health check server port")
 	serveCmd.Flags().IntVar(&metricsPort, "metrics-port", 9090, "Metrics server port")
+	serveCmd.Flags().StringVar(&profile, "profile", config.ProfileAll, "Route set to serve (all, frontend, platform)")
+	serveCmd.Flags().StringVar(&workKMSKeyID, "work-kms-key-id", "", "KMS key for optional envelope encryption of Secret manifests in work requests")
+	serveCmd.Flags().BoolVar(&workSecretRefs, "work-secret-refs", false, "Resolve {{resolve:secretsmanager|ssm:<arn>}} placeholders in work requests server-side")
+	serveCmd.Flags().StringVar(&workSecretPlat, "work-secret-refs-platform-prefixes", "", "Comma-separated ARN prefixes of the platform's own secrets and parameters, the only references privileged accounts may resolve outside their account")
+	serveCmd.Flags().BoolVar(&workMetadata, "work-metadata-store", false, "Record submitted works in the work metadata table and deduplicate identical submissions")
+	serveCmd.Flags().BoolVar(&workSchedules, "work-schedules", false, "Accept scheduled work requests and run the work scheduler, which authorizes each run again as the caller who scheduled it")
+	serveCmd.Flags().DurationVar(&workSchedInt, "work-schedule-interval", 30*time.Second, "How often the work scheduler submits due schedules")
+	serveCmd.Flags().BoolVar(&workHistory, "work-status-history", false, "Record condition transitions of submitted works and serve GET /api/v0/work/{id}/history (requires --work-metadata-store)")
+	serveCmd.Flags().DurationVar(&historyEvery, "work-status-history-interval", 30*time.Second, "How often the work status history recorder polls Maestro")
+	serveCmd.Flags().DurationVar(&historyKeep, "work-status-history-retention", 90*24*time.Hour, "How long work condition transitions are kept (0 keeps them forever)")
+	serveCmd.Flags().IntVar(&workMaxManifest, "work-max-manifests", 500, "Maximum manifests per work request (0 disables the limit)")
+	serveCmd.Flags().IntVar(&workMaxBytes, "work-max-payload-bytes", 128*1024, "Maximum encoded ManifestWork size in bytes (0 disables the limit)")
+	serveCmd.Flags().IntVar(&workMaxMessage, "work-max-message-bytes", 128*1024, "Transport message size limit for the work CloudEvent pre-flight check (0 disables the check)")
+	serveCmd.Flags().IntVar(&workMaxChunks, "work-max-chunks", 16, "Maximum ManifestWorks a chunked work request may be split into (0 disables the limit)")
+	serveCmd.Flags().IntVar(&workMaxInFlight, "work-max-concurrent", 0, "Maximum concurrent work submissions to Maestro; the rest wait by priority class (0 disables the limit)")
+	serveCmd.Flags().IntVar(&workMaxQueued, "work-max-queued", 100, "Maximum work submissions waiting for a slot before new ones are rejected (0 leaves it unbounded)")
+	serveCmd.Flags().IntVar(&workClusterRate, "work-cluster-rate-limit", 0, "Maximum work submissions to any one management cluster per --work-cluster-rate-window, per replica (0 disables the limit)")
+	serveCmd.Flags().DurationVar(&workClusterWin, "work-cluster-rate-window", time.Minute, "Window of the per-management-cluster work submission rate limit")
+	serveCmd.Flags().StringVar(&workChartRegs, "work-chart-registries", "", "Comma-separated OCI registry hosts Helm charts in work requests may be pulled from (empty disables chart rendering)")
+	serveCmd.Flags().IntVar(&workChartBytes, "work-chart-max-bytes", 1024*1024, "Maximum size of a Helm chart archive in bytes (0 disables the limit)")
+	serveCmd.Flags().DurationVar(&workChartPull, "work-chart-pull-timeout", 30*time.Second, "Timeout for pulling a Helm chart from its registry")
+	serveCmd.Flags().StringVar(&workPayloadRegs, "work-payload-registries", "", "Comma-separated OCI registry hosts work payloads may be fetched from by reference")
+	serveCmd.Flags().StringVar(&workPayloadBkts, "work-payload-buckets", "", "Comma-separated S3 buckets work payloads may be fetched from by reference")
+	serveCmd.Flags().Int64Var(&workPayloadMax, "work-payload-max-bytes", 16*1024*1024, "Maximum size of a work payload fetched by reference in bytes (0 disables the limit)")
+	serveCmd.Flags().DurationVar(&workPayloadTime, "work-payload-fetch-timeout", 30*time.Second, "Timeout for fetching a work payload from an OCI registry")
+	serveCmd.Flags().StringVar(&workPayloadSign, "work-payload-signers", "", "Comma-separated issuer=subject-regexp identities work payloads referenced from OCI registries must be cosign-signed by (empty disables signature verification)")
+	serveCmd.Flags().StringVar(&workPayloadRoot, "work-payload-trusted-root", "", "Path to the Sigstore trusted root (trusted_root.json) payload signatures are verified against")
+	serveCmd.Flags().StringVar(&requiredCluster, "required-cluster-tags", "", "Comma-separated tag keys every cluster create request must carry as request tags")
+	serveCmd.Flags().StringVar(&requiredWork, "required-work-tags", "", "Comma-separated tag keys every work create request must carry as request tags")
+	serveCmd.Flags().Float64Var(&statusErrRate, "status-error-rate-threshold", 0.05, "5xx response fraction above which /api/v0/status reports the region degraded")
+	serveCmd.Flags().DurationVar(&statusLag, "status-delivery-lag-threshold", time.Minute, "p95 work delivery lag above which /api/v0/status reports the region degraded")
+	serveCmd.Flags().StringVar(&canaryCluster, "canary-cluster", "", "Management cluster the delivery canary sends its ManifestWorks to (empty disables the canary)")
+	serveCmd.Flags().StringVar(&canaryNamespace, "canary-namespace", "default", "Namespace on the canary cluster the canary ConfigMap is created in")
+	serveCmd.Flags().DurationVar(&canaryInterval, "canary-interval", 5*time.Minute, "How often the delivery canary probes the work delivery path")
+	serveCmd.Flags().DurationVar(&canaryTimeout, "canary-timeout", 2*time.Minute, "How long a delivery canary probe waits for its work to be applied")
+	serveCmd.Flags().StringVar(&meteringSink, "metering-sink", "", "Where metering records of billable API calls are sent: file, sqs or kinesis (empty disables metering)")
+	serveCmd.Flags().StringVar(&meteringTarget, "metering-target", "", "Metering file path, SQS queue URL or Kinesis stream name")
+	serveCmd.Flags().IntVar(&meteringBatch, "metering-batch-size", 100, "Most metering records sent to the sink at once")
+	serveCmd.Flags().DurationVar(&meteringFlush, "metering-flush-interval", 5*time.Second, "Longest a metering record waits to be sent")
+	serveCmd.Flags().IntVar(&meteringBuffer, "metering-buffer-size", 10000, "Metering records that may wait for delivery before new ones are dropped")
+	serveCmd.Flags().StringVar(&kafkaBrokers, "events-kafka-brokers", "", "Comma-separated Kafka brokers platform events are published to (empty disables events)")
+	serveCmd.Flags().StringVar(&kafkaTopics, "events-kafka-topics", "", "Comma-separated type=topic rules routing events to topics, such as work.*=rosa.work,authz.changed=rosa.authz")
+	serveCmd.Flags().StringVar(&kafkaTopic, "events-kafka-default-topic", "rosa.platform.events", "Topic of the events no rule routes (empty drops them)")
+	serveCmd.Flags().StringVar(&eventSource, "events-source", "rosa-regional-platform-api", "CloudEvents source of published events")
+	serveCmd.Flags().StringVar(&eventEncoding, "events-encoding", "json", "Encoding of published events: json or avro")
+	serveCmd.Flags().StringVar(&schemaRegistry, "events-schema-registry-url", "", "Schema registry the events' schema is registered with; values then carry its schema ID (required with avro)")
+	serveCmd.Flags().BoolVar(&kafkaTLS, "events-kafka-tls", false, "Connect to the Kafka brokers over TLS")
+	serveCmd.Flags().BoolVar(&kafkaMSKIAM, "events-kafka-msk-iam", false, "Authenticate to Amazon MSK with IAM, over TLS")
+	serveCmd.Flags().IntVar(&eventBuffer, "events-buffer-size", 10000, "Events that may wait for delivery before new ones are dropped")
+	serveCmd.Flags().StringVar(&captureFile, "capture-file", "", "File request envelopes are appended to for the replay command (empty disables capture)")
+	serveCmd.Flags().StringVar(&captureAccts, "capture-accounts", "", "Comma-separated account IDs whose requests are captured (empty captures all)")
+	serveCmd.Flags().StringVar(&captureBodies, "capture-body-accounts", "", "Comma-separated account IDs that opted in to having request bodies captured; other bodies are recorded as a SHA-256 hash")
+	serveCmd.Flags().StringVar(&mirrorTarget, "mirror-target", "", "Base URL of a secondary deployment a sample of requests is mirrored to, comparing its answers (empty disables mirroring)")
+	serveCmd.Flags().Float64Var(&mirrorPercent, "mirror-percent", 10, "Percent of reads (and dry-run writes with --mirror-dry-run-writes) mirrored to --mirror-target")
+	serveCmd.Flags().BoolVar(&mirrorDryRuns, "mirror-dry-run-writes", false, "Also mirror writes that are dry runs; other writes are never mirrored")
+	serveCmd.Flags().DurationVar(&mirrorTimeout, "mirror-timeout", 10*time.Second, "How long a mirrored request may take")
+	serveCmd.Flags().IntVar(&mirrorInFlight, "mirror-max-in-flight", 16, "Mirrored requests outstanding at once; more are dropped")
+	serveCmd.Flags().StringVar(&backupBucket, "policy-backup-bucket", "", "S3 bucket for scheduled AVP policy store backups (empty disables backups)")
+	serveCmd.Flags().DurationVar(&backupInterval, "policy-backup-interval", 6*time.Hour, "Interval between AVP policy store backups")
+	serveCmd.Flags().DurationVar(&backupRetention, "policy-backup-retention", 30*24*time.Hour, "How long AVP policy store backups are kept; the newest backup of each account is always kept (0 keeps all)")
+	serveCmd.Flags().DurationVar(&opResultTTL, "operation-result-ttl", time.Hour, "How long finished long-running operations are kept with their outcome (0 forgets them when they end)")
+	serveCmd.Flags().StringVar(&purgeKey, "account-purge-signing-key", "", "KMS key ID or alias signing account purge deletion reports (empty disables account purges)")
+	serveCmd.Flags().BoolVar(&identityRC, "identity-request-context", false, "Read caller identity from the API Gateway v2 request context header ("+middleware.HeaderRequestContext+")")
+	serveCmd.Flags().StringVar(&identityAcctHdr, "identity-account-id-header", middleware.HeaderAccountID, "Header carrying the caller account ID (e.g. one injected by a Lambda authorizer)")
+	serveCmd.Flags().StringVar(&identityARNHdr, "identity-caller-arn-header", middleware.HeaderCallerARN, "Header carrying the caller ARN (e.g. one injected by a Lambda authorizer)")
+	serveCmd.Flags().StringVar(&identityAcctKey, "identity-authorizer-account-id-key", "accountId", "Lambda authorizer context key holding the caller account ID")
+	serveCmd.Flags().StringVar(&identityARNKey, "identity-authorizer-caller-arn-key", "callerArn", "Lambda authorizer context key holding the caller ARN")
+	serveCmd.Flags().StringVar(&identitySecHdr, "identity-secret-header", middleware.DefaultSharedSecretHeader, "Header carrying the gateway shared secret (secret read from IDENTITY_SHARED_SECRET)")
+	serveCmd.Flags().DurationVar(&replayWindow, "identity-replay-window", 0, "Require mutating requests to carry a timestamp within this long of now and an unused nonce ("+middleware.HeaderRequestTimestamp+", "+middleware.HeaderRequestNonce+"); 0 disables replay protection")
+	serveCmd.Flags().IntVar(&replayCacheSize, "identity-replay-cache-size", 100000, "Maximum number of request nonces each replica remembers per account for replay protection")
+	serveCmd.Flags().StringVar(&superAdmins, "identity-super-admins", "", "Comma-separated IAM user or role ARNs that may bootstrap the first privileged account with POST /api/v0/bootstrap/privileged_account")
+	serveCmd.Flags().StringVar(&trustedProxies, "trusted-proxies", "", "Comma-separated CIDRs identity headers are accepted from (empty accepts any peer)")
+	serveCmd.Flags().IntVar(&pageTenant, "tenant-page-size", 50, "Default page size for cluster and nodepool lists")
+	serveCmd.Flags().IntVar(&pageTenantMax, "tenant-max-page-size", 100, "Maximum page size for cluster and nodepool lists; larger requests are rejected")
+	serveCmd.Flags().IntVar(&pagePlatform, "platform-page-size", 100, "Default page size for management cluster and resource bundle lists")
+	serveCmd.Flags().IntVar(&pagePlatformMax, "platform-max-page-size", 100, "Maximum page size for management cluster and resource bundle lists; larger requests are rejected")
+	serveCmd.Flags().IntVar(&pageRuns, "trusted-action-page-size", 20, "Default page size for trusted action run lists")
+	serveCmd.Flags().IntVar(&pageRunsMax, "trusted-action-max-page-size", 100, "Maximum page size for trusted action run lists; larger requests are rejected")
+	serveCmd.Flags().IntVar(&pageAudit, "audit-page-size", 50, "Default page size for the trusted action audit log")
+	serveCmd.Flags().IntVar(&pageAuditMax, "audit-max-page-size", 200, "Maximum page size for the trusted action audit log; larger requests are rejected")
+	serveCmd.Flags().DurationVar(&warmupTimeout, "warmup-timeout", 30*time.Second, "Time bound on priming Maestro, DynamoDB and AVP connections and caches before readiness passes (0 is ready at once)")
+	serveCmd.Flags().DurationVar(&requestTimeout, "request-timeout", 30*time.Second, "Overall time budget for an API request (0 disables)")
+	serveCmd.Flags().DurationVar(&authzBudget, "authz-timeout-budget", 5*time.Second, "Share of the request budget reserved for authorization checks (AVP and DynamoDB); the remainder is left to Maestro")
+	serveCmd.Flags().DurationVar(&slowDefault, "slow-request-threshold", 2*time.Second, "Latency above which requests outside the route classes below are logged as slow (0 disables)")
+	serveCmd.Flags().DurationVar(&slowTenant, "tenant-slow-request-threshold", 2*time.Second, "Latency above which cluster and nodepool requests are logged as slow (0 disables)")
+	serveCmd.Flags().DurationVar(&slowPlatform, "platform-slow-request-threshold", 3*time.Second, "Latency above which management cluster, resource bundle and work requests are logged as slow (0 disables)")
+	serveCmd.Flags().DurationVar(&slowRuns, "trusted-action-slow-request-threshold", 5*time.Second, "Latency above which trusted action requests are logged as slow (0 disables)")
+	serveCmd.Flags().DurationVar(&slowAuthz, "authz-slow-request-threshold", time.Second, "Latency above which authz, accounts and admin requests are logged as slow (0 disables)")
+	serveCmd.Flags().DurationVar(&sloPeriod, "slo-period", 28*24*time.Hour, "Rolling window the SLO error budgets cover")
+	serveCmd.Flags().Float64Var(&sloAvailability, "slo-availability-objective", 0.999, "Target fraction of requests per route class answered without a 5xx")
+	serveCmd.Flags().Float64Var(&sloLatency, "slo-latency-objective", 0.99, "Target fraction of requests per route class answered within its slow-request threshold")
+	serveCmd.Flags().StringVar(&shedRules, "load-shed-priorities", loadshed.DefaultRules, "Comma-separated [METHOD ]route=priority rules (critical, normal, low); low-priority routes are shed with 503 while the server is degraded (empty disables shedding)")
+	serveCmd.Flags().Float64Var(&shedQueueLoad, "load-shed-queue-load", 2, "Work queue load, submissions per slot, at which low-priority requests are shed (0 ignores the queue)")
+	serveCmd.Flags().StringVar(&shedComponents, "load-shed-components", "delivery-canary", "Comma-separated health components whose failure sheds low-priority requests, besides authz")
+	serveCmd.Flags().IntVar(&topAccounts, "metrics-top-accounts", 20, "Number of busiest accounts labeled by account ID in per-account request metrics; the rest are counted as other (0 labels none)")
+	serveCmd.Flags().DurationVar(&accountRanking, "metrics-account-rank-interval", time.Minute, "How often the busiest accounts of per-account request metrics are re-ranked")
+	serveCmd.Flags().BoolVar(&degradedStart, "authz-degraded-start", false, "Start with authz marked unhealthy instead of failing when DynamoDB is unreachable at boot, retrying in the background")
+	serveCmd.Flags().StringVar(&degradedMode, "authz-degraded-mode", authz.DegradedDenyAll, "Handling of authz-protected routes while authz is unhealthy (deny-all, read-only)")
+	serveCmd.Flags().StringVar(&crossAccounts, "authz-cross-account-resource-accounts", "", "Comma-separated account IDs whose resource ARNs any caller may name in authorization requests")
+	serveCmd.Flags().IntVar(&maxMembers, "authz-max-group-members", 1000, "Most members a group may have (0 is no limit)")
+	serveCmd.Flags().StringVar(&memberPartners, "authz-member-partner-accounts", "", "Comma-separated account IDs whose principals may be added to groups of other accounts")
+	serveCmd.Flags().BoolVar(&patterns, "authz-principal-patterns", false, "Allow attaching policies to principal ARN patterns such as arn:aws:iam::123456789012:role/dev-*, matched against the principals seen in authorization checks")
+	serveCmd.Flags().DurationVar(&patternMatch, "authz-pattern-match-interval", 30*time.Second, "How often each replica adds the principals it saw to the pattern groups they match")
+	serveCmd.Flags().StringVar(&guardrailStore, "authz-guardrail-policy-store-id", "", "AVP policy store of platform guardrails evaluated before tenant policies for every caller (empty disables)")
+	serveCmd.Flags().BoolVar(&waitVisible, "authz-wait-for-visibility", false, "Make policy and attachment changes wait until authorization checks reflect them, as if every request passed ?wait=true")
+	serveCmd.Flags().DurationVar(&visibleTimeout, "authz-visibility-timeout", 5*time.Second, "Longest time a policy or attachment change waits to become visible before returning 202 Accepted")
+	serveCmd.Flags().StringVar(&cedarNamespace, "authz-cedar-namespace", schema.DefaultNamespace, "Cedar namespace of the entity and action types sent to AVP and of the schema put in new policy stores")
+	serveCmd.Flags().DurationVar(&deletionRetain, "authz-deletion-retention", 90*24*time.Hour, "How long tombstones of deleted policies, groups and attachments are kept for forensic review")
+	serveCmd.Flags().DurationVar(&apiKeyCacheTTL, "authz-api-key-cache-ttl", 5*time.Minute, "How long each replica caches an API key it resolved (0 reads the API keys table on every request made with a key)")
+	serveCmd.Flags().DurationVar(&apiKeyRefresh, "authz-api-key-revocation-refresh", 5*time.Second, "How often each replica evicts cached API keys revoked or given an expiry on any replica")
+	serveCmd.Flags().IntVar(&onboardWorkers, "authz-onboarding-workers", 4, "Workers onboarding the accounts enabled with ?async=true on each replica (0 turns asynchronous enabling off)")
+	serveCmd.Flags().IntVar(&onboardQueue, "authz-onboarding-queue-size", 10000, "Most accounts waiting for an onboarding worker on each replica")
+	serveCmd.Flags().Float64Var(&onboardRate, "authz-onboarding-rate", 5, "Most AVP calls per second the onboarding workers of each replica make")
+	serveCmd.Flags().StringVar(&bypassRules, "authz-bypass", "", "Comma-separated routes, as \"METHOD /path/template\" or \"/path/*\", that only privileged accounts may call and on which they skip authorization")
+	serveCmd.Flags().BoolVar(&shadowMode, "authz-shadow", false, "Enforce --allowed-accounts on the resource routes and only log and count the Cedar decisions, to measure their divergence before cutting over")
+	serveCmd.Flags().BoolVar(&decisionStats, "authz-decision-analytics", false, "Roll authorization decisions up into hourly per-account, per-action allow and deny counts, served by GET /api/v0/authz/analytics")
+	serveCmd.Flags().DurationVar(&decisionFlush, "authz-decision-flush-interval", time.Minute, "How often each replica adds the decisions it counted to the decision counts table")
+	serveCmd.Flags().DurationVar(&decisionRetain, "authz-decision-retention", 90*24*time.Hour, "How long hourly decision counts are kept")
+	serveCmd.Flags().BoolVar(&decisionAudit, "authz-decision-audit", false, "Write every policy-evaluated, organization forbid and admin bypass decision to the decision audit table, queried by GET /api/v0/audit")
+	serveCmd.Flags().DurationVar(&auditRetain, "authz-decision-audit-retention", 90*24*time.Hour, "How long audited decisions are kept")
+	serveCmd.Flags().IntVar(&auditQueryRate, "authz-audit-query-rate-limit", 30, "Audit queries each account may make per minute on each replica (0 is no limit)")
+	serveCmd.Flags().StringVar(&auditOSURL, "authz-audit-opensearch-endpoint", "", "OpenSearch or Elasticsearch endpoint that audited decisions are also indexed into (empty disables)")
+	serveCmd.Flags().StringVar(&auditOSIndex, "authz-audit-opensearch-index", "rosa-authz-decisions", "Prefix of the indices audited decisions are indexed into")
+	serveCmd.Flags().StringVar(&auditOSRollover, "authz-audit-opensearch-rollover", opensearch.RolloverDaily, "How often audited decisions move to a new index: daily, monthly or none")
+	serveCmd.Flags().StringVar(&auditOSService, "authz-audit-opensearch-aws-service", "", "Sign OpenSearch requests with SigV4 for this service: es for Amazon OpenSearch Service, aoss for OpenSearch Serverless (empty sends them unsigned)")
+	serveCmd.Flags().BoolVar(&groupTagsCtx, "authz-group-tags-context", false, "Add the tags of the caller's groups to the Cedar context as groupTags, a set of \"key=value\" strings")
+	serveCmd.Flags().StringVar(&approvalOps, "authz-approval-required", "", "Comma-separated operations a second admin must approve (DeletePolicy, RemoveAdmin, DisableAccount)")
+	serveCmd.Flags().DurationVar(&approvalExpiry, "authz-approval-expiry", 24*time.Hour, "How long a change waiting for approval can still be approved")
+	serveCmd.Flags().StringVar(&sentryEnv, "sentry-environment", "", "Environment tag for Sentry events (DSN read from SENTRY_DSN)")
+	serveCmd.Flags().StringVar(&configFile, configFileFlag, "", "YAML or JSON file of serve flag values, re-read on SIGHUP or POST /api/v0/admin/config/reload; command line flags win")
+	serveCmd.Flags().DurationVar(&secretRefresh, "secret-refresh-interval", 0, "How often secrets given as secretsmanager:// or ssm:// references are re-read (0 reads them only at startup)")
+	serveCmd.Flags().StringVar(&sentryLevel, "sentry-min-level", "error", "Lowest log level reported to Sentry (debug, info, warn, error)")
+	serveCmd.Flags().IntVar(&maxProcs, "gomaxprocs", 0, "GOMAXPROCS override (0 keeps the runtime default, which follows the container CPU limit)")
+	serveCmd.Flags().IntVar(&gcPercent, "gogc", 0, "GOGC override; -1 turns the collector off below the memory limit (0 keeps GOGC or the default of 100)")
+	serveCmd.Flags().StringVar(&memoryLimit, "memory-limit", "", "Soft memory limit as a quantity such as 900Mi (empty derives it from --memory-limit-ratio)")
+	serveCmd.Flags().Float64Var(&memoryRatio, "memory-limit-ratio", 0.9, "Fraction of the container memory limit used as the soft memory limit when neither --memory-limit nor GOMEMLIMIT is set (0 disables)")
+	serveCmd.Flags().StringVar(&memoryBallast, "memory-ballast", "", "Size of a heap ballast such as 256Mi; prefer a memory limit (empty disables)")
+	serveCmd.Flags().BoolVar(&mgmtRegistry, "management-cluster-registry", false, "Scope management cluster Get/List to the owning account via the registry table")
+	serveCmd.Flags().DurationVar(&mgmtCacheTTL, "management-cluster-list-cache-ttl", 30*time.Second, "How long the unscoped management cluster list is served from cache (0 disables)")
+	serveCmd.Flags().DurationVar(&mgmtCacheStale, "management-cluster-list-cache-stale", 5*time.Minute, "How long past the TTL a cached management cluster list is still served while it is refreshed")
+	serveCmd.Flags().BoolVar(&mgmtDrain, "management-cluster-drain-bundles", true, "Delete the resource bundles of a deregistered management cluster before its consumer (otherwise refuse while any remain)")
+	serveCmd.Flags().BoolVar(&mgmtCaps, "management-cluster-capabilities", false, "Reject works embedding manifests whose apiVersion/kind the target management cluster is not recorded to serve")
+	serveCmd.Flags().StringVar(&agentCompat, "work-agent-compatibility", "warn", "How works using features the target cluster's work agent is too old for are treated: off, warn (Warning header) or block (422)")
+	serveCmd.Flags().DurationVar(&mgmtDrainWait, "management-cluster-drain-timeout", 10*time.Minute, "How long a deregistration waits for Maestro to remove drained resource bundles")
+	serveCmd.Flags().DurationVar(&rbSummaryTTL, "resource-bundle-summary-cache-ttl", time.Minute, "How long a computed resource bundle summary is reused (0 computes it on every request)")
+	serveCmd.Flags().BoolVar(&notifications, "notifications", false, "Enable per-account notification settings via the notification settings table")
+	serveCmd.Flags().StringVar(&notifySender, "notifications-email-sender", "", "SES-verified From address for email notifications (empty disables the email channel)")
+	serveCmd.Flags().BoolVar(&leaderElection, "leader-election", false, "Run background workers on one replica at a time, elected through the leases table")
+	serveCmd.Flags().IntVar(&rateLimit, "rate-limit", 0, "Requests each account may make per --rate-limit-window (0 disables rate limiting)")
+	serveCmd.Flags().DurationVar(&rateWindow, "rate-limit-window", 10*time.Second, "Window the per-account rate limit applies to")
+	serveCmd.Flags().StringVar(&rateBackend, "rate-limit-backend", ratelimit.BackendLocal, "Where request counts are kept: local (per replica) or dynamodb (shared by all replicas, falling back to local)")
+	serveCmd.Flags().StringVar(&defaultPlan, "default-plan", plans.Standard, "Plan of accounts not given one: free, standard or premium")
+	serveCmd.Flags().DurationVar(&planCacheTTL, "plan-cache-ttl", time.Minute, "How long each replica caches an account's plan")
+	serveCmd.Flags().StringVar(&cacheBackend, "cache-backend", cache.BackendMemory, "Where caches are kept: memory (per replica) or redis (shared by all replicas)")
+	serveCmd.Flags().StringVar(&redisAddrs, "cache-redis-addrs", "", "Comma-separated Redis or ElastiCache addresses (host:port); password read from REDIS_PASSWORD")
+	serveCmd.Flags().BoolVar(&redisCluster, "cache-redis-cluster", false, "Use Redis cluster mode with a single configuration endpoint address")
+	serveCmd.Flags().StringVar(&redisUsername, "cache-redis-username", "", "Redis ACL username")
+	serveCmd.Flags().BoolVar(&redisTLS, "cache-redis-tls", false, "Connect to Redis over TLS")
+	serveCmd.Flags().BoolVar(&authzStreams, "authz-streams", false, "Consume the authz tables' DynamoDB streams to audit, invalidate caches and notify on every change")
+	serveCmd.Flags().DurationVar(&streamsPoll, "authz-streams-poll-interval", 5*time.Second, "How often each authz table's stream is read")
+	serveCmd.Flags().StringVar(&bootstrapFile, "bootstrap-file", "", "YAML or JSON manifest of accounts, admins and policies applied at startup while authz is enabled")
+	serveCmd.Flags().DurationVar(&leaseDuration, "leader-election-lease-duration", 15*time.Second, "How long a leadership lease lasts without renewal; leaders renew every third of it")
 
 	rootCmd.AddCommand(serveCmd)
+
+	checkConfigCmd.Flags().AddFlagSet(serveCmd.Flags())
+	rootCmd.AddCommand(checkConfigCmd)
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
+	// Settings from the config file, before anything reads the flags
+	reloader, err := newConfigReloader(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
 	// Create logger
 	logger := createLogger(logLevel, logFormat)
 
@@ -75,42 +442,161 @@ func runServe(cmd *cobra.Command, args []string) error {
 		"log_format", logFormat,
 	)
 
-	// Create config
+	cfg, err := serveConfig(logger)
+	if err != nil {
+		return err
+	}
+
+	// Error tracking: panics and log records at or above the minimum level
+	dsn, err := resolveStartupSecret(cfg, cfg.ErrorTracking.DSN, logger)
+	if err != nil {
+		return fmt.Errorf("failed to resolve SENTRY_DSN: %w", err)
+	}
+	if err := errtrack.Init(errtrack.Config{
+		DSN:         dsn,
+		Environment: cfg.ErrorTracking.Environment,
+	}); err != nil {
+		return err
+	}
+	if errtrack.Enabled() {
+		defer errtrack.Flush(2 * time.Second)
+		logger = slog.New(errtrack.NewHandler(logger.Handler(), parseLevel(cfg.ErrorTracking.MinLevel)))
+		logger.Info("error tracking enabled", "environment", cfg.ErrorTracking.Environment, "release", errtrack.Release())
+	}
+	// Upstream calls abandoned by disconnected clients are not server errors
+	logger = slog.New(middleware.NewCanceledLogHandler(logger.Handler()))
+
+	// Go runtime: GOMAXPROCS, GC target and soft memory limit
+	settings, err := runtimetune.Apply(runtimetune.Config{
+		MaxProcs:         cfg.Runtime.MaxProcs,
+		GCPercent:        cfg.Runtime.GCPercent,
+		MemoryLimit:      cfg.Runtime.MemoryLimit,
+		MemoryLimitRatio: cfg.Runtime.MemoryLimitRatio,
+		Ballast:          cfg.Runtime.Ballast,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to apply runtime settings: %w", err)
+	}
+	logger.Info("runtime settings", settings.LogArgs()...)
+
+	// Create server
+	srv, err := server.New(cfg, logger)
+	if err != nil {
+		return fmt.Errorf("failed to create server: %w", err)
+	}
+
+	// Setup signal handling
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if reloader != nil {
+		reloader.logger = logger
+		reloader.build = func() (*config.Config, error) {
+			// Startup already logged how the configuration was built
+			return serveConfig(slog.New(slog.DiscardHandler))
+		}
+		reloader.apply = func(ctx context.Context, cfg *config.Config, changed []string) map[string]error {
+			if slices.Contains(changed, config.SettingLogLevel) {
+				logLevelVar.Set(parseLevel(cfg.Logging.Level))
+			}
+			return srv.Reload(ctx, cfg, changed)
+		}
+		srv.SetReloader(reloader.Reload)
+	}
+	go reloadOnHangup(ctx, reloader, logger)
+
+	// Run server
+	logger.Info("server configuration",
+		"profile", cfg.Server.Profile,
+		"api_port", cfg.Server.APIPort,
+		"health_port", cfg.Server.HealthPort,
+		"metrics_port", cfg.Server.MetricsPort,
+		"maestro_url", cfg.Maestro.BaseURL,
+		"maestro_grpc_url", cfg.Maestro.GRPCBaseURL,
+		"hyperfleet_url", cfg.Hyperfleet.BaseURL,
+		"allowed_accounts_count", len(cfg.AllowedAccounts),
+	)
+
+	if err := srv.Run(ctx); err != nil {
+		return fmt.Errorf("server error: %w", err)
+	}
+
+	return nil
+}
+
+// serveConfig builds the configuration from the serve flags and environment
+// and validates it, reporting every problem found at once
+func serveConfig(logger *slog.Logger) (*config.Config, error) {
 	cfg := config.NewConfig()
 	cfg.Logging.Level = logLevel
 	cfg.Logging.Format = logFormat
-	cfg.Maestro.BaseURL = maestroURL
-	cfg.Maestro.GRPCBaseURL = maestroGRPCURL
+	cfg.ErrorTracking.DSN = os.Getenv("SENTRY_DSN")
+	cfg.ErrorTracking.Environment = sentryEnv
+	cfg.ErrorTracking.MinLevel = sentryLevel
+	cfg.Secrets.RefreshInterval = secretRefresh
 
-	// Validate Hyperfleet URL
-	parsedURL, err := url.ParseRequestURI(hyperfleetURL)
+	cfg.Runtime.MaxProcs = maxProcs
+	cfg.Runtime.GCPercent = gcPercent
+	cfg.Runtime.MemoryLimitRatio = memoryRatio
+	limit, err := parseBytes(memoryLimit)
 	if err != nil {
-		logger.Error("invalid hyperfleet URL", "url", hyperfleetURL, "error", err)
-		return fmt.Errorf("invalid hyperfleet URL: %w", err)
+		return nil, fmt.Errorf("invalid --memory-limit: %w", err)
 	}
-	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
-		logger.Error("hyperfleet URL must have http or https scheme", "url", hyperfleetURL, "scheme", parsedURL.Scheme)
-		return fmt.Errorf("hyperfleet URL must have http or https scheme, got: %s", parsedURL.Scheme)
+	cfg.Runtime.MemoryLimit = limit
+	ballast, err := parseBytes(memoryBallast)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --memory-ballast: %w", err)
 	}
+	cfg.Runtime.Ballast = ballast
+
+	cfg.Maestro.BaseURL = maestroURL
+	cfg.Maestro.GRPCBaseURL = maestroGRPCURL
+	cfg.Maestro.VersionCheckInterval = maestroVersion
+	cfg.Maestro.SecondaryBaseURL = maestroSecURL
+	cfg.Maestro.SecondaryGRPCBaseURL = maestroSecGRPC
+	cfg.Maestro.ActiveEndpoint = maestroActive
+	cfg.Maestro.FailoverCheckInterval = failoverEvery
+	cfg.Maestro.FailoverThreshold = failoverAfter
 	cfg.Hyperfleet.BaseURL = hyperfleetURL
 
-	cfg.AllowedAccounts = parseAllowedAccounts(allowedAccounts)
+	cfg.AllowedAccounts = parseCommaList(allowedAccounts)
 	cfg.Server.APIPort = apiPort
+	cfg.Server.BasePath = basePath
+	cfg.Server.ExternalURL = externalURL
+	cfg.Server.Region = serviceRegion
 	cfg.Server.HealthPort = healthPort
 	cfg.Server.MetricsPort = metricsPort
+	cfg.Server.WarmupTimeout = warmupTimeout
+	cfg.Server.RequestTimeout = requestTimeout
+	cfg.Server.AuthzBudget = authzBudget
+
+	// Caller identity sources
+	cfg.Identity.RequestContext = identityRC
+	cfg.Identity.AccountIDHeader = identityAcctHdr
+	cfg.Identity.CallerARNHeader = identityARNHdr
+	cfg.Identity.AuthorizerAccountIDKey = identityAcctKey
+	cfg.Identity.AuthorizerCallerARNKey = identityARNKey
+	cfg.Identity.SharedSecretHeader = identitySecHdr
+	cfg.Identity.SharedSecret = os.Getenv("IDENTITY_SHARED_SECRET")
+	cfg.Identity.ReplayWindow = replayWindow
+	cfg.Identity.ReplayCacheSize = replayCacheSize
+	if trustedProxies != "" {
+		cfg.Identity.TrustedProxies = strings.Split(trustedProxies, ",")
+	}
+	cfg.Identity.SuperAdmins = parseCommaList(superAdmins)
+	cfg.Server.Dev = devMode
+	cfg.Server.Profile = profile
 
 	// Set DynamoDB region from flag if provided
 	if dynamodbRegion != "" {
 		cfg.Authz.AWSRegion = dynamodbRegion
 		logger.Info("using DynamoDB region from flag", "region", dynamodbRegion)
 	}
+	cfg.Secrets.AWSRegion = cfg.Authz.AWSRegion
 
 	// Set DynamoDB table name prefix
 	if dynamodbPrefix != "" {
-		cfg.Authz.AccountsTableName = dynamodbPrefix + "-authz-accounts"
-		cfg.Authz.AdminsTableName = dynamodbPrefix + "-authz-admins"
-		cfg.Authz.GroupsTableName = dynamodbPrefix + "-authz-groups"
-		cfg.Authz.MembersTableName = dynamodbPrefix + "-authz-group-members"
+		setAuthzTablePrefix(cfg.Authz, dynamodbPrefix)
 		logger.Info("using DynamoDB table prefix", "prefix", dynamodbPrefix)
 	}
 
@@ -119,14 +605,271 @@ func runServe(cmd *cobra.Command, args []string) error {
 		cfg.Authz.DynamoDBEndpoint = endpoint
 		logger.Info("using custom DynamoDB endpoint", "endpoint", endpoint)
 	}
+
+	// Work envelope encryption and secret reference resolution
+	cfg.Work.EnvelopeKMSKeyID = workKMSKeyID
+	cfg.Work.SecretRefsEnabled = workSecretRefs
+	cfg.Work.SecretRefsPlatformPrefixes = parseCommaList(workSecretPlat)
+	cfg.Work.AWSRegion = cfg.Authz.AWSRegion
+	cfg.Work.DynamoDBEndpoint = cfg.Authz.DynamoDBEndpoint
+	cfg.Work.MaxManifests = workMaxManifest
+	cfg.Work.MaxPayloadBytes = workMaxBytes
+	cfg.Work.MaxMessageBytes = workMaxMessage
+	cfg.Work.MaxChunks = workMaxChunks
+	cfg.Work.MaxConcurrent = workMaxInFlight
+	cfg.Work.MaxQueued = workMaxQueued
+	cfg.Work.ClusterRateLimit = workClusterRate
+	cfg.Work.ClusterRateWindow = workClusterWin
+	if workChartRegs != "" {
+		cfg.Work.ChartRegistries = strings.Split(workChartRegs, ",")
+	}
+	cfg.Work.MaxChartBytes = workChartBytes
+	cfg.Work.ChartPullTimeout = workChartPull
+	cfg.Work.PayloadRegistries = parseCommaList(workPayloadRegs)
+	cfg.Work.PayloadBuckets = parseCommaList(workPayloadBkts)
+	cfg.Work.MaxPayloadRefBytes = workPayloadMax
+	cfg.Work.PayloadFetchTimeout = workPayloadTime
+	cfg.Work.PayloadSigners = parseCommaList(workPayloadSign)
+	cfg.Work.PayloadTrustedRoot = workPayloadRoot
+	cfg.RequiredTags.Cluster = parseCommaList(requiredCluster)
+	cfg.RequiredTags.Work = parseCommaList(requiredWork)
+	for _, key := range slices.Concat(cfg.RequiredTags.Cluster, cfg.RequiredTags.Work) {
+		if err := middleware.ValidateRequestTagKey(key); err != nil {
+			return nil, fmt.Errorf("invalid required tag: %w", err)
+		}
+	}
+
+	// Regional status thresholds
+	cfg.Status.ErrorRateThreshold = statusErrRate
+	cfg.Status.DeliveryLagThreshold = statusLag
+
+	// Delivery canary
+	if canaryCluster != "" {
+		cfg.Canary.ClusterName = canaryCluster
+		cfg.Canary.Namespace = canaryNamespace
+		cfg.Canary.Interval = canaryInterval
+		cfg.Canary.Timeout = canaryTimeout
+	}
+
+	// Metering of billable API calls
+	if meteringSink != "" {
+		cfg.Metering.Sink = meteringSink
+		cfg.Metering.Target = meteringTarget
+		cfg.Metering.AWSRegion = cfg.Authz.AWSRegion
+		cfg.Metering.BatchSize = meteringBatch
+		cfg.Metering.FlushInterval = meteringFlush
+		cfg.Metering.BufferSize = meteringBuffer
+	}
+
+	// Platform events published to Kafka
+	if kafkaBrokers != "" {
+		cfg.Events.KafkaBrokers = parseCommaList(kafkaBrokers)
+		cfg.Events.Topics = kafkaTopics
+		cfg.Events.DefaultTopic = kafkaTopic
+		cfg.Events.Source = eventSource
+		cfg.Events.Encoding = eventEncoding
+		cfg.Events.SchemaRegistryURL = schemaRegistry
+		cfg.Events.TLS = kafkaTLS
+		cfg.Events.MSKIAM = kafkaMSKIAM
+		cfg.Events.AWSRegion = cfg.Authz.AWSRegion
+		cfg.Events.BufferSize = eventBuffer
+	}
+
+	cfg.Capture.File = captureFile
+	cfg.Capture.Accounts = parseCommaList(captureAccts)
+	cfg.Capture.BodyAccounts = parseCommaList(captureBodies)
+	cfg.Mirror.Target = mirrorTarget
+	cfg.Mirror.Percent = mirrorPercent
+	cfg.Mirror.DryRunWrites = mirrorDryRuns
+	cfg.Mirror.Timeout = mirrorTimeout
+	cfg.Mirror.MaxInFlight = mirrorInFlight
+
+	// List page sizes per endpoint class
+	cfg.Pagination.Tenant = config.PageLimits{Default: pageTenant, Max: pageTenantMax}
+	cfg.Pagination.Platform = config.PageLimits{Default: pagePlatform, Max: pagePlatformMax}
+	cfg.Pagination.TrustedActions = config.PageLimits{Default: pageRuns, Max: pageRunsMax}
+	cfg.Pagination.Audit = config.PageLimits{Default: pageAudit, Max: pageAuditMax}
+
+	// Slow-request thresholds per route class
+	cfg.SlowRequests = config.SlowRequestConfig{
+		Default:        slowDefault,
+		Tenant:         slowTenant,
+		Platform:       slowPlatform,
+		TrustedActions: slowRuns,
+		Authz:          slowAuthz,
+	}
+
+	// Availability and latency objectives per route class
+	cfg.SLO = config.SLOConfig{
+		Period:       sloPeriod,
+		Availability: sloAvailability,
+		Latency:      sloLatency,
+	}
+
+	// Load shedding while degraded
+	cfg.LoadShed.Rules = shedRules
+	cfg.LoadShed.QueueLoad = shedQueueLoad
+	cfg.LoadShed.Components = parseCommaList(shedComponents)
+
+	// Per-account request metrics
+	cfg.AccountMetrics.TopAccounts = topAccounts
+	cfg.AccountMetrics.RankInterval = accountRanking
+
+	// Scheduled AVP policy store backups
+	cfg.PolicyBackup.Bucket = backupBucket
+	cfg.PolicyBackup.Interval = backupInterval
+	cfg.PolicyBackup.Retention = backupRetention
+	cfg.PolicyBackup.AWSRegion = cfg.Authz.AWSRegion
+	cfg.AccountPurge.SigningKeyID = purgeKey
+	cfg.Operations.ResultTTL = opResultTTL
+	cfg.AccountPurge.AWSRegion = cfg.Authz.AWSRegion
+	if workMetadata {
+		cfg.Work.MetadataTableName = dynamodbPrefix + "-work-metadata"
+	}
+	if workSchedules {
+		cfg.Work.SchedulesTableName = dynamodbPrefix + "-work-schedules"
+		cfg.Work.ScheduleInterval = workSchedInt
+	}
+	if workHistory {
+		cfg.Work.HistoryTableName = dynamodbPrefix + "-work-history"
+	}
+	cfg.Work.HistoryInterval = historyEvery
+	cfg.Work.HistoryRetention = historyKeep
+
+	cfg.MgmtClusters.ListCacheTTL = mgmtCacheTTL
+	cfg.MgmtClusters.ListCacheStale = mgmtCacheStale
+	cfg.MgmtClusters.DrainBundles = mgmtDrain
+	cfg.MgmtClusters.DrainTimeout = mgmtDrainWait
+	cfg.MgmtClusters.AgentCompatibility = agentCompat
+	cfg.ResourceBundles.SummaryCacheTTL = rbSummaryTTL
+
+	// Management cluster ownership registry
+	if mgmtRegistry {
+		cfg.MgmtClusters.RegistryEnabled = true
+		cfg.MgmtClusters.RegistryTableName = dynamodbPrefix + "-management-clusters"
+		cfg.MgmtClusters.AWSRegion = cfg.Authz.AWSRegion
+		cfg.MgmtClusters.DynamoDBEndpoint = cfg.Authz.DynamoDBEndpoint
+	}
+	if mgmtCaps {
+		cfg.MgmtClusters.CapabilitiesTableName = dynamodbPrefix + "-cluster-capabilities"
+		cfg.MgmtClusters.AWSRegion = cfg.Authz.AWSRegion
+		cfg.MgmtClusters.DynamoDBEndpoint = cfg.Authz.DynamoDBEndpoint
+	}
+
+	// Per-account notification settings
+	if notifications {
+		cfg.Notifications.Enabled = true
+		cfg.Notifications.TableName = dynamodbPrefix + "-notification-settings"
+		cfg.Notifications.AWSRegion = cfg.Authz.AWSRegion
+		cfg.Notifications.DynamoDBEndpoint = cfg.Authz.DynamoDBEndpoint
+		cfg.Notifications.EmailSender = notifySender
+	}
+
+	// Leader election for background workers
+	if leaderElection {
+		cfg.LeaderElection.Enabled = true
+		cfg.LeaderElection.TableName = dynamodbPrefix + "-leases"
+		cfg.LeaderElection.AWSRegion = cfg.Authz.AWSRegion
+		cfg.LeaderElection.DynamoDBEndpoint = cfg.Authz.DynamoDBEndpoint
+		cfg.LeaderElection.LeaseDuration = leaseDuration
+		cfg.LeaderElection.Identity = os.Getenv("POD_NAME")
+		if cfg.LeaderElection.Identity == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				return nil, fmt.Errorf("failed to determine leader election identity: %w", err)
+			}
+			cfg.LeaderElection.Identity = hostname
+		}
+	}
+
+	// Per-account rate limiting
+	cfg.RateLimit.Limit = rateLimit
+	cfg.RateLimit.Window = rateWindow
+	cfg.RateLimit.Backend = rateBackend
+	cfg.RateLimit.TableName = dynamodbPrefix + "-rate-limits"
+	cfg.RateLimit.AWSRegion = cfg.Authz.AWSRegion
+	cfg.RateLimit.DynamoDBEndpoint = cfg.Authz.DynamoDBEndpoint
+
+	// Plan tiers
+	cfg.Plans.Default = defaultPlan
+	cfg.Plans.CacheTTL = planCacheTTL
+
+	// Shared cache backend
+	cfg.Cache.Backend = cacheBackend
+	if cacheBackend == cache.BackendRedis {
+		cfg.Cache.Redis.Addrs = parseCommaList(redisAddrs)
+		cfg.Cache.Redis.ClusterMode = redisCluster
+		cfg.Cache.Redis.Username = redisUsername
+		cfg.Cache.Redis.Password = os.Getenv("REDIS_PASSWORD")
+		cfg.Cache.Redis.TLS = redisTLS
+	}
+
+	// Declarative initial authz state
+	if bootstrapFile != "" {
+		manifest, err := bootstrap.Load(bootstrapFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Bootstrap = manifest
+	}
+
+	// Authz table change streams
+	if authzStreams {
+		cfg.AuthzStreams.Enabled = true
+		cfg.AuthzStreams.PollInterval = streamsPoll
+	}
+
 	if endpoint := os.Getenv("CEDAR_AGENT_ENDPOINT"); endpoint != "" {
 		cfg.Authz.CedarAgentEndpoint = endpoint
 		logger.Info("using cedar-agent for local AVP", "endpoint", endpoint)
 	}
+
+	// Degraded start when DynamoDB is unreachable at boot
+	cfg.Authz.DegradedStart = degradedStart
+	cfg.Authz.DegradedMode = degradedMode
+
+	// Resource ARNs from these accounts pass the resource account check
+	cfg.Authz.CrossAccountResourceAccounts = parseCommaList(crossAccounts)
+	cfg.Authz.MaxGroupMembers = maxMembers
+	cfg.Authz.MemberPartnerAccounts = parseCommaList(memberPartners)
+	cfg.Authz.PrincipalPatterns = patterns
+	cfg.Authz.PatternMatchInterval = patternMatch
+	cfg.Authz.GuardrailPolicyStoreID = guardrailStore
+	cfg.Authz.WaitForVisibility = waitVisible
+	cfg.Authz.VisibilityTimeout = visibleTimeout
+	cfg.Authz.DeletionRetention = deletionRetain
+	cfg.Authz.APIKeyCacheTTL = apiKeyCacheTTL
+	cfg.Authz.APIKeyRevocationRefresh = apiKeyRefresh
+	cfg.Authz.OnboardingWorkers = onboardWorkers
+	cfg.Authz.OnboardingQueueSize = onboardQueue
+	cfg.Authz.OnboardingRate = onboardRate
+	cfg.Authz.ShadowMode = shadowMode
+	cfg.Authz.Bypass = parseCommaList(bypassRules)
+	cfg.Authz.DecisionAnalytics = decisionStats
+	cfg.Authz.DecisionFlushInterval = decisionFlush
+	cfg.Authz.DecisionRetention = decisionRetain
+	cfg.Authz.DecisionAudit = decisionAudit
+	cfg.Authz.DecisionAuditRetention = auditRetain
+	cfg.Authz.AuditQueryRateLimit = auditQueryRate
+	cfg.Authz.AuditOpenSearchEndpoint = auditOSURL
+	cfg.Authz.AuditOpenSearchIndex = auditOSIndex
+	cfg.Authz.AuditOpenSearchRollover = auditOSRollover
+	cfg.Authz.AuditOpenSearchService = auditOSService
+	cfg.Authz.GroupTagsInContext = groupTagsCtx
+	cfg.Authz.ApprovalRequired = parseCommaList(approvalOps)
+	cfg.Authz.ApprovalExpiry = approvalExpiry
+	cfg.Authz.CedarNamespace = cedarNamespace
+
 	if os.Getenv("AUTHZ_DISABLED") == "true" {
 		cfg.Authz.Enabled = false
 		logger.Info("authz disabled via environment variable")
 	}
+	if cfg.Bootstrap != nil && !cfg.Authz.Enabled {
+		logger.Warn("ignoring --bootstrap-file while authz is disabled")
+	}
+	if len(cfg.Identity.SuperAdmins) > 0 && !cfg.Authz.Enabled {
+		logger.Warn("ignoring --identity-super-admins while authz is disabled")
+	}
 
 	// ZOA configuration from environment variables
 	if os.Getenv("ZOA_ENABLED") == "true" {
@@ -163,73 +906,106 @@ func runServe(cmd *cobra.Command, args []string) error {
 		)
 	}
 
-	// Create server
-	srv, err := server.New(cfg, logger)
-	if err != nil {
-		return fmt.Errorf("failed to create server: %w", err)
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
+	return cfg, nil
+}
 
-	// Setup signal handling
-	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+// resolveStartupSecret returns value, read from Secrets Manager or SSM when
+// it is a reference
+func resolveStartupSecret(cfg *config.Config, value string, logger *slog.Logger) (string, error) {
+	if !secretsource.IsReference(value) {
+		return value, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
+	source, err := secretsource.NewAWS(ctx, cfg.Secrets.AWSRegion, 0, logger)
+	if err != nil {
+		return "", err
+	}
+	resolved, err := source.Resolve(ctx, value)
+	if err != nil {
+		return "", err
+	}
+	return resolved.Get(), nil
+}
 
-	// Run server
-	logger.Info("server configuration",
-		"api_port", cfg.Server.APIPort,
-		"health_port", cfg.Server.HealthPort,
-		"metrics_port", cfg.Server.MetricsPort,
-		"maestro_url", cfg.Maestro.BaseURL,
-		"maestro_grpc_url", cfg.Maestro.GRPCBaseURL,
-		"hyperfleet_url", cfg.Hyperfleet.BaseURL,
-		"allowed_accounts_count", len(cfg.AllowedAccounts),
-	)
+// logLevelVar is the level of the serve logger, changed by config reloads
+var logLevelVar = new(slog.LevelVar)
 
-	if err := srv.Run(ctx); err != nil {
-		return fmt.Errorf("server error: %w", err)
+func createLogger(level, format string) *slog.Logger {
+	logLevelVar.Set(parseLevel(level))
+	opts := &slog.HandlerOptions{
+		Level: logLevelVar,
 	}
 
-	return nil
+	var handler slog.Handler
+	if format == "text" {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	}
+
+	return slog.New(handler)
 }
 
-func createLogger(level, format string) *slog.Logger {
-	var logLevel slog.Level
+// parseLevel maps a level name to a slog level, defaulting to info
+func parseLevel(level string) slog.Level {
 	switch level {
 	case "debug":
-		logLevel = slog.LevelDebug
+		return slog.LevelDebug
 	case "info":
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	case "warn":
-		logLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case "error":
-		logLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	}
+}
 
-	opts := &slog.HandlerOptions{
-		Level: logLevel,
+// parseBytes parses a Kubernetes quantity such as 512Mi into bytes; empty is 0
+func parseBytes(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
 	}
-
-	var handler slog.Handler
-	if format == "text" {
-		handler = slog.NewTextHandler(os.Stdout, opts)
-	} else {
-		handler = slog.NewJSONHandler(os.Stdout, opts)
+	q, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, err
 	}
-
-	return slog.New(handler)
+	return q.Value(), nil
 }
 
-func parseAllowedAccounts(accounts string) []string {
-	if accounts == "" {
+func parseCommaList(list string) []string {
+	if list == "" {
 		return nil
 	}
 	var result []string
-	for _, acc := range strings.Split(accounts, ",") {
-		acc = strings.TrimSpace(acc)
-		if acc != "" {
-			result = append(result, acc)
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			result = append(result, item)
 		}
 	}
 	return result
 }
+
+// setAuthzTablePrefix names the authz tables after a DynamoDB table prefix
+func setAuthzTablePrefix(cfg *authz.Config, prefix string) {
+	cfg.AccountsTableName = prefix + "-authz-accounts"
+	cfg.AdminsTableName = prefix + "-authz-admins"
+	cfg.GroupsTableName = prefix + "-authz-groups"
+	cfg.MembersTableName = prefix + "-authz-group-members"
+	cfg.DelegationsTableName = prefix + "-authz-delegations"
+	cfg.APIKeysTableName = prefix + "-authz-api-keys"
+	cfg.OrganizationsTableName = prefix + "-authz-organizations"
+	cfg.AttachmentsTableName = prefix + "-authz-attachments"
+	cfg.DeletionsTableName = prefix + "-authz-deletions"
+	cfg.PendingChangesTableName = prefix + "-authz-pending-changes"
+	cfg.ChangeRequestsTableName = prefix + "-authz-change-requests"
+	cfg.DecisionCountsTableName = prefix + "-authz-decision-counts"
+	cfg.PolicyTagsTableName = prefix + "-authz-policy-tags"
+	cfg.DecisionAuditTableName = prefix + "-authz-decision-audit"
+}
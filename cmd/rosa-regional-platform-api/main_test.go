@@ -63,7 +63,7 @@ func TestCreateLogger(t *testing.T) {
 	}
 }
 
-func TestParseAllowedAccounts(t *testing.T) {
+func TestParseCommaList(t *testing.T) {
 	tests := []struct {
 		name     string
 		input    string
@@ -118,7 +118,7 @@ func TestParseAllowedAccounts(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := parseAllowedAccounts(tt.input)
+			result := parseCommaList(tt.input)
 
 			if tt.expected == nil {
 				if result != nil {
@@ -141,6 +141,38 @@ func TestParseAllowedAccounts(t *testing.T) {
 	}
 }
 
+func TestParseBytes(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  int64
+		expectErr bool
+	}{
+		{input: "", expected: 0},
+		{input: "512Mi", expected: 512 << 20},
+		{input: "1G", expected: 1000000000},
+		{input: "1048576", expected: 1 << 20},
+		{input: "lots", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := parseBytes(tt.input)
+			if tt.expectErr {
+				if err == nil {
+					t.Error("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %d, got %d", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestRootCmd(t *testing.T) {
 	if rootCmd == nil {
 		t.Fatal("expected non-nil rootCmd")
@@ -195,3 +227,40 @@ func TestServeCmd(t *testing.T) {
 		}
 	}
 }
+
+func TestDoctorCmd(t *testing.T) {
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd == doctorCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected doctor to be registered on the root command")
+	}
+
+	for _, flagName := range []string{"maestro-url", "maestro-grpc-url", "dynamodb-region", "dynamodb-prefix", "policy-store-id", "timeout", "no-color"} {
+		if doctorCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("expected flag %s to be registered", flagName)
+		}
+	}
+}
+
+func TestCheckConfigCmd(t *testing.T) {
+	found := false
+	for _, cmd := range rootCmd.Commands() {
+		if cmd == checkConfigCmd {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("expected check-config to be registered on the root command")
+	}
+
+	// check-config validates the flags serve takes
+	for _, flagName := range []string{"api-port", "hyperfleet-url", "rate-limit-backend", "metering-sink"} {
+		if checkConfigCmd.Flags().Lookup(flagName) == nil {
+			t.Errorf("expected flag %s to be registered", flagName)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/migrate"
+)
+
+var (
+	migrateConfigFile string
+	migrateAdmin      string
+	migrateDryRun     bool
+	migrateOutput     string
+)
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate-allowed-accounts",
+	Short: "Convert the --allowed-accounts allow-list into authz accounts",
+	Long: "Enables every account of the legacy allow-list for Cedar/AVP authorization, makes --admin its admin and " +
+		"attaches a permissive \"" + migrate.PolicyName + "\" policy to the admin, who can then grant access to the " +
+		"account's other principals. The allow-list is read from --allowed-accounts, or from the allowed-accounts " +
+		"setting of a serve --config-file. Only what is missing is added, so the migration can be re-run; " +
+		"privileged accounts are left as they are. With --dry-run, the changes are reported without being made.",
+	RunE: runMigrate,
+}
+
+func init() {
+	migrateCmd.Flags().StringVar(&allowedAccounts, "allowed-accounts", "", "Comma-separated list of allowed AWS account IDs to migrate")
+	migrateCmd.Flags().StringVar(&migrateConfigFile, configFileFlag, "", "Serve config file to read allowed-accounts from")
+	migrateCmd.Flags().StringVar(&migrateAdmin, "admin", "", "IAM user or role ARN made admin of every account; "+migrate.AccountIDPlaceholder+" stands for the account ID")
+	migrateCmd.Flags().BoolVar(&migrateDryRun, "dry-run", false, "Report the changes without making them")
+	migrateCmd.Flags().StringVarP(&migrateOutput, "output", "o", "text", "Report format: text or json")
+	migrateCmd.Flags().StringVar(&dynamodbRegion, "dynamodb-region", "", "AWS region for DynamoDB and AVP (defaults to us-east-1)")
+	migrateCmd.Flags().StringVar(&dynamodbPrefix, "dynamodb-prefix", "rosa", "Prefix for DynamoDB table names")
+
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	if migrateOutput != "text" && migrateOutput != "json" {
+		return fmt.Errorf("invalid --output %q: must be text or json", migrateOutput)
+	}
+	if migrateAdmin == "" {
+		return fmt.Errorf("--admin is required")
+	}
+	list := allowedAccounts
+	if migrateConfigFile != "" {
+		if list != "" {
+			return fmt.Errorf("--allowed-accounts and --%s are mutually exclusive", configFileFlag)
+		}
+		values, err := loadConfigFile(migrateConfigFile)
+		if err != nil {
+			return err
+		}
+		list = values["allowed-accounts"]
+	}
+	accountIDs, err := migrate.ParseAccounts(parseCommaList(list))
+	if err != nil {
+		return err
+	}
+	if len(accountIDs) == 0 {
+		return fmt.Errorf("no allowed accounts to migrate")
+	}
+
+	// The report goes to stdout, the progress of each change to stderr
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	cfg := config.NewConfig()
+	if dynamodbRegion != "" {
+		cfg.Authz.AWSRegion = dynamodbRegion
+	}
+	setAuthzTablePrefix(cfg.Authz, dynamodbPrefix)
+	cfg.Authz.DynamoDBEndpoint = os.Getenv("DYNAMODB_ENDPOINT")
+	cfg.Authz.CedarAgentEndpoint = os.Getenv("CEDAR_AGENT_ENDPOINT")
+	// Accounts are onboarded before the migration moves on
+	cfg.Authz.OnboardingWorkers = 0
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	dynamoClient, err := client.NewDynamoDBClient(ctx, cfg.Authz.AWSRegion, cfg.Authz.DynamoDBEndpoint)
+	if err != nil {
+		return fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+	var avpClient client.AVPClient
+	if cfg.Authz.CedarAgentEndpoint != "" {
+		avpClient = client.NewMockAVPClient(cfg.Authz.CedarAgentEndpoint, logger)
+	} else if avpClient, err = client.NewAVPClient(ctx, cfg.Authz.AWSRegion); err != nil {
+		return fmt.Errorf("failed to create AVP client: %w", err)
+	}
+	svc := authz.New(cfg.Authz, dynamoClient, avpClient, logger)
+
+	result, runErr := migrate.Run(ctx, svc, accountIDs, migrate.Options{AdminARN: migrateAdmin, DryRun: migrateDryRun}, logger)
+	out := cmd.OutOrStdout()
+	if migrateOutput == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	} else {
+		migrate.WriteReport(out, result, migrateDryRun)
+	}
+	if runErr != nil {
+		return fmt.Errorf("migration stopped, re-run it to continue: %w", runErr)
+	}
+	return nil
+}
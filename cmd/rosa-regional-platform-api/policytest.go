@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/policytest"
+)
+
+var (
+	policyTestTarget    string
+	policyTestAccount   string
+	policyTestCaller    string
+	policyTestRegion    string
+	policyTestAgent     string
+	policyTestNamespace string
+	policyTestTimeout   time.Duration
+	policyTestOutput    string
+	policyTestCoverage  bool
+	policyTestRequire   bool
+)
+
+var policyTestCmd = &cobra.Command{
+	Use:   "policy-test <file-or-dir>...",
+	Short: "Run declarative Cedar policy tests against an API or the local mock engine",
+	Long: "Reads policy test files (JSON or YAML: a policy, or a policyFile naming a .cedar file, and testCases each " +
+		"giving a request and its expectedResult, ALLOW or DENY) and checks every case. With --target, each case " +
+		"creates its policies in the caller's account of that API, attaches them to a throwaway group holding the " +
+		"case's principal, asks /api/v0/authz/check and cleans up; the caller must be an account admin. Without it, " +
+		"cases are decided locally by the cedar-agent mock at --cedar-agent-endpoint. Exits non-zero when any case " +
+		"fails or errors. --coverage also reports which statements of each file's policy decided a case, flagging " +
+		"permits and forbids no case exercised; it needs a local run, as the check API does not report determining " +
+		"policies.",
+	Args: cobra.MinimumNArgs(1),
+	RunE: runPolicyTest,
+}
+
+func init() {
+	policyTestCmd.Flags().StringVar(&policyTestTarget, "target", "", "Base URL of the API to run against (empty runs locally against the cedar-agent mock)")
+	policyTestCmd.Flags().StringVar(&policyTestAccount, "account-id", "123456789012", "Account the test principals belong to; with --target, also sent as X-Amz-Account-Id")
+	policyTestCmd.Flags().StringVar(&policyTestCaller, "caller-arn", "", "Sent as X-Amz-Caller-Arn with --target, for servers running without API Gateway")
+	policyTestCmd.Flags().StringVar(&policyTestRegion, "sigv4-region", "", "Sign requests to --target with SigV4 for execute-api in this region, using the default AWS credentials")
+	policyTestCmd.Flags().StringVar(&policyTestAgent, "cedar-agent-endpoint", os.Getenv("CEDAR_AGENT_ENDPOINT"), "cedar-agent URL for local runs (defaults to CEDAR_AGENT_ENDPOINT)")
+	policyTestCmd.Flags().StringVar(&policyTestNamespace, "cedar-namespace", schema.DefaultNamespace, "Cedar namespace of local runs; must match the policies")
+	policyTestCmd.Flags().DurationVar(&policyTestTimeout, "timeout", 30*time.Second, "Timeout for each request to --target")
+	policyTestCmd.Flags().StringVarP(&policyTestOutput, "output", "o", "text", "Report format: text or json")
+	policyTestCmd.Flags().BoolVar(&policyTestCoverage, "coverage", false, "Report the policy statements no case exercised (local runs only)")
+	policyTestCmd.Flags().BoolVar(&policyTestRequire, "require-coverage", false, "Like --coverage, and exit non-zero when a statement is untested")
+
+	rootCmd.AddCommand(policyTestCmd)
+}
+
+func runPolicyTest(cmd *cobra.Command, args []string) error {
+	if policyTestOutput != "text" && policyTestOutput != "json" {
+		return fmt.Errorf("invalid --output %q: must be text or json", policyTestOutput)
+	}
+	coverage := policyTestCoverage || policyTestRequire
+	if coverage && policyTestTarget != "" {
+		return fmt.Errorf("--coverage needs a local run: /api/v0/authz/check does not report determining policies")
+	}
+	files, err := policytest.Load(args...)
+	if err != nil {
+		return fmt.Errorf("failed to load policy tests: %w", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	engine, err := newPolicyTestEngine(ctx)
+	if err != nil {
+		return err
+	}
+
+	results := policytest.Run(ctx, engine, policyTestAccount, files)
+	var report []policytest.FileCoverage
+	if coverage {
+		report = policytest.Coverage(files, results)
+	}
+	out := cmd.OutOrStdout()
+	if policyTestOutput == "json" {
+		doc := map[string]any{"results": results, "summary": policytest.Summarize(results)}
+		if coverage {
+			doc["coverage"] = report
+			doc["coverageSummary"] = policytest.SummarizeCoverage(report)
+		}
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+	} else {
+		policytest.WriteText(out, results)
+		if coverage {
+			fmt.Fprintln(out)
+			policytest.WriteCoverage(out, report)
+		}
+	}
+
+	if s := policytest.Summarize(results); s.Failed > 0 || s.Errors > 0 {
+		return fmt.Errorf("%d policy test cases failed, %d errored", s.Failed, s.Errors)
+	}
+	if s := policytest.SummarizeCoverage(report); policyTestRequire && s.Untested() > 0 {
+		return fmt.Errorf("%d policy statements are not exercised by any test case", s.Untested())
+	}
+	return nil
+}
+
+// newPolicyTestEngine returns the engine the flags select
+func newPolicyTestEngine(ctx context.Context) (policytest.Engine, error) {
+	if policyTestTarget != "" {
+		cfg := policytest.RemoteConfig{
+			BaseURL:   policyTestTarget,
+			AccountID: policyTestAccount,
+			CallerARN: policyTestCaller,
+			AWSRegion: policyTestRegion,
+			Timeout:   policyTestTimeout,
+		}
+		var credentials aws.CredentialsProvider
+		if policyTestRegion != "" {
+			awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(policyTestRegion))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load AWS config: %w", err)
+			}
+			credentials = awsCfg.Credentials
+		}
+		return policytest.NewRemoteEngine(cfg, credentials), nil
+	}
+
+	if policyTestAgent == "" {
+		return nil, fmt.Errorf("--cedar-agent-endpoint or CEDAR_AGENT_ENDPOINT is required without --target")
+	}
+	if err := schema.Namespace(policyTestNamespace).Validate(); err != nil {
+		return nil, err
+	}
+	cfg := authz.DefaultConfig()
+	cfg.CedarNamespace = policyTestNamespace
+	// Only failures of the mock itself are worth logging, away from the report
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	mock := client.NewMockAVPClient(policyTestAgent, logger)
+	return policytest.NewLocalEngine(authz.NewPolicyEvaluator(cfg, mock, logger)), nil
+}
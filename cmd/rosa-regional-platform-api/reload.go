@@ -0,0 +1,245 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+)
+
+const configFileFlag = "config-file"
+
+// loadConfigFile reads a YAML or JSON file of serve flag names and values.
+// Lists are joined with commas, as the list flags expect.
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	// Nodes keep scalars as written, so account IDs keep their leading zeros
+	var raw map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	for name, node := range raw {
+		switch node.Kind {
+		case yaml.ScalarNode:
+			values[name] = node.Value
+		case yaml.SequenceNode:
+			items := make([]string, 0, len(node.Content))
+			for _, item := range node.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("config file %s: %s must be a list of values", path, name)
+				}
+				items = append(items, item.Value)
+			}
+			values[name] = strings.Join(items, ",")
+		default:
+			return nil, fmt.Errorf("config file %s: %s must be a value or a list of values", path, name)
+		}
+	}
+	return values, nil
+}
+
+// reloadOnHangup reloads the config file on every SIGHUP until ctx is done.
+// Without a config file SIGHUP is ignored rather than ending the process.
+func reloadOnHangup(ctx context.Context, reloader *configReloader, logger *slog.Logger) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			if reloader == nil {
+				logger.Warn("ignoring SIGHUP: no --config-file to reload")
+				continue
+			}
+			report, err := reloader.Reload(ctx, false)
+			if err != nil {
+				logger.Error("configuration reload failed, nothing was changed", "error", err)
+				continue
+			}
+			logger.Info("configuration reloaded", "applied", len(report.Applied), "rejected", len(report.Rejected))
+		}
+	}
+}
+
+// configReloader sets serve flags from the config file at startup and
+// applies the reloadable ones again whenever the file is reloaded. Flags
+// given on the command line win over the file.
+type configReloader struct {
+	path   string
+	flags  *pflag.FlagSet
+	logger *slog.Logger
+	// build creates the configuration from the flags, and apply applies the
+	// changed reloadable settings of it, returning why any could not be
+	build func() (*config.Config, error)
+	apply func(ctx context.Context, cfg *config.Config, changed []string) map[string]error
+
+	mu sync.Mutex
+	// cli holds the flags set on the command line, fromFile those set from
+	// the file
+	cli      map[string]bool
+	fromFile map[string]bool
+}
+
+// newConfigReloader sets flags from the --config-file, returning nil when
+// none was given. Its logger, build and apply must be set before Reload.
+func newConfigReloader(flags *pflag.FlagSet) (*configReloader, error) {
+	path, err := flags.GetString(configFileFlag)
+	if err != nil || path == "" {
+		return nil, err
+	}
+	r := &configReloader{path: path, flags: flags, cli: map[string]bool{}, fromFile: map[string]bool{}}
+	flags.Visit(func(f *pflag.Flag) { r.cli[f.Name] = true })
+
+	values, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+	var problems []error
+	for name, value := range values {
+		r.fromFile[name] = true
+		if r.cli[name] {
+			continue
+		}
+		if err := flags.Set(name, value); err != nil {
+			problems = append(problems, fmt.Errorf("invalid %s: %w", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("config file %s: %w", path, errors.Join(problems...))
+	}
+	return r, nil
+}
+
+// read loads the file, rejecting names that are not serve flags
+func (r *configReloader) read() (map[string]string, error) {
+	values, err := loadConfigFile(r.path)
+	if err != nil {
+		return nil, err
+	}
+	for name := range values {
+		if name == configFileFlag || r.flags.Lookup(name) == nil {
+			return nil, fmt.Errorf("config file %s: unknown setting %q", r.path, name)
+		}
+	}
+	return values, nil
+}
+
+// Reload re-reads the file and applies the settings that changed and can
+// change without a restart. Other changes are rejected and reported; the
+// flags keep the values in effect, so they are reported again on the next
+// reload. A file that cannot be read or a configuration that is invalid
+// changes nothing. dryRun only reports what would change.
+func (r *configReloader) Reload(ctx context.Context, dryRun bool) (*config.ReloadReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	values, err := r.read()
+	if err != nil {
+		return nil, err
+	}
+
+	previous := map[string]string{}
+	r.flags.VisitAll(func(f *pflag.Flag) { previous[f.Name] = f.Value.String() })
+	restore := func(names ...string) {
+		for _, name := range names {
+			_ = r.flags.Set(name, previous[name])
+		}
+	}
+
+	// Settings removed from the file go back to their defaults
+	var touched []string
+	var problems []error
+	for name := range previous {
+		value, inFile := values[name]
+		if r.cli[name] || (!inFile && !r.fromFile[name]) {
+			continue
+		}
+		if !inFile {
+			value = r.flags.Lookup(name).DefValue
+		}
+		touched = append(touched, name)
+		if err := r.flags.Set(name, value); err != nil {
+			problems = append(problems, fmt.Errorf("invalid %s: %w", name, err))
+		}
+	}
+	if len(problems) > 0 {
+		restore(touched...)
+		return nil, fmt.Errorf("config file %s: %w", r.path, errors.Join(problems...))
+	}
+
+	report := &config.ReloadReport{DryRun: dryRun, Applied: []config.SettingChange{}, Rejected: []config.SettingChange{}}
+	var changes []config.SettingChange
+	sort.Strings(touched)
+	for _, name := range touched {
+		if value := r.flags.Lookup(name).Value.String(); value != previous[name] {
+			changes = append(changes, config.SettingChange{Setting: name, From: previous[name], To: value})
+		}
+	}
+
+	var reloadable []config.SettingChange
+	for _, change := range changes {
+		if slices.Contains(config.ReloadableSettings, change.Setting) {
+			reloadable = append(reloadable, change)
+			continue
+		}
+		change.Reason = "changing this setting requires a restart"
+		report.Rejected = append(report.Rejected, change)
+		restore(change.Setting)
+	}
+
+	cfg, err := r.build()
+	if err != nil || dryRun {
+		restore(touched...)
+		if err != nil {
+			return nil, err
+		}
+		report.Applied = append(report.Applied, reloadable...)
+		return report, nil
+	}
+
+	names := make([]string, 0, len(reloadable))
+	for _, change := range reloadable {
+		names = append(names, change.Setting)
+	}
+	failed := r.apply(ctx, cfg, names)
+	for _, change := range reloadable {
+		if err, ok := failed[change.Setting]; ok {
+			change.Reason = err.Error()
+			report.Rejected = append(report.Rejected, change)
+			restore(change.Setting)
+			continue
+		}
+		report.Applied = append(report.Applied, change)
+	}
+
+	r.fromFile = make(map[string]bool, len(values))
+	for name := range values {
+		r.fromFile[name] = true
+	}
+	for _, change := range report.Applied {
+		r.logger.Info("configuration setting reloaded", "setting", change.Setting, "from", change.From, "to", change.To)
+	}
+	for _, change := range report.Rejected {
+		r.logger.Warn("configuration setting change rejected", "setting", change.Setting, "from", change.From, "to", change.To, "reason", change.Reason)
+	}
+	return report, nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+)
+
+type reloadFlags struct {
+	flags    *pflag.FlagSet
+	level    string
+	accounts string
+	limit    int
+	window   time.Duration
+	port     int
+}
+
+func newReloadFlags(t *testing.T, file string, args ...string) *reloadFlags {
+	t.Helper()
+	f := &reloadFlags{flags: pflag.NewFlagSet("serve", pflag.ContinueOnError)}
+	f.flags.String(configFileFlag, "", "")
+	f.flags.StringVar(&f.level, "log-level", "info", "")
+	f.flags.StringVar(&f.accounts, "allowed-accounts", "", "")
+	f.flags.IntVar(&f.limit, "rate-limit", 0, "")
+	f.flags.DurationVar(&f.window, "rate-limit-window", 10*time.Second, "")
+	f.flags.IntVar(&f.port, "api-port", 8000, "")
+	if err := f.flags.Parse(append([]string{"--config-file", file}, args...)); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+	return f
+}
+
+func writeConfigFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write config file: %v", err)
+	}
+}
+
+// newTestReloader returns a reloader whose apply records the settings it
+// was given, failing those in failing
+func newTestReloader(t *testing.T, f *reloadFlags, failing map[string]error) (*configReloader, *[]string) {
+	t.Helper()
+	r, err := newConfigReloader(f.flags)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	applied := &[]string{}
+	r.logger = slog.New(slog.DiscardHandler)
+	r.build = func() (*config.Config, error) {
+		cfg := config.NewConfig()
+		cfg.RateLimit.Limit = f.limit
+		return cfg, nil
+	}
+	r.apply = func(ctx context.Context, cfg *config.Config, changed []string) map[string]error {
+		*applied = changed
+		return failing
+	}
+	return r, applied
+}
+
+func TestConfigReloader_Startup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "log-level: debug\napi-port: 9000\nallowed-accounts:\n  - \"012345678901\"\n  - \"111111111111\"\n")
+
+	f := newReloadFlags(t, path, "--api-port", "7000")
+	if _, err := newConfigReloader(f.flags); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if f.level != "debug" {
+		t.Errorf("expected the log level from the file, got %q", f.level)
+	}
+	if f.accounts != "012345678901,111111111111" {
+		t.Errorf("expected the account list joined with leading zeros kept, got %q", f.accounts)
+	}
+	if f.port != 7000 {
+		t.Errorf("expected the command line to win over the file, got port %d", f.port)
+	}
+}
+
+func TestConfigReloader_UnknownSetting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "log-levle: debug\n")
+
+	if _, err := newConfigReloader(newReloadFlags(t, path).flags); err == nil {
+		t.Fatal("expected an error for an unknown setting")
+	}
+}
+
+func TestConfigReloader_Reload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "log-level: debug\nrate-limit: 100\n")
+	f := newReloadFlags(t, path)
+	r, applied := newTestReloader(t, f, map[string]error{"rate-limit": errors.New("limiter unavailable")})
+
+	// log-level is removed and so reverts to its default
+	writeConfigFile(t, path, "rate-limit: 200\nrate-limit-window: 1m\napi-port: 9000\n")
+	report, err := r.Reload(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(*applied) != 3 {
+		t.Errorf("expected 3 reloadable changes to be applied, got %v", *applied)
+	}
+	if len(report.Applied) != 2 || report.Applied[0].Setting != "log-level" || report.Applied[1].Setting != "rate-limit-window" {
+		t.Fatalf("unexpected applied changes: %+v", report.Applied)
+	}
+	if report.Applied[0].From != "debug" || report.Applied[0].To != "info" {
+		t.Errorf("expected log-level to revert to info, got %+v", report.Applied[0])
+	}
+	if len(report.Rejected) != 2 {
+		t.Fatalf("expected 2 rejected changes, got %+v", report.Rejected)
+	}
+	if report.Rejected[0].Setting != "api-port" || report.Rejected[0].Reason != "changing this setting requires a restart" {
+		t.Errorf("unexpected rejection: %+v", report.Rejected[0])
+	}
+	if report.Rejected[1].Setting != "rate-limit" || report.Rejected[1].Reason != "limiter unavailable" {
+		t.Errorf("unexpected rejection: %+v", report.Rejected[1])
+	}
+
+	// Rejected settings keep the values in effect
+	if f.port != 8000 || f.limit != 100 {
+		t.Errorf("expected rejected settings to keep their values, got port %d and limit %d", f.port, f.limit)
+	}
+	if f.window != time.Minute || f.level != "info" {
+		t.Errorf("expected applied settings to change, got window %s and level %q", f.window, f.level)
+	}
+}
+
+func TestConfigReloader_ReloadDryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "log-level: info\n")
+	f := newReloadFlags(t, path)
+	r, applied := newTestReloader(t, f, nil)
+
+	writeConfigFile(t, path, "log-level: warn\n")
+	report, err := r.Reload(context.Background(), true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !report.DryRun || len(report.Applied) != 1 {
+		t.Errorf("expected one change in a dry-run report, got %+v", report)
+	}
+	if len(*applied) != 0 || f.level != "info" {
+		t.Errorf("expected a dry run to change nothing, got applied %v and level %q", *applied, f.level)
+	}
+}
+
+func TestConfigReloader_ReloadInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfigFile(t, path, "rate-limit: 100\n")
+	f := newReloadFlags(t, path)
+	r, applied := newTestReloader(t, f, nil)
+
+	writeConfigFile(t, path, "rate-limit: 50\nrate-limit-window: soon\n")
+	if _, err := r.Reload(context.Background(), false); err == nil {
+		t.Fatal("expected an error for an invalid value")
+	}
+	if len(*applied) != 0 || f.limit != 100 {
+		t.Errorf("expected an invalid file to change nothing, got applied %v and limit %d", *applied, f.limit)
+	}
+}
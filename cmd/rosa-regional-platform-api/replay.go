@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/capture"
+)
+
+var (
+	replayTarget    string
+	replayAccount   string
+	replayRequestID string
+	replayTimeout   time.Duration
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <capture-file>",
+	Short: "Re-issue captured requests against a local instance",
+	Long: "Reads the request envelopes written by serve --capture-file and re-issues them, in order, against a local " +
+		"instance (typically one running with --dev and the mock backends), reporting the captured and replayed status " +
+		"of each. Requests whose body was only hashed are skipped. Exits non-zero when any replayed status differs.",
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().StringVar(&replayTarget, "target", "http://localhost:8000", "Base URL of the instance to replay against")
+	replayCmd.Flags().StringVar(&replayAccount, "account", "", "Only replay requests from this account ID")
+	replayCmd.Flags().StringVar(&replayRequestID, "request-id", "", "Only replay the request with this request ID")
+	replayCmd.Flags().DurationVar(&replayTimeout, "timeout", 30*time.Second, "Timeout for each replayed request")
+
+	rootCmd.AddCommand(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	f, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open capture file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	envs, err := capture.Read(f)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	client := &http.Client{Timeout: replayTimeout}
+	out := cmd.OutOrStdout()
+	var replayed, skipped, mismatched int
+	for _, env := range envs {
+		if replayAccount != "" && env.AccountID != replayAccount {
+			continue
+		}
+		if replayRequestID != "" && env.RequestID != replayRequestID {
+			continue
+		}
+
+		status, err := replay(ctx, client, env)
+		switch {
+		case errors.Is(err, capture.ErrBodyNotCaptured):
+			skipped++
+			fmt.Fprintf(out, "[SKIP] %s %s %s: body not captured (sha256 %s)\n", env.RequestID, env.Method, env.Path, env.BodySHA256)
+			continue
+		case err != nil:
+			return fmt.Errorf("failed to replay %s %s: %w", env.Method, env.Path, err)
+		}
+
+		replayed++
+		result := "SAME"
+		if status != env.Status {
+			result = "DIFF"
+			mismatched++
+		}
+		fmt.Fprintf(out, "[%s] %s %s %s: captured %d, replayed %d\n", result, env.RequestID, env.Method, env.Path, env.Status, status)
+	}
+	fmt.Fprintf(out, "\n%d replayed, %d differed, %d skipped\n", replayed, mismatched, skipped)
+
+	if mismatched > 0 {
+		return fmt.Errorf("%d replayed requests got a different status", mismatched)
+	}
+	return nil
+}
+
+// replay re-issues env against the target and returns the response status
+func replay(ctx context.Context, client *http.Client, env capture.Envelope) (int, error) {
+	req, err := env.Request(ctx, replayTarget)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.StatusCode, nil
+}
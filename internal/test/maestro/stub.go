@@ -0,0 +1,329 @@
+package maestro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cloudevents/sdk-go/v2/binding"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/payload"
+	pbv1 "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protobuf/v1"
+	grpcprotocol "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc/protocol"
+	cetypes "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+
+	maestroclient "github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+)
+
+const (
+	consumersPath       = "/api/maestro/v1/consumers"
+	resourceBundlesPath = "/api/maestro/v1/resource-bundles"
+)
+
+// Stub serves the parts of the Maestro REST and gRPC APIs the platform API
+// calls: consumers and resource bundles over REST, and the CloudEvents
+// service ManifestWorks are published to over gRPC. Published works are
+// kept as resource bundles and nothing applies them, so their status stays
+// empty. List searches are ignored.
+type Stub struct {
+	rest     *httptest.Server
+	grpc     *grpc.Server
+	grpcAddr string
+
+	mu        sync.Mutex
+	consumers map[string]maestroclient.Consumer
+	bundles   map[string]maestroclient.ResourceBundle
+}
+
+// NewStub starts a Maestro stub on loopback ports
+func NewStub() (*Stub, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for gRPC: %w", err)
+	}
+
+	s := &Stub{
+		grpcAddr:  lis.Addr().String(),
+		consumers: make(map[string]maestroclient.Consumer),
+		bundles:   make(map[string]maestroclient.ResourceBundle),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/maestro", getAPI)
+	mux.HandleFunc("GET "+consumersPath, s.listConsumers)
+	mux.HandleFunc("POST "+consumersPath, s.createConsumer)
+	mux.HandleFunc("GET "+consumersPath+"/{id}", s.getConsumer)
+	mux.HandleFunc("DELETE "+consumersPath+"/{id}", s.deleteConsumer)
+	mux.HandleFunc("GET "+resourceBundlesPath, s.listResourceBundles)
+	mux.HandleFunc("GET "+resourceBundlesPath+"/{id}", s.getResourceBundle)
+	mux.HandleFunc("DELETE "+resourceBundlesPath+"/{id}", s.deleteResourceBundle)
+	s.rest = httptest.NewServer(mux)
+
+	s.grpc = grpc.NewServer()
+	pbv1.RegisterCloudEventServiceServer(s.grpc, &cloudEventService{stub: s})
+	go func() { _ = s.grpc.Serve(lis) }()
+
+	return s, nil
+}
+
+// BaseURL is the URL of the REST API
+func (s *Stub) BaseURL() string {
+	return s.rest.URL
+}
+
+// GRPCBaseURL is the URL of the gRPC API
+func (s *Stub) GRPCBaseURL() string {
+	return "grpc://" + s.grpcAddr
+}
+
+// Close stops the stub
+func (s *Stub) Close() {
+	s.grpc.Stop()
+	s.rest.Close()
+}
+
+// AddConsumer registers a consumer, as a management cluster's agent would
+func (s *Stub) AddConsumer(name string) maestroclient.Consumer {
+	now := time.Now().UTC()
+	consumer := maestroclient.Consumer{
+		ID:        uuid.NewString(),
+		Kind:      "Consumer",
+		Name:      name,
+		CreatedAt: &now,
+		UpdatedAt: &now,
+	}
+	consumer.Href = consumersPath + "/" + consumer.ID
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.consumers[consumer.ID] = consumer
+	return consumer
+}
+
+// ResourceBundles returns the resource bundles published to consumer, by name
+func (s *Stub) ResourceBundles(consumer string) []maestroclient.ResourceBundle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var bundles []maestroclient.ResourceBundle
+	for _, rb := range s.bundles {
+		if rb.ConsumerName == consumer {
+			bundles = append(bundles, rb)
+		}
+	}
+	sort.Slice(bundles, func(i, j int) bool { return bundles[i].Name < bundles[j].Name })
+	return bundles
+}
+
+func (s *Stub) listConsumers(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	items := make([]maestroclient.Consumer, 0, len(s.consumers))
+	for _, c := range s.consumers {
+		items = append(items, c)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	page, size, pageItems := paginate(r, items)
+	writeJSON(w, http.StatusOK, maestroclient.ConsumerList{Kind: "ConsumerList", Page: page, Size: size, Total: len(items), Items: pageItems})
+}
+
+func (s *Stub) createConsumer(w http.ResponseWriter, r *http.Request) {
+	var req maestroclient.ConsumerCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Name == "" {
+		writeError(w, http.StatusBadRequest, "maestro-bad-request", "A consumer needs a name")
+		return
+	}
+	consumer := s.AddConsumer(req.Name)
+	if len(req.Labels) > 0 {
+		s.mu.Lock()
+		consumer.Labels = req.Labels
+		s.consumers[consumer.ID] = consumer
+		s.mu.Unlock()
+	}
+	writeJSON(w, http.StatusCreated, consumer)
+}
+
+// getAPI describes the API versions served, as Maestro does
+func getAPI(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"kind":     "API",
+		"id":       "maestro",
+		"href":     "/api/maestro",
+		"versions": []map[string]string{{"kind": "APIVersion", "id": "v1", "href": "/api/maestro/v1"}},
+	})
+}
+
+func (s *Stub) getConsumer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	consumer, ok := s.consumers[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "maestro-not-found", "Consumer not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, consumer)
+}
+
+func (s *Stub) deleteConsumer(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.consumers[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, "maestro-not-found", "Consumer not found")
+		return
+	}
+	delete(s.consumers, r.PathValue("id"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Stub) listResourceBundles(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	items := make([]maestroclient.ResourceBundle, 0, len(s.bundles))
+	for _, rb := range s.bundles {
+		items = append(items, rb)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	page, size, pageItems := paginate(r, items)
+	writeJSON(w, http.StatusOK, maestroclient.ResourceBundleList{Kind: "ResourceBundleList", Page: page, Size: size, Total: len(items), Items: pageItems})
+}
+
+func (s *Stub) getResourceBundle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	rb, ok := s.bundles[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, "maestro-not-found", "Resource bundle not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, rb)
+}
+
+func (s *Stub) deleteResourceBundle(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.bundles[r.PathValue("id")]; !ok {
+		writeError(w, http.StatusNotFound, "maestro-not-found", "Resource bundle not found")
+		return
+	}
+	delete(s.bundles, r.PathValue("id"))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// publish stores the ManifestWork a spec event carries as a resource bundle,
+// or removes it when the event marks the work deleted
+func (s *Stub) publish(ctx context.Context, evt *pbv1.CloudEvent) error {
+	event, err := binding.ToEvent(ctx, grpcprotocol.NewMessage(evt))
+	if err != nil {
+		return fmt.Errorf("failed to decode cloudevent: %w", err)
+	}
+	ext := event.Extensions()
+	id, _ := ext[cetypes.ExtensionResourceID].(string)
+	consumer, _ := ext[cetypes.ExtensionClusterName].(string)
+	if id == "" || consumer == "" {
+		return fmt.Errorf("cloudevent %s has no resource ID or cluster name", event.ID())
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, deleted := ext[cetypes.ExtensionDeletionTimestamp]; deleted {
+		delete(s.bundles, id)
+		return nil
+	}
+
+	var meta map[string]interface{}
+	if raw, ok := ext[cetypes.ExtensionWorkMeta].(string); ok {
+		if err := json.Unmarshal([]byte(raw), &meta); err != nil {
+			return fmt.Errorf("failed to decode work metadata: %w", err)
+		}
+	}
+	var bundle payload.ManifestBundle
+	if err := event.DataAs(&bundle); err != nil {
+		return fmt.Errorf("failed to decode manifest bundle: %w", err)
+	}
+	var spec struct {
+		Manifests       []interface{}          `json:"manifests"`
+		DeleteOption    map[string]interface{} `json:"deleteOption"`
+		ManifestConfigs []interface{}          `json:"manifestConfigs"`
+	}
+	data, _ := json.Marshal(bundle)
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return fmt.Errorf("failed to decode manifest bundle: %w", err)
+	}
+
+	now := time.Now().UTC()
+	rb, exists := s.bundles[id]
+	if !exists {
+		rb = maestroclient.ResourceBundle{
+			ID:           id,
+			Kind:         "ResourceBundle",
+			Href:         resourceBundlesPath + "/" + id,
+			ConsumerName: consumer,
+			CreatedAt:    &now,
+		}
+	}
+	rb.Name, _ = meta["name"].(string)
+	rb.Version++
+	rb.UpdatedAt = &now
+	rb.Metadata = meta
+	rb.Manifests = spec.Manifests
+	rb.DeleteOption = spec.DeleteOption
+	rb.ManifestConfigs = spec.ManifestConfigs
+	s.bundles[id] = rb
+	return nil
+}
+
+// cloudEventService accepts the spec events of the platform API's source
+// client. It sends no status events, so subscriptions only wait.
+type cloudEventService struct {
+	pbv1.UnimplementedCloudEventServiceServer
+	stub *Stub
+}
+
+func (c *cloudEventService) Publish(ctx context.Context, req *pbv1.PublishRequest) (*emptypb.Empty, error) {
+	if err := c.stub.publish(ctx, req.Event); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (c *cloudEventService) Subscribe(req *pbv1.SubscriptionRequest, stream pbv1.CloudEventService_SubscribeServer) error {
+	<-stream.Context().Done()
+	return nil
+}
+
+// paginate returns the page and size requested, defaulting to everything, and
+// the items on that page
+func paginate[T any](r *http.Request, items []T) (int, int, []T) {
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	if page < 1 {
+		page = 1
+	}
+	if size < 1 {
+		size = len(items)
+	}
+	start := min((page-1)*size, len(items))
+	return page, size, items[start:min(start+size, len(items))]
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, code, reason string) {
+	writeJSON(w, status, maestroclient.Error{Kind: "Error", Code: code, Reason: reason})
+}
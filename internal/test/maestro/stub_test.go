@@ -0,0 +1,72 @@
+package maestro
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	maestroclient "github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+)
+
+func TestStub_ServesTheMaestroClient(t *testing.T) {
+	stub, err := NewStub()
+	if err != nil {
+		t.Fatalf("failed to start stub: %v", err)
+	}
+	defer stub.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	client := maestroclient.NewClient(config.MaestroConfig{
+		BaseURL:     stub.BaseURL(),
+		GRPCBaseURL: stub.GRPCBaseURL(),
+		Timeout:     5 * time.Second,
+	}, logger)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	stub.AddConsumer("mc-1")
+	consumers, err := client.ListConsumers(ctx, 1, 10)
+	if err != nil {
+		t.Fatalf("failed to list consumers: %v", err)
+	}
+	if consumers.Total != 1 || consumers.Items[0].Name != "mc-1" {
+		t.Errorf("expected consumer mc-1, got %+v", consumers)
+	}
+
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "app"},
+		Spec: workv1.ManifestWorkSpec{Workload: workv1.ManifestsTemplate{Manifests: []workv1.Manifest{{
+			RawExtension: runtime.RawExtension{Raw: []byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"app","namespace":"default"}}`)},
+		}}}},
+	}
+	created, err := client.CreateManifestWork(ctx, "mc-1", mw)
+	if err != nil {
+		t.Fatalf("failed to create manifestwork: %v", err)
+	}
+	bundles := stub.ResourceBundles("mc-1")
+	if len(bundles) != 1 || bundles[0].ID != string(created.UID) || bundles[0].Name != "app" || len(bundles[0].Manifests) != 1 {
+		t.Fatalf("expected the work stored as a resource bundle, got %+v", bundles)
+	}
+
+	got, err := client.GetManifestWork(ctx, "mc-1", "app")
+	if err != nil {
+		t.Fatalf("failed to get manifestwork: %v", err)
+	}
+	if got.UID != created.UID || len(got.Spec.Workload.Manifests) != 1 {
+		t.Errorf("unexpected manifestwork %+v", got)
+	}
+
+	if err := client.DeleteManifestWork(ctx, "mc-1", "app"); err != nil {
+		t.Fatalf("failed to delete manifestwork: %v", err)
+	}
+	if bundles := stub.ResourceBundles("mc-1"); len(bundles) != 0 {
+		t.Errorf("expected the resource bundle deleted, got %+v", bundles)
+	}
+}
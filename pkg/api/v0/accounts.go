@@ -0,0 +1,125 @@
+package v0
+
+import (
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/policybackup"
+)
+
+// EnableAccountRequest is the request body for enabling an account
+type EnableAccountRequest struct {
+	AccountID  string `json:"accountId"`
+	Privileged bool   `json:"privileged"`
+}
+
+// AccountResponse is the response for account operations
+type AccountResponse struct {
+	Kind          string `json:"kind"`
+	AccountID     string `json:"accountId"`
+	PolicyStoreID string `json:"policyStoreId,omitempty"`
+	Privileged    bool   `json:"privileged"`
+	CreatedAt     string `json:"createdAt"`
+	CreatedBy     string `json:"createdBy"`
+	// OrganizationID is set when the account belongs to an organization
+	OrganizationID string `json:"organizationId,omitempty"`
+	// RequiredTags are the account's own required tag keys
+	RequiredTags []string `json:"requiredTags,omitempty"`
+	// ChangeReview is set when the account's authz mutations are staged
+	// for review
+	ChangeReview bool `json:"changeReview,omitempty"`
+	// Plan is the plan tier limiting the account; empty is the default plan
+	Plan string `json:"plan,omitempty"`
+	// PinnedRegion is the AWS region the account's data must stay in
+	PinnedRegion string `json:"pinnedRegion,omitempty"`
+	// ExportControl is the account's export-control classification
+	ExportControl string `json:"exportControl,omitempty"`
+	// Onboarding is the progress of enabling the account
+	Onboarding *store.Onboarding `json:"onboarding,omitempty"`
+}
+
+// SetRequiredTagsRequest is the request body for setting an account's
+// required tags
+type SetRequiredTagsRequest struct {
+	RequiredTags []string `json:"requiredTags"`
+}
+
+// SetChangeReviewRequest is the request body for turning an account's
+// change review mode on or off
+type SetChangeReviewRequest struct {
+	Enabled *bool `json:"enabled"`
+}
+
+// SetPlanRequest is the request body for moving an account to another plan
+type SetPlanRequest struct {
+	Plan string `json:"plan"`
+}
+
+// SetResidencyRequest is the request body for setting an account's
+// residency; empty fields clear them
+type SetResidencyRequest struct {
+	PinnedRegion  string `json:"pinnedRegion"`
+	ExportControl string `json:"exportControl"`
+}
+
+// AccountListResponse is the response for listing accounts
+type AccountListResponse struct {
+	Kind  string            `json:"kind"`
+	Items []AccountResponse `json:"items"`
+	Total int               `json:"total"`
+	// NextPageToken fetches the next page when passed as pageToken; it is
+	// omitted on the last page
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
+
+// AccountCountResponse is the response for counting accounts
+type AccountCountResponse struct {
+	Kind  string `json:"kind"`
+	Count int    `json:"count"`
+}
+
+// PrincipalAccountListResponse is the response for searching accounts by principal
+type PrincipalAccountListResponse struct {
+	Kind         string                    `json:"kind"`
+	PrincipalARN string                    `json:"principalArn"`
+	Items        []*authz.PrincipalAccount `json:"items"`
+	Total        int                       `json:"total"`
+}
+
+// RebuildPolicyStoreResponse is the response for rebuilding a policy store
+type RebuildPolicyStoreResponse struct {
+	Kind string `json:"kind"`
+	*authz.RebuildResult
+}
+
+// PolicyBackupListResponse is the response for listing policy store backups
+type PolicyBackupListResponse struct {
+	Kind  string                `json:"kind"`
+	Items []policybackup.Backup `json:"items"`
+	Total int                   `json:"total"`
+}
+
+// DeletionListResponse is the response for listing an account's tombstones
+type DeletionListResponse struct {
+	Kind  string             `json:"kind"`
+	Items []*store.Tombstone `json:"items"`
+	Total int                `json:"total"`
+}
+
+// SchemaMigrationResponse is the response for migrating a policy store schema
+type SchemaMigrationResponse struct {
+	Kind string `json:"kind"`
+	*authz.SchemaMigration
+}
+
+// BootstrapAccountRequest is the request body for bootstrapping the first
+// privileged account
+type BootstrapAccountRequest struct {
+	// AccountID defaults to the caller's account
+	AccountID string `json:"accountId,omitempty"`
+}
+
+// PurgeAccountRequest confirms an account purge
+type PurgeAccountRequest struct {
+	// Confirm must repeat the account ID
+	Confirm string `json:"confirm"`
+}
@@ -0,0 +1,14 @@
+package v0
+
+// AuditListResponse is a page of audited decisions. Items hold the
+// requested fields of each decision.
+type AuditListResponse struct {
+	Kind  string           `json:"kind"`
+	From  string           `json:"from"`
+	To    string           `json:"to"`
+	Items []map[string]any `json:"items"`
+	Total int              `json:"total"`
+	// NextPageToken fetches the next page when passed as pageToken; it is
+	// omitted on the last page
+	NextPageToken string `json:"nextPageToken,omitempty"`
+}
@@ -0,0 +1,275 @@
+package v0
+
+import (
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+type CreatePolicyRequest struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Policy      string            `json:"policy"` // Native Cedar policy text
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+type PolicyResponse struct {
+	Kind        string            `json:"kind"`
+	PolicyID    string            `json:"policyId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	CreatedAt   string            `json:"createdAt"`
+}
+
+type PolicyListResponse struct {
+	Kind  string           `json:"kind"`
+	Items []PolicyResponse `json:"items"`
+	Total int              `json:"total"`
+}
+
+type CreateGroupRequest struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+type GroupResponse struct {
+	Kind        string            `json:"kind"`
+	GroupID     string            `json:"groupId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	// MemberPattern is set on pattern groups, whose members are the
+	// principals seen matching it
+	MemberPattern string `json:"memberPattern,omitempty"`
+	CreatedAt     string `json:"createdAt"`
+}
+
+type GroupListResponse struct {
+	Kind  string          `json:"kind"`
+	Items []GroupResponse `json:"items"`
+	Total int             `json:"total"`
+}
+
+type UpdateMembersRequest struct {
+	Add    []string `json:"add"`
+	Remove []string `json:"remove"`
+	// Version, when set, is the members version the update is based on
+	Version *int64 `json:"version,omitempty"`
+}
+
+type MemberListResponse struct {
+	Kind    string   `json:"kind"`
+	Items   []string `json:"items"`
+	Total   int      `json:"total"`
+	Version int64    `json:"version"`
+}
+
+// MemberUpdateResponse is the response for updating group members: the
+// resulting member list and the outcome of each requested change
+type MemberUpdateResponse struct {
+	Kind    string               `json:"kind"`
+	Items   []string             `json:"items"`
+	Total   int                  `json:"total"`
+	Version int64                `json:"version"`
+	Results []authz.MemberResult `json:"results"`
+}
+
+// MemberConflictResponse is the error for a member update that lost to a
+// concurrent one, with the member list it should be retried against
+type MemberConflictResponse struct {
+	Kind    string   `json:"kind"`
+	Code    string   `json:"code"`
+	Reason  string   `json:"reason"`
+	Items   []string `json:"items"`
+	Total   int      `json:"total"`
+	Version int64    `json:"version"`
+}
+
+type CreateAttachmentRequest struct {
+	PolicyID    string `json:"policyId"`
+	TargetType  string `json:"targetType"` // "user", "role" or "group"
+	TargetID    string `json:"targetId"`   // ARN for user and role, groupId for group
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type UpdateAttachmentRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type AttachmentResponse struct {
+	Kind         string `json:"kind"`
+	AttachmentID string `json:"attachmentId"`
+	PolicyID     string `json:"policyId"`
+	TargetType   string `json:"targetType"`
+	TargetID     string `json:"targetId"`
+	Pattern      string `json:"pattern,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+	CreatedAt    string `json:"createdAt"`
+}
+
+type AttachmentListResponse struct {
+	Kind  string               `json:"kind"`
+	Items []AttachmentResponse `json:"items"`
+	Total int                  `json:"total"`
+}
+
+type AddAdminRequest struct {
+	PrincipalARN string `json:"principalArn"`
+}
+
+type AdminResponse struct {
+	Kind string `json:"kind"`
+	*store.Admin
+}
+
+type CheckAuthorizationRequest struct {
+	Principal    string            `json:"principal"`    // Principal ARN making the request
+	Action       string            `json:"action"`       // Action being performed (e.g., "rosa:CreateCluster")
+	Resource     string            `json:"resource"`     // Resource ARN (e.g., "arn:aws:rosa:us-west-2:123456789012:cluster/*")
+	Context      map[string]any    `json:"context"`      // Additional context (e.g., request tags)
+	ResourceTags map[string]string `json:"resourceTags"` // Tags on the resource
+}
+
+type CheckAuthorizationResponse struct {
+	Kind     string `json:"kind"`
+	Decision string `json:"decision"` // "ALLOW" or "DENY"
+	Reason   string `json:"reason,omitempty"`
+}
+
+type AdminListResponse struct {
+	Kind  string   `json:"kind"`
+	Items []string `json:"items"`
+	Total int      `json:"total"`
+}
+
+// PrincipalAccessResponse is the response for a principal's effective access
+type PrincipalAccessResponse struct {
+	Kind string `json:"kind"`
+	*authz.PrincipalAccess
+}
+
+// DecisionCountResponse is an hourly allow and deny count of one action
+type DecisionCountResponse struct {
+	Kind   string `json:"kind"`
+	Hour   string `json:"hour"`
+	Action string `json:"action"`
+	Allow  int64  `json:"allow"`
+	Deny   int64  `json:"deny"`
+}
+
+// ActionDecisionTotals sums an action's counts over the requested window
+type ActionDecisionTotals struct {
+	Action string `json:"action"`
+	Allow  int64  `json:"allow"`
+	Deny   int64  `json:"deny"`
+}
+
+// DecisionCountListResponse lists the hourly counts of a window, with the
+// totals of each action in it
+type DecisionCountListResponse struct {
+	Kind   string                  `json:"kind"`
+	From   string                  `json:"from"`
+	To     string                  `json:"to"`
+	Totals []ActionDecisionTotals  `json:"totals"`
+	Items  []DecisionCountResponse `json:"items"`
+	Total  int                     `json:"total"`
+}
+
+// RecommendationResponse is a policy or group recommended for pruning
+type RecommendationResponse struct {
+	Kind string `json:"kind"`
+	*authz.Recommendation
+}
+
+// RecommendationListResponse lists an account's pruning recommendations.
+// PolicyUsage is false when decision analytics is disabled, in which case
+// unused policies are not looked for.
+type RecommendationListResponse struct {
+	Kind        string                   `json:"kind"`
+	UnusedDays  int                      `json:"unusedDays"`
+	PolicyUsage bool                     `json:"policyUsage"`
+	Items       []RecommendationResponse `json:"items"`
+	Total       int                      `json:"total"`
+}
+
+// ObservedPrincipalResponse is a caller ARN seen in the account's decisions
+type ObservedPrincipalResponse struct {
+	Kind         string `json:"kind"`
+	PrincipalARN string `json:"principalArn"`
+	LastSeenAt   string `json:"lastSeenAt"`
+}
+
+// ObservedPrincipalListResponse lists the callers seen in the last Days
+// days, most recently seen first
+type ObservedPrincipalListResponse struct {
+	Kind  string                      `json:"kind"`
+	Days  int                         `json:"days"`
+	Items []ObservedPrincipalResponse `json:"items"`
+	Total int                         `json:"total"`
+}
+
+// CreateAPIKeyRequest is the request body for minting an API key
+type CreateAPIKeyRequest struct {
+	// Name is the service account the key acts as
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Actions     []string `json:"actions"`
+	ExpiresAt   string   `json:"expiresAt,omitempty"`
+}
+
+// RotateAPIKeyRequest is the request body for rotating an API key
+type RotateAPIKeyRequest struct {
+	// Overlap is how long the rotated key keeps working, as a Go duration;
+	// empty or "0s" revokes it at once
+	Overlap string `json:"overlap,omitempty"`
+	// ExpiresAt is the optional expiry of the new key
+	ExpiresAt string `json:"expiresAt,omitempty"`
+}
+
+// APIKeyResponse is the response for API key operations. Key is only set
+// when a key is minted or rotated: it cannot be retrieved again.
+type APIKeyResponse struct {
+	Kind string `json:"kind"`
+	*store.APIKey
+	Key string `json:"key,omitempty"`
+}
+
+// APIKeyListResponse is the response for listing API keys
+type APIKeyListResponse struct {
+	Kind  string           `json:"kind"`
+	Items []APIKeyResponse `json:"items"`
+	Total int              `json:"total"`
+}
+
+// PolicyDiffResponse compares a policy's current Cedar text with a proposed one
+type PolicyDiffResponse struct {
+	Kind     string `json:"kind"`
+	PolicyID string `json:"policyId"`
+	// ChangeID is the staged change request diffed against, if any
+	ChangeID string `json:"changeId,omitempty"`
+	*authz.PolicyDiff
+}
+
+// FormatPolicyRequest carries Cedar text to format
+type FormatPolicyRequest struct {
+	Policy string `json:"policy"`
+}
+
+// FormattedPolicyResponse is Cedar text in the canonical layout policies are
+// stored in
+type FormattedPolicyResponse struct {
+	Kind   string `json:"kind"`
+	Policy string `json:"policy"`
+	// Changed is false when the text was already formatted
+	Changed bool `json:"changed"`
+}
+
+// SetTagsRequest replaces the tags of a policy or group; empty tags remove
+// them all
+type SetTagsRequest struct {
+	Tags map[string]string `json:"tags"`
+}
@@ -0,0 +1,35 @@
+package v0
+
+import (
+	"encoding/json"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// ChangeRequestResponse is a staged mutation. Result is the response body the
+// request got when it was applied on approval.
+type ChangeRequestResponse struct {
+	Kind string `json:"kind"`
+	*store.ChangeRequest
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+// ChangeRequestListResponse is the response for listing change requests
+type ChangeRequestListResponse struct {
+	Kind  string                 `json:"kind"`
+	Items []*store.ChangeRequest `json:"items"`
+	Total int                    `json:"total"`
+}
+
+// PendingChangeResponse is a change waiting for a second admin's approval
+type PendingChangeResponse struct {
+	Kind string `json:"kind"`
+	*store.PendingChange
+}
+
+// PendingChangeListResponse is the response for listing pending changes
+type PendingChangeListResponse struct {
+	Kind  string                 `json:"kind"`
+	Items []*store.PendingChange `json:"items"`
+	Total int                    `json:"total"`
+}
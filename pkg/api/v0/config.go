@@ -0,0 +1,9 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/config"
+
+// ConfigReloadResponse is the response for a configuration reload
+type ConfigReloadResponse struct {
+	Kind string `json:"kind"`
+	config.ReloadReport
+}
@@ -0,0 +1,23 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+
+// CreateDelegationRequest is the request body for delegating an account
+type CreateDelegationRequest struct {
+	DelegateAccountID string   `json:"delegateAccountId"`
+	Actions           []string `json:"actions"`
+	ExpiresAt         string   `json:"expiresAt,omitempty"`
+}
+
+// DelegationResponse is the response for delegation operations
+type DelegationResponse struct {
+	Kind string `json:"kind"`
+	*store.Delegation
+}
+
+// DelegationListResponse is the response for listing delegations
+type DelegationListResponse struct {
+	Kind  string               `json:"kind"`
+	Items []DelegationResponse `json:"items"`
+	Total int                  `json:"total"`
+}
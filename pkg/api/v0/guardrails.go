@@ -0,0 +1,22 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+
+// CreateGuardrailRequest is the request body for adding a guardrail
+type CreateGuardrailRequest struct {
+	Description string `json:"description,omitempty"`
+	CedarPolicy string `json:"cedarPolicy"`
+}
+
+// GuardrailResponse is the response for guardrail operations
+type GuardrailResponse struct {
+	Kind string `json:"kind"`
+	*store.StaticPolicy
+}
+
+// GuardrailListResponse is the response for listing guardrails
+type GuardrailListResponse struct {
+	Kind  string              `json:"kind"`
+	Items []GuardrailResponse `json:"items"`
+	Total int                 `json:"total"`
+}
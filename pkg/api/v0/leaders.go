@@ -0,0 +1,12 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/leader"
+
+// LeaderList is the leadership of every background worker
+type LeaderList struct {
+	Kind string `json:"kind"`
+	// Identity is the replica that served the request
+	Identity string          `json:"identity"`
+	Items    []leader.Status `json:"items"`
+	Total    int             `json:"total"`
+}
@@ -0,0 +1,11 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/maestrofailover"
+
+// MaestroEndpointListResponse is the response for listing Maestro endpoints
+type MaestroEndpointListResponse struct {
+	Kind  string                           `json:"kind"`
+	Items []maestrofailover.EndpointStatus `json:"items"`
+	// LastSwitch is the last change of the active endpoint on this replica
+	LastSwitch *maestrofailover.Switch `json:"last_switch,omitempty"`
+}
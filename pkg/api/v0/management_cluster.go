@@ -0,0 +1,40 @@
+package v0
+
+import (
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+)
+
+// ManagementClusterCreateRequest registers a management cluster, optionally
+// recording its capabilities at once
+type ManagementClusterCreateRequest struct {
+	maestro.ConsumerCreateRequest
+	Capabilities *ManagementClusterCapabilitiesRequest `json:"capabilities,omitempty"`
+}
+
+// ManagementClusterCapabilities is what a management cluster is known to
+// serve, checked against the manifests of works targeting it
+type ManagementClusterCapabilities struct {
+	Kind string `json:"kind"`
+	ID   string `json:"id"`
+	clustercaps.Capabilities
+}
+
+// ManagementClusterCapabilitiesRequest replaces a management cluster's
+// capabilities
+type ManagementClusterCapabilitiesRequest struct {
+	APIResources      []string `json:"api_resources"`
+	KubernetesVersion string   `json:"kubernetes_version,omitempty"`
+	CRDs              []string `json:"crds,omitempty"`
+	AgentVersion      string   `json:"agent_version,omitempty"`
+}
+
+// ManagementClusterDeregistration is the response to a deregistration, which
+// carries on in the background as operation OperationID
+type ManagementClusterDeregistration struct {
+	Kind            string `json:"kind"`
+	ID              string `json:"id"`
+	Name            string `json:"name"`
+	ResourceBundles int    `json:"resource_bundles"`
+	OperationID     string `json:"operation_id,omitempty"`
+}
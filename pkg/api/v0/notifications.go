@@ -0,0 +1,36 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/notify"
+
+// NotificationSettingsResponse is an account's notification settings. The
+// webhook secret is never returned.
+type NotificationSettingsResponse struct {
+	Kind string `json:"kind"`
+	*notify.Settings
+}
+
+// NotificationTestResponse is the outcome of a test notification per channel
+type NotificationTestResponse struct {
+	Kind       string            `json:"kind"`
+	AccountID  string            `json:"accountId"`
+	Deliveries []notify.Delivery `json:"deliveries"`
+}
+
+// RotateWebhookSecretRequest is the request body for rotating a webhook
+// secret
+type RotateWebhookSecretRequest struct {
+	// Secret is the new secret; empty generates one
+	Secret string `json:"secret,omitempty"`
+	// Overlap is how long the old secret keeps signing requests, as a Go
+	// duration; empty or "0s" retires it at once
+	Overlap string `json:"overlap,omitempty"`
+}
+
+// WebhookSecretResponse is the outcome of a webhook secret rotation. Secret
+// is only returned here: it cannot be retrieved again.
+type WebhookSecretResponse struct {
+	Kind                    string `json:"kind"`
+	AccountID               string `json:"accountId"`
+	Secret                  string `json:"secret,omitempty"`
+	PreviousSecretExpiresAt string `json:"previousSecretExpiresAt,omitempty"`
+}
@@ -0,0 +1,16 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/operations"
+
+// OperationResponse is the response for a single operation
+type OperationResponse struct {
+	Kind string `json:"kind"`
+	operations.Operation
+}
+
+// OperationListResponse is the response for listing operations
+type OperationListResponse struct {
+	Kind  string              `json:"kind"`
+	Items []OperationResponse `json:"items"`
+	Total int                 `json:"total"`
+}
@@ -0,0 +1,47 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+
+// CreateOrganizationRequest is the request body for creating an organization
+type CreateOrganizationRequest struct {
+	Name string `json:"name"`
+}
+
+// OrganizationResponse is the response for organization operations
+type OrganizationResponse struct {
+	Kind string `json:"kind"`
+	*store.Organization
+}
+
+// OrganizationListResponse is the response for listing organizations
+type OrganizationListResponse struct {
+	Kind  string                 `json:"kind"`
+	Items []OrganizationResponse `json:"items"`
+	Total int                    `json:"total"`
+}
+
+// CreateOrganizationPolicyRequest is the request body for adding an
+// organization policy
+type CreateOrganizationPolicyRequest struct {
+	Description string `json:"description,omitempty"`
+	CedarPolicy string `json:"cedarPolicy"`
+}
+
+// OrganizationPolicyResponse is the response for organization policy operations
+type OrganizationPolicyResponse struct {
+	Kind string `json:"kind"`
+	*store.OrganizationPolicy
+}
+
+// OrganizationPolicyListResponse is the response for listing organization policies
+type OrganizationPolicyListResponse struct {
+	Kind  string                       `json:"kind"`
+	Items []OrganizationPolicyResponse `json:"items"`
+	Total int                          `json:"total"`
+}
+
+// SetAccountOrganizationRequest is the request body for adding an account to
+// an organization
+type SetAccountOrganizationRequest struct {
+	OrganizationID string `json:"organizationId"`
+}
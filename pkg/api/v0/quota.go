@@ -0,0 +1,29 @@
+package v0
+
+// QuotaResponse is the response for the caller's quota
+type QuotaResponse struct {
+	Kind      string      `json:"kind"`
+	AccountID string      `json:"accountId"`
+	Plan      string      `json:"plan"`
+	Limits    QuotaLimits `json:"limits"`
+	Usage     QuotaUsage  `json:"usage"`
+}
+
+// QuotaLimits is what the caller's plan allows. Zero limits are unlimited
+// and omitted.
+type QuotaLimits struct {
+	// RequestsPerWindow is the requests allowed per RateLimitWindow
+	RequestsPerWindow int    `json:"requestsPerWindow,omitempty"`
+	RateLimitWindow   string `json:"rateLimitWindow,omitempty"`
+	// MaxManifests is the manifests one work may hold
+	MaxManifests int `json:"maxManifests,omitempty"`
+	// BulkSubmission is whether chunked works may be submitted
+	BulkSubmission bool `json:"bulkSubmission"`
+}
+
+// QuotaUsage is how much of its limits the caller has used
+type QuotaUsage struct {
+	// Requests made in the current rate limit window; omitted when requests
+	// are not counted
+	Requests *int `json:"requests,omitempty"`
+}
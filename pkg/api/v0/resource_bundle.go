@@ -0,0 +1,27 @@
+package v0
+
+import "time"
+
+// ConditionCounts counts resource bundles by the status of one condition
+type ConditionCounts struct {
+	True    int `json:"True"`
+	False   int `json:"False"`
+	Unknown int `json:"Unknown"`
+}
+
+// ClusterBundleSummary summarizes the resource bundles of one management
+// cluster
+type ClusterBundleSummary struct {
+	Total      int                        `json:"total"`
+	Conditions map[string]ConditionCounts `json:"conditions"`
+}
+
+// ResourceBundleSummary counts resource bundles by condition status, across
+// the region and per management cluster
+type ResourceBundleSummary struct {
+	Kind       string                          `json:"kind"`
+	Total      int                             `json:"total"`
+	Conditions map[string]ConditionCounts      `json:"conditions"`
+	Clusters   map[string]ClusterBundleSummary `json:"clusters"`
+	ComputedAt time.Time                       `json:"computedAt"`
+}
@@ -0,0 +1,21 @@
+package v0
+
+// RouteInfo describes a registered API route
+type RouteInfo struct {
+	// Path is the path template, or the prefix of a prefix route
+	Path string `json:"path"`
+	// Methods the route answers; absent when it answers every method
+	Methods []string `json:"methods,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	Handler string   `json:"handler"`
+	// Middleware lists what runs before the handler, outermost first
+	Middleware []string `json:"middleware"`
+}
+
+// RouteListResponse is the response for listing routes
+type RouteListResponse struct {
+	Kind    string      `json:"kind"`
+	Profile string      `json:"profile"`
+	Items   []RouteInfo `json:"items"`
+	Total   int         `json:"total"`
+}
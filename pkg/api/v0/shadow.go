@@ -0,0 +1,12 @@
+package v0
+
+import "github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+
+// ShadowReportResponse is the response for the shadow authorization report
+type ShadowReportResponse struct {
+	Kind     string                     `json:"kind"`
+	Items    []middleware.ShadowAccount `json:"items"`
+	Total    int                        `json:"total"`
+	Requests int64                      `json:"requests"`
+	Diverged int64                      `json:"diverged"`
+}
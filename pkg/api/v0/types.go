@@ -0,0 +1,84 @@
+// Package v0 holds the request and response bodies of the /api/v0 endpoints,
+// so the handlers, Go clients and the OpenAPI document share one definition
+// of each shape instead of building untyped maps.
+package v0
+
+import (
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/types"
+)
+
+// Error is the body of every error response
+type Error struct {
+	Kind   string `json:"kind"`
+	Code   string `json:"code"`
+	Reason string `json:"reason"`
+}
+
+// NewError returns the error response for code
+func NewError(code, reason string) Error {
+	return Error{Kind: "Error", Code: code, Reason: reason}
+}
+
+// Status is the body of the liveness, startup and readiness probes. The
+// readiness probe lists the state of each component reporting one.
+type Status struct {
+	Status     string                    `json:"status"`
+	Components map[string]ComponentState `json:"components,omitempty"`
+}
+
+// ComponentState is the last reported health of a server component
+type ComponentState struct {
+	Healthy bool `json:"healthy"`
+	// Critical components fail readiness while unhealthy; others are reported only
+	Critical bool      `json:"critical"`
+	Reason   string    `json:"reason,omitempty"`
+	Since    time.Time `json:"since"`
+}
+
+// LambdaExecutor is the response of GET /api/v0/info
+type LambdaExecutor struct {
+	ARN string `json:"arn"`
+}
+
+// ClusterList is the response of GET /api/v0/clusters
+type ClusterList struct {
+	Kind   string           `json:"kind"`
+	Items  []*types.Cluster `json:"items"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+// ClusterDeletion is the response of DELETE /api/v0/clusters/{id}
+type ClusterDeletion struct {
+	Message   string `json:"message"`
+	ClusterID string `json:"cluster_id"`
+}
+
+// NodePoolList is the response of GET /api/v0/nodepools
+type NodePoolList struct {
+	Kind   string            `json:"kind"`
+	Items  []*types.NodePool `json:"items"`
+	Total  int               `json:"total"`
+	Limit  int               `json:"limit"`
+	Offset int               `json:"offset"`
+}
+
+// NodePoolDeletion is the response of DELETE /api/v0/nodepools/{id}
+type NodePoolDeletion struct {
+	Message    string `json:"message"`
+	NodePoolID string `json:"nodepool_id"`
+}
+
+// DryRunResult answers a dry run (see middleware.DryRun): the request passed
+// validation and authorization, but nothing was changed
+type DryRunResult struct {
+	Kind string `json:"kind"`
+	// Status is the status the request would have been answered with
+	Status int `json:"status"`
+	// Preview is what the request would have created or changed, or the
+	// resource it would have deleted
+	Preview any `json:"preview,omitempty"`
+}
@@ -0,0 +1,129 @@
+package v0
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/zoa"
+)
+
+func TestRoundTrip(t *testing.T) {
+	runAt := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		// body is a pointer to the value to encode; decoding into a new
+		// value of the same type must give it back
+		body any
+		// wire is the expected encoding, when pinned
+		wire string
+	}{
+		{
+			name: "error",
+			body: ptr(NewError("not-found", "Cluster not found")),
+			wire: `{"kind":"Error","code":"not-found","reason":"Cluster not found"}`,
+		},
+		{
+			name: "dry run",
+			body: &DryRunResult{Kind: "DryRun", Status: 201, Preview: map[string]any{"kind": "Account", "accountId": "123456789012"}},
+			wire: `{"kind":"DryRun","status":201,"preview":{"accountId":"123456789012","kind":"Account"}}`,
+		},
+		{
+			name: "work request",
+			body: &WorkRequest{
+				ClusterID: "mc-1",
+				Data:      map[string]any{"kind": "ManifestWork"},
+				Chunk:     true,
+				Schedule:  &WorkScheduleSpec{RunAt: &runAt},
+				Chart:     &WorkChart{Repository: "oci://quay.io/example/charts/app", Version: "1.0.0", Values: map[string]any{"replicas": float64(2)}},
+				PayloadRef: &WorkPayloadRef{
+					URI:             "s3://bucket/key",
+					Digest:          "sha256:abc",
+					SignatureBundle: json.RawMessage(`{"mediaType":"bundle"}`),
+				},
+				OnBehalfOfAccount: "123456789012",
+				Requires:          &clustercaps.Requirements{KubernetesVersion: ">= 1.28", CRDs: []string{"widgets.example.com"}},
+			},
+		},
+		{
+			name: "work schedule spec",
+			body: &WorkScheduleSpec{Cron: "@daily"},
+			wire: `{"cron":"@daily"}`,
+		},
+		{
+			name: "account",
+			body: &AccountResponse{
+				Kind:          "Account",
+				AccountID:     "123456789012",
+				PolicyStoreID: "ps-1",
+				CreatedAt:     "2026-03-01T12:00:00Z",
+				CreatedBy:     "arn:aws:iam::123456789012:user/admin",
+				RequiredTags:  []string{"team"},
+				Onboarding:    &store.Onboarding{},
+			},
+		},
+		{
+			name: "attachment list",
+			body: &AttachmentListResponse{
+				Kind:  "AttachmentList",
+				Items: []AttachmentResponse{{Kind: "Attachment", AttachmentID: "att-1", PolicyID: "pol-1", TargetType: "user", TargetID: "arn:aws:iam::123456789012:user/alice", CreatedAt: "2026-03-01T12:00:00Z"}},
+				Total: 1,
+			},
+			wire: `{"kind":"AttachmentList","items":[{"kind":"Attachment","attachmentId":"att-1","policyId":"pol-1","targetType":"user","targetId":"arn:aws:iam::123456789012:user/alice","createdAt":"2026-03-01T12:00:00Z"}],"total":1}`,
+		},
+		{
+			name: "management cluster capabilities",
+			body: &ManagementClusterCapabilities{
+				Kind:         "ManagementClusterCapabilities",
+				ID:           "consumer-1",
+				Capabilities: clustercaps.Capabilities{ClusterID: "mc-1", APIResources: []string{"apps/v1/Deployment"}, KubernetesVersion: "1.30.2"},
+			},
+		},
+		{
+			name: "route list",
+			body: &RouteListResponse{
+				Kind:    "RouteList",
+				Profile: "default",
+				Items:   []RouteInfo{{Path: "/api/v0/live", Methods: []string{"GET"}, Handler: "handlers.HealthHandler.Liveness", Middleware: []string{}}},
+				Total:   1,
+			},
+		},
+		{
+			name: "trusted action list",
+			body: &TrustedActionList{Kind: "TrustedActionList", Items: []zoa.TAListItem{{Name: "restart", Scope: "cluster", Type: "write", Description: "Restart a deployment"}}, Total: 1},
+			wire: `{"kind":"TrustedActionList","items":[{"name":"restart","scope":"cluster","type":"write","description":"Restart a deployment"}],"total":1}`,
+		},
+		{
+			name: "trusted action audit list",
+			body: &TrustedActionAuditList{Kind: "AuditList", Items: []*zoa.AuditEntry{{ID: "audit-1", AccountID: "123456789012"}}, Total: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("failed to marshal: %v", err)
+			}
+			if tt.wire != "" && string(data) != tt.wire {
+				t.Errorf("encoded %s, want %s", data, tt.wire)
+			}
+
+			decoded := reflect.New(reflect.TypeOf(tt.body).Elem()).Interface()
+			if err := json.Unmarshal(data, decoded); err != nil {
+				t.Fatalf("failed to unmarshal %s: %v", data, err)
+			}
+			if !reflect.DeepEqual(decoded, tt.body) {
+				t.Errorf("round trip gave %+v, want %+v", decoded, tt.body)
+			}
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
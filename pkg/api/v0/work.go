@@ -0,0 +1,214 @@
+package v0
+
+import (
+	"encoding/json"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+)
+
+// Work is a ManifestWork submitted through POST /api/v0/work
+type Work struct {
+	ID                string                    `json:"id"`
+	Kind              string                    `json:"kind"`
+	Href              string                    `json:"href"`
+	ClusterID         string                    `json:"cluster_id"`
+	Name              string                    `json:"name"`
+	Status            workv1.ManifestWorkStatus `json:"status"`
+	ContentHash       string                    `json:"content_hash,omitempty"`
+	Checksum          string                    `json:"checksum,omitempty"`
+	OnBehalfOfAccount string                    `json:"on_behalf_of_account,omitempty"`
+	// Deduplicated is set when an identical submission returned the work
+	// created by an earlier one
+	Deduplicated bool `json:"deduplicated,omitempty"`
+}
+
+// WorkPreview is the ManifestWork a dry run would have sent to Maestro
+type WorkPreview struct {
+	Kind              string               `json:"kind"`
+	ClusterID         string               `json:"cluster_id"`
+	Name              string               `json:"name"`
+	ManifestWork      *workv1.ManifestWork `json:"manifestwork"`
+	ContentHash       string               `json:"content_hash,omitempty"`
+	Checksum          string               `json:"checksum,omitempty"`
+	OnBehalfOfAccount string               `json:"on_behalf_of_account,omitempty"`
+}
+
+// WorkRecord is a work recorded in the metadata store, as listed by
+// GET /api/v0/work
+type WorkRecord struct {
+	ID                string `json:"id"`
+	Kind              string `json:"kind"`
+	Href              string `json:"href"`
+	ClusterID         string `json:"cluster_id"`
+	Name              string `json:"name"`
+	SubmittedBy       string `json:"submitted_by"`
+	AccountID         string `json:"account_id"`
+	CreatedAt         string `json:"created_at"`
+	OnBehalfOfAccount string `json:"on_behalf_of_account,omitempty"`
+	ContentHash       string `json:"content_hash,omitempty"`
+	Checksum          string `json:"checksum,omitempty"`
+}
+
+// WorkList is the response of GET /api/v0/work
+type WorkList struct {
+	Kind  string       `json:"kind"`
+	Items []WorkRecord `json:"items"`
+	Total int          `json:"total"`
+}
+
+// WorkGroup is the response of a chunked work submission, one item per chunk
+type WorkGroup struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	ClusterID string `json:"cluster_id"`
+	Href      string `json:"href"`
+	Items     []Work `json:"items"`
+	Total     int    `json:"total"`
+	Checksum  string `json:"checksum,omitempty"`
+}
+
+// WorkGroupPreview is the dry run of a chunked work submission
+type WorkGroupPreview struct {
+	Kind      string        `json:"kind"`
+	Name      string        `json:"name"`
+	ClusterID string        `json:"cluster_id"`
+	Items     []WorkPreview `json:"items"`
+	Total     int           `json:"total"`
+	Checksum  string        `json:"checksum,omitempty"`
+}
+
+// WorkChunkStatus is the status of one chunk of a work group
+type WorkChunkStatus struct {
+	Name   string                    `json:"name"`
+	Status workv1.ManifestWorkStatus `json:"status"`
+}
+
+// WorkGroupStatus is the response of GET /api/v0/work/groups/{id}, with
+// the conditions of the chunks combined into the group's
+type WorkGroupStatus struct {
+	Kind       string             `json:"kind"`
+	Name       string             `json:"name"`
+	ClusterID  string             `json:"cluster_id"`
+	Total      int                `json:"total"`
+	Items      []WorkChunkStatus  `json:"items"`
+	Conditions []metav1.Condition `json:"conditions"`
+}
+
+// WorkSchedule is a scheduled work submission
+type WorkSchedule struct {
+	ID           string `json:"id"`
+	Kind         string `json:"kind"`
+	Href         string `json:"href"`
+	ClusterID    string `json:"cluster_id"`
+	Status       string `json:"status"`
+	CreatedBy    string `json:"created_by"`
+	CreatedAt    string `json:"created_at"`
+	RunCount     int    `json:"run_count"`
+	RunAt        string `json:"run_at,omitempty"`
+	Cron         string `json:"cron,omitempty"`
+	NextRunAt    string `json:"next_run_at,omitempty"`
+	LastRunAt    string `json:"last_run_at,omitempty"`
+	LastWorkName string `json:"last_work_name,omitempty"`
+	LastError    string `json:"last_error,omitempty"`
+}
+
+// WorkScheduleList is the response of GET /api/v0/work/schedules
+type WorkScheduleList struct {
+	Kind  string         `json:"kind"`
+	Items []WorkSchedule `json:"items"`
+	Total int            `json:"total"`
+}
+
+// WorkTransition is a condition of a work taking a new status
+type WorkTransition struct {
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+	Message        string `json:"message,omitempty"`
+	TransitionedAt string `json:"transitioned_at"`
+	ObservedAt     string `json:"observed_at"`
+}
+
+// WorkHistory is the response of GET /api/v0/work/{id}/history, oldest
+// transition first. With at, Conditions holds the status of each condition
+// at that time.
+type WorkHistory struct {
+	Kind       string           `json:"kind"`
+	ID         string           `json:"id"`
+	ClusterID  string           `json:"cluster_id"`
+	Name       string           `json:"name"`
+	Items      []WorkTransition `json:"items"`
+	Total      int              `json:"total"`
+	At         string           `json:"at,omitempty"`
+	Conditions []WorkTransition `json:"conditions,omitempty"`
+}
+
+// WorkRequest represents the request payload for creating manifestwork
+type WorkRequest struct {
+	ClusterID      string                 `json:"cluster_id"`
+	Data           map[string]interface{} `json:"data"`
+	EncryptSecrets bool                   `json:"encrypt_secrets,omitempty"`
+	// Chunk splits the work across multiple ManifestWorks when it exceeds
+	// the transport message size limit
+	Chunk bool `json:"chunk,omitempty"`
+	// Schedule submits the work later, once or repeatedly, instead of now
+	Schedule *WorkScheduleSpec `json:"schedule,omitempty"`
+	// Priority orders the work against other waiting submissions when the
+	// submission limit is reached; empty means normal
+	Priority string `json:"priority,omitempty"`
+	// Checksum is the hex SHA-256 of the data field exactly as sent. When
+	// set, a payload whose data does not match it is rejected.
+	Checksum string `json:"checksum,omitempty"`
+	// Chart is rendered into the work's manifests instead of sending data
+	Chart *WorkChart `json:"chart,omitempty"`
+	// PayloadRef fetches the work's manifests from a stored payload instead
+	// of sending data
+	PayloadRef *WorkPayloadRef `json:"payload_ref,omitempty"`
+	// OnBehalfOfAccount submits the work for this tenant account. Only
+	// privileged accounts may set it, and the work is then listed as the
+	// tenant's.
+	OnBehalfOfAccount string `json:"on_behalf_of_account,omitempty"`
+	// Requires rejects the work unless the target management cluster's
+	// recorded capabilities meet it
+	Requires *clustercaps.Requirements `json:"requires,omitempty"`
+}
+
+// WorkChart is a Helm chart rendered into the manifests of a work. The work
+// is named after release_name when it is set.
+type WorkChart struct {
+	// Repository is the chart's OCI reference without a tag, such as
+	// oci://quay.io/example/charts/app
+	Repository  string                 `json:"repository"`
+	Version     string                 `json:"version"`
+	Values      map[string]interface{} `json:"values,omitempty"`
+	ReleaseName string                 `json:"release_name,omitempty"`
+	Namespace   string                 `json:"namespace,omitempty"`
+}
+
+// WorkPayloadRef points at a stored payload of YAML or JSON manifests, or of
+// a lone ManifestWork, pinned by its digest
+type WorkPayloadRef struct {
+	// URI is oci://host/repository for a blob of an OCI repository, or
+	// s3://bucket/key for an S3 object
+	URI string `json:"uri"`
+	// Digest is the payload's sha256:<hex> digest
+	Digest string `json:"digest"`
+	// Name names the ManifestWork manifests are wrapped into
+	Name string `json:"name,omitempty"`
+	// SignatureBundle is a Sigstore bundle signing Digest, as written by
+	// cosign sign-blob --new-bundle-format
+	SignatureBundle json.RawMessage `json:"signature_bundle,omitempty"`
+}
+
+// WorkScheduleSpec delays a work request to a future time or repeats it on
+// a cron schedule. Exactly one of RunAt and Cron is set.
+type WorkScheduleSpec struct {
+	RunAt *time.Time `json:"run_at,omitempty"`
+	// Cron is a five-field cron expression or descriptor such as @daily,
+	// evaluated in UTC
+	Cron string `json:"cron,omitempty"`
+}
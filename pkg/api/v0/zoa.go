@@ -0,0 +1,30 @@
+package v0
+
+import (
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/zoa"
+)
+
+// TrustedActionRunPreview previews a dry run of
+// POST /api/v0/trusted-actions/{action}/run: the execution that would be
+// recorded and the ManifestWork that would run it
+type TrustedActionRunPreview struct {
+	Kind         string               `json:"kind"`
+	Execution    *zoa.Execution       `json:"execution"`
+	ManifestWork *workv1.ManifestWork `json:"manifestwork"`
+}
+
+// TrustedActionList is the response of GET /api/v0/trusted-actions
+type TrustedActionList struct {
+	Kind  string           `json:"kind"`
+	Items []zoa.TAListItem `json:"items"`
+	Total int              `json:"total"`
+}
+
+// TrustedActionAuditList is the response of GET /api/v0/trusted-actions/audit
+type TrustedActionAuditList struct {
+	Kind  string            `json:"kind"`
+	Items []*zoa.AuditEntry `json:"items"`
+	Total int               `json:"total"`
+}
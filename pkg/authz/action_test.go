@@ -0,0 +1,57 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// TestActionParity checks that the action AVP is asked about and the action
+// the cedar-agent mock evaluates are the same for every spelling of an action
+func TestActionParity(t *testing.T) {
+	var agentAction string
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/is_authorized" {
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			agentAction, _ = body["action"].(string)
+			_, _ = w.Write([]byte(`{"decision":"Allow"}`))
+		}
+	}))
+	defer agent.Close()
+
+	a := &authorizerImpl{cfg: DefaultConfig()}
+	mock := client.NewMockAVPClient(agent.URL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, action := range []string{"DescribeCluster", "rosa:DescribeCluster", "ROSA:DescribeCluster", "GetCluster", "rosa:GetCluster"} {
+		t.Run(action, func(t *testing.T) {
+			input := a.buildAVPRequest(&AuthzRequest{
+				AccountID: "123456789012",
+				CallerARN: "arn:aws:iam::123456789012:user/alice",
+				Action:    action,
+				Resource:  "arn:aws:rosa:us-east-1:123456789012:cluster/c1",
+			}, nil, "store-1")
+
+			avpAction := fmt.Sprintf("%s::%q", aws.ToString(input.Action.ActionType), aws.ToString(input.Action.ActionId))
+			if avpAction != `ROSA::Action::"DescribeCluster"` {
+				t.Errorf("AVP request action = %s, want the canonical DescribeCluster", avpAction)
+			}
+
+			if _, err := mock.IsAuthorized(context.Background(), input); err != nil {
+				t.Fatalf("IsAuthorized: %v", err)
+			}
+			if agentAction != avpAction {
+				t.Errorf("cedar-agent action %s differs from AVP action %s", agentAction, avpAction)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package authz
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// APIKeyPrefix starts every API key, so keys are recognizable in headers
+// and by secret scanners. A key is APIKeyPrefix, the key ID, "_" and the
+// secret.
+const APIKeyPrefix = "rosa_"
+
+// ServiceAccountPath is the IAM path of the synthetic principals API keys
+// act as: a key named ci of account 123456789012 acts as
+// arn:aws:iam::123456789012:user/rosa-service-accounts/ci
+const ServiceAccountPath = "/rosa-service-accounts/"
+
+// MaxAPIKeyOverlap caps how long a rotated key keeps working next to the
+// key that replaced it
+const MaxAPIKeyOverlap = 7 * 24 * time.Hour
+
+var (
+	// ErrInvalidAPIKey is returned for a key that is malformed, unknown,
+	// revoked or expired
+	ErrInvalidAPIKey = errors.New("invalid API key")
+	// ErrAPIKeyNotFound is returned for a key ID the account does not own
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrAPIKeyInactive is returned when rotating a revoked or expired key
+	ErrAPIKeyInactive = errors.New("API key is revoked or expired")
+	// ErrPrivilegedAPIKey is returned when minting a key in a privileged
+	// account; keys must not carry the privileged bypass
+	ErrPrivilegedAPIKey = errors.New("privileged accounts cannot mint API keys")
+	// ErrInvalidServiceAccountName is returned for a key name that cannot be
+	// an IAM user name
+	ErrInvalidServiceAccountName = errors.New("invalid service account name")
+)
+
+// serviceAccountNamePattern matches the IAM user names a key can act as
+var serviceAccountNamePattern = regexp.MustCompile(`^[A-Za-z0-9+=,.@_-]{1,64}$`)
+
+// apiKeyIDLength is the length of the hex-encoded key ID
+const apiKeyIDLength = 16
+
+// ServiceAccountARN returns the synthetic principal ARN the API keys named
+// name of an account act as
+func ServiceAccountARN(accountID, name string) string {
+	return "arn:aws:iam::" + accountID + ":user" + ServiceAccountPath + name
+}
+
+// CreateAPIKey mints a key for key.AccountID, acting as the service account
+// key.Name, and returns the key. Only its hash is stored: the key cannot be
+// shown again. The account must be enabled and not privileged.
+func (a *authorizerImpl) CreateAPIKey(ctx context.Context, key *store.APIKey) (string, error) {
+	if err := a.checkAPIKey(ctx, key); err != nil {
+		return "", err
+	}
+	secret, err := newAPIKey(key)
+	if err != nil {
+		return "", err
+	}
+	if err := a.apiKeyStore.Create(ctx, key); err != nil {
+		return "", err
+	}
+	return secret, nil
+}
+
+// CheckAPIKey returns the error CreateAPIKey would, without minting the key
+func (a *authorizerImpl) CheckAPIKey(ctx context.Context, key *store.APIKey) error {
+	return a.checkAPIKey(ctx, key)
+}
+
+func (a *authorizerImpl) checkAPIKey(ctx context.Context, key *store.APIKey) error {
+	if !serviceAccountNamePattern.MatchString(key.Name) {
+		return fmt.Errorf("%w %q: must be 1 to 64 letters, digits or +=,.@_-", ErrInvalidServiceAccountName, key.Name)
+	}
+	account, err := a.accountStore.Get(ctx, key.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("%w: %s", ErrAccountNotEnabled, key.AccountID)
+	}
+	if account.Privileged {
+		return fmt.Errorf("%w: %s", ErrPrivilegedAPIKey, key.AccountID)
+	}
+	return nil
+}
+
+// newAPIKey fills in the ID, principal and hash of key and returns its
+// secret form
+func newAPIKey(key *store.APIKey) (string, error) {
+	id := make([]byte, apiKeyIDLength/2)
+	secret := make([]byte, 32)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate API key ID: %w", err)
+	}
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(secret)
+	key.KeyID = hex.EncodeToString(id)
+	key.PrincipalARN = ServiceAccountARN(key.AccountID, key.Name)
+	key.Prefix = APIKeyPrefix + key.KeyID
+	key.Last4 = encoded[len(encoded)-4:]
+	key.SecretHash = hashAPIKeySecret(encoded)
+	return key.Prefix + "_" + encoded, nil
+}
+
+// parseAPIKey splits a key into its ID and secret
+func parseAPIKey(token string) (keyID, secret string, ok bool) {
+	rest, ok := strings.CutPrefix(token, APIKeyPrefix)
+	if !ok {
+		return "", "", false
+	}
+	keyID, secret, ok = strings.Cut(rest, "_")
+	if !ok || len(keyID) != apiKeyIDLength || secret == "" {
+		return "", "", false
+	}
+	return keyID, secret, true
+}
+
+func hashAPIKeySecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// ResolveAPIKey returns the active key token is, or ErrInvalidAPIKey
+func (a *authorizerImpl) ResolveAPIKey(ctx context.Context, token string) (*store.APIKey, error) {
+	keyID, secret, ok := parseAPIKey(token)
+	if !ok {
+		return nil, fmt.Errorf("%w: malformed", ErrInvalidAPIKey)
+	}
+	key, err := a.lookupAPIKey(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil || subtle.ConstantTimeCompare([]byte(hashAPIKeySecret(secret)), []byte(key.SecretHash)) != 1 {
+		return nil, fmt.Errorf("%w: unknown key %s", ErrInvalidAPIKey, keyID)
+	}
+	if !key.Active(a.now()) {
+		return nil, fmt.Errorf("%w: key %s is revoked or expired", ErrInvalidAPIKey, keyID)
+	}
+	return key, nil
+}
+
+// lookupAPIKey returns a key through the cache when keys are cached
+func (a *authorizerImpl) lookupAPIKey(ctx context.Context, keyID string) (*store.APIKey, error) {
+	if a.apiKeyCache != nil {
+		return a.apiKeyCache.Get(ctx, keyID)
+	}
+	return a.apiKeyStore.Get(ctx, keyID)
+}
+
+// invalidateAPIKey evicts a changed key from this replica's cache; other
+// replicas evict it at their next revocation refresh
+func (a *authorizerImpl) invalidateAPIKey(keyID string) {
+	if a.apiKeyCache != nil {
+		a.apiKeyCache.Invalidate(keyID)
+	}
+}
+
+// GetAPIKey returns an API key of an account, or nil if the account has no
+// key with that ID
+func (a *authorizerImpl) GetAPIKey(ctx context.Context, accountID, keyID string) (*store.APIKey, error) {
+	key, err := a.apiKeyStore.Get(ctx, keyID)
+	if err != nil || key == nil || key.AccountID != accountID {
+		return nil, err
+	}
+	return key, nil
+}
+
+// ListAPIKeys returns the API keys of an account, including revoked and
+// expired ones
+func (a *authorizerImpl) ListAPIKeys(ctx context.Context, accountID string) ([]*store.APIKey, error) {
+	return a.apiKeyStore.ListByAccount(ctx, accountID)
+}
+
+// RotateAPIKey mints a key replacing an active key of an account, acting as
+// the same service account with the same actions, and returns it with its
+// secret form. The replaced key keeps working for overlap, capped at
+// MaxAPIKeyOverlap and its own expiry, so clients can switch over; a zero
+// overlap revokes it at once. expiresAt is the new key's optional expiry.
+func (a *authorizerImpl) RotateAPIKey(ctx context.Context, accountID, keyID, rotatedBy string, overlap time.Duration, expiresAt string) (*store.APIKey, string, error) {
+	old, err := a.GetAPIKey(ctx, accountID, keyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if old == nil {
+		return nil, "", fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+	}
+	now := a.now()
+	if !old.Active(now) {
+		return nil, "", fmt.Errorf("%w: %s", ErrAPIKeyInactive, keyID)
+	}
+
+	key := &store.APIKey{
+		AccountID:   accountID,
+		Name:        old.Name,
+		Description: old.Description,
+		Actions:     old.Actions,
+		ExpiresAt:   expiresAt,
+		RotatedFrom: old.KeyID,
+		CreatedBy:   rotatedBy,
+	}
+	secret, err := a.CreateAPIKey(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+
+	overlap = min(overlap, MaxAPIKeyOverlap)
+	if overlap <= 0 {
+		err = a.apiKeyStore.Revoke(ctx, old.KeyID, rotatedBy, now)
+	} else if retireAt := now.Add(overlap); old.ExpiresAt == "" || retireAt.Before(parseExpiry(old.ExpiresAt)) {
+		err = a.apiKeyStore.SetExpiry(ctx, old.KeyID, retireAt)
+	}
+	a.invalidateAPIKey(old.KeyID)
+	if err != nil {
+		// The new key works, so hand it out; the old one is left as it was
+		a.logger.Error("failed to retire rotated API key", "error", err, "account_id", accountID, "key_id", old.KeyID, "new_key_id", key.KeyID)
+	}
+	return key, secret, nil
+}
+
+// RevokeAPIKey revokes an API key of an account
+func (a *authorizerImpl) RevokeAPIKey(ctx context.Context, accountID, keyID, revokedBy string) error {
+	key, err := a.GetAPIKey(ctx, accountID, keyID)
+	if err != nil {
+		return err
+	}
+	if key == nil {
+		return fmt.Errorf("%w: %s", ErrAPIKeyNotFound, keyID)
+	}
+	defer a.invalidateAPIKey(keyID)
+	return a.apiKeyStore.Revoke(ctx, keyID, revokedBy, a.now())
+}
+
+// revokeAPIKeys revokes every active API key of an account, logging the
+// keys that could not be revoked
+func (a *authorizerImpl) revokeAPIKeys(ctx context.Context, accountID, revokedBy string) {
+	keys, err := a.apiKeyStore.ListByAccount(ctx, accountID)
+	if err != nil {
+		a.logger.Warn("failed to list API keys to revoke", "error", err, "account_id", accountID)
+		return
+	}
+	now := a.now()
+	for _, key := range keys {
+		if !key.Active(now) {
+			continue
+		}
+		if err := a.apiKeyStore.Revoke(ctx, key.KeyID, revokedBy, now); err != nil {
+			a.logger.Warn("failed to revoke API key", "error", err, "account_id", accountID, "key_id", key.KeyID)
+		}
+		a.invalidateAPIKey(key.KeyID)
+	}
+}
+
+// parseExpiry parses a stored RFC3339 expiry, treating a malformed one as
+// already passed
+func parseExpiry(s string) time.Time {
+	t, err := clock.Parse(s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
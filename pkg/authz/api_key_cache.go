@@ -0,0 +1,155 @@
+package authz
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// apiKeyChangeSkew widens each revocation refresh back past the previous
+// one, so a change written by a replica whose clock runs behind, or not yet
+// visible in the changes index, is still picked up
+const apiKeyChangeSkew = time.Minute
+
+// apiKeyStaleRefreshes is how many refresh intervals may pass without a
+// successful refresh before the cache is bypassed
+const apiKeyStaleRefreshes = 3
+
+// apiKeySource is the part of the API key store the cache reads through
+type apiKeySource interface {
+	Get(ctx context.Context, keyID string) (*store.APIKey, error)
+	ListChangedSince(ctx context.Context, since time.Time) ([]string, error)
+}
+
+// apiKeyEntry is a cached API key and when it stops being served
+type apiKeyEntry struct {
+	key     *store.APIKey
+	expires time.Time
+}
+
+// APIKeyCache caches API keys by ID for a TTL, so authenticating a request
+// with a key does not read the API keys table. Every refresh interval it
+// lists the keys revoked or given an expiry since the last refresh and
+// evicts them, so a key revoked on any replica stops working on every
+// replica within about one interval. When refreshes keep failing, the cache
+// is bypassed rather than serving keys that may have been revoked.
+type APIKeyCache struct {
+	mu        sync.Mutex
+	entries   map[string]apiKeyEntry
+	source    apiKeySource
+	ttl       time.Duration
+	interval  time.Duration
+	watermark time.Time
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewAPIKeyCache creates a new APIKeyCache that keeps keys for ttl and
+// refreshes revocations every interval
+func NewAPIKeyCache(keys *store.APIKeyStore, ttl, interval time.Duration, logger *slog.Logger) *APIKeyCache {
+	return newAPIKeyCache(keys, ttl, interval, logger, time.Now)
+}
+
+func newAPIKeyCache(source apiKeySource, ttl, interval time.Duration, logger *slog.Logger, now func() time.Time) *APIKeyCache {
+	return &APIKeyCache{
+		entries:   make(map[string]apiKeyEntry),
+		source:    source,
+		ttl:       ttl,
+		interval:  interval,
+		watermark: now(),
+		logger:    logger,
+		now:       now,
+	}
+}
+
+// Get returns the key with the given ID, or nil if none exists. Unknown IDs
+// are not cached, so guessed IDs cannot fill the cache.
+func (c *APIKeyCache) Get(ctx context.Context, keyID string) (*store.APIKey, error) {
+	now := c.now()
+	c.mu.Lock()
+	entry, ok := c.entries[keyID]
+	fresh := c.fresh(now)
+	c.mu.Unlock()
+	if ok && fresh && now.Before(entry.expires) {
+		return entry.key, nil
+	}
+
+	key, err := c.source.Get(ctx, keyID)
+	if err != nil || key == nil {
+		return key, err
+	}
+	if fresh {
+		c.mu.Lock()
+		c.entries[keyID] = apiKeyEntry{key: key, expires: now.Add(c.ttl)}
+		c.mu.Unlock()
+	}
+	return key, nil
+}
+
+// fresh reports whether the last successful refresh is recent enough to
+// serve cached keys. c.mu must be held.
+func (c *APIKeyCache) fresh(now time.Time) bool {
+	return now.Sub(c.watermark) <= apiKeyStaleRefreshes*c.interval
+}
+
+// Invalidate evicts a key, for changes made on this replica
+func (c *APIKeyCache) Invalidate(keyID string) {
+	c.mu.Lock()
+	delete(c.entries, keyID)
+	c.mu.Unlock()
+}
+
+// Refresh evicts the keys changed since the last refresh, and the keys past
+// their TTL
+func (c *APIKeyCache) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	since := c.watermark.Add(-apiKeyChangeSkew)
+	c.mu.Unlock()
+
+	start := c.now()
+	changed, err := c.source.ListChangedSince(ctx, since)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, keyID := range changed {
+		delete(c.entries, keyID)
+	}
+	for keyID, entry := range c.entries {
+		if !start.Before(entry.expires) {
+			delete(c.entries, keyID)
+		}
+	}
+	c.watermark = start
+	return nil
+}
+
+// Run refreshes revocations every interval until ctx is cancelled
+func (c *APIKeyCache) Run(ctx context.Context) {
+	c.logger.Info("API key cache started", "ttl", c.ttl, "refresh_interval", c.interval)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			c.logger.Info("API key cache stopped")
+			return
+		case <-ticker.C:
+			if err := c.Refresh(ctx); err != nil {
+				c.logger.Error("API key revocation refresh failed", "error", err)
+			}
+		}
+	}
+}
+
+// APIKeyCache returns the authorizer's API key cache, or nil when keys are
+// not cached
+func (a *authorizerImpl) APIKeyCache() *APIKeyCache {
+	return a.apiKeyCache
+}
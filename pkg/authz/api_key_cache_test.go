@@ -0,0 +1,108 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// fakeAPIKeySource counts reads and reports the IDs in changed as changed
+type fakeAPIKeySource struct {
+	keys    map[string]*store.APIKey
+	changed []string
+	listErr error
+	gets    int
+}
+
+func (s *fakeAPIKeySource) Get(ctx context.Context, keyID string) (*store.APIKey, error) {
+	s.gets++
+	key, ok := s.keys[keyID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *key
+	return &copied, nil
+}
+
+func (s *fakeAPIKeySource) ListChangedSince(ctx context.Context, since time.Time) ([]string, error) {
+	return s.changed, s.listErr
+}
+
+func TestAPIKeyCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &fakeAPIKeySource{keys: map[string]*store.APIKey{"k1": {KeyID: "k1"}, "k2": {KeyID: "k2"}}}
+	cache := newAPIKeyCache(source, time.Minute, 5*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)), func() time.Time { return now })
+
+	get := func(keyID string) *store.APIKey {
+		t.Helper()
+		key, err := cache.Get(ctx, keyID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return key
+	}
+
+	get("k1")
+	get("k2")
+	get("k1")
+	if source.gets != 2 {
+		t.Fatalf("expected cached keys to be served without reads, got %d reads", source.gets)
+	}
+	if get("unknown") != nil || get("unknown") != nil || source.gets != 4 {
+		t.Errorf("expected unknown keys not to be cached, got %d reads", source.gets)
+	}
+
+	// A key revoked on another replica is read again after the next refresh
+	source.keys["k1"].RevokedAt = "2024-01-01T00:00:01Z"
+	source.changed = []string{"k1"}
+	now = now.Add(5 * time.Second)
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if get("k1").RevokedAt == "" {
+		t.Error("expected the revoked key to be evicted")
+	}
+	get("k2")
+	if source.gets != 5 {
+		t.Errorf("expected only the changed key to be read again, got %d reads", source.gets)
+	}
+
+	// Keys are read again once their TTL passes
+	source.changed = nil
+	now = now.Add(time.Minute)
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	get("k2")
+	if source.gets != 6 {
+		t.Errorf("expected an expired entry to be read again, got %d reads", source.gets)
+	}
+
+	// Without a successful refresh for three intervals, the cache is bypassed
+	source.listErr = errors.New("dynamodb unavailable")
+	if err := cache.Refresh(ctx); err == nil {
+		t.Fatal("expected the refresh to fail")
+	}
+	now = now.Add(16 * time.Second)
+	get("k2")
+	get("k2")
+	if source.gets != 8 {
+		t.Errorf("expected a stale cache to be bypassed, got %d reads", source.gets)
+	}
+
+	source.listErr = nil
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	get("k2")
+	get("k2")
+	if source.gets != 8 {
+		t.Errorf("expected caching to resume after a refresh, got %d reads", source.gets)
+	}
+}
@@ -0,0 +1,178 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// apiKeyTables keeps API keys in memory on top of benchTables. UpdateItem
+// applies the SET values of the key store's updates.
+type apiKeyTables struct {
+	benchTables
+	keys map[string]map[string]types.AttributeValue
+}
+
+func (c *apiKeyTables) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	id := params.Item["keyId"].(*types.AttributeValueMemberS).Value
+	c.keys[id] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *apiKeyTables) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if aws.ToString(params.TableName) != c.cfg.APIKeysTableName {
+		return c.benchTables.GetItem(ctx, params, optFns...)
+	}
+	id := params.Key["keyId"].(*types.AttributeValueMemberS).Value
+	return &dynamodb.GetItemOutput{Item: c.keys[id]}, nil
+}
+
+func (c *apiKeyTables) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	item := c.keys[params.Key["keyId"].(*types.AttributeValueMemberS).Value]
+	set := strings.TrimPrefix(aws.ToString(params.UpdateExpression), "SET ")
+	for _, assignment := range strings.Split(set, ", ") {
+		name, value, _ := strings.Cut(assignment, " = ")
+		item[name] = params.ExpressionAttributeValues[value]
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func newAPIKeyAuthorizer(t *testing.T, privileged bool) (*authorizerImpl, *apiKeyTables) {
+	t.Helper()
+	cfg := DefaultConfig()
+	account, err := attributevalue.MarshalMap(&store.Account{AccountID: "123456789012", PolicyStoreID: "ps-1", Privileged: privileged})
+	if err != nil {
+		t.Fatalf("failed to marshal account: %v", err)
+	}
+	tables := &apiKeyTables{benchTables: benchTables{cfg: cfg, account: account}, keys: map[string]map[string]types.AttributeValue{}}
+	return New(cfg, tables, benchAVP{}, slog.New(slog.NewTextHandler(io.Discard, nil))), tables
+}
+
+func TestAPIKey_CreateAndResolve(t *testing.T) {
+	a, _ := newAPIKeyAuthorizer(t, false)
+	ctx := context.Background()
+
+	key := &store.APIKey{AccountID: "123456789012", Name: "ci", Actions: []string{"ListClusters"}}
+	secret, err := a.CreateAPIKey(ctx, key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(secret, key.Prefix+"_") || !strings.HasSuffix(secret, key.Last4) {
+		t.Errorf("expected key %q to start with %q and end with %q", secret, key.Prefix, key.Last4)
+	}
+	if strings.Contains(key.SecretHash, secret[len(key.Prefix)+1:]) {
+		t.Error("expected only a hash of the key to be stored")
+	}
+	if key.PrincipalARN != "arn:aws:iam::123456789012:user/rosa-service-accounts/ci" {
+		t.Errorf("unexpected principal ARN %q", key.PrincipalARN)
+	}
+
+	resolved, err := a.ResolveAPIKey(ctx, secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resolved.KeyID != key.KeyID || resolved.PrincipalARN != key.PrincipalARN {
+		t.Errorf("resolved %+v, want key %s", resolved, key.KeyID)
+	}
+
+	for _, token := range []string{
+		secret[:len(secret)-1] + "x",
+		"rosa_short_secret",
+		"rosa_" + key.KeyID,
+		strings.TrimPrefix(secret, APIKeyPrefix),
+	} {
+		if _, err := a.ResolveAPIKey(ctx, token); !errors.Is(err, ErrInvalidAPIKey) {
+			t.Errorf("ResolveAPIKey(%q) = %v, want ErrInvalidAPIKey", token, err)
+		}
+	}
+
+	if err := a.RevokeAPIKey(ctx, "123456789012", key.KeyID, "arn:aws:iam::123456789012:user/admin"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := a.ResolveAPIKey(ctx, secret); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("expected a revoked key to be rejected, got %v", err)
+	}
+	if err := a.RevokeAPIKey(ctx, "210987654321", key.KeyID, "arn:aws:iam::210987654321:user/admin"); !errors.Is(err, ErrAPIKeyNotFound) {
+		t.Errorf("expected another account's key not to be found, got %v", err)
+	}
+}
+
+func TestAPIKey_CreateRejected(t *testing.T) {
+	a, _ := newAPIKeyAuthorizer(t, false)
+	_, err := a.CreateAPIKey(context.Background(), &store.APIKey{AccountID: "123456789012", Name: "ci/deploy", Actions: []string{"*"}})
+	if !errors.Is(err, ErrInvalidServiceAccountName) {
+		t.Errorf("expected ErrInvalidServiceAccountName, got %v", err)
+	}
+
+	a, _ = newAPIKeyAuthorizer(t, true)
+	_, err = a.CreateAPIKey(context.Background(), &store.APIKey{AccountID: "123456789012", Name: "ci", Actions: []string{"*"}})
+	if !errors.Is(err, ErrPrivilegedAPIKey) {
+		t.Errorf("expected ErrPrivilegedAPIKey, got %v", err)
+	}
+}
+
+func TestAPIKey_Rotate(t *testing.T) {
+	tests := []struct {
+		name         string
+		overlap      time.Duration
+		expectOldOK  bool
+		expectExpiry bool
+	}{
+		{name: "revoked at once", overlap: 0},
+		{name: "overlap", overlap: time.Hour, expectOldOK: true, expectExpiry: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, _ := newAPIKeyAuthorizer(t, false)
+			ctx := context.Background()
+
+			old := &store.APIKey{AccountID: "123456789012", Name: "ci", Description: "CI pipeline", Actions: []string{"ListClusters"}}
+			oldSecret, err := a.CreateAPIKey(ctx, old)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			key, secret, err := a.RotateAPIKey(ctx, "123456789012", old.KeyID, "arn:aws:iam::123456789012:user/admin", tt.overlap, "")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if key.KeyID == old.KeyID || key.RotatedFrom != old.KeyID || key.PrincipalARN != old.PrincipalARN || key.Description != old.Description {
+				t.Errorf("unexpected rotated key %+v", key)
+			}
+			if _, err := a.ResolveAPIKey(ctx, secret); err != nil {
+				t.Errorf("expected the new key to work, got %v", err)
+			}
+
+			_, err = a.ResolveAPIKey(ctx, oldSecret)
+			if (err == nil) != tt.expectOldOK {
+				t.Errorf("expected old key working=%v, got %v", tt.expectOldOK, err)
+			}
+			retired, err := a.GetAPIKey(ctx, "123456789012", old.KeyID)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if (retired.ExpiresAt != "") != tt.expectExpiry {
+				t.Errorf("expected old key expiry set=%v, got %q", tt.expectExpiry, retired.ExpiresAt)
+			}
+
+			if tt.expectOldOK {
+				return
+			}
+			if _, _, err := a.RotateAPIKey(ctx, "123456789012", old.KeyID, "arn:aws:iam::123456789012:user/admin", 0, ""); !errors.Is(err, ErrAPIKeyInactive) {
+				t.Errorf("expected rotating a revoked key to fail with ErrAPIKeyInactive, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// Operations that can be configured to need a second admin's approval (see
+// Config.ApprovalRequired)
+const (
+	OperationDeletePolicy   = "DeletePolicy"
+	OperationRemoveAdmin    = "RemoveAdmin"
+	OperationDisableAccount = "DisableAccount"
+)
+
+// ApprovalOperations lists the operations that can need approval
+var ApprovalOperations = []string{OperationDeletePolicy, OperationRemoveAdmin, OperationDisableAccount}
+
+var (
+	// ErrApprovalRequired matches an ApprovalRequiredError
+	ErrApprovalRequired = errors.New("operation requires approval by a second admin")
+
+	// ErrSelfApproval is returned when the admin who requested a change
+	// tries to approve it
+	ErrSelfApproval = errors.New("a change cannot be approved by the admin who requested it")
+)
+
+// ApprovalRequiredError is returned by an operation that was queued as a
+// pending change instead of being performed
+type ApprovalRequiredError struct {
+	Change *store.PendingChange
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("%s: %s %s is pending as change %s", ErrApprovalRequired, e.Change.Operation, e.Change.TargetID, e.Change.ChangeID)
+}
+
+// Is makes errors.Is(err, ErrApprovalRequired) match
+func (e *ApprovalRequiredError) Is(target error) bool {
+	return target == ErrApprovalRequired
+}
+
+// IsAccountOperation reports whether an operation is one an account's own
+// admins approve. Disabling an account is approved by privileged callers.
+func IsAccountOperation(operation string) bool {
+	return operation == OperationDeletePolicy || operation == OperationRemoveAdmin
+}
+
+// requestApproval queues operation for approval when it is configured to
+// need it, returning the ApprovalRequiredError to hand back to the caller. It
+// returns nil when the operation may go ahead. Approval is only asked of
+// callers the request can be attributed to.
+func (a *authorizerImpl) requestApproval(ctx context.Context, accountID, operation, targetID, requestedBy string) error {
+	if requestedBy == "" || !slices.Contains(a.cfg.ApprovalRequired, operation) {
+		return nil
+	}
+
+	change := &store.PendingChange{
+		AccountID:   accountID,
+		Operation:   operation,
+		TargetID:    targetID,
+		RequestedBy: requestedBy,
+	}
+	if err := a.pendingChangeStore.Create(ctx, change, a.cfg.ApprovalExpiry); err != nil {
+		return err
+	}
+	return &ApprovalRequiredError{Change: change}
+}
+
+// GetPendingChange returns an unexpired pending change
+func (a *authorizerImpl) GetPendingChange(ctx context.Context, accountID, changeID string) (*store.PendingChange, error) {
+	return a.pendingChangeStore.Get(ctx, accountID, changeID)
+}
+
+// ListPendingChanges returns an account's unexpired pending changes
+func (a *authorizerImpl) ListPendingChanges(ctx context.Context, accountID string) ([]*store.PendingChange, error) {
+	return a.pendingChangeStore.List(ctx, accountID)
+}
+
+// ApproveChange performs a pending change on behalf of a second admin. The
+// change is removed first, so it is performed at most once even if the
+// operation then fails.
+func (a *authorizerImpl) ApproveChange(ctx context.Context, accountID, changeID, approvedBy string) (*store.PendingChange, error) {
+	change, err := a.pendingChangeStore.Get(ctx, accountID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if change.RequestedBy == approvedBy {
+		return nil, ErrSelfApproval
+	}
+	if err := a.pendingChangeStore.Delete(ctx, accountID, changeID); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("pending change approved",
+		"account_id", accountID,
+		"change_id", changeID,
+		"operation", change.Operation,
+		"target_id", change.TargetID,
+		"requested_by", change.RequestedBy,
+		"approved_by", approvedBy,
+	)
+
+	switch change.Operation {
+	case OperationDeletePolicy:
+		err = a.deletePolicy(ctx, accountID, change.TargetID, change.RequestedBy)
+	case OperationRemoveAdmin:
+		err = a.adminStore.Remove(ctx, accountID, change.TargetID)
+	case OperationDisableAccount:
+		err = a.disableAccount(ctx, accountID)
+	default:
+		err = fmt.Errorf("unknown pending operation: %s", change.Operation)
+	}
+	return change, err
+}
+
+// RejectChange discards a pending change. Any admin, including the one who
+// requested it, may reject it.
+func (a *authorizerImpl) RejectChange(ctx context.Context, accountID, changeID, rejectedBy string) error {
+	if err := a.pendingChangeStore.Delete(ctx, accountID, changeID); err != nil {
+		return err
+	}
+	a.logger.Info("pending change rejected", "account_id", accountID, "change_id", changeID, "rejected_by", rejectedBy)
+	return nil
+}
@@ -0,0 +1,138 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// approvalTableClient keeps items per table, keyed by their sort key, which
+// is unique within the single account these tests use
+type approvalTableClient struct {
+	client.DynamoDBClient
+	tables map[string]map[string]map[string]types.AttributeValue
+}
+
+var approvalSortKeys = map[string]string{
+	"admins":  "principalArn",
+	"pending": "changeId",
+}
+
+func (c *approvalTableClient) key(table string, item map[string]types.AttributeValue) string {
+	return item[approvalSortKeys[table]].(*types.AttributeValueMemberS).Value
+}
+
+func (c *approvalTableClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	table := aws.ToString(params.TableName)
+	if c.tables[table] == nil {
+		c.tables[table] = make(map[string]map[string]types.AttributeValue)
+	}
+	c.tables[table][c.key(table, params.Item)] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *approvalTableClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	table := aws.ToString(params.TableName)
+	return &dynamodb.GetItemOutput{Item: c.tables[table][c.key(table, params.Key)]}, nil
+}
+
+func (c *approvalTableClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	table := aws.ToString(params.TableName)
+	key := c.key(table, params.Key)
+	if _, ok := c.tables[table][key]; !ok {
+		return nil, &types.ConditionalCheckFailedException{Message: aws.String("The conditional request failed")}
+	}
+	delete(c.tables[table], key)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestRemoveAdmin_RequiresApproval(t *testing.T) {
+	const (
+		account = "123456789012"
+		alice   = "arn:aws:iam::123456789012:user/alice"
+		bob     = "arn:aws:iam::123456789012:user/bob"
+		carol   = "arn:aws:iam::123456789012:user/carol"
+	)
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &approvalTableClient{tables: map[string]map[string]map[string]types.AttributeValue{}}
+
+	cfg := DefaultConfig()
+	cfg.AdminsTableName = "admins"
+	cfg.PendingChangesTableName = "pending"
+	cfg.ApprovalRequired = []string{OperationRemoveAdmin}
+	a := New(cfg, c, nil, logger)
+
+	for _, arn := range []string{alice, bob, carol} {
+		if err := a.adminStore.Add(ctx, &store.Admin{AccountID: account, PrincipalARN: arn}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := a.RemoveAdmin(ctx, account, "arn:aws:iam::123456789012:user/nobody", alice); !errors.Is(err, store.ErrAdminNotFound) {
+		t.Fatalf("removing a non-admin should fail before queuing, got %v", err)
+	}
+
+	err := a.RemoveAdmin(ctx, account, carol, alice)
+	var approvalErr *ApprovalRequiredError
+	if !errors.As(err, &approvalErr) || !errors.Is(err, ErrApprovalRequired) {
+		t.Fatalf("expected ApprovalRequiredError, got %v", err)
+	}
+	change := approvalErr.Change
+	if change.Operation != OperationRemoveAdmin || change.TargetID != carol || change.RequestedBy != alice || change.Expired(time.Now()) {
+		t.Fatalf("unexpected pending change %+v", change)
+	}
+	if isAdmin, _ := a.adminStore.IsAdmin(ctx, account, carol); !isAdmin {
+		t.Fatal("admin removed before approval")
+	}
+
+	if _, err := a.ApproveChange(ctx, account, change.ChangeID, alice); !errors.Is(err, ErrSelfApproval) {
+		t.Fatalf("expected ErrSelfApproval, got %v", err)
+	}
+	if _, err := a.ApproveChange(ctx, account, change.ChangeID, bob); err != nil {
+		t.Fatalf("ApproveChange: %v", err)
+	}
+	if isAdmin, _ := a.adminStore.IsAdmin(ctx, account, carol); isAdmin {
+		t.Error("admin not removed after approval")
+	}
+	if _, err := a.ApproveChange(ctx, account, change.ChangeID, bob); !errors.Is(err, store.ErrPendingChangeNotFound) {
+		t.Errorf("a change should be approved at most once, got %v", err)
+	}
+
+	// Operations that are not configured, and callers that cannot be
+	// attributed, act immediately
+	if err := a.RemoveAdmin(ctx, account, bob, ""); err != nil {
+		t.Errorf("RemoveAdmin without a requester: %v", err)
+	}
+}
+
+func TestRejectChange(t *testing.T) {
+	const account = "123456789012"
+	ctx := context.Background()
+	c := &approvalTableClient{tables: map[string]map[string]map[string]types.AttributeValue{}}
+	cfg := DefaultConfig()
+	cfg.PendingChangesTableName = "pending"
+	a := New(cfg, c, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	change := &store.PendingChange{AccountID: account, Operation: OperationDisableAccount, TargetID: account, RequestedBy: "arn:aws:iam::123456789012:user/alice"}
+	if err := a.pendingChangeStore.Create(ctx, change, cfg.ApprovalExpiry); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.RejectChange(ctx, account, change.ChangeID, change.RequestedBy); err != nil {
+		t.Fatalf("the requester may reject their own change: %v", err)
+	}
+	if _, err := a.GetPendingChange(ctx, account, change.ChangeID); !errors.Is(err, store.ErrPendingChangeNotFound) {
+		t.Errorf("expected rejected change to be gone, got %v", err)
+	}
+}
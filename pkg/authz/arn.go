@@ -0,0 +1,36 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrResourceAccountMismatch is returned when a request names a resource ARN
+// owned by an account other than the caller's
+var ErrResourceAccountMismatch = errors.New("resource belongs to another account")
+
+// ResourceAccountID returns the account ID embedded in an ARN
+// (arn:partition:service:region:account:resource). Wildcards, bare resource
+// IDs and ARNs without an account, such as S3 bucket ARNs, report false.
+func ResourceAccountID(resource string) (string, bool) {
+	parts := strings.SplitN(resource, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || parts[4] == "" {
+		return "", false
+	}
+	return parts[4], true
+}
+
+// checkResourceAccount rejects resources owned by an account other than
+// accountID unless the owning account is listed in
+// Config.CrossAccountResourceAccounts. Evaluating a caller's policies against
+// another account's resource would let the caller borrow this service's
+// authority over that account.
+func (a *authorizerImpl) checkResourceAccount(accountID, resource string) error {
+	owner, ok := ResourceAccountID(resource)
+	if !ok || owner == accountID || slices.Contains(a.cfg.CrossAccountResourceAccounts, owner) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is owned by account %s", ErrResourceAccountMismatch, resource, owner)
+}
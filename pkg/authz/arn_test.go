@@ -0,0 +1,55 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestResourceAccountID(t *testing.T) {
+	tests := []struct {
+		resource string
+		account  string
+		ok       bool
+	}{
+		{resource: "arn:aws:rosa:us-east-1:123456789012:cluster/abc", account: "123456789012", ok: true},
+		{resource: "arn:aws:rosa:us-east-1:123456789012:cluster/a:b", account: "123456789012", ok: true},
+		{resource: "arn:aws:s3:::bucket", ok: false},
+		{resource: "*", ok: false},
+		{resource: "cluster-123", ok: false},
+		{resource: "arn:aws:rosa", ok: false},
+	}
+
+	for _, tt := range tests {
+		account, ok := ResourceAccountID(tt.resource)
+		if account != tt.account || ok != tt.ok {
+			t.Errorf("ResourceAccountID(%q) = %q, %v; want %q, %v", tt.resource, account, ok, tt.account, tt.ok)
+		}
+	}
+}
+
+func TestCheckResourceAccount(t *testing.T) {
+	a := &authorizerImpl{cfg: &Config{CrossAccountResourceAccounts: []string{"999999999999"}}}
+
+	tests := []struct {
+		name     string
+		resource string
+		wantErr  bool
+	}{
+		{name: "own account", resource: "arn:aws:rosa:us-east-1:123456789012:cluster/abc"},
+		{name: "wildcard", resource: "*"},
+		{name: "cross-account allowed", resource: "arn:aws:rosa:us-east-1:999999999999:cluster/shared"},
+		{name: "other account", resource: "arn:aws:rosa:us-east-1:210987654321:cluster/abc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := a.checkResourceAccount("123456789012", tt.resource)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error=%v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrResourceAccountMismatch) {
+				t.Errorf("expected ErrResourceAccountMismatch, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// newAttachmentAuthorizer returns an authorizer over account 123456789012,
+// whose policy store ps-1 holds the policy tmpl-1
+func newAttachmentAuthorizer(t *testing.T) (*authorizerImpl, *policyStoreTables, *policyStoreAVP) {
+	t.Helper()
+	cfg := DefaultConfig()
+	tables := newPolicyStoreTables(t, cfg, &store.Account{AccountID: "123456789012", PolicyStoreID: "ps-1", SchemaVersion: 1})
+	avp := newPolicyStoreAVP("ps-1")
+	if _, err := avp.CreatePolicyTemplate(context.Background(), &verifiedpermissions.CreatePolicyTemplateInput{
+		PolicyStoreId: aws.String("ps-1"),
+		Statement:     aws.String(`permit(principal == ?principal, action, resource);`),
+		Description:   aws.String(encodePolicyMeta("readers", "")),
+	}); err != nil {
+		t.Fatalf("failed to seed policy: %v", err)
+	}
+	return New(cfg, tables, avp, slog.New(slog.NewTextHandler(io.Discard, nil))), tables, avp
+}
+
+func TestAttachPolicy_Deduplicates(t *testing.T) {
+	ctx := context.Background()
+	a, _, avp := newAttachmentAuthorizer(t)
+	alice := "arn:aws:iam::123456789012:user/alice"
+
+	first, created, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeUser, alice, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created {
+		t.Error("expected the first attach to create the attachment")
+	}
+
+	again, created, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeUser, alice, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created || again.AttachmentID != first.AttachmentID {
+		t.Errorf("expected the existing attachment %s, got %s (created %t)", first.AttachmentID, again.AttachmentID, created)
+	}
+	if n := len(avp.stores["ps-1"].policies); n != 1 {
+		t.Errorf("expected one template-linked policy, got %d", n)
+	}
+
+	// Another target of the same policy is a new attachment
+	other, created, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeGroup, "readers", "", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !created || other.AttachmentID == first.AttachmentID {
+		t.Errorf("expected a new attachment for the group, got %+v (created %t)", other, created)
+	}
+}
+
+func TestAttachmentMetadata(t *testing.T) {
+	ctx := context.Background()
+	a, tables, _ := newAttachmentAuthorizer(t)
+
+	named, _, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeGroup, "oncall", "oncall-read", "Pager rotation reads clusters")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeGroup, "auditors", "", ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tables.attachments) != 1 {
+		t.Errorf("expected metadata only for the named attachment, got %d items", len(tables.attachments))
+	}
+
+	attachments, err := a.ListAttachments(ctx, "123456789012", AttachmentFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(attachments) != 2 || attachments[0].Name != "oncall-read" || attachments[0].Description != "Pager rotation reads clusters" || attachments[1].Name != "" {
+		t.Errorf("unexpected attachments %+v, %+v", attachments[0], attachments[1])
+	}
+
+	updated, err := a.UpdateAttachment(ctx, "123456789012", named.AttachmentID, "oncall-readonly", "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.Name != "oncall-readonly" || updated.Description != "" || updated.TargetID != "oncall" {
+		t.Errorf("unexpected updated attachment %+v", updated)
+	}
+	attachments, _ = a.ListAttachments(ctx, "123456789012", AttachmentFilter{TargetType: TargetTypeGroup, TargetID: "oncall"})
+	if len(attachments) != 1 || attachments[0].Name != "oncall-readonly" {
+		t.Errorf("expected the new name listed, got %+v", attachments)
+	}
+
+	if _, err := a.UpdateAttachment(ctx, "123456789012", "policy-404", "x", ""); !errors.Is(err, ErrAttachmentNotFound) {
+		t.Errorf("expected ErrAttachmentNotFound, got %v", err)
+	}
+}
+
+func TestGetAttachment(t *testing.T) {
+	ctx := context.Background()
+	a, tables, avp := newAttachmentAuthorizer(t)
+	other, err := attributevalue.MarshalMap(&store.Account{AccountID: "210987654321", PolicyStoreID: "ps-2", SchemaVersion: 1})
+	if err != nil {
+		t.Fatalf("failed to marshal account: %v", err)
+	}
+	tables.accounts["210987654321"] = other
+	avp.stores["ps-2"] = &memoryPolicyStore{}
+
+	attached, _, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeUser, "arn:aws:iam::123456789012:role/deployer", "deployer", "CI deploys")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		accountID    string
+		attachmentID string
+		expectErr    error
+	}{
+		{name: "found", accountID: "123456789012", attachmentID: attached.AttachmentID},
+		{name: "not found", accountID: "123456789012", attachmentID: "policy-404", expectErr: ErrAttachmentNotFound},
+		{name: "other account", accountID: "210987654321", attachmentID: attached.AttachmentID, expectErr: ErrAttachmentNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := a.GetAttachment(ctx, tt.accountID, tt.attachmentID)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := Attachment{
+				AttachmentID: attached.AttachmentID,
+				PolicyID:     "tmpl-1",
+				TargetType:   TargetTypeRole,
+				TargetID:     "arn:aws:iam::123456789012:role/deployer",
+				Name:         "deployer",
+				Description:  "CI deploys",
+			}
+			got.CreatedAt = ""
+			if *got != want {
+				t.Errorf("got %+v, want %+v", *got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// benchTables is an in-memory DynamoDB holding one account, keyed by table.
+// GetItem answers from the accounts and admins tables, and Query returns
+// the caller's group memberships.
+type benchTables struct {
+	client.DynamoDBClient
+	cfg         *Config
+	account     map[string]types.AttributeValue
+	admin       map[string]types.AttributeValue
+	memberships []map[string]types.AttributeValue
+}
+
+func (c *benchTables) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	switch aws.ToString(params.TableName) {
+	case c.cfg.AccountsTableName:
+		return &dynamodb.GetItemOutput{Item: c.account}, nil
+	case c.cfg.AdminsTableName:
+		return &dynamodb.GetItemOutput{Item: c.admin}, nil
+	}
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (c *benchTables) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{Items: c.memberships, Count: int32(len(c.memberships))}, nil
+}
+
+// benchAVP allows every request without a network round trip, so the
+// benchmarks measure the authorizer itself
+type benchAVP struct {
+	client.AVPClient
+}
+
+func (benchAVP) IsAuthorized(ctx context.Context, params *verifiedpermissions.IsAuthorizedInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.IsAuthorizedOutput, error) {
+	return &verifiedpermissions.IsAuthorizedOutput{Decision: avptypes.DecisionAllow}, nil
+}
+
+func newBenchAuthorizer(b *testing.B, groups int, privileged, admin, guardrails bool) (*authorizerImpl, *AuthzRequest) {
+	b.Helper()
+	const (
+		accountID = "123456789012"
+		callerARN = "arn:aws:iam::123456789012:role/console"
+	)
+
+	cfg := DefaultConfig()
+	if guardrails {
+		cfg.GuardrailPolicyStoreID = "guardrails"
+	}
+
+	account, err := attributevalue.MarshalMap(&store.Account{AccountID: accountID, PolicyStoreID: "ps-1", Privileged: privileged})
+	if err != nil {
+		b.Fatalf("failed to marshal account: %v", err)
+	}
+	tables := &benchTables{cfg: cfg, account: account}
+	if admin {
+		tables.admin = map[string]types.AttributeValue{"accountId": &types.AttributeValueMemberS{Value: accountID}}
+	}
+	for i := range groups {
+		member, err := attributevalue.MarshalMap(&store.GroupMember{
+			AccountID: accountID,
+			GroupID:   fmt.Sprintf("group-%03d", i),
+			MemberARN: callerARN,
+		})
+		if err != nil {
+			b.Fatalf("failed to marshal membership: %v", err)
+		}
+		tables.memberships = append(tables.memberships, member)
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	req := &AuthzRequest{
+		AccountID: accountID,
+		CallerARN: callerARN,
+		Action:    "DescribeCluster",
+		Resource:  "arn:aws:rosa:us-east-1:123456789012:cluster/abc",
+		ResourceTags: map[string]string{
+			"env":  "prod",
+			"team": "platform",
+		},
+	}
+	return New(cfg, tables, benchAVP{}, logger), req
+}
+
+// BenchmarkAuthorize covers Authorize end to end for a regular caller,
+// with the caller in 0, 10 and 100 groups, and for the admin and privileged
+// bypasses. Run it with make bench-authz, which also records CPU and memory
+// profiles and checks allocations against the committed baseline.
+func BenchmarkAuthorize(b *testing.B) {
+	cases := []struct {
+		name       string
+		groups     int
+		privileged bool
+		admin      bool
+		guardrails bool
+	}{
+		{name: "groups=0", groups: 0},
+		{name: "groups=10", groups: 10},
+		{name: "groups=100", groups: 100},
+		{name: "groups=10/guardrails", groups: 10, guardrails: true},
+		{name: "admin", admin: true},
+		{name: "privileged", privileged: true},
+	}
+
+	for _, tc := range cases {
+		b.Run(tc.name, func(b *testing.B) {
+			a, req := newBenchAuthorizer(b, tc.groups, tc.privileged, tc.admin, tc.guardrails)
+			ctx := context.Background()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for range b.N {
+				allowed, err := a.Authorize(ctx, req)
+				if err != nil || !allowed {
+					b.Fatalf("expected allow, got %v (%v)", allowed, err)
+				}
+			}
+		})
+	}
+}
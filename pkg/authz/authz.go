@@ -3,8 +3,10 @@ package authz
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/privileged"
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
 )
 
 // AuthzRequest represents an authorization request
@@ -27,6 +30,11 @@ type AuthzRequest struct {
 	ResourceTags map[string]string
 	RequestTags  map[string]string
 	Context      map[string]any
+
+	// ResourceParents are the ARNs of the resources Resource belongs to,
+	// outermost first (cluster, then nodepool), so "resource in" policies
+	// match everything below a resource
+	ResourceParents []string
 }
 
 // Checker handles authorization decisions (used by middleware)
@@ -37,13 +45,8 @@ type Checker interface {
 	IsAccountProvisioned(ctx context.Context, accountID string) (bool, error)
 }
 
-// TargetType represents the type of attachment target
-type TargetType string
-
-const (
-	TargetTypeUser  TargetType = "user"
-	TargetTypeGroup TargetType = "group"
-)
+// ErrAttachmentNotFound is returned when an attachment does not exist
+var ErrAttachmentNotFound = errors.New("attachment not found")
 
 // Attachment represents a policy attachment (backed by an AVP template-linked policy)
 type Attachment struct {
@@ -51,7 +54,14 @@ type Attachment struct {
 	PolicyID     string     `json:"policyId"`     // = AVP template ID
 	TargetType   TargetType `json:"targetType"`
 	TargetID     string     `json:"targetId"`
-	CreatedAt    string     `json:"createdAt"`
+	// Pattern is the principal ARN pattern an attachment to a pattern group
+	// was made for, set when it is created
+	Pattern string `json:"pattern,omitempty"`
+	// Name and Description are optional metadata kept in the attachment
+	// metadata table (see store.AttachmentMeta)
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	CreatedAt   string `json:"createdAt"`
 }
 
 // AttachmentFilter defines filter options for listing attachments
@@ -65,72 +75,242 @@ type AttachmentFilter struct {
 type Service interface {
 	// Account lifecycle
 	EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error)
-	DisableAccount(ctx context.Context, accountID string) error
+	// EnableAccountAsync creates a new account and queues its onboarding
+	EnableAccountAsync(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error)
+	// RetryOnboarding resumes a failed or stalled onboarding from the step
+	// it stopped at
+	RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error)
+	DisableAccount(ctx context.Context, accountID, disabledBy string) error
 	GetAccount(ctx context.Context, accountID string) (*store.Account, error)
 	ListAccounts(ctx context.Context) ([]*store.Account, error)
+	ListAccountsPage(ctx context.Context, limit int, pageToken string, filter store.AccountFilter) (*store.AccountPage, error)
+	CountAccounts(ctx context.Context, filter store.AccountFilter) (int, error)
+	// SetAccountRequiredTags replaces the tag keys the account's clusters and
+	// works must carry; AccountRequiredTags returns them, or nil for an
+	// account that is not enabled
+	SetAccountRequiredTags(ctx context.Context, accountID string, tags []string) (*store.Account, error)
+	AccountRequiredTags(ctx context.Context, accountID string) ([]string, error)
+	// SetAccountPlan moves the account to another plan tier
+	SetAccountPlan(ctx context.Context, accountID, plan string) (*store.Account, error)
+	// SetAccountResidency pins the account to a region and sets its
+	// export-control classification
+	SetAccountResidency(ctx context.Context, accountID, region, exportControl string) (*store.Account, error)
 
 	// Admin management
-	AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) error
-	RemoveAdmin(ctx context.Context, accountID, principalARN string) error
+	AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) (admin *store.Admin, created bool, err error)
+	RemoveAdmin(ctx context.Context, accountID, principalARN, removedBy string) error
 	ListAdmins(ctx context.Context, accountID string) ([]string, error)
+	FindPrincipalAccounts(ctx context.Context, principalARN string) ([]*PrincipalAccount, error)
 
 	// Group management
-	CreateGroup(ctx context.Context, accountID, name, description string) (*store.Group, error)
+	CreateGroup(ctx context.Context, accountID, name, description string, tags map[string]string) (*store.Group, error)
+	SetGroupTags(ctx context.Context, accountID, groupID string, tags map[string]string) (*store.Group, error)
 	GetGroup(ctx context.Context, accountID, groupID string) (*store.Group, error)
-	DeleteGroup(ctx context.Context, accountID, groupID string) error
+	DeleteGroup(ctx context.Context, accountID, groupID, deletedBy string) error
 	ListGroups(ctx context.Context, accountID string) ([]*store.Group, error)
 	AddGroupMember(ctx context.Context, accountID, groupID, memberARN string) error
 	RemoveGroupMember(ctx context.Context, accountID, groupID, memberARN string) error
+	UpdateGroupMembers(ctx context.Context, accountID, groupID string, version *int64, add, remove []string) ([]MemberResult, int64, error)
 	ListGroupMembers(ctx context.Context, accountID, groupID string) ([]string, error)
 	GetUserGroups(ctx context.Context, accountID, memberARN string) ([]string, error)
-
-	// Policy management — policy templates stored in AVP
-	CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string) (*store.Policy, error)
+	GetPrincipalAccess(ctx context.Context, accountID, principalARN string) (*PrincipalAccess, error)
+
+	// Policy management — policy templates stored in AVP. The policy and
+	// attachment mutations return ErrNotYetVisible, along with their result,
+	// when asked to wait for visibility (see WithWaitForVisibility) and AVP
+	// does not reflect the change in time.
+	CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error)
+	// ValidatePolicy runs the checks made on Cedar text before it is sent to
+	// AVP, which validates it against the schema itself
+	ValidatePolicy(cedarPolicy string) error
 	GetPolicy(ctx context.Context, accountID, policyID string) (*store.Policy, error)
-	UpdatePolicy(ctx context.Context, accountID, policyID, name, description, cedarPolicy string) (*store.Policy, error)
-	DeletePolicy(ctx context.Context, accountID, policyID string) error
+	UpdatePolicy(ctx context.Context, accountID, policyID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error)
+	SetPolicyTags(ctx context.Context, accountID, policyID string, tags map[string]string) (*store.Policy, error)
+	DeletePolicy(ctx context.Context, accountID, policyID, deletedBy string) error
 	ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error)
 
 	// Attachment management
-	AttachPolicy(ctx context.Context, accountID, policyID string, targetType TargetType, targetID string) (*Attachment, error)
-	DetachPolicy(ctx context.Context, accountID, attachmentID string) error
+	AttachPolicy(ctx context.Context, accountID, policyID string, targetType TargetType, targetID, name, description string) (attachment *Attachment, created bool, err error)
+	// GetAttachment fails with ErrAttachmentNotFound if the attachment does
+	// not exist
+	GetAttachment(ctx context.Context, accountID, attachmentID string) (*Attachment, error)
+	// UpdateAttachment replaces an attachment's name and description; the
+	// policy and target cannot be changed
+	UpdateAttachment(ctx context.Context, accountID, attachmentID, name, description string) (*Attachment, error)
+	DetachPolicy(ctx context.Context, accountID, attachmentID, deletedBy string) error
 	ListAttachments(ctx context.Context, accountID string, filter AttachmentFilter) ([]*Attachment, error)
+
+	// Deletion audit trail: DeletePolicy, DeleteGroup and DetachPolicy
+	// record a tombstone of what they removed
+	ListDeletions(ctx context.Context, accountID, kind string, limit int) ([]*store.Tombstone, error)
+
+	// Separation of duties: DeletePolicy, RemoveAdmin and DisableAccount
+	// return an ApprovalRequiredError instead of acting when configured to
+	// need approval (see Config.ApprovalRequired), and the change waits here
+	// for a second admin
+	GetPendingChange(ctx context.Context, accountID, changeID string) (*store.PendingChange, error)
+	ListPendingChanges(ctx context.Context, accountID string) ([]*store.PendingChange, error)
+	ApproveChange(ctx context.Context, accountID, changeID, approvedBy string) (*store.PendingChange, error)
+	RejectChange(ctx context.Context, accountID, changeID, rejectedBy string) error
+
+	// Change review: while an account's change review mode is on, its authz
+	// mutations are staged as change requests and applied once another admin
+	// approves them
+	SetAccountChangeReview(ctx context.Context, accountID string, enabled bool) (*store.Account, error)
+	ChangeReviewEnabled(ctx context.Context, accountID string) (bool, error)
+	StageChangeRequest(ctx context.Context, cr *store.ChangeRequest) error
+	GetChangeRequest(ctx context.Context, accountID, changeID string) (*store.ChangeRequest, error)
+	ListChangeRequests(ctx context.Context, accountID, status string) ([]*store.ChangeRequest, error)
+	DecideChangeRequest(ctx context.Context, accountID, changeID string, approve bool, decidedBy string) (*store.ChangeRequest, error)
+	RecordChangeRequestResult(ctx context.Context, accountID, changeID string, resultStatus int) error
+
+	// Cross-account delegation
+	CreateDelegation(ctx context.Context, delegation *store.Delegation) error
+	// CheckDelegation returns the error CreateDelegation would, without
+	// creating the delegation
+	CheckDelegation(ctx context.Context, delegation *store.Delegation) error
+	DeleteDelegation(ctx context.Context, accountID, delegateAccountID string) error
+	ListDelegations(ctx context.Context, accountID string) ([]*store.Delegation, error)
+
+	// API keys of machine clients
+	CreateAPIKey(ctx context.Context, key *store.APIKey) (string, error)
+	// CheckAPIKey returns the error CreateAPIKey would, without minting the
+	// key
+	CheckAPIKey(ctx context.Context, key *store.APIKey) error
+	GetAPIKey(ctx context.Context, accountID, keyID string) (*store.APIKey, error)
+	ListAPIKeys(ctx context.Context, accountID string) ([]*store.APIKey, error)
+	RotateAPIKey(ctx context.Context, accountID, keyID, rotatedBy string, overlap time.Duration, expiresAt string) (*store.APIKey, string, error)
+	RevokeAPIKey(ctx context.Context, accountID, keyID, revokedBy string) error
+
+	// Organizations and organization-level policies
+	CreateOrganization(ctx context.Context, name, createdBy string) (*store.Organization, error)
+	GetOrganization(ctx context.Context, orgID string) (*store.Organization, error)
+	ListOrganizations(ctx context.Context) ([]*store.Organization, error)
+	DeleteOrganization(ctx context.Context, orgID string) error
+	SetAccountOrganization(ctx context.Context, accountID, orgID string) error
+	CreateOrganizationPolicy(ctx context.Context, orgID, description, cedarPolicy string) (*store.OrganizationPolicy, error)
+	ListOrganizationPolicies(ctx context.Context, orgID string) ([]*store.OrganizationPolicy, error)
+	DeleteOrganizationPolicy(ctx context.Context, orgID, policyID string) error
+
+	// Platform guardrails
+	CreateGuardrail(ctx context.Context, description, cedarPolicy string) (*store.StaticPolicy, error)
+	ListGuardrails(ctx context.Context) ([]*store.StaticPolicy, error)
+	DeleteGuardrail(ctx context.Context, policyID string) error
+
+	// Decision analytics: hourly allow and deny counts per action, recorded
+	// while Config.DecisionAnalytics is on
+	ListDecisionCounts(ctx context.Context, accountID string, from, to time.Time) ([]*store.DecisionCount, error)
+	// ListPrincipals returns the callers seen in the account's decisions
+	// since a time, most recently seen first
+	ListPrincipals(ctx context.Context, accountID string, since time.Time) ([]*store.PrincipalSighting, error)
+	// QueryDecisionAudit pages through the account's audited decisions,
+	// recorded while Config.DecisionAudit is on
+	QueryDecisionAudit(ctx context.Context, accountID string, q store.DecisionAuditQuery, limit int, pageToken string) (*store.DecisionAuditPage, error)
+	// GetRecommendations flags policies and groups that look safe to prune;
+	// unused policies are only flagged with decision analytics
+	GetRecommendations(ctx context.Context, accountID string, unusedFor time.Duration) (*Recommendations, error)
+
+	// Policy store recovery
+	ExportPolicyStore(ctx context.Context, accountID string) (*PolicyStoreExport, error)
+	RebuildPolicyStore(ctx context.Context, accountID string, export *PolicyStoreExport) (*RebuildResult, error)
+	// MigrateSchema brings the account's policy store to the current schema
+	MigrateSchema(ctx context.Context, accountID string) (*SchemaMigration, error)
 }
 
 // authorizerImpl implements both Checker and Service interfaces
 type authorizerImpl struct {
-	cfg             *Config
-	logger          *slog.Logger
-	avpClient       client.AVPClient
-	privilegedCheck *privileged.Checker
-	accountStore    *store.AccountStore
-	adminStore      *store.AdminStore
-	groupStore      *store.GroupStore
-	memberStore     *store.MemberStore
+	cfg                *Config
+	logger             *slog.Logger
+	avpClient          client.AVPClient
+	dynamoClient       client.DynamoDBClient
+	privilegedCheck    *privileged.Checker
+	accountStore       *store.AccountStore
+	adminStore         *store.AdminStore
+	groupStore         *store.GroupStore
+	memberStore        *store.MemberStore
+	delegationStore    *store.DelegationStore
+	apiKeyStore        *store.APIKeyStore
+	apiKeyCache        *APIKeyCache
+	organizationStore  *store.OrganizationStore
+	attachmentStore    *store.AttachmentMetaStore
+	policyTagStore     *store.PolicyTagStore
+	deletionStore      *store.DeletionStore
+	pendingChangeStore *store.PendingChangeStore
+	changeRequestStore *store.ChangeRequestStore
+	analytics          *DecisionAnalytics
+	audit              *DecisionAudit
+	patterns           *PatternMatcher
+	provisioner        *AccountProvisioner
+	disableHooks       []DisableHook
+	now                clock.Clock
 }
 
 // New creates a new authorizer that implements both Checker and Service
 func New(cfg *Config, dynamoClient client.DynamoDBClient, avpClient client.AVPClient, logger *slog.Logger) *authorizerImpl {
+	now := clock.Or(cfg.Clock)
 	privilegedChecker := privileged.NewChecker(
 		cfg.AccountsTableName,
 		dynamoClient,
 		logger,
 	)
 
-	return &authorizerImpl{
-		cfg:             cfg,
-		logger:          logger,
-		avpClient:       avpClient,
-		privilegedCheck: privilegedChecker,
-		accountStore:    store.NewAccountStore(cfg.AccountsTableName, dynamoClient, logger),
-		adminStore:      store.NewAdminStore(cfg.AdminsTableName, dynamoClient, logger),
-		groupStore:      store.NewGroupStore(cfg.GroupsTableName, dynamoClient, logger),
-		memberStore:     store.NewMemberStore(cfg.MembersTableName, dynamoClient, logger),
+	var analytics *DecisionAnalytics
+	if cfg.DecisionAnalytics {
+		counts := store.NewDecisionCountStore(cfg.DecisionCountsTableName, dynamoClient, logger)
+		analytics = NewDecisionAnalytics(counts, cfg.DecisionFlushInterval, cfg.DecisionRetention, logger)
+		analytics.now = now
+	}
+	var audit *DecisionAudit
+	if cfg.DecisionAudit {
+		entries := store.NewDecisionAuditStore(cfg.DecisionAuditTableName, dynamoClient, logger)
+		audit = NewDecisionAudit(entries, cfg.DecisionFlushInterval, cfg.DecisionAuditRetention, logger)
+		audit.now = now
+	}
+
+	a := &authorizerImpl{
+		cfg:                cfg,
+		logger:             logger,
+		avpClient:          avpClient,
+		dynamoClient:       dynamoClient,
+		privilegedCheck:    privilegedChecker,
+		accountStore:       store.NewAccountStore(cfg.AccountsTableName, dynamoClient, logger).WithClock(now),
+		adminStore:         store.NewAdminStore(cfg.AdminsTableName, dynamoClient, logger).WithClock(now),
+		groupStore:         store.NewGroupStore(cfg.GroupsTableName, dynamoClient, logger).WithClock(now),
+		memberStore:        store.NewMemberStore(cfg.MembersTableName, dynamoClient, logger).WithGroupsTable(cfg.GroupsTableName).WithClock(now),
+		delegationStore:    store.NewDelegationStore(cfg.DelegationsTableName, dynamoClient, logger).WithClock(now),
+		apiKeyStore:        store.NewAPIKeyStore(cfg.APIKeysTableName, dynamoClient, logger).WithClock(now),
+		organizationStore:  store.NewOrganizationStore(cfg.OrganizationsTableName, dynamoClient, logger).WithClock(now),
+		attachmentStore:    store.NewAttachmentMetaStore(cfg.AttachmentsTableName, dynamoClient, logger).WithClock(now),
+		policyTagStore:     store.NewPolicyTagStore(cfg.PolicyTagsTableName, dynamoClient, logger).WithClock(now),
+		deletionStore:      store.NewDeletionStore(cfg.DeletionsTableName, dynamoClient, logger).WithClock(now),
+		pendingChangeStore: store.NewPendingChangeStore(cfg.PendingChangesTableName, dynamoClient, logger).WithClock(now),
+		changeRequestStore: store.NewChangeRequestStore(cfg.ChangeRequestsTableName, dynamoClient, logger).WithClock(now),
+		analytics:          analytics,
+		audit:              audit,
+		now:                now,
 	}
+	if cfg.APIKeyCacheTTL > 0 {
+		a.apiKeyCache = newAPIKeyCache(a.apiKeyStore, cfg.APIKeyCacheTTL, cfg.APIKeyRevocationRefresh, logger, now)
+	}
+	if cfg.PrincipalPatterns {
+		a.patterns = NewPatternMatcher(a.matchPatterns, cfg.PatternMatchInterval, logger)
+	}
+	if cfg.OnboardingWorkers > 0 {
+		a.provisioner = NewAccountProvisioner(a.accountStore, a.onboard, cfg.OnboardingWorkers, cfg.OnboardingQueueSize, cfg.OnboardingRate, logger)
+		a.provisioner.now = now
+	}
+	return a
 }
 
-// Authorize performs the authorization check
+// Authorize performs the authorization check. Policies take precedence in
+// this order: platform guardrails, the privileged bypass, organization
+// forbids, the account admin bypass, then organization and account policies.
 func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool, error) {
+	// Platform guardrails apply to every caller
+	if err := a.CheckGuardrails(ctx, req); err != nil {
+		return false, err
+	}
+
 	// Check if privileged (bypass all)
 	isPriv, err := a.IsPrivileged(ctx, req.AccountID)
 	if err != nil {
@@ -142,6 +322,12 @@ func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool
 		return true, nil
 	}
 
+	// Resources must belong to the caller's account
+	if err := a.checkResourceAccount(req.AccountID, req.Resource); err != nil {
+		a.logger.Warn("cross-account resource rejected", "account_id", req.AccountID, "resource", req.Resource)
+		return false, err
+	}
+
 	// Check if account is provisioned
 	account, err := a.accountStore.Get(ctx, req.AccountID)
 	if err != nil {
@@ -152,6 +338,29 @@ func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool
 		return false, fmt.Errorf("account not provisioned: %s", req.AccountID)
 	}
 
+	// Organization policies apply to every member account. An organization
+	// forbid cannot be overridden by the account, not even by its admins.
+	orgEffect := effectNoMatch
+	if account.OrganizationID != "" {
+		orgEffect, err = a.evaluateOrganization(ctx, req, account.OrganizationID)
+		if err != nil {
+			return false, err
+		}
+		if orgEffect == effectForbid {
+			a.logger.Info("authorization decision",
+				"account_id", req.AccountID,
+				"caller_arn", req.CallerARN,
+				"action", req.Action,
+				"resource", req.Resource,
+				"decision", false,
+				"organization_id", account.OrganizationID,
+			)
+			a.analytics.Record(req.AccountID, req.CallerARN, req.Action, false, nil)
+			a.audit.Record(req, false, BasisOrganization)
+			return false, nil
+		}
+	}
+
 	// Check if caller is admin (bypass Cedar)
 	isAdm, err := a.IsAdmin(ctx, req.AccountID, req.CallerARN)
 	if err != nil {
@@ -159,9 +368,13 @@ func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool
 	}
 	if isAdm {
 		a.logger.Debug("admin bypass", "account_id", req.AccountID, "caller_arn", req.CallerARN)
+		a.audit.Record(req, true, BasisAdmin)
 		return true, nil
 	}
 
+	// Pattern groups pick the caller up on the matcher's next pass
+	a.patterns.Observe(req.AccountID, req.CallerARN)
+
 	// Get user's group memberships
 	groups, err := a.memberStore.GetUserGroups(ctx, req.AccountID, req.CallerARN)
 	if err != nil {
@@ -170,6 +383,17 @@ func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool
 
 	// Build AVP request
 	avpReq := a.buildAVPRequest(req, groups, account.PolicyStoreID)
+	// Stores whose schema declares principal kinds also get the caller's
+	if StoreSchemaVersion(account.SchemaVersion) >= schema.PrincipalKindsVersion {
+		addPrincipalAttributes(avpReq, req.CallerARN)
+	}
+	if a.cfg.GroupTagsInContext {
+		groupTags, err := a.groupTagsContext(ctx, req.AccountID, groups)
+		if err != nil {
+			return false, err
+		}
+		avpReq.Context.(*avptypes.ContextDefinitionMemberContextMap).Value["groupTags"] = groupTags
+	}
 
 	// Call AVP
 	resp, err := a.avpClient.IsAuthorized(ctx, avpReq)
@@ -178,7 +402,7 @@ func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool
 		return false, fmt.Errorf("authorization check failed: %w", err)
 	}
 
-	decision := resp.Decision == avptypes.DecisionAllow
+	decision := combineEffects(orgEffect, effectOf(resp))
 	a.logger.Info("authorization decision",
 		"account_id", req.AccountID,
 		"caller_arn", req.CallerARN,
@@ -186,27 +410,39 @@ func (a *authorizerImpl) Authorize(ctx context.Context, req *AuthzRequest) (bool
 		"resource", req.Resource,
 		"decision", decision,
 	)
+	a.analytics.Record(req.AccountID, req.CallerARN, req.Action, decision, determiningPolicies(resp))
+	a.audit.Record(req, decision, BasisPolicy)
 
 	return decision, nil
 }
 
+// namespace returns the configured Cedar namespace
+func (a *authorizerImpl) namespace() schema.Namespace {
+	if a.cfg.CedarNamespace == "" {
+		return schema.DefaultNamespace
+	}
+	return schema.Namespace(a.cfg.CedarNamespace)
+}
+
 // buildAVPRequest creates the AVP IsAuthorized request
 func (a *authorizerImpl) buildAVPRequest(req *AuthzRequest, groups []string, policyStoreID string) *verifiedpermissions.IsAuthorizedInput {
+	ns := a.namespace()
+
 	// Build principal
 	principal := &avptypes.EntityIdentifier{
-		EntityType: aws.String("ROSA::Principal"),
+		EntityType: aws.String(ns.Principal()),
 		EntityId:   aws.String(req.CallerARN),
 	}
 
 	// Build action
 	action := &avptypes.ActionIdentifier{
-		ActionType: aws.String("ROSA::Action"),
-		ActionId:   aws.String(req.Action),
+		ActionType: aws.String(ns.Action()),
+		ActionId:   aws.String(schema.CanonicalAction(req.Action)),
 	}
 
 	// Build resource
 	resource := &avptypes.EntityIdentifier{
-		EntityType: aws.String("ROSA::Resource"),
+		EntityType: aws.String(ns.Resource()),
 		EntityId:   aws.String(req.Resource),
 	}
 
@@ -252,24 +488,26 @@ func (a *authorizerImpl) buildAVPRequest(req *AuthzRequest, groups []string, pol
 	for _, groupID := range groups {
 		entities = append(entities, avptypes.EntityItem{
 			Identifier: &avptypes.EntityIdentifier{
-				EntityType: aws.String("ROSA::Group"),
+				EntityType: aws.String(ns.Group()),
 				EntityId:   aws.String(groupID),
 			},
 		})
 	}
 
-	// Add resource with tags
-	if len(req.ResourceTags) > 0 {
+	// Add resource with tags and its parents
+	if len(req.ResourceTags) > 0 || len(req.ResourceParents) > 0 {
 		tagsMap := make(map[string]avptypes.AttributeValue)
 		for k, v := range req.ResourceTags {
 			tagsMap[k] = &avptypes.AttributeValueMemberString{Value: v}
 		}
-		entities = append(entities, avptypes.EntityItem{
-			Identifier: resource,
-			Attributes: map[string]avptypes.AttributeValue{
-				"tags": &avptypes.AttributeValueMemberRecord{Value: tagsMap},
-			},
-		})
+		entities = append(entities, resourceEntity(resource, tagsMap, ns, req.ResourceParents))
+	}
+	for i, parentARN := range req.ResourceParents {
+		parent := &avptypes.EntityIdentifier{
+			EntityType: aws.String(ns.Resource()),
+			EntityId:   aws.String(parentARN),
+		}
+		entities = append(entities, resourceEntity(parent, map[string]avptypes.AttributeValue{}, ns, req.ResourceParents[:i]))
 	}
 
 	return &verifiedpermissions.IsAuthorizedInput{
@@ -286,6 +524,24 @@ func (a *authorizerImpl) buildAVPRequest(req *AuthzRequest, groups []string, pol
 	}
 }
 
+// resourceEntity builds a resource entity whose parent is the innermost of
+// ancestors. The schema requires tags, so parents get an empty record.
+func resourceEntity(id *avptypes.EntityIdentifier, tags map[string]avptypes.AttributeValue, ns schema.Namespace, ancestors []string) avptypes.EntityItem {
+	item := avptypes.EntityItem{
+		Identifier: id,
+		Attributes: map[string]avptypes.AttributeValue{
+			"tags": &avptypes.AttributeValueMemberRecord{Value: tags},
+		},
+	}
+	if len(ancestors) > 0 {
+		item.Parents = []avptypes.EntityIdentifier{{
+			EntityType: aws.String(ns.Resource()),
+			EntityId:   aws.String(ancestors[len(ancestors)-1]),
+		}}
+	}
+	return item
+}
+
 // toAttributeValue converts a Go value (from JSON unmarshalling) to an AVP AttributeValue.
 func toAttributeValue(v any) avptypes.AttributeValue {
 	switch val := v.(type) {
@@ -321,60 +577,113 @@ func (a *authorizerImpl) IsPrivileged(ctx context.Context, accountID string) (bo
 	return a.privilegedCheck.IsPrivileged(ctx, accountID)
 }
 
-// EnableAccount creates a new account with an optional policy store
+// EnableAccount creates a new account and onboards it: tenant accounts get
+// a policy store holding the ROSA schema. An account whose onboarding fails
+// is kept as Failed, for RetryOnboarding or DisableAccount.
 func (a *authorizerImpl) EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error) {
 	account := &store.Account{
 		AccountID:  accountID,
 		Privileged: isPrivileged,
 		CreatedBy:  createdBy,
+		Onboarding: &store.Onboarding{State: store.OnboardingPending},
+	}
+	// Privileged accounts have no policy store to set up
+	if isPrivileged {
+		account.Onboarding.State = store.OnboardingReady
 	}
 
-	// If not privileged, create a policy store
+	if err := a.accountStore.Create(ctx, account); err != nil {
+		return nil, err
+	}
 	if !isPrivileged {
-		psResp, err := a.avpClient.CreatePolicyStore(ctx, &verifiedpermissions.CreatePolicyStoreInput{
-			ValidationSettings: &avptypes.ValidationSettings{
-				Mode: avptypes.ValidationModeStrict,
-			},
-			Description: aws.String(fmt.Sprintf("ROSA authorization policy store for account %s", accountID)),
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to create policy store: %w", err)
+		if err := a.onboard(ctx, account); err != nil {
+			return nil, err
 		}
+	}
 
-		account.PolicyStoreID = *psResp.PolicyStoreId
+	a.logger.Info("account enabled", "account_id", accountID, "privileged", isPrivileged)
+	return account, nil
+}
+
+func accountPolicyStoreDescription(accountID string) string {
+	return fmt.Sprintf("ROSA authorization policy store for account %s", accountID)
+}
+
+// createPolicyStore creates a policy store and puts the ROSA schema
+func (a *authorizerImpl) createPolicyStore(ctx context.Context, description string) (string, error) {
+	policyStoreID, err := a.newPolicyStore(ctx, description)
+	if err != nil {
+		return "", err
+	}
+	if err := a.putSchema(ctx, policyStoreID); err != nil {
+		// Try to clean up the policy store
+		_, _ = a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+			PolicyStoreId: aws.String(policyStoreID),
+		})
+		return "", err
+	}
+	return policyStoreID, nil
+}
+
+// newPolicyStore creates an empty policy store
+func (a *authorizerImpl) newPolicyStore(ctx context.Context, description string) (string, error) {
+	psResp, err := a.avpClient.CreatePolicyStore(ctx, &verifiedpermissions.CreatePolicyStoreInput{
+		ValidationSettings: &avptypes.ValidationSettings{
+			Mode: avptypes.ValidationModeStrict,
+		},
+		Description: aws.String(description),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create policy store: %w", err)
+	}
+	return aws.ToString(psResp.PolicyStoreId), nil
+}
 
-		// Set up the schema
+// putSchema puts the ROSA schema in a policy store
+func (a *authorizerImpl) putSchema(ctx context.Context, policyStoreID string) error {
+	cedarSchema, err := a.namespace().SchemaJSON()
+	if err == nil {
 		_, err = a.avpClient.PutSchema(ctx, &verifiedpermissions.PutSchemaInput{
-			PolicyStoreId: psResp.PolicyStoreId,
+			PolicyStoreId: aws.String(policyStoreID),
 			Definition: &avptypes.SchemaDefinitionMemberCedarJson{
-				Value: schema.CedarSchemaJSON,
+				Value: cedarSchema,
 			},
 		})
-		if err != nil {
-			// Try to clean up the policy store
-			_, _ = a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
-				PolicyStoreId: psResp.PolicyStoreId,
-			})
-			return nil, fmt.Errorf("failed to set policy store schema: %w", err)
-		}
 	}
+	if err != nil {
+		return fmt.Errorf("failed to set policy store schema: %w", err)
+	}
+	return nil
+}
 
-	if err := a.accountStore.Create(ctx, account); err != nil {
-		// Clean up policy store if we created one
-		if account.PolicyStoreID != "" {
-			_, _ = a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
-				PolicyStoreId: aws.String(account.PolicyStoreID),
-			})
+// DisableAccount removes an account and its policy store, or queues the
+// removal when it needs approval
+func (a *authorizerImpl) DisableAccount(ctx context.Context, accountID, disabledBy string) error {
+	if slices.Contains(a.cfg.ApprovalRequired, OperationDisableAccount) {
+		account, err := a.accountStore.Get(ctx, accountID)
+		if err != nil {
+			return err
 		}
-		return nil, err
+		if account == nil {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+	}
+	if err := a.requestApproval(ctx, accountID, OperationDisableAccount, accountID, disabledBy); err != nil {
+		return err
 	}
+	return a.disableAccount(ctx, accountID)
+}
 
-	a.logger.Info("account enabled", "account_id", accountID, "privileged", isPrivileged)
-	return account, nil
+// DisableHook stops what an account has running once it is disabled
+type DisableHook func(ctx context.Context, accountID string)
+
+// OnDisable adds hooks run when an account is disabled, directly or once the
+// disable is approved, such as cancelling its scheduled work
+func (a *authorizerImpl) OnDisable(hooks ...DisableHook) {
+	a.disableHooks = append(a.disableHooks, hooks...)
 }
 
-// DisableAccount removes an account and its policy store
-func (a *authorizerImpl) DisableAccount(ctx context.Context, accountID string) error {
+func (a *authorizerImpl) disableAccount(ctx context.Context, accountID string) error {
 	account, err := a.accountStore.Get(ctx, accountID)
 	if err != nil {
 		return err
@@ -393,6 +702,12 @@ func (a *authorizerImpl) DisableAccount(ctx context.Context, accountID string) e
 		}
 	}
 
+	// Keys of an unlinked account must not outlive it
+	a.revokeAPIKeys(ctx, accountID, "account-disabled")
+	for _, hook := range a.disableHooks {
+		hook(ctx, accountID)
+	}
+
 	return a.accountStore.Delete(ctx, accountID)
 }
 
@@ -406,6 +721,16 @@ func (a *authorizerImpl) ListAccounts(ctx context.Context) ([]*store.Account, er
 	return a.accountStore.List(ctx)
 }
 
+// ListAccountsPage returns one page of the accounts matching filter
+func (a *authorizerImpl) ListAccountsPage(ctx context.Context, limit int, pageToken string, filter store.AccountFilter) (*store.AccountPage, error) {
+	return a.accountStore.ListPage(ctx, limit, pageToken, filter)
+}
+
+// CountAccounts returns the number of accounts matching filter
+func (a *authorizerImpl) CountAccounts(ctx context.Context, filter store.AccountFilter) (int, error) {
+	return a.accountStore.Count(ctx, filter)
+}
+
 // IsAccountProvisioned checks if an account is provisioned
 func (a *authorizerImpl) IsAccountProvisioned(ctx context.Context, accountID string) (bool, error) {
 	// Privileged accounts are always considered provisioned
@@ -417,7 +742,8 @@ func (a *authorizerImpl) IsAccountProvisioned(ctx context.Context, accountID str
 		return true, nil
 	}
 
-	return a.accountStore.Exists(ctx, accountID)
+	// Accounts still onboarding have no usable policy store yet
+	return a.accountStore.IsOnboarded(ctx, accountID)
 }
 
 // IsAdmin checks if a principal is an admin
@@ -425,18 +751,49 @@ func (a *authorizerImpl) IsAdmin(ctx context.Context, accountID, principalARN st
 	return a.adminStore.IsAdmin(ctx, accountID, principalARN)
 }
 
-// AddAdmin adds an admin
-func (a *authorizerImpl) AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) error {
+// AddAdmin makes a principal an account admin. Adding an existing admin is
+// not an error: the existing entry is returned with created false.
+func (a *authorizerImpl) AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) (*store.Admin, bool, error) {
 	admin := &store.Admin{
 		AccountID:    accountID,
 		PrincipalARN: principalARN,
 		CreatedBy:    createdBy,
 	}
-	return a.adminStore.Add(ctx, admin)
+	err := a.adminStore.Add(ctx, admin)
+	if err == nil {
+		return admin, true, nil
+	}
+	if !errors.Is(err, store.ErrAdminExists) {
+		return nil, false, err
+	}
+
+	existing, getErr := a.adminStore.Get(ctx, accountID, principalARN)
+	if getErr != nil {
+		return nil, false, getErr
+	}
+	if existing == nil {
+		// Removed between the conditional write and the read
+		return nil, false, err
+	}
+	return existing, false, nil
 }
 
-// RemoveAdmin removes an admin
-func (a *authorizerImpl) RemoveAdmin(ctx context.Context, accountID, principalARN string) error {
+// RemoveAdmin removes an admin, or queues the removal when it needs
+// approval, failing with store.ErrAdminNotFound if the principal is not an
+// admin
+func (a *authorizerImpl) RemoveAdmin(ctx context.Context, accountID, principalARN, removedBy string) error {
+	if slices.Contains(a.cfg.ApprovalRequired, OperationRemoveAdmin) {
+		admin, err := a.adminStore.Get(ctx, accountID, principalARN)
+		if err != nil {
+			return err
+		}
+		if admin == nil {
+			return fmt.Errorf("%w: %s in account %s", store.ErrAdminNotFound, principalARN, accountID)
+		}
+	}
+	if err := a.requestApproval(ctx, accountID, OperationRemoveAdmin, principalARN, removedBy); err != nil {
+		return err
+	}
 	return a.adminStore.Remove(ctx, accountID, principalARN)
 }
 
@@ -446,8 +803,11 @@ func (a *authorizerImpl) ListAdmins(ctx context.Context, accountID string) ([]st
 }
 
 // CreateGroup creates a new group
-func (a *authorizerImpl) CreateGroup(ctx context.Context, accountID, name, description string) (*store.Group, error) {
-	return a.groupStore.Create(ctx, accountID, name, description)
+func (a *authorizerImpl) CreateGroup(ctx context.Context, accountID, name, description string, tags map[string]string) (*store.Group, error) {
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
+	}
+	return a.groupStore.Create(ctx, accountID, name, description, tags)
 }
 
 // GetGroup retrieves a group
@@ -456,14 +816,30 @@ func (a *authorizerImpl) GetGroup(ctx context.Context, accountID, groupID string
 }
 
 // DeleteGroup removes a group and its members
-func (a *authorizerImpl) DeleteGroup(ctx context.Context, accountID, groupID string) error {
+func (a *authorizerImpl) DeleteGroup(ctx context.Context, accountID, groupID, deletedBy string) error {
+	group, err := a.groupStore.Get(ctx, accountID, groupID)
+	if err != nil {
+		return err
+	}
+	members, err := a.memberStore.ListGroupMembers(ctx, accountID, groupID)
+	if err != nil {
+		return err
+	}
+
 	// First remove all members
 	if err := a.memberStore.RemoveAllGroupMembers(ctx, accountID, groupID); err != nil {
 		return err
 	}
 
 	// Then delete the group
-	return a.groupStore.Delete(ctx, accountID, groupID)
+	if err := a.groupStore.Delete(ctx, accountID, groupID); err != nil {
+		return err
+	}
+
+	if group != nil {
+		a.recordDeletion(ctx, accountID, store.DeletedGroup, groupID, deletedGroup{Group: group, Members: members}, deletedBy)
+	}
+	return nil
 }
 
 // ListGroups returns all groups for an account
@@ -471,8 +847,31 @@ func (a *authorizerImpl) ListGroups(ctx context.Context, accountID string) ([]*s
 	return a.groupStore.List(ctx, accountID)
 }
 
-// AddGroupMember adds a member to a group
+// AddGroupMember adds a member to a group, held to the same member account,
+// group size and pattern group rules as UpdateGroupMembers
 func (a *authorizerImpl) AddGroupMember(ctx context.Context, accountID, groupID, memberARN string) error {
+	if err := validatePrincipalARN(memberARN); err != nil {
+		return fmt.Errorf("invalid member %s: %w", memberARN, err)
+	}
+	if err := a.checkMemberAccount(accountID, memberARN); err != nil {
+		return err
+	}
+	group, err := a.groupStore.Get(ctx, accountID, groupID)
+	if err != nil {
+		return err
+	}
+	if group != nil && group.MemberPattern != "" {
+		return fmt.Errorf("%w: %s", ErrPatternGroup, groupID)
+	}
+	if a.cfg.MaxGroupMembers > 0 {
+		over, err := a.overMemberLimit(ctx, accountID, groupID, []string{memberARN}, nil)
+		if err != nil {
+			return err
+		}
+		if over[memberARN] {
+			return fmt.Errorf("%w: group %s has %d members", ErrGroupMemberLimit, groupID, a.cfg.MaxGroupMembers)
+		}
+	}
 	return a.memberStore.Add(ctx, accountID, groupID, memberARN)
 }
 
@@ -526,12 +925,29 @@ func (a *authorizerImpl) getAccountPolicyStoreID(ctx context.Context, accountID
 	return account.PolicyStoreID, nil
 }
 
+// ValidatePolicy checks that cedarPolicy is not empty and names only types
+// in the configured namespace
+func (a *authorizerImpl) ValidatePolicy(cedarPolicy string) error {
+	if strings.TrimSpace(cedarPolicy) == "" {
+		return fmt.Errorf("invalid policy: cedar policy text is required")
+	}
+	if err := a.namespace().CheckPolicy(cedarPolicy); err != nil {
+		return fmt.Errorf("invalid policy: %w", err)
+	}
+	return nil
+}
+
 // CreatePolicy creates a new policy template in AVP.
 // The cedarPolicy should use ?principal as the placeholder for template-linked policies.
-func (a *authorizerImpl) CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string) (*store.Policy, error) {
-	if strings.TrimSpace(cedarPolicy) == "" {
-		return nil, fmt.Errorf("invalid policy: cedar policy text is required")
+func (a *authorizerImpl) CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error) {
+	if err := a.ValidatePolicy(cedarPolicy); err != nil {
+		return nil, err
+	}
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
 	}
+	// Stored formatted, so diffs and comparisons ignore how it was written
+	cedarPolicy = CanonicalPolicy(cedarPolicy)
 
 	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
 	if err != nil {
@@ -549,14 +965,20 @@ func (a *authorizerImpl) CreatePolicy(ctx context.Context, accountID, name, desc
 
 	a.logger.Info("policy template created", "account_id", accountID, "policy_template_id", *resp.PolicyTemplateId, "name", name)
 
-	return &store.Policy{
+	policy := &store.Policy{
 		AccountID:   accountID,
 		PolicyID:    *resp.PolicyTemplateId,
 		Name:        name,
 		Description: description,
 		CedarPolicy: cedarPolicy,
-		CreatedAt:   resp.CreatedDate.Format(time.RFC3339),
-	}, nil
+		Tags:        tags,
+		CreatedAt:   clock.Format(*resp.CreatedDate),
+	}
+	if err := a.policyTagStore.Put(ctx, accountID, policy.PolicyID, tags); err != nil {
+		return nil, err
+	}
+	return policy, a.awaitVisibility(ctx, "create policy "+policy.PolicyID,
+		a.policyTemplateVisible(policyStoreID, policy.PolicyID, ""))
 }
 
 // GetPolicy retrieves a policy template from AVP
@@ -575,6 +997,10 @@ func (a *authorizerImpl) GetPolicy(ctx context.Context, accountID, policyID stri
 	}
 
 	name, description := decodePolicyMeta(aws.ToString(resp.Description))
+	tags, err := a.policyTagStore.Get(ctx, accountID, policyID)
+	if err != nil {
+		return nil, err
+	}
 
 	return &store.Policy{
 		AccountID:   accountID,
@@ -582,16 +1008,22 @@ func (a *authorizerImpl) GetPolicy(ctx context.Context, accountID, policyID stri
 		Name:        name,
 		Description: description,
 		CedarPolicy: aws.ToString(resp.Statement),
-		CreatedAt:   resp.CreatedDate.Format(time.RFC3339),
+		Tags:        tags,
+		CreatedAt:   clock.Format(*resp.CreatedDate),
 	}, nil
 }
 
 // UpdatePolicy updates a policy template in AVP.
 // AVP automatically propagates template changes to all template-linked policies.
-func (a *authorizerImpl) UpdatePolicy(ctx context.Context, accountID, policyID, name, description, cedarPolicy string) (*store.Policy, error) {
-	if strings.TrimSpace(cedarPolicy) == "" {
-		return nil, fmt.Errorf("invalid policy: cedar policy text is required")
+// Nil tags leave the policy's tags as they are.
+func (a *authorizerImpl) UpdatePolicy(ctx context.Context, accountID, policyID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error) {
+	if err := a.ValidatePolicy(cedarPolicy); err != nil {
+		return nil, err
 	}
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
+	}
+	cedarPolicy = CanonicalPolicy(cedarPolicy)
 
 	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
 	if err != nil {
@@ -610,18 +1042,43 @@ func (a *authorizerImpl) UpdatePolicy(ctx context.Context, accountID, policyID,
 
 	a.logger.Info("policy template updated", "account_id", accountID, "policy_template_id", policyID)
 
-	return &store.Policy{
+	if tags != nil {
+		err = a.policyTagStore.Put(ctx, accountID, policyID, tags)
+	} else {
+		tags, err = a.policyTagStore.Get(ctx, accountID, policyID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	policy := &store.Policy{
 		AccountID:   accountID,
 		PolicyID:    policyID,
 		Name:        name,
 		Description: description,
 		CedarPolicy: cedarPolicy,
-		CreatedAt:   resp.CreatedDate.Format(time.RFC3339),
-	}, nil
+		Tags:        tags,
+		CreatedAt:   clock.Format(*resp.CreatedDate),
+	}
+	return policy, a.awaitVisibility(ctx, "update policy "+policyID,
+		a.policyTemplateVisible(policyStoreID, policyID, cedarPolicy))
+}
+
+// DeletePolicy removes a policy template from AVP, or queues the removal when
+// it needs approval
+func (a *authorizerImpl) DeletePolicy(ctx context.Context, accountID, policyID, deletedBy string) error {
+	if slices.Contains(a.cfg.ApprovalRequired, OperationDeletePolicy) {
+		if _, err := a.GetPolicy(ctx, accountID, policyID); err != nil {
+			return err
+		}
+	}
+	if err := a.requestApproval(ctx, accountID, OperationDeletePolicy, policyID, deletedBy); err != nil {
+		return err
+	}
+	return a.deletePolicy(ctx, accountID, policyID, deletedBy)
 }
 
-// DeletePolicy removes a policy template from AVP
-func (a *authorizerImpl) DeletePolicy(ctx context.Context, accountID, policyID string) error {
+func (a *authorizerImpl) deletePolicy(ctx context.Context, accountID, policyID, deletedBy string) error {
 	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
 	if err != nil {
 		return err
@@ -642,6 +1099,11 @@ func (a *authorizerImpl) DeletePolicy(ctx context.Context, accountID, policyID s
 		return fmt.Errorf("cannot delete policy with existing attachments")
 	}
 
+	policy, err := a.GetPolicy(ctx, accountID, policyID)
+	if err != nil {
+		return err
+	}
+
 	_, err = a.avpClient.DeletePolicyTemplate(ctx, &verifiedpermissions.DeletePolicyTemplateInput{
 		PolicyStoreId:    aws.String(policyStoreID),
 		PolicyTemplateId: aws.String(policyID),
@@ -651,7 +1113,11 @@ func (a *authorizerImpl) DeletePolicy(ctx context.Context, accountID, policyID s
 	}
 
 	a.logger.Info("policy template deleted", "account_id", accountID, "policy_template_id", policyID)
-	return nil
+	if err := a.policyTagStore.Delete(ctx, accountID, policyID); err != nil {
+		a.logger.Warn("failed to delete policy tags", "error", err, "account_id", accountID, "policy_id", policyID)
+	}
+	a.recordDeletion(ctx, accountID, store.DeletedPolicy, policyID, policy, deletedBy)
+	return a.awaitVisibility(ctx, "delete policy "+policyID, a.policyTemplateGone(policyStoreID, policyID))
 }
 
 // ListPolicies returns all policy templates for an account from AVP
@@ -668,6 +1134,11 @@ func (a *authorizerImpl) ListPolicies(ctx context.Context, accountID string) ([]
 		return nil, fmt.Errorf("failed to list policy templates: %w", err)
 	}
 
+	tags, err := a.policyTagStore.List(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
 	policies := make([]*store.Policy, 0, len(resp.PolicyTemplates))
 	for _, tmpl := range resp.PolicyTemplates {
 		templateID := aws.ToString(tmpl.PolicyTemplateId)
@@ -689,7 +1160,8 @@ func (a *authorizerImpl) ListPolicies(ctx context.Context, accountID string) ([]
 			Name:        name,
 			Description: description,
 			CedarPolicy: aws.ToString(detail.Statement),
-			CreatedAt:   detail.CreatedDate.Format(time.RFC3339),
+			Tags:        tags[templateID],
+			CreatedAt:   clock.Format(*detail.CreatedDate),
 		})
 	}
 
@@ -697,28 +1169,85 @@ func (a *authorizerImpl) ListPolicies(ctx context.Context, accountID string) ([]
 }
 
 // AttachPolicy creates a template-linked policy in AVP, binding the template
-// to a concrete principal (user or group).
-func (a *authorizerImpl) AttachPolicy(ctx context.Context, accountID, policyID string, targetType TargetType, targetID string) (*Attachment, error) {
+// to a concrete principal (user, role or group). The optional name and description
+// are stored alongside. If the policy is already attached to the target, the
+// existing attachment is returned unchanged with created false.
+// AVP has no conditional create, so concurrent attaches of the same pair can
+// still race; the check only stops repeated requests from piling up links.
+// A user or role target that is a principal pattern is attached through
+// the pattern group of the pattern; a user target naming an IAM role is
+// attached as a role.
+func (a *authorizerImpl) AttachPolicy(ctx context.Context, accountID, policyID string, targetType TargetType, targetID, name, description string) (*Attachment, bool, error) {
+	if err := ValidateTarget(targetType, targetID); err != nil {
+		return nil, false, err
+	}
+	targetType = NormalizeTarget(targetType, targetID)
+
 	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
-	// Build principal entity for template linking
-	var principalEntity *avptypes.EntityIdentifier
-	switch targetType {
-	case TargetTypeGroup:
-		principalEntity = &avptypes.EntityIdentifier{
-			EntityType: aws.String("ROSA::Group"),
-			EntityId:   aws.String(targetID),
+	var pattern string
+	if targetType != TargetTypeGroup && IsPrincipalPattern(targetID) {
+		group, err := a.patternGroup(ctx, accountID, targetID)
+		if err != nil {
+			return nil, false, err
 		}
-	default:
-		principalEntity = &avptypes.EntityIdentifier{
-			EntityType: aws.String("ROSA::Principal"),
-			EntityId:   aws.String(targetID),
+		pattern, targetType, targetID = targetID, TargetTypeGroup, group.GroupID
+	}
+
+	existing, err := a.ListAttachments(ctx, accountID, AttachmentFilter{PolicyID: policyID, TargetType: targetType, TargetID: targetID})
+	if err != nil {
+		return nil, false, err
+	}
+	for _, att := range existing {
+		if att.PolicyID == policyID && att.TargetType == targetType && att.TargetID == targetID {
+			a.logger.Info("policy already attached", "account_id", accountID, "policy_id", policyID, "target_type", targetType, "target_id", targetID, "avp_policy_id", att.AttachmentID)
+			att.Pattern = pattern
+			return att, false, nil
 		}
 	}
 
+	avpResp, err := a.createTemplateLinkedPolicy(ctx, policyStoreID, policyID, targetType, targetID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	a.logger.Info("policy attached", "account_id", accountID, "policy_id", policyID, "target_type", targetType, "target_id", targetID, "avp_policy_id", *avpResp.PolicyId)
+
+	attachment := &Attachment{
+		AttachmentID: *avpResp.PolicyId,
+		PolicyID:     policyID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		Pattern:      pattern,
+		Name:         name,
+		Description:  description,
+		CreatedAt:    clock.Format(*avpResp.CreatedDate),
+	}
+	if name != "" || description != "" {
+		if err := a.attachmentStore.Put(ctx, &store.AttachmentMeta{
+			AccountID:    accountID,
+			AttachmentID: attachment.AttachmentID,
+			Name:         name,
+			Description:  description,
+		}); err != nil {
+			return nil, false, err
+		}
+	}
+	return attachment, true, a.awaitVisibility(ctx, "attach policy "+attachment.AttachmentID,
+		a.policyVisible(policyStoreID, attachment.AttachmentID, true))
+}
+
+// createTemplateLinkedPolicy binds a policy template to a user or group principal
+func (a *authorizerImpl) createTemplateLinkedPolicy(ctx context.Context, policyStoreID, policyID string, targetType TargetType, targetID string) (*verifiedpermissions.CreatePolicyOutput, error) {
+	// Build principal entity for template linking
+	principalEntity := &avptypes.EntityIdentifier{
+		EntityType: aws.String(a.targetEntityType(targetType)),
+		EntityId:   aws.String(targetID),
+	}
+
 	// Create template-linked policy in AVP
 	avpResp, err := a.avpClient.CreatePolicy(ctx, &verifiedpermissions.CreatePolicyInput{
 		PolicyStoreId: aws.String(policyStoreID),
@@ -732,20 +1261,115 @@ func (a *authorizerImpl) AttachPolicy(ctx context.Context, accountID, policyID s
 	if err != nil {
 		return nil, fmt.Errorf("failed to create template-linked policy: %w", err)
 	}
+	return avpResp, nil
+}
 
-	a.logger.Info("policy attached", "account_id", accountID, "policy_id", policyID, "target_type", targetType, "target_id", targetID, "avp_policy_id", *avpResp.PolicyId)
+// GetAttachment returns an attachment resolved from AVP, with its metadata
+func (a *authorizerImpl) GetAttachment(ctx context.Context, accountID, attachmentID string) (*Attachment, error) {
+	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
 
-	return &Attachment{
-		AttachmentID: *avpResp.PolicyId,
-		PolicyID:     policyID,
-		TargetType:   targetType,
-		TargetID:     targetID,
-		CreatedAt:    avpResp.CreatedDate.Format(time.RFC3339),
-	}, nil
+	attachment, err := a.getAttachment(ctx, policyStoreID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := a.attachmentStore.Get(ctx, accountID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if meta != nil {
+		attachment.Name, attachment.Description = meta.Name, meta.Description
+	}
+	return attachment, nil
+}
+
+// UpdateAttachment replaces the name and description of an attachment
+func (a *authorizerImpl) UpdateAttachment(ctx context.Context, accountID, attachmentID, name, description string) (*Attachment, error) {
+	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment, err := a.getAttachment(ctx, policyStoreID, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.attachmentStore.Put(ctx, &store.AttachmentMeta{
+		AccountID:    accountID,
+		AttachmentID: attachmentID,
+		Name:         name,
+		Description:  description,
+	}); err != nil {
+		return nil, err
+	}
+
+	attachment.Name, attachment.Description = name, description
+	return attachment, nil
+}
+
+// getAttachment reads a template-linked policy from AVP, failing with
+// ErrAttachmentNotFound if it does not exist or is not an attachment
+func (a *authorizerImpl) getAttachment(ctx context.Context, policyStoreID, attachmentID string) (*Attachment, error) {
+	resp, err := a.avpClient.GetPolicy(ctx, &verifiedpermissions.GetPolicyInput{
+		PolicyStoreId: aws.String(policyStoreID),
+		PolicyId:      aws.String(attachmentID),
+	})
+	if isNotFound(err) {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachmentID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy attachment: %w", err)
+	}
+
+	tlDef, ok := resp.Definition.(*avptypes.PolicyDefinitionDetailMemberTemplateLinked)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentNotFound, attachmentID)
+	}
+
+	att := &Attachment{
+		AttachmentID: attachmentID,
+		PolicyID:     aws.ToString(tlDef.Value.PolicyTemplateId),
+	}
+	if resp.CreatedDate != nil {
+		att.CreatedAt = clock.Format(*resp.CreatedDate)
+	}
+	if tlDef.Value.Principal != nil {
+		att.TargetType, att.TargetID = attachmentTarget(tlDef.Value.Principal)
+	}
+	return att, nil
+}
+
+// targetEntityType returns the principal entity type of an attachment target;
+// users and roles are both principals
+func (a *authorizerImpl) targetEntityType(targetType TargetType) string {
+	if targetType == TargetTypeGroup {
+		return a.namespace().Group()
+	}
+	return a.namespace().Principal()
+}
+
+// attachmentTarget maps a template-linked policy's principal to an attachment
+// target. Any namespace's Group type is a group, so attachments made before a
+// namespace change are still recognized; principals are users or, by their
+// ARN, roles.
+func attachmentTarget(principal *avptypes.EntityIdentifier) (TargetType, string) {
+	id := aws.ToString(principal.EntityId)
+	if strings.HasSuffix(aws.ToString(principal.EntityType), "::Group") {
+		return TargetTypeGroup, id
+	}
+	return NormalizeTarget(TargetTypeUser, id), id
 }
 
 // DetachPolicy removes a policy attachment. The attachmentID is the AVP policy ID.
-func (a *authorizerImpl) DetachPolicy(ctx context.Context, accountID, attachmentID string) error {
+func (a *authorizerImpl) DetachPolicy(ctx context.Context, accountID, attachmentID, deletedBy string) error {
+	attachment, err := a.GetAttachment(ctx, accountID, attachmentID)
+	if err != nil {
+		return err
+	}
 	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
 	if err != nil {
 		return err
@@ -758,9 +1382,13 @@ func (a *authorizerImpl) DetachPolicy(ctx context.Context, accountID, attachment
 	if err != nil {
 		return fmt.Errorf("failed to delete policy attachment: %w", err)
 	}
+	if err := a.attachmentStore.Delete(ctx, accountID, attachmentID); err != nil {
+		a.logger.Warn("failed to delete attachment metadata", "error", err, "account_id", accountID, "attachment_id", attachmentID)
+	}
 
 	a.logger.Info("policy detached", "account_id", accountID, "avp_policy_id", attachmentID)
-	return nil
+	a.recordDeletion(ctx, accountID, store.DeletedAttachment, attachmentID, attachment, deletedBy)
+	return a.awaitVisibility(ctx, "detach policy "+attachmentID, a.policyVisible(policyStoreID, attachmentID, false))
 }
 
 // ListAttachments returns attachments matching the filter by querying AVP ListPolicies.
@@ -779,13 +1407,9 @@ func (a *authorizerImpl) ListAttachments(ctx context.Context, accountID string,
 	}
 
 	if filter.TargetType != "" && filter.TargetID != "" {
-		entityType := "ROSA::Principal"
-		if filter.TargetType == TargetTypeGroup {
-			entityType = "ROSA::Group"
-		}
 		policyFilter.Principal = &avptypes.EntityReferenceMemberIdentifier{
 			Value: avptypes.EntityIdentifier{
-				EntityType: aws.String(entityType),
+				EntityType: aws.String(a.targetEntityType(filter.TargetType)),
 				EntityId:   aws.String(filter.TargetID),
 			},
 		}
@@ -799,6 +1423,11 @@ func (a *authorizerImpl) ListAttachments(ctx context.Context, accountID string,
 		return nil, fmt.Errorf("failed to list policy attachments: %w", err)
 	}
 
+	metas, err := a.attachmentStore.List(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
 	attachments := make([]*Attachment, 0, len(listResp.Policies))
 	for _, p := range listResp.Policies {
 		att := &Attachment{
@@ -806,23 +1435,21 @@ func (a *authorizerImpl) ListAttachments(ctx context.Context, accountID string,
 		}
 
 		if p.CreatedDate != nil {
-			att.CreatedAt = p.CreatedDate.Format(time.RFC3339)
+			att.CreatedAt = clock.Format(*p.CreatedDate)
 		}
 
 		// Extract template ID and principal from definition
 		if tlDef, ok := p.Definition.(*avptypes.PolicyDefinitionItemMemberTemplateLinked); ok {
 			att.PolicyID = aws.ToString(tlDef.Value.PolicyTemplateId)
 			if tlDef.Value.Principal != nil {
-				entityType := aws.ToString(tlDef.Value.Principal.EntityType)
-				att.TargetID = aws.ToString(tlDef.Value.Principal.EntityId)
-				if entityType == "ROSA::Group" {
-					att.TargetType = TargetTypeGroup
-				} else {
-					att.TargetType = TargetTypeUser
-				}
+				att.TargetType, att.TargetID = attachmentTarget(tlDef.Value.Principal)
 			}
 		}
 
+		if meta, ok := metas[att.AttachmentID]; ok {
+			att.Name, att.Description = meta.Name, meta.Description
+		}
+
 		attachments = append(attachments, att)
 	}
 
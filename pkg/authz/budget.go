@@ -0,0 +1,60 @@
+package authz
+
+import (
+	"context"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
+)
+
+// budgetedChecker bounds every check by the authz share of the request's
+// timeout budget
+type budgetedChecker struct {
+	inner Checker
+}
+
+// NewBudgetedChecker wraps a Checker so the checks made for one request
+// together finish within the upstream.Authz budget set by upstream.WithBudget.
+// A check that runs out of budget fails with context.DeadlineExceeded.
+func NewBudgetedChecker(inner Checker) Checker {
+	return &budgetedChecker{inner: inner}
+}
+
+func (c *budgetedChecker) Authorize(ctx context.Context, req *AuthzRequest) (bool, error) {
+	ctx, cancel := upstream.WithDeadline(ctx, upstream.Authz)
+	defer cancel()
+	return c.inner.Authorize(ctx, req)
+}
+
+func (c *budgetedChecker) IsPrivileged(ctx context.Context, accountID string) (bool, error) {
+	ctx, cancel := upstream.WithDeadline(ctx, upstream.Authz)
+	defer cancel()
+	return c.inner.IsPrivileged(ctx, accountID)
+}
+
+func (c *budgetedChecker) IsAdmin(ctx context.Context, accountID, principalARN string) (bool, error) {
+	ctx, cancel := upstream.WithDeadline(ctx, upstream.Authz)
+	defer cancel()
+	return c.inner.IsAdmin(ctx, accountID, principalARN)
+}
+
+func (c *budgetedChecker) IsAccountProvisioned(ctx context.Context, accountID string) (bool, error) {
+	ctx, cancel := upstream.WithDeadline(ctx, upstream.Authz)
+	defer cancel()
+	return c.inner.IsAccountProvisioned(ctx, accountID)
+}
+
+// budgetedGuardrails bounds guardrail checks by the authz budget
+type budgetedGuardrails struct {
+	inner GuardrailChecker
+}
+
+// NewBudgetedGuardrails wraps a GuardrailChecker like NewBudgetedChecker
+func NewBudgetedGuardrails(inner GuardrailChecker) GuardrailChecker {
+	return &budgetedGuardrails{inner: inner}
+}
+
+func (g *budgetedGuardrails) CheckGuardrails(ctx context.Context, req *AuthzRequest) error {
+	ctx, cancel := upstream.WithDeadline(ctx, upstream.Authz)
+	defer cancel()
+	return g.inner.CheckGuardrails(ctx, req)
+}
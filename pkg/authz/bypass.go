@@ -0,0 +1,82 @@
+package authz
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// BasisBypass records a decision made by a route bypass rule in the
+// decision audit log
+const BasisBypass = "bypass"
+
+// bypassMethods are the methods a bypass rule may name
+var bypassMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+}
+
+// BypassRule exempts the requests of privileged accounts on matching routes
+// from authorization, and refuses the requests of other accounts. Rules are
+// written "METHOD /path/template" or "/path/template" for any method. The
+// template is compared with the route's, variables included, and a
+// template ending in /* also matches every route below it.
+type BypassRule struct {
+	Method string
+	Path   string
+}
+
+// ParseBypassRule parses a route bypass rule
+func ParseBypassRule(s string) (BypassRule, error) {
+	fields := strings.Fields(s)
+	var rule BypassRule
+	switch len(fields) {
+	case 1:
+		rule.Path = fields[0]
+	case 2:
+		rule.Method, rule.Path = strings.ToUpper(fields[0]), fields[1]
+		if !slices.Contains(bypassMethods, rule.Method) {
+			return BypassRule{}, fmt.Errorf("bypass rule %q: invalid method %s", s, fields[0])
+		}
+	default:
+		return BypassRule{}, fmt.Errorf("bypass rule %q: must be a path template, optionally preceded by a method", s)
+	}
+	if !strings.HasPrefix(rule.Path, "/") {
+		return BypassRule{}, fmt.Errorf("bypass rule %q: path template must start with /", s)
+	}
+	if strings.Contains(strings.TrimSuffix(rule.Path, "/*"), "*") {
+		return BypassRule{}, fmt.Errorf("bypass rule %q: * may only end the path template, as /*", s)
+	}
+	return rule, nil
+}
+
+// ParseBypassRules parses route bypass rules
+func ParseBypassRules(rules []string) ([]BypassRule, error) {
+	parsed := make([]BypassRule, 0, len(rules))
+	for _, s := range rules {
+		rule, err := ParseBypassRule(s)
+		if err != nil {
+			return nil, err
+		}
+		parsed = append(parsed, rule)
+	}
+	return parsed, nil
+}
+
+// Matches reports whether the rule covers a request to a route
+func (r BypassRule) Matches(method, pathTemplate string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "/*"); ok {
+		return pathTemplate == prefix || strings.HasPrefix(pathTemplate, prefix+"/")
+	}
+	return pathTemplate == r.Path
+}
+
+func (r BypassRule) String() string {
+	if r.Method == "" {
+		return r.Path
+	}
+	return r.Method + " " + r.Path
+}
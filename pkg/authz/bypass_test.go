@@ -0,0 +1,60 @@
+package authz
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBypassRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    BypassRule
+		wantErr string
+	}{
+		{rule: "POST /api/v0/work", want: BypassRule{Method: "POST", Path: "/api/v0/work"}},
+		{rule: "delete /api/v0/work/{id}", want: BypassRule{Method: "DELETE", Path: "/api/v0/work/{id}"}},
+		{rule: "/api/v0/management_clusters/*", want: BypassRule{Path: "/api/v0/management_clusters/*"}},
+		{rule: "FETCH /api/v0/work", wantErr: "invalid method"},
+		{rule: "api/v0/work", wantErr: "must start with /"},
+		{rule: "/api/v0/*/work", wantErr: "may only end"},
+		{rule: "GET /api/v0/work extra", wantErr: "optionally preceded by a method"},
+	}
+	for _, tt := range tests {
+		got, err := ParseBypassRule(tt.rule)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseBypassRule(%q): expected an error containing %q, got %v", tt.rule, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseBypassRule(%q) = %+v, %v, want %+v", tt.rule, got, err, tt.want)
+		}
+	}
+}
+
+func TestBypassRuleMatches(t *testing.T) {
+	tests := []struct {
+		rule     string
+		method   string
+		template string
+		want     bool
+	}{
+		{"POST /api/v0/work", "POST", "/api/v0/work", true},
+		{"POST /api/v0/work", "GET", "/api/v0/work", false},
+		{"POST /api/v0/work", "POST", "/api/v0/work/{id}", false},
+		{"/api/v0/work/{id}", "DELETE", "/api/v0/work/{id}", true},
+		{"/api/v0/work/*", "GET", "/api/v0/work", true},
+		{"/api/v0/work/*", "GET", "/api/v0/work/schedules/{id}", true},
+		{"/api/v0/work/*", "GET", "/api/v0/workloads", false},
+	}
+	for _, tt := range tests {
+		rule, err := ParseBypassRule(tt.rule)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got := rule.Matches(tt.method, tt.template); got != tt.want {
+			t.Errorf("%q.Matches(%s, %s) = %v, want %v", tt.rule, tt.method, tt.template, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// SetAccountChangeReview turns the account's change review mode on or off.
+// While it is on, authz mutations are staged as change requests.
+func (a *authorizerImpl) SetAccountChangeReview(ctx context.Context, accountID string, enabled bool) (*store.Account, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotEnabled, accountID)
+	}
+
+	if err := a.accountStore.SetChangeReview(ctx, accountID, enabled); err != nil {
+		return nil, err
+	}
+	account.ChangeReview = enabled
+	return account, nil
+}
+
+// ChangeReviewEnabled reports whether the account stages its authz mutations
+func (a *authorizerImpl) ChangeReviewEnabled(ctx context.Context, accountID string) (bool, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get account: %w", err)
+	}
+	return account != nil && account.ChangeReview, nil
+}
+
+// StageChangeRequest records a mutation for review. It can be approved until
+// ApprovalExpiry has passed.
+func (a *authorizerImpl) StageChangeRequest(ctx context.Context, cr *store.ChangeRequest) error {
+	return a.changeRequestStore.Create(ctx, cr, a.cfg.ApprovalExpiry)
+}
+
+// GetChangeRequest returns an unexpired change request
+func (a *authorizerImpl) GetChangeRequest(ctx context.Context, accountID, changeID string) (*store.ChangeRequest, error) {
+	return a.changeRequestStore.Get(ctx, accountID, changeID)
+}
+
+// ListChangeRequests returns an account's change requests, optionally only
+// those with one status
+func (a *authorizerImpl) ListChangeRequests(ctx context.Context, accountID, status string) ([]*store.ChangeRequest, error) {
+	return a.changeRequestStore.List(ctx, accountID, status)
+}
+
+// DecideChangeRequest approves or rejects a pending change request. The
+// requester may reject their own request but not approve it. Applying an
+// approved request is up to the caller, which records the outcome with
+// RecordChangeRequestResult. Decided requests are kept for DeletionRetention.
+func (a *authorizerImpl) DecideChangeRequest(ctx context.Context, accountID, changeID string, approve bool, decidedBy string) (*store.ChangeRequest, error) {
+	cr, err := a.changeRequestStore.Get(ctx, accountID, changeID)
+	if err != nil {
+		return nil, err
+	}
+	if cr.Status != store.ChangeRequestPending {
+		return nil, fmt.Errorf("%w: %s", store.ErrChangeRequestDecided, changeID)
+	}
+
+	status := store.ChangeRequestRejected
+	if approve {
+		if cr.RequestedBy == decidedBy {
+			return nil, ErrSelfApproval
+		}
+		status = store.ChangeRequestApproved
+	}
+	if err := a.changeRequestStore.Decide(ctx, accountID, changeID, status, decidedBy, a.cfg.DeletionRetention); err != nil {
+		return nil, err
+	}
+
+	cr.Status = status
+	cr.DecidedBy = decidedBy
+	return cr, nil
+}
+
+// RecordChangeRequestResult stores the HTTP status an approved change request
+// got when it was applied
+func (a *authorizerImpl) RecordChangeRequestResult(ctx context.Context, accountID, changeID string, resultStatus int) error {
+	return a.changeRequestStore.RecordResult(ctx, accountID, changeID, resultStatus)
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/tracecontext"
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
 )
 
 // NewAVPClient creates a new Amazon Verified Permissions client using the default AWS config
@@ -14,10 +17,14 @@ func NewAVPClient(ctx context.Context, region string) (AVPClient, error) {
 	if err != nil {
 		return nil, err
 	}
-	return verifiedpermissions.NewFromConfig(cfg), nil
+	return NewAVPClientFromConfig(cfg), nil
 }
 
-// NewAVPClientFromConfig creates a new AVP client from an existing AWS config
+// NewAVPClientFromConfig creates a new AVP client from an existing AWS config.
+// Calls are added to the request's upstream timings and carry its trace
+// context.
 func NewAVPClientFromConfig(cfg aws.Config) AVPClient {
-	return verifiedpermissions.NewFromConfig(cfg)
+	return verifiedpermissions.NewFromConfig(cfg, func(o *verifiedpermissions.Options) {
+		o.APIOptions = append(o.APIOptions, upstream.AWSMiddleware(upstream.AVP), tracecontext.AWSMiddleware())
+	})
 }
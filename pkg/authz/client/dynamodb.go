@@ -7,9 +7,14 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/tracecontext"
 )
 
-// NewDynamoDBClient creates a new DynamoDB client using the default AWS config
+// NewDynamoDBClient creates a new DynamoDB client using the default AWS config.
+// The client records per-table operation metrics (see NewInstrumentedDynamoDBClient)
+// and sends the request's trace context.
 // If endpoint is provided, it overrides the default AWS endpoint (for local development)
 func NewDynamoDBClient(ctx context.Context, region, endpoint string) (DynamoDBClient, error) {
 	// FedRAMP SC-13 / IA-7: enable FIPS 140-3 validated endpoints.
@@ -24,7 +29,7 @@ func NewDynamoDBClient(ctx context.Context, region, endpoint string) (DynamoDBCl
 		return nil, err
 	}
 
-	var opts []func(*dynamodb.Options)
+	opts := []func(*dynamodb.Options){withTraceContext}
 	if endpoint != "" {
 		// For local DynamoDB, use dummy credentials and custom endpoint.
 		// The local endpoint override disables FIPS routing for development only.
@@ -34,10 +39,38 @@ func NewDynamoDBClient(ctx context.Context, region, endpoint string) (DynamoDBCl
 		})
 	}
 
-	return dynamodb.NewFromConfig(cfg, opts...), nil
+	return NewInstrumentedDynamoDBClient(dynamodb.NewFromConfig(cfg, opts...)), nil
+}
+
+// NewDynamoDBStreamsClient creates a new DynamoDB Streams client using the
+// default AWS config, with the same FIPS and local endpoint handling as
+// NewDynamoDBClient
+func NewDynamoDBStreamsClient(ctx context.Context, region, endpoint string) (DynamoDBStreamsClient, error) {
+	cfg, err := config.LoadDefaultConfig(ctx,
+		config.WithRegion(region),
+		config.WithUseFIPSEndpoint(aws.FIPSEndpointStateEnabled),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []func(*dynamodbstreams.Options)
+	if endpoint != "" {
+		opts = append(opts, func(o *dynamodbstreams.Options) {
+			o.BaseEndpoint = aws.String(endpoint)
+			o.Credentials = credentials.NewStaticCredentialsProvider("dummy", "dummy", "")
+		})
+	}
+
+	return dynamodbstreams.NewFromConfig(cfg, opts...), nil
 }
 
 // NewDynamoDBClientFromConfig creates a new DynamoDB client from an existing AWS config
 func NewDynamoDBClientFromConfig(cfg aws.Config) DynamoDBClient {
-	return dynamodb.NewFromConfig(cfg)
+	return NewInstrumentedDynamoDBClient(dynamodb.NewFromConfig(cfg, withTraceContext))
+}
+
+// withTraceContext sends the trace context of each operation's request
+func withTraceContext(o *dynamodb.Options) {
+	o.APIOptions = append(o.APIOptions, tracecontext.AWSMiddleware())
 }
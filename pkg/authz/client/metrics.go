@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
+)
+
+var (
+	dynamoOperationDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rosa_dynamodb_operation_duration_seconds",
+		Help:    "Latency of DynamoDB store operations by table and operation.",
+		Buckets: []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"table", "operation"})
+
+	dynamoOperationErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_dynamodb_operation_errors_total",
+		Help: "DynamoDB store operations that returned an error, including throttling.",
+	}, []string{"table", "operation"})
+
+	dynamoOperationThrottles = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_dynamodb_operation_throttled_total",
+		Help: "DynamoDB store operations rejected for exceeding provisioned or account throughput.",
+	}, []string{"table", "operation"})
+)
+
+// instrumentedDynamoDBClient records Prometheus metrics for every call to the
+// wrapped client
+type instrumentedDynamoDBClient struct {
+	inner DynamoDBClient
+}
+
+// NewInstrumentedDynamoDBClient wraps a DynamoDBClient so each operation is
+// recorded in the rosa_dynamodb_operation_* metrics, labelled by table and
+// operation
+func NewInstrumentedDynamoDBClient(inner DynamoDBClient) DynamoDBClient {
+	return &instrumentedDynamoDBClient{inner: inner}
+}
+
+func (c *instrumentedDynamoDBClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	start := time.Now()
+	out, err := c.inner.GetItem(ctx, params, optFns...)
+	observeDynamoOperation(ctx, params.TableName, "GetItem", start, err)
+	return out, err
+}
+
+func (c *instrumentedDynamoDBClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	start := time.Now()
+	out, err := c.inner.PutItem(ctx, params, optFns...)
+	observeDynamoOperation(ctx, params.TableName, "PutItem", start, err)
+	return out, err
+}
+
+func (c *instrumentedDynamoDBClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	start := time.Now()
+	out, err := c.inner.DeleteItem(ctx, params, optFns...)
+	observeDynamoOperation(ctx, params.TableName, "DeleteItem", start, err)
+	return out, err
+}
+
+func (c *instrumentedDynamoDBClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	start := time.Now()
+	out, err := c.inner.Query(ctx, params, optFns...)
+	observeDynamoOperation(ctx, params.TableName, "Query", start, err)
+	return out, err
+}
+
+func (c *instrumentedDynamoDBClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	start := time.Now()
+	out, err := c.inner.Scan(ctx, params, optFns...)
+	observeDynamoOperation(ctx, params.TableName, "Scan", start, err)
+	return out, err
+}
+
+func (c *instrumentedDynamoDBClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	start := time.Now()
+	out, err := c.inner.UpdateItem(ctx, params, optFns...)
+	observeDynamoOperation(ctx, params.TableName, "UpdateItem", start, err)
+	return out, err
+}
+
+func (c *instrumentedDynamoDBClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	start := time.Now()
+	out, err := c.inner.BatchWriteItem(ctx, params, optFns...)
+	// Stores batch within a single table
+	var table *string
+	for name := range params.RequestItems {
+		table = aws.String(name)
+	}
+	observeDynamoOperation(ctx, table, "BatchWriteItem", start, err)
+	return out, err
+}
+
+// observeDynamoOperation records the latency and outcome of one operation,
+// and adds the latency to the request's upstream timings
+func observeDynamoOperation(ctx context.Context, tableName *string, operation string, start time.Time, err error) {
+	table := aws.ToString(tableName)
+	elapsed := time.Since(start)
+	dynamoOperationDuration.WithLabelValues(table, operation).Observe(elapsed.Seconds())
+	upstream.Observe(ctx, upstream.DynamoDB, elapsed)
+	if err == nil {
+		return
+	}
+
+	dynamoOperationErrors.WithLabelValues(table, operation).Inc()
+	if isThrottlingError(err) {
+		dynamoOperationThrottles.WithLabelValues(table, operation).Inc()
+	}
+}
+
+// isThrottlingError reports whether err is one of the DynamoDB throughput errors
+func isThrottlingError(err error) bool {
+	var provisioned *types.ProvisionedThroughputExceededException
+	var requestLimit *types.RequestLimitExceeded
+	var throttling *types.ThrottlingException
+	return errors.As(err, &provisioned) || errors.As(err, &requestLimit) || errors.As(err, &throttling)
+}
+
+func (c *instrumentedDynamoDBClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	start := time.Now()
+	out, err := c.inner.TransactWriteItems(ctx, params, optFns...)
+	// Transactions may span tables; they are observed under the table of
+	// their first item
+	var table *string
+	if len(params.TransactItems) > 0 {
+		item := params.TransactItems[0]
+		switch {
+		case item.Update != nil:
+			table = item.Update.TableName
+		case item.Put != nil:
+			table = item.Put.TableName
+		case item.Delete != nil:
+			table = item.Delete.TableName
+		case item.ConditionCheck != nil:
+			table = item.ConditionCheck.TableName
+		}
+	}
+	observeDynamoOperation(ctx, table, "TransactWriteItems", start, err)
+	return out, err
+}
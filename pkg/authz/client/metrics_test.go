@@ -0,0 +1,56 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type stubDynamoDBClient struct {
+	DynamoDBClient
+	err error
+}
+
+func (s *stubDynamoDBClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{}, s.err
+}
+
+func (s *stubDynamoDBClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{}, s.err
+}
+
+func TestInstrumentedDynamoDBClient(t *testing.T) {
+	ctx := context.Background()
+
+	ok := NewInstrumentedDynamoDBClient(&stubDynamoDBClient{})
+	if _, err := ok.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("metrics-accounts")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failing := NewInstrumentedDynamoDBClient(&stubDynamoDBClient{err: errors.New("boom")})
+	_, _ = failing.GetItem(ctx, &dynamodb.GetItemInput{TableName: aws.String("metrics-accounts")})
+
+	throttled := NewInstrumentedDynamoDBClient(&stubDynamoDBClient{err: &types.ProvisionedThroughputExceededException{}})
+	_, _ = throttled.Query(ctx, &dynamodb.QueryInput{TableName: aws.String("metrics-accounts")})
+
+	if got := testutil.CollectAndCount(dynamoOperationDuration, "rosa_dynamodb_operation_duration_seconds"); got < 2 {
+		t.Errorf("expected latency series for GetItem and Query, got %d", got)
+	}
+	if got := testutil.ToFloat64(dynamoOperationErrors.WithLabelValues("metrics-accounts", "GetItem")); got != 1 {
+		t.Errorf("expected 1 GetItem error, got %v", got)
+	}
+	if got := testutil.ToFloat64(dynamoOperationErrors.WithLabelValues("metrics-accounts", "Query")); got != 1 {
+		t.Errorf("expected 1 Query error, got %v", got)
+	}
+	if got := testutil.ToFloat64(dynamoOperationThrottles.WithLabelValues("metrics-accounts", "GetItem")); got != 0 {
+		t.Errorf("expected no GetItem throttles, got %v", got)
+	}
+	if got := testutil.ToFloat64(dynamoOperationThrottles.WithLabelValues("metrics-accounts", "Query")); got != 1 {
+		t.Errorf("expected 1 Query throttle, got %v", got)
+	}
+}
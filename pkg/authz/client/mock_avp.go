@@ -16,6 +16,8 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
 	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
 	"github.com/google/uuid"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
 )
 
 // mockTemplate holds a Cedar policy template in memory.
@@ -103,7 +105,7 @@ func (m *MockAVPClient) syncPolicies(ctx context.Context, policyStoreID string)
 		stmts := splitCedarStatements(adaptForCedarAgent(p.cedarText))
 		for i, stmt := range stmts {
 			policyList = append(policyList, map[string]string{
-				"id":      fmt.Sprintf("%s-%d", id, i),
+				"id":      agentPolicyID(id, i),
 				"content": stmt,
 			})
 		}
@@ -140,6 +142,20 @@ func (m *MockAVPClient) syncPolicies(ctx context.Context, policyStoreID string)
 	return nil
 }
 
+// agentPolicyID is the cedar-agent ID of statement i of policy id
+func agentPolicyID(id string, i int) string {
+	return fmt.Sprintf("%s-%d", id, i)
+}
+
+// policyIDOf returns the policy a cedar-agent ID made by agentPolicyID
+// belongs to
+func policyIDOf(agentID string) string {
+	if i := strings.LastIndex(agentID, "-"); i >= 0 {
+		return agentID[:i]
+	}
+	return agentID
+}
+
 // resolvePrincipal replaces ?principal in Cedar template text with the concrete principal entity.
 // It uses "principal in" so that Cedar traverses the entity hierarchy — this allows
 // group-based policies to match any principal that is a member (descendant) of the group.
@@ -604,8 +620,23 @@ func (m *MockAVPClient) IsAuthorized(ctx context.Context, params *verifiedpermis
 		decision = avptypes.DecisionAllow
 	}
 
+	// Like AVP, report the policies that determined the decision, so a deny
+	// caused by a forbid can be told apart from an implicit deny. Reasons
+	// name statements, which are reported once per policy they belong to.
+	determining := make([]avptypes.DeterminingPolicyItem, 0, len(cedarResp.Diagnostics.Reason))
+	seen := make(map[string]bool, len(cedarResp.Diagnostics.Reason))
+	for _, agentID := range cedarResp.Diagnostics.Reason {
+		policyID := policyIDOf(agentID)
+		if seen[policyID] {
+			continue
+		}
+		seen[policyID] = true
+		determining = append(determining, avptypes.DeterminingPolicyItem{PolicyId: aws.String(policyID)})
+	}
+
 	return &verifiedpermissions.IsAuthorizedOutput{
-		Decision: decision,
+		Decision:            decision,
+		DeterminingPolicies: determining,
 	}, nil
 }
 
@@ -619,6 +650,11 @@ func (m *MockAVPClient) PutSchema(ctx context.Context, params *verifiedpermissio
 	}, nil
 }
 
+// isEntityType reports whether entityType is name, unqualified or in any namespace
+func isEntityType(entityType, name string) bool {
+	return entityType == name || strings.HasSuffix(entityType, "::"+name)
+}
+
 // buildCedarAgentRequest converts an AVP IsAuthorizedInput to cedar-agent format.
 func (m *MockAVPClient) buildCedarAgentRequest(params *verifiedpermissions.IsAuthorizedInput) map[string]any {
 	req := make(map[string]any)
@@ -635,10 +671,9 @@ func (m *MockAVPClient) buildCedarAgentRequest(params *verifiedpermissions.IsAut
 
 	// Action: ROSA::Action::"action-name"
 	if params.Action != nil {
-		actionID := aws.ToString(params.Action.ActionId)
-		// Strip "rosa:" prefix if present to match Cedar policy format
-		actionID = strings.TrimPrefix(actionID, "rosa:")
-		req["action"] = fmt.Sprintf("ROSA::Action::\"%s\"", actionID)
+		actionType := aws.ToString(params.Action.ActionType)
+		actionID := schema.CanonicalAction(aws.ToString(params.Action.ActionId))
+		req["action"] = fmt.Sprintf("%s::\"%s\"", actionType, actionID)
 	}
 
 	// Resource: ROSA::Resource::"resource-id"
@@ -673,7 +708,7 @@ func (m *MockAVPClient) buildCedarAgentRequest(params *verifiedpermissions.IsAut
 				uid := fmt.Sprintf("%s::\"%s\"", entityType, entityID)
 
 				// Track group UIDs for principal parents
-				if entityType == "ROSA::Group" || entityType == "Group" {
+				if isEntityType(entityType, "Group") {
 					groupUIDs = append(groupUIDs, uid)
 					entities = append(entities, map[string]any{
 						"uid":     uid,
@@ -682,19 +717,26 @@ func (m *MockAVPClient) buildCedarAgentRequest(params *verifiedpermissions.IsAut
 					})
 				}
 
-				// Handle resource entities with attributes (e.g., tags)
-				if entity.Attributes != nil && (entityType == "ROSA::Resource" || entityType == "Resource") {
+				// Handle resource entities with attributes (e.g., tags) and
+				// the resources they belong to
+				if isEntityType(entityType, "Resource") {
 					attrs := make(map[string]any)
 					for k, v := range entity.Attributes {
 						attrs[k] = convertAttributeValue(v)
 					}
 					attrs["arn"] = entityID
+					parents := []string{}
+					for _, parent := range entity.Parents {
+						parents = append(parents, fmt.Sprintf("%s::\"%s\"", aws.ToString(parent.EntityType), aws.ToString(parent.EntityId)))
+					}
 					entities = append(entities, map[string]any{
 						"uid":     uid,
 						"attrs":   attrs,
-						"parents": []string{},
+						"parents": parents,
 					})
-					resourceAdded = true
+					if params.Resource != nil && entityID == aws.ToString(params.Resource.EntityId) {
+						resourceAdded = true
+					}
 				}
 			}
 
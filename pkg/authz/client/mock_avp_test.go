@@ -0,0 +1,72 @@
+package client
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+var cedarSeeds = []string{
+	"permit(principal, action, resource);",
+	"permit(\n  ?principal,\n  action == ROSA::Action::\"DescribeCluster\",\n  resource\n);\n\npermit(\n  ?principal,\n  action == ROSA::Action::\"ListClusters\",\n  resource\n);\n",
+	"forbid(principal, action, resource) when { resource like \"arn:aws:rosa:*\" };",
+	"// permit everything\npermit(principal, action, resource)\nwhen { context.note == \"\n  forbid\" };",
+	"permitted\nforbidden\n",
+	"resource resource like like ",
+	"\r\n\t permit \n",
+}
+
+// FuzzSplitCedarStatements checks that splitting only cuts policy text
+// before permit and forbid lines and never drops or adds anything but
+// whitespace
+func FuzzSplitCedarStatements(f *testing.F) {
+	for _, seed := range cedarSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, cedarText string) {
+		statements := splitCedarStatements(cedarText)
+		for i, stmt := range statements {
+			if stmt == "" || stmt != strings.TrimSpace(stmt) {
+				t.Fatalf("statement %d of %q is empty or untrimmed: %q", i, cedarText, stmt)
+			}
+			if i > 0 && !strings.HasPrefix(stmt, "permit") && !strings.HasPrefix(stmt, "forbid") {
+				t.Fatalf("statement %d of %q does not start a policy: %q", i, cedarText, stmt)
+			}
+		}
+		if got, want := stripSpace(strings.Join(statements, "")), stripSpace(cedarText); got != want {
+			t.Fatalf("splitting %q changed its content: %q, want %q", cedarText, got, want)
+		}
+	})
+}
+
+// FuzzAdaptForCedarAgent checks that the rewrite removes every implicit
+// entity ID comparison, is stable, and changes nothing else
+func FuzzAdaptForCedarAgent(f *testing.F) {
+	for _, seed := range cedarSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, cedarText string) {
+		adapted := adaptForCedarAgent(cedarText)
+		if strings.Contains(adapted, "resource like ") {
+			t.Fatalf("adapting %q left an entity ID comparison: %q", cedarText, adapted)
+		}
+		if again := adaptForCedarAgent(adapted); again != adapted {
+			t.Fatalf("adapting is not idempotent: %q -> %q -> %q", cedarText, adapted, again)
+		}
+		undo := func(s string) string { return strings.ReplaceAll(s, "resource.arn like ", "resource like ") }
+		if undo(adapted) != undo(cedarText) {
+			t.Fatalf("adapting %q changed more than the comparison: %q", cedarText, adapted)
+		}
+	})
+}
+
+func stripSpace(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
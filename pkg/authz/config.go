@@ -1,5 +1,20 @@
 package authz
 
+import (
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Degraded modes for authz-protected routes while authz is unavailable
+const (
+	// DegradedDenyAll rejects every authz-protected request
+	DegradedDenyAll = "deny-all"
+	// DegradedReadOnly serves reads and rejects writes
+	DegradedReadOnly = "read-only"
+)
+
 // Config holds the configuration for the authorization service
 type Config struct {
 	// AWSRegion is the AWS region for AVP and DynamoDB
@@ -10,6 +25,33 @@ type Config struct {
 	AdminsTableName   string
 	GroupsTableName   string
 	MembersTableName  string
+	// DelegationsTableName holds cross-account delegations (see store.Delegation)
+	DelegationsTableName string
+	// APIKeysTableName holds the API keys of machine clients (see
+	// store.APIKey)
+	APIKeysTableName string
+	// OrganizationsTableName holds organizations (see store.Organization)
+	OrganizationsTableName string
+	// AttachmentsTableName holds attachment names and descriptions (see
+	// store.AttachmentMeta)
+	AttachmentsTableName string
+	// DeletionsTableName holds tombstones of deleted policies, groups and
+	// attachments (see store.Tombstone)
+	DeletionsTableName string
+	// PendingChangesTableName holds changes waiting for approval (see
+	// store.PendingChange)
+	PendingChangesTableName string
+	// ChangeRequestsTableName holds mutations staged by accounts in change
+	// review mode (see store.ChangeRequest)
+	ChangeRequestsTableName string
+	// DecisionCountsTableName holds hourly authorization decision counts
+	// (see store.DecisionCount)
+	DecisionCountsTableName string
+	// PolicyTagsTableName holds policy tags (see store.PolicyTags)
+	PolicyTagsTableName string
+	// DecisionAuditTableName holds audited authorization decisions (see
+	// store.DecisionAuditEntry)
+	DecisionAuditTableName string
 
 	// Enabled determines if Cedar/AVP authorization is enabled
 	// When false, falls back to legacy allowlist behavior
@@ -22,16 +64,164 @@ type Config struct {
 	// CedarAgentEndpoint is the URL for cedar-agent (local testing only)
 	// When set, MockAVPClient is used instead of real AVP
 	CedarAgentEndpoint string
+
+	// DegradedStart lets the server start when DynamoDB is unreachable at
+	// boot. Authz is marked unhealthy, readiness fails, and the DynamoDB
+	// check is retried every InitRetryInterval until it passes.
+	DegradedStart     bool
+	DegradedMode      string
+	InitRetryInterval time.Duration
+
+	// CrossAccountResourceAccounts lists accounts whose resource ARNs any
+	// caller may name in an authorization request. Resources owned by other
+	// accounts are rejected with ErrResourceAccountMismatch.
+	CrossAccountResourceAccounts []string
+
+	// MaxGroupMembers caps the members of a group, as every group of a
+	// caller adds to the cost of its authorization checks; 0 is no limit.
+	// MemberPartnerAccounts lists the accounts other than a group's own
+	// whose principals may be added to it.
+	MaxGroupMembers       int
+	MemberPartnerAccounts []string
+
+	// PrincipalPatterns lets policies be attached to principal ARN patterns,
+	// such as arn:aws:iam::123456789012:role/dev-*. Each pattern is a pattern
+	// group whose members are the principals seen in authorization checks
+	// that match it, added every PatternMatchInterval.
+	PrincipalPatterns    bool
+	PatternMatchInterval time.Duration
+
+	// GuardrailPolicyStoreID is the AVP policy store holding platform
+	// guardrails, evaluated before tenant policies for every request,
+	// including those of privileged accounts. Empty disables guardrails.
+	GuardrailPolicyStoreID string
+
+	// WaitForVisibility makes policy and attachment mutations poll AVP until
+	// the change is visible, for up to VisibilityTimeout, so an authorization
+	// check made right after the mutation reflects it. Callers can also ask
+	// per request with WithWaitForVisibility.
+	WaitForVisibility      bool
+	VisibilityTimeout      time.Duration
+	VisibilityPollInterval time.Duration
+
+	// CedarNamespace is the Cedar namespace of the entity and action types
+	// sent to AVP and of the schema put in new policy stores. Policies must
+	// reference types in this namespace.
+	CedarNamespace string
+
+	// DeletionRetention is how long tombstones of deleted policies, groups
+	// and attachments are kept
+	DeletionRetention time.Duration
+
+	// ApprovalRequired lists the operations (see ApprovalOperations) that a
+	// second admin must approve. They are queued as pending changes, which
+	// expire after ApprovalExpiry, as do unapproved change requests.
+	ApprovalRequired []string
+	ApprovalExpiry   time.Duration
+
+	// APIKeyCacheTTL is how long each replica caches an API key it resolved;
+	// zero reads the API keys table on every request made with a key. Cached
+	// keys revoked or given an expiry on any replica are evicted within
+	// APIKeyRevocationRefresh.
+	APIKeyCacheTTL          time.Duration
+	APIKeyRevocationRefresh time.Duration
+
+	// OnboardingWorkers onboard the accounts enabled asynchronously, taking
+	// them from a queue of up to OnboardingQueueSize accounts per replica
+	// and making at most OnboardingRate AVP calls per second between them;
+	// zero workers turns asynchronous enabling off
+	OnboardingWorkers   int
+	OnboardingQueueSize int
+	OnboardingRate      float64
+
+	// ShadowMode keeps enforcing the legacy allowed-accounts allow-list on
+	// the resource routes while the Cedar decision of each request is only
+	// evaluated, logged and counted, to measure how far the two diverge
+	// before enforcement is switched over
+	ShadowMode bool
+
+	// Bypass lists route bypass rules (see BypassRule) for platform
+	// automation: requests of privileged accounts on those routes skip
+	// authorization but not guardrails, and are audited; requests of other
+	// accounts are refused
+	Bypass []string
+
+	// DecisionAnalytics counts the policy-evaluated authorization decisions
+	// of each account per action and hour, flushing the counts to the
+	// decision counts table every DecisionFlushInterval and keeping them for
+	// DecisionRetention
+	DecisionAnalytics     bool
+	DecisionFlushInterval time.Duration
+	DecisionRetention     time.Duration
+
+	// DecisionAudit writes each authorization decision of an account to the
+	// decision audit table every DecisionFlushInterval, keeping it for
+	// DecisionAuditRetention. AuditQueryRateLimit caps the audit queries
+	// each account makes per minute on each replica; zero leaves them
+	// unlimited.
+	DecisionAudit          bool
+	DecisionAuditRetention time.Duration
+	AuditQueryRateLimit    int
+
+	// AuditOpenSearchEndpoint also indexes audited decisions into an
+	// OpenSearch or Elasticsearch cluster, in indices named
+	// AuditOpenSearchIndex-<date> that start anew every
+	// AuditOpenSearchRollover (daily, monthly or none). With
+	// AuditOpenSearchService (es or aoss) set, requests are signed with
+	// SigV4 for AWSRegion. An empty endpoint indexes nothing.
+	AuditOpenSearchEndpoint string
+	AuditOpenSearchIndex    string
+	AuditOpenSearchRollover string
+	AuditOpenSearchService  string
+
+	// GroupTagsInContext adds the tags of the caller's groups to the Cedar
+	// context of authorization requests as groupTags, a set of "key=value"
+	// strings. It costs a group lookup per group of the caller.
+	GroupTagsInContext bool
+
+	// Clock is what the service and its stores stamp times and judge
+	// expiries with; nil means time.Now
+	Clock clock.Clock
 }
 
 // DefaultConfig returns the default authorization configuration
 func DefaultConfig() *Config {
 	return &Config{
-		AWSRegion:         "us-east-1",
-		AccountsTableName: "rosa-authz-accounts",
-		AdminsTableName:   "rosa-authz-admins",
-		GroupsTableName:   "rosa-authz-groups",
-		MembersTableName:  "rosa-authz-group-members",
-		Enabled:           true,
+		AWSRegion:               "us-east-1",
+		AccountsTableName:       "rosa-authz-accounts",
+		AdminsTableName:         "rosa-authz-admins",
+		GroupsTableName:         "rosa-authz-groups",
+		MembersTableName:        "rosa-authz-group-members",
+		DelegationsTableName:    "rosa-authz-delegations",
+		APIKeysTableName:        "rosa-authz-api-keys",
+		OrganizationsTableName:  "rosa-authz-organizations",
+		AttachmentsTableName:    "rosa-authz-attachments",
+		DeletionsTableName:      "rosa-authz-deletions",
+		PendingChangesTableName: "rosa-authz-pending-changes",
+		ChangeRequestsTableName: "rosa-authz-change-requests",
+		DecisionCountsTableName: "rosa-authz-decision-counts",
+		PolicyTagsTableName:     "rosa-authz-policy-tags",
+		DecisionAuditTableName:  "rosa-authz-decision-audit",
+		Enabled:                 true,
+		DegradedMode:            DegradedDenyAll,
+		InitRetryInterval:       10 * time.Second,
+		MaxGroupMembers:         1000,
+		VisibilityTimeout:       5 * time.Second,
+		VisibilityPollInterval:  250 * time.Millisecond,
+		DeletionRetention:       90 * 24 * time.Hour,
+		ApprovalExpiry:          24 * time.Hour,
+		APIKeyCacheTTL:          5 * time.Minute,
+		APIKeyRevocationRefresh: 5 * time.Second,
+		OnboardingWorkers:       4,
+		OnboardingQueueSize:     10000,
+		OnboardingRate:          5,
+		DecisionFlushInterval:   time.Minute,
+		PatternMatchInterval:    30 * time.Second,
+		DecisionRetention:       90 * 24 * time.Hour,
+		DecisionAuditRetention:  90 * 24 * time.Hour,
+		AuditQueryRateLimit:     30,
+		AuditOpenSearchIndex:    "rosa-authz-decisions",
+		AuditOpenSearchRollover: "daily",
+		CedarNamespace:          schema.DefaultNamespace,
 	}
 }
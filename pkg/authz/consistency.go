@@ -0,0 +1,127 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+)
+
+// ErrNotYetVisible is returned alongside a successful policy mutation when
+// the change did not become visible in AVP within the visibility timeout.
+// The change is saved; authorization checks may not reflect it yet.
+var ErrNotYetVisible = errors.New("change is not yet visible to authorization checks")
+
+type waitForVisibilityKey struct{}
+
+// WithWaitForVisibility makes the policy mutations made with ctx wait until
+// AVP reflects the change, as if Config.WaitForVisibility were set
+func WithWaitForVisibility(ctx context.Context) context.Context {
+	return context.WithValue(ctx, waitForVisibilityKey{}, true)
+}
+
+// waitsForVisibility reports whether mutations made with ctx must wait
+func (a *authorizerImpl) waitsForVisibility(ctx context.Context) bool {
+	wait, _ := ctx.Value(waitForVisibilityKey{}).(bool)
+	return wait || a.cfg.WaitForVisibility
+}
+
+// awaitVisibility polls visible until it reports true, when ctx asks for
+// read-after-write consistency. AVP is eventually consistent, so a check made
+// right after a write may not see it; polling the read APIs until they do
+// gives callers a predictable point after which the change is in effect.
+func (a *authorizerImpl) awaitVisibility(ctx context.Context, change string, visible func(ctx context.Context) (bool, error)) error {
+	if !a.waitsForVisibility(ctx) {
+		return nil
+	}
+
+	timeout := a.cfg.VisibilityTimeout
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	interval := a.cfg.VisibilityPollInterval
+	if interval <= 0 {
+		interval = 250 * time.Millisecond
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	start := time.Now()
+	for {
+		ok, err := visible(ctx)
+		if err == nil && ok {
+			a.logger.Debug("change visible", "change", change, "wait", time.Since(start))
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			a.logger.Debug("visibility check failed, retrying", "change", change, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			a.logger.Warn("change not visible before timeout", "change", change, "timeout", timeout)
+			return fmt.Errorf("%w: %s", ErrNotYetVisible, change)
+		case <-ticker.C:
+		}
+	}
+}
+
+// isNotFound reports whether err is an AVP resource-not-found error
+func isNotFound(err error) bool {
+	var notFound *avptypes.ResourceNotFoundException
+	return errors.As(err, &notFound)
+}
+
+// policyTemplateVisible checks that a policy template exists with the given
+// statement; an empty statement matches any
+func (a *authorizerImpl) policyTemplateVisible(policyStoreID, policyID, statement string) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		resp, err := a.avpClient.GetPolicyTemplate(ctx, &verifiedpermissions.GetPolicyTemplateInput{
+			PolicyStoreId:    aws.String(policyStoreID),
+			PolicyTemplateId: aws.String(policyID),
+		})
+		if err != nil {
+			return false, err
+		}
+		return statement == "" || aws.ToString(resp.Statement) == statement, nil
+	}
+}
+
+// policyTemplateGone checks that a policy template no longer exists
+func (a *authorizerImpl) policyTemplateGone(policyStoreID, policyID string) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		_, err := a.avpClient.GetPolicyTemplate(ctx, &verifiedpermissions.GetPolicyTemplateInput{
+			PolicyStoreId:    aws.String(policyStoreID),
+			PolicyTemplateId: aws.String(policyID),
+		})
+		if isNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+}
+
+// policyVisible checks that a policy exists (exists true) or no longer
+// exists (exists false)
+func (a *authorizerImpl) policyVisible(policyStoreID, policyID string, exists bool) func(ctx context.Context) (bool, error) {
+	return func(ctx context.Context) (bool, error) {
+		_, err := a.avpClient.GetPolicy(ctx, &verifiedpermissions.GetPolicyInput{
+			PolicyStoreId: aws.String(policyStoreID),
+			PolicyId:      aws.String(policyID),
+		})
+		if isNotFound(err) {
+			return !exists, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return exists, nil
+	}
+}
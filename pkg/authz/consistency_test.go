@@ -0,0 +1,92 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestAwaitVisibility(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	errTransient := errors.New("throttled")
+
+	tests := []struct {
+		name      string
+		cfg       Config
+		wait      bool
+		visibleAt int
+		err       error
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:      "no wait skips polling",
+			visibleAt: 100,
+			wantCalls: 0,
+		},
+		{
+			name:      "visible immediately",
+			wait:      true,
+			visibleAt: 1,
+			wantCalls: 1,
+		},
+		{
+			name:      "visible after polling",
+			wait:      true,
+			visibleAt: 3,
+			wantCalls: 3,
+		},
+		{
+			name:      "config enables waiting",
+			cfg:       Config{WaitForVisibility: true},
+			visibleAt: 2,
+			wantCalls: 2,
+		},
+		{
+			name:      "transient errors are retried",
+			wait:      true,
+			visibleAt: 2,
+			err:       errTransient,
+			wantCalls: 2,
+		},
+		{
+			name:      "timeout returns ErrNotYetVisible",
+			wait:      true,
+			visibleAt: 1000,
+			wantErr:   ErrNotYetVisible,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.VisibilityTimeout = 50 * time.Millisecond
+			cfg.VisibilityPollInterval = time.Millisecond
+			a := &authorizerImpl{cfg: &cfg, logger: logger}
+
+			ctx := context.Background()
+			if tt.wait {
+				ctx = WithWaitForVisibility(ctx)
+			}
+
+			calls := 0
+			err := a.awaitVisibility(ctx, "policy test", func(ctx context.Context) (bool, error) {
+				calls++
+				if calls < tt.visibleAt && tt.err != nil {
+					return false, tt.err
+				}
+				return calls >= tt.visibleAt, nil
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && calls != tt.wantCalls {
+				t.Errorf("expected %d visibility checks, got %d", tt.wantCalls, calls)
+			}
+		})
+	}
+}
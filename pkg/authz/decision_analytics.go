@@ -0,0 +1,239 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// decisionFlushTimeout bounds the final flush when the recorder stops
+const decisionFlushTimeout = 5 * time.Second
+
+// decisionKey identifies the hourly count a decision is added to
+type decisionKey struct {
+	accountID string
+	action    string
+	hour      time.Time
+}
+
+// decisionTally is a count of allow and deny decisions
+type decisionTally struct {
+	allow, deny int64
+}
+
+// policyKey identifies a policy of an account
+type policyKey struct {
+	accountID string
+	policyID  string
+}
+
+// DecisionAnalytics rolls authorization decisions up into hourly
+// per-account, per-action allow and deny counts, and records when each
+// account policy last determined a decision and each caller last made a
+// request. Decisions are counted in
+// memory and added to the decision counts table every flush interval, so
+// authorization checks never wait on the table. Every replica flushes its
+// own counts; the table adds them up.
+type DecisionAnalytics struct {
+	mu        sync.Mutex
+	pending   map[decisionKey]decisionTally
+	matched   map[policyKey]time.Time
+	seen      map[principalKey]time.Time
+	store     *store.DecisionCountStore
+	interval  time.Duration
+	retention time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewDecisionAnalytics creates a new DecisionAnalytics that flushes every
+// interval and keeps counts for retention
+func NewDecisionAnalytics(counts *store.DecisionCountStore, interval, retention time.Duration, logger *slog.Logger) *DecisionAnalytics {
+	return &DecisionAnalytics{
+		pending:   make(map[decisionKey]decisionTally),
+		matched:   make(map[policyKey]time.Time),
+		seen:      make(map[principalKey]time.Time),
+		store:     counts,
+		interval:  interval,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Record counts one decision of callerARN and the account policies that
+// determined it. A nil DecisionAnalytics records nothing.
+func (d *DecisionAnalytics) Record(accountID, callerARN, action string, allowed bool, policyIDs []string) {
+	if d == nil {
+		return
+	}
+	now := d.now().UTC()
+	key := decisionKey{accountID: accountID, action: action, hour: now.Truncate(time.Hour)}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	tally := d.pending[key]
+	if allowed {
+		tally.allow++
+	} else {
+		tally.deny++
+	}
+	d.pending[key] = tally
+	for _, policyID := range policyIDs {
+		d.matched[policyKey{accountID: accountID, policyID: policyID}] = now
+	}
+	d.seen[principalKey{accountID: accountID, arn: callerARN}] = now
+}
+
+// Flush adds the decisions counted since the last flush to the table.
+// Counts that fail to be added are kept for the next flush.
+func (d *DecisionAnalytics) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	pending, matched, seen := d.pending, d.matched, d.seen
+	d.pending = make(map[decisionKey]decisionTally)
+	d.matched = make(map[policyKey]time.Time)
+	d.seen = make(map[principalKey]time.Time)
+	d.mu.Unlock()
+
+	failed := 0
+	var lastErr error
+	for key, tally := range pending {
+		if err := d.store.Add(ctx, key.accountID, key.hour, key.action, tally.allow, tally.deny, d.retention); err != nil {
+			failed++
+			lastErr = err
+			d.merge(key, tally)
+		}
+	}
+	for key, at := range matched {
+		if err := d.store.MarkMatched(ctx, key.accountID, key.policyID, at, d.retention); err != nil {
+			failed++
+			lastErr = err
+			d.mergeMatch(key, at)
+		}
+	}
+	for key, at := range seen {
+		if err := d.store.MarkSeen(ctx, key.accountID, key.arn, at, d.retention); err != nil {
+			failed++
+			lastErr = err
+			d.mergeSeen(key, at)
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to flush %d of %d decision counts, policy matches and principal sightings: %w", failed, len(pending)+len(matched)+len(seen), lastErr)
+	}
+	return nil
+}
+
+// merge adds tally back to the pending count for key
+func (d *DecisionAnalytics) merge(key decisionKey, tally decisionTally) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	pending := d.pending[key]
+	pending.allow += tally.allow
+	pending.deny += tally.deny
+	d.pending[key] = pending
+}
+
+// mergeMatch restores a policy match unless a later one was recorded since
+func (d *DecisionAnalytics) mergeMatch(key policyKey, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if at.After(d.matched[key]) {
+		d.matched[key] = at
+	}
+}
+
+// mergeSeen restores a principal sighting unless a later one was recorded
+// since
+func (d *DecisionAnalytics) mergeSeen(key principalKey, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if at.After(d.seen[key]) {
+		d.seen[key] = at
+	}
+}
+
+// Run flushes counts every interval until ctx is cancelled, then flushes
+// once more so the counts of a stopping replica are not lost
+func (d *DecisionAnalytics) Run(ctx context.Context) {
+	d.logger.Info("decision analytics started", "interval", d.interval, "retention", d.retention)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), decisionFlushTimeout)
+			if err := d.Flush(flushCtx); err != nil {
+				d.logger.Error("final decision count flush failed", "error", err)
+			}
+			cancel()
+			d.logger.Info("decision analytics stopped")
+			return
+		case <-ticker.C:
+			if err := d.Flush(ctx); err != nil {
+				d.logger.Error("decision count flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// ErrDecisionAnalyticsDisabled is returned when decision counts are listed
+// while decision analytics is disabled
+var ErrDecisionAnalyticsDisabled = errors.New("decision analytics is disabled")
+
+// DecisionAnalytics returns the authorizer's decision analytics, or nil when
+// it is disabled
+func (a *authorizerImpl) DecisionAnalytics() *DecisionAnalytics {
+	return a.analytics
+}
+
+// ListDecisionCounts returns an account's hourly decision counts for the
+// hours from from through to, oldest first
+func (a *authorizerImpl) ListDecisionCounts(ctx context.Context, accountID string, from, to time.Time) ([]*store.DecisionCount, error) {
+	if a.analytics == nil {
+		return nil, ErrDecisionAnalyticsDisabled
+	}
+	return a.analytics.store.List(ctx, accountID, from, to)
+}
+
+// ListPrincipals returns the principals seen in an account's decisions
+// at or after since, most recently seen first
+func (a *authorizerImpl) ListPrincipals(ctx context.Context, accountID string, since time.Time) ([]*store.PrincipalSighting, error) {
+	if a.analytics == nil {
+		return nil, ErrDecisionAnalyticsDisabled
+	}
+	sightings, err := a.analytics.store.ListPrincipals(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := since.UTC().Format(time.RFC3339)
+	principals := []*store.PrincipalSighting{}
+	for _, p := range sightings {
+		if p.LastSeenAt >= cutoff {
+			principals = append(principals, p)
+		}
+	}
+	sort.SliceStable(principals, func(i, j int) bool { return principals[i].LastSeenAt > principals[j].LastSeenAt })
+	return principals, nil
+}
+
+// determiningPolicies returns the IDs of the policies that determined an AVP
+// decision: the permits of an allow, or the forbids of a deny
+func determiningPolicies(out *verifiedpermissions.IsAuthorizedOutput) []string {
+	ids := make([]string, 0, len(out.DeterminingPolicies))
+	for _, p := range out.DeterminingPolicies {
+		ids = append(ids, aws.ToString(p.PolicyId))
+	}
+	return ids
+}
@@ -0,0 +1,139 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// decisionCountUpdates records the decision count updates it is sent and
+// fails them while err is set
+type decisionCountUpdates struct {
+	client.DynamoDBClient
+	updates []map[string]types.AttributeValue
+	err     error
+}
+
+func (c *decisionCountUpdates) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	update := map[string]types.AttributeValue{"bucketId": params.Key["bucketId"]}
+	for k, v := range params.ExpressionAttributeValues {
+		update[k] = v
+	}
+	c.updates = append(c.updates, update)
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func TestDecisionAnalytics_RecordAndFlush(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &decisionCountUpdates{err: errors.New("throttled")}
+	d := NewDecisionAnalytics(store.NewDecisionCountStore("decision-counts", c, logger), time.Minute, time.Hour, logger)
+	d.now = func() time.Time { return time.Date(2026, 10, 15, 13, 25, 0, 0, time.UTC) }
+
+	d.Record("123456789012", "arn:aws:iam::123456789012:role/dev", "rosa:CreateCluster", true, []string{"attachment-1"})
+	d.Record("123456789012", "arn:aws:iam::123456789012:role/dev", "rosa:CreateCluster", false, nil)
+	d.Record("123456789012", "arn:aws:iam::123456789012:role/dev", "rosa:CreateCluster", true, nil)
+
+	// A failed flush keeps the counts for the next one
+	if err := d.Flush(context.Background()); err == nil {
+		t.Fatal("expected the flush to fail")
+	}
+	d.Record("123456789012", "arn:aws:iam::123456789012:role/dev", "rosa:CreateCluster", true, nil)
+	c.err = nil
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+
+	if len(c.updates) != 3 {
+		t.Fatalf("expected a count update, a policy match and a principal sighting, got %d updates", len(c.updates))
+	}
+	var update, match, seen map[string]types.AttributeValue
+	for _, u := range c.updates {
+		switch {
+		case u[":pid"] != nil:
+			match = u
+		case u[":arn"] != nil:
+			seen = u
+		default:
+			update = u
+		}
+	}
+	if got := match["bucketId"].(*types.AttributeValueMemberS).Value; got != "policy#attachment-1" {
+		t.Errorf("unexpected policy match bucket %q", got)
+	}
+	if got := match[":at"].(*types.AttributeValueMemberS).Value; got != "2026-10-15T13:25:00Z" {
+		t.Errorf("unexpected match time %q", got)
+	}
+	if got := seen["bucketId"].(*types.AttributeValueMemberS).Value; got != "principal#arn:aws:iam::123456789012:role/dev" {
+		t.Errorf("unexpected principal sighting bucket %q", got)
+	}
+	if got := update["bucketId"].(*types.AttributeValueMemberS).Value; got != "2026-10-15T13Z#rosa:CreateCluster" {
+		t.Errorf("unexpected bucket %q", got)
+	}
+	if allow, deny := update[":allow"].(*types.AttributeValueMemberN).Value, update[":deny"].(*types.AttributeValueMemberN).Value; allow != "3" || deny != "1" {
+		t.Errorf("expected 3 allows and 1 deny, got %s and %s", allow, deny)
+	}
+
+	// Nothing new to flush
+	if err := d.Flush(context.Background()); err != nil || len(c.updates) != 3 {
+		t.Errorf("expected an empty flush to send nothing, got %d updates and %v", len(c.updates), err)
+	}
+}
+
+func TestDecisionAnalytics_Nil(t *testing.T) {
+	var d *DecisionAnalytics
+	d.Record("123456789012", "arn:aws:iam::123456789012:role/dev", "rosa:CreateCluster", true, nil)
+
+	a := &authorizerImpl{}
+	if _, err := a.ListDecisionCounts(context.Background(), "123456789012", time.Now(), time.Now()); !errors.Is(err, ErrDecisionAnalyticsDisabled) {
+		t.Errorf("expected ErrDecisionAnalyticsDisabled, got %v", err)
+	}
+	if _, err := a.ListPrincipals(context.Background(), "123456789012", time.Now()); !errors.Is(err, ErrDecisionAnalyticsDisabled) {
+		t.Errorf("expected ErrDecisionAnalyticsDisabled, got %v", err)
+	}
+}
+
+// principalSightings serves fixed principal sightings, by ARN like the table
+type principalSightings struct {
+	client.DynamoDBClient
+	items []map[string]types.AttributeValue
+}
+
+func (c *principalSightings) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{Items: c.items}, nil
+}
+
+func TestListPrincipals(t *testing.T) {
+	sighting := func(arn, at string) map[string]types.AttributeValue {
+		return map[string]types.AttributeValue{
+			"principalArn": &types.AttributeValueMemberS{Value: arn},
+			"lastSeenAt":   &types.AttributeValueMemberS{Value: at},
+		}
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &principalSightings{items: []map[string]types.AttributeValue{
+		sighting("arn:aws:iam::123456789012:role/a", "2026-10-15T12:00:00Z"),
+		sighting("arn:aws:iam::123456789012:role/b", "2026-10-15T13:00:00Z"),
+		sighting("arn:aws:iam::123456789012:role/c", "2026-10-01T00:00:00Z"),
+	}}
+	a := &authorizerImpl{analytics: NewDecisionAnalytics(store.NewDecisionCountStore("decision-counts", c, logger), time.Minute, time.Hour, logger)}
+
+	principals, err := a.ListPrincipals(context.Background(), "123456789012", time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("ListPrincipals: %v", err)
+	}
+	if len(principals) != 2 || principals[0].PrincipalARN != "arn:aws:iam::123456789012:role/b" || principals[1].PrincipalARN != "arn:aws:iam::123456789012:role/a" {
+		t.Errorf("expected b then a, got %+v", principals)
+	}
+}
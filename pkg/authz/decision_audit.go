@@ -0,0 +1,183 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// What made an audited decision, recorded in store.DecisionAuditEntry.Basis
+const (
+	BasisPolicy       = "policy"
+	BasisOrganization = "organization"
+	BasisAdmin        = "admin"
+)
+
+// maxPendingAuditEntries bounds the decisions held between flushes; while
+// it is reached, further decisions are dropped and counted
+const maxPendingAuditEntries = 10000
+
+// ErrDecisionAuditDisabled is returned when the decision audit log is
+// queried while it is disabled
+var ErrDecisionAuditDisabled = errors.New("decision audit log is disabled")
+
+// DecisionSink receives audited decisions as well as the decision audit
+// table, such as to index them for search. Send returns the entries it did
+// not deliver, which are sent again with the next flush, so sinks must
+// tolerate receiving an entry twice.
+type DecisionSink interface {
+	Send(ctx context.Context, entries []*store.DecisionAuditEntry) ([]*store.DecisionAuditEntry, error)
+}
+
+// DecisionAudit writes every authorization decision an account's admins may
+// need to investigate to the decision audit log: policy evaluations,
+// organization forbids and account admin bypasses. Decisions are held in
+// memory and written every flush interval, so authorization checks never
+// wait on the table.
+type DecisionAudit struct {
+	mu        sync.Mutex
+	pending   []*store.DecisionAuditEntry
+	dropped   int
+	store     *store.DecisionAuditStore
+	sink      DecisionSink
+	interval  time.Duration
+	retention time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewDecisionAudit creates a new DecisionAudit that flushes every interval
+// and keeps entries for retention
+func NewDecisionAudit(entries *store.DecisionAuditStore, interval, retention time.Duration, logger *slog.Logger) *DecisionAudit {
+	return &DecisionAudit{
+		store:     entries,
+		interval:  interval,
+		retention: retention,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// WithSink also sends every flushed entry to sink
+func (d *DecisionAudit) WithSink(sink DecisionSink) *DecisionAudit {
+	d.sink = sink
+	return d
+}
+
+// Record holds the decision on req for the next flush. A nil DecisionAudit
+// records nothing.
+func (d *DecisionAudit) Record(req *AuthzRequest, allowed bool, basis string) {
+	if d == nil {
+		return
+	}
+	entry := store.NewDecisionAuditEntry(req.AccountID, req.CallerARN, req.Action, req.Resource, allowed, basis, d.now(), d.retention)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.pending) >= maxPendingAuditEntries {
+		d.dropped++
+		return
+	}
+	d.pending = append(d.pending, entry)
+}
+
+// Flush writes the decisions recorded since the last flush, and sends them
+// to the sink if there is one. Entries that fail to be written or sent are
+// kept for the next flush, which writes and sends them again; both writes
+// overwrite rather than duplicate an entry.
+func (d *DecisionAudit) Flush(ctx context.Context) error {
+	d.mu.Lock()
+	pending, dropped := d.pending, d.dropped
+	d.pending, d.dropped = nil, 0
+	d.mu.Unlock()
+
+	if dropped > 0 {
+		d.logger.Warn("decisions past the audit buffer limit were not audited", "dropped", dropped, "limit", maxPendingAuditEntries)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+
+	unwritten, err := d.store.Write(ctx, pending)
+	if d.sink != nil {
+		unsent, sinkErr := d.sink.Send(ctx, pending)
+		if sinkErr != nil {
+			err = errors.Join(err, fmt.Errorf("decision sink: %w", sinkErr))
+		}
+		unwritten = unionEntries(unwritten, unsent)
+	}
+	if len(unwritten) == 0 {
+		return err
+	}
+	d.mu.Lock()
+	keep := min(len(unwritten), maxPendingAuditEntries-len(d.pending))
+	d.pending = append(unwritten[:keep:keep], d.pending...)
+	d.dropped += len(unwritten) - keep
+	d.mu.Unlock()
+	if err == nil {
+		err = errors.New("entries left unprocessed")
+	}
+	return fmt.Errorf("failed to write %d of %d decision audit entries: %w", len(unwritten), len(pending), err)
+}
+
+// unionEntries returns the entries in a or b, each once
+func unionEntries(a, b []*store.DecisionAuditEntry) []*store.DecisionAuditEntry {
+	if len(b) == 0 {
+		return a
+	}
+	seen := make(map[*store.DecisionAuditEntry]bool, len(a))
+	for _, entry := range a {
+		seen[entry] = true
+	}
+	for _, entry := range b {
+		if !seen[entry] {
+			a = append(a, entry)
+		}
+	}
+	return a
+}
+
+// Run flushes entries every interval until ctx is cancelled, then flushes
+// once more so the decisions of a stopping replica are not lost
+func (d *DecisionAudit) Run(ctx context.Context) {
+	d.logger.Info("decision audit log started", "interval", d.interval, "retention", d.retention)
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), decisionFlushTimeout)
+			if err := d.Flush(flushCtx); err != nil {
+				d.logger.Error("final decision audit flush failed", "error", err)
+			}
+			cancel()
+			d.logger.Info("decision audit log stopped")
+			return
+		case <-ticker.C:
+			if err := d.Flush(ctx); err != nil {
+				d.logger.Error("decision audit flush failed", "error", err)
+			}
+		}
+	}
+}
+
+// DecisionAudit returns the authorizer's decision audit log, or nil when it
+// is disabled
+func (a *authorizerImpl) DecisionAudit() *DecisionAudit {
+	return a.audit
+}
+
+// QueryDecisionAudit returns a page of an account's audited decisions
+// matching q, newest first
+func (a *authorizerImpl) QueryDecisionAudit(ctx context.Context, accountID string, q store.DecisionAuditQuery, limit int, pageToken string) (*store.DecisionAuditPage, error) {
+	if a.audit == nil {
+		return nil, ErrDecisionAuditDisabled
+	}
+	return a.audit.store.Query(ctx, accountID, q, limit, pageToken)
+}
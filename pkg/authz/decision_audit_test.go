@@ -0,0 +1,125 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// auditBatches counts the decision audit entries it is sent and fails them
+// while err is set
+type auditBatches struct {
+	client.DynamoDBClient
+	written int
+	err     error
+}
+
+func (c *auditBatches) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	for _, requests := range params.RequestItems {
+		c.written += len(requests)
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func TestDecisionAudit_RecordAndFlush(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &auditBatches{err: errors.New("throttled")}
+	d := NewDecisionAudit(store.NewDecisionAuditStore("decision-audit", c, logger), time.Minute, time.Hour, logger)
+
+	req := &AuthzRequest{AccountID: "123456789012", CallerARN: "arn:aws:iam::123456789012:role/dev", Action: "rosa:CreateCluster"}
+	for range 30 {
+		d.Record(req, true, BasisPolicy)
+	}
+
+	// A failed flush keeps the entries for the next one
+	if err := d.Flush(context.Background()); err == nil {
+		t.Fatal("expected the flush to fail")
+	}
+	d.Record(req, false, BasisOrganization)
+	c.err = nil
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if c.written != 31 {
+		t.Errorf("expected 31 entries written, got %d", c.written)
+	}
+
+	var nilAudit *DecisionAudit
+	nilAudit.Record(req, true, BasisAdmin)
+	a := &authorizerImpl{}
+	if _, err := a.QueryDecisionAudit(context.Background(), "123456789012", store.DecisionAuditQuery{}, 10, ""); !errors.Is(err, ErrDecisionAuditDisabled) {
+		t.Errorf("expected ErrDecisionAuditDisabled, got %v", err)
+	}
+}
+
+func TestDecisionAudit_BufferLimit(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &auditBatches{}
+	d := NewDecisionAudit(store.NewDecisionAuditStore("decision-audit", c, logger), time.Minute, time.Hour, logger)
+
+	req := &AuthzRequest{AccountID: "123456789012", CallerARN: "arn:aws:iam::123456789012:role/dev", Action: "rosa:CreateCluster"}
+	for range maxPendingAuditEntries + 5 {
+		d.Record(req, true, BasisPolicy)
+	}
+	if d.dropped != 5 {
+		t.Errorf("expected 5 dropped decisions, got %d", d.dropped)
+	}
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if c.written != maxPendingAuditEntries || d.dropped != 0 {
+		t.Errorf("expected %d entries written and the drop count reset, got %d and %d", maxPendingAuditEntries, c.written, d.dropped)
+	}
+}
+
+// failingSink delivers nothing while err is set
+type failingSink struct {
+	sent int
+	err  error
+}
+
+func (s *failingSink) Send(ctx context.Context, entries []*store.DecisionAuditEntry) ([]*store.DecisionAuditEntry, error) {
+	if s.err != nil {
+		return entries, s.err
+	}
+	s.sent += len(entries)
+	return nil, nil
+}
+
+func TestDecisionAudit_Sink(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	c := &auditBatches{}
+	sink := &failingSink{err: errors.New("cluster unavailable")}
+	d := NewDecisionAudit(store.NewDecisionAuditStore("decision-audit", c, logger), time.Minute, time.Hour, logger).WithSink(sink)
+
+	req := &AuthzRequest{AccountID: "123456789012", CallerARN: "arn:aws:iam::123456789012:role/dev", Action: "rosa:CreateCluster"}
+	for range 3 {
+		d.Record(req, true, BasisPolicy)
+	}
+
+	// Entries the sink did not take are kept even though the table took them
+	if err := d.Flush(context.Background()); err == nil {
+		t.Fatal("expected the flush to fail")
+	}
+	if len(d.pending) != 3 {
+		t.Fatalf("expected 3 pending entries, got %d", len(d.pending))
+	}
+	sink.err = nil
+	if err := d.Flush(context.Background()); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if sink.sent != 3 || c.written != 6 {
+		t.Errorf("expected 3 entries sent and 6 written, got %d and %d", sink.sent, c.written)
+	}
+}
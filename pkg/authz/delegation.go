@@ -0,0 +1,88 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+var (
+	// ErrAccountNotEnabled is returned when a delegation names an account
+	// that is not enabled
+	ErrAccountNotEnabled = errors.New("account is not enabled")
+	// ErrPrivilegedDelegation is returned when a privileged account would be
+	// delegated; delegates must not inherit the privileged bypass
+	ErrPrivilegedDelegation = errors.New("privileged accounts cannot be delegated")
+)
+
+// DelegationResolver looks up the delegation that lets a delegate account act
+// on an owner account (used by the delegation middleware)
+type DelegationResolver interface {
+	GetDelegation(ctx context.Context, accountID, delegateAccountID string) (*store.Delegation, error)
+}
+
+// CreateDelegation records that principals of delegation.DelegateAccountID
+// may act on delegation.AccountID. Both accounts must be enabled and the
+// owner must not be privileged.
+func (a *authorizerImpl) CreateDelegation(ctx context.Context, delegation *store.Delegation) error {
+	if err := a.checkDelegationAccounts(ctx, delegation); err != nil {
+		return err
+	}
+	return a.delegationStore.Create(ctx, delegation)
+}
+
+// CheckDelegation checks the accounts as CreateDelegation does, and that the
+// pair has no delegation yet
+func (a *authorizerImpl) CheckDelegation(ctx context.Context, delegation *store.Delegation) error {
+	if err := a.checkDelegationAccounts(ctx, delegation); err != nil {
+		return err
+	}
+	existing, err := a.delegationStore.Get(ctx, delegation.AccountID, delegation.DelegateAccountID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w: %s to %s", store.ErrDelegationExists, delegation.AccountID, delegation.DelegateAccountID)
+	}
+	return nil
+}
+
+func (a *authorizerImpl) checkDelegationAccounts(ctx context.Context, delegation *store.Delegation) error {
+	owner, err := a.accountStore.Get(ctx, delegation.AccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if owner == nil {
+		return fmt.Errorf("%w: %s", ErrAccountNotEnabled, delegation.AccountID)
+	}
+	if owner.Privileged {
+		return fmt.Errorf("%w: %s", ErrPrivilegedDelegation, delegation.AccountID)
+	}
+
+	delegate, err := a.accountStore.Get(ctx, delegation.DelegateAccountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if delegate == nil {
+		return fmt.Errorf("%w: %s", ErrAccountNotEnabled, delegation.DelegateAccountID)
+	}
+	return nil
+}
+
+// DeleteDelegation removes a delegation
+func (a *authorizerImpl) DeleteDelegation(ctx context.Context, accountID, delegateAccountID string) error {
+	return a.delegationStore.Delete(ctx, accountID, delegateAccountID)
+}
+
+// ListDelegations returns the delegations granted by an account
+func (a *authorizerImpl) ListDelegations(ctx context.Context, accountID string) ([]*store.Delegation, error) {
+	return a.delegationStore.List(ctx, accountID)
+}
+
+// GetDelegation returns the delegation from accountID to delegateAccountID,
+// or nil if none exists
+func (a *authorizerImpl) GetDelegation(ctx context.Context, accountID, delegateAccountID string) (*store.Delegation, error) {
+	return a.delegationStore.Get(ctx, accountID, delegateAccountID)
+}
@@ -0,0 +1,40 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// deletedGroup is the tombstone object of a group: the group and the members
+// it had, which are removed with it
+type deletedGroup struct {
+	*store.Group
+	Members []string `json:"members"`
+}
+
+// recordDeletion stores a tombstone of a resource that has just been deleted.
+// The deletion has already happened, so a failure is logged rather than
+// returned.
+func (a *authorizerImpl) recordDeletion(ctx context.Context, accountID, kind, resourceID string, object any, deletedBy string) {
+	raw, err := json.Marshal(object)
+	if err == nil {
+		err = a.deletionStore.Record(ctx, &store.Tombstone{
+			AccountID:  accountID,
+			Kind:       kind,
+			ResourceID: resourceID,
+			Object:     raw,
+			DeletedBy:  deletedBy,
+		}, a.cfg.DeletionRetention)
+	}
+	if err != nil {
+		a.logger.Error("failed to record deletion", "error", err, "account_id", accountID, "kind", kind, "resource_id", resourceID)
+	}
+}
+
+// ListDeletions returns an account's tombstones, newest first, optionally
+// limited to one kind
+func (a *authorizerImpl) ListDeletions(ctx context.Context, accountID, kind string, limit int) ([]*store.Tombstone, error) {
+	return a.deletionStore.List(ctx, accountID, kind, limit)
+}
@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+var (
+	// ErrGuardrailDenied is returned when a platform guardrail forbids a request
+	ErrGuardrailDenied = errors.New("request forbidden by a platform guardrail")
+	// ErrGuardrailsDisabled is returned when managing guardrails without a
+	// guardrail policy store configured
+	ErrGuardrailsDisabled = errors.New("guardrails are not configured")
+)
+
+// GuardrailChecker evaluates platform guardrails. Unlike tenant policies,
+// guardrails apply to every caller, privileged accounts included.
+type GuardrailChecker interface {
+	CheckGuardrails(ctx context.Context, req *AuthzRequest) error
+}
+
+// CheckGuardrails evaluates req against the guardrail policy store and
+// returns ErrGuardrailDenied if a guardrail forbids it. Guardrails can only
+// restrict: permit policies in the guardrail store grant nothing. Without a
+// guardrail policy store every request passes.
+func (a *authorizerImpl) CheckGuardrails(ctx context.Context, req *AuthzRequest) error {
+	if a.cfg.GuardrailPolicyStoreID == "" {
+		return nil
+	}
+
+	resp, err := a.avpClient.IsAuthorized(ctx, a.buildAVPRequest(req, nil, a.cfg.GuardrailPolicyStoreID))
+	if err != nil {
+		a.logger.Error("AVP guardrail check failed", "error", err, "account_id", req.AccountID)
+		return fmt.Errorf("guardrail check failed: %w", err)
+	}
+
+	if effectOf(resp) == effectForbid {
+		a.logger.Warn("request denied by guardrail",
+			"account_id", req.AccountID,
+			"caller_arn", req.CallerARN,
+			"action", req.Action,
+			"resource", req.Resource,
+		)
+		return fmt.Errorf("%w: %s on %s", ErrGuardrailDenied, req.Action, req.Resource)
+	}
+	return nil
+}
+
+// CreateGuardrail adds a static Cedar policy to the guardrail policy store
+func (a *authorizerImpl) CreateGuardrail(ctx context.Context, description, cedarPolicy string) (*store.StaticPolicy, error) {
+	if a.cfg.GuardrailPolicyStoreID == "" {
+		return nil, ErrGuardrailsDisabled
+	}
+
+	policy, err := a.createStaticPolicy(ctx, a.cfg.GuardrailPolicyStoreID, description, cedarPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("guardrail created", "policy_id", policy.PolicyID)
+	return policy, nil
+}
+
+// ListGuardrails returns the policies in the guardrail policy store
+func (a *authorizerImpl) ListGuardrails(ctx context.Context) ([]*store.StaticPolicy, error) {
+	if a.cfg.GuardrailPolicyStoreID == "" {
+		return nil, ErrGuardrailsDisabled
+	}
+	return a.listStaticPolicies(ctx, a.cfg.GuardrailPolicyStoreID)
+}
+
+// DeleteGuardrail removes a policy from the guardrail policy store
+func (a *authorizerImpl) DeleteGuardrail(ctx context.Context, policyID string) error {
+	if a.cfg.GuardrailPolicyStoreID == "" {
+		return ErrGuardrailsDisabled
+	}
+
+	if err := a.deleteStaticPolicy(ctx, a.cfg.GuardrailPolicyStoreID, policyID); err != nil {
+		return err
+	}
+
+	a.logger.Info("guardrail deleted", "policy_id", policyID)
+	return nil
+}
@@ -0,0 +1,198 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// ErrGroupNotFound is returned when a group does not exist
+var ErrGroupNotFound = errors.New("group not found")
+
+// ErrGroupMemberLimit is returned when adding a member would take a group
+// past Config.MaxGroupMembers
+var ErrGroupMemberLimit = errors.New("group member limit reached")
+
+// ErrMemberAccountNotAllowed is returned for a member ARN of an account other
+// than the group's that is not in Config.MemberPartnerAccounts
+var ErrMemberAccountNotAllowed = errors.New("member belongs to another account")
+
+// Member update statuses reported in MemberResult.Status
+const (
+	MemberAdded   = "added"
+	MemberRemoved = "removed"
+	MemberFailed  = "failed"
+)
+
+// MemberResult is the outcome of one requested membership change
+type MemberResult struct {
+	MemberARN string `json:"memberArn"`
+	// Operation is "add" or "remove"
+	Operation string `json:"operation"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// UpdateGroupMembers adds and removes group members. Every entry is
+// validated before anything is written; invalid entries are reported as
+// failed and the rest are applied conditioned on the group's members
+// version being unchanged since it was read, and version when given. If
+// another update got there first it returns store.ErrMembersConflict and
+// changes nothing. Adds must name principals of the account or of a partner
+// account, and adds that would take the group past Config.MaxGroupMembers
+// fail; the version check keeps a concurrent update from slipping past the
+// limit. The members of pattern groups are left to the pattern matcher and
+// return ErrPatternGroup. Results are in request order, adds first. It
+// returns the group's new members version.
+func (a *authorizerImpl) UpdateGroupMembers(ctx context.Context, accountID, groupID string, version *int64, add, remove []string) ([]MemberResult, int64, error) {
+	group, err := a.groupStore.Get(ctx, accountID, groupID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if group == nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+	if group.MemberPattern != "" {
+		return nil, 0, fmt.Errorf("%w: %s", ErrPatternGroup, groupID)
+	}
+	if version != nil && *version != group.MembersVersion {
+		return nil, 0, fmt.Errorf("%w: group %s is at version %d", store.ErrMembersConflict, groupID, group.MembersVersion)
+	}
+
+	addCount, removeCount := countMembers(add), countMembers(remove)
+	results := make([]MemberResult, 0, len(add)+len(remove))
+	var applyAdd, applyRemove []string
+	validate := func(memberARN, operation string, count map[string]int) bool {
+		reason := ""
+		switch {
+		case addCount[memberARN] > 0 && removeCount[memberARN] > 0:
+			reason = "member is listed in both add and remove"
+		case count[memberARN] > 1:
+			reason = "member is listed more than once"
+		default:
+			if err := validatePrincipalARN(memberARN); err != nil {
+				reason = err.Error()
+			} else if operation == "add" {
+				// Members already outside the account can still be removed
+				if err := a.checkMemberAccount(accountID, memberARN); err != nil {
+					reason = err.Error()
+				}
+			}
+		}
+		results = append(results, MemberResult{MemberARN: memberARN, Operation: operation, Status: MemberFailed, Reason: reason})
+		return reason == ""
+	}
+	for _, memberARN := range add {
+		if validate(memberARN, "add", addCount) {
+			applyAdd = append(applyAdd, memberARN)
+		}
+	}
+	for _, memberARN := range remove {
+		if validate(memberARN, "remove", removeCount) {
+			applyRemove = append(applyRemove, memberARN)
+		}
+	}
+
+	if a.cfg.MaxGroupMembers > 0 && len(applyAdd) > 0 {
+		over, err := a.overMemberLimit(ctx, accountID, groupID, applyAdd, applyRemove)
+		if err != nil {
+			return nil, 0, err
+		}
+		applyAdd = slices.DeleteFunc(applyAdd, func(memberARN string) bool { return over[memberARN] })
+		for i, result := range results {
+			if result.Operation == "add" && over[result.MemberARN] {
+				results[i].Reason = fmt.Sprintf("group has reached its limit of %d members", a.cfg.MaxGroupMembers)
+			}
+		}
+	}
+
+	newVersion, failed, err := a.memberStore.UpdateMembers(ctx, accountID, groupID, group.MembersVersion, applyAdd, applyRemove)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, result := range results {
+		if result.Reason != "" {
+			continue
+		}
+		if err := failed[result.MemberARN]; err != nil {
+			results[i].Reason = err.Error()
+		} else if result.Operation == "add" {
+			results[i].Status = MemberAdded
+		} else {
+			results[i].Status = MemberRemoved
+		}
+	}
+	return results, newVersion, nil
+}
+
+// overMemberLimit returns the adds, in order, that would take the group past
+// Config.MaxGroupMembers once remove is applied. Adds of existing members
+// do not count.
+func (a *authorizerImpl) overMemberLimit(ctx context.Context, accountID, groupID string, add, remove []string) (map[string]bool, error) {
+	current, err := a.memberStore.ListGroupMembers(ctx, accountID, groupID)
+	if err != nil {
+		return nil, err
+	}
+	size := len(current)
+	for _, memberARN := range remove {
+		if slices.Contains(current, memberARN) {
+			size--
+		}
+	}
+
+	over := map[string]bool{}
+	for _, memberARN := range add {
+		if slices.Contains(current, memberARN) {
+			continue
+		}
+		if size >= a.cfg.MaxGroupMembers {
+			over[memberARN] = true
+			continue
+		}
+		size++
+	}
+	return over, nil
+}
+
+// checkMemberAccount rejects member ARNs of accounts other than accountID
+// unless the account is listed in Config.MemberPartnerAccounts. Members
+// from arbitrary accounts make the group's policies grant access nobody
+// meant to.
+func (a *authorizerImpl) checkMemberAccount(accountID, memberARN string) error {
+	owner, _ := ResourceAccountID(memberARN)
+	if owner == accountID || slices.Contains(a.cfg.MemberPartnerAccounts, owner) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s is in account %s, which is not a partner account", ErrMemberAccountNotAllowed, memberARN, owner)
+}
+
+func countMembers(memberARNs []string) map[string]int {
+	count := make(map[string]int, len(memberARNs))
+	for _, memberARN := range memberARNs {
+		count[memberARN]++
+	}
+	return count
+}
+
+// validatePrincipalARN checks that arn names an IAM user or role, or an STS
+// assumed-role session
+func validatePrincipalARN(arn string) error {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" || !strings.HasPrefix(parts[1], "aws") {
+		return errors.New("not an ARN")
+	}
+	if len(parts[4]) != 12 || strings.Trim(parts[4], "0123456789") != "" {
+		return errors.New("ARN has no valid account ID")
+	}
+	switch resourceType, _, _ := strings.Cut(parts[5], "/"); {
+	case parts[2] == "iam" && (resourceType == "user" || resourceType == "role") && strings.Contains(parts[5], "/"):
+		return nil
+	case parts[2] == "sts" && resourceType == "assumed-role" && strings.Count(parts[5], "/") >= 2:
+		return nil
+	}
+	return errors.New("ARN is not an IAM user, role or assumed-role session")
+}
@@ -0,0 +1,112 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func TestValidatePrincipalARN(t *testing.T) {
+	tests := []struct {
+		arn     string
+		wantErr bool
+	}{
+		{arn: "arn:aws:iam::123456789012:user/alice"},
+		{arn: "arn:aws:iam::123456789012:role/path/DeveloperRole"},
+		{arn: "arn:aws:sts::123456789012:assumed-role/DeveloperRole/session"},
+		{arn: "arn:aws-us-gov:iam::123456789012:role/Admin"},
+		{arn: "alice", wantErr: true},
+		{arn: "arn:aws:iam::12345:user/alice", wantErr: true},
+		{arn: "arn:aws:iam::123456789012:group/devs", wantErr: true},
+		{arn: "arn:aws:iam::123456789012:role", wantErr: true},
+		{arn: "arn:aws:sts::123456789012:assumed-role/DeveloperRole", wantErr: true},
+		{arn: "arn:aws:s3:::bucket/key", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			err := validatePrincipalARN(tt.arn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePrincipalARN(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckMemberAccount(t *testing.T) {
+	a := &authorizerImpl{cfg: &Config{MemberPartnerAccounts: []string{"999999999999"}}}
+
+	tests := []struct {
+		arn     string
+		wantErr bool
+	}{
+		{arn: "arn:aws:iam::123456789012:user/alice"},
+		{arn: "arn:aws:sts::999999999999:assumed-role/Support/session"},
+		{arn: "arn:aws:iam::210987654321:role/Admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			err := a.checkMemberAccount("123456789012", tt.arn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkMemberAccount(%q) error = %v, wantErr %v", tt.arn, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrMemberAccountNotAllowed) {
+				t.Errorf("expected ErrMemberAccountNotAllowed, got %v", err)
+			}
+		})
+	}
+}
+
+// membersClient answers member queries with members
+type membersClient struct {
+	client.DynamoDBClient
+	members []string
+}
+
+func (c *membersClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	out := &dynamodb.QueryOutput{}
+	for _, arn := range c.members {
+		item, err := attributevalue.MarshalMap(&store.GroupMember{AccountID: "123456789012", GroupID: "g1", MemberARN: arn})
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func TestOverMemberLimit(t *testing.T) {
+	alice, bob, carol, dave := "arn:aws:iam::123456789012:user/alice", "arn:aws:iam::123456789012:user/bob", "arn:aws:iam::123456789012:user/carol", "arn:aws:iam::123456789012:user/dave"
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	a := &authorizerImpl{
+		cfg:         &Config{MaxGroupMembers: 3},
+		memberStore: store.NewMemberStore("members", &membersClient{members: []string{alice, bob}}, logger),
+	}
+
+	// alice is already a member, so only carol fits; removing bob makes
+	// room for dave as well
+	over, err := a.overMemberLimit(context.Background(), "123456789012", "g1", []string{alice, carol, dave}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(over) != 1 || !over[dave] {
+		t.Errorf("expected only dave over the limit, got %v", over)
+	}
+
+	over, err = a.overMemberLimit(context.Background(), "123456789012", "g1", []string{carol, dave}, []string{bob})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(over) != 0 {
+		t.Errorf("expected both adds to fit, got %v", over)
+	}
+}
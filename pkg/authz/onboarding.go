@@ -0,0 +1,139 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// onboardingStallTimeout is how long an onboarding may stay in a step before
+// a retry takes it over, for replicas that stopped in the middle of one
+const onboardingStallTimeout = 5 * time.Minute
+
+// ErrOnboardingNotRetryable is returned when retrying an onboarding that is
+// neither failed nor stalled
+var ErrOnboardingNotRetryable = errors.New("account onboarding is not retryable")
+
+// OnboardingError is returned when an onboarding step fails. The account is
+// left Failed at Step.
+type OnboardingError struct {
+	Step store.OnboardingState
+	Err  error
+}
+
+func (e *OnboardingError) Error() string {
+	return fmt.Sprintf("account onboarding failed at %s: %v", e.Step, e.Err)
+}
+
+func (e *OnboardingError) Unwrap() error {
+	return e.Err
+}
+
+// RetryOnboarding resumes a failed or stalled onboarding from the step it
+// stopped at
+func (a *authorizerImpl) RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found: %s", accountID)
+	}
+	if !OnboardingRetryable(account.OnboardingStatus(), a.now()) {
+		return nil, fmt.Errorf("%w: onboarding is %s", ErrOnboardingNotRetryable, account.OnboardingStatus().State)
+	}
+	if err := a.onboard(ctx, account); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("account onboarding retried", "account_id", accountID)
+	return account, nil
+}
+
+// OnboardingRetryable reports whether an onboarding failed, or has been in a
+// step for longer than onboardingStallTimeout
+func OnboardingRetryable(onboarding *store.Onboarding, now time.Time) bool {
+	switch onboarding.State {
+	case store.OnboardingFailed:
+		return true
+	case store.OnboardingReady:
+		return false
+	}
+	updatedAt, err := time.Parse(time.RFC3339Nano, onboarding.UpdatedAt)
+	return err == nil && now.Sub(updatedAt) > onboardingStallTimeout
+}
+
+// onboard runs account's onboarding steps from the one it is at, or failed
+// at, until it is Ready. Every step is recorded before it runs, and claiming
+// the first one fails with store.ErrOnboardingChanged when another request
+// got there first.
+func (a *authorizerImpl) onboard(ctx context.Context, account *store.Account) error {
+	step := account.Onboarding.State
+	switch step {
+	case store.OnboardingFailed:
+		step = account.Onboarding.FailedStep
+	case store.OnboardingPending:
+		step = store.OnboardingProvisioningStore
+	}
+
+	prev := account.Onboarding.UpdatedAt
+	account.Onboarding = &store.Onboarding{State: step}
+	if err := a.accountStore.SetOnboarding(ctx, account, prev); err != nil {
+		return err
+	}
+
+	for step != store.OnboardingReady {
+		next, err := a.runOnboardingStep(ctx, account, step)
+		prev = account.Onboarding.UpdatedAt
+		if err != nil {
+			account.Onboarding = &store.Onboarding{State: store.OnboardingFailed, FailedStep: step, Error: err.Error()}
+			// The failure is recorded even when the request was cancelled
+			if setErr := a.accountStore.SetOnboarding(context.WithoutCancel(ctx), account, prev); setErr != nil {
+				a.logger.Error("failed to record onboarding failure", "error", setErr, "account_id", account.AccountID, "step", step)
+			}
+			return &OnboardingError{Step: step, Err: err}
+		}
+
+		account.Onboarding = &store.Onboarding{State: next}
+		if err := a.accountStore.SetOnboarding(ctx, account, prev); err != nil {
+			// A store nobody recorded would be left behind
+			if step == store.OnboardingProvisioningStore {
+				_, _ = a.avpClient.DeletePolicyStore(context.WithoutCancel(ctx), &verifiedpermissions.DeletePolicyStoreInput{
+					PolicyStoreId: aws.String(account.PolicyStoreID),
+				})
+			}
+			return err
+		}
+		a.logger.Info("account onboarding step completed", "account_id", account.AccountID, "step", step, "next", next)
+		step = next
+	}
+	return nil
+}
+
+// runOnboardingStep runs one onboarding step, updating account with what it
+// set up, and returns the state that follows it
+func (a *authorizerImpl) runOnboardingStep(ctx context.Context, account *store.Account, step store.OnboardingState) (store.OnboardingState, error) {
+	switch step {
+	case store.OnboardingProvisioningStore:
+		policyStoreID, err := a.newPolicyStore(ctx, accountPolicyStoreDescription(account.AccountID))
+		if err != nil {
+			return "", err
+		}
+		account.PolicyStoreID = policyStoreID
+		return store.OnboardingSeedingDefaults, nil
+	case store.OnboardingSeedingDefaults:
+		if err := a.putSchema(ctx, account.PolicyStoreID); err != nil {
+			return "", err
+		}
+		account.SchemaVersion = schema.Version
+		return store.OnboardingReady, nil
+	}
+	return "", fmt.Errorf("unknown onboarding step %q", step)
+}
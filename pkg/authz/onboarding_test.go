@@ -0,0 +1,143 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// accountTable is an in-memory accounts table holding one account, applying
+// the onboarding updates of store.AccountStore.SetOnboarding
+type accountTable struct {
+	client.DynamoDBClient
+	item map[string]types.AttributeValue
+}
+
+func (t *accountTable) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if t.item != nil {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	t.item = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (t *accountTable) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: t.item}, nil
+}
+
+func (t *accountTable) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	values := params.ExpressionAttributeValues
+	onboarding, _ := t.item["onboarding"].(*types.AttributeValueMemberM)
+	if onboarding == nil || onboarding.Value["updatedAt"].(*types.AttributeValueMemberS).Value != values[":prev"].(*types.AttributeValueMemberS).Value {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	t.item["onboarding"] = values[":onboarding"]
+	if v, ok := values[":psid"]; ok {
+		t.item["policyStoreId"] = v
+	}
+	if v, ok := values[":version"]; ok {
+		t.item["schemaVersion"] = v
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+// onboardingAVP creates numbered policy stores and fails the first
+// failSchemas schema puts
+type onboardingAVP struct {
+	benchAVP
+	stores      int
+	schemas     []string
+	failSchemas int
+}
+
+func (a *onboardingAVP) CreatePolicyStore(ctx context.Context, params *verifiedpermissions.CreatePolicyStoreInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.CreatePolicyStoreOutput, error) {
+	a.stores++
+	return &verifiedpermissions.CreatePolicyStoreOutput{PolicyStoreId: aws.String(fmt.Sprintf("ps-%d", a.stores))}, nil
+}
+
+func (a *onboardingAVP) PutSchema(ctx context.Context, params *verifiedpermissions.PutSchemaInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.PutSchemaOutput, error) {
+	if a.failSchemas > 0 {
+		a.failSchemas--
+		return nil, errors.New("throttled")
+	}
+	a.schemas = append(a.schemas, aws.ToString(params.PolicyStoreId))
+	return &verifiedpermissions.PutSchemaOutput{}, nil
+}
+
+func TestOnboarding_RetryResumesFailedStep(t *testing.T) {
+	ctx := context.Background()
+	avp := &onboardingAVP{failSchemas: 1}
+	a := New(DefaultConfig(), &accountTable{}, avp, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := a.EnableAccount(ctx, "123456789012", "arn:aws:iam::000000000000:role/platform", false)
+	var onboardingErr *OnboardingError
+	if !errors.As(err, &onboardingErr) || onboardingErr.Step != store.OnboardingSeedingDefaults {
+		t.Fatalf("expected the onboarding to fail at SeedingDefaults, got %v", err)
+	}
+	account, err := a.GetAccount(ctx, "123456789012")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.Onboarding.State != store.OnboardingFailed || account.Onboarding.FailedStep != store.OnboardingSeedingDefaults || account.Onboarding.Error == "" {
+		t.Errorf("unexpected onboarding %+v", account.Onboarding)
+	}
+	if account.PolicyStoreID != "ps-1" {
+		t.Errorf("expected the created store to be recorded, got %q", account.PolicyStoreID)
+	}
+	if provisioned, err := a.IsAccountProvisioned(ctx, "123456789012"); err != nil || provisioned {
+		t.Errorf("expected a failed account not to be provisioned, got %v, %v", provisioned, err)
+	}
+
+	account, err = a.RetryOnboarding(ctx, "123456789012")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !account.Onboarded() || account.PolicyStoreID != "ps-1" || account.SchemaVersion != schema.Version {
+		t.Errorf("unexpected account after retry %+v", account)
+	}
+	if avp.stores != 1 || len(avp.schemas) != 1 || avp.schemas[0] != "ps-1" {
+		t.Errorf("expected the retry to put the schema in the existing store, got %d stores and schemas %v", avp.stores, avp.schemas)
+	}
+	if provisioned, err := a.IsAccountProvisioned(ctx, "123456789012"); err != nil || !provisioned {
+		t.Errorf("expected an onboarded account to be provisioned, got %v, %v", provisioned, err)
+	}
+
+	if _, err := a.RetryOnboarding(ctx, "123456789012"); !errors.Is(err, ErrOnboardingNotRetryable) {
+		t.Errorf("expected a Ready onboarding not to be retryable, got %v", err)
+	}
+}
+
+func TestOnboardingRetryable(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-time.Minute).Format(time.RFC3339Nano)
+	stalled := now.Add(-time.Hour).Format(time.RFC3339Nano)
+
+	tests := []struct {
+		onboarding store.Onboarding
+		want       bool
+	}{
+		{store.Onboarding{State: store.OnboardingFailed, UpdatedAt: recent}, true},
+		{store.Onboarding{State: store.OnboardingReady, UpdatedAt: stalled}, false},
+		{store.Onboarding{State: store.OnboardingSeedingDefaults, UpdatedAt: recent}, false},
+		{store.Onboarding{State: store.OnboardingSeedingDefaults, UpdatedAt: stalled}, true},
+		{store.Onboarding{State: store.OnboardingPending, UpdatedAt: stalled}, true},
+	}
+	for _, tt := range tests {
+		if got := OnboardingRetryable(&tt.onboarding, now); got != tt.want {
+			t.Errorf("OnboardingRetryable(%+v) = %v, want %v", tt.onboarding, got, tt.want)
+		}
+	}
+}
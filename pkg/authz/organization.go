@@ -0,0 +1,233 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+var (
+	// ErrOrganizationNotFound is returned when an organization does not exist
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrOrganizationHasMembers is returned when deleting an organization
+	// that still has member accounts
+	ErrOrganizationHasMembers = errors.New("organization has member accounts")
+	// ErrPrivilegedOrganizationMember is returned when a privileged account
+	// would join an organization; privileged accounts bypass all policies
+	ErrPrivilegedOrganizationMember = errors.New("privileged accounts cannot join an organization")
+)
+
+// policyEffect is the outcome of evaluating a request against one policy store
+type policyEffect int
+
+const (
+	// effectNoMatch means no policy matched (implicit deny)
+	effectNoMatch policyEffect = iota
+	effectPermit
+	effectForbid
+)
+
+// effectOf classifies an AVP decision. AVP reports the matching forbid
+// policies as the determining policies of a deny; an implicit deny has none.
+func effectOf(out *verifiedpermissions.IsAuthorizedOutput) policyEffect {
+	switch {
+	case out.Decision == avptypes.DecisionAllow:
+		return effectPermit
+	case len(out.DeterminingPolicies) > 0:
+		return effectForbid
+	default:
+		return effectNoMatch
+	}
+}
+
+// combineEffects merges the organization and account evaluations with Cedar's
+// semantics across both stores: a forbid in either denies, otherwise a
+// permit in either allows
+func combineEffects(org, account policyEffect) bool {
+	if org == effectForbid || account == effectForbid {
+		return false
+	}
+	return org == effectPermit || account == effectPermit
+}
+
+// evaluateOrganization evaluates req against the organization's policy store.
+// Group memberships are account-scoped, so organization policies match
+// principals by ARN or by the principalAccount context attribute.
+func (a *authorizerImpl) evaluateOrganization(ctx context.Context, req *AuthzRequest, orgID string) (policyEffect, error) {
+	org, err := a.organizationStore.Get(ctx, orgID)
+	if err != nil {
+		return effectNoMatch, err
+	}
+	if org == nil {
+		a.logger.Warn("account references missing organization", "account_id", req.AccountID, "organization_id", orgID)
+		return effectNoMatch, nil
+	}
+
+	resp, err := a.avpClient.IsAuthorized(ctx, a.buildAVPRequest(req, nil, org.PolicyStoreID))
+	if err != nil {
+		a.logger.Error("AVP organization authorization failed", "error", err, "organization_id", orgID)
+		return effectNoMatch, fmt.Errorf("authorization check failed: %w", err)
+	}
+	return effectOf(resp), nil
+}
+
+// CreateOrganization creates an organization with its own policy store
+func (a *authorizerImpl) CreateOrganization(ctx context.Context, name, createdBy string) (*store.Organization, error) {
+	policyStoreID, err := a.createPolicyStore(ctx, fmt.Sprintf("ROSA authorization policy store for organization %s", name))
+	if err != nil {
+		return nil, err
+	}
+
+	org := &store.Organization{
+		Name:          name,
+		PolicyStoreID: policyStoreID,
+		CreatedBy:     createdBy,
+	}
+	if err := a.organizationStore.Create(ctx, org); err != nil {
+		_, _ = a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+			PolicyStoreId: aws.String(policyStoreID),
+		})
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// GetOrganization retrieves an organization, or nil if it does not exist
+func (a *authorizerImpl) GetOrganization(ctx context.Context, orgID string) (*store.Organization, error) {
+	return a.organizationStore.Get(ctx, orgID)
+}
+
+// ListOrganizations returns all organizations
+func (a *authorizerImpl) ListOrganizations(ctx context.Context) ([]*store.Organization, error) {
+	return a.organizationStore.List(ctx)
+}
+
+// DeleteOrganization removes an organization and its policy store. Member
+// accounts must be removed first.
+func (a *authorizerImpl) DeleteOrganization(ctx context.Context, orgID string) error {
+	org, err := a.organizationStore.Get(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if org == nil {
+		return fmt.Errorf("%w: %s", ErrOrganizationNotFound, orgID)
+	}
+
+	accounts, err := a.accountStore.List(ctx)
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if account.OrganizationID == orgID {
+			return fmt.Errorf("%w: %s", ErrOrganizationHasMembers, orgID)
+		}
+	}
+
+	_, err = a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+		PolicyStoreId: aws.String(org.PolicyStoreID),
+	})
+	if err != nil {
+		a.logger.Warn("failed to delete policy store", "error", err, "policy_store_id", org.PolicyStoreID)
+	}
+
+	return a.organizationStore.Delete(ctx, orgID)
+}
+
+// SetAccountOrganization makes an account a member of an organization, or
+// removes it from its organization when orgID is empty
+func (a *authorizerImpl) SetAccountOrganization(ctx context.Context, accountID, orgID string) error {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return fmt.Errorf("%w: %s", ErrAccountNotEnabled, accountID)
+	}
+
+	if orgID != "" {
+		if account.Privileged {
+			return fmt.Errorf("%w: %s", ErrPrivilegedOrganizationMember, accountID)
+		}
+		org, err := a.organizationStore.Get(ctx, orgID)
+		if err != nil {
+			return err
+		}
+		if org == nil {
+			return fmt.Errorf("%w: %s", ErrOrganizationNotFound, orgID)
+		}
+	}
+
+	return a.accountStore.SetOrganizationID(ctx, accountID, orgID)
+}
+
+// getOrganizationPolicyStoreID returns the policy store ID for an organization
+func (a *authorizerImpl) getOrganizationPolicyStoreID(ctx context.Context, orgID string) (string, error) {
+	org, err := a.organizationStore.Get(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	if org == nil {
+		return "", fmt.Errorf("%w: %s", ErrOrganizationNotFound, orgID)
+	}
+	return org.PolicyStoreID, nil
+}
+
+// CreateOrganizationPolicy adds a static Cedar policy to the organization's
+// policy store. Unlike account policies it is not a template: it applies to
+// every principal of every member account that it matches.
+func (a *authorizerImpl) CreateOrganizationPolicy(ctx context.Context, orgID, description, cedarPolicy string) (*store.OrganizationPolicy, error) {
+	policyStoreID, err := a.getOrganizationPolicyStoreID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := a.createStaticPolicy(ctx, policyStoreID, description, cedarPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("organization policy created", "organization_id", orgID, "policy_id", policy.PolicyID)
+	return &store.OrganizationPolicy{OrganizationID: orgID, StaticPolicy: *policy}, nil
+}
+
+// ListOrganizationPolicies returns the static policies in an organization's
+// policy store
+func (a *authorizerImpl) ListOrganizationPolicies(ctx context.Context, orgID string) ([]*store.OrganizationPolicy, error) {
+	policyStoreID, err := a.getOrganizationPolicyStoreID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	policies, err := a.listStaticPolicies(ctx, policyStoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	orgPolicies := make([]*store.OrganizationPolicy, len(policies))
+	for i, policy := range policies {
+		orgPolicies[i] = &store.OrganizationPolicy{OrganizationID: orgID, StaticPolicy: *policy}
+	}
+	return orgPolicies, nil
+}
+
+// DeleteOrganizationPolicy removes a policy from an organization's policy store
+func (a *authorizerImpl) DeleteOrganizationPolicy(ctx context.Context, orgID, policyID string) error {
+	policyStoreID, err := a.getOrganizationPolicyStoreID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+
+	if err := a.deleteStaticPolicy(ctx, policyStoreID, policyID); err != nil {
+		return err
+	}
+
+	a.logger.Info("organization policy deleted", "organization_id", orgID, "policy_id", policyID)
+	return nil
+}
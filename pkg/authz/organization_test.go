@@ -0,0 +1,70 @@
+package authz
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+)
+
+func TestEffectOf(t *testing.T) {
+	tests := []struct {
+		name string
+		out  *verifiedpermissions.IsAuthorizedOutput
+		want policyEffect
+	}{
+		{
+			name: "allow is a permit",
+			out: &verifiedpermissions.IsAuthorizedOutput{
+				Decision:            avptypes.DecisionAllow,
+				DeterminingPolicies: []avptypes.DeterminingPolicyItem{{PolicyId: aws.String("p1")}},
+			},
+			want: effectPermit,
+		},
+		{
+			name: "deny with determining policies is a forbid",
+			out: &verifiedpermissions.IsAuthorizedOutput{
+				Decision:            avptypes.DecisionDeny,
+				DeterminingPolicies: []avptypes.DeterminingPolicyItem{{PolicyId: aws.String("f1")}},
+			},
+			want: effectForbid,
+		},
+		{
+			name: "deny without determining policies is no match",
+			out:  &verifiedpermissions.IsAuthorizedOutput{Decision: avptypes.DecisionDeny},
+			want: effectNoMatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := effectOf(tt.out); got != tt.want {
+				t.Errorf("effectOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineEffects(t *testing.T) {
+	tests := []struct {
+		name    string
+		org     policyEffect
+		account policyEffect
+		want    bool
+	}{
+		{name: "no organization policy, account permits", org: effectNoMatch, account: effectPermit, want: true},
+		{name: "no policy matches", org: effectNoMatch, account: effectNoMatch, want: false},
+		{name: "organization permits", org: effectPermit, account: effectNoMatch, want: true},
+		{name: "organization forbid overrides account permit", org: effectForbid, account: effectPermit, want: false},
+		{name: "account forbid overrides organization permit", org: effectPermit, account: effectForbid, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := combineEffects(tt.org, tt.account); got != tt.want {
+				t.Errorf("combineEffects(%v, %v) = %v, want %v", tt.org, tt.account, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+package authz
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+const (
+	parityAccount = "123456789012"
+	parityCaller  = "arn:aws:iam::123456789012:user/alice"
+	parityCluster = "arn:aws:rosa:us-east-1:123456789012:cluster/c1"
+	parityGroup   = "developers"
+)
+
+// parityAttachment links a policy template to a user or a group
+type parityAttachment struct {
+	template   string
+	targetType TargetType
+	targetID   string
+}
+
+// parityScenario is one authorization question asked of both AVP clients
+type parityScenario struct {
+	name        string
+	attachments []parityAttachment
+	groups      []string
+	request     AuthzRequest
+	want        string
+}
+
+// Outcomes of a scenario: the decision, or rejected when AVP refused a
+// template or attachment
+const (
+	outcomeAllow    = string(avptypes.DecisionAllow)
+	outcomeDeny     = string(avptypes.DecisionDeny)
+	outcomeRejected = "REJECTED"
+)
+
+func parityRequest(action string, resourceTags map[string]string) AuthzRequest {
+	return AuthzRequest{
+		AccountID:    parityAccount,
+		CallerARN:    parityCaller,
+		Action:       action,
+		Resource:     parityCluster,
+		ResourceTags: resourceTags,
+	}
+}
+
+// parityScenarios cover the places where the cedar-agent mock rewrites
+// policies or requests to imitate AVP
+var parityScenarios = []parityScenario{
+	{
+		name: "direct user attachment",
+		attachments: []parityAttachment{
+			{`permit(?principal, action == ROSA::Action::"DescribeCluster", resource);`, TargetTypeUser, parityCaller},
+		},
+		request: parityRequest("DescribeCluster", nil),
+		want:    outcomeAllow,
+	},
+	{
+		name: "no matching policy",
+		attachments: []parityAttachment{
+			{`permit(?principal, action == ROSA::Action::"DescribeCluster", resource);`, TargetTypeUser, parityCaller},
+		},
+		request: parityRequest("DeleteCluster", nil),
+		want:    outcomeDeny,
+	},
+	{
+		name: "group membership",
+		attachments: []parityAttachment{
+			{`permit(?principal, action == ROSA::Action::"DescribeCluster", resource);`, TargetTypeGroup, parityGroup},
+		},
+		groups:  []string{parityGroup},
+		request: parityRequest("DescribeCluster", nil),
+		want:    outcomeAllow,
+	},
+	{
+		name: "group the caller is not in",
+		attachments: []parityAttachment{
+			{`permit(?principal, action == ROSA::Action::"DescribeCluster", resource);`, TargetTypeGroup, parityGroup},
+		},
+		request: parityRequest("DescribeCluster", nil),
+		want:    outcomeDeny,
+	},
+	{
+		name: "forbid overrides permit",
+		attachments: []parityAttachment{
+			{`permit(?principal, action, resource);`, TargetTypeUser, parityCaller},
+			{`forbid(?principal, action == ROSA::Action::"DeleteCluster", resource);`, TargetTypeGroup, parityGroup},
+		},
+		groups:  []string{parityGroup},
+		request: parityRequest("DeleteCluster", nil),
+		want:    outcomeDeny,
+	},
+	{
+		name: "prefixed action",
+		attachments: []parityAttachment{
+			{`permit(?principal, action == ROSA::Action::"DescribeCluster", resource);`, TargetTypeUser, parityCaller},
+		},
+		request: parityRequest("rosa:GetCluster", nil),
+		want:    outcomeAllow,
+	},
+	{
+		name: "resource tag condition",
+		attachments: []parityAttachment{
+			{`permit(?principal, action, resource) when { resource.tags has "env" && resource.tags["env"] == "dev" };`, TargetTypeUser, parityCaller},
+		},
+		request: parityRequest("DescribeCluster", map[string]string{"env": "dev"}),
+		want:    outcomeAllow,
+	},
+	{
+		name: "resource in a parent",
+		attachments: []parityAttachment{
+			{`permit(?principal, action, resource in ROSA::Resource::"` + parityCluster + `");`, TargetTypeUser, parityCaller},
+		},
+		request: AuthzRequest{
+			AccountID:       parityAccount,
+			CallerARN:       parityCaller,
+			Action:          "DescribeNodePool",
+			Resource:        "arn:aws:rosa:us-east-1:123456789012:nodepool/np1",
+			ResourceParents: []string{parityCluster},
+		},
+		want: outcomeAllow,
+	},
+	{
+		// The mock rewrites this to resource.arn like, assuming AVP compares
+		// the entity ID as a string
+		name: "resource compared as a string",
+		attachments: []parityAttachment{
+			{`permit(?principal, action, resource) when { resource like "arn:aws:rosa:*:123456789012:cluster/*" };`, TargetTypeUser, parityCaller},
+		},
+		request: parityRequest("DescribeCluster", nil),
+		want:    outcomeAllow,
+	},
+	{
+		// The mock splits multi-statement text into one cedar-agent policy
+		// per statement
+		name: "multi-statement template",
+		attachments: []parityAttachment{
+			{"permit(?principal, action == ROSA::Action::\"DescribeCluster\", resource);\nforbid(?principal, action == ROSA::Action::\"DeleteCluster\", resource);", TargetTypeUser, parityCaller},
+		},
+		request: parityRequest("DescribeCluster", nil),
+		want:    outcomeRejected,
+	},
+}
+
+// TestAVPParity runs the same scenarios against Amazon Verified Permissions
+// and the cedar-agent mock and fails where their decisions differ. It needs
+// both backends: CEDAR_AGENT_ENDPOINT for the mock, and AVP_PARITY_REGION
+// plus AWS credentials for AVP. AVP_PARITY_ENDPOINT points the AVP client at
+// an emulator such as localstack instead of the real service.
+func TestAVPParity(t *testing.T) {
+	agentURL := os.Getenv("CEDAR_AGENT_ENDPOINT")
+	region := os.Getenv("AVP_PARITY_REGION")
+	if agentURL == "" || region == "" {
+		t.Skip("CEDAR_AGENT_ENDPOINT and AVP_PARITY_REGION not set — skipping AVP parity tests")
+	}
+
+	ctx := context.Background()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	awsCfg, err := config.LoadDefaultConfig(ctx, config.WithRegion(region))
+	if err != nil {
+		t.Fatalf("failed to load AWS config: %v", err)
+	}
+	if endpoint := os.Getenv("AVP_PARITY_ENDPOINT"); endpoint != "" {
+		awsCfg.BaseEndpoint = aws.String(endpoint)
+	}
+
+	backends := []struct {
+		name  string
+		authz *authorizerImpl
+	}{
+		{"avp", &authorizerImpl{cfg: DefaultConfig(), avpClient: client.NewAVPClientFromConfig(awsCfg), logger: logger}},
+		{"cedar-agent", &authorizerImpl{cfg: DefaultConfig(), avpClient: client.NewMockAVPClient(agentURL, logger), logger: logger}},
+	}
+
+	for _, sc := range parityScenarios {
+		t.Run(sc.name, func(t *testing.T) {
+			outcomes := make(map[string]string, len(backends))
+			for _, b := range backends {
+				outcome, err := b.authz.parityOutcome(ctx, sc)
+				if err != nil {
+					t.Fatalf("%s: %v", b.name, err)
+				}
+				outcomes[b.name] = outcome
+			}
+
+			if outcomes["avp"] != outcomes["cedar-agent"] {
+				t.Errorf("AVP decided %s but cedar-agent decided %s", outcomes["avp"], outcomes["cedar-agent"])
+			}
+			if outcomes["avp"] != sc.want {
+				t.Errorf("AVP decided %s, scenario expects %s", outcomes["avp"], sc.want)
+			}
+		})
+	}
+}
+
+// parityOutcome evaluates a scenario in a policy store of its own, which is
+// deleted afterwards
+func (a *authorizerImpl) parityOutcome(ctx context.Context, sc parityScenario) (string, error) {
+	policyStoreID, err := a.createPolicyStore(ctx, "rosa-authz-parity "+sc.name)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_, _ = a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+			PolicyStoreId: aws.String(policyStoreID),
+		})
+	}()
+
+	for _, att := range sc.attachments {
+		tmpl, err := a.avpClient.CreatePolicyTemplate(ctx, &verifiedpermissions.CreatePolicyTemplateInput{
+			PolicyStoreId: aws.String(policyStoreID),
+			Statement:     aws.String(att.template),
+		})
+		if err != nil {
+			return outcomeRejected, nil
+		}
+		if _, err := a.createTemplateLinkedPolicy(ctx, policyStoreID, aws.ToString(tmpl.PolicyTemplateId), att.targetType, att.targetID); err != nil {
+			return outcomeRejected, nil
+		}
+	}
+
+	resp, err := a.avpClient.IsAuthorized(ctx, a.buildAVPRequest(&sc.request, sc.groups, policyStoreID))
+	if err != nil {
+		return "", err
+	}
+	return string(resp.Decision), nil
+}
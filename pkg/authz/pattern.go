@@ -0,0 +1,263 @@
+package authz
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// ErrPrincipalPatternsDisabled is returned when a policy is attached to a
+// principal pattern while Config.PrincipalPatterns is off
+var ErrPrincipalPatternsDisabled = errors.New("principal pattern attachments are disabled")
+
+// ErrPatternGroup is returned when the members of a pattern group are
+// changed by hand; the pattern matcher maintains them
+var ErrPatternGroup = errors.New("members of a pattern group are maintained by the pattern matcher")
+
+// maxObservedPrincipals bounds the principals remembered between match
+// passes; callers seen once it is reached are matched when they call again
+const maxObservedPrincipals = 10000
+
+// IsPrincipalPattern reports whether a user attachment target is an ARN
+// pattern, such as arn:aws:iam::123456789012:role/dev-*, rather than an ARN
+func IsPrincipalPattern(target string) bool {
+	return strings.Contains(target, "*")
+}
+
+// validatePrincipalPattern checks that pattern is a principal ARN whose name
+// alone holds wildcards, so it cannot reach past its account or principal
+// type
+func validatePrincipalPattern(pattern string) error {
+	if err := validatePrincipalARN(pattern); err != nil {
+		return err
+	}
+	i := strings.Index(pattern, "*")
+	if j := strings.LastIndex(pattern[:i], ":"); !strings.Contains(pattern[j:i], "/") {
+		return errors.New("only the name of a principal pattern may contain *")
+	}
+	return nil
+}
+
+// matchPrincipalPattern reports whether arn matches pattern, in which *
+// matches any run of characters
+func matchPrincipalPattern(pattern, arn string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == arn
+	}
+	if !strings.HasPrefix(arn, parts[0]) {
+		return false
+	}
+	rest := arn[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(rest, part)
+		if i < 0 {
+			return false
+		}
+		rest = rest[i+len(part):]
+	}
+	return len(rest) >= len(last) && strings.HasSuffix(rest, last)
+}
+
+// SessionRoleARN returns the role ARN of an STS assumed-role session ARN
+func SessionRoleARN(arn string) (string, bool) {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[2] != "sts" {
+		return "", false
+	}
+	role, ok := strings.CutPrefix(parts[5], "assumed-role/")
+	if !ok {
+		return "", false
+	}
+	name, _, ok := strings.Cut(role, "/")
+	if !ok {
+		return "", false
+	}
+	return fmt.Sprintf("arn:%s:iam::%s:role/%s", parts[1], parts[4], name), true
+}
+
+// PatternGroupID returns the ID of the pattern group for pattern, derived
+// from it so concurrent attaches of one pattern share a group
+func PatternGroupID(pattern string) string {
+	sum := sha256.Sum256([]byte(pattern))
+	return "pattern-" + hex.EncodeToString(sum[:8])
+}
+
+// patternGroup returns the account's pattern group for pattern, creating it
+// on first use
+func (a *authorizerImpl) patternGroup(ctx context.Context, accountID, pattern string) (*store.Group, error) {
+	if !a.cfg.PrincipalPatterns {
+		return nil, ErrPrincipalPatternsDisabled
+	}
+	if err := validatePrincipalPattern(pattern); err != nil {
+		return nil, fmt.Errorf("invalid principal pattern %s: %w", pattern, err)
+	}
+	if err := a.checkMemberAccount(accountID, pattern); err != nil {
+		return nil, err
+	}
+
+	groupID := PatternGroupID(pattern)
+	group, err := a.groupStore.Get(ctx, accountID, groupID)
+	if err != nil || group != nil {
+		return group, err
+	}
+	return a.groupStore.CreatePatternGroup(ctx, accountID, groupID, pattern)
+}
+
+// matchPatterns adds principals of an account to the pattern groups whose
+// pattern they match. An assumed-role session matches through its role
+// ARN too; the session ARN is added, as that is what callers present.
+func (a *authorizerImpl) matchPatterns(ctx context.Context, accountID string, principals []string) error {
+	groups, err := a.groupStore.List(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, group := range groups {
+		if group.MemberPattern == "" {
+			continue
+		}
+		for _, principal := range principals {
+			role, _ := SessionRoleARN(principal)
+			if !matchPrincipalPattern(group.MemberPattern, principal) && (role == "" || !matchPrincipalPattern(group.MemberPattern, role)) {
+				continue
+			}
+			if err := a.addPatternMember(ctx, group, principal); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// addPatternMember adds principal to a pattern group unless it is already a
+// member or the group is at Config.MaxGroupMembers
+func (a *authorizerImpl) addPatternMember(ctx context.Context, group *store.Group, principal string) error {
+	isMember, err := a.memberStore.IsMember(ctx, group.AccountID, group.GroupID, principal)
+	if err != nil || isMember {
+		return err
+	}
+	if a.cfg.MaxGroupMembers > 0 {
+		over, err := a.overMemberLimit(ctx, group.AccountID, group.GroupID, []string{principal}, nil)
+		if err != nil {
+			return err
+		}
+		if over[principal] {
+			a.logger.Warn("pattern group member limit reached", "account_id", group.AccountID, "group_id", group.GroupID, "pattern", group.MemberPattern, "member_arn", principal)
+			return nil
+		}
+	}
+	return a.memberStore.Add(ctx, group.AccountID, group.GroupID, principal)
+}
+
+// principalKey identifies a principal observed calling for an account
+type principalKey struct {
+	accountID string
+	arn       string
+}
+
+// PatternMatcher adds the principals seen in authorization checks to the
+// pattern groups of their account whose pattern they match. Principals are
+// remembered in memory and matched every interval, so authorization checks
+// never wait on it; a principal's first requests can be denied until the
+// next pass. Every replica matches the principals it saw.
+type PatternMatcher struct {
+	mu       sync.Mutex
+	observed map[principalKey]struct{}
+	dropped  int
+	match    func(ctx context.Context, accountID string, principals []string) error
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewPatternMatcher creates a new PatternMatcher that passes the principals
+// observed for each account to match every interval
+func NewPatternMatcher(match func(ctx context.Context, accountID string, principals []string) error, interval time.Duration, logger *slog.Logger) *PatternMatcher {
+	return &PatternMatcher{
+		observed: make(map[principalKey]struct{}),
+		match:    match,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Observe remembers that principal called for accountID. A nil
+// PatternMatcher observes nothing.
+func (m *PatternMatcher) Observe(accountID, principal string) {
+	if m == nil {
+		return
+	}
+	key := principalKey{accountID: accountID, arn: principal}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.observed[key]; !ok && len(m.observed) >= maxObservedPrincipals {
+		m.dropped++
+		return
+	}
+	m.observed[key] = struct{}{}
+}
+
+// Match matches the principals observed since the last pass. Principals of
+// an account whose match fails are kept for the next pass.
+func (m *PatternMatcher) Match(ctx context.Context) error {
+	m.mu.Lock()
+	observed, dropped := m.observed, m.dropped
+	m.observed = make(map[principalKey]struct{})
+	m.dropped = 0
+	m.mu.Unlock()
+
+	if dropped > 0 {
+		m.logger.Warn("principals observed past the pattern matcher limit were skipped", "skipped", dropped, "limit", maxObservedPrincipals)
+	}
+	byAccount := map[string][]string{}
+	for key := range observed {
+		byAccount[key.accountID] = append(byAccount[key.accountID], key.arn)
+	}
+
+	var errs []error
+	for accountID, principals := range byAccount {
+		if err := m.match(ctx, accountID, principals); err != nil {
+			errs = append(errs, fmt.Errorf("account %s: %w", accountID, err))
+			for _, principal := range principals {
+				m.Observe(accountID, principal)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Run matches observed principals every interval until ctx is cancelled
+func (m *PatternMatcher) Run(ctx context.Context) {
+	m.logger.Info("principal pattern matcher started", "interval", m.interval)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("principal pattern matcher stopped")
+			return
+		case <-ticker.C:
+			if err := m.Match(ctx); err != nil {
+				m.logger.Error("principal pattern match failed", "error", err)
+			}
+		}
+	}
+}
+
+// PatternMatcher returns the authorizer's pattern matcher, or nil when
+// principal patterns are disabled
+func (a *authorizerImpl) PatternMatcher() *PatternMatcher {
+	return a.patterns
+}
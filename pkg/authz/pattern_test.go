@@ -0,0 +1,106 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidatePrincipalPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		wantErr bool
+	}{
+		{pattern: "arn:aws:iam::123456789012:role/dev-*"},
+		{pattern: "arn:aws:iam::123456789012:role/team/*-admin"},
+		{pattern: "arn:aws:sts::123456789012:assumed-role/dev-*/*"},
+		{pattern: "arn:aws:iam::123456789012:*/dev", wantErr: true},
+		{pattern: "arn:aws*:iam::123456789012:role/dev-*", wantErr: true},
+		{pattern: "arn:aws:iam:*:123456789012:role/dev-*", wantErr: true},
+		{pattern: "arn:aws:iam::*:role/dev-*", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			err := validatePrincipalPattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validatePrincipalPattern(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchPrincipalPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		arn     string
+		want    bool
+	}{
+		{pattern: "arn:aws:iam::123456789012:role/dev-*", arn: "arn:aws:iam::123456789012:role/dev-payments", want: true},
+		{pattern: "arn:aws:iam::123456789012:role/dev-*", arn: "arn:aws:iam::123456789012:role/dev-", want: true},
+		{pattern: "arn:aws:iam::123456789012:role/dev-*", arn: "arn:aws:iam::123456789012:role/prod-payments"},
+		{pattern: "arn:aws:iam::123456789012:role/*-admin", arn: "arn:aws:iam::123456789012:role/team-admin", want: true},
+		{pattern: "arn:aws:iam::123456789012:role/*-admin", arn: "arn:aws:iam::123456789012:role/team-admin-ro"},
+		{pattern: "arn:aws:iam::123456789012:role/a*a", arn: "arn:aws:iam::123456789012:role/a"},
+		{pattern: "arn:aws:iam::123456789012:role/*pay*", arn: "arn:aws:iam::123456789012:role/dev-payments", want: true},
+	}
+
+	for _, tt := range tests {
+		if got := matchPrincipalPattern(tt.pattern, tt.arn); got != tt.want {
+			t.Errorf("matchPrincipalPattern(%q, %q) = %v, want %v", tt.pattern, tt.arn, got, tt.want)
+		}
+	}
+}
+
+func TestSessionRoleARN(t *testing.T) {
+	role, ok := SessionRoleARN("arn:aws:sts::123456789012:assumed-role/dev-payments/alice")
+	if !ok || role != "arn:aws:iam::123456789012:role/dev-payments" {
+		t.Errorf("unexpected role %q", role)
+	}
+	if _, ok := SessionRoleARN("arn:aws:iam::123456789012:role/dev-payments"); ok {
+		t.Error("expected a role ARN not to be a session")
+	}
+}
+
+func TestPatternMatcher(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	matched := map[string][]string{}
+	failing := true
+	m := NewPatternMatcher(func(ctx context.Context, accountID string, principals []string) error {
+		if accountID == "210987654321" && failing {
+			return errors.New("throttled")
+		}
+		slices.Sort(principals)
+		matched[accountID] = principals
+		return nil
+	}, time.Minute, logger)
+
+	m.Observe("123456789012", "arn:aws:iam::123456789012:role/dev-b")
+	m.Observe("123456789012", "arn:aws:iam::123456789012:role/dev-a")
+	m.Observe("123456789012", "arn:aws:iam::123456789012:role/dev-a")
+	m.Observe("210987654321", "arn:aws:iam::210987654321:role/dev-c")
+
+	if err := m.Match(context.Background()); err == nil {
+		t.Fatal("expected the failing account to be reported")
+	}
+	if !slices.Equal(matched["123456789012"], []string{"arn:aws:iam::123456789012:role/dev-a", "arn:aws:iam::123456789012:role/dev-b"}) {
+		t.Errorf("expected each principal matched once, got %v", matched["123456789012"])
+	}
+
+	// The failed account's principals are matched on the next pass
+	failing = false
+	delete(matched, "123456789012")
+	if err := m.Match(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(matched["210987654321"]) != 1 || len(matched["123456789012"]) != 0 {
+		t.Errorf("expected only the retried account, got %v", matched)
+	}
+
+	var nilMatcher *PatternMatcher
+	nilMatcher.Observe("123456789012", "arn:aws:iam::123456789012:role/dev-a")
+}
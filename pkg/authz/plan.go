@@ -0,0 +1,25 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// SetAccountPlan moves the account to plan. Callers validate the plan name.
+func (a *authorizerImpl) SetAccountPlan(ctx context.Context, accountID, plan string) (*store.Account, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotEnabled, accountID)
+	}
+
+	if err := a.accountStore.SetPlan(ctx, accountID, plan); err != nil {
+		return nil, err
+	}
+	account.Plan = plan
+	return account, nil
+}
@@ -0,0 +1,168 @@
+package authz
+
+import (
+	"strings"
+)
+
+// Policy diff operations
+const (
+	DiffUnchanged = "unchanged"
+	DiffAdded     = "added"
+	DiffRemoved   = "removed"
+)
+
+// StatementDiff is one normalized Cedar statement of a PolicyDiff
+type StatementDiff struct {
+	// Op is DiffUnchanged, DiffAdded or DiffRemoved
+	Op        string `json:"op"`
+	Statement string `json:"statement"`
+}
+
+// PolicyDiff compares two Cedar policy texts statement by statement, after
+// normalizing their formatting
+type PolicyDiff struct {
+	Statements []StatementDiff `json:"statements"`
+	Added      int             `json:"added"`
+	Removed    int             `json:"removed"`
+	// Text is the diff in unified format, one statement per line
+	Text string `json:"text"`
+}
+
+// DiffPolicies diffs the Cedar text from against to. Comments and formatting
+// are ignored, so only changes to the statements themselves are reported.
+func DiffPolicies(from, to string) *PolicyDiff {
+	a, b := NormalizePolicy(from), NormalizePolicy(to)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:], b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	diff := &PolicyDiff{Statements: []StatementDiff{}}
+	var text strings.Builder
+	text.WriteString("--- current\n+++ proposed\n")
+	add := func(op, prefix, statement string) {
+		diff.Statements = append(diff.Statements, StatementDiff{Op: op, Statement: statement})
+		text.WriteString(prefix + statement + "\n")
+	}
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			add(DiffUnchanged, "  ", a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			add(DiffRemoved, "- ", a[i])
+			diff.Removed++
+			i++
+		default:
+			add(DiffAdded, "+ ", b[j])
+			diff.Added++
+			j++
+		}
+	}
+	diff.Text = text.String()
+	return diff
+}
+
+// cedarOperatorChars make up Cedar's comparison and logical operators
+const cedarOperatorChars = "=!<>&|"
+
+// NormalizePolicy splits Cedar text into its statements, each on one line
+// with comments removed and whitespace collapsed. String literals are kept
+// as written.
+func NormalizePolicy(text string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		space      bool
+	)
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			statements = append(statements, s+";")
+		}
+		current.Reset()
+		space = false
+	}
+	write := func(c byte) {
+		// The effect is always followed by a space, as in "permit ("
+		if c == '(' && (endsWithWord(current.String(), "permit") || endsWithWord(current.String(), "forbid")) {
+			space = true
+		}
+		// No space after an opening bracket or before a closing one or a comma
+		if space && !strings.ContainsRune(")],;", rune(c)) {
+			if last := lastByte(&current); last != 0 && !strings.ContainsRune("([", rune(last)) {
+				current.WriteByte(' ')
+			}
+		}
+		space = false
+		current.WriteByte(c)
+		if c == ',' {
+			space = true
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end = min(end+1, len(text))
+			write(c)
+			current.WriteString(text[i+1 : end])
+			i = end - 1
+		case c == '/' && i+1 < len(text) && text[i+1] == '/':
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+			space = true
+		case c == ';':
+			flush()
+		case strings.IndexByte(cedarOperatorChars, c) >= 0:
+			// Operators are spaced out, however they were written
+			space = true
+			for ; i < len(text) && strings.IndexByte(cedarOperatorChars, text[i]) >= 0; i++ {
+				write(text[i])
+			}
+			i--
+			space = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+		default:
+			write(c)
+		}
+	}
+	flush()
+	return statements
+}
+
+// endsWithWord reports whether s ends with word on its own
+func endsWithWord(s, word string) bool {
+	rest, ok := strings.CutSuffix(s, word)
+	return ok && (rest == "" || strings.HasSuffix(rest, " ") || strings.HasSuffix(rest, ")"))
+}
+
+func lastByte(b *strings.Builder) byte {
+	s := b.String()
+	if s == "" {
+		return 0
+	}
+	return s[len(s)-1]
+}
@@ -0,0 +1,63 @@
+package authz
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizePolicy(t *testing.T) {
+	text := `// Allow cluster reads
+permit (
+    principal==?principal,
+    action in [ ROSA::Action::"DescribeCluster" ,ROSA::Action::"ListClusters" ],
+    resource
+) when { resource.name == "a  // b;c" };
+
+forbid(principal,action,resource)`
+
+	want := []string{
+		`permit (principal == ?principal, action in [ROSA::Action::"DescribeCluster", ROSA::Action::"ListClusters"], resource) when { resource.name == "a  // b;c" };`,
+		`forbid (principal, action, resource);`,
+	}
+	if got := NormalizePolicy(text); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func TestDiffPolicies(t *testing.T) {
+	current := `permit (principal, action == ROSA::Action::"ListClusters", resource);
+permit (principal, action == ROSA::Action::"DescribeCluster", resource);`
+	proposed := `// reformatted and extended
+permit (
+  principal,
+  action == ROSA::Action::"ListClusters",
+  resource
+);
+permit (principal, action == ROSA::Action::"DeleteCluster", resource);`
+
+	diff := DiffPolicies(current, proposed)
+	want := []StatementDiff{
+		{Op: DiffUnchanged, Statement: `permit (principal, action == ROSA::Action::"ListClusters", resource);`},
+		{Op: DiffRemoved, Statement: `permit (principal, action == ROSA::Action::"DescribeCluster", resource);`},
+		{Op: DiffAdded, Statement: `permit (principal, action == ROSA::Action::"DeleteCluster", resource);`},
+	}
+	if !reflect.DeepEqual(diff.Statements, want) {
+		t.Errorf("expected %+v, got %+v", want, diff.Statements)
+	}
+	if diff.Added != 1 || diff.Removed != 1 {
+		t.Errorf("expected 1 added and 1 removed, got %d and %d", diff.Added, diff.Removed)
+	}
+	wantText := `--- current
++++ proposed
+  permit (principal, action == ROSA::Action::"ListClusters", resource);
+- permit (principal, action == ROSA::Action::"DescribeCluster", resource);
++ permit (principal, action == ROSA::Action::"DeleteCluster", resource);
+`
+	if diff.Text != wantText {
+		t.Errorf("expected text:\n%s\ngot:\n%s", wantText, diff.Text)
+	}
+
+	if diff := DiffPolicies(current, current+"\n// no change"); diff.Added != 0 || diff.Removed != 0 {
+		t.Errorf("expected no changes for a comment, got %+v", diff)
+	}
+}
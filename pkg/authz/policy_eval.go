@@ -0,0 +1,97 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// policyEvalGroup is the group the evaluated policies are attached to
+const policyEvalGroup = "policy-test"
+
+// PolicyEvaluator decides requests against a set of policies with an AVP
+// client alone, without the authz tables: there are no accounts, admins or
+// organizations, only the policies, attached to a group the caller is a
+// member of. It backs local policy tests run against the cedar-agent mock.
+type PolicyEvaluator struct {
+	a *authorizerImpl
+}
+
+// NewPolicyEvaluator creates a new PolicyEvaluator using cfg's Cedar
+// namespace
+func NewPolicyEvaluator(cfg *Config, avpClient client.AVPClient, logger *slog.Logger) *PolicyEvaluator {
+	return &PolicyEvaluator{a: &authorizerImpl{cfg: cfg, avpClient: avpClient, logger: logger}}
+}
+
+// PolicyStatement is statement Statement of policy Policy of an
+// evaluation, both counted from zero
+type PolicyStatement struct {
+	Policy    int
+	Statement int
+}
+
+// PolicyDecision is the outcome of an evaluation
+type PolicyDecision struct {
+	Allowed bool
+	// Determining are the statements that decided it: the permits of an
+	// allow, or the forbids of a deny. An implicit deny has none.
+	Determining []PolicyStatement
+}
+
+// Evaluate decides req in a policy store of its own, which is deleted
+// afterwards, holding policies attached to a group req.CallerARN is a member
+// of. Policies are validated as CreatePolicy validates them, then each of
+// their statements is linked on its own so the decision can name the
+// statements that determined it.
+func (e *PolicyEvaluator) Evaluate(ctx context.Context, policies []string, req *AuthzRequest) (*PolicyDecision, error) {
+	a := e.a
+	policyStoreID, err := a.createPolicyStore(ctx, "rosa-authz-policy-test")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_, _ = a.avpClient.DeletePolicyStore(context.WithoutCancel(ctx), &verifiedpermissions.DeletePolicyStoreInput{
+			PolicyStoreId: aws.String(policyStoreID),
+		})
+	}()
+
+	statements := make(map[string]PolicyStatement)
+	for i, policy := range policies {
+		if err := a.ValidatePolicy(policy); err != nil {
+			return nil, fmt.Errorf("policy %d: %w", i+1, err)
+		}
+		for j, statement := range NormalizePolicy(policy) {
+			tmpl, err := a.avpClient.CreatePolicyTemplate(ctx, &verifiedpermissions.CreatePolicyTemplateInput{
+				PolicyStoreId: aws.String(policyStoreID),
+				Statement:     aws.String(CanonicalPolicy(statement)),
+			})
+			if err != nil {
+				return nil, fmt.Errorf("policy %d: failed to create policy template: %w", i+1, err)
+			}
+			linked, err := a.createTemplateLinkedPolicy(ctx, policyStoreID, aws.ToString(tmpl.PolicyTemplateId), TargetTypeGroup, policyEvalGroup)
+			if err != nil {
+				return nil, fmt.Errorf("policy %d: %w", i+1, err)
+			}
+			statements[aws.ToString(linked.PolicyId)] = PolicyStatement{Policy: i, Statement: j}
+		}
+	}
+
+	avpReq := a.buildAVPRequest(req, []string{policyEvalGroup}, policyStoreID)
+	addPrincipalAttributes(avpReq, req.CallerARN)
+	resp, err := a.avpClient.IsAuthorized(ctx, avpReq)
+	if err != nil {
+		return nil, fmt.Errorf("authorization check failed: %w", err)
+	}
+	decision := &PolicyDecision{Allowed: effectOf(resp) == effectPermit}
+	for _, id := range determiningPolicies(resp) {
+		if statement, ok := statements[id]; ok {
+			decision.Determining = append(decision.Determining, statement)
+		}
+	}
+	return decision, nil
+}
@@ -0,0 +1,165 @@
+package authz
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FormatPolicy pretty-prints Cedar text in a canonical layout: each
+// statement's scope on its own lines, action lists sorted, when clauses
+// before unless clauses, each group sorted, and statements separated by a
+// blank line. Comments are dropped. Formatting the same policy written two
+// ways gives the same text.
+func FormatPolicy(text string) (string, error) {
+	statements := NormalizePolicy(text)
+	if len(statements) == 0 {
+		return "", fmt.Errorf("cedar policy text is required")
+	}
+	formatted := make([]string, 0, len(statements))
+	for i, statement := range statements {
+		f, err := formatStatement(strings.TrimSuffix(statement, ";"))
+		if err != nil {
+			return "", fmt.Errorf("statement %d: %w", i+1, err)
+		}
+		formatted = append(formatted, f)
+	}
+	return strings.Join(formatted, "\n\n") + "\n", nil
+}
+
+// CanonicalPolicy returns text formatted by FormatPolicy, or trimmed as it
+// is when it cannot be formatted. It is used to store and compare policies,
+// which should not be rejected only because the formatter does not follow
+// them.
+func CanonicalPolicy(text string) string {
+	if formatted, err := FormatPolicy(text); err == nil {
+		return formatted
+	}
+	return strings.TrimSpace(text)
+}
+
+// formatStatement formats one normalized statement without its semicolon
+func formatStatement(statement string) (string, error) {
+	var out strings.Builder
+
+	// Annotations, such as @id("...")
+	for strings.HasPrefix(statement, "@") {
+		open := strings.Index(statement, "(")
+		if open < 0 {
+			return "", fmt.Errorf("annotation has no value")
+		}
+		_, rest, err := splitPolicyHead(statement[open+1:])
+		if err != nil {
+			return "", fmt.Errorf("annotation is not closed")
+		}
+		out.WriteString(strings.TrimSpace(statement[:len(statement)-len(rest)]) + "\n")
+		statement = strings.TrimSpace(rest)
+	}
+
+	effect, rest, _ := strings.Cut(statement, " ")
+	if effect != "permit" && effect != "forbid" {
+		return "", fmt.Errorf("policy does not start with permit or forbid")
+	}
+	if !strings.HasPrefix(rest, "(") {
+		return "", fmt.Errorf("policy has no scope")
+	}
+	head, rest, err := splitPolicyHead(rest[1:])
+	if err != nil {
+		return "", err
+	}
+	if len(head) != 3 {
+		return "", fmt.Errorf("policy scope has %d elements, expected principal, action and resource", len(head))
+	}
+	for i := range head {
+		head[i] = strings.TrimSpace(head[i])
+	}
+	if head[1], err = sortActionList(head[1]); err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&out, "%s (\n  %s,\n  %s,\n  %s\n)", effect, head[0], head[1], head[2])
+
+	conditions, err := splitConditions(strings.TrimSpace(rest))
+	if err != nil {
+		return "", err
+	}
+	sort.SliceStable(conditions, func(i, j int) bool {
+		wi, wj := strings.HasPrefix(conditions[i], "when"), strings.HasPrefix(conditions[j], "when")
+		if wi != wj {
+			return wi
+		}
+		return conditions[i] < conditions[j]
+	})
+	for _, condition := range conditions {
+		out.WriteString("\n" + condition)
+	}
+	out.WriteString(";")
+	return out.String(), nil
+}
+
+// sortActionList sorts the entries of an `action in [...]` constraint, whose
+// order does not matter
+func sortActionList(action string) (string, error) {
+	open := strings.Index(action, "[")
+	if !strings.HasPrefix(action, "action in ") || open < 0 || !strings.HasSuffix(action, "]") {
+		return action, nil
+	}
+	entries, rest, err := splitPolicyHead(action[open+1:len(action)-1] + ")")
+	if err != nil || rest != "" {
+		return "", fmt.Errorf("unrecognized action scope %q", action)
+	}
+	for i := range entries {
+		entries[i] = strings.TrimSpace(entries[i])
+	}
+	sort.Strings(entries)
+	return action[:open+1] + strings.Join(entries, ", ") + "]", nil
+}
+
+// splitConditions splits the text after a policy's scope into its when and
+// unless clauses, each as `when { ... }` with its body trimmed
+func splitConditions(text string) ([]string, error) {
+	var conditions []string
+	for text != "" {
+		open := strings.Index(text, "{")
+		if open < 0 {
+			return nil, fmt.Errorf("unexpected %q after the policy scope", text)
+		}
+		keyword, rest := strings.TrimSpace(text[:open]), text[open:]
+		if keyword != "when" && keyword != "unless" {
+			return nil, fmt.Errorf("unexpected %q after the policy scope", text)
+		}
+		end := closingBrace(rest)
+		if end < 0 {
+			return nil, fmt.Errorf("%s clause is not closed", keyword)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s { %s }", keyword, strings.TrimSpace(rest[1:end])))
+		text = strings.TrimSpace(rest[end+1:])
+	}
+	return conditions, nil
+}
+
+// closingBrace returns the index of the brace closing the one text starts
+// with, skipping string literals, or -1
+func closingBrace(text string) int {
+	depth, inString := 0, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
@@ -0,0 +1,70 @@
+package authz
+
+import (
+	"testing"
+)
+
+func TestFormatPolicy(t *testing.T) {
+	want := `@id("cluster-readers")
+permit (
+  principal == ?principal,
+  action in [ROSA::Action::"DescribeCluster", ROSA::Action::"ListClusters"],
+  resource
+)
+when { context.mfa == true }
+when { resource.tags["team"] == "a" }
+unless { resource.name == "prod" };
+
+forbid (
+  principal,
+  action == ROSA::Action::"DeleteCluster",
+  resource
+);
+`
+
+	tests := []struct {
+		name    string
+		text    string
+		wantErr bool
+	}{
+		{
+			name: "already formatted",
+			text: want,
+		},
+		{
+			name: "reordered and reformatted",
+			text: `@id("cluster-readers") permit(principal==?principal,
+  action in [ROSA::Action::"ListClusters",ROSA::Action::"DescribeCluster"], // reads
+  resource) unless{resource.name=="prod"} when { context.mfa == true }
+  when {resource.tags["team"]=="a"};
+forbid (principal, action == ROSA::Action::"DeleteCluster", resource)`,
+		},
+		{name: "not a policy", text: `allow (principal, action, resource);`, wantErr: true},
+		{name: "unclosed condition", text: `permit (principal, action, resource) when { true;`, wantErr: true},
+		{name: "empty", text: "// nothing", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatPolicy(tt.text)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got:\n%s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != want {
+				t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestCanonicalPolicy(t *testing.T) {
+	if got := CanonicalPolicy("  permit (principal, action, resource) something else;  "); got != "permit (principal, action, resource) something else;" {
+		t.Errorf("expected unformattable text to be kept trimmed, got %q", got)
+	}
+}
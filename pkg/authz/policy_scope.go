@@ -0,0 +1,113 @@
+package authz
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AnyScope marks an unconstrained action or resource in a PolicyScope
+const AnyScope = "*"
+
+// PolicyScope is the head of a Cedar policy: what it applies to, without its
+// conditions
+type PolicyScope struct {
+	// Effect is "permit" or "forbid"
+	Effect string `json:"effect"`
+	// Actions are the action names the policy names, or AnyScope. Action
+	// groups are listed by name and not expanded.
+	Actions []string `json:"actions"`
+	// Resource is the resource constraint (e.g. `== ROSA::Resource::"id"`),
+	// or AnyScope
+	Resource string `json:"resource"`
+	// Conditional is true when when/unless clauses further limit the policy
+	Conditional bool `json:"conditional"`
+}
+
+var (
+	cedarLineComment = regexp.MustCompile(`//[^\n]*`)
+	cedarActionName  = regexp.MustCompile(`Action::"([^"]*)"`)
+	cedarConditions  = regexp.MustCompile(`\b(when|unless)\s*\{`)
+)
+
+// parsePolicyScope extracts the scope of a single Cedar policy or policy
+// template. It reads only the head, so it is a summary rather than an
+// evaluation: conditions are reported but not interpreted.
+func parsePolicyScope(statement string) (*PolicyScope, error) {
+	text := strings.TrimSpace(cedarLineComment.ReplaceAllString(statement, ""))
+
+	scope := &PolicyScope{}
+	switch {
+	case strings.HasPrefix(text, "permit"):
+		scope.Effect = "permit"
+	case strings.HasPrefix(text, "forbid"):
+		scope.Effect = "forbid"
+	default:
+		return nil, fmt.Errorf("policy does not start with permit or forbid")
+	}
+
+	open := strings.Index(text, "(")
+	if open < 0 {
+		return nil, fmt.Errorf("policy has no scope")
+	}
+	head, rest, err := splitPolicyHead(text[open+1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(head) != 3 {
+		return nil, fmt.Errorf("policy scope has %d elements, expected principal, action and resource", len(head))
+	}
+
+	action := strings.TrimSpace(head[1])
+	if action == "action" {
+		scope.Actions = []string{AnyScope}
+	} else {
+		for _, m := range cedarActionName.FindAllStringSubmatch(action, -1) {
+			scope.Actions = append(scope.Actions, m[1])
+		}
+		if len(scope.Actions) == 0 {
+			return nil, fmt.Errorf("unrecognized action scope %q", action)
+		}
+	}
+
+	scope.Resource = AnyScope
+	if resource := strings.Join(strings.Fields(head[2]), " "); resource != "resource" {
+		scope.Resource = strings.TrimSpace(strings.TrimPrefix(resource, "resource"))
+	}
+
+	scope.Conditional = cedarConditions.MatchString(rest)
+	return scope, nil
+}
+
+// splitPolicyHead splits the text after a policy's opening parenthesis into
+// its comma-separated scope elements, up to the matching close parenthesis,
+// and returns the text that follows it
+func splitPolicyHead(text string) ([]string, string, error) {
+	var parts []string
+	depth, start, inString := 0, 0, false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '(' || c == '[':
+			depth++
+		case c == ']':
+			depth--
+		case c == ')' && depth > 0:
+			depth--
+		case c == ')':
+			return append(parts, text[start:i]), text[i+1:], nil
+		case c == ',' && depth == 0:
+			parts = append(parts, text[start:i])
+			start = i + 1
+		}
+	}
+	return nil, "", fmt.Errorf("policy scope is not closed")
+}
@@ -0,0 +1,81 @@
+package authz
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParsePolicyScope(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    PolicyScope
+		wantErr bool
+	}{
+		{
+			name:   "all actions on all resources",
+			policy: `permit(?principal, action, resource);`,
+			want:   PolicyScope{Effect: "permit", Actions: []string{AnyScope}, Resource: AnyScope},
+		},
+		{
+			name: "action list",
+			policy: `permit(
+  ?principal,
+  action in [
+    ROSA::Action::"DescribeCluster", ROSA::Action::"ListClusters"
+  ],
+  resource
+);`,
+			want: PolicyScope{Effect: "permit", Actions: []string{"DescribeCluster", "ListClusters"}, Resource: AnyScope},
+		},
+		{
+			name:   "action group",
+			policy: `permit(?principal, action in ROSA::Action::"ReadOnly", resource);`,
+			want:   PolicyScope{Effect: "permit", Actions: []string{"ReadOnly"}, Resource: AnyScope},
+		},
+		{
+			name: "conditional forbid",
+			policy: `// Protect production
+forbid(
+  ?principal,
+  action == ROSA::Action::"DeleteCluster",
+  resource
+)
+when { resource.labels["Environment"] == "production" };`,
+			want: PolicyScope{Effect: "forbid", Actions: []string{"DeleteCluster"}, Resource: AnyScope, Conditional: true},
+		},
+		{
+			name:   "resource constraint",
+			policy: `permit(?principal, action == ROSA::Action::"ScaleNodePool", resource ==  ROSA::Resource::"np-1");`,
+			want:   PolicyScope{Effect: "permit", Actions: []string{"ScaleNodePool"}, Resource: `== ROSA::Resource::"np-1"`},
+		},
+		{
+			name:    "not a policy",
+			policy:  `allow everything`,
+			wantErr: true,
+		},
+		{
+			name:    "unclosed scope",
+			policy:  `permit(?principal, action, resource`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePolicyScope(tt.policy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Effect != tt.want.Effect || got.Resource != tt.want.Resource || got.Conditional != tt.want.Conditional || !slices.Equal(got.Actions, tt.want.Actions) {
+				t.Errorf("expected %+v, got %+v", tt.want, *got)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package authz
+
+import (
+	"context"
+	"slices"
+	"sort"
+)
+
+// PrincipalAccount describes how a principal is known to one account
+type PrincipalAccount struct {
+	AccountID string `json:"accountId"`
+	// Admin is true when the principal is an admin of the account
+	Admin bool `json:"admin"`
+	// Groups lists the IDs of the account's groups the principal belongs to
+	Groups []string `json:"groups"`
+}
+
+// FindPrincipalAccounts returns every account where principalARN is an admin
+// or a group member, ordered by account ID. Policies attached directly to the
+// principal are not considered; they live in each account's policy store.
+func (a *authorizerImpl) FindPrincipalAccounts(ctx context.Context, principalARN string) ([]*PrincipalAccount, error) {
+	admins, err := a.adminStore.ListByPrincipal(ctx, principalARN)
+	if err != nil {
+		return nil, err
+	}
+	members, err := a.memberStore.ListByMember(ctx, principalARN)
+	if err != nil {
+		return nil, err
+	}
+
+	byAccount := map[string]*PrincipalAccount{}
+	account := func(accountID string) *PrincipalAccount {
+		if pa, ok := byAccount[accountID]; ok {
+			return pa
+		}
+		pa := &PrincipalAccount{AccountID: accountID, Groups: []string{}}
+		byAccount[accountID] = pa
+		return pa
+	}
+	for _, admin := range admins {
+		account(admin.AccountID).Admin = true
+	}
+	for _, member := range members {
+		pa := account(member.AccountID)
+		pa.Groups = append(pa.Groups, member.GroupID)
+	}
+
+	accounts := make([]*PrincipalAccount, 0, len(byAccount))
+	for _, pa := range byAccount {
+		sort.Strings(pa.Groups)
+		accounts = append(accounts, pa)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		return accounts[i].AccountID < accounts[j].AccountID
+	})
+	return accounts, nil
+}
+
+// PrincipalAccess is a principal's effective access within one account
+type PrincipalAccess struct {
+	AccountID    string `json:"accountId"`
+	PrincipalARN string `json:"principalArn"`
+	// Admin is true when the principal is an account admin, which bypasses
+	// policies entirely
+	Admin bool `json:"admin"`
+	// Groups lists the IDs of the groups the principal belongs to
+	Groups []string `json:"groups"`
+	// Policies are the policies attached to the principal or its groups
+	Policies []*PrincipalPolicy `json:"policies"`
+	// Summary merges the policies' scopes by effect and resource
+	Summary []*AccessSummary `json:"summary"`
+}
+
+// PrincipalPolicy is a policy that applies to a principal through an
+// attachment to the principal itself or to one of its groups
+type PrincipalPolicy struct {
+	AttachmentID string     `json:"attachmentId"`
+	PolicyID     string     `json:"policyId"`
+	Name         string     `json:"name"`
+	TargetType   TargetType `json:"targetType"`
+	TargetID     string     `json:"targetId"`
+	// Scope is nil when the policy could not be parsed
+	Scope *PolicyScope `json:"scope,omitempty"`
+}
+
+// AccessSummary lists the actions that policies with the same effect grant or
+// deny on one resource pattern
+type AccessSummary struct {
+	Effect      string   `json:"effect"`
+	Resource    string   `json:"resource"`
+	Actions     []string `json:"actions"`
+	Conditional bool     `json:"conditional"`
+	PolicyIDs   []string `json:"policyIds"`
+}
+
+// GetPrincipalAccess reports what a principal can do in an account by
+// walking its group memberships and attachments and reading the attached
+// policy templates. The summary is derived from policy scopes only: when a
+// policy has conditions it is reported as conditional rather than evaluated.
+func (a *authorizerImpl) GetPrincipalAccess(ctx context.Context, accountID, principalARN string) (*PrincipalAccess, error) {
+	isAdmin, err := a.IsAdmin(ctx, accountID, principalARN)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := a.memberStore.GetUserGroups(ctx, accountID, principalARN)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []string{}
+	}
+	sort.Strings(groups)
+
+	attachments, err := a.ListAttachments(ctx, accountID, AttachmentFilter{TargetType: TargetTypeUser, TargetID: principalARN})
+	if err != nil {
+		return nil, err
+	}
+	for _, groupID := range groups {
+		groupAttachments, err := a.ListAttachments(ctx, accountID, AttachmentFilter{TargetType: TargetTypeGroup, TargetID: groupID})
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, groupAttachments...)
+	}
+
+	access := &PrincipalAccess{
+		AccountID:    accountID,
+		PrincipalARN: principalARN,
+		Admin:        isAdmin,
+		Groups:       groups,
+		Policies:     make([]*PrincipalPolicy, 0, len(attachments)),
+	}
+
+	policies := map[string]*PrincipalPolicy{}
+	for _, att := range attachments {
+		template, ok := policies[att.PolicyID]
+		if !ok {
+			template = &PrincipalPolicy{PolicyID: att.PolicyID}
+			policy, err := a.GetPolicy(ctx, accountID, att.PolicyID)
+			if err != nil {
+				a.logger.Warn("failed to get attached policy", "error", err, "account_id", accountID, "policy_id", att.PolicyID)
+			} else {
+				template.Name = policy.Name
+				if template.Scope, err = parsePolicyScope(policy.CedarPolicy); err != nil {
+					a.logger.Warn("failed to parse attached policy", "error", err, "account_id", accountID, "policy_id", att.PolicyID)
+				}
+			}
+			policies[att.PolicyID] = template
+		}
+
+		access.Policies = append(access.Policies, &PrincipalPolicy{
+			AttachmentID: att.AttachmentID,
+			PolicyID:     att.PolicyID,
+			Name:         template.Name,
+			TargetType:   att.TargetType,
+			TargetID:     att.TargetID,
+			Scope:        template.Scope,
+		})
+	}
+
+	access.Summary = summarizeAccess(access.Policies)
+	return access, nil
+}
+
+// summarizeAccess merges policy scopes that share an effect, resource and
+// conditionality, ordered with permits first
+func summarizeAccess(policies []*PrincipalPolicy) []*AccessSummary {
+	type key struct {
+		effect, resource string
+		conditional      bool
+	}
+	byKey := map[key]*AccessSummary{}
+	var summary []*AccessSummary
+	for _, p := range policies {
+		if p.Scope == nil {
+			continue
+		}
+		k := key{p.Scope.Effect, p.Scope.Resource, p.Scope.Conditional}
+		entry, ok := byKey[k]
+		if !ok {
+			entry = &AccessSummary{Effect: k.effect, Resource: k.resource, Conditional: k.conditional}
+			byKey[k] = entry
+			summary = append(summary, entry)
+		}
+		for _, action := range p.Scope.Actions {
+			if !slices.Contains(entry.Actions, action) {
+				entry.Actions = append(entry.Actions, action)
+			}
+		}
+		if !slices.Contains(entry.PolicyIDs, p.PolicyID) {
+			entry.PolicyIDs = append(entry.PolicyIDs, p.PolicyID)
+		}
+	}
+
+	for _, entry := range summary {
+		if slices.Contains(entry.Actions, AnyScope) {
+			entry.Actions = []string{AnyScope}
+		}
+		sort.Strings(entry.Actions)
+	}
+	sort.SliceStable(summary, func(i, j int) bool {
+		if summary[i].Effect != summary[j].Effect {
+			return summary[i].Effect == "permit"
+		}
+		return summary[i].Resource < summary[j].Resource
+	})
+	if summary == nil {
+		summary = []*AccessSummary{}
+	}
+	return summary
+}
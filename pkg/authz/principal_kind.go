@@ -0,0 +1,110 @@
+package authz
+
+import (
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+)
+
+// PrincipalKind is the kind of IAM principal a caller is, as sent to AVP in
+// the principal entity's kind attribute
+type PrincipalKind string
+
+const (
+	// PrincipalKindUser is an IAM user, the account root or a federated user
+	PrincipalKindUser PrincipalKind = "user"
+	// PrincipalKindRole is an IAM role or a session of one
+	PrincipalKindRole PrincipalKind = "role"
+	// PrincipalKindService is an AWS service: a service principal, a
+	// service-linked role or a session of one
+	PrincipalKindService PrincipalKind = "service"
+)
+
+// serviceLinkedRolePath is the IAM path of service-linked roles
+const serviceLinkedRolePath = "/aws-service-role/"
+
+// serviceLinkedRolePrefix starts the names of service-linked roles, which
+// identifies them in assumed-role session ARNs that carry no path
+const serviceLinkedRolePrefix = "AWSServiceRoleFor"
+
+// PrincipalAttributes describes a caller from its ARN
+type PrincipalAttributes struct {
+	Kind PrincipalKind
+	// Path is the IAM path of a user or role, such as "/team/"; empty when
+	// the ARN does not carry it, as for assumed-role sessions
+	Path string
+	// Name is the name of the user or role
+	Name string
+}
+
+// ParsePrincipal derives the kind, path and name of a caller from its ARN,
+// or reports false when it is not an IAM principal the authorizer knows
+func ParsePrincipal(principalARN string) (PrincipalAttributes, bool) {
+	if !strings.HasPrefix(principalARN, "arn:") {
+		// Service principals, such as ec2.amazonaws.com
+		if strings.HasSuffix(principalARN, ".amazonaws.com") {
+			return PrincipalAttributes{Kind: PrincipalKindService, Name: principalARN}, true
+		}
+		return PrincipalAttributes{}, false
+	}
+	parts := strings.SplitN(principalARN, ":", 6)
+	if len(parts) != 6 {
+		return PrincipalAttributes{}, false
+	}
+	service, resource := parts[2], parts[5]
+
+	switch {
+	case service == "iam" && resource == "root":
+		return PrincipalAttributes{Kind: PrincipalKindUser, Path: "/", Name: "root"}, true
+	case service == "iam" && strings.HasPrefix(resource, "user/"):
+		path, name := splitIAMPath(strings.TrimPrefix(resource, "user"))
+		return PrincipalAttributes{Kind: PrincipalKindUser, Path: path, Name: name}, name != ""
+	case service == "iam" && strings.HasPrefix(resource, "role/"):
+		path, name := splitIAMPath(strings.TrimPrefix(resource, "role"))
+		kind := PrincipalKindRole
+		if strings.HasPrefix(path, serviceLinkedRolePath) {
+			kind = PrincipalKindService
+		}
+		return PrincipalAttributes{Kind: kind, Path: path, Name: name}, name != ""
+	case service == "sts" && strings.HasPrefix(resource, "assumed-role/"):
+		name, _, ok := strings.Cut(strings.TrimPrefix(resource, "assumed-role/"), "/")
+		kind := PrincipalKindRole
+		if strings.HasPrefix(name, serviceLinkedRolePrefix) {
+			kind = PrincipalKindService
+		}
+		return PrincipalAttributes{Kind: kind, Name: name}, ok && name != ""
+	case service == "sts" && strings.HasPrefix(resource, "federated-user/"):
+		name := strings.TrimPrefix(resource, "federated-user/")
+		return PrincipalAttributes{Kind: PrincipalKindUser, Name: name}, name != ""
+	}
+	return PrincipalAttributes{}, false
+}
+
+// splitIAMPath splits "/path/to/name" into the path "/path/to/" and "name"
+func splitIAMPath(pathAndName string) (string, string) {
+	i := strings.LastIndex(pathAndName, "/")
+	return pathAndName[:i+1], pathAndName[i+1:]
+}
+
+// addPrincipalAttributes sets the kind, path and name of the caller on the
+// principal entity of an AVP request built by buildAVPRequest, which lists it
+// first. Callers whose ARN does not parse are sent without attributes.
+func addPrincipalAttributes(input *verifiedpermissions.IsAuthorizedInput, principalARN string) {
+	attrs, ok := ParsePrincipal(principalARN)
+	if !ok {
+		return
+	}
+	entities, isList := input.Entities.(*avptypes.EntitiesDefinitionMemberEntityList)
+	if !isList || len(entities.Value) == 0 {
+		return
+	}
+	values := map[string]avptypes.AttributeValue{
+		"kind": &avptypes.AttributeValueMemberString{Value: string(attrs.Kind)},
+		"name": &avptypes.AttributeValueMemberString{Value: attrs.Name},
+	}
+	if attrs.Path != "" {
+		values["path"] = &avptypes.AttributeValueMemberString{Value: attrs.Path}
+	}
+	entities.Value[0].Attributes = values
+}
@@ -0,0 +1,144 @@
+package authz
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func TestParsePrincipal(t *testing.T) {
+	tests := []struct {
+		arn  string
+		want PrincipalAttributes
+		ok   bool
+	}{
+		{arn: "arn:aws:iam::123456789012:user/alice", want: PrincipalAttributes{Kind: PrincipalKindUser, Path: "/", Name: "alice"}, ok: true},
+		{arn: "arn:aws:iam::123456789012:user/eng/alice", want: PrincipalAttributes{Kind: PrincipalKindUser, Path: "/eng/", Name: "alice"}, ok: true},
+		{arn: "arn:aws:iam::123456789012:root", want: PrincipalAttributes{Kind: PrincipalKindUser, Path: "/", Name: "root"}, ok: true},
+		{arn: "arn:aws:iam::123456789012:role/team/deployer", want: PrincipalAttributes{Kind: PrincipalKindRole, Path: "/team/", Name: "deployer"}, ok: true},
+		{arn: "arn:aws:sts::123456789012:assumed-role/deployer/ci-run", want: PrincipalAttributes{Kind: PrincipalKindRole, Name: "deployer"}, ok: true},
+		{arn: "arn:aws:sts::123456789012:federated-user/bob", want: PrincipalAttributes{Kind: PrincipalKindUser, Name: "bob"}, ok: true},
+		{arn: "arn:aws:iam::123456789012:role/aws-service-role/eks.amazonaws.com/AWSServiceRoleForAmazonEKS", want: PrincipalAttributes{Kind: PrincipalKindService, Path: "/aws-service-role/eks.amazonaws.com/", Name: "AWSServiceRoleForAmazonEKS"}, ok: true},
+		{arn: "arn:aws:sts::123456789012:assumed-role/AWSServiceRoleForAmazonEKS/eks", want: PrincipalAttributes{Kind: PrincipalKindService, Name: "AWSServiceRoleForAmazonEKS"}, ok: true},
+		{arn: "eks.amazonaws.com", want: PrincipalAttributes{Kind: PrincipalKindService, Name: "eks.amazonaws.com"}, ok: true},
+		{arn: "arn:aws:sts::123456789012:assumed-role/deployer"},
+		{arn: "arn:aws:iam::123456789012:group/admins"},
+		{arn: "alice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arn, func(t *testing.T) {
+			got, ok := ParsePrincipal(tt.arn)
+			if ok != tt.ok || (ok && got != tt.want) {
+				t.Errorf("ParsePrincipal(%q) = %+v, %v, want %+v, %v", tt.arn, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+// recordingAVP allows every request and keeps the last one
+type recordingAVP struct {
+	benchAVP
+	last *verifiedpermissions.IsAuthorizedInput
+}
+
+func (r *recordingAVP) IsAuthorized(ctx context.Context, params *verifiedpermissions.IsAuthorizedInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.IsAuthorizedOutput, error) {
+	r.last = params
+	return r.benchAVP.IsAuthorized(ctx, params, optFns...)
+}
+
+func TestAuthorize_PrincipalAttributes(t *testing.T) {
+	for _, version := range []int{0, schema.PrincipalKindsVersion} {
+		cfg := DefaultConfig()
+		account, err := attributevalue.MarshalMap(&store.Account{AccountID: "123456789012", PolicyStoreID: "ps-1", SchemaVersion: version})
+		if err != nil {
+			t.Fatalf("failed to marshal account: %v", err)
+		}
+		avp := &recordingAVP{}
+		a := New(cfg, &benchTables{cfg: cfg, account: account}, avp, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		if _, err := a.Authorize(context.Background(), &AuthzRequest{
+			AccountID: "123456789012",
+			CallerARN: "arn:aws:iam::123456789012:role/team/deployer",
+			Action:    "DescribeCluster",
+			Resource:  "arn:aws:rosa:us-east-1:123456789012:cluster/abc",
+		}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		principal := avp.last.Entities.(*avptypes.EntitiesDefinitionMemberEntityList).Value[0]
+		if version < schema.PrincipalKindsVersion {
+			if principal.Attributes != nil {
+				t.Errorf("expected no principal attributes before the migration, got %v", principal.Attributes)
+			}
+			continue
+		}
+		for name, want := range map[string]string{"kind": "role", "path": "/team/", "name": "deployer"} {
+			got, ok := principal.Attributes[name].(*avptypes.AttributeValueMemberString)
+			if !ok || got.Value != want {
+				t.Errorf("expected principal %s %q, got %v", name, want, principal.Attributes[name])
+			}
+		}
+	}
+}
+
+// migrationTables records account updates on top of benchTables
+type migrationTables struct {
+	benchTables
+	updates []*dynamodb.UpdateItemInput
+}
+
+func (m *migrationTables) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	m.updates = append(m.updates, params)
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+// schemaAVP records the schemas put in policy stores
+type schemaAVP struct {
+	benchAVP
+	schemas map[string]string
+}
+
+func (s *schemaAVP) PutSchema(ctx context.Context, params *verifiedpermissions.PutSchemaInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.PutSchemaOutput, error) {
+	s.schemas[aws.ToString(params.PolicyStoreId)] = params.Definition.(*avptypes.SchemaDefinitionMemberCedarJson).Value
+	return &verifiedpermissions.PutSchemaOutput{}, nil
+}
+
+func TestMigrateSchema(t *testing.T) {
+	for _, version := range []int{0, schema.Version} {
+		cfg := DefaultConfig()
+		account, err := attributevalue.MarshalMap(&store.Account{AccountID: "123456789012", PolicyStoreID: "ps-1", SchemaVersion: version})
+		if err != nil {
+			t.Fatalf("failed to marshal account: %v", err)
+		}
+		tables := &migrationTables{benchTables: benchTables{cfg: cfg, account: account}}
+		avp := &schemaAVP{schemas: map[string]string{}}
+		a := New(cfg, tables, avp, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		migration, err := a.MigrateSchema(context.Background(), "123456789012")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if migration.ToVersion != schema.Version || migration.FromVersion != StoreSchemaVersion(version) {
+			t.Errorf("unexpected migration %+v", migration)
+		}
+
+		wantMigrated := version < schema.Version
+		if migration.Migrated != wantMigrated || (len(avp.schemas) == 1) != wantMigrated || (len(tables.updates) == 1) != wantMigrated {
+			t.Errorf("version %d: expected migrated=%v, got %+v with %d schemas put and %d updates", version, wantMigrated, migration, len(avp.schemas), len(tables.updates))
+		}
+		if wantMigrated && avp.schemas["ps-1"] != schema.CedarSchemaJSON {
+			t.Error("expected the current schema to be put in the account's store")
+		}
+	}
+}
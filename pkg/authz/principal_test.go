@@ -0,0 +1,130 @@
+package authz
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// principalIndexTables answers the admins and members reverse-lookup
+// queries of one principal from in-memory entries
+type principalIndexTables struct {
+	client.DynamoDBClient
+	cfg     *Config
+	admins  []*store.Admin
+	members []*store.GroupMember
+}
+
+func (c *principalIndexTables) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	var entries []any
+	switch aws.ToString(params.TableName) {
+	case c.cfg.AdminsTableName:
+		for _, admin := range c.admins {
+			entries = append(entries, admin)
+		}
+	case c.cfg.MembersTableName:
+		for _, member := range c.members {
+			entries = append(entries, member)
+		}
+	}
+	out := &dynamodb.QueryOutput{}
+	for _, entry := range entries {
+		item, err := attributevalue.MarshalMap(entry)
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func TestSummarizeAccess(t *testing.T) {
+	readOnly := &PolicyScope{Effect: "permit", Actions: []string{"ListClusters", "DescribeCluster"}, Resource: AnyScope}
+	policies := []*PrincipalPolicy{
+		{PolicyID: "p1", Scope: readOnly},
+		{PolicyID: "p1", Scope: readOnly},
+		{PolicyID: "p2", Scope: &PolicyScope{Effect: "forbid", Actions: []string{"DeleteCluster"}, Resource: AnyScope}},
+		{PolicyID: "p3", Scope: &PolicyScope{Effect: "permit", Actions: []string{"ScaleNodePool"}, Resource: AnyScope}},
+		{PolicyID: "p4"},
+	}
+
+	summary := summarizeAccess(policies)
+	if len(summary) != 2 {
+		t.Fatalf("expected 2 summary entries, got %d", len(summary))
+	}
+	if summary[0].Effect != "permit" || !slices.Equal(summary[0].Actions, []string{"DescribeCluster", "ListClusters", "ScaleNodePool"}) {
+		t.Errorf("unexpected permit entry %+v", summary[0])
+	}
+	if !slices.Equal(summary[0].PolicyIDs, []string{"p1", "p3"}) {
+		t.Errorf("expected policies p1 and p3, got %v", summary[0].PolicyIDs)
+	}
+	if summary[1].Effect != "forbid" || !slices.Equal(summary[1].Actions, []string{"DeleteCluster"}) {
+		t.Errorf("unexpected forbid entry %+v", summary[1])
+	}
+
+	if got := summarizeAccess(nil); got == nil || len(got) != 0 {
+		t.Errorf("expected empty summary, got %v", got)
+	}
+}
+
+func TestFindPrincipalAccounts(t *testing.T) {
+	ctx := context.Background()
+	arn := "arn:aws:iam::123456789012:user/alice"
+	cfg := DefaultConfig()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	t.Run("merges admin and group entries by account", func(t *testing.T) {
+		tables := &principalIndexTables{
+			cfg: cfg,
+			admins: []*store.Admin{
+				{AccountID: "222222222222", PrincipalARN: arn},
+				{AccountID: "111111111111", PrincipalARN: arn},
+			},
+			members: []*store.GroupMember{
+				{AccountID: "222222222222", GroupID: "readers", MemberARN: arn},
+				{AccountID: "333333333333", GroupID: "operators", MemberARN: arn},
+				{AccountID: "333333333333", GroupID: "auditors", MemberARN: arn},
+			},
+		}
+		a := New(cfg, tables, &benchAVP{}, logger)
+
+		accounts, err := a.FindPrincipalAccounts(ctx, arn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want := []PrincipalAccount{
+			{AccountID: "111111111111", Admin: true, Groups: []string{}},
+			{AccountID: "222222222222", Admin: true, Groups: []string{"readers"}},
+			{AccountID: "333333333333", Groups: []string{"auditors", "operators"}},
+		}
+		if len(accounts) != len(want) {
+			t.Fatalf("expected %d accounts, got %+v", len(want), accounts)
+		}
+		for i, got := range accounts {
+			if got.AccountID != want[i].AccountID || got.Admin != want[i].Admin || !slices.Equal(got.Groups, want[i].Groups) {
+				t.Errorf("account %d = %+v, want %+v", i, got, want[i])
+			}
+		}
+	})
+
+	t.Run("unknown principal", func(t *testing.T) {
+		a := New(cfg, &principalIndexTables{cfg: cfg}, &benchAVP{}, logger)
+
+		accounts, err := a.FindPrincipalAccounts(ctx, arn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if accounts == nil || len(accounts) != 0 {
+			t.Errorf("expected an empty list, got %v", accounts)
+		}
+	})
+}
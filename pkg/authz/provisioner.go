@@ -0,0 +1,217 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// onboardingAVPCalls is how many AVP calls onboarding a tenant account makes
+const onboardingAVPCalls = 2
+
+// ErrAsyncOnboardingDisabled is returned when enabling an account
+// asynchronously without onboarding workers
+var ErrAsyncOnboardingDisabled = errors.New("asynchronous account enabling is disabled")
+
+// ErrOnboardingQueueFull is returned when the onboarding queue has no room
+// for another account
+var ErrOnboardingQueueFull = errors.New("account onboarding queue is full")
+
+// onboardingSource is the account storage the provisioner uses
+type onboardingSource interface {
+	Get(ctx context.Context, accountID string) (*store.Account, error)
+	List(ctx context.Context) ([]*store.Account, error)
+}
+
+// AccountProvisioner onboards the accounts enabled asynchronously. Accounts
+// wait in a queue on the replica that enabled them and are onboarded by a
+// bounded number of workers sharing an AVP call rate. Queues are not
+// persisted: Sweep requeues the accounts whose onboarding stalled, such as
+// those queued on a replica that stopped.
+type AccountProvisioner struct {
+	accounts onboardingSource
+	onboard  func(ctx context.Context, account *store.Account) error
+	workers  int
+	jobs     chan string
+	limiter  *rate.Limiter
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewAccountProvisioner creates a provisioner running onboard with workers
+// workers, queueing up to queueSize accounts and making at most avpRate AVP
+// calls per second
+func NewAccountProvisioner(accounts onboardingSource, onboard func(ctx context.Context, account *store.Account) error, workers, queueSize int, avpRate float64, logger *slog.Logger) *AccountProvisioner {
+	return &AccountProvisioner{
+		accounts: accounts,
+		onboard:  onboard,
+		workers:  workers,
+		jobs:     make(chan string, queueSize),
+		limiter:  rate.NewLimiter(rate.Limit(avpRate), onboardingAVPCalls),
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Enqueue queues an account for onboarding
+func (p *AccountProvisioner) Enqueue(accountID string) error {
+	select {
+	case p.jobs <- accountID:
+		return nil
+	default:
+		return ErrOnboardingQueueFull
+	}
+}
+
+// Full reports whether the queue has no room for another account
+func (p *AccountProvisioner) Full() bool {
+	return len(p.jobs) == cap(p.jobs)
+}
+
+// Queued returns the number of accounts waiting for a worker
+func (p *AccountProvisioner) Queued() int {
+	return len(p.jobs)
+}
+
+// Run onboards queued accounts until ctx is cancelled. Accounts still
+// queued then are left Pending for Sweep.
+func (p *AccountProvisioner) Run(ctx context.Context) {
+	p.logger.Info("account provisioner started", "workers", p.workers, "queue_size", cap(p.jobs))
+	var wg sync.WaitGroup
+	for range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case accountID := <-p.jobs:
+					p.process(ctx, accountID)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	p.logger.Info("account provisioner stopped", "queued", len(p.jobs))
+}
+
+// process onboards one queued account, unless it was onboarded, failed or
+// taken over since it was queued
+func (p *AccountProvisioner) process(ctx context.Context, accountID string) {
+	account, err := p.accounts.Get(ctx, accountID)
+	if err != nil {
+		p.logger.Error("failed to get account to onboard", "error", err, "account_id", accountID)
+		return
+	}
+	if account == nil || !p.due(account.OnboardingStatus()) {
+		return
+	}
+	if err := p.limiter.WaitN(ctx, onboardingAVPCalls); err != nil {
+		return
+	}
+
+	start := p.now()
+	switch err := p.onboard(ctx, account); {
+	case errors.Is(err, store.ErrOnboardingChanged):
+		p.logger.Debug("account onboarding taken over", "account_id", accountID)
+	case err != nil:
+		p.logger.Error("account onboarding failed", "error", err, "account_id", accountID)
+	default:
+		p.logger.Info("account onboarded", "account_id", accountID, "duration", p.now().Sub(start))
+	}
+}
+
+// due reports whether an onboarding should be run: it was never started, or
+// it stalled in a step. Failed onboardings wait for an explicit retry.
+func (p *AccountProvisioner) due(onboarding *store.Onboarding) bool {
+	if onboarding.State == store.OnboardingPending {
+		return true
+	}
+	return onboarding.State != store.OnboardingFailed && OnboardingRetryable(onboarding, p.now())
+}
+
+// Sweep queues the accounts whose onboarding stalled, and returns how many
+// it queued
+func (p *AccountProvisioner) Sweep(ctx context.Context) (int, error) {
+	accounts, err := p.accounts.List(ctx)
+	if err != nil {
+		return 0, err
+	}
+	queued := 0
+	for _, account := range accounts {
+		onboarding := account.OnboardingStatus()
+		if onboarding.State == store.OnboardingFailed || !OnboardingRetryable(onboarding, p.now()) {
+			continue
+		}
+		if err := p.Enqueue(account.AccountID); err != nil {
+			return queued, err
+		}
+		queued++
+	}
+	return queued, nil
+}
+
+// RunSweeper sweeps until ctx is cancelled, as often as an onboarding can
+// stall
+func (p *AccountProvisioner) RunSweeper(ctx context.Context) {
+	ticker := time.NewTicker(onboardingStallTimeout)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			queued, err := p.Sweep(ctx)
+			if err != nil {
+				p.logger.Error("account onboarding sweep failed", "error", err, "queued", queued)
+			} else if queued > 0 {
+				p.logger.Info("requeued stalled account onboardings", "queued", queued)
+			}
+		}
+	}
+}
+
+// EnableAccountAsync saves a new account as Pending and queues its
+// onboarding, whose progress shows in the account's onboarding. Privileged
+// accounts have nothing to onboard and are Ready at once.
+func (a *authorizerImpl) EnableAccountAsync(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error) {
+	if isPrivileged {
+		return a.EnableAccount(ctx, accountID, createdBy, true)
+	}
+	if a.provisioner == nil {
+		return nil, ErrAsyncOnboardingDisabled
+	}
+	// Checked before the account is saved, so a full queue does not leave
+	// it Pending until the next sweep
+	if a.provisioner.Full() {
+		return nil, ErrOnboardingQueueFull
+	}
+
+	account := &store.Account{
+		AccountID:  accountID,
+		CreatedBy:  createdBy,
+		Onboarding: &store.Onboarding{State: store.OnboardingPending},
+	}
+	if err := a.accountStore.Create(ctx, account); err != nil {
+		return nil, err
+	}
+	if err := a.provisioner.Enqueue(accountID); err != nil {
+		a.logger.Warn("onboarding queue filled up, leaving the account for the sweep", "account_id", accountID)
+	}
+
+	a.logger.Info("account enabled asynchronously", "account_id", accountID, "queued", a.provisioner.Queued())
+	return account, nil
+}
+
+// Provisioner returns the authorizer's account provisioner, or nil when
+// asynchronous enabling is off
+func (a *authorizerImpl) Provisioner() *AccountProvisioner {
+	return a.provisioner
+}
@@ -0,0 +1,134 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// fakeOnboardingSource holds accounts by ID
+type fakeOnboardingSource struct {
+	mu       sync.Mutex
+	accounts map[string]*store.Account
+}
+
+func (s *fakeOnboardingSource) Get(ctx context.Context, accountID string) (*store.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.accounts[accountID], nil
+}
+
+func (s *fakeOnboardingSource) List(ctx context.Context) ([]*store.Account, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var accounts []*store.Account
+	for _, account := range s.accounts {
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func TestAccountProvisioner_BoundedConcurrency(t *testing.T) {
+	now := time.Now().UTC().Format(time.RFC3339Nano)
+	source := &fakeOnboardingSource{accounts: map[string]*store.Account{}}
+	ids := []string{"111111111111", "222222222222", "333333333333", "444444444444", "555555555555"}
+	for _, id := range ids {
+		source.accounts[id] = &store.Account{AccountID: id, Onboarding: &store.Onboarding{State: store.OnboardingPending, UpdatedAt: now}}
+	}
+	// Already onboarded or failed accounts are skipped
+	source.accounts["666666666666"] = &store.Account{AccountID: "666666666666", Onboarding: &store.Onboarding{State: store.OnboardingReady}}
+	source.accounts["777777777777"] = &store.Account{AccountID: "777777777777", Onboarding: &store.Onboarding{State: store.OnboardingFailed}}
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	done := make(chan string, 10)
+	onboard := func(ctx context.Context, account *store.Account) error {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+		time.Sleep(5 * time.Millisecond)
+		mu.Lock()
+		running--
+		mu.Unlock()
+		done <- account.AccountID
+		return nil
+	}
+	p := NewAccountProvisioner(source, onboard, 2, 10, 1000, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	for _, id := range append(ids, "666666666666", "777777777777") {
+		if err := p.Enqueue(id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		p.Run(ctx)
+		close(stopped)
+	}()
+	onboarded := map[string]bool{}
+	for range ids {
+		select {
+		case id := <-done:
+			onboarded[id] = true
+		case <-time.After(time.Second):
+			t.Fatalf("timed out with %d accounts onboarded", len(onboarded))
+		}
+	}
+	cancel()
+	<-stopped
+
+	if len(onboarded) != len(ids) || len(done) != 0 {
+		t.Errorf("expected exactly the pending accounts to be onboarded, got %v and %d more", onboarded, len(done))
+	}
+	if maxRunning > 2 {
+		t.Errorf("expected at most 2 concurrent onboardings, got %d", maxRunning)
+	}
+}
+
+func TestAccountProvisioner_QueueFull(t *testing.T) {
+	p := NewAccountProvisioner(&fakeOnboardingSource{}, nil, 1, 1, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := p.Enqueue("111111111111"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !p.Full() {
+		t.Error("expected the queue to be full")
+	}
+	if err := p.Enqueue("222222222222"); !errors.Is(err, ErrOnboardingQueueFull) {
+		t.Errorf("expected ErrOnboardingQueueFull, got %v", err)
+	}
+}
+
+func TestAccountProvisioner_SweepQueuesStalledOnboardings(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	stalled := now.Add(-time.Hour).Format(time.RFC3339Nano)
+	recent := now.Add(-time.Second).Format(time.RFC3339Nano)
+	source := &fakeOnboardingSource{accounts: map[string]*store.Account{
+		"111111111111": {AccountID: "111111111111", Onboarding: &store.Onboarding{State: store.OnboardingPending, UpdatedAt: stalled}},
+		"222222222222": {AccountID: "222222222222", Onboarding: &store.Onboarding{State: store.OnboardingSeedingDefaults, UpdatedAt: stalled}},
+		"333333333333": {AccountID: "333333333333", Onboarding: &store.Onboarding{State: store.OnboardingPending, UpdatedAt: recent}},
+		"444444444444": {AccountID: "444444444444", Onboarding: &store.Onboarding{State: store.OnboardingFailed, UpdatedAt: stalled}},
+		"555555555555": {AccountID: "555555555555", CreatedAt: stalled},
+	}}
+	p := NewAccountProvisioner(source, nil, 1, 10, 1, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	p.now = func() time.Time { return now }
+
+	queued, err := p.Sweep(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if queued != 2 || p.Queued() != 2 {
+		t.Fatalf("expected the 2 stalled onboardings to be queued, got %d", queued)
+	}
+	got := map[string]bool{<-p.jobs: true, <-p.jobs: true}
+	if !got["111111111111"] || !got["222222222222"] {
+		t.Errorf("unexpected accounts queued: %v", got)
+	}
+}
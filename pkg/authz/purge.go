@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+)
+
+// PurgeTargets returns the targets deleting an account's authorization
+// data: the records of every authz table, including audited decisions and
+// deletion tombstones, then the account's policy store and finally the
+// account itself
+func (a *authorizerImpl) PurgeTargets() []purge.Target {
+	partitions := []struct {
+		name  string
+		table string
+		index string
+		keys  []string
+		// skip leaves out the tables of disabled features
+		skip bool
+	}{
+		{name: "authz-admins", table: a.cfg.AdminsTableName, keys: []string{"accountId", "principalArn"}},
+		{name: "authz-group-members", table: a.cfg.MembersTableName, keys: []string{"accountId", "groupId#memberArn"}},
+		{name: "authz-groups", table: a.cfg.GroupsTableName, keys: []string{"accountId", "groupId"}},
+		{name: "authz-delegations", table: a.cfg.DelegationsTableName, keys: []string{"accountId", "delegateAccountId"}},
+		{name: "authz-api-keys", table: a.cfg.APIKeysTableName, index: "account-index", keys: []string{"keyId"}},
+		{name: "authz-attachments", table: a.cfg.AttachmentsTableName, keys: []string{"accountId", "attachmentId"}},
+		{name: "authz-policy-tags", table: a.cfg.PolicyTagsTableName, keys: []string{"accountId", "policyId"}},
+		{name: "authz-pending-changes", table: a.cfg.PendingChangesTableName, keys: []string{"accountId", "changeId"}},
+		{name: "authz-change-requests", table: a.cfg.ChangeRequestsTableName, keys: []string{"accountId", "changeId"}},
+		{name: "authz-deletions", table: a.cfg.DeletionsTableName, keys: []string{"accountId", "deletionId"}},
+		{name: "authz-decision-counts", table: a.cfg.DecisionCountsTableName, keys: []string{"accountId", "bucketId"}, skip: !a.cfg.DecisionAnalytics},
+		{name: "authz-decision-audit", table: a.cfg.DecisionAuditTableName, keys: []string{"accountId", "entryId"}, skip: !a.cfg.DecisionAudit},
+	}
+
+	var targets []purge.Target
+	for _, p := range partitions {
+		if p.skip {
+			continue
+		}
+		targets = append(targets, purge.DynamoTarget(p.name, a.dynamoClient, purge.Partition{
+			Table:         p.table,
+			IndexName:     p.index,
+			PartitionKey:  "accountId",
+			KeyAttributes: p.keys,
+		}))
+	}
+	return append(targets,
+		purge.Target{Name: "avp-policy-store", Purge: a.purgePolicyStore},
+		purge.Target{Name: "authz-account", Purge: a.purgeAccount},
+	)
+}
+
+func (a *authorizerImpl) purgePolicyStore(ctx context.Context, accountID string) (int, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	if account == nil || account.PolicyStoreID == "" {
+		return 0, nil
+	}
+	if _, err := a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+		PolicyStoreId: aws.String(account.PolicyStoreID),
+	}); err != nil {
+		return 0, fmt.Errorf("failed to delete policy store %s: %w", account.PolicyStoreID, err)
+	}
+	return 1, nil
+}
+
+func (a *authorizerImpl) purgeAccount(ctx context.Context, accountID string) (int, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	if account == nil {
+		return 0, nil
+	}
+	if err := a.accountStore.Delete(ctx, accountID); err != nil {
+		return 0, err
+	}
+	return 1, nil
+}
@@ -0,0 +1,263 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// PolicyStoreExport is a portable snapshot of an account's policy templates
+// and attachments, from which its policy store can be rebuilt
+type PolicyStoreExport struct {
+	AccountID     string               `json:"accountId"`
+	PolicyStoreID string               `json:"policyStoreId,omitempty"`
+	ExportedAt    string               `json:"exportedAt,omitempty"`
+	Policies      []ExportedPolicy     `json:"policies"`
+	Attachments   []ExportedAttachment `json:"attachments"`
+}
+
+// ExportedPolicy is a policy template in a PolicyStoreExport
+type ExportedPolicy struct {
+	PolicyID    string `json:"policyId"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	CedarPolicy string `json:"cedarPolicy"`
+}
+
+// ExportedAttachment is a template-linked policy in a PolicyStoreExport.
+// PolicyID refers to an ExportedPolicy in the same export.
+type ExportedAttachment struct {
+	PolicyID    string     `json:"policyId"`
+	TargetType  TargetType `json:"targetType"`
+	TargetID    string     `json:"targetId"`
+	Name        string     `json:"name,omitempty"`
+	Description string     `json:"description,omitempty"`
+}
+
+// RebuildResult describes a rebuilt policy store. Policy and attachment IDs
+// are assigned by AVP, so PolicyIDs maps each exported policy ID to its new ID.
+type RebuildResult struct {
+	AccountID             string            `json:"accountId"`
+	PreviousPolicyStoreID string            `json:"previousPolicyStoreId,omitempty"`
+	PolicyStoreID         string            `json:"policyStoreId"`
+	Policies              int               `json:"policies"`
+	Attachments           int               `json:"attachments"`
+	PolicyIDs             map[string]string `json:"policyIds"`
+}
+
+// ExportPolicyStore snapshots every policy template and attachment in the
+// account's policy store. Unlike ListPolicies it fails rather than skipping
+// templates it cannot read, so an export is always complete.
+func (a *authorizerImpl) ExportPolicyStore(ctx context.Context, accountID string) (*PolicyStoreExport, error) {
+	policyStoreID, err := a.getAccountPolicyStoreID(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &PolicyStoreExport{
+		AccountID:     accountID,
+		PolicyStoreID: policyStoreID,
+		ExportedAt:    clock.Format(a.now()),
+		Policies:      []ExportedPolicy{},
+		Attachments:   []ExportedAttachment{},
+	}
+
+	var nextToken *string
+	for {
+		resp, err := a.avpClient.ListPolicyTemplates(ctx, &verifiedpermissions.ListPolicyTemplatesInput{
+			PolicyStoreId: aws.String(policyStoreID),
+			NextToken:     nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy templates: %w", err)
+		}
+
+		for _, tmpl := range resp.PolicyTemplates {
+			detail, err := a.avpClient.GetPolicyTemplate(ctx, &verifiedpermissions.GetPolicyTemplateInput{
+				PolicyStoreId:    aws.String(policyStoreID),
+				PolicyTemplateId: tmpl.PolicyTemplateId,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to get policy template %s: %w", aws.ToString(tmpl.PolicyTemplateId), err)
+			}
+
+			name, description := decodePolicyMeta(aws.ToString(detail.Description))
+			export.Policies = append(export.Policies, ExportedPolicy{
+				PolicyID:    aws.ToString(tmpl.PolicyTemplateId),
+				Name:        name,
+				Description: description,
+				CedarPolicy: aws.ToString(detail.Statement),
+			})
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	metas, err := a.attachmentStore.List(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	nextToken = nil
+	for {
+		resp, err := a.avpClient.ListPolicies(ctx, &verifiedpermissions.ListPoliciesInput{
+			PolicyStoreId: aws.String(policyStoreID),
+			Filter: &avptypes.PolicyFilter{
+				PolicyType: avptypes.PolicyTypeTemplateLinked,
+			},
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy attachments: %w", err)
+		}
+
+		for _, p := range resp.Policies {
+			tlDef, ok := p.Definition.(*avptypes.PolicyDefinitionItemMemberTemplateLinked)
+			if !ok || tlDef.Value.Principal == nil {
+				continue
+			}
+			exported := ExportedAttachment{PolicyID: aws.ToString(tlDef.Value.PolicyTemplateId)}
+			exported.TargetType, exported.TargetID = attachmentTarget(tlDef.Value.Principal)
+			if meta, ok := metas[aws.ToString(p.PolicyId)]; ok {
+				exported.Name, exported.Description = meta.Name, meta.Description
+			}
+			export.Attachments = append(export.Attachments, exported)
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		nextToken = resp.NextToken
+	}
+
+	return export, nil
+}
+
+// RebuildPolicyStore recreates an account's policy store from an export: it
+// creates a new store with the ROSA schema, re-creates the templates and
+// attachments, then swaps the account record to the new store with a
+// conditional write. If export is nil the current store is exported first,
+// which only works while that store is still readable. The new store is
+// deleted if any step before the swap fails; the old store is deleted
+// best-effort after it.
+func (a *authorizerImpl) RebuildPolicyStore(ctx context.Context, accountID string, export *PolicyStoreExport) (*RebuildResult, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found: %s", accountID)
+	}
+	if account.Privileged {
+		return nil, fmt.Errorf("account is privileged and has no policy store: %s", accountID)
+	}
+
+	if export == nil {
+		export, err = a.ExportPolicyStore(ctx, accountID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to export current policy store: %w", err)
+		}
+	}
+	if export.AccountID != "" && export.AccountID != accountID {
+		return nil, fmt.Errorf("invalid export: exported from account %s", export.AccountID)
+	}
+	for _, att := range export.Attachments {
+		if err := ValidateTarget(att.TargetType, att.TargetID); err != nil {
+			return nil, fmt.Errorf("invalid export: %w", err)
+		}
+	}
+
+	newStoreID, err := a.createPolicyStore(ctx, accountPolicyStoreDescription(accountID))
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := a.restorePolicyStore(ctx, accountID, newStoreID, export)
+	if err == nil {
+		err = a.accountStore.SwapPolicyStoreID(ctx, accountID, account.PolicyStoreID, newStoreID, schema.Version)
+	}
+	if err != nil {
+		if _, delErr := a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+			PolicyStoreId: aws.String(newStoreID),
+		}); delErr != nil {
+			a.logger.Warn("failed to clean up rebuilt policy store", "error", delErr, "policy_store_id", newStoreID)
+		}
+		return nil, err
+	}
+
+	result.AccountID = accountID
+	result.PreviousPolicyStoreID = account.PolicyStoreID
+	result.PolicyStoreID = newStoreID
+
+	if account.PolicyStoreID != "" {
+		_, err := a.avpClient.DeletePolicyStore(ctx, &verifiedpermissions.DeletePolicyStoreInput{
+			PolicyStoreId: aws.String(account.PolicyStoreID),
+		})
+		var notFound *avptypes.ResourceNotFoundException
+		if err != nil && !errors.As(err, &notFound) {
+			a.logger.Warn("failed to delete previous policy store", "error", err, "policy_store_id", account.PolicyStoreID)
+		}
+	}
+
+	a.logger.Info("policy store rebuilt",
+		"account_id", accountID,
+		"previous_policy_store_id", account.PolicyStoreID,
+		"policy_store_id", newStoreID,
+		"policies", result.Policies,
+		"attachments", result.Attachments,
+	)
+	return result, nil
+}
+
+// restorePolicyStore re-creates the templates and attachments of export in policyStoreID
+func (a *authorizerImpl) restorePolicyStore(ctx context.Context, accountID, policyStoreID string, export *PolicyStoreExport) (*RebuildResult, error) {
+	result := &RebuildResult{PolicyIDs: make(map[string]string, len(export.Policies))}
+
+	for _, p := range export.Policies {
+		resp, err := a.avpClient.CreatePolicyTemplate(ctx, &verifiedpermissions.CreatePolicyTemplateInput{
+			PolicyStoreId: aws.String(policyStoreID),
+			Statement:     aws.String(p.CedarPolicy),
+			Description:   aws.String(encodePolicyMeta(p.Name, p.Description)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore policy %s: %w", p.PolicyID, err)
+		}
+		result.PolicyIDs[p.PolicyID] = aws.ToString(resp.PolicyTemplateId)
+		result.Policies++
+	}
+
+	for _, att := range export.Attachments {
+		policyID, ok := result.PolicyIDs[att.PolicyID]
+		if !ok {
+			return nil, fmt.Errorf("invalid export: attachment references unknown policy %s", att.PolicyID)
+		}
+		resp, err := a.createTemplateLinkedPolicy(ctx, policyStoreID, policyID, att.TargetType, att.TargetID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to restore attachment of policy %s to %s: %w", att.PolicyID, att.TargetID, err)
+		}
+		if att.Name != "" || att.Description != "" {
+			if err := a.attachmentStore.Put(ctx, &store.AttachmentMeta{
+				AccountID:    accountID,
+				AttachmentID: aws.ToString(resp.PolicyId),
+				Name:         att.Name,
+				Description:  att.Description,
+			}); err != nil {
+				return nil, err
+			}
+		}
+		result.Attachments++
+	}
+
+	return result, nil
+}
@@ -0,0 +1,439 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// policyStoreTables is an in-memory DynamoDB holding the accounts and
+// attachment metadata tables. Swapping an account's policy store honours the
+// condition on the old store; concurrentStore, when set, is swapped in just
+// before, as by a rebuild that won the race.
+type policyStoreTables struct {
+	client.DynamoDBClient
+	cfg             *Config
+	accounts        map[string]map[string]types.AttributeValue
+	attachments     []map[string]types.AttributeValue
+	concurrentStore string
+}
+
+func newPolicyStoreTables(t *testing.T, cfg *Config, accounts ...*store.Account) *policyStoreTables {
+	t.Helper()
+	tables := &policyStoreTables{cfg: cfg, accounts: map[string]map[string]types.AttributeValue{}}
+	for _, account := range accounts {
+		item, err := attributevalue.MarshalMap(account)
+		if err != nil {
+			t.Fatalf("failed to marshal account: %v", err)
+		}
+		tables.accounts[account.AccountID] = item
+	}
+	return tables
+}
+
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	v, _ := item[name].(*types.AttributeValueMemberS)
+	if v == nil {
+		return ""
+	}
+	return v.Value
+}
+
+func (c *policyStoreTables) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	accountID := stringAttr(params.Key, "accountId")
+	switch aws.ToString(params.TableName) {
+	case c.cfg.AccountsTableName:
+		return &dynamodb.GetItemOutput{Item: c.accounts[accountID]}, nil
+	case c.cfg.AttachmentsTableName:
+		for _, item := range c.attachments {
+			if stringAttr(item, "accountId") == accountID && stringAttr(item, "attachmentId") == stringAttr(params.Key, "attachmentId") {
+				return &dynamodb.GetItemOutput{Item: item}, nil
+			}
+		}
+	}
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (c *policyStoreTables) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if aws.ToString(params.TableName) != c.cfg.AttachmentsTableName {
+		return &dynamodb.QueryOutput{}, nil
+	}
+	accountID := stringAttr(params.ExpressionAttributeValues, ":aid")
+	var items []map[string]types.AttributeValue
+	for _, item := range c.attachments {
+		if stringAttr(item, "accountId") == accountID {
+			items = append(items, item)
+		}
+	}
+	return &dynamodb.QueryOutput{Items: items, Count: int32(len(items))}, nil
+}
+
+func (c *policyStoreTables) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if aws.ToString(params.TableName) == c.cfg.AttachmentsTableName {
+		for i, item := range c.attachments {
+			if stringAttr(item, "accountId") == stringAttr(params.Item, "accountId") && stringAttr(item, "attachmentId") == stringAttr(params.Item, "attachmentId") {
+				c.attachments[i] = params.Item
+				return &dynamodb.PutItemOutput{}, nil
+			}
+		}
+		c.attachments = append(c.attachments, params.Item)
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *policyStoreTables) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	if aws.ToString(params.TableName) != c.cfg.AccountsTableName || aws.ToString(params.ConditionExpression) != "policyStoreId = :old" {
+		return &dynamodb.UpdateItemOutput{}, nil
+	}
+	account := c.accounts[stringAttr(params.Key, "accountId")]
+	if c.concurrentStore != "" {
+		account["policyStoreId"] = &types.AttributeValueMemberS{Value: c.concurrentStore}
+	}
+	values := params.ExpressionAttributeValues
+	if stringAttr(account, "policyStoreId") != stringAttr(values, ":old") {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	account["policyStoreId"] = values[":new"]
+	account["schemaVersion"] = values[":version"]
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+// memoryPolicyStore is one policy store of a policyStoreAVP
+type memoryPolicyStore struct {
+	templates []memoryTemplate
+	policies  []memoryPolicy
+}
+
+type memoryTemplate struct {
+	id, statement, description string
+}
+
+type memoryPolicy struct {
+	id, templateID string
+	principal      *avptypes.EntityIdentifier
+}
+
+// policyStoreAVP is an in-memory AVP holding policy templates and
+// template-linked policies. failCreatePolicy fails that CreatePolicy call,
+// counting from 1.
+type policyStoreAVP struct {
+	benchAVP
+	stores            map[string]*memoryPolicyStore
+	storeCount        int
+	idCount           int
+	deleted           []string
+	createPolicyCalls int
+	failCreatePolicy  int
+}
+
+func newPolicyStoreAVP(policyStoreIDs ...string) *policyStoreAVP {
+	avp := &policyStoreAVP{stores: map[string]*memoryPolicyStore{}}
+	for _, id := range policyStoreIDs {
+		avp.stores[id] = &memoryPolicyStore{}
+		avp.storeCount++
+	}
+	return avp
+}
+
+func (a *policyStoreAVP) nextID(prefix string) string {
+	a.idCount++
+	return fmt.Sprintf("%s-%d", prefix, a.idCount)
+}
+
+func (a *policyStoreAVP) store(policyStoreID *string) (*memoryPolicyStore, error) {
+	ps, ok := a.stores[aws.ToString(policyStoreID)]
+	if !ok {
+		return nil, &avptypes.ResourceNotFoundException{Message: aws.String("policy store not found")}
+	}
+	return ps, nil
+}
+
+func (a *policyStoreAVP) CreatePolicyStore(ctx context.Context, params *verifiedpermissions.CreatePolicyStoreInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.CreatePolicyStoreOutput, error) {
+	a.storeCount++
+	id := fmt.Sprintf("ps-%d", a.storeCount)
+	a.stores[id] = &memoryPolicyStore{}
+	return &verifiedpermissions.CreatePolicyStoreOutput{PolicyStoreId: aws.String(id)}, nil
+}
+
+func (a *policyStoreAVP) PutSchema(ctx context.Context, params *verifiedpermissions.PutSchemaInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.PutSchemaOutput, error) {
+	return &verifiedpermissions.PutSchemaOutput{}, nil
+}
+
+func (a *policyStoreAVP) DeletePolicyStore(ctx context.Context, params *verifiedpermissions.DeletePolicyStoreInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.DeletePolicyStoreOutput, error) {
+	if _, err := a.store(params.PolicyStoreId); err != nil {
+		return nil, err
+	}
+	delete(a.stores, aws.ToString(params.PolicyStoreId))
+	a.deleted = append(a.deleted, aws.ToString(params.PolicyStoreId))
+	return &verifiedpermissions.DeletePolicyStoreOutput{}, nil
+}
+
+func (a *policyStoreAVP) CreatePolicyTemplate(ctx context.Context, params *verifiedpermissions.CreatePolicyTemplateInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.CreatePolicyTemplateOutput, error) {
+	ps, err := a.store(params.PolicyStoreId)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := memoryTemplate{id: a.nextID("tmpl"), statement: aws.ToString(params.Statement), description: aws.ToString(params.Description)}
+	ps.templates = append(ps.templates, tmpl)
+	return &verifiedpermissions.CreatePolicyTemplateOutput{PolicyStoreId: params.PolicyStoreId, PolicyTemplateId: aws.String(tmpl.id)}, nil
+}
+
+func (a *policyStoreAVP) ListPolicyTemplates(ctx context.Context, params *verifiedpermissions.ListPolicyTemplatesInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.ListPolicyTemplatesOutput, error) {
+	ps, err := a.store(params.PolicyStoreId)
+	if err != nil {
+		return nil, err
+	}
+	out := &verifiedpermissions.ListPolicyTemplatesOutput{}
+	for _, tmpl := range ps.templates {
+		out.PolicyTemplates = append(out.PolicyTemplates, avptypes.PolicyTemplateItem{PolicyStoreId: params.PolicyStoreId, PolicyTemplateId: aws.String(tmpl.id)})
+	}
+	return out, nil
+}
+
+func (a *policyStoreAVP) GetPolicyTemplate(ctx context.Context, params *verifiedpermissions.GetPolicyTemplateInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.GetPolicyTemplateOutput, error) {
+	ps, err := a.store(params.PolicyStoreId)
+	if err != nil {
+		return nil, err
+	}
+	for _, tmpl := range ps.templates {
+		if tmpl.id == aws.ToString(params.PolicyTemplateId) {
+			return &verifiedpermissions.GetPolicyTemplateOutput{
+				PolicyStoreId:    params.PolicyStoreId,
+				PolicyTemplateId: aws.String(tmpl.id),
+				Statement:        aws.String(tmpl.statement),
+				Description:      aws.String(tmpl.description),
+			}, nil
+		}
+	}
+	return nil, &avptypes.ResourceNotFoundException{Message: aws.String("policy template not found")}
+}
+
+func (a *policyStoreAVP) CreatePolicy(ctx context.Context, params *verifiedpermissions.CreatePolicyInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.CreatePolicyOutput, error) {
+	a.createPolicyCalls++
+	if a.createPolicyCalls == a.failCreatePolicy {
+		return nil, errors.New("throttled")
+	}
+	ps, err := a.store(params.PolicyStoreId)
+	if err != nil {
+		return nil, err
+	}
+	def := params.Definition.(*avptypes.PolicyDefinitionMemberTemplateLinked).Value
+	policy := memoryPolicy{id: a.nextID("policy"), templateID: aws.ToString(def.PolicyTemplateId), principal: def.Principal}
+	ps.policies = append(ps.policies, policy)
+	return &verifiedpermissions.CreatePolicyOutput{
+		PolicyStoreId: params.PolicyStoreId,
+		PolicyId:      aws.String(policy.id),
+		CreatedDate:   aws.Time(time.Now()),
+	}, nil
+}
+
+func (a *policyStoreAVP) ListPolicies(ctx context.Context, params *verifiedpermissions.ListPoliciesInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.ListPoliciesOutput, error) {
+	ps, err := a.store(params.PolicyStoreId)
+	if err != nil {
+		return nil, err
+	}
+	out := &verifiedpermissions.ListPoliciesOutput{}
+	for _, policy := range ps.policies {
+		if filter := params.Filter; filter != nil {
+			if filter.PolicyTemplateId != nil && aws.ToString(filter.PolicyTemplateId) != policy.templateID {
+				continue
+			}
+			if ref, ok := filter.Principal.(*avptypes.EntityReferenceMemberIdentifier); ok &&
+				(aws.ToString(ref.Value.EntityType) != aws.ToString(policy.principal.EntityType) || aws.ToString(ref.Value.EntityId) != aws.ToString(policy.principal.EntityId)) {
+				continue
+			}
+		}
+		out.Policies = append(out.Policies, avptypes.PolicyItem{
+			PolicyId:    aws.String(policy.id),
+			CreatedDate: aws.Time(time.Now()),
+			Definition: &avptypes.PolicyDefinitionItemMemberTemplateLinked{Value: avptypes.TemplateLinkedPolicyDefinitionItem{
+				PolicyTemplateId: aws.String(policy.templateID),
+				Principal:        policy.principal,
+			}},
+		})
+	}
+	return out, nil
+}
+
+func (a *policyStoreAVP) GetPolicy(ctx context.Context, params *verifiedpermissions.GetPolicyInput, optFns ...func(*verifiedpermissions.Options)) (*verifiedpermissions.GetPolicyOutput, error) {
+	ps, err := a.store(params.PolicyStoreId)
+	if err != nil {
+		return nil, err
+	}
+	for _, policy := range ps.policies {
+		if policy.id == aws.ToString(params.PolicyId) {
+			return &verifiedpermissions.GetPolicyOutput{
+				PolicyId:    aws.String(policy.id),
+				CreatedDate: aws.Time(time.Now()),
+				Definition: &avptypes.PolicyDefinitionDetailMemberTemplateLinked{Value: avptypes.TemplateLinkedPolicyDefinitionDetail{
+					PolicyTemplateId: aws.String(policy.templateID),
+					Principal:        policy.principal,
+				}},
+			}, nil
+		}
+	}
+	return nil, &avptypes.ResourceNotFoundException{Message: aws.String("policy not found")}
+}
+
+// newRebuildAuthorizer returns an authorizer over account 123456789012, whose
+// policy store ps-1 holds two policies: one attached to a named user
+// attachment and one to a group
+func newRebuildAuthorizer(t *testing.T) (*authorizerImpl, *policyStoreTables, *policyStoreAVP) {
+	t.Helper()
+	ctx := context.Background()
+	cfg := DefaultConfig()
+	tables := newPolicyStoreTables(t, cfg, &store.Account{AccountID: "123456789012", PolicyStoreID: "ps-1", SchemaVersion: 1})
+	avp := newPolicyStoreAVP("ps-1")
+	a := New(cfg, tables, avp, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	for _, name := range []string{"readers", "operators"} {
+		if _, err := avp.CreatePolicyTemplate(ctx, &verifiedpermissions.CreatePolicyTemplateInput{
+			PolicyStoreId: aws.String("ps-1"),
+			Statement:     aws.String(`permit(principal == ?principal, action, resource);`),
+			Description:   aws.String(encodePolicyMeta(name, name+" policy")),
+		}); err != nil {
+			t.Fatalf("failed to seed policy: %v", err)
+		}
+	}
+	if _, _, err := a.AttachPolicy(ctx, "123456789012", "tmpl-1", TargetTypeUser, "arn:aws:iam::123456789012:user/alice", "alice-read", ""); err != nil {
+		t.Fatalf("failed to seed attachment: %v", err)
+	}
+	if _, _, err := a.AttachPolicy(ctx, "123456789012", "tmpl-2", TargetTypeGroup, "group-ops", "", ""); err != nil {
+		t.Fatalf("failed to seed attachment: %v", err)
+	}
+	return a, tables, avp
+}
+
+func TestExportPolicyStore(t *testing.T) {
+	a, _, _ := newRebuildAuthorizer(t)
+
+	export, err := a.ExportPolicyStore(context.Background(), "123456789012")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if export.PolicyStoreID != "ps-1" || len(export.Policies) != 2 || len(export.Attachments) != 2 {
+		t.Fatalf("unexpected export %+v", export)
+	}
+	if p := export.Policies[0]; p.PolicyID != "tmpl-1" || p.Name != "readers" || p.Description != "readers policy" {
+		t.Errorf("unexpected policy %+v", p)
+	}
+	want := []ExportedAttachment{
+		{PolicyID: "tmpl-1", TargetType: TargetTypeUser, TargetID: "arn:aws:iam::123456789012:user/alice", Name: "alice-read"},
+		{PolicyID: "tmpl-2", TargetType: TargetTypeGroup, TargetID: "group-ops"},
+	}
+	for i, att := range export.Attachments {
+		if att != want[i] {
+			t.Errorf("attachment %d = %+v, want %+v", i, att, want[i])
+		}
+	}
+}
+
+func TestRebuildPolicyStore(t *testing.T) {
+	ctx := context.Background()
+	a, tables, avp := newRebuildAuthorizer(t)
+
+	result, err := a.RebuildPolicyStore(ctx, "123456789012", nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PreviousPolicyStoreID != "ps-1" || result.PolicyStoreID != "ps-2" || result.Policies != 2 || result.Attachments != 2 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	account, err := a.GetAccount(ctx, "123456789012")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if account.PolicyStoreID != "ps-2" || account.SchemaVersion != schema.Version {
+		t.Errorf("expected the account swapped to ps-2 at the current schema, got %+v", account)
+	}
+	if len(avp.deleted) != 1 || avp.deleted[0] != "ps-1" {
+		t.Errorf("expected only the previous store to be deleted, got %v", avp.deleted)
+	}
+
+	rebuilt := avp.stores["ps-2"]
+	if len(rebuilt.templates) != 2 || len(rebuilt.policies) != 2 {
+		t.Fatalf("unexpected rebuilt store %+v", rebuilt)
+	}
+	if got := rebuilt.policies[0].templateID; got != result.PolicyIDs["tmpl-1"] {
+		t.Errorf("attachment links %s, want the new ID of tmpl-1 %s", got, result.PolicyIDs["tmpl-1"])
+	}
+
+	// Attachment names move to the new attachment IDs
+	attachments, err := a.ListAttachments(ctx, "123456789012", AttachmentFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attachments[0].Name != "alice-read" || attachments[0].AttachmentID != rebuilt.policies[0].id {
+		t.Errorf("unexpected restored attachment %+v", attachments[0])
+	}
+	if len(tables.attachments) != 2 {
+		t.Errorf("expected the restored attachment's metadata to be written, got %d items", len(tables.attachments))
+	}
+}
+
+func TestRebuildPolicyStore_FailsPartWay(t *testing.T) {
+	ctx := context.Background()
+	a, tables, avp := newRebuildAuthorizer(t)
+	// Two calls seeded the store; fail the second attachment of the rebuild
+	avp.failCreatePolicy = avp.createPolicyCalls + 2
+
+	if _, err := a.RebuildPolicyStore(ctx, "123456789012", nil); err == nil {
+		t.Fatal("expected the rebuild to fail")
+	}
+
+	if got := stringAttr(tables.accounts["123456789012"], "policyStoreId"); got != "ps-1" {
+		t.Errorf("expected the account to keep ps-1, got %s", got)
+	}
+	if len(avp.deleted) != 1 || avp.deleted[0] != "ps-2" {
+		t.Errorf("expected the partly restored store to be deleted, got %v", avp.deleted)
+	}
+	if ps := avp.stores["ps-1"]; ps == nil || len(ps.policies) != 2 {
+		t.Errorf("expected ps-1 to be left intact, got %+v", ps)
+	}
+}
+
+func TestRebuildPolicyStore_SwapConflict(t *testing.T) {
+	ctx := context.Background()
+	a, tables, avp := newRebuildAuthorizer(t)
+	tables.concurrentStore = "ps-other"
+
+	_, err := a.RebuildPolicyStore(ctx, "123456789012", nil)
+	if !errors.Is(err, store.ErrPolicyStoreChanged) {
+		t.Fatalf("expected ErrPolicyStoreChanged, got %v", err)
+	}
+
+	if got := stringAttr(tables.accounts["123456789012"], "policyStoreId"); got != "ps-other" {
+		t.Errorf("expected the concurrent rebuild's store to stay, got %s", got)
+	}
+	if len(avp.deleted) != 1 || avp.deleted[0] != "ps-2" {
+		t.Errorf("expected only this rebuild's store to be deleted, got %v", avp.deleted)
+	}
+}
+
+func TestRebuildPolicyStore_InvalidExport(t *testing.T) {
+	a, _, avp := newRebuildAuthorizer(t)
+
+	_, err := a.RebuildPolicyStore(context.Background(), "123456789012", &PolicyStoreExport{AccountID: "210987654321"})
+	if err == nil {
+		t.Fatal("expected an export of another account to be rejected")
+	}
+	if avp.storeCount != 1 {
+		t.Errorf("expected no store to be created, got %d", avp.storeCount)
+	}
+}
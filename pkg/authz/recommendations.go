@@ -0,0 +1,171 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// Recommendation types
+const (
+	// RecommendUnusedPolicy flags a policy none of whose attachments
+	// determined a decision within the unused window
+	RecommendUnusedPolicy = "unused-policy"
+	// RecommendUnattachedPolicy flags a policy with no attachments
+	RecommendUnattachedPolicy = "unattached-policy"
+	// RecommendEmptyGroup flags a group with no members
+	RecommendEmptyGroup = "empty-group"
+	// RecommendUnattachedGroup flags a group no policy is attached to
+	RecommendUnattachedGroup = "unattached-group"
+)
+
+// Recommendation is a policy or group an admin may want to prune
+type Recommendation struct {
+	Type         string `json:"type"`
+	ResourceType string `json:"resourceType"`
+	ResourceID   string `json:"resourceId"`
+	Name         string `json:"name"`
+	Reason       string `json:"reason"`
+	// LastMatchedAt is when an unused policy last determined a decision,
+	// empty when it has not within the decision retention
+	LastMatchedAt string `json:"lastMatchedAt,omitempty"`
+}
+
+// Recommendations are the pruning recommendations of an account
+type Recommendations struct {
+	// PolicyUsage is true when decision analytics is enabled, so unused
+	// policies could be looked for
+	PolicyUsage bool              `json:"policyUsage"`
+	Items       []*Recommendation `json:"items"`
+}
+
+// GetRecommendations flags the account's policies and groups that look
+// safe to prune: policies without attachments, groups without members or
+// attachments and, with decision analytics, policies that have not
+// determined a decision within unusedFor. Policies created or attached
+// within unusedFor are not flagged as unused, since they have not had the
+// chance to be. Recommendations are ordered by type, then name.
+func (a *authorizerImpl) GetRecommendations(ctx context.Context, accountID string, unusedFor time.Duration) (*Recommendations, error) {
+	policies, err := a.ListPolicies(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := a.ListAttachments(ctx, accountID, AttachmentFilter{})
+	if err != nil {
+		return nil, err
+	}
+	groups, err := a.ListGroups(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	members := make(map[string]int, len(groups))
+	for _, g := range groups {
+		groupMembers, err := a.ListGroupMembers(ctx, accountID, g.GroupID)
+		if err != nil {
+			return nil, err
+		}
+		members[g.GroupID] = len(groupMembers)
+	}
+
+	// Policy usage is recorded per AVP policy, which for an attachment is
+	// its attachment ID
+	var lastMatched map[string]string
+	if a.analytics != nil {
+		usage, err := a.analytics.store.ListPolicyUsage(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		lastMatched = make(map[string]string, len(usage))
+		for _, u := range usage {
+			lastMatched[u.PolicyID] = u.LastMatchedAt
+		}
+	}
+
+	return recommend(policies, attachments, groups, members, lastMatched, a.now().UTC().Add(-unusedFor), unusedFor), nil
+}
+
+// recommend builds the recommendations of an account from its policies,
+// attachments, groups and their member counts. lastMatched maps AVP policy
+// IDs to when they last determined a decision, and is nil without decision
+// analytics; policies whose newest match, creation and attachment are all
+// before cutoff are unused.
+func recommend(policies []*store.Policy, attachments []*Attachment, groups []*store.Group, members map[string]int, lastMatched map[string]string, cutoffTime time.Time, unusedFor time.Duration) *Recommendations {
+	byPolicy := map[string][]*Attachment{}
+	attachedGroups := map[string]bool{}
+	for _, att := range attachments {
+		byPolicy[att.PolicyID] = append(byPolicy[att.PolicyID], att)
+		if att.TargetType == TargetTypeGroup {
+			attachedGroups[att.TargetID] = true
+		}
+	}
+
+	recs := &Recommendations{PolicyUsage: lastMatched != nil, Items: []*Recommendation{}}
+	cutoff := cutoffTime.UTC().Format(time.RFC3339)
+	for _, p := range policies {
+		atts := byPolicy[p.PolicyID]
+		if len(atts) == 0 {
+			recs.Items = append(recs.Items, &Recommendation{
+				Type:         RecommendUnattachedPolicy,
+				ResourceType: "policy",
+				ResourceID:   p.PolicyID,
+				Name:         p.Name,
+				Reason:       "The policy is not attached to any user or group",
+			})
+			continue
+		}
+		if lastMatched == nil {
+			continue
+		}
+
+		// Times are RFC3339 UTC, so they compare as strings
+		newest, matched := p.CreatedAt, ""
+		for _, att := range atts {
+			newest = max(newest, att.CreatedAt)
+			matched = max(matched, lastMatched[att.AttachmentID])
+		}
+		if newest > cutoff || matched > cutoff {
+			continue
+		}
+		reason := fmt.Sprintf("No attachment of the policy determined an authorization decision in the last %s", unusedFor)
+		recs.Items = append(recs.Items, &Recommendation{
+			Type:          RecommendUnusedPolicy,
+			ResourceType:  "policy",
+			ResourceID:    p.PolicyID,
+			Name:          p.Name,
+			Reason:        reason,
+			LastMatchedAt: matched,
+		})
+	}
+
+	for _, g := range groups {
+		if members[g.GroupID] == 0 {
+			recs.Items = append(recs.Items, &Recommendation{
+				Type:         RecommendEmptyGroup,
+				ResourceType: "group",
+				ResourceID:   g.GroupID,
+				Name:         g.Name,
+				Reason:       "The group has no members",
+			})
+		}
+		if !attachedGroups[g.GroupID] {
+			recs.Items = append(recs.Items, &Recommendation{
+				Type:         RecommendUnattachedGroup,
+				ResourceType: "group",
+				ResourceID:   g.GroupID,
+				Name:         g.Name,
+				Reason:       "No policy is attached to the group",
+			})
+		}
+	}
+
+	sort.SliceStable(recs.Items, func(i, j int) bool {
+		if recs.Items[i].Type != recs.Items[j].Type {
+			return recs.Items[i].Type < recs.Items[j].Type
+		}
+		return recs.Items[i].Name < recs.Items[j].Name
+	})
+	return recs
+}
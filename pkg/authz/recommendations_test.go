@@ -0,0 +1,68 @@
+package authz
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func TestRecommend(t *testing.T) {
+	old, recent := "2026-01-01T00:00:00Z", "2026-10-14T00:00:00Z"
+	cutoff := time.Date(2026, 9, 15, 0, 0, 0, 0, time.UTC)
+
+	policies := []*store.Policy{
+		{PolicyID: "p-used", Name: "used", CreatedAt: old},
+		{PolicyID: "p-unused", Name: "unused", CreatedAt: old},
+		{PolicyID: "p-never", Name: "never", CreatedAt: old},
+		{PolicyID: "p-new", Name: "new", CreatedAt: recent},
+		{PolicyID: "p-loose", Name: "loose", CreatedAt: old},
+	}
+	attachments := []*Attachment{
+		{AttachmentID: "a-used", PolicyID: "p-used", TargetType: TargetTypeGroup, TargetID: "g-ops", CreatedAt: old},
+		{AttachmentID: "a-unused", PolicyID: "p-unused", TargetType: TargetTypeUser, TargetID: "arn:aws:iam::123456789012:user/alice", CreatedAt: old},
+		{AttachmentID: "a-never", PolicyID: "p-never", TargetType: TargetTypeGroup, TargetID: "g-empty", CreatedAt: old},
+		{AttachmentID: "a-new", PolicyID: "p-new", TargetType: TargetTypeUser, TargetID: "arn:aws:iam::123456789012:user/bob", CreatedAt: recent},
+	}
+	groups := []*store.Group{
+		{GroupID: "g-ops", Name: "ops"},
+		{GroupID: "g-empty", Name: "empty"},
+		{GroupID: "g-idle", Name: "idle"},
+	}
+	members := map[string]int{"g-ops": 3, "g-idle": 1}
+	lastMatched := map[string]string{"a-used": recent, "a-unused": "2026-03-01T00:00:00Z"}
+
+	recs := recommend(policies, attachments, groups, members, lastMatched, cutoff, 30*24*time.Hour)
+	if !recs.PolicyUsage {
+		t.Error("expected policy usage to be reported with decision analytics")
+	}
+
+	type finding struct{ kind, id, lastMatched string }
+	want := []finding{
+		{RecommendEmptyGroup, "g-empty", ""},
+		{RecommendUnattachedGroup, "g-idle", ""},
+		{RecommendUnattachedPolicy, "p-loose", ""},
+		{RecommendUnusedPolicy, "p-never", ""},
+		{RecommendUnusedPolicy, "p-unused", "2026-03-01T00:00:00Z"},
+	}
+	if len(recs.Items) != len(want) {
+		t.Fatalf("expected %d recommendations, got %d: %+v", len(want), len(recs.Items), recs.Items)
+	}
+	for i, w := range want {
+		got := recs.Items[i]
+		if got.Type != w.kind || got.ResourceID != w.id || got.LastMatchedAt != w.lastMatched {
+			t.Errorf("recommendation %d: expected %+v, got %+v", i, w, got)
+		}
+	}
+
+	// Without decision analytics, no policy is flagged as unused
+	recs = recommend(policies, attachments, groups, members, nil, cutoff, 30*24*time.Hour)
+	for _, r := range recs.Items {
+		if r.Type == RecommendUnusedPolicy {
+			t.Errorf("expected no unused policies without decision analytics, got %+v", r)
+		}
+	}
+	if recs.PolicyUsage {
+		t.Error("expected policy usage to be unavailable without decision analytics")
+	}
+}
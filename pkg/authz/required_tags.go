@@ -0,0 +1,42 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// SetAccountRequiredTags replaces the tag keys the account's clusters and
+// works must carry. Duplicates are dropped and an empty list removes the
+// requirement.
+func (a *authorizerImpl) SetAccountRequiredTags(ctx context.Context, accountID string, tags []string) (*store.Account, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotEnabled, accountID)
+	}
+
+	tags = slices.Compact(slices.Sorted(slices.Values(tags)))
+	if err := a.accountStore.SetRequiredTags(ctx, accountID, tags); err != nil {
+		return nil, err
+	}
+	account.RequiredTags = tags
+	return account, nil
+}
+
+// AccountRequiredTags returns the tag keys the account requires, or nil when
+// the account is not enabled
+func (a *authorizerImpl) AccountRequiredTags(ctx context.Context, accountID string) ([]string, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, nil
+	}
+	return account.RequiredTags, nil
+}
@@ -0,0 +1,27 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// SetAccountResidency pins the account to region and sets its export-control
+// classification; empty values clear them. Callers validate both.
+func (a *authorizerImpl) SetAccountResidency(ctx context.Context, accountID, region, exportControl string) (*store.Account, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotEnabled, accountID)
+	}
+
+	if err := a.accountStore.SetResidency(ctx, accountID, region, exportControl); err != nil {
+		return nil, err
+	}
+	account.PinnedRegion = region
+	account.ExportControl = exportControl
+	return account, nil
+}
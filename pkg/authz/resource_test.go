@@ -0,0 +1,90 @@
+package authz
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// TestResourceParents checks that resource parentage reaches AVP and the
+// cedar-agent mock as an entity hierarchy
+func TestResourceParents(t *testing.T) {
+	const (
+		cluster  = "arn:aws:rosa:us-east-1:123456789012:cluster/c1"
+		nodePool = "arn:aws:rosa:us-east-1:123456789012:nodepool/np1"
+		machine  = "arn:aws:rosa:us-east-1:123456789012:machine/m1"
+	)
+
+	var agentEntities []struct {
+		UID     string   `json:"uid"`
+		Parents []string `json:"parents"`
+	}
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/is_authorized" {
+			var body struct {
+				Entities json.RawMessage `json:"entities"`
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			_ = json.Unmarshal(body.Entities, &agentEntities)
+			_, _ = w.Write([]byte(`{"decision":"Allow"}`))
+		}
+	}))
+	defer agent.Close()
+
+	a := &authorizerImpl{cfg: DefaultConfig()}
+	input := a.buildAVPRequest(&AuthzRequest{
+		AccountID:       "123456789012",
+		CallerARN:       "arn:aws:iam::123456789012:user/alice",
+		Action:          "DescribeCluster",
+		Resource:        machine,
+		ResourceParents: []string{cluster, nodePool},
+	}, nil, "store-1")
+
+	avpParents := make(map[string]string)
+	for _, entity := range input.Entities.(*avptypes.EntitiesDefinitionMemberEntityList).Value {
+		if aws.ToString(entity.Identifier.EntityType) != "ROSA::Resource" {
+			continue
+		}
+		if _, ok := entity.Attributes["tags"]; !ok {
+			t.Errorf("resource entity %s has no tags attribute", aws.ToString(entity.Identifier.EntityId))
+		}
+		parent := ""
+		if len(entity.Parents) > 0 {
+			parent = aws.ToString(entity.Parents[0].EntityId)
+		}
+		avpParents[aws.ToString(entity.Identifier.EntityId)] = parent
+	}
+	want := map[string]string{machine: nodePool, nodePool: cluster, cluster: ""}
+	for id, parent := range want {
+		if got, ok := avpParents[id]; !ok || got != parent {
+			t.Errorf("AVP entity %s parent = %q, want %q", id, got, parent)
+		}
+	}
+
+	mock := client.NewMockAVPClient(agent.URL, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if _, err := mock.IsAuthorized(context.Background(), input); err != nil {
+		t.Fatalf("IsAuthorized: %v", err)
+	}
+	agentParents := make(map[string][]string)
+	for _, entity := range agentEntities {
+		agentParents[entity.UID] = entity.Parents
+	}
+	if got := agentParents[`ROSA::Resource::"`+machine+`"`]; len(got) != 1 || got[0] != `ROSA::Resource::"`+nodePool+`"` {
+		t.Errorf("cedar-agent machine parents = %v, want the nodepool", got)
+	}
+	if got := agentParents[`ROSA::Resource::"`+nodePool+`"`]; len(got) != 1 || got[0] != `ROSA::Resource::"`+cluster+`"` {
+		t.Errorf("cedar-agent nodepool parents = %v, want the cluster", got)
+	}
+	if len(agentEntities) != 3 {
+		t.Errorf("expected 3 resource entities sent to cedar-agent, got %d", len(agentEntities))
+	}
+}
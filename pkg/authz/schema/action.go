@@ -0,0 +1,40 @@
+package schema
+
+import (
+	"slices"
+	"strings"
+)
+
+// ActionPrefix is the IAM-style service prefix callers may put on action
+// names (e.g. "rosa:CreateCluster"). Schema actions never carry it.
+const ActionPrefix = "rosa:"
+
+// ActionInfo describes an action of the catalog
+type ActionInfo struct {
+	Name        string
+	Group       string
+	Description string
+	// ResourceTypes are the entity types the action applies to
+	ResourceTypes []string
+}
+
+// CanonicalAction returns the schema action ID for an action as callers name
+// it: the service prefix is dropped, in any case, and aliases are resolved.
+// Both AVP clients send canonical actions, so policies match the same way
+// locally and in production.
+func CanonicalAction(action string) string {
+	if len(action) >= len(ActionPrefix) && strings.EqualFold(action[:len(ActionPrefix)], ActionPrefix) {
+		action = action[len(ActionPrefix):]
+	}
+	if canonical, ok := actionAliases[action]; ok {
+		return canonical
+	}
+	return action
+}
+
+// IsAction reports whether action, as callers name it, is an action of the
+// schema
+func IsAction(action string) bool {
+	action = CanonicalAction(action)
+	return slices.ContainsFunc(Actions, func(a ActionInfo) bool { return a.Name == action })
+}
@@ -0,0 +1,33 @@
+package schema
+
+import "testing"
+
+func TestCanonicalAction(t *testing.T) {
+	tests := map[string]string{
+		"CreateCluster":      "CreateCluster",
+		"rosa:CreateCluster": "CreateCluster",
+		"ROSA:CreateCluster": "CreateCluster",
+		"GetCluster":         "DescribeCluster",
+		"rosa:GetNodePool":   "DescribeNodePool",
+		"ec2:RunInstances":   "ec2:RunInstances",
+		"rosa:":              "",
+	}
+	for action, want := range tests {
+		if got := CanonicalAction(action); got != want {
+			t.Errorf("CanonicalAction(%q) = %q, want %q", action, got, want)
+		}
+	}
+}
+
+func TestIsAction(t *testing.T) {
+	for _, action := range []string{"CreateCluster", "rosa:GetAccessEntry", ActionResolveSecretReference} {
+		if !IsAction(action) {
+			t.Errorf("IsAction(%q) = false, want true", action)
+		}
+	}
+	for _, action := range []string{"CreateResource", "ListAccessEntrys", ""} {
+		if IsAction(action) {
+			t.Errorf("IsAction(%q) = true, want false", action)
+		}
+	}
+}
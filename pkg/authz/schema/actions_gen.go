@@ -0,0 +1,119 @@
+// Code generated by actiongen from actions.yaml. DO NOT EDIT.
+
+package schema
+
+// Actions of the Cedar schema
+const (
+	// Cluster
+
+	// Create a cluster
+	ActionCreateCluster = "CreateCluster"
+
+	// Delete a cluster
+	ActionDeleteCluster = "DeleteCluster"
+
+	// Get a cluster
+	ActionDescribeCluster = "DescribeCluster"
+
+	// List the account's clusters
+	ActionListClusters = "ListClusters"
+
+	// Update a cluster
+	ActionUpdateCluster = "UpdateCluster"
+
+	// Update a cluster's configuration
+	ActionUpdateClusterConfig = "UpdateClusterConfig"
+
+	// Upgrade a cluster
+	ActionUpdateClusterVersion = "UpdateClusterVersion"
+
+	// NodePool
+
+	// Create a nodepool in a cluster
+	ActionCreateNodePool = "CreateNodePool"
+
+	// Delete a nodepool
+	ActionDeleteNodePool = "DeleteNodePool"
+
+	// Get a nodepool
+	ActionDescribeNodePool = "DescribeNodePool"
+
+	// List nodepools
+	ActionListNodePools = "ListNodePools"
+
+	// Update a nodepool
+	ActionUpdateNodePool = "UpdateNodePool"
+
+	// Change a nodepool's replicas
+	ActionScaleNodePool = "ScaleNodePool"
+
+	// Access Entry
+
+	// Grant a principal access to a cluster
+	ActionCreateAccessEntry = "CreateAccessEntry"
+
+	// Revoke an access entry
+	ActionDeleteAccessEntry = "DeleteAccessEntry"
+
+	// Get an access entry
+	ActionDescribeAccessEntry = "DescribeAccessEntry"
+
+	// List a cluster's access entries
+	ActionListAccessEntries = "ListAccessEntries"
+
+	// Update an access entry
+	ActionUpdateAccessEntry = "UpdateAccessEntry"
+
+	// List the access policies that can be associated with access entries
+	ActionListAccessPolicies = "ListAccessPolicies"
+
+	// Tags
+
+	// Add tags to a resource
+	ActionTagResource = "TagResource"
+
+	// Remove tags from a resource
+	ActionUntagResource = "UntagResource"
+
+	// List a resource's tags
+	ActionListTagsForResource = "ListTagsForResource"
+
+	// Work
+
+	// Resolve a Secrets Manager or SSM reference in a work manifest; the resource is the referenced ARN
+	ActionResolveSecretReference = "ResolveSecretReference"
+)
+
+// Actions is the action catalog, in the order the schema declares it
+var Actions = []ActionInfo{
+	{Name: ActionCreateCluster, Group: "Cluster", Description: "Create a cluster", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionDeleteCluster, Group: "Cluster", Description: "Delete a cluster", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionDescribeCluster, Group: "Cluster", Description: "Get a cluster", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionListClusters, Group: "Cluster", Description: "List the account's clusters", ResourceTypes: []string{"Resource"}},
+	{Name: ActionUpdateCluster, Group: "Cluster", Description: "Update a cluster", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionUpdateClusterConfig, Group: "Cluster", Description: "Update a cluster's configuration", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionUpdateClusterVersion, Group: "Cluster", Description: "Upgrade a cluster", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionCreateNodePool, Group: "NodePool", Description: "Create a nodepool in a cluster", ResourceTypes: []string{"Resource", "Cluster", "NodePool"}},
+	{Name: ActionDeleteNodePool, Group: "NodePool", Description: "Delete a nodepool", ResourceTypes: []string{"Resource", "NodePool"}},
+	{Name: ActionDescribeNodePool, Group: "NodePool", Description: "Get a nodepool", ResourceTypes: []string{"Resource", "NodePool"}},
+	{Name: ActionListNodePools, Group: "NodePool", Description: "List nodepools", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionUpdateNodePool, Group: "NodePool", Description: "Update a nodepool", ResourceTypes: []string{"Resource", "NodePool"}},
+	{Name: ActionScaleNodePool, Group: "NodePool", Description: "Change a nodepool's replicas", ResourceTypes: []string{"Resource", "NodePool"}},
+	{Name: ActionCreateAccessEntry, Group: "Access Entry", Description: "Grant a principal access to a cluster", ResourceTypes: []string{"Resource", "Cluster", "AccessEntry"}},
+	{Name: ActionDeleteAccessEntry, Group: "Access Entry", Description: "Revoke an access entry", ResourceTypes: []string{"Resource", "AccessEntry"}},
+	{Name: ActionDescribeAccessEntry, Group: "Access Entry", Description: "Get an access entry", ResourceTypes: []string{"Resource", "AccessEntry"}},
+	{Name: ActionListAccessEntries, Group: "Access Entry", Description: "List a cluster's access entries", ResourceTypes: []string{"Resource", "Cluster"}},
+	{Name: ActionUpdateAccessEntry, Group: "Access Entry", Description: "Update an access entry", ResourceTypes: []string{"Resource", "AccessEntry"}},
+	{Name: ActionListAccessPolicies, Group: "Access Entry", Description: "List the access policies that can be associated with access entries", ResourceTypes: []string{"Resource"}},
+	{Name: ActionTagResource, Group: "Tags", Description: "Add tags to a resource", ResourceTypes: []string{"Resource", "Cluster", "NodePool", "AccessEntry"}},
+	{Name: ActionUntagResource, Group: "Tags", Description: "Remove tags from a resource", ResourceTypes: []string{"Resource", "Cluster", "NodePool", "AccessEntry"}},
+	{Name: ActionListTagsForResource, Group: "Tags", Description: "List a resource's tags", ResourceTypes: []string{"Resource", "Cluster", "NodePool", "AccessEntry"}},
+	{Name: ActionResolveSecretReference, Group: "Work", Description: "Resolve a Secrets Manager or SSM reference in a work manifest; the resource is the referenced ARN", ResourceTypes: []string{"Resource"}},
+}
+
+// actionAliases maps alternative action names to the schema action they mean
+var actionAliases = map[string]string{
+	"GetCluster":     ActionDescribeCluster,
+	"GetNodePool":    ActionDescribeNodePool,
+	"GetAccessEntry": ActionDescribeAccessEntry,
+}
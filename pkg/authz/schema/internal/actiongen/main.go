@@ -0,0 +1,297 @@
+// Command actiongen generates the ROSA Cedar actions from the action catalog
+// (pkg/authz/schema/actions.yaml): the Go constants of the schema package,
+// the actions of the Cedar schema in both its forms, the CedarAction enum of
+// the OpenAPI spec and the action reference of the authz docs. With -check it
+// changes nothing and fails if any of them is out of date.
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The generated sections of the files that are otherwise edited by hand are
+// delimited by these markers
+const (
+	beginMarker = "BEGIN GENERATED ACTIONS"
+	endMarker   = "END GENERATED ACTIONS"
+	editNotice  = "edit pkg/authz/schema/actions.yaml and run make generate-actions"
+)
+
+// principalTypes are the principal types every action applies to
+var principalTypes = []string{"Principal", "Group"}
+
+var actionNamePattern = regexp.MustCompile(`^[A-Z][A-Za-z]+$`)
+
+type catalog struct {
+	Groups []group `yaml:"groups"`
+}
+
+type group struct {
+	Name    string   `yaml:"name"`
+	Comment string   `yaml:"comment"`
+	Actions []action `yaml:"actions"`
+}
+
+type action struct {
+	Name          string   `yaml:"name"`
+	Description   string   `yaml:"description"`
+	Aliases       []string `yaml:"aliases"`
+	ResourceTypes []string `yaml:"resourceTypes"`
+}
+
+// output is a file generated, wholly or in part, from the catalog
+type output struct {
+	path     string
+	generate func(c *catalog, current []byte) ([]byte, error)
+}
+
+func main() {
+	schemaDir := flag.String("schema-dir", ".", "Directory of the schema package")
+	repoRoot := flag.String("repo-root", "../../..", "Root of the repository")
+	check := flag.Bool("check", false, "Fail if a generated file is out of date instead of writing it")
+	flag.Parse()
+
+	stale, err := run(*schemaDir, *repoRoot, !*check)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "actiongen:", err)
+		os.Exit(1)
+	}
+	if *check && len(stale) > 0 {
+		fmt.Fprintf(os.Stderr, "actiongen: out of date, run make generate-actions: %s\n", strings.Join(stale, ", "))
+		os.Exit(1)
+	}
+}
+
+// run generates every output and returns those that changed, writing them
+// if write is set
+func run(schemaDir, repoRoot string, write bool) ([]string, error) {
+	c, err := load(filepath.Join(schemaDir, "actions.yaml"))
+	if err != nil {
+		return nil, err
+	}
+
+	outputs := []output{
+		{filepath.Join(schemaDir, "actions_gen.go"), goSource},
+		{filepath.Join(schemaDir, "rosa.cedarschema.json"), cedarSchemaJSON},
+		{filepath.Join(schemaDir, "rosa.cedarschema"), cedarSchema},
+		{filepath.Join(repoRoot, "openapi", "openapi.yaml"), openAPI},
+		{filepath.Join(repoRoot, "docs", "authz.md"), docs},
+	}
+
+	var stale []string
+	for _, out := range outputs {
+		current, err := os.ReadFile(out.path)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		generated, err := out.generate(c, current)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", out.path, err)
+		}
+		if bytes.Equal(current, generated) {
+			continue
+		}
+		stale = append(stale, out.path)
+		if write {
+			if err := os.WriteFile(out.path, generated, 0o644); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stale, nil
+}
+
+// load reads and checks the catalog
+func load(path string) (*catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c catalog
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	seen := map[string]bool{}
+	for _, g := range c.Groups {
+		if g.Name == "" || len(g.Actions) == 0 {
+			return nil, fmt.Errorf("%s: every group needs a name and actions", path)
+		}
+		for _, a := range g.Actions {
+			for _, name := range append([]string{a.Name}, a.Aliases...) {
+				if !actionNamePattern.MatchString(name) {
+					return nil, fmt.Errorf("%s: invalid action name %q", path, name)
+				}
+				if seen[name] {
+					return nil, fmt.Errorf("%s: action %s is declared twice", path, name)
+				}
+				seen[name] = true
+			}
+			if a.Description == "" || len(a.ResourceTypes) == 0 {
+				return nil, fmt.Errorf("%s: action %s needs a description and resource types", path, a.Name)
+			}
+		}
+	}
+	return &c, nil
+}
+
+func goSource(c *catalog, _ []byte) ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("// Code generated by actiongen from actions.yaml. DO NOT EDIT.\n\npackage schema\n\n")
+	b.WriteString("// Actions of the Cedar schema\nconst (\n")
+	for i, g := range c.Groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "\t// %s\n", g.Name)
+		for _, a := range g.Actions {
+			fmt.Fprintf(&b, "\n\t// %s\n\tAction%s = %q\n", a.Description, a.Name, a.Name)
+		}
+	}
+	b.WriteString(")\n\n")
+
+	b.WriteString("// Actions is the action catalog, in the order the schema declares it\nvar Actions = []ActionInfo{\n")
+	for _, g := range c.Groups {
+		for _, a := range g.Actions {
+			fmt.Fprintf(&b, "\t{Name: Action%s, Group: %q, Description: %q, ResourceTypes: %#v},\n", a.Name, g.Name, a.Description, a.ResourceTypes)
+		}
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("// actionAliases maps alternative action names to the schema action they mean\nvar actionAliases = map[string]string{\n")
+	for _, g := range c.Groups {
+		for _, a := range g.Actions {
+			for _, alias := range a.Aliases {
+				fmt.Fprintf(&b, "\t%q: Action%s,\n", alias, a.Name)
+			}
+		}
+	}
+	b.WriteString("}\n")
+
+	return format.Source(b.Bytes())
+}
+
+// cedarSchemaJSON replaces the actions of the JSON schema, the last member of
+// its namespace, and checks their resource types are entity types
+func cedarSchemaJSON(c *catalog, current []byte) ([]byte, error) {
+	const start = "\n    \"actions\": {\n"
+	i := bytes.Index(current, []byte(start))
+	if i < 0 {
+		return nil, fmt.Errorf("no actions object")
+	}
+
+	var b bytes.Buffer
+	b.Write(current[:i])
+	b.WriteString(start)
+	first := true
+	for _, g := range c.Groups {
+		for _, a := range g.Actions {
+			if !first {
+				b.WriteString(",\n")
+			}
+			first = false
+			fmt.Fprintf(&b, "      %q: {\n        \"appliesTo\": {\n          \"principalTypes\": %s,\n          \"resourceTypes\": %s\n        }\n      }",
+				a.Name, jsonList(principalTypes), jsonList(a.ResourceTypes))
+		}
+	}
+	b.WriteString("\n    }\n  }\n}\n")
+
+	var namespaces map[string]struct {
+		EntityTypes map[string]json.RawMessage `json:"entityTypes"`
+	}
+	if err := json.Unmarshal(b.Bytes(), &namespaces); err != nil {
+		return nil, fmt.Errorf("generated invalid JSON: %w", err)
+	}
+	for _, ns := range namespaces {
+		for _, g := range c.Groups {
+			for _, a := range g.Actions {
+				for _, t := range append(slices.Clone(principalTypes), a.ResourceTypes...) {
+					if _, ok := ns.EntityTypes[t]; !ok {
+						return nil, fmt.Errorf("action %s applies to %s, which is not an entity type", a.Name, t)
+					}
+				}
+			}
+		}
+	}
+	return b.Bytes(), nil
+}
+
+func jsonList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = fmt.Sprintf("%q", item)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+func cedarSchema(c *catalog, current []byte) ([]byte, error) {
+	var b strings.Builder
+	for i, g := range c.Groups {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "    // %s\n", g.Comment)
+		for j, a := range g.Actions {
+			if j > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "    action %s appliesTo {\n        principal: [%s],\n        resource: [%s]\n    };\n",
+				a.Name, strings.Join(principalTypes, ", "), strings.Join(a.ResourceTypes, ", "))
+		}
+	}
+	return replaceSection(current, "    // ", "", b.String())
+}
+
+func openAPI(c *catalog, current []byte) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("    CedarAction:\n      type: string\n      description: |\n")
+	b.WriteString("        A ROSA action of the Cedar schema. Requests may also name an action\n")
+	b.WriteString("        with the rosa: prefix or by an alias (GetCluster, GetNodePool and\n")
+	b.WriteString("        GetAccessEntry for the matching Describe actions).\n      enum:\n")
+	for _, g := range c.Groups {
+		for _, a := range g.Actions {
+			fmt.Fprintf(&b, "        - %s\n", a.Name)
+		}
+	}
+	return replaceSection(current, "    # ", "", b.String())
+}
+
+func docs(c *catalog, current []byte) ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("| Group | Action | Description | Resource types |\n| --- | --- | --- | --- |\n")
+	for _, g := range c.Groups {
+		for _, a := range g.Actions {
+			name := "`" + a.Name + "`"
+			for _, alias := range a.Aliases {
+				name += " (alias `" + alias + "`)"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", g.Name, name, a.Description, strings.Join(a.ResourceTypes, ", "))
+		}
+	}
+	return replaceSection(current, "<!-- ", " -->", b.String())
+}
+
+// replaceSection replaces the lines between the begin and end marker lines,
+// which are commented with prefix and suffix, with content
+func replaceSection(current []byte, prefix, suffix, content string) ([]byte, error) {
+	begin := prefix + beginMarker + ": " + editNotice + suffix + "\n"
+	end := prefix + endMarker + suffix + "\n"
+	s := string(current)
+	i := strings.Index(s, begin)
+	j := strings.Index(s, end)
+	if i < 0 || j < i {
+		return nil, fmt.Errorf("missing %q and %q marker lines", strings.TrimSpace(begin), strings.TrimSpace(end))
+	}
+	return []byte(s[:i+len(begin)] + content + s[j:]), nil
+}
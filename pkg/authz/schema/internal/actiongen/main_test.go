@@ -0,0 +1,66 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGeneratedFilesUpToDate fails when actions.yaml was edited without
+// running make generate-actions
+func TestGeneratedFilesUpToDate(t *testing.T) {
+	stale, err := run("../..", "../../../../..", false)
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if len(stale) > 0 {
+		t.Errorf("out of date, run make generate-actions: %s", strings.Join(stale, ", "))
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"duplicate action": `
+groups:
+  - name: Cluster
+    actions:
+      - {name: CreateCluster, description: Create, resourceTypes: [Resource]}
+      - {name: CreateCluster, description: Create, resourceTypes: [Resource]}`,
+		"alias of another action": `
+groups:
+  - name: Cluster
+    actions:
+      - {name: DescribeCluster, description: Get, aliases: [ListClusters], resourceTypes: [Resource]}
+      - {name: ListClusters, description: List, resourceTypes: [Resource]}`,
+		"missing resource types": `
+groups:
+  - name: Cluster
+    actions:
+      - {name: CreateCluster, description: Create}`,
+		"invalid name": `
+groups:
+  - name: Cluster
+    actions:
+      - {name: "rosa:CreateCluster", description: Create, resourceTypes: [Resource]}`,
+	}
+	for name, catalog := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "actions.yaml")
+			if err := os.WriteFile(path, []byte(catalog), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := load(path); err == nil {
+				t.Error("expected the catalog to be rejected")
+			}
+		})
+	}
+}
+
+func TestCedarSchemaJSON_UnknownResourceType(t *testing.T) {
+	c := &catalog{Groups: []group{{Name: "Work", Actions: []action{{Name: "ApplyWork", Description: "Apply", ResourceTypes: []string{"Work"}}}}}}
+	current := []byte("{\n  \"ROSA\": {\n    \"entityTypes\": {\n      \"Principal\": {},\n      \"Group\": {}\n    },\n    \"actions\": {\n    }\n  }\n}\n")
+	if _, err := cedarSchemaJSON(c, current); err == nil {
+		t.Error("expected an action applying to an undeclared entity type to be rejected")
+	}
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultNamespace is the namespace the embedded schema is written in
+const DefaultNamespace = "ROSA"
+
+// Namespace is the Cedar namespace of the entity and action types the
+// authorizer sends to AVP. The embedded schema is renamed into it, so forks
+// or staged schema migrations can use their own namespace.
+type Namespace string
+
+const cedarPath = `[A-Za-z_][A-Za-z0-9_]*(?:::[A-Za-z_][A-Za-z0-9_]*)*`
+
+var (
+	namespaceName = regexp.MustCompile(`^` + cedarPath + `$`)
+	// entityReference matches the type of an entity UID (Ns::Type::"id") or
+	// of an is test (is Ns::Type)
+	entityReference = regexp.MustCompile(`(` + cedarPath + `)::"|\bis\s+(` + cedarPath + `)`)
+)
+
+// Validate checks that n is a valid Cedar namespace path
+func (n Namespace) Validate() error {
+	if !namespaceName.MatchString(string(n)) {
+		return fmt.Errorf("invalid Cedar namespace %q", n)
+	}
+	return nil
+}
+
+// Type returns the fully qualified name of an entity or action type
+func (n Namespace) Type(name string) string {
+	return string(n) + "::" + name
+}
+
+// Principal is the entity type of callers
+func (n Namespace) Principal() string { return n.Type("Principal") }
+
+// Group is the entity type of authorization groups
+func (n Namespace) Group() string { return n.Type("Group") }
+
+// Resource is the entity type of resources
+func (n Namespace) Resource() string { return n.Type("Resource") }
+
+// Action is the action type
+func (n Namespace) Action() string { return n.Type("Action") }
+
+// SchemaJSON returns CedarSchemaJSON with its namespace renamed to n
+func (n Namespace) SchemaJSON() (string, error) {
+	if n == DefaultNamespace {
+		return CedarSchemaJSON, nil
+	}
+	var namespaces map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(CedarSchemaJSON), &namespaces); err != nil {
+		return "", fmt.Errorf("failed to parse embedded schema: %w", err)
+	}
+	definition, ok := namespaces[DefaultNamespace]
+	if !ok {
+		return "", fmt.Errorf("embedded schema has no %s namespace", DefaultNamespace)
+	}
+	out, err := json.Marshal(map[string]json.RawMessage{string(n): definition})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
+// CheckPolicy checks that every namespaced entity or action type a policy
+// names is in n. Unqualified types are left to AVP schema validation.
+func (n Namespace) CheckPolicy(statement string) error {
+	for _, m := range entityReference.FindAllStringSubmatch(statement, -1) {
+		typeName := m[1]
+		if typeName == "" {
+			typeName = m[2]
+		}
+		i := strings.LastIndex(typeName, "::")
+		if i < 0 {
+			continue
+		}
+		if ns := typeName[:i]; ns != string(n) {
+			return fmt.Errorf("policy references %s, outside the active namespace %s", typeName, n)
+		}
+	}
+	return nil
+}
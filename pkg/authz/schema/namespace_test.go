@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNamespace_CheckPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace Namespace
+		policy    string
+		wantErr   bool
+	}{
+		{
+			name:      "default namespace",
+			namespace: DefaultNamespace,
+			policy:    `permit(?principal, action == ROSA::Action::"DescribeCluster", resource is ROSA::Cluster);`,
+		},
+		{
+			name:      "no entity references",
+			namespace: "Staging::ROSA",
+			policy:    `permit(?principal, action, resource) when { context.region == "us-east-1" };`,
+		},
+		{
+			name:      "nested namespace",
+			namespace: "Staging::ROSA",
+			policy:    `permit(?principal, action in [Staging::ROSA::Action::"ListClusters"], resource);`,
+		},
+		{
+			name:      "action from another namespace",
+			namespace: "Staging::ROSA",
+			policy:    `permit(?principal, action == ROSA::Action::"ListClusters", resource);`,
+			wantErr:   true,
+		},
+		{
+			name:      "is test from another namespace",
+			namespace: DefaultNamespace,
+			policy:    `forbid(?principal, action, resource is Other::Cluster);`,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.namespace.CheckPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNamespace_SchemaJSON(t *testing.T) {
+	out, err := Namespace("Staging::ROSA").SchemaJSON()
+	if err != nil {
+		t.Fatalf("SchemaJSON: %v", err)
+	}
+	var namespaces map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(out), &namespaces); err != nil {
+		t.Fatalf("renamed schema is not JSON: %v", err)
+	}
+	if _, ok := namespaces["Staging::ROSA"]; !ok || len(namespaces) != 1 {
+		t.Errorf("expected only the Staging::ROSA namespace, got %d namespaces", len(namespaces))
+	}
+}
+
+func TestNamespace_Validate(t *testing.T) {
+	for _, ns := range []Namespace{"ROSA", "Staging::ROSA_v2"} {
+		if err := ns.Validate(); err != nil {
+			t.Errorf("Validate(%q): %v", ns, err)
+		}
+	}
+	for _, ns := range []Namespace{"", "ROSA::", "2ROSA", "ROSA Staging"} {
+		if err := ns.Validate(); err == nil {
+			t.Errorf("Validate(%q): expected error", ns)
+		}
+	}
+}
@@ -1,7 +1,18 @@
 package schema
 
+//go:generate go run ./internal/actiongen
+
 import _ "embed"
 
+// Version is the version of the embedded schema. Policy stores record the
+// version they were given, so features that need a newer schema are only
+// used on stores that have it.
+const Version = 2
+
+// PrincipalKindsVersion is the schema version that added the kind, path and
+// name attributes of principals
+const PrincipalKindsVersion = 2
+
 // CedarSchema is the Cedar schema for ROSA authorization
 // This schema defines the entity types and actions for the ROSA authorization model
 //
@@ -0,0 +1,83 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+)
+
+// SchemaMigration is the outcome of migrating an account's policy store to
+// the current schema
+type SchemaMigration struct {
+	AccountID     string `json:"accountId"`
+	PolicyStoreID string `json:"policyStoreId"`
+	FromVersion   int    `json:"fromVersion"`
+	ToVersion     int    `json:"toVersion"`
+	// Migrated is false when the store already had the current schema
+	Migrated bool `json:"migrated"`
+}
+
+// StoreSchemaVersion returns the schema version of a policy store; stores
+// created before versions were recorded have version 1
+func StoreSchemaVersion(recorded int) int {
+	return max(recorded, 1)
+}
+
+// MigrateSchema puts the current schema in the account's policy store and
+// records its version, so features of the newer schema, such as principal
+// kinds, apply to the account from its next request. The schema only adds
+// optional attributes, so existing policies and principal entities stay
+// valid. Stores already at the current version are left alone.
+func (a *authorizerImpl) MigrateSchema(ctx context.Context, accountID string) (*SchemaMigration, error) {
+	account, err := a.accountStore.Get(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account: %w", err)
+	}
+	if account == nil {
+		return nil, fmt.Errorf("account not found: %s", accountID)
+	}
+	if account.Privileged {
+		return nil, fmt.Errorf("account is privileged and has no policy store: %s", accountID)
+	}
+
+	migration := &SchemaMigration{
+		AccountID:     accountID,
+		PolicyStoreID: account.PolicyStoreID,
+		FromVersion:   StoreSchemaVersion(account.SchemaVersion),
+		ToVersion:     schema.Version,
+	}
+	if migration.FromVersion >= schema.Version {
+		migration.ToVersion = migration.FromVersion
+		return migration, nil
+	}
+
+	cedarSchema, err := a.namespace().SchemaJSON()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := a.avpClient.PutSchema(ctx, &verifiedpermissions.PutSchemaInput{
+		PolicyStoreId: aws.String(account.PolicyStoreID),
+		Definition: &avptypes.SchemaDefinitionMemberCedarJson{
+			Value: cedarSchema,
+		},
+	}); err != nil {
+		return nil, fmt.Errorf("failed to put policy store schema: %w", err)
+	}
+	if err := a.accountStore.SetSchemaVersion(ctx, accountID, account.PolicyStoreID, schema.Version); err != nil {
+		return nil, err
+	}
+
+	a.logger.Info("policy store schema migrated",
+		"account_id", accountID,
+		"policy_store_id", account.PolicyStoreID,
+		"from_version", migration.FromVersion,
+		"to_version", migration.ToVersion,
+	)
+	migration.Migrated = true
+	return migration, nil
+}
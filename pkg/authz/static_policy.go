@@ -0,0 +1,92 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// createStaticPolicy adds a static Cedar policy to a policy store
+func (a *authorizerImpl) createStaticPolicy(ctx context.Context, policyStoreID, description, cedarPolicy string) (*store.StaticPolicy, error) {
+	if err := a.ValidatePolicy(cedarPolicy); err != nil {
+		return nil, err
+	}
+
+	resp, err := a.avpClient.CreatePolicy(ctx, &verifiedpermissions.CreatePolicyInput{
+		PolicyStoreId: aws.String(policyStoreID),
+		Definition: &avptypes.PolicyDefinitionMemberStatic{
+			Value: avptypes.StaticPolicyDefinition{
+				Statement:   aws.String(cedarPolicy),
+				Description: aws.String(description),
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create policy: %w", err)
+	}
+
+	return &store.StaticPolicy{
+		PolicyID:    aws.ToString(resp.PolicyId),
+		Description: description,
+		CedarPolicy: cedarPolicy,
+		CreatedAt:   clock.Format(*resp.CreatedDate),
+	}, nil
+}
+
+// listStaticPolicies returns the static policies in a policy store
+func (a *authorizerImpl) listStaticPolicies(ctx context.Context, policyStoreID string) ([]*store.StaticPolicy, error) {
+	resp, err := a.avpClient.ListPolicies(ctx, &verifiedpermissions.ListPoliciesInput{
+		PolicyStoreId: aws.String(policyStoreID),
+		Filter: &avptypes.PolicyFilter{
+			PolicyType: avptypes.PolicyTypeStatic,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	policies := make([]*store.StaticPolicy, 0, len(resp.Policies))
+	for _, item := range resp.Policies {
+		policyID := aws.ToString(item.PolicyId)
+
+		// Fetch the full policy to get the statement
+		detail, err := a.avpClient.GetPolicy(ctx, &verifiedpermissions.GetPolicyInput{
+			PolicyStoreId: aws.String(policyStoreID),
+			PolicyId:      aws.String(policyID),
+		})
+		if err != nil {
+			a.logger.Warn("failed to get policy detail", "error", err, "policy_id", policyID)
+			continue
+		}
+
+		policy := &store.StaticPolicy{
+			PolicyID:  policyID,
+			CreatedAt: clock.Format(*detail.CreatedDate),
+		}
+		if def, ok := detail.Definition.(*avptypes.PolicyDefinitionDetailMemberStatic); ok {
+			policy.Description = aws.ToString(def.Value.Description)
+			policy.CedarPolicy = aws.ToString(def.Value.Statement)
+		}
+		policies = append(policies, policy)
+	}
+
+	return policies, nil
+}
+
+// deleteStaticPolicy removes a policy from a policy store
+func (a *authorizerImpl) deleteStaticPolicy(ctx context.Context, policyStoreID, policyID string) error {
+	_, err := a.avpClient.DeletePolicy(ctx, &verifiedpermissions.DeletePolicyInput{
+		PolicyStoreId: aws.String(policyStoreID),
+		PolicyId:      aws.String(policyID),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete policy: %w", err)
+	}
+	return nil
+}
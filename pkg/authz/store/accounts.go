@@ -2,8 +2,12 @@ package store
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,6 +16,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
 )
 
 // Account represents an enabled account in the authorization system
@@ -19,8 +24,101 @@ type Account struct {
 	AccountID     string `dynamodbav:"accountId" json:"accountId"`
 	PolicyStoreID string `dynamodbav:"policyStoreId,omitempty" json:"policyStoreId,omitempty"`
 	Privileged    bool   `dynamodbav:"privileged" json:"privileged"`
-	CreatedAt     string `dynamodbav:"createdAt" json:"createdAt"`
-	CreatedBy     string `dynamodbav:"createdBy" json:"createdBy"`
+	// OrganizationID is the organization whose policies also apply to the account
+	OrganizationID string `dynamodbav:"organizationId,omitempty" json:"organizationId,omitempty"`
+	// RequiredTags are tag keys every cluster and work the account creates
+	// must carry, on top of the platform-wide ones
+	RequiredTags []string `dynamodbav:"requiredTags,omitempty" json:"requiredTags,omitempty"`
+	// ChangeReview stages the account's authz mutations as change requests
+	// that take effect only once another admin approves them
+	ChangeReview bool `dynamodbav:"changeReview,omitempty" json:"changeReview,omitempty"`
+	// Plan is the plan tier limiting the account; empty is the default plan
+	Plan string `dynamodbav:"plan,omitempty" json:"plan,omitempty"`
+	// PinnedRegion is the AWS region the account's data must stay in; empty
+	// leaves it unpinned
+	PinnedRegion string `dynamodbav:"pinnedRegion,omitempty" json:"pinnedRegion,omitempty"`
+	// ExportControl is the account's export-control classification, such as
+	// EAR99 or an ECCN, for compliance tooling
+	ExportControl string `dynamodbav:"exportControl,omitempty" json:"exportControl,omitempty"`
+	// SchemaVersion is the version of the Cedar schema the account's policy
+	// store was given; zero for stores created before versions were recorded,
+	// which have version 1
+	SchemaVersion int `dynamodbav:"schemaVersion,omitempty" json:"schemaVersion,omitempty"`
+	// Onboarding is the progress of enabling the account; nil for accounts
+	// enabled before it was tracked, which are onboarded
+	Onboarding *Onboarding `dynamodbav:"onboarding,omitempty" json:"onboarding,omitempty"`
+	CreatedAt  string      `dynamodbav:"createdAt" json:"createdAt"`
+	CreatedBy  string      `dynamodbav:"createdBy" json:"createdBy"`
+}
+
+// OnboardingState is a state of an account's onboarding
+type OnboardingState string
+
+// Onboarding states, in the order an account goes through them. An
+// account that fails a step is Failed until onboarding is retried from it.
+const (
+	OnboardingPending           OnboardingState = "Pending"
+	OnboardingProvisioningStore OnboardingState = "ProvisioningStore"
+	OnboardingSeedingDefaults   OnboardingState = "SeedingDefaults"
+	OnboardingReady             OnboardingState = "Ready"
+	OnboardingFailed            OnboardingState = "Failed"
+)
+
+// Onboarding is the progress of enabling an account
+type Onboarding struct {
+	State OnboardingState `dynamodbav:"state" json:"state"`
+	// FailedStep and Error are the step a Failed onboarding stopped at and
+	// why
+	FailedStep OnboardingState `dynamodbav:"failedStep,omitempty" json:"failedStep,omitempty"`
+	Error      string          `dynamodbav:"error,omitempty" json:"error,omitempty"`
+	// UpdatedAt is when the onboarding last changed state, an RFC3339 UTC
+	// time with nanoseconds that also guards concurrent changes
+	UpdatedAt string `dynamodbav:"updatedAt" json:"updatedAt"`
+}
+
+// OnboardingStatus returns the account's onboarding, which is Ready for
+// accounts enabled before it was tracked
+func (a *Account) OnboardingStatus() *Onboarding {
+	if a.Onboarding == nil {
+		return &Onboarding{State: OnboardingReady, UpdatedAt: a.CreatedAt}
+	}
+	return a.Onboarding
+}
+
+// Onboarded reports whether the account finished onboarding
+func (a *Account) Onboarded() bool {
+	return a.OnboardingStatus().State == OnboardingReady
+}
+
+// ErrOnboardingChanged is returned when an account's onboarding changed
+// since it was read, typically because another request is onboarding it
+var ErrOnboardingChanged = errors.New("account onboarding changed concurrently")
+
+// ErrPolicyStoreChanged is returned when an account's policy store changed
+// since it was read, typically because of a concurrent rebuild
+var ErrPolicyStoreChanged = errors.New("account policy store changed concurrently")
+
+// ErrInvalidPageToken is returned when a page token was not produced by a
+// previous ListPage call
+var ErrInvalidPageToken = errors.New("invalid page token")
+
+// AccountFilter narrows an account listing. Zero values match every account.
+type AccountFilter struct {
+	// Privileged, when set, matches only privileged or only tenant accounts
+	Privileged *bool
+	// CreatedAfter is an RFC3339 UTC time; only accounts created after it match
+	CreatedAfter string
+	// PinnedRegion, when set, matches only accounts pinned to that region
+	PinnedRegion string
+	// ExportControl, when set, matches only accounts with that classification
+	ExportControl string
+}
+
+// AccountPage is one page of an account listing
+type AccountPage struct {
+	Accounts []*Account
+	// NextToken resumes the listing after this page; empty on the last page
+	NextToken string
 }
 
 // AccountStore provides CRUD operations for accounts
@@ -28,6 +126,7 @@ type AccountStore struct {
 	tableName    string
 	dynamoClient client.DynamoDBClient
 	logger       *slog.Logger
+	now          clock.Clock
 }
 
 // NewAccountStore creates a new account store
@@ -36,13 +135,23 @@ func NewAccountStore(tableName string, dynamoClient client.DynamoDBClient, logge
 		tableName:    tableName,
 		dynamoClient: dynamoClient,
 		logger:       logger,
+		now:          time.Now,
 	}
 }
 
+// WithClock sets the clock the store stamps times with
+func (s *AccountStore) WithClock(now clock.Clock) *AccountStore {
+	s.now = clock.Or(now)
+	return s
+}
+
 // Create creates a new account entry
 func (s *AccountStore) Create(ctx context.Context, account *Account) error {
 	if account.CreatedAt == "" {
-		account.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+		account.CreatedAt = clock.Format(s.now())
+	}
+	if account.Onboarding != nil && account.Onboarding.UpdatedAt == "" {
+		account.Onboarding.UpdatedAt = s.now().UTC().Format(time.RFC3339Nano)
 	}
 
 	item, err := attributevalue.MarshalMap(account)
@@ -109,23 +218,151 @@ func (s *AccountStore) Delete(ctx context.Context, accountID string) error {
 
 // List returns all accounts
 func (s *AccountStore) List(ctx context.Context) ([]*Account, error) {
-	result, err := s.dynamoClient.Scan(ctx, &dynamodb.ScanInput{
-		TableName: aws.String(s.tableName),
-	})
+	var accounts []*Account
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Scan(ctx, &dynamodb.ScanInput{
+			TableName:         aws.String(s.tableName),
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var account Account
+			if err := attributevalue.UnmarshalMap(item, &account); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal account: %w", err)
+			}
+			accounts = append(accounts, &account)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return accounts, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// ListPage returns up to limit accounts matching filter, starting after the
+// account that pageToken points to. DynamoDB applies filters after reading,
+// so a scan page may hold fewer matches than limit; ListPage keeps scanning
+// until the page is full or the table is exhausted.
+func (s *AccountStore) ListPage(ctx context.Context, limit int, pageToken string, filter AccountFilter) (*AccountPage, error) {
+	startKey, err := decodeAccountPageToken(pageToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list accounts: %w", err)
+		return nil, err
 	}
 
-	accounts := make([]*Account, 0, len(result.Items))
-	for _, item := range result.Items {
-		var account Account
-		if err := attributevalue.UnmarshalMap(item, &account); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal account: %w", err)
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(s.tableName),
+		Limit:     aws.Int32(int32(limit)),
+	}
+	filter.apply(input)
+
+	page := &AccountPage{}
+	for {
+		input.ExclusiveStartKey = startKey
+		result, err := s.dynamoClient.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list accounts: %w", err)
+		}
+
+		for i, item := range result.Items {
+			var account Account
+			if err := attributevalue.UnmarshalMap(item, &account); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal account: %w", err)
+			}
+			page.Accounts = append(page.Accounts, &account)
+
+			// Resume after the last returned account rather than at the
+			// scan's own position, which may be past unreturned matches
+			if len(page.Accounts) == limit {
+				if i < len(result.Items)-1 || len(result.LastEvaluatedKey) > 0 {
+					page.NextToken = encodeAccountPageToken(account.AccountID)
+				}
+				return page, nil
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return page, nil
 		}
-		accounts = append(accounts, &account)
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// Count returns the number of accounts matching filter
+func (s *AccountStore) Count(ctx context.Context, filter AccountFilter) (int, error) {
+	input := &dynamodb.ScanInput{
+		TableName: aws.String(s.tableName),
+		Select:    types.SelectCount,
 	}
+	filter.apply(input)
 
-	return accounts, nil
+	count := 0
+	for {
+		result, err := s.dynamoClient.Scan(ctx, input)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count accounts: %w", err)
+		}
+		count += int(result.Count)
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return count, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// apply sets the filter expression for f on a scan
+func (f AccountFilter) apply(input *dynamodb.ScanInput) {
+	var filterParts []string
+	exprValues := map[string]types.AttributeValue{}
+
+	if f.Privileged != nil {
+		filterParts = append(filterParts, "privileged = :priv")
+		exprValues[":priv"] = &types.AttributeValueMemberBOOL{Value: *f.Privileged}
+	}
+	if f.CreatedAfter != "" {
+		filterParts = append(filterParts, "createdAt > :after")
+		exprValues[":after"] = &types.AttributeValueMemberS{Value: f.CreatedAfter}
+	}
+	if f.PinnedRegion != "" {
+		filterParts = append(filterParts, "pinnedRegion = :region")
+		exprValues[":region"] = &types.AttributeValueMemberS{Value: f.PinnedRegion}
+	}
+	if f.ExportControl != "" {
+		filterParts = append(filterParts, "exportControl = :export")
+		exprValues[":export"] = &types.AttributeValueMemberS{Value: f.ExportControl}
+	}
+
+	if len(filterParts) == 0 {
+		return
+	}
+	input.FilterExpression = aws.String(strings.Join(filterParts, " AND "))
+	input.ExpressionAttributeValues = exprValues
+}
+
+// encodeAccountPageToken makes an opaque page token that resumes a listing
+// after accountID
+func encodeAccountPageToken(accountID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(accountID))
+}
+
+// decodeAccountPageToken returns the scan start key for a page token, or nil
+// for an empty token
+func decodeAccountPageToken(token string) (map[string]types.AttributeValue, error) {
+	if token == "" {
+		return nil, nil
+	}
+	accountID, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(accountID) == 0 {
+		return nil, ErrInvalidPageToken
+	}
+	return map[string]types.AttributeValue{
+		"accountId": &types.AttributeValueMemberS{Value: string(accountID)},
+	}, nil
 }
 
 // Exists checks if an account exists
@@ -144,6 +381,75 @@ func (s *AccountStore) Exists(ctx context.Context, accountID string) (bool, erro
 	return result.Item != nil, nil
 }
 
+// IsOnboarded checks if an account exists and finished onboarding
+func (s *AccountStore) IsOnboarded(ctx context.Context, accountID string) (bool, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		ProjectionExpression: aws.String("accountId, onboarding"),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check account onboarding: %w", err)
+	}
+	if result.Item == nil {
+		return false, nil
+	}
+
+	var account Account
+	if err := attributevalue.UnmarshalMap(result.Item, &account); err != nil {
+		return false, fmt.Errorf("failed to unmarshal account: %w", err)
+	}
+	return account.Onboarded(), nil
+}
+
+// SetOnboarding saves account.Onboarding, along with the policy store ID
+// and schema version it has reached, only if the onboarding was last
+// changed at prevUpdatedAt. It stamps account.Onboarding.UpdatedAt, to the
+// nanosecond as it is what later changes are conditioned on.
+func (s *AccountStore) SetOnboarding(ctx context.Context, account *Account, prevUpdatedAt string) error {
+	account.Onboarding.UpdatedAt = s.now().UTC().Format(time.RFC3339Nano)
+	onboarding, err := attributevalue.Marshal(account.Onboarding)
+	if err != nil {
+		return fmt.Errorf("failed to marshal onboarding: %w", err)
+	}
+
+	update := "SET onboarding = :onboarding"
+	values := map[string]types.AttributeValue{
+		":onboarding": onboarding,
+		":prev":       &types.AttributeValueMemberS{Value: prevUpdatedAt},
+	}
+	if account.PolicyStoreID != "" {
+		update += ", policyStoreId = :psid"
+		values[":psid"] = &types.AttributeValueMemberS{Value: account.PolicyStoreID}
+	}
+	if account.SchemaVersion != 0 {
+		update += ", schemaVersion = :version"
+		values[":version"] = &types.AttributeValueMemberN{Value: strconv.Itoa(account.SchemaVersion)}
+	}
+
+	_, err = s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: account.AccountID},
+		},
+		UpdateExpression:          aws.String(update),
+		ConditionExpression:       aws.String("onboarding.updatedAt = :prev"),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("%w: %s", ErrOnboardingChanged, account.AccountID)
+		}
+		return fmt.Errorf("failed to update account onboarding: %w", err)
+	}
+
+	s.logger.Info("account onboarding updated", "account_id", account.AccountID, "state", account.Onboarding.State)
+	return nil
+}
+
 // UpdatePolicyStoreID updates the policy store ID for an account
 func (s *AccountStore) UpdatePolicyStoreID(ctx context.Context, accountID, policyStoreID string) error {
 	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
@@ -164,6 +470,221 @@ func (s *AccountStore) UpdatePolicyStoreID(ctx context.Context, accountID, polic
 	return nil
 }
 
+// SetOrganizationID makes the account a member of an organization, or removes
+// it from its organization when orgID is empty
+func (s *AccountStore) SetOrganizationID(ctx context.Context, accountID, orgID string) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression:    aws.String("REMOVE organizationId"),
+		ConditionExpression: aws.String("attribute_exists(accountId)"),
+	}
+	if orgID != "" {
+		input.UpdateExpression = aws.String("SET organizationId = :oid")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{
+			":oid": &types.AttributeValueMemberS{Value: orgID},
+		}
+	}
+
+	if _, err := s.dynamoClient.UpdateItem(ctx, input); err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+		return fmt.Errorf("failed to update account organization: %w", err)
+	}
+
+	s.logger.Info("account organization updated", "account_id", accountID, "organization_id", orgID)
+	return nil
+}
+
+// SetRequiredTags replaces the account's required tag keys, or removes them
+// when tags is empty
+func (s *AccountStore) SetRequiredTags(ctx context.Context, accountID string, tags []string) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression:    aws.String("REMOVE requiredTags"),
+		ConditionExpression: aws.String("attribute_exists(accountId)"),
+	}
+	if len(tags) > 0 {
+		av, err := attributevalue.Marshal(tags)
+		if err != nil {
+			return fmt.Errorf("failed to marshal required tags: %w", err)
+		}
+		input.UpdateExpression = aws.String("SET requiredTags = :tags")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{":tags": av}
+	}
+
+	if _, err := s.dynamoClient.UpdateItem(ctx, input); err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+		return fmt.Errorf("failed to update account required tags: %w", err)
+	}
+
+	s.logger.Info("account required tags updated", "account_id", accountID, "required_tags", tags)
+	return nil
+}
+
+// SetPlan moves the account to plan
+func (s *AccountStore) SetPlan(ctx context.Context, accountID, plan string) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression: aws.String("SET #plan = :plan"),
+		// plan is a DynamoDB reserved word
+		ExpressionAttributeNames: map[string]string{"#plan": "plan"},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":plan": &types.AttributeValueMemberS{Value: plan},
+		},
+		ConditionExpression: aws.String("attribute_exists(accountId)"),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+		return fmt.Errorf("failed to update account plan: %w", err)
+	}
+
+	s.logger.Info("account plan updated", "account_id", accountID, "plan", plan)
+	return nil
+}
+
+// SetResidency pins the account to region and sets its export-control
+// classification; empty values clear them
+func (s *AccountStore) SetResidency(ctx context.Context, accountID, region, exportControl string) error {
+	var set, remove []string
+	values := map[string]types.AttributeValue{}
+	for _, attr := range [][2]string{{"pinnedRegion", region}, {"exportControl", exportControl}} {
+		if attr[1] == "" {
+			remove = append(remove, attr[0])
+			continue
+		}
+		set = append(set, attr[0]+" = :"+attr[0])
+		values[":"+attr[0]] = &types.AttributeValueMemberS{Value: attr[1]}
+	}
+	var update []string
+	if len(set) > 0 {
+		update = append(update, "SET "+strings.Join(set, ", "))
+	}
+	if len(remove) > 0 {
+		update = append(update, "REMOVE "+strings.Join(remove, ", "))
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression:    aws.String(strings.Join(update, " ")),
+		ConditionExpression: aws.String("attribute_exists(accountId)"),
+	}
+	if len(values) > 0 {
+		input.ExpressionAttributeValues = values
+	}
+	if _, err := s.dynamoClient.UpdateItem(ctx, input); err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+		return fmt.Errorf("failed to update account residency: %w", err)
+	}
+
+	s.logger.Info("account residency updated", "account_id", accountID, "pinned_region", region, "export_control", exportControl)
+	return nil
+}
+
+// SetChangeReview turns the account's change review mode on or off
+func (s *AccountStore) SetChangeReview(ctx context.Context, accountID string, enabled bool) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression: aws.String("SET changeReview = :enabled"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":enabled": &types.AttributeValueMemberBOOL{Value: enabled},
+		},
+		ConditionExpression: aws.String("attribute_exists(accountId)"),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("account not found: %s", accountID)
+		}
+		return fmt.Errorf("failed to update account change review: %w", err)
+	}
+
+	s.logger.Info("account change review updated", "account_id", accountID, "enabled", enabled)
+	return nil
+}
+
+// SwapPolicyStoreID replaces the account's policy store ID only if it is still
+// oldPolicyStoreID, so concurrent rebuilds cannot overwrite each other. The
+// new store has the given schema version.
+func (s *AccountStore) SwapPolicyStoreID(ctx context.Context, accountID, oldPolicyStoreID, newPolicyStoreID string, schemaVersion int) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression:    aws.String("SET policyStoreId = :new, schemaVersion = :version"),
+		ConditionExpression: aws.String("policyStoreId = :old"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":new":     &types.AttributeValueMemberS{Value: newPolicyStoreID},
+			":old":     &types.AttributeValueMemberS{Value: oldPolicyStoreID},
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(schemaVersion)},
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("%w: %s", ErrPolicyStoreChanged, accountID)
+		}
+		return fmt.Errorf("failed to update policy store ID: %w", err)
+	}
+
+	s.logger.Info("account policy store ID swapped", "account_id", accountID, "old_policy_store_id", oldPolicyStoreID, "policy_store_id", newPolicyStoreID)
+	return nil
+}
+
+// SetSchemaVersion records the schema version of the account's policy store,
+// only if it is still policyStoreID, so a migration racing a rebuild cannot
+// mark the rebuilt store
+func (s *AccountStore) SetSchemaVersion(ctx context.Context, accountID, policyStoreID string, schemaVersion int) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		UpdateExpression:    aws.String("SET schemaVersion = :version"),
+		ConditionExpression: aws.String("policyStoreId = :psid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":version": &types.AttributeValueMemberN{Value: strconv.Itoa(schemaVersion)},
+			":psid":    &types.AttributeValueMemberS{Value: policyStoreID},
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("%w: %s", ErrPolicyStoreChanged, accountID)
+		}
+		return fmt.Errorf("failed to update schema version: %w", err)
+	}
+
+	s.logger.Info("account schema version updated", "account_id", accountID, "policy_store_id", policyStoreID, "schema_version", schemaVersion)
+	return nil
+}
+
 // isConditionalCheckFailed checks if the error is a conditional check failed error
 func isConditionalCheckFailed(err error, target **types.ConditionalCheckFailedException) bool {
 	if err == nil {
@@ -0,0 +1,264 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// scanClient serves Scan from an ordered list of accounts, honouring Limit,
+// ExclusiveStartKey and the privileged filter like DynamoDB: Limit bounds the
+// items read, and the filter is applied afterwards
+type scanClient struct {
+	client.DynamoDBClient
+	accounts []Account
+	scans    int
+}
+
+func (c *scanClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	c.scans++
+
+	start := 0
+	if key, ok := params.ExclusiveStartKey["accountId"].(*types.AttributeValueMemberS); ok {
+		for i, a := range c.accounts {
+			if a.AccountID == key.Value {
+				start = i + 1
+			}
+		}
+	}
+	end := len(c.accounts)
+	if params.Limit != nil && start+int(*params.Limit) < end {
+		end = start + int(*params.Limit)
+	}
+
+	out := &dynamodb.ScanOutput{}
+	for _, a := range c.accounts[start:end] {
+		if priv, ok := params.ExpressionAttributeValues[":priv"].(*types.AttributeValueMemberBOOL); ok && a.Privileged != priv.Value {
+			continue
+		}
+		out.Count++
+		out.Items = append(out.Items, map[string]types.AttributeValue{
+			"accountId":  &types.AttributeValueMemberS{Value: a.AccountID},
+			"privileged": &types.AttributeValueMemberBOOL{Value: a.Privileged},
+		})
+	}
+	if end < len(c.accounts) {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: c.accounts[end-1].AccountID},
+		}
+	}
+	return out, nil
+}
+
+func newScanStore(accounts ...Account) (*AccountStore, *scanClient) {
+	c := &scanClient{accounts: accounts}
+	return NewAccountStore("accounts", c, slog.New(slog.NewTextHandler(io.Discard, nil))), c
+}
+
+func accountIDs(accounts []*Account) []string {
+	ids := make([]string, len(accounts))
+	for i, a := range accounts {
+		ids[i] = a.AccountID
+	}
+	return ids
+}
+
+func TestAccountStore_ListPage(t *testing.T) {
+	s, _ := newScanStore(
+		Account{AccountID: "111111111111"},
+		Account{AccountID: "222222222222", Privileged: true},
+		Account{AccountID: "333333333333"},
+		Account{AccountID: "444444444444", Privileged: true},
+		Account{AccountID: "555555555555"},
+	)
+	ctx := context.Background()
+
+	var got []string
+	token := ""
+	for pages := 0; ; pages++ {
+		if pages > 5 {
+			t.Fatal("pagination did not terminate")
+		}
+		page, err := s.ListPage(ctx, 2, token, AccountFilter{})
+		if err != nil {
+			t.Fatalf("ListPage: %v", err)
+		}
+		if len(page.Accounts) > 2 {
+			t.Fatalf("page holds %d accounts, limit is 2", len(page.Accounts))
+		}
+		got = append(got, accountIDs(page.Accounts)...)
+		if page.NextToken == "" {
+			break
+		}
+		token = page.NextToken
+	}
+
+	if len(got) != 5 || got[0] != "111111111111" || got[4] != "555555555555" {
+		t.Errorf("expected every account once in order, got %v", got)
+	}
+}
+
+func TestAccountStore_ListPageFiltered(t *testing.T) {
+	s, c := newScanStore(
+		Account{AccountID: "111111111111"},
+		Account{AccountID: "222222222222", Privileged: true},
+		Account{AccountID: "333333333333"},
+		Account{AccountID: "444444444444"},
+		Account{AccountID: "555555555555", Privileged: true},
+	)
+	privileged := true
+
+	page, err := s.ListPage(context.Background(), 2, "", AccountFilter{Privileged: &privileged})
+	if err != nil {
+		t.Fatalf("ListPage: %v", err)
+	}
+
+	// The first scan page of two holds one match, so a second scan fills the page
+	if ids := accountIDs(page.Accounts); len(ids) != 2 || ids[0] != "222222222222" || ids[1] != "555555555555" {
+		t.Errorf("expected both privileged accounts, got %v", ids)
+	}
+	if page.NextToken != "" {
+		t.Errorf("expected no next page, got token %q", page.NextToken)
+	}
+	if c.scans < 2 {
+		t.Errorf("expected ListPage to keep scanning, got %d scans", c.scans)
+	}
+}
+
+func TestAccountStore_ListPageInvalidToken(t *testing.T) {
+	s, _ := newScanStore()
+	if _, err := s.ListPage(context.Background(), 10, "not base64!", AccountFilter{}); !errors.Is(err, ErrInvalidPageToken) {
+		t.Errorf("expected ErrInvalidPageToken, got %v", err)
+	}
+}
+
+func TestAccountStore_ListAndCountFollowScanPages(t *testing.T) {
+	s, c := newScanStore(
+		Account{AccountID: "111111111111"},
+		Account{AccountID: "222222222222", Privileged: true},
+		Account{AccountID: "333333333333"},
+	)
+	ctx := context.Background()
+
+	// A small page size forces Count through several scan pages
+	s.dynamoClient = &limitedScanClient{scanClient: c, limit: 1}
+
+	accounts, err := s.List(ctx)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(accounts) != 3 {
+		t.Errorf("expected 3 accounts, got %d", len(accounts))
+	}
+
+	privileged := false
+	count, err := s.Count(ctx, AccountFilter{Privileged: &privileged})
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 tenant accounts, got %d", count)
+	}
+}
+
+// limitedScanClient caps every scan at limit items, like DynamoDB's 1 MB
+// scan page limit does for large tables
+type limitedScanClient struct {
+	*scanClient
+	limit int32
+}
+
+func (c *limitedScanClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	capped := *params
+	capped.Limit = aws.Int32(c.limit)
+	return c.scanClient.Scan(ctx, &capped, optFns...)
+}
+
+// updateClient records the UpdateItem calls it is sent, failing them with
+// err if set
+type updateClient struct {
+	client.DynamoDBClient
+	updates []*dynamodb.UpdateItemInput
+	err     error
+}
+
+func (c *updateClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	c.updates = append(c.updates, params)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func TestAccountStore_SetResidency(t *testing.T) {
+	c := &updateClient{}
+	s := NewAccountStore("accounts", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	if err := s.SetResidency(ctx, "111111111111", "eu-west-1", "EAR99"); err != nil {
+		t.Fatalf("SetResidency() error = %v", err)
+	}
+	if err := s.SetResidency(ctx, "111111111111", "eu-west-1", ""); err != nil {
+		t.Fatalf("SetResidency() error = %v", err)
+	}
+	if err := s.SetResidency(ctx, "111111111111", "", ""); err != nil {
+		t.Fatalf("SetResidency() error = %v", err)
+	}
+
+	want := []string{
+		"SET pinnedRegion = :pinnedRegion, exportControl = :exportControl",
+		"SET pinnedRegion = :pinnedRegion REMOVE exportControl",
+		"REMOVE pinnedRegion, exportControl",
+	}
+	for i, update := range c.updates {
+		if got := aws.ToString(update.UpdateExpression); got != want[i] {
+			t.Errorf("update %d = %q, want %q", i, got, want[i])
+		}
+	}
+	if c.updates[2].ExpressionAttributeValues != nil {
+		t.Errorf("expected no values when clearing both, got %v", c.updates[2].ExpressionAttributeValues)
+	}
+}
+
+func TestAccountStore_SwapPolicyStoreID(t *testing.T) {
+	c := &updateClient{}
+	s := NewAccountStore("accounts", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := s.SwapPolicyStoreID(context.Background(), "111111111111", "ps-old", "ps-new", 3); err != nil {
+		t.Fatalf("SwapPolicyStoreID() error = %v", err)
+	}
+	update := c.updates[0]
+	if got := aws.ToString(update.ConditionExpression); got != "policyStoreId = :old" {
+		t.Errorf("condition = %q, want the swap conditioned on the old store", got)
+	}
+	values := update.ExpressionAttributeValues
+	if values[":old"].(*types.AttributeValueMemberS).Value != "ps-old" ||
+		values[":new"].(*types.AttributeValueMemberS).Value != "ps-new" ||
+		values[":version"].(*types.AttributeValueMemberN).Value != "3" {
+		t.Errorf("unexpected values %v", values)
+	}
+}
+
+func TestAccountStore_SwapPolicyStoreID_Conflict(t *testing.T) {
+	c := &updateClient{err: &types.ConditionalCheckFailedException{}}
+	s := NewAccountStore("accounts", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	err := s.SwapPolicyStoreID(context.Background(), "111111111111", "ps-old", "ps-new", 3)
+	if !errors.Is(err, ErrPolicyStoreChanged) {
+		t.Fatalf("SwapPolicyStoreID() error = %v, want ErrPolicyStoreChanged", err)
+	}
+
+	c.err = errors.New("throttled")
+	err = s.SwapPolicyStoreID(context.Background(), "111111111111", "ps-old", "ps-new", 3)
+	if err == nil || errors.Is(err, ErrPolicyStoreChanged) {
+		t.Errorf("SwapPolicyStoreID() error = %v, want a plain failure", err)
+	}
+}
@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -12,6 +13,16 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+var (
+	// ErrAdminExists is returned when adding a principal that is already an
+	// admin of the account
+	ErrAdminExists = errors.New("admin already exists")
+	// ErrAdminNotFound is returned when removing a principal that is not an
+	// admin of the account
+	ErrAdminNotFound = errors.New("admin not found")
 )
 
 // Admin represents an admin entry for an account
@@ -27,6 +38,7 @@ type AdminStore struct {
 	tableName    string
 	dynamoClient client.DynamoDBClient
 	logger       *slog.Logger
+	now          clock.Clock
 }
 
 // NewAdminStore creates a new admin store
@@ -35,13 +47,20 @@ func NewAdminStore(tableName string, dynamoClient client.DynamoDBClient, logger
 		tableName:    tableName,
 		dynamoClient: dynamoClient,
 		logger:       logger,
+		now:          time.Now,
 	}
 }
 
+// WithClock sets the clock the store stamps times with
+func (s *AdminStore) WithClock(now clock.Clock) *AdminStore {
+	s.now = clock.Or(now)
+	return s
+}
+
 // Add adds an admin for an account
 func (s *AdminStore) Add(ctx context.Context, admin *Admin) error {
 	if admin.CreatedAt == "" {
-		admin.CreatedAt = time.Now().UTC().Format(time.RFC3339)
+		admin.CreatedAt = clock.Format(s.now())
 	}
 
 	item, err := attributevalue.MarshalMap(admin)
@@ -57,7 +76,7 @@ func (s *AdminStore) Add(ctx context.Context, admin *Admin) error {
 	if err != nil {
 		var condErr *types.ConditionalCheckFailedException
 		if ok := isConditionalCheckFailed(err, &condErr); ok {
-			return fmt.Errorf("admin already exists: %s in account %s", admin.PrincipalARN, admin.AccountID)
+			return fmt.Errorf("%w: %s in account %s", ErrAdminExists, admin.PrincipalARN, admin.AccountID)
 		}
 		return fmt.Errorf("failed to add admin: %w", err)
 	}
@@ -66,7 +85,33 @@ func (s *AdminStore) Add(ctx context.Context, admin *Admin) error {
 	return nil
 }
 
-// Remove removes an admin from an account
+// Get returns the admin entry for a principal, or nil if it is not an admin
+func (s *AdminStore) Get(ctx context.Context, accountID, principalARN string) (*Admin, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId":    &types.AttributeValueMemberS{Value: accountID},
+			"principalArn": &types.AttributeValueMemberS{Value: principalARN},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get admin: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var admin Admin
+	if err := attributevalue.UnmarshalMap(result.Item, &admin); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal admin: %w", err)
+	}
+
+	return &admin, nil
+}
+
+// Remove removes an admin from an account, failing with ErrAdminNotFound if
+// the principal is not an admin
 func (s *AdminStore) Remove(ctx context.Context, accountID, principalARN string) error {
 	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
 		TableName: aws.String(s.tableName),
@@ -74,8 +119,13 @@ func (s *AdminStore) Remove(ctx context.Context, accountID, principalARN string)
 			"accountId":    &types.AttributeValueMemberS{Value: accountID},
 			"principalArn": &types.AttributeValueMemberS{Value: principalARN},
 		},
+		ConditionExpression: aws.String("attribute_exists(principalArn)"),
 	})
 	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("%w: %s in account %s", ErrAdminNotFound, principalARN, accountID)
+		}
 		return fmt.Errorf("failed to remove admin: %w", err)
 	}
 
@@ -139,3 +189,36 @@ func (s *AdminStore) ListARNs(ctx context.Context, accountID string) ([]string,
 
 	return arns, nil
 }
+
+// ListByPrincipal returns every admin entry for a principal across all
+// accounts. Uses the principal-accounts-index GSI.
+func (s *AdminStore) ListByPrincipal(ctx context.Context, principalARN string) ([]*Admin, error) {
+	var admins []*Admin
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String("principal-accounts-index"),
+		KeyConditionExpression: aws.String("principalArn = :arn"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":arn": &types.AttributeValueMemberS{Value: principalARN},
+		},
+	}
+	for {
+		result, err := s.dynamoClient.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list admin accounts: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var admin Admin
+			if err := attributevalue.UnmarshalMap(item, &admin); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal admin: %w", err)
+			}
+			admins = append(admins, &admin)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return admins, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
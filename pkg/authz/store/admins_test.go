@@ -0,0 +1,133 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// conditionClient holds admin keys and enforces the conditions AdminStore
+// sends with its writes
+type conditionClient struct {
+	client.DynamoDBClient
+	admins map[string]bool
+}
+
+func adminKey(item map[string]types.AttributeValue) string {
+	return item["accountId"].(*types.AttributeValueMemberS).Value + "|" + item["principalArn"].(*types.AttributeValueMemberS).Value
+}
+
+func (c *conditionClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	key := adminKey(params.Item)
+	if params.ConditionExpression != nil && c.admins[key] {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	c.admins[key] = true
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *conditionClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	key := adminKey(params.Key)
+	if params.ConditionExpression != nil && !c.admins[key] {
+		return nil, &types.ConditionalCheckFailedException{}
+	}
+	delete(c.admins, key)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestAdminStore_ConditionalWrites(t *testing.T) {
+	s := NewAdminStore("admins", &conditionClient{admins: map[string]bool{}}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	arn := "arn:aws:iam::123456789012:user/alice"
+
+	if err := s.Add(ctx, &Admin{AccountID: "123456789012", PrincipalARN: arn}); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.Add(ctx, &Admin{AccountID: "123456789012", PrincipalARN: arn}); !errors.Is(err, ErrAdminExists) {
+		t.Errorf("expected ErrAdminExists on duplicate add, got %v", err)
+	}
+
+	if err := s.Remove(ctx, "123456789012", arn); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if err := s.Remove(ctx, "123456789012", arn); !errors.Is(err, ErrAdminNotFound) {
+		t.Errorf("expected ErrAdminNotFound removing an unknown admin, got %v", err)
+	}
+}
+
+// pagedQueryClient answers Query with pages in order, recording each input.
+// Every page but the last carries a LastEvaluatedKey.
+type pagedQueryClient struct {
+	client.DynamoDBClient
+	pages  [][]any
+	inputs []*dynamodb.QueryInput
+}
+
+func (c *pagedQueryClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	in := *params
+	c.inputs = append(c.inputs, &in)
+	out := &dynamodb.QueryOutput{}
+	page := len(c.inputs) - 1
+	if page >= len(c.pages) {
+		return out, nil
+	}
+	for _, v := range c.pages[page] {
+		item, err := attributevalue.MarshalMap(v)
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, item)
+	}
+	if page < len(c.pages)-1 {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{"page": &types.AttributeValueMemberN{Value: fmt.Sprint(page)}}
+	}
+	return out, nil
+}
+
+func TestAdminStore_ListByPrincipal(t *testing.T) {
+	ctx := context.Background()
+	arn := "arn:aws:iam::123456789012:user/alice"
+
+	t.Run("pages through the index", func(t *testing.T) {
+		c := &pagedQueryClient{pages: [][]any{
+			{&Admin{AccountID: "111111111111", PrincipalARN: arn}},
+			{&Admin{AccountID: "222222222222", PrincipalARN: arn}, &Admin{AccountID: "333333333333", PrincipalARN: arn}},
+		}}
+		s := NewAdminStore("admins", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		admins, err := s.ListByPrincipal(ctx, arn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(admins) != 3 || admins[0].AccountID != "111111111111" || admins[2].AccountID != "333333333333" {
+			t.Errorf("unexpected admins %+v", admins)
+		}
+		if len(c.inputs) != 2 {
+			t.Fatalf("expected 2 queries, got %d", len(c.inputs))
+		}
+		if aws.ToString(c.inputs[0].IndexName) != "principal-accounts-index" || c.inputs[0].ExpressionAttributeValues[":arn"].(*types.AttributeValueMemberS).Value != arn {
+			t.Errorf("unexpected query %+v", c.inputs[0])
+		}
+		if c.inputs[0].ExclusiveStartKey != nil || c.inputs[1].ExclusiveStartKey == nil {
+			t.Error("expected the second query to resume from the first page")
+		}
+	})
+
+	t.Run("no accounts", func(t *testing.T) {
+		s := NewAdminStore("admins", &pagedQueryClient{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		admins, err := s.ListByPrincipal(ctx, arn)
+		if err != nil || len(admins) != 0 {
+			t.Errorf("expected no admins, got %v, %v", admins, err)
+		}
+	})
+}
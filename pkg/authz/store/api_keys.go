@@ -0,0 +1,294 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// apiKeyAccountIndexName is the GSI keyed on accountId and sorted by
+// createdAt, used to list an account's API keys
+const apiKeyAccountIndexName = "account-index"
+
+// apiKeyChangesIndexName is the sparse GSI keyed on changeDay and sorted by
+// changedAt, holding the keys revoked or given an expiry after minting
+const apiKeyChangesIndexName = "changes-index"
+
+// ErrAPIKeyExists is returned when creating an API key whose ID is taken
+var ErrAPIKeyExists = errors.New("API key already exists")
+
+// APIKey is a credential a machine client of an account presents instead of
+// AWS identity. Requests made with it act as PrincipalARN, a synthetic
+// principal of the account that policies and groups can name like any other,
+// limited to Actions. Only a hash of the key is stored; Prefix and Last4
+// identify it to its owners.
+type APIKey struct {
+	KeyID        string   `dynamodbav:"keyId" json:"keyId"`
+	AccountID    string   `dynamodbav:"accountId" json:"accountId"`
+	Name         string   `dynamodbav:"name" json:"name"`
+	Description  string   `dynamodbav:"description,omitempty" json:"description,omitempty"`
+	PrincipalARN string   `dynamodbav:"principalArn" json:"principalArn"`
+	Actions      []string `dynamodbav:"actions" json:"actions"`
+	Prefix       string   `dynamodbav:"prefix" json:"prefix"`
+	Last4        string   `dynamodbav:"last4" json:"last4"`
+	// SecretHash is the hex-encoded SHA-256 of the key's secret
+	SecretHash string `dynamodbav:"secretHash" json:"-"`
+	// ExpiresAt is an optional RFC3339 time after which the key is rejected
+	ExpiresAt string `dynamodbav:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	RevokedAt string `dynamodbav:"revokedAt,omitempty" json:"revokedAt,omitempty"`
+	RevokedBy string `dynamodbav:"revokedBy,omitempty" json:"revokedBy,omitempty"`
+	// RotatedFrom is the key this key replaced
+	RotatedFrom string `dynamodbav:"rotatedFrom,omitempty" json:"rotatedFrom,omitempty"`
+	CreatedAt   string `dynamodbav:"createdAt" json:"createdAt"`
+	CreatedBy   string `dynamodbav:"createdBy" json:"createdBy"`
+	// ChangeDay and ChangedAt record the last revocation or expiry change,
+	// so replicas caching the key find it through ListChangedSince
+	ChangeDay string `dynamodbav:"changeDay,omitempty" json:"-"`
+	ChangedAt string `dynamodbav:"changedAt,omitempty" json:"-"`
+}
+
+// Expired reports whether the key has passed its expiry time
+func (k *APIKey) Expired(now time.Time) bool {
+	if k.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, k.ExpiresAt)
+	return err != nil || !now.Before(expiresAt)
+}
+
+// Active reports whether the key is neither revoked nor expired
+func (k *APIKey) Active(now time.Time) bool {
+	return k.RevokedAt == "" && !k.Expired(now)
+}
+
+// Allows reports whether action is within the key's actions. Actions are
+// compared in canonical form, so "rosa:" prefixes and aliases match.
+func (k *APIKey) Allows(action string) bool {
+	action = schema.CanonicalAction(action)
+	return slices.ContainsFunc(k.Actions, func(allowed string) bool {
+		return allowed == AllActions || schema.CanonicalAction(allowed) == action
+	})
+}
+
+// APIKeyStore provides CRUD operations for API keys
+type APIKeyStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewAPIKeyStore creates a new API key store
+func NewAPIKeyStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *APIKeyStore {
+	return &APIKeyStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *APIKeyStore) WithClock(now clock.Clock) *APIKeyStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Create stores an API key, failing if its ID is taken
+func (s *APIKeyStore) Create(ctx context.Context, key *APIKey) error {
+	if key.CreatedAt == "" {
+		key.CreatedAt = clock.Format(s.now())
+	}
+
+	item, err := attributevalue.MarshalMap(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal API key: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(keyId)"),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("%w: %s", ErrAPIKeyExists, key.KeyID)
+		}
+		return fmt.Errorf("failed to create API key: %w", err)
+	}
+
+	s.logger.Info("API key created",
+		"account_id", key.AccountID,
+		"key_id", key.KeyID,
+		"name", key.Name,
+		"actions", key.Actions,
+		"expires_at", key.ExpiresAt,
+		"created_by", key.CreatedBy,
+	)
+	return nil
+}
+
+// Get returns an API key, or nil if none exists
+func (s *APIKeyStore) Get(ctx context.Context, keyID string) (*APIKey, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"keyId": &types.AttributeValueMemberS{Value: keyID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get API key: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var key APIKey
+	if err := attributevalue.UnmarshalMap(result.Item, &key); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+	}
+
+	return &key, nil
+}
+
+// ListByAccount returns the API keys of an account, oldest first
+func (s *APIKeyStore) ListByAccount(ctx context.Context, accountID string) ([]*APIKey, error) {
+	var keys []*APIKey
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			IndexName:              aws.String(apiKeyAccountIndexName),
+			KeyConditionExpression: aws.String("accountId = :aid"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid": &types.AttributeValueMemberS{Value: accountID},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list API keys: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var key APIKey
+			if err := attributevalue.UnmarshalMap(item, &key); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal API key: %w", err)
+			}
+			keys = append(keys, &key)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return keys, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// Revoke marks an API key revoked at the given time. Revoking a key that is
+// already revoked keeps the original revocation.
+func (s *APIKeyStore) Revoke(ctx context.Context, keyID, revokedBy string, at time.Time) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"keyId": &types.AttributeValueMemberS{Value: keyID},
+		},
+		UpdateExpression:    aws.String("SET revokedAt = :at, revokedBy = :by, changeDay = :day, changedAt = :changed"),
+		ConditionExpression: aws.String("attribute_exists(keyId) AND attribute_not_exists(revokedAt)"),
+		ExpressionAttributeValues: s.withChange(map[string]types.AttributeValue{
+			":at": &types.AttributeValueMemberS{Value: clock.Format(at)},
+			":by": &types.AttributeValueMemberS{Value: revokedBy},
+		}),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return nil
+		}
+		return fmt.Errorf("failed to revoke API key: %w", err)
+	}
+
+	s.logger.Info("API key revoked", "key_id", keyID, "revoked_by", revokedBy)
+	return nil
+}
+
+// SetExpiry sets when an API key expires
+func (s *APIKeyStore) SetExpiry(ctx context.Context, keyID string, expiresAt time.Time) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"keyId": &types.AttributeValueMemberS{Value: keyID},
+		},
+		UpdateExpression:    aws.String("SET expiresAt = :at, changeDay = :day, changedAt = :changed"),
+		ConditionExpression: aws.String("attribute_exists(keyId)"),
+		ExpressionAttributeValues: s.withChange(map[string]types.AttributeValue{
+			":at": &types.AttributeValueMemberS{Value: clock.Format(expiresAt)},
+		}),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set API key expiry: %w", err)
+	}
+
+	s.logger.Info("API key expiry set", "key_id", keyID, "expires_at", expiresAt)
+	return nil
+}
+
+// apiKeyChangeDayFormat partitions the changes index by UTC day
+const apiKeyChangeDayFormat = "2006-01-02"
+
+// withChange adds the :day and :changed values recording a change now
+func (s *APIKeyStore) withChange(values map[string]types.AttributeValue) map[string]types.AttributeValue {
+	now := s.now().UTC()
+	values[":day"] = &types.AttributeValueMemberS{Value: now.Format(apiKeyChangeDayFormat)}
+	values[":changed"] = &types.AttributeValueMemberS{Value: now.Format(time.RFC3339Nano)}
+	return values
+}
+
+// ListChangedSince returns the IDs of the keys revoked or given an expiry
+// after since
+func (s *APIKeyStore) ListChangedSince(ctx context.Context, since time.Time) ([]string, error) {
+	since = since.UTC()
+	var keyIDs []string
+	for day := since.Truncate(24 * time.Hour); !day.After(s.now().UTC()); day = day.Add(24 * time.Hour) {
+		var startKey map[string]types.AttributeValue
+		for {
+			result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+				TableName:              aws.String(s.tableName),
+				IndexName:              aws.String(apiKeyChangesIndexName),
+				KeyConditionExpression: aws.String("changeDay = :day AND changedAt > :since"),
+				ExpressionAttributeValues: map[string]types.AttributeValue{
+					":day":   &types.AttributeValueMemberS{Value: day.Format(apiKeyChangeDayFormat)},
+					":since": &types.AttributeValueMemberS{Value: since.Format(time.RFC3339Nano)},
+				},
+				ProjectionExpression: aws.String("keyId"),
+				ExclusiveStartKey:    startKey,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to list changed API keys: %w", err)
+			}
+			for _, item := range result.Items {
+				if id, ok := item["keyId"].(*types.AttributeValueMemberS); ok {
+					keyIDs = append(keyIDs, id.Value)
+				}
+			}
+			if len(result.LastEvaluatedKey) == 0 {
+				break
+			}
+			startKey = result.LastEvaluatedKey
+		}
+	}
+	return keyIDs, nil
+}
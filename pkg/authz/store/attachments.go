@@ -0,0 +1,145 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// AttachmentMeta is the human-readable metadata of a policy attachment. AVP
+// template-linked policies have no description field, so it is kept here,
+// keyed by the attachment's AVP policy ID.
+type AttachmentMeta struct {
+	AccountID    string `dynamodbav:"accountId" json:"accountId"`
+	AttachmentID string `dynamodbav:"attachmentId" json:"attachmentId"`
+	Name         string `dynamodbav:"name,omitempty" json:"name,omitempty"`
+	Description  string `dynamodbav:"description,omitempty" json:"description,omitempty"`
+	UpdatedAt    string `dynamodbav:"updatedAt" json:"updatedAt"`
+}
+
+// AttachmentMetaStore provides CRUD operations for attachment metadata
+type AttachmentMetaStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewAttachmentMetaStore creates a new attachment metadata store
+func NewAttachmentMetaStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *AttachmentMetaStore {
+	return &AttachmentMetaStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *AttachmentMetaStore) WithClock(now clock.Clock) *AttachmentMetaStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Put creates or replaces the metadata of an attachment
+func (s *AttachmentMetaStore) Put(ctx context.Context, meta *AttachmentMeta) error {
+	meta.UpdatedAt = clock.Format(s.now())
+
+	item, err := attributevalue.MarshalMap(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal attachment metadata: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put attachment metadata: %w", err)
+	}
+
+	s.logger.Info("attachment metadata updated", "account_id", meta.AccountID, "attachment_id", meta.AttachmentID)
+	return nil
+}
+
+// Get returns the metadata of an attachment, or nil if it has none
+func (s *AttachmentMetaStore) Get(ctx context.Context, accountID, attachmentID string) (*AttachmentMeta, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId":    &types.AttributeValueMemberS{Value: accountID},
+			"attachmentId": &types.AttributeValueMemberS{Value: attachmentID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment metadata: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var meta AttachmentMeta
+	if err := attributevalue.UnmarshalMap(result.Item, &meta); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal attachment metadata: %w", err)
+	}
+
+	return &meta, nil
+}
+
+// Delete removes the metadata of an attachment
+func (s *AttachmentMetaStore) Delete(ctx context.Context, accountID, attachmentID string) error {
+	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId":    &types.AttributeValueMemberS{Value: accountID},
+			"attachmentId": &types.AttributeValueMemberS{Value: attachmentID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment metadata: %w", err)
+	}
+	return nil
+}
+
+// List returns the metadata of every attachment in an account, keyed by
+// attachment ID
+func (s *AttachmentMetaStore) List(ctx context.Context, accountID string) (map[string]*AttachmentMeta, error) {
+	metas := map[string]*AttachmentMeta{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("accountId = :aid"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid": &types.AttributeValueMemberS{Value: accountID},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list attachment metadata: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var meta AttachmentMeta
+			if err := attributevalue.UnmarshalMap(item, &meta); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal attachment metadata: %w", err)
+			}
+			metas[meta.AttachmentID] = &meta
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return metas, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,239 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+var (
+	// ErrChangeRequestNotFound is returned for a change request that does not
+	// exist or has expired
+	ErrChangeRequestNotFound = errors.New("change request not found")
+
+	// ErrChangeRequestDecided is returned when deciding a change request that
+	// was already approved, rejected or has expired
+	ErrChangeRequestDecided = errors.New("change request is no longer pending")
+)
+
+// Change request statuses
+const (
+	ChangeRequestPending  = "pending"
+	ChangeRequestApproved = "approved"
+	ChangeRequestRejected = "rejected"
+)
+
+// ChangeRequest is an authz API mutation staged for review. It records the
+// request as it was made, so approving it replays the request.
+type ChangeRequest struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	ChangeID  string `dynamodbav:"changeId" json:"changeId"`
+	Method    string `dynamodbav:"method" json:"method"`
+	// Path includes the query string
+	Path        string `dynamodbav:"path" json:"path"`
+	Body        string `dynamodbav:"body,omitempty" json:"body,omitempty"`
+	Status      string `dynamodbav:"status" json:"status"`
+	RequestedBy string `dynamodbav:"requestedBy" json:"requestedBy"`
+	RequestedAt string `dynamodbav:"requestedAt" json:"requestedAt"`
+	DecidedBy   string `dynamodbav:"decidedBy,omitempty" json:"decidedBy,omitempty"`
+	DecidedAt   string `dynamodbav:"decidedAt,omitempty" json:"decidedAt,omitempty"`
+	// ResultStatus is the HTTP status the request got when it was applied
+	ResultStatus int `dynamodbav:"resultStatus,omitempty" json:"resultStatus,omitempty"`
+	// ExpiresAt is the Unix time after which a pending request can no longer
+	// be approved, and a decided one is removed
+	ExpiresAt int64 `dynamodbav:"expiresAt" json:"expiresAt"`
+}
+
+// ChangeRequestStore keeps change requests. Records expire through DynamoDB
+// TTL on expiresAt.
+type ChangeRequestStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewChangeRequestStore creates a new change request store
+func NewChangeRequestStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *ChangeRequestStore {
+	return &ChangeRequestStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *ChangeRequestStore) WithClock(now clock.Clock) *ChangeRequestStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Create stages a pending change request that expires after expiry
+func (s *ChangeRequestStore) Create(ctx context.Context, cr *ChangeRequest, expiry time.Duration) error {
+	now := s.now().UTC()
+	cr.ChangeID = uuid.New().String()
+	cr.Status = ChangeRequestPending
+	cr.RequestedAt = clock.Format(now)
+	cr.ExpiresAt = now.Add(expiry).Unix()
+
+	item, err := attributevalue.MarshalMap(cr)
+	if err != nil {
+		return fmt.Errorf("failed to marshal change request: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create change request: %w", err)
+	}
+
+	s.logger.Info("change request staged",
+		"account_id", cr.AccountID,
+		"change_id", cr.ChangeID,
+		"method", cr.Method,
+		"path", cr.Path,
+		"requested_by", cr.RequestedBy,
+	)
+	return nil
+}
+
+// Get returns an unexpired change request, or ErrChangeRequestNotFound
+func (s *ChangeRequestStore) Get(ctx context.Context, accountID, changeID string) (*ChangeRequest, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       changeRequestKey(accountID, changeID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get change request: %w", err)
+	}
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrChangeRequestNotFound, changeID)
+	}
+
+	var cr ChangeRequest
+	if err := attributevalue.UnmarshalMap(result.Item, &cr); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal change request: %w", err)
+	}
+	// TTL removal lags expiry
+	if s.now().Unix() >= cr.ExpiresAt {
+		return nil, fmt.Errorf("%w: %s", ErrChangeRequestNotFound, changeID)
+	}
+	return &cr, nil
+}
+
+// List returns an account's unexpired change requests, optionally only those
+// with one status
+func (s *ChangeRequestStore) List(ctx context.Context, accountID, status string) ([]*ChangeRequest, error) {
+	filter := "expiresAt > :now"
+	values := map[string]types.AttributeValue{
+		":aid": &types.AttributeValueMemberS{Value: accountID},
+		":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Unix(), 10)},
+	}
+	var names map[string]string
+	if status != "" {
+		filter += " AND #status = :status"
+		values[":status"] = &types.AttributeValueMemberS{Value: status}
+		names = map[string]string{"#status": "status"}
+	}
+
+	requests := []*ChangeRequest{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(s.tableName),
+			KeyConditionExpression:    aws.String("accountId = :aid"),
+			FilterExpression:          aws.String(filter),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list change requests: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var cr ChangeRequest
+			if err := attributevalue.UnmarshalMap(item, &cr); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal change request: %w", err)
+			}
+			requests = append(requests, &cr)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return requests, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// Decide approves or rejects a pending change request, failing with
+// ErrChangeRequestDecided if it is no longer pending. The decided request is
+// kept for retention so it can still be reviewed.
+func (s *ChangeRequestStore) Decide(ctx context.Context, accountID, changeID, status, decidedBy string, retention time.Duration) error {
+	now := s.now().UTC()
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(s.tableName),
+		Key:                 changeRequestKey(accountID, changeID),
+		UpdateExpression:    aws.String("SET #status = :status, decidedBy = :by, decidedAt = :at, expiresAt = :exp"),
+		ConditionExpression: aws.String("#status = :pending AND expiresAt > :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":status":  &types.AttributeValueMemberS{Value: status},
+			":by":      &types.AttributeValueMemberS{Value: decidedBy},
+			":at":      &types.AttributeValueMemberS{Value: clock.Format(now)},
+			":exp":     &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Add(retention).Unix(), 10)},
+			":pending": &types.AttributeValueMemberS{Value: ChangeRequestPending},
+			":now":     &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if isConditionalCheckFailed(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrChangeRequestDecided, changeID)
+		}
+		return fmt.Errorf("failed to decide change request: %w", err)
+	}
+
+	s.logger.Info("change request decided", "account_id", accountID, "change_id", changeID, "status", status, "decided_by", decidedBy)
+	return nil
+}
+
+// RecordResult stores the HTTP status an approved request got when applied
+func (s *ChangeRequestStore) RecordResult(ctx context.Context, accountID, changeID string, resultStatus int) error {
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:        aws.String(s.tableName),
+		Key:              changeRequestKey(accountID, changeID),
+		UpdateExpression: aws.String("SET resultStatus = :result"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":result": &types.AttributeValueMemberN{Value: strconv.Itoa(resultStatus)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record change request result: %w", err)
+	}
+	return nil
+}
+
+func changeRequestKey(accountID, changeID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"accountId": &types.AttributeValueMemberS{Value: accountID},
+		"changeId":  &types.AttributeValueMemberS{Value: changeID},
+	}
+}
@@ -0,0 +1,269 @@
+package store
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// DecisionAuditTimeFormat is the fixed-width layout of DecisionAuditEntry
+// times, so entry IDs sort chronologically
+const DecisionAuditTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// Decisions recorded in DecisionAuditEntry.Decision
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+// decisionAuditBatchSize is the most items a BatchWriteItem call takes
+const decisionAuditBatchSize = 25
+
+// DecisionAuditEntry is one authorization decision of an account. The
+// principal-index GSI, keyed by accountId#principalArn and entryId, finds a
+// principal's decisions without reading the rest of the account's. The
+// table expires entries through DynamoDB TTL on expiresAt.
+type DecisionAuditEntry struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	// EntryID sorts entries by time: time#random
+	EntryID          string `dynamodbav:"entryId" json:"id"`
+	AccountPrincipal string `dynamodbav:"accountId#principalArn" json:"-"`
+	Time             string `dynamodbav:"time" json:"time"`
+	PrincipalARN     string `dynamodbav:"principalArn" json:"principalArn"`
+	Action           string `dynamodbav:"action" json:"action"`
+	Resource         string `dynamodbav:"resource" json:"resource"`
+	// Decision is allow or deny
+	Decision string `dynamodbav:"decision" json:"decision"`
+	// Basis is what made the decision: policy, organization or admin
+	Basis     string `dynamodbav:"basis" json:"basis"`
+	ExpiresAt int64  `dynamodbav:"expiresAt" json:"-"`
+}
+
+// NewDecisionAuditEntry returns an entry for a decision made at at, which
+// expires retention later
+func NewDecisionAuditEntry(accountID, principalARN, action, resource string, allowed bool, basis string, at time.Time, retention time.Duration) *DecisionAuditEntry {
+	at = at.UTC()
+	suffix := make([]byte, 4)
+	_, _ = rand.Read(suffix)
+	decision := DecisionDeny
+	if allowed {
+		decision = DecisionAllow
+	}
+	return &DecisionAuditEntry{
+		AccountID:        accountID,
+		EntryID:          at.Format(DecisionAuditTimeFormat) + "#" + hex.EncodeToString(suffix),
+		AccountPrincipal: accountID + "#" + principalARN,
+		Time:             clock.Format(at),
+		PrincipalARN:     principalARN,
+		Action:           action,
+		Resource:         resource,
+		Decision:         decision,
+		Basis:            basis,
+		ExpiresAt:        at.Add(retention).Unix(),
+	}
+}
+
+// DecisionAuditQuery narrows a decision audit query. Zero values match
+// every entry.
+type DecisionAuditQuery struct {
+	From, To time.Time
+	// PrincipalARN is matched through the principal-index GSI
+	PrincipalARN   string
+	Action         string
+	Decision       string
+	ResourcePrefix string
+}
+
+// DecisionAuditPage is one page of a decision audit query
+type DecisionAuditPage struct {
+	Entries []*DecisionAuditEntry
+	// NextToken resumes the query after this page; empty on the last page
+	NextToken string
+}
+
+// DecisionAuditStore writes and queries the decision audit log
+type DecisionAuditStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+}
+
+// NewDecisionAuditStore creates a new decision audit store
+func NewDecisionAuditStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DecisionAuditStore {
+	return &DecisionAuditStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+	}
+}
+
+// Write stores entries in batches and returns those that were not written,
+// either because their batch failed or DynamoDB left them unprocessed, with
+// the last error
+func (s *DecisionAuditStore) Write(ctx context.Context, entries []*DecisionAuditEntry) ([]*DecisionAuditEntry, error) {
+	var unwritten []*DecisionAuditEntry
+	var lastErr error
+	for start := 0; start < len(entries); start += decisionAuditBatchSize {
+		batch := entries[start:min(start+decisionAuditBatchSize, len(entries))]
+		requests := make([]types.WriteRequest, 0, len(batch))
+		byID := make(map[string]*DecisionAuditEntry, len(batch))
+		for _, entry := range batch {
+			item, err := attributevalue.MarshalMap(entry)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal decision audit entry: %w", err)
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+			byID[entry.EntryID] = entry
+		}
+
+		result, err := s.dynamoClient.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{s.tableName: requests},
+		})
+		if err != nil {
+			unwritten = append(unwritten, batch...)
+			lastErr = fmt.Errorf("failed to write decision audit entries: %w", err)
+			continue
+		}
+		for _, req := range result.UnprocessedItems[s.tableName] {
+			if id, ok := req.PutRequest.Item["entryId"].(*types.AttributeValueMemberS); ok && byID[id.Value] != nil {
+				unwritten = append(unwritten, byID[id.Value])
+			}
+		}
+	}
+	return unwritten, lastErr
+}
+
+// Query returns up to limit of an account's entries matching q, newest
+// first, starting after the entry that pageToken points to. DynamoDB applies
+// the action, decision and resource filters after reading, so Query keeps
+// reading until the page is full or the entries run out.
+func (s *DecisionAuditStore) Query(ctx context.Context, accountID string, q DecisionAuditQuery, limit int, pageToken string) (*DecisionAuditPage, error) {
+	input := &dynamodb.QueryInput{
+		TableName:        aws.String(s.tableName),
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(int32(limit)),
+	}
+	names := map[string]string{"#pk": "accountId", "#sk": "entryId"}
+	values := map[string]types.AttributeValue{
+		":pk": &types.AttributeValueMemberS{Value: accountID},
+	}
+	if q.PrincipalARN != "" {
+		input.IndexName = aws.String("principal-index")
+		names["#pk"] = "accountId#principalArn"
+		values[":pk"] = &types.AttributeValueMemberS{Value: accountID + "#" + q.PrincipalARN}
+	}
+
+	// Entry IDs start with their time, and '$' sorts after the '#' that
+	// follows it, so the upper bound takes in every entry of the last
+	// instant
+	keyCondition := "#pk = :pk"
+	switch {
+	case !q.From.IsZero() && !q.To.IsZero():
+		keyCondition += " AND #sk BETWEEN :from AND :to"
+	case !q.From.IsZero():
+		keyCondition += " AND #sk >= :from"
+	case !q.To.IsZero():
+		keyCondition += " AND #sk <= :to"
+	default:
+		delete(names, "#sk")
+	}
+	if !q.From.IsZero() {
+		values[":from"] = &types.AttributeValueMemberS{Value: q.From.UTC().Format(DecisionAuditTimeFormat)}
+	}
+	if !q.To.IsZero() {
+		values[":to"] = &types.AttributeValueMemberS{Value: q.To.UTC().Format(DecisionAuditTimeFormat) + "$"}
+	}
+	input.KeyConditionExpression = aws.String(keyCondition)
+
+	// action and resource are reserved words in DynamoDB expressions
+	var filterParts []string
+	if q.Action != "" {
+		filterParts = append(filterParts, "#action = :action")
+		names["#action"] = "action"
+		values[":action"] = &types.AttributeValueMemberS{Value: q.Action}
+	}
+	if q.Decision != "" {
+		filterParts = append(filterParts, "decision = :decision")
+		values[":decision"] = &types.AttributeValueMemberS{Value: q.Decision}
+	}
+	if q.ResourcePrefix != "" {
+		filterParts = append(filterParts, "begins_with(#resource, :resource)")
+		names["#resource"] = "resource"
+		values[":resource"] = &types.AttributeValueMemberS{Value: q.ResourcePrefix}
+	}
+	if len(filterParts) > 0 {
+		input.FilterExpression = aws.String(strings.Join(filterParts, " AND "))
+	}
+	input.ExpressionAttributeNames = names
+	input.ExpressionAttributeValues = values
+
+	startKey, err := decodeDecisionAuditPageToken(pageToken, accountID, q.PrincipalARN)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &DecisionAuditPage{Entries: []*DecisionAuditEntry{}}
+	for {
+		input.ExclusiveStartKey = startKey
+		result, err := s.dynamoClient.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query decision audit log: %w", err)
+		}
+
+		for i, item := range result.Items {
+			var entry DecisionAuditEntry
+			if err := attributevalue.UnmarshalMap(item, &entry); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal decision audit entry: %w", err)
+			}
+			page.Entries = append(page.Entries, &entry)
+
+			// Resume after the last returned entry rather than at the
+			// query's own position, which may be past unreturned matches
+			if len(page.Entries) == limit {
+				if i < len(result.Items)-1 || len(result.LastEvaluatedKey) > 0 {
+					page.NextToken = base64.RawURLEncoding.EncodeToString([]byte(entry.EntryID))
+				}
+				return page, nil
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return page, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// decodeDecisionAuditPageToken returns the query start key for a page
+// token, or nil for an empty token. The GSI's start key also needs its own
+// key.
+func decodeDecisionAuditPageToken(token, accountID, principalARN string) (map[string]types.AttributeValue, error) {
+	if token == "" {
+		return nil, nil
+	}
+	entryID, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(entryID) == 0 {
+		return nil, ErrInvalidPageToken
+	}
+	key := map[string]types.AttributeValue{
+		"accountId": &types.AttributeValueMemberS{Value: accountID},
+		"entryId":   &types.AttributeValueMemberS{Value: string(entryID)},
+	}
+	if principalARN != "" {
+		key["accountId#principalArn"] = &types.AttributeValueMemberS{Value: accountID + "#" + principalARN}
+	}
+	return key, nil
+}
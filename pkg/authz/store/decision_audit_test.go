@@ -0,0 +1,193 @@
+package store
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// decisionAuditClient keeps written entries in memory, leaving the first
+// unprocessed entries unwritten, and answers queries newest first a page of
+// Limit items at a time, applying the key condition and filters like
+// DynamoDB
+type decisionAuditClient struct {
+	client.DynamoDBClient
+	entries     []*DecisionAuditEntry
+	unprocessed int
+	queries     []*dynamodb.QueryInput
+}
+
+func (c *decisionAuditClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	out := &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{}}
+	for table, requests := range params.RequestItems {
+		for _, req := range requests {
+			if c.unprocessed > 0 {
+				c.unprocessed--
+				out.UnprocessedItems[table] = append(out.UnprocessedItems[table], req)
+				continue
+			}
+			var entry DecisionAuditEntry
+			if err := attributevalue.UnmarshalMap(req.PutRequest.Item, &entry); err != nil {
+				return nil, err
+			}
+			c.entries = append(c.entries, &entry)
+		}
+	}
+	return out, nil
+}
+
+func (c *decisionAuditClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	c.queries = append(c.queries, params)
+	values := params.ExpressionAttributeValues
+	str := func(name string) string {
+		if v, ok := values[name].(*types.AttributeValueMemberS); ok {
+			return v.Value
+		}
+		return ""
+	}
+
+	var matches []*DecisionAuditEntry
+	for _, e := range c.entries {
+		pk := e.AccountID
+		if params.IndexName != nil {
+			pk = e.AccountPrincipal
+		}
+		if pk != str(":pk") || (str(":from") != "" && e.EntryID < str(":from")) || (str(":to") != "" && e.EntryID > str(":to")) {
+			continue
+		}
+		matches = append(matches, e)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].EntryID > matches[j].EntryID })
+
+	start := 0
+	if params.ExclusiveStartKey != nil {
+		after := params.ExclusiveStartKey["entryId"].(*types.AttributeValueMemberS).Value
+		for start < len(matches) && matches[start].EntryID >= after {
+			start++
+		}
+	}
+	end := min(start+int(aws.ToInt32(params.Limit)), len(matches))
+
+	out := &dynamodb.QueryOutput{}
+	for _, e := range matches[start:end] {
+		if (str(":action") != "" && e.Action != str(":action")) ||
+			(str(":decision") != "" && e.Decision != str(":decision")) ||
+			(str(":resource") != "" && !strings.HasPrefix(e.Resource, str(":resource"))) {
+			continue
+		}
+		item, _ := attributevalue.MarshalMap(e)
+		out.Items = append(out.Items, item)
+	}
+	if end < len(matches) {
+		out.LastEvaluatedKey = map[string]types.AttributeValue{"entryId": &types.AttributeValueMemberS{Value: matches[end-1].EntryID}}
+	}
+	return out, nil
+}
+
+func TestDecisionAuditStore_WriteAndQuery(t *testing.T) {
+	c := &decisionAuditClient{unprocessed: 1}
+	s := NewDecisionAuditStore("decision-audit", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	at := time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)
+
+	alice := "arn:aws:iam::123456789012:user/alice"
+	bob := "arn:aws:iam::123456789012:user/bob"
+	var entries []*DecisionAuditEntry
+	for i := range 30 {
+		principal, action, allowed := alice, "rosa:DescribeCluster", true
+		if i%3 == 0 {
+			principal, action, allowed = bob, "rosa:DeleteCluster", false
+		}
+		resource := "arn:aws:rosa:us-east-1:123456789012:cluster/c1"
+		if i%2 == 0 {
+			resource = "arn:aws:rosa:us-east-1:123456789012:cluster/c2"
+		}
+		entries = append(entries, NewDecisionAuditEntry("123456789012", principal, action, resource, allowed, "policy", at.Add(time.Duration(i)*time.Minute), time.Hour))
+	}
+	entries = append(entries, NewDecisionAuditEntry("210987654321", alice, "rosa:DescribeCluster", "", true, "admin", at, time.Hour))
+
+	unwritten, err := s.Write(ctx, entries)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if len(unwritten) != 1 || unwritten[0] != entries[0] {
+		t.Fatalf("expected the unprocessed entry back, got %d entries", len(unwritten))
+	}
+	if _, err := s.Write(ctx, unwritten); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	t.Run("pages through a time range newest first", func(t *testing.T) {
+		q := DecisionAuditQuery{From: at.Add(10 * time.Minute), To: at.Add(19 * time.Minute)}
+		page, err := s.Query(ctx, "123456789012", q, 6, "")
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		if len(page.Entries) != 6 || page.Entries[0].Time != "2026-10-15T13:19:00Z" || page.NextToken == "" {
+			t.Fatalf("expected a full first page from 13:19, got %d entries and token %q", len(page.Entries), page.NextToken)
+		}
+		page, err = s.Query(ctx, "123456789012", q, 6, page.NextToken)
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		if len(page.Entries) != 4 || page.Entries[3].Time != "2026-10-15T13:10:00Z" || page.NextToken != "" {
+			t.Errorf("expected the last 4 entries down to 13:10, got %d entries and token %q", len(page.Entries), page.NextToken)
+		}
+	})
+
+	t.Run("filters fill a page across reads", func(t *testing.T) {
+		q := DecisionAuditQuery{Decision: DecisionDeny, ResourcePrefix: "arn:aws:rosa:us-east-1:123456789012:cluster/c2"}
+		page, err := s.Query(ctx, "123456789012", q, 3, "")
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		if len(page.Entries) != 3 || page.NextToken == "" {
+			t.Fatalf("expected a full page, got %d entries", len(page.Entries))
+		}
+		for _, e := range page.Entries {
+			if e.Decision != DecisionDeny || e.PrincipalARN != bob || !strings.HasSuffix(e.Resource, "/c2") {
+				t.Errorf("unexpected entry %+v", e)
+			}
+		}
+		if filter := aws.ToString(c.queries[len(c.queries)-1].FilterExpression); filter != "decision = :decision AND begins_with(#resource, :resource)" {
+			t.Errorf("unexpected filter %q", filter)
+		}
+	})
+
+	t.Run("principal queries use the index", func(t *testing.T) {
+		page, err := s.Query(ctx, "123456789012", DecisionAuditQuery{PrincipalARN: bob}, 50, "")
+		if err != nil {
+			t.Fatalf("Query: %v", err)
+		}
+		if len(page.Entries) != 10 {
+			t.Errorf("expected bob's 10 decisions, got %d", len(page.Entries))
+		}
+		query := c.queries[len(c.queries)-1]
+		if aws.ToString(query.IndexName) != "principal-index" || aws.ToString(query.KeyConditionExpression) != "#pk = :pk" {
+			t.Errorf("unexpected query %+v", query)
+		}
+	})
+
+	t.Run("rejects foreign page tokens", func(t *testing.T) {
+		if _, err := s.Query(ctx, "123456789012", DecisionAuditQuery{}, 5, "not base64!"); !errors.Is(err, ErrInvalidPageToken) {
+			t.Errorf("expected ErrInvalidPageToken, got %v", err)
+		}
+		key, err := decodeDecisionAuditPageToken(base64.RawURLEncoding.EncodeToString([]byte("x")), "123456789012", bob)
+		if err != nil || key["accountId#principalArn"] == nil {
+			t.Errorf("expected an index start key, got %v and %v", key, err)
+		}
+	})
+}
@@ -0,0 +1,286 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// DecisionHourFormat is the format of DecisionCount.Hour, which sorts
+// chronologically
+const DecisionHourFormat = "2006-01-02T15Z"
+
+// DecisionCount is the number of authorization decisions made for one action
+// of an account during one hour. Replicas add their counts to the same item,
+// so it holds the total across replicas. The table expires counts through
+// DynamoDB TTL on expiresAt.
+type DecisionCount struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	// BucketID sorts counts by hour, then action: hour#action
+	BucketID string `dynamodbav:"bucketId" json:"-"`
+	Hour     string `dynamodbav:"hour" json:"hour"`
+	Action   string `dynamodbav:"action" json:"action"`
+	Allow    int64  `dynamodbav:"allow" json:"allow"`
+	Deny     int64  `dynamodbav:"deny" json:"deny"`
+	// ExpiresAt is the Unix time after which DynamoDB removes the count
+	ExpiresAt int64 `dynamodbav:"expiresAt" json:"-"`
+}
+
+// policyUsagePrefix starts the sort key of policy usage items, which sort
+// apart from the hourly counts
+const policyUsagePrefix = "policy#"
+
+// PolicyUsage records when an AVP policy of an account, such as an
+// attachment, last determined an authorization decision. It is kept in the
+// decision counts table and expires retention after that decision.
+type PolicyUsage struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	// BucketID is policy#policyId
+	BucketID      string `dynamodbav:"bucketId" json:"-"`
+	PolicyID      string `dynamodbav:"policyId" json:"policyId"`
+	LastMatchedAt string `dynamodbav:"lastMatchedAt" json:"lastMatchedAt"`
+	ExpiresAt     int64  `dynamodbav:"expiresAt" json:"-"`
+}
+
+// principalSeenPrefix starts the sort key of principal sightings
+const principalSeenPrefix = "principal#"
+
+// PrincipalSighting records when a caller ARN was last seen in an
+// authorization decision of an account. It is kept in the decision counts
+// table and expires retention after that decision.
+type PrincipalSighting struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	// BucketID is principal#principalArn
+	BucketID     string `dynamodbav:"bucketId" json:"-"`
+	PrincipalARN string `dynamodbav:"principalArn" json:"principalArn"`
+	LastSeenAt   string `dynamodbav:"lastSeenAt" json:"lastSeenAt"`
+	ExpiresAt    int64  `dynamodbav:"expiresAt" json:"-"`
+}
+
+// DecisionCountStore adds to and lists hourly decision counts
+type DecisionCountStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+}
+
+// NewDecisionCountStore creates a new decision count store
+func NewDecisionCountStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DecisionCountStore {
+	return &DecisionCountStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+	}
+}
+
+// Add atomically adds allow and deny decisions to an account's count for
+// action during hour, creating the count if needed. The count expires
+// retention after the end of its hour.
+func (s *DecisionCountStore) Add(ctx context.Context, accountID string, hour time.Time, action string, allow, deny int64, retention time.Duration) error {
+	hour = hour.UTC().Truncate(time.Hour)
+	label := hour.Format(DecisionHourFormat)
+
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"bucketId":  &types.AttributeValueMemberS{Value: label + "#" + action},
+		},
+		UpdateExpression: aws.String("SET #hour = :hour, #action = :action, expiresAt = :exp ADD #allow :allow, #deny :deny"),
+		// hour and action are reserved words in DynamoDB expressions
+		ExpressionAttributeNames: map[string]string{
+			"#hour":   "hour",
+			"#action": "action",
+			"#allow":  "allow",
+			"#deny":   "deny",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":hour":   &types.AttributeValueMemberS{Value: label},
+			":action": &types.AttributeValueMemberS{Value: action},
+			":exp":    &types.AttributeValueMemberN{Value: strconv.FormatInt(hour.Add(time.Hour+retention).Unix(), 10)},
+			":allow":  &types.AttributeValueMemberN{Value: strconv.FormatInt(allow, 10)},
+			":deny":   &types.AttributeValueMemberN{Value: strconv.FormatInt(deny, 10)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add decision count: %w", err)
+	}
+	return nil
+}
+
+// List returns an account's counts for the hours from from through to,
+// oldest first and by action within an hour
+func (s *DecisionCountStore) List(ctx context.Context, accountID string, from, to time.Time) ([]*DecisionCount, error) {
+	// '$' sorts after '#', so the upper bound takes in every action of the
+	// last hour
+	lower := from.UTC().Truncate(time.Hour).Format(DecisionHourFormat) + "#"
+	upper := to.UTC().Truncate(time.Hour).Format(DecisionHourFormat) + "$"
+
+	counts := []*DecisionCount{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("accountId = :aid AND bucketId BETWEEN :lower AND :upper"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid":   &types.AttributeValueMemberS{Value: accountID},
+				":lower": &types.AttributeValueMemberS{Value: lower},
+				":upper": &types.AttributeValueMemberS{Value: upper},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list decision counts: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var count DecisionCount
+			if err := attributevalue.UnmarshalMap(item, &count); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal decision count: %w", err)
+			}
+			counts = append(counts, &count)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return counts, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// MarkMatched records that policyID determined a decision of the account at
+// at, unless a later match is already recorded
+func (s *DecisionCountStore) MarkMatched(ctx context.Context, accountID, policyID string, at time.Time, retention time.Duration) error {
+	at = at.UTC()
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"bucketId":  &types.AttributeValueMemberS{Value: policyUsagePrefix + policyID},
+		},
+		UpdateExpression: aws.String("SET policyId = :pid, lastMatchedAt = :at, expiresAt = :exp"),
+		// Replicas flush independently, so an older match must not
+		// overwrite a newer one; RFC3339 UTC times compare as strings
+		ConditionExpression: aws.String("attribute_not_exists(lastMatchedAt) OR lastMatchedAt < :at"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pid": &types.AttributeValueMemberS{Value: policyID},
+			":at":  &types.AttributeValueMemberS{Value: at.Format(time.RFC3339)},
+			":exp": &types.AttributeValueMemberN{Value: strconv.FormatInt(at.Add(retention).Unix(), 10)},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record policy match: %w", err)
+	}
+	return nil
+}
+
+// ListPolicyUsage returns when each of an account's policies last
+// determined a decision. Policies that have not within the retention have
+// no usage.
+func (s *DecisionCountStore) ListPolicyUsage(ctx context.Context, accountID string) ([]*PolicyUsage, error) {
+	usage := []*PolicyUsage{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("accountId = :aid AND begins_with(bucketId, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid":    &types.AttributeValueMemberS{Value: accountID},
+				":prefix": &types.AttributeValueMemberS{Value: policyUsagePrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy usage: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var u PolicyUsage
+			if err := attributevalue.UnmarshalMap(item, &u); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal policy usage: %w", err)
+			}
+			usage = append(usage, &u)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return usage, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// MarkSeen records that principalARN called for the account at at, unless
+// a later call is already recorded
+func (s *DecisionCountStore) MarkSeen(ctx context.Context, accountID, principalARN string, at time.Time, retention time.Duration) error {
+	at = at.UTC()
+	_, err := s.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"bucketId":  &types.AttributeValueMemberS{Value: principalSeenPrefix + principalARN},
+		},
+		UpdateExpression:    aws.String("SET principalArn = :arn, lastSeenAt = :at, expiresAt = :exp"),
+		ConditionExpression: aws.String("attribute_not_exists(lastSeenAt) OR lastSeenAt < :at"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":arn": &types.AttributeValueMemberS{Value: principalARN},
+			":at":  &types.AttributeValueMemberS{Value: at.Format(time.RFC3339)},
+			":exp": &types.AttributeValueMemberN{Value: strconv.FormatInt(at.Add(retention).Unix(), 10)},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record principal sighting: %w", err)
+	}
+	return nil
+}
+
+// ListPrincipals returns the principals seen calling for an account within
+// the retention, by ARN
+func (s *DecisionCountStore) ListPrincipals(ctx context.Context, accountID string) ([]*PrincipalSighting, error) {
+	sightings := []*PrincipalSighting{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("accountId = :aid AND begins_with(bucketId, :prefix)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid":    &types.AttributeValueMemberS{Value: accountID},
+				":prefix": &types.AttributeValueMemberS{Value: principalSeenPrefix},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list principal sightings: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var p PrincipalSighting
+			if err := attributevalue.UnmarshalMap(item, &p); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal principal sighting: %w", err)
+			}
+			sightings = append(sightings, &p)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return sightings, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
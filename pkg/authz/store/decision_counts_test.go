@@ -0,0 +1,196 @@
+package store
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// decisionCountClient applies decision count updates like DynamoDB, adding
+// to existing counts and keeping the latest policy match or principal
+// sighting, and serves items
+// in sort key order within the queried range or prefix
+type decisionCountClient struct {
+	client.DynamoDBClient
+	items map[string]map[string]types.AttributeValue
+}
+
+func (c *decisionCountClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	key := params.Key["accountId"].(*types.AttributeValueMemberS).Value + "|" + params.Key["bucketId"].(*types.AttributeValueMemberS).Value
+	item, ok := c.items[key]
+	if !ok {
+		item = map[string]types.AttributeValue{"accountId": params.Key["accountId"], "bucketId": params.Key["bucketId"]}
+		c.items[key] = item
+	}
+	values := params.ExpressionAttributeValues
+	if at, ok := values[":at"].(*types.AttributeValueMemberS); ok {
+		idAttr, idValue, atAttr := "policyId", ":pid", "lastMatchedAt"
+		if _, ok := values[":arn"]; ok {
+			idAttr, idValue, atAttr = "principalArn", ":arn", "lastSeenAt"
+		}
+		if last, ok := item[atAttr].(*types.AttributeValueMemberS); ok && last.Value >= at.Value {
+			return nil, &types.ConditionalCheckFailedException{}
+		}
+		item[idAttr], item[atAttr], item["expiresAt"] = values[idValue], at, values[":exp"]
+		return &dynamodb.UpdateItemOutput{}, nil
+	}
+	item["hour"], item["action"], item["expiresAt"] = values[":hour"], values[":action"], values[":exp"]
+	for _, attr := range []string{"allow", "deny"} {
+		var n int64
+		if v, ok := item[attr].(*types.AttributeValueMemberN); ok {
+			n, _ = strconv.ParseInt(v.Value, 10, 64)
+		}
+		add, _ := strconv.ParseInt(values[":"+attr].(*types.AttributeValueMemberN).Value, 10, 64)
+		item[attr] = &types.AttributeValueMemberN{Value: strconv.FormatInt(n+add, 10)}
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (c *decisionCountClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	values := params.ExpressionAttributeValues
+	accountID := values[":aid"].(*types.AttributeValueMemberS).Value
+	var lower, upper string
+	if prefix, ok := values[":prefix"].(*types.AttributeValueMemberS); ok {
+		lower, upper = prefix.Value, prefix.Value+"\xff"
+	} else {
+		lower, upper = values[":lower"].(*types.AttributeValueMemberS).Value, values[":upper"].(*types.AttributeValueMemberS).Value
+	}
+
+	out := &dynamodb.QueryOutput{}
+	for _, item := range c.items {
+		bucketID := item["bucketId"].(*types.AttributeValueMemberS).Value
+		if item["accountId"].(*types.AttributeValueMemberS).Value == accountID && bucketID >= lower && bucketID <= upper {
+			out.Items = append(out.Items, item)
+		}
+	}
+	sort.Slice(out.Items, func(i, j int) bool {
+		return out.Items[i]["bucketId"].(*types.AttributeValueMemberS).Value < out.Items[j]["bucketId"].(*types.AttributeValueMemberS).Value
+	})
+	return out, nil
+}
+
+func TestDecisionCountStore_AddAndList(t *testing.T) {
+	c := &decisionCountClient{items: map[string]map[string]types.AttributeValue{}}
+	s := NewDecisionCountStore("decision-counts", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	hour := time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)
+
+	adds := []struct {
+		accountID   string
+		at          time.Time
+		action      string
+		allow, deny int64
+	}{
+		{"123456789012", hour.Add(5 * time.Minute), "rosa:CreateCluster", 2, 1},
+		// A second replica's counts for the same hour add up
+		{"123456789012", hour.Add(40 * time.Minute), "rosa:CreateCluster", 3, 0},
+		{"123456789012", hour.Add(10 * time.Minute), "rosa:DeleteCluster", 0, 4},
+		{"123456789012", hour.Add(2 * time.Hour), "rosa:CreateCluster", 1, 0},
+		{"210987654321", hour, "rosa:CreateCluster", 9, 9},
+	}
+	for _, a := range adds {
+		if err := s.Add(ctx, a.accountID, a.at, a.action, a.allow, a.deny, time.Hour); err != nil {
+			t.Fatalf("Add: %v", err)
+		}
+	}
+
+	counts, err := s.List(ctx, "123456789012", hour.Add(30*time.Minute), hour.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("expected the 2 counts of the first hour, got %d", len(counts))
+	}
+	create, del := counts[0], counts[1]
+	if create.Hour != "2026-10-15T13Z" || create.Action != "rosa:CreateCluster" || create.Allow != 5 || create.Deny != 1 {
+		t.Errorf("unexpected create count %+v", create)
+	}
+	if del.Action != "rosa:DeleteCluster" || del.Allow != 0 || del.Deny != 4 {
+		t.Errorf("unexpected delete count %+v", del)
+	}
+	if want := hour.Add(2 * time.Hour).Unix(); create.ExpiresAt != want {
+		t.Errorf("expected the count to expire retention after its hour ends (%d), got %d", want, create.ExpiresAt)
+	}
+}
+
+func TestDecisionCountStore_PolicyUsage(t *testing.T) {
+	c := &decisionCountClient{items: map[string]map[string]types.AttributeValue{}}
+	s := NewDecisionCountStore("decision-counts", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	at := time.Date(2026, 10, 15, 13, 25, 0, 0, time.UTC)
+
+	if err := s.Add(ctx, "123456789012", at, "rosa:CreateCluster", 1, 0, time.Hour); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if err := s.MarkMatched(ctx, "123456789012", "attachment-1", at, time.Hour); err != nil {
+		t.Fatalf("MarkMatched: %v", err)
+	}
+	// A replica flushing an older match does not move the time back
+	if err := s.MarkMatched(ctx, "123456789012", "attachment-1", at.Add(-time.Hour), time.Hour); err != nil {
+		t.Fatalf("MarkMatched: %v", err)
+	}
+
+	usage, err := s.ListPolicyUsage(ctx, "123456789012")
+	if err != nil {
+		t.Fatalf("ListPolicyUsage: %v", err)
+	}
+	if len(usage) != 1 || usage[0].PolicyID != "attachment-1" || usage[0].LastMatchedAt != "2026-10-15T13:25:00Z" {
+		t.Fatalf("expected attachment-1 last matched at 13:25, got %+v", usage)
+	}
+
+	// Policy usage items sort apart from the hourly counts
+	counts, err := s.List(ctx, "123456789012", at, at)
+	if err != nil || len(counts) != 1 {
+		t.Errorf("expected only the hourly count to be listed, got %d counts and %v", len(counts), err)
+	}
+}
+
+func TestDecisionCountStore_Principals(t *testing.T) {
+	c := &decisionCountClient{items: map[string]map[string]types.AttributeValue{}}
+	s := NewDecisionCountStore("decision-counts", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+	at := time.Date(2026, 10, 15, 13, 25, 0, 0, time.UTC)
+
+	if err := s.MarkMatched(ctx, "123456789012", "attachment-1", at, time.Hour); err != nil {
+		t.Fatalf("MarkMatched: %v", err)
+	}
+	sightings := []struct {
+		accountID string
+		arn       string
+		at        time.Time
+	}{
+		{"123456789012", "arn:aws:iam::123456789012:role/dev", at},
+		// A replica flushing an older sighting does not move the time back
+		{"123456789012", "arn:aws:iam::123456789012:role/dev", at.Add(-time.Hour)},
+		{"123456789012", "arn:aws:sts::123456789012:assumed-role/ops/alice", at.Add(-time.Minute)},
+		{"210987654321", "arn:aws:iam::210987654321:role/dev", at},
+	}
+	for _, p := range sightings {
+		if err := s.MarkSeen(ctx, p.accountID, p.arn, p.at, time.Hour); err != nil {
+			t.Fatalf("MarkSeen: %v", err)
+		}
+	}
+
+	principals, err := s.ListPrincipals(ctx, "123456789012")
+	if err != nil {
+		t.Fatalf("ListPrincipals: %v", err)
+	}
+	if len(principals) != 2 {
+		t.Fatalf("expected the account's 2 principals, got %d", len(principals))
+	}
+	if p := principals[0]; p.PrincipalARN != "arn:aws:iam::123456789012:role/dev" || p.LastSeenAt != "2026-10-15T13:25:00Z" {
+		t.Errorf("expected the role last seen at 13:25, got %+v", p)
+	}
+	if p := principals[1]; p.PrincipalARN != "arn:aws:sts::123456789012:assumed-role/ops/alice" || p.ExpiresAt != at.Add(59*time.Minute).Unix() {
+		t.Errorf("unexpected session sighting %+v", p)
+	}
+}
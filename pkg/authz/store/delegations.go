@@ -0,0 +1,182 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// AllActions in Delegation.Actions delegates every action
+const AllActions = "*"
+
+// ErrDelegationExists is returned when creating a delegation for an account
+// pair that already has one
+var ErrDelegationExists = errors.New("delegation already exists")
+
+// Delegation lets principals of a delegate account act on an owner account's
+// resources, limited to Actions. The owner account's policies still decide
+// each request; the delegation only bounds what may be attempted.
+type Delegation struct {
+	AccountID         string   `dynamodbav:"accountId" json:"accountId"`
+	DelegateAccountID string   `dynamodbav:"delegateAccountId" json:"delegateAccountId"`
+	Actions           []string `dynamodbav:"actions" json:"actions"`
+	// ExpiresAt is an optional RFC3339 time after which the delegation is ignored
+	ExpiresAt string `dynamodbav:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	CreatedAt string `dynamodbav:"createdAt" json:"createdAt"`
+	CreatedBy string `dynamodbav:"createdBy" json:"createdBy"`
+}
+
+// Expired reports whether the delegation has passed its expiry time
+func (d *Delegation) Expired(now time.Time) bool {
+	if d.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, d.ExpiresAt)
+	return err != nil || !now.Before(expiresAt)
+}
+
+// Allows reports whether action is within the delegated actions. Actions
+// are compared in canonical form, so "rosa:" prefixes and aliases match.
+func (d *Delegation) Allows(action string) bool {
+	action = schema.CanonicalAction(action)
+	return slices.ContainsFunc(d.Actions, func(delegated string) bool {
+		return delegated == AllActions || schema.CanonicalAction(delegated) == action
+	})
+}
+
+// DelegationStore provides CRUD operations for delegations
+type DelegationStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewDelegationStore creates a new delegation store
+func NewDelegationStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DelegationStore {
+	return &DelegationStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *DelegationStore) WithClock(now clock.Clock) *DelegationStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Create stores a delegation, failing if one already exists for the pair
+func (s *DelegationStore) Create(ctx context.Context, delegation *Delegation) error {
+	if delegation.CreatedAt == "" {
+		delegation.CreatedAt = clock.Format(s.now())
+	}
+
+	item, err := attributevalue.MarshalMap(delegation)
+	if err != nil {
+		return fmt.Errorf("failed to marshal delegation: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(accountId) AND attribute_not_exists(delegateAccountId)"),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return fmt.Errorf("%w: %s to %s", ErrDelegationExists, delegation.AccountID, delegation.DelegateAccountID)
+		}
+		return fmt.Errorf("failed to create delegation: %w", err)
+	}
+
+	s.logger.Info("delegation created",
+		"account_id", delegation.AccountID,
+		"delegate_account_id", delegation.DelegateAccountID,
+		"actions", delegation.Actions,
+		"expires_at", delegation.ExpiresAt,
+		"created_by", delegation.CreatedBy,
+	)
+	return nil
+}
+
+// Get returns the delegation from accountID to delegateAccountID, or nil if none exists
+func (s *DelegationStore) Get(ctx context.Context, accountID, delegateAccountID string) (*Delegation, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId":         &types.AttributeValueMemberS{Value: accountID},
+			"delegateAccountId": &types.AttributeValueMemberS{Value: delegateAccountID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get delegation: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var delegation Delegation
+	if err := attributevalue.UnmarshalMap(result.Item, &delegation); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal delegation: %w", err)
+	}
+
+	return &delegation, nil
+}
+
+// Delete removes the delegation from accountID to delegateAccountID
+func (s *DelegationStore) Delete(ctx context.Context, accountID, delegateAccountID string) error {
+	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId":         &types.AttributeValueMemberS{Value: accountID},
+			"delegateAccountId": &types.AttributeValueMemberS{Value: delegateAccountID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete delegation: %w", err)
+	}
+
+	s.logger.Info("delegation deleted", "account_id", accountID, "delegate_account_id", delegateAccountID)
+	return nil
+}
+
+// List returns all delegations granted by an account
+func (s *DelegationStore) List(ctx context.Context, accountID string) ([]*Delegation, error) {
+	result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("accountId = :aid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":aid": &types.AttributeValueMemberS{Value: accountID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list delegations: %w", err)
+	}
+
+	delegations := make([]*Delegation, 0, len(result.Items))
+	for _, item := range result.Items {
+		var delegation Delegation
+		if err := attributevalue.UnmarshalMap(item, &delegation); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal delegation: %w", err)
+		}
+		delegations = append(delegations, &delegation)
+	}
+
+	return delegations, nil
+}
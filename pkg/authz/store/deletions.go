@@ -0,0 +1,146 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Kinds of deleted resources recorded in Tombstone.Kind
+const (
+	DeletedPolicy     = "policy"
+	DeletedGroup      = "group"
+	DeletedAttachment = "attachment"
+)
+
+// Tombstone records a deleted authz resource for forensic review. The table
+// expires tombstones through DynamoDB TTL on expiresAt.
+type Tombstone struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	// DeletionID sorts tombstones by deletion time: deletedAt#kind#resourceId
+	DeletionID string `dynamodbav:"deletionId" json:"deletionId"`
+	Kind       string `dynamodbav:"kind" json:"kind"`
+	ResourceID string `dynamodbav:"resourceId" json:"resourceId"`
+	// Object is the resource as it was just before deletion
+	Object    json.RawMessage `dynamodbav:"object" json:"object"`
+	DeletedBy string          `dynamodbav:"deletedBy" json:"deletedBy"`
+	DeletedAt string          `dynamodbav:"deletedAt" json:"deletedAt"`
+	// ExpiresAt is the Unix time after which DynamoDB removes the tombstone
+	ExpiresAt int64 `dynamodbav:"expiresAt" json:"expiresAt"`
+}
+
+// DeletionStore records and lists tombstones
+type DeletionStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewDeletionStore creates a new deletion store
+func NewDeletionStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DeletionStore {
+	return &DeletionStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *DeletionStore) WithClock(now clock.Clock) *DeletionStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Record stores a tombstone that expires after retention
+func (s *DeletionStore) Record(ctx context.Context, tombstone *Tombstone, retention time.Duration) error {
+	now := s.now().UTC()
+	tombstone.DeletedAt = clock.Format(now)
+	// The ID keeps the full precision, so tombstones of the same second
+	// still sort by deletion time
+	tombstone.DeletionID = now.Format(time.RFC3339Nano) + "#" + tombstone.Kind + "#" + tombstone.ResourceID
+	tombstone.ExpiresAt = now.Add(retention).Unix()
+
+	item, err := attributevalue.MarshalMap(tombstone)
+	if err != nil {
+		return fmt.Errorf("failed to marshal tombstone: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record deletion: %w", err)
+	}
+
+	s.logger.Info("deletion recorded",
+		"account_id", tombstone.AccountID,
+		"kind", tombstone.Kind,
+		"resource_id", tombstone.ResourceID,
+		"deleted_by", tombstone.DeletedBy,
+	)
+	return nil
+}
+
+// List returns an account's unexpired tombstones, newest first, optionally
+// limited to one kind. A limit of zero returns them all.
+func (s *DeletionStore) List(ctx context.Context, accountID, kind string, limit int) ([]*Tombstone, error) {
+	// TTL removal lags expiry, so expired tombstones are filtered out here
+	filter := "expiresAt > :now"
+	var names map[string]string
+	values := map[string]types.AttributeValue{
+		":aid": &types.AttributeValueMemberS{Value: accountID},
+		":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Unix(), 10)},
+	}
+	if kind != "" {
+		filter += " AND #kind = :kind"
+		names = map[string]string{"#kind": "kind"}
+		values[":kind"] = &types.AttributeValueMemberS{Value: kind}
+	}
+
+	tombstones := []*Tombstone{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:                 aws.String(s.tableName),
+			KeyConditionExpression:    aws.String("accountId = :aid"),
+			FilterExpression:          aws.String(filter),
+			ExpressionAttributeNames:  names,
+			ExpressionAttributeValues: values,
+			ScanIndexForward:          aws.Bool(false),
+			ExclusiveStartKey:         startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list deletions: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var tombstone Tombstone
+			if err := attributevalue.UnmarshalMap(item, &tombstone); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal tombstone: %w", err)
+			}
+			tombstones = append(tombstones, &tombstone)
+			if limit > 0 && len(tombstones) == limit {
+				return tombstones, nil
+			}
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return tombstones, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,130 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// tombstoneClient keeps recorded tombstones and serves them newest first,
+// one per Query page, applying the expiry filter like DynamoDB
+type tombstoneClient struct {
+	client.DynamoDBClient
+	items []map[string]types.AttributeValue
+}
+
+func (c *tombstoneClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	c.items = append([]map[string]types.AttributeValue{params.Item}, c.items...)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (c *tombstoneClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	start := 0
+	if key, ok := params.ExclusiveStartKey["deletionId"].(*types.AttributeValueMemberS); ok {
+		for i, item := range c.items {
+			if item["deletionId"].(*types.AttributeValueMemberS).Value == key.Value {
+				start = i + 1
+			}
+		}
+	}
+	if start >= len(c.items) {
+		return &dynamodb.QueryOutput{}, nil
+	}
+
+	item := c.items[start]
+	out := &dynamodb.QueryOutput{
+		LastEvaluatedKey: map[string]types.AttributeValue{"deletionId": item["deletionId"]},
+	}
+	now, _ := strconv.ParseInt(params.ExpressionAttributeValues[":now"].(*types.AttributeValueMemberN).Value, 10, 64)
+	expiresAt, _ := strconv.ParseInt(item["expiresAt"].(*types.AttributeValueMemberN).Value, 10, 64)
+	if expiresAt > now {
+		out.Items = append(out.Items, item)
+	}
+	return out, nil
+}
+
+func TestDeletionStore_RecordAndList(t *testing.T) {
+	c := &tombstoneClient{}
+	s := NewDeletionStore("deletions", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	for _, id := range []string{"p-1", "p-2", "p-3"} {
+		if err := s.Record(ctx, &Tombstone{
+			AccountID:  "123456789012",
+			Kind:       DeletedPolicy,
+			ResourceID: id,
+			Object:     json.RawMessage(`{"policyId":"` + id + `"}`),
+			DeletedBy:  "arn:aws:iam::123456789012:user/alice",
+		}, time.Hour); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	// An expired tombstone that TTL has not removed yet
+	expired, err := attributevalue.MarshalMap(&Tombstone{AccountID: "123456789012", DeletionID: "old", Kind: DeletedGroup, ExpiresAt: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.items = append(c.items, expired)
+
+	all, err := s.List(ctx, "123456789012", "", 0)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 3 || all[0].ResourceID != "p-3" {
+		t.Fatalf("expected 3 unexpired tombstones newest first, got %d", len(all))
+	}
+	if !strings.HasSuffix(all[0].DeletionID, "#policy#p-3") || string(all[0].Object) != `{"policyId":"p-3"}` {
+		t.Errorf("unexpected tombstone %+v", all[0])
+	}
+
+	limited, err := s.List(ctx, "123456789012", "", 2)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(limited) != 2 {
+		t.Errorf("expected the limit to stop the query at 2 tombstones, got %d", len(limited))
+	}
+}
+
+func TestDeletionStore_Record_Clock(t *testing.T) {
+	c := &tombstoneClient{}
+	at := time.Date(2026, 10, 15, 15, 0, 0, 123456789, time.FixedZone("CEST", 2*60*60))
+	s := NewDeletionStore("deletions", c, slog.New(slog.NewTextHandler(io.Discard, nil))).WithClock(func() time.Time { return at })
+
+	tombstone := &Tombstone{AccountID: "123456789012", Kind: DeletedPolicy, ResourceID: "p-1"}
+	if err := s.Record(context.Background(), tombstone, time.Hour); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// The API sees the time in UTC to the second; the ID keeps its full
+	// precision so it still sorts
+	if tombstone.DeletedAt != "2026-10-15T13:00:00Z" {
+		t.Errorf("DeletedAt = %q, want the injected time in UTC", tombstone.DeletedAt)
+	}
+	if tombstone.DeletionID != "2026-10-15T13:00:00.123456789Z#policy#p-1" {
+		t.Errorf("DeletionID = %q", tombstone.DeletionID)
+	}
+	if tombstone.ExpiresAt != at.Add(time.Hour).Unix() {
+		t.Errorf("ExpiresAt = %d, want an hour after the injected time", tombstone.ExpiresAt)
+	}
+
+	var stored Tombstone
+	if err := attributevalue.UnmarshalMap(c.items[0], &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored.DeletedAt != tombstone.DeletedAt {
+		t.Errorf("stored DeletedAt = %q, want %q", stored.DeletedAt, tombstone.DeletedAt)
+	}
+}
@@ -13,15 +13,22 @@ import (
 	"github.com/google/uuid"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
 )
 
 // Group represents an authorization group
 type Group struct {
-	AccountID   string `dynamodbav:"accountId" json:"accountId"`
-	GroupID     string `dynamodbav:"groupId" json:"groupId"`
-	Name        string `dynamodbav:"name" json:"name"`
-	Description string `dynamodbav:"description,omitempty" json:"description,omitempty"`
-	CreatedAt   string `dynamodbav:"createdAt" json:"createdAt"`
+	AccountID   string            `dynamodbav:"accountId" json:"accountId"`
+	GroupID     string            `dynamodbav:"groupId" json:"groupId"`
+	Name        string            `dynamodbav:"name" json:"name"`
+	Description string            `dynamodbav:"description,omitempty" json:"description,omitempty"`
+	Tags        map[string]string `dynamodbav:"tags,omitempty" json:"tags,omitempty"`
+	CreatedAt   string            `dynamodbav:"createdAt" json:"createdAt"`
+	// MembersVersion counts the member updates, so concurrent ones conflict
+	MembersVersion int64 `dynamodbav:"membersVersion,omitempty" json:"membersVersion,omitempty"`
+	// MemberPattern marks a pattern group, whose members are the principals
+	// seen matching this ARN pattern
+	MemberPattern string `dynamodbav:"memberPattern,omitempty" json:"memberPattern,omitempty"`
 }
 
 // GroupStore provides CRUD operations for groups
@@ -29,6 +36,7 @@ type GroupStore struct {
 	tableName    string
 	dynamoClient client.DynamoDBClient
 	logger       *slog.Logger
+	now          clock.Clock
 }
 
 // NewGroupStore creates a new group store
@@ -37,17 +45,25 @@ func NewGroupStore(tableName string, dynamoClient client.DynamoDBClient, logger
 		tableName:    tableName,
 		dynamoClient: dynamoClient,
 		logger:       logger,
+		now:          time.Now,
 	}
 }
 
+// WithClock sets the clock the store stamps times with
+func (s *GroupStore) WithClock(now clock.Clock) *GroupStore {
+	s.now = clock.Or(now)
+	return s
+}
+
 // Create creates a new group
-func (s *GroupStore) Create(ctx context.Context, accountID, name, description string) (*Group, error) {
+func (s *GroupStore) Create(ctx context.Context, accountID, name, description string, tags map[string]string) (*Group, error) {
 	group := &Group{
 		AccountID:   accountID,
 		GroupID:     uuid.New().String(),
 		Name:        name,
 		Description: description,
-		CreatedAt:   time.Now().UTC().Format(time.RFC3339),
+		Tags:        tags,
+		CreatedAt:   clock.Format(s.now()),
 	}
 
 	item, err := attributevalue.MarshalMap(group)
@@ -67,6 +83,40 @@ func (s *GroupStore) Create(ctx context.Context, accountID, name, description st
 	return group, nil
 }
 
+// CreatePatternGroup creates the group groupID holding the principals that
+// match pattern. Creating a group that exists returns the existing one.
+func (s *GroupStore) CreatePatternGroup(ctx context.Context, accountID, groupID, pattern string) (*Group, error) {
+	group := &Group{
+		AccountID:     accountID,
+		GroupID:       groupID,
+		Name:          "pattern:" + pattern,
+		Description:   "Principals matching " + pattern,
+		MemberPattern: pattern,
+		CreatedAt:     clock.Format(s.now()),
+	}
+
+	item, err := attributevalue.MarshalMap(group)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal group: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(groupId)"),
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if isConditionalCheckFailed(err, &condErr) {
+		return s.Get(ctx, accountID, groupID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+
+	s.logger.Info("pattern group created", "account_id", accountID, "group_id", groupID, "pattern", pattern)
+	return group, nil
+}
+
 // Get retrieves a group by ID
 func (s *GroupStore) Get(ctx context.Context, accountID, groupID string) (*Group, error) {
 	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
@@ -164,3 +214,43 @@ func (s *GroupStore) Update(ctx context.Context, accountID, groupID, name, descr
 	s.logger.Info("group updated", "account_id", accountID, "group_id", groupID)
 	return &group, nil
 }
+
+// SetTags replaces a group's tags, or removes them when tags is empty. It
+// returns nil if the group does not exist.
+func (s *GroupStore) SetTags(ctx context.Context, accountID, groupID string, tags map[string]string) (*Group, error) {
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"groupId":   &types.AttributeValueMemberS{Value: groupID},
+		},
+		UpdateExpression:    aws.String("REMOVE tags"),
+		ConditionExpression: aws.String("attribute_exists(groupId)"),
+		ReturnValues:        types.ReturnValueAllNew,
+	}
+	if len(tags) > 0 {
+		av, err := attributevalue.Marshal(tags)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal group tags: %w", err)
+		}
+		input.UpdateExpression = aws.String("SET tags = :tags")
+		input.ExpressionAttributeValues = map[string]types.AttributeValue{":tags": av}
+	}
+
+	result, err := s.dynamoClient.UpdateItem(ctx, input)
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if ok := isConditionalCheckFailed(err, &condErr); ok {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to update group tags: %w", err)
+	}
+
+	var group Group
+	if err := attributevalue.UnmarshalMap(result.Attributes, &group); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal group: %w", err)
+	}
+
+	s.logger.Info("group tags updated", "account_id", accountID, "group_id", groupID)
+	return &group, nil
+}
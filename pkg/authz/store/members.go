@@ -2,8 +2,10 @@ package store
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -12,6 +14,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
 )
 
 // GroupMember represents a group membership
@@ -28,9 +31,13 @@ type GroupMember struct {
 
 // MemberStore provides CRUD operations for group members
 type MemberStore struct {
-	tableName    string
-	dynamoClient client.DynamoDBClient
-	logger       *slog.Logger
+	tableName string
+	// groupsTableName holds the groups, whose members version UpdateMembers
+	// checks and bumps
+	groupsTableName string
+	dynamoClient    client.DynamoDBClient
+	logger          *slog.Logger
+	now             clock.Clock
 }
 
 // NewMemberStore creates a new member store
@@ -39,9 +46,22 @@ func NewMemberStore(tableName string, dynamoClient client.DynamoDBClient, logger
 		tableName:    tableName,
 		dynamoClient: dynamoClient,
 		logger:       logger,
+		now:          time.Now,
 	}
 }
 
+// WithClock sets the clock the store stamps times with
+func (s *MemberStore) WithClock(now clock.Clock) *MemberStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// WithGroupsTable sets the groups table UpdateMembers versions members in
+func (s *MemberStore) WithGroupsTable(tableName string) *MemberStore {
+	s.groupsTableName = tableName
+	return s
+}
+
 // Add adds a member to a group
 func (s *MemberStore) Add(ctx context.Context, accountID, groupID, memberARN string) error {
 	member := &GroupMember{
@@ -49,7 +69,7 @@ func (s *MemberStore) Add(ctx context.Context, accountID, groupID, memberARN str
 		GroupIDMemberARN:   fmt.Sprintf("%s#%s", groupID, memberARN),
 		GroupID:            groupID,
 		MemberARN:          memberARN,
-		AddedAt:            time.Now().UTC().Format(time.RFC3339),
+		AddedAt:            clock.Format(s.now()),
 		AccountIDMemberARN: fmt.Sprintf("%s#%s", accountID, memberARN),
 	}
 
@@ -87,6 +107,127 @@ func (s *MemberStore) Remove(ctx context.Context, accountID, groupID, memberARN
 	return nil
 }
 
+// ErrMembersConflict is returned when a group's members changed since the
+// version an update was based on
+var ErrMembersConflict = errors.New("group members were modified concurrently")
+
+// maxTransactMemberWrites is the most member writes in one transaction:
+// DynamoDB accepts 100 items, one of which bumps the group's version
+const maxTransactMemberWrites = 99
+
+// UpdateMembers adds and removes members of a group in transactions that
+// each also bump the group's members version, conditioned on it being
+// version. The same ARN must not appear in both add and remove. When the
+// first transaction finds a different version it returns ErrMembersConflict
+// and nothing is written. Updates of more than 99 members span several
+// transactions; the members of a later one that fails are returned with the
+// reason. It returns the group's new members version.
+func (s *MemberStore) UpdateMembers(ctx context.Context, accountID, groupID string, version int64, add, remove []string) (int64, map[string]error, error) {
+	addedAt := clock.Format(s.now())
+	failed := map[string]error{}
+
+	type memberWrite struct {
+		memberARN string
+		item      types.TransactWriteItem
+	}
+	var writes []memberWrite
+	for _, memberARN := range add {
+		item, err := attributevalue.MarshalMap(&GroupMember{
+			AccountID:          accountID,
+			GroupIDMemberARN:   fmt.Sprintf("%s#%s", groupID, memberARN),
+			GroupID:            groupID,
+			MemberARN:          memberARN,
+			AddedAt:            addedAt,
+			AccountIDMemberARN: fmt.Sprintf("%s#%s", accountID, memberARN),
+		})
+		if err != nil {
+			failed[memberARN] = fmt.Errorf("failed to marshal member: %w", err)
+			continue
+		}
+		writes = append(writes, memberWrite{memberARN, types.TransactWriteItem{
+			Put: &types.Put{TableName: aws.String(s.tableName), Item: item},
+		}})
+	}
+	for _, memberARN := range remove {
+		writes = append(writes, memberWrite{memberARN, types.TransactWriteItem{
+			Delete: &types.Delete{
+				TableName: aws.String(s.tableName),
+				Key: map[string]types.AttributeValue{
+					"accountId":         &types.AttributeValueMemberS{Value: accountID},
+					"groupId#memberArn": &types.AttributeValueMemberS{Value: fmt.Sprintf("%s#%s", groupID, memberARN)},
+				},
+			},
+		}})
+	}
+
+	for start := 0; start < len(writes); start += maxTransactMemberWrites {
+		chunk := writes[start:min(start+maxTransactMemberWrites, len(writes))]
+		items := make([]types.TransactWriteItem, 0, len(chunk)+1)
+		for _, write := range chunk {
+			items = append(items, write.item)
+		}
+		items = append(items, s.bumpMembersVersion(accountID, groupID, version))
+
+		_, err := s.dynamoClient.TransactWriteItems(ctx, &dynamodb.TransactWriteItemsInput{TransactItems: items})
+		if err != nil {
+			if isVersionConflict(err) {
+				err = ErrMembersConflict
+				if start == 0 {
+					return 0, nil, fmt.Errorf("%w: group %s", ErrMembersConflict, groupID)
+				}
+			} else {
+				err = fmt.Errorf("failed to update group members: %w", err)
+			}
+			// Later chunks are not attempted, as their version check would fail
+			for _, write := range writes[start:] {
+				failed[write.memberARN] = err
+			}
+			break
+		}
+		version++
+	}
+
+	s.logger.Info("group members updated", "account_id", accountID, "group_id", groupID, "added", len(add), "removed", len(remove), "failed", len(failed), "version", version)
+	return version, failed, nil
+}
+
+// bumpMembersVersion is the transaction item that moves a group's members
+// version on from version, failing if it is not version
+func (s *MemberStore) bumpMembersVersion(accountID, groupID string, version int64) types.TransactWriteItem {
+	values := map[string]types.AttributeValue{
+		":next": &types.AttributeValueMemberN{Value: strconv.FormatInt(version+1, 10)},
+	}
+	// Groups created before member versions have none, which is version 0
+	condition := "attribute_exists(groupId) AND attribute_not_exists(membersVersion)"
+	if version > 0 {
+		condition = "membersVersion = :version"
+		values[":version"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(version, 10)}
+	}
+	return types.TransactWriteItem{
+		Update: &types.Update{
+			TableName: aws.String(s.groupsTableName),
+			Key: map[string]types.AttributeValue{
+				"accountId": &types.AttributeValueMemberS{Value: accountID},
+				"groupId":   &types.AttributeValueMemberS{Value: groupID},
+			},
+			UpdateExpression:          aws.String("SET membersVersion = :next"),
+			ConditionExpression:       aws.String(condition),
+			ExpressionAttributeValues: values,
+		},
+	}
+}
+
+// isVersionConflict reports whether a transaction was cancelled because the
+// group's members version check, its last item, failed
+func isVersionConflict(err error) bool {
+	var canceled *types.TransactionCanceledException
+	if !errors.As(err, &canceled) || len(canceled.CancellationReasons) == 0 {
+		return false
+	}
+	reason := canceled.CancellationReasons[len(canceled.CancellationReasons)-1]
+	return aws.ToString(reason.Code) == "ConditionalCheckFailed"
+}
+
 // ListGroupMembers returns all members of a group
 func (s *MemberStore) ListGroupMembers(ctx context.Context, accountID, groupID string) ([]string, error) {
 	result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
@@ -146,6 +287,39 @@ func (s *MemberStore) GetUserGroups(ctx context.Context, accountID, memberARN st
 	return groups, nil
 }
 
+// ListByMember returns every group membership of a principal across all
+// accounts. Uses the member-accounts-index GSI.
+func (s *MemberStore) ListByMember(ctx context.Context, memberARN string) ([]*GroupMember, error) {
+	var members []*GroupMember
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String("member-accounts-index"),
+		KeyConditionExpression: aws.String("memberArn = :arn"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":arn": &types.AttributeValueMemberS{Value: memberARN},
+		},
+	}
+	for {
+		result, err := s.dynamoClient.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list member accounts: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var member GroupMember
+			if err := attributevalue.UnmarshalMap(item, &member); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal member: %w", err)
+			}
+			members = append(members, &member)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return members, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
 // IsMember checks if a user is a member of a group
 func (s *MemberStore) IsMember(ctx context.Context, accountID, groupID, memberARN string) (bool, error) {
 	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
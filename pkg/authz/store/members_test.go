@@ -0,0 +1,205 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// transactClient records TransactWriteItems calls against a group at
+// version, checking the condition of the version update each carries like
+// DynamoDB would. Every call fails with err when set, and calls after the
+// first fail once concurrent is true, as if another update got in between.
+type transactClient struct {
+	client.DynamoDBClient
+	version      int64
+	err          error
+	concurrent   bool
+	transactions [][]types.TransactWriteItem
+}
+
+func (c *transactClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	c.transactions = append(c.transactions, params.TransactItems)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if c.concurrent && len(c.transactions) > 1 {
+		c.version++
+	}
+
+	update := params.TransactItems[len(params.TransactItems)-1].Update
+	expected := int64(0)
+	if v, ok := update.ExpressionAttributeValues[":version"].(*types.AttributeValueMemberN); ok {
+		expected, _ = strconv.ParseInt(v.Value, 10, 64)
+	}
+	if expected != c.version {
+		reasons := make([]types.CancellationReason, len(params.TransactItems))
+		for i := range reasons {
+			reasons[i].Code = aws.String("None")
+		}
+		reasons[len(reasons)-1].Code = aws.String("ConditionalCheckFailed")
+		return nil, &types.TransactionCanceledException{CancellationReasons: reasons}
+	}
+	c.version++
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func memberARNs(n int) []string {
+	arns := make([]string, n)
+	for i := range arns {
+		arns[i] = fmt.Sprintf("arn:aws:iam::123456789012:user/user-%d", i)
+	}
+	return arns
+}
+
+func TestMemberStore_UpdateMembers(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ctx := context.Background()
+
+	t.Run("splits into transactions that each bump the version", func(t *testing.T) {
+		c := &transactClient{version: 4}
+		s := NewMemberStore("members", c, logger).WithGroupsTable("groups")
+
+		version, failed, err := s.UpdateMembers(ctx, "123456789012", "admins", 4, memberARNs(120), memberARNs(1))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(failed) != 0 {
+			t.Fatalf("expected no failures, got %v", failed)
+		}
+		if version != 6 {
+			t.Errorf("expected version 6, got %d", version)
+		}
+		if len(c.transactions) != 2 || len(c.transactions[0]) != 100 || len(c.transactions[1]) != 23 {
+			t.Fatalf("expected transactions of 100 and 23 items, got %d transactions", len(c.transactions))
+		}
+		if c.transactions[1][21].Delete == nil {
+			t.Error("expected the remove to be a delete")
+		}
+		update := c.transactions[0][99].Update
+		if update == nil || aws.ToString(update.TableName) != "groups" || aws.ToString(update.ConditionExpression) != "membersVersion = :version" {
+			t.Errorf("expected a conditional version update of the group, got %+v", update)
+		}
+	})
+
+	t.Run("groups without a version start at zero", func(t *testing.T) {
+		c := &transactClient{}
+		s := NewMemberStore("members", c, logger).WithGroupsTable("groups")
+
+		version, _, err := s.UpdateMembers(ctx, "123456789012", "admins", 0, memberARNs(1), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if version != 1 {
+			t.Errorf("expected version 1, got %d", version)
+		}
+		if cond := aws.ToString(c.transactions[0][1].Update.ConditionExpression); cond != "attribute_exists(groupId) AND attribute_not_exists(membersVersion)" {
+			t.Errorf("unexpected condition %q", cond)
+		}
+	})
+
+	t.Run("a stale version conflicts without writing", func(t *testing.T) {
+		c := &transactClient{version: 5}
+		s := NewMemberStore("members", c, logger).WithGroupsTable("groups")
+
+		_, failed, err := s.UpdateMembers(ctx, "123456789012", "admins", 4, memberARNs(2), nil)
+		if !errors.Is(err, ErrMembersConflict) {
+			t.Fatalf("expected ErrMembersConflict, got %v", err)
+		}
+		if failed != nil || c.version != 5 {
+			t.Errorf("expected nothing to be written, got failed %v and version %d", failed, c.version)
+		}
+	})
+
+	t.Run("reports the members of a later transaction that conflicts", func(t *testing.T) {
+		c := &transactClient{concurrent: true}
+		s := NewMemberStore("members", c, logger).WithGroupsTable("groups")
+
+		version, failed, err := s.UpdateMembers(ctx, "123456789012", "admins", 0, memberARNs(100), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(failed) != 1 || !errors.Is(failed[memberARNs(100)[99]], ErrMembersConflict) {
+			t.Errorf("expected only the last member to fail, got %v", failed)
+		}
+		if version != 1 {
+			t.Errorf("expected the version after the first transaction, got %d", version)
+		}
+	})
+
+	t.Run("reports every member of a failed transaction", func(t *testing.T) {
+		c := &transactClient{err: errors.New("throttled")}
+		s := NewMemberStore("members", c, logger).WithGroupsTable("groups")
+
+		_, failed, err := s.UpdateMembers(ctx, "123456789012", "admins", 0, memberARNs(2), []string{"arn:aws:iam::123456789012:role/old"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(failed) != 3 || failed["arn:aws:iam::123456789012:role/old"] == nil {
+			t.Errorf("expected all 3 members to fail, got %v", failed)
+		}
+	})
+}
+
+// failingQueryClient fails every Query with err
+type failingQueryClient struct {
+	client.DynamoDBClient
+	err error
+}
+
+func (c *failingQueryClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return nil, c.err
+}
+
+func TestMemberStore_ListByMember(t *testing.T) {
+	ctx := context.Background()
+	arn := "arn:aws:iam::123456789012:user/alice"
+
+	t.Run("pages through the index", func(t *testing.T) {
+		c := &pagedQueryClient{pages: [][]any{
+			{&GroupMember{AccountID: "111111111111", GroupID: "readers", MemberARN: arn}},
+			{},
+			{&GroupMember{AccountID: "222222222222", GroupID: "operators", MemberARN: arn}},
+		}}
+		s := NewMemberStore("members", c, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		members, err := s.ListByMember(ctx, arn)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(members) != 2 || members[0].GroupID != "readers" || members[1].GroupID != "operators" {
+			t.Errorf("unexpected members %+v", members)
+		}
+		if len(c.inputs) != 3 {
+			t.Fatalf("expected an empty page not to end the listing, got %d queries", len(c.inputs))
+		}
+		if aws.ToString(c.inputs[0].IndexName) != "member-accounts-index" {
+			t.Errorf("unexpected index %s", aws.ToString(c.inputs[0].IndexName))
+		}
+	})
+
+	t.Run("no groups", func(t *testing.T) {
+		s := NewMemberStore("members", &pagedQueryClient{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		members, err := s.ListByMember(ctx, arn)
+		if err != nil || len(members) != 0 {
+			t.Errorf("expected no members, got %v, %v", members, err)
+		}
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		s := NewMemberStore("members", &failingQueryClient{err: errors.New("throttled")}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+		if _, err := s.ListByMember(ctx, arn); err == nil {
+			t.Error("expected the query error")
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Organization groups accounts whose requests are also evaluated against the
+// organization's policy store
+type Organization struct {
+	OrganizationID string `dynamodbav:"organizationId" json:"organizationId"`
+	Name           string `dynamodbav:"name" json:"name"`
+	PolicyStoreID  string `dynamodbav:"policyStoreId" json:"policyStoreId"`
+	CreatedAt      string `dynamodbav:"createdAt" json:"createdAt"`
+	CreatedBy      string `dynamodbav:"createdBy" json:"createdBy"`
+}
+
+// OrganizationPolicy is a static Cedar policy in an organization's policy
+// store, evaluated for every member account
+type OrganizationPolicy struct {
+	OrganizationID string `json:"organizationId"`
+	StaticPolicy
+}
+
+// OrganizationStore provides CRUD operations for organizations
+type OrganizationStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewOrganizationStore creates a new organization store
+func NewOrganizationStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *OrganizationStore {
+	return &OrganizationStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *OrganizationStore) WithClock(now clock.Clock) *OrganizationStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Create stores a new organization, assigning its ID
+func (s *OrganizationStore) Create(ctx context.Context, org *Organization) error {
+	org.OrganizationID = uuid.New().String()
+	if org.CreatedAt == "" {
+		org.CreatedAt = clock.Format(s.now())
+	}
+
+	item, err := attributevalue.MarshalMap(org)
+	if err != nil {
+		return fmt.Errorf("failed to marshal organization: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(organizationId)"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	s.logger.Info("organization created", "organization_id", org.OrganizationID, "name", org.Name)
+	return nil
+}
+
+// Get retrieves an organization by ID, or nil if it does not exist
+func (s *OrganizationStore) Get(ctx context.Context, orgID string) (*Organization, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"organizationId": &types.AttributeValueMemberS{Value: orgID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var org Organization
+	if err := attributevalue.UnmarshalMap(result.Item, &org); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal organization: %w", err)
+	}
+
+	return &org, nil
+}
+
+// Delete removes an organization
+func (s *OrganizationStore) Delete(ctx context.Context, orgID string) error {
+	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"organizationId": &types.AttributeValueMemberS{Value: orgID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	s.logger.Info("organization deleted", "organization_id", orgID)
+	return nil
+}
+
+// List returns all organizations
+func (s *OrganizationStore) List(ctx context.Context) ([]*Organization, error) {
+	result, err := s.dynamoClient.Scan(ctx, &dynamodb.ScanInput{
+		TableName: aws.String(s.tableName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	orgs := make([]*Organization, 0, len(result.Items))
+	for _, item := range result.Items {
+		var org Organization
+		if err := attributevalue.UnmarshalMap(item, &org); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal organization: %w", err)
+		}
+		orgs = append(orgs, &org)
+	}
+
+	return orgs, nil
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// ErrPendingChangeNotFound is returned for a change that does not exist, has
+// expired or has already been approved or rejected
+var ErrPendingChangeNotFound = errors.New("pending change not found")
+
+// PendingChange is a high-risk operation waiting for a second admin's
+// approval. The table expires changes through DynamoDB TTL on expiresAt.
+type PendingChange struct {
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	ChangeID  string `dynamodbav:"changeId" json:"changeId"`
+	// Operation is what approval performs, e.g. DeletePolicy
+	Operation string `dynamodbav:"operation" json:"operation"`
+	// TargetID is what the operation acts on: a policy ID, an admin ARN or
+	// the account ID
+	TargetID    string `dynamodbav:"targetId" json:"targetId"`
+	RequestedBy string `dynamodbav:"requestedBy" json:"requestedBy"`
+	RequestedAt string `dynamodbav:"requestedAt" json:"requestedAt"`
+	// ExpiresAt is the Unix time after which the change can no longer be
+	// approved
+	ExpiresAt int64 `dynamodbav:"expiresAt" json:"expiresAt"`
+}
+
+// Expired reports whether the change can no longer be approved at now
+func (c *PendingChange) Expired(now time.Time) bool {
+	return now.Unix() >= c.ExpiresAt
+}
+
+// PendingChangeStore queues pending changes
+type PendingChangeStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewPendingChangeStore creates a new pending change store
+func NewPendingChangeStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *PendingChangeStore {
+	return &PendingChangeStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *PendingChangeStore) WithClock(now clock.Clock) *PendingChangeStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Create queues a change that expires after expiry
+func (s *PendingChangeStore) Create(ctx context.Context, change *PendingChange, expiry time.Duration) error {
+	now := s.now().UTC()
+	change.ChangeID = uuid.New().String()
+	change.RequestedAt = clock.Format(now)
+	change.ExpiresAt = now.Add(expiry).Unix()
+
+	item, err := attributevalue.MarshalMap(change)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending change: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create pending change: %w", err)
+	}
+
+	s.logger.Info("pending change created",
+		"account_id", change.AccountID,
+		"change_id", change.ChangeID,
+		"operation", change.Operation,
+		"target_id", change.TargetID,
+		"requested_by", change.RequestedBy,
+	)
+	return nil
+}
+
+// Get returns an unexpired pending change, or ErrPendingChangeNotFound
+func (s *PendingChangeStore) Get(ctx context.Context, accountID, changeID string) (*PendingChange, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"changeId":  &types.AttributeValueMemberS{Value: changeID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending change: %w", err)
+	}
+	if result.Item == nil {
+		return nil, fmt.Errorf("%w: %s", ErrPendingChangeNotFound, changeID)
+	}
+
+	var change PendingChange
+	if err := attributevalue.UnmarshalMap(result.Item, &change); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending change: %w", err)
+	}
+	// TTL removal lags expiry
+	if change.Expired(s.now()) {
+		return nil, fmt.Errorf("%w: %s", ErrPendingChangeNotFound, changeID)
+	}
+	return &change, nil
+}
+
+// List returns an account's unexpired pending changes
+func (s *PendingChangeStore) List(ctx context.Context, accountID string) ([]*PendingChange, error) {
+	changes := []*PendingChange{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("accountId = :aid"),
+			FilterExpression:       aws.String("expiresAt > :now"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid": &types.AttributeValueMemberS{Value: accountID},
+				":now": &types.AttributeValueMemberN{Value: strconv.FormatInt(s.now().Unix(), 10)},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pending changes: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var change PendingChange
+			if err := attributevalue.UnmarshalMap(item, &change); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal pending change: %w", err)
+			}
+			changes = append(changes, &change)
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return changes, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
+
+// Delete removes a pending change once it is approved or rejected. The
+// condition makes sure only one of concurrent decisions wins.
+func (s *PendingChangeStore) Delete(ctx context.Context, accountID, changeID string) error {
+	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"changeId":  &types.AttributeValueMemberS{Value: changeID},
+		},
+		ConditionExpression: aws.String("attribute_exists(changeId)"),
+	})
+	if err != nil {
+		var condErr *types.ConditionalCheckFailedException
+		if isConditionalCheckFailed(err, &condErr) {
+			return fmt.Errorf("%w: %s", ErrPendingChangeNotFound, changeID)
+		}
+		return fmt.Errorf("failed to delete pending change: %w", err)
+	}
+	return nil
+}
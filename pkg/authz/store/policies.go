@@ -2,9 +2,19 @@ package store
 
 // Policy represents a policy template for API responses
 type Policy struct {
-	AccountID   string `json:"accountId"`
+	AccountID   string            `json:"accountId"`
+	PolicyID    string            `json:"policyId"`
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	CedarPolicy string            `json:"cedarPolicy"`
+	Tags        map[string]string `json:"tags,omitempty"`
+	CreatedAt   string            `json:"createdAt"`
+}
+
+// StaticPolicy is a static Cedar policy, applied as written rather than
+// linked to a principal like a Policy template
+type StaticPolicy struct {
 	PolicyID    string `json:"policyId"`
-	Name        string `json:"name"`
 	Description string `json:"description,omitempty"`
 	CedarPolicy string `json:"cedarPolicy"`
 	CreatedAt   string `json:"createdAt"`
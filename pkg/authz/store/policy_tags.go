@@ -0,0 +1,151 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// PolicyTags are the tags of a policy. AVP policy templates have room only
+// for the encoded name and description, so tags are kept here, keyed by the
+// template ID.
+type PolicyTags struct {
+	AccountID string            `dynamodbav:"accountId" json:"accountId"`
+	PolicyID  string            `dynamodbav:"policyId" json:"policyId"`
+	Tags      map[string]string `dynamodbav:"tags" json:"tags"`
+	UpdatedAt string            `dynamodbav:"updatedAt" json:"updatedAt"`
+}
+
+// PolicyTagStore provides CRUD operations for policy tags
+type PolicyTagStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewPolicyTagStore creates a new policy tag store
+func NewPolicyTagStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *PolicyTagStore {
+	return &PolicyTagStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *PolicyTagStore) WithClock(now clock.Clock) *PolicyTagStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Put replaces the tags of a policy, or removes them when tags is empty
+func (s *PolicyTagStore) Put(ctx context.Context, accountID, policyID string, tags map[string]string) error {
+	if len(tags) == 0 {
+		return s.Delete(ctx, accountID, policyID)
+	}
+
+	item, err := attributevalue.MarshalMap(&PolicyTags{
+		AccountID: accountID,
+		PolicyID:  policyID,
+		Tags:      tags,
+		UpdatedAt: clock.Format(s.now()),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal policy tags: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put policy tags: %w", err)
+	}
+
+	s.logger.Info("policy tags updated", "account_id", accountID, "policy_id", policyID)
+	return nil
+}
+
+// Get returns the tags of a policy, or nil if it has none
+func (s *PolicyTagStore) Get(ctx context.Context, accountID, policyID string) (map[string]string, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"policyId":  &types.AttributeValueMemberS{Value: policyID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get policy tags: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var tags PolicyTags
+	if err := attributevalue.UnmarshalMap(result.Item, &tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal policy tags: %w", err)
+	}
+
+	return tags.Tags, nil
+}
+
+// Delete removes the tags of a policy
+func (s *PolicyTagStore) Delete(ctx context.Context, accountID, policyID string) error {
+	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+			"policyId":  &types.AttributeValueMemberS{Value: policyID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete policy tags: %w", err)
+	}
+	return nil
+}
+
+// List returns the tags of every tagged policy in an account, keyed by
+// policy ID
+func (s *PolicyTagStore) List(ctx context.Context, accountID string) (map[string]map[string]string, error) {
+	tags := map[string]map[string]string{}
+	var startKey map[string]types.AttributeValue
+	for {
+		result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+			TableName:              aws.String(s.tableName),
+			KeyConditionExpression: aws.String("accountId = :aid"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":aid": &types.AttributeValueMemberS{Value: accountID},
+			},
+			ExclusiveStartKey: startKey,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy tags: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var policyTags PolicyTags
+			if err := attributevalue.UnmarshalMap(item, &policyTags); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal policy tags: %w", err)
+			}
+			tags[policyTags.PolicyID] = policyTags.Tags
+		}
+
+		if result.LastEvaluatedKey == nil {
+			return tags, nil
+		}
+		startKey = result.LastEvaluatedKey
+	}
+}
@@ -0,0 +1,331 @@
+// Package stream consumes the DynamoDB streams of the authz tables. Every
+// change to a table, whether made through the API or edited out of band, is
+// turned into a Change and passed to the configured handlers, which
+// invalidate caches, write the audit log and notify the account.
+//
+// The tables' streams must be enabled with the NEW_AND_OLD_IMAGES view type.
+// The consumer starts at the latest record of each open shard and keeps its
+// position in memory, so changes made while no replica is consuming are not
+// replayed.
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// discoverInterval is how often each stream is described to find new shards
+const discoverInterval = time.Minute
+
+var (
+	streamRecords = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_authz_stream_records_total",
+		Help: "Authz table changes read from DynamoDB streams, by table and event (INSERT, MODIFY, REMOVE).",
+	}, []string{"table", "event"})
+
+	streamErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_authz_stream_errors_total",
+		Help: "Failed DynamoDB Streams calls, by table.",
+	}, []string{"table"})
+
+	streamLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_authz_stream_lag_seconds",
+		Help: "Age of the last authz table change read from each table's stream.",
+	}, []string{"table"})
+)
+
+// Change is one change to an authz table item
+type Change struct {
+	Table string
+	// Event is INSERT, MODIFY or REMOVE
+	Event string
+	// AccountID is the item's accountId, empty for tables keyed otherwise
+	AccountID string
+	// Keys are the item's string key attributes
+	Keys map[string]string
+	// Changed names the attributes whose value differs between the old and
+	// new item, in order
+	Changed []string
+	// CreatedBy is the item's createdBy attribute, when it has one
+	CreatedBy string
+	Time      time.Time
+}
+
+// Handler receives the changes of every consumed table. Handlers run one
+// change at a time and log their own failures.
+type Handler func(ctx context.Context, change *Change)
+
+// Consumer reads the streams of a set of tables
+type Consumer struct {
+	streams      client.DynamoDBStreamsClient
+	tables       []string
+	handlers     []Handler
+	pollInterval time.Duration
+	logger       *slog.Logger
+}
+
+// NewConsumer creates a consumer polling each of tables' open shards every
+// pollInterval
+func NewConsumer(streams client.DynamoDBStreamsClient, tables []string, pollInterval time.Duration, logger *slog.Logger) *Consumer {
+	return &Consumer{
+		streams:      streams,
+		tables:       tables,
+		pollInterval: pollInterval,
+		logger:       logger,
+	}
+}
+
+// WithHandler adds a handler for the consumed changes
+func (c *Consumer) WithHandler(h Handler) *Consumer {
+	c.handlers = append(c.handlers, h)
+	return c
+}
+
+// Run consumes every table's stream until ctx is cancelled
+func (c *Consumer) Run(ctx context.Context) {
+	c.logger.Info("consuming authz table streams", "tables", c.tables, "poll_interval", c.pollInterval)
+
+	var wg sync.WaitGroup
+	for _, table := range c.tables {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.consume(ctx, table)
+		}()
+	}
+	wg.Wait()
+}
+
+// shard is the read position in one shard
+type shard struct {
+	id       string
+	parentID string
+	// iterator is empty until the shard is started
+	iterator string
+	// lastSeq is the last sequence number handled, to resume from when the
+	// iterator expires
+	lastSeq string
+	// fromStart reads the shard from its oldest record rather than its
+	// latest, for shards that opened after the consumer started
+	fromStart bool
+}
+
+// tableStream is the consumer's state for one table
+type tableStream struct {
+	table      string
+	arn        string
+	shards     map[string]*shard
+	done       map[string]bool
+	discovered time.Time
+	// started is false until the first discovery, whose shards are read
+	// from their latest record
+	started bool
+}
+
+// consume polls one table's stream, retrying failed calls every poll
+func (c *Consumer) consume(ctx context.Context, table string) {
+	ts := &tableStream{table: table, shards: make(map[string]*shard), done: make(map[string]bool)}
+	logger := c.logger.With("table", table)
+
+	ticker := time.NewTicker(c.pollInterval)
+	defer ticker.Stop()
+	for {
+		if err := c.poll(ctx, ts); err != nil && ctx.Err() == nil {
+			streamErrors.WithLabelValues(table).Inc()
+			logger.Warn("failed to read authz table stream", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// poll discovers shards when due and reads every started shard once
+func (c *Consumer) poll(ctx context.Context, ts *tableStream) error {
+	if ts.arn == "" {
+		arn, err := c.streamARN(ctx, ts.table)
+		if err != nil {
+			return err
+		}
+		ts.arn = arn
+	}
+	if time.Since(ts.discovered) >= discoverInterval {
+		if err := c.discover(ctx, ts); err != nil {
+			return err
+		}
+	}
+
+	ids := make([]string, 0, len(ts.shards))
+	for id := range ts.shards {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		s := ts.shards[id]
+		// Children are read after their parent so changes stay in order
+		if _, parentOpen := ts.shards[s.parentID]; parentOpen {
+			continue
+		}
+		closed, err := c.read(ctx, ts, s)
+		if err != nil {
+			return err
+		}
+		if closed {
+			delete(ts.shards, id)
+			ts.done[id] = true
+			// Pick up the children soon rather than at the next discovery
+			ts.discovered = time.Time{}
+		}
+	}
+	return nil
+}
+
+// streamARN returns the latest stream of table
+func (c *Consumer) streamARN(ctx context.Context, table string) (string, error) {
+	out, err := c.streams.ListStreams(ctx, &dynamodbstreams.ListStreamsInput{TableName: aws.String(table)})
+	if err != nil {
+		return "", fmt.Errorf("failed to list streams: %w", err)
+	}
+	if len(out.Streams) == 0 {
+		return "", fmt.Errorf("table %s has no stream enabled", table)
+	}
+	// Streams are listed oldest first
+	return aws.ToString(out.Streams[len(out.Streams)-1].StreamArn), nil
+}
+
+// discover adds the stream's shards that are not being read or done
+func (c *Consumer) discover(ctx context.Context, ts *tableStream) error {
+	listed := make(map[string]bool)
+	var start *string
+	for {
+		out, err := c.streams.DescribeStream(ctx, &dynamodbstreams.DescribeStreamInput{
+			StreamArn:             aws.String(ts.arn),
+			ExclusiveStartShardId: start,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to describe stream: %w", err)
+		}
+		for _, sh := range out.StreamDescription.Shards {
+			id := aws.ToString(sh.ShardId)
+			listed[id] = true
+			if ts.done[id] || ts.shards[id] != nil {
+				continue
+			}
+			closed := sh.SequenceNumberRange != nil && sh.SequenceNumberRange.EndingSequenceNumber != nil
+			if !ts.started && closed {
+				// Closed before the consumer started; nothing new to read
+				ts.done[id] = true
+				continue
+			}
+			ts.shards[id] = &shard{id: id, parentID: aws.ToString(sh.ParentShardId), fromStart: ts.started}
+		}
+		start = out.StreamDescription.LastEvaluatedShardId
+		if start == nil {
+			break
+		}
+	}
+
+	// Forget finished shards once the stream has trimmed them
+	for id := range ts.done {
+		if !listed[id] {
+			delete(ts.done, id)
+		}
+	}
+	ts.started = true
+	ts.discovered = time.Now()
+	return nil
+}
+
+// read handles one batch of records from a shard, reporting whether the
+// shard is closed and fully read
+func (c *Consumer) read(ctx context.Context, ts *tableStream, s *shard) (bool, error) {
+	if s.iterator == "" {
+		iterator, err := c.iterator(ctx, ts, s)
+		if err != nil {
+			return false, err
+		}
+		s.iterator = iterator
+	}
+
+	out, err := c.streams.GetRecords(ctx, &dynamodbstreams.GetRecordsInput{ShardIterator: aws.String(s.iterator)})
+	var expired *types.ExpiredIteratorException
+	if errors.As(err, &expired) {
+		// Resume from the last record on the next poll
+		s.iterator = ""
+		return false, nil
+	}
+	var trimmed *types.TrimmedDataAccessException
+	if errors.As(err, &trimmed) {
+		c.logger.Warn("authz table stream records were trimmed before they were read", "table", ts.table, "shard", s.id)
+		s.iterator, s.lastSeq, s.fromStart = "", "", true
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get records: %w", err)
+	}
+
+	for i := range out.Records {
+		record := &out.Records[i]
+		c.handle(ctx, ts.table, record)
+		if record.Dynamodb != nil && record.Dynamodb.SequenceNumber != nil {
+			s.lastSeq = *record.Dynamodb.SequenceNumber
+		}
+	}
+
+	if out.NextShardIterator == nil {
+		return true, nil
+	}
+	s.iterator = *out.NextShardIterator
+	return false, nil
+}
+
+// iterator starts reading a shard after its last handled record or, for a
+// new shard, from its oldest or latest record
+func (c *Consumer) iterator(ctx context.Context, ts *tableStream, s *shard) (string, error) {
+	in := &dynamodbstreams.GetShardIteratorInput{
+		StreamArn: aws.String(ts.arn),
+		ShardId:   aws.String(s.id),
+	}
+	switch {
+	case s.lastSeq != "":
+		in.ShardIteratorType = types.ShardIteratorTypeAfterSequenceNumber
+		in.SequenceNumber = aws.String(s.lastSeq)
+	case s.fromStart:
+		in.ShardIteratorType = types.ShardIteratorTypeTrimHorizon
+	default:
+		in.ShardIteratorType = types.ShardIteratorTypeLatest
+	}
+
+	out, err := c.streams.GetShardIterator(ctx, in)
+	if err != nil {
+		return "", fmt.Errorf("failed to get shard iterator: %w", err)
+	}
+	return aws.ToString(out.ShardIterator), nil
+}
+
+// handle passes one record to every handler
+func (c *Consumer) handle(ctx context.Context, table string, record *types.Record) {
+	change := newChange(table, record)
+	streamRecords.WithLabelValues(table, change.Event).Inc()
+	if !change.Time.IsZero() {
+		streamLag.WithLabelValues(table).Set(time.Since(change.Time).Seconds())
+	}
+	for _, h := range c.handlers {
+		h(ctx, change)
+	}
+}
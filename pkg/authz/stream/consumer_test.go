@@ -0,0 +1,179 @@
+package stream
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeStreams is one table's stream; each shard's iterator is its ID
+type fakeStreams struct {
+	client.DynamoDBStreamsClient
+
+	mu        sync.Mutex
+	shards    []types.Shard
+	records   map[string][]types.Record
+	iterators map[string]types.ShardIteratorType
+}
+
+func newFakeStreams(shards ...types.Shard) *fakeStreams {
+	return &fakeStreams{
+		shards:    shards,
+		records:   make(map[string][]types.Record),
+		iterators: make(map[string]types.ShardIteratorType),
+	}
+}
+
+func (f *fakeStreams) ListStreams(ctx context.Context, in *dynamodbstreams.ListStreamsInput, _ ...func(*dynamodbstreams.Options)) (*dynamodbstreams.ListStreamsOutput, error) {
+	return &dynamodbstreams.ListStreamsOutput{
+		Streams: []types.Stream{{StreamArn: aws.String("arn:" + aws.ToString(in.TableName))}},
+	}, nil
+}
+
+func (f *fakeStreams) DescribeStream(ctx context.Context, in *dynamodbstreams.DescribeStreamInput, _ ...func(*dynamodbstreams.Options)) (*dynamodbstreams.DescribeStreamOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodbstreams.DescribeStreamOutput{
+		StreamDescription: &types.StreamDescription{Shards: append([]types.Shard(nil), f.shards...)},
+	}, nil
+}
+
+func (f *fakeStreams) GetShardIterator(ctx context.Context, in *dynamodbstreams.GetShardIteratorInput, _ ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetShardIteratorOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.iterators[aws.ToString(in.ShardId)] = in.ShardIteratorType
+	return &dynamodbstreams.GetShardIteratorOutput{ShardIterator: in.ShardId}, nil
+}
+
+func (f *fakeStreams) GetRecords(ctx context.Context, in *dynamodbstreams.GetRecordsInput, _ ...func(*dynamodbstreams.Options)) (*dynamodbstreams.GetRecordsOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	id := aws.ToString(in.ShardIterator)
+	out := &dynamodbstreams.GetRecordsOutput{Records: f.records[id], NextShardIterator: in.ShardIterator}
+	delete(f.records, id)
+	for _, sh := range f.shards {
+		if aws.ToString(sh.ShardId) == id && sh.SequenceNumberRange.EndingSequenceNumber != nil {
+			out.NextShardIterator = nil
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeStreams) iteratorType(shardID string) (types.ShardIteratorType, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	t, ok := f.iterators[shardID]
+	return t, ok
+}
+
+func openShard(id, parent string) types.Shard {
+	sh := types.Shard{ShardId: aws.String(id), SequenceNumberRange: &types.SequenceNumberRange{StartingSequenceNumber: aws.String("1")}}
+	if parent != "" {
+		sh.ParentShardId = aws.String(parent)
+	}
+	return sh
+}
+
+func closedShard(id, endingSeq string) types.Shard {
+	sh := openShard(id, "")
+	sh.SequenceNumberRange.EndingSequenceNumber = aws.String(endingSeq)
+	return sh
+}
+
+func insertRecord(seq, accountID string) types.Record {
+	return types.Record{
+		EventName: types.OperationTypeInsert,
+		Dynamodb: &types.StreamRecord{
+			SequenceNumber: aws.String(seq),
+			Keys:           map[string]types.AttributeValue{"accountId": &types.AttributeValueMemberS{Value: accountID}},
+			NewImage:       map[string]types.AttributeValue{"accountId": &types.AttributeValueMemberS{Value: accountID}},
+		},
+	}
+}
+
+func TestConsumer_ReadsShardsInOrder(t *testing.T) {
+	streams := newFakeStreams(openShard("shard-1", ""))
+	changes := make(chan *Change, 10)
+	consumer := NewConsumer(streams, []string{"rosa-accounts"}, time.Millisecond, testLogger()).
+		WithHandler(func(ctx context.Context, change *Change) { changes <- change })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		consumer.Run(ctx)
+		close(done)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok := streams.iteratorType("shard-1"); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the open shard to be started")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if typ, _ := streams.iteratorType("shard-1"); typ != types.ShardIteratorTypeLatest {
+		t.Errorf("expected shards open at start to be read from the latest record, got %s", typ)
+	}
+
+	// The shard closes with one last record and a child shard takes over
+	streams.mu.Lock()
+	streams.records["shard-1"] = []types.Record{insertRecord("2", "111111111111")}
+	streams.shards[0] = closedShard("shard-1", "2")
+	streams.shards = append(streams.shards, openShard("shard-2", "shard-1"))
+	streams.records["shard-2"] = []types.Record{insertRecord("3", "222222222222")}
+	streams.mu.Unlock()
+
+	for _, want := range []string{"111111111111", "222222222222"} {
+		select {
+		case change := <-changes:
+			if change.AccountID != want || change.Table != "rosa-accounts" {
+				t.Errorf("expected a change to account %s in rosa-accounts, got %s in %s", want, change.AccountID, change.Table)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected a change to account %s", want)
+		}
+	}
+	if typ, _ := streams.iteratorType("shard-2"); typ != types.ShardIteratorTypeTrimHorizon {
+		t.Errorf("expected shards opened after start to be read from their oldest record, got %s", typ)
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected Run to return once cancelled")
+	}
+}
+
+func TestConsumer_SkipsShardsClosedBeforeStart(t *testing.T) {
+	streams := newFakeStreams(closedShard("shard-0", "1"), openShard("shard-1", "shard-0"))
+	consumer := NewConsumer(streams, []string{"rosa-admins"}, time.Hour, testLogger())
+
+	ts := &tableStream{table: "rosa-admins", shards: make(map[string]*shard), done: make(map[string]bool)}
+	if err := consumer.poll(context.Background(), ts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := streams.iteratorType("shard-0"); ok {
+		t.Error("expected the closed shard not to be read")
+	}
+	if typ, _ := streams.iteratorType("shard-1"); typ != types.ShardIteratorTypeLatest {
+		t.Errorf("expected the open child to be read from its latest record, got %q", typ)
+	}
+}
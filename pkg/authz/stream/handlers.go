@@ -0,0 +1,185 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
+	"github.com/openshift/rosa-regional-platform-api/pkg/notify"
+)
+
+// CacheNamespace is the shared cache namespace holding an account's cached
+// authz data; every change to the account's items invalidates it
+func CacheNamespace(accountID string) string {
+	return "authz:" + accountID
+}
+
+// newChange converts a stream record
+func newChange(table string, record *types.Record) *Change {
+	change := &Change{
+		Table: table,
+		Event: string(record.EventName),
+		Keys:  make(map[string]string),
+	}
+	sr := record.Dynamodb
+	if sr == nil {
+		return change
+	}
+	if sr.ApproximateCreationDateTime != nil {
+		change.Time = *sr.ApproximateCreationDateTime
+	}
+	for name, value := range sr.Keys {
+		if s, ok := value.(*types.AttributeValueMemberS); ok {
+			change.Keys[name] = s.Value
+		}
+	}
+	change.AccountID = change.Keys["accountId"]
+
+	image := sr.NewImage
+	if image == nil {
+		image = sr.OldImage
+	}
+	if createdBy, ok := image["createdBy"].(*types.AttributeValueMemberS); ok {
+		change.CreatedBy = createdBy.Value
+	}
+
+	for name := range union(sr.OldImage, sr.NewImage) {
+		if !reflect.DeepEqual(sr.OldImage[name], sr.NewImage[name]) {
+			change.Changed = append(change.Changed, name)
+		}
+	}
+	sort.Strings(change.Changed)
+	return change
+}
+
+func union(a, b map[string]types.AttributeValue) map[string]bool {
+	names := make(map[string]bool, len(a)+len(b))
+	for name := range a {
+		names[name] = true
+	}
+	for name := range b {
+		names[name] = true
+	}
+	return names
+}
+
+// InvalidateCache invalidates the changed account's namespace in the shared
+// cache, on every replica
+func InvalidateCache(c cache.Cache, logger *slog.Logger) Handler {
+	return func(ctx context.Context, change *Change) {
+		if change.AccountID == "" {
+			return
+		}
+		if err := c.Invalidate(ctx, CacheNamespace(change.AccountID)); err != nil {
+			logger.Warn("failed to invalidate authz cache", "error", err, "account_id", change.AccountID, "table", change.Table)
+		}
+	}
+}
+
+// AuditLog logs every change with the attributes it changed, so edits made
+// directly to the tables are recorded alongside those made through the API
+func AuditLog(logger *slog.Logger) Handler {
+	return func(ctx context.Context, change *Change) {
+		logger.Info("authz table changed",
+			"table", change.Table,
+			"event", change.Event,
+			"account_id", change.AccountID,
+			"keys", change.Keys,
+			"changed", change.Changed,
+			"created_by", change.CreatedBy,
+			"changed_at", change.Time,
+		)
+	}
+}
+
+// Notifier sends events to an account's notification channels
+type Notifier interface {
+	Notify(ctx context.Context, event *notify.Event) ([]notify.Delivery, error)
+}
+
+// Notify sends an authz-changed event to the changed account
+func Notify(n Notifier, logger *slog.Logger) Handler {
+	return func(ctx context.Context, change *Change) {
+		if change.AccountID == "" {
+			return
+		}
+		_, err := n.Notify(ctx, &notify.Event{
+			Type:      notify.EventAuthzChanged,
+			AccountID: change.AccountID,
+			Subject:   "ROSA authorization data changed",
+			Message:   fmt.Sprintf("An item in %s was %s for account %s.", change.Table, eventVerb(change.Event), change.AccountID),
+			Details:   changeDetails(change),
+			Time:      change.Time,
+		})
+		if err != nil {
+			logger.Warn("failed to notify authz change", "error", err, "account_id", change.AccountID, "table", change.Table)
+		}
+	}
+}
+
+// Publish publishes every change as a platform event: changes to
+// accountsTable as account.enabled, account.updated or account.disabled,
+// and changes to the other tables as authz.changed
+func Publish(p events.Publisher, accountsTable string) Handler {
+	return func(ctx context.Context, change *Change) {
+		if change.AccountID == "" {
+			return
+		}
+		eventType := events.TypeAuthzChanged
+		if change.Table == accountsTable {
+			switch change.Event {
+			case string(types.OperationTypeInsert):
+				eventType = events.TypeAccountEnabled
+			case string(types.OperationTypeRemove):
+				eventType = events.TypeAccountDisabled
+			default:
+				eventType = events.TypeAccountUpdated
+			}
+		}
+
+		event := events.New(eventType, change.AccountID, change.Table, changeDetails(change))
+		if !change.Time.IsZero() {
+			event.Time = change.Time
+		}
+		if change.CreatedBy != "" {
+			event.Data["createdBy"] = change.CreatedBy
+		}
+		p.Publish(ctx, event)
+	}
+}
+
+// changeDetails describes a change as string attributes: its table and
+// event, the item's keys other than accountId, and the changed attributes
+func changeDetails(change *Change) map[string]string {
+	details := map[string]string{
+		"table": change.Table,
+		"event": change.Event,
+	}
+	for name, value := range change.Keys {
+		if name != "accountId" {
+			details[name] = value
+		}
+	}
+	if len(change.Changed) > 0 {
+		details["changed"] = strings.Join(change.Changed, ",")
+	}
+	return details
+}
+
+func eventVerb(event string) string {
+	switch event {
+	case string(types.OperationTypeInsert):
+		return "created"
+	case string(types.OperationTypeRemove):
+		return "deleted"
+	default:
+		return "modified"
+	}
+}
@@ -0,0 +1,151 @@
+package stream
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodbstreams/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
+	"github.com/openshift/rosa-regional-platform-api/pkg/notify"
+)
+
+func TestNewChange(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	record := &types.Record{
+		EventName: types.OperationTypeModify,
+		Dynamodb: &types.StreamRecord{
+			ApproximateCreationDateTime: aws.Time(at),
+			Keys: map[string]types.AttributeValue{
+				"accountId": &types.AttributeValueMemberS{Value: "123456789012"},
+				"groupId":   &types.AttributeValueMemberS{Value: "admins"},
+			},
+			OldImage: map[string]types.AttributeValue{
+				"accountId":   &types.AttributeValueMemberS{Value: "123456789012"},
+				"groupId":     &types.AttributeValueMemberS{Value: "admins"},
+				"description": &types.AttributeValueMemberS{Value: "old"},
+				"retired":     &types.AttributeValueMemberBOOL{Value: true},
+				"createdBy":   &types.AttributeValueMemberS{Value: "arn:aws:iam::123456789012:user/alice"},
+			},
+			NewImage: map[string]types.AttributeValue{
+				"accountId":   &types.AttributeValueMemberS{Value: "123456789012"},
+				"groupId":     &types.AttributeValueMemberS{Value: "admins"},
+				"description": &types.AttributeValueMemberS{Value: "new"},
+				"name":        &types.AttributeValueMemberS{Value: "Admins"},
+				"createdBy":   &types.AttributeValueMemberS{Value: "arn:aws:iam::123456789012:user/alice"},
+			},
+		},
+	}
+
+	change := newChange("rosa-groups", record)
+	if change.Table != "rosa-groups" || change.Event != "MODIFY" || !change.Time.Equal(at) {
+		t.Errorf("unexpected change %+v", change)
+	}
+	if change.AccountID != "123456789012" || change.Keys["groupId"] != "admins" {
+		t.Errorf("expected the item's keys, got account %q and keys %v", change.AccountID, change.Keys)
+	}
+	if change.CreatedBy != "arn:aws:iam::123456789012:user/alice" {
+		t.Errorf("expected createdBy from the item, got %q", change.CreatedBy)
+	}
+	if want := []string{"description", "name", "retired"}; !slices.Equal(change.Changed, want) {
+		t.Errorf("expected changed attributes %v, got %v", want, change.Changed)
+	}
+}
+
+func TestInvalidateCache(t *testing.T) {
+	c := cache.NewMemory()
+	var invalidated []string
+	c.OnInvalidate(CacheNamespace("123456789012"), func() { invalidated = append(invalidated, "123456789012") })
+
+	h := InvalidateCache(c, testLogger())
+	h(context.Background(), &Change{Table: "rosa-admins"})
+	h(context.Background(), &Change{Table: "rosa-admins", AccountID: "123456789012"})
+	if len(invalidated) != 1 {
+		t.Errorf("expected only the changed account's namespace to be invalidated once, got %v", invalidated)
+	}
+}
+
+type fakeNotifier struct {
+	events []*notify.Event
+}
+
+func (f *fakeNotifier) Notify(ctx context.Context, event *notify.Event) ([]notify.Delivery, error) {
+	f.events = append(f.events, event)
+	return nil, nil
+}
+
+func TestNotify(t *testing.T) {
+	n := &fakeNotifier{}
+	h := Notify(n, testLogger())
+
+	h(context.Background(), &Change{Table: "rosa-delegations", Event: "INSERT"})
+	if len(n.events) != 0 {
+		t.Fatal("expected changes without an account not to be notified")
+	}
+
+	h(context.Background(), &Change{
+		Table:     "rosa-members",
+		Event:     "REMOVE",
+		AccountID: "123456789012",
+		Keys:      map[string]string{"accountId": "123456789012", "groupId": "admins"},
+		Changed:   []string{"groupId", "memberArn"},
+	})
+	if len(n.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(n.events))
+	}
+	event := n.events[0]
+	if event.Type != notify.EventAuthzChanged || event.AccountID != "123456789012" {
+		t.Errorf("unexpected event %+v", event)
+	}
+	if event.Details["table"] != "rosa-members" || event.Details["groupId"] != "admins" || event.Details["changed"] != "groupId,memberArn" {
+		t.Errorf("unexpected details %v", event.Details)
+	}
+	if _, ok := event.Details["accountId"]; ok {
+		t.Error("expected the account key not to be repeated in the details")
+	}
+	if event.Message != "An item in rosa-members was deleted for account 123456789012." {
+		t.Errorf("unexpected message %q", event.Message)
+	}
+}
+
+type fakePublisher struct {
+	events []events.Event
+}
+
+func (f *fakePublisher) Publish(ctx context.Context, event events.Event) {
+	f.events = append(f.events, event)
+}
+
+func TestPublish(t *testing.T) {
+	p := &fakePublisher{}
+	h := Publish(p, "rosa-accounts")
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	h(context.Background(), &Change{Table: "rosa-accounts", Event: "INSERT", AccountID: "123456789012", CreatedBy: "arn:aws:iam::123456789012:user/alice", Time: at})
+	h(context.Background(), &Change{Table: "rosa-accounts", Event: "REMOVE", AccountID: "123456789012"})
+	h(context.Background(), &Change{
+		Table:     "rosa-members",
+		Event:     "MODIFY",
+		AccountID: "123456789012",
+		Keys:      map[string]string{"accountId": "123456789012", "groupId": "admins"},
+	})
+	h(context.Background(), &Change{Table: "rosa-delegations", Event: "INSERT"})
+
+	var got []string
+	for _, event := range p.events {
+		got = append(got, event.Type)
+	}
+	if want := []string{events.TypeAccountEnabled, events.TypeAccountDisabled, events.TypeAuthzChanged}; !slices.Equal(got, want) {
+		t.Fatalf("expected events %v, got %v", want, got)
+	}
+	if first := p.events[0]; !first.Time.Equal(at) || first.Data["createdBy"] != "arn:aws:iam::123456789012:user/alice" || first.Subject != "rosa-accounts" {
+		t.Errorf("unexpected event %+v", first)
+	}
+	if p.events[2].Data["groupId"] != "admins" || p.events[2].ID == "" {
+		t.Errorf("unexpected event %+v", p.events[2])
+	}
+}
@@ -0,0 +1,140 @@
+package authz
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// Tag limits of policies and groups
+const (
+	MaxTags        = 50
+	MaxTagKeyLen   = 128
+	MaxTagValueLen = 256
+)
+
+// ErrInvalidTags is returned for tags over the limits or with malformed keys
+var ErrInvalidTags = errors.New("invalid tags")
+
+// tagKeyPattern matches tag keys. "=" is left out so filters and the
+// groupTags context entries can be written as key=value.
+var tagKeyPattern = regexp.MustCompile(`^[\p{L}\p{N} _.:/+\-@]+$`)
+
+// ValidateTags checks tags against the tag limits
+func ValidateTags(tags map[string]string) error {
+	if len(tags) > MaxTags {
+		return fmt.Errorf("%w: %d tags, at most %d are allowed", ErrInvalidTags, len(tags), MaxTags)
+	}
+	for key, value := range tags {
+		if len(key) > MaxTagKeyLen || !tagKeyPattern.MatchString(key) {
+			return fmt.Errorf("%w: key %q must be 1-%d letters, digits, spaces or _.:/+-@", ErrInvalidTags, key, MaxTagKeyLen)
+		}
+		if len(value) > MaxTagValueLen {
+			return fmt.Errorf("%w: value of %q is longer than %d characters", ErrInvalidTags, key, MaxTagValueLen)
+		}
+	}
+	return nil
+}
+
+// TagFilter selects tagged policies or groups: those with the tag Key and,
+// unless AnyValue, the value Value
+type TagFilter struct {
+	Key      string
+	Value    string
+	AnyValue bool
+}
+
+// ParseTagFilters parses tag filters written as key=value, or as key to
+// match any value
+func ParseTagFilters(filters []string) ([]TagFilter, error) {
+	parsed := make([]TagFilter, 0, len(filters))
+	for _, f := range filters {
+		key, value, hasValue := strings.Cut(f, "=")
+		if key == "" {
+			return nil, fmt.Errorf("%w: filter %q must be key or key=value", ErrInvalidTags, f)
+		}
+		parsed = append(parsed, TagFilter{Key: key, Value: value, AnyValue: !hasValue})
+	}
+	return parsed, nil
+}
+
+// MatchTags reports whether tags match every filter
+func MatchTags(tags map[string]string, filters []TagFilter) bool {
+	for _, f := range filters {
+		value, ok := tags[f.Key]
+		if !ok || (!f.AnyValue && value != f.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetPolicyTags replaces a policy's tags, or removes them when tags is empty
+func (a *authorizerImpl) SetPolicyTags(ctx context.Context, accountID, policyID string, tags map[string]string) (*store.Policy, error) {
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
+	}
+	policy, err := a.GetPolicy(ctx, accountID, policyID)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+	if err := a.policyTagStore.Put(ctx, accountID, policyID, tags); err != nil {
+		return nil, err
+	}
+	policy.Tags = tags
+	if len(tags) == 0 {
+		policy.Tags = nil
+	}
+	return policy, nil
+}
+
+// SetGroupTags replaces a group's tags, or removes them when tags is empty
+func (a *authorizerImpl) SetGroupTags(ctx context.Context, accountID, groupID string, tags map[string]string) (*store.Group, error) {
+	if err := ValidateTags(tags); err != nil {
+		return nil, err
+	}
+	group, err := a.groupStore.SetTags(ctx, accountID, groupID, tags)
+	if err != nil {
+		return nil, err
+	}
+	if group == nil {
+		return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, groupID)
+	}
+	return group, nil
+}
+
+// groupTagsContext returns the tags of groups as a Cedar set of "key=value"
+// strings
+func (a *authorizerImpl) groupTagsContext(ctx context.Context, accountID string, groups []string) (avptypes.AttributeValue, error) {
+	seen := make(map[string]bool)
+	for _, groupID := range groups {
+		group, err := a.groupStore.Get(ctx, accountID, groupID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get group tags: %w", err)
+		}
+		if group == nil {
+			continue
+		}
+		for key, value := range group.Tags {
+			seen[key+"="+value] = true
+		}
+	}
+
+	entries := make([]string, 0, len(seen))
+	for entry := range seen {
+		entries = append(entries, entry)
+	}
+	sort.Strings(entries)
+	set := make([]avptypes.AttributeValue, len(entries))
+	for i, entry := range entries {
+		set[i] = &avptypes.AttributeValueMemberString{Value: entry}
+	}
+	return &avptypes.AttributeValueMemberSet{Value: set}, nil
+}
@@ -0,0 +1,71 @@
+package authz
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateTags(t *testing.T) {
+	tooMany := make(map[string]string)
+	for i := 0; i <= MaxTags; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+
+	tests := []struct {
+		name    string
+		tags    map[string]string
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "valid", tags: map[string]string{"team": "payments", "cost-center:id": "", "owner@example.com": "a"}},
+		{name: "empty key", tags: map[string]string{"": "v"}, wantErr: true},
+		{name: "equals in key", tags: map[string]string{"team=a": "v"}, wantErr: true},
+		{name: "key too long", tags: map[string]string{strings.Repeat("k", MaxTagKeyLen+1): "v"}, wantErr: true},
+		{name: "value too long", tags: map[string]string{"team": strings.Repeat("v", MaxTagValueLen+1)}, wantErr: true},
+		{name: "too many", tags: tooMany, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTags(tt.tags)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTags) {
+				t.Errorf("expected ErrInvalidTags, got %v", err)
+			}
+		})
+	}
+}
+
+func TestMatchTags(t *testing.T) {
+	tags := map[string]string{"team": "payments", "env": "prod"}
+
+	tests := []struct {
+		filters []string
+		want    bool
+	}{
+		{filters: nil, want: true},
+		{filters: []string{"team=payments"}, want: true},
+		{filters: []string{"team"}, want: true},
+		{filters: []string{"team=payments", "env=prod"}, want: true},
+		{filters: []string{"team=payments", "env=dev"}, want: false},
+		{filters: []string{"owner"}, want: false},
+		{filters: []string{"env="}, want: false},
+	}
+
+	for _, tt := range tests {
+		filters, err := ParseTagFilters(tt.filters)
+		if err != nil {
+			t.Fatalf("unexpected error for %v: %v", tt.filters, err)
+		}
+		if got := MatchTags(tags, filters); got != tt.want {
+			t.Errorf("filters %v: expected %v, got %v", tt.filters, tt.want, got)
+		}
+	}
+
+	if _, err := ParseTagFilters([]string{"=payments"}); err == nil {
+		t.Error("expected an error for a filter without a key")
+	}
+}
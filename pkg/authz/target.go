@@ -0,0 +1,69 @@
+package authz
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// TargetType represents the type of attachment target
+type TargetType string
+
+const (
+	// TargetTypeUser is a principal, by IAM user or assumed-role session ARN
+	TargetTypeUser TargetType = "user"
+	// TargetTypeGroup is a group, by group ID
+	TargetTypeGroup TargetType = "group"
+	// TargetTypeRole is an IAM role, by role ARN. Roles are linked to the same
+	// Cedar principal type as users, so policies see no difference; they are
+	// reported as roles so role grants can be told apart from user grants.
+	TargetTypeRole TargetType = "role"
+)
+
+// ErrInvalidTargetType is returned for a target type that is not one of
+// TargetTypes
+var ErrInvalidTargetType = errors.New("invalid target type")
+
+// TargetTypes lists the attachment target types
+var TargetTypes = []TargetType{TargetTypeUser, TargetTypeGroup, TargetTypeRole}
+
+// ParseTargetType parses an attachment target type
+func ParseTargetType(s string) (TargetType, error) {
+	for _, t := range TargetTypes {
+		if s == string(t) {
+			return t, nil
+		}
+	}
+	return "", fmt.Errorf("%w %q: must be one of user, group or role", ErrInvalidTargetType, s)
+}
+
+// ValidateTarget checks that targetType is known and targetID names a target
+// of that type. Role targets must be IAM role ARNs; users and groups are
+// checked where they are resolved.
+func ValidateTarget(targetType TargetType, targetID string) error {
+	if _, err := ParseTargetType(string(targetType)); err != nil {
+		return err
+	}
+	if targetType == TargetTypeRole && !isRoleARN(targetID) {
+		return fmt.Errorf("role target %s is not an IAM role ARN", targetID)
+	}
+	return nil
+}
+
+// NormalizeTarget returns the type a target is attached and reported as: a
+// user target naming an IAM role ARN is a role
+func NormalizeTarget(targetType TargetType, targetID string) TargetType {
+	if targetType == TargetTypeUser && isRoleARN(targetID) {
+		return TargetTypeRole
+	}
+	return targetType
+}
+
+// isRoleARN reports whether arn names an IAM role
+func isRoleARN(arn string) bool {
+	if validatePrincipalARN(arn) != nil {
+		return false
+	}
+	parts := strings.SplitN(arn, ":", 6)
+	return parts[2] == "iam" && strings.HasPrefix(parts[5], "role/")
+}
@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+)
+
+func TestParseTargetType(t *testing.T) {
+	for _, s := range []string{"user", "group", "role"} {
+		if got, err := ParseTargetType(s); err != nil || string(got) != s {
+			t.Errorf("ParseTargetType(%q) = %q, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "User", "users", "policy"} {
+		if _, err := ParseTargetType(s); !errors.Is(err, ErrInvalidTargetType) {
+			t.Errorf("ParseTargetType(%q) error = %v, want ErrInvalidTargetType", s, err)
+		}
+	}
+}
+
+func TestValidateTarget(t *testing.T) {
+	tests := []struct {
+		targetType TargetType
+		targetID   string
+		wantErr    bool
+	}{
+		{targetType: TargetTypeUser, targetID: "arn:aws:iam::123456789012:user/alice"},
+		{targetType: TargetTypeGroup, targetID: "admins"},
+		{targetType: TargetTypeRole, targetID: "arn:aws:iam::123456789012:role/dev"},
+		{targetType: TargetTypeRole, targetID: "arn:aws:iam::123456789012:role/dev-*"},
+		{targetType: TargetTypeRole, targetID: "arn:aws:iam::123456789012:user/alice", wantErr: true},
+		{targetType: TargetTypeRole, targetID: "arn:aws:sts::123456789012:assumed-role/dev/session", wantErr: true},
+		{targetType: TargetTypeRole, targetID: "dev", wantErr: true},
+		{targetType: "policy", targetID: "admins", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.targetType)+" "+tt.targetID, func(t *testing.T) {
+			err := ValidateTarget(tt.targetType, tt.targetID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTarget(%q, %q) error = %v, wantErr %v", tt.targetType, tt.targetID, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	tests := []struct {
+		targetType TargetType
+		targetID   string
+		want       TargetType
+	}{
+		{targetType: TargetTypeUser, targetID: "arn:aws:iam::123456789012:role/dev", want: TargetTypeRole},
+		{targetType: TargetTypeUser, targetID: "arn:aws:iam::123456789012:user/alice", want: TargetTypeUser},
+		{targetType: TargetTypeUser, targetID: "arn:aws:sts::123456789012:assumed-role/dev/session", want: TargetTypeUser},
+		{targetType: TargetTypeGroup, targetID: "arn:aws:iam::123456789012:role/dev", want: TargetTypeGroup},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeTarget(tt.targetType, tt.targetID); got != tt.want {
+			t.Errorf("NormalizeTarget(%q, %q) = %q, want %q", tt.targetType, tt.targetID, got, tt.want)
+		}
+	}
+}
+
+func TestAttachmentTarget(t *testing.T) {
+	tests := []struct {
+		entityType string
+		entityID   string
+		want       TargetType
+	}{
+		{entityType: "ROSA::Group", entityID: "admins", want: TargetTypeGroup},
+		{entityType: "ROSA::Principal", entityID: "arn:aws:iam::123456789012:user/alice", want: TargetTypeUser},
+		{entityType: "ROSA::Principal", entityID: "arn:aws:iam::123456789012:role/dev", want: TargetTypeRole},
+	}
+
+	for _, tt := range tests {
+		got, id := attachmentTarget(&avptypes.EntityIdentifier{EntityType: aws.String(tt.entityType), EntityId: aws.String(tt.entityID)})
+		if got != tt.want || id != tt.entityID {
+			t.Errorf("attachmentTarget(%s::%q) = %q, %q, want %q", tt.entityType, tt.entityID, got, id, tt.want)
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Package bootstrap applies a manifest of initial authz state at startup:
+// accounts, their admins and the policies managed in their policy stores.
+// Applying a manifest only adds what is missing and updates managed policies
+// that differ, so every replica can apply it on every start.
+package bootstrap
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// CreatedBy is recorded as the creator of the accounts and admins a manifest
+// adds
+const CreatedBy = "bootstrap"
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// Manifest is the declared initial state
+type Manifest struct {
+	Accounts []Account `yaml:"accounts"`
+}
+
+// Account is an account to enable, with its admins and managed policies
+type Account struct {
+	AccountID  string   `yaml:"accountId"`
+	Privileged bool     `yaml:"privileged"`
+	Admins     []string `yaml:"admins"`
+	// Policies are kept in the account's policy store, so privileged
+	// accounts, which have none, cannot have them
+	Policies []Policy `yaml:"policies"`
+}
+
+// Policy is a policy template identified by its name within the account
+type Policy struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	CedarPolicy string `yaml:"cedarPolicy"`
+}
+
+// Service is the subset of authz.Service used to apply a manifest
+type Service interface {
+	GetAccount(ctx context.Context, accountID string) (*store.Account, error)
+	EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error)
+	RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error)
+	AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) (admin *store.Admin, created bool, err error)
+	ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error)
+	CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error)
+	UpdatePolicy(ctx context.Context, accountID, policyID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error)
+}
+
+// Result counts what applying a manifest changed
+type Result struct {
+	AccountsEnabled int
+	AdminsAdded     int
+	PoliciesCreated int
+	PoliciesUpdated int
+}
+
+// Load reads and validates a YAML or JSON manifest
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bootstrap manifest: %w", err)
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid bootstrap manifest %s: %w", path, err)
+	}
+	return m, nil
+}
+
+// Parse decodes and validates a YAML or JSON manifest, rejecting unknown
+// fields so typos are not silently ignored
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Validate checks the manifest can be applied
+func (m *Manifest) Validate() error {
+	accounts := make(map[string]bool, len(m.Accounts))
+	for i, account := range m.Accounts {
+		if !accountIDPattern.MatchString(account.AccountID) {
+			return fmt.Errorf("accounts[%d]: accountId must be a 12-digit AWS account ID", i)
+		}
+		if accounts[account.AccountID] {
+			return fmt.Errorf("accounts[%d]: account %s is listed more than once", i, account.AccountID)
+		}
+		accounts[account.AccountID] = true
+
+		for j, admin := range account.Admins {
+			if !strings.HasPrefix(admin, "arn:") {
+				return fmt.Errorf("accounts[%d].admins[%d]: %q is not an ARN", i, j, admin)
+			}
+		}
+		if account.Privileged && len(account.Policies) > 0 {
+			return fmt.Errorf("accounts[%d]: privileged account %s has no policy store for policies", i, account.AccountID)
+		}
+		names := make(map[string]bool, len(account.Policies))
+		for j, policy := range account.Policies {
+			if policy.Name == "" {
+				return fmt.Errorf("accounts[%d].policies[%d]: name is required", i, j)
+			}
+			if names[policy.Name] {
+				return fmt.Errorf("accounts[%d].policies[%d]: policy %q is listed more than once", i, j, policy.Name)
+			}
+			names[policy.Name] = true
+			if strings.TrimSpace(policy.CedarPolicy) == "" {
+				return fmt.Errorf("accounts[%d].policies[%d]: cedarPolicy is required", i, j)
+			}
+		}
+	}
+	return nil
+}
+
+// Apply enables the manifest's missing accounts, adds their missing admins
+// and creates or updates their managed policies. Accounts, admins and
+// policies not in the manifest are left alone. An existing account whose
+// privileged flag differs from the manifest is an error, since it cannot be
+// changed in place.
+func Apply(ctx context.Context, svc Service, m *Manifest, logger *slog.Logger) (*Result, error) {
+	result := &Result{}
+	for _, account := range m.Accounts {
+		if err := applyAccount(ctx, svc, account, result, logger); err != nil {
+			return result, fmt.Errorf("account %s: %w", account.AccountID, err)
+		}
+	}
+	return result, nil
+}
+
+func applyAccount(ctx context.Context, svc Service, account Account, result *Result, logger *slog.Logger) error {
+	existing, err := svc.GetAccount(ctx, account.AccountID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case existing == nil:
+		if _, err := svc.EnableAccount(ctx, account.AccountID, CreatedBy, account.Privileged); err != nil {
+			// Another replica applying the manifest may have enabled it first
+			if raced, getErr := svc.GetAccount(ctx, account.AccountID); getErr != nil || raced == nil || raced.OnboardingStatus().State == store.OnboardingFailed {
+				return err
+			}
+		} else {
+			result.AccountsEnabled++
+			logger.Info("bootstrap enabled account", "account_id", account.AccountID, "privileged", account.Privileged)
+		}
+	case existing.Privileged != account.Privileged:
+		return fmt.Errorf("account is enabled with privileged=%t, the manifest declares privileged=%t", existing.Privileged, account.Privileged)
+	case existing.OnboardingStatus().State == store.OnboardingFailed:
+		if _, err := svc.RetryOnboarding(ctx, account.AccountID); err != nil {
+			return err
+		}
+		logger.Info("bootstrap retried account onboarding", "account_id", account.AccountID)
+	}
+
+	for _, principalARN := range account.Admins {
+		_, created, err := svc.AddAdmin(ctx, account.AccountID, principalARN, CreatedBy)
+		if err != nil {
+			return err
+		}
+		if created {
+			result.AdminsAdded++
+			logger.Info("bootstrap added admin", "account_id", account.AccountID, "principal_arn", principalARN)
+		}
+	}
+
+	if len(account.Policies) == 0 {
+		return nil
+	}
+	policies, err := svc.ListPolicies(ctx, account.AccountID)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]*store.Policy, len(policies))
+	for _, p := range policies {
+		byName[p.Name] = p
+	}
+	for _, policy := range account.Policies {
+		current, ok := byName[policy.Name]
+		switch {
+		case !ok:
+			created, err := svc.CreatePolicy(ctx, account.AccountID, policy.Name, policy.Description, policy.CedarPolicy, nil)
+			if err != nil {
+				return fmt.Errorf("policy %q: %w", policy.Name, err)
+			}
+			result.PoliciesCreated++
+			logger.Info("bootstrap created policy", "account_id", account.AccountID, "policy_id", created.PolicyID, "name", policy.Name)
+		case current.Description != policy.Description || authz.CanonicalPolicy(current.CedarPolicy) != authz.CanonicalPolicy(policy.CedarPolicy):
+			if _, err := svc.UpdatePolicy(ctx, account.AccountID, current.PolicyID, policy.Name, policy.Description, policy.CedarPolicy, nil); err != nil {
+				return fmt.Errorf("policy %q: %w", policy.Name, err)
+			}
+			result.PoliciesUpdated++
+			logger.Info("bootstrap updated policy", "account_id", account.AccountID, "policy_id", current.PolicyID, "name", policy.Name)
+		}
+	}
+	return nil
+}
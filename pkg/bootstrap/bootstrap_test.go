@@ -0,0 +1,207 @@
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeService keeps accounts, admins and policies in memory
+type fakeService struct {
+	accounts map[string]*store.Account
+	admins   map[string]bool
+	policies map[string][]*store.Policy
+	retried  []string
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		accounts: make(map[string]*store.Account),
+		admins:   make(map[string]bool),
+		policies: make(map[string][]*store.Policy),
+	}
+}
+
+func (f *fakeService) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	return f.accounts[accountID], nil
+}
+
+func (f *fakeService) EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error) {
+	if f.accounts[accountID] != nil {
+		return nil, fmt.Errorf("account already exists: %s", accountID)
+	}
+	account := &store.Account{AccountID: accountID, Privileged: isPrivileged, CreatedBy: createdBy}
+	f.accounts[accountID] = account
+	return account, nil
+}
+
+func (f *fakeService) RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error) {
+	f.retried = append(f.retried, accountID)
+	account := f.accounts[accountID]
+	account.Onboarding = &store.Onboarding{State: store.OnboardingReady}
+	return account, nil
+}
+
+func (f *fakeService) AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) (*store.Admin, bool, error) {
+	key := accountID + "/" + principalARN
+	created := !f.admins[key]
+	f.admins[key] = true
+	return &store.Admin{AccountID: accountID, PrincipalARN: principalARN, CreatedBy: createdBy}, created, nil
+}
+
+func (f *fakeService) ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error) {
+	return f.policies[accountID], nil
+}
+
+func (f *fakeService) CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error) {
+	policy := &store.Policy{
+		AccountID:   accountID,
+		PolicyID:    fmt.Sprintf("policy-%d", len(f.policies[accountID])),
+		Name:        name,
+		Description: description,
+		CedarPolicy: cedarPolicy,
+	}
+	f.policies[accountID] = append(f.policies[accountID], policy)
+	return policy, nil
+}
+
+func (f *fakeService) UpdatePolicy(ctx context.Context, accountID, policyID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error) {
+	for _, policy := range f.policies[accountID] {
+		if policy.PolicyID == policyID {
+			policy.Name, policy.Description, policy.CedarPolicy = name, description, cedarPolicy
+			return policy, nil
+		}
+	}
+	return nil, fmt.Errorf("policy %s not found", policyID)
+}
+
+const manifest = `
+accounts:
+  - accountId: "111111111111"
+    privileged: true
+    admins:
+      - arn:aws:iam::111111111111:role/platform-admin
+  - accountId: "222222222222"
+    admins:
+      - arn:aws:iam::222222222222:role/admin
+    policies:
+      - name: read-only
+        description: View clusters
+        cedarPolicy: |
+          permit(principal == ?principal, action == ROSA::Action::"DescribeCluster", resource);
+`
+
+func TestApply(t *testing.T) {
+	m, err := Parse([]byte(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := newFakeService()
+	ctx := context.Background()
+
+	result, err := Apply(ctx, svc, m, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != (Result{AccountsEnabled: 2, AdminsAdded: 2, PoliciesCreated: 1}) {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if account := svc.accounts["111111111111"]; !account.Privileged || account.CreatedBy != CreatedBy {
+		t.Errorf("expected a privileged account created by %s, got %+v", CreatedBy, account)
+	}
+
+	// Applying again changes nothing
+	result, err = Apply(ctx, svc, m, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *result != (Result{}) {
+		t.Errorf("expected a second apply to change nothing, got %+v", result)
+	}
+
+	// A changed policy is updated in place
+	m.Accounts[1].Policies[0].Description = "View every cluster"
+	result, err = Apply(ctx, svc, m, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.PoliciesUpdated != 1 || len(svc.policies["222222222222"]) != 1 || svc.policies["222222222222"][0].Description != "View every cluster" {
+		t.Errorf("expected the policy to be updated in place, got %+v and %+v", result, svc.policies["222222222222"])
+	}
+}
+
+func TestApply_PrivilegedMismatch(t *testing.T) {
+	m, err := Parse([]byte(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := newFakeService()
+	svc.accounts["111111111111"] = &store.Account{AccountID: "111111111111"}
+
+	_, err = Apply(context.Background(), svc, m, testLogger())
+	if err == nil || !strings.Contains(err.Error(), "account 111111111111") {
+		t.Errorf("expected an error naming the account, got %v", err)
+	}
+}
+
+func TestApply_RetriesFailedOnboarding(t *testing.T) {
+	m, err := Parse([]byte(manifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	svc := newFakeService()
+	svc.accounts["222222222222"] = &store.Account{
+		AccountID:  "222222222222",
+		Onboarding: &store.Onboarding{State: store.OnboardingFailed, FailedStep: store.OnboardingSeedingDefaults},
+	}
+
+	result, err := Apply(context.Background(), svc, m, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(svc.retried) != 1 || svc.retried[0] != "222222222222" {
+		t.Errorf("expected the failed account's onboarding to be retried, got %v", svc.retried)
+	}
+	if result.AccountsEnabled != 1 || result.PoliciesCreated != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":       "accounts:\n  - accountId: \"111111111111\"\n    privilged: true\n",
+		"bad account ID":      "accounts:\n  - accountId: \"1234\"\n",
+		"duplicate account":   "accounts:\n  - accountId: \"111111111111\"\n  - accountId: \"111111111111\"\n",
+		"admin not an ARN":    "accounts:\n  - accountId: \"111111111111\"\n    admins: [admin]\n",
+		"privileged policies": "accounts:\n  - accountId: \"111111111111\"\n    privileged: true\n    policies:\n      - {name: p, cedarPolicy: permit(principal, action, resource);}\n",
+		"unnamed policy":      "accounts:\n  - accountId: \"111111111111\"\n    policies:\n      - {cedarPolicy: permit(principal, action, resource);}\n",
+		"duplicate policy":    "accounts:\n  - accountId: \"111111111111\"\n    policies:\n      - {name: p, cedarPolicy: a}\n      - {name: p, cedarPolicy: b}\n",
+		"empty policy":        "accounts:\n  - accountId: \"111111111111\"\n    policies:\n      - {name: p}\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Parse([]byte(data)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestParse_JSON(t *testing.T) {
+	m, err := Parse([]byte(`{"accounts": [{"accountId": "111111111111", "privileged": true}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(m.Accounts) != 1 || !m.Accounts[0].Privileged {
+		t.Errorf("unexpected manifest %+v", m)
+	}
+}
@@ -0,0 +1,123 @@
+// Package cache stores cached values that several replicas of the API can
+// share. Values live in namespaces; invalidating a namespace drops every value
+// in it and notifies each replica, so replicas that also keep values in
+// process can drop those too.
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Backends
+const (
+	BackendMemory = "memory"
+	BackendRedis  = "redis"
+)
+
+// Cache is a namespaced byte cache
+type Cache interface {
+	// Get returns key's value in namespace and whether it was found
+	Get(ctx context.Context, namespace, key string) ([]byte, bool, error)
+	// Set stores key's value in namespace for ttl
+	Set(ctx context.Context, namespace, key string, value []byte, ttl time.Duration) error
+	// Invalidate drops every value in namespace for every replica
+	Invalidate(ctx context.Context, namespace string) error
+	// OnInvalidate calls fn whenever namespace is invalidated, by this or any
+	// other replica, or may have been invalidated without notice
+	OnInvalidate(namespace string, fn func())
+}
+
+// listeners holds the OnInvalidate callbacks by namespace
+type listeners struct {
+	mu  sync.Mutex
+	fns map[string][]func()
+}
+
+func (l *listeners) add(namespace string, fn func()) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.fns == nil {
+		l.fns = make(map[string][]func())
+	}
+	l.fns[namespace] = append(l.fns[namespace], fn)
+}
+
+// notify calls namespace's callbacks, or every callback when namespace is
+// empty
+func (l *listeners) notify(namespace string) {
+	l.mu.Lock()
+	var fns []func()
+	for ns, nsFns := range l.fns {
+		if namespace == "" || ns == namespace {
+			fns = append(fns, nsFns...)
+		}
+	}
+	l.mu.Unlock()
+
+	for _, fn := range fns {
+		fn()
+	}
+}
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// Memory is an in-process Cache. Its values and invalidations are only seen
+// by the replica that holds it.
+type Memory struct {
+	mu         sync.Mutex
+	namespaces map[string]map[string]memoryEntry
+	listeners  listeners
+	now        func() time.Time
+}
+
+// NewMemory creates an empty in-process cache
+func NewMemory() *Memory {
+	return &Memory{
+		namespaces: make(map[string]map[string]memoryEntry),
+		now:        time.Now,
+	}
+}
+
+// Get returns key's value unless it has expired
+func (m *Memory) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entry, ok := m.namespaces[namespace][key]
+	if !ok || !m.now().Before(entry.expiresAt) {
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set stores key's value until ttl has passed
+func (m *Memory) Set(ctx context.Context, namespace, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	entries, ok := m.namespaces[namespace]
+	if !ok {
+		entries = make(map[string]memoryEntry)
+		m.namespaces[namespace] = entries
+	}
+	entries[key] = memoryEntry{value: value, expiresAt: m.now().Add(ttl)}
+	return nil
+}
+
+// Invalidate drops namespace and calls its callbacks
+func (m *Memory) Invalidate(ctx context.Context, namespace string) error {
+	m.mu.Lock()
+	delete(m.namespaces, namespace)
+	m.mu.Unlock()
+
+	m.listeners.notify(namespace)
+	return nil
+}
+
+// OnInvalidate registers fn for namespace's invalidations
+func (m *Memory) OnInvalidate(namespace string, fn func()) {
+	m.listeners.add(namespace, fn)
+}
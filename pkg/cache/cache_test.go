@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestMemory(t *testing.T) {
+	now := time.Now()
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	var invalidated atomic.Int32
+	m.OnInvalidate("clusters", func() { invalidated.Add(1) })
+
+	if err := m.Set(ctx, "clusters", "page-1", []byte("a"), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, ok, _ := m.Get(ctx, "clusters", "page-1"); !ok || string(value) != "a" {
+		t.Fatalf("expected cached value, got %q (%v)", value, ok)
+	}
+	if _, ok, _ := m.Get(ctx, "groups", "page-1"); ok {
+		t.Error("expected namespaces to be separate")
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := m.Get(ctx, "clusters", "page-1"); ok {
+		t.Error("expected the value to expire")
+	}
+
+	_ = m.Set(ctx, "clusters", "page-1", []byte("b"), time.Minute)
+	_ = m.Invalidate(ctx, "groups")
+	if invalidated.Load() != 0 {
+		t.Error("expected other namespaces' callbacks not to be called")
+	}
+	_ = m.Invalidate(ctx, "clusters")
+	if _, ok, _ := m.Get(ctx, "clusters", "page-1"); ok || invalidated.Load() != 1 {
+		t.Errorf("expected the namespace to be dropped and its callback called, got %v and %d calls", ok, invalidated.Load())
+	}
+}
+
+// newRedisReplicas connects n replicas to one miniredis server and runs
+// their invalidation subscriptions
+func newRedisReplicas(t *testing.T, n int) (*miniredis.Miniredis, []*Redis) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	replicas := make([]*Redis, n)
+	for i := range replicas {
+		replicas[i] = newRedis(redis.NewClient(&redis.Options{Addr: server.Addr()}), "rosa", testLogger())
+		t.Cleanup(func() { _ = replicas[i].Close() })
+		go replicas[i].Run(ctx)
+	}
+	// Wait for every subscription so no invalidation is missed
+	deadline := time.Now().Add(2 * time.Second)
+	for server.PubSubNumSub("rosa:invalidations")["rosa:invalidations"] < n {
+		if time.Now().After(deadline) {
+			t.Fatal("replicas did not subscribe in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return server, replicas
+}
+
+func TestRedis_SharedBetweenReplicas(t *testing.T) {
+	server, replicas := newRedisReplicas(t, 2)
+	ctx := context.Background()
+
+	if err := replicas[0].Set(ctx, "clusters", "page-1", []byte("a"), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	value, ok, err := replicas[1].Get(ctx, "clusters", "page-1")
+	if err != nil || !ok || string(value) != "a" {
+		t.Fatalf("expected the other replica to see the value, got %q (%v, %v)", value, ok, err)
+	}
+
+	server.FastForward(time.Minute)
+	if _, ok, _ := replicas[1].Get(ctx, "clusters", "page-1"); ok {
+		t.Error("expected the value to expire")
+	}
+}
+
+func TestRedis_InvalidationReachesEveryReplica(t *testing.T) {
+	_, replicas := newRedisReplicas(t, 2)
+	ctx := context.Background()
+
+	var local, remote atomic.Int32
+	replicas[0].OnInvalidate("clusters", func() { local.Add(1) })
+	replicas[1].OnInvalidate("clusters", func() { remote.Add(1) })
+
+	_ = replicas[0].Set(ctx, "clusters", "page-1", []byte("a"), time.Minute)
+	if _, ok, _ := replicas[1].Get(ctx, "clusters", "page-1"); !ok {
+		t.Fatal("expected the value to be shared")
+	}
+
+	if err := replicas[0].Invalidate(ctx, "clusters"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if local.Load() != 1 {
+		t.Errorf("expected the invalidating replica's callback to run once, got %d", local.Load())
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for remote.Load() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the invalidation to reach the other replica")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if _, ok, _ := replicas[1].Get(ctx, "clusters", "page-1"); ok {
+		t.Error("expected the other replica to stop seeing the invalidated value")
+	}
+
+	// Values written after the invalidation are shared again
+	_ = replicas[1].Set(ctx, "clusters", "page-1", []byte("b"), time.Minute)
+	if value, ok, _ := replicas[0].Get(ctx, "clusters", "page-1"); !ok || string(value) != "b" {
+		t.Errorf("expected the new value, got %q (%v)", value, ok)
+	}
+	if local.Load() != 1 {
+		t.Errorf("expected the replica's own invalidation message not to call its callback again, got %d", local.Load())
+	}
+}
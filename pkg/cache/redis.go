@@ -0,0 +1,216 @@
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// subscribeRetry is how long Run waits after the invalidation subscription
+// fails before receiving again
+const subscribeRetry = time.Second
+
+// RedisConfig configures a Redis-compatible server such as ElastiCache
+type RedisConfig struct {
+	// Addrs are the server addresses; with more than one, or with
+	// ClusterMode, the client discovers and routes to the cluster's shards
+	Addrs       []string
+	ClusterMode bool
+	Username    string
+	Password    string
+	// PasswordFunc, when set, returns the current password in place of
+	// Password each time a connection is opened
+	PasswordFunc func() string
+	TLS          bool
+	// KeyPrefix starts every key and the invalidation channel, so several
+	// deployments can share a server
+	KeyPrefix string
+}
+
+// Redis is a Cache shared by every replica through a Redis-compatible
+// server. Each namespace has a generation counter that is part of its keys:
+// invalidating the namespace increments the generation, which orphans the old
+// keys until they expire, and publishes the new generation so other replicas
+// stop reading the old keys and run their callbacks.
+type Redis struct {
+	client    redis.UniversalClient
+	prefix    string
+	channel   string
+	logger    *slog.Logger
+	listeners listeners
+
+	mu          sync.Mutex
+	generations map[string]int64
+}
+
+// NewRedis creates a Redis cache. Run must be running to receive other
+// replicas' invalidations.
+func NewRedis(cfg RedisConfig, logger *slog.Logger) *Redis {
+	opts := &redis.UniversalOptions{
+		Addrs:         cfg.Addrs,
+		IsClusterMode: cfg.ClusterMode,
+		Username:      cfg.Username,
+		Password:      cfg.Password,
+	}
+	if cfg.PasswordFunc != nil {
+		opts.CredentialsProvider = func() (string, string) {
+			return cfg.Username, cfg.PasswordFunc()
+		}
+	}
+	if cfg.TLS {
+		opts.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return newRedis(redis.NewUniversalClient(opts), cfg.KeyPrefix, logger)
+}
+
+func newRedis(client redis.UniversalClient, prefix string, logger *slog.Logger) *Redis {
+	return &Redis{
+		client:      client,
+		prefix:      prefix,
+		channel:     prefix + ":invalidations",
+		logger:      logger,
+		generations: make(map[string]int64),
+	}
+}
+
+// Get reads key from namespace's current generation
+func (c *Redis) Get(ctx context.Context, namespace, key string) ([]byte, bool, error) {
+	generation, err := c.generation(ctx, namespace)
+	if err != nil {
+		return nil, false, err
+	}
+	value, err := c.client.Get(ctx, c.key(namespace, generation, key)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get cached value: %w", err)
+	}
+	return value, true, nil
+}
+
+// Set writes key to namespace's current generation
+func (c *Redis) Set(ctx context.Context, namespace, key string, value []byte, ttl time.Duration) error {
+	generation, err := c.generation(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if err := c.client.Set(ctx, c.key(namespace, generation, key), value, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set cached value: %w", err)
+	}
+	return nil
+}
+
+// Invalidate starts a new generation of namespace and publishes it
+func (c *Redis) Invalidate(ctx context.Context, namespace string) error {
+	generation, err := c.client.Incr(ctx, c.generationKey(namespace)).Result()
+	if err != nil {
+		return fmt.Errorf("failed to invalidate cache namespace: %w", err)
+	}
+	c.setGeneration(namespace, generation)
+	c.listeners.notify(namespace)
+
+	if err := c.client.Publish(ctx, c.channel, namespace+" "+strconv.FormatInt(generation, 10)).Err(); err != nil {
+		return fmt.Errorf("failed to publish cache invalidation: %w", err)
+	}
+	return nil
+}
+
+// OnInvalidate registers fn for namespace's invalidations
+func (c *Redis) OnInvalidate(namespace string, fn func()) {
+	c.listeners.add(namespace, fn)
+}
+
+// Run receives other replicas' invalidations until ctx is cancelled. Each
+// time the subscription is (re)established, invalidations may have been
+// missed, so every namespace's generation is read again and every callback
+// is called.
+func (c *Redis) Run(ctx context.Context) {
+	pubsub := c.client.Subscribe(ctx, c.channel)
+	defer pubsub.Close()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			c.logger.Warn("failed to receive cache invalidations", "error", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(subscribeRetry):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			c.mu.Lock()
+			c.generations = make(map[string]int64)
+			c.mu.Unlock()
+			c.listeners.notify("")
+		case *redis.Message:
+			namespace, generation, ok := strings.Cut(msg.Payload, " ")
+			gen, err := strconv.ParseInt(generation, 10, 64)
+			if !ok || err != nil {
+				c.logger.Warn("ignoring malformed cache invalidation", "payload", msg.Payload)
+				continue
+			}
+			if c.setGeneration(namespace, gen) {
+				c.listeners.notify(namespace)
+			}
+		}
+	}
+}
+
+// Close closes the connections to the server
+func (c *Redis) Close() error {
+	return c.client.Close()
+}
+
+// generation returns namespace's generation, reading it from the server the
+// first time
+func (c *Redis) generation(ctx context.Context, namespace string) (int64, error) {
+	c.mu.Lock()
+	generation, ok := c.generations[namespace]
+	c.mu.Unlock()
+	if ok {
+		return generation, nil
+	}
+
+	generation, err := c.client.Get(ctx, c.generationKey(namespace)).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return 0, fmt.Errorf("failed to get cache generation: %w", err)
+	}
+	c.setGeneration(namespace, generation)
+	return generation, nil
+}
+
+// setGeneration records generation unless a newer one is known, reporting
+// whether it changed
+func (c *Redis) setGeneration(namespace string, generation int64) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if current, ok := c.generations[namespace]; ok && current >= generation {
+		return false
+	}
+	c.generations[namespace] = generation
+	return true
+}
+
+func (c *Redis) generationKey(namespace string) string {
+	return c.prefix + ":" + namespace + ":generation"
+}
+
+func (c *Redis) key(namespace string, generation int64, key string) string {
+	return c.prefix + ":" + namespace + ":" + strconv.FormatInt(generation, 10) + ":" + key
+}
@@ -0,0 +1,224 @@
+// Package canary continuously checks the work delivery path end to end: it
+// submits a tiny ManifestWork to a designated canary cluster through Maestro,
+// waits for the agent to report it applied, and deletes it again, so a
+// broken path shows in metrics and readiness before customers notice.
+package canary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// LabelCanary marks the ManifestWorks and objects created by the canary
+const LabelCanary = "rosa.openshift.io/canary"
+
+var (
+	probeResults = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_canary_probes_total",
+		Help: "Delivery canary probes by result (success, failure).",
+	}, []string{"result"})
+
+	probeUp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rosa_canary_up",
+		Help: "1 when the last delivery canary probe saw its ManifestWork applied, 0 when it failed.",
+	})
+
+	deliveryLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "rosa_canary_delivery_seconds",
+		Help:    "Time from submitting the canary ManifestWork to the agent reporting it applied.",
+		Buckets: prometheus.ExponentialBuckets(0.5, 2, 10),
+	})
+
+	lastSuccess = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rosa_canary_last_success_timestamp_seconds",
+		Help: "Unix time of the last successful delivery canary probe.",
+	})
+)
+
+// WorkClient is the part of the Maestro client the canary uses
+type WorkClient interface {
+	CreateManifestWork(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error)
+	GetManifestWork(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error)
+	DeleteManifestWork(ctx context.Context, clusterName string, name string) error
+}
+
+// Config configures the canary
+type Config struct {
+	// ClusterName is the management cluster canary works are sent to
+	ClusterName string
+	// Namespace on the cluster the canary ConfigMap is created in
+	Namespace string
+	// Interval between probes
+	Interval time.Duration
+	// Timeout bounds how long a probe waits for its work to be applied
+	Timeout time.Duration
+	// PollInterval is how often a probe checks whether its work is applied
+	PollInterval time.Duration
+}
+
+// Canary probes the delivery path on an interval
+type Canary struct {
+	cfg          Config
+	client       WorkClient
+	replica      string
+	reportHealth func(error)
+	logger       *slog.Logger
+}
+
+// New creates a canary sending works through client
+func New(cfg Config, client WorkClient, logger *slog.Logger) *Canary {
+	if cfg.Namespace == "" {
+		cfg.Namespace = "default"
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	return &Canary{
+		cfg:     cfg,
+		client:  client,
+		replica: uuid.NewString()[:8],
+		logger:  logger,
+	}
+}
+
+// WithHealth calls report after each probe with the error that failed it, or
+// nil when the work was applied
+func (c *Canary) WithHealth(report func(error)) *Canary {
+	c.reportHealth = report
+	return c
+}
+
+// Run probes immediately and then on every interval until ctx is done
+func (c *Canary) Run(ctx context.Context) {
+	c.logger.Info("delivery canary started", "cluster", c.cfg.ClusterName, "interval", c.cfg.Interval, "timeout", c.cfg.Timeout)
+	ticker := time.NewTicker(c.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		c.probeOnce(ctx)
+
+		select {
+		case <-ctx.Done():
+			c.logger.Info("delivery canary stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// probeOnce runs one probe and records its result, unless ctx ended it
+func (c *Canary) probeOnce(ctx context.Context) {
+	latency, err := c.Probe(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	if c.reportHealth != nil {
+		c.reportHealth(err)
+	}
+	if err != nil {
+		probeResults.WithLabelValues("failure").Inc()
+		probeUp.Set(0)
+		c.logger.Error("delivery canary probe failed", "error", err, "cluster", c.cfg.ClusterName)
+		return
+	}
+	probeResults.WithLabelValues("success").Inc()
+	probeUp.Set(1)
+	deliveryLatency.Observe(latency.Seconds())
+	lastSuccess.SetToCurrentTime()
+	c.logger.Debug("delivery canary probe succeeded", "cluster", c.cfg.ClusterName, "latency", latency)
+}
+
+// Probe submits a canary ManifestWork, waits up to the timeout for it to be
+// applied and deletes it, returning how long it took to be applied
+func (c *Canary) Probe(ctx context.Context) (time.Duration, error) {
+	mw, err := c.manifestWork()
+	if err != nil {
+		return 0, err
+	}
+
+	start := time.Now()
+	if _, err := c.client.CreateManifestWork(ctx, c.cfg.ClusterName, mw); err != nil {
+		return 0, fmt.Errorf("failed to submit canary work: %w", err)
+	}
+	// Remove the work whatever happens, even as the server shuts down
+	defer func() {
+		delCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.cfg.Timeout)
+		defer cancel()
+		if err := c.client.DeleteManifestWork(delCtx, c.cfg.ClusterName, mw.Name); err != nil {
+			c.logger.Warn("failed to delete canary work", "error", err, "cluster", c.cfg.ClusterName, "work_name", mw.Name)
+		}
+	}()
+
+	waitCtx, cancel := context.WithTimeout(ctx, c.cfg.Timeout)
+	defer cancel()
+	ticker := time.NewTicker(c.cfg.PollInterval)
+	defer ticker.Stop()
+
+	var lastErr error
+	for {
+		got, err := c.client.GetManifestWork(waitCtx, c.cfg.ClusterName, mw.Name)
+		if err == nil && got != nil && meta.IsStatusConditionTrue(got.Status.Conditions, workv1.WorkApplied) {
+			return time.Since(start), nil
+		}
+		lastErr = err
+
+		select {
+		case <-waitCtx.Done():
+			if lastErr != nil {
+				return 0, fmt.Errorf("canary work not applied within %s: %w", c.cfg.Timeout, lastErr)
+			}
+			return 0, fmt.Errorf("canary work not applied within %s", c.cfg.Timeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+// manifestWork builds a ManifestWork holding a single ConfigMap. Each probe
+// uses a new name so a work left behind by an earlier probe cannot pass it.
+func (c *Canary) manifestWork() (*workv1.ManifestWork, error) {
+	name := strings.Join([]string{"rosa-canary", c.replica, fmt.Sprint(time.Now().Unix())}, "-")
+	labels := map[string]string{LabelCanary: "true"}
+
+	cm, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":      name,
+			"namespace": c.cfg.Namespace,
+			"labels":    labels,
+		},
+		"data": map[string]string{"probedAt": time.Now().UTC().Format(time.RFC3339)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build canary ConfigMap: %w", err)
+	}
+
+	return &workv1.ManifestWork{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "work.open-cluster-management.io/v1",
+			Kind:       "ManifestWork",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: c.cfg.ClusterName,
+			Labels:    labels,
+		},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: cm}}},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,138 @@
+package canary
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// fakeWorkClient applies works after appliedAfter Get calls
+type fakeWorkClient struct {
+	mu           sync.Mutex
+	appliedAfter int
+	createErr    error
+	works        map[string]*workv1.ManifestWork
+	gets         int
+	deleted      []string
+}
+
+func (f *fakeWorkClient) CreateManifestWork(ctx context.Context, clusterName string, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	f.works[mw.Name] = mw.DeepCopy()
+	return mw, nil
+}
+
+func (f *fakeWorkClient) GetManifestWork(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.gets++
+	mw := f.works[name].DeepCopy()
+	if f.appliedAfter >= 0 && f.gets > f.appliedAfter {
+		mw.Status.Conditions = []metav1.Condition{{Type: workv1.WorkApplied, Status: metav1.ConditionTrue}}
+	}
+	return mw, nil
+}
+
+func (f *fakeWorkClient) DeleteManifestWork(ctx context.Context, clusterName string, name string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.works, name)
+	f.deleted = append(f.deleted, name)
+	return nil
+}
+
+func newTestCanary(client *fakeWorkClient) *Canary {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	return New(Config{
+		ClusterName:  "canary-mc",
+		Interval:     time.Hour,
+		Timeout:      50 * time.Millisecond,
+		PollInterval: time.Millisecond,
+	}, client, logger)
+}
+
+func TestProbe_AppliedThenDeleted(t *testing.T) {
+	client := &fakeWorkClient{appliedAfter: 2, works: map[string]*workv1.ManifestWork{}}
+
+	if _, err := newTestCanary(client).Probe(context.Background()); err != nil {
+		t.Fatalf("expected probe to succeed, got %v", err)
+	}
+	if client.gets != 3 {
+		t.Errorf("expected the work to be polled until applied, got %d polls", client.gets)
+	}
+	if len(client.deleted) != 1 || len(client.works) != 0 {
+		t.Errorf("expected the canary work to be deleted, got %v", client.deleted)
+	}
+}
+
+func TestProbe_NotAppliedInTime(t *testing.T) {
+	client := &fakeWorkClient{appliedAfter: 1 << 30, works: map[string]*workv1.ManifestWork{}}
+
+	if _, err := newTestCanary(client).Probe(context.Background()); err == nil {
+		t.Fatal("expected probe to time out")
+	}
+	if len(client.deleted) != 1 {
+		t.Error("expected the canary work to be deleted after a timeout")
+	}
+}
+
+func TestProbe_SubmitFails(t *testing.T) {
+	client := &fakeWorkClient{createErr: errors.New("grpc unavailable"), works: map[string]*workv1.ManifestWork{}}
+
+	if _, err := newTestCanary(client).Probe(context.Background()); err == nil {
+		t.Fatal("expected probe to fail")
+	}
+	if len(client.deleted) != 0 {
+		t.Error("expected nothing to be deleted when nothing was submitted")
+	}
+}
+
+func TestProbe_WorkHoldsCanaryConfigMap(t *testing.T) {
+	c := newTestCanary(&fakeWorkClient{})
+
+	mw, err := c.manifestWork()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if mw.Namespace != "canary-mc" || mw.Labels[LabelCanary] != "true" {
+		t.Errorf("unexpected work metadata: %+v", mw.ObjectMeta)
+	}
+	if len(mw.Spec.Workload.Manifests) != 1 {
+		t.Fatalf("expected one manifest, got %d", len(mw.Spec.Workload.Manifests))
+	}
+}
+
+func TestRun_ReportsHealth(t *testing.T) {
+	client := &fakeWorkClient{appliedAfter: 0, works: map[string]*workv1.ManifestWork{}}
+	reported := make(chan error, 1)
+	c := newTestCanary(client).WithHealth(func(err error) { reported <- err })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.Run(ctx)
+	}()
+
+	select {
+	case err := <-reported:
+		if err != nil {
+			t.Errorf("expected a healthy report, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a health report")
+	}
+	cancel()
+	<-done
+}
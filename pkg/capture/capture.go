@@ -0,0 +1,194 @@
+// Package capture records sanitized envelopes of API requests so they can
+// be re-issued against a local instance to debug tenant-specific failures.
+// Envelopes keep the route, a fixed subset of headers and the caller's
+// identity; bodies are kept as a hash, and in full only for accounts that
+// opted in.
+package capture
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Identity headers set on replayed requests, as API Gateway sends them
+const (
+	headerAccountID = "X-Amz-Account-Id"
+	headerCallerARN = "X-Amz-Caller-Arn"
+	headerUserID    = "X-Amz-User-Id"
+)
+
+// capturedHeaders are the request headers kept in envelopes, besides those
+// starting with capturedHeaderPrefix. Credentials, gateway secrets and
+// client addresses are never kept.
+var capturedHeaders = []string{
+	"Accept",
+	"Accept-Language",
+	"Content-Type",
+	"X-Dry-Run",
+	"X-Rosa-Target-Account-Id",
+}
+
+// capturedHeaderPrefix keeps request tags
+const capturedHeaderPrefix = "X-Rosa-Request-Tag-"
+
+// ErrBodyNotCaptured is returned when replaying a request whose body was
+// only hashed
+var ErrBodyNotCaptured = errors.New("request body was not captured")
+
+// Envelope is one captured request and the status it was answered with
+type Envelope struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId,omitempty"`
+	AccountID string    `json:"accountId,omitempty"`
+	CallerARN string    `json:"callerArn,omitempty"`
+	UserID    string    `json:"userId,omitempty"`
+	Method    string    `json:"method"`
+	// Route is the route template, such as /api/v0/clusters/{id}
+	Route  string            `json:"route,omitempty"`
+	Path   string            `json:"path"`
+	Query  string            `json:"query,omitempty"`
+	Header map[string]string `json:"header,omitempty"`
+	// BodySize and BodySHA256 describe the request body; Body holds it only
+	// for accounts whose bodies are captured
+	BodySize   int    `json:"bodySize"`
+	BodySHA256 string `json:"bodySha256,omitempty"`
+	Body       []byte `json:"body,omitempty"`
+	Status     int    `json:"status"`
+}
+
+// NewEnvelope describes r, keeping its body when keepBody is set. The caller
+// fills in identity, the route and the status.
+func NewEnvelope(r *http.Request, body []byte, keepBody bool) Envelope {
+	env := Envelope{
+		Time:     time.Now().UTC(),
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Query:    r.URL.RawQuery,
+		BodySize: len(body),
+	}
+	for _, name := range capturedHeaders {
+		if v := r.Header.Get(name); v != "" {
+			env.setHeader(name, v)
+		}
+	}
+	for name, values := range r.Header {
+		if strings.HasPrefix(name, capturedHeaderPrefix) && len(values) > 0 {
+			env.setHeader(name, values[0])
+		}
+	}
+	if len(body) > 0 {
+		sum := sha256.Sum256(body)
+		env.BodySHA256 = hex.EncodeToString(sum[:])
+		if keepBody {
+			env.Body = body
+		}
+	}
+	return env
+}
+
+func (e *Envelope) setHeader(name, value string) {
+	if e.Header == nil {
+		e.Header = map[string]string{}
+	}
+	e.Header[name] = value
+}
+
+// Request builds the request re-issuing e against the API at target, such
+// as http://localhost:8000. It returns ErrBodyNotCaptured when e had a body
+// that was not kept.
+func (e Envelope) Request(ctx context.Context, target string) (*http.Request, error) {
+	if e.BodySize > 0 && e.Body == nil {
+		return nil, ErrBodyNotCaptured
+	}
+	url := strings.TrimSuffix(target, "/") + e.Path
+	if e.Query != "" {
+		url += "?" + e.Query
+	}
+	var body io.Reader
+	if e.Body != nil {
+		body = bytes.NewReader(e.Body)
+	}
+	req, err := http.NewRequestWithContext(ctx, e.Method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range e.Header {
+		req.Header.Set(name, value)
+	}
+	if e.AccountID != "" {
+		req.Header.Set(headerAccountID, e.AccountID)
+	}
+	if e.CallerARN != "" {
+		req.Header.Set(headerCallerARN, e.CallerARN)
+	}
+	if e.UserID != "" {
+		req.Header.Set(headerUserID, e.UserID)
+	}
+	return req, nil
+}
+
+// Read decodes the envelopes written by a Writer
+func Read(r io.Reader) ([]Envelope, error) {
+	var envs []Envelope
+	dec := json.NewDecoder(r)
+	for {
+		var env Envelope
+		err := dec.Decode(&env)
+		if errors.Is(err, io.EOF) {
+			return envs, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid envelope %d: %w", len(envs)+1, err)
+		}
+		envs = append(envs, env)
+	}
+}
+
+// Writer appends envelopes to a file as JSON lines
+type Writer struct {
+	mu   sync.Mutex
+	file *os.File
+	w    *bufio.Writer
+}
+
+// NewWriter opens path for appending, creating it if needed
+func NewWriter(path string) (*Writer, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open capture file: %w", err)
+	}
+	return &Writer{file: f, w: bufio.NewWriter(f)}, nil
+}
+
+// Write appends env
+func (w *Writer) Write(env Envelope) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := json.NewEncoder(w.w).Encode(env); err != nil {
+		return fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	if err := w.w.Flush(); err != nil {
+		return fmt.Errorf("failed to write envelope: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
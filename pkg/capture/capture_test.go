@@ -0,0 +1,106 @@
+package capture
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewEnvelope_KeepsHeaderSubset(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/clusters?dryRun=true", nil)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("X-Rosa-Gateway-Secret", "s3cret")
+	req.Header.Set("X-Forwarded-For", "198.51.100.1")
+	req.Header.Set("X-Rosa-Request-Tag-Team", "payments")
+
+	env := NewEnvelope(req, []byte(`{"name":"c1"}`), false)
+
+	if env.Header["Content-Type"] != "application/json" {
+		t.Errorf("expected Content-Type to be kept, got %v", env.Header)
+	}
+	if env.Header["X-Rosa-Request-Tag-Team"] != "payments" {
+		t.Errorf("expected request tags to be kept, got %v", env.Header)
+	}
+	for _, name := range []string{"Authorization", "X-Rosa-Gateway-Secret", "X-Forwarded-For"} {
+		if _, ok := env.Header[name]; ok {
+			t.Errorf("expected %s not to be captured", name)
+		}
+	}
+	if env.Query != "dryRun=true" {
+		t.Errorf("expected query dryRun=true, got %q", env.Query)
+	}
+	if env.Body != nil {
+		t.Error("expected the body not to be kept")
+	}
+	if env.BodySize != 13 || len(env.BodySHA256) != 64 {
+		t.Errorf("expected the body size and hash, got %d %q", env.BodySize, env.BodySHA256)
+	}
+}
+
+func TestEnvelope_Request(t *testing.T) {
+	env := Envelope{
+		AccountID: "123456789012",
+		CallerARN: "arn:aws:iam::123456789012:user/alice",
+		Method:    http.MethodPost,
+		Path:      "/api/v0/clusters",
+		Query:     "dryRun=true",
+		Header:    map[string]string{"Content-Type": "application/json"},
+		BodySize:  13,
+		Body:      []byte(`{"name":"c1"}`),
+	}
+
+	req, err := env.Request(context.Background(), "http://localhost:8000/")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := req.URL.String(); got != "http://localhost:8000/api/v0/clusters?dryRun=true" {
+		t.Errorf("unexpected URL %s", got)
+	}
+	if req.Header.Get(headerAccountID) != "123456789012" || req.Header.Get(headerCallerARN) != env.CallerARN {
+		t.Errorf("expected identity headers, got %v", req.Header)
+	}
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"name":"c1"}` {
+		t.Errorf("unexpected body %s", body)
+	}
+
+	env.Body = nil
+	if _, err := env.Request(context.Background(), "http://localhost:8000"); !errors.Is(err, ErrBodyNotCaptured) {
+		t.Errorf("expected ErrBodyNotCaptured, got %v", err)
+	}
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "capture.jsonl")
+	w, err := NewWriter(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, id := range []string{"req-1", "req-2"} {
+		if err := w.Write(Envelope{RequestID: id, Method: http.MethodGet, Path: "/api/v0/clusters", Status: 200, Body: []byte("a\nb")}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	envs, err := Read(strings.NewReader(string(data)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(envs) != 2 || envs[1].RequestID != "req-2" || string(envs[0].Body) != "a\nb" {
+		t.Errorf("unexpected envelopes %+v", envs)
+	}
+}
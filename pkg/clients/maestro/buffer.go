@@ -0,0 +1,99 @@
+package maestro
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+const (
+	// maxPooledBufferBytes keeps buffers that grew for an unusually large
+	// body out of the pool, so one big response does not pin its memory
+	maxPooledBufferBytes = 1 << 20
+
+	// maxPresizedItems bounds how many list items are allocated up front from
+	// the size a response reports
+	maxPresizedItems = 1000
+)
+
+// bufferPool holds the buffers request and response bodies are marshaled
+// into and read into
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBufferBytes {
+		return
+	}
+	buf.Reset()
+	bufferPool.Put(buf)
+}
+
+// readBody reads a response body into a pooled buffer, sized up front from
+// its Content-Length when that is known. The caller returns the buffer with
+// putBuffer once nothing refers to its bytes.
+func readBody(r io.Reader, contentLength int64) (*bytes.Buffer, error) {
+	buf := getBuffer()
+	if contentLength > 0 {
+		// ReadFrom grows the buffer whenever less than MinRead bytes are
+		// free, so leave that much over
+		buf.Grow(int(contentLength) + bytes.MinRead)
+	}
+	if _, err := buf.ReadFrom(r); err != nil {
+		putBuffer(buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+// pooledBody is a request body marshaled into a pooled buffer. The transport
+// closes the body once it has been sent, which may be after Do returns, so
+// the buffer goes back to the pool on Close rather than when the call ends.
+type pooledBody struct {
+	mu  sync.Mutex
+	buf *bytes.Buffer
+}
+
+// newJSONBody marshals v into a pooled request body and returns it with its
+// length
+func newJSONBody(v any) (*pooledBody, int64, error) {
+	buf := getBuffer()
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		putBuffer(buf)
+		return nil, 0, err
+	}
+	// Drop the newline Encode appends so the body matches json.Marshal
+	buf.Truncate(buf.Len() - 1)
+	return &pooledBody{buf: buf}, int64(buf.Len()), nil
+}
+
+func (b *pooledBody) Read(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.buf == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return b.buf.Read(p)
+}
+
+func (b *pooledBody) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.buf != nil {
+		putBuffer(b.buf)
+		b.buf = nil
+	}
+	return nil
+}
+
+// presize returns the capacity to allocate for a list page that reports
+// size items
+func presize(size int) int {
+	return max(0, min(size, maxPresizedItems))
+}
@@ -0,0 +1,171 @@
+package maestro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestNewJSONBody(t *testing.T) {
+	req := &ConsumerCreateRequest{Name: "mc-<01>&"}
+
+	body, length, err := newJSONBody(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, _ := json.Marshal(req)
+	if !bytes.Equal(got, want) {
+		t.Errorf("expected body %s, got %s", want, got)
+	}
+	if length != int64(len(want)) {
+		t.Errorf("expected length %d, got %d", len(want), length)
+	}
+
+	if err := body.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := body.Close(); err != nil {
+		t.Fatalf("expected a second close to be a no-op, got %v", err)
+	}
+	if _, err := body.Read(make([]byte, 1)); err == nil {
+		t.Error("expected read after close to fail")
+	}
+}
+
+func TestReadBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		contentLength int64
+	}{
+		{name: "known length", body: `{"id":"a"}`, contentLength: 10},
+		{name: "unknown length", body: `{"id":"a"}`, contentLength: -1},
+		{name: "larger than MinRead", body: strings.Repeat("x", 4096), contentLength: 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf, err := readBody(strings.NewReader(tt.body), tt.contentLength)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer putBuffer(buf)
+			if buf.String() != tt.body {
+				t.Errorf("expected %q, got %q", tt.body, buf.String())
+			}
+		})
+	}
+}
+
+func TestPutBuffer_DropsLargeBuffers(t *testing.T) {
+	buf := bytes.NewBuffer(make([]byte, 10, maxPooledBufferBytes+1))
+	putBuffer(buf)
+	if buf.Len() != 10 {
+		t.Error("expected an oversized buffer to be left out of the pool")
+	}
+
+	buf = getBuffer()
+	buf.WriteString("data")
+	putBuffer(buf)
+	if buf.Len() != 0 {
+		t.Error("expected a pooled buffer to be reset")
+	}
+}
+
+func TestPresize(t *testing.T) {
+	tests := []struct {
+		size int
+		want int
+	}{
+		{size: -1, want: 0},
+		{size: 0, want: 0},
+		{size: 100, want: 100},
+		{size: 1 << 20, want: maxPresizedItems},
+	}
+	for _, tt := range tests {
+		if got := presize(tt.size); got != tt.want {
+			t.Errorf("presize(%d) = %d, want %d", tt.size, got, tt.want)
+		}
+	}
+}
+
+// Reading a one-item page the way the client used to, with a
+// fresh slice from io.ReadAll, against reading it into a pooled buffer
+// sized from Content-Length
+func BenchmarkReadAll(b *testing.B) {
+	body := largeResourceBundleListBody(b, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		data, err := io.ReadAll(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var list ResourceBundleList
+		if err := json.Unmarshal(data, &list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadBody(b *testing.B) {
+	body := largeResourceBundleListBody(b, 1)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		buf, err := readBody(bytes.NewReader(body), int64(len(body)))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var list ResourceBundleList
+		if err := json.Unmarshal(buf.Bytes(), &list); err != nil {
+			b.Fatal(err)
+		}
+		putBuffer(buf)
+	}
+}
+
+// benchConsumerRequest is a create request with enough labels that the body
+// is a few KiB
+func benchConsumerRequest() *ConsumerCreateRequest {
+	req := &ConsumerCreateRequest{Name: "management-cluster-01", Labels: map[string]string{}}
+	for i := range 64 {
+		req.Labels[fmt.Sprintf("example.com/label-%02d", i)] = strings.Repeat("v", 32)
+	}
+	return req
+}
+
+// Marshaling a request body with json.Marshal, which copies the encoded body
+// into a fresh slice every call, against encoding it into a pooled buffer
+func BenchmarkMarshalRequest(b *testing.B) {
+	req := benchConsumerRequest()
+	b.ReportAllocs()
+	for range b.N {
+		data, err := json.Marshal(req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, _ = io.Copy(io.Discard, bytes.NewReader(data))
+	}
+}
+
+func BenchmarkNewJSONBody(b *testing.B) {
+	req := benchConsumerRequest()
+	b.ReportAllocs()
+	for range b.N {
+		body, _, err := newJSONBody(req)
+		if err != nil {
+			b.Fatal(err)
+		}
+		_, _ = io.Copy(io.Discard, body)
+		_ = body.Close()
+	}
+}
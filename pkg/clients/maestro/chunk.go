@@ -0,0 +1,96 @@
+package maestro
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// Labels shared by the ManifestWorks produced by ChunkManifestWork
+const (
+	// LabelWorkGroup correlates the chunks of one logical work
+	LabelWorkGroup = "rosa.openshift.io/work-group"
+	// LabelWorkGroupSize is the number of chunks in the group
+	LabelWorkGroupSize = "rosa.openshift.io/work-group-size"
+	// LabelWorkGroupIndex is the zero-based position of a chunk in the group
+	LabelWorkGroupIndex = "rosa.openshift.io/work-group-index"
+)
+
+// maxChunkIndex bounds the chunk count and sizes the placeholder name and labels
+const maxChunkIndex = 9999
+
+// ErrManifestTooLarge is returned when a single manifest cannot fit in one message
+var ErrManifestTooLarge = errors.New("manifest exceeds the message size limit on its own")
+
+// ChunkName returns the name of chunk index of the work group
+func ChunkName(group string, index int) string {
+	return fmt.Sprintf("%s-chunk-%d", group, index)
+}
+
+// ChunkManifestWork splits the manifests of work across as few ManifestWorks
+// as possible such that each one's estimated CloudEvent size stays within
+// maxBytes. Manifest order is preserved. Each chunk is named
+// ChunkName(work.Name, i) and labelled with the group name, size and index.
+func ChunkManifestWork(work *workv1.ManifestWork, maxBytes int) ([]*workv1.ManifestWork, error) {
+	// Estimate with the longest name and labels a chunk can carry so the
+	// final values never push a chunk over the limit
+	base := work.DeepCopy()
+	base.Spec.Workload.Manifests = nil
+	base.Name = ChunkName(work.Name, maxChunkIndex)
+	if base.Labels == nil {
+		base.Labels = map[string]string{}
+	}
+	base.Labels[LabelWorkGroup] = work.Name
+	base.Labels[LabelWorkGroupSize] = strconv.Itoa(maxChunkIndex)
+	base.Labels[LabelWorkGroupIndex] = strconv.Itoa(maxChunkIndex)
+
+	var chunks []*workv1.ManifestWork
+	current := base.DeepCopy()
+
+	for i, manifest := range work.Spec.Workload.Manifests {
+		candidate := current.DeepCopy()
+		candidate.Spec.Workload.Manifests = append(candidate.Spec.Workload.Manifests, manifest)
+
+		size, err := EstimateCloudEventSize(candidate)
+		if err != nil {
+			return nil, err
+		}
+		if size <= maxBytes {
+			current = candidate
+			continue
+		}
+
+		if len(current.Spec.Workload.Manifests) == 0 {
+			return nil, fmt.Errorf("%w: manifest %d is an estimated %d bytes (limit %d)", ErrManifestTooLarge, i, size, maxBytes)
+		}
+
+		chunks = append(chunks, current)
+		current = base.DeepCopy()
+		current.Spec.Workload.Manifests = []workv1.Manifest{manifest}
+
+		size, err = EstimateCloudEventSize(current)
+		if err != nil {
+			return nil, err
+		}
+		if size > maxBytes {
+			return nil, fmt.Errorf("%w: manifest %d is an estimated %d bytes (limit %d)", ErrManifestTooLarge, i, size, maxBytes)
+		}
+	}
+	if len(current.Spec.Workload.Manifests) > 0 {
+		chunks = append(chunks, current)
+	}
+
+	if len(chunks) > maxChunkIndex {
+		return nil, fmt.Errorf("work would need %d chunks", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		chunk.Name = ChunkName(work.Name, i)
+		chunk.Labels[LabelWorkGroupSize] = strconv.Itoa(len(chunks))
+		chunk.Labels[LabelWorkGroupIndex] = strconv.Itoa(i)
+	}
+
+	return chunks, nil
+}
@@ -0,0 +1,85 @@
+package maestro
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func testWorkWithConfigMaps(t *testing.T, count, valueSize int) *workv1.ManifestWork {
+	t.Helper()
+	work := &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: "work", Namespace: "mc01"}}
+	for i := 0; i < count; i++ {
+		raw, err := json.Marshal(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "cm-" + string(rune('a'+i)), "namespace": "default"},
+			"data":       map[string]interface{}{"value": strings.Repeat("x", valueSize)},
+		})
+		if err != nil {
+			t.Fatalf("failed to marshal manifest: %v", err)
+		}
+		work.Spec.Workload.Manifests = append(work.Spec.Workload.Manifests, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}})
+	}
+	return work
+}
+
+func TestChunkManifestWork(t *testing.T) {
+	work := testWorkWithConfigMaps(t, 5, 1500)
+
+	chunks, err := ChunkManifestWork(work, 4000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected the work to be split, got %d chunks", len(chunks))
+	}
+
+	var manifests []workv1.Manifest
+	for i, chunk := range chunks {
+		if chunk.Name != ChunkName("work", i) {
+			t.Errorf("expected chunk %d to be named %s, got %s", i, ChunkName("work", i), chunk.Name)
+		}
+		if chunk.Labels[LabelWorkGroup] != "work" {
+			t.Errorf("expected group label 'work', got %q", chunk.Labels[LabelWorkGroup])
+		}
+		if chunk.Labels[LabelWorkGroupSize] != strconv.Itoa(len(chunks)) {
+			t.Errorf("expected group size label to match chunk count, got %q", chunk.Labels[LabelWorkGroupSize])
+		}
+		size, err := EstimateCloudEventSize(chunk)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if size > 4000 {
+			t.Errorf("chunk %d is an estimated %d bytes, exceeding the limit", i, size)
+		}
+		manifests = append(manifests, chunk.Spec.Workload.Manifests...)
+	}
+
+	if len(manifests) != len(work.Spec.Workload.Manifests) {
+		t.Fatalf("expected %d manifests across chunks, got %d", len(work.Spec.Workload.Manifests), len(manifests))
+	}
+	for i := range manifests {
+		if string(manifests[i].Raw) != string(work.Spec.Workload.Manifests[i].Raw) {
+			t.Errorf("manifest %d out of order", i)
+		}
+	}
+	if work.Labels != nil {
+		t.Error("expected chunking not to modify the input work")
+	}
+}
+
+func TestChunkManifestWork_ManifestTooLarge(t *testing.T) {
+	work := testWorkWithConfigMaps(t, 2, 5000)
+
+	_, err := ChunkManifestWork(work, 4000)
+	if !errors.Is(err, ErrManifestTooLarge) {
+		t.Errorf("expected ErrManifestTooLarge, got %v", err)
+	}
+}
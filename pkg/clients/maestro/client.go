@@ -1,23 +1,23 @@
 package maestro
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
-	"io"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"os"
 	"strconv"
+	"sync/atomic"
 
 	"github.com/openshift-online/maestro/pkg/api/openapi"
 	"github.com/openshift-online/maestro/pkg/client/cloudevents/grpcsource"
 	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/tracecontext"
 	"github.com/openshift/rosa-regional-platform-api/pkg/types"
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
 )
@@ -26,6 +26,9 @@ const (
 	consumersPath       = "/api/maestro/v1/consumers"
 	resourceBundlesPath = "/api/maestro/v1/resource-bundles"
 
+	// SourceID identifies this API as the CloudEvents source for ManifestWorks
+	SourceID = "rosa-regional-platform-api"
+
 	// /api/maestro/v1/resource-bundles
 )
 
@@ -71,43 +74,67 @@ func (l *loggerAdapter) Fatal(ctx context.Context, format string, args ...interf
 	os.Exit(1)
 }
 
-// Client provides access to the Maestro API
+// Client provides access to the Maestro API. With a secondary Maestro
+// configured, calls go to whichever endpoint is active.
 type Client struct {
-	baseURL       string
-	grpcBaseURL   string
-	httpClient    *http.Client
-	logger        *slog.Logger
-	grpcOpts      *grpcoptions.GRPCOptions
-	sourceID      string
-	openapiClient *openapi.APIClient
-	workClient    workv1client.WorkV1Interface
+	endpoints  []*endpoint
+	active     atomic.Int32
+	httpClient *http.Client
+	logger     *slog.Logger
+	sourceID   string
 }
 
 // NewClient creates a new Maestro client
 func NewClient(cfg config.MaestroConfig, logger *slog.Logger) *Client {
+	c := &Client{
+		endpoints: []*endpoint{newEndpoint(EndpointPrimary, cfg.BaseURL, cfg.GRPCBaseURL, logger)},
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: upstream.Transport(upstream.Maestro, tracecontext.Transport(nil)),
+		},
+		logger:   logger,
+		sourceID: SourceID,
+	}
+	if cfg.SecondaryBaseURL != "" {
+		c.endpoints = append(c.endpoints, newEndpoint(EndpointSecondary, cfg.SecondaryBaseURL, cfg.SecondaryGRPCBaseURL, logger))
+	}
+	if cfg.ActiveEndpoint != "" {
+		if err := c.SetActiveEndpoint(cfg.ActiveEndpoint); err != nil {
+			logger.Error("failed to select the active Maestro endpoint, using the primary", "error", err)
+		}
+	}
+	return c
+}
+
+// newEndpoint creates the clients for one Maestro deployment
+func newEndpoint(name, baseURL, grpcBaseURL string, logger *slog.Logger) *endpoint {
 	// Create OpenAPI client configuration
 	openapiCfg := openapi.NewConfiguration()
 	// Parse the base URL to extract host and scheme
-	parsedURL, err := url.Parse(cfg.BaseURL)
+	parsedURL, err := url.Parse(baseURL)
 	if err == nil {
 		openapiCfg.Host = parsedURL.Host
 		openapiCfg.Scheme = parsedURL.Scheme
 	}
+	// Calls carry the request's trace context
+	openapiCfg.HTTPClient = &http.Client{Transport: tracecontext.Transport(nil)}
 	openapiClient := openapi.NewAPIClient(openapiCfg)
 
 	// Setup gRPC options
 	grpcOpts := grpcoptions.NewGRPCOptions()
 
 	// Parse the gRPC URL to extract just the host:port (without scheme)
-	grpcURL := cfg.GRPCBaseURL
-	parsedGRPC, err := url.Parse(cfg.GRPCBaseURL)
+	grpcURL := grpcBaseURL
+	parsedGRPC, err := url.Parse(grpcBaseURL)
 	if err != nil {
 		logger.Error("failed to parse gRPC URL, using original value",
-			"grpc_url", cfg.GRPCBaseURL,
+			"endpoint", name,
+			"grpc_url", grpcBaseURL,
 			"error", err)
 	} else if parsedGRPC.Host == "" {
 		logger.Warn("parsed gRPC URL has empty host, using original value",
-			"grpc_url", cfg.GRPCBaseURL)
+			"endpoint", name,
+			"grpc_url", grpcBaseURL)
 	} else {
 		// Successfully parsed and has a host - use the host:port portion
 		grpcURL = parsedGRPC.Host
@@ -127,23 +154,19 @@ func NewClient(cfg config.MaestroConfig, logger *slog.Logger) *Client {
 		adaptedLogger,
 		openapiClient,
 		grpcOpts,
-		"rosa-regional-platform-api", // Source ID
+		SourceID,
 	)
 	if err != nil {
 		// Log the error but don't fail - the client can still be used for non-gRPC operations
-		logger.Error("failed to create gRPC work client during initialization", "error", err)
+		logger.Error("failed to create gRPC work client during initialization", "endpoint", name, "error", err)
 		// workClient will be nil, and CreateManifestWork will handle this gracefully
 	}
 
-	return &Client{
-		baseURL:     cfg.BaseURL,
-		grpcBaseURL: cfg.GRPCBaseURL,
-		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
-		},
-		logger:        logger,
+	return &endpoint{
+		name:          name,
+		baseURL:       baseURL,
+		grpcBaseURL:   grpcBaseURL,
 		grpcOpts:      grpcOpts,
-		sourceID:      "rosa-regional-platform-api", // Default source ID
 		openapiClient: openapiClient,
 		workClient:    workClient,
 	}
@@ -151,15 +174,17 @@ func NewClient(cfg config.MaestroConfig, logger *slog.Logger) *Client {
 
 // CreateConsumer creates a new consumer in Maestro
 func (c *Client) CreateConsumer(ctx context.Context, req *ConsumerCreateRequest) (*Consumer, error) {
-	body, err := json.Marshal(req)
+	body, length, err := newJSONBody(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+consumersPath, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint().baseURL+consumersPath, body)
 	if err != nil {
+		_ = body.Close()
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
+	httpReq.ContentLength = length
 	httpReq.Header.Set("Content-Type", "application/json")
 
 	c.logger.Debug("creating consumer in Maestro", "name", req.Name)
@@ -170,21 +195,18 @@ func (c *Client) CreateConsumer(ctx context.Context, req *ConsumerCreateRequest)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
+	buf, err := readBody(resp.Body, resp.ContentLength)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	defer putBuffer(buf)
 
 	if resp.StatusCode != http.StatusCreated {
-		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
-			return nil, &apiErr
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, errorFromBody(resp.StatusCode, buf.Bytes())
 	}
 
 	var consumer Consumer
-	if err := json.Unmarshal(respBody, &consumer); err != nil {
+	if err := json.Unmarshal(buf.Bytes(), &consumer); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
@@ -195,7 +217,7 @@ func (c *Client) CreateConsumer(ctx context.Context, req *ConsumerCreateRequest)
 
 // ListConsumers lists consumers from Maestro with pagination
 func (c *Client) ListConsumers(ctx context.Context, page, size int) (*ConsumerList, error) {
-	u, err := url.Parse(c.baseURL + consumersPath)
+	u, err := url.Parse(c.endpoint().baseURL + consumersPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
 	}
@@ -222,21 +244,26 @@ func (c *Client) ListConsumers(ctx context.Context, page, size int) (*ConsumerLi
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
-			return nil, &apiErr
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, readError(resp)
 	}
 
+	// Large pages are decoded item by item rather than read whole first
 	var list ConsumerList
-	if err := json.Unmarshal(respBody, &list); err != nil {
+	targets := map[string]any{"kind": &list.Kind, "page": &list.Page, "size": &list.Size, "total": &list.Total}
+	err = decodeList(resp.Body, targets, func(dec *json.Decoder) error {
+		if list.Items == nil {
+			// size precedes items in Maestro's responses
+			list.Items = make([]Consumer, 0, presize(list.Size))
+		}
+		var item Consumer
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		list.Items = append(list.Items, item)
+		return nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
@@ -247,7 +274,7 @@ func (c *Client) ListConsumers(ctx context.Context, page, size int) (*ConsumerLi
 
 // GetConsumer retrieves a consumer by ID from Maestro
 func (c *Client) GetConsumer(ctx context.Context, id string) (*Consumer, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+consumersPath+"/"+url.PathEscape(id), nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint().baseURL+consumersPath+"/"+url.PathEscape(id), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -260,25 +287,22 @@ func (c *Client) GetConsumer(ctx context.Context, id string) (*Consumer, error)
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
+	buf, err := readBody(resp.Body, resp.ContentLength)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	defer putBuffer(buf)
 
 	if resp.StatusCode == http.StatusNotFound {
 		return nil, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
-			return nil, &apiErr
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, errorFromBody(resp.StatusCode, buf.Bytes())
 	}
 
 	var consumer Consumer
-	if err := json.Unmarshal(respBody, &consumer); err != nil {
+	if err := json.Unmarshal(buf.Bytes(), &consumer); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
@@ -287,9 +311,48 @@ func (c *Client) GetConsumer(ctx context.Context, id string) (*Consumer, error)
 	return &consumer, nil
 }
 
+// DeleteConsumer deletes a consumer by ID from Maestro. Maestro refuses to
+// delete a consumer that still has resource bundles.
+func (c *Client) DeleteConsumer(ctx context.Context, id string) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.endpoint().baseURL+consumersPath+"/"+url.PathEscape(id), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.logger.Debug("deleting consumer from Maestro", "id", id)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	buf, err := readBody(resp.Body, resp.ContentLength)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	defer putBuffer(buf)
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &Error{
+			Kind:   "Error",
+			Code:   "404",
+			Reason: "Consumer not found",
+		}
+	}
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return errorFromBody(resp.StatusCode, buf.Bytes())
+	}
+
+	c.logger.Debug("consumer deleted", "id", id)
+
+	return nil
+}
+
 // ListResourceBundles lists resource bundles from Maestro with pagination and optional filters
 func (c *Client) ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*ResourceBundleList, error) {
-	u, err := url.Parse(c.baseURL + resourceBundlesPath)
+	u, err := url.Parse(c.endpoint().baseURL + resourceBundlesPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse URL: %w", err)
 	}
@@ -325,21 +388,26 @@ func (c *Client) ListResourceBundles(ctx context.Context, page, size int, search
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
 	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
-			return nil, &apiErr
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, readError(resp)
 	}
 
+	// Large pages are decoded item by item rather than read whole first
 	var list ResourceBundleList
-	if err := json.Unmarshal(respBody, &list); err != nil {
+	targets := map[string]any{"kind": &list.Kind, "page": &list.Page, "size": &list.Size, "total": &list.Total}
+	err = decodeList(resp.Body, targets, func(dec *json.Decoder) error {
+		if list.Items == nil {
+			// size precedes items in Maestro's responses
+			list.Items = make([]ResourceBundle, 0, presize(list.Size))
+		}
+		var item ResourceBundle
+		if err := dec.Decode(&item); err != nil {
+			return err
+		}
+		list.Items = append(list.Items, item)
+		return nil
+	})
+	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
@@ -350,7 +418,7 @@ func (c *Client) ListResourceBundles(ctx context.Context, page, size int, search
 
 // GetResourceBundle retrieves a single resource bundle by ID from Maestro
 func (c *Client) GetResourceBundle(ctx context.Context, id string) (*ResourceBundle, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+resourceBundlesPath+"/"+url.PathEscape(id), nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint().baseURL+resourceBundlesPath+"/"+url.PathEscape(id), nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -361,21 +429,18 @@ func (c *Client) GetResourceBundle(ctx context.Context, id string) (*ResourceBun
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
+	buf, err := readBody(resp.Body, resp.ContentLength)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response: %w", err)
 	}
+	defer putBuffer(buf)
 
 	if resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
-			return nil, &apiErr
-		}
-		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return nil, errorFromBody(resp.StatusCode, buf.Bytes())
 	}
 
 	var bundle ResourceBundle
-	if err := json.Unmarshal(respBody, &bundle); err != nil {
+	if err := json.Unmarshal(buf.Bytes(), &bundle); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
@@ -384,7 +449,7 @@ func (c *Client) GetResourceBundle(ctx context.Context, id string) (*ResourceBun
 
 // DeleteResourceBundle deletes a resource bundle by ID from Maestro
 func (c *Client) DeleteResourceBundle(ctx context.Context, id string) error {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL+resourceBundlesPath+"/"+url.PathEscape(id), nil)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.endpoint().baseURL+resourceBundlesPath+"/"+url.PathEscape(id), nil)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -397,14 +462,15 @@ func (c *Client) DeleteResourceBundle(ctx context.Context, id string) error {
 	}
 	defer func() { _ = resp.Body.Close() }()
 
-	respBody, err := io.ReadAll(resp.Body)
+	buf, err := readBody(resp.Body, resp.ContentLength)
 	if err != nil {
 		return fmt.Errorf("failed to read response: %w", err)
 	}
+	defer putBuffer(buf)
 
 	if resp.StatusCode == http.StatusNotFound {
 		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil {
+		if json.Unmarshal(buf.Bytes(), &apiErr) == nil {
 			if apiErr.Kind == "" {
 				apiErr.Kind = "Error"
 			}
@@ -422,11 +488,7 @@ func (c *Client) DeleteResourceBundle(ctx context.Context, id string) error {
 	}
 
 	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
-		var apiErr Error
-		if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
-			return &apiErr
-		}
-		return fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, string(respBody))
+		return errorFromBody(resp.StatusCode, buf.Bytes())
 	}
 
 	c.logger.Debug("resource bundle deleted", "id", id)
@@ -439,12 +501,15 @@ func (c *Client) CreateManifestWork(ctx context.Context, clusterName string, man
 	c.logger.Debug("creating manifestwork via gRPC", "cluster", clusterName, "work_name", manifestWork.Name)
 
 	// Check if workClient was initialized successfully
-	if c.workClient == nil {
+	workClient := c.endpoint().workClient
+	if workClient == nil {
 		return nil, fmt.Errorf("gRPC work client not initialized")
 	}
 
 	// Create the ManifestWork using the reusable client interface
-	result, err := c.workClient.ManifestWorks(clusterName).Create(ctx, manifestWork, metav1.CreateOptions{})
+	done := upstream.Track(ctx, upstream.Maestro)
+	result, err := workClient.ManifestWorks(clusterName).Create(tracecontext.OutgoingGRPC(ctx), manifestWork, metav1.CreateOptions{})
+	done()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create manifestwork: %w", err)
 	}
@@ -458,11 +523,14 @@ func (c *Client) CreateManifestWork(ctx context.Context, clusterName string, man
 // This follows the ARO-HCP pattern of using the gRPC client's Get method which
 // resolves by metadata.name (the name set during Create).
 func (c *Client) GetManifestWork(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
-	if c.workClient == nil {
+	workClient := c.endpoint().workClient
+	if workClient == nil {
 		return nil, fmt.Errorf("gRPC work client not initialized")
 	}
 
-	result, err := c.workClient.ManifestWorks(clusterName).Get(ctx, name, metav1.GetOptions{})
+	done := upstream.Track(ctx, upstream.Maestro)
+	result, err := workClient.ManifestWorks(clusterName).Get(tracecontext.OutgoingGRPC(ctx), name, metav1.GetOptions{})
+	done()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get manifestwork: %w", err)
 	}
@@ -472,11 +540,14 @@ func (c *Client) GetManifestWork(ctx context.Context, clusterName string, name s
 
 // DeleteManifestWork deletes a ManifestWork by name from Maestro via gRPC.
 func (c *Client) DeleteManifestWork(ctx context.Context, clusterName string, name string) error {
-	if c.workClient == nil {
+	workClient := c.endpoint().workClient
+	if workClient == nil {
 		return fmt.Errorf("gRPC work client not initialized")
 	}
 
-	err := c.workClient.ManifestWorks(clusterName).Delete(ctx, name, metav1.DeleteOptions{})
+	done := upstream.Track(ctx, upstream.Maestro)
+	err := workClient.ManifestWorks(clusterName).Delete(tracecontext.OutgoingGRPC(ctx), name, metav1.DeleteOptions{})
+	done()
 	if err != nil {
 		return fmt.Errorf("failed to delete manifestwork: %w", err)
 	}
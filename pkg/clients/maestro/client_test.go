@@ -27,8 +27,8 @@ func TestNewClient(t *testing.T) {
 		t.Fatal("expected non-nil client")
 	}
 
-	if client.baseURL != cfg.BaseURL {
-		t.Errorf("expected baseURL=%s, got %s", cfg.BaseURL, client.baseURL)
+	if client.endpoint().baseURL != cfg.BaseURL {
+		t.Errorf("expected baseURL=%s, got %s", cfg.BaseURL, client.endpoint().baseURL)
 	}
 
 	if client.httpClient == nil {
@@ -452,6 +452,54 @@ func TestClient_GetConsumer_MaestroError(t *testing.T) {
 	}
 }
 
+func TestClient_DeleteConsumer(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		wantCode string
+	}{
+		{name: "deleted", status: http.StatusNoContent},
+		{name: "not found", status: http.StatusNotFound, wantCode: "404"},
+		{name: "has resource bundles", status: http.StatusConflict, wantCode: "conflict"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodDelete {
+					t.Errorf("expected DELETE request, got %s", r.Method)
+				}
+				if r.URL.Path != "/api/maestro/v1/consumers/consumer-123" {
+					t.Errorf("expected path /api/maestro/v1/consumers/consumer-123, got %s", r.URL.Path)
+				}
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusConflict {
+					_ = json.NewEncoder(w).Encode(&Error{Kind: "Error", Code: "conflict", Reason: "Consumer has resource bundles"})
+				}
+			}))
+			defer server.Close()
+
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			client := NewClient(config.MaestroConfig{BaseURL: server.URL, Timeout: 10 * time.Second}, logger)
+
+			err := client.DeleteConsumer(context.Background(), "consumer-123")
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				return
+			}
+			maestroErr, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("expected *Error, got %T", err)
+			}
+			if maestroErr.Code != tt.wantCode {
+				t.Errorf("expected code=%s, got %s", tt.wantCode, maestroErr.Code)
+			}
+		})
+	}
+}
+
 func TestClient_ListResourceBundles_Success(t *testing.T) {
 	now := time.Now()
 	expectedList := &ResourceBundleList{
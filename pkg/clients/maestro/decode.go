@@ -0,0 +1,99 @@
+package maestro
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// maxErrorBodyBytes bounds how much of a failed response is read for its error
+const maxErrorBodyBytes = 64 * 1024
+
+// decodeList decodes a paginated list response one item at a time, so the
+// raw body is never held in memory alongside the decoded items. Top-level
+// fields named in fields are decoded into their targets, each element of
+// items is passed to item, and other fields are skipped.
+func decodeList(r io.Reader, fields map[string]any, item func(*json.Decoder) error) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		if key == "items" {
+			if err := decodeItems(dec, item); err != nil {
+				return fmt.Errorf("items: %w", err)
+			}
+			continue
+		}
+		target, ok := fields[key]
+		if !ok {
+			var skip json.RawMessage
+			target = &skip
+		}
+		if err := dec.Decode(target); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return expectDelim(dec, '}')
+}
+
+// decodeItems decodes a JSON array, or null, element by element
+func decodeItems(dec *json.Decoder, item func(*json.Decoder) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		return nil
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected array, got %v", tok)
+	}
+	for dec.More() {
+		if err := item(dec); err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
+}
+
+// readError turns a non-200 response into a Maestro error, reading at most
+// maxErrorBodyBytes of its body
+func readError(resp *http.Response) error {
+	buf, err := readBody(io.LimitReader(resp.Body, maxErrorBodyBytes), min(resp.ContentLength, maxErrorBodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	defer putBuffer(buf)
+	return errorFromBody(resp.StatusCode, buf.Bytes())
+}
+
+// errorFromBody returns the Maestro error in a failed response's body, or a
+// generic error carrying the body when it is not one
+func errorFromBody(status int, respBody []byte) error {
+	var apiErr Error
+	if json.Unmarshal(respBody, &apiErr) == nil && apiErr.Reason != "" {
+		return &apiErr
+	}
+	return fmt.Errorf("unexpected status code %d: %s", status, string(respBody))
+}
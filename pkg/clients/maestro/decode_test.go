@@ -0,0 +1,148 @@
+package maestro
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestDecodeList(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		expectIDs   []string
+		expectTotal int
+		expectErr   bool
+	}{
+		{
+			name:        "items and fields",
+			body:        `{"kind":"ConsumerList","page":2,"size":2,"total":12,"items":[{"id":"a"},{"id":"b"}]}`,
+			expectIDs:   []string{"a", "b"},
+			expectTotal: 12,
+		},
+		{
+			name:        "unknown fields are skipped",
+			body:        `{"total":1,"links":{"next":"/x"},"items":[{"id":"a","extra":[1,2]}],"more":true}`,
+			expectIDs:   []string{"a"},
+			expectTotal: 1,
+		},
+		{
+			name: "null items",
+			body: `{"kind":"ConsumerList","items":null}`,
+		},
+		{
+			name:      "items not an array",
+			body:      `{"items":{"id":"a"}}`,
+			expectErr: true,
+		},
+		{
+			name:      "truncated body",
+			body:      `{"total":2,"items":[{"id":"a"},`,
+			expectErr: true,
+		},
+		{
+			name:      "not an object",
+			body:      `[{"id":"a"}]`,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list ConsumerList
+			targets := map[string]any{"kind": &list.Kind, "page": &list.Page, "size": &list.Size, "total": &list.Total}
+			err := decodeList(strings.NewReader(tt.body), targets, func(dec *json.Decoder) error {
+				var c Consumer
+				if err := dec.Decode(&c); err != nil {
+					return err
+				}
+				list.Items = append(list.Items, c)
+				return nil
+			})
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if list.Total != tt.expectTotal {
+				t.Errorf("expected total %d, got %d", tt.expectTotal, list.Total)
+			}
+			if len(list.Items) != len(tt.expectIDs) {
+				t.Fatalf("expected %d items, got %d", len(tt.expectIDs), len(list.Items))
+			}
+			for i, id := range tt.expectIDs {
+				if list.Items[i].ID != id {
+					t.Errorf("expected item %d to be %q, got %q", i, id, list.Items[i].ID)
+				}
+			}
+		})
+	}
+}
+
+func largeResourceBundleListBody(b *testing.B, n int) []byte {
+	b.Helper()
+	list := ResourceBundleList{Kind: "ResourceBundleList", Page: 1, Size: n, Total: n}
+	for i := range n {
+		list.Items = append(list.Items, ResourceBundle{
+			ID:        fmt.Sprintf("rb-%05d", i),
+			Name:      fmt.Sprintf("bundle-%05d", i),
+			Metadata:  map[string]interface{}{"name": fmt.Sprintf("bundle-%05d", i)},
+			Manifests: []interface{}{map[string]interface{}{"kind": "ConfigMap", "data": strings.Repeat("x", 256)}},
+		})
+	}
+	body, err := json.Marshal(list)
+	if err != nil {
+		b.Fatalf("failed to marshal list: %v", err)
+	}
+	return body
+}
+
+// Reading a 10k-item page whole and unmarshalling it, as the client used to,
+// against decoding it item by item. The difference in B/op is the raw body
+// the streaming decoder never holds.
+func BenchmarkReadAllUnmarshal_10kItems(b *testing.B) {
+	body := largeResourceBundleListBody(b, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		data, err := io.ReadAll(bytes.NewReader(body))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var list ResourceBundleList
+		if err := json.Unmarshal(data, &list); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecodeList_10kItems(b *testing.B) {
+	body := largeResourceBundleListBody(b, 10000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		var list ResourceBundleList
+		targets := map[string]any{"kind": &list.Kind, "page": &list.Page, "size": &list.Size, "total": &list.Total}
+		err := decodeList(bytes.NewReader(body), targets, func(dec *json.Decoder) error {
+			if list.Items == nil {
+				list.Items = make([]ResourceBundle, 0, presize(list.Size))
+			}
+			var item ResourceBundle
+			if err := dec.Decode(&item); err != nil {
+				return err
+			}
+			list.Items = append(list.Items, item)
+			return nil
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
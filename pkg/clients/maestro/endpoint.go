@@ -0,0 +1,83 @@
+package maestro
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/openshift-online/maestro/pkg/api/openapi"
+	workv1client "open-cluster-management.io/api/client/work/clientset/versioned/typed/work/v1"
+	grpcoptions "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/options/grpc"
+)
+
+// Endpoint names
+const (
+	EndpointPrimary   = "primary"
+	EndpointSecondary = "secondary"
+)
+
+// ErrUnknownEndpoint is returned when switching to an endpoint that is not
+// configured
+var ErrUnknownEndpoint = errors.New("unknown Maestro endpoint")
+
+// endpoint is one Maestro deployment the client can call
+type endpoint struct {
+	name          string
+	baseURL       string
+	grpcBaseURL   string
+	grpcOpts      *grpcoptions.GRPCOptions
+	openapiClient *openapi.APIClient
+	workClient    workv1client.WorkV1Interface
+}
+
+// endpoint returns the endpoint calls go to
+func (c *Client) endpoint() *endpoint {
+	return c.endpoints[c.active.Load()]
+}
+
+// Endpoints returns the names of the configured endpoints, the primary first
+func (c *Client) Endpoints() []string {
+	names := make([]string, 0, len(c.endpoints))
+	for _, e := range c.endpoints {
+		names = append(names, e.name)
+	}
+	return names
+}
+
+// ActiveEndpoint returns the name of the endpoint calls go to
+func (c *Client) ActiveEndpoint() string {
+	return c.endpoint().name
+}
+
+// SetActiveEndpoint sends calls made from now on to the named endpoint. Calls
+// in flight finish against the endpoint they started on.
+func (c *Client) SetActiveEndpoint(name string) error {
+	for i, e := range c.endpoints {
+		if e.name == name {
+			c.active.Store(int32(i))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w %q", ErrUnknownEndpoint, name)
+}
+
+// ProbeEndpoint checks that the named endpoint answers and serves the API
+// version this client calls, whether or not it is active
+func (c *Client) ProbeEndpoint(ctx context.Context, name string) error {
+	for _, e := range c.endpoints {
+		if e.name != name {
+			continue
+		}
+		versions, err := c.apiVersions(ctx, e)
+		if err != nil {
+			return err
+		}
+		for _, v := range versions {
+			if v == APIVersion {
+				return nil
+			}
+		}
+		return fmt.Errorf("maestro does not serve API version %s (serves %v)", APIVersion, versions)
+	}
+	return fmt.Errorf("%w %q", ErrUnknownEndpoint, name)
+}
@@ -0,0 +1,112 @@
+package maestro
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+)
+
+// newEndpointServer answers every consumer request with a consumer named
+// after the endpoint, and the API metadata with versions
+func newEndpointServer(t *testing.T, name string, versions string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/maestro" {
+			_, _ = w.Write([]byte(`{"versions":[` + versions + `]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"c1","name":"` + name + `"}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_SetActiveEndpoint(t *testing.T) {
+	primary := newEndpointServer(t, "primary", `{"id":"v1"}`)
+	secondary := newEndpointServer(t, "secondary", `{"id":"v1"}`)
+
+	client := NewClient(config.MaestroConfig{
+		BaseURL:          primary.URL,
+		SecondaryBaseURL: secondary.URL,
+		Timeout:          10 * time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if got := client.Endpoints(); !slices.Equal(got, []string{EndpointPrimary, EndpointSecondary}) {
+		t.Fatalf("expected both endpoints, got %v", got)
+	}
+	if client.ActiveEndpoint() != EndpointPrimary {
+		t.Fatalf("expected the primary to be active, got %s", client.ActiveEndpoint())
+	}
+
+	consumer, err := client.GetConsumer(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if consumer.Name != "primary" {
+		t.Errorf("expected the call to reach the primary, reached %s", consumer.Name)
+	}
+
+	if err := client.SetActiveEndpoint(EndpointSecondary); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	consumer, err = client.GetConsumer(context.Background(), "c1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if consumer.Name != "secondary" {
+		t.Errorf("expected the call to reach the secondary, reached %s", consumer.Name)
+	}
+
+	if err := client.SetActiveEndpoint("tertiary"); !errors.Is(err, ErrUnknownEndpoint) {
+		t.Errorf("expected ErrUnknownEndpoint, got %v", err)
+	}
+}
+
+func TestNewClient_ActiveEndpoint(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	client := NewClient(config.MaestroConfig{
+		BaseURL:          "http://primary:8000",
+		SecondaryBaseURL: "http://secondary:8000",
+		ActiveEndpoint:   EndpointSecondary,
+	}, logger)
+	if client.ActiveEndpoint() != EndpointSecondary {
+		t.Errorf("expected the secondary to be active, got %s", client.ActiveEndpoint())
+	}
+
+	// Without a secondary the primary is used
+	client = NewClient(config.MaestroConfig{BaseURL: "http://primary:8000", ActiveEndpoint: EndpointSecondary}, logger)
+	if client.ActiveEndpoint() != EndpointPrimary {
+		t.Errorf("expected the primary to be active, got %s", client.ActiveEndpoint())
+	}
+}
+
+func TestClient_ProbeEndpoint(t *testing.T) {
+	primary := newEndpointServer(t, "primary", `{"id":"v1"}`)
+	secondary := newEndpointServer(t, "secondary", `{"id":"v2"}`)
+
+	client := NewClient(config.MaestroConfig{
+		BaseURL:          primary.URL,
+		SecondaryBaseURL: secondary.URL,
+		Timeout:          10 * time.Second,
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := client.ProbeEndpoint(context.Background(), EndpointPrimary); err != nil {
+		t.Errorf("expected the primary to pass, got %v", err)
+	}
+	// The inactive endpoint is probed too, and must serve v1
+	if err := client.ProbeEndpoint(context.Background(), EndpointSecondary); err == nil {
+		t.Error("expected the secondary serving only v2 to fail")
+	}
+	if err := client.ProbeEndpoint(context.Background(), "tertiary"); !errors.Is(err, ErrUnknownEndpoint) {
+		t.Errorf("expected ErrUnknownEndpoint, got %v", err)
+	}
+}
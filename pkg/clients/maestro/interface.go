@@ -11,6 +11,7 @@ type ClientInterface interface {
 	CreateConsumer(ctx context.Context, req *ConsumerCreateRequest) (*Consumer, error)
 	ListConsumers(ctx context.Context, page, size int) (*ConsumerList, error)
 	GetConsumer(ctx context.Context, id string) (*Consumer, error)
+	DeleteConsumer(ctx context.Context, id string) error
 	ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*ResourceBundleList, error)
 	GetResourceBundle(ctx context.Context, id string) (*ResourceBundle, error)
 	DeleteResourceBundle(ctx context.Context, id string) error
@@ -0,0 +1,355 @@
+package maestro
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxSearchLength bounds the search expressions accepted from callers
+const maxSearchLength = 2048
+
+// searchFields are the resource bundle columns a search expression may filter on
+var searchFields = map[string]bool{
+	"id":            true,
+	"name":          true,
+	"consumer_name": true,
+	"version":       true,
+	"created_at":    true,
+	"updated_at":    true,
+}
+
+// OptionalSearchFields are the resource bundle columns only newer Maestro
+// releases accept in search expressions, each with an expression that
+// searches on it, used to probe whether a deployment accepts it
+var OptionalSearchFields = map[string]string{
+	"deleted_at": "deleted_at > '1970-01-01T00:00:00Z'",
+}
+
+// searchOperators are the symbolic comparison operators a search expression
+// may use, besides like, in and not in
+var searchOperators = map[string]bool{
+	"=":  true,
+	"!=": true,
+	"<>": true,
+	"<":  true,
+	"<=": true,
+	">":  true,
+	">=": true,
+}
+
+// QuoteSearchValue quotes v as a string literal of a Maestro search
+// expression, doubling any single quotes it contains
+func QuoteSearchValue(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", "''") + "'"
+}
+
+// SearchEquals returns the search expression matching field equal to value.
+// The field is not checked against the allowed fields.
+func SearchEquals(field, value string) string {
+	return field + " = " + QuoteSearchValue(value)
+}
+
+// AndSearch combines search expressions so that all of them must match,
+// skipping empty ones
+func AndSearch(exprs ...string) string {
+	var parts []string
+	for _, e := range exprs {
+		if e != "" {
+			parts = append(parts, e)
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	for i, p := range parts {
+		parts[i] = "(" + p + ")"
+	}
+	return strings.Join(parts, " and ")
+}
+
+// ValidateSearch parses a caller supplied search expression, checks that it
+// only compares allowed fields with allowed operators, and returns it
+// rebuilt with every value quoted, so nothing in it reaches Maestro as
+// anything other than a literal. An empty expression is returned as is.
+func ValidateSearch(expr string) (string, error) {
+	return ValidateSearchWith(expr, nil)
+}
+
+// ValidateSearchWith validates a search expression like ValidateSearch, also
+// allowing the optional fields for which supported returns true. A nil
+// supported allows none of them.
+func ValidateSearchWith(expr string, supported func(field string) bool) (string, error) {
+	if strings.TrimSpace(expr) == "" {
+		return "", nil
+	}
+	if len(expr) > maxSearchLength {
+		return "", fmt.Errorf("search expression is longer than %d characters", maxSearchLength)
+	}
+	tokens, err := lexSearch(expr)
+	if err != nil {
+		return "", err
+	}
+	p := &searchParser{tokens: tokens, supported: supported}
+	out, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return "", fmt.Errorf("unexpected %q at position %d of search expression", tok.text, tok.pos)
+	}
+	return out, nil
+}
+
+type searchTokenKind int
+
+const (
+	tokEOF searchTokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokOperator
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type searchToken struct {
+	kind searchTokenKind
+	text string
+	pos  int
+}
+
+// lexSearch splits a search expression into tokens. Keywords are returned
+// as lower case identifiers and string literals unquoted.
+func lexSearch(expr string) ([]searchToken, error) {
+	var tokens []searchToken
+	i := 0
+	for i < len(expr) {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, searchToken{kind: tokLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, searchToken{kind: tokRParen, text: ")", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, searchToken{kind: tokComma, text: ",", pos: i})
+			i++
+		case c == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(expr) {
+					return nil, fmt.Errorf("unterminated string at position %d of search expression", start)
+				}
+				if expr[i] == '\'' {
+					if i+1 < len(expr) && expr[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, searchToken{kind: tokString, text: sb.String(), pos: start})
+		case c == '=' || c == '!' || c == '<' || c == '>':
+			start := i
+			i++
+			if i < len(expr) && (expr[i] == '=' || (c == '<' && expr[i] == '>')) {
+				i++
+			}
+			op := expr[start:i]
+			if !searchOperators[op] {
+				return nil, fmt.Errorf("unsupported operator %q at position %d of search expression", op, start)
+			}
+			tokens = append(tokens, searchToken{kind: tokOperator, text: op, pos: start})
+		case isSearchDigit(c) || (c == '-' && i+1 < len(expr) && isSearchDigit(expr[i+1])):
+			start := i
+			i++
+			for i < len(expr) && (isSearchDigit(expr[i]) || expr[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, searchToken{kind: tokNumber, text: expr[start:i], pos: start})
+		case isSearchIdentStart(c):
+			start := i
+			for i < len(expr) && (isSearchIdentStart(expr[i]) || isSearchDigit(expr[i])) {
+				i++
+			}
+			tokens = append(tokens, searchToken{kind: tokIdent, text: strings.ToLower(expr[start:i]), pos: start})
+		default:
+			return nil, fmt.Errorf("unexpected character %q at position %d of search expression", c, i)
+		}
+	}
+	return append(tokens, searchToken{kind: tokEOF, pos: len(expr)}), nil
+}
+
+func isSearchDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isSearchIdentStart(c byte) bool {
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// searchParser is a recursive descent parser over
+//
+//	or         = and { "or" and }
+//	and        = not { "and" not }
+//	not        = "not" not | "(" or ")" | comparison
+//	comparison = field operator value | field [ "not" ] "in" "(" value { "," value } ")"
+//
+// that renders what it parses back into a search expression.
+type searchParser struct {
+	tokens    []searchToken
+	pos       int
+	supported func(field string) bool
+}
+
+func (p *searchParser) peek() searchToken {
+	return p.tokens[p.pos]
+}
+
+func (p *searchParser) next() searchToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *searchParser) isKeyword(kw string) bool {
+	tok := p.peek()
+	return tok.kind == tokIdent && tok.text == kw
+}
+
+func (p *searchParser) parseOr() (string, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return "", err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		left = left + " or " + right
+	}
+	return left, nil
+}
+
+func (p *searchParser) parseAnd() (string, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return "", err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return "", err
+		}
+		left = left + " and " + right
+	}
+	return left, nil
+}
+
+func (p *searchParser) parseNot() (string, error) {
+	if p.isKeyword("not") {
+		p.next()
+		inner, err := p.parseNot()
+		if err != nil {
+			return "", err
+		}
+		return "not " + inner, nil
+	}
+	if p.peek().kind == tokLParen {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if tok := p.next(); tok.kind != tokRParen {
+			return "", fmt.Errorf("expected ')' at position %d of search expression", tok.pos)
+		}
+		return "(" + inner + ")", nil
+	}
+	return p.parseComparison()
+}
+
+func (p *searchParser) parseComparison() (string, error) {
+	field := p.next()
+	if field.kind != tokIdent {
+		return "", fmt.Errorf("expected a field name at position %d of search expression", field.pos)
+	}
+	if _, optional := OptionalSearchFields[field.text]; optional {
+		if p.supported == nil || !p.supported(field.text) {
+			return "", fmt.Errorf("field %q cannot be searched on by this Maestro deployment", field.text)
+		}
+	} else if !searchFields[field.text] {
+		return "", fmt.Errorf("field %q cannot be searched on", field.text)
+	}
+
+	op := p.next()
+	switch {
+	case op.kind == tokOperator:
+	case op.kind == tokIdent && op.text == "like":
+	case op.kind == tokIdent && op.text == "in":
+		return p.parseIn(field.text, "in")
+	case op.kind == tokIdent && op.text == "not" && p.isKeyword("in"):
+		p.next()
+		return p.parseIn(field.text, "not in")
+	default:
+		return "", fmt.Errorf("expected an operator after %q at position %d of search expression", field.text, op.pos)
+	}
+
+	value, err := p.parseValue()
+	if err != nil {
+		return "", err
+	}
+	return field.text + " " + op.text + " " + value, nil
+}
+
+func (p *searchParser) parseIn(field, op string) (string, error) {
+	if tok := p.next(); tok.kind != tokLParen {
+		return "", fmt.Errorf("expected '(' at position %d of search expression", tok.pos)
+	}
+	var values []string
+	for {
+		value, err := p.parseValue()
+		if err != nil {
+			return "", err
+		}
+		values = append(values, value)
+		tok := p.next()
+		if tok.kind == tokRParen {
+			break
+		}
+		if tok.kind != tokComma {
+			return "", fmt.Errorf("expected ',' or ')' at position %d of search expression", tok.pos)
+		}
+	}
+	return field + " " + op + " (" + strings.Join(values, ", ") + ")", nil
+}
+
+func (p *searchParser) parseValue() (string, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokString:
+		return QuoteSearchValue(tok.text), nil
+	case tokNumber:
+		if strings.Count(tok.text, ".") > 1 || strings.HasSuffix(tok.text, ".") {
+			return "", fmt.Errorf("invalid number %q at position %d of search expression", tok.text, tok.pos)
+		}
+		return tok.text, nil
+	default:
+		return "", fmt.Errorf("expected a quoted value or number at position %d of search expression", tok.pos)
+	}
+}
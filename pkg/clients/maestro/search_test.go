@@ -0,0 +1,140 @@
+package maestro
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateSearch(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want string
+	}{
+		{name: "empty", expr: "", want: ""},
+		{name: "equality", expr: "name='test'", want: "name = 'test'"},
+		{name: "keywords and fields are case insensitive", expr: "Name = 'a' AND Version >= 2", want: "name = 'a' and version >= 2"},
+		{name: "embedded quote", expr: "name = 'o''brien'", want: "name = 'o''brien'"},
+		{name: "like", expr: "name like 'web-%'", want: "name like 'web-%'"},
+		{name: "in", expr: "consumer_name in ('mc01','mc02')", want: "consumer_name in ('mc01', 'mc02')"},
+		{name: "not in", expr: "consumer_name not in ('mc01')", want: "consumer_name not in ('mc01')"},
+		{name: "grouping", expr: "not (name = 'a' or name = 'b') and created_at > '2026-01-01'", want: "not (name = 'a' or name = 'b') and created_at > '2026-01-01'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateSearch(tt.expr)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateSearch_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+	}{
+		{name: "unknown field", expr: "payload = 'x'"},
+		{name: "unquoted value", expr: "name = test"},
+		{name: "unterminated string", expr: "name = 'test"},
+		{name: "unsupported operator", expr: "name == 'test'"},
+		{name: "statement separator", expr: "name = 'a'; delete"},
+		{name: "unbalanced parenthesis", expr: "(name = 'a'"},
+		{name: "dangling and", expr: "name = 'a' and"},
+		{name: "trailing tokens", expr: "name = 'a' name = 'b'"},
+		{name: "field compared to field", expr: "name = consumer_name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ValidateSearch(tt.expr); err == nil {
+				t.Errorf("expected an error, got %q", got)
+			}
+		})
+	}
+}
+
+func TestValidateSearchWith_OptionalFields(t *testing.T) {
+	expr := "deleted_at > '2026-01-01'"
+	if _, err := ValidateSearch(expr); err == nil || !strings.Contains(err.Error(), "this Maestro deployment") {
+		t.Errorf("expected optional field to be rejected without support, got %v", err)
+	}
+	unsupported := func(string) bool { return false }
+	if _, err := ValidateSearchWith(expr, unsupported); err == nil {
+		t.Error("expected optional field to be rejected when unsupported")
+	}
+	supported := func(field string) bool { return field == "deleted_at" }
+	if got, err := ValidateSearchWith(expr, supported); err != nil || got != expr {
+		t.Errorf("expected %q to be accepted once supported, got %q, %v", expr, got, err)
+	}
+	if _, err := ValidateSearchWith("labels = 'a'", supported); err == nil {
+		t.Error("expected unknown fields to be rejected")
+	}
+}
+
+func TestAndSearch(t *testing.T) {
+	if got := AndSearch("", SearchEquals("name", "it's")); got != "name = 'it''s'" {
+		t.Errorf("unexpected single expression: %q", got)
+	}
+	if got := AndSearch("name = 'a'", "", "version > 1"); got != "(name = 'a') and (version > 1)" {
+		t.Errorf("unexpected combined expression: %q", got)
+	}
+	if got := AndSearch(); got != "" {
+		t.Errorf("expected empty expression, got %q", got)
+	}
+}
+
+// FuzzValidateSearch checks that whatever ValidateSearch accepts comes back
+// in a canonical form it accepts unchanged, and that nothing but allowed
+// fields and keywords appears outside its string literals
+func FuzzValidateSearch(f *testing.F) {
+	for _, seed := range []string{
+		"name='test'",
+		"Name = 'a' AND Version >= 2",
+		"name = 'o''brien'",
+		"consumer_name not in ('mc01','mc02')",
+		"not (name = 'a' or name = 'b') and created_at > '2026-01-01'",
+		"name = 'a'; delete",
+		"name = 'a' or 1=1 --",
+		"version = -1.5",
+		"((((name = 'a'))))",
+	} {
+		f.Add(seed)
+	}
+
+	keywords := map[string]bool{"and": true, "or": true, "not": true, "in": true, "like": true}
+	f.Fuzz(func(t *testing.T, expr string) {
+		out, err := ValidateSearch(expr)
+		if err != nil || out == "" {
+			return
+		}
+
+		tokens, err := lexSearch(out)
+		if err != nil {
+			t.Fatalf("ValidateSearch(%q) returned %q, which does not lex: %v", expr, out, err)
+		}
+		for _, tok := range tokens {
+			if tok.kind == tokIdent && !searchFields[tok.text] && !keywords[tok.text] {
+				t.Fatalf("ValidateSearch(%q) returned %q, which contains identifier %q", expr, out, tok.text)
+			}
+		}
+
+		// Rebuilding adds spaces, so an expression near the limit may grow
+		// past it
+		if len(out) > maxSearchLength {
+			return
+		}
+		again, err := ValidateSearch(out)
+		if err != nil {
+			t.Fatalf("ValidateSearch(%q) returned %q, which it rejects: %v", expr, out, err)
+		}
+		if again != out {
+			t.Fatalf("ValidateSearch is not idempotent: %q -> %q -> %q", expr, out, again)
+		}
+	})
+}
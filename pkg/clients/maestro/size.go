@@ -0,0 +1,45 @@
+package maestro
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/payload"
+	"open-cluster-management.io/sdk-go/pkg/cloudevents/clients/work/source/codec"
+	cetypes "open-cluster-management.io/sdk-go/pkg/cloudevents/generic/types"
+)
+
+// placeholderUID stands in for the UID Maestro assigns on creation so the
+// estimate accounts for the resourceid extension
+const placeholderUID = types.UID("00000000-0000-0000-0000-000000000000")
+
+// EstimateCloudEventSize returns the size in bytes of the structured-mode
+// CloudEvent that carries the ManifestWork from Maestro to the agent. It uses
+// the same ManifestBundle codec as the gRPC source client, so it tracks what
+// is actually published to the MQTT broker.
+func EstimateCloudEventSize(work *workv1.ManifestWork) (int, error) {
+	w := work.DeepCopy()
+	if w.UID == "" {
+		w.UID = placeholderUID
+	}
+
+	eventType := cetypes.CloudEventsType{
+		CloudEventsDataType: payload.ManifestBundleEventDataType,
+		SubResource:         cetypes.SubResourceSpec,
+		Action:              cetypes.CreateRequestAction,
+	}
+
+	evt, err := codec.NewManifestBundleCodec().Encode(SourceID, eventType, w)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode manifestwork as cloudevent: %w", err)
+	}
+
+	b, err := json.Marshal(evt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal cloudevent: %w", err)
+	}
+
+	return len(b), nil
+}
@@ -0,0 +1,57 @@
+package maestro
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func testWorkWithConfigMap(t *testing.T, value string) *workv1.ManifestWork {
+	t.Helper()
+	raw, err := json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm", "namespace": "default"},
+		"data":       map[string]interface{}{"value": value},
+	})
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{Name: "work", Namespace: "mc01"},
+		Spec: workv1.ManifestWorkSpec{
+			Workload: workv1.ManifestsTemplate{
+				Manifests: []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: raw}}},
+			},
+		},
+	}
+}
+
+func TestEstimateCloudEventSize(t *testing.T) {
+	small := testWorkWithConfigMap(t, "x")
+	large := testWorkWithConfigMap(t, strings.Repeat("x", 10000))
+
+	smallSize, err := EstimateCloudEventSize(small)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	largeSize, err := EstimateCloudEventSize(large)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rawSize := len(small.Spec.Workload.Manifests[0].Raw)
+	if smallSize <= rawSize {
+		t.Errorf("expected estimate %d to include envelope overhead beyond manifest size %d", smallSize, rawSize)
+	}
+	if largeSize-smallSize < 9000 {
+		t.Errorf("expected estimate to grow with payload, got small=%d large=%d", smallSize, largeSize)
+	}
+	if small.UID != "" {
+		t.Error("expected estimate not to modify the input work")
+	}
+}
@@ -0,0 +1,94 @@
+package maestro
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+const (
+	apiPath = "/api/maestro"
+
+	// APIVersion is the Maestro API version this client calls
+	APIVersion = "v1"
+)
+
+// apiMetadata is Maestro's description of the API versions it serves
+type apiMetadata struct {
+	Versions []struct {
+		ID string `json:"id"`
+	} `json:"versions"`
+}
+
+// GetAPIVersions returns the API versions Maestro reports serving
+func (c *Client) GetAPIVersions(ctx context.Context) ([]string, error) {
+	return c.apiVersions(ctx, c.endpoint())
+}
+
+func (c *Client) apiVersions(ctx context.Context, e *endpoint) ([]string, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, e.baseURL+apiPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, readError(resp)
+	}
+
+	var meta apiMetadata
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxErrorBodyBytes)).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	versions := make([]string, 0, len(meta.Versions))
+	for _, v := range meta.Versions {
+		versions = append(versions, v.ID)
+	}
+	return versions, nil
+}
+
+// SupportsSearchField reports whether Maestro accepts field in resource bundle
+// search expressions, by searching on it with the field's probe expression
+// and a page size of one. Maestro answers 400 for fields it does not know.
+func (c *Client) SupportsSearchField(ctx context.Context, field string) (bool, error) {
+	probe, ok := OptionalSearchFields[field]
+	if !ok {
+		return searchFields[field], nil
+	}
+
+	u, err := url.Parse(c.endpoint().baseURL + resourceBundlesPath)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse URL: %w", err)
+	}
+	u.RawQuery = url.Values{"page": {"1"}, "size": {"1"}, "fields": {"id"}, "search": {probe}}.Encode()
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return false, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return true, nil
+	case http.StatusBadRequest:
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return false, nil
+	default:
+		return false, readError(resp)
+	}
+}
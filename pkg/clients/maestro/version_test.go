@@ -0,0 +1,67 @@
+package maestro
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+)
+
+func TestClient_GetAPIVersions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/maestro" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"kind":"API","id":"maestro","href":"/api/maestro","versions":[{"kind":"APIVersion","id":"v1","href":"/api/maestro/v1"}]}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(config.MaestroConfig{BaseURL: server.URL, Timeout: 10 * time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	versions, err := client.GetAPIVersions(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(versions, []string{"v1"}) {
+		t.Errorf("expected [v1], got %v", versions)
+	}
+}
+
+func TestClient_SupportsSearchField(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    bool
+		wantErr bool
+	}{
+		{name: "accepted", status: http.StatusOK, want: true},
+		{name: "unknown field", status: http.StatusBadRequest, want: false},
+		{name: "maestro failing", status: http.StatusInternalServerError, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("search"); got != OptionalSearchFields["deleted_at"] {
+					t.Errorf("expected the probe expression, got %q", got)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(`{"kind":"ResourceBundleList","items":[]}`))
+			}))
+			defer server.Close()
+
+			client := NewClient(config.MaestroConfig{BaseURL: server.URL, Timeout: 10 * time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+			got, err := client.SupportsSearchField(context.Background(), "deleted_at")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+// Package clock is how services and stores tell the time. They take a Clock
+// instead of calling time.Now, so tests can fix the time they stamp, and
+// format the timestamps the API returns with Format, so every one of them
+// is RFC3339 in UTC.
+package clock
+
+import (
+	"fmt"
+	"time"
+)
+
+// Clock returns the current time. time.Now is the real clock; a nil Clock
+// given to a constructor or WithClock means time.Now.
+type Clock func() time.Time
+
+// Or returns c, or time.Now when c is nil
+func Or(c Clock) Clock {
+	if c == nil {
+		return time.Now
+	}
+	return c
+}
+
+// Fixed returns a clock that always reads t
+func Fixed(t time.Time) Clock {
+	return func() time.Time { return t }
+}
+
+// Format returns t as an API timestamp: RFC3339 in UTC, to the second, such
+// as 2026-10-15T13:00:00Z
+func Format(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Stamp returns t as the API's time.Time fields hold it: in UTC and to the
+// second, so it encodes to JSON as Format would write it
+func Stamp(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Second)
+}
+
+// Parse reads an RFC3339 timestamp, with any offset and fractional seconds,
+// and returns it in UTC. Format(Parse(s)) is s normalized to an API
+// timestamp.
+func Parse(s string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is not an RFC3339 timestamp", s)
+	}
+	return t.UTC(), nil
+}
@@ -0,0 +1,64 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatParse_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{name: "utc", in: "2026-10-15T13:00:00Z", want: "2026-10-15T13:00:00Z"},
+		{name: "offset", in: "2026-10-15T15:00:00+02:00", want: "2026-10-15T13:00:00Z"},
+		{name: "negative offset across midnight", in: "2026-10-14T22:30:00-05:00", want: "2026-10-15T03:30:00Z"},
+		{name: "fractional seconds", in: "2026-10-15T13:00:00.987654321Z", want: "2026-10-15T13:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := Parse(tt.in)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.in, err)
+			}
+			if parsed.Location() != time.UTC {
+				t.Errorf("Parse(%q) location = %v, want UTC", tt.in, parsed.Location())
+			}
+			got := Format(parsed)
+			if got != tt.want {
+				t.Errorf("Format(Parse(%q)) = %q, want %q", tt.in, got, tt.want)
+			}
+			// A formatted timestamp reads back as itself
+			again, err := Parse(got)
+			if err != nil || Format(again) != got {
+				t.Errorf("%q does not round-trip: %v, %v", got, again, err)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, s := range []string{"", "yesterday", "2026-10-15", "2026-10-15 13:00:00"} {
+		if _, err := Parse(s); err == nil {
+			t.Errorf("expected Parse(%q) to fail", s)
+		}
+	}
+}
+
+func TestFormat_LocalTime(t *testing.T) {
+	at := time.Date(2026, 10, 15, 9, 0, 0, 0, time.FixedZone("EDT", -4*60*60))
+	if got := Format(at); got != "2026-10-15T13:00:00Z" {
+		t.Errorf("Format() = %q, want the time in UTC", got)
+	}
+}
+
+func TestOr(t *testing.T) {
+	at := time.Date(2026, 10, 15, 13, 0, 0, 0, time.UTC)
+	if got := Or(Fixed(at))(); !got.Equal(at) {
+		t.Errorf("Or(Fixed) = %v, want %v", got, at)
+	}
+	if got := Or(nil)(); time.Since(got) > time.Minute {
+		t.Errorf("Or(nil) = %v, want the real time", got)
+	}
+}
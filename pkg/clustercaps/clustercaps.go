@@ -0,0 +1,196 @@
+// Package clustercaps keeps a registry of what each management cluster can
+// apply, so work submissions embedding manifests the cluster does not serve
+// are rejected up front instead of failing on the cluster at apply time.
+package clustercaps
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Capabilities are what a management cluster is known to serve
+type Capabilities struct {
+	// ClusterID is the management cluster's name, the cluster_id works
+	// target
+	ClusterID string `dynamodbav:"clusterId" json:"cluster_id"`
+	// APIResources are the resource types the cluster serves, as
+	// apiVersion/kind: v1/ConfigMap, apps/v1/Deployment
+	APIResources []string `dynamodbav:"apiResources" json:"api_resources"`
+	// KubernetesVersion is the cluster's Kubernetes version, such as 1.29.4
+	KubernetesVersion string `dynamodbav:"kubernetesVersion,omitempty" json:"kubernetes_version,omitempty"`
+	// CRDs are the names of the installed custom resource definitions,
+	// such as servicemonitors.monitoring.coreos.com
+	CRDs []string `dynamodbav:"crds,omitempty" json:"crds,omitempty"`
+	// AgentVersion is the version of the work agent applying works
+	AgentVersion string `dynamodbav:"agentVersion,omitempty" json:"agent_version,omitempty"`
+	UpdatedAt    string `dynamodbav:"updatedAt" json:"updated_at"`
+	UpdatedBy    string `dynamodbav:"updatedBy" json:"updated_by,omitempty"`
+}
+
+// Validate returns why c is malformed, or ""
+func (c *Capabilities) Validate() string {
+	if problem := ValidateResourceTypes(c.APIResources); problem != "" {
+		return problem
+	}
+	for _, v := range [][2]string{{"kubernetes_version", c.KubernetesVersion}, {"agent_version", c.AgentVersion}} {
+		if v[1] == "" {
+			continue
+		}
+		if _, err := semver.NewVersion(v[1]); err != nil {
+			return fmt.Sprintf("%s %q is not a version: %v", v[0], v[1], err)
+		}
+	}
+	for _, crd := range c.CRDs {
+		if !strings.Contains(crd, ".") || strings.ContainsAny(crd, "/ ") {
+			return fmt.Sprintf("CRD %q must be a CRD name, such as servicemonitors.monitoring.coreos.com", crd)
+		}
+	}
+	return ""
+}
+
+// Store persists the capabilities of each management cluster
+type Store interface {
+	// Get returns a cluster's capabilities, or nil when none were recorded
+	Get(ctx context.Context, clusterID string) (*Capabilities, error)
+	Put(ctx context.Context, caps *Capabilities) error
+}
+
+// ResourceType names a manifest's type as listed in APIResources
+func ResourceType(apiVersion, kind string) string {
+	return apiVersion + "/" + kind
+}
+
+// ValidateResourceTypes returns why an APIResources list is malformed, or ""
+func ValidateResourceTypes(resources []string) string {
+	for _, r := range resources {
+		parts := strings.Split(r, "/")
+		if len(parts) < 2 || len(parts) > 3 || slicesContainEmpty(parts) {
+			return fmt.Sprintf("api resource %q must be apiVersion/kind, such as v1/ConfigMap or apps/v1/Deployment", r)
+		}
+	}
+	return ""
+}
+
+func slicesContainEmpty(parts []string) bool {
+	for _, p := range parts {
+		if p == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// Unsupported returns the resource types of mw's manifests that caps does
+// not list, sorted and without duplicates
+func Unsupported(caps *Capabilities, mw *workv1.ManifestWork) ([]string, error) {
+	served := make(map[string]bool, len(caps.APIResources))
+	for _, r := range caps.APIResources {
+		served[r] = true
+	}
+
+	missing := map[string]bool{}
+	for i, manifest := range mw.Spec.Workload.Manifests {
+		var meta struct {
+			APIVersion string `json:"apiVersion"`
+			Kind       string `json:"kind"`
+		}
+		if err := json.Unmarshal(manifest.Raw, &meta); err != nil {
+			return nil, fmt.Errorf("failed to read the type of manifest %d: %w", i, err)
+		}
+		if t := ResourceType(meta.APIVersion, meta.Kind); !served[t] {
+			missing[t] = true
+		}
+	}
+
+	unsupported := make([]string, 0, len(missing))
+	for t := range missing {
+		unsupported = append(unsupported, t)
+	}
+	sort.Strings(unsupported)
+	return unsupported, nil
+}
+
+// DynamoStore implements Store backed by DynamoDB
+type DynamoStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewDynamoStore creates a new DynamoDB-backed capability store
+func NewDynamoStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DynamoStore {
+	return &DynamoStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *DynamoStore) WithClock(now clock.Clock) *DynamoStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Get retrieves a cluster's capabilities, returning nil if none exist
+func (s *DynamoStore) Get(ctx context.Context, clusterID string) (*Capabilities, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"clusterId": &types.AttributeValueMemberS{Value: clusterID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster capabilities: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var caps Capabilities
+	if err := attributevalue.UnmarshalMap(result.Item, &caps); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal cluster capabilities: %w", err)
+	}
+
+	return &caps, nil
+}
+
+// Put stores a cluster's capabilities, replacing any recorded before
+func (s *DynamoStore) Put(ctx context.Context, caps *Capabilities) error {
+	if caps.UpdatedAt == "" {
+		caps.UpdatedAt = clock.Format(s.now())
+	}
+
+	item, err := attributevalue.MarshalMap(caps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal cluster capabilities: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put cluster capabilities: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package clustercaps
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+type mockDynamoClient struct {
+	items map[string]map[string]types.AttributeValue
+}
+
+func (m *mockDynamoClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	m.items[params.Item["clusterId"].(*types.AttributeValueMemberS).Value] = params.Item
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	return &dynamodb.GetItemOutput{Item: m.items[params.Key["clusterId"].(*types.AttributeValueMemberS).Value]}, nil
+}
+
+func (m *mockDynamoClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	return &dynamodb.QueryOutput{}, nil
+}
+
+func (m *mockDynamoClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return &dynamodb.ScanOutput{}, nil
+}
+
+func (m *mockDynamoClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (m *mockDynamoClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func TestDynamoStore_PutGet(t *testing.T) {
+	store := NewDynamoStore("caps", &mockDynamoClient{items: map[string]map[string]types.AttributeValue{}}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	if caps, err := store.Get(ctx, "mc-a"); err != nil || caps != nil {
+		t.Fatalf("expected no capabilities before any were stored, got %+v, %v", caps, err)
+	}
+
+	if err := store.Put(ctx, &Capabilities{ClusterID: "mc-a", APIResources: []string{"v1/ConfigMap"}}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	caps, err := store.Get(ctx, "mc-a")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if caps == nil || len(caps.APIResources) != 1 || caps.APIResources[0] != "v1/ConfigMap" || caps.UpdatedAt == "" {
+		t.Errorf("unexpected capabilities %+v", caps)
+	}
+}
+
+func TestValidateResourceTypes(t *testing.T) {
+	for _, r := range []string{"v1/ConfigMap", "apps/v1/Deployment"} {
+		if problem := ValidateResourceTypes([]string{r}); problem != "" {
+			t.Errorf("expected %q to be valid, got %q", r, problem)
+		}
+	}
+	for _, r := range []string{"ConfigMap", "v1/", "a/b/c/Kind", "/v1/Kind"} {
+		if problem := ValidateResourceTypes([]string{r}); problem == "" {
+			t.Errorf("expected %q to be rejected", r)
+		}
+	}
+}
+
+func TestUnsupported(t *testing.T) {
+	manifest := func(apiVersion, kind string) workv1.Manifest {
+		raw, _ := json.Marshal(map[string]string{"apiVersion": apiVersion, "kind": kind})
+		return workv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}}
+	}
+	mw := &workv1.ManifestWork{Spec: workv1.ManifestWorkSpec{Workload: workv1.ManifestsTemplate{Manifests: []workv1.Manifest{
+		manifest("v1", "ConfigMap"),
+		manifest("monitoring.coreos.com/v1", "ServiceMonitor"),
+		manifest("batch/v1beta1", "CronJob"),
+		manifest("monitoring.coreos.com/v1", "ServiceMonitor"),
+	}}}}
+
+	got, err := Unsupported(&Capabilities{APIResources: []string{"v1/ConfigMap", "batch/v1/CronJob"}}, mw)
+	if err != nil {
+		t.Fatalf("Unsupported: %v", err)
+	}
+	want := []string{"batch/v1beta1/CronJob", "monitoring.coreos.com/v1/ServiceMonitor"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
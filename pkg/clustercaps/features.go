@@ -0,0 +1,89 @@
+package clustercaps
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// How works using features the target cluster's agent is too old for are
+// treated
+const (
+	CompatibilityOff   = "off"
+	CompatibilityWarn  = "warn"
+	CompatibilityBlock = "block"
+)
+
+// CompatibilityModes lists the valid compatibility modes
+var CompatibilityModes = []string{CompatibilityOff, CompatibilityWarn, CompatibilityBlock}
+
+// AgentFeature is a ManifestWork feature only work agents from MinVersion
+// on support; older agents ignore or reject it
+type AgentFeature struct {
+	Name       string
+	MinVersion string
+	used       func(*workv1.ManifestWork) bool
+}
+
+// AgentFeatures is the compatibility matrix of the ManifestWork features
+// that need a newer agent than the oldest still deployed
+var AgentFeatures = []AgentFeature{
+	{Name: "ServerSideApply update strategy", MinVersion: "0.9.0", used: usesUpdateStrategy(workv1.UpdateStrategyTypeServerSideApply)},
+	{Name: "CreateOnly update strategy", MinVersion: "0.9.0", used: usesUpdateStrategy(workv1.UpdateStrategyTypeCreateOnly)},
+	{Name: "ReadOnly update strategy", MinVersion: "0.13.0", used: usesUpdateStrategy(workv1.UpdateStrategyTypeReadOnly)},
+	{Name: "ServerSideApply ignoreFields", MinVersion: "0.14.0", used: usesIgnoreFields},
+	{Name: "WellKnownStatus feedback rules", MinVersion: "0.6.0", used: usesFeedbackRule(workv1.WellKnownStatusType)},
+	{Name: "JSONPaths feedback rules", MinVersion: "0.9.0", used: usesFeedbackRule(workv1.JSONPathsType)},
+	{Name: "SelectivelyOrphan delete option", MinVersion: "0.7.0", used: usesSelectiveOrphan},
+}
+
+// Incompatible describes each feature of mw the agent caps records is too
+// old for. Clusters that record no agent version are assumed compatible.
+func Incompatible(caps *Capabilities, mw *workv1.ManifestWork) []string {
+	if caps == nil || caps.AgentVersion == "" {
+		return nil
+	}
+	agent, err := semver.NewVersion(caps.AgentVersion)
+	if err != nil {
+		return nil
+	}
+
+	var incompatible []string
+	for _, f := range AgentFeatures {
+		if !f.used(mw) {
+			continue
+		}
+		if agent.LessThan(semver.MustParse(f.MinVersion)) {
+			incompatible = append(incompatible, fmt.Sprintf("%s requires work agent %s or later, the cluster runs %s", f.Name, f.MinVersion, caps.AgentVersion))
+		}
+	}
+	return incompatible
+}
+
+func usesUpdateStrategy(t workv1.UpdateStrategyType) func(*workv1.ManifestWork) bool {
+	return func(mw *workv1.ManifestWork) bool {
+		return slices.ContainsFunc(mw.Spec.ManifestConfigs, func(c workv1.ManifestConfigOption) bool {
+			return c.UpdateStrategy != nil && c.UpdateStrategy.Type == t
+		})
+	}
+}
+
+func usesIgnoreFields(mw *workv1.ManifestWork) bool {
+	return slices.ContainsFunc(mw.Spec.ManifestConfigs, func(c workv1.ManifestConfigOption) bool {
+		return c.UpdateStrategy != nil && c.UpdateStrategy.ServerSideApply != nil && len(c.UpdateStrategy.ServerSideApply.IgnoreFields) > 0
+	})
+}
+
+func usesFeedbackRule(t workv1.FeedBackType) func(*workv1.ManifestWork) bool {
+	return func(mw *workv1.ManifestWork) bool {
+		return slices.ContainsFunc(mw.Spec.ManifestConfigs, func(c workv1.ManifestConfigOption) bool {
+			return slices.ContainsFunc(c.FeedbackRules, func(r workv1.FeedbackRule) bool { return r.Type == t })
+		})
+	}
+}
+
+func usesSelectiveOrphan(mw *workv1.ManifestWork) bool {
+	return mw.Spec.DeleteOption != nil && mw.Spec.DeleteOption.PropagationPolicy == workv1.DeletePropagationPolicyTypeSelectivelyOrphan
+}
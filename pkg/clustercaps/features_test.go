@@ -0,0 +1,55 @@
+package clustercaps
+
+import (
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+func TestIncompatible(t *testing.T) {
+	mw := &workv1.ManifestWork{Spec: workv1.ManifestWorkSpec{
+		ManifestConfigs: []workv1.ManifestConfigOption{{
+			UpdateStrategy: &workv1.UpdateStrategy{
+				Type:            workv1.UpdateStrategyTypeServerSideApply,
+				ServerSideApply: &workv1.ServerSideApplyConfig{IgnoreFields: []workv1.IgnoreField{{JSONPaths: []string{".spec.replicas"}}}},
+			},
+			FeedbackRules: []workv1.FeedbackRule{{Type: workv1.JSONPathsType}},
+		}},
+	}}
+
+	tests := []struct {
+		name  string
+		agent string
+		want  []string
+	}{
+		{name: "current agent", agent: "0.15.2"},
+		{name: "agent without ignoreFields", agent: "v0.13.0", want: []string{"ServerSideApply ignoreFields requires work agent 0.14.0"}},
+		{name: "old agent", agent: "0.8.1", want: []string{
+			"ServerSideApply update strategy requires work agent 0.9.0",
+			"ServerSideApply ignoreFields requires work agent 0.14.0",
+			"JSONPaths feedback rules requires work agent 0.9.0",
+		}},
+		{name: "agent version not recorded"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Incompatible(&Capabilities{AgentVersion: tt.agent}, mw)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("expected %q, got %q", tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestIncompatible_PlainWork(t *testing.T) {
+	if got := Incompatible(&Capabilities{AgentVersion: "0.1.0"}, &workv1.ManifestWork{}); len(got) != 0 {
+		t.Errorf("expected a work using no gated feature to be compatible, got %v", got)
+	}
+}
@@ -0,0 +1,73 @@
+package clustercaps
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Requirements restrict which management clusters a work may target, by
+// what their capabilities record
+type Requirements struct {
+	// KubernetesVersion is a version constraint, such as ">= 1.28"
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+	// CRDs are custom resource definitions that must be installed
+	CRDs []string `json:"crds,omitempty"`
+	// AgentVersion is a version constraint on the work agent
+	AgentVersion string `json:"agent_version,omitempty"`
+}
+
+// Validate returns why r is malformed, or ""
+func (r *Requirements) Validate() string {
+	for _, c := range [][2]string{{"kubernetes_version", r.KubernetesVersion}, {"agent_version", r.AgentVersion}} {
+		if c[1] == "" {
+			continue
+		}
+		if _, err := semver.NewConstraint(c[1]); err != nil {
+			return fmt.Sprintf("%s requirement %q is not a version constraint: %v", c[0], c[1], err)
+		}
+	}
+	return ""
+}
+
+// Unmet describes each requirement caps does not meet; a version caps does
+// not record does not meet a constraint on it. caps may be nil, meeting no
+// requirement. r must be valid.
+func (r *Requirements) Unmet(caps *Capabilities) []string {
+	if caps == nil {
+		caps = &Capabilities{}
+	}
+
+	var unmet []string
+	if problem := versionUnmet("kubernetes version", r.KubernetesVersion, caps.KubernetesVersion); problem != "" {
+		unmet = append(unmet, problem)
+	}
+	for _, crd := range r.CRDs {
+		if !slices.Contains(caps.CRDs, crd) {
+			unmet = append(unmet, fmt.Sprintf("CRD %s is not installed", crd))
+		}
+	}
+	if problem := versionUnmet("agent version", r.AgentVersion, caps.AgentVersion); problem != "" {
+		unmet = append(unmet, problem)
+	}
+	return unmet
+}
+
+func versionUnmet(name, constraint, version string) string {
+	if constraint == "" {
+		return ""
+	}
+	if version == "" {
+		return fmt.Sprintf("%s is not recorded, so %s cannot be checked", name, constraint)
+	}
+	c, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return fmt.Sprintf("%s constraint %q is invalid", name, constraint)
+	}
+	v, err := semver.NewVersion(version)
+	if err != nil || !c.Check(v) {
+		return fmt.Sprintf("%s %s does not satisfy %s", name, version, constraint)
+	}
+	return ""
+}
@@ -0,0 +1,66 @@
+package clustercaps
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequirements_Unmet(t *testing.T) {
+	caps := &Capabilities{
+		KubernetesVersion: "1.29.4",
+		CRDs:              []string{"servicemonitors.monitoring.coreos.com"},
+		AgentVersion:      "v0.6.0",
+	}
+
+	tests := []struct {
+		name     string
+		requires Requirements
+		caps     *Capabilities
+		want     []string
+	}{
+		{name: "none", caps: caps},
+		{name: "met", requires: Requirements{KubernetesVersion: ">= 1.28", CRDs: []string{"servicemonitors.monitoring.coreos.com"}, AgentVersion: "~0.6"}, caps: caps},
+		{name: "kubernetes too old", requires: Requirements{KubernetesVersion: ">= 1.30"}, caps: caps, want: []string{"kubernetes version 1.29.4 does not satisfy >= 1.30"}},
+		{name: "missing CRD and agent too old", requires: Requirements{CRDs: []string{"prometheusrules.monitoring.coreos.com"}, AgentVersion: ">= 0.7"}, caps: caps,
+			want: []string{"CRD prometheusrules.monitoring.coreos.com is not installed", "agent version v0.6.0 does not satisfy >= 0.7"}},
+		{name: "nothing recorded", requires: Requirements{KubernetesVersion: ">= 1.28"}, want: []string{"kubernetes version is not recorded"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.requires.Unmet(tt.caps)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if !strings.HasPrefix(got[i], tt.want[i]) {
+					t.Errorf("expected %q, got %q", tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRequirements_Validate(t *testing.T) {
+	if problem := (&Requirements{KubernetesVersion: ">= 1.28, < 1.31"}).Validate(); problem != "" {
+		t.Errorf("expected a valid constraint, got %q", problem)
+	}
+	if problem := (&Requirements{AgentVersion: "newest"}).Validate(); !strings.Contains(problem, "agent_version") {
+		t.Errorf("expected the agent constraint to be rejected, got %q", problem)
+	}
+}
+
+func TestCapabilities_Validate(t *testing.T) {
+	valid := Capabilities{APIResources: []string{"v1/ConfigMap"}, KubernetesVersion: "1.29.4", CRDs: []string{"servicemonitors.monitoring.coreos.com"}}
+	if problem := valid.Validate(); problem != "" {
+		t.Errorf("expected valid capabilities, got %q", problem)
+	}
+	for _, c := range []Capabilities{
+		{KubernetesVersion: "latest"},
+		{CRDs: []string{"monitoring.coreos.com/v1"}},
+	} {
+		if problem := c.Validate(); problem == "" {
+			t.Errorf("expected %+v to be rejected", c)
+		}
+	}
+}
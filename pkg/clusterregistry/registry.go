@@ -0,0 +1,166 @@
+package clusterregistry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Labels stamped on Maestro consumers when a management cluster is registered
+const (
+	// LabelOwnerAccount identifies the AWS account that registered the cluster
+	LabelOwnerAccount = "rosa.openshift.io/owner-account"
+	// LabelRegisteredAt is the registration time as Unix seconds
+	LabelRegisteredAt = "rosa.openshift.io/registered-at"
+)
+
+// accountIndexName is the GSI used to list registrations for an account
+const accountIndexName = "account-index"
+
+// Registration records which account owns a management cluster (Maestro consumer)
+type Registration struct {
+	ConsumerID   string `dynamodbav:"consumerId" json:"consumerId"`
+	ConsumerName string `dynamodbav:"consumerName" json:"consumerName"`
+	AccountID    string `dynamodbav:"accountId" json:"accountId"`
+	RegisteredBy string `dynamodbav:"registeredBy" json:"registeredBy"`
+	RegisteredAt string `dynamodbav:"registeredAt" json:"registeredAt"`
+}
+
+// Registry tracks management cluster ownership per account
+type Registry interface {
+	Register(ctx context.Context, reg *Registration) error
+	Get(ctx context.Context, consumerID string) (*Registration, error)
+	ListByAccount(ctx context.Context, accountID string) ([]*Registration, error)
+	Delete(ctx context.Context, consumerID string) error
+}
+
+// OwnerLabels returns the labels to stamp on a consumer registered by accountID
+func OwnerLabels(accountID string, registeredAt time.Time) map[string]string {
+	return map[string]string{
+		LabelOwnerAccount: accountID,
+		LabelRegisteredAt: fmt.Sprintf("%d", registeredAt.Unix()),
+	}
+}
+
+// DynamoRegistry implements Registry backed by DynamoDB
+type DynamoRegistry struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewDynamoRegistry creates a new DynamoDB-backed management cluster registry
+func NewDynamoRegistry(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DynamoRegistry {
+	return &DynamoRegistry{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (r *DynamoRegistry) WithClock(now clock.Clock) *DynamoRegistry {
+	r.now = clock.Or(now)
+	return r
+}
+
+// Register stores a new registration
+func (r *DynamoRegistry) Register(ctx context.Context, reg *Registration) error {
+	if reg.RegisteredAt == "" {
+		reg.RegisteredAt = clock.Format(r.now())
+	}
+
+	item, err := attributevalue.MarshalMap(reg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal registration: %w", err)
+	}
+
+	_, err = r.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(consumerId)"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register management cluster: %w", err)
+	}
+
+	r.logger.Info("management cluster registered", "consumer_id", reg.ConsumerID, "account_id", reg.AccountID)
+	return nil
+}
+
+// Get retrieves a registration by consumer ID, returning nil if none exists
+func (r *DynamoRegistry) Get(ctx context.Context, consumerID string) (*Registration, error) {
+	result, err := r.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"consumerId": &types.AttributeValueMemberS{Value: consumerID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get registration: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var reg Registration
+	if err := attributevalue.UnmarshalMap(result.Item, &reg); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal registration: %w", err)
+	}
+
+	return &reg, nil
+}
+
+// ListByAccount returns all registrations owned by an account
+func (r *DynamoRegistry) ListByAccount(ctx context.Context, accountID string) ([]*Registration, error) {
+	result, err := r.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tableName),
+		IndexName:              aws.String(accountIndexName),
+		KeyConditionExpression: aws.String("accountId = :aid"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":aid": &types.AttributeValueMemberS{Value: accountID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list registrations: %w", err)
+	}
+
+	regs := make([]*Registration, 0, len(result.Items))
+	for _, item := range result.Items {
+		var reg Registration
+		if err := attributevalue.UnmarshalMap(item, &reg); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal registration: %w", err)
+		}
+		regs = append(regs, &reg)
+	}
+
+	return regs, nil
+}
+
+// Delete removes a registration
+func (r *DynamoRegistry) Delete(ctx context.Context, consumerID string) error {
+	_, err := r.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tableName),
+		Key: map[string]types.AttributeValue{
+			"consumerId": &types.AttributeValueMemberS{Value: consumerID},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete registration: %w", err)
+	}
+
+	r.logger.Info("management cluster registration deleted", "consumer_id", consumerID)
+	return nil
+}
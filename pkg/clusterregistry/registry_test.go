@@ -0,0 +1,122 @@
+package clusterregistry
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockDynamoClient struct {
+	putItemFunc func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	getItemFunc func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	queryFunc   func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+func (m *mockDynamoClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if m.putItemFunc != nil {
+		return m.putItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if m.getItemFunc != nil {
+		return m.getItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.QueryOutput{}, nil
+}
+
+func (m *mockDynamoClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return &dynamodb.ScanOutput{}, nil
+}
+
+func (m *mockDynamoClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (m *mockDynamoClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func TestOwnerLabels(t *testing.T) {
+	labels := OwnerLabels("111222333444", time.Unix(1700000000, 0))
+	assert.Equal(t, "111222333444", labels[LabelOwnerAccount])
+	assert.Equal(t, "1700000000", labels[LabelRegisteredAt])
+}
+
+func TestDynamoRegistry_Register(t *testing.T) {
+	var capturedInput *dynamodb.PutItemInput
+	client := &mockDynamoClient{
+		putItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			capturedInput = params
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+
+	registry := NewDynamoRegistry("test-table", client, testLogger())
+
+	reg := &Registration{ConsumerID: "mc-1", AccountID: "111222333444"}
+	require.NoError(t, registry.Register(context.Background(), reg))
+	assert.Equal(t, "test-table", *capturedInput.TableName)
+	assert.Contains(t, *capturedInput.ConditionExpression, "attribute_not_exists")
+	assert.NotEmpty(t, reg.RegisteredAt)
+}
+
+func TestDynamoRegistry_Get_NotFound(t *testing.T) {
+	registry := NewDynamoRegistry("test-table", &mockDynamoClient{}, testLogger())
+
+	reg, err := registry.Get(context.Background(), "missing")
+	require.NoError(t, err)
+	assert.Nil(t, reg)
+}
+
+func TestDynamoRegistry_ListByAccount(t *testing.T) {
+	var capturedInput *dynamodb.QueryInput
+	client := &mockDynamoClient{
+		queryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			capturedInput = params
+			return &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"consumerId": &types.AttributeValueMemberS{Value: "mc-1"},
+						"accountId":  &types.AttributeValueMemberS{Value: "111222333444"},
+					},
+				},
+			}, nil
+		},
+	}
+
+	registry := NewDynamoRegistry("test-table", client, testLogger())
+
+	regs, err := registry.ListByAccount(context.Background(), "111222333444")
+	require.NoError(t, err)
+	require.Len(t, regs, 1)
+	assert.Equal(t, "mc-1", regs[0].ConsumerID)
+	assert.Equal(t, accountIndexName, *capturedInput.IndexName)
+}
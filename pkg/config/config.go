@@ -1,9 +1,17 @@
 package config
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/bootstrap"
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
+	"github.com/openshift/rosa-regional-platform-api/pkg/loadshed"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
 )
 
 type Config struct {
@@ -11,11 +19,473 @@ type Config struct {
 	Maestro         MaestroConfig
 	Hyperfleet      HyperfleetConfig
 	Logging         LoggingConfig
+	Identity        IdentityConfig
 	Authz           *authz.Config
 	Zoa             ZoaConfig
+	MgmtClusters    ManagementClusterConfig
+	ResourceBundles ResourceBundleConfig
+	Work            WorkConfig
+	Status          StatusConfig
+	Canary          CanaryConfig
+	Metering        MeteringConfig
+	Events          EventsConfig
+	Capture         CaptureConfig
+	Mirror          MirrorConfig
+	Secrets         SecretsConfig
+	PolicyBackup    PolicyBackupConfig
+	AccountPurge    AccountPurgeConfig
+	Operations      OperationsConfig
+	Notifications   NotificationsConfig
+	Pagination      PaginationConfig
+	SlowRequests    SlowRequestConfig
+	SLO             SLOConfig
+	LoadShed        LoadShedConfig
+	AccountMetrics  AccountMetricsConfig
+	ErrorTracking   ErrorTrackingConfig
+	Runtime         RuntimeConfig
+	LeaderElection  LeaderElectionConfig
+	RateLimit       RateLimitConfig
+	Plans           PlansConfig
+	Cache           CacheConfig
+	AuthzStreams    AuthzStreamConfig
+	Bootstrap       *bootstrap.Manifest
+	RequiredTags    RequiredTagsConfig
 	AllowedAccounts []string
 }
 
+// IdentityConfig configures where caller identity is read from
+type IdentityConfig struct {
+	// RequestContext reads identity from the API Gateway v2 request context header
+	RequestContext bool
+	// AccountIDHeader and CallerARNHeader name the identity headers, which
+	// Lambda authorizers may inject under their own names
+	AccountIDHeader string
+	CallerARNHeader string
+	// AuthorizerAccountIDKey and AuthorizerCallerARNKey name the Lambda
+	// authorizer context keys holding the account ID and caller ARN
+	AuthorizerAccountIDKey string
+	AuthorizerCallerARNKey string
+	// SharedSecret, when set, must be sent by the gateway in SharedSecretHeader
+	SharedSecret       string
+	SharedSecretHeader string
+	// TrustedProxies lists the CIDRs identity headers are accepted from; empty accepts any peer
+	TrustedProxies []string
+	// ReplayWindow, when set, requires mutating requests to carry a timestamp
+	// within this long of now and a nonce not seen within the window
+	ReplayWindow time.Duration
+	// ReplayCacheSize bounds the nonces each replica remembers per account
+	ReplayCacheSize int
+	// SuperAdmins lists IAM user and role ARNs that may bootstrap the first
+	// privileged account through the API
+	SuperAdmins []string
+}
+
+// StatusConfig configures the regional status endpoint
+type StatusConfig struct {
+	// Window is the trailing window over which error rates and delivery lag are computed
+	Window       time.Duration
+	ProbeTimeout time.Duration
+	CacheTTL     time.Duration
+	// ErrorRateThreshold is the 5xx fraction above which the region reports degraded
+	ErrorRateThreshold float64
+	// DeliveryLagThreshold is the p95 delivery lag above which the region reports degraded
+	DeliveryLagThreshold time.Duration
+}
+
+// PolicyBackupConfig configures scheduled backups of AVP policy stores
+type PolicyBackupConfig struct {
+	// Bucket enables the backup worker when set
+	Bucket    string
+	Prefix    string
+	AWSRegion string
+	Interval  time.Duration
+	// Retention is how long backups are kept; 0 keeps them forever
+	Retention time.Duration
+}
+
+// AccountPurgeConfig configures the privileged account purge used to
+// offboard tenants
+type AccountPurgeConfig struct {
+	// SigningKeyID is the KMS key deletion reports are signed with; empty
+	// disables purging
+	SigningKeyID string
+	AWSRegion    string
+}
+
+// OperationsConfig configures the long-running operations framework
+type OperationsConfig struct {
+	// ResultTTL is how long finished operations are kept with their outcome
+	ResultTTL time.Duration
+}
+
+// NotificationsConfig configures per-account notification settings and
+// delivery of platform events to them
+type NotificationsConfig struct {
+	Enabled          bool
+	TableName        string
+	AWSRegion        string
+	DynamoDBEndpoint string
+	// EmailSender is the SES-verified From address; empty disables email
+	EmailSender string
+}
+
+// RequiredTagsConfig lists the tag keys every account's create requests must
+// carry as request tags. Accounts may require more of their own.
+type RequiredTagsConfig struct {
+	Cluster []string
+	Work    []string
+}
+
+// ErrorTrackingConfig configures reporting of panics and error logs to Sentry
+type ErrorTrackingConfig struct {
+	// DSN enables reporting when set
+	DSN         string
+	Environment string
+	// MinLevel is the lowest log level reported (debug, info, warn, error)
+	MinLevel string
+}
+
+// RuntimeConfig configures the Go runtime; zero values keep the runtime's
+// defaults and any GOMAXPROCS, GOGC or GOMEMLIMIT environment variable
+type RuntimeConfig struct {
+	MaxProcs  int
+	GCPercent int
+	// MemoryLimit is the soft memory limit in bytes
+	MemoryLimit int64
+	// MemoryLimitRatio derives the soft memory limit from the container's
+	// cgroup memory limit when MemoryLimit is unset
+	MemoryLimitRatio float64
+	// Ballast is the size in bytes of a heap ballast
+	Ballast int64
+}
+
+// LeaderElectionConfig configures which replica runs each background worker
+type LeaderElectionConfig struct {
+	// Enabled runs each worker on one replica at a time; otherwise every
+	// replica runs every worker
+	Enabled          bool
+	TableName        string
+	AWSRegion        string
+	DynamoDBEndpoint string
+	// Identity names this replica in the leases, normally the pod name
+	Identity string
+	// LeaseDuration is how long a lease lasts without renewal, and so how
+	// long a worker goes without a leader after its replica dies
+	LeaseDuration time.Duration
+}
+
+// RateLimitConfig configures per-account API rate limiting
+type RateLimitConfig struct {
+	// Limit is the requests each account may make per Window; 0 disables
+	// rate limiting
+	Limit  int
+	Window time.Duration
+	// Backend is local, which limits each replica separately, or dynamodb,
+	// which shares counters between replicas through TableName
+	Backend          string
+	TableName        string
+	AWSRegion        string
+	DynamoDBEndpoint string
+}
+
+// PlansConfig configures the plan tiers that scale each account's rate
+// limit, quotas and features
+type PlansConfig struct {
+	// Default is the plan of accounts that were not given one
+	Default string
+	// CacheTTL is how long an account's plan is cached, and so how long a
+	// plan change takes to reach every replica
+	CacheTTL time.Duration
+}
+
+// CacheConfig configures where caches shared between replicas are kept
+type CacheConfig struct {
+	// Backend is memory, which keeps each replica's caches to itself, or
+	// redis
+	Backend string
+	Redis   cache.RedisConfig
+}
+
+// AuthzStreamConfig configures consumption of the authz tables' DynamoDB
+// streams
+type AuthzStreamConfig struct {
+	// Enabled consumes the streams, which must be enabled on the tables with
+	// the NEW_AND_OLD_IMAGES view type
+	Enabled bool
+	// PollInterval is how often each table's stream is read
+	PollInterval time.Duration
+}
+
+// PageLimits bounds the page size accepted by a list endpoint
+type PageLimits struct {
+	// Default is used when a request does not set a page size
+	Default int
+	// Max is the largest page size accepted; larger requests are rejected
+	Max int
+}
+
+// Validate checks that the default is positive and within the maximum
+func (p PageLimits) Validate() error {
+	if p.Default <= 0 {
+		return fmt.Errorf("default page size must be positive, got %d", p.Default)
+	}
+	if p.Max < p.Default {
+		return fmt.Errorf("maximum page size %d is below the default %d", p.Max, p.Default)
+	}
+	return nil
+}
+
+// PaginationConfig sets page sizes per endpoint class
+type PaginationConfig struct {
+	// Tenant covers the cluster and nodepool lists
+	Tenant PageLimits
+	// Platform covers the management cluster and resource bundle lists
+	Platform PageLimits
+	// TrustedActions covers the trusted action run list
+	TrustedActions PageLimits
+	// Audit covers the trusted action audit log
+	Audit PageLimits
+}
+
+// SlowRequestConfig sets the latency above which a request is logged and
+// counted as slow, per route class. A zero threshold disables reporting for
+// the class.
+type SlowRequestConfig struct {
+	// Default covers routes outside the classes below
+	Default time.Duration
+	// Tenant covers the cluster and nodepool routes
+	Tenant time.Duration
+	// Platform covers the management cluster, resource bundle and work routes
+	Platform time.Duration
+	// TrustedActions covers the trusted action routes
+	TrustedActions time.Duration
+	// Authz covers the authz, accounts and admin routes
+	Authz time.Duration
+}
+
+// SLOConfig sets the availability and latency objectives reported per route
+// class. The latency SLI uses the slow-request threshold of each class.
+type SLOConfig struct {
+	// Period is the rolling window the error budget covers
+	Period time.Duration
+	// Availability is the target fraction of requests answered without a 5xx
+	Availability float64
+	// Latency is the target fraction of requests answered within their
+	// class's slow-request threshold
+	Latency float64
+}
+
+// LoadShedConfig configures shedding low-priority requests while the server
+// is degraded
+type LoadShedConfig struct {
+	// Rules give routes their priority (see loadshed.ParseRules); empty
+	// disables shedding
+	Rules string
+	// QueueLoad is the work queue load, submissions per slot, at which the
+	// server is degraded; 0 ignores the queue
+	QueueLoad float64
+	// Components are the health components whose failure degrades the
+	// server, besides authz
+	Components []string
+}
+
+// AccountMetricsConfig bounds the cardinality of per-account request
+// metrics
+type AccountMetricsConfig struct {
+	// TopAccounts is the number of busiest accounts labeled by account ID;
+	// the rest are counted under "other"
+	TopAccounts int
+	// RankInterval is how often the busiest accounts are re-ranked
+	RankInterval time.Duration
+}
+
+// WorkConfig configures the work (ManifestWork) endpoints
+type WorkConfig struct {
+	// EnvelopeKMSKeyID enables envelope encryption of Secret manifests when set
+	EnvelopeKMSKeyID string
+	// SecretRefsEnabled enables server-side resolution of {{resolve:...}} placeholders
+	SecretRefsEnabled bool
+	// SecretRefsPlatformPrefixes are the ARN prefixes of the platform's own
+	// secrets and parameters, the only references privileged callers resolve
+	// outside their account (see secretref.Resolver.WithPlatformSecrets)
+	SecretRefsPlatformPrefixes []string
+	// MetadataTableName enables the work metadata store (and deduplication) when set
+	MetadataTableName string
+	// SchedulesTableName enables scheduled work requests and the scheduler when set
+	SchedulesTableName string
+	// ScheduleInterval is how often the scheduler submits due schedules
+	ScheduleInterval time.Duration
+	AWSRegion        string
+	DynamoDBEndpoint string
+	// MaxManifests caps manifests per ManifestWork; 0 disables the limit
+	MaxManifests int
+	// MaxPayloadBytes caps the encoded ManifestWork size; 0 disables the limit
+	MaxPayloadBytes int
+	// MaxMessageBytes is the transport message size limit used for the
+	// CloudEvent pre-flight check; 0 disables the check
+	MaxMessageBytes int
+	// MaxChunks caps the ManifestWorks a chunked request may create; 0 disables the limit
+	MaxChunks int
+	// MaxConcurrent caps concurrent submissions to Maestro, queueing the rest
+	// by priority; 0 disables the limit
+	MaxConcurrent int
+	// MaxQueued caps the submissions waiting for a slot; 0 leaves it unbounded
+	MaxQueued int
+	// ClusterRateLimit caps the submissions per ClusterRateWindow to any one
+	// management cluster, per replica; 0 disables the limit
+	ClusterRateLimit  int
+	ClusterRateWindow time.Duration
+	// ChartRegistries enables Helm chart rendering, from these OCI registry
+	// hosts only, when set
+	ChartRegistries []string
+	// MaxChartBytes caps the size of a chart archive; 0 disables the limit
+	MaxChartBytes    int
+	ChartPullTimeout time.Duration
+	// PayloadRegistries and PayloadBuckets enable work payloads fetched by
+	// reference, from these OCI registry hosts and S3 buckets only, when set
+	PayloadRegistries []string
+	PayloadBuckets    []string
+	// MaxPayloadRefBytes caps the size of a referenced payload; 0 disables the limit
+	MaxPayloadRefBytes  int64
+	PayloadFetchTimeout time.Duration
+	// PayloadSigners, as issuer=subject-regexp, requires payloads referenced
+	// from OCI registries to be cosign-signed by one of them, verified
+	// against the Sigstore trusted root at PayloadTrustedRoot
+	PayloadSigners     []string
+	PayloadTrustedRoot string
+	// HistoryTableName enables the work status history and its recorder
+	// when set; it requires the metadata store
+	HistoryTableName string
+	// HistoryInterval is how often the recorder polls Maestro for changed
+	// conditions, and HistoryRetention how long transitions are kept (0
+	// keeps them forever)
+	HistoryInterval  time.Duration
+	HistoryRetention time.Duration
+}
+
+// ManagementClusterConfig configures per-account ownership of management clusters
+type ManagementClusterConfig struct {
+	RegistryEnabled   bool
+	RegistryTableName string
+	AWSRegion         string
+	DynamoDBEndpoint  string
+	// ListCacheTTL is how long the unscoped management cluster list is
+	// served from cache; 0 disables the cache
+	ListCacheTTL time.Duration
+	// ListCacheStale is how long past ListCacheTTL a cached list is still
+	// served while it is refreshed
+	ListCacheStale time.Duration
+	// DrainBundles deletes the resource bundles of a deregistered management
+	// cluster before its consumer; without it, clusters that still have
+	// resource bundles cannot be deregistered
+	DrainBundles bool
+	// DrainTimeout bounds how long a deregistration waits for Maestro to
+	// remove the drained resource bundles
+	DrainTimeout time.Duration
+	// CapabilitiesTableName stores what each management cluster serves, so
+	// works embedding manifests it does not serve are rejected; empty
+	// disables the check
+	CapabilitiesTableName string
+	// AgentCompatibility is how works using features the target cluster's
+	// work agent is too old for are treated: off, warn or block
+	AgentCompatibility string
+}
+
+// ResourceBundleConfig configures the resource bundle endpoints
+type ResourceBundleConfig struct {
+	// SummaryCacheTTL is how long a computed resource bundle summary is
+	// reused; 0 computes it on every request
+	SummaryCacheTTL time.Duration
+}
+
+// CanaryConfig configures the in-process delivery canary
+type CanaryConfig struct {
+	// ClusterName is the management cluster canary works are sent to; empty
+	// disables the canary
+	ClusterName string
+	// Namespace on the canary cluster the canary ConfigMap is created in
+	Namespace string
+	// Interval between probes
+	Interval time.Duration
+	// Timeout bounds how long a probe waits for its work to be applied
+	Timeout time.Duration
+}
+
+// EventsConfig configures publishing platform events to Kafka
+type EventsConfig struct {
+	// KafkaBrokers are the seed brokers; empty disables events
+	KafkaBrokers []string
+	// Topics maps event types to topics (see events.ParseTopics), and
+	// DefaultTopic receives the events it does not map; empty drops them
+	Topics       string
+	DefaultTopic string
+	// Source is the CloudEvents source of the events
+	Source string
+	// Encoding is json or avro. SchemaRegistryURL, required with avro,
+	// registers the events' schema for each topic.
+	Encoding          string
+	SchemaRegistryURL string
+	// TLS connects over TLS; MSKIAM authenticates to Amazon MSK with IAM in
+	// AWSRegion, over TLS
+	TLS        bool
+	MSKIAM     bool
+	AWSRegion  string
+	BufferSize int
+}
+
+// MeteringConfig configures emitting metering records for billable API
+// calls
+type MeteringConfig struct {
+	// Sink is file, sqs or kinesis; empty disables metering
+	Sink string
+	// Target is the file path, SQS queue URL or Kinesis stream name
+	Target    string
+	AWSRegion string
+	// BatchSize, FlushInterval and BufferSize configure batching; see
+	// metering.Config
+	BatchSize     int
+	FlushInterval time.Duration
+	BufferSize    int
+}
+
+// SecretsConfig configures resolving secret values given as
+// secretsmanager:// or ssm:// references (see package secretsource): the
+// identity shared secret, the Redis password and the Sentry DSN
+type SecretsConfig struct {
+	AWSRegion string
+	// RefreshInterval is how often resolved secrets are re-read; 0 reads
+	// them only at startup. The Sentry DSN is only read at startup.
+	RefreshInterval time.Duration
+}
+
+// CaptureConfig configures recording request envelopes for replay
+type CaptureConfig struct {
+	// File is where envelopes are appended; empty disables capture
+	File string
+	// Accounts limits capture to these accounts; empty captures all
+	Accounts []string
+	// BodyAccounts lists the accounts that opted in to having request
+	// bodies captured; other bodies are recorded as a hash
+	BodyAccounts []string
+}
+
+// MirrorConfig configures mirroring requests to a secondary deployment
+type MirrorConfig struct {
+	// Target is the base URL of the secondary deployment; empty disables
+	// mirroring
+	Target string
+	// Percent of the eligible requests that are mirrored
+	Percent float64
+	// DryRunWrites also mirrors writes that are dry runs; other writes are
+	// never mirrored
+	DryRunWrites bool
+	// Timeout bounds each mirrored request
+	Timeout time.Duration
+	// MaxInFlight bounds the mirrored requests outstanding at once; more are
+	// dropped
+	MaxInFlight int
+}
+
 type ZoaConfig struct {
 	Enabled        bool
 	TableName      string
@@ -27,7 +497,18 @@ type ZoaConfig struct {
 	PollInterval   time.Duration
 }
 
+// Server profiles select which route sets the API server registers.
+const (
+	// ProfileAll serves both the tenant-facing and platform routes
+	ProfileAll = "all"
+	// ProfileFrontend serves tenant-facing routes (clusters, nodepools, authz, accounts)
+	ProfileFrontend = "frontend"
+	// ProfilePlatform serves platform routes (management clusters, resource bundles, work, trusted actions)
+	ProfilePlatform = "platform"
+)
+
 type ServerConfig struct {
+	Profile            string
 	APIBindAddress     string
 	APIPort            int
 	GRPCBindAddress    string
@@ -37,12 +518,59 @@ type ServerConfig struct {
 	MetricsBindAddress string
 	MetricsPort        int
 	ShutdownTimeout    time.Duration
+	// WarmupTimeout bounds priming connections and caches before readiness
+	// passes; 0 makes the server ready as soon as it starts
+	WarmupTimeout time.Duration
+	// RequestTimeout bounds each API request; 0 disables the overall deadline
+	RequestTimeout time.Duration
+	// AuthzBudget is the share of RequestTimeout reserved for authorization
+	// checks; the remainder is left to Maestro and other upstreams
+	AuthzBudget time.Duration
+	// BasePath is the external path prefix clients reach the API under, such
+	// as an API Gateway stage; empty serves the API at the root
+	BasePath string
+	// ExternalURL is the absolute URL clients reach the API at, such as a
+	// custom domain; when set, generated links are absolute under it
+	ExternalURL string
+	// Dev enables local development aids, such as taking caller identity
+	// from basic auth or query parameters. Never enable it in a deployment.
+	Dev bool
+	// Region is the AWS region this deployment serves; accounts pinned to
+	// another region may not submit work through it. Empty skips the check.
+	Region string
+}
+
+// ServesFrontend reports whether the tenant-facing route set is enabled
+func (s ServerConfig) ServesFrontend() bool {
+	return s.Profile == "" || s.Profile == ProfileAll || s.Profile == ProfileFrontend
+}
+
+// ServesPlatform reports whether the platform route set is enabled
+func (s ServerConfig) ServesPlatform() bool {
+	return s.Profile == "" || s.Profile == ProfileAll || s.Profile == ProfilePlatform
 }
 
 type MaestroConfig struct {
 	BaseURL     string
 	GRPCBaseURL string
 	Timeout     time.Duration
+	// VersionCheckInterval is how often Maestro's API version and optional
+	// capabilities are detected; 0 disables detection and the optional
+	// features that depend on it
+	VersionCheckInterval time.Duration
+	// SecondaryBaseURL and SecondaryGRPCBaseURL address a second Maestro
+	// deployment calls can be switched to; empty configures none
+	SecondaryBaseURL     string
+	SecondaryGRPCBaseURL string
+	// ActiveEndpoint is the endpoint calls go to at startup: primary or
+	// secondary
+	ActiveEndpoint string
+	// FailoverCheckInterval is how often each endpoint's health is checked;
+	// 0 disables automatic failover
+	FailoverCheckInterval time.Duration
+	// FailoverThreshold is how many consecutive failed checks of the active
+	// endpoint switch calls to a healthy one
+	FailoverThreshold int
 }
 
 type HyperfleetConfig struct {
@@ -58,6 +586,7 @@ type LoggingConfig struct {
 func NewConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
+			Profile:            ProfileAll,
 			APIBindAddress:     "0.0.0.0",
 			APIPort:            8000,
 			GRPCBindAddress:    "0.0.0.0",
@@ -67,11 +596,18 @@ func NewConfig() *Config {
 			MetricsBindAddress: "0.0.0.0",
 			MetricsPort:        9090,
 			ShutdownTimeout:    30 * time.Second,
+			WarmupTimeout:      30 * time.Second,
+			RequestTimeout:     30 * time.Second,
+			AuthzBudget:        5 * time.Second,
 		},
 		Maestro: MaestroConfig{
-			BaseURL:     "http://maestro:8000",
-			GRPCBaseURL: "maestro-grpc.maestro-server:8090",
-			Timeout:     30 * time.Second,
+			BaseURL:               "http://maestro:8000",
+			GRPCBaseURL:           "maestro-grpc.maestro-server:8090",
+			Timeout:               30 * time.Second,
+			VersionCheckInterval:  5 * time.Minute,
+			ActiveEndpoint:        "primary",
+			FailoverCheckInterval: 10 * time.Second,
+			FailoverThreshold:     3,
 		},
 		Hyperfleet: HyperfleetConfig{
 			BaseURL: "http://hyperfleet-api.hyperfleet-system:8000",
@@ -81,9 +617,134 @@ func NewConfig() *Config {
 			Level:  "info",
 			Format: "json",
 		},
+		Identity: IdentityConfig{
+			AccountIDHeader:        "X-Amz-Account-Id",
+			CallerARNHeader:        "X-Amz-Caller-Arn",
+			AuthorizerAccountIDKey: "accountId",
+			AuthorizerCallerARNKey: "callerArn",
+			SharedSecretHeader:     "X-Rosa-Gateway-Secret",
+			ReplayCacheSize:        100000,
+		},
 		Authz: authz.DefaultConfig(),
 		Zoa: ZoaConfig{
 			PollInterval: 15 * time.Second,
 		},
+		MgmtClusters: ManagementClusterConfig{
+			ListCacheTTL:   30 * time.Second,
+			ListCacheStale: 5 * time.Minute,
+			DrainBundles:   true,
+			DrainTimeout:   10 * time.Minute,
+			// Blocking is opt-in, as the matrix can lag behind agent releases
+			AgentCompatibility: clustercaps.CompatibilityWarn,
+		},
+		ResourceBundles: ResourceBundleConfig{
+			SummaryCacheTTL: time.Minute,
+		},
+		Work: WorkConfig{
+			MaxManifests: 500,
+			// AWS IoT Core rejects MQTT messages larger than 128 KiB
+			MaxPayloadBytes:   128 * 1024,
+			MaxMessageBytes:   128 * 1024,
+			MaxChunks:         16,
+			ScheduleInterval:  30 * time.Second,
+			MaxQueued:         100,
+			ClusterRateWindow: time.Minute,
+			MaxChartBytes:     1024 * 1024,
+			ChartPullTimeout:  30 * time.Second,
+			// Referenced payloads exist to exceed the API Gateway body limit
+			MaxPayloadRefBytes:  16 * 1024 * 1024,
+			PayloadFetchTimeout: 30 * time.Second,
+			HistoryInterval:     30 * time.Second,
+			HistoryRetention:    90 * 24 * time.Hour,
+		},
+		Status: StatusConfig{
+			Window:               5 * time.Minute,
+			ProbeTimeout:         2 * time.Second,
+			CacheTTL:             10 * time.Second,
+			ErrorRateThreshold:   0.05,
+			DeliveryLagThreshold: time.Minute,
+		},
+		Canary: CanaryConfig{
+			Namespace: "default",
+			Interval:  5 * time.Minute,
+			Timeout:   2 * time.Minute,
+		},
+		Metering: MeteringConfig{
+			BatchSize:     100,
+			FlushInterval: 5 * time.Second,
+			BufferSize:    10000,
+		},
+		Events: EventsConfig{
+			DefaultTopic: "rosa.platform.events",
+			Source:       events.DefaultSource,
+			Encoding:     events.EncodingJSON,
+			BufferSize:   10000,
+		},
+		PolicyBackup: PolicyBackupConfig{
+			Prefix:    "policy-stores",
+			Interval:  6 * time.Hour,
+			Retention: 30 * 24 * time.Hour,
+		},
+		Operations: OperationsConfig{
+			ResultTTL: time.Hour,
+		},
+		ErrorTracking: ErrorTrackingConfig{
+			MinLevel: "error",
+		},
+		Runtime: RuntimeConfig{
+			MemoryLimitRatio: 0.9,
+		},
+		LeaderElection: LeaderElectionConfig{
+			LeaseDuration: 15 * time.Second,
+		},
+		RateLimit: RateLimitConfig{
+			Window:  10 * time.Second,
+			Backend: ratelimit.BackendLocal,
+		},
+		Plans: PlansConfig{
+			Default:  plans.Standard,
+			CacheTTL: time.Minute,
+		},
+		Cache: CacheConfig{
+			Backend: cache.BackendMemory,
+			Redis: cache.RedisConfig{
+				KeyPrefix: "rosa",
+			},
+		},
+		AuthzStreams: AuthzStreamConfig{
+			PollInterval: 5 * time.Second,
+		},
+		Pagination: PaginationConfig{
+			Tenant:         PageLimits{Default: 50, Max: 100},
+			Platform:       PageLimits{Default: 100, Max: 100},
+			TrustedActions: PageLimits{Default: 20, Max: 100},
+			Audit:          PageLimits{Default: 50, Max: 200},
+		},
+		SlowRequests: SlowRequestConfig{
+			Default:        2 * time.Second,
+			Tenant:         2 * time.Second,
+			Platform:       3 * time.Second,
+			TrustedActions: 5 * time.Second,
+			Authz:          time.Second,
+		},
+		Mirror: MirrorConfig{
+			Percent:     10,
+			Timeout:     10 * time.Second,
+			MaxInFlight: 16,
+		},
+		SLO: SLOConfig{
+			Period:       28 * 24 * time.Hour,
+			Availability: 0.999,
+			Latency:      0.99,
+		},
+		LoadShed: LoadShedConfig{
+			Rules:      loadshed.DefaultRules,
+			QueueLoad:  2,
+			Components: []string{"delivery-canary"},
+		},
+		AccountMetrics: AccountMetricsConfig{
+			TopAccounts:  20,
+			RankInterval: time.Minute,
+		},
 	}
 }
@@ -59,6 +59,58 @@ func TestNewConfig(t *testing.T) {
 		t.Errorf("expected Logging.Format=json, got %s", cfg.Logging.Format)
 	}
 
+	// Test Work limit defaults
+	if cfg.Work.MaxManifests != 500 {
+		t.Errorf("expected Work.MaxManifests=500, got %d", cfg.Work.MaxManifests)
+	}
+
+	if cfg.Work.MaxPayloadBytes != 128*1024 {
+		t.Errorf("expected Work.MaxPayloadBytes=131072, got %d", cfg.Work.MaxPayloadBytes)
+	}
+
+	if cfg.Work.MaxMessageBytes != 128*1024 {
+		t.Errorf("expected Work.MaxMessageBytes=131072, got %d", cfg.Work.MaxMessageBytes)
+	}
+
+	if cfg.Work.MaxChunks != 16 {
+		t.Errorf("expected Work.MaxChunks=16, got %d", cfg.Work.MaxChunks)
+	}
+
+	// Test Status defaults
+	if cfg.Status.Window != 5*time.Minute {
+		t.Errorf("expected Status.Window=5m, got %v", cfg.Status.Window)
+	}
+
+	if cfg.Status.ErrorRateThreshold != 0.05 {
+		t.Errorf("expected Status.ErrorRateThreshold=0.05, got %v", cfg.Status.ErrorRateThreshold)
+	}
+
+	if cfg.Status.DeliveryLagThreshold != time.Minute {
+		t.Errorf("expected Status.DeliveryLagThreshold=1m, got %v", cfg.Status.DeliveryLagThreshold)
+	}
+
+	// Test Identity defaults
+	if cfg.Identity.RequestContext {
+		t.Error("expected Identity.RequestContext=false")
+	}
+
+	if cfg.Identity.AccountIDHeader != "X-Amz-Account-Id" {
+		t.Errorf("expected Identity.AccountIDHeader=X-Amz-Account-Id, got %s", cfg.Identity.AccountIDHeader)
+	}
+
+	// Test PolicyBackup defaults
+	if cfg.PolicyBackup.Bucket != "" {
+		t.Errorf("expected PolicyBackup.Bucket empty, got %q", cfg.PolicyBackup.Bucket)
+	}
+
+	if cfg.PolicyBackup.Interval != 6*time.Hour {
+		t.Errorf("expected PolicyBackup.Interval=6h, got %v", cfg.PolicyBackup.Interval)
+	}
+
+	if cfg.PolicyBackup.Retention != 30*24*time.Hour {
+		t.Errorf("expected PolicyBackup.Retention=720h, got %v", cfg.PolicyBackup.Retention)
+	}
+
 	// Test that AllowedAccounts defaults to empty/nil
 	if len(cfg.AllowedAccounts) != 0 {
 		t.Errorf("expected empty AllowedAccounts, got %d items", len(cfg.AllowedAccounts))
@@ -223,3 +275,28 @@ func TestConfig_CustomValues(t *testing.T) {
 		t.Errorf("expected second account=987654321098, got %s", cfg.AllowedAccounts[1])
 	}
 }
+
+func TestPageLimits_Validate(t *testing.T) {
+	tests := []struct {
+		name      string
+		limits    PageLimits
+		expectErr bool
+	}{
+		{name: "defaults", limits: NewConfig().Pagination.Tenant},
+		{name: "default equals max", limits: PageLimits{Default: 100, Max: 100}},
+		{name: "zero default", limits: PageLimits{Default: 0, Max: 100}, expectErr: true},
+		{name: "max below default", limits: PageLimits{Default: 50, Max: 10}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.limits.Validate()
+			if tt.expectErr && err == nil {
+				t.Error("expected error, got nil")
+			}
+			if !tt.expectErr && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
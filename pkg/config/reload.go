@@ -0,0 +1,44 @@
+package config
+
+// Settings that can be changed by reloading the configuration file without
+// a restart, named after the serve flags that set them
+const (
+	SettingLogLevel                 = "log-level"
+	SettingAllowedAccounts          = "allowed-accounts"
+	SettingRateLimit                = "rate-limit"
+	SettingRateLimitWindow          = "rate-limit-window"
+	SettingManagementClusterTTL     = "management-cluster-list-cache-ttl"
+	SettingResourceBundleSummaryTTL = "resource-bundle-summary-cache-ttl"
+	SettingPlanCacheTTL             = "plan-cache-ttl"
+	SettingMaestroActiveEndpoint    = "maestro-active-endpoint"
+)
+
+// ReloadableSettings lists the settings a reload applies; changes to any
+// other setting are rejected until the next restart
+var ReloadableSettings = []string{
+	SettingLogLevel,
+	SettingAllowedAccounts,
+	SettingRateLimit,
+	SettingRateLimitWindow,
+	SettingManagementClusterTTL,
+	SettingResourceBundleSummaryTTL,
+	SettingPlanCacheTTL,
+	SettingMaestroActiveEndpoint,
+}
+
+// SettingChange is a setting whose value differs from the one in effect
+type SettingChange struct {
+	Setting string `json:"setting"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	// Reason is why a rejected change was not applied
+	Reason string `json:"reason,omitempty"`
+}
+
+// ReloadReport describes what a reload of the configuration file changed
+type ReloadReport struct {
+	// DryRun is set when the changes were only checked
+	DryRun   bool            `json:"dryRun,omitempty"`
+	Applied  []SettingChange `json:"applied"`
+	Rejected []SettingChange `json:"rejected"`
+}
@@ -0,0 +1,410 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
+	"github.com/openshift/rosa-regional-platform-api/pkg/loadshed"
+	"github.com/openshift/rosa-regional-platform-api/pkg/metering"
+	"github.com/openshift/rosa-regional-platform-api/pkg/opensearch"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+	"github.com/openshift/rosa-regional-platform-api/pkg/residency"
+	"github.com/openshift/rosa-regional-platform-api/pkg/secretref"
+	"github.com/openshift/rosa-regional-platform-api/pkg/secretsource"
+)
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// ValidationError lists every problem found in a Config
+type ValidationError struct {
+	Problems []error
+}
+
+func (e *ValidationError) Error() string {
+	noun := "problems"
+	if len(e.Problems) == 1 {
+		noun = "problem"
+	}
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("invalid configuration (%d %s):", len(e.Problems), noun))
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns the problems, so errors.Is and errors.As match any of them
+func (e *ValidationError) Unwrap() []error { return e.Problems }
+
+// validator collects problems
+type validator struct {
+	problems []error
+}
+
+func (v *validator) addf(format string, args ...any) {
+	v.problems = append(v.problems, fmt.Errorf(format, args...))
+}
+
+func (v *validator) check(ok bool, format string, args ...any) {
+	if !ok {
+		v.addf(format, args...)
+	}
+}
+
+// Validate checks the whole configuration and returns a *ValidationError
+// listing every problem found, rather than stopping at the first
+func (c *Config) Validate() error {
+	v := &validator{}
+	c.validateServer(v)
+	c.validateUpstreams(v)
+	c.validateIdentity(v)
+	c.validateAuthz(v)
+	c.validateWork(v)
+	c.validateWorkers(v)
+	c.validateLimits(v)
+	c.validateSecrets(v)
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+func (c *Config) validateServer(v *validator) {
+	s := c.Server
+	switch s.Profile {
+	case ProfileAll, ProfileFrontend, ProfilePlatform:
+	default:
+		v.addf("server: invalid profile %q: must be one of all, frontend, platform", s.Profile)
+	}
+
+	ports := map[string]int{"API": s.APIPort, "health": s.HealthPort, "metrics": s.MetricsPort}
+	seen := map[int]string{}
+	for _, name := range []string{"API", "health", "metrics"} {
+		port := ports[name]
+		if port < 1 || port > 65535 {
+			v.addf("server: %s port %d is out of range 1-65535", name, port)
+			continue
+		}
+		if other, ok := seen[port]; ok {
+			v.addf("server: %s and %s servers both use port %d", other, name, port)
+		}
+		seen[port] = name
+	}
+
+	if s.BasePath != "" && !strings.HasPrefix(s.BasePath, "/") {
+		v.addf("server: base path %q must start with /", s.BasePath)
+	}
+	if s.ExternalURL != "" {
+		u, err := url.Parse(s.ExternalURL)
+		v.check(err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != "" && u.RawQuery == "" && u.Fragment == "",
+			"server: external URL %q must be an absolute http or https URL without a query or fragment", s.ExternalURL)
+	}
+	v.check(residency.ValidateRegion(s.Region) == "", "server: region %q must be an AWS region, such as us-east-2", s.Region)
+	v.check(s.WarmupTimeout >= 0, "server: warm-up timeout must not be negative")
+	v.check(s.RequestTimeout >= 0, "server: request timeout must not be negative")
+	if s.RequestTimeout > 0 && s.AuthzBudget > s.RequestTimeout {
+		v.addf("server: authz timeout budget %s exceeds the request timeout %s", s.AuthzBudget, s.RequestTimeout)
+	}
+}
+
+func (c *Config) validateUpstreams(v *validator) {
+	checkHTTPURL(v, "maestro: base URL", c.Maestro.BaseURL)
+	checkHTTPURL(v, "hyperfleet: base URL", c.Hyperfleet.BaseURL)
+	v.check(c.Maestro.GRPCBaseURL != "", "maestro: gRPC address is required")
+	v.check(c.Maestro.VersionCheckInterval >= 0, "maestro: version check interval must not be negative")
+	if c.Maestro.SecondaryBaseURL != "" {
+		checkHTTPURL(v, "maestro: secondary base URL", c.Maestro.SecondaryBaseURL)
+		v.check(c.Maestro.SecondaryGRPCBaseURL != "", "maestro: secondary gRPC address is required with a secondary base URL")
+		v.check(c.Maestro.FailoverCheckInterval >= 0, "maestro: failover check interval must not be negative")
+		v.check(c.Maestro.FailoverThreshold >= 1, "maestro: failover threshold must be at least 1")
+	} else {
+		v.check(c.Maestro.SecondaryGRPCBaseURL == "", "maestro: secondary gRPC address requires a secondary base URL")
+	}
+	switch c.Maestro.ActiveEndpoint {
+	case "primary":
+	case "secondary":
+		v.check(c.Maestro.SecondaryBaseURL != "", "maestro: active endpoint secondary requires a secondary base URL")
+	default:
+		v.addf("maestro: active endpoint %q must be primary or secondary", c.Maestro.ActiveEndpoint)
+	}
+}
+
+// checkHTTPURL adds a problem unless raw is an absolute http or https URL
+func checkHTTPURL(v *validator, name, raw string) {
+	u, err := url.ParseRequestURI(raw)
+	if err != nil {
+		v.addf("%s %q is not a valid URL: %v", name, raw, err)
+		return
+	}
+	v.check(u.Scheme == "http" || u.Scheme == "https", "%s %q must have an http or https scheme", name, raw)
+	v.check(u.Host != "", "%s %q must have a host", name, raw)
+}
+
+func (c *Config) validateIdentity(v *validator) {
+	id := c.Identity
+	v.check(id.AccountIDHeader != "", "identity: account ID header is required")
+	if id.ReplayWindow > 0 && id.ReplayCacheSize <= 0 {
+		v.addf("identity: replay cache size must be positive when replay protection is enabled")
+	}
+	for _, proxy := range id.TrustedProxies {
+		proxy = strings.TrimSpace(proxy)
+		if proxy == "" {
+			continue
+		}
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		v.check(cidrErr == nil || net.ParseIP(proxy) != nil, "identity: trusted proxy %q is not an IP address or CIDR", proxy)
+	}
+	if c.Server.Dev && len(id.TrustedProxies) > 0 {
+		v.addf("identity: dev mode cannot be combined with trusted proxies")
+	}
+	if c.Capture.File == "" && (len(c.Capture.Accounts) > 0 || len(c.Capture.BodyAccounts) > 0) {
+		v.addf("capture: capture accounts require a capture file")
+	}
+	if m := c.Mirror; m.Target != "" {
+		checkHTTPURL(v, "mirror: target", m.Target)
+		v.check(m.Percent > 0 && m.Percent <= 100, "mirror: percent %v must be above 0 and at most 100", m.Percent)
+		v.check(m.Timeout > 0, "mirror: timeout must be positive")
+		v.check(m.MaxInFlight >= 1, "mirror: max in-flight requests must be at least 1")
+	}
+}
+
+func (c *Config) validateAuthz(v *validator) {
+	a := c.Authz
+	if a == nil {
+		v.addf("authz: configuration is missing")
+		return
+	}
+	switch a.DegradedMode {
+	case authz.DegradedDenyAll, authz.DegradedReadOnly:
+	default:
+		v.addf("authz: invalid degraded mode %q: must be one of deny-all, read-only", a.DegradedMode)
+	}
+	for _, op := range a.ApprovalRequired {
+		v.check(slices.Contains(authz.ApprovalOperations, op),
+			"authz: invalid approval-required operation %q: must be one of %s", op, strings.Join(authz.ApprovalOperations, ", "))
+	}
+	if err := schema.Namespace(a.CedarNamespace).Validate(); err != nil {
+		v.addf("authz: %v", err)
+	}
+	v.check(a.MaxGroupMembers >= 0, "authz: max group members must not be negative")
+	for _, account := range a.MemberPartnerAccounts {
+		v.check(accountIDPattern.MatchString(account), "authz: invalid member partner account %q: must be a 12-digit account ID", account)
+	}
+	if a.PrincipalPatterns {
+		v.check(a.PatternMatchInterval > 0, "authz: pattern match interval must be positive")
+	}
+	v.check(a.APIKeyCacheTTL >= 0, "authz: API key cache TTL must not be negative")
+	if a.APIKeyCacheTTL > 0 {
+		v.check(a.APIKeyRevocationRefresh > 0, "authz: API key revocation refresh must be positive when API keys are cached")
+	}
+	v.check(a.OnboardingWorkers >= 0, "authz: onboarding workers must not be negative")
+	if a.OnboardingWorkers > 0 {
+		v.check(a.OnboardingQueueSize > 0, "authz: onboarding queue size must be positive when onboarding workers run")
+		v.check(a.OnboardingRate > 0, "authz: onboarding rate must be positive when onboarding workers run")
+	}
+	if _, err := authz.ParseBypassRules(a.Bypass); err != nil {
+		v.addf("authz: %v", err)
+	}
+	if a.ShadowMode {
+		v.check(a.Enabled, "authz: shadow mode needs authz enabled")
+		v.check(len(c.AllowedAccounts) > 0, "authz: shadow mode enforces the allowed accounts, which are empty")
+	}
+	if a.DecisionAnalytics || a.DecisionAudit {
+		v.check(a.DecisionFlushInterval > 0, "authz: decision flush interval must be positive")
+	}
+	if a.DecisionAudit {
+		v.check(a.DecisionAuditRetention > 0, "authz: decision audit retention must be positive")
+	}
+	v.check(a.AuditQueryRateLimit >= 0, "authz: audit query rate limit must not be negative")
+	if a.AuditOpenSearchEndpoint != "" {
+		v.check(a.DecisionAudit, "authz: the OpenSearch audit endpoint requires the decision audit log")
+		checkHTTPURL(v, "authz: OpenSearch audit endpoint", a.AuditOpenSearchEndpoint)
+		v.check(a.AuditOpenSearchIndex != "", "authz: OpenSearch audit index is required with an OpenSearch audit endpoint")
+		v.check(slices.Contains(opensearch.Rollovers, a.AuditOpenSearchRollover),
+			"authz: invalid OpenSearch audit rollover %q: must be one of %s", a.AuditOpenSearchRollover, strings.Join(opensearch.Rollovers, ", "))
+		switch a.AuditOpenSearchService {
+		case "", "es", "aoss":
+		default:
+			v.addf("authz: invalid OpenSearch audit AWS service %q: must be es, aoss or empty", a.AuditOpenSearchService)
+		}
+	}
+	if c.AccountPurge.SigningKeyID != "" {
+		v.check(a.Enabled, "account purge: requires authz to be enabled")
+	}
+	if c.AuthzStreams.Enabled {
+		v.check(c.AuthzStreams.PollInterval > 0, "authz: streams poll interval must be positive")
+	}
+	if !a.Enabled {
+		return
+	}
+
+	v.check(a.AWSRegion != "", "authz: AWS region is required when authz is enabled")
+	for _, table := range []struct{ name, value string }{
+		{"accounts", a.AccountsTableName},
+		{"admins", a.AdminsTableName},
+		{"groups", a.GroupsTableName},
+		{"group members", a.MembersTableName},
+		{"delegations", a.DelegationsTableName},
+		{"API keys", a.APIKeysTableName},
+		{"organizations", a.OrganizationsTableName},
+		{"attachments", a.AttachmentsTableName},
+		{"deletions", a.DeletionsTableName},
+		{"pending changes", a.PendingChangesTableName},
+		{"change requests", a.ChangeRequestsTableName},
+		{"decision counts", a.DecisionCountsTableName},
+		{"policy tags", a.PolicyTagsTableName},
+		{"decision audit", a.DecisionAuditTableName},
+	} {
+		v.check(table.value != "", "authz: %s table name is required when authz is enabled", table.name)
+	}
+	if a.CedarAgentEndpoint != "" {
+		checkHTTPURL(v, "authz: cedar-agent endpoint", a.CedarAgentEndpoint)
+	}
+	if a.DynamoDBEndpoint != "" {
+		checkHTTPURL(v, "authz: DynamoDB endpoint", a.DynamoDBEndpoint)
+	}
+}
+
+func (c *Config) validateWork(v *validator) {
+	w := c.Work
+	if _, err := secretref.ParsePlatformPrefixes(w.SecretRefsPlatformPrefixes); err != nil {
+		v.addf("work: %v", err)
+	}
+	if len(w.PayloadSigners) > 0 && w.PayloadTrustedRoot == "" {
+		v.addf("work: payload signers require a trusted root")
+	}
+	if w.SchedulesTableName != "" {
+		v.check(w.ScheduleInterval > 0, "work: schedule interval must be positive")
+	}
+	if w.HistoryTableName != "" {
+		v.check(w.MetadataTableName != "", "work: status history requires the metadata store")
+		v.check(w.HistoryInterval > 0, "work: status history interval must be positive")
+		v.check(w.HistoryRetention >= 0, "work: status history retention must not be negative")
+	}
+	if w.ClusterRateLimit < 0 || (w.ClusterRateLimit > 0 && w.ClusterRateWindow <= 0) {
+		v.addf("work: invalid cluster rate limit %d per %s: the limit must not be negative and the window must be positive", w.ClusterRateLimit, w.ClusterRateWindow)
+	}
+	v.check(slices.Contains(clustercaps.CompatibilityModes, c.MgmtClusters.AgentCompatibility),
+		"work: invalid agent compatibility %q: must be one of %s", c.MgmtClusters.AgentCompatibility, strings.Join(clustercaps.CompatibilityModes, ", "))
+}
+
+func (c *Config) validateWorkers(v *validator) {
+	if c.Canary.ClusterName != "" {
+		v.check(c.Canary.Interval > 0 && c.Canary.Timeout > 0, "canary: interval and timeout must be positive")
+	}
+
+	m := c.Metering
+	switch m.Sink {
+	case "":
+	case metering.SinkFile, metering.SinkSQS, metering.SinkKinesis:
+		v.check(m.Target != "", "metering: a target is required with the %s sink", m.Sink)
+		v.check(m.BatchSize > 0 && m.FlushInterval > 0 && m.BufferSize > 0,
+			"metering: batch size, flush interval and buffer size must be positive")
+	default:
+		v.addf("metering: invalid sink %q: must be one of file, sqs, kinesis", m.Sink)
+	}
+
+	if e := c.Events; len(e.KafkaBrokers) > 0 {
+		topics, err := events.ParseTopics(e.Topics, e.DefaultTopic)
+		if err != nil {
+			v.addf("events: %v", err)
+		} else {
+			v.check(len(topics.Topics()) > 0, "events: topics or a default topic are required with Kafka brokers")
+		}
+		v.check(e.Source != "", "events: source is required with Kafka brokers")
+		v.check(slices.Contains(events.Encodings, e.Encoding),
+			"events: invalid encoding %q: must be one of %s", e.Encoding, strings.Join(events.Encodings, ", "))
+		if e.SchemaRegistryURL != "" {
+			checkHTTPURL(v, "events: schema registry URL", e.SchemaRegistryURL)
+		}
+		v.check(e.Encoding != events.EncodingAvro || e.SchemaRegistryURL != "", "events: avro encoding requires a schema registry URL")
+		v.check(e.BufferSize > 0, "events: buffer size must be positive")
+	}
+
+	v.check(c.Operations.ResultTTL >= 0, "operations: result TTL must not be negative")
+
+	if c.PolicyBackup.Bucket != "" {
+		v.check(c.PolicyBackup.Interval > 0, "policy backup: interval must be positive")
+	}
+
+	if c.LeaderElection.Enabled {
+		v.check(c.LeaderElection.Identity != "", "leader election: replica identity is required")
+		v.check(c.LeaderElection.LeaseDuration > 0, "leader election: lease duration must be positive")
+	}
+
+	if c.Zoa.Enabled {
+		v.check(c.Zoa.TableName != "", "zoa: table name is required when trusted actions are enabled")
+		v.check(c.Zoa.TemplatesDir != "", "zoa: templates directory is required when trusted actions are enabled")
+	}
+}
+
+func (c *Config) validateLimits(v *validator) {
+	for _, page := range []struct {
+		class  string
+		limits PageLimits
+	}{
+		{"tenant", c.Pagination.Tenant},
+		{"platform", c.Pagination.Platform},
+		{"trusted-action", c.Pagination.TrustedActions},
+		{"audit", c.Pagination.Audit},
+	} {
+		if err := page.limits.Validate(); err != nil {
+			v.addf("pagination: invalid %s page size: %v", page.class, err)
+		}
+	}
+
+	if _, err := loadshed.ParseRules(c.LoadShed.Rules); err != nil {
+		v.addf("load shedding: %v", err)
+	}
+	v.check(c.LoadShed.QueueLoad >= 0, "load shedding: queue load must not be negative")
+
+	v.check(c.SLO.Period >= time.Hour, "slo: period must be at least 1h, got %s", c.SLO.Period)
+	v.check(c.SLO.Availability > 0 && c.SLO.Availability < 1, "slo: availability objective must be between 0 and 1, got %v", c.SLO.Availability)
+	v.check(c.SLO.Latency > 0 && c.SLO.Latency < 1, "slo: latency objective must be between 0 and 1, got %v", c.SLO.Latency)
+
+	v.check(c.AccountMetrics.TopAccounts >= 0, "account metrics: top accounts must not be negative")
+	v.check(c.AccountMetrics.RankInterval > 0, "account metrics: rank interval must be positive")
+
+	rl := c.RateLimit
+	if rl.Limit < 0 || rl.Window <= 0 {
+		v.addf("rate limit: invalid rate limit %d per %s: the limit must not be negative and the window must be positive", rl.Limit, rl.Window)
+	}
+	switch rl.Backend {
+	case ratelimit.BackendLocal, ratelimit.BackendDynamoDB:
+	default:
+		v.addf("rate limit: invalid backend %q: must be one of local, dynamodb", rl.Backend)
+	}
+	v.check(plans.Valid(c.Plans.Default), "plans: invalid default plan %q: must be one of %s", c.Plans.Default, strings.Join(plans.Names, ", "))
+
+	switch c.Cache.Backend {
+	case cache.BackendMemory:
+	case cache.BackendRedis:
+		v.check(len(c.Cache.Redis.Addrs) > 0, "cache: redis addresses are required with the redis backend")
+	default:
+		v.addf("cache: invalid backend %q: must be one of memory, redis", c.Cache.Backend)
+	}
+}
+
+func (c *Config) validateSecrets(v *validator) {
+	for _, secret := range []struct{ name, value string }{
+		{"identity shared secret", c.Identity.SharedSecret},
+		{"redis password", c.Cache.Redis.Password},
+		{"sentry DSN", c.ErrorTracking.DSN},
+	} {
+		if err := secretsource.Validate(secret.value); err != nil {
+			v.addf("secrets: %s: %v", secret.name, err)
+		}
+	}
+	v.check(c.Secrets.RefreshInterval >= 0, "secrets: refresh interval must not be negative")
+}
@@ -0,0 +1,274 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidate_Defaults(t *testing.T) {
+	if err := NewConfig().Validate(); err != nil {
+		t.Fatalf("expected the default config to be valid, got: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mutate  func(*Config)
+		problem string
+	}{
+		{
+			name:    "port out of range",
+			mutate:  func(c *Config) { c.Server.APIPort = 70000 },
+			problem: "API port 70000 is out of range",
+		},
+		{
+			name:    "ports clash",
+			mutate:  func(c *Config) { c.Server.MetricsPort = c.Server.HealthPort },
+			problem: "health and metrics servers both use port 8080",
+		},
+		{
+			name:    "negative warm-up timeout",
+			mutate:  func(c *Config) { c.Server.WarmupTimeout = -time.Second },
+			problem: "warm-up timeout must not be negative",
+		},
+		{
+			name:    "invalid profile",
+			mutate:  func(c *Config) { c.Server.Profile = "edge" },
+			problem: `invalid profile "edge"`,
+		},
+		{
+			name:    "relative external URL",
+			mutate:  func(c *Config) { c.Server.ExternalURL = "api.example.com" },
+			problem: "external URL",
+		},
+		{
+			name:    "region that is not an AWS region",
+			mutate:  func(c *Config) { c.Server.Region = "US East" },
+			problem: `region "US East" must be an AWS region`,
+		},
+		{
+			name:    "authz budget above the request timeout",
+			mutate:  func(c *Config) { c.Server.AuthzBudget = time.Minute },
+			problem: "authz timeout budget 1m0s exceeds the request timeout",
+		},
+		{
+			name:    "malformed maestro URL",
+			mutate:  func(c *Config) { c.Maestro.BaseURL = "maestro:8000" },
+			problem: "maestro: base URL",
+		},
+		{
+			name:    "secondary maestro endpoint active without a secondary",
+			mutate:  func(c *Config) { c.Maestro.ActiveEndpoint = "secondary" },
+			problem: "active endpoint secondary requires a secondary base URL",
+		},
+		{
+			name: "secondary maestro without a gRPC address",
+			mutate: func(c *Config) {
+				c.Maestro.SecondaryBaseURL = "http://maestro-green:8000"
+			},
+			problem: "secondary gRPC address is required",
+		},
+		{
+			name: "mirroring every request and more",
+			mutate: func(c *Config) {
+				c.Mirror.Target = "http://api-canary:8000"
+				c.Mirror.Percent = 150
+			},
+			problem: "mirror: percent 150 must be above 0 and at most 100",
+		},
+		{
+			name:    "malformed trusted proxy",
+			mutate:  func(c *Config) { c.Identity.TrustedProxies = []string{"10.0.0.0/33"} },
+			problem: `trusted proxy "10.0.0.0/33"`,
+		},
+		{
+			name:    "missing authz table while enabled",
+			mutate:  func(c *Config) { c.Authz.GroupsTableName = "" },
+			problem: "groups table name is required",
+		},
+		{
+			name:    "invalid approval operation",
+			mutate:  func(c *Config) { c.Authz.ApprovalRequired = []string{"launch-missiles"} },
+			problem: `invalid approval-required operation "launch-missiles"`,
+		},
+		{
+			name:    "invalid member partner account",
+			mutate:  func(c *Config) { c.Authz.MemberPartnerAccounts = []string{"12345"} },
+			problem: `invalid member partner account "12345"`,
+		},
+		{
+			name: "principal patterns without a match interval",
+			mutate: func(c *Config) {
+				c.Authz.PrincipalPatterns = true
+				c.Authz.PatternMatchInterval = 0
+			},
+			problem: "pattern match interval must be positive",
+		},
+		{
+			name:    "API key cache without a revocation refresh",
+			mutate:  func(c *Config) { c.Authz.APIKeyRevocationRefresh = 0 },
+			problem: "API key revocation refresh must be positive",
+		},
+		{
+			name:    "bypass rule with an invalid method",
+			mutate:  func(c *Config) { c.Authz.Bypass = []string{"FETCH /api/v0/work"} },
+			problem: "invalid method FETCH",
+		},
+		{
+			name: "platform secret prefix covering a whole account",
+			mutate: func(c *Config) {
+				c.Work.SecretRefsPlatformPrefixes = []string{"arn:aws:ssm:us-east-1:111122223333:parameter/"}
+			},
+			problem: "must name a secret or parameter path",
+		},
+		{
+			name:    "shadow mode without allowed accounts",
+			mutate:  func(c *Config) { c.Authz.ShadowMode = true },
+			problem: "shadow mode enforces the allowed accounts",
+		},
+		{
+			name: "account purge without authz",
+			mutate: func(c *Config) {
+				c.Authz.Enabled = false
+				c.AccountPurge.SigningKeyID = "alias/purge-reports"
+			},
+			problem: "account purge: requires authz to be enabled",
+		},
+		{
+			name:    "onboarding workers without a rate",
+			mutate:  func(c *Config) { c.Authz.OnboardingRate = 0 },
+			problem: "onboarding rate must be positive",
+		},
+		{
+			name: "decision audit without a retention",
+			mutate: func(c *Config) {
+				c.Authz.DecisionAudit = true
+				c.Authz.DecisionAuditRetention = 0
+			},
+			problem: "decision audit retention must be positive",
+		},
+		{
+			name: "OpenSearch audit endpoint with an unknown rollover",
+			mutate: func(c *Config) {
+				c.Authz.DecisionAudit = true
+				c.Authz.AuditOpenSearchEndpoint = "https://search.example.com"
+				c.Authz.AuditOpenSearchRollover = "weekly"
+			},
+			problem: `invalid OpenSearch audit rollover "weekly"`,
+		},
+		{
+			name:    "metering sink without a target",
+			mutate:  func(c *Config) { c.Metering.Sink = "kinesis" },
+			problem: "a target is required with the kinesis sink",
+		},
+		{
+			name: "avro events without a schema registry",
+			mutate: func(c *Config) {
+				c.Events.KafkaBrokers = []string{"b-1.msk.example.com:9098"}
+				c.Events.Encoding = "avro"
+			},
+			problem: "avro encoding requires a schema registry URL",
+		},
+		{
+			name: "events without a source",
+			mutate: func(c *Config) {
+				c.Events.KafkaBrokers = []string{"b-1.msk.example.com:9098"}
+				c.Events.Source = ""
+			},
+			problem: "source is required",
+		},
+		{
+			name:    "load shedding rule with an unknown priority",
+			mutate:  func(c *Config) { c.LoadShed.Rules = "GET /api/v0/work=urgent" },
+			problem: "invalid load shedding rule",
+		},
+		{
+			name:    "cluster rate limit without a window",
+			mutate:  func(c *Config) { c.Work.ClusterRateLimit = 10; c.Work.ClusterRateWindow = 0 },
+			problem: "invalid cluster rate limit",
+		},
+		{
+			name:    "status history without the metadata store",
+			mutate:  func(c *Config) { c.Work.HistoryTableName = "rosa-work-history" },
+			problem: "status history requires the metadata store",
+		},
+		{
+			name:    "unknown agent compatibility mode",
+			mutate:  func(c *Config) { c.MgmtClusters.AgentCompatibility = "strict" },
+			problem: `invalid agent compatibility "strict"`,
+		},
+		{
+			name:    "slo objective of 100%",
+			mutate:  func(c *Config) { c.SLO.Availability = 1 },
+			problem: "availability objective must be between 0 and 1",
+		},
+		{
+			name:    "redis cache without addresses",
+			mutate:  func(c *Config) { c.Cache.Backend = "redis" },
+			problem: "redis addresses are required",
+		},
+		{
+			name:    "invalid page limits",
+			mutate:  func(c *Config) { c.Pagination.Audit = PageLimits{Default: 10, Max: 5} },
+			problem: "invalid audit page size",
+		},
+		{
+			name:    "negative operation result TTL",
+			mutate:  func(c *Config) { c.Operations.ResultTTL = -time.Minute },
+			problem: "operations: result TTL must not be negative",
+		},
+		{
+			name:    "invalid default plan",
+			mutate:  func(c *Config) { c.Plans.Default = "gold" },
+			problem: `invalid default plan "gold"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := NewConfig()
+			cfg.Server.RequestTimeout = 30 * time.Second
+			tt.mutate(cfg)
+
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("expected a validation error")
+			}
+			if !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("expected problem %q, got: %v", tt.problem, err)
+			}
+		})
+	}
+}
+
+func TestValidate_AggregatesProblems(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Server.APIPort = 0
+	cfg.Hyperfleet.BaseURL = "ftp://hyperfleet"
+	cfg.RateLimit.Backend = "memcached"
+
+	err := cfg.Validate()
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("expected a *ValidationError, got %v", err)
+	}
+	if len(invalid.Problems) != 3 {
+		t.Errorf("expected 3 problems, got %d: %v", len(invalid.Problems), err)
+	}
+	if !strings.HasPrefix(err.Error(), "invalid configuration (3 problems):") {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestValidate_AuthzDisabledSkipsTables(t *testing.T) {
+	cfg := NewConfig()
+	cfg.Authz.Enabled = false
+	cfg.Authz.AccountsTableName = ""
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected no error with authz disabled, got: %v", err)
+	}
+}
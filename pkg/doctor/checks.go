@@ -0,0 +1,159 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+)
+
+// CanaryKey is the key of the item the DynamoDB check writes, reads back and
+// deletes. It is not an account ID, so nothing mistakes it for one.
+const CanaryKey = "rosa-doctor-canary"
+
+// DynamoDBCanary writes a canary item to the table keyed by keyAttribute,
+// reads it back with a consistent read and deletes it
+func DynamoDBCanary(tableName, keyAttribute string, dynamoClient client.DynamoDBClient) Check {
+	return Check{
+		Name: "dynamodb",
+		Run: func(ctx context.Context) (string, error) {
+			nonce := uuid.NewString()
+			key := map[string]types.AttributeValue{
+				keyAttribute: &types.AttributeValueMemberS{Value: CanaryKey},
+			}
+			item := map[string]types.AttributeValue{
+				keyAttribute: &types.AttributeValueMemberS{Value: CanaryKey},
+				"nonce":      &types.AttributeValueMemberS{Value: nonce},
+				"writtenAt":  &types.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+			}
+
+			if _, err := dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+				TableName: aws.String(tableName),
+				Item:      item,
+			}); err != nil {
+				return "", fmt.Errorf("failed to write canary to %s: %w", tableName, err)
+			}
+			// Leave no canary behind, even if reading it back fails
+			defer func() {
+				_, _ = dynamoClient.DeleteItem(context.WithoutCancel(ctx), &dynamodb.DeleteItemInput{
+					TableName: aws.String(tableName),
+					Key:       key,
+				})
+			}()
+
+			out, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+				TableName:      aws.String(tableName),
+				Key:            key,
+				ConsistentRead: aws.Bool(true),
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to read canary from %s: %w", tableName, err)
+			}
+			got, _ := out.Item["nonce"].(*types.AttributeValueMemberS)
+			if got == nil || got.Value != nonce {
+				return "", fmt.Errorf("canary read back from %s does not match what was written", tableName)
+			}
+			return fmt.Sprintf("wrote and read back a canary in %s", tableName), nil
+		},
+	}
+}
+
+// PolicyStore looks up a policy store in Amazon Verified Permissions: the one
+// given, or else the first one found among the accounts. It is skipped when
+// there is none to look up.
+func PolicyStore(policyStoreID string, accounts *store.AccountStore, avpClient client.AVPClient) Check {
+	return Check{
+		Name: "avp",
+		Run: func(ctx context.Context) (string, error) {
+			if policyStoreID == "" && accounts != nil {
+				page, err := accounts.ListPage(ctx, 10, "", store.AccountFilter{})
+				if err != nil {
+					return "", fmt.Errorf("failed to find a policy store: %w", err)
+				}
+				for _, account := range page.Accounts {
+					if account.PolicyStoreID != "" {
+						policyStoreID = account.PolicyStoreID
+						break
+					}
+				}
+			}
+			if policyStoreID == "" {
+				return "", Skipped("no policy store to look up")
+			}
+
+			out, err := avpClient.GetPolicyStore(ctx, &verifiedpermissions.GetPolicyStoreInput{
+				PolicyStoreId: aws.String(policyStoreID),
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to get policy store %s: %w", policyStoreID, err)
+			}
+			mode := "unknown"
+			if out.ValidationSettings != nil {
+				mode = string(out.ValidationSettings.Mode)
+			}
+			return fmt.Sprintf("policy store %s (validation %s)", policyStoreID, mode), nil
+		},
+	}
+}
+
+// Maestro lists consumers through the Maestro REST API
+func Maestro(maestroClient maestro.ClientInterface) Check {
+	return Check{
+		Name: "maestro",
+		Run: func(ctx context.Context) (string, error) {
+			list, err := maestroClient.ListConsumers(ctx, 1, 1)
+			if err != nil {
+				return "", fmt.Errorf("failed to list consumers: %w", err)
+			}
+			return fmt.Sprintf("%d management clusters", list.Total), nil
+		},
+	}
+}
+
+// GRPC connects to the Maestro gRPC server ManifestWorks are published to,
+// waiting until the connection is ready
+func GRPC(grpcURL string) Check {
+	return Check{
+		Name: "maestro-grpc",
+		Run: func(ctx context.Context) (string, error) {
+			// Accept the URL in any of the forms the Maestro client does
+			target := grpcURL
+			if u, err := url.Parse(grpcURL); err == nil && u.Host != "" {
+				target = u.Host
+			}
+			if target == "" {
+				return "", Skipped("no Maestro gRPC URL configured")
+			}
+
+			conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+			if err != nil {
+				return "", fmt.Errorf("invalid gRPC target %s: %w", target, err)
+			}
+			defer func() { _ = conn.Close() }()
+
+			conn.Connect()
+			for {
+				state := conn.GetState()
+				if state == connectivity.Ready {
+					return fmt.Sprintf("connected to %s", target), nil
+				}
+				if !conn.WaitForStateChange(ctx, state) {
+					return "", fmt.Errorf("failed to connect to %s: still %s", target, state)
+				}
+			}
+		},
+	}
+}
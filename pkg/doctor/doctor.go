@@ -0,0 +1,120 @@
+// Package doctor exercises each dependency of the API with real calls and
+// reports which of them work, for operators diagnosing a misbehaving region.
+// Unlike the status probes, which only prove a dependency answers, checks
+// write and read back data and connect the transports the API relies on.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Check outcomes
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+	StatusSkip = "skip"
+)
+
+// Check exercises one dependency. Run returns a short description of what it
+// found, or an error; an error wrapping ErrSkipped marks the check as not
+// applicable rather than failed.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// ErrSkipped is wrapped by the errors of checks that do not apply to the
+// configuration being checked
+var ErrSkipped = errors.New("skipped")
+
+// Skipped returns an error marking a check as skipped for reason
+func Skipped(reason string) error {
+	return fmt.Errorf("%w: %s", ErrSkipped, reason)
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Name     string
+	Status   string
+	Detail   string
+	Duration time.Duration
+}
+
+// Run runs checks concurrently, each bounded by timeout, and returns their
+// results in the order of checks
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = run(ctx, check, timeout)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	result := Result{Name: check.Name, Status: StatusOK, Detail: detail, Duration: time.Since(start)}
+	switch {
+	case errors.Is(err, ErrSkipped):
+		result.Status = StatusSkip
+		result.Detail = strings.TrimPrefix(err.Error(), ErrSkipped.Error()+": ")
+	case err != nil:
+		result.Status = StatusFail
+		result.Detail = err.Error()
+	}
+	return result
+}
+
+// Failed reports whether any check failed
+func Failed(results []Result) bool {
+	for _, r := range results {
+		if r.Status == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// ANSI colors of each status in the report
+var statusColors = map[string]string{
+	StatusOK:   "\033[32m",
+	StatusFail: "\033[31m",
+	StatusSkip: "\033[33m",
+}
+
+const colorReset = "\033[0m"
+
+// Report writes one line per result, status first, colored when color is set,
+// followed by a summary line
+func Report(w io.Writer, results []Result, color bool) {
+	width := 0
+	for _, r := range results {
+		width = max(width, len(r.Name))
+	}
+
+	counts := map[string]int{}
+	for _, r := range results {
+		counts[r.Status]++
+		status := fmt.Sprintf("%-4s", strings.ToUpper(r.Status))
+		if color {
+			status = statusColors[r.Status] + status + colorReset
+		}
+		fmt.Fprintf(w, "[%s] %-*s  %-40s  %s\n", status, width, r.Name, r.Detail, r.Duration.Round(time.Millisecond))
+	}
+	fmt.Fprintf(w, "\n%d ok, %d failed, %d skipped\n", counts[StatusOK], counts[StatusFail], counts[StatusSkip])
+}
@@ -0,0 +1,196 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// fakeDynamo is an in-memory table keyed by the string attribute "accountId"
+type fakeDynamo struct {
+	client.DynamoDBClient
+	mu       sync.Mutex
+	items    map[string]map[string]types.AttributeValue
+	putErr   error
+	corrupt  bool
+	deleted  int
+	canaries int
+}
+
+func (f *fakeDynamo) key(key map[string]types.AttributeValue) string {
+	return key["accountId"].(*types.AttributeValueMemberS).Value
+}
+
+func (f *fakeDynamo) PutItem(ctx context.Context, in *dynamodb.PutItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.putErr != nil {
+		return nil, f.putErr
+	}
+	f.items[f.key(in.Item)] = in.Item
+	f.canaries++
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeDynamo) GetItem(ctx context.Context, in *dynamodb.GetItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	item := f.items[f.key(in.Key)]
+	if f.corrupt {
+		item = map[string]types.AttributeValue{"nonce": &types.AttributeValueMemberS{Value: "other"}}
+	}
+	return &dynamodb.GetItemOutput{Item: item}, nil
+}
+
+func (f *fakeDynamo) DeleteItem(ctx context.Context, in *dynamodb.DeleteItemInput, _ ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.items, f.key(in.Key))
+	f.deleted++
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// fakeAVP answers GetPolicyStore for the stores it knows
+type fakeAVP struct {
+	client.AVPClient
+	stores map[string]bool
+}
+
+func (f *fakeAVP) GetPolicyStore(ctx context.Context, in *verifiedpermissions.GetPolicyStoreInput, _ ...func(*verifiedpermissions.Options)) (*verifiedpermissions.GetPolicyStoreOutput, error) {
+	if !f.stores[*in.PolicyStoreId] {
+		return nil, &avptypes.ResourceNotFoundException{}
+	}
+	return &verifiedpermissions.GetPolicyStoreOutput{
+		PolicyStoreId:      in.PolicyStoreId,
+		ValidationSettings: &avptypes.ValidationSettings{Mode: avptypes.ValidationModeStrict},
+	}, nil
+}
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "good", Run: func(ctx context.Context) (string, error) { return "fine", nil }},
+		{Name: "bad", Run: func(ctx context.Context) (string, error) { return "", errors.New("broken") }},
+		{Name: "na", Run: func(ctx context.Context) (string, error) { return "", Skipped("not configured") }},
+		{Name: "slow", Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+	}
+
+	results := Run(context.Background(), checks, 10*time.Millisecond)
+
+	want := []struct{ name, status, detail string }{
+		{"good", StatusOK, "fine"},
+		{"bad", StatusFail, "broken"},
+		{"na", StatusSkip, "not configured"},
+		{"slow", StatusFail, context.DeadlineExceeded.Error()},
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Name != w.name || r.Status != w.status || r.Detail != w.detail {
+			t.Errorf("result %d: expected %s %s %q, got %s %s %q", i, w.name, w.status, w.detail, r.Name, r.Status, r.Detail)
+		}
+	}
+	if !Failed(results) {
+		t.Error("expected results to have failed")
+	}
+	if Failed(results[:1]) {
+		t.Error("expected a passing check not to fail")
+	}
+}
+
+func TestReport(t *testing.T) {
+	results := []Result{
+		{Name: "maestro", Status: StatusOK, Detail: "3 management clusters"},
+		{Name: "avp", Status: StatusFail, Detail: "denied"},
+		{Name: "maestro-grpc", Status: StatusSkip, Detail: "no URL"},
+	}
+
+	var plain bytes.Buffer
+	Report(&plain, results, false)
+	out := plain.String()
+	for _, want := range []string{"[OK  ] maestro", "[FAIL] avp", "[SKIP] maestro-grpc", "1 ok, 1 failed, 1 skipped"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected report to contain %q, got:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "\033[") {
+		t.Error("expected no color codes in a plain report")
+	}
+
+	var colored bytes.Buffer
+	Report(&colored, results, true)
+	if !strings.Contains(colored.String(), "\033[31mFAIL\033[0m") {
+		t.Errorf("expected failures in red, got:\n%s", colored.String())
+	}
+}
+
+func TestDynamoDBCanary(t *testing.T) {
+	t.Run("writes, reads back and deletes", func(t *testing.T) {
+		db := &fakeDynamo{items: map[string]map[string]types.AttributeValue{}}
+		if _, err := DynamoDBCanary("accounts", "accountId", db).Run(context.Background()); err != nil {
+			t.Fatalf("expected check to pass, got %v", err)
+		}
+		if db.canaries != 1 || db.deleted != 1 || len(db.items) != 0 {
+			t.Errorf("expected the canary to be written and deleted, got %d writes, %d deletes, %d items", db.canaries, db.deleted, len(db.items))
+		}
+	})
+
+	t.Run("mismatched read fails and still deletes", func(t *testing.T) {
+		db := &fakeDynamo{items: map[string]map[string]types.AttributeValue{}, corrupt: true}
+		if _, err := DynamoDBCanary("accounts", "accountId", db).Run(context.Background()); err == nil {
+			t.Fatal("expected check to fail")
+		}
+		if db.deleted != 1 {
+			t.Error("expected the canary to be deleted")
+		}
+	})
+
+	t.Run("write failure", func(t *testing.T) {
+		db := &fakeDynamo{items: map[string]map[string]types.AttributeValue{}, putErr: errors.New("access denied")}
+		_, err := DynamoDBCanary("accounts", "accountId", db).Run(context.Background())
+		if err == nil || !strings.Contains(err.Error(), "access denied") {
+			t.Fatalf("expected write error, got %v", err)
+		}
+	})
+}
+
+func TestPolicyStore(t *testing.T) {
+	avp := &fakeAVP{stores: map[string]bool{"ps-1": true}}
+
+	detail, err := PolicyStore("ps-1", nil, avp).Run(context.Background())
+	if err != nil || !strings.Contains(detail, "ps-1") || !strings.Contains(detail, "STRICT") {
+		t.Errorf("expected ps-1 to be found, got %q, %v", detail, err)
+	}
+
+	if _, err := PolicyStore("ps-missing", nil, avp).Run(context.Background()); err == nil {
+		t.Error("expected a missing policy store to fail")
+	}
+
+	if _, err := PolicyStore("", nil, avp).Run(context.Background()); !errors.Is(err, ErrSkipped) {
+		t.Errorf("expected the check to be skipped without a policy store, got %v", err)
+	}
+}
+
+func TestGRPC_Unreachable(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	if _, err := GRPC("127.0.0.1:1").Run(ctx); err == nil {
+		t.Error("expected connecting to a closed port to fail")
+	}
+	if _, err := GRPC("").Run(ctx); !errors.Is(err, ErrSkipped) {
+		t.Errorf("expected the check to be skipped without a URL, got %v", err)
+	}
+}
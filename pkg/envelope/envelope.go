@@ -0,0 +1,249 @@
+// Package envelope implements client-side envelope encryption of Secret
+// manifests carried in ManifestWorks. Each Secret is encrypted with a fresh
+// KMS data key; the encrypted data key travels with the Secret as an
+// annotation so the agent on the management cluster can decrypt it.
+package envelope
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// Annotations set on encrypted Secrets
+const (
+	// AnnotationEncryptedKey holds the base64 KMS ciphertext of the data key
+	AnnotationEncryptedKey = "rosa.openshift.io/envelope-key"
+	// AnnotationKeyID holds the KMS key used to generate the data key
+	AnnotationKeyID = "rosa.openshift.io/envelope-key-id"
+	// AnnotationAlgorithm holds the algorithm used to encrypt data values
+	AnnotationAlgorithm = "rosa.openshift.io/envelope-algorithm"
+)
+
+// AlgorithmAES256GCM is the only supported data encryption algorithm.
+// Each value is stored as base64(nonce || ciphertext || tag).
+const AlgorithmAES256GCM = "AES-256-GCM"
+
+// encryptionContextKey binds a data key to the Secret it protects
+const encryptionContextKey = "rosa.openshift.io/secret"
+
+// KMSClient defines the KMS operations used for envelope encryption
+type KMSClient interface {
+	GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error)
+	Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error)
+}
+
+// Encrypter encrypts Secret manifests with KMS data keys
+type Encrypter struct {
+	kmsClient KMSClient
+	keyID     string
+	logger    *slog.Logger
+}
+
+// NewEncrypter creates a new Encrypter using the given KMS key
+func NewEncrypter(kmsClient KMSClient, keyID string, logger *slog.Logger) *Encrypter {
+	return &Encrypter{
+		kmsClient: kmsClient,
+		keyID:     keyID,
+		logger:    logger,
+	}
+}
+
+// EncryptManifestWork encrypts the data of every Secret manifest in the
+// ManifestWork in place and returns the number of Secrets encrypted.
+func (e *Encrypter) EncryptManifestWork(ctx context.Context, mw *workv1.ManifestWork) (int, error) {
+	count := 0
+	for i := range mw.Spec.Workload.Manifests {
+		manifest := &mw.Spec.Workload.Manifests[i]
+
+		raw := manifest.Raw
+		if raw == nil && manifest.Object != nil {
+			b, err := json.Marshal(manifest.Object)
+			if err != nil {
+				return count, fmt.Errorf("failed to marshal manifest %d: %w", i, err)
+			}
+			raw = b
+		}
+
+		var obj map[string]interface{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return count, fmt.Errorf("failed to unmarshal manifest %d: %w", i, err)
+		}
+
+		if !isSecret(obj) {
+			continue
+		}
+
+		if err := e.encryptSecret(ctx, obj); err != nil {
+			return count, fmt.Errorf("failed to encrypt secret in manifest %d: %w", i, err)
+		}
+
+		b, err := json.Marshal(obj)
+		if err != nil {
+			return count, fmt.Errorf("failed to marshal manifest %d: %w", i, err)
+		}
+		manifest.Raw = b
+		manifest.Object = nil
+		count++
+	}
+
+	return count, nil
+}
+
+func (e *Encrypter) encryptSecret(ctx context.Context, obj map[string]interface{}) error {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+		obj["metadata"] = metadata
+	}
+
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	if _, ok := annotations[AnnotationEncryptedKey]; ok {
+		return fmt.Errorf("secret is already envelope encrypted")
+	}
+
+	// Fold stringData into data so every value is encrypted the same way
+	data, _ := obj["data"].(map[string]interface{})
+	if data == nil {
+		data = map[string]interface{}{}
+	}
+	if stringData, ok := obj["stringData"].(map[string]interface{}); ok {
+		for k, v := range stringData {
+			s, _ := v.(string)
+			data[k] = base64.StdEncoding.EncodeToString([]byte(s))
+		}
+		delete(obj, "stringData")
+	}
+
+	keyOut, err := e.kmsClient.GenerateDataKey(ctx, &kms.GenerateDataKeyInput{
+		KeyId:             aws.String(e.keyID),
+		KeySpec:           kmstypes.DataKeySpecAes256,
+		EncryptionContext: encryptionContext(metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to generate data key: %w", err)
+	}
+
+	gcm, err := newGCM(keyOut.Plaintext)
+	if err != nil {
+		return err
+	}
+
+	for k, v := range data {
+		s, _ := v.(string)
+		plaintext, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("failed to decode data key %q: %w", k, err)
+		}
+
+		nonce := make([]byte, gcm.NonceSize())
+		if _, err := rand.Read(nonce); err != nil {
+			return fmt.Errorf("failed to generate nonce: %w", err)
+		}
+
+		data[k] = base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, plaintext, nil))
+	}
+	obj["data"] = data
+
+	if annotations == nil {
+		annotations = map[string]interface{}{}
+		metadata["annotations"] = annotations
+	}
+	annotations[AnnotationEncryptedKey] = base64.StdEncoding.EncodeToString(keyOut.CiphertextBlob)
+	annotations[AnnotationKeyID] = aws.ToString(keyOut.KeyId)
+	annotations[AnnotationAlgorithm] = AlgorithmAES256GCM
+
+	return nil
+}
+
+// DecryptSecret reverses EncryptManifestWork for a single Secret object.
+// It is the companion hook for the agent side: it decrypts the data key
+// with KMS, replaces each data value with its plaintext (still base64, as
+// Kubernetes expects) and strips the envelope annotations.
+func DecryptSecret(ctx context.Context, kmsClient KMSClient, obj map[string]interface{}) error {
+	metadata, _ := obj["metadata"].(map[string]interface{})
+	annotations, _ := metadata["annotations"].(map[string]interface{})
+	encodedKey, _ := annotations[AnnotationEncryptedKey].(string)
+	if encodedKey == "" {
+		return nil
+	}
+
+	if alg, _ := annotations[AnnotationAlgorithm].(string); alg != AlgorithmAES256GCM {
+		return fmt.Errorf("unsupported envelope algorithm %q", alg)
+	}
+
+	encryptedKey, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return fmt.Errorf("failed to decode encrypted data key: %w", err)
+	}
+
+	keyOut, err := kmsClient.Decrypt(ctx, &kms.DecryptInput{
+		CiphertextBlob:    encryptedKey,
+		EncryptionContext: encryptionContext(metadata),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to decrypt data key: %w", err)
+	}
+
+	gcm, err := newGCM(keyOut.Plaintext)
+	if err != nil {
+		return err
+	}
+
+	data, _ := obj["data"].(map[string]interface{})
+	for k, v := range data {
+		s, _ := v.(string)
+		sealed, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("failed to decode data key %q: %w", k, err)
+		}
+		if len(sealed) < gcm.NonceSize() {
+			return fmt.Errorf("ciphertext for data key %q is too short", k)
+		}
+
+		plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt data key %q: %w", k, err)
+		}
+		data[k] = base64.StdEncoding.EncodeToString(plaintext)
+	}
+
+	delete(annotations, AnnotationEncryptedKey)
+	delete(annotations, AnnotationKeyID)
+	delete(annotations, AnnotationAlgorithm)
+
+	return nil
+}
+
+func isSecret(obj map[string]interface{}) bool {
+	return obj["apiVersion"] == "v1" && obj["kind"] == "Secret"
+}
+
+// encryptionContext binds the data key to the Secret's namespace and name
+func encryptionContext(metadata map[string]interface{}) map[string]string {
+	namespace, _ := metadata["namespace"].(string)
+	name, _ := metadata["name"].(string)
+	return map[string]string{encryptionContextKey: namespace + "/" + name}
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,161 @@
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// fakeKMS "encrypts" data keys by reversing them and checks the encryption context
+type fakeKMS struct {
+	key        []byte
+	genContext map[string]string
+}
+
+func (f *fakeKMS) GenerateDataKey(ctx context.Context, params *kms.GenerateDataKeyInput, optFns ...func(*kms.Options)) (*kms.GenerateDataKeyOutput, error) {
+	f.genContext = params.EncryptionContext
+	return &kms.GenerateDataKeyOutput{
+		KeyId:          params.KeyId,
+		Plaintext:      append([]byte(nil), f.key...),
+		CiphertextBlob: reverse(f.key),
+	}, nil
+}
+
+func (f *fakeKMS) Decrypt(ctx context.Context, params *kms.DecryptInput, optFns ...func(*kms.Options)) (*kms.DecryptOutput, error) {
+	for k, v := range f.genContext {
+		if params.EncryptionContext[k] != v {
+			return nil, errors.New("encryption context mismatch")
+		}
+	}
+	return &kms.DecryptOutput{Plaintext: reverse(params.CiphertextBlob)}, nil
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func testManifestWork(t *testing.T) *workv1.ManifestWork {
+	t.Helper()
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "creds", "namespace": "app"},
+		"data":       map[string]interface{}{"password": base64.StdEncoding.EncodeToString([]byte("hunter2"))},
+		"stringData": map[string]interface{}{"token": "abc123"},
+	}
+	configMap := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cfg", "namespace": "app"},
+		"data":       map[string]interface{}{"key": "value"},
+	}
+
+	mw := &workv1.ManifestWork{}
+	for _, obj := range []map[string]interface{}{secret, configMap} {
+		raw, err := json.Marshal(obj)
+		if err != nil {
+			t.Fatalf("failed to marshal manifest: %v", err)
+		}
+		mw.Spec.Workload.Manifests = append(mw.Spec.Workload.Manifests, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: raw}})
+	}
+	return mw
+}
+
+func TestEncryptManifestWork_RoundTrip(t *testing.T) {
+	kmsClient := &fakeKMS{key: bytes.Repeat([]byte{0x42}, 32)}
+	encrypter := NewEncrypter(kmsClient, "alias/work", testLogger())
+
+	mw := testManifestWork(t)
+	originalConfigMap := string(mw.Spec.Workload.Manifests[1].Raw)
+
+	count, err := encrypter.EncryptManifestWork(context.Background(), mw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 secret encrypted, got %d", count)
+	}
+	if string(mw.Spec.Workload.Manifests[1].Raw) != originalConfigMap {
+		t.Error("expected non-Secret manifests to be left untouched")
+	}
+	if kmsClient.genContext[encryptionContextKey] != "app/creds" {
+		t.Errorf("unexpected encryption context: %v", kmsClient.genContext)
+	}
+
+	var secret map[string]interface{}
+	if err := json.Unmarshal(mw.Spec.Workload.Manifests[0].Raw, &secret); err != nil {
+		t.Fatalf("failed to unmarshal secret: %v", err)
+	}
+	if bytes.Contains(mw.Spec.Workload.Manifests[0].Raw, []byte("abc123")) {
+		t.Error("expected stringData plaintext to be removed")
+	}
+	annotations := secret["metadata"].(map[string]interface{})["annotations"].(map[string]interface{})
+	if annotations[AnnotationKeyID] != "alias/work" || annotations[AnnotationAlgorithm] != AlgorithmAES256GCM {
+		t.Errorf("unexpected annotations: %v", annotations)
+	}
+
+	if err := DecryptSecret(context.Background(), kmsClient, secret); err != nil {
+		t.Fatalf("unexpected decrypt error: %v", err)
+	}
+
+	data := secret["data"].(map[string]interface{})
+	for key, want := range map[string]string{"password": "hunter2", "token": "abc123"} {
+		got, _ := base64.StdEncoding.DecodeString(data[key].(string))
+		if string(got) != want {
+			t.Errorf("expected %s=%q, got %q", key, want, got)
+		}
+	}
+	if _, ok := annotations[AnnotationEncryptedKey]; ok {
+		t.Error("expected envelope annotations to be removed after decryption")
+	}
+}
+
+func TestDecryptSecret_RenamedSecretFails(t *testing.T) {
+	kmsClient := &fakeKMS{key: bytes.Repeat([]byte{0x07}, 32)}
+	encrypter := NewEncrypter(kmsClient, "alias/work", testLogger())
+
+	mw := testManifestWork(t)
+	if _, err := encrypter.EncryptManifestWork(context.Background(), mw); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var secret map[string]interface{}
+	_ = json.Unmarshal(mw.Spec.Workload.Manifests[0].Raw, &secret)
+	secret["metadata"].(map[string]interface{})["name"] = "other"
+
+	if err := DecryptSecret(context.Background(), kmsClient, secret); err == nil {
+		t.Fatal("expected decryption to fail when the secret identity changes")
+	}
+}
+
+func TestDecryptSecret_PlainSecretIsNoop(t *testing.T) {
+	secret := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "plain"},
+		"data":       map[string]interface{}{"k": "dg=="},
+	}
+	if err := DecryptSecret(context.Background(), &fakeKMS{}, secret); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if secret["data"].(map[string]interface{})["k"] != "dg==" {
+		t.Error("expected plain secret to be unchanged")
+	}
+}
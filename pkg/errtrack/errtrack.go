@@ -0,0 +1,205 @@
+// Package errtrack reports panics and high-severity log records to Sentry
+// for production triage. Until Init is called with a DSN every function is a
+// no-op, so callers never need to check whether error tracking is enabled.
+package errtrack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Config configures error reporting
+type Config struct {
+	// DSN enables reporting when set
+	DSN         string
+	Environment string
+	// Release tags events; empty uses Release()
+	Release string
+	// Transport overrides the Sentry transport (tests)
+	Transport sentry.Transport
+}
+
+// Init configures the process-wide Sentry client. An empty DSN leaves error
+// tracking disabled.
+func Init(cfg Config) error {
+	if cfg.DSN == "" {
+		return nil
+	}
+	if cfg.Release == "" {
+		cfg.Release = Release()
+	}
+
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:         cfg.DSN,
+		Environment: cfg.Environment,
+		Release:     cfg.Release,
+		Transport:   cfg.Transport,
+	}); err != nil {
+		return fmt.Errorf("failed to initialize Sentry: %w", err)
+	}
+	return nil
+}
+
+// Enabled reports whether events are sent anywhere
+func Enabled() bool {
+	return sentry.CurrentHub().Client() != nil
+}
+
+// CapturePanic reports a value recovered from a panic while serving req
+func CapturePanic(req *http.Request, recovered any) {
+	if !Enabled() {
+		return
+	}
+	hub := sentry.CurrentHub().Clone()
+	hub.ConfigureScope(func(scope *sentry.Scope) {
+		scope.SetRequest(req)
+	})
+	hub.RecoverWithContext(req.Context(), recovered)
+}
+
+// Flush waits up to timeout for queued events to be sent
+func Flush(timeout time.Duration) {
+	if Enabled() {
+		sentry.Flush(timeout)
+	}
+}
+
+// Release returns the release tag for events: the module version and VCS
+// revision recorded in the build info, when available
+func Release() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	version := info.Main.Version
+	if version == "(devel)" {
+		version = ""
+	}
+	for _, s := range info.Settings {
+		if s.Key != "vcs.revision" || s.Value == "" {
+			continue
+		}
+		if version == "" {
+			return s.Value
+		}
+		return version + "+" + s.Value
+	}
+	return version
+}
+
+type skipKey struct{}
+
+// SkipReport marks ctx so log records written with it are not sent to
+// Sentry, for callers that have already reported the event themselves
+func SkipReport(ctx context.Context) context.Context {
+	return context.WithValue(ctx, skipKey{}, true)
+}
+
+// NewHandler wraps next so that records at or above minLevel are also sent
+// to Sentry. Records are always passed on to next when it accepts them.
+func NewHandler(next slog.Handler, minLevel slog.Level) slog.Handler {
+	return &handler{next: next, minLevel: minLevel}
+}
+
+type handler struct {
+	next     slog.Handler
+	minLevel slog.Level
+	attrs    []slog.Attr
+	groups   []string
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level) || (level >= h.minLevel && Enabled())
+}
+
+func (h *handler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level >= h.minLevel && ctx.Value(skipKey{}) == nil && Enabled() {
+		h.capture(record)
+	}
+	if !h.next.Enabled(ctx, record.Level) {
+		return nil
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &handler{
+		next:     h.next.WithAttrs(attrs),
+		minLevel: h.minLevel,
+		attrs:    append(append([]slog.Attr{}, h.attrs...), h.qualify(attrs)...),
+		groups:   h.groups,
+	}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{
+		next:     h.next.WithGroup(name),
+		minLevel: h.minLevel,
+		attrs:    h.attrs,
+		groups:   append(append([]string{}, h.groups...), name),
+	}
+}
+
+// qualify prefixes attribute keys with the open groups
+func (h *handler) qualify(attrs []slog.Attr) []slog.Attr {
+	if len(h.groups) == 0 {
+		return attrs
+	}
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		key := a.Key
+		for j := len(h.groups) - 1; j >= 0; j-- {
+			key = h.groups[j] + "." + key
+		}
+		out[i] = slog.Attr{Key: key, Value: a.Value}
+	}
+	return out
+}
+
+// capture sends record as an event. An error-valued "error" attribute
+// becomes the event's exception; other attributes are attached as extra data.
+func (h *handler) capture(record slog.Record) {
+	event := sentry.NewEvent()
+	event.Level = sentryLevel(record.Level)
+	event.Message = record.Message
+	event.Timestamp = record.Time
+
+	var recordAttrs []slog.Attr
+	record.Attrs(func(a slog.Attr) bool {
+		recordAttrs = append(recordAttrs, a)
+		return true
+	})
+
+	for _, a := range append(append([]slog.Attr{}, h.attrs...), h.qualify(recordAttrs)...) {
+		if err, ok := a.Value.Any().(error); ok && a.Key == "error" {
+			event.Exception = []sentry.Exception{{
+				Type:       fmt.Sprintf("%T", err),
+				Value:      err.Error(),
+				Stacktrace: sentry.NewStacktrace(),
+			}}
+			continue
+		}
+		event.Extra[a.Key] = a.Value.String()
+	}
+
+	sentry.CurrentHub().CaptureEvent(event)
+}
+
+func sentryLevel(level slog.Level) sentry.Level {
+	switch {
+	case level >= slog.LevelError:
+		return sentry.LevelError
+	case level >= slog.LevelWarn:
+		return sentry.LevelWarning
+	case level >= slog.LevelInfo:
+		return sentry.LevelInfo
+	default:
+		return sentry.LevelDebug
+	}
+}
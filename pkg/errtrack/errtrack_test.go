@@ -0,0 +1,116 @@
+package errtrack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTransport records events instead of sending them
+type fakeTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *fakeTransport) Flush(timeout time.Duration) bool       { return true }
+func (t *fakeTransport) Configure(options sentry.ClientOptions) {}
+func (t *fakeTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func (t *fakeTransport) Events() []*sentry.Event {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]*sentry.Event{}, t.events...)
+}
+
+func initFake(t *testing.T) *fakeTransport {
+	t.Helper()
+	transport := &fakeTransport{}
+	require.NoError(t, Init(Config{
+		DSN:         "https://public@sentry.example.com/1",
+		Environment: "test",
+		Release:     "v1.2.3",
+		Transport:   transport,
+	}))
+	t.Cleanup(func() { sentry.CurrentHub().BindClient(nil) })
+	return transport
+}
+
+func TestInit_EmptyDSNDisables(t *testing.T) {
+	require.NoError(t, Init(Config{}))
+	assert.False(t, Enabled())
+
+	// No-ops while disabled
+	CapturePanic(httptest.NewRequest("GET", "/", nil), "boom")
+	Flush(time.Millisecond)
+}
+
+func TestHandler_ReportsAtMinLevel(t *testing.T) {
+	transport := initFake(t)
+
+	var out bytes.Buffer
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&out, nil), slog.LevelError)).With("component", "test")
+
+	logger.Info("routine")
+	logger.Warn("unusual")
+	logger.Error("failed to list clusters", "error", errors.New("maestro down"), "account_id", "123456789012")
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, "failed to list clusters", event.Message)
+	assert.Equal(t, sentry.LevelError, event.Level)
+	assert.Equal(t, "v1.2.3", event.Release)
+	assert.Equal(t, "test", event.Environment)
+	assert.Equal(t, "123456789012", event.Extra["account_id"])
+	assert.Equal(t, "test", event.Extra["component"])
+	require.Len(t, event.Exception, 1)
+	assert.Equal(t, "maestro down", event.Exception[0].Value)
+
+	// Every record still reaches the wrapped handler
+	assert.Equal(t, 3, bytes.Count(out.Bytes(), []byte("\n")))
+}
+
+func TestHandler_SkipReport(t *testing.T) {
+	transport := initFake(t)
+
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&bytes.Buffer{}, nil), slog.LevelError))
+	logger.ErrorContext(SkipReport(context.Background()), "already reported")
+
+	assert.Empty(t, transport.Events())
+}
+
+func TestHandler_Groups(t *testing.T) {
+	transport := initFake(t)
+
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&bytes.Buffer{}, nil), slog.LevelError)).WithGroup("authz")
+	logger.Error("check failed", "account_id", "123456789012")
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, "123456789012", events[0].Extra["authz.account_id"])
+}
+
+func TestCapturePanic(t *testing.T) {
+	transport := initFake(t)
+
+	CapturePanic(httptest.NewRequest("POST", "/api/v0/clusters", nil), "nil map write")
+
+	events := transport.Events()
+	require.Len(t, events, 1)
+	assert.Equal(t, sentry.LevelFatal, events[0].Level)
+	require.NotNil(t, events[0].Request)
+	assert.Equal(t, "POST", events[0].Request.Method)
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+)
+
+// TypePrefix qualifies event types into CloudEvents types, such as
+// com.redhat.rosa.work.created
+const TypePrefix = "com.redhat.rosa."
+
+// DefaultSource is the CloudEvents source of events when none is
+// configured, the source ID the platform also uses with Maestro
+const DefaultSource = "rosa-regional-platform-api"
+
+// CloudEvents extension attributes set on every event that has them
+const (
+	ExtensionAccountID   = "accountid"
+	ExtensionTraceParent = "traceparent"
+)
+
+// EventData is the data of an event's CloudEvent
+type EventData struct {
+	AccountID string            `json:"accountId"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// CloudEvent returns event as a CloudEvent from source, with its data as
+// JSON
+func (e Event) CloudEvent(source string) (cloudevents.Event, error) {
+	ce := cloudevents.New()
+	ce.SetID(e.ID)
+	ce.SetSource(source)
+	ce.SetType(TypePrefix + e.Type)
+	ce.SetSubject(e.Subject)
+	ce.SetTime(e.Time)
+	ce.SetExtension(ExtensionAccountID, e.AccountID)
+	if e.TraceParent != "" {
+		ce.SetExtension(ExtensionTraceParent, e.TraceParent)
+	}
+	if err := ce.SetData(cloudevents.ApplicationJSON, EventData{AccountID: e.AccountID, Details: e.Data}); err != nil {
+		return ce, fmt.Errorf("failed to set event data: %w", err)
+	}
+	if err := ce.Validate(); err != nil {
+		return ce, fmt.Errorf("invalid CloudEvent: %w", err)
+	}
+	return ce, nil
+}
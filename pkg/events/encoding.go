@@ -0,0 +1,172 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+)
+
+// Encodings
+const (
+	EncodingJSON = "json"
+	EncodingAvro = "avro"
+)
+
+// Encodings lists the valid encodings
+var Encodings = []string{EncodingJSON, EncodingAvro}
+
+// jsonSchema is the JSON Schema of JSON-encoded events: structured-mode
+// CloudEvents with EventData
+const jsonSchema = `{"$schema":"http://json-schema.org/draft-07/schema#","title":"PlatformCloudEvent","type":"object",` +
+	`"properties":{"specversion":{"const":"1.0"},"id":{"type":"string"},"source":{"type":"string"},"type":{"type":"string"},` +
+	`"subject":{"type":"string"},"time":{"type":"string","format":"date-time"},"datacontenttype":{"type":"string"},` +
+	`"accountid":{"type":"string"},"traceparent":{"type":"string"},` +
+	`"data":{"type":"object","properties":{"accountId":{"type":"string"},"details":{"type":"object","additionalProperties":{"type":"string"}}},"required":["accountId"]}},` +
+	`"required":["specversion","id","source","type","accountid","data"]}`
+
+// avroSchema is the Avro schema of the data of Avro-encoded events, whose
+// fields appendAvro writes in order. The CloudEvent's attributes travel in
+// the record headers.
+const avroSchema = `{"type":"record","name":"EventData","namespace":"com.redhat.rosa.events","fields":[` +
+	`{"name":"accountId","type":"string"},{"name":"details","type":{"type":"map","values":"string"}}]}`
+
+// Encoder turns CloudEvents into Kafka record values: the whole event as
+// JSON, in structured mode, or its data as Avro, in binary mode. With a
+// schema registry, values are framed in its wire format: a zero byte, the
+// big-endian schema ID and the encoded value, so registry-aware consumers
+// decode them.
+type Encoder struct {
+	encoding string
+	// schemaIDs are the registered schema IDs by topic; nil without a
+	// schema registry
+	schemaIDs map[string]uint32
+}
+
+// NewEncoder creates an encoder for encoding, json or avro. With a
+// registryURL, it registers the events' schema for each of topics under the
+// subject <topic>-value and frames values with the schema ID.
+func NewEncoder(ctx context.Context, encoding, registryURL string, topics []string) (*Encoder, error) {
+	if !slices.Contains(Encodings, encoding) {
+		return nil, fmt.Errorf("invalid event encoding %q", encoding)
+	}
+	e := &Encoder{encoding: encoding}
+	if registryURL == "" {
+		return e, nil
+	}
+
+	e.schemaIDs = make(map[string]uint32, len(topics))
+	registry := &http.Client{Timeout: 10 * time.Second}
+	for _, topic := range topics {
+		id, err := e.register(ctx, registry, registryURL, topic+"-value")
+		if err != nil {
+			return nil, err
+		}
+		e.schemaIDs[topic] = id
+	}
+	return e, nil
+}
+
+// register registers the encoding's schema under subject and returns its
+// ID. Registering a schema the subject already has returns its existing ID.
+func (e *Encoder) register(ctx context.Context, registry *http.Client, registryURL, subject string) (uint32, error) {
+	body := map[string]string{"schema": avroSchema}
+	if e.encoding == EncodingJSON {
+		body = map[string]string{"schema": jsonSchema, "schemaType": "JSON"}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode schema: %w", err)
+	}
+
+	endpoint := strings.TrimSuffix(registryURL, "/") + "/subjects/" + url.PathEscape(subject) + "/versions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to build schema registration: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/vnd.schemaregistry.v1+json")
+	resp, err := registry.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to register event schema for %s: %w", subject, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return 0, fmt.Errorf("schema registry returned %s for %s: %s", resp.Status, subject, msg)
+	}
+
+	var result struct {
+		ID uint32 `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("failed to decode schema registration for %s: %w", subject, err)
+	}
+	return result.ID, nil
+}
+
+// Structured reports whether values hold whole CloudEvents, rather than
+// their data with the attributes in headers
+func (e *Encoder) Structured() bool {
+	return e.encoding == EncodingJSON
+}
+
+// Encode returns the record value of ce for topic
+func (e *Encoder) Encode(topic string, ce cloudevents.Event) ([]byte, error) {
+	var buf []byte
+	if e.schemaIDs != nil {
+		id, ok := e.schemaIDs[topic]
+		if !ok {
+			return nil, fmt.Errorf("no schema registered for topic %s", topic)
+		}
+		buf = binary.BigEndian.AppendUint32(append(buf, 0), id)
+	}
+
+	if e.encoding == EncodingAvro {
+		var data EventData
+		if err := ce.DataAs(&data); err != nil {
+			return nil, fmt.Errorf("failed to decode event data: %w", err)
+		}
+		return appendAvro(buf, data), nil
+	}
+	value, err := json.Marshal(ce)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode event: %w", err)
+	}
+	return append(buf, value...), nil
+}
+
+// appendAvro appends the Avro binary encoding of data, following
+// avroSchema, to buf
+func appendAvro(buf []byte, data EventData) []byte {
+	buf = appendAvroString(buf, data.AccountID)
+
+	// A map is a block of its entries, sorted here so equal events encode
+	// equally, ended by an empty block
+	if len(data.Details) > 0 {
+		keys := make([]string, 0, len(data.Details))
+		for k := range data.Details {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		buf = binary.AppendVarint(buf, int64(len(keys)))
+		for _, k := range keys {
+			buf = appendAvroString(appendAvroString(buf, k), data.Details[k])
+		}
+	}
+	return binary.AppendVarint(buf, 0)
+}
+
+// appendAvroString appends s as an Avro string: its zigzag length, then its
+// bytes. binary.AppendVarint writes the zigzag encoding Avro uses.
+func appendAvroString(buf []byte, s string) []byte {
+	return append(binary.AppendVarint(buf, int64(len(s))), s...)
+}
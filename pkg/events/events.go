@@ -0,0 +1,60 @@
+// Package events publishes platform events, such as works being created and
+// accounts being enabled, to an event bus other systems consume. Events are
+// sent as CloudEvents and delivered at least once, so consumers must
+// deduplicate on the event ID.
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event types
+const (
+	TypeWorkCreated = "work.created"
+	TypeWorkDeleted = "work.deleted"
+	// TypeAuthzChanged is published for every change to an account's authz
+	// items other than the account itself
+	TypeAuthzChanged    = "authz.changed"
+	TypeAccountEnabled  = "account.enabled"
+	TypeAccountUpdated  = "account.updated"
+	TypeAccountDisabled = "account.disabled"
+)
+
+// Types lists every event type
+var Types = []string{TypeWorkCreated, TypeWorkDeleted, TypeAuthzChanged, TypeAccountEnabled, TypeAccountUpdated, TypeAccountDisabled}
+
+// Event is something that happened on the platform
+type Event struct {
+	// ID is unique per event; consumers deduplicate redelivered events on it
+	ID        string
+	Type      string
+	AccountID string
+	// Subject identifies what the event is about, such as a work's cluster
+	// and name
+	Subject string
+	Time    time.Time
+	Data    map[string]string
+	// TraceParent is the W3C traceparent of the request that caused the
+	// event, if any
+	TraceParent string
+}
+
+// Publisher publishes events without waiting for their delivery
+type Publisher interface {
+	Publish(ctx context.Context, event Event)
+}
+
+// New returns an event with a new ID, made now
+func New(eventType, accountID, subject string, data map[string]string) Event {
+	return Event{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		AccountID: accountID,
+		Subject:   subject,
+		Time:      time.Now().UTC(),
+		Data:      data,
+	}
+}
@@ -0,0 +1,226 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+func TestParseTopics(t *testing.T) {
+	m, err := ParseTopics("work.*=rosa.work, work.deleted=rosa.deletions,authz.changed=rosa.authz", "rosa.events")
+	if err != nil {
+		t.Fatalf("ParseTopics: %v", err)
+	}
+	for typ, want := range map[string]string{
+		TypeWorkCreated:    "rosa.work",
+		TypeWorkDeleted:    "rosa.deletions",
+		TypeAuthzChanged:   "rosa.authz",
+		TypeAccountEnabled: "rosa.events",
+	} {
+		if got := m.Topic(typ); got != want {
+			t.Errorf("%s: expected topic %q, got %q", typ, want, got)
+		}
+	}
+	if want := []string{"rosa.work", "rosa.deletions", "rosa.authz", "rosa.events"}; !slices.Equal(m.Topics(), want) {
+		t.Errorf("expected topics %v, got %v", want, m.Topics())
+	}
+
+	if m, _ := ParseTopics("account.*=rosa.accounts", ""); m.Topic(TypeWorkCreated) != "" {
+		t.Error("expected unmapped events to have no topic without a default")
+	}
+	for _, list := range []string{"work.created", "work.created=", "cluster.created=rosa.clusters"} {
+		if _, err := ParseTopics(list, ""); err == nil {
+			t.Errorf("expected %q to be rejected", list)
+		}
+	}
+}
+
+func TestEvent_CloudEvent(t *testing.T) {
+	event := Event{
+		ID:          "e1",
+		Type:        TypeWorkCreated,
+		AccountID:   "123456789012",
+		Subject:     "c1/w1",
+		Time:        time.UnixMilli(1000).UTC(),
+		Data:        map[string]string{"clusterId": "c1"},
+		TraceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}
+	ce, err := event.CloudEvent(DefaultSource)
+	if err != nil {
+		t.Fatalf("CloudEvent: %v", err)
+	}
+	if ce.ID() != "e1" || ce.Source() != DefaultSource || ce.Type() != "com.redhat.rosa.work.created" || ce.Subject() != "c1/w1" || !ce.Time().Equal(event.Time) {
+		t.Errorf("unexpected attributes %s", ce)
+	}
+	if ce.Extensions()[ExtensionAccountID] != "123456789012" || ce.Extensions()[ExtensionTraceParent] != event.TraceParent {
+		t.Errorf("unexpected extensions %v", ce.Extensions())
+	}
+	var data EventData
+	if err := ce.DataAs(&data); err != nil || data.AccountID != "123456789012" || data.Details["clusterId"] != "c1" {
+		t.Errorf("unexpected data %+v: %v", data, err)
+	}
+
+	event.TraceParent = ""
+	if ce, _ := event.CloudEvent(DefaultSource); ce.Extensions()[ExtensionTraceParent] != nil {
+		t.Error("expected no traceparent extension for an event without one")
+	}
+	if _, err := event.CloudEvent(""); err == nil {
+		t.Error("expected an error without a source")
+	}
+}
+
+func TestEncoder_Avro(t *testing.T) {
+	e, err := NewEncoder(context.Background(), EncodingAvro, "", nil)
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	ce, _ := New(TypeWorkCreated, "123456789012", "c1/w1", map[string]string{"b": "2", "a": "1"}).CloudEvent(DefaultSource)
+	value, err := e.Encode("rosa.work", ce)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+
+	// Strings are zigzag lengths and bytes, and the map is one sorted block
+	// of two entries ended by an empty block
+	want := append([]byte{24}, "123456789012"...)
+	want = append(want, 4, 2, 'a', 2, '1', 2, 'b', 2, '2', 0)
+	if !bytes.Equal(value, want) {
+		t.Errorf("unexpected encoding\n got %v\nwant %v", value, want)
+	}
+	if e.Structured() {
+		t.Error("expected Avro values to be binary mode")
+	}
+}
+
+func TestEncoder_SchemaRegistry(t *testing.T) {
+	var subjects []string
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		if body["schemaType"] != "JSON" || body["schema"] != jsonSchema {
+			http.Error(w, "unexpected schema", http.StatusUnprocessableEntity)
+			return
+		}
+		subjects = append(subjects, r.URL.Path)
+		_, _ = w.Write([]byte(`{"id": 42}`))
+	}))
+	defer registry.Close()
+
+	e, err := NewEncoder(context.Background(), EncodingJSON, registry.URL, []string{"rosa.work"})
+	if err != nil {
+		t.Fatalf("NewEncoder: %v", err)
+	}
+	if !slices.Equal(subjects, []string{"/subjects/rosa.work-value/versions"}) {
+		t.Errorf("unexpected registrations %v", subjects)
+	}
+
+	ce, _ := New(TypeWorkCreated, "123456789012", "c1/w1", nil).CloudEvent(DefaultSource)
+	value, err := e.Encode("rosa.work", ce)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	if value[0] != 0 || binary.BigEndian.Uint32(value[1:5]) != 42 {
+		t.Errorf("expected the wire format header of schema 42, got %v", value[:5])
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(value[5:], &decoded); err != nil || decoded["type"] != "com.redhat.rosa.work.created" || decoded["accountid"] != "123456789012" {
+		t.Errorf("expected the structured CloudEvent after the header, got %s: %v", value[5:], err)
+	}
+	if _, err := e.Encode("rosa.other", ce); err == nil {
+		t.Error("expected an error for a topic without a registered schema")
+	}
+}
+
+// fakeProducer records produced records and completes them with err
+type fakeProducer struct {
+	records []*kgo.Record
+	err     error
+	flushed bool
+	closed  bool
+}
+
+func (f *fakeProducer) TryProduce(ctx context.Context, r *kgo.Record, promise func(*kgo.Record, error)) {
+	f.records = append(f.records, r)
+	promise(r, f.err)
+}
+
+func (f *fakeProducer) Flush(ctx context.Context) error {
+	f.flushed = true
+	return nil
+}
+
+func (f *fakeProducer) Close() { f.closed = true }
+
+func TestKafkaPublisher(t *testing.T) {
+	topics, _ := ParseTopics("work.*=rosa.work", "")
+	encoder, _ := NewEncoder(context.Background(), EncodingJSON, "", nil)
+	client := &fakeProducer{}
+	p := newKafkaPublisher(client, topics, encoder, DefaultSource, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	p.Publish(context.Background(), New(TypeWorkCreated, "123456789012", "c1/w1", nil))
+	p.Publish(context.Background(), New(TypeAccountEnabled, "123456789012", "accounts", nil))
+	client.err = errors.New("broker unavailable")
+	p.Publish(context.Background(), New(TypeWorkDeleted, "123456789012", "b1", nil))
+
+	if len(client.records) != 2 {
+		t.Fatalf("expected the unmapped event not to be produced, got %d records", len(client.records))
+	}
+	record := client.records[0]
+	if record.Topic != "rosa.work" || string(record.Key) != "123456789012" || string(record.Headers[0].Value) != "application/cloudevents+json" {
+		t.Errorf("unexpected record %+v", record)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(record.Value, &decoded); err != nil || decoded["source"] != DefaultSource || decoded["type"] != "com.redhat.rosa.work.created" {
+		t.Errorf("expected a structured CloudEvent, got %s: %v", record.Value, err)
+	}
+
+	var nilPublisher *KafkaPublisher
+	nilPublisher.Publish(context.Background(), New(TypeWorkCreated, "123456789012", "c1/w1", nil))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	p.Run(ctx)
+	if !client.flushed || !client.closed {
+		t.Error("expected Run to flush and close the client on shutdown")
+	}
+}
+
+func TestKafkaHeaders_Binary(t *testing.T) {
+	event := New(TypeAccountEnabled, "123456789012", "accounts", nil)
+	event.TraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	ce, _ := event.CloudEvent(DefaultSource)
+
+	headers := make(map[string]string)
+	for _, h := range kafkaHeaders(ce, false) {
+		headers[h.Key] = string(h.Value)
+	}
+	for key, want := range map[string]string{
+		"content-type":   "application/avro",
+		"ce_specversion": "1.0",
+		"ce_id":          event.ID,
+		"ce_source":      DefaultSource,
+		"ce_type":        "com.redhat.rosa.account.enabled",
+		"ce_subject":     "accounts",
+		"ce_accountid":   "123456789012",
+		"ce_traceparent": event.TraceParent,
+	} {
+		if headers[key] != want {
+			t.Errorf("%s: expected %q, got %q", key, want, headers[key])
+		}
+	}
+	if _, err := time.Parse(time.RFC3339Nano, headers["ce_time"]); err != nil {
+		t.Errorf("expected an RFC 3339 ce_time, got %q", headers["ce_time"])
+	}
+}
@@ -0,0 +1,206 @@
+package events
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	cloudevents "github.com/cloudevents/sdk-go/v2/event"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/twmb/franz-go/pkg/kgo"
+	awssasl "github.com/twmb/franz-go/pkg/sasl/aws"
+)
+
+// finalFlushTimeout bounds delivering the buffered events on shutdown
+const finalFlushTimeout = 10 * time.Second
+
+var (
+	eventsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_events_published_total",
+		Help: "Platform events delivered to Kafka, by type.",
+	}, []string{"type"})
+
+	eventsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_events_dropped_total",
+		Help: "Platform events not delivered, by reason (unmapped, encoding, buffer-full, delivery).",
+	}, []string{"reason"})
+)
+
+// KafkaConfig configures a KafkaPublisher
+type KafkaConfig struct {
+	Brokers []string
+	Topics  *TopicMap
+	// Source is the CloudEvents source of the events
+	Source string
+	// Encoding is json or avro; SchemaRegistryURL, when set, registers the
+	// events' schema and frames values in the registry's wire format
+	Encoding          string
+	SchemaRegistryURL string
+	// TLS connects to the brokers over TLS. MSKIAM authenticates with the
+	// AWS credentials of the server, for Amazon MSK, and implies TLS.
+	TLS    bool
+	MSKIAM bool
+	// BufferSize is how many events may wait for delivery; events published
+	// while it is full are dropped
+	BufferSize int
+}
+
+// producer is the part of *kgo.Client KafkaPublisher uses
+type producer interface {
+	TryProduce(ctx context.Context, r *kgo.Record, promise func(*kgo.Record, error))
+	Flush(ctx context.Context) error
+	Close()
+}
+
+// KafkaPublisher publishes events to Kafka topics as CloudEvents, following
+// the CloudEvents Kafka protocol binding. Records are keyed by account, so
+// each account's events keep their order within a topic. The client batches
+// records and retries failed deliveries until they succeed or the server
+// stops.
+type KafkaPublisher struct {
+	client  producer
+	topics  *TopicMap
+	encoder *Encoder
+	source  string
+	logger  *slog.Logger
+}
+
+// NewKafkaPublisher creates a publisher for cfg. credentials authenticate
+// with MSK IAM when cfg.MSKIAM is set.
+func NewKafkaPublisher(ctx context.Context, cfg KafkaConfig, credentials aws.CredentialsProvider, logger *slog.Logger) (*KafkaPublisher, error) {
+	encoder, err := NewEncoder(ctx, cfg.Encoding, cfg.SchemaRegistryURL, cfg.Topics.Topics())
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.MaxBufferedRecords(cfg.BufferSize),
+		kgo.ProducerLinger(50 * time.Millisecond),
+	}
+	if cfg.TLS || cfg.MSKIAM {
+		opts = append(opts, kgo.DialTLSConfig(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	if cfg.MSKIAM {
+		opts = append(opts, kgo.SASL(awssasl.ManagedStreamingIAM(func(ctx context.Context) (awssasl.Auth, error) {
+			creds, err := credentials.Retrieve(ctx)
+			if err != nil {
+				return awssasl.Auth{}, fmt.Errorf("failed to retrieve AWS credentials for MSK: %w", err)
+			}
+			return awssasl.Auth{
+				AccessKey:    creds.AccessKeyID,
+				SecretKey:    creds.SecretAccessKey,
+				SessionToken: creds.SessionToken,
+				UserAgent:    "rosa-regional-platform-api",
+			}, nil
+		})))
+	}
+
+	client, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kafka client: %w", err)
+	}
+	return newKafkaPublisher(client, cfg.Topics, encoder, cfg.Source, logger), nil
+}
+
+func newKafkaPublisher(client producer, topics *TopicMap, encoder *Encoder, source string, logger *slog.Logger) *KafkaPublisher {
+	return &KafkaPublisher{
+		client:  client,
+		topics:  topics,
+		encoder: encoder,
+		source:  source,
+		logger:  logger,
+	}
+}
+
+// Publish queues event for delivery to its type's topic without blocking.
+// Events of types mapped to no topic are dropped. A nil KafkaPublisher
+// publishes nothing.
+func (p *KafkaPublisher) Publish(ctx context.Context, event Event) {
+	if p == nil {
+		return
+	}
+	topic := p.topics.Topic(event.Type)
+	if topic == "" {
+		eventsDropped.WithLabelValues("unmapped").Inc()
+		return
+	}
+	ce, err := event.CloudEvent(p.source)
+	if err != nil {
+		eventsDropped.WithLabelValues("encoding").Inc()
+		p.logger.Error("failed to build event", "error", err, "event_id", event.ID, "type", event.Type)
+		return
+	}
+	value, err := p.encoder.Encode(topic, ce)
+	if err != nil {
+		eventsDropped.WithLabelValues("encoding").Inc()
+		p.logger.Error("failed to encode event", "error", err, "event_id", event.ID, "type", event.Type)
+		return
+	}
+
+	record := &kgo.Record{
+		Topic:   topic,
+		Key:     []byte(event.AccountID),
+		Value:   value,
+		Headers: kafkaHeaders(ce, p.encoder.Structured()),
+	}
+	// The request may end before the record is delivered
+	p.client.TryProduce(context.WithoutCancel(ctx), record, func(_ *kgo.Record, err error) {
+		switch {
+		case err == nil:
+			eventsPublished.WithLabelValues(event.Type).Inc()
+		case errors.Is(err, kgo.ErrMaxBuffered):
+			eventsDropped.WithLabelValues("buffer-full").Inc()
+			p.logger.Error("event buffer full, dropping event", "event_id", event.ID, "type", event.Type, "account_id", event.AccountID)
+		default:
+			eventsDropped.WithLabelValues("delivery").Inc()
+			p.logger.Error("failed to deliver event", "error", err, "event_id", event.ID, "type", event.Type, "topic", topic)
+		}
+	})
+}
+
+// kafkaHeaders returns the record headers of ce: in structured mode only its
+// content type, and in binary mode its attributes as ce_ headers, with the
+// content type of the Avro data
+func kafkaHeaders(ce cloudevents.Event, structured bool) []kgo.RecordHeader {
+	if structured {
+		return []kgo.RecordHeader{{Key: "content-type", Value: []byte("application/cloudevents+json")}}
+	}
+	headers := []kgo.RecordHeader{
+		{Key: "content-type", Value: []byte("application/avro")},
+		{Key: "ce_specversion", Value: []byte(ce.SpecVersion())},
+		{Key: "ce_id", Value: []byte(ce.ID())},
+		{Key: "ce_source", Value: []byte(ce.Source())},
+		{Key: "ce_type", Value: []byte(ce.Type())},
+		{Key: "ce_time", Value: []byte(ce.Time().Format(time.RFC3339Nano))},
+	}
+	if subject := ce.Subject(); subject != "" {
+		headers = append(headers, kgo.RecordHeader{Key: "ce_subject", Value: []byte(subject)})
+	}
+	for _, name := range []string{ExtensionAccountID, ExtensionTraceParent} {
+		if value, ok := ce.Extensions()[name].(string); ok {
+			headers = append(headers, kgo.RecordHeader{Key: "ce_" + name, Value: []byte(value)})
+		}
+	}
+	return headers
+}
+
+// Run waits until ctx is done, then delivers the buffered events and closes
+// the client
+func (p *KafkaPublisher) Run(ctx context.Context) {
+	p.logger.Info("event publishing started", "topics", p.topics.Topics())
+	<-ctx.Done()
+
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalFlushTimeout)
+	defer cancel()
+	if err := p.client.Flush(flushCtx); err != nil {
+		p.logger.Error("failed to deliver buffered events on shutdown", "error", err)
+	}
+	p.client.Close()
+	p.logger.Info("event publishing stopped")
+}
@@ -0,0 +1,82 @@
+package events
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TopicMap routes events to topics by type
+type TopicMap struct {
+	rules []topicRule
+	// fallback receives the events no rule matches; empty drops them
+	fallback string
+}
+
+// topicRule sends the events whose type is typ, or starts with typ when
+// prefix is set, to topic
+type topicRule struct {
+	typ    string
+	prefix bool
+	topic  string
+}
+
+// ParseTopics parses a comma-separated list of type=topic rules, such as
+// "work.*=rosa.work,authz.changed=rosa.authz". A type ending in ".*" matches
+// every type in that family. Exact types win over families, and fallback
+// receives the events no rule matches.
+func ParseTopics(list, fallback string) (*TopicMap, error) {
+	m := &TopicMap{fallback: fallback}
+	for _, rule := range strings.Split(list, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		typ, topic, ok := strings.Cut(rule, "=")
+		typ, topic = strings.TrimSpace(typ), strings.TrimSpace(topic)
+		if !ok || typ == "" || topic == "" {
+			return nil, fmt.Errorf("invalid topic rule %q: must be type=topic", rule)
+		}
+		r := topicRule{typ: typ, topic: topic}
+		if family, ok := strings.CutSuffix(typ, ".*"); ok {
+			r.typ, r.prefix = family+".", true
+		}
+		if !r.prefix && !slices.Contains(Types, r.typ) {
+			return nil, fmt.Errorf("invalid topic rule %q: unknown event type %q", rule, typ)
+		}
+		m.rules = append(m.rules, r)
+	}
+	// Exact rules first, so they win over the families they belong to
+	slices.SortStableFunc(m.rules, func(a, b topicRule) int {
+		switch {
+		case a.prefix == b.prefix:
+			return 0
+		case a.prefix:
+			return 1
+		default:
+			return -1
+		}
+	})
+	return m, nil
+}
+
+// Topic returns the topic of an event type, or "" if it is not published
+func (m *TopicMap) Topic(eventType string) string {
+	for _, r := range m.rules {
+		if r.typ == eventType || (r.prefix && strings.HasPrefix(eventType, r.typ)) {
+			return r.topic
+		}
+	}
+	return m.fallback
+}
+
+// Topics returns every topic events can be published to
+func (m *TopicMap) Topics() []string {
+	var topics []string
+	for _, typ := range Types {
+		if topic := m.Topic(typ); topic != "" && !slices.Contains(topics, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
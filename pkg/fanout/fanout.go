@@ -0,0 +1,74 @@
+// Package fanout runs independent backend calls (typically to Maestro)
+// concurrently with a bound on the number in flight, so handlers that merge
+// many lookups do not pay for them serially.
+package fanout
+
+import (
+	"context"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultLimit is the concurrency used when a caller passes a non-positive limit.
+// It keeps a single request from monopolising the Maestro connection pool.
+const DefaultLimit = 8
+
+// Map calls fn for every item with at most limit calls in flight and returns
+// the results in input order. The first error cancels the context passed to
+// the remaining calls and is returned with no results.
+func Map[In, Out any](ctx context.Context, items []In, limit int, fn func(context.Context, In) (Out, error)) ([]Out, error) {
+	out := make([]Out, len(items))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(normalize(limit))
+	for i, item := range items {
+		g.Go(func() error {
+			v, err := fn(gctx, item)
+			if err != nil {
+				return err
+			}
+			out[i] = v
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Result is the outcome of one call made by MapPartial
+type Result[T any] struct {
+	Value T
+	Err   error
+}
+
+// MapPartial calls fn for every item with at most limit calls in flight and
+// returns one Result per item in input order. A failing call does not stop the
+// others, so callers can serve what succeeded and report what did not. Calls
+// not yet started when ctx is cancelled get ctx's error.
+func MapPartial[In, Out any](ctx context.Context, items []In, limit int, fn func(context.Context, In) (Out, error)) []Result[Out] {
+	out := make([]Result[Out], len(items))
+
+	var g errgroup.Group
+	g.SetLimit(normalize(limit))
+	for i, item := range items {
+		g.Go(func() error {
+			if err := ctx.Err(); err != nil {
+				out[i].Err = err
+				return nil
+			}
+			out[i].Value, out[i].Err = fn(ctx, item)
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return out
+}
+
+func normalize(limit int) int {
+	if limit <= 0 {
+		return DefaultLimit
+	}
+	return limit
+}
@@ -0,0 +1,101 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMap_PreservesOrder(t *testing.T) {
+	items := []int{5, 1, 4, 2, 3}
+
+	out, err := Map(context.Background(), items, 2, func(ctx context.Context, n int) (int, error) {
+		time.Sleep(time.Duration(n) * time.Millisecond)
+		return n * 10, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []int{50, 10, 40, 20, 30}, out)
+}
+
+func TestMap_RespectsLimit(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	items := make([]int, 20)
+
+	_, err := Map(context.Background(), items, 3, func(ctx context.Context, _ int) (struct{}, error) {
+		n := inFlight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		inFlight.Add(-1)
+		return struct{}{}, nil
+	})
+
+	require.NoError(t, err)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestMap_FirstErrorCancels(t *testing.T) {
+	boom := errors.New("boom")
+	var cancelled atomic.Int32
+
+	out, err := Map(context.Background(), []int{0, 1, 2, 3}, 4, func(ctx context.Context, n int) (int, error) {
+		if n == 0 {
+			return 0, boom
+		}
+		select {
+		case <-ctx.Done():
+			cancelled.Add(1)
+			return 0, ctx.Err()
+		case <-time.After(time.Second):
+			return n, nil
+		}
+	})
+
+	require.ErrorIs(t, err, boom)
+	assert.Nil(t, out)
+	assert.Equal(t, int32(3), cancelled.Load())
+}
+
+func TestMapPartial_KeepsSuccesses(t *testing.T) {
+	boom := errors.New("boom")
+
+	results := MapPartial(context.Background(), []int{1, 2, 3}, 0, func(ctx context.Context, n int) (int, error) {
+		if n == 2 {
+			return 0, boom
+		}
+		return n * 10, nil
+	})
+
+	require.Len(t, results, 3)
+	assert.Equal(t, 10, results[0].Value)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorIs(t, results[1].Err, boom)
+	assert.Equal(t, 30, results[2].Value)
+	assert.NoError(t, results[2].Err)
+}
+
+func TestMapPartial_CancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var calls atomic.Int32
+	results := MapPartial(ctx, []int{1, 2}, 1, func(ctx context.Context, n int) (int, error) {
+		calls.Add(1)
+		return n, nil
+	})
+
+	assert.Equal(t, int32(0), calls.Load())
+	for _, r := range results {
+		assert.ErrorIs(t, r.Err, context.Canceled)
+	}
+}
@@ -1,51 +1,80 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/policybackup"
+	"github.com/openshift/rosa-regional-platform-api/pkg/residency"
 )
 
 // AccountsHandler handles account management endpoints
 type AccountsHandler struct {
-	authorizer authz.Service
-	logger     *slog.Logger
+	authorizer    authz.Service
+	backups       PolicyBackupReader
+	notifications NotificationSettingsStore
+	notifier      NotificationSender
+	planCache     PlanCache
+	purger        AccountPurger
+	operations    *operations.Registry
+	pageLimits    PageLimits
+	logger        *slog.Logger
+}
+
+// PolicyBackupReader reads scheduled policy store backups for restore
+type PolicyBackupReader interface {
+	List(ctx context.Context, accountID string) ([]policybackup.Backup, error)
+	Get(ctx context.Context, accountID, key string) (*authz.PolicyStoreExport, error)
 }
 
 // NewAccountsHandler creates a new AccountsHandler
 func NewAccountsHandler(authorizer authz.Service, logger *slog.Logger) *AccountsHandler {
 	return &AccountsHandler{
 		authorizer: authorizer,
+		pageLimits: DefaultPlatformPageLimits,
 		logger:     logger,
 	}
 }
 
-// EnableAccountRequest is the request body for enabling an account
-type EnableAccountRequest struct {
-	AccountID  string `json:"accountId"`
-	Privileged bool   `json:"privileged"`
+// WithPageLimits sets the default and maximum page size for List
+func (h *AccountsHandler) WithPageLimits(limits PageLimits) *AccountsHandler {
+	h.pageLimits = limits
+	return h
 }
 
-// AccountResponse is the response for account operations
-type AccountResponse struct {
-	Kind          string `json:"kind"`
-	AccountID     string `json:"accountId"`
-	PolicyStoreID string `json:"policyStoreId,omitempty"`
-	Privileged    bool   `json:"privileged"`
-	CreatedAt     string `json:"createdAt"`
-	CreatedBy     string `json:"createdBy"`
+// WithPolicyBackups enables restoring policy stores from scheduled backups
+func (h *AccountsHandler) WithPolicyBackups(backups PolicyBackupReader) *AccountsHandler {
+	h.backups = backups
+	return h
 }
 
-// AccountListResponse is the response for listing accounts
-type AccountListResponse struct {
-	Kind  string            `json:"kind"`
-	Items []AccountResponse `json:"items"`
-	Total int               `json:"total"`
+// PlanCache caches the plans of accounts, such as the plan resolver
+type PlanCache interface {
+	Invalidate(accountID string)
+}
+
+// WithPlanCache forgets an account's cached plan when SetPlan changes it
+func (h *AccountsHandler) WithPlanCache(cache PlanCache) *AccountsHandler {
+	h.planCache = cache
+	return h
 }
 
 // Create handles POST /api/v0/accounts (enable an account)
@@ -55,9 +84,9 @@ func (h *AccountsHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("enabling account", "caller_arn", callerARN)
 
-	var req EnableAccountRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.EnableAccountRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -74,62 +103,189 @@ func (h *AccountsHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	if existing != nil {
+		if onboarding := existing.OnboardingStatus(); onboarding.State == store.OnboardingFailed {
+			h.writeError(w, http.StatusConflict, "account-onboarding-failed",
+				fmt.Sprintf("Account onboarding failed at %s; retry it with POST /api/v0/accounts/%s/onboarding/retry", onboarding.FailedStep, req.AccountID))
+			return
+		}
 		h.writeError(w, http.StatusConflict, "account-exists", "Account is already enabled")
 		return
 	}
 
-	account, err := h.authorizer.EnableAccount(ctx, req.AccountID, callerARN, req.Privileged)
-	if err != nil {
+	// With async=true, the account is saved as Pending and onboarded by a
+	// worker; its progress shows in GET /api/v0/accounts/{id}
+	async := r.URL.Query().Get("async") == "true"
+	status := http.StatusCreated
+	if async {
+		status = http.StatusAccepted
+	}
+
+	if writeDryRun(w, r, status, apiv0.AccountResponse{
+		Kind:       "Account",
+		AccountID:  req.AccountID,
+		Privileged: req.Privileged,
+		CreatedBy:  callerARN,
+	}) {
+		return
+	}
+
+	var account *store.Account
+	if async {
+		account, err = h.authorizer.EnableAccountAsync(ctx, req.AccountID, callerARN, req.Privileged)
+	} else {
+		account, err = h.authorizer.EnableAccount(ctx, req.AccountID, callerARN, req.Privileged)
+	}
+	var onboardingErr *authz.OnboardingError
+	switch {
+	case errors.Is(err, authz.ErrAsyncOnboardingDisabled):
+		h.writeError(w, http.StatusBadRequest, "async-disabled", "Asynchronous account enabling is disabled")
+		return
+	case errors.Is(err, authz.ErrOnboardingQueueFull):
+		w.Header().Set("Retry-After", "60")
+		h.writeError(w, http.StatusServiceUnavailable, "onboarding-queue-full", "Too many accounts are waiting to be onboarded; retry later")
+		return
+	case errors.As(err, &onboardingErr):
+		h.logger.Error("failed to enable account", "error", err, "account_id", req.AccountID)
+		h.writeError(w, http.StatusInternalServerError, "account-onboarding-failed",
+			fmt.Sprintf("Account onboarding failed at %s; retry it with POST /api/v0/accounts/%s/onboarding/retry", onboardingErr.Step, req.AccountID))
+		return
+	case err != nil:
 		h.logger.Error("failed to enable account", "error", err, "account_id", req.AccountID)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to enable account")
 		return
 	}
 
-	h.logger.Info("account enabled", "account_id", req.AccountID, "privileged", req.Privileged)
-
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(AccountResponse{
-		Kind:          "Account",
-		AccountID:     account.AccountID,
-		PolicyStoreID: account.PolicyStoreID,
-		Privileged:    account.Privileged,
-		CreatedAt:     account.CreatedAt,
-		CreatedBy:     account.CreatedBy,
+	h.logger.Info("account enabled", "account_id", req.AccountID, "privileged", req.Privileged, "async", async)
+
+	writeResponse(w, r, status, apiv0.AccountResponse{
+		Kind:           "Account",
+		AccountID:      account.AccountID,
+		PolicyStoreID:  account.PolicyStoreID,
+		Privileged:     account.Privileged,
+		CreatedAt:      account.CreatedAt,
+		CreatedBy:      account.CreatedBy,
+		OrganizationID: account.OrganizationID,
+		Onboarding:     account.OnboardingStatus(),
 	})
 }
 
 // List handles GET /api/v0/accounts
+// Results are paged with limit and pageToken, and can be filtered with
+// privileged=true|false and createdAfter (RFC3339).
 func (h *AccountsHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	accounts, err := h.authorizer.ListAccounts(ctx)
+	limit, err := pageSize(r, "limit", h.pageLimits)
 	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
+	}
+	filter, err := accountFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-filter", err.Error())
+		return
+	}
+
+	page, err := h.authorizer.ListAccountsPage(ctx, limit, r.URL.Query().Get("pageToken"), filter)
+	if err != nil {
+		if errors.Is(err, store.ErrInvalidPageToken) {
+			h.writeError(w, http.StatusBadRequest, "invalid-page-token", "pageToken is not valid")
+			return
+		}
 		h.logger.Error("failed to list accounts", "error", err)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list accounts")
 		return
 	}
 
-	items := make([]AccountResponse, len(accounts))
-	for i, acc := range accounts {
-		items[i] = AccountResponse{
-			Kind:          "Account",
-			AccountID:     acc.AccountID,
-			PolicyStoreID: acc.PolicyStoreID,
-			Privileged:    acc.Privileged,
-			CreatedAt:     acc.CreatedAt,
-			CreatedBy:     acc.CreatedBy,
+	items := make([]apiv0.AccountResponse, len(page.Accounts))
+	for i, acc := range page.Accounts {
+		items[i] = apiv0.AccountResponse{
+			Kind:           "Account",
+			AccountID:      acc.AccountID,
+			PolicyStoreID:  acc.PolicyStoreID,
+			Privileged:     acc.Privileged,
+			CreatedAt:      acc.CreatedAt,
+			CreatedBy:      acc.CreatedBy,
+			OrganizationID: acc.OrganizationID,
+			RequiredTags:   acc.RequiredTags,
+			ChangeReview:   acc.ChangeReview,
+			Plan:           acc.Plan,
+			PinnedRegion:   acc.PinnedRegion,
+			ExportControl:  acc.ExportControl,
+			Onboarding:     acc.OnboardingStatus(),
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(AccountListResponse{
-		Kind:  "AccountList",
-		Items: items,
-		Total: len(items),
+	if page.NextToken != "" {
+		setPageLinks(w, r, url.Values{"pageToken": {page.NextToken}}, nil)
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.AccountListResponse{
+		Kind:          "AccountList",
+		Items:         items,
+		Total:         len(items),
+		NextPageToken: page.NextToken,
 	})
 }
 
+// Count handles GET /api/v0/accounts/count
+// It accepts the same filters as List.
+func (h *AccountsHandler) Count(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, err := accountFilter(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-filter", err.Error())
+		return
+	}
+
+	count, err := h.authorizer.CountAccounts(ctx, filter)
+	if err != nil {
+		h.logger.Error("failed to count accounts", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to count accounts")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.AccountCountResponse{
+		Kind:  "AccountCount",
+		Count: count,
+	})
+}
+
+// accountFilter reads the account listing filters from the query string
+func accountFilter(r *http.Request) (store.AccountFilter, error) {
+	var filter store.AccountFilter
+	query := r.URL.Query()
+
+	switch v := query.Get("privileged"); v {
+	case "":
+	case "true", "false":
+		privileged := v == "true"
+		filter.Privileged = &privileged
+	default:
+		return filter, errors.New("privileged must be true or false")
+	}
+
+	if v := query.Get("createdAfter"); v != "" {
+		after, err := clock.Parse(v)
+		if err != nil {
+			return filter, errors.New("createdAfter must be an RFC3339 time")
+		}
+		// createdAt is stored as an API timestamp, so it compares as a string
+		filter.CreatedAfter = clock.Format(after)
+	}
+
+	filter.PinnedRegion = query.Get("pinnedRegion")
+	if problem := residency.ValidateRegion(filter.PinnedRegion); problem != "" {
+		return filter, errors.New(problem)
+	}
+	filter.ExportControl = query.Get("exportControl")
+	if problem := residency.ValidateExportControl(filter.ExportControl); problem != "" {
+		return filter, errors.New(problem)
+	}
+
+	return filter, nil
+}
+
 // Get handles GET /api/v0/accounts/{id}
 func (h *AccountsHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -148,17 +304,295 @@ func (h *AccountsHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(AccountResponse{
-		Kind:          "Account",
-		AccountID:     account.AccountID,
-		PolicyStoreID: account.PolicyStoreID,
-		Privileged:    account.Privileged,
-		CreatedAt:     account.CreatedAt,
-		CreatedBy:     account.CreatedBy,
+	writeResponse(w, r, http.StatusOK, apiv0.AccountResponse{
+		Kind:           "Account",
+		AccountID:      account.AccountID,
+		PolicyStoreID:  account.PolicyStoreID,
+		Privileged:     account.Privileged,
+		CreatedAt:      account.CreatedAt,
+		CreatedBy:      account.CreatedBy,
+		OrganizationID: account.OrganizationID,
+		RequiredTags:   account.RequiredTags,
+		ChangeReview:   account.ChangeReview,
+		Plan:           account.Plan,
+		Onboarding:     account.OnboardingStatus(),
 	})
 }
 
+// RetryOnboarding handles POST /api/v0/accounts/{id}/onboarding/retry
+// It resumes a failed onboarding from the step it failed at, or one stalled
+// in a step for several minutes, and returns the onboarded account. A dry run
+// only reports whether the onboarding can be retried.
+func (h *AccountsHandler) RetryOnboarding(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get account")
+		return
+	}
+	if account == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+
+	h.logger.Info("retrying account onboarding", "account_id", accountID, "caller_arn", middleware.GetCallerARN(ctx),
+		"state", account.OnboardingStatus().State)
+
+	if middleware.IsDryRun(ctx) {
+		if !authz.OnboardingRetryable(account.OnboardingStatus(), time.Now()) {
+			h.writeError(w, http.StatusConflict, "onboarding-not-retryable", "Account onboarding is neither failed nor stalled")
+			return
+		}
+		writeDryRun(w, r, http.StatusOK, accountResponse(account))
+		return
+	}
+
+	account, err = h.authorizer.RetryOnboarding(ctx, accountID)
+	var onboardingErr *authz.OnboardingError
+	switch {
+	case errors.Is(err, authz.ErrOnboardingNotRetryable):
+		h.writeError(w, http.StatusConflict, "onboarding-not-retryable", "Account onboarding is neither failed nor stalled")
+		return
+	case errors.Is(err, store.ErrOnboardingChanged):
+		h.writeError(w, http.StatusConflict, "onboarding-in-progress", "Account onboarding is being retried by another request")
+		return
+	case errors.As(err, &onboardingErr):
+		h.logger.Error("account onboarding failed again", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "account-onboarding-failed", fmt.Sprintf("Account onboarding failed at %s", onboardingErr.Step))
+		return
+	case err != nil:
+		h.logger.Error("failed to retry account onboarding", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to retry account onboarding")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.AccountResponse{
+		Kind:           "Account",
+		AccountID:      account.AccountID,
+		PolicyStoreID:  account.PolicyStoreID,
+		Privileged:     account.Privileged,
+		CreatedAt:      account.CreatedAt,
+		CreatedBy:      account.CreatedBy,
+		OrganizationID: account.OrganizationID,
+		RequiredTags:   account.RequiredTags,
+		ChangeReview:   account.ChangeReview,
+		Plan:           account.Plan,
+		Onboarding:     account.OnboardingStatus(),
+	})
+}
+
+// SetRequiredTags handles PUT /api/v0/accounts/{id}/required_tags
+// The list replaces the account's required tags; an empty list clears them.
+func (h *AccountsHandler) SetRequiredTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	var req apiv0.SetRequiredTagsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	for _, key := range req.RequiredTags {
+		if err := middleware.ValidateRequestTagKey(key); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-tag-key", err.Error())
+			return
+		}
+	}
+
+	if middleware.IsDryRun(ctx) {
+		if account, ok := h.getAccount(w, ctx, accountID); ok {
+			account.RequiredTags = req.RequiredTags
+			writeDryRun(w, r, http.StatusOK, accountResponse(account))
+		}
+		return
+	}
+
+	account, err := h.authorizer.SetAccountRequiredTags(ctx, accountID, req.RequiredTags)
+	if errors.Is(err, authz.ErrAccountNotEnabled) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to set account required tags", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update account required tags")
+		return
+	}
+
+	h.logger.Info("account required tags updated",
+		"account_id", accountID,
+		"required_tags", account.RequiredTags,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	writeResponse(w, r, http.StatusOK, apiv0.AccountResponse{
+		Kind:           "Account",
+		AccountID:      account.AccountID,
+		PolicyStoreID:  account.PolicyStoreID,
+		Privileged:     account.Privileged,
+		CreatedAt:      account.CreatedAt,
+		CreatedBy:      account.CreatedBy,
+		OrganizationID: account.OrganizationID,
+		RequiredTags:   account.RequiredTags,
+		ChangeReview:   account.ChangeReview,
+		Plan:           account.Plan,
+	})
+}
+
+// SetChangeReview handles PUT /api/v0/accounts/{id}/change_review
+// While change review is on, the account's authz mutations are staged as
+// change requests for another admin to approve.
+func (h *AccountsHandler) SetChangeReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	var req apiv0.SetChangeReviewRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if req.Enabled == nil {
+		h.writeError(w, http.StatusBadRequest, "missing-enabled", "enabled is required")
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		if account, ok := h.getAccount(w, ctx, accountID); ok {
+			account.ChangeReview = *req.Enabled
+			writeDryRun(w, r, http.StatusOK, accountResponse(account))
+		}
+		return
+	}
+
+	account, err := h.authorizer.SetAccountChangeReview(ctx, accountID, *req.Enabled)
+	if errors.Is(err, authz.ErrAccountNotEnabled) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to set account change review", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update account change review")
+		return
+	}
+
+	h.logger.Info("account change review updated",
+		"account_id", accountID,
+		"enabled", account.ChangeReview,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	writeResponse(w, r, http.StatusOK, apiv0.AccountResponse{
+		Kind:           "Account",
+		AccountID:      account.AccountID,
+		PolicyStoreID:  account.PolicyStoreID,
+		Privileged:     account.Privileged,
+		CreatedAt:      account.CreatedAt,
+		CreatedBy:      account.CreatedBy,
+		OrganizationID: account.OrganizationID,
+		RequiredTags:   account.RequiredTags,
+		ChangeReview:   account.ChangeReview,
+		Plan:           account.Plan,
+	})
+}
+
+// SetPlan handles PUT /api/v0/accounts/{id}/plan
+// The plan sets the account's rate limit, quotas and features; other
+// replicas apply it once their cached plan expires.
+func (h *AccountsHandler) SetPlan(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	var req apiv0.SetPlanRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if !plans.Valid(req.Plan) {
+		h.writeError(w, http.StatusBadRequest, "invalid-plan",
+			fmt.Sprintf("plan must be one of %s", strings.Join(plans.Names, ", ")))
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		if account, ok := h.getAccount(w, ctx, accountID); ok {
+			account.Plan = req.Plan
+			writeDryRun(w, r, http.StatusOK, accountResponse(account))
+		}
+		return
+	}
+
+	account, err := h.authorizer.SetAccountPlan(ctx, accountID, req.Plan)
+	if errors.Is(err, authz.ErrAccountNotEnabled) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to set account plan", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update account plan")
+		return
+	}
+	if h.planCache != nil {
+		h.planCache.Invalidate(accountID)
+	}
+
+	h.logger.Info("account plan updated",
+		"account_id", accountID,
+		"plan", account.Plan,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	writeResponse(w, r, http.StatusOK, accountResponse(account))
+}
+
+// SetResidency handles PUT /api/v0/accounts/{id}/residency
+func (h *AccountsHandler) SetResidency(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	var req apiv0.SetResidencyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	for _, problem := range []string{residency.ValidateRegion(req.PinnedRegion), residency.ValidateExportControl(req.ExportControl)} {
+		if problem != "" {
+			h.writeError(w, http.StatusBadRequest, "invalid-residency", problem)
+			return
+		}
+	}
+
+	if middleware.IsDryRun(ctx) {
+		if account, ok := h.getAccount(w, ctx, accountID); ok {
+			account.PinnedRegion = req.PinnedRegion
+			account.ExportControl = req.ExportControl
+			writeDryRun(w, r, http.StatusOK, accountResponse(account))
+		}
+		return
+	}
+
+	account, err := h.authorizer.SetAccountResidency(ctx, accountID, req.PinnedRegion, req.ExportControl)
+	if errors.Is(err, authz.ErrAccountNotEnabled) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to set account residency", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update account residency")
+		return
+	}
+
+	h.logger.Info("account residency updated",
+		"account_id", accountID,
+		"pinned_region", account.PinnedRegion,
+		"export_control", account.ExportControl,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	writeResponse(w, r, http.StatusOK, accountResponse(account))
+}
+
 // Delete handles DELETE /api/v0/accounts/{id}
 func (h *AccountsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -168,7 +602,18 @@ func (h *AccountsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("disabling account", "account_id", accountID, "caller_arn", callerARN)
 
-	err := h.authorizer.DisableAccount(ctx, accountID)
+	if middleware.IsDryRun(ctx) {
+		if account, ok := h.getAccount(w, ctx, accountID); ok {
+			writeDryRun(w, r, http.StatusNoContent, accountResponse(account))
+		}
+		return
+	}
+
+	err := h.authorizer.DisableAccount(ctx, accountID, callerARN)
+	if writeApprovalRequired(w, r, err) {
+		h.logger.Info("account disable awaits approval", "account_id", accountID)
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to disable account", "error", err, "account_id", accountID)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to disable account")
@@ -180,15 +625,320 @@ func (h *AccountsHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// SearchByPrincipal handles GET /api/v0/admin/accounts?principalArn=...
+// It returns every account where the principal is an admin or group member.
+func (h *AccountsHandler) SearchByPrincipal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	principalARN := r.URL.Query().Get("principalArn")
+	if principalARN == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-principal-arn", "principalArn is required")
+		return
+	}
+
+	h.logger.Info("searching accounts by principal", "principal_arn", principalARN, "caller_arn", middleware.GetCallerARN(ctx))
+
+	accounts, err := h.authorizer.FindPrincipalAccounts(ctx, principalARN)
+	if err != nil {
+		h.logger.Error("failed to search accounts by principal", "error", err, "principal_arn", principalARN)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to search accounts")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.PrincipalAccountListResponse{
+		Kind:         "PrincipalAccountList",
+		PrincipalARN: principalARN,
+		Items:        emptyIfNil(accounts),
+		Total:        len(accounts),
+	})
+}
+
+// ListPolicyBackups handles GET /api/v0/admin/accounts/{id}/policy_backups
+func (h *AccountsHandler) ListPolicyBackups(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	if h.backups == nil {
+		h.writeError(w, http.StatusNotFound, "backups-disabled", "Policy store backups are not enabled")
+		return
+	}
+
+	backups, err := h.backups.List(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to list policy store backups", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list policy store backups")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.PolicyBackupListResponse{
+		Kind:  "PolicyStoreBackupList",
+		Items: emptyIfNil(backups),
+		Total: len(backups),
+	})
+}
+
+// ListDeletions handles GET /api/v0/admin/accounts/{id}/deletions
+// Tombstones are returned newest first, up to limit, and can be filtered with
+// kind=policy|group|attachment.
+func (h *AccountsHandler) ListDeletions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	limit, err := pageSize(r, "limit", h.pageLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
+	}
+	kind := r.URL.Query().Get("kind")
+	switch kind {
+	case "", store.DeletedPolicy, store.DeletedGroup, store.DeletedAttachment:
+	default:
+		h.writeError(w, http.StatusBadRequest, "invalid-filter", "kind must be policy, group or attachment")
+		return
+	}
+
+	tombstones, err := h.authorizer.ListDeletions(ctx, accountID, kind, limit)
+	if err != nil {
+		h.logger.Error("failed to list deletions", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list deletions")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.DeletionListResponse{
+		Kind:  "DeletionList",
+		Items: emptyIfNil(tombstones),
+		Total: len(tombstones),
+	})
+}
+
+// RebuildPolicyStore handles POST /api/v0/admin/accounts/{id}/rebuild_policy_store
+// The optional request body is a policy store export. Alternatively the
+// backup query parameter restores a scheduled backup, either "latest" or a
+// key from ListPolicyBackups. With neither, the current store is exported
+// first, which fails if the store has been deleted.
+func (h *AccountsHandler) RebuildPolicyStore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+	callerARN := middleware.GetCallerARN(ctx)
+
+	h.logger.Info("rebuilding account policy store", "account_id", accountID, "caller_arn", callerARN)
+
+	var export *authz.PolicyStoreExport
+	var body authz.PolicyStoreExport
+	switch err := decodeJSON(r, &body); {
+	case errors.Is(err, io.EOF):
+		// No body: the current store is exported
+	case err != nil:
+		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid policy store export: "+err.Error())
+		return
+	default:
+		export = &body
+	}
+	backupKey := r.URL.Query().Get("backup")
+	if backupKey != "" {
+		if export != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", "Provide either an export in the request body or a backup, not both")
+			return
+		}
+		if h.backups == nil {
+			h.writeError(w, http.StatusBadRequest, "backups-disabled", "Policy store backups are not enabled")
+			return
+		}
+	}
+	if export != nil && export.AccountID != "" && export.AccountID != accountID {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", "Export belongs to a different account")
+		return
+	}
+
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get account")
+		return
+	}
+	if account == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if account.Privileged {
+		h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts have no policy store")
+		return
+	}
+
+	if backupKey != "" {
+		var ok bool
+		export, ok = h.loadBackup(w, r, accountID, backupKey)
+		if !ok {
+			return
+		}
+	}
+
+	if middleware.IsDryRun(ctx) {
+		h.dryRunRebuildPolicyStore(w, r, account, export)
+		return
+	}
+
+	result, err := h.authorizer.RebuildPolicyStore(ctx, accountID, export)
+	if err != nil {
+		h.logger.Error("failed to rebuild policy store", "error", err, "account_id", accountID)
+		reason := "Failed to rebuild policy store"
+		if export == nil {
+			reason += "; if the current store was deleted, provide an export in the request body"
+		}
+		h.writeError(w, http.StatusInternalServerError, "rebuild-failed", reason)
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.RebuildPolicyStoreResponse{
+		Kind:          "PolicyStoreRebuild",
+		RebuildResult: result,
+	})
+}
+
+// MigrateSchema handles POST /api/v0/admin/accounts/{id}/migrate_schema
+// The account's policy store is given the current Cedar schema. Migrating a
+// store already at the current version changes nothing.
+func (h *AccountsHandler) MigrateSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get account")
+		return
+	}
+	if account == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if account.Privileged {
+		h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts have no policy store")
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		from := authz.StoreSchemaVersion(account.SchemaVersion)
+		writeDryRun(w, r, http.StatusOK, apiv0.SchemaMigrationResponse{
+			Kind: "PolicyStoreSchemaMigration",
+			SchemaMigration: &authz.SchemaMigration{
+				AccountID:     accountID,
+				PolicyStoreID: account.PolicyStoreID,
+				FromVersion:   from,
+				ToVersion:     max(from, schema.Version),
+				Migrated:      from < schema.Version,
+			},
+		})
+		return
+	}
+
+	h.logger.Info("migrating account policy store schema", "account_id", accountID, "caller_arn", middleware.GetCallerARN(ctx))
+	migration, err := h.authorizer.MigrateSchema(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to migrate policy store schema", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "migration-failed", "Failed to migrate policy store schema")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.SchemaMigrationResponse{
+		Kind:            "PolicyStoreSchemaMigration",
+		SchemaMigration: migration,
+	})
+}
+
+// dryRunRebuildPolicyStore previews rebuilding from export, exporting the
+// current store when there is none so an unreadable store fails as it would
+func (h *AccountsHandler) dryRunRebuildPolicyStore(w http.ResponseWriter, r *http.Request, account *store.Account, export *authz.PolicyStoreExport) {
+	if export == nil {
+		var err error
+		export, err = h.authorizer.ExportPolicyStore(r.Context(), account.AccountID)
+		if err != nil {
+			h.logger.Error("failed to export policy store", "error", err, "account_id", account.AccountID)
+			h.writeError(w, http.StatusInternalServerError, "rebuild-failed",
+				"Failed to rebuild policy store; if the current store was deleted, provide an export in the request body")
+			return
+		}
+	}
+	writeDryRun(w, r, http.StatusOK, apiv0.RebuildPolicyStoreResponse{
+		Kind: "PolicyStoreRebuild",
+		RebuildResult: &authz.RebuildResult{
+			AccountID:             account.AccountID,
+			PreviousPolicyStoreID: account.PolicyStoreID,
+			Policies:              len(export.Policies),
+			Attachments:           len(export.Attachments),
+		},
+	})
+}
+
+// getAccount returns an account for a dry run, answering 404 when it is not
+// enabled
+func (h *AccountsHandler) getAccount(w http.ResponseWriter, ctx context.Context, accountID string) (*store.Account, bool) {
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get account")
+		return nil, false
+	}
+	if account == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return nil, false
+	}
+	return account, true
+}
+
+func accountResponse(account *store.Account) apiv0.AccountResponse {
+	return apiv0.AccountResponse{
+		Kind:           "Account",
+		AccountID:      account.AccountID,
+		PolicyStoreID:  account.PolicyStoreID,
+		Privileged:     account.Privileged,
+		CreatedAt:      account.CreatedAt,
+		CreatedBy:      account.CreatedBy,
+		OrganizationID: account.OrganizationID,
+		RequiredTags:   account.RequiredTags,
+		ChangeReview:   account.ChangeReview,
+		Plan:           account.Plan,
+		PinnedRegion:   account.PinnedRegion,
+		ExportControl:  account.ExportControl,
+	}
+}
+
+// loadBackup reads the backup named by key ("latest" for the newest) and
+// writes an error response if it cannot
+func (h *AccountsHandler) loadBackup(w http.ResponseWriter, r *http.Request, accountID, key string) (*authz.PolicyStoreExport, bool) {
+	ctx := r.Context()
+
+	backups, err := h.backups.List(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to list policy store backups", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list policy store backups")
+		return nil, false
+	}
+
+	found := false
+	if key == "latest" && len(backups) > 0 {
+		key = backups[len(backups)-1].Key
+		found = true
+	}
+	for _, b := range backups {
+		found = found || b.Key == key
+	}
+	if !found {
+		h.writeError(w, http.StatusNotFound, "backup-not-found", "Policy store backup not found")
+		return nil, false
+	}
+
+	export, err := h.backups.Get(ctx, accountID, key)
+	if err != nil {
+		h.logger.Error("failed to read policy store backup", "error", err, "account_id", accountID, "key", key)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to read policy store backup")
+		return nil, false
+	}
+
+	h.logger.Info("restoring policy store from backup", "account_id", accountID, "key", key)
+	return export, true
+}
+
 func (h *AccountsHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
-	resp := map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// Bootstrap handles POST /api/v0/bootstrap/privileged_account
+// A super admin, declared in configuration rather than in DynamoDB, enables
+// the region's first privileged account, which then manages every other
+// account through the privileged routes. Once any privileged account exists
+// the endpoint returns 409, so super admins cannot mint more; two concurrent
+// first calls may both succeed, which only super admins can cause.
+func (h *AccountsHandler) Bootstrap(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	callerARN := middleware.GetCallerARN(ctx)
+
+	var req apiv0.BootstrapAccountRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if req.AccountID == "" {
+		req.AccountID = middleware.GetAccountID(ctx)
+	}
+
+	h.logger.Info("bootstrapping privileged account", "account_id", req.AccountID, "caller_arn", callerARN)
+
+	privileged := true
+	count, err := h.authorizer.CountAccounts(ctx, store.AccountFilter{Privileged: &privileged})
+	if err != nil {
+		h.logger.Error("failed to count privileged accounts", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check privileged accounts")
+		return
+	}
+	if count > 0 {
+		h.writeError(w, http.StatusConflict, "already-bootstrapped", "A privileged account already exists; manage accounts with POST /api/v0/accounts")
+		return
+	}
+
+	existing, err := h.authorizer.GetAccount(ctx, req.AccountID)
+	if err != nil {
+		h.logger.Error("failed to check existing account", "error", err, "account_id", req.AccountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check account status")
+		return
+	}
+	if existing != nil {
+		h.writeError(w, http.StatusConflict, "account-exists", "Account is already enabled without privileges; bootstrap another account")
+		return
+	}
+
+	if writeDryRun(w, r, http.StatusCreated, apiv0.AccountResponse{
+		Kind:       "Account",
+		AccountID:  req.AccountID,
+		Privileged: true,
+		CreatedBy:  callerARN,
+	}) {
+		return
+	}
+
+	account, err := h.authorizer.EnableAccount(ctx, req.AccountID, callerARN, true)
+	if err != nil {
+		h.logger.Error("failed to bootstrap privileged account", "error", err, "account_id", req.AccountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to enable account")
+		return
+	}
+
+	h.logger.Warn("privileged account bootstrapped", "account_id", account.AccountID, "caller_arn", callerARN)
+	writeResponse(w, r, http.StatusCreated, accountResponse(account))
+}
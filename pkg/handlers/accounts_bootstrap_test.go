@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+type bootstrapAccountService struct {
+	authz.Service
+	accounts map[string]*store.Account
+}
+
+func (s *bootstrapAccountService) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	return s.accounts[accountID], nil
+}
+
+func (s *bootstrapAccountService) CountAccounts(ctx context.Context, filter store.AccountFilter) (int, error) {
+	count := 0
+	for _, account := range s.accounts {
+		if filter.Privileged == nil || account.Privileged == *filter.Privileged {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (s *bootstrapAccountService) EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error) {
+	account := &store.Account{AccountID: accountID, Privileged: isPrivileged, CreatedBy: createdBy}
+	s.accounts[accountID] = account
+	return account, nil
+}
+
+func TestAccountsHandler_Bootstrap(t *testing.T) {
+	const callerARN = "arn:aws:iam::123456789012:role/platform-admin"
+
+	tests := []struct {
+		name        string
+		accounts    map[string]*store.Account
+		body        string
+		wantStatus  int
+		wantAccount string
+	}{
+		{name: "caller account", body: `{}`, wantStatus: http.StatusCreated, wantAccount: "123456789012"},
+		{name: "named account", body: `{"accountId":"210987654321"}`, wantStatus: http.StatusCreated, wantAccount: "210987654321"},
+		{
+			name:       "already bootstrapped",
+			accounts:   map[string]*store.Account{"999999999999": {AccountID: "999999999999", Privileged: true}},
+			body:       `{}`,
+			wantStatus: http.StatusConflict,
+		},
+		{
+			name:       "account exists",
+			accounts:   map[string]*store.Account{"123456789012": {AccountID: "123456789012"}},
+			body:       `{}`,
+			wantStatus: http.StatusConflict,
+		},
+		{name: "invalid body", body: `{"accountId":`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &bootstrapAccountService{accounts: map[string]*store.Account{}}
+			for id, account := range tt.accounts {
+				service.accounts[id] = account
+			}
+			handler := NewAccountsHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/bootstrap/privileged_account", strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012")
+			ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, callerARN)
+			rec := httptest.NewRecorder()
+
+			handler.Bootstrap(rec, req.WithContext(ctx))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantAccount == "" {
+				return
+			}
+			var resp apiv0.AccountResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.AccountID != tt.wantAccount || !resp.Privileged || resp.CreatedBy != callerARN {
+				t.Errorf("unexpected account %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+)
+
+// AccountPurger deletes everything held about an account, such as
+// purge.Purger
+type AccountPurger interface {
+	Targets() []string
+	Purge(ctx context.Context, accountID, purgedBy string) (*purge.Report, error)
+}
+
+// WithPurger enables purging accounts for tenant offboarding. With a
+// registry, purges can run asynchronously as operations.
+func (h *AccountsHandler) WithPurger(purger AccountPurger, ops *operations.Registry) *AccountsHandler {
+	h.purger = purger
+	h.operations = ops
+	return h
+}
+
+// Purge handles POST /api/v0/admin/accounts/{id}/purge
+// Every record of the account is deleted, including its policy store, work
+// records, backups and audit entries, and a deletion report signed with the
+// purge KMS key is returned. Accounts that no longer exist are purged too, so
+// an incomplete purge can be retried; an incomplete report is returned with
+// status 500. With async=true the purge runs as an operation and 202 is
+// returned with it; the report is the operation's result.
+func (h *AccountsHandler) Purge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+	callerARN := middleware.GetCallerARN(ctx)
+
+	if h.purger == nil {
+		h.writeError(w, http.StatusNotFound, "purge-disabled", "Account purges are not enabled")
+		return
+	}
+
+	var req apiv0.PurgeAccountRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if req.Confirm != accountID {
+		h.writeError(w, http.StatusBadRequest, "confirmation-required", "confirm must repeat the account ID")
+		return
+	}
+
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get account")
+		return
+	}
+	if account != nil && account.Privileged {
+		h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts cannot be purged")
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		preview := &purge.Report{Kind: "AccountPurgeReport", AccountID: accountID, PurgedBy: callerARN}
+		for _, target := range h.purger.Targets() {
+			preview.Items = append(preview.Items, purge.Item{Target: target})
+		}
+		writeDryRun(w, r, http.StatusOK, preview)
+		return
+	}
+
+	h.logger.Info("purging account", "account_id", accountID, "caller_arn", callerARN)
+
+	if r.URL.Query().Get("async") == "true" {
+		if h.operations == nil {
+			h.writeError(w, http.StatusBadRequest, "async-disabled", "Asynchronous account purges are disabled")
+			return
+		}
+		op := h.operations.Go(ctx, operations.Operation{
+			Type:      operations.TypeAccountPurge,
+			State:     operations.StateRunning,
+			AccountID: accountID,
+			CallerARN: callerARN,
+		}, func(ctx context.Context) (any, error) {
+			report, err := h.purger.Purge(ctx, accountID, callerARN)
+			if err == nil && !report.Complete {
+				err = errors.New("some account data could not be purged; purge the account again")
+			}
+			h.logger.Info("account purged", "account_id", accountID, "complete", report.Complete, "operation_id", operations.ID(ctx), "error", err)
+			return report, err
+		})
+		writeResponse(w, r, http.StatusAccepted, apiv0.OperationResponse{Kind: "Operation", Operation: op})
+		return
+	}
+
+	report, err := h.purger.Purge(ctx, accountID, callerARN)
+	if err != nil {
+		h.logger.Error("failed to sign deletion report", "error", err, "account_id", accountID, "complete", report.Complete)
+		h.writeError(w, http.StatusInternalServerError, "signing-failed", "The account was purged but the deletion report could not be signed; purge it again for a signed report")
+		return
+	}
+
+	status := http.StatusOK
+	if !report.Complete {
+		status = http.StatusInternalServerError
+	}
+	h.logger.Info("account purged", "account_id", accountID, "complete", report.Complete, "caller_arn", callerARN)
+	writeResponse(w, r, status, report)
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+)
+
+type purgeAccountService struct {
+	authz.Service
+	accounts map[string]*store.Account
+}
+
+func (s *purgeAccountService) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	return s.accounts[accountID], nil
+}
+
+type fakePurger struct {
+	failing string
+	purged  []string
+}
+
+func (f *fakePurger) Targets() []string {
+	return []string{"work-metadata", "authz-account"}
+}
+
+func (f *fakePurger) Purge(ctx context.Context, accountID, purgedBy string) (*purge.Report, error) {
+	f.purged = append(f.purged, accountID)
+	report := &purge.Report{Kind: "AccountPurgeReport", AccountID: accountID, PurgedBy: purgedBy, Complete: true}
+	for _, target := range f.Targets() {
+		item := purge.Item{Target: target, Deleted: 1}
+		if target == f.failing {
+			item.Error = "throttled"
+			report.Complete = false
+		}
+		report.Items = append(report.Items, item)
+	}
+	report.Signature = &purge.Signature{KeyID: "alias/purge", Algorithm: purge.SigningAlgorithm}
+	return report, nil
+}
+
+func TestAccountsHandler_Purge(t *testing.T) {
+	service := &purgeAccountService{accounts: map[string]*store.Account{
+		"111111111111": {AccountID: "111111111111"},
+		"999999999999": {AccountID: "999999999999", Privileged: true},
+	}}
+
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		failing    string
+		wantStatus int
+		wantPurged bool
+	}{
+		{name: "purged", id: "111111111111", body: `{"confirm":"111111111111"}`, wantStatus: http.StatusOK, wantPurged: true},
+		{name: "already removed", id: "222222222222", body: `{"confirm":"222222222222"}`, wantStatus: http.StatusOK, wantPurged: true},
+		{name: "incomplete", id: "111111111111", body: `{"confirm":"111111111111"}`, failing: "work-metadata", wantStatus: http.StatusInternalServerError, wantPurged: true},
+		{name: "not confirmed", id: "111111111111", body: `{"confirm":"222222222222"}`, wantStatus: http.StatusBadRequest},
+		{name: "no body", id: "111111111111", wantStatus: http.StatusBadRequest},
+		{name: "privileged", id: "999999999999", body: `{"confirm":"999999999999"}`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			purger := &fakePurger{failing: tt.failing}
+			handler := NewAccountsHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil))).WithPurger(purger, nil)
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/admin/accounts/"+tt.id+"/purge", strings.NewReader(tt.body))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rec := httptest.NewRecorder()
+
+			handler.Purge(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if (len(purger.purged) == 1) != tt.wantPurged {
+				t.Fatalf("purged %v, want purged %v", purger.purged, tt.wantPurged)
+			}
+			if !tt.wantPurged {
+				return
+			}
+			var report purge.Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("failed to decode report: %v", err)
+			}
+			if report.AccountID != tt.id || report.Complete != (tt.failing == "") || report.Signature == nil {
+				t.Errorf("unexpected report %+v", report)
+			}
+		})
+	}
+}
+
+func TestAccountsHandler_Purge_Async(t *testing.T) {
+	service := &purgeAccountService{accounts: map[string]*store.Account{"111111111111": {AccountID: "111111111111"}}}
+	purger := &fakePurger{failing: "work-metadata"}
+	registry := operations.NewRegistry().WithResultTTL(time.Hour)
+	handler := NewAccountsHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil))).WithPurger(purger, registry)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/admin/accounts/111111111111/purge?async=true", strings.NewReader(`{"confirm":"111111111111"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "111111111111"})
+	rec := httptest.NewRecorder()
+
+	handler.Purge(rec, req)
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusAccepted, rec.Body.String())
+	}
+	var started apiv0.OperationResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &started); err != nil {
+		t.Fatalf("failed to decode operation: %v", err)
+	}
+	if started.Type != operations.TypeAccountPurge || started.AccountID != "111111111111" {
+		t.Fatalf("unexpected operation %+v", started)
+	}
+
+	for range 100 {
+		if op, _ := registry.Get(started.ID); operations.Finished(op.State) {
+			report, ok := op.Result.(*purge.Report)
+			if op.State != operations.StateFailed || !ok || report.Complete {
+				t.Errorf("expected the incomplete purge to fail with its report, got %+v", op)
+			}
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("the purge operation did not finish")
+}
+
+func TestAccountsHandler_Purge_Disabled(t *testing.T) {
+	handler := NewAccountsHandler(&purgeAccountService{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/admin/accounts/111111111111/purge", strings.NewReader(`{"confirm":"111111111111"}`))
+	req = mux.SetURLVars(req, map[string]string{"id": "111111111111"})
+	rec := httptest.NewRecorder()
+
+	handler.Purge(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
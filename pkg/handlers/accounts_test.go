@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+type onboardingAccountService struct {
+	authz.Service
+	accounts map[string]*store.Account
+	retried  []string
+}
+
+func (s *onboardingAccountService) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	return s.accounts[accountID], nil
+}
+
+func (s *onboardingAccountService) RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error) {
+	s.retried = append(s.retried, accountID)
+	account := s.accounts[accountID]
+	account.Onboarding = &store.Onboarding{State: store.OnboardingReady}
+	return account, nil
+}
+
+func TestAccountsHandler_RetryOnboarding_DryRun(t *testing.T) {
+	stalledAt := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339Nano)
+
+	tests := []struct {
+		name       string
+		onboarding *store.Onboarding
+		wantStatus int
+	}{
+		{name: "failed", onboarding: &store.Onboarding{State: store.OnboardingFailed, FailedStep: store.OnboardingProvisioningStore}, wantStatus: http.StatusOK},
+		{name: "stalled", onboarding: &store.Onboarding{State: store.OnboardingProvisioningStore, UpdatedAt: stalledAt}, wantStatus: http.StatusOK},
+		{name: "ready", onboarding: &store.Onboarding{State: store.OnboardingReady}, wantStatus: http.StatusConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &onboardingAccountService{accounts: map[string]*store.Account{
+				"111111111111": {AccountID: "111111111111", Onboarding: tt.onboarding},
+			}}
+			handler := NewAccountsHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/accounts/111111111111/onboarding/retry?dryRun=true", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": "111111111111"})
+			req = req.WithContext(middleware.WithDryRun(req.Context()))
+			rec := httptest.NewRecorder()
+
+			handler.RetryOnboarding(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if len(service.retried) != 0 {
+				t.Errorf("dry run retried onboarding of %v", service.retried)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var result apiv0.DryRunResult
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("failed to decode dry run: %v", err)
+			}
+			if result.Kind != "DryRun" || result.Status != http.StatusOK {
+				t.Errorf("unexpected dry run %+v", result)
+			}
+		})
+	}
+}
+
+func TestAccountsHandler_RetryOnboarding(t *testing.T) {
+	service := &onboardingAccountService{accounts: map[string]*store.Account{
+		"111111111111": {AccountID: "111111111111", Onboarding: &store.Onboarding{State: store.OnboardingFailed}},
+	}}
+	handler := NewAccountsHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/accounts/111111111111/onboarding/retry", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": "111111111111"})
+	rec := httptest.NewRecorder()
+
+	handler.RetryOnboarding(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body.String())
+	}
+	if len(service.retried) != 1 {
+		t.Errorf("retried %v, want one retry", service.retried)
+	}
+}
+
+type principalAccountsService struct {
+	authz.Service
+	accounts map[string][]*authz.PrincipalAccount
+}
+
+func (s *principalAccountsService) FindPrincipalAccounts(ctx context.Context, principalARN string) ([]*authz.PrincipalAccount, error) {
+	return s.accounts[principalARN], nil
+}
+
+func TestAccountsHandler_SearchByPrincipal(t *testing.T) {
+	service := &principalAccountsService{accounts: map[string][]*authz.PrincipalAccount{
+		"arn:aws:iam::123456789012:user/alice": {
+			{AccountID: "111111111111", Admin: true, Groups: []string{}},
+			{AccountID: "222222222222", Groups: []string{"readers"}},
+		},
+	}}
+	handler := NewAccountsHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantItems  string
+	}{
+		{name: "admin and member", query: "?principalArn=arn:aws:iam::123456789012:user/alice", wantStatus: http.StatusOK, wantItems: `[{"accountId":"111111111111","admin":true,"groups":[]},{"accountId":"222222222222","admin":false,"groups":["readers"]}]`},
+		{name: "no accounts", query: "?principalArn=arn:aws:iam::123456789012:user/bob", wantStatus: http.StatusOK, wantItems: `[]`},
+		{name: "missing principal", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/admin/accounts"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler.SearchByPrincipal(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var resp struct {
+				Kind  string          `json:"kind"`
+				Items json.RawMessage `json:"items"`
+				Total int             `json:"total"`
+			}
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "PrincipalAccountList" || string(resp.Items) != tt.wantItems {
+				t.Errorf("unexpected response %s", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+// defaultAuditWindow is how far back an audit query looks when from is not
+// given
+const defaultAuditWindow = 24 * time.Hour
+
+// auditFields are the fields of an audited decision that fields may
+// project, by name
+var auditFields = map[string]func(e *store.DecisionAuditEntry) any{
+	"id":           func(e *store.DecisionAuditEntry) any { return e.EntryID },
+	"time":         func(e *store.DecisionAuditEntry) any { return e.Time },
+	"principalArn": func(e *store.DecisionAuditEntry) any { return e.PrincipalARN },
+	"action":       func(e *store.DecisionAuditEntry) any { return e.Action },
+	"resource":     func(e *store.DecisionAuditEntry) any { return e.Resource },
+	"decision":     func(e *store.DecisionAuditEntry) any { return e.Decision },
+	"basis":        func(e *store.DecisionAuditEntry) any { return e.Basis },
+}
+
+// auditFieldOrder lists every field, for projections that name none
+var auditFieldOrder = []string{"id", "time", "principalArn", "action", "resource", "decision", "basis"}
+
+// AuditHandler serves queries of the authorization decision audit log
+type AuditHandler struct {
+	service    authz.Service
+	limiter    ratelimit.Limiter
+	pageLimits PageLimits
+	logger     *slog.Logger
+}
+
+// NewAuditHandler creates a new AuditHandler
+func NewAuditHandler(service authz.Service, logger *slog.Logger) *AuditHandler {
+	return &AuditHandler{
+		service:    service,
+		pageLimits: DefaultTenantPageLimits,
+		logger:     logger,
+	}
+}
+
+// WithPageLimits sets the default and maximum page size
+func (h *AuditHandler) WithPageLimits(limits PageLimits) *AuditHandler {
+	h.pageLimits = limits
+	return h
+}
+
+// WithRateLimit limits the audit queries of each account with limiter,
+// apart from the API-wide rate limit, as queries that filter out most of
+// what they read are expensive
+func (h *AuditHandler) WithRateLimit(limiter ratelimit.Limiter) *AuditHandler {
+	h.limiter = limiter
+	return h
+}
+
+// Query handles GET /api/v0/audit, returning the account's audited
+// authorization decisions newest first. Decisions can be filtered by time
+// range (from and to, RFC3339, defaulting to the last 24 hours), principal,
+// action, decision and resourcePrefix, are paged with limit and pageToken,
+// and fields selects the fields returned. With format=ndjson every decision
+// from pageToken on is streamed instead of a page.
+func (h *AuditHandler) Query(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	if h.limiter != nil {
+		allowed, retryAfter, err := h.limiter.Allow(ctx, accountID)
+		if err != nil {
+			h.logger.Warn("failed to check audit query rate limit, allowing query", "error", err, "account_id", accountID)
+		} else if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			h.writeError(w, http.StatusTooManyRequests, "rate-limited", "Audit query rate limit exceeded, retry later")
+			return
+		}
+	}
+
+	limit, err := pageSize(r, "limit", h.pageLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
+	}
+	q, err := auditQuery(r.URL.Query(), time.Now())
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
+	fields, err := auditProjection(r.URL.Query().Get("fields"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
+
+	format, err := listFormat(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-format", err.Error())
+		return
+	}
+
+	page, err := h.service.QueryDecisionAudit(ctx, accountID, q, limit, r.URL.Query().Get("pageToken"))
+	if err != nil {
+		h.writeQueryError(w, accountID, err)
+		return
+	}
+	if format == formatNDJSON {
+		h.stream(w, r, accountID, q, limit, fields, page)
+		return
+	}
+
+	items := make([]map[string]any, len(page.Entries))
+	for i, entry := range page.Entries {
+		items[i] = auditItem(entry, fields)
+	}
+
+	if page.NextToken != "" {
+		setPageLinks(w, r, url.Values{"pageToken": {page.NextToken}}, nil)
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.AuditListResponse{
+		Kind:          "AuditList",
+		From:          clock.Format(q.From),
+		To:            clock.Format(q.To),
+		Items:         items,
+		Total:         len(items),
+		NextPageToken: page.NextToken,
+	})
+}
+
+// stream writes the audited decisions from first on as NDJSON, one per line
+// followed by a StreamEnd line, reading them limit at a time. Each page is
+// written before the next is queried, so memory stays flat however many
+// decisions the time range holds.
+func (h *AuditHandler) stream(w http.ResponseWriter, r *http.Request, accountID string, q store.DecisionAuditQuery, limit int, fields []string, first *store.DecisionAuditPage) {
+	ctx := r.Context()
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Type", mediaTypeNDJSON)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	stream := newNDJSONStream(w, r)
+	page := first
+	for {
+		for _, entry := range page.Entries {
+			_ = stream.Write(auditItem(entry, fields))
+		}
+		if err := stream.Flush(); err != nil {
+			h.logger.Info("decision audit stream abandoned by the client", "error", err, "account_id", accountID, "written", stream.total)
+			return
+		}
+		if page.NextToken == "" {
+			break
+		}
+		var err error
+		page, err = h.service.QueryDecisionAudit(ctx, accountID, q, limit, page.NextToken)
+		if err != nil {
+			h.logger.Error("failed to stream decision audit log", "error", err, "account_id", accountID)
+			stream.Fail(ctx, "internal-error", "Failed to query the decision audit log")
+			return
+		}
+	}
+	stream.End()
+}
+
+// writeQueryError writes the response to a failed audit query
+func (h *AuditHandler) writeQueryError(w http.ResponseWriter, accountID string, err error) {
+	switch {
+	case errors.Is(err, authz.ErrDecisionAuditDisabled):
+		h.writeError(w, http.StatusNotFound, "audit-disabled", "The decision audit log is not enabled")
+	case errors.Is(err, store.ErrInvalidPageToken):
+		h.writeError(w, http.StatusBadRequest, "invalid-page-token", "pageToken is not valid")
+	default:
+		h.logger.Error("failed to query decision audit log", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to query the decision audit log")
+	}
+}
+
+// auditItem returns the requested fields of an audited decision
+func auditItem(entry *store.DecisionAuditEntry, fields []string) map[string]any {
+	item := map[string]any{"kind": "AuditedDecision"}
+	for _, field := range fields {
+		item[field] = auditFields[field](entry)
+	}
+	return item
+}
+
+// auditQuery returns the filters of an audit query, defaulting to the day
+// before now
+func auditQuery(query url.Values, now time.Time) (store.DecisionAuditQuery, error) {
+	q := store.DecisionAuditQuery{
+		To:             now.UTC(),
+		PrincipalARN:   query.Get("principal"),
+		Action:         query.Get("action"),
+		Decision:       query.Get("decision"),
+		ResourcePrefix: query.Get("resourcePrefix"),
+	}
+	if v := query.Get("to"); v != "" {
+		t, err := clock.Parse(v)
+		if err != nil {
+			return q, errors.New("to must be an RFC3339 time")
+		}
+		q.To = t
+	}
+	q.From = q.To.Add(-defaultAuditWindow)
+	if v := query.Get("from"); v != "" {
+		t, err := clock.Parse(v)
+		if err != nil {
+			return q, errors.New("from must be an RFC3339 time")
+		}
+		q.From = t
+	}
+	if q.From.After(q.To) {
+		return q, errors.New("from must not be after to")
+	}
+	if q.Decision != "" && q.Decision != store.DecisionAllow && q.Decision != store.DecisionDeny {
+		return q, fmt.Errorf("decision must be %s or %s", store.DecisionAllow, store.DecisionDeny)
+	}
+	return q, nil
+}
+
+// auditProjection returns the fields named in a comma-separated list, or
+// every field for an empty list
+func auditProjection(list string) ([]string, error) {
+	if list == "" {
+		return auditFieldOrder, nil
+	}
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if _, ok := auditFields[field]; !ok {
+			return nil, fmt.Errorf("unknown field %q: must be one of %s", field, strings.Join(auditFieldOrder, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func (h *AuditHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+// decisionAuditService serves a fixed page of audited decisions, or err
+type decisionAuditService struct {
+	authz.Service
+	page      *store.DecisionAuditPage
+	err       error
+	query     store.DecisionAuditQuery
+	limit     int
+	pageToken string
+}
+
+func (s *decisionAuditService) QueryDecisionAudit(ctx context.Context, accountID string, q store.DecisionAuditQuery, limit int, pageToken string) (*store.DecisionAuditPage, error) {
+	s.query, s.limit, s.pageToken = q, limit, pageToken
+	return s.page, s.err
+}
+
+func TestAuditHandler_Query(t *testing.T) {
+	page := &store.DecisionAuditPage{
+		Entries: []*store.DecisionAuditEntry{{
+			EntryID:      "2026-10-15T13:00:00.000000000Z#0a1b2c3d",
+			Time:         "2026-10-15T13:00:00Z",
+			PrincipalARN: "arn:aws:iam::123456789012:user/bob",
+			Action:       "rosa:DeleteCluster",
+			Resource:     "arn:aws:rosa:us-east-1:123456789012:cluster/c1",
+			Decision:     store.DecisionDeny,
+			Basis:        authz.BasisPolicy,
+		}},
+		NextToken: "next",
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		err          error
+		expectCode   int
+		expectError  string
+		expectFields int
+	}{
+		{name: "all fields", expectCode: http.StatusOK, expectFields: 8},
+		{name: "projected fields", query: "?fields=time,principalArn", expectCode: http.StatusOK, expectFields: 3},
+		{name: "unknown field", query: "?fields=time,secret", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+		{name: "invalid decision", query: "?decision=maybe", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+		{name: "from after to", query: "?from=2026-10-15T00:00:00Z&to=2026-10-14T00:00:00Z", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+		{name: "invalid page token", err: store.ErrInvalidPageToken, expectCode: http.StatusBadRequest, expectError: "invalid-page-token"},
+		{name: "disabled", err: authz.ErrDecisionAuditDisabled, expectCode: http.StatusNotFound, expectError: "audit-disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &decisionAuditService{page: page, err: tt.err}
+			handler := NewAuditHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/audit"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+			handler.Query(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]any
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+
+			var resp apiv0.AuditListResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "AuditList" || resp.Total != 1 || resp.NextPageToken != "next" {
+				t.Fatalf("unexpected response %+v", resp)
+			}
+			if item := resp.Items[0]; len(item) != tt.expectFields || item["kind"] != "AuditedDecision" || item["time"] != "2026-10-15T13:00:00Z" {
+				t.Errorf("expected %d fields, got %v", tt.expectFields, item)
+			}
+			if got := service.query.To.Sub(service.query.From); got != 24*time.Hour {
+				t.Errorf("expected the last 24 hours by default, got a %s window", got)
+			}
+		})
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		service := &decisionAuditService{page: page}
+		handler := NewAuditHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/audit?action=rosa:DeleteCluster&decision=deny&principal=arn:aws:iam::123456789012:user/bob&resourcePrefix=arn:aws:rosa&limit=20&pageToken=abc", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+		handler.Query(httptest.NewRecorder(), req)
+
+		q := service.query
+		if q.Action != "rosa:DeleteCluster" || q.Decision != "deny" || q.PrincipalARN != "arn:aws:iam::123456789012:user/bob" || q.ResourcePrefix != "arn:aws:rosa" {
+			t.Errorf("unexpected query %+v", q)
+		}
+		if service.limit != 20 || service.pageToken != "abc" {
+			t.Errorf("expected limit 20 and token abc, got %d and %q", service.limit, service.pageToken)
+		}
+	})
+}
+
+// pagedAuditService serves one page per page token, following each page's
+// NextToken
+type pagedAuditService struct {
+	authz.Service
+	pages  map[string]*store.DecisionAuditPage
+	tokens []string
+}
+
+func (s *pagedAuditService) QueryDecisionAudit(ctx context.Context, accountID string, q store.DecisionAuditQuery, limit int, pageToken string) (*store.DecisionAuditPage, error) {
+	s.tokens = append(s.tokens, pageToken)
+	page, ok := s.pages[pageToken]
+	if !ok {
+		return nil, store.ErrInvalidPageToken
+	}
+	return page, nil
+}
+
+func TestAuditHandler_Query_NDJSON(t *testing.T) {
+	entry := func(id string) *store.DecisionAuditEntry {
+		return &store.DecisionAuditEntry{EntryID: id, Time: "2026-10-15T13:00:00Z", Decision: store.DecisionAllow}
+	}
+	service := &pagedAuditService{pages: map[string]*store.DecisionAuditPage{
+		"":   {Entries: []*store.DecisionAuditEntry{entry("e1"), entry("e2")}, NextToken: "p2"},
+		"p2": {Entries: []*store.DecisionAuditEntry{entry("e3")}, NextToken: "p3"},
+		"p3": {Entries: []*store.DecisionAuditEntry{entry("e4")}},
+	}}
+	handler := NewAuditHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/audit?format=ndjson&fields=id", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+	w := httptest.NewRecorder()
+	handler.Query(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if len(service.tokens) != 3 || service.tokens[1] != "p2" || service.tokens[2] != "p3" {
+		t.Errorf("expected every page to be read in turn, got tokens %q", service.tokens)
+	}
+	lines := ndjsonLines(t, w.Body.String())
+	if len(lines) != 5 {
+		t.Fatalf("expected four decisions and an end line, got %d lines", len(lines))
+	}
+	for i, id := range []string{"e1", "e2", "e3", "e4"} {
+		if lines[i]["id"] != id || lines[i]["kind"] != "AuditedDecision" || len(lines[i]) != 2 {
+			t.Errorf("line %d = %v, want the projected decision %s", i, lines[i], id)
+		}
+	}
+	if end := lines[4]; end["kind"] != "StreamEnd" || end["total"] != float64(4) {
+		t.Errorf("unexpected end line %v", end)
+	}
+
+	// A query that fails before the stream starts still gets an error status
+	req = httptest.NewRequest(http.MethodGet, "/api/v0/audit?format=ndjson&pageToken=bogus", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+	w = httptest.NewRecorder()
+	handler.Query(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid page token, got %d", w.Code)
+	}
+}
+
+func TestAuditHandler_RateLimit(t *testing.T) {
+	service := &decisionAuditService{page: &store.DecisionAuditPage{}}
+	handler := NewAuditHandler(service, slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithRateLimit(ratelimit.NewLocal(2, time.Minute))
+
+	codes := make([]int, 3)
+	for i := range codes {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/audit", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+		w := httptest.NewRecorder()
+		handler.Query(w, req)
+		codes[i] = w.Code
+		if w.Code == http.StatusTooManyRequests && w.Header().Get("Retry-After") == "" {
+			t.Error("expected a Retry-After header")
+		}
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("expected two queries then 429, got %v", codes)
+	}
+}
@@ -1,13 +1,18 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"slices"
 
 	"github.com/gorilla/mux"
 
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 )
 
@@ -27,120 +32,51 @@ func NewAuthzHandler(checker authz.Checker, service authz.Service, logger *slog.
 	}
 }
 
-// Policy request/response types
-
-type CreatePolicyRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Policy      string `json:"policy"` // Native Cedar policy text
-}
-
-type PolicyResponse struct {
-	Kind        string `json:"kind"`
-	PolicyID    string `json:"policyId"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	CreatedAt   string `json:"createdAt"`
-}
-
-type PolicyListResponse struct {
-	Kind  string           `json:"kind"`
-	Items []PolicyResponse `json:"items"`
-	Total int              `json:"total"`
-}
-
-// Group request/response types
-
-type CreateGroupRequest struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-}
-
-type GroupResponse struct {
-	Kind        string `json:"kind"`
-	GroupID     string `json:"groupId"`
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	CreatedAt   string `json:"createdAt"`
-}
-
-type GroupListResponse struct {
-	Kind  string          `json:"kind"`
-	Items []GroupResponse `json:"items"`
-	Total int             `json:"total"`
-}
-
-type UpdateMembersRequest struct {
-	Add    []string `json:"add"`
-	Remove []string `json:"remove"`
-}
-
-type MemberListResponse struct {
-	Kind  string   `json:"kind"`
-	Items []string `json:"items"`
-	Total int      `json:"total"`
-}
-
-// Attachment request/response types
-
-type CreateAttachmentRequest struct {
-	PolicyID   string `json:"policyId"`
-	TargetType string `json:"targetType"` // "user" or "group"
-	TargetID   string `json:"targetId"`   // ARN for user, groupId for group
-}
-
-type AttachmentResponse struct {
-	Kind         string `json:"kind"`
-	AttachmentID string `json:"attachmentId"`
-	PolicyID     string `json:"policyId"`
-	TargetType   string `json:"targetType"`
-	TargetID     string `json:"targetId"`
-	CreatedAt    string `json:"createdAt"`
-}
-
-type AttachmentListResponse struct {
-	Kind  string               `json:"kind"`
-	Items []AttachmentResponse `json:"items"`
-	Total int                  `json:"total"`
-}
-
-// Admin request/response types
-
-type AddAdminRequest struct {
-	PrincipalARN string `json:"principalArn"`
-}
-
-// Authorization check request/response types
-
-type CheckAuthorizationRequest struct {
-	Principal    string            `json:"principal"`    // Principal ARN making the request
-	Action       string            `json:"action"`       // Action being performed (e.g., "rosa:CreateCluster")
-	Resource     string            `json:"resource"`     // Resource ARN (e.g., "arn:aws:rosa:us-west-2:123456789012:cluster/*")
-	Context      map[string]any    `json:"context"`      // Additional context (e.g., request tags)
-	ResourceTags map[string]string `json:"resourceTags"` // Tags on the resource
+func attachmentResponse(a *authz.Attachment) apiv0.AttachmentResponse {
+	return apiv0.AttachmentResponse{
+		Kind:         "Attachment",
+		AttachmentID: a.AttachmentID,
+		PolicyID:     a.PolicyID,
+		TargetType:   string(a.TargetType),
+		TargetID:     a.TargetID,
+		Pattern:      a.Pattern,
+		Name:         a.Name,
+		Description:  a.Description,
+		CreatedAt:    a.CreatedAt,
+	}
 }
 
-type CheckAuthorizationResponse struct {
-	Kind     string `json:"kind"`
-	Decision string `json:"decision"` // "ALLOW" or "DENY"
-	Reason   string `json:"reason,omitempty"`
+// visibilityContext returns the request context, asking policy mutations to
+// wait until authorization checks reflect them when the caller passes
+// ?wait=true
+func visibilityContext(r *http.Request) context.Context {
+	if r.URL.Query().Get("wait") == "true" {
+		return authz.WithWaitForVisibility(r.Context())
+	}
+	return r.Context()
 }
 
-type AdminListResponse struct {
-	Kind  string   `json:"kind"`
-	Items []string `json:"items"`
-	Total int      `json:"total"`
+// visibilityStatus returns status for a completed mutation, or 202 Accepted
+// when the change was saved but is not yet visible to authorization checks
+func visibilityStatus(status int, err error) (int, error) {
+	if errors.Is(err, authz.ErrNotYetVisible) {
+		return http.StatusAccepted, nil
+	}
+	return status, err
 }
 
 // Policy Handlers
 
 func (h *AuthzHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	ctx := visibilityContext(r)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
-	var req CreatePolicyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.CreatePolicyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -153,28 +89,48 @@ func (h *AuthzHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
 		h.writeError(w, http.StatusBadRequest, "missing-policy", "policy (Cedar text) is required")
 		return
 	}
+	if err := h.service.ValidatePolicy(req.Policy); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-policy", err.Error())
+		return
+	}
+	if err := authz.ValidateTags(req.Tags); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-tags", err.Error())
+		return
+	}
+	if writeDryRun(w, r, http.StatusCreated, apiv0.PolicyResponse{Kind: "Policy", Name: req.Name, Description: req.Description, Tags: req.Tags}) {
+		return
+	}
 
-	p, err := h.service.CreatePolicy(ctx, accountID, req.Name, req.Description, req.Policy)
+	p, err := h.service.CreatePolicy(ctx, accountID, req.Name, req.Description, req.Policy, req.Tags)
+	status, err := visibilityStatus(http.StatusCreated, err)
 	if err != nil {
 		h.logger.Error("failed to create policy", "error", err, "account_id", accountID)
 		h.writeError(w, http.StatusBadRequest, "invalid-policy", err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(PolicyResponse{
+	writeResponse(w, r, status, apiv0.PolicyResponse{
 		Kind:        "Policy",
 		PolicyID:    p.PolicyID,
 		Name:        p.Name,
 		Description: p.Description,
+		Tags:        p.Tags,
 		CreatedAt:   p.CreatedAt,
 	})
 }
 
 func (h *AuthzHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	filters, err := authz.ParseTagFilters(r.URL.Query()["tag"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
 
 	policies, err := h.service.ListPolicies(ctx, accountID)
 	if err != nil {
@@ -183,19 +139,22 @@ func (h *AuthzHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items := make([]PolicyResponse, len(policies))
-	for i, p := range policies {
-		items[i] = PolicyResponse{
+	items := make([]apiv0.PolicyResponse, 0, len(policies))
+	for _, p := range policies {
+		if !authz.MatchTags(p.Tags, filters) {
+			continue
+		}
+		items = append(items, apiv0.PolicyResponse{
 			Kind:        "Policy",
 			PolicyID:    p.PolicyID,
 			Name:        p.Name,
 			Description: p.Description,
+			Tags:        p.Tags,
 			CreatedAt:   p.CreatedAt,
-		}
+		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(PolicyListResponse{
+	writeResponse(w, r, http.StatusOK, apiv0.PolicyListResponse{
 		Kind:  "PolicyList",
 		Items: items,
 		Total: len(items),
@@ -204,7 +163,10 @@ func (h *AuthzHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
 
 func (h *AuthzHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	policyID := vars["id"]
 
@@ -220,52 +182,118 @@ func (h *AuthzHandler) GetPolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(PolicyResponse{
+	writeResponse(w, r, http.StatusOK, apiv0.PolicyResponse{
 		Kind:        "Policy",
 		PolicyID:    p.PolicyID,
 		Name:        p.Name,
 		Description: p.Description,
+		Tags:        p.Tags,
 		CreatedAt:   p.CreatedAt,
 	})
 }
 
 func (h *AuthzHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	ctx := visibilityContext(r)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	policyID := vars["id"]
 
-	var req CreatePolicyRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.CreatePolicyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if err := authz.ValidateTags(req.Tags); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-tags", err.Error())
 		return
 	}
 
-	p, err := h.service.UpdatePolicy(ctx, accountID, policyID, req.Name, req.Description, req.Policy)
+	if middleware.IsDryRun(ctx) {
+		if err := h.service.ValidatePolicy(req.Policy); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-policy", err.Error())
+			return
+		}
+		current, ok := h.getPolicy(w, ctx, accountID, policyID)
+		if !ok {
+			return
+		}
+		tags := current.Tags
+		if req.Tags != nil {
+			tags = req.Tags
+		}
+		writeDryRun(w, r, http.StatusOK, apiv0.PolicyResponse{
+			Kind:        "Policy",
+			PolicyID:    current.PolicyID,
+			Name:        req.Name,
+			Description: req.Description,
+			Tags:        tags,
+			CreatedAt:   current.CreatedAt,
+		})
+		return
+	}
+
+	// Omitted tags are left as they are
+	p, err := h.service.UpdatePolicy(ctx, accountID, policyID, req.Name, req.Description, req.Policy, req.Tags)
+	status, err := visibilityStatus(http.StatusOK, err)
 	if err != nil {
 		h.logger.Error("failed to update policy", "error", err, "account_id", accountID, "policy_id", policyID)
 		h.writeError(w, http.StatusBadRequest, "invalid-policy", err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(PolicyResponse{
+	writeResponse(w, r, status, apiv0.PolicyResponse{
 		Kind:        "Policy",
 		PolicyID:    p.PolicyID,
 		Name:        p.Name,
 		Description: p.Description,
+		Tags:        p.Tags,
 		CreatedAt:   p.CreatedAt,
 	})
 }
 
 func (h *AuthzHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	ctx := visibilityContext(r)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	policyID := vars["id"]
 
-	err := h.service.DeletePolicy(ctx, accountID, policyID)
+	if middleware.IsDryRun(ctx) {
+		current, ok := h.getPolicy(w, ctx, accountID, policyID)
+		if !ok {
+			return
+		}
+		attachments, err := h.service.ListAttachments(ctx, accountID, authz.AttachmentFilter{PolicyID: policyID})
+		if err != nil {
+			h.logger.Error("failed to list attachments", "error", err, "account_id", accountID, "policy_id", policyID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete policy")
+			return
+		}
+		if len(attachments) > 0 {
+			h.writeError(w, http.StatusConflict, "policy-in-use", "cannot delete policy with existing attachments")
+			return
+		}
+		writeDryRun(w, r, http.StatusNoContent, apiv0.PolicyResponse{
+			Kind:        "Policy",
+			PolicyID:    current.PolicyID,
+			Name:        current.Name,
+			Description: current.Description,
+			Tags:        current.Tags,
+			CreatedAt:   current.CreatedAt,
+		})
+		return
+	}
+
+	err := h.service.DeletePolicy(ctx, accountID, policyID, middleware.GetCallerARN(ctx))
+	if writeApprovalRequired(w, r, err) {
+		return
+	}
+	status, err := visibilityStatus(http.StatusNoContent, err)
 	if err != nil {
 		h.logger.Error("failed to delete policy", "error", err, "account_id", accountID, "policy_id", policyID)
 		if err.Error() == "cannot delete policy with existing attachments" {
@@ -276,18 +304,21 @@ func (h *AuthzHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.WriteHeader(status)
 }
 
 // Group Handlers
 
 func (h *AuthzHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
-	var req CreateGroupRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.CreateGroupRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -296,27 +327,45 @@ func (h *AuthzHandler) CreateGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	g, err := h.service.CreateGroup(ctx, accountID, req.Name, req.Description)
+	if err := authz.ValidateTags(req.Tags); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-tags", err.Error())
+		return
+	}
+
+	if writeDryRun(w, r, http.StatusCreated, apiv0.GroupResponse{Kind: "Group", Name: req.Name, Description: req.Description, Tags: req.Tags}) {
+		return
+	}
+
+	g, err := h.service.CreateGroup(ctx, accountID, req.Name, req.Description, req.Tags)
 	if err != nil {
 		h.logger.Error("failed to create group", "error", err, "account_id", accountID)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to create group")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(GroupResponse{
-		Kind:        "Group",
-		GroupID:     g.GroupID,
-		Name:        g.Name,
-		Description: g.Description,
-		CreatedAt:   g.CreatedAt,
+	writeResponse(w, r, http.StatusCreated, apiv0.GroupResponse{
+		Kind:          "Group",
+		GroupID:       g.GroupID,
+		Name:          g.Name,
+		Description:   g.Description,
+		Tags:          g.Tags,
+		MemberPattern: g.MemberPattern,
+		CreatedAt:     g.CreatedAt,
 	})
 }
 
 func (h *AuthzHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	filters, err := authz.ParseTagFilters(r.URL.Query()["tag"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
 
 	groups, err := h.service.ListGroups(ctx, accountID)
 	if err != nil {
@@ -325,19 +374,23 @@ func (h *AuthzHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items := make([]GroupResponse, len(groups))
-	for i, g := range groups {
-		items[i] = GroupResponse{
-			Kind:        "Group",
-			GroupID:     g.GroupID,
-			Name:        g.Name,
-			Description: g.Description,
-			CreatedAt:   g.CreatedAt,
+	items := make([]apiv0.GroupResponse, 0, len(groups))
+	for _, g := range groups {
+		if !authz.MatchTags(g.Tags, filters) {
+			continue
 		}
+		items = append(items, apiv0.GroupResponse{
+			Kind:          "Group",
+			GroupID:       g.GroupID,
+			Name:          g.Name,
+			Description:   g.Description,
+			Tags:          g.Tags,
+			MemberPattern: g.MemberPattern,
+			CreatedAt:     g.CreatedAt,
+		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(GroupListResponse{
+	writeResponse(w, r, http.StatusOK, apiv0.GroupListResponse{
 		Kind:  "GroupList",
 		Items: items,
 		Total: len(items),
@@ -346,7 +399,10 @@ func (h *AuthzHandler) ListGroups(w http.ResponseWriter, r *http.Request) {
 
 func (h *AuthzHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	groupID := vars["id"]
 
@@ -362,23 +418,43 @@ func (h *AuthzHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(GroupResponse{
-		Kind:        "Group",
-		GroupID:     g.GroupID,
-		Name:        g.Name,
-		Description: g.Description,
-		CreatedAt:   g.CreatedAt,
+	writeResponse(w, r, http.StatusOK, apiv0.GroupResponse{
+		Kind:          "Group",
+		GroupID:       g.GroupID,
+		Name:          g.Name,
+		Description:   g.Description,
+		Tags:          g.Tags,
+		MemberPattern: g.MemberPattern,
+		CreatedAt:     g.CreatedAt,
 	})
 }
 
 func (h *AuthzHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	groupID := vars["id"]
 
-	err := h.service.DeleteGroup(ctx, accountID, groupID)
+	if middleware.IsDryRun(ctx) {
+		// Deleting a group that does not exist succeeds
+		g, err := h.service.GetGroup(ctx, accountID, groupID)
+		if err != nil {
+			h.logger.Error("failed to get group", "error", err, "account_id", accountID, "group_id", groupID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete group")
+			return
+		}
+		var preview any
+		if g != nil {
+			preview = apiv0.GroupResponse{Kind: "Group", GroupID: g.GroupID, Name: g.Name, Description: g.Description, Tags: g.Tags, CreatedAt: g.CreatedAt}
+		}
+		writeDryRun(w, r, http.StatusNoContent, preview)
+		return
+	}
+
+	err := h.service.DeleteGroup(ctx, accountID, groupID, middleware.GetCallerARN(ctx))
 	if err != nil {
 		h.logger.Error("failed to delete group", "error", err, "account_id", accountID, "group_id", groupID)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete group")
@@ -388,33 +464,55 @@ func (h *AuthzHandler) DeleteGroup(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// UpdateGroupMembers applies the adds and removes in the request and reports
+// a result per member. Invalid entries and failed writes do not stop the
+// others; the response is 207 Multi-Status when any entry failed. An update
+// that conflicts with a concurrent one, or whose version is not the current
+// one, changes nothing and gets 409 with the latest member list.
 func (h *AuthzHandler) UpdateGroupMembers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	groupID := vars["id"]
 
-	var req UpdateMembersRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.UpdateMembersRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
-	// Add members
-	for _, memberARN := range req.Add {
-		if err := h.service.AddGroupMember(ctx, accountID, groupID, memberARN); err != nil {
-			h.logger.Error("failed to add group member", "error", err, "account_id", accountID, "group_id", groupID, "member", memberARN)
-			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to add group member")
+	if middleware.IsDryRun(ctx) {
+		h.dryRunUpdateGroupMembers(w, r, accountID, groupID, req)
+		return
+	}
+
+	results, version, err := h.service.UpdateGroupMembers(ctx, accountID, groupID, req.Version, req.Add, req.Remove)
+	if err != nil {
+		if errors.Is(err, authz.ErrGroupNotFound) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Group not found")
 			return
 		}
+		if errors.Is(err, store.ErrMembersConflict) {
+			h.writeMemberConflict(w, r, accountID, groupID)
+			return
+		}
+		if errors.Is(err, authz.ErrPatternGroup) {
+			h.writeError(w, http.StatusConflict, "pattern-group", "The members of a pattern group are maintained by the pattern matcher")
+			return
+		}
+		h.logger.Error("failed to update group members", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update group members")
+		return
 	}
 
-	// Remove members
-	for _, memberARN := range req.Remove {
-		if err := h.service.RemoveGroupMember(ctx, accountID, groupID, memberARN); err != nil {
-			h.logger.Error("failed to remove group member", "error", err, "account_id", accountID, "group_id", groupID, "member", memberARN)
-			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to remove group member")
-			return
+	status := http.StatusOK
+	for _, result := range results {
+		if result.Status == authz.MemberFailed {
+			status = http.StatusMultiStatus
+			h.logger.Warn("group member update failed", "account_id", accountID, "group_id", groupID, "member", result.MemberARN, "operation", result.Operation, "reason", result.Reason)
 		}
 	}
 
@@ -426,20 +524,64 @@ func (h *AuthzHandler) UpdateGroupMembers(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(MemberListResponse{
-		Kind:  "MemberList",
-		Items: members,
-		Total: len(members),
+	writeResponse(w, r, status, apiv0.MemberUpdateResponse{
+		Kind:    "MemberList",
+		Items:   emptyIfNil(members),
+		Total:   len(members),
+		Version: version,
+		Results: emptyIfNil(results),
+	})
+}
+
+// writeMemberConflict writes the 409 for a conflicting member update
+func (h *AuthzHandler) writeMemberConflict(w http.ResponseWriter, r *http.Request, accountID, groupID string) {
+	ctx := r.Context()
+	g, err := h.service.GetGroup(ctx, accountID, groupID)
+	if err != nil || g == nil {
+		h.logger.Error("failed to get group", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update group members")
+		return
+	}
+	members, err := h.service.ListGroupMembers(ctx, accountID, groupID)
+	if err != nil {
+		h.logger.Error("failed to list group members", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list group members")
+		return
+	}
+
+	h.logger.Warn("group member update conflicted", "account_id", accountID, "group_id", groupID, "version", g.MembersVersion)
+	writeResponse(w, r, http.StatusConflict, apiv0.MemberConflictResponse{
+		Kind:    "Error",
+		Code:    "concurrent-modification",
+		Reason:  "Group members were modified concurrently; retry against the latest member list",
+		Items:   emptyIfNil(members),
+		Total:   len(members),
+		Version: g.MembersVersion,
 	})
 }
 
 func (h *AuthzHandler) ListGroupMembers(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	groupID := vars["id"]
 
+	// The version is read first, so a concurrent update shows as a conflict
+	// when it is sent back rather than being missed
+	g, err := h.service.GetGroup(ctx, accountID, groupID)
+	if err != nil {
+		h.logger.Error("failed to get group", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list group members")
+		return
+	}
+	var version int64
+	if g != nil {
+		version = g.MembersVersion
+	}
+
 	members, err := h.service.ListGroupMembers(ctx, accountID, groupID)
 	if err != nil {
 		h.logger.Error("failed to list group members", "error", err, "account_id", accountID, "group_id", groupID)
@@ -447,23 +589,26 @@ func (h *AuthzHandler) ListGroupMembers(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(MemberListResponse{
-		Kind:  "MemberList",
-		Items: members,
-		Total: len(members),
+	writeResponse(w, r, http.StatusOK, apiv0.MemberListResponse{
+		Kind:    "MemberList",
+		Items:   emptyIfNil(members),
+		Total:   len(members),
+		Version: version,
 	})
 }
 
 // Attachment Handlers
 
 func (h *AuthzHandler) CreateAttachment(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	ctx := visibilityContext(r)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
-	var req CreateAttachmentRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.CreateAttachmentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -472,39 +617,56 @@ func (h *AuthzHandler) CreateAttachment(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if req.TargetType != "user" && req.TargetType != "group" {
-		h.writeError(w, http.StatusBadRequest, "invalid-target-type", "targetType must be 'user' or 'group'")
+	targetType, err := authz.ParseTargetType(req.TargetType)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-target-type", "targetType must be 'user', 'role' or 'group'")
+		return
+	}
+	if err := authz.ValidateTarget(targetType, req.TargetID); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-target", err.Error())
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		h.dryRunCreateAttachment(w, r, accountID, targetType, req)
 		return
 	}
 
-	a, err := h.service.AttachPolicy(ctx, accountID, req.PolicyID, authz.TargetType(req.TargetType), req.TargetID)
+	a, created, err := h.service.AttachPolicy(ctx, accountID, req.PolicyID, targetType, req.TargetID, req.Name, req.Description)
+	// An existing attachment for the same policy and target is returned as is
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	status, err = visibilityStatus(status, err)
 	if err != nil {
 		h.logger.Error("failed to attach policy", "error", err, "account_id", accountID, "policy_id", req.PolicyID)
 		h.writeError(w, http.StatusBadRequest, "attachment-failed", err.Error())
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(AttachmentResponse{
-		Kind:         "Attachment",
-		AttachmentID: a.AttachmentID,
-		PolicyID:     a.PolicyID,
-		TargetType:   string(a.TargetType),
-		TargetID:     a.TargetID,
-		CreatedAt:    a.CreatedAt,
-	})
+	writeResponse(w, r, status, attachmentResponse(a))
 }
 
 func (h *AuthzHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	// Parse filter parameters
 	filter := authz.AttachmentFilter{
-		PolicyID:   r.URL.Query().Get("policyId"),
-		TargetType: authz.TargetType(r.URL.Query().Get("targetType")),
-		TargetID:   r.URL.Query().Get("targetId"),
+		PolicyID: r.URL.Query().Get("policyId"),
+		TargetID: r.URL.Query().Get("targetId"),
+	}
+	if v := r.URL.Query().Get("targetType"); v != "" {
+		targetType, err := authz.ParseTargetType(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-target-type", "targetType must be 'user', 'role' or 'group'")
+			return
+		}
+		filter.TargetType = targetType
 	}
 
 	attachments, err := h.service.ListAttachments(ctx, accountID, filter)
@@ -514,52 +676,150 @@ func (h *AuthzHandler) ListAttachments(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	items := make([]AttachmentResponse, len(attachments))
+	items := make([]apiv0.AttachmentResponse, len(attachments))
 	for i, a := range attachments {
-		items[i] = AttachmentResponse{
-			Kind:         "Attachment",
-			AttachmentID: a.AttachmentID,
-			PolicyID:     a.PolicyID,
-			TargetType:   string(a.TargetType),
-			TargetID:     a.TargetID,
-			CreatedAt:    a.CreatedAt,
-		}
+		items[i] = attachmentResponse(a)
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(AttachmentListResponse{
+	writeResponse(w, r, http.StatusOK, apiv0.AttachmentListResponse{
 		Kind:  "AttachmentList",
 		Items: items,
 		Total: len(items),
 	})
 }
 
-func (h *AuthzHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+func (h *AuthzHandler) GetAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	attachmentID := vars["id"]
+
+	a, err := h.service.GetAttachment(ctx, accountID, attachmentID)
+	if errors.Is(err, authz.ErrAttachmentNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Attachment not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get attachment", "error", err, "account_id", accountID, "attachment_id", attachmentID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get attachment")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, attachmentResponse(a))
+}
+
+// UpdateAttachment replaces the name and description of an attachment
+func (h *AuthzHandler) UpdateAttachment(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	vars := mux.Vars(r)
+	attachmentID := vars["id"]
+
+	var req apiv0.UpdateAttachmentRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		current, ok := h.getAttachment(w, ctx, accountID, attachmentID)
+		if !ok {
+			return
+		}
+		preview := attachmentResponse(current)
+		preview.Name, preview.Description = req.Name, req.Description
+		writeDryRun(w, r, http.StatusOK, preview)
+		return
+	}
+
+	a, err := h.service.UpdateAttachment(ctx, accountID, attachmentID, req.Name, req.Description)
+	if errors.Is(err, authz.ErrAttachmentNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Attachment not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to update attachment", "error", err, "account_id", accountID, "attachment_id", attachmentID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update attachment")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, attachmentResponse(a))
+}
+
+func (h *AuthzHandler) DeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := visibilityContext(r)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	attachmentID := vars["id"]
 
-	err := h.service.DetachPolicy(ctx, accountID, attachmentID)
+	if middleware.IsDryRun(ctx) {
+		current, ok := h.getAttachment(w, ctx, accountID, attachmentID)
+		if !ok {
+			return
+		}
+		writeDryRun(w, r, http.StatusNoContent, attachmentResponse(current))
+		return
+	}
+
+	err := h.service.DetachPolicy(ctx, accountID, attachmentID, middleware.GetCallerARN(ctx))
+	if errors.Is(err, authz.ErrAttachmentNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Attachment not found")
+		return
+	}
+	status, err := visibilityStatus(http.StatusNoContent, err)
 	if err != nil {
 		h.logger.Error("failed to detach policy", "error", err, "account_id", accountID, "attachment_id", attachmentID)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to detach policy")
 		return
 	}
 
-	w.WriteHeader(http.StatusNoContent)
+	w.WriteHeader(status)
+}
+
+// GetPrincipalAccess handles GET /api/v0/authz/principals/{arn}/access
+func (h *AuthzHandler) GetPrincipalAccess(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	principalARN := mux.Vars(r)["arn"]
+
+	access, err := h.service.GetPrincipalAccess(ctx, accountID, principalARN)
+	if err != nil {
+		h.logger.Error("failed to get principal access", "error", err, "account_id", accountID, "principal_arn", principalARN)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get principal access")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.PrincipalAccessResponse{
+		Kind:            "PrincipalAccess",
+		PrincipalAccess: access,
+	})
 }
 
 // Admin Handlers
 
 func (h *AuthzHandler) AddAdmin(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	callerARN := middleware.GetCallerARN(ctx)
 
-	var req AddAdminRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.AddAdminRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -568,24 +828,48 @@ func (h *AuthzHandler) AddAdmin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.service.AddAdmin(ctx, accountID, req.PrincipalARN, callerARN)
+	if middleware.IsDryRun(ctx) {
+		admins, err := h.service.ListAdmins(ctx, accountID)
+		if err != nil {
+			h.logger.Error("failed to list admins", "error", err, "account_id", accountID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to add admin")
+			return
+		}
+		status := http.StatusCreated
+		if slices.Contains(admins, req.PrincipalARN) {
+			status = http.StatusOK
+		}
+		writeDryRun(w, r, status, apiv0.AdminResponse{
+			Kind:  "Admin",
+			Admin: &store.Admin{AccountID: accountID, PrincipalARN: req.PrincipalARN, CreatedBy: callerARN},
+		})
+		return
+	}
+
+	admin, created, err := h.service.AddAdmin(ctx, accountID, req.PrincipalARN, callerARN)
 	if err != nil {
 		h.logger.Error("failed to add admin", "error", err, "account_id", accountID, "principal_arn", req.PrincipalARN)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to add admin")
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]any{
-		"kind":         "Admin",
-		"principalArn": req.PrincipalARN,
+	// Adding an existing admin returns the existing entry unchanged
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	writeResponse(w, r, status, apiv0.AdminResponse{
+		Kind:  "Admin",
+		Admin: admin,
 	})
 }
 
 func (h *AuthzHandler) ListAdmins(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	admins, err := h.service.ListAdmins(ctx, accountID)
 	if err != nil {
@@ -594,22 +878,49 @@ func (h *AuthzHandler) ListAdmins(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(AdminListResponse{
+	writeResponse(w, r, http.StatusOK, apiv0.AdminListResponse{
 		Kind:  "AdminList",
-		Items: admins,
+		Items: emptyIfNil(admins),
 		Total: len(admins),
 	})
 }
 
 func (h *AuthzHandler) RemoveAdmin(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	// The ARN is URL-encoded in the path
 	principalARN := vars["arn"]
 
-	err := h.service.RemoveAdmin(ctx, accountID, principalARN)
+	if middleware.IsDryRun(ctx) {
+		admins, err := h.service.ListAdmins(ctx, accountID)
+		if err != nil {
+			h.logger.Error("failed to list admins", "error", err, "account_id", accountID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to remove admin")
+			return
+		}
+		if !slices.Contains(admins, principalARN) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Admin not found")
+			return
+		}
+		writeDryRun(w, r, http.StatusNoContent, apiv0.AdminResponse{
+			Kind:  "Admin",
+			Admin: &store.Admin{AccountID: accountID, PrincipalARN: principalARN},
+		})
+		return
+	}
+
+	err := h.service.RemoveAdmin(ctx, accountID, principalARN, middleware.GetCallerARN(ctx))
+	if writeApprovalRequired(w, r, err) {
+		return
+	}
+	if errors.Is(err, store.ErrAdminNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Admin not found")
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to remove admin", "error", err, "account_id", accountID, "principal_arn", principalARN)
 		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to remove admin")
@@ -622,11 +933,14 @@ func (h *AuthzHandler) RemoveAdmin(w http.ResponseWriter, r *http.Request) {
 // CheckAuthorization evaluates an authorization request and returns the decision.
 func (h *AuthzHandler) CheckAuthorization(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
-	var req CheckAuthorizationRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	var req apiv0.CheckAuthorizationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -657,6 +971,15 @@ func (h *AuthzHandler) CheckAuthorization(w http.ResponseWriter, r *http.Request
 
 	// Check authorization
 	allowed, err := h.checker.Authorize(ctx, authzReq)
+	if errors.Is(err, authz.ErrGuardrailDenied) {
+		// A guardrail forbid is a policy decision, not a failure
+		allowed, err = false, nil
+	}
+	if errors.Is(err, authz.ErrResourceAccountMismatch) {
+		h.writeError(w, http.StatusForbidden, "resource-account-mismatch",
+			"Resource "+req.Resource+" does not belong to account "+accountID)
+		return
+	}
 	if err != nil {
 		h.logger.Error("authorization check failed", "error", err, "account_id", accountID, "principal", req.Principal, "action", req.Action)
 		h.writeError(w, http.StatusInternalServerError, "authorization-error", err.Error())
@@ -668,22 +991,130 @@ func (h *AuthzHandler) CheckAuthorization(w http.ResponseWriter, r *http.Request
 		decision = "ALLOW"
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(CheckAuthorizationResponse{
+	writeResponse(w, r, http.StatusOK, apiv0.CheckAuthorizationResponse{
 		Kind:     "AuthorizationDecision",
 		Decision: decision,
 	})
 }
 
+// getPolicy returns a policy for a dry run, answering 404 when it does not
+// exist
+func (h *AuthzHandler) getPolicy(w http.ResponseWriter, ctx context.Context, accountID, policyID string) (*store.Policy, bool) {
+	p, err := h.service.GetPolicy(ctx, accountID, policyID)
+	if err != nil {
+		h.logger.Error("failed to get policy", "error", err, "account_id", accountID, "policy_id", policyID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get policy")
+		return nil, false
+	}
+	if p == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Policy not found")
+		return nil, false
+	}
+	return p, true
+}
+
+// getAttachment returns an attachment for a dry run, answering 404 when it
+// does not exist
+func (h *AuthzHandler) getAttachment(w http.ResponseWriter, ctx context.Context, accountID, attachmentID string) (*authz.Attachment, bool) {
+	a, err := h.service.GetAttachment(ctx, accountID, attachmentID)
+	if errors.Is(err, authz.ErrAttachmentNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Attachment not found")
+		return nil, false
+	}
+	if err != nil {
+		h.logger.Error("failed to get attachment", "error", err, "account_id", accountID, "attachment_id", attachmentID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get attachment")
+		return nil, false
+	}
+	return a, true
+}
+
+// dryRunCreateAttachment checks that the policy exists and previews the
+// attachment, or the existing one attaching would return
+func (h *AuthzHandler) dryRunCreateAttachment(w http.ResponseWriter, r *http.Request, accountID string, targetType authz.TargetType, req apiv0.CreateAttachmentRequest) {
+	ctx := r.Context()
+	p, err := h.service.GetPolicy(ctx, accountID, req.PolicyID)
+	if err != nil || p == nil {
+		h.writeError(w, http.StatusBadRequest, "attachment-failed", "policy "+req.PolicyID+" not found")
+		return
+	}
+
+	// A principal pattern is attached through its pattern group
+	targetType, targetID, pattern := authz.NormalizeTarget(targetType, req.TargetID), req.TargetID, ""
+	if targetType != authz.TargetTypeGroup && authz.IsPrincipalPattern(targetID) {
+		pattern, targetType, targetID = targetID, authz.TargetTypeGroup, authz.PatternGroupID(targetID)
+	}
+
+	existing, err := h.service.ListAttachments(ctx, accountID, authz.AttachmentFilter{
+		PolicyID:   req.PolicyID,
+		TargetType: targetType,
+		TargetID:   targetID,
+	})
+	if err != nil {
+		h.logger.Error("failed to list attachments", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list attachments")
+		return
+	}
+	for _, a := range existing {
+		if a.PolicyID == req.PolicyID && a.TargetType == targetType && a.TargetID == targetID {
+			a.Pattern = pattern
+			writeDryRun(w, r, http.StatusOK, attachmentResponse(a))
+			return
+		}
+	}
+	writeDryRun(w, r, http.StatusCreated, apiv0.AttachmentResponse{
+		Kind:        "Attachment",
+		PolicyID:    req.PolicyID,
+		TargetType:  string(targetType),
+		TargetID:    targetID,
+		Pattern:     pattern,
+		Name:        req.Name,
+		Description: req.Description,
+	})
+}
+
+// dryRunUpdateGroupMembers previews the group's member list after the
+// requested adds and removes
+func (h *AuthzHandler) dryRunUpdateGroupMembers(w http.ResponseWriter, r *http.Request, accountID, groupID string, req apiv0.UpdateMembersRequest) {
+	ctx := r.Context()
+	g, err := h.service.GetGroup(ctx, accountID, groupID)
+	if err != nil {
+		h.logger.Error("failed to get group", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update group members")
+		return
+	}
+	if g == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Group not found")
+		return
+	}
+	if req.Version != nil && *req.Version != g.MembersVersion {
+		h.writeMemberConflict(w, r, accountID, groupID)
+		return
+	}
+	members, err := h.service.ListGroupMembers(ctx, accountID, groupID)
+	if err != nil {
+		h.logger.Error("failed to list group members", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list group members")
+		return
+	}
+
+	for _, arn := range req.Add {
+		if !slices.Contains(members, arn) {
+			members = append(members, arn)
+		}
+	}
+	members = slices.DeleteFunc(members, func(arn string) bool { return slices.Contains(req.Remove, arn) })
+	writeDryRun(w, r, http.StatusOK, apiv0.MemberListResponse{
+		Kind:    "MemberList",
+		Items:   emptyIfNil(members),
+		Total:   len(members),
+		Version: g.MembersVersion,
+	})
+}
+
 func (h *AuthzHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
-	resp := map[string]any{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// Decision analytics windows: the default when from is not given, and the
+// longest that may be asked for
+const (
+	defaultAnalyticsWindow = 24 * time.Hour
+	maxAnalyticsWindow     = 31 * 24 * time.Hour
+)
+
+// Days without a decision after which a policy is recommended as unused:
+// the default, and the most that may be asked for
+const (
+	defaultUnusedDays = 30
+	maxUnusedDays     = 365
+)
+
+// Days back to look for observed principals: the default, and the most
+// that may be asked for
+const (
+	defaultObservedDays = 30
+	maxObservedDays     = 365
+)
+
+// GetDecisionAnalytics handles GET /api/v0/authz/analytics, returning the
+// account's hourly allow and deny counts per action between from and to
+// (RFC3339, defaulting to the last 24 hours), optionally for one action
+func (h *AuthzHandler) GetDecisionAnalytics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	from, to, err := analyticsWindow(r, time.Now())
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
+	action := r.URL.Query().Get("action")
+
+	counts, err := h.service.ListDecisionCounts(ctx, accountID, from, to)
+	if err != nil {
+		if errors.Is(err, authz.ErrDecisionAnalyticsDisabled) {
+			h.writeError(w, http.StatusNotFound, "analytics-disabled", "Decision analytics is not enabled")
+			return
+		}
+		h.logger.Error("failed to list decision counts", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list decision counts")
+		return
+	}
+
+	items := []apiv0.DecisionCountResponse{}
+	totals := map[string]*apiv0.ActionDecisionTotals{}
+	for _, c := range counts {
+		if action != "" && c.Action != action {
+			continue
+		}
+		items = append(items, apiv0.DecisionCountResponse{
+			Kind:   "DecisionCount",
+			Hour:   c.Hour,
+			Action: c.Action,
+			Allow:  c.Allow,
+			Deny:   c.Deny,
+		})
+		t, ok := totals[c.Action]
+		if !ok {
+			t = &apiv0.ActionDecisionTotals{Action: c.Action}
+			totals[c.Action] = t
+		}
+		t.Allow += c.Allow
+		t.Deny += c.Deny
+	}
+	totalsList := make([]apiv0.ActionDecisionTotals, 0, len(totals))
+	for _, t := range totals {
+		totalsList = append(totalsList, *t)
+	}
+	sort.Slice(totalsList, func(i, j int) bool { return totalsList[i].Action < totalsList[j].Action })
+
+	writeResponse(w, r, http.StatusOK, apiv0.DecisionCountListResponse{
+		Kind:   "DecisionCountList",
+		From:   clock.Format(from),
+		To:     clock.Format(to),
+		Totals: totalsList,
+		Items:  items,
+		Total:  len(items),
+	})
+}
+
+// analyticsWindow returns the from and to query parameters of r, defaulting
+// to the day before now
+func analyticsWindow(r *http.Request, now time.Time) (time.Time, time.Time, error) {
+	query := r.URL.Query()
+	to := now.UTC()
+	if v := query.Get("to"); v != "" {
+		t, err := clock.Parse(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("to must be an RFC3339 time")
+		}
+		to = t
+	}
+	from := to.Add(-defaultAnalyticsWindow)
+	if v := query.Get("from"); v != "" {
+		t, err := clock.Parse(v)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("from must be an RFC3339 time")
+		}
+		from = t
+	}
+	if from.After(to) {
+		return time.Time{}, time.Time{}, errors.New("from must not be after to")
+	}
+	if to.Sub(from) > maxAnalyticsWindow {
+		return time.Time{}, time.Time{}, errors.New("from and to must be at most 31 days apart")
+	}
+	return from, to, nil
+}
+
+// GetRecommendations handles GET /api/v0/authz/recommendations, flagging
+// policies and groups that look safe to prune. Policies are unused when
+// they have not determined a decision in the last unusedDays days.
+func (h *AuthzHandler) GetRecommendations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	unusedDays := defaultUnusedDays
+	if v := r.URL.Query().Get("unusedDays"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxUnusedDays {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", "unusedDays must be between 1 and 365")
+			return
+		}
+		unusedDays = n
+	}
+
+	recs, err := h.service.GetRecommendations(ctx, accountID, time.Duration(unusedDays)*24*time.Hour)
+	if err != nil {
+		h.logger.Error("failed to get recommendations", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get recommendations")
+		return
+	}
+
+	items := make([]apiv0.RecommendationResponse, len(recs.Items))
+	for i, rec := range recs.Items {
+		items[i] = apiv0.RecommendationResponse{Kind: "Recommendation", Recommendation: rec}
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.RecommendationListResponse{
+		Kind:        "RecommendationList",
+		UnusedDays:  unusedDays,
+		PolicyUsage: recs.PolicyUsage,
+		Items:       items,
+		Total:       len(items),
+	})
+}
+
+// ListObservedPrincipals handles GET /api/v0/authz/principals, returning the
+// distinct caller ARNs seen in the account's decisions in the last days
+// days, with when each was last seen, so admins can copy exact ARNs into
+// groups
+func (h *AuthzHandler) ListObservedPrincipals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	days := defaultObservedDays
+	if v := r.URL.Query().Get("days"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxObservedDays {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", "days must be between 1 and 365")
+			return
+		}
+		days = n
+	}
+
+	principals, err := h.service.ListPrincipals(ctx, accountID, time.Now().Add(-time.Duration(days)*24*time.Hour))
+	if err != nil {
+		if errors.Is(err, authz.ErrDecisionAnalyticsDisabled) {
+			h.writeError(w, http.StatusNotFound, "analytics-disabled", "Decision analytics is not enabled")
+			return
+		}
+		h.logger.Error("failed to list observed principals", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list observed principals")
+		return
+	}
+
+	items := make([]apiv0.ObservedPrincipalResponse, len(principals))
+	for i, p := range principals {
+		items[i] = apiv0.ObservedPrincipalResponse{
+			Kind:         "ObservedPrincipal",
+			PrincipalARN: p.PrincipalARN,
+			LastSeenAt:   p.LastSeenAt,
+		}
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.ObservedPrincipalListResponse{
+		Kind:  "ObservedPrincipalList",
+		Days:  days,
+		Items: items,
+		Total: len(items),
+	})
+}
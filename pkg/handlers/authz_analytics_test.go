@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// decisionCountService serves fixed decision counts, or err
+type decisionCountService struct {
+	authz.Service
+	counts   []*store.DecisionCount
+	err      error
+	from, to time.Time
+}
+
+func (s *decisionCountService) ListDecisionCounts(ctx context.Context, accountID string, from, to time.Time) ([]*store.DecisionCount, error) {
+	s.from, s.to = from, to
+	return s.counts, s.err
+}
+
+func TestAuthzHandler_GetDecisionAnalytics(t *testing.T) {
+	counts := []*store.DecisionCount{
+		{Hour: "2026-10-15T12Z", Action: "rosa:CreateCluster", Allow: 2, Deny: 1},
+		{Hour: "2026-10-15T12Z", Action: "rosa:DeleteCluster", Deny: 3},
+		{Hour: "2026-10-15T13Z", Action: "rosa:CreateCluster", Allow: 4},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		err         error
+		expectCode  int
+		expectError string
+		expectItems int
+		expectTotal apiv0.ActionDecisionTotals
+	}{
+		{name: "all actions", expectCode: http.StatusOK, expectItems: 3, expectTotal: apiv0.ActionDecisionTotals{Action: "rosa:CreateCluster", Allow: 6, Deny: 1}},
+		{name: "one action", query: "?action=rosa:DeleteCluster", expectCode: http.StatusOK, expectItems: 1, expectTotal: apiv0.ActionDecisionTotals{Action: "rosa:DeleteCluster", Deny: 3}},
+		{name: "invalid from", query: "?from=yesterday", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+		{name: "window too long", query: "?from=2026-01-01T00:00:00Z&to=2026-10-15T00:00:00Z", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+		{name: "disabled", err: authz.ErrDecisionAnalyticsDisabled, expectCode: http.StatusNotFound, expectError: "analytics-disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &decisionCountService{counts: counts, err: tt.err}
+			handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/authz/analytics"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+			handler.GetDecisionAnalytics(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]any
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+
+			var resp apiv0.DecisionCountListResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Total != tt.expectItems || len(resp.Items) != tt.expectItems {
+				t.Errorf("expected %d items, got %d (total %d)", tt.expectItems, len(resp.Items), resp.Total)
+			}
+			if len(resp.Totals) == 0 || resp.Totals[0] != tt.expectTotal {
+				t.Errorf("expected first totals %+v, got %+v", tt.expectTotal, resp.Totals)
+			}
+			if got := service.to.Sub(service.from); got != 24*time.Hour {
+				t.Errorf("expected the last 24 hours by default, got a %s window", got)
+			}
+		})
+	}
+}
+
+// recommendationService serves fixed recommendations
+type recommendationService struct {
+	authz.Service
+	recs      *authz.Recommendations
+	unusedFor time.Duration
+}
+
+func (s *recommendationService) GetRecommendations(ctx context.Context, accountID string, unusedFor time.Duration) (*authz.Recommendations, error) {
+	s.unusedFor = unusedFor
+	return s.recs, nil
+}
+
+func TestAuthzHandler_GetRecommendations(t *testing.T) {
+	recs := &authz.Recommendations{
+		PolicyUsage: true,
+		Items: []*authz.Recommendation{
+			{Type: authz.RecommendEmptyGroup, ResourceType: "group", ResourceID: "g-1", Name: "ops", Reason: "The group has no members"},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		query           string
+		expectCode      int
+		expectUnusedFor time.Duration
+	}{
+		{name: "default window", expectCode: http.StatusOK, expectUnusedFor: 30 * 24 * time.Hour},
+		{name: "custom window", query: "?unusedDays=90", expectCode: http.StatusOK, expectUnusedFor: 90 * 24 * time.Hour},
+		{name: "invalid window", query: "?unusedDays=0", expectCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &recommendationService{recs: recs}
+			handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/authz/recommendations"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+			handler.GetRecommendations(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectCode != http.StatusOK {
+				return
+			}
+			if service.unusedFor != tt.expectUnusedFor {
+				t.Errorf("expected unused window %s, got %s", tt.expectUnusedFor, service.unusedFor)
+			}
+			var resp map[string]any
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			items, _ := resp["items"].([]any)
+			if resp["kind"] != "RecommendationList" || resp["policyUsage"] != true || len(items) != 1 {
+				t.Fatalf("unexpected response %v", resp)
+			}
+			if item := items[0].(map[string]any); item["kind"] != "Recommendation" || item["type"] != authz.RecommendEmptyGroup {
+				t.Errorf("unexpected recommendation %v", item)
+			}
+		})
+	}
+}
+
+// observedPrincipalService serves fixed principals, or err
+type observedPrincipalService struct {
+	authz.Service
+	principals []*store.PrincipalSighting
+	err        error
+	since      time.Time
+}
+
+func (s *observedPrincipalService) ListPrincipals(ctx context.Context, accountID string, since time.Time) ([]*store.PrincipalSighting, error) {
+	s.since = since
+	return s.principals, s.err
+}
+
+func TestAuthzHandler_ListObservedPrincipals(t *testing.T) {
+	principals := []*store.PrincipalSighting{
+		{PrincipalARN: "arn:aws:sts::123456789012:assumed-role/dev/alice", LastSeenAt: "2026-10-15T13:25:00Z"},
+		{PrincipalARN: "arn:aws:iam::123456789012:role/ci", LastSeenAt: "2026-10-14T08:00:00Z"},
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		err         error
+		expectCode  int
+		expectError string
+		expectDays  int
+	}{
+		{name: "default window", expectCode: http.StatusOK, expectDays: 30},
+		{name: "custom window", query: "?days=7", expectCode: http.StatusOK, expectDays: 7},
+		{name: "invalid window", query: "?days=400", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+		{name: "disabled", err: authz.ErrDecisionAnalyticsDisabled, expectCode: http.StatusNotFound, expectError: "analytics-disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &observedPrincipalService{principals: principals, err: tt.err}
+			handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/authz/principals"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+			handler.ListObservedPrincipals(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]any
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+
+			var resp apiv0.ObservedPrincipalListResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "ObservedPrincipalList" || resp.Days != tt.expectDays || resp.Total != 2 {
+				t.Errorf("unexpected response %+v", resp)
+			}
+			if resp.Items[0].PrincipalARN != principals[0].PrincipalARN || resp.Items[0].LastSeenAt != principals[0].LastSeenAt {
+				t.Errorf("unexpected first principal %+v", resp.Items[0])
+			}
+			if got := time.Since(service.since).Round(time.Hour); got != time.Duration(tt.expectDays)*24*time.Hour {
+				t.Errorf("expected the last %d days, got %s", tt.expectDays, got)
+			}
+		})
+	}
+}
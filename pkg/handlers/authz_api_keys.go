@@ -0,0 +1,261 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// CreateAPIKey handles POST /api/v0/authz/api_keys
+func (h *AuthzHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	if !h.requireAWSCaller(w, r) {
+		return
+	}
+
+	var req apiv0.CreateAPIKeyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if len(req.Actions) == 0 {
+		h.writeError(w, http.StatusBadRequest, "missing-actions", "actions is required; use [\"*\"] to allow every action")
+		return
+	}
+	if !h.validAPIKeyExpiry(w, &req.ExpiresAt) {
+		return
+	}
+
+	key := &store.APIKey{
+		AccountID:   accountID,
+		Name:        req.Name,
+		Description: req.Description,
+		Actions:     req.Actions,
+		ExpiresAt:   req.ExpiresAt,
+		CreatedBy:   middleware.GetCallerARN(ctx),
+	}
+	var secret string
+	var err error
+	if middleware.IsDryRun(ctx) {
+		err = h.service.CheckAPIKey(ctx, key)
+		key.PrincipalARN = authz.ServiceAccountARN(accountID, key.Name)
+	} else {
+		secret, err = h.service.CreateAPIKey(ctx, key)
+	}
+	if err != nil {
+		h.writeAPIKeyError(w, err, accountID, "", "Failed to create API key")
+		return
+	}
+
+	resp := apiv0.APIKeyResponse{Kind: "APIKey", APIKey: key, Key: secret}
+	if writeDryRun(w, r, http.StatusCreated, resp) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, resp)
+}
+
+// ListAPIKeys handles GET /api/v0/authz/api_keys
+func (h *AuthzHandler) ListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	keys, err := h.service.ListAPIKeys(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to list API keys", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list API keys")
+		return
+	}
+
+	items := make([]apiv0.APIKeyResponse, len(keys))
+	for i, key := range keys {
+		items[i] = apiv0.APIKeyResponse{Kind: "APIKey", APIKey: key}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.APIKeyListResponse{
+		Kind:  "APIKeyList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// GetAPIKey handles GET /api/v0/authz/api_keys/{id}
+func (h *AuthzHandler) GetAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	keyID := mux.Vars(r)["id"]
+
+	key, err := h.service.GetAPIKey(ctx, accountID, keyID)
+	if err != nil {
+		h.logger.Error("failed to get API key", "error", err, "account_id", accountID, "key_id", keyID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get API key")
+		return
+	}
+	if key == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "API key not found")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.APIKeyResponse{Kind: "APIKey", APIKey: key})
+}
+
+// RotateAPIKey handles POST /api/v0/authz/api_keys/{id}/rotate
+func (h *AuthzHandler) RotateAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	if !h.requireAWSCaller(w, r) {
+		return
+	}
+	keyID := mux.Vars(r)["id"]
+
+	// The body is optional: without one the key is revoked at once
+	var req apiv0.RotateAPIKeyRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	var overlap time.Duration
+	if req.Overlap != "" {
+		var err error
+		overlap, err = time.ParseDuration(req.Overlap)
+		if err != nil || overlap < 0 || overlap > authz.MaxAPIKeyOverlap {
+			h.writeError(w, http.StatusBadRequest, "invalid-overlap", "overlap must be a duration between 0s and "+authz.MaxAPIKeyOverlap.String())
+			return
+		}
+	}
+	if !h.validAPIKeyExpiry(w, &req.ExpiresAt) {
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		old, err := h.service.GetAPIKey(ctx, accountID, keyID)
+		switch {
+		case err != nil:
+			h.writeAPIKeyError(w, err, accountID, keyID, "Failed to rotate API key")
+		case old == nil:
+			h.writeError(w, http.StatusNotFound, "not-found", "API key not found")
+		case !old.Active(time.Now()):
+			h.writeError(w, http.StatusConflict, "api-key-inactive", "Revoked and expired API keys cannot be rotated")
+		default:
+			writeDryRun(w, r, http.StatusCreated, apiv0.APIKeyResponse{Kind: "APIKey", APIKey: &store.APIKey{
+				AccountID:    accountID,
+				Name:         old.Name,
+				Description:  old.Description,
+				PrincipalARN: old.PrincipalARN,
+				Actions:      old.Actions,
+				ExpiresAt:    req.ExpiresAt,
+				RotatedFrom:  old.KeyID,
+				CreatedBy:    middleware.GetCallerARN(ctx),
+			}})
+		}
+		return
+	}
+
+	key, secret, err := h.service.RotateAPIKey(ctx, accountID, keyID, middleware.GetCallerARN(ctx), overlap, req.ExpiresAt)
+	if err != nil {
+		h.writeAPIKeyError(w, err, accountID, keyID, "Failed to rotate API key")
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, apiv0.APIKeyResponse{Kind: "APIKey", APIKey: key, Key: secret})
+}
+
+// RevokeAPIKey handles DELETE /api/v0/authz/api_keys/{id}. The key is kept,
+// marked revoked, so its owners can still see what it was.
+func (h *AuthzHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	keyID := mux.Vars(r)["id"]
+
+	if middleware.IsDryRun(ctx) {
+		key, err := h.service.GetAPIKey(ctx, accountID, keyID)
+		switch {
+		case err != nil:
+			h.writeAPIKeyError(w, err, accountID, keyID, "Failed to revoke API key")
+		case key == nil:
+			h.writeError(w, http.StatusNotFound, "not-found", "API key not found")
+		default:
+			writeDryRun(w, r, http.StatusNoContent, apiv0.APIKeyResponse{Kind: "APIKey", APIKey: key})
+		}
+		return
+	}
+
+	if err := h.service.RevokeAPIKey(ctx, accountID, keyID, middleware.GetCallerARN(ctx)); err != nil {
+		h.writeAPIKeyError(w, err, accountID, keyID, "Failed to revoke API key")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// requireAWSCaller rejects callers authenticated by an API key, so a leaked
+// key cannot mint or rotate keys that outlive its revocation
+func (h *AuthzHandler) requireAWSCaller(w http.ResponseWriter, r *http.Request) bool {
+	if middleware.GetAPIKey(r.Context()) != nil {
+		h.writeError(w, http.StatusForbidden, "api-key-caller", "API keys cannot be minted or rotated with an API key")
+		return false
+	}
+	return true
+}
+
+// validAPIKeyExpiry checks that an optional key expiry is a future RFC3339
+// time, answering 400 when it is not, and rewrites it in UTC
+func (h *AuthzHandler) validAPIKeyExpiry(w http.ResponseWriter, expiresAt *string) bool {
+	if *expiresAt == "" {
+		return true
+	}
+	t, err := clock.Parse(*expiresAt)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-expires-at", "expiresAt must be an RFC3339 time")
+		return false
+	}
+	if !t.After(time.Now()) {
+		h.writeError(w, http.StatusBadRequest, "invalid-expires-at", "expiresAt must be in the future")
+		return false
+	}
+	*expiresAt = clock.Format(t)
+	return true
+}
+
+// writeAPIKeyError writes the response for a failed API key operation
+func (h *AuthzHandler) writeAPIKeyError(w http.ResponseWriter, err error, accountID, keyID, reason string) {
+	switch {
+	case errors.Is(err, authz.ErrInvalidServiceAccountName):
+		h.writeError(w, http.StatusBadRequest, "invalid-name", err.Error())
+	case errors.Is(err, authz.ErrAccountNotEnabled):
+		h.writeError(w, http.StatusNotFound, "not-found", err.Error())
+	case errors.Is(err, authz.ErrPrivilegedAPIKey):
+		h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts cannot mint API keys")
+	case errors.Is(err, authz.ErrAPIKeyNotFound):
+		h.writeError(w, http.StatusNotFound, "not-found", "API key not found")
+	case errors.Is(err, authz.ErrAPIKeyInactive):
+		h.writeError(w, http.StatusConflict, "api-key-inactive", "Revoked and expired API keys cannot be rotated")
+	default:
+		h.logger.Error("API key operation failed", "error", err, "reason", reason, "account_id", accountID, "key_id", keyID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", reason)
+	}
+}
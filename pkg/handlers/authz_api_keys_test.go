@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// apiKeyService mints keys without storing them
+type apiKeyService struct {
+	authz.Service
+}
+
+func (s *apiKeyService) CreateAPIKey(ctx context.Context, key *store.APIKey) (string, error) {
+	key.KeyID = "0123456789abcdef"
+	key.PrincipalARN = authz.ServiceAccountARN(key.AccountID, key.Name)
+	key.Prefix = authz.APIKeyPrefix + key.KeyID
+	key.Last4 = "wxyz"
+	return key.Prefix + "_secretwxyz", nil
+}
+
+func TestAuthzHandler_CreateAPIKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		viaAPIKey  bool
+		expectCode int
+		expectErr  string
+		// expectExpiresAt is the expiry returned, in UTC
+		expectExpiresAt string
+	}{
+		{name: "minted", body: `{"name":"ci","actions":["ListClusters"]}`, expectCode: http.StatusCreated},
+		{name: "expiry normalized to UTC", body: `{"name":"ci","actions":["*"],"expiresAt":"2099-01-01T02:00:00+02:00"}`, expectCode: http.StatusCreated, expectExpiresAt: "2099-01-01T00:00:00Z"},
+		{name: "missing actions", body: `{"name":"ci"}`, expectCode: http.StatusBadRequest, expectErr: "missing-actions"},
+		{name: "past expiry", body: `{"name":"ci","actions":["*"],"expiresAt":"2001-01-01T00:00:00Z"}`, expectCode: http.StatusBadRequest, expectErr: "invalid-expires-at"},
+		{name: "minted with an API key", body: `{"name":"ci","actions":["*"]}`, viaAPIKey: true, expectCode: http.StatusForbidden, expectErr: "api-key-caller"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuthzHandler(nil, &apiKeyService{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/authz/api_keys", strings.NewReader(tt.body))
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012")
+			ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, "arn:aws:iam::123456789012:user/admin")
+			if tt.viaAPIKey {
+				ctx = context.WithValue(ctx, middleware.ContextKeyAPIKey, &store.APIKey{KeyID: "fedcba9876543210"})
+			}
+			w := httptest.NewRecorder()
+			handler.CreateAPIKey(w, req.WithContext(ctx))
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			var resp map[string]any
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.expectErr != "" {
+				if resp["code"] != tt.expectErr {
+					t.Errorf("expected code %s, got %v", tt.expectErr, resp["code"])
+				}
+				return
+			}
+			if resp["key"] != "rosa_0123456789abcdef_secretwxyz" || resp["last4"] != "wxyz" {
+				t.Errorf("expected the minted key in the response, got %v", resp)
+			}
+			if _, ok := resp["secretHash"]; ok {
+				t.Error("expected the key hash not to be returned")
+			}
+			if tt.expectExpiresAt != "" && resp["expiresAt"] != tt.expectExpiresAt {
+				t.Errorf("expected expiresAt %s, got %v", tt.expectExpiresAt, resp["expiresAt"])
+			}
+		})
+	}
+}
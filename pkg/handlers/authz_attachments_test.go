@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// attachmentService keeps attachments in memory per account, returning the
+// existing attachment for a repeated policy and target like AttachPolicy
+type attachmentService struct {
+	authz.Service
+	attachments map[string][]*authz.Attachment
+	created     int
+}
+
+func (s *attachmentService) AttachPolicy(ctx context.Context, accountID, policyID string, targetType authz.TargetType, targetID, name, description string) (*authz.Attachment, bool, error) {
+	for _, a := range s.attachments[accountID] {
+		if a.PolicyID == policyID && a.TargetType == targetType && a.TargetID == targetID {
+			return a, false, nil
+		}
+	}
+	s.created++
+	a := &authz.Attachment{
+		AttachmentID: fmt.Sprintf("att-%d", s.created),
+		PolicyID:     policyID,
+		TargetType:   targetType,
+		TargetID:     targetID,
+		Name:         name,
+		Description:  description,
+		CreatedAt:    "2026-03-01T12:00:00Z",
+	}
+	if s.attachments == nil {
+		s.attachments = map[string][]*authz.Attachment{}
+	}
+	s.attachments[accountID] = append(s.attachments[accountID], a)
+	return a, true, nil
+}
+
+func (s *attachmentService) ListAttachments(ctx context.Context, accountID string, filter authz.AttachmentFilter) ([]*authz.Attachment, error) {
+	return s.attachments[accountID], nil
+}
+
+func (s *attachmentService) GetAttachment(ctx context.Context, accountID, attachmentID string) (*authz.Attachment, error) {
+	for _, a := range s.attachments[accountID] {
+		if a.AttachmentID == attachmentID {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", authz.ErrAttachmentNotFound, attachmentID)
+}
+
+func (s *attachmentService) UpdateAttachment(ctx context.Context, accountID, attachmentID, name, description string) (*authz.Attachment, error) {
+	for _, a := range s.attachments[accountID] {
+		if a.AttachmentID == attachmentID {
+			a.Name, a.Description = name, description
+			return a, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", authz.ErrAttachmentNotFound, attachmentID)
+}
+
+func newAttachmentRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	return req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+}
+
+func TestAuthzHandler_CreateAttachment_Deduplicates(t *testing.T) {
+	service := &attachmentService{}
+	handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	body := `{"policyId": "pol-1", "targetType": "user", "targetId": "arn:aws:iam::123456789012:user/alice"}`
+
+	var ids []string
+	for _, expectCode := range []int{http.StatusCreated, http.StatusOK} {
+		w := httptest.NewRecorder()
+		handler.CreateAttachment(w, newAttachmentRequest(http.MethodPost, "/api/v0/authz/attachments", body))
+
+		if w.Code != expectCode {
+			t.Fatalf("status = %d, want %d: %s", w.Code, expectCode, w.Body.String())
+		}
+		var resp apiv0.AttachmentResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		ids = append(ids, resp.AttachmentID)
+	}
+
+	if ids[0] != ids[1] {
+		t.Errorf("expected the repeated attach to return %s, got %s", ids[0], ids[1])
+	}
+	if n := len(service.attachments["123456789012"]); n != 1 {
+		t.Errorf("expected one attachment, got %d", n)
+	}
+}
+
+func TestAuthzHandler_AttachmentMetadata(t *testing.T) {
+	service := &attachmentService{}
+	handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	handler.CreateAttachment(w, newAttachmentRequest(http.MethodPost, "/api/v0/authz/attachments",
+		`{"policyId": "pol-1", "targetType": "group", "targetId": "oncall", "name": "oncall-read", "description": "Pager rotation reads clusters"}`))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d: %s", w.Code, w.Body.String())
+	}
+	var created apiv0.AttachmentResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Name != "oncall-read" || created.Description != "Pager rotation reads clusters" {
+		t.Errorf("expected the name and description back, got %+v", created)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ListAttachments(w, newAttachmentRequest(http.MethodGet, "/api/v0/authz/attachments", ""))
+	var list apiv0.AttachmentListResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+		t.Fatalf("failed to decode list: %v", err)
+	}
+	if list.Total != 1 || list.Items[0].Name != "oncall-read" {
+		t.Errorf("expected the named attachment listed, got %+v", list)
+	}
+
+	tests := []struct {
+		name         string
+		id           string
+		dryRun       bool
+		expectCode   int
+		expectStored string
+	}{
+		{name: "dry run", id: created.AttachmentID, dryRun: true, expectCode: http.StatusOK, expectStored: "oncall-read"},
+		{name: "rename", id: created.AttachmentID, expectCode: http.StatusOK, expectStored: "oncall-readonly"},
+		{name: "unknown attachment", id: "att-404", expectCode: http.StatusNotFound, expectStored: "oncall-readonly"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newAttachmentRequest(http.MethodPatch, "/api/v0/authz/attachments/"+tt.id, `{"name": "oncall-readonly", "description": "Renamed"}`)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			if tt.dryRun {
+				req = req.WithContext(middleware.WithDryRun(req.Context()))
+			}
+			w := httptest.NewRecorder()
+			handler.UpdateAttachment(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.expectCode, w.Body.String())
+			}
+			if got := service.attachments["123456789012"][0].Name; got != tt.expectStored {
+				t.Errorf("stored name = %q, want %q", got, tt.expectStored)
+			}
+		})
+	}
+}
+
+func TestAuthzHandler_GetAttachment(t *testing.T) {
+	service := &attachmentService{attachments: map[string][]*authz.Attachment{
+		"123456789012": {{AttachmentID: "att-1", PolicyID: "pol-1", TargetType: authz.TargetTypeGroup, TargetID: "oncall", Name: "oncall-read", CreatedAt: "2026-03-01T12:00:00Z"}},
+		"210987654321": {{AttachmentID: "att-2", PolicyID: "pol-2", TargetType: authz.TargetTypeGroup, TargetID: "auditors"}},
+	}}
+	handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name       string
+		id         string
+		expectCode int
+	}{
+		{name: "found", id: "att-1", expectCode: http.StatusOK},
+		{name: "not found", id: "att-404", expectCode: http.StatusNotFound},
+		{name: "other account", id: "att-2", expectCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mux.SetURLVars(newAttachmentRequest(http.MethodGet, "/api/v0/authz/attachments/"+tt.id, ""), map[string]string{"id": tt.id})
+			w := httptest.NewRecorder()
+			handler.GetAttachment(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.expectCode, w.Body.String())
+			}
+			if tt.expectCode != http.StatusOK {
+				return
+			}
+			var resp apiv0.AttachmentResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			want := apiv0.AttachmentResponse{Kind: "Attachment", AttachmentID: "att-1", PolicyID: "pol-1", TargetType: "group", TargetID: "oncall", Name: "oncall-read", CreatedAt: "2026-03-01T12:00:00Z"}
+			if resp != want {
+				t.Errorf("got %+v, want %+v", resp, want)
+			}
+		})
+	}
+}
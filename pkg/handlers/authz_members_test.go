@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// versionedMemberService keeps one group's members in memory at a members
+// version, conflicting like the store does
+type versionedMemberService struct {
+	authz.Service
+	members []string
+	version int64
+}
+
+func (s *versionedMemberService) GetGroup(ctx context.Context, accountID, groupID string) (*store.Group, error) {
+	return &store.Group{GroupID: groupID, MembersVersion: s.version}, nil
+}
+
+func (s *versionedMemberService) ListGroupMembers(ctx context.Context, accountID, groupID string) ([]string, error) {
+	return s.members, nil
+}
+
+func (s *versionedMemberService) UpdateGroupMembers(ctx context.Context, accountID, groupID string, version *int64, add, remove []string) ([]authz.MemberResult, int64, error) {
+	if version != nil && *version != s.version {
+		return nil, 0, fmt.Errorf("%w: group %s", store.ErrMembersConflict, groupID)
+	}
+	var results []authz.MemberResult
+	for _, arn := range add {
+		s.members = append(s.members, arn)
+		results = append(results, authz.MemberResult{MemberARN: arn, Operation: "add", Status: authz.MemberAdded})
+	}
+	for _, arn := range remove {
+		s.members = slices.DeleteFunc(s.members, func(m string) bool { return m == arn })
+		results = append(results, authz.MemberResult{MemberARN: arn, Operation: "remove", Status: authz.MemberRemoved})
+	}
+	s.version++
+	return results, s.version, nil
+}
+
+func TestAuthzHandler_UpdateGroupMembers_Version(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		expectCode    int
+		expectVersion int64
+		expectMembers []string
+	}{
+		{name: "no version", body: `{"add": ["arn:aws:iam::123456789012:user/bob"]}`, expectCode: http.StatusOK, expectVersion: 4, expectMembers: []string{"arn:aws:iam::123456789012:user/alice", "arn:aws:iam::123456789012:user/bob"}},
+		{name: "current version", body: `{"remove": ["arn:aws:iam::123456789012:user/alice"], "version": 3}`, expectCode: http.StatusOK, expectVersion: 4, expectMembers: []string{}},
+		{name: "stale version", body: `{"add": ["arn:aws:iam::123456789012:user/bob"], "version": 2}`, expectCode: http.StatusConflict, expectVersion: 3, expectMembers: []string{"arn:aws:iam::123456789012:user/alice"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &versionedMemberService{members: []string{"arn:aws:iam::123456789012:user/alice"}, version: 3}
+			handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v0/authz/groups/g1/members", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			req = mux.SetURLVars(req, map[string]string{"id": "g1"})
+			w := httptest.NewRecorder()
+			handler.UpdateGroupMembers(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			var resp apiv0.MemberConflictResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.expectCode == http.StatusConflict && resp.Code != "concurrent-modification" {
+				t.Errorf("expected code concurrent-modification, got %q", resp.Code)
+			}
+			if resp.Version != tt.expectVersion {
+				t.Errorf("expected version %d, got %d", tt.expectVersion, resp.Version)
+			}
+			if !slices.Equal(resp.Items, tt.expectMembers) || resp.Total != len(tt.expectMembers) {
+				t.Errorf("expected members %v, got %v (total %d)", tt.expectMembers, resp.Items, resp.Total)
+			}
+		})
+	}
+}
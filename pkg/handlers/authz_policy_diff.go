@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// GetPolicyDiff handles GET /api/v0/authz/policies/{id}/diff, diffing the
+// policy's Cedar text against the against parameter: either the ID of a
+// staged change request updating the policy, or proposed Cedar text
+func (h *AuthzHandler) GetPolicyDiff(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	policyID := mux.Vars(r)["id"]
+
+	against := r.URL.Query().Get("against")
+	if strings.TrimSpace(against) == "" {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", "against is required: a change request ID or Cedar policy text")
+		return
+	}
+
+	p, ok := h.getPolicy(w, ctx, accountID, policyID)
+	if !ok {
+		return
+	}
+
+	resp := apiv0.PolicyDiffResponse{Kind: "PolicyDiff", PolicyID: p.PolicyID}
+	proposed := against
+	if _, err := uuid.Parse(against); err == nil {
+		cr, err := h.service.GetChangeRequest(ctx, accountID, against)
+		if errors.Is(err, store.ErrChangeRequestNotFound) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Change request not found")
+			return
+		}
+		if err != nil {
+			h.logger.Error("failed to get change request", "error", err, "account_id", accountID, "change_id", against)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get change request")
+			return
+		}
+		path, _, _ := strings.Cut(cr.Path, "?")
+		var req apiv0.CreatePolicyRequest
+		if cr.Method != http.MethodPut || !strings.HasSuffix(path, "/authz/policies/"+policyID) ||
+			json.Unmarshal([]byte(cr.Body), &req) != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", "Change request "+against+" does not update policy "+policyID)
+			return
+		}
+		resp.ChangeID = cr.ChangeID
+		proposed = req.Policy
+	}
+
+	resp.PolicyDiff = authz.DiffPolicies(p.CedarPolicy, proposed)
+	writeResponse(w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+const stagedChangeID = "5f0c6b1e-8a7d-4e8e-9d2a-3c1b2a4f6e7d"
+
+// policyDiffService serves one policy and one staged change request
+type policyDiffService struct {
+	authz.Service
+}
+
+func (s *policyDiffService) GetPolicy(ctx context.Context, accountID, policyID string) (*store.Policy, error) {
+	if policyID != "policy-1" {
+		return nil, nil
+	}
+	return &store.Policy{PolicyID: "policy-1", CedarPolicy: `permit (principal, action == ROSA::Action::"ListClusters", resource);`}, nil
+}
+
+func (s *policyDiffService) GetChangeRequest(ctx context.Context, accountID, changeID string) (*store.ChangeRequest, error) {
+	if changeID != stagedChangeID {
+		return nil, store.ErrChangeRequestNotFound
+	}
+	return &store.ChangeRequest{
+		ChangeID: stagedChangeID,
+		Method:   http.MethodPut,
+		Path:     "/api/v0/authz/policies/policy-1",
+		Body:     `{"name":"p","policy":"permit (principal, action == ROSA::Action::\"DescribeCluster\", resource);"}`,
+	}, nil
+}
+
+func TestAuthzHandler_GetPolicyDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		policyID      string
+		against       string
+		expectCode    int
+		expectError   string
+		expectAdded   int
+		expectRemoved int
+	}{
+		{name: "cedar text", policyID: "policy-1", against: `permit(principal,action==ROSA::Action::"ListClusters",resource); forbid (principal, action, resource);`, expectCode: http.StatusOK, expectAdded: 1},
+		{name: "staged change", policyID: "policy-1", against: stagedChangeID, expectCode: http.StatusOK, expectAdded: 1, expectRemoved: 1},
+		{name: "unknown change", policyID: "policy-1", against: "00000000-0000-0000-0000-000000000000", expectCode: http.StatusNotFound, expectError: "not-found"},
+		{name: "change for another policy", policyID: "policy-2", against: stagedChangeID, expectCode: http.StatusNotFound, expectError: "not-found"},
+		{name: "missing against", policyID: "policy-1", expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuthzHandler(nil, &policyDiffService{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			target := "/api/v0/authz/policies/" + tt.policyID + "/diff?against=" + url.QueryEscape(tt.against)
+			req := httptest.NewRequest(http.MethodGet, target, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.policyID})
+			w := httptest.NewRecorder()
+			handler.GetPolicyDiff(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]any
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+
+			var resp apiv0.PolicyDiffResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "PolicyDiff" || resp.Added != tt.expectAdded || resp.Removed != tt.expectRemoved || resp.Text == "" {
+				t.Errorf("expected %d added and %d removed, got %+v", tt.expectAdded, tt.expectRemoved, resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package handlers
+
+import (
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+// FormatPolicy handles POST /api/v0/authz/policies/format, returning the
+// Cedar text formatted the way policies are stored on create and update
+func (h *AuthzHandler) FormatPolicy(w http.ResponseWriter, r *http.Request) {
+	var req apiv0.FormatPolicyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+
+	formatted, err := authz.FormatPolicy(req.Policy)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-policy", err.Error())
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.FormattedPolicyResponse{
+		Kind:    "FormattedPolicy",
+		Policy:  formatted,
+		Changed: formatted != req.Policy,
+	})
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+)
+
+func TestAuthzHandler_FormatPolicy(t *testing.T) {
+	formatted := "permit (\n  principal,\n  action == ROSA::Action::\"ListClusters\",\n  resource\n);\n"
+	alreadyFormatted, _ := json.Marshal(apiv0.FormatPolicyRequest{Policy: formatted})
+
+	tests := []struct {
+		name          string
+		body          string
+		expectCode    int
+		expectError   string
+		expectChanged bool
+	}{
+		{name: "reformatted", body: `{"policy": "permit(principal, action==ROSA::Action::\"ListClusters\", resource);"}`, expectCode: http.StatusOK, expectChanged: true},
+		{name: "already formatted", body: string(alreadyFormatted), expectCode: http.StatusOK},
+		{name: "not cedar", body: `{"policy": "allow everything"}`, expectCode: http.StatusBadRequest, expectError: "invalid-policy"},
+		{name: "malformed body", body: `{`, expectCode: http.StatusBadRequest, expectError: "invalid-request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewAuthzHandler(nil, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/authz/policies/format", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			handler.FormatPolicy(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]any
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+
+			var resp apiv0.FormattedPolicyResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "FormattedPolicy" || resp.Policy != formatted || resp.Changed != tt.expectChanged {
+				t.Errorf("expected %q (changed %v), got %+v", formatted, tt.expectChanged, resp)
+			}
+		})
+	}
+}
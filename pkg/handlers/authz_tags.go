@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// SetPolicyTags handles PUT /api/v0/authz/policies/{id}/tags
+func (h *AuthzHandler) SetPolicyTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	policyID := mux.Vars(r)["id"]
+
+	var req apiv0.SetTagsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if err := authz.ValidateTags(req.Tags); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-tags", err.Error())
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		current, ok := h.getPolicy(w, ctx, accountID, policyID)
+		if !ok {
+			return
+		}
+		writeDryRun(w, r, http.StatusOK, apiv0.PolicyResponse{
+			Kind:        "Policy",
+			PolicyID:    current.PolicyID,
+			Name:        current.Name,
+			Description: current.Description,
+			Tags:        req.Tags,
+			CreatedAt:   current.CreatedAt,
+		})
+		return
+	}
+
+	p, err := h.service.SetPolicyTags(ctx, accountID, policyID, req.Tags)
+	if err != nil {
+		h.logger.Error("failed to set policy tags", "error", err, "account_id", accountID, "policy_id", policyID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to set policy tags")
+		return
+	}
+	if p == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Policy not found")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.PolicyResponse{
+		Kind:        "Policy",
+		PolicyID:    p.PolicyID,
+		Name:        p.Name,
+		Description: p.Description,
+		Tags:        p.Tags,
+		CreatedAt:   p.CreatedAt,
+	})
+}
+
+// SetGroupTags handles PUT /api/v0/authz/groups/{id}/tags
+func (h *AuthzHandler) SetGroupTags(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	groupID := mux.Vars(r)["id"]
+
+	var req apiv0.SetTagsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if err := authz.ValidateTags(req.Tags); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-tags", err.Error())
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		g, err := h.service.GetGroup(ctx, accountID, groupID)
+		if err != nil {
+			h.logger.Error("failed to get group", "error", err, "account_id", accountID, "group_id", groupID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to set group tags")
+			return
+		}
+		if g == nil {
+			h.writeError(w, http.StatusNotFound, "not-found", "Group not found")
+			return
+		}
+		writeDryRun(w, r, http.StatusOK, apiv0.GroupResponse{Kind: "Group", GroupID: g.GroupID, Name: g.Name, Description: g.Description, Tags: req.Tags, CreatedAt: g.CreatedAt})
+		return
+	}
+
+	g, err := h.service.SetGroupTags(ctx, accountID, groupID, req.Tags)
+	if errors.Is(err, authz.ErrGroupNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Group not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to set group tags", "error", err, "account_id", accountID, "group_id", groupID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to set group tags")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.GroupResponse{
+		Kind:        "Group",
+		GroupID:     g.GroupID,
+		Name:        g.Name,
+		Description: g.Description,
+		Tags:        g.Tags,
+		CreatedAt:   g.CreatedAt,
+	})
+}
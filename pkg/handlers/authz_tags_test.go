@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// taggedGroupService keeps groups in memory
+type taggedGroupService struct {
+	authz.Service
+	groups map[string]*store.Group
+}
+
+func (s *taggedGroupService) ListGroups(ctx context.Context, accountID string) ([]*store.Group, error) {
+	groups := make([]*store.Group, 0, len(s.groups))
+	for _, id := range []string{"g1", "g2", "g3"} {
+		if g, ok := s.groups[id]; ok {
+			groups = append(groups, g)
+		}
+	}
+	return groups, nil
+}
+
+func (s *taggedGroupService) SetGroupTags(ctx context.Context, accountID, groupID string, tags map[string]string) (*store.Group, error) {
+	g, ok := s.groups[groupID]
+	if !ok {
+		return nil, authz.ErrGroupNotFound
+	}
+	g.Tags = tags
+	return g, nil
+}
+
+func newTaggedGroupService() *taggedGroupService {
+	return &taggedGroupService{groups: map[string]*store.Group{
+		"g1": {GroupID: "g1", Name: "payments-admins", Tags: map[string]string{"team": "payments", "env": "prod"}},
+		"g2": {GroupID: "g2", Name: "payments-devs", Tags: map[string]string{"team": "payments"}},
+		"g3": {GroupID: "g3", Name: "untagged"},
+	}}
+}
+
+func TestAuthzHandler_ListGroups_TagFilter(t *testing.T) {
+	tests := []struct {
+		query       string
+		expectCode  int
+		expectItems []string
+	}{
+		{query: "", expectCode: http.StatusOK, expectItems: []string{"g1", "g2", "g3"}},
+		{query: "?tag=team=payments", expectCode: http.StatusOK, expectItems: []string{"g1", "g2"}},
+		{query: "?tag=team&tag=env=prod", expectCode: http.StatusOK, expectItems: []string{"g1"}},
+		{query: "?tag==prod", expectCode: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			handler := NewAuthzHandler(nil, newTaggedGroupService(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/authz/groups"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+			handler.ListGroups(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectCode != http.StatusOK {
+				return
+			}
+			var resp apiv0.GroupListResponse
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			var ids []string
+			for _, g := range resp.Items {
+				ids = append(ids, g.GroupID)
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.expectItems, ",") || resp.Total != len(tt.expectItems) {
+				t.Errorf("expected %v, got %v (total %d)", tt.expectItems, ids, resp.Total)
+			}
+		})
+	}
+}
+
+func TestAuthzHandler_SetGroupTags(t *testing.T) {
+	tests := []struct {
+		name        string
+		groupID     string
+		body        string
+		expectCode  int
+		expectError string
+	}{
+		{name: "replaced", groupID: "g3", body: `{"tags": {"team": "platform"}}`, expectCode: http.StatusOK},
+		{name: "invalid key", groupID: "g3", body: `{"tags": {"team=platform": "x"}}`, expectCode: http.StatusBadRequest, expectError: "invalid-tags"},
+		{name: "unknown group", groupID: "g9", body: `{"tags": {}}`, expectCode: http.StatusNotFound, expectError: "not-found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTaggedGroupService()
+			handler := NewAuthzHandler(nil, service, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v0/authz/groups/"+tt.groupID+"/tags", strings.NewReader(tt.body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			req = mux.SetURLVars(req, map[string]string{"id": tt.groupID})
+			w := httptest.NewRecorder()
+			handler.SetGroupTags(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			var resp map[string]any
+			_ = json.NewDecoder(w.Body).Decode(&resp)
+			if tt.expectError != "" {
+				if resp["code"] != tt.expectError {
+					t.Errorf("expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+			if tags, _ := resp["tags"].(map[string]any); tags["team"] != "platform" {
+				t.Errorf("expected the new tags in the response, got %v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// ChangeRequestsHandler reviews the authz mutations staged by accounts in
+// change review mode (see middleware.ChangeReview)
+type ChangeRequestsHandler struct {
+	service authz.Service
+	// apply serves approved requests; it is the router they were staged from
+	apply  http.Handler
+	logger *slog.Logger
+}
+
+// NewChangeRequestsHandler creates a new ChangeRequestsHandler that applies
+// approved change requests by replaying them through apply
+func NewChangeRequestsHandler(service authz.Service, apply http.Handler, logger *slog.Logger) *ChangeRequestsHandler {
+	return &ChangeRequestsHandler{
+		service: service,
+		apply:   apply,
+		logger:  logger,
+	}
+}
+
+// List handles GET /api/v0/authz/changes
+// status=pending|approved|rejected narrows the result.
+func (h *ChangeRequestsHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	switch status {
+	case "", store.ChangeRequestPending, store.ChangeRequestApproved, store.ChangeRequestRejected:
+	default:
+		h.writeError(w, http.StatusBadRequest, "invalid-filter", "status must be pending, approved or rejected")
+		return
+	}
+
+	requests, err := h.service.ListChangeRequests(ctx, accountID, status)
+	if err != nil {
+		h.logger.Error("failed to list change requests", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list change requests")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.ChangeRequestListResponse{
+		Kind:  "ChangeRequestList",
+		Items: emptyIfNil(requests),
+		Total: len(requests),
+	})
+}
+
+// Get handles GET /api/v0/authz/changes/{changeId}
+func (h *ChangeRequestsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	changeID := mux.Vars(r)["changeId"]
+
+	cr, err := h.service.GetChangeRequest(ctx, accountID, changeID)
+	if errors.Is(err, store.ErrChangeRequestNotFound) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Change request not found")
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to get change request", "error", err, "account_id", accountID, "change_id", changeID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get change request")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.ChangeRequestResponse{Kind: "ChangeRequest", ChangeRequest: cr})
+}
+
+// Approve handles POST /api/v0/authz/changes/{changeId}/approve
+// The staged request is replayed as the approver, and the response it got
+// is returned as the change request's result.
+func (h *ChangeRequestsHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, true)
+}
+
+// Reject handles POST /api/v0/authz/changes/{changeId}/reject
+func (h *ChangeRequestsHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	h.decide(w, r, false)
+}
+
+func (h *ChangeRequestsHandler) decide(w http.ResponseWriter, r *http.Request, approve bool) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	changeID := mux.Vars(r)["changeId"]
+	callerARN := middleware.GetCallerARN(ctx)
+
+	if middleware.IsDryRun(ctx) {
+		h.dryRunDecide(w, r, accountID, changeID, approve, callerARN)
+		return
+	}
+
+	cr, err := h.service.DecideChangeRequest(ctx, accountID, changeID, approve, callerARN)
+	switch {
+	case errors.Is(err, store.ErrChangeRequestNotFound):
+		h.writeError(w, http.StatusNotFound, "not-found", "Change request not found")
+		return
+	case errors.Is(err, store.ErrChangeRequestDecided):
+		h.writeError(w, http.StatusConflict, "already-decided", "Change request is no longer pending")
+		return
+	case errors.Is(err, authz.ErrSelfApproval):
+		h.writeError(w, http.StatusForbidden, "self-approval", err.Error())
+		return
+	case err != nil:
+		h.logger.Error("failed to decide change request", "error", err, "account_id", accountID, "change_id", changeID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to decide change request")
+		return
+	}
+
+	resp := apiv0.ChangeRequestResponse{Kind: "ChangeRequest", ChangeRequest: cr}
+	if approve {
+		status, body := h.replay(r, cr)
+		if err := h.service.RecordChangeRequestResult(ctx, accountID, changeID, status); err != nil {
+			// The change has been applied; only its record is incomplete
+			h.logger.Error("failed to record change request result", "error", err, "account_id", accountID, "change_id", changeID)
+		}
+		cr.ResultStatus = status
+		if json.Valid(body) {
+			resp.Result = body
+		}
+	}
+
+	h.logger.Info("change request decided",
+		"account_id", accountID,
+		"change_id", changeID,
+		"status", cr.Status,
+		"result_status", cr.ResultStatus,
+		"requested_by", cr.RequestedBy,
+		"decided_by", callerARN,
+	)
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+// dryRunDecide previews a decision. Approving previews the staged request too:
+// the replay inherits the dry run, so its result is a DryRunResult.
+func (h *ChangeRequestsHandler) dryRunDecide(w http.ResponseWriter, r *http.Request, accountID, changeID string, approve bool, callerARN string) {
+	cr, err := h.service.GetChangeRequest(r.Context(), accountID, changeID)
+	switch {
+	case errors.Is(err, store.ErrChangeRequestNotFound):
+		h.writeError(w, http.StatusNotFound, "not-found", "Change request not found")
+		return
+	case err != nil:
+		h.logger.Error("failed to get change request", "error", err, "account_id", accountID, "change_id", changeID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to decide change request")
+		return
+	case cr.Status != store.ChangeRequestPending:
+		h.writeError(w, http.StatusConflict, "already-decided", "Change request is no longer pending")
+		return
+	case approve && cr.RequestedBy == callerARN:
+		h.writeError(w, http.StatusForbidden, "self-approval", authz.ErrSelfApproval.Error())
+		return
+	}
+
+	cr.Status = store.ChangeRequestRejected
+	cr.DecidedBy = callerARN
+	resp := apiv0.ChangeRequestResponse{Kind: "ChangeRequest", ChangeRequest: cr}
+	if approve {
+		cr.Status = store.ChangeRequestApproved
+		status, body := h.replay(r, cr)
+		cr.ResultStatus = status
+		if json.Valid(body) {
+			resp.Result = body
+		}
+	}
+	writeDryRun(w, r, http.StatusOK, resp)
+}
+
+// replay serves a staged request with the approver's identity and returns the
+// status and body of its response
+func (h *ChangeRequestsHandler) replay(r *http.Request, cr *store.ChangeRequest) (int, []byte) {
+	req, err := http.NewRequestWithContext(middleware.WithChangeApproved(r.Context()), cr.Method, cr.Path, strings.NewReader(cr.Body))
+	if err != nil {
+		h.logger.Error("failed to build staged request", "error", err, "change_id", cr.ChangeID)
+		return http.StatusInternalServerError, nil
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	rec := &replayRecorder{header: make(http.Header), status: http.StatusOK}
+	h.apply.ServeHTTP(rec, req)
+	return rec.status, rec.body.Bytes()
+}
+
+// replayRecorder captures the response to a replayed request
+type replayRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *replayRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *replayRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *replayRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+func (h *ChangeRequestsHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
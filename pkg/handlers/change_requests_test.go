@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// changeReviewService keeps one account's change requests in change review
+// mode
+type changeReviewService struct {
+	authz.Service
+	requests map[string]*store.ChangeRequest
+	results  map[string]int
+}
+
+func (s *changeReviewService) ChangeReviewEnabled(ctx context.Context, accountID string) (bool, error) {
+	return true, nil
+}
+
+func (s *changeReviewService) StageChangeRequest(ctx context.Context, cr *store.ChangeRequest) error {
+	cr.ChangeID = "change-1"
+	cr.Status = store.ChangeRequestPending
+	s.requests[cr.ChangeID] = cr
+	return nil
+}
+
+func (s *changeReviewService) GetChangeRequest(ctx context.Context, accountID, changeID string) (*store.ChangeRequest, error) {
+	cr, ok := s.requests[changeID]
+	if !ok {
+		return nil, store.ErrChangeRequestNotFound
+	}
+	copied := *cr
+	return &copied, nil
+}
+
+func (s *changeReviewService) DecideChangeRequest(ctx context.Context, accountID, changeID string, approve bool, decidedBy string) (*store.ChangeRequest, error) {
+	cr, ok := s.requests[changeID]
+	if !ok {
+		return nil, store.ErrChangeRequestNotFound
+	}
+	if cr.Status != store.ChangeRequestPending {
+		return nil, store.ErrChangeRequestDecided
+	}
+	if approve && cr.RequestedBy == decidedBy {
+		return nil, authz.ErrSelfApproval
+	}
+	cr.Status = store.ChangeRequestRejected
+	if approve {
+		cr.Status = store.ChangeRequestApproved
+	}
+	cr.DecidedBy = decidedBy
+	return cr, nil
+}
+
+func (s *changeReviewService) RecordChangeRequestResult(ctx context.Context, accountID, changeID string, resultStatus int) error {
+	s.results[changeID] = resultStatus
+	return nil
+}
+
+func TestChangeRequests_StageAndApprove(t *testing.T) {
+	const (
+		accountID = "123456789012"
+		alice     = "arn:aws:iam::123456789012:user/alice"
+		bob       = "arn:aws:iam::123456789012:user/bob"
+	)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	svc := &changeReviewService{requests: map[string]*store.ChangeRequest{}, results: map[string]int{}}
+
+	var deleted []string
+	router := mux.NewRouter()
+	authzRouter := router.PathPrefix("/api/v0/authz").Subrouter()
+	authzRouter.Use(middleware.NewChangeReview(svc, logger, "/api/v0/authz/changes").Stage)
+	h := NewChangeRequestsHandler(svc, authzRouter, logger)
+	authzRouter.HandleFunc("/policies/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if writeDryRun(w, r, http.StatusOK, nil) {
+			return
+		}
+		deleted = append(deleted, mux.Vars(r)["id"])
+		writeResponse(w, r, http.StatusOK, map[string]string{"deletedBy": middleware.GetCallerARN(r.Context())})
+	}).Methods(http.MethodDelete)
+	authzRouter.HandleFunc("/changes/{changeId}/approve", h.Approve).Methods(http.MethodPost)
+
+	router.Use(middleware.DryRun)
+
+	serve := func(method, target, callerARN string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, accountID)
+		ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, callerARN)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req.WithContext(ctx))
+		return rec
+	}
+
+	if rec := serve(http.MethodDelete, "/api/v0/authz/policies/p1", alice); rec.Code != http.StatusAccepted {
+		t.Fatalf("expected the delete to be staged with 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(deleted) != 0 {
+		t.Fatal("staged delete was applied")
+	}
+
+	// A dry run of the approval previews the replay without deciding
+	dryRun := serve(http.MethodPost, "/api/v0/authz/changes/change-1/approve?dryRun=true", bob)
+	if dryRun.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", dryRun.Code, dryRun.Body.String())
+	}
+	var preview struct {
+		Kind    string `json:"kind"`
+		Preview struct {
+			Status string             `json:"status"`
+			Result apiv0.DryRunResult `json:"result"`
+		} `json:"preview"`
+	}
+	if err := json.NewDecoder(dryRun.Body).Decode(&preview); err != nil {
+		t.Fatal(err)
+	}
+	if preview.Kind != "DryRun" || preview.Preview.Status != store.ChangeRequestApproved || preview.Preview.Result.Kind != "DryRun" {
+		t.Errorf("unexpected dry run response %+v", preview)
+	}
+	if len(deleted) != 0 || svc.requests["change-1"].Status != store.ChangeRequestPending {
+		t.Fatal("dry run approval applied the change")
+	}
+
+	if rec := serve(http.MethodPost, "/api/v0/authz/changes/change-1/approve", alice); rec.Code != http.StatusForbidden {
+		t.Fatalf("expected self-approval to be refused with 403, got %d", rec.Code)
+	}
+
+	rec := serve(http.MethodPost, "/api/v0/authz/changes/change-1/approve", bob)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(deleted) != 1 || deleted[0] != "p1" {
+		t.Fatalf("expected the approved delete to be replayed once, got %v", deleted)
+	}
+	if svc.results["change-1"] != http.StatusOK {
+		t.Errorf("expected result status 200 to be recorded, got %d", svc.results["change-1"])
+	}
+
+	var resp struct {
+		Status       string            `json:"status"`
+		ResultStatus int               `json:"resultStatus"`
+		Result       map[string]string `json:"result"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Status != store.ChangeRequestApproved || resp.ResultStatus != http.StatusOK || resp.Result["deletedBy"] != bob {
+		t.Errorf("unexpected approval response %+v", resp)
+	}
+
+	if rec := serve(http.MethodPost, "/api/v0/authz/changes/change-1/approve", bob); rec.Code != http.StatusConflict {
+		t.Errorf("expected a second approval to conflict, got %d", rec.Code)
+	}
+}
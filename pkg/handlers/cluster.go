@@ -8,6 +8,7 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/hyperfleet"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
@@ -18,6 +19,8 @@ import (
 type ClusterHandler struct {
 	hyperfleetClient *hyperfleet.Client
 	maestroClient    *maestro.Client
+	pageLimits       PageLimits
+	requiredTags     *RequiredTags
 	logger           *slog.Logger
 }
 
@@ -26,28 +29,41 @@ func NewClusterHandler(hyperfleetClient *hyperfleet.Client, maestroClient *maest
 	return &ClusterHandler{
 		hyperfleetClient: hyperfleetClient,
 		maestroClient:    maestroClient,
+		pageLimits:       DefaultTenantPageLimits,
 		logger:           logger,
 	}
 }
 
+// WithPageLimits sets the default and maximum page size for List
+func (h *ClusterHandler) WithPageLimits(limits PageLimits) *ClusterHandler {
+	h.pageLimits = limits
+	return h
+}
+
+// WithRequiredTags rejects cluster creation without the required tags
+func (h *ClusterHandler) WithRequiredTags(tags *RequiredTags) *ClusterHandler {
+	h.requiredTags = tags
+	return h
+}
+
 // List handles GET /api/v0/clusters
 func (h *ClusterHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 	status := r.URL.Query().Get("status")
 
-	limit := 50 // default
-	offset := 0 // default
-
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	limit, err := pageSize(r, "limit", h.pageLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-LIST-002", err.Error())
+		return
 	}
+	offset := 0 // default
 
 	if offsetStr != "" {
 		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
@@ -65,25 +81,30 @@ func (h *ClusterHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"items":  clusters,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+	response := apiv0.ClusterList{
+		Kind:   "ClusterList",
+		Items:  emptyIfNil(clusters),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	setOffsetLinks(w, r, offset, limit, total)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
 // Create handles POST /api/v0/clusters
 func (h *ClusterHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	userEmail := middleware.GetUserID(ctx) // May be empty if not provided
 
 	var req types.ClusterCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-CREATE-001", "Invalid request body")
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-CREATE-001", invalidBodyReason(err))
 		return
 	}
 
@@ -93,6 +114,22 @@ func (h *ClusterHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	tags, err := middleware.ParseRequestTags(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-CREATE-008", err.Error())
+		return
+	}
+	missing, err := h.requiredTags.missingClusterTags(ctx, accountID, tags)
+	if err != nil {
+		h.logger.Error("failed to get required tags", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "CLUSTERS-MGMT-CREATE-010", "Failed to check required tags")
+		return
+	}
+	if len(missing) > 0 {
+		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-CREATE-009", missingTagsReason(missing))
+		return
+	}
+
 	// Get CloudFront URL from the first management cluster before creating the cluster
 	managementClusters, err := h.maestroClient.ListConsumers(ctx, 1, 1)
 	if err != nil {
@@ -135,6 +172,11 @@ func (h *ClusterHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("creating cluster", "account_id", accountID, "cluster_name", req.Name)
 
+	// A dry run previews the request Hyperfleet would be sent
+	if writeDryRun(w, r, http.StatusCreated, req) {
+		return
+	}
+
 	cluster, err := h.hyperfleetClient.CreateCluster(ctx, accountID, userEmail, &req)
 	if err != nil {
 		h.logger.Error("failed to create cluster", "error", err, "account_id", accountID)
@@ -167,13 +209,16 @@ func (h *ClusterHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("cluster created with cloudUrl", "cluster_id", cluster.ID, "cloudUrl", cluster.Spec["cloudUrl"])
 
-	h.writeJSON(w, http.StatusCreated, cluster)
+	writeResponse(w, r, http.StatusCreated, cluster)
 }
 
 // Get handles GET /api/v0/clusters/{id}
 func (h *ClusterHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
 
@@ -190,19 +235,22 @@ func (h *ClusterHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, cluster)
+	writeResponse(w, r, http.StatusOK, cluster)
 }
 
 // Update handles PUT /api/v0/clusters/{id}
 func (h *ClusterHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
 
 	var req types.ClusterUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-UPDATE-001", "Invalid request body")
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "CLUSTERS-MGMT-UPDATE-001", invalidBodyReason(err))
 		return
 	}
 
@@ -213,6 +261,13 @@ func (h *ClusterHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("updating cluster", "account_id", accountID, "cluster_id", clusterID)
 
+	if middleware.IsDryRun(ctx) {
+		if _, ok := h.getCluster(w, r, accountID, clusterID, "CLUSTERS-MGMT-UPDATE-003", "CLUSTERS-MGMT-UPDATE-004"); ok {
+			writeDryRun(w, r, http.StatusOK, req)
+		}
+		return
+	}
+
 	cluster, err := h.hyperfleetClient.UpdateCluster(ctx, accountID, clusterID, &req)
 	if err != nil {
 		if hyperfleet.IsNotFound(err) {
@@ -224,13 +279,16 @@ func (h *ClusterHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, cluster)
+	writeResponse(w, r, http.StatusOK, cluster)
 }
 
 // Delete handles DELETE /api/v0/clusters/{id}
 func (h *ClusterHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
 
@@ -239,6 +297,13 @@ func (h *ClusterHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("deleting cluster", "account_id", accountID, "cluster_id", clusterID, "force", force)
 
+	if middleware.IsDryRun(ctx) {
+		if cluster, ok := h.getCluster(w, r, accountID, clusterID, "CLUSTERS-MGMT-DELETE-001", "CLUSTERS-MGMT-DELETE-002"); ok {
+			writeDryRun(w, r, http.StatusAccepted, cluster)
+		}
+		return
+	}
+
 	err := h.hyperfleetClient.DeleteCluster(ctx, accountID, clusterID, force)
 	if err != nil {
 		if hyperfleet.IsNotFound(err) {
@@ -250,18 +315,21 @@ func (h *ClusterHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"message":    "Cluster deletion initiated",
-		"cluster_id": clusterID,
+	response := apiv0.ClusterDeletion{
+		Message:   "Cluster deletion initiated",
+		ClusterID: clusterID,
 	}
 
-	h.writeJSON(w, http.StatusAccepted, response)
+	writeResponse(w, r, http.StatusAccepted, response)
 }
 
 // GetStatus handles GET /api/v0/clusters/{id}/statuses
 func (h *ClusterHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	clusterID := vars["id"]
 
@@ -278,23 +346,28 @@ func (h *ClusterHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, status)
+	writeResponse(w, r, http.StatusOK, status)
 }
 
 // Helper methods
-func (h *ClusterHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(data)
+// getCluster looks a cluster up for a dry run, answering with the codes of the
+// operation it stands in for when it cannot
+func (h *ClusterHandler) getCluster(w http.ResponseWriter, r *http.Request, accountID, clusterID, notFoundCode, errorCode string) (*types.Cluster, bool) {
+	cluster, err := h.hyperfleetClient.GetCluster(r.Context(), accountID, clusterID)
+	if err != nil {
+		if hyperfleet.IsNotFound(err) {
+			h.writeError(w, http.StatusNotFound, notFoundCode, "Cluster not found")
+			return nil, false
+		}
+		h.logger.Error("failed to get cluster", "error", err, "account_id", accountID, "cluster_id", clusterID)
+		h.writeError(w, http.StatusInternalServerError, errorCode, "Failed to get cluster")
+		return nil, false
+	}
+	return cluster, true
 }
 
 func (h *ClusterHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	resp := map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
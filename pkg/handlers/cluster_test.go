@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gorilla/mux"
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/hyperfleet"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
 	"github.com/openshift/rosa-regional-platform-api/pkg/config"
@@ -768,6 +769,60 @@ func TestClusterHandler_Delete_NotFound(t *testing.T) {
 	}
 }
 
+// TestClusterHandler_Delete_DryRun tests that a dry run looks the cluster up
+// without deleting it
+func TestClusterHandler_Delete_DryRun(t *testing.T) {
+	deleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodDelete:
+			deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/hyperfleet/v1/clusters/cluster-123":
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": "cluster-123", "name": "test-cluster"})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": "404", "message": "cluster not found"})
+		}
+	}))
+	defer server.Close()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	hfClient := hyperfleet.NewClient(config.HyperfleetConfig{
+		BaseURL: server.URL,
+		Timeout: 30 * time.Second,
+	}, logger)
+	handler := NewClusterHandler(hfClient, nil, logger)
+
+	for id, want := range map[string]int{"cluster-123": http.StatusOK, "cluster-999": http.StatusNotFound} {
+		req := httptest.NewRequest(http.MethodDelete, "/api/v0/clusters/"+id, nil)
+		ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+		req = req.WithContext(middleware.WithDryRun(ctx))
+		req = mux.SetURLVars(req, map[string]string{"id": id})
+
+		w := httptest.NewRecorder()
+		handler.Delete(w, req)
+
+		if w.Code != want {
+			t.Fatalf("%s: expected status %d, got %d", id, want, w.Code)
+		}
+		if want != http.StatusOK {
+			continue
+		}
+		var result apiv0.DryRunResult
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if result.Kind != "DryRun" || result.Status != http.StatusAccepted {
+			t.Errorf("expected a dry run answered with 202, got %+v", result)
+		}
+	}
+	if deleted {
+		t.Error("dry run deleted the cluster")
+	}
+}
+
 // TestClusterHandler_GetStatus_Success tests successful status retrieval
 func TestClusterHandler_GetStatus_Success(t *testing.T) {
 	now := time.Now()
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// ConfigReloader re-reads the configuration file and applies the settings
+// that can change without a restart; dryRun only reports what would change
+type ConfigReloader func(ctx context.Context, dryRun bool) (*config.ReloadReport, error)
+
+// ConfigHandler handles the admin endpoint reloading the configuration
+type ConfigHandler struct {
+	reload ConfigReloader
+	logger *slog.Logger
+}
+
+// NewConfigHandler creates a new ConfigHandler, which has nothing to reload
+// until a reloader is set
+func NewConfigHandler(logger *slog.Logger) *ConfigHandler {
+	return &ConfigHandler{logger: logger}
+}
+
+// SetReloader reloads the configuration with reload. It must be set before
+// the server starts serving.
+func (h *ConfigHandler) SetReloader(reload ConfigReloader) {
+	h.reload = reload
+}
+
+// Reload handles POST /api/v0/admin/config/reload
+func (h *ConfigHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	if h.reload == nil {
+		h.writeError(w, http.StatusConflict, "no-config-file", "The server was not started with a configuration file")
+		return
+	}
+
+	dryRun := middleware.IsDryRun(ctx)
+	report, err := h.reload(ctx, dryRun)
+	if err != nil {
+		h.logger.Warn("configuration reload failed", "error", err, "caller_arn", middleware.GetCallerARN(ctx))
+		var invalid *config.ValidationError
+		if errors.As(err, &invalid) {
+			h.writeError(w, http.StatusUnprocessableEntity, "invalid-config", err.Error())
+			return
+		}
+		h.writeError(w, http.StatusBadRequest, "invalid-config-file", err.Error())
+		return
+	}
+
+	resp := apiv0.ConfigReloadResponse{Kind: "ConfigReload", ReloadReport: *report}
+	if writeDryRun(w, r, http.StatusOK, resp) {
+		return
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (h *ConfigHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+)
+
+func TestConfigHandler_Reload(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name       string
+		reload     ConfigReloader
+		expectCode int
+		expectKind string
+	}{
+		{
+			name:       "no config file",
+			expectCode: http.StatusConflict,
+			expectKind: "Error",
+		},
+		{
+			name: "report",
+			reload: func(ctx context.Context, dryRun bool) (*config.ReloadReport, error) {
+				return &config.ReloadReport{
+					Applied:  []config.SettingChange{{Setting: config.SettingLogLevel, From: "info", To: "debug"}},
+					Rejected: []config.SettingChange{{Setting: "api-port", From: "8000", To: "9000", Reason: "changing this setting requires a restart"}},
+				}, nil
+			},
+			expectCode: http.StatusOK,
+			expectKind: "ConfigReload",
+		},
+		{
+			name: "invalid configuration",
+			reload: func(ctx context.Context, dryRun bool) (*config.ReloadReport, error) {
+				return nil, &config.ValidationError{}
+			},
+			expectCode: http.StatusUnprocessableEntity,
+			expectKind: "Error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewConfigHandler(logger)
+			if tt.reload != nil {
+				handler.SetReloader(tt.reload)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/admin/config/reload", nil)
+			rr := httptest.NewRecorder()
+			handler.Reload(rr, req)
+
+			if rr.Code != tt.expectCode {
+				t.Errorf("expected status %d, got %d: %s", tt.expectCode, rr.Code, rr.Body.String())
+			}
+			var resp map[string]interface{}
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp["kind"] != tt.expectKind {
+				t.Errorf("expected kind %s, got %v", tt.expectKind, resp["kind"])
+			}
+		})
+	}
+}
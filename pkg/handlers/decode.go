@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// decodeJSON decodes the request body into v, rejecting unknown fields and
+// trailing data. Errors describe the offending field so they can be returned
+// to the caller; an empty body yields io.EOF.
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return describeDecodeError(err)
+	}
+	if dec.More() {
+		return errors.New("request body must contain a single JSON object")
+	}
+	return nil
+}
+
+func describeDecodeError(err error) error {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return err
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("request body is truncated JSON")
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("malformed JSON at offset %d", syntaxErr.Offset)
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Errorf("request body must be a JSON %s", typeErr.Type)
+		}
+		return fmt.Errorf("field %q must be %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json has no typed error for unknown fields
+		return fmt.Errorf("unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	default:
+		return err
+	}
+}
+
+// invalidBodyReason formats a decode error for an Error response
+func invalidBodyReason(err error) string {
+	if errors.Is(err, io.EOF) {
+		return "Request body is required"
+	}
+	return "Invalid request body: " + err.Error()
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	type spec struct {
+		Replicas int `json:"replicas"`
+	}
+	type request struct {
+		Name string `json:"name"`
+		Spec spec   `json:"spec"`
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		expectErr string
+	}{
+		{name: "valid", body: `{"name":"a","spec":{"replicas":2}}`},
+		{name: "unknown field", body: `{"name":"a","colour":"blue"}`, expectErr: `unknown field "colour"`},
+		{name: "wrong type", body: `{"spec":{"replicas":"two"}}`, expectErr: `field "spec.replicas" must be int, got string`},
+		{name: "malformed", body: `{"name":}`, expectErr: "malformed JSON at offset"},
+		{name: "truncated", body: `{"name":"a"`, expectErr: "truncated"},
+		{name: "trailing data", body: `{"name":"a"}{"name":"b"}`, expectErr: "single JSON object"},
+		{name: "not an object", body: `[1]`, expectErr: "must be a JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/test", strings.NewReader(tt.body))
+
+			var req request
+			err := decodeJSON(r, &req)
+			if tt.expectErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectErr) {
+				t.Errorf("expected error containing %q, got %v", tt.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestDecodeJSON_EmptyBody(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/test", nil)
+
+	var req map[string]any
+	if err := decodeJSON(r, &req); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
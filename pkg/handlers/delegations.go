@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// CreateDelegation handles POST /api/v0/accounts/{id}/delegations
+func (h *AccountsHandler) CreateDelegation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+	callerARN := middleware.GetCallerARN(ctx)
+
+	var req apiv0.CreateDelegationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+
+	if !accountIDPattern.MatchString(req.DelegateAccountID) {
+		h.writeError(w, http.StatusBadRequest, "invalid-delegate-account-id", "delegateAccountId must be a 12-digit AWS account ID")
+		return
+	}
+	if req.DelegateAccountID == accountID {
+		h.writeError(w, http.StatusBadRequest, "invalid-delegate-account-id", "An account cannot delegate to itself")
+		return
+	}
+	if len(req.Actions) == 0 {
+		h.writeError(w, http.StatusBadRequest, "missing-actions", "actions is required; use [\"*\"] to delegate every action")
+		return
+	}
+	if req.ExpiresAt != "" {
+		expiresAt, err := clock.Parse(req.ExpiresAt)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-expires-at", "expiresAt must be an RFC3339 time")
+			return
+		}
+		if !expiresAt.After(time.Now()) {
+			h.writeError(w, http.StatusBadRequest, "invalid-expires-at", "expiresAt must be in the future")
+			return
+		}
+		req.ExpiresAt = clock.Format(expiresAt)
+	}
+
+	delegation := &store.Delegation{
+		AccountID:         accountID,
+		DelegateAccountID: req.DelegateAccountID,
+		Actions:           req.Actions,
+		ExpiresAt:         req.ExpiresAt,
+		CreatedBy:         callerARN,
+	}
+	var err error
+	if middleware.IsDryRun(ctx) {
+		err = h.authorizer.CheckDelegation(ctx, delegation)
+	} else {
+		err = h.authorizer.CreateDelegation(ctx, delegation)
+	}
+	switch {
+	case errors.Is(err, authz.ErrAccountNotEnabled):
+		h.writeError(w, http.StatusNotFound, "not-found", err.Error())
+		return
+	case errors.Is(err, authz.ErrPrivilegedDelegation):
+		h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts cannot be delegated")
+		return
+	case errors.Is(err, store.ErrDelegationExists):
+		h.writeError(w, http.StatusConflict, "delegation-exists", "Account "+accountID+" already delegates to account "+req.DelegateAccountID)
+		return
+	case err != nil:
+		h.logger.Error("failed to create delegation", "error", err, "account_id", accountID, "delegate_account_id", req.DelegateAccountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to create delegation")
+		return
+	}
+
+	resp := apiv0.DelegationResponse{Kind: "Delegation", Delegation: delegation}
+	if writeDryRun(w, r, http.StatusCreated, resp) {
+		return
+	}
+	writeResponse(w, r, http.StatusCreated, resp)
+}
+
+// ListDelegations handles GET /api/v0/accounts/{id}/delegations
+func (h *AccountsHandler) ListDelegations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	delegations, err := h.authorizer.ListDelegations(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to list delegations", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list delegations")
+		return
+	}
+
+	items := make([]apiv0.DelegationResponse, len(delegations))
+	for i, d := range delegations {
+		items[i] = apiv0.DelegationResponse{Kind: "Delegation", Delegation: d}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.DelegationListResponse{
+		Kind:  "DelegationList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// DeleteDelegation handles DELETE /api/v0/accounts/{id}/delegations/{delegateAccountId}
+func (h *AccountsHandler) DeleteDelegation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	accountID := vars["id"]
+	delegateAccountID := vars["delegateAccountId"]
+	callerARN := middleware.GetCallerARN(ctx)
+
+	h.logger.Info("deleting delegation", "account_id", accountID, "delegate_account_id", delegateAccountID, "caller_arn", callerARN)
+
+	if writeDryRun(w, r, http.StatusNoContent, nil) {
+		return
+	}
+
+	if err := h.authorizer.DeleteDelegation(ctx, accountID, delegateAccountID); err != nil {
+		h.logger.Error("failed to delete delegation", "error", err, "account_id", accountID, "delegate_account_id", delegateAccountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete delegation")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// writeDryRun answers a dry run with 200 and a DryRunResult, reporting
+// whether r was one. Handlers call it once every check that changes nothing
+// has passed, right before the mutation.
+func writeDryRun(w http.ResponseWriter, r *http.Request, status int, preview any) bool {
+	if !middleware.IsDryRun(r.Context()) {
+		return false
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.DryRunResult{
+		Kind:    "DryRun",
+		Status:  status,
+		Preview: preview,
+	})
+	return true
+}
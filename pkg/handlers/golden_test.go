@@ -0,0 +1,280 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/hyperfleet"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+// goldenDir holds the recorded responses, one file per case. Run the tests
+// with UPDATE_GOLDEN=1 to rewrite them after an intentional change, and
+// review the diff: a renamed or removed field breaks the console and CLI.
+const goldenDir = "testdata/golden"
+
+const goldenAccountID = "111111111111"
+
+// goldenTime stands in for every timestamp so recordings are stable
+var goldenTime = time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+
+// generatedAtPattern matches the response metadata stamped with the time a
+// response is written, which checkGolden masks
+var generatedAtPattern = regexp.MustCompile(`"generatedAt":"[^"]*"`)
+
+// goldenCase is a request whose response is recorded in goldenDir as
+// <name>.json
+type goldenCase struct {
+	name    string
+	handler http.HandlerFunc
+	method  string
+	target  string
+	body    string
+	vars    map[string]string
+}
+
+// checkGolden compares the status and body of rec with the recording of name
+func checkGolden(t *testing.T, name string, rec *httptest.ResponseRecorder) {
+	t.Helper()
+
+	raw := generatedAtPattern.ReplaceAll(bytes.TrimSpace(rec.Body.Bytes()), []byte(`"generatedAt":"<generatedAt>"`))
+	var body bytes.Buffer
+	if err := json.Indent(&body, raw, "  ", "  "); err != nil {
+		t.Fatalf("response is not JSON: %v: %s", err, rec.Body.String())
+	}
+	got := []byte(fmt.Sprintf("{\n  \"status\": %d,\n  \"body\": %s\n}\n", rec.Code, body.String()))
+
+	path := filepath.Join(goldenDir, name+".json")
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.MkdirAll(goldenDir, 0o755); err != nil {
+			t.Fatalf("failed to create %s: %v", goldenDir, err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", path, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s; run the test with UPDATE_GOLDEN=1 to record it: %v", path, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("response differs from %s; if the change is intended, run the test with UPDATE_GOLDEN=1\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// goldenAuthzService serves one account with a policy, a group and an
+// attachment
+type goldenAuthzService struct {
+	authz.Service
+}
+
+func (s *goldenAuthzService) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	if accountID != goldenAccountID {
+		return nil, nil
+	}
+	return &store.Account{
+		AccountID:     goldenAccountID,
+		PolicyStoreID: "ps-1",
+		CreatedAt:     goldenTime.Format(time.RFC3339),
+		CreatedBy:     "arn:aws:iam::000000000000:role/platform",
+		RequiredTags:  []string{"cost-center"},
+	}, nil
+}
+
+func (s *goldenAuthzService) ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error) {
+	return []*store.Policy{{
+		AccountID:   accountID,
+		PolicyID:    "policy-1",
+		Name:        "viewers",
+		Description: "List and describe clusters",
+		CedarPolicy: `permit(principal in ?principal, action == ROSA::Action::"ListClusters", resource);`,
+		Tags:        map[string]string{"team": "platform"},
+		CreatedAt:   goldenTime.Format(time.RFC3339),
+	}}, nil
+}
+
+func (s *goldenAuthzService) GetPolicy(ctx context.Context, accountID, policyID string) (*store.Policy, error) {
+	return nil, nil
+}
+
+func (s *goldenAuthzService) ListGroups(ctx context.Context, accountID string) ([]*store.Group, error) {
+	return []*store.Group{{
+		AccountID:   accountID,
+		GroupID:     "group-1",
+		Name:        "developers",
+		Description: "Application developers",
+		CreatedAt:   goldenTime.Format(time.RFC3339),
+	}}, nil
+}
+
+func (s *goldenAuthzService) ListAttachments(ctx context.Context, accountID string, filter authz.AttachmentFilter) ([]*authz.Attachment, error) {
+	return []*authz.Attachment{{
+		AttachmentID: "attachment-1",
+		PolicyID:     "policy-1",
+		TargetType:   authz.TargetTypeGroup,
+		TargetID:     "group-1",
+		Name:         "developers-view",
+		CreatedAt:    goldenTime.Format(time.RFC3339),
+	}}, nil
+}
+
+func (s *goldenAuthzService) ListAdmins(ctx context.Context, accountID string) ([]string, error) {
+	return []string{"arn:aws:iam::" + accountID + ":role/admin"}, nil
+}
+
+// newGoldenHyperfleet serves cluster-1 of the golden account, and a 404 for
+// any other cluster
+func newGoldenHyperfleet(t *testing.T) *hyperfleet.Client {
+	cluster := hyperfleet.HFCluster{
+		ID:         "cluster-1",
+		Name:       "prod",
+		Labels:     map[string]string{"target_project_id": goldenAccountID},
+		Spec:       map[string]interface{}{"region": "us-east-1"},
+		Generation: 1,
+		CreatedBy:  "arn:aws:iam::" + goldenAccountID + ":user/test",
+		CreatedAt:  goldenTime,
+		UpdatedAt:  goldenTime,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/hyperfleet/v1/clusters":
+			_ = json.NewEncoder(w).Encode(hyperfleet.HFClusterList{Items: []hyperfleet.HFCluster{cluster}, TotalCount: 1, Page: 1, PageSize: 50})
+		case "/api/hyperfleet/v1/clusters/cluster-1":
+			_ = json.NewEncoder(w).Encode(cluster)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"code": "404", "message": "cluster not found"})
+		}
+	}))
+	t.Cleanup(server.Close)
+	return hyperfleet.NewClient(config.HyperfleetConfig{BaseURL: server.URL, Timeout: 5 * time.Second}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// TestResponses_Golden records the success and error responses of the
+// handlers, so a change to their JSON shows up in review as a diff of
+// testdata/golden
+func TestResponses_Golden(t *testing.T) {
+	t.Setenv("TARGET_GROUP_ARN", "arn:aws:elasticloadbalancing:us-east-1:123456789012:targetgroup/rosa-api/abc123")
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	accounts := NewAccountsHandler(&goldenAuthzService{}, logger)
+	authzHandler := NewAuthzHandler(nil, &goldenAuthzService{}, logger)
+	clusters := NewClusterHandler(newGoldenHyperfleet(t), nil, logger)
+	health := NewHealthHandler()
+	health.SetReady(true)
+	mgmtClusters, _, _ := newMgmtClusterTestHandler()
+
+	bundles := NewResourceBundleHandler(&mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			return &maestro.ResourceBundleList{
+				Kind:  "ResourceBundleList",
+				Page:  page,
+				Size:  1,
+				Total: 1,
+				Items: []maestro.ResourceBundle{{
+					ID:           "rb-1",
+					Kind:         "ResourceBundle",
+					Href:         "/api/maestro/v1/resource-bundles/rb-1",
+					Name:         "app",
+					ConsumerName: "mc-a",
+					Version:      1,
+					CreatedAt:    &goldenTime,
+					UpdatedAt:    &goldenTime,
+					Metadata:     map[string]interface{}{"name": "app"},
+				}},
+			}, nil
+		},
+	}, logger)
+
+	// Submissions and the listing get their own stores, so no case sees
+	// what another recorded
+	works := NewWorkHandler(&mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			created := manifestWork.DeepCopy()
+			created.UID = "work-uid-1"
+			created.CreationTimestamp = metav1.NewTime(goldenTime)
+			return created, nil
+		},
+	}, WorkConfig{MetadataStore: &mockWorkMetadataStore{}}, logger)
+	workList := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{MetadataStore: &mockWorkMetadataStore{records: []*workmeta.Record{{
+		ClusterID:   "mc-a",
+		WorkName:    "app",
+		WorkID:      "work-uid-0",
+		AccountID:   goldenAccountID,
+		CallerARN:   "arn:aws:iam::" + goldenAccountID + ":user/test",
+		CreatedAt:   goldenTime.Format(time.RFC3339),
+		ContentHash: "ca1f35d8b5d023a6a37dc8eed9f464b83721e38f9f7aed0b147d884a635fb74c",
+	}}}}, logger)
+	worksWithoutStore := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{}, logger)
+
+	const workBody = `{"cluster_id":"mc-a","data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"web"},"spec":{"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"web","namespace":"default"},"data":{"key":"value"}}]}}}}`
+
+	cases := []goldenCase{
+		{name: "accounts_get", handler: accounts.Get, method: http.MethodGet, target: "/api/v0/accounts/" + goldenAccountID, vars: map[string]string{"id": goldenAccountID}},
+		{name: "accounts_get_not_found", handler: accounts.Get, method: http.MethodGet, target: "/api/v0/accounts/999999999999", vars: map[string]string{"id": "999999999999"}},
+		{name: "authz_admins_list", handler: authzHandler.ListAdmins, method: http.MethodGet, target: "/api/v0/authz/admins"},
+		{name: "authz_attachments_list", handler: authzHandler.ListAttachments, method: http.MethodGet, target: "/api/v0/authz/attachments"},
+		{name: "authz_attachments_list_invalid_target_type", handler: authzHandler.ListAttachments, method: http.MethodGet, target: "/api/v0/authz/attachments?targetType=robot"},
+		{name: "authz_groups_list", handler: authzHandler.ListGroups, method: http.MethodGet, target: "/api/v0/authz/groups"},
+		{name: "authz_policies_format", handler: authzHandler.FormatPolicy, method: http.MethodPost, target: "/api/v0/authz/policies/format", body: `{"policy":"permit(principal, action==ROSA::Action::\"ListClusters\", resource);"}`},
+		{name: "authz_policies_format_invalid", handler: authzHandler.FormatPolicy, method: http.MethodPost, target: "/api/v0/authz/policies/format", body: `{"policy":"allow everything"}`},
+		{name: "authz_policies_get_not_found", handler: authzHandler.GetPolicy, method: http.MethodGet, target: "/api/v0/authz/policies/policy-2", vars: map[string]string{"id": "policy-2"}},
+		{name: "authz_policies_list", handler: authzHandler.ListPolicies, method: http.MethodGet, target: "/api/v0/authz/policies"},
+		{name: "clusters_get", handler: clusters.Get, method: http.MethodGet, target: "/api/v0/clusters/cluster-1", vars: map[string]string{"id": "cluster-1"}},
+		{name: "clusters_get_not_found", handler: clusters.Get, method: http.MethodGet, target: "/api/v0/clusters/cluster-2", vars: map[string]string{"id": "cluster-2"}},
+		{name: "clusters_list", handler: clusters.List, method: http.MethodGet, target: "/api/v0/clusters"},
+		{name: "info", handler: NewInfoHandler().Info, method: http.MethodGet, target: "/api/v0/info"},
+		{name: "management_clusters_get", handler: mgmtClusters.Get, method: http.MethodGet, target: "/api/v0/management_clusters/mc-a", vars: map[string]string{"id": "mc-a"}},
+		{name: "management_clusters_list", handler: mgmtClusters.List, method: http.MethodGet, target: "/api/v0/management_clusters"},
+		{name: "ready", handler: health.Readiness, method: http.MethodGet, target: "/api/v0/ready"},
+		{name: "resource_bundles_list", handler: bundles.List, method: http.MethodGet, target: "/api/v0/resource_bundles"},
+		{name: "resource_bundles_list_invalid_search", handler: bundles.List, method: http.MethodGet, target: "/api/v0/resource_bundles?search=payload%3D%27x%27"},
+		{name: "work_create", handler: works.Create, method: http.MethodPost, target: "/api/v0/work", body: workBody},
+		{name: "work_create_missing_cluster_id", handler: works.Create, method: http.MethodPost, target: "/api/v0/work", body: `{"data":{}}`},
+		{name: "work_list", handler: workList.List, method: http.MethodGet, target: "/api/v0/work"},
+		{name: "work_list_unavailable", handler: worksWithoutStore.List, method: http.MethodGet, target: "/api/v0/work"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var body io.Reader
+			if tc.body != "" {
+				body = strings.NewReader(tc.body)
+			}
+			req := httptest.NewRequest(tc.method, tc.target, body)
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req = withAccount(req, goldenAccountID, false)
+			if tc.vars != nil {
+				req = mux.SetURLVars(req, tc.vars)
+			}
+
+			rec := httptest.NewRecorder()
+			tc.handler(rec, req)
+			checkGolden(t, tc.name, rec)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// GuardrailsHandler handles platform guardrail management endpoints
+type GuardrailsHandler struct {
+	authorizer authz.Service
+	logger     *slog.Logger
+}
+
+// NewGuardrailsHandler creates a new GuardrailsHandler
+func NewGuardrailsHandler(authorizer authz.Service, logger *slog.Logger) *GuardrailsHandler {
+	return &GuardrailsHandler{
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+// Create handles POST /api/v0/admin/guardrails
+func (h *GuardrailsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var req apiv0.CreateGuardrailRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if strings.TrimSpace(req.CedarPolicy) == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-cedar-policy", "cedarPolicy is required")
+		return
+	}
+
+	h.logger.Info("creating guardrail", "caller_arn", middleware.GetCallerARN(ctx))
+
+	if middleware.IsDryRun(ctx) {
+		if _, ok := h.listGuardrails(w, ctx); !ok {
+			return
+		}
+		if err := h.authorizer.ValidatePolicy(req.CedarPolicy); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-policy", "Failed to create guardrail: "+err.Error())
+			return
+		}
+		writeDryRun(w, r, http.StatusCreated, apiv0.GuardrailResponse{
+			Kind:         "Guardrail",
+			StaticPolicy: &store.StaticPolicy{Description: req.Description, CedarPolicy: req.CedarPolicy},
+		})
+		return
+	}
+
+	policy, err := h.authorizer.CreateGuardrail(ctx, req.Description, req.CedarPolicy)
+	if err != nil {
+		if errors.Is(err, authz.ErrGuardrailsDisabled) {
+			h.writeError(w, http.StatusNotFound, "guardrails-disabled", "Platform guardrails are not enabled")
+			return
+		}
+		h.logger.Error("failed to create guardrail", "error", err)
+		h.writeError(w, http.StatusBadRequest, "invalid-policy", "Failed to create guardrail: "+err.Error())
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, apiv0.GuardrailResponse{Kind: "Guardrail", StaticPolicy: policy})
+}
+
+// List handles GET /api/v0/admin/guardrails
+func (h *GuardrailsHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	policies, ok := h.listGuardrails(w, ctx)
+	if !ok {
+		return
+	}
+
+	items := make([]apiv0.GuardrailResponse, len(policies))
+	for i, p := range policies {
+		items[i] = apiv0.GuardrailResponse{Kind: "Guardrail", StaticPolicy: p}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.GuardrailListResponse{
+		Kind:  "GuardrailList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// Delete handles DELETE /api/v0/admin/guardrails/{id}
+func (h *GuardrailsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	policyID := mux.Vars(r)["id"]
+
+	h.logger.Info("deleting guardrail", "policy_id", policyID, "caller_arn", middleware.GetCallerARN(ctx))
+
+	if middleware.IsDryRun(ctx) {
+		policies, ok := h.listGuardrails(w, ctx)
+		if !ok {
+			return
+		}
+		for _, p := range policies {
+			if p.PolicyID == policyID {
+				writeDryRun(w, r, http.StatusNoContent, apiv0.GuardrailResponse{Kind: "Guardrail", StaticPolicy: p})
+				return
+			}
+		}
+		h.writeError(w, http.StatusNotFound, "not-found", "Guardrail not found")
+		return
+	}
+
+	if err := h.authorizer.DeleteGuardrail(ctx, policyID); err != nil {
+		if errors.Is(err, authz.ErrGuardrailsDisabled) {
+			h.writeError(w, http.StatusNotFound, "guardrails-disabled", "Platform guardrails are not enabled")
+			return
+		}
+		h.logger.Error("failed to delete guardrail", "error", err, "policy_id", policyID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete guardrail")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listGuardrails returns the guardrails, having answered 404 if guardrails
+// are not enabled
+func (h *GuardrailsHandler) listGuardrails(w http.ResponseWriter, ctx context.Context) ([]*store.StaticPolicy, bool) {
+	policies, err := h.authorizer.ListGuardrails(ctx)
+	if err != nil {
+		if errors.Is(err, authz.ErrGuardrailsDisabled) {
+			h.writeError(w, http.StatusNotFound, "guardrails-disabled", "Platform guardrails are not enabled")
+			return nil, false
+		}
+		h.logger.Error("failed to list guardrails", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list guardrails")
+		return nil, false
+	}
+	return policies, true
+}
+
+func (h *GuardrailsHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
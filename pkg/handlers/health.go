@@ -3,12 +3,27 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"sync"
 	"sync/atomic"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
 )
 
-// HealthHandler handles health check endpoints
+// ComponentState is the last reported health of a server component
+type ComponentState = apiv0.ComponentState
+
+// HealthHandler handles health check endpoints. Liveness reports that the
+// process is serving, startup that initialization has completed, and
+// readiness that the server is accepting traffic and no critical component is
+// unhealthy.
 type HealthHandler struct {
-	ready *atomic.Bool
+	ready   *atomic.Bool
+	started *atomic.Bool
+
+	mu         sync.RWMutex
+	components map[string]ComponentState
 }
 
 // NewHealthHandler creates a new HealthHandler
@@ -16,7 +31,9 @@ func NewHealthHandler() *HealthHandler {
 	ready := &atomic.Bool{}
 	ready.Store(true)
 	return &HealthHandler{
-		ready: ready,
+		ready:      ready,
+		started:    &atomic.Bool{},
+		components: make(map[string]ComponentState),
 	}
 }
 
@@ -25,21 +42,82 @@ func (h *HealthHandler) SetReady(ready bool) {
 	h.ready.Store(ready)
 }
 
+// MarkStarted records that initialization has completed
+func (h *HealthHandler) MarkStarted() {
+	h.started.Store(true)
+}
+
+// SetComponent records the health of a component; a nil err marks it healthy.
+// Since only moves when the component changes between healthy and unhealthy.
+func (h *HealthHandler) SetComponent(name string, critical bool, err error) {
+	state := ComponentState{Healthy: err == nil, Critical: critical, Since: clock.Stamp(time.Now())}
+	if err != nil {
+		state.Reason = err.Error()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if prev, ok := h.components[name]; ok && prev.Healthy == state.Healthy {
+		state.Since = prev.Since
+	}
+	h.components[name] = state
+}
+
+// Components returns a snapshot of the component states
+func (h *HealthHandler) Components() map[string]ComponentState {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	out := make(map[string]ComponentState, len(h.components))
+	for name, state := range h.components {
+		out[name] = state
+	}
+	return out
+}
+
+// Component returns the last reported state of the component name
+func (h *HealthHandler) Component(name string) (ComponentState, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	state, ok := h.components[name]
+	return state, ok
+}
+
 // Liveness handles GET /live
 func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	_ = json.NewEncoder(w).Encode(apiv0.Status{Status: "ok"})
+}
+
+// Startup handles GET /startupz
+func (h *HealthHandler) Startup(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if !h.started.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(apiv0.Status{Status: "starting"})
+		return
+	}
+
+	_ = json.NewEncoder(w).Encode(apiv0.Status{Status: "ok"})
 }
 
 // Readiness handles GET /ready
 func (h *HealthHandler) Readiness(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
-	if !h.ready.Load() {
+	components := h.Components()
+	ready := h.ready.Load()
+	for _, state := range components {
+		if state.Critical && !state.Healthy {
+			ready = false
+		}
+	}
+
+	resp := apiv0.Status{Status: "ok", Components: components}
+	if !ready {
 		w.WriteHeader(http.StatusServiceUnavailable)
-		_ = json.NewEncoder(w).Encode(map[string]string{"status": "unavailable"})
-		return
+		resp.Status = "unavailable"
 	}
 
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	_ = json.NewEncoder(w).Encode(resp)
 }
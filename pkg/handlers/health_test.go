@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHealthHandler_Startup(t *testing.T) {
+	h := NewHealthHandler()
+
+	w := httptest.NewRecorder()
+	h.Startup(w, httptest.NewRequest(http.MethodGet, "/startupz", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 before startup completes, got %d", w.Code)
+	}
+
+	h.MarkStarted()
+
+	w = httptest.NewRecorder()
+	h.Startup(w, httptest.NewRequest(http.MethodGet, "/startupz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected 200 after startup completes, got %d", w.Code)
+	}
+}
+
+func TestHealthHandler_ReadinessComponents(t *testing.T) {
+	tests := []struct {
+		name           string
+		critical       bool
+		err            error
+		expectedStatus int
+	}{
+		{name: "healthy critical component", critical: true, expectedStatus: http.StatusOK},
+		{name: "unhealthy critical component", critical: true, err: errors.New("unreachable"), expectedStatus: http.StatusServiceUnavailable},
+		{name: "unhealthy non-critical component", err: errors.New("unreachable"), expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewHealthHandler()
+			h.SetComponent("dep", tt.critical, tt.err)
+
+			w := httptest.NewRecorder()
+			h.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+
+			var resp struct {
+				Components map[string]ComponentState `json:"components"`
+			}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			state, ok := resp.Components["dep"]
+			if !ok {
+				t.Fatal("expected component in readiness response")
+			}
+			if state.Healthy != (tt.err == nil) {
+				t.Errorf("expected healthy=%v, got %v", tt.err == nil, state.Healthy)
+			}
+		})
+	}
+}
+
+func TestHealthHandler_LivenessIgnoresComponents(t *testing.T) {
+	h := NewHealthHandler()
+	h.SetComponent("dep", true, errors.New("unreachable"))
+
+	w := httptest.NewRecorder()
+	h.Liveness(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected liveness 200 with an unhealthy component, got %d", w.Code)
+	}
+}
+
+func TestHealthHandler_SetComponentKeepsSince(t *testing.T) {
+	h := NewHealthHandler()
+	h.SetComponent("dep", true, errors.New("first"))
+	first := h.Components()["dep"].Since
+
+	h.SetComponent("dep", true, errors.New("second"))
+	state := h.Components()["dep"]
+	if !state.Since.Equal(first) {
+		t.Errorf("expected since to stay at %v while unhealthy, got %v", first, state.Since)
+	}
+	if state.Reason != "second" {
+		t.Errorf("expected latest reason, got %q", state.Reason)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"net/http"
 	"os"
 	"strings"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 )
 
 // InfoHandler handles the info endpoint
@@ -27,16 +29,12 @@ func (h *InfoHandler) Info(w http.ResponseWriter, r *http.Request) {
 	parts := strings.SplitN(tgARN, ":", 6)
 	if len(parts) < 6 || parts[4] == "" {
 		w.WriteHeader(http.StatusServiceUnavailable)
-		_ = json.NewEncoder(w).Encode(map[string]string{
-			"kind":   "Error",
-			"code":   "regional-account-unavailable",
-			"reason": "regional account ID is not configured",
-		})
+		_ = json.NewEncoder(w).Encode(apiv0.NewError("regional-account-unavailable", "regional account ID is not configured"))
 		return
 	}
 
 	accountID := parts[4]
 	arn := fmt.Sprintf("arn:aws:iam::%s:role/LambdaExecutor", accountID)
 
-	_ = json.NewEncoder(w).Encode(map[string]string{"arn": arn})
+	_ = json.NewEncoder(w).Encode(apiv0.LambdaExecutor{ARN: arn})
 }
@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/leader"
+)
+
+// LeadersHandler handles the leader election status endpoint
+type LeadersHandler struct {
+	elector *leader.Elector
+	logger  *slog.Logger
+}
+
+// NewLeadersHandler creates a new LeadersHandler. A nil elector reports no
+// leaders, since every replica runs every worker.
+func NewLeadersHandler(elector *leader.Elector, logger *slog.Logger) *LeadersHandler {
+	return &LeadersHandler{elector: elector, logger: logger}
+}
+
+// List handles GET /api/v0/status/leaders
+// Returns which replica holds the lease for each background worker.
+func (h *LeadersHandler) List(w http.ResponseWriter, r *http.Request) {
+	leaders, err := h.elector.Leaders(r.Context())
+	if err != nil {
+		h.logger.Error("failed to list leadership leases", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "leases-error", "Failed to list leadership leases")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.LeaderList{
+		Kind:     "LeaderList",
+		Identity: h.elector.Identity(),
+		Items:    emptyIfNil(leaders),
+		Total:    len(leaders),
+	})
+}
+
+func (h *LeadersHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// PageLimits bounds the page size accepted by a list endpoint
+type PageLimits struct {
+	Default int
+	Max     int
+}
+
+// Default page limits, used until a handler is configured otherwise
+var (
+	DefaultTenantPageLimits        = PageLimits{Default: 50, Max: 100}
+	DefaultPlatformPageLimits      = PageLimits{Default: 100, Max: 100}
+	DefaultTrustedActionPageLimits = PageLimits{Default: 20, Max: 100}
+	DefaultAuditPageLimits         = PageLimits{Default: 50, Max: 200}
+)
+
+// pageSize reads the page size from the named query parameter. A missing or
+// non-positive value yields the default. A value above the maximum is an
+// error rather than being clamped, so callers are never silently given a
+// smaller page than they asked for.
+func pageSize(r *http.Request, param string, limits PageLimits) (int, error) {
+	v := r.URL.Query().Get(param)
+	if v == "" {
+		return limits.Default, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return limits.Default, nil
+	}
+	if n > limits.Max {
+		return 0, fmt.Errorf("%s must not exceed %d", param, limits.Max)
+	}
+	return n, nil
+}
+
+// setPageLinks sets a Link header with rel="next" and rel="prev" links to
+// the request URL with the given query parameters replaced. A nil set omits
+// that link, and the header is left unset when both are nil.
+func setPageLinks(w http.ResponseWriter, r *http.Request, next, prev url.Values) {
+	var links []string
+	for _, l := range []struct {
+		rel    string
+		params url.Values
+	}{{"next", next}, {"prev", prev}} {
+		if l.params == nil {
+			continue
+		}
+		query := r.URL.Query()
+		for k, v := range l.params {
+			query[k] = v
+		}
+		u := url.URL{Path: r.URL.Path, RawQuery: query.Encode()}
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, href(r, u.String()), l.rel))
+	}
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// setOffsetLinks sets the page links of a list paged with offset and limit
+func setOffsetLinks(w http.ResponseWriter, r *http.Request, offset, limit, total int) {
+	var next, prev url.Values
+	if offset+limit < total {
+		next = url.Values{"offset": {strconv.Itoa(offset + limit)}}
+	}
+	if offset > 0 {
+		prev = url.Values{"offset": {strconv.Itoa(max(offset-limit, 0))}}
+	}
+	setPageLinks(w, r, next, prev)
+}
+
+// setNumberedLinks sets the page links of a list paged with 1-indexed page
+// numbers of size items
+func setNumberedLinks(w http.ResponseWriter, r *http.Request, page, size, total int) {
+	var next, prev url.Values
+	if page*size < total {
+		next = url.Values{"page": {strconv.Itoa(page + 1)}}
+	}
+	if page > 1 {
+		prev = url.Values{"page": {strconv.Itoa(page - 1)}}
+	}
+	setPageLinks(w, r, next, prev)
+}
+
+// pageEnvelope is the fields of a Maestro page other than its items, for
+// writeListResponse
+type pageEnvelope struct {
+	Kind  string `json:"kind"`
+	Page  int    `json:"page"`
+	Size  int    `json:"size"`
+	Total int    `json:"total"`
+}
+
+// emptyIfNil returns items, or an empty slice if items is nil, so that list
+// responses always encode items as a JSON array rather than null
+func emptyIfNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/hyperfleet"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/policybackup"
+	"github.com/openshift/rosa-regional-platform-api/pkg/zoa"
+)
+
+// emptyAuthzService returns nil from every list method
+type emptyAuthzService struct {
+	authz.Service
+}
+
+func (s *emptyAuthzService) ListAccounts(ctx context.Context) ([]*store.Account, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListAccountsPage(ctx context.Context, limit int, pageToken string, filter store.AccountFilter) (*store.AccountPage, error) {
+	return &store.AccountPage{}, nil
+}
+
+func (s *emptyAuthzService) ListAdmins(ctx context.Context, accountID string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListGroups(ctx context.Context, accountID string) ([]*store.Group, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) GetGroup(ctx context.Context, accountID, groupID string) (*store.Group, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListGroupMembers(ctx context.Context, accountID, groupID string) ([]string, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListAttachments(ctx context.Context, accountID string, filter authz.AttachmentFilter) ([]*authz.Attachment, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListPendingChanges(ctx context.Context, accountID string) ([]*store.PendingChange, error) {
+	return nil, nil
+}
+
+func (s *emptyAuthzService) ListChangeRequests(ctx context.Context, accountID, status string) ([]*store.ChangeRequest, error) {
+	return nil, nil
+}
+
+// emptyPolicyBackups has no backups for any account
+type emptyPolicyBackups struct{}
+
+func (emptyPolicyBackups) List(ctx context.Context, accountID string) ([]policybackup.Backup, error) {
+	return nil, nil
+}
+
+func (emptyPolicyBackups) Get(ctx context.Context, accountID, key string) (*authz.PolicyStoreExport, error) {
+	return nil, nil
+}
+
+// TestListHandlers_EmptyContract checks that every list endpoint encodes an
+// empty result as "items": [] with a "total" of 0, never null or omitted
+func TestListHandlers_EmptyContract(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	hfServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"items":null,"total_count":0,"page":1,"page_size":50}`))
+	}))
+	defer hfServer.Close()
+	hfClient := hyperfleet.NewClient(config.HyperfleetConfig{BaseURL: hfServer.URL, Timeout: 5 * time.Second}, logger)
+
+	emptyMaestro := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page}, nil
+		},
+	}
+	emptyConsumers := &mockConsumerMaestroClient{consumers: map[string]*maestro.Consumer{}}
+
+	zoaHandler := NewZoaHandler(&mockExecutionStore{}, testTemplateRegistry(t), &zoaMockMaestroClient{}, &mockS3Client{}, ZoaConfig{
+		BucketName: "test-bucket",
+		JobConfig:  testJobConfig(),
+		AuditStore: &mockAuditStore{},
+	}, logger)
+	authzHandler := NewAuthzHandler(nil, &emptyAuthzService{}, logger)
+	accountsHandler := NewAccountsHandler(&emptyAuthzService{}, logger).WithPolicyBackups(emptyPolicyBackups{})
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{name: "clusters", handler: NewClusterHandler(hfClient, nil, logger).List},
+		{name: "nodepools", handler: NewNodePoolHandler(maestro.NewClient(config.MaestroConfig{}, logger), logger).List},
+		{name: "resource bundles", handler: NewResourceBundleHandler(emptyMaestro, logger).List},
+		{name: "management clusters", handler: NewManagementClusterHandler(emptyConsumers, nil, logger).List},
+		{name: "trusted action catalog", handler: NewZoaHandler(&mockExecutionStore{}, zoa.NewTemplateRegistry(logger), &zoaMockMaestroClient{}, &mockS3Client{}, ZoaConfig{JobConfig: testJobConfig()}, logger).Catalog},
+		{name: "trusted action runs", handler: zoaHandler.List},
+		{name: "trusted action audit", handler: zoaHandler.AuditList},
+		{name: "policies", handler: authzHandler.ListPolicies},
+		{name: "groups", handler: authzHandler.ListGroups},
+		{name: "group members", handler: authzHandler.ListGroupMembers},
+		{name: "attachments", handler: authzHandler.ListAttachments},
+		{name: "admins", handler: authzHandler.ListAdmins},
+		{name: "pending changes", handler: authzHandler.ListPendingChanges},
+		{name: "change requests", handler: NewChangeRequestsHandler(&emptyAuthzService{}, nil, logger).List},
+		{name: "accounts", handler: accountsHandler.List},
+		{name: "policy backups", handler: accountsHandler.ListPolicyBackups},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/list", nil)
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012")
+			ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, "arn:aws:iam::123456789012:user/test")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			tt.handler(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got := string(body["items"]); got != "[]" {
+				t.Errorf("expected items=[], got %q", got)
+			}
+			if got := string(body["total"]); got != "0" {
+				t.Errorf("expected total=0, got %q", got)
+			}
+		})
+	}
+}
+
+func TestPageSize(t *testing.T) {
+	limits := PageLimits{Default: 20, Max: 100}
+
+	tests := []struct {
+		name      string
+		query     string
+		expect    int
+		expectErr bool
+	}{
+		{name: "missing uses default", query: "", expect: 20},
+		{name: "explicit value", query: "?limit=75", expect: 75},
+		{name: "at maximum", query: "?limit=100", expect: 100},
+		{name: "not a number uses default", query: "?limit=abc", expect: 20},
+		{name: "non-positive uses default", query: "?limit=0", expect: 20},
+		{name: "above maximum is rejected", query: "?limit=101", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/list"+tt.query, nil)
+			got, err := pageSize(req, "limit", limits)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected error, got size %d", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expect {
+				t.Errorf("expected size %d, got %d", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		set    func(w http.ResponseWriter, r *http.Request)
+		expect string
+	}{
+		{
+			name:   "offset first page",
+			query:  "?limit=10&status=ready",
+			set:    func(w http.ResponseWriter, r *http.Request) { setOffsetLinks(w, r, 0, 10, 25) },
+			expect: `</list?limit=10&offset=10&status=ready>; rel="next"`,
+		},
+		{
+			name:  "offset middle page",
+			query: "?limit=10&offset=5",
+			set:   func(w http.ResponseWriter, r *http.Request) { setOffsetLinks(w, r, 5, 10, 25) },
+			expect: `</list?limit=10&offset=15>; rel="next", ` +
+				`</list?limit=10&offset=0>; rel="prev"`,
+		},
+		{
+			name:   "offset last page",
+			query:  "?limit=10&offset=20",
+			set:    func(w http.ResponseWriter, r *http.Request) { setOffsetLinks(w, r, 20, 10, 25) },
+			expect: `</list?limit=10&offset=10>; rel="prev"`,
+		},
+		{
+			name:   "single page has no links",
+			set:    func(w http.ResponseWriter, r *http.Request) { setNumberedLinks(w, r, 1, 100, 3) },
+			expect: "",
+		},
+		{
+			name:  "numbered middle page",
+			query: "?page=2&size=10",
+			set:   func(w http.ResponseWriter, r *http.Request) { setNumberedLinks(w, r, 2, 10, 30) },
+			expect: `</list?page=3&size=10>; rel="next", ` +
+				`</list?page=1&size=10>; rel="prev"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/list"+tt.query, nil)
+			w := httptest.NewRecorder()
+			tt.set(w, req)
+			if got := w.Header().Get("Link"); got != tt.expect {
+				t.Errorf("expected Link %q, got %q", tt.expect, got)
+			}
+		})
+	}
+}
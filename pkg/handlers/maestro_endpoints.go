@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestrofailover"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// MaestroEndpoints reports and switches the Maestro endpoint calls go to
+type MaestroEndpoints interface {
+	Status() ([]maestrofailover.EndpointStatus, *maestrofailover.Switch)
+	SwitchTo(name, reason string, force bool) error
+}
+
+// MaestroEndpointsHandler handles the admin endpoints for switching between
+// the primary and secondary Maestro
+type MaestroEndpointsHandler struct {
+	endpoints MaestroEndpoints
+	logger    *slog.Logger
+}
+
+// NewMaestroEndpointsHandler creates a new MaestroEndpointsHandler
+func NewMaestroEndpointsHandler(endpoints MaestroEndpoints, logger *slog.Logger) *MaestroEndpointsHandler {
+	return &MaestroEndpointsHandler{
+		endpoints: endpoints,
+		logger:    logger,
+	}
+}
+
+// List handles GET /api/v0/admin/maestro/endpoints
+func (h *MaestroEndpointsHandler) List(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, h.list())
+}
+
+func (h *MaestroEndpointsHandler) list() apiv0.MaestroEndpointListResponse {
+	items, last := h.endpoints.Status()
+	return apiv0.MaestroEndpointListResponse{
+		Kind:       "MaestroEndpointList",
+		Items:      items,
+		LastSwitch: last,
+	}
+}
+
+// Activate handles POST /api/v0/admin/maestro/endpoints/{name}/activate,
+// sending this replica's Maestro calls to the endpoint. An endpoint whose
+// last health check failed is refused unless force=true.
+func (h *MaestroEndpointsHandler) Activate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := mux.Vars(r)["name"]
+	force := r.URL.Query().Get("force") == "true"
+
+	if middleware.IsDryRun(ctx) {
+		list := h.list()
+		found := false
+		for i := range list.Items {
+			if list.Items[i].Name == name {
+				found = true
+				if !list.Items[i].Healthy && !force {
+					h.writeError(w, http.StatusConflict, "endpoint-unhealthy", "Maestro endpoint "+name+" failed its last health check; use force=true to switch anyway")
+					return
+				}
+			}
+			list.Items[i].Active = list.Items[i].Name == name
+		}
+		if !found {
+			h.writeError(w, http.StatusNotFound, "not-found", "Maestro endpoint not found")
+			return
+		}
+		writeDryRun(w, r, http.StatusOK, list)
+		return
+	}
+
+	err := h.endpoints.SwitchTo(name, maestrofailover.ReasonManual, force)
+	switch {
+	case errors.Is(err, maestro.ErrUnknownEndpoint):
+		h.writeError(w, http.StatusNotFound, "not-found", "Maestro endpoint not found")
+		return
+	case errors.Is(err, maestrofailover.ErrUnhealthy):
+		h.writeError(w, http.StatusConflict, "endpoint-unhealthy", err.Error()+"; use force=true to switch anyway")
+		return
+	case err != nil:
+		h.logger.Error("failed to switch maestro endpoint", "error", err, "endpoint", name)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to switch Maestro endpoint")
+		return
+	}
+
+	h.logger.Info("activated maestro endpoint",
+		"endpoint", name,
+		"force", force,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	writeResponse(w, r, http.StatusOK, h.list())
+}
+
+func (h *MaestroEndpointsHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestrofailover"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+type fakeMaestroEndpoints struct {
+	items []maestrofailover.EndpointStatus
+	last  *maestrofailover.Switch
+}
+
+func (f *fakeMaestroEndpoints) Status() ([]maestrofailover.EndpointStatus, *maestrofailover.Switch) {
+	return append([]maestrofailover.EndpointStatus(nil), f.items...), f.last
+}
+
+func (f *fakeMaestroEndpoints) SwitchTo(name, reason string, force bool) error {
+	target := -1
+	for i, item := range f.items {
+		if item.Name == name {
+			target = i
+		}
+	}
+	if target < 0 {
+		return fmt.Errorf("%w %q", maestro.ErrUnknownEndpoint, name)
+	}
+	if !f.items[target].Healthy && !force {
+		return fmt.Errorf("%w: connection refused", maestrofailover.ErrUnhealthy)
+	}
+	for i := range f.items {
+		f.items[i].Active = i == target
+	}
+	f.last = &maestrofailover.Switch{From: "primary", To: name, Reason: reason}
+	return nil
+}
+
+func TestMaestroEndpointsHandler_Activate(t *testing.T) {
+	tests := []struct {
+		name           string
+		endpoint       string
+		query          string
+		dryRun         bool
+		secondaryUp    bool
+		expectedStatus int
+		expectedActive string
+	}{
+		{name: "healthy endpoint", endpoint: "secondary", secondaryUp: true, expectedStatus: http.StatusOK, expectedActive: "secondary"},
+		{name: "unhealthy endpoint", endpoint: "secondary", expectedStatus: http.StatusConflict, expectedActive: "primary"},
+		{name: "forced to unhealthy endpoint", endpoint: "secondary", query: "?force=true", expectedStatus: http.StatusOK, expectedActive: "secondary"},
+		{name: "unknown endpoint", endpoint: "tertiary", expectedStatus: http.StatusNotFound, expectedActive: "primary"},
+		{name: "dry run", endpoint: "secondary", secondaryUp: true, dryRun: true, expectedStatus: http.StatusOK, expectedActive: "primary"},
+		{name: "dry run to unhealthy endpoint", endpoint: "secondary", dryRun: true, expectedStatus: http.StatusConflict, expectedActive: "primary"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoints := &fakeMaestroEndpoints{items: []maestrofailover.EndpointStatus{
+				{Name: "primary", Active: true, Healthy: true},
+				{Name: "secondary", Healthy: tt.secondaryUp},
+			}}
+			handler := NewMaestroEndpointsHandler(endpoints, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/admin/maestro/endpoints/"+tt.endpoint+"/activate"+tt.query, nil)
+			req = mux.SetURLVars(req, map[string]string{"name": tt.endpoint})
+			if tt.dryRun {
+				req = req.WithContext(middleware.WithDryRun(req.Context()))
+			}
+			w := httptest.NewRecorder()
+			handler.Activate(w, req)
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedStatus, w.Code, w.Body.String())
+			}
+			items, _ := endpoints.Status()
+			for _, item := range items {
+				if item.Active != (item.Name == tt.expectedActive) {
+					t.Errorf("Expected %s to be active, got %+v", tt.expectedActive, items)
+				}
+			}
+		})
+	}
+}
+
+func TestMaestroEndpointsHandler_List(t *testing.T) {
+	endpoints := &fakeMaestroEndpoints{
+		items: []maestrofailover.EndpointStatus{
+			{Name: "primary", Healthy: false, ConsecutiveFailures: 3, LastError: "connection refused"},
+			{Name: "secondary", Active: true, Healthy: true},
+		},
+		last: &maestrofailover.Switch{From: "primary", To: "secondary", Reason: maestrofailover.ReasonFailover},
+	}
+	handler := NewMaestroEndpointsHandler(endpoints, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	w := httptest.NewRecorder()
+	handler.List(w, httptest.NewRequest(http.MethodGet, "/api/v0/admin/maestro/endpoints", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+
+	var resp apiv0.MaestroEndpointListResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Kind != "MaestroEndpointList" || len(resp.Items) != 2 {
+		t.Fatalf("Unexpected response %+v", resp)
+	}
+	if resp.Items[0].ConsecutiveFailures != 3 || !resp.Items[1].Active {
+		t.Errorf("Unexpected endpoints %+v", resp.Items)
+	}
+	if resp.LastSwitch == nil || resp.LastSwitch.Reason != maestrofailover.ReasonFailover {
+		t.Errorf("Expected the failover to be reported, got %+v", resp.LastSwitch)
+	}
+}
@@ -1,46 +1,98 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clusterregistry"
+	"github.com/openshift/rosa-regional-platform-api/pkg/fanout"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 )
 
 // ManagementClusterHandler handles management cluster endpoints
 type ManagementClusterHandler struct {
-	maestroClient *maestro.Client
-	logger        *slog.Logger
+	maestroClient  maestro.ClientInterface
+	pageLimits     PageLimits
+	registry       clusterregistry.Registry
+	listCache      *consumerListCache
+	deregistration deregistration
+	capabilities   clustercaps.Store
+	logger         *slog.Logger
 }
 
-// NewManagementClusterHandler creates a new ManagementClusterHandler
-func NewManagementClusterHandler(maestroClient *maestro.Client, logger *slog.Logger) *ManagementClusterHandler {
+// NewManagementClusterHandler creates a new ManagementClusterHandler.
+// When registry is non-nil, non-privileged callers only see management clusters
+// registered by their own account.
+func NewManagementClusterHandler(maestroClient maestro.ClientInterface, registry clusterregistry.Registry, logger *slog.Logger) *ManagementClusterHandler {
 	return &ManagementClusterHandler{
 		maestroClient: maestroClient,
+		pageLimits:    DefaultPlatformPageLimits,
+		registry:      registry,
 		logger:        logger,
 	}
 }
 
+// scopedToAccount reports whether the request must be limited to the caller's registrations
+func (h *ManagementClusterHandler) scopedToAccount(r *http.Request) bool {
+	return h.registry != nil && !middleware.GetPrivileged(r.Context())
+}
+
 // Create handles POST /api/v0/management_clusters
 func (h *ManagementClusterHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	h.logger.Info("creating management cluster", "account_id", accountID)
 
-	var req maestro.ConsumerCreateRequest
+	var body apiv0.ManagementClusterCreateRequest
 	if r.Body != nil && r.ContentLength > 0 {
-		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-			h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+		if err := decodeJSON(r, &body); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+			return
+		}
+	}
+	req := body.ConsumerCreateRequest
+	if body.Capabilities != nil {
+		if h.capabilities == nil {
+			h.writeError(w, http.StatusBadRequest, "capabilities-disabled", "Management cluster capabilities are not enabled")
+			return
+		}
+		if problem := validateCapabilities(body.Capabilities); problem != "" {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", problem)
 			return
 		}
 	}
 
+	registeredAt := time.Now().UTC()
+	if h.registry != nil {
+		if req.Labels == nil {
+			req.Labels = make(map[string]string)
+		}
+		for k, v := range clusterregistry.OwnerLabels(accountID, registeredAt) {
+			req.Labels[k] = v
+		}
+	}
+
+	// A dry run previews the consumer Maestro would be asked to create
+	if writeDryRun(w, r, http.StatusCreated, req) {
+		return
+	}
+
 	consumer, err := h.maestroClient.CreateConsumer(ctx, &req)
 	if err != nil {
 		h.logger.Error("failed to create consumer in Maestro", "error", err, "account_id", accountID)
@@ -52,22 +104,86 @@ func (h *ManagementClusterHandler) Create(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	if h.registry != nil {
+		reg := &clusterregistry.Registration{
+			ConsumerID:   consumer.ID,
+			ConsumerName: consumer.Name,
+			AccountID:    accountID,
+			RegisteredBy: middleware.GetCallerARN(ctx),
+			RegisteredAt: clock.Format(registeredAt),
+		}
+		if err := h.registry.Register(ctx, reg); err != nil {
+			h.logger.Error("failed to register management cluster", "error", err, "id", consumer.ID, "account_id", accountID)
+			// An unregistered consumer is owned by no account, so remove it
+			// rather than leave it behind; the caller can simply retry
+			if err := h.maestroClient.DeleteConsumer(context.WithoutCancel(ctx), consumer.ID); err != nil && !isMaestroNotFound(err) {
+				h.logger.Error("failed to delete unregistered consumer", "error", err, "id", consumer.ID, "account_id", accountID)
+			}
+			h.writeError(w, http.StatusInternalServerError, "registry-error", "Failed to register management cluster ownership")
+			return
+		}
+	}
+
+	// The cluster is registered by now; capabilities can be recorded again
+	// later, so failing to record them does not fail the registration
+	if body.Capabilities != nil {
+		caps := requestedCapabilities(r, body.Capabilities, consumer.Name)
+		if err := h.capabilities.Put(ctx, &caps); err != nil {
+			h.logger.Warn("failed to record management cluster capabilities", "error", err, "id", consumer.ID, "account_id", accountID)
+		}
+	}
+
+	if h.listCache != nil {
+		h.listCache.invalidate(ctx)
+	}
+
 	h.logger.Info("management cluster created", "id", consumer.ID, "name", consumer.Name, "account_id", accountID)
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(consumer)
+	writeResponse(w, r, http.StatusCreated, consumer)
+}
+
+// WithPageLimits sets the default and maximum page size for List
+func (h *ManagementClusterHandler) WithPageLimits(limits PageLimits) *ManagementClusterHandler {
+	h.pageLimits = limits
+	return h
+}
+
+// WithListCache caches the unscoped management cluster list for ttl and
+// serves it for up to stale longer while it is refreshed in the background.
+// Registering a management cluster through this handler clears the cache.
+// A non-nil shared cache shares the list and its invalidation with the other
+// replicas.
+func (h *ManagementClusterHandler) WithListCache(ttl, stale time.Duration, shared cache.Cache) *ManagementClusterHandler {
+	if ttl > 0 {
+		h.listCache = newConsumerListCache(ttl, stale, shared, h.logger)
+	}
+	return h
+}
+
+// SetListCacheTTL changes how long the cached list is fresh, when the
+// configuration is reloaded. A cache cannot be enabled or disabled this way.
+func (h *ManagementClusterHandler) SetListCacheTTL(ttl time.Duration) error {
+	switch {
+	case h.listCache == nil && ttl > 0:
+		return errors.New("enabling the management cluster list cache requires a restart")
+	case h.listCache != nil && ttl <= 0:
+		return errors.New("disabling the management cluster list cache requires a restart")
+	case h.listCache != nil:
+		h.listCache.setTTL(ttl)
+	}
+	return nil
 }
 
 // List handles GET /api/v0/management_clusters
 func (h *ManagementClusterHandler) List(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	h.logger.Debug("listing management clusters", "account_id", accountID)
 
 	page := 1
-	size := 100
 
 	if p := r.URL.Query().Get("page"); p != "" {
 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
@@ -75,13 +191,18 @@ func (h *ManagementClusterHandler) List(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
-	if s := r.URL.Query().Get("size"); s != "" {
-		if parsed, err := strconv.Atoi(s); err == nil && parsed > 0 && parsed <= 100 {
-			size = parsed
-		}
+	size, err := pageSize(r, "size", h.pageLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
+	}
+
+	if h.scopedToAccount(r) {
+		h.listRegistered(w, r, accountID, page, size)
+		return
 	}
 
-	list, err := h.maestroClient.ListConsumers(ctx, page, size)
+	list, err := h.listConsumers(w, r, page, size)
 	if err != nil {
 		h.logger.Error("failed to list consumers from Maestro", "error", err, "account_id", accountID)
 		if maestroErr, ok := err.(*maestro.Error); ok {
@@ -94,19 +215,65 @@ func (h *ManagementClusterHandler) List(w http.ResponseWriter, r *http.Request)
 
 	h.logger.Debug("management clusters listed", "total", list.Total, "account_id", accountID)
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(list)
+	setNumberedLinks(w, r, page, size, list.Total)
+	writeListResponse(w, r, http.StatusOK, pageEnvelope{Kind: list.Kind, Page: list.Page, Size: list.Size, Total: list.Total}, list.Items)
+}
+
+// listConsumers lists a page of consumers from Maestro, through the list
+// cache when one is configured
+func (h *ManagementClusterHandler) listConsumers(w http.ResponseWriter, r *http.Request, page, size int) (*maestro.ConsumerList, error) {
+	list, state, age, err := h.consumers(r.Context(), page, size)
+	if err != nil {
+		return nil, err
+	}
+	if h.listCache != nil {
+		h.listCache.setHeaders(w, state, age)
+	}
+	return list, nil
+}
+
+// consumers returns a page of consumers with its cache state and age, which
+// are empty without a list cache
+func (h *ManagementClusterHandler) consumers(ctx context.Context, page, size int) (*maestro.ConsumerList, string, time.Duration, error) {
+	fetch := func(ctx context.Context) (*maestro.ConsumerList, error) {
+		list, err := h.maestroClient.ListConsumers(ctx, page, size)
+		if err != nil {
+			return nil, err
+		}
+		list.Items = emptyIfNil(list.Items)
+		return list, nil
+	}
+	if h.listCache == nil {
+		list, err := fetch(ctx)
+		return list, "", 0, err
+	}
+	return h.listCache.get(ctx, consumerListKey{page: page, size: size}, fetch)
+}
+
+// Warm lists the first page of management clusters at the default size, so
+// the Maestro connection is open and the list cache holds the page before
+// the first request
+func (h *ManagementClusterHandler) Warm(ctx context.Context) error {
+	_, _, _, err := h.consumers(ctx, 1, h.pageLimits.Default)
+	return err
 }
 
 // Get handles GET /api/v0/management_clusters/{id}
 func (h *ManagementClusterHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	id := vars["id"]
 
 	h.logger.Debug("getting management cluster", "id", id, "account_id", accountID)
 
+	if h.scopedToAccount(r) && !h.ownsCluster(w, r, accountID, id) {
+		return
+	}
+
 	consumer, err := h.maestroClient.GetConsumer(ctx, id)
 	if err != nil {
 		h.logger.Error("failed to get consumer from Maestro", "error", err, "id", id, "account_id", accountID)
@@ -125,19 +292,72 @@ func (h *ManagementClusterHandler) Get(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Debug("management cluster retrieved", "id", consumer.ID, "name", consumer.Name, "account_id", accountID)
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(consumer)
+	writeResponse(w, r, http.StatusOK, consumer)
+}
+
+// listRegistered lists the management clusters registered by accountID
+func (h *ManagementClusterHandler) listRegistered(w http.ResponseWriter, r *http.Request, accountID string, page, size int) {
+	ctx := r.Context()
+
+	regs, err := h.registry.ListByAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to list management cluster registrations", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "registry-error", "Failed to list management clusters")
+		return
+	}
+
+	list := &maestro.ConsumerList{
+		Kind:  "ConsumerList",
+		Page:  page,
+		Total: len(regs),
+		Items: []maestro.Consumer{},
+	}
+
+	start := (page - 1) * size
+	if start < len(regs) {
+		end := start + size
+		if end > len(regs) {
+			end = len(regs)
+		}
+		consumers, err := fanout.Map(ctx, regs[start:end], fanout.DefaultLimit,
+			func(ctx context.Context, reg *clusterregistry.Registration) (*maestro.Consumer, error) {
+				consumer, err := h.maestroClient.GetConsumer(ctx, reg.ConsumerID)
+				if err != nil {
+					h.logger.Error("failed to get consumer from Maestro", "error", err, "id", reg.ConsumerID, "account_id", accountID)
+				}
+				return consumer, err
+			})
+		if err != nil {
+			if maestroErr, ok := err.(*maestro.Error); ok {
+				h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
+				return
+			}
+			h.writeError(w, http.StatusInternalServerError, "maestro-error", "Failed to list management clusters")
+			return
+		}
+		for _, consumer := range consumers {
+			// Registrations can outlive their consumer; skip stale entries
+			if consumer == nil {
+				continue
+			}
+			list.Items = append(list.Items, *consumer)
+		}
+	}
+	list.Size = len(list.Items)
+
+	h.logger.Debug("management clusters listed", "total", list.Total, "account_id", accountID)
+
+	setNumberedLinks(w, r, page, size, list.Total)
+	if h.listCache != nil {
+		// Account-scoped lists are not cached
+		w.Header().Set("Cache-Control", "private, no-cache")
+	}
+	writeListResponse(w, r, http.StatusOK, pageEnvelope{Kind: list.Kind, Page: list.Page, Size: list.Size, Total: list.Total}, list.Items)
 }
 
 func (h *ManagementClusterHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
-	resp := map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+)
+
+// Cache states reported in the X-Cache response header
+const (
+	cacheHit   = "HIT"
+	cacheStale = "STALE"
+	cacheMiss  = "MISS"
+)
+
+// consumerListNamespace holds the consumer list pages in a shared cache
+const consumerListNamespace = "management-clusters"
+
+// consumerListKey identifies a cached page of the Maestro consumer list
+type consumerListKey struct {
+	page, size int
+}
+
+func (k consumerListKey) String() string {
+	return fmt.Sprintf("%d:%d", k.page, k.size)
+}
+
+// sharedConsumerList is a page as stored in the shared cache
+type sharedConsumerList struct {
+	List      *maestro.ConsumerList `json:"list"`
+	FetchedAt time.Time             `json:"fetchedAt"`
+}
+
+type cachedConsumerList struct {
+	list       *maestro.ConsumerList
+	fetchedAt  time.Time
+	refreshing bool
+}
+
+// consumerListCache caches pages of the Maestro consumer list. A page is
+// served from the cache for ttl; for a further stale it is still served while
+// a single background fetch refreshes it. Older pages are fetched before
+// responding.
+//
+// With a shared cache, pages are also written to it and pages missing here
+// are read from it, so replicas reuse each other's fetches; invalidations
+// reach every replica through it.
+type consumerListCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	stale   time.Duration
+	entries map[consumerListKey]*cachedConsumerList
+	shared  cache.Cache
+	// generation changes on every invalidation so fetches started before it
+	// are not stored
+	generation uint64
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// newConsumerListCache creates the cache; shared may be nil to keep pages in
+// this replica only
+func newConsumerListCache(ttl, stale time.Duration, shared cache.Cache, logger *slog.Logger) *consumerListCache {
+	c := &consumerListCache{
+		ttl:     ttl,
+		stale:   stale,
+		entries: make(map[consumerListKey]*cachedConsumerList),
+		shared:  shared,
+		logger:  logger,
+		now:     time.Now,
+	}
+	if shared != nil {
+		shared.OnInvalidate(consumerListNamespace, c.drop)
+	}
+	return c
+}
+
+// get returns the page for key, calling fetch when it is missing or too old,
+// together with its cache state and age
+func (c *consumerListCache) get(ctx context.Context, key consumerListKey, fetch func(context.Context) (*maestro.ConsumerList, error)) (*maestro.ConsumerList, string, time.Duration, error) {
+	if list, state, age, ok := c.lookup(ctx, key, fetch); ok {
+		return list, state, age, nil
+	}
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+
+	if c.load(ctx, key, generation) {
+		if list, state, age, ok := c.lookup(ctx, key, fetch); ok {
+			return list, state, age, nil
+		}
+	}
+
+	list, err := fetch(ctx)
+	if err != nil {
+		return nil, "", 0, err
+	}
+	c.store(ctx, key, generation, list)
+	return list, cacheMiss, 0, nil
+}
+
+// lookup returns the page for key if it is held here and fresh or stale,
+// starting a background refresh for a stale page
+func (c *consumerListCache) lookup(ctx context.Context, key consumerListKey, fetch func(context.Context) (*maestro.ConsumerList, error)) (*maestro.ConsumerList, string, time.Duration, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, "", 0, false
+	}
+	age := c.now().Sub(entry.fetchedAt)
+	if age < c.ttl {
+		return entry.list, cacheHit, age, true
+	}
+	if age < c.ttl+c.stale {
+		if !entry.refreshing {
+			entry.refreshing = true
+			go c.refresh(context.WithoutCancel(ctx), key, c.generation, fetch)
+		}
+		return entry.list, cacheStale, age, true
+	}
+	return nil, "", 0, false
+}
+
+// load copies the page for key from the shared cache, reporting whether it
+// found one. Shared cache errors are logged and treated as a miss.
+func (c *consumerListCache) load(ctx context.Context, key consumerListKey, generation uint64) bool {
+	if c.shared == nil {
+		return false
+	}
+	data, ok, err := c.shared.Get(ctx, consumerListNamespace, key.String())
+	if err != nil {
+		c.logger.Warn("failed to read shared management cluster list cache", "error", err, "page", key.page, "size", key.size)
+		return false
+	}
+	if !ok {
+		return false
+	}
+	var shared sharedConsumerList
+	if err := json.Unmarshal(data, &shared); err != nil || shared.List == nil {
+		c.logger.Warn("ignoring malformed shared management cluster list", "error", err, "page", key.page, "size", key.size)
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return false
+	}
+	c.entries[key] = &cachedConsumerList{list: shared.List, fetchedAt: shared.FetchedAt}
+	return true
+}
+
+// refresh fetches a stale page in the background
+func (c *consumerListCache) refresh(ctx context.Context, key consumerListKey, generation uint64, fetch func(context.Context) (*maestro.ConsumerList, error)) {
+	list, err := fetch(ctx)
+	if err != nil {
+		c.logger.Warn("failed to refresh cached management cluster list", "error", err, "page", key.page, "size", key.size)
+		c.mu.Lock()
+		if entry, ok := c.entries[key]; ok {
+			entry.refreshing = false
+		}
+		c.mu.Unlock()
+		return
+	}
+	c.store(ctx, key, generation, list)
+}
+
+// store keeps a fetched page, unless the cache was invalidated since the
+// fetch started, and writes it to the shared cache
+func (c *consumerListCache) store(ctx context.Context, key consumerListKey, generation uint64, list *maestro.ConsumerList) {
+	c.mu.Lock()
+	if generation != c.generation {
+		c.mu.Unlock()
+		return
+	}
+	fetchedAt := c.now()
+	c.entries[key] = &cachedConsumerList{list: list, fetchedAt: fetchedAt}
+	expiry := c.ttl + c.stale
+	c.mu.Unlock()
+
+	if c.shared == nil {
+		return
+	}
+	data, err := json.Marshal(sharedConsumerList{List: list, FetchedAt: fetchedAt})
+	if err == nil {
+		err = c.shared.Set(ctx, consumerListNamespace, key.String(), data, expiry)
+	}
+	if err != nil {
+		c.logger.Warn("failed to write shared management cluster list cache", "error", err, "page", key.page, "size", key.size)
+	}
+}
+
+// invalidate drops every cached page, in every replica when the cache is
+// shared
+func (c *consumerListCache) invalidate(ctx context.Context) {
+	c.drop()
+	if c.shared == nil {
+		return
+	}
+	if err := c.shared.Invalidate(ctx, consumerListNamespace); err != nil {
+		c.logger.Warn("failed to invalidate shared management cluster list cache", "error", err)
+	}
+}
+
+// drop drops the pages held by this replica
+func (c *consumerListCache) drop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[consumerListKey]*cachedConsumerList)
+	c.generation++
+}
+
+// setTTL changes how long pages are fresh, including pages already cached
+func (c *consumerListCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	c.ttl = ttl
+	c.mu.Unlock()
+}
+
+// setHeaders describes a cached response: how long it is fresh, how long
+// after that it may be served stale, and how old it already is
+func (c *consumerListCache) setHeaders(w http.ResponseWriter, state string, age time.Duration) {
+	c.mu.Lock()
+	ttl, stale := c.ttl, c.stale
+	c.mu.Unlock()
+	w.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d, stale-while-revalidate=%d",
+		int(ttl.Seconds()), int(stale.Seconds())))
+	w.Header().Set("Age", strconv.Itoa(int(age.Seconds())))
+	w.Header().Set("X-Cache", state)
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// requestedCapabilities returns the capabilities req records for the cluster
+// named clusterID, by the caller of r
+func requestedCapabilities(r *http.Request, req *apiv0.ManagementClusterCapabilitiesRequest, clusterID string) clustercaps.Capabilities {
+	caps := clustercaps.Capabilities{
+		ClusterID:         clusterID,
+		APIResources:      req.APIResources,
+		KubernetesVersion: req.KubernetesVersion,
+		CRDs:              req.CRDs,
+		AgentVersion:      req.AgentVersion,
+		UpdatedAt:         clock.Format(time.Now()),
+		UpdatedBy:         middleware.GetCallerARN(r.Context()),
+	}
+	if caps.APIResources == nil {
+		caps.APIResources = []string{}
+	}
+	return caps
+}
+
+// validateCapabilities returns why req is malformed, or ""
+func validateCapabilities(req *apiv0.ManagementClusterCapabilitiesRequest) string {
+	caps := clustercaps.Capabilities{
+		APIResources:      req.APIResources,
+		KubernetesVersion: req.KubernetesVersion,
+		CRDs:              req.CRDs,
+		AgentVersion:      req.AgentVersion,
+	}
+	return caps.Validate()
+}
+
+// WithCapabilities records management cluster capabilities in caps
+func (h *ManagementClusterHandler) WithCapabilities(caps clustercaps.Store) *ManagementClusterHandler {
+	h.capabilities = caps
+	return h
+}
+
+// GetCapabilities handles GET /api/v0/management_clusters/{id}/capabilities
+func (h *ManagementClusterHandler) GetCapabilities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+
+	if h.capabilities == nil {
+		h.writeError(w, http.StatusNotImplemented, "capabilities-disabled", "Management cluster capabilities are not enabled")
+		return
+	}
+	if h.scopedToAccount(r) && !h.ownsCluster(w, r, accountID, id) {
+		return
+	}
+
+	consumer, err := h.maestroClient.GetConsumer(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get consumer from Maestro", "error", err, "id", id, "account_id", accountID)
+		h.writeMaestroError(w, err, "Failed to get management cluster capabilities")
+		return
+	}
+	if consumer == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Management cluster not found")
+		return
+	}
+
+	caps, err := h.capabilities.Get(ctx, consumer.Name)
+	if err != nil {
+		h.logger.Error("failed to get management cluster capabilities", "error", err, "id", id, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "capabilities-error", "Failed to get management cluster capabilities")
+		return
+	}
+	if caps == nil {
+		h.writeError(w, http.StatusNotFound, "capabilities-not-found", "No capabilities are recorded for this management cluster")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.ManagementClusterCapabilities{
+		Kind:         "ManagementClusterCapabilities",
+		ID:           consumer.ID,
+		Capabilities: *caps,
+	})
+}
+
+// PutCapabilities handles PUT /api/v0/management_clusters/{id}/capabilities
+func (h *ManagementClusterHandler) PutCapabilities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+
+	if h.capabilities == nil {
+		h.writeError(w, http.StatusNotImplemented, "capabilities-disabled", "Management cluster capabilities are not enabled")
+		return
+	}
+
+	var req apiv0.ManagementClusterCapabilitiesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if problem := validateCapabilities(&req); problem != "" {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", problem)
+		return
+	}
+
+	if h.scopedToAccount(r) && !h.ownsCluster(w, r, accountID, id) {
+		return
+	}
+
+	consumer, err := h.maestroClient.GetConsumer(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get consumer from Maestro", "error", err, "id", id, "account_id", accountID)
+		h.writeMaestroError(w, err, "Failed to update management cluster capabilities")
+		return
+	}
+	if consumer == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Management cluster not found")
+		return
+	}
+
+	resp := apiv0.ManagementClusterCapabilities{
+		Kind:         "ManagementClusterCapabilities",
+		ID:           consumer.ID,
+		Capabilities: requestedCapabilities(r, &req, consumer.Name),
+	}
+	if writeDryRun(w, r, http.StatusOK, resp) {
+		return
+	}
+
+	if err := h.capabilities.Put(ctx, &resp.Capabilities); err != nil {
+		h.logger.Error("failed to store management cluster capabilities", "error", err, "id", id, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "capabilities-error", "Failed to update management cluster capabilities")
+		return
+	}
+
+	h.logger.Info("management cluster capabilities updated", "id", consumer.ID, "name", consumer.Name, "api_resources", len(resp.APIResources), "account_id", accountID)
+
+	writeResponse(w, r, http.StatusOK, resp)
+}
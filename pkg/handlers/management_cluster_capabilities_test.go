@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+func capabilitiesRequest(id, accountID, body string, privileged bool) *http.Request {
+	req := httptest.NewRequest(http.MethodPut, "/api/v0/management_clusters/"+id+"/capabilities", strings.NewReader(body))
+	req = mux.SetURLVars(req, map[string]string{"id": id})
+	return withAccount(req, accountID, privileged)
+}
+
+func TestManagementClusterHandler_PutCapabilities(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		privileged bool
+		wantStatus int
+	}{
+		{name: "own cluster", id: "mc-a", body: `{"api_resources":["v1/ConfigMap","apps/v1/Deployment"]}`, wantStatus: http.StatusOK},
+		{name: "other account's cluster", id: "mc-b", body: `{"api_resources":["v1/ConfigMap"]}`, wantStatus: http.StatusNotFound},
+		{name: "privileged caller", id: "mc-b", body: `{"api_resources":["v1/ConfigMap"]}`, privileged: true, wantStatus: http.StatusOK},
+		{name: "malformed resource", id: "mc-a", body: `{"api_resources":["ConfigMap"]}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, _ := newMgmtClusterTestHandler()
+			caps := &fakeCapabilities{caps: map[string]*clustercaps.Capabilities{}}
+			handler.WithCapabilities(caps)
+
+			rec := httptest.NewRecorder()
+			handler.PutCapabilities(rec, capabilitiesRequest(tt.id, "111111111111", tt.body, tt.privileged))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(caps.caps) != 0 {
+					t.Errorf("expected nothing to be stored, got %+v", caps.caps)
+				}
+				return
+			}
+
+			var resp apiv0.ManagementClusterCapabilities
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			stored := caps.caps[tt.id]
+			if stored == nil || resp.ClusterID != tt.id || len(stored.APIResources) != len(resp.APIResources) {
+				t.Errorf("expected the capabilities to be stored under the cluster name, got %+v", caps.caps)
+			}
+			if stored.UpdatedBy != "arn:aws:iam::111111111111:user/test" {
+				t.Errorf("expected the caller to be recorded, got %q", stored.UpdatedBy)
+			}
+		})
+	}
+}
+
+func TestManagementClusterHandler_PutCapabilities_DryRun(t *testing.T) {
+	handler, _, _ := newMgmtClusterTestHandler()
+	caps := &fakeCapabilities{caps: map[string]*clustercaps.Capabilities{}}
+	handler.WithCapabilities(caps)
+
+	req := capabilitiesRequest("mc-a", "111111111111", `{"api_resources":["v1/ConfigMap"]}`, false)
+	req = req.WithContext(middleware.WithDryRun(req.Context()))
+	rec := httptest.NewRecorder()
+	handler.PutCapabilities(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(caps.caps) != 0 {
+		t.Errorf("expected a dry run to store nothing, got %+v", caps.caps)
+	}
+}
+
+func TestManagementClusterHandler_GetCapabilities(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		privileged bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "own cluster", id: "mc-a", wantStatus: http.StatusOK},
+		{name: "other account's cluster", id: "mc-b", wantStatus: http.StatusNotFound, wantCode: "not-found"},
+		{name: "nothing recorded", id: "mc-b", privileged: true, wantStatus: http.StatusNotFound, wantCode: "capabilities-not-found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, _ := newMgmtClusterTestHandler()
+			handler.WithCapabilities(&fakeCapabilities{caps: map[string]*clustercaps.Capabilities{
+				"mc-a": {ClusterID: "mc-a", APIResources: []string{"v1/ConfigMap"}, KubernetesVersion: "1.29.4", AgentVersion: "0.6.0"},
+			}})
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters/"+tt.id+"/capabilities", nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			rec := httptest.NewRecorder()
+			handler.GetCapabilities(rec, withAccount(req, "111111111111", tt.privileged))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantCode != "" {
+				if !strings.Contains(rec.Body.String(), tt.wantCode) {
+					t.Errorf("expected code %s, got %s", tt.wantCode, rec.Body.String())
+				}
+				return
+			}
+			var resp apiv0.ManagementClusterCapabilities
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "ManagementClusterCapabilities" || resp.KubernetesVersion != "1.29.4" || resp.AgentVersion != "0.6.0" {
+				t.Errorf("unexpected capabilities %+v", resp)
+			}
+		})
+	}
+}
+
+func TestManagementClusterHandler_Create_RecordsCapabilities(t *testing.T) {
+	handler, _, _ := newMgmtClusterTestHandler()
+	caps := &fakeCapabilities{caps: map[string]*clustercaps.Capabilities{}}
+	handler.WithCapabilities(caps)
+
+	body := `{"name":"mc-new","capabilities":{"api_resources":["v1/ConfigMap"],"kubernetes_version":"1.30.2","crds":["servicemonitors.monitoring.coreos.com"],"agent_version":"0.6.1"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/management_clusters", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.Create(rec, withAccount(req, "111111111111", false))
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	got := caps.caps["mc-new"]
+	if got == nil || got.KubernetesVersion != "1.30.2" || got.AgentVersion != "0.6.1" || len(got.CRDs) != 1 {
+		t.Errorf("expected the capabilities to be recorded at registration, got %+v", got)
+	}
+}
+
+func TestManagementClusterHandler_Create_InvalidCapabilities(t *testing.T) {
+	handler, mc, _ := newMgmtClusterTestHandler()
+	handler.WithCapabilities(&fakeCapabilities{caps: map[string]*clustercaps.Capabilities{}})
+
+	body := `{"name":"mc-new","capabilities":{"api_resources":[],"kubernetes_version":"latest"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/management_clusters", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.Create(rec, withAccount(req, "111111111111", false))
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if mc.lastCreate != nil {
+		t.Error("expected no consumer to be created")
+	}
+}
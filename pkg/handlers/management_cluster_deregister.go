@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+)
+
+// Steps of a deregistration, reported as the progress of its operation
+const (
+	stepDrainBundles    = "draining-bundles"
+	stepAwaitBundles    = "awaiting-bundle-removal"
+	stepDeleteConsumer  = "deleting-consumer"
+	drainPollInterval   = 5 * time.Second
+	defaultDrainTimeout = 10 * time.Minute
+)
+
+// deregistration configures how management clusters are deregistered
+type deregistration struct {
+	drainBundles bool
+	drainTimeout time.Duration
+	pollInterval time.Duration
+	operations   *operations.Registry
+}
+
+// WithDeregistration configures Deregister. With drainBundles, the resource
+// bundles of a deregistered cluster are deleted and waited for, up to
+// drainTimeout, before its consumer is; without, a cluster that still has
+// resource bundles cannot be deregistered. Deregistrations are tracked in ops.
+func (h *ManagementClusterHandler) WithDeregistration(drainBundles bool, drainTimeout time.Duration, ops *operations.Registry) *ManagementClusterHandler {
+	if drainTimeout <= 0 {
+		drainTimeout = defaultDrainTimeout
+	}
+	h.deregistration = deregistration{
+		drainBundles: drainBundles,
+		drainTimeout: drainTimeout,
+		pollInterval: drainPollInterval,
+		operations:   ops,
+	}
+	return h
+}
+
+// Deregister handles DELETE /api/v0/management_clusters/{id}. The cluster's
+// resource bundles are drained and its consumer deleted in the background,
+// tracked as a cluster-deregistration operation.
+func (h *ManagementClusterHandler) Deregister(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	id := mux.Vars(r)["id"]
+
+	h.logger.Info("deregistering management cluster", "id", id, "account_id", accountID)
+
+	if h.scopedToAccount(r) && !h.ownsCluster(w, r, accountID, id) {
+		return
+	}
+
+	consumer, err := h.maestroClient.GetConsumer(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to get consumer from Maestro", "error", err, "id", id, "account_id", accountID)
+		h.writeMaestroError(w, err, "Failed to deregister management cluster")
+		return
+	}
+	if consumer == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Management cluster not found")
+		return
+	}
+
+	bundleIDs, err := h.consumerBundleIDs(ctx, consumer.Name)
+	if err != nil {
+		h.logger.Error("failed to list resource bundles of consumer", "error", err, "id", id, "account_id", accountID)
+		h.writeMaestroError(w, err, "Failed to deregister management cluster")
+		return
+	}
+	if len(bundleIDs) > 0 && !h.deregistration.drainBundles {
+		h.writeError(w, http.StatusConflict, "management-cluster-not-empty",
+			fmt.Sprintf("Management cluster still has %d resource bundles; delete them before deregistering it", len(bundleIDs)))
+		return
+	}
+
+	resp := apiv0.ManagementClusterDeregistration{
+		Kind:            "ManagementClusterDeregistration",
+		ID:              consumer.ID,
+		Name:            consumer.Name,
+		ResourceBundles: len(bundleIDs),
+	}
+	if writeDryRun(w, r, http.StatusAccepted, resp) {
+		return
+	}
+
+	// The deregistration outlives the request, but can still be followed and
+	// cancelled through the operations API
+	op := h.deregistration.operations.Go(ctx, operations.Operation{
+		Type:      operations.TypeClusterDeregistration,
+		State:     operations.StateRunning,
+		AccountID: accountID,
+		CallerARN: middleware.GetCallerARN(ctx),
+		ClusterID: consumer.Name,
+		Name:      consumer.ID,
+	}, func(ctx context.Context) (any, error) {
+		return nil, h.deregister(ctx, accountID, consumer, bundleIDs)
+	})
+	resp.OperationID = op.ID
+
+	writeResponse(w, r, http.StatusAccepted, resp)
+}
+
+// deregister deletes the given resource bundles of consumer, waits for
+// Maestro to remove them, then deletes the consumer and its registration.
+// The error it stopped on is the operation's.
+func (h *ManagementClusterHandler) deregister(ctx context.Context, accountID string, consumer *maestro.Consumer, bundleIDs []string) error {
+	ops := h.deregistration.operations
+	log := h.logger.With("id", consumer.ID, "name", consumer.Name, "account_id", accountID, "operation_id", operations.ID(ctx))
+
+	for i, bundleID := range bundleIDs {
+		ops.SetProgress(ctx, operations.Progress{Step: stepDrainBundles, Done: i, Total: len(bundleIDs)})
+		if err := h.maestroClient.DeleteResourceBundle(ctx, bundleID); err != nil && !isMaestroNotFound(err) {
+			log.Error("management cluster deregistration stopped: failed to delete resource bundle", "error", err, "bundle_id", bundleID)
+			return fmt.Errorf("failed to delete resource bundle %s: %w", bundleID, err)
+		}
+	}
+
+	if len(bundleIDs) > 0 {
+		// Maestro removes a deleted bundle once its agent confirms it
+		deadline := time.Now().Add(h.deregistration.drainTimeout)
+		for {
+			remaining, err := h.consumerBundleIDs(ctx, consumer.Name)
+			if err != nil {
+				log.Error("management cluster deregistration stopped: failed to list resource bundles", "error", err)
+				return fmt.Errorf("failed to list resource bundles: %w", err)
+			}
+			ops.SetProgress(ctx, operations.Progress{Step: stepAwaitBundles, Done: len(bundleIDs) - len(remaining), Total: len(bundleIDs)})
+			if len(remaining) == 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				log.Error("management cluster deregistration stopped: resource bundles not removed in time", "remaining", len(remaining), "timeout", h.deregistration.drainTimeout)
+				return fmt.Errorf("%d resource bundles were not removed within %s", len(remaining), h.deregistration.drainTimeout)
+			}
+			select {
+			case <-ctx.Done():
+				log.Warn("management cluster deregistration cancelled", "error", context.Cause(ctx))
+				return context.Cause(ctx)
+			case <-time.After(h.deregistration.pollInterval):
+			}
+		}
+	}
+
+	ops.SetProgress(ctx, operations.Progress{Step: stepDeleteConsumer, Done: 0, Total: 1})
+	if err := h.maestroClient.DeleteConsumer(ctx, consumer.ID); err != nil && !isMaestroNotFound(err) {
+		log.Error("management cluster deregistration stopped: failed to delete consumer", "error", err)
+		return fmt.Errorf("failed to delete consumer: %w", err)
+	}
+	ops.SetProgress(ctx, operations.Progress{Step: stepDeleteConsumer, Done: 1, Total: 1})
+
+	if h.registry != nil {
+		if err := h.registry.Delete(ctx, consumer.ID); err != nil {
+			log.Error("failed to delete management cluster registration", "error", err)
+		}
+	}
+	if h.listCache != nil {
+		h.listCache.invalidate(ctx)
+	}
+
+	log.Info("management cluster deregistered", "resource_bundles", len(bundleIDs))
+	return nil
+}
+
+// consumerBundleIDs returns the IDs of every resource bundle of the consumer
+// named name
+func (h *ManagementClusterHandler) consumerBundleIDs(ctx context.Context, name string) ([]string, error) {
+	search := maestro.SearchEquals("consumer_name", name)
+	var ids []string
+	for page := 1; ; page++ {
+		list, err := h.maestroClient.ListResourceBundles(ctx, page, h.pageLimits.Max, search, "", "id")
+		if err != nil {
+			return nil, err
+		}
+		for _, bundle := range list.Items {
+			ids = append(ids, bundle.ID)
+		}
+		if len(list.Items) == 0 || len(ids) >= list.Total {
+			return ids, nil
+		}
+	}
+}
+
+// ownsCluster writes an error and returns false unless accountID registered
+// the management cluster id
+func (h *ManagementClusterHandler) ownsCluster(w http.ResponseWriter, r *http.Request, accountID, id string) bool {
+	reg, err := h.registry.Get(r.Context(), id)
+	if err != nil {
+		h.logger.Error("failed to look up management cluster registration", "error", err, "id", id, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "registry-error", "Failed to get management cluster")
+		return false
+	}
+	// Clusters owned by other accounts are indistinguishable from missing ones
+	if reg == nil || reg.AccountID != accountID {
+		h.writeError(w, http.StatusNotFound, "not-found", "Management cluster not found")
+		return false
+	}
+	return true
+}
+
+// writeMaestroError answers a failed Maestro call, with reason for errors
+// that did not come from Maestro itself
+func (h *ManagementClusterHandler) writeMaestroError(w http.ResponseWriter, err error, reason string) {
+	if maestroErr, ok := err.(*maestro.Error); ok {
+		h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
+		return
+	}
+	h.writeError(w, http.StatusInternalServerError, "maestro-error", reason)
+}
+
+// isMaestroNotFound reports whether err is Maestro answering 404
+func isMaestroNotFound(err error) bool {
+	maestroErr, ok := err.(*maestro.Error)
+	return ok && maestroErr.Code == "404"
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+)
+
+// bundleStore serves the resource bundles of the mock Maestro, deleting them
+// either at once or only when released, as Maestro does once the agent
+// confirms
+type bundleStore struct {
+	mu       sync.Mutex
+	bundles  map[string]string // ID to consumer name
+	deleted  []string
+	deferred bool
+}
+
+func (s *bundleStore) list(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page}
+	for id, consumer := range s.bundles {
+		if search == maestro.SearchEquals("consumer_name", consumer) {
+			list.Items = append(list.Items, maestro.ResourceBundle{ID: id, ConsumerName: consumer})
+		}
+	}
+	list.Size = len(list.Items)
+	list.Total = len(list.Items)
+	return list, nil
+}
+
+func (s *bundleStore) delete(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deleted = append(s.deleted, id)
+	if !s.deferred {
+		delete(s.bundles, id)
+	}
+	return nil
+}
+
+func (s *bundleStore) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range s.deleted {
+		delete(s.bundles, id)
+	}
+}
+
+func newDeregisterTestHandler(drain bool, ops *operations.Registry) (*ManagementClusterHandler, *mockConsumerMaestroClient, *mockRegistry, *bundleStore) {
+	handler, mc, reg := newMgmtClusterTestHandler()
+	bundles := &bundleStore{bundles: map[string]string{"rb-1": "mc-a", "rb-2": "mc-a", "rb-3": "mc-b"}}
+	mc.listResourceBundlesFunc = bundles.list
+	mc.deleteResourceBundleFunc = bundles.delete
+	handler.WithDeregistration(drain, time.Second, ops)
+	handler.deregistration.pollInterval = time.Millisecond
+	return handler, mc, reg, bundles
+}
+
+func deregisterRequest(id, accountID string, privileged bool) *http.Request {
+	req := httptest.NewRequest(http.MethodDelete, "/api/v0/management_clusters/"+id, nil)
+	req = mux.SetURLVars(req, map[string]string{"id": id})
+	return withAccount(req, accountID, privileged)
+}
+
+func TestManagementClusterHandler_Deregister_DrainsThenDeletesConsumer(t *testing.T) {
+	ops := operations.NewRegistry()
+	handler, mc, reg, bundles := newDeregisterTestHandler(true, ops)
+
+	rec := httptest.NewRecorder()
+	handler.Deregister(rec, deregisterRequest("mc-a", "111111111111", false))
+
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var resp apiv0.ManagementClusterDeregistration
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.ResourceBundles != 2 || resp.OperationID == "" {
+		t.Errorf("unexpected response: %+v", resp)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(ops.List()) > 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("deregistration did not finish")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	bundles.mu.Lock()
+	if _, ok := bundles.bundles["rb-3"]; !ok || len(bundles.bundles) != 1 {
+		t.Errorf("expected only mc-b's bundle to remain, got %v", bundles.bundles)
+	}
+	bundles.mu.Unlock()
+	if c, _ := mc.GetConsumer(context.Background(), "mc-a"); c != nil {
+		t.Error("expected consumer to be deleted")
+	}
+	if reg.regs["mc-a"] != nil {
+		t.Error("expected registration to be deleted")
+	}
+}
+
+func TestManagementClusterHandler_Deregister_WaitsForBundleRemoval(t *testing.T) {
+	handler, mc, _, bundles := newDeregisterTestHandler(true, nil)
+	bundles.deferred = true
+	consumer := &maestro.Consumer{ID: "mc-a", Name: "mc-a"}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.deregister(context.Background(), "111111111111", consumer, []string{"rb-1", "rb-2"})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	if c, _ := mc.GetConsumer(context.Background(), "mc-a"); c == nil {
+		t.Fatal("expected consumer to be kept while its bundles remain")
+	}
+	bundles.release()
+	<-done
+
+	if c, _ := mc.GetConsumer(context.Background(), "mc-a"); c != nil {
+		t.Error("expected consumer to be deleted once its bundles are gone")
+	}
+}
+
+func TestManagementClusterHandler_Deregister_DrainTimeoutKeepsConsumer(t *testing.T) {
+	handler, mc, _, bundles := newDeregisterTestHandler(true, nil)
+	bundles.deferred = true
+	handler.deregistration.drainTimeout = 10 * time.Millisecond
+
+	handler.deregister(context.Background(), "111111111111", &maestro.Consumer{ID: "mc-a", Name: "mc-a"}, []string{"rb-1", "rb-2"})
+
+	if c, _ := mc.GetConsumer(context.Background(), "mc-a"); c == nil {
+		t.Error("expected consumer to be kept when its bundles are not removed in time")
+	}
+}
+
+func TestManagementClusterHandler_Deregister_Refused(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		drain      bool
+		privileged bool
+		wantStatus int
+		wantCode   string
+	}{
+		{"bundles remain without draining", "mc-a", false, false, http.StatusConflict, "management-cluster-not-empty"},
+		{"other account's cluster", "mc-b", true, false, http.StatusNotFound, "not-found"},
+		{"missing cluster", "mc-missing", true, true, http.StatusNotFound, "not-found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, mc, _, bundles := newDeregisterTestHandler(tt.drain, nil)
+
+			rec := httptest.NewRecorder()
+			handler.Deregister(rec, deregisterRequest(tt.id, "111111111111", tt.privileged))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			var body map[string]interface{}
+			_ = json.NewDecoder(rec.Body).Decode(&body)
+			if body["code"] != tt.wantCode {
+				t.Errorf("expected code %q, got %v", tt.wantCode, body["code"])
+			}
+			if len(bundles.deleted) != 0 || len(mc.consumers) != 2 {
+				t.Error("expected nothing to be deleted")
+			}
+		})
+	}
+}
+
+func TestManagementClusterHandler_Deregister_DryRun(t *testing.T) {
+	handler, mc, _, bundles := newDeregisterTestHandler(true, nil)
+
+	req := deregisterRequest("mc-a", "111111111111", false)
+	req = req.WithContext(middleware.WithDryRun(req.Context()))
+	rec := httptest.NewRecorder()
+	handler.Deregister(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if len(bundles.deleted) != 0 || len(mc.consumers) != 2 {
+		t.Error("expected a dry run to delete nothing")
+	}
+}
@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clusterregistry"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// mockConsumerMaestroClient serves consumers from an in-memory map
+type mockConsumerMaestroClient struct {
+	mockMaestroClient
+	consumers  map[string]*maestro.Consumer
+	lastCreate *maestro.ConsumerCreateRequest
+	listCalled bool
+
+	mu        sync.Mutex
+	listCalls int
+}
+
+func (m *mockConsumerMaestroClient) CreateConsumer(ctx context.Context, req *maestro.ConsumerCreateRequest) (*maestro.Consumer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastCreate = req
+	c := &maestro.Consumer{ID: "consumer-" + req.Name, Name: req.Name, Labels: req.Labels}
+	m.consumers[c.ID] = c
+	return c, nil
+}
+
+func (m *mockConsumerMaestroClient) ListConsumers(ctx context.Context, page, size int) (*maestro.ConsumerList, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.listCalled = true
+	m.listCalls++
+	list := &maestro.ConsumerList{Kind: "ConsumerList", Page: page}
+	for _, c := range m.consumers {
+		list.Items = append(list.Items, *c)
+	}
+	list.Size = len(list.Items)
+	list.Total = len(list.Items)
+	return list, nil
+}
+
+func (m *mockConsumerMaestroClient) GetConsumer(ctx context.Context, id string) (*maestro.Consumer, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.consumers[id], nil
+}
+
+func (m *mockConsumerMaestroClient) DeleteConsumer(ctx context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.consumers[id] == nil {
+		return &maestro.Error{Code: "404", Reason: "Consumer not found"}
+	}
+	delete(m.consumers, id)
+	return nil
+}
+
+// mockRegistry is an in-memory clusterregistry.Registry. Register fails
+// with registerErr when it is set.
+type mockRegistry struct {
+	regs        map[string]*clusterregistry.Registration
+	registerErr error
+}
+
+func (m *mockRegistry) Register(ctx context.Context, reg *clusterregistry.Registration) error {
+	if m.registerErr != nil {
+		return m.registerErr
+	}
+	m.regs[reg.ConsumerID] = reg
+	return nil
+}
+
+func (m *mockRegistry) Get(ctx context.Context, consumerID string) (*clusterregistry.Registration, error) {
+	return m.regs[consumerID], nil
+}
+
+func (m *mockRegistry) ListByAccount(ctx context.Context, accountID string) ([]*clusterregistry.Registration, error) {
+	var out []*clusterregistry.Registration
+	for _, reg := range m.regs {
+		if reg.AccountID == accountID {
+			out = append(out, reg)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockRegistry) Delete(ctx context.Context, consumerID string) error {
+	delete(m.regs, consumerID)
+	return nil
+}
+
+func newMgmtClusterTestHandler() (*ManagementClusterHandler, *mockConsumerMaestroClient, *mockRegistry) {
+	mc := &mockConsumerMaestroClient{consumers: map[string]*maestro.Consumer{
+		"mc-a": {ID: "mc-a", Name: "mc-a"},
+		"mc-b": {ID: "mc-b", Name: "mc-b"},
+	}}
+	reg := &mockRegistry{regs: map[string]*clusterregistry.Registration{
+		"mc-a": {ConsumerID: "mc-a", AccountID: "111111111111"},
+		"mc-b": {ConsumerID: "mc-b", AccountID: "222222222222"},
+	}}
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	return NewManagementClusterHandler(mc, reg, logger), mc, reg
+}
+
+func withAccount(r *http.Request, accountID string, privileged bool) *http.Request {
+	ctx := context.WithValue(r.Context(), middleware.ContextKeyAccountID, accountID)
+	ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, "arn:aws:iam::"+accountID+":user/test")
+	ctx = context.WithValue(ctx, middleware.ContextKeyPrivileged, privileged)
+	return r.WithContext(ctx)
+}
+
+func TestManagementClusterHandler_Create_StampsAndRegisters(t *testing.T) {
+	handler, mc, reg := newMgmtClusterTestHandler()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/management_clusters", strings.NewReader(`{"name":"mc-new"}`))
+	req = withAccount(req, "111111111111", false)
+	rec := httptest.NewRecorder()
+
+	handler.Create(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("expected status 201, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if got := mc.lastCreate.Labels[clusterregistry.LabelOwnerAccount]; got != "111111111111" {
+		t.Errorf("expected owner label 111111111111, got %q", got)
+	}
+	if mc.lastCreate.Labels[clusterregistry.LabelRegisteredAt] == "" {
+		t.Error("expected registered-at label to be set")
+	}
+
+	r := reg.regs["consumer-mc-new"]
+	if r == nil {
+		t.Fatal("expected consumer to be registered")
+	}
+	if r.AccountID != "111111111111" || r.RegisteredBy != "arn:aws:iam::111111111111:user/test" {
+		t.Errorf("unexpected registration: %+v", r)
+	}
+}
+
+func TestManagementClusterHandler_Create_RegisterFailureDeletesConsumer(t *testing.T) {
+	handler, mc, reg := newMgmtClusterTestHandler()
+	reg.registerErr = errors.New("throttled")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/management_clusters", strings.NewReader(`{"name":"mc-new"}`))
+	req = withAccount(req, "111111111111", false)
+	rec := httptest.NewRecorder()
+
+	handler.Create(rec, req)
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status 500, got %d: %s", rec.Code, rec.Body.String())
+	}
+	if mc.lastCreate == nil {
+		t.Fatal("expected the consumer to be created")
+	}
+	if _, ok := mc.consumers["consumer-mc-new"]; ok {
+		t.Error("expected the unregistered consumer to be deleted")
+	}
+	if len(mc.consumers) != 2 {
+		t.Errorf("expected the other consumers to be kept, got %d", len(mc.consumers))
+	}
+}
+
+func TestManagementClusterHandler_Get_ScopedToAccount(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		privileged bool
+		wantStatus int
+	}{
+		{"own cluster", "mc-a", false, http.StatusOK},
+		{"other account's cluster", "mc-b", false, http.StatusNotFound},
+		{"unregistered cluster", "mc-missing", false, http.StatusNotFound},
+		{"privileged sees any cluster", "mc-b", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, _, _ := newMgmtClusterTestHandler()
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters/"+tt.id, nil)
+			req = mux.SetURLVars(req, map[string]string{"id": tt.id})
+			req = withAccount(req, "111111111111", tt.privileged)
+			rec := httptest.NewRecorder()
+
+			handler.Get(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, rec.Code)
+			}
+		})
+	}
+}
+
+func TestManagementClusterHandler_List_ScopedToAccount(t *testing.T) {
+	handler, mc, _ := newMgmtClusterTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters", nil)
+	req = withAccount(req, "111111111111", false)
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if mc.listCalled {
+		t.Error("expected global Maestro list not to be used for scoped callers")
+	}
+
+	var list maestro.ConsumerList
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if list.Total != 1 || len(list.Items) != 1 || list.Items[0].ID != "mc-a" {
+		t.Errorf("expected only mc-a, got %+v", list)
+	}
+}
+
+func TestManagementClusterHandler_List_PrivilegedUsesGlobalList(t *testing.T) {
+	handler, mc, _ := newMgmtClusterTestHandler()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters", nil)
+	req = withAccount(req, "000000000000", true)
+	rec := httptest.NewRecorder()
+
+	handler.List(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", rec.Code)
+	}
+	if !mc.listCalled {
+		t.Error("expected privileged callers to use the global Maestro list")
+	}
+}
+
+func (m *mockConsumerMaestroClient) calls() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.listCalls
+}
+
+func TestManagementClusterHandler_List_Cache(t *testing.T) {
+	handler, mc, _ := newMgmtClusterTestHandler()
+	handler.WithListCache(30*time.Second, 5*time.Minute, nil)
+	now := time.Now()
+	handler.listCache.now = func() time.Time { return now }
+
+	list := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters", nil)
+		req = withAccount(req, "000000000000", true)
+		rec := httptest.NewRecorder()
+		handler.List(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		return rec
+	}
+
+	rec := list()
+	if got := rec.Header().Get("X-Cache"); got != cacheMiss {
+		t.Errorf("expected first list to miss, got %q", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "private, max-age=30, stale-while-revalidate=300" {
+		t.Errorf("unexpected Cache-Control %q", got)
+	}
+
+	now = now.Add(10 * time.Second)
+	rec = list()
+	if got := rec.Header().Get("X-Cache"); got != cacheHit {
+		t.Errorf("expected fresh list to hit, got %q", got)
+	}
+	if got := rec.Header().Get("Age"); got != "10" {
+		t.Errorf("expected Age 10, got %q", got)
+	}
+	if got := mc.calls(); got != 1 {
+		t.Errorf("expected 1 Maestro list, got %d", got)
+	}
+
+	// A stale list is served while a single refresh runs in the background
+	now = now.Add(time.Minute)
+	if got := list().Header().Get("X-Cache"); got != cacheStale {
+		t.Errorf("expected stale list, got %q", got)
+	}
+	deadline := time.Now().Add(time.Second)
+	for mc.calls() < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if got := mc.calls(); got != 2 {
+		t.Fatalf("expected background refresh, got %d Maestro lists", got)
+	}
+
+	// Registering a management cluster invalidates the cache
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/management_clusters", strings.NewReader(`{"name":"mc-new"}`))
+	handler.Create(httptest.NewRecorder(), withAccount(req, "000000000000", true))
+	rec = list()
+	if got := rec.Header().Get("X-Cache"); got != cacheMiss {
+		t.Errorf("expected list after registration to miss, got %q", got)
+	}
+	var got maestro.ConsumerList
+	if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.Total != 3 {
+		t.Errorf("expected new cluster to be listed, got total %d", got.Total)
+	}
+}
+
+func TestManagementClusterHandler_Warm(t *testing.T) {
+	handler, mc, _ := newMgmtClusterTestHandler()
+	handler.WithListCache(30*time.Second, 5*time.Minute, nil)
+
+	if err := handler.Warm(context.Background()); err != nil {
+		t.Fatalf("Warm: %v", err)
+	}
+	req := withAccount(httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters", nil), "000000000000", true)
+	rec := httptest.NewRecorder()
+	handler.List(rec, req)
+	if got := rec.Header().Get("X-Cache"); got != cacheHit {
+		t.Errorf("expected the first list after warming to hit, got %q", got)
+	}
+	if got := mc.calls(); got != 1 {
+		t.Errorf("expected 1 Maestro list, got %d", got)
+	}
+}
+
+func TestManagementClusterHandler_List_SharedCache(t *testing.T) {
+	shared := cache.NewMemory()
+	replicaA, mc, _ := newMgmtClusterTestHandler()
+	replicaA.WithListCache(30*time.Second, 5*time.Minute, shared)
+	replicaB, _, _ := newMgmtClusterTestHandler()
+	// Both replicas list the same Maestro
+	replicaB.maestroClient = mc
+	replicaB.WithListCache(30*time.Second, 5*time.Minute, shared)
+
+	list := func(h *ManagementClusterHandler) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters", nil)
+		rec := httptest.NewRecorder()
+		h.List(rec, withAccount(req, "000000000000", true))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d", rec.Code)
+		}
+		return rec.Header().Get("X-Cache")
+	}
+
+	if got := list(replicaA); got != cacheMiss {
+		t.Errorf("expected first list to miss, got %q", got)
+	}
+	if got := list(replicaB); got != cacheHit {
+		t.Errorf("expected the other replica to reuse the shared list, got %q", got)
+	}
+	if got := mc.calls(); got != 1 {
+		t.Errorf("expected 1 Maestro list, got %d", got)
+	}
+
+	// Registering through one replica invalidates the other's list too
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/management_clusters", strings.NewReader(`{"name":"mc-new"}`))
+	replicaA.Create(httptest.NewRecorder(), withAccount(req, "000000000000", true))
+	if got := list(replicaB); got != cacheMiss {
+		t.Errorf("expected list after registration to miss, got %q", got)
+	}
+}
+
+func TestManagementClusterHandler_List_ScopedNotCached(t *testing.T) {
+	handler, _, _ := newMgmtClusterTestHandler()
+	handler.WithListCache(30*time.Second, 5*time.Minute, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/management_clusters", nil)
+	req = withAccount(req, "111111111111", false)
+	rec := httptest.NewRecorder()
+	handler.List(rec, req)
+
+	if got := rec.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("expected scoped list not to be cacheable, got %q", got)
+	}
+	if got := rec.Header().Get("X-Cache"); got != "" {
+		t.Errorf("expected no X-Cache header, got %q", got)
+	}
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+const (
+	// formatNDJSON is the format query value that streams a list as
+	// newline-delimited JSON
+	formatNDJSON = "ndjson"
+
+	mediaTypeNDJSON = "application/x-ndjson"
+
+	// ndjsonWriteTimeout bounds how long the client may take to read each
+	// flushed batch. The write deadline is pushed back after every flush, so
+	// a stream outlasts the server's write timeout as long as the client
+	// keeps reading, while one that stops reading is cut off.
+	ndjsonWriteTimeout = 30 * time.Second
+)
+
+// streamEnd is the last line of a complete NDJSON stream. A stream that
+// fails part way ends with an Error line instead, and one that ends with
+// neither was cut off.
+type streamEnd struct {
+	Kind      string `json:"kind"`
+	Total     int    `json:"total"`
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// listFormat returns the format query parameter of a list request: json,
+// the default, or ndjson
+func listFormat(r *http.Request) (string, error) {
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "json":
+		return "json", nil
+	case formatNDJSON:
+		return formatNDJSON, nil
+	default:
+		return "", fmt.Errorf("format must be json or %s", formatNDJSON)
+	}
+}
+
+// ndjsonStream writes a list one item per line. Items are buffered and
+// flushed to the client a batch at a time; as each write blocks until the
+// client has taken the previous bytes, a caller that fetches the next batch
+// only after writing the last one reads no faster than the client does and
+// holds no more than one batch in memory.
+type ndjsonStream struct {
+	rc        *http.ResponseController
+	bw        *bufio.Writer
+	enc       *json.Encoder
+	requestID string
+	total     int
+	err       error
+}
+
+// newNDJSONStream writes the headers of a successful stream. The status
+// cannot change once items are written, so callers must validate the
+// request first.
+func newNDJSONStream(w http.ResponseWriter, r *http.Request) *ndjsonStream {
+	w.Header().Set("Content-Type", mediaTypeNDJSON)
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+
+	bw := bufio.NewWriter(w)
+	return &ndjsonStream{
+		rc:        http.NewResponseController(w),
+		bw:        bw,
+		enc:       json.NewEncoder(bw),
+		requestID: middleware.GetRequestID(r.Context()),
+	}
+}
+
+// Write encodes item as one line. Once a write fails, because the client
+// went away or stopped reading, later writes do nothing and return the same
+// error.
+func (s *ndjsonStream) Write(item any) error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.enc.Encode(item); err != nil {
+		s.err = err
+		return err
+	}
+	s.total++
+	return nil
+}
+
+// Flush sends the buffered lines to the client and gives it another
+// ndjsonWriteTimeout to read the next batch
+func (s *ndjsonStream) Flush() error {
+	if s.err != nil {
+		return s.err
+	}
+	if err := s.bw.Flush(); err != nil {
+		s.err = err
+		return err
+	}
+	if err := s.rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.err = err
+		return err
+	}
+	if err := s.rc.SetWriteDeadline(time.Now().Add(ndjsonWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		s.err = err
+		return err
+	}
+	return nil
+}
+
+// End writes the streamEnd line and flushes the stream
+func (s *ndjsonStream) End() {
+	_ = s.Write(streamEnd{Kind: "StreamEnd", Total: s.total, RequestID: s.requestID})
+	_ = s.Flush()
+}
+
+// Fail ends the stream with an Error line with code and reason, or with
+// stream-timeout when the request ran out of time
+func (s *ndjsonStream) Fail(ctx context.Context, code, reason string) {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		code, reason = "stream-timeout", "The stream did not complete within the request timeout"
+	}
+	_ = s.Write(apiv0.NewError(code, reason))
+	_ = s.Flush()
+}
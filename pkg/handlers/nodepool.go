@@ -7,6 +7,7 @@ import (
 	"strconv"
 
 	"github.com/gorilla/mux"
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 	"github.com/openshift/rosa-regional-platform-api/pkg/types"
@@ -15,6 +16,7 @@ import (
 // NodePoolHandler handles nodepool-related HTTP requests
 type NodePoolHandler struct {
 	maestroClient *maestro.Client
+	pageLimits    PageLimits
 	logger        *slog.Logger
 }
 
@@ -22,28 +24,35 @@ type NodePoolHandler struct {
 func NewNodePoolHandler(maestroClient *maestro.Client, logger *slog.Logger) *NodePoolHandler {
 	return &NodePoolHandler{
 		maestroClient: maestroClient,
+		pageLimits:    DefaultTenantPageLimits,
 		logger:        logger,
 	}
 }
 
+// WithPageLimits sets the default and maximum page size for List
+func (h *NodePoolHandler) WithPageLimits(limits PageLimits) *NodePoolHandler {
+	h.pageLimits = limits
+	return h
+}
+
 // List handles GET /api/v0/nodepools
 func (h *NodePoolHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
 	offsetStr := r.URL.Query().Get("offset")
 	clusterID := r.URL.Query().Get("clusterId")
 
-	limit := 50 // default
-	offset := 0 // default
-
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	limit, err := pageSize(r, "limit", h.pageLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "NODEPOOLS-MGMT-LIST-002", err.Error())
+		return
 	}
+	offset := 0 // default
 
 	if offsetStr != "" {
 		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
@@ -61,25 +70,30 @@ func (h *NodePoolHandler) List(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"items":  nodepools,
-		"total":  total,
-		"limit":  limit,
-		"offset": offset,
+	response := apiv0.NodePoolList{
+		Kind:   "NodePoolList",
+		Items:  emptyIfNil(nodepools),
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
 	}
 
-	h.writeJSON(w, http.StatusOK, response)
+	setOffsetLinks(w, r, offset, limit, total)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
 // Create handles POST /api/v0/nodepools
 func (h *NodePoolHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	userEmail := middleware.GetUserID(ctx) // May be empty if not provided
 
 	var req types.NodePoolCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "NODEPOOLS-MGMT-CREATE-001", "Invalid request body")
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "NODEPOOLS-MGMT-CREATE-001", invalidBodyReason(err))
 		return
 	}
 
@@ -91,6 +105,10 @@ func (h *NodePoolHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("creating nodepool", "account_id", accountID, "cluster_id", req.ClusterID, "nodepool_name", req.Name)
 
+	if writeDryRun(w, r, http.StatusCreated, req) {
+		return
+	}
+
 	nodepool, err := h.maestroClient.CreateNodePool(ctx, accountID, userEmail, &req)
 	if err != nil {
 		h.logger.Error("failed to create nodepool", "error", err, "account_id", accountID)
@@ -98,13 +116,16 @@ func (h *NodePoolHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusCreated, nodepool)
+	writeResponse(w, r, http.StatusCreated, nodepool)
 }
 
 // Get handles GET /api/v0/nodepools/{id}
 func (h *NodePoolHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	nodepoolID := vars["id"]
 
@@ -121,19 +142,22 @@ func (h *NodePoolHandler) Get(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, nodepool)
+	writeResponse(w, r, http.StatusOK, nodepool)
 }
 
 // Update handles PUT /api/v0/nodepools/{id}
 func (h *NodePoolHandler) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	nodepoolID := vars["id"]
 
 	var req types.NodePoolUpdateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "NODEPOOLS-MGMT-UPDATE-001", "Invalid request body")
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "NODEPOOLS-MGMT-UPDATE-001", invalidBodyReason(err))
 		return
 	}
 
@@ -144,6 +168,13 @@ func (h *NodePoolHandler) Update(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Info("updating nodepool", "account_id", accountID, "nodepool_id", nodepoolID)
 
+	if middleware.IsDryRun(ctx) {
+		if _, ok := h.getNodePool(w, r, accountID, nodepoolID, "NODEPOOLS-MGMT-UPDATE-003", "NODEPOOLS-MGMT-UPDATE-004"); ok {
+			writeDryRun(w, r, http.StatusOK, req)
+		}
+		return
+	}
+
 	nodepool, err := h.maestroClient.UpdateNodePool(ctx, accountID, nodepoolID, &req)
 	if err != nil {
 		if maestro.IsNotFound(err) {
@@ -155,18 +186,28 @@ func (h *NodePoolHandler) Update(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, nodepool)
+	writeResponse(w, r, http.StatusOK, nodepool)
 }
 
 // Delete handles DELETE /api/v0/nodepools/{id}
 func (h *NodePoolHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	nodepoolID := vars["id"]
 
 	h.logger.Info("deleting nodepool", "account_id", accountID, "nodepool_id", nodepoolID)
 
+	if middleware.IsDryRun(ctx) {
+		if nodepool, ok := h.getNodePool(w, r, accountID, nodepoolID, "NODEPOOLS-MGMT-DELETE-001", "NODEPOOLS-MGMT-DELETE-002"); ok {
+			writeDryRun(w, r, http.StatusAccepted, nodepool)
+		}
+		return
+	}
+
 	err := h.maestroClient.DeleteNodePool(ctx, accountID, nodepoolID)
 	if err != nil {
 		if maestro.IsNotFound(err) {
@@ -178,18 +219,21 @@ func (h *NodePoolHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"message":     "NodePool deletion initiated",
-		"nodepool_id": nodepoolID,
+	response := apiv0.NodePoolDeletion{
+		Message:    "NodePool deletion initiated",
+		NodePoolID: nodepoolID,
 	}
 
-	h.writeJSON(w, http.StatusAccepted, response)
+	writeResponse(w, r, http.StatusAccepted, response)
 }
 
 // GetStatus handles GET /api/v0/nodepools/{id}/status
 func (h *NodePoolHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	vars := mux.Vars(r)
 	nodepoolID := vars["id"]
 
@@ -206,23 +250,28 @@ func (h *NodePoolHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h.writeJSON(w, http.StatusOK, status)
+	writeResponse(w, r, http.StatusOK, status)
 }
 
 // Helper methods
-func (h *NodePoolHandler) writeJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(data)
+// getNodePool looks a nodepool up for a dry run, answering with the codes of
+// the operation it stands in for when it cannot
+func (h *NodePoolHandler) getNodePool(w http.ResponseWriter, r *http.Request, accountID, nodepoolID, notFoundCode, errorCode string) (*types.NodePool, bool) {
+	nodepool, err := h.maestroClient.GetNodePool(r.Context(), accountID, nodepoolID)
+	if err != nil {
+		if maestro.IsNotFound(err) {
+			h.writeError(w, http.StatusNotFound, notFoundCode, "NodePool not found")
+			return nil, false
+		}
+		h.logger.Error("failed to get nodepool", "error", err, "account_id", accountID, "nodepool_id", nodepoolID)
+		h.writeError(w, http.StatusInternalServerError, errorCode, "Failed to get nodepool")
+		return nil, false
+	}
+	return nodepool, true
 }
 
 func (h *NodePoolHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	resp := map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
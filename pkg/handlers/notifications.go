@@ -0,0 +1,296 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/notify"
+)
+
+// NotificationSettingsStore keeps per-account notification settings
+type NotificationSettingsStore interface {
+	Get(ctx context.Context, accountID string) (*notify.Settings, error)
+	Put(ctx context.Context, settings *notify.Settings) error
+	Delete(ctx context.Context, accountID string) (bool, error)
+}
+
+// NotificationSender validates settings and delivers events to them
+type NotificationSender interface {
+	Validate(settings *notify.Settings) error
+	Notify(ctx context.Context, event *notify.Event) ([]notify.Delivery, error)
+}
+
+// WithNotifications enables the account notification settings endpoints
+func (h *AccountsHandler) WithNotifications(settings NotificationSettingsStore, sender NotificationSender) *AccountsHandler {
+	h.notifications = settings
+	h.notifier = sender
+	return h
+}
+
+// GetNotifications handles GET /api/v0/accounts/{id}/notifications
+func (h *AccountsHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	if !h.notificationsEnabled(w) {
+		return
+	}
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	settings, err := h.notifications.Get(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get notification settings", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get notification settings")
+		return
+	}
+	if settings == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account has no notification settings")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, notificationSettingsResponse(settings))
+}
+
+// PutNotifications handles PUT /api/v0/accounts/{id}/notifications
+// The body replaces the account's notification settings.
+func (h *AccountsHandler) PutNotifications(w http.ResponseWriter, r *http.Request) {
+	if !h.notificationsEnabled(w) {
+		return
+	}
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	var settings notify.Settings
+	if err := decodeJSON(r, &settings); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if err := h.notifier.Validate(&settings); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-notifications", err.Error())
+		return
+	}
+
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get account")
+		return
+	}
+	if account == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+
+	// Only a rotation starts an overlap; a replaced secret is retired
+	if settings.Webhook != nil {
+		settings.Webhook.PreviousSecretExpiresAt = ""
+	}
+	settings.AccountID = accountID
+	settings.UpdatedBy = middleware.GetCallerARN(ctx)
+	if writeDryRun(w, r, http.StatusOK, notificationSettingsResponse(&settings)) {
+		return
+	}
+	if err := h.notifications.Put(ctx, &settings); err != nil {
+		h.logger.Error("failed to put notification settings", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update notification settings")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, notificationSettingsResponse(&settings))
+}
+
+// DeleteNotifications handles DELETE /api/v0/accounts/{id}/notifications
+func (h *AccountsHandler) DeleteNotifications(w http.ResponseWriter, r *http.Request) {
+	if !h.notificationsEnabled(w) {
+		return
+	}
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	if middleware.IsDryRun(ctx) {
+		settings, err := h.notifications.Get(ctx, accountID)
+		if err != nil {
+			h.logger.Error("failed to get notification settings", "error", err, "account_id", accountID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete notification settings")
+			return
+		}
+		if settings == nil {
+			h.writeError(w, http.StatusNotFound, "not-found", "Account has no notification settings")
+			return
+		}
+		writeDryRun(w, r, http.StatusNoContent, notificationSettingsResponse(settings))
+		return
+	}
+
+	deleted, err := h.notifications.Delete(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to delete notification settings", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete notification settings")
+		return
+	}
+	if !deleted {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account has no notification settings")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// RotateWebhookSecret handles
+// POST /api/v0/accounts/{id}/notifications/webhook/rotate_secret
+// It replaces the webhook secret, generating one unless the body names it,
+// and returns it once. With an overlap, requests are also signed with the
+// old secret until the overlap ends.
+func (h *AccountsHandler) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	if !h.notificationsEnabled(w) {
+		return
+	}
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	// The body is optional: without one a secret is generated and the old
+	// one retired at once
+	var req apiv0.RotateWebhookSecretRequest
+	if err := decodeJSON(r, &req); err != nil && !errors.Is(err, io.EOF) {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	var overlap time.Duration
+	if req.Overlap != "" {
+		var err error
+		overlap, err = time.ParseDuration(req.Overlap)
+		if err != nil || overlap < 0 || overlap > notify.MaxSecretOverlap {
+			h.writeError(w, http.StatusBadRequest, "invalid-overlap", "overlap must be a duration between 0s and "+notify.MaxSecretOverlap.String())
+			return
+		}
+	}
+
+	settings, err := h.notifications.Get(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get notification settings", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get notification settings")
+		return
+	}
+	if settings == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account has no notification settings")
+		return
+	}
+	if settings.Webhook == nil {
+		h.writeError(w, http.StatusConflict, "no-webhook", "Account has no webhook channel")
+		return
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		if secret, err = newWebhookSecret(); err != nil {
+			h.logger.Error("failed to generate webhook secret", "error", err, "account_id", accountID)
+			h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to rotate webhook secret")
+			return
+		}
+	}
+	settings.Webhook.RotateSecret(secret, overlap, time.Now())
+	settings.UpdatedBy = middleware.GetCallerARN(ctx)
+
+	resp := apiv0.WebhookSecretResponse{
+		Kind:                    "WebhookSecret",
+		AccountID:               accountID,
+		Secret:                  secret,
+		PreviousSecretExpiresAt: settings.Webhook.PreviousSecretExpiresAt,
+	}
+	// A dry run shows when the old secret would be retired, not the secret
+	if middleware.IsDryRun(ctx) {
+		resp.Secret = ""
+		writeDryRun(w, r, http.StatusOK, resp)
+		return
+	}
+	if err := h.notifications.Put(ctx, settings); err != nil {
+		h.logger.Error("failed to put notification settings", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to rotate webhook secret")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+// newWebhookSecret returns a random 256-bit hex-encoded webhook secret
+func newWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// TestNotifications handles POST /api/v0/accounts/{id}/notifications/test
+// It sends a test event to every configured channel and reports the outcome
+// of each delivery.
+func (h *AccountsHandler) TestNotifications(w http.ResponseWriter, r *http.Request) {
+	if !h.notificationsEnabled(w) {
+		return
+	}
+	ctx := r.Context()
+	accountID := mux.Vars(r)["id"]
+
+	settings, err := h.notifications.Get(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get notification settings", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get notification settings")
+		return
+	}
+	if settings == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account has no notification settings")
+		return
+	}
+
+	event := &notify.Event{
+		Type:      notify.EventTest,
+		AccountID: accountID,
+		Subject:   "ROSA notification test",
+		Message:   "This is a test notification for account " + accountID + ".",
+		Details:   map[string]string{"requestedBy": middleware.GetCallerARN(ctx)},
+	}
+	// A dry run shows the event without sending it
+	if writeDryRun(w, r, http.StatusOK, event) {
+		return
+	}
+
+	deliveries, err := h.notifier.Notify(ctx, event)
+	if err != nil {
+		h.logger.Error("failed to send test notification", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to send test notification")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.NotificationTestResponse{
+		Kind:       "NotificationTest",
+		AccountID:  accountID,
+		Deliveries: deliveries,
+	})
+}
+
+func (h *AccountsHandler) notificationsEnabled(w http.ResponseWriter) bool {
+	if h.notifications == nil {
+		h.writeError(w, http.StatusNotFound, "notifications-disabled", "Notifications are not enabled")
+		return false
+	}
+	return true
+}
+
+// notificationSettingsResponse copies settings with the webhook secret
+// removed
+func notificationSettingsResponse(settings *notify.Settings) apiv0.NotificationSettingsResponse {
+	redacted := *settings
+	if settings.Webhook != nil {
+		webhook := *settings.Webhook
+		webhook.Secret = ""
+		redacted.Webhook = &webhook
+	}
+	return apiv0.NotificationSettingsResponse{Kind: "NotificationSettings", Settings: &redacted}
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+)
+
+// OperationsHandler handles the endpoints for long-running operations.
+// Privileged callers see every operation; others only their account's.
+type OperationsHandler struct {
+	registry *operations.Registry
+	logger   *slog.Logger
+}
+
+// NewOperationsHandler creates a new OperationsHandler
+func NewOperationsHandler(registry *operations.Registry, logger *slog.Logger) *OperationsHandler {
+	return &OperationsHandler{
+		registry: registry,
+		logger:   logger,
+	}
+}
+
+// List handles GET /api/v0/operations and GET /api/v0/admin/operations. The
+// account_id, type, state and cluster_id query parameters filter the
+// operations.
+func (h *OperationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	filters := map[string]func(operations.Operation) string{
+		"account_id": func(op operations.Operation) string { return op.AccountID },
+		"type":       func(op operations.Operation) string { return op.Type },
+		"state":      func(op operations.Operation) string { return op.State },
+		"cluster_id": func(op operations.Operation) string { return op.ClusterID },
+	}
+
+	items := make([]apiv0.OperationResponse, 0)
+	for _, op := range h.registry.List() {
+		matches := visible(r, op)
+		for param, field := range filters {
+			if v := query.Get(param); v != "" && field(op) != v {
+				matches = false
+			}
+		}
+		if matches {
+			items = append(items, apiv0.OperationResponse{Kind: "Operation", Operation: op})
+		}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.OperationListResponse{
+		Kind:  "OperationList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// Get handles GET /api/v0/operations/{id} and GET /api/v0/admin/operations/{id}
+func (h *OperationsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	op, ok := h.registry.Get(mux.Vars(r)["id"])
+	if !ok || !visible(r, op) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Operation not found")
+		return
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.OperationResponse{Kind: "Operation", Operation: op})
+}
+
+// Cancel handles DELETE /api/v0/operations/{id} and DELETE
+// /api/v0/admin/operations/{id}. The operation is cancelled asynchronously:
+// a work submission's request answers operation-cancelled once it has
+// stopped, rolling back any chunks of a group it created, and other
+// operations end as cancelled.
+func (h *OperationsHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	operationID := mux.Vars(r)["id"]
+
+	op, ok := h.registry.Get(operationID)
+	if !ok || !visible(r, op) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Operation not found")
+		return
+	}
+	if operations.Finished(op.State) {
+		h.writeError(w, http.StatusConflict, "operation-finished", "Operation has already "+op.State)
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		op.State = operations.StateCancelling
+		writeDryRun(w, r, http.StatusAccepted, apiv0.OperationResponse{Kind: "Operation", Operation: op})
+		return
+	}
+
+	op, ok = h.registry.Cancel(operationID)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, "not-found", "Operation not found")
+		return
+	}
+
+	h.logger.Info("cancelled operation",
+		"operation_id", op.ID,
+		"type", op.Type,
+		"account_id", op.AccountID,
+		"cluster_id", op.ClusterID,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	writeResponse(w, r, http.StatusAccepted, apiv0.OperationResponse{Kind: "Operation", Operation: op})
+}
+
+// visible reports whether the caller may see op: privileged callers see
+// every operation, others their account's
+func visible(r *http.Request, op operations.Operation) bool {
+	ctx := r.Context()
+	return middleware.GetPrivileged(ctx) || op.AccountID == middleware.GetAccountID(ctx)
+}
+
+func (h *OperationsHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+)
+
+func TestOperationsHandler_CancelWorkSubmission(t *testing.T) {
+	submitting := make(chan struct{})
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			// Hang like an unresponsive Maestro until the submission is cancelled
+			close(submitting)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	registry := operations.NewRegistry()
+	workHandler := NewWorkHandler(mockClient, WorkConfig{Operations: registry}, logger)
+	opsHandler := NewOperationsHandler(registry, logger)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "runaway"},
+			"spec":       map[string]interface{}{"workload": map[string]interface{}{"manifests": []interface{}{}}},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+	created := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		workHandler.Create(created, req)
+		close(done)
+	}()
+	<-submitting
+
+	w := httptest.NewRecorder()
+	opsHandler.List(w, withAccount(httptest.NewRequest(http.MethodGet, "/api/v0/admin/operations?account_id=test-account-123", nil), "admin-account", true))
+	var list apiv0.OperationListResponse
+	_ = json.NewDecoder(w.Body).Decode(&list)
+	if list.Total != 1 || list.Items[0].State != operations.StateRunning || list.Items[0].Name != "runaway" {
+		t.Fatalf("Expected the running submission to be listed, got %+v", list)
+	}
+	operationID := list.Items[0].ID
+
+	w = httptest.NewRecorder()
+	opsHandler.List(w, withAccount(httptest.NewRequest(http.MethodGet, "/api/v0/admin/operations?account_id=other", nil), "admin-account", true))
+	_ = json.NewDecoder(w.Body).Decode(&list)
+	if list.Total != 0 {
+		t.Errorf("Expected operations of other accounts to be filtered out, got %+v", list)
+	}
+
+	cancelReq := mux.SetURLVars(withAccount(httptest.NewRequest(http.MethodDelete, "/api/v0/admin/operations/"+operationID, nil), "admin-account", true), map[string]string{"id": operationID})
+	w = httptest.NewRecorder()
+	opsHandler.Cancel(w, cancelReq)
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusAccepted, w.Code, w.Body.String())
+	}
+
+	<-done
+	if created.Code != http.StatusConflict {
+		t.Fatalf("Expected the cancelled submission to answer %d, got %d: %s", http.StatusConflict, created.Code, created.Body.String())
+	}
+	var resp map[string]interface{}
+	_ = json.NewDecoder(created.Body).Decode(&resp)
+	if resp["code"] != "operation-cancelled" {
+		t.Errorf("Expected code operation-cancelled, got %v", resp["code"])
+	}
+	if ops := registry.List(); len(ops) != 0 {
+		t.Errorf("Expected the cancelled operation to end, got %+v", ops)
+	}
+
+	w = httptest.NewRecorder()
+	opsHandler.Cancel(w, cancelReq)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an ended operation, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestOperationsHandler_AccountScope(t *testing.T) {
+	registry := operations.NewRegistry().WithResultTTL(time.Hour)
+	opsHandler := NewOperationsHandler(registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	finished := registry.Go(context.Background(), operations.Operation{Type: operations.TypeAccountPurge, State: operations.StateRunning, AccountID: "111111111111"},
+		func(ctx context.Context) (any, error) { return map[string]int{"deleted": 3}, nil })
+	_, end := registry.Start(context.Background(), operations.Operation{Type: operations.TypeWorkSubmission, State: operations.StateRunning, AccountID: "222222222222"})
+	defer end()
+
+	get := func(id, account string) *httptest.ResponseRecorder {
+		req := mux.SetURLVars(withAccount(httptest.NewRequest(http.MethodGet, "/api/v0/operations/"+id, nil), account, false), map[string]string{"id": id})
+		w := httptest.NewRecorder()
+		opsHandler.Get(w, req)
+		return w
+	}
+	var op apiv0.OperationResponse
+	for range 100 {
+		w := get(finished.ID, "111111111111")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		_ = json.NewDecoder(w.Body).Decode(&op)
+		if operations.Finished(op.State) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if op.State != operations.StateSucceeded || op.Result == nil {
+		t.Fatalf("Expected the purge to have succeeded with a result, got %+v", op)
+	}
+	if w := get(finished.ID, "222222222222"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected another account's operation to be hidden, got %d", w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	opsHandler.List(w, withAccount(httptest.NewRequest(http.MethodGet, "/api/v0/operations", nil), "222222222222", false))
+	var list apiv0.OperationListResponse
+	_ = json.NewDecoder(w.Body).Decode(&list)
+	if list.Total != 1 || list.Items[0].AccountID != "222222222222" {
+		t.Errorf("Expected only the caller's operation, got %+v", list)
+	}
+
+	w = httptest.NewRecorder()
+	opsHandler.Cancel(w, mux.SetURLVars(withAccount(httptest.NewRequest(http.MethodDelete, "/api/v0/operations/"+finished.ID, nil), "111111111111", false), map[string]string{"id": finished.ID}))
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status code %d cancelling a finished operation, got %d", http.StatusConflict, w.Code)
+	}
+}
@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// OrganizationsHandler handles organization management endpoints
+type OrganizationsHandler struct {
+	authorizer authz.Service
+	logger     *slog.Logger
+}
+
+// NewOrganizationsHandler creates a new OrganizationsHandler
+func NewOrganizationsHandler(authorizer authz.Service, logger *slog.Logger) *OrganizationsHandler {
+	return &OrganizationsHandler{
+		authorizer: authorizer,
+		logger:     logger,
+	}
+}
+
+// Create handles POST /api/v0/organizations
+func (h *OrganizationsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	callerARN := middleware.GetCallerARN(ctx)
+
+	var req apiv0.CreateOrganizationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-name", "name is required")
+		return
+	}
+
+	if writeDryRun(w, r, http.StatusCreated, apiv0.OrganizationResponse{
+		Kind:         "Organization",
+		Organization: &store.Organization{Name: req.Name, CreatedBy: callerARN},
+	}) {
+		return
+	}
+
+	org, err := h.authorizer.CreateOrganization(ctx, req.Name, callerARN)
+	if err != nil {
+		h.logger.Error("failed to create organization", "error", err, "name", req.Name)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to create organization")
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, apiv0.OrganizationResponse{Kind: "Organization", Organization: org})
+}
+
+// List handles GET /api/v0/organizations
+func (h *OrganizationsHandler) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	orgs, err := h.authorizer.ListOrganizations(ctx)
+	if err != nil {
+		h.logger.Error("failed to list organizations", "error", err)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list organizations")
+		return
+	}
+
+	items := make([]apiv0.OrganizationResponse, len(orgs))
+	for i, org := range orgs {
+		items[i] = apiv0.OrganizationResponse{Kind: "Organization", Organization: org}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.OrganizationListResponse{
+		Kind:  "OrganizationList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// Get handles GET /api/v0/organizations/{id}
+func (h *OrganizationsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID := mux.Vars(r)["id"]
+
+	org, err := h.authorizer.GetOrganization(ctx, orgID)
+	if err != nil {
+		h.logger.Error("failed to get organization", "error", err, "organization_id", orgID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get organization")
+		return
+	}
+	if org == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.OrganizationResponse{Kind: "Organization", Organization: org})
+}
+
+// Delete handles DELETE /api/v0/organizations/{id}
+func (h *OrganizationsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID := mux.Vars(r)["id"]
+
+	h.logger.Info("deleting organization", "organization_id", orgID, "caller_arn", middleware.GetCallerARN(ctx))
+
+	if middleware.IsDryRun(ctx) {
+		h.dryRunDelete(w, r, orgID)
+		return
+	}
+
+	err := h.authorizer.DeleteOrganization(ctx, orgID)
+	switch {
+	case errors.Is(err, authz.ErrOrganizationNotFound):
+		h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+		return
+	case errors.Is(err, authz.ErrOrganizationHasMembers):
+		h.writeError(w, http.StatusConflict, "organization-has-members", "Remove all member accounts before deleting the organization")
+		return
+	case err != nil:
+		h.logger.Error("failed to delete organization", "error", err, "organization_id", orgID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete organization")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// CreatePolicy handles POST /api/v0/organizations/{id}/policies
+func (h *OrganizationsHandler) CreatePolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID := mux.Vars(r)["id"]
+
+	var req apiv0.CreateOrganizationPolicyRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if strings.TrimSpace(req.CedarPolicy) == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-cedar-policy", "cedarPolicy is required")
+		return
+	}
+
+	if middleware.IsDryRun(ctx) {
+		if _, ok := h.getOrganization(w, ctx, orgID); !ok {
+			return
+		}
+		if err := h.authorizer.ValidatePolicy(req.CedarPolicy); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-policy", "Failed to create policy: "+err.Error())
+			return
+		}
+		writeDryRun(w, r, http.StatusCreated, apiv0.OrganizationPolicyResponse{
+			Kind: "OrganizationPolicy",
+			OrganizationPolicy: &store.OrganizationPolicy{
+				OrganizationID: orgID,
+				StaticPolicy:   store.StaticPolicy{Description: req.Description, CedarPolicy: req.CedarPolicy},
+			},
+		})
+		return
+	}
+
+	policy, err := h.authorizer.CreateOrganizationPolicy(ctx, orgID, req.Description, req.CedarPolicy)
+	if err != nil {
+		if errors.Is(err, authz.ErrOrganizationNotFound) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+			return
+		}
+		h.logger.Error("failed to create organization policy", "error", err, "organization_id", orgID)
+		h.writeError(w, http.StatusBadRequest, "invalid-policy", "Failed to create policy: "+err.Error())
+		return
+	}
+
+	writeResponse(w, r, http.StatusCreated, apiv0.OrganizationPolicyResponse{Kind: "OrganizationPolicy", OrganizationPolicy: policy})
+}
+
+// ListPolicies handles GET /api/v0/organizations/{id}/policies
+func (h *OrganizationsHandler) ListPolicies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	orgID := mux.Vars(r)["id"]
+
+	policies, err := h.authorizer.ListOrganizationPolicies(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, authz.ErrOrganizationNotFound) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+			return
+		}
+		h.logger.Error("failed to list organization policies", "error", err, "organization_id", orgID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list organization policies")
+		return
+	}
+
+	items := make([]apiv0.OrganizationPolicyResponse, len(policies))
+	for i, p := range policies {
+		items[i] = apiv0.OrganizationPolicyResponse{Kind: "OrganizationPolicy", OrganizationPolicy: p}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.OrganizationPolicyListResponse{
+		Kind:  "OrganizationPolicyList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// DeletePolicy handles DELETE /api/v0/organizations/{id}/policies/{policyId}
+func (h *OrganizationsHandler) DeletePolicy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	vars := mux.Vars(r)
+	orgID := vars["id"]
+	policyID := vars["policyId"]
+
+	if middleware.IsDryRun(ctx) {
+		if _, ok := h.getOrganization(w, ctx, orgID); ok {
+			writeDryRun(w, r, http.StatusNoContent, nil)
+		}
+		return
+	}
+
+	if err := h.authorizer.DeleteOrganizationPolicy(ctx, orgID, policyID); err != nil {
+		if errors.Is(err, authz.ErrOrganizationNotFound) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+			return
+		}
+		h.logger.Error("failed to delete organization policy", "error", err, "organization_id", orgID, "policy_id", policyID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete organization policy")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetAccountOrganization handles PUT /api/v0/accounts/{id}/organization
+func (h *OrganizationsHandler) SetAccountOrganization(w http.ResponseWriter, r *http.Request) {
+	accountID := mux.Vars(r)["id"]
+
+	var req apiv0.SetAccountOrganizationRequest
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
+		return
+	}
+	if req.OrganizationID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-organization-id", "organizationId is required")
+		return
+	}
+
+	h.setAccountOrganization(w, r, accountID, req.OrganizationID)
+}
+
+// RemoveAccountOrganization handles DELETE /api/v0/accounts/{id}/organization
+func (h *OrganizationsHandler) RemoveAccountOrganization(w http.ResponseWriter, r *http.Request) {
+	h.setAccountOrganization(w, r, mux.Vars(r)["id"], "")
+}
+
+func (h *OrganizationsHandler) setAccountOrganization(w http.ResponseWriter, r *http.Request, accountID, orgID string) {
+	ctx := r.Context()
+
+	h.logger.Info("setting account organization",
+		"account_id", accountID,
+		"organization_id", orgID,
+		"caller_arn", middleware.GetCallerARN(ctx),
+	)
+
+	if middleware.IsDryRun(ctx) {
+		h.dryRunSetAccountOrganization(w, r, accountID, orgID)
+		return
+	}
+
+	err := h.authorizer.SetAccountOrganization(ctx, accountID, orgID)
+	switch {
+	case errors.Is(err, authz.ErrAccountNotEnabled):
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	case errors.Is(err, authz.ErrOrganizationNotFound):
+		h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+		return
+	case errors.Is(err, authz.ErrPrivilegedOrganizationMember):
+		h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts cannot join an organization")
+		return
+	case err != nil:
+		h.logger.Error("failed to set account organization", "error", err, "account_id", accountID, "organization_id", orgID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update account organization")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dryRunDelete checks the organization exists and has no members
+func (h *OrganizationsHandler) dryRunDelete(w http.ResponseWriter, r *http.Request, orgID string) {
+	ctx := r.Context()
+	org, ok := h.getOrganization(w, ctx, orgID)
+	if !ok {
+		return
+	}
+	accounts, err := h.authorizer.ListAccounts(ctx)
+	if err != nil {
+		h.logger.Error("failed to list accounts", "error", err, "organization_id", orgID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to delete organization")
+		return
+	}
+	for _, account := range accounts {
+		if account.OrganizationID == orgID {
+			h.writeError(w, http.StatusConflict, "organization-has-members", "Remove all member accounts before deleting the organization")
+			return
+		}
+	}
+	writeDryRun(w, r, http.StatusNoContent, apiv0.OrganizationResponse{Kind: "Organization", Organization: org})
+}
+
+// dryRunSetAccountOrganization checks what SetAccountOrganization would
+// reject
+func (h *OrganizationsHandler) dryRunSetAccountOrganization(w http.ResponseWriter, r *http.Request, accountID, orgID string) {
+	ctx := r.Context()
+	account, err := h.authorizer.GetAccount(ctx, accountID)
+	if err != nil {
+		h.logger.Error("failed to get account", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to update account organization")
+		return
+	}
+	if account == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Account not found")
+		return
+	}
+	if orgID != "" {
+		if account.Privileged {
+			h.writeError(w, http.StatusBadRequest, "privileged-account", "Privileged accounts cannot join an organization")
+			return
+		}
+		if _, ok := h.getOrganization(w, ctx, orgID); !ok {
+			return
+		}
+	}
+	account.OrganizationID = orgID
+	writeDryRun(w, r, http.StatusNoContent, accountResponse(account))
+}
+
+// getOrganization returns an organization for a dry run, answering 404 when
+// it does not exist
+func (h *OrganizationsHandler) getOrganization(w http.ResponseWriter, ctx context.Context, orgID string) (*store.Organization, bool) {
+	org, err := h.authorizer.GetOrganization(ctx, orgID)
+	if err != nil {
+		h.logger.Error("failed to get organization", "error", err, "organization_id", orgID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get organization")
+		return nil, false
+	}
+	if org == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Organization not found")
+		return nil, false
+	}
+	return org, true
+}
+
+func (h *OrganizationsHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// writeApprovalRequired answers 202 Accepted with the pending change when err
+// says the operation was queued for approval, and reports whether it did
+func writeApprovalRequired(w http.ResponseWriter, r *http.Request, err error) bool {
+	var approvalErr *authz.ApprovalRequiredError
+	if !errors.As(err, &approvalErr) {
+		return false
+	}
+	writeResponse(w, r, http.StatusAccepted, apiv0.PendingChangeResponse{
+		Kind:          "PendingChange",
+		PendingChange: approvalErr.Change,
+	})
+	return true
+}
+
+// pendingChanges serves the pending change endpoints for an account. Tenant
+// admins see only the operations their account approves itself (see
+// authz.IsAccountOperation); privileged callers see all of them.
+type pendingChanges struct {
+	service               authz.Service
+	logger                *slog.Logger
+	writeError            func(w http.ResponseWriter, status int, code, reason string)
+	accountOperationsOnly bool
+}
+
+func (p *pendingChanges) visible(change *store.PendingChange) bool {
+	return !p.accountOperationsOnly || authz.IsAccountOperation(change.Operation)
+}
+
+func (p *pendingChanges) list(w http.ResponseWriter, r *http.Request, accountID string) {
+	changes, err := p.service.ListPendingChanges(r.Context(), accountID)
+	if err != nil {
+		p.logger.Error("failed to list pending changes", "error", err, "account_id", accountID)
+		p.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list pending changes")
+		return
+	}
+
+	items := make([]*store.PendingChange, 0, len(changes))
+	for _, change := range changes {
+		if p.visible(change) {
+			items = append(items, change)
+		}
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.PendingChangeListResponse{
+		Kind:  "PendingChangeList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// get returns the change named in the path, having answered 404 if it is
+// missing or not visible to the caller
+func (p *pendingChanges) get(w http.ResponseWriter, r *http.Request, accountID string) (*store.PendingChange, bool) {
+	changeID := mux.Vars(r)["changeId"]
+	change, err := p.service.GetPendingChange(r.Context(), accountID, changeID)
+	if err == nil && !p.visible(change) {
+		err = store.ErrPendingChangeNotFound
+	}
+	if errors.Is(err, store.ErrPendingChangeNotFound) {
+		p.writeError(w, http.StatusNotFound, "not-found", "Pending change not found")
+		return nil, false
+	}
+	if err != nil {
+		p.logger.Error("failed to get pending change", "error", err, "account_id", accountID, "change_id", changeID)
+		p.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get pending change")
+		return nil, false
+	}
+	return change, true
+}
+
+func (p *pendingChanges) approve(w http.ResponseWriter, r *http.Request, accountID string) {
+	ctx := visibilityContext(r)
+	change, ok := p.get(w, r, accountID)
+	if !ok {
+		return
+	}
+	if middleware.IsDryRun(ctx) && change.RequestedBy == middleware.GetCallerARN(ctx) {
+		p.writeError(w, http.StatusForbidden, "self-approval", authz.ErrSelfApproval.Error())
+		return
+	}
+	if writeDryRun(w, r, http.StatusOK, apiv0.PendingChangeResponse{Kind: "PendingChange", PendingChange: change}) {
+		return
+	}
+
+	approved, err := p.service.ApproveChange(ctx, accountID, change.ChangeID, middleware.GetCallerARN(ctx))
+	status, err := visibilityStatus(http.StatusOK, err)
+	switch {
+	case errors.Is(err, authz.ErrSelfApproval):
+		p.writeError(w, http.StatusForbidden, "self-approval", err.Error())
+		return
+	case errors.Is(err, store.ErrPendingChangeNotFound):
+		p.writeError(w, http.StatusNotFound, "not-found", "Pending change not found")
+		return
+	case errors.Is(err, store.ErrAdminNotFound):
+		p.writeError(w, http.StatusConflict, "change-failed", "Admin no longer exists")
+		return
+	case err != nil && err.Error() == "cannot delete policy with existing attachments":
+		p.writeError(w, http.StatusConflict, "policy-in-use", err.Error())
+		return
+	case err != nil:
+		p.logger.Error("failed to apply pending change", "error", err, "account_id", accountID, "change_id", change.ChangeID)
+		p.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to apply pending change")
+		return
+	}
+
+	writeResponse(w, r, status, apiv0.PendingChangeResponse{
+		Kind:          "PendingChange",
+		PendingChange: approved,
+	})
+}
+
+func (p *pendingChanges) reject(w http.ResponseWriter, r *http.Request, accountID string) {
+	ctx := r.Context()
+	change, ok := p.get(w, r, accountID)
+	if !ok {
+		return
+	}
+	if writeDryRun(w, r, http.StatusNoContent, apiv0.PendingChangeResponse{Kind: "PendingChange", PendingChange: change}) {
+		return
+	}
+
+	err := p.service.RejectChange(ctx, accountID, change.ChangeID, middleware.GetCallerARN(ctx))
+	if errors.Is(err, store.ErrPendingChangeNotFound) {
+		p.writeError(w, http.StatusNotFound, "not-found", "Pending change not found")
+		return
+	}
+	if err != nil {
+		p.logger.Error("failed to reject pending change", "error", err, "account_id", accountID, "change_id", change.ChangeID)
+		p.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to reject pending change")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *AuthzHandler) pendingChanges() *pendingChanges {
+	return &pendingChanges{service: h.service, logger: h.logger, writeError: h.writeError, accountOperationsOnly: true}
+}
+
+// ListPendingChanges handles GET /api/v0/authz/pending_changes
+func (h *AuthzHandler) ListPendingChanges(w http.ResponseWriter, r *http.Request) {
+	if accountID, ok := middleware.MustGetAccountID(w, r); ok {
+		h.pendingChanges().list(w, r, accountID)
+	}
+}
+
+// ApprovePendingChange handles POST /api/v0/authz/pending_changes/{changeId}/approve
+func (h *AuthzHandler) ApprovePendingChange(w http.ResponseWriter, r *http.Request) {
+	if accountID, ok := middleware.MustGetAccountID(w, r); ok {
+		h.pendingChanges().approve(w, r, accountID)
+	}
+}
+
+// RejectPendingChange handles POST /api/v0/authz/pending_changes/{changeId}/reject
+func (h *AuthzHandler) RejectPendingChange(w http.ResponseWriter, r *http.Request) {
+	if accountID, ok := middleware.MustGetAccountID(w, r); ok {
+		h.pendingChanges().reject(w, r, accountID)
+	}
+}
+
+func (h *AccountsHandler) pendingChanges() *pendingChanges {
+	return &pendingChanges{service: h.authorizer, logger: h.logger, writeError: h.writeError}
+}
+
+// ListPendingChanges handles GET /api/v0/accounts/{id}/pending_changes
+func (h *AccountsHandler) ListPendingChanges(w http.ResponseWriter, r *http.Request) {
+	h.pendingChanges().list(w, r, mux.Vars(r)["id"])
+}
+
+// ApprovePendingChange handles POST /api/v0/accounts/{id}/pending_changes/{changeId}/approve
+func (h *AccountsHandler) ApprovePendingChange(w http.ResponseWriter, r *http.Request) {
+	h.pendingChanges().approve(w, r, mux.Vars(r)["id"])
+}
+
+// RejectPendingChange handles POST /api/v0/accounts/{id}/pending_changes/{changeId}/reject
+func (h *AccountsHandler) RejectPendingChange(w http.ResponseWriter, r *http.Request) {
+	h.pendingChanges().reject(w, r, mux.Vars(r)["id"])
+}
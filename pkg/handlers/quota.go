@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+// RequestCounter reports the requests an account made in the current rate
+// limit window, such as the RateLimit middleware
+type RequestCounter interface {
+	Used(ctx context.Context, plan, accountID string) (int, error)
+}
+
+// QuotaHandler reports the caller's plan, its limits and how much of them
+// the caller uses
+type QuotaHandler struct {
+	window   atomic.Int64
+	requests RequestCounter
+	logger   *slog.Logger
+}
+
+// NewQuotaHandler creates a new QuotaHandler for rate limits counted per
+// window
+func NewQuotaHandler(window time.Duration, logger *slog.Logger) *QuotaHandler {
+	h := &QuotaHandler{logger: logger}
+	h.window.Store(int64(window))
+	return h
+}
+
+// SetWindow changes the reported rate limit window, when the configuration
+// is reloaded
+func (h *QuotaHandler) SetWindow(window time.Duration) {
+	h.window.Store(int64(window))
+}
+
+// WithRequestCounter reports the requests made in the current window, when
+// rate limiting is enabled
+func (h *QuotaHandler) WithRequestCounter(requests RequestCounter) *QuotaHandler {
+	h.requests = requests
+	return h
+}
+
+// Get handles GET /api/v0/quota
+func (h *QuotaHandler) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	plan, ok := middleware.GetPlan(ctx)
+	if !ok {
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "No plan was resolved for the account")
+		return
+	}
+
+	resp := apiv0.QuotaResponse{
+		Kind:      "Quota",
+		AccountID: accountID,
+		Plan:      plan.Plan,
+		Limits: apiv0.QuotaLimits{
+			RequestsPerWindow: plan.RateLimit,
+			MaxManifests:      plan.MaxManifests,
+			BulkSubmission:    plan.BulkSubmission,
+		},
+	}
+	if plan.RateLimit > 0 {
+		resp.Limits.RateLimitWindow = time.Duration(h.window.Load()).String()
+	}
+
+	if h.requests != nil {
+		used, err := h.requests.Used(ctx, plan.Plan, accountID)
+		switch {
+		case errors.Is(err, ratelimit.ErrNotCounted):
+		case err != nil:
+			// Usage is informational; report the limits without it
+			h.logger.Warn("failed to read request usage", "error", err, "account_id", accountID)
+		default:
+			resp.Usage.Requests = &used
+		}
+	}
+
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func (h *QuotaHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+// fixedCounter reports the same usage for every account
+type fixedCounter struct {
+	used int
+	err  error
+	plan string
+}
+
+func (c *fixedCounter) Used(ctx context.Context, plan, accountID string) (int, error) {
+	c.plan = plan
+	return c.used, c.err
+}
+
+func quotaRequest(plan *plans.Limits) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/quota", nil)
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012")
+	if plan != nil {
+		ctx = context.WithValue(ctx, middleware.ContextKeyPlan, *plan)
+	}
+	return req.WithContext(ctx)
+}
+
+func TestQuotaHandler_Get(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	tiers := plans.NewTiers(100, 500)
+
+	t.Run("reports limits and usage", func(t *testing.T) {
+		counter := &fixedCounter{used: 7}
+		handler := NewQuotaHandler(10*time.Second, logger).WithRequestCounter(counter)
+		plan := tiers[plans.Free]
+
+		rec := httptest.NewRecorder()
+		handler.Get(rec, quotaRequest(&plan))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", rec.Code, rec.Body.String())
+		}
+		var resp apiv0.QuotaResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Plan != plans.Free || resp.Limits.RequestsPerWindow != 25 || resp.Limits.RateLimitWindow != "10s" ||
+			resp.Limits.MaxManifests != 100 || resp.Limits.BulkSubmission {
+			t.Errorf("unexpected quota %+v", resp)
+		}
+		if resp.Usage.Requests == nil || *resp.Usage.Requests != 7 {
+			t.Errorf("expected 7 requests used, got %v", resp.Usage.Requests)
+		}
+		if counter.plan != plans.Free {
+			t.Errorf("expected usage to be read for the free plan, got %q", counter.plan)
+		}
+	})
+
+	t.Run("omits usage that is not counted", func(t *testing.T) {
+		handler := NewQuotaHandler(10*time.Second, logger).WithRequestCounter(&fixedCounter{err: ratelimit.ErrNotCounted})
+		plan := tiers[plans.Premium]
+
+		rec := httptest.NewRecorder()
+		handler.Get(rec, quotaRequest(&plan))
+
+		var resp apiv0.QuotaResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if resp.Usage.Requests != nil || resp.Limits.RequestsPerWindow != 400 || !resp.Limits.BulkSubmission {
+			t.Errorf("unexpected quota %+v", resp)
+		}
+	})
+
+	t.Run("no plan resolved", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		NewQuotaHandler(10*time.Second, logger).Get(rec, quotaRequest(nil))
+		if rec.Code != http.StatusInternalServerError {
+			t.Errorf("expected 500, got %d", rec.Code)
+		}
+	})
+}
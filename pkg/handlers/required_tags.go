@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// AccountTagSource returns the tag keys an account requires on the clusters
+// and works it creates
+type AccountTagSource interface {
+	AccountRequiredTags(ctx context.Context, accountID string) ([]string, error)
+}
+
+// RequiredTags are the tag keys create requests must carry as request tags
+// (see middleware.ParseRequestTags). The platform-wide keys apply to every
+// account; Accounts, when set, adds each account's own.
+type RequiredTags struct {
+	Cluster  []string
+	Work     []string
+	Accounts AccountTagSource
+}
+
+// missingClusterTags returns the required keys a cluster create request's
+// tags lack or leave empty. A nil RequiredTags requires nothing.
+func (t *RequiredTags) missingClusterTags(ctx context.Context, accountID string, tags map[string]string) ([]string, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return t.missing(ctx, accountID, t.Cluster, tags)
+}
+
+// missingWorkTags is missingClusterTags for work create requests
+func (t *RequiredTags) missingWorkTags(ctx context.Context, accountID string, tags map[string]string) ([]string, error) {
+	if t == nil {
+		return nil, nil
+	}
+	return t.missing(ctx, accountID, t.Work, tags)
+}
+
+// missing returns the platform-wide then the account's required keys that
+// tags lacks or leaves empty
+func (t *RequiredTags) missing(ctx context.Context, accountID string, platform []string, tags map[string]string) ([]string, error) {
+	required := slices.Clone(platform)
+	if t.Accounts != nil {
+		accountTags, err := t.Accounts.AccountRequiredTags(ctx, accountID)
+		if err != nil {
+			return nil, err
+		}
+		required = append(required, accountTags...)
+	}
+
+	var missing []string
+	for _, key := range required {
+		if tags[key] == "" && !slices.Contains(missing, key) {
+			missing = append(missing, key)
+		}
+	}
+	return missing, nil
+}
+
+func missingTagsReason(missing []string) string {
+	return "Missing required tags: " + strings.Join(missing, ", ")
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// accountTags serves fixed required tags per account
+type accountTags map[string][]string
+
+func (a accountTags) AccountRequiredTags(ctx context.Context, accountID string) ([]string, error) {
+	return a[accountID], nil
+}
+
+func TestRequiredTags_Missing(t *testing.T) {
+	required := &RequiredTags{
+		Cluster:  []string{"cost-center"},
+		Work:     []string{"team"},
+		Accounts: accountTags{"123456789012": {"cost-center", "env"}},
+	}
+
+	tests := []struct {
+		name      string
+		required  *RequiredTags
+		accountID string
+		work      bool
+		tags      map[string]string
+		want      []string
+	}{
+		{
+			name: "nothing required",
+			tags: map[string]string{},
+		},
+		{
+			name:      "platform and account tags, without duplicates",
+			required:  required,
+			accountID: "123456789012",
+			tags:      map[string]string{},
+			want:      []string{"cost-center", "env"},
+		},
+		{
+			name:      "empty value counts as missing",
+			required:  required,
+			accountID: "123456789012",
+			tags:      map[string]string{"cost-center": "", "env": "dev"},
+			want:      []string{"cost-center"},
+		},
+		{
+			name:      "work requirements",
+			required:  required,
+			accountID: "210987654321",
+			work:      true,
+			tags:      map[string]string{"cost-center": "eng"},
+			want:      []string{"team"},
+		},
+		{
+			name:      "all present",
+			required:  required,
+			accountID: "123456789012",
+			tags:      map[string]string{"cost-center": "eng", "env": "dev"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			missingFn := tt.required.missingClusterTags
+			if tt.work {
+				missingFn = tt.required.missingWorkTags
+			}
+			got, err := missingFn(context.Background(), tt.accountID, tt.tags)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("missing = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkHandler_Create_RequiredTags(t *testing.T) {
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			return &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: manifestWork.Name, Namespace: clusterName}}, nil
+		},
+	}
+	handler := NewWorkHandler(mockClient, WorkConfig{
+		RequiredTags: &RequiredTags{Work: []string{"cost-center"}},
+	}, slog.New(slog.NewJSONHandler(os.Stdout, nil)))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-work"},
+		},
+	})
+
+	send := func(target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+		w := httptest.NewRecorder()
+		handler.Create(w, req)
+		return w
+	}
+
+	w := send("/api/v0/work")
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400 without the required tag, got %d", w.Code)
+	}
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["code"] != "missing-required-tags" || !strings.Contains(resp["reason"].(string), "cost-center") {
+		t.Errorf("unexpected error response %v", resp)
+	}
+
+	if w := send("/api/v0/work?tag.cost-center=eng"); w.Code != http.StatusCreated {
+		t.Errorf("expected status 201 with the required tag, got %d: %s", w.Code, w.Body.String())
+	}
+}
@@ -2,38 +2,82 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"regexp"
 	"strconv"
 
 	"github.com/gorilla/mux"
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 )
 
+// consumerNamePattern matches cluster IDs that can be quoted in a Maestro
+// search expression as they are
+var consumerNamePattern = regexp.MustCompile(`^[A-Za-z0-9._-]+$`)
+
 // ResourceBundleHandler handles resource bundle endpoints
 type ResourceBundleHandler struct {
 	maestroClient maestro.ClientInterface
+	pageLimits    PageLimits
+	summaryCache  *bundleSummaryCache
+	events        events.Publisher
+	searchFields  SearchFieldSupport
 	logger        *slog.Logger
 }
 
+// SearchFieldSupport reports whether Maestro accepts an optional resource
+// bundle search field
+type SearchFieldSupport interface {
+	SearchFieldSupported(field string) bool
+}
+
 // NewResourceBundleHandler creates a new ResourceBundleHandler
 func NewResourceBundleHandler(maestroClient maestro.ClientInterface, logger *slog.Logger) *ResourceBundleHandler {
 	return &ResourceBundleHandler{
 		maestroClient: maestroClient,
+		pageLimits:    DefaultPlatformPageLimits,
 		logger:        logger,
 	}
 }
 
-// List handles GET /api/v0/resource_bundles
+// WithEvents publishes a work.deleted event for every resource bundle
+// deleted
+func (h *ResourceBundleHandler) WithEvents(publisher events.Publisher) *ResourceBundleHandler {
+	h.events = publisher
+	return h
+}
+
+// WithSearchFields allows searching on the optional fields Maestro was found
+// to accept; without it only the fields every Maestro release accepts are
+// allowed
+func (h *ResourceBundleHandler) WithSearchFields(support SearchFieldSupport) *ResourceBundleHandler {
+	h.searchFields = support
+	return h
+}
+
+// WithPageLimits sets the default and maximum page size for List
+func (h *ResourceBundleHandler) WithPageLimits(limits PageLimits) *ResourceBundleHandler {
+	h.pageLimits = limits
+	return h
+}
+
+// List handles GET /api/v0/resource_bundles. With format=ndjson every
+// matching resource bundle is streamed instead of a page; see streamList.
 func (h *ResourceBundleHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	h.logger.Debug("listing resource bundles", "account_id", accountID)
 
 	page := 1
-	size := 100
 
 	if p := r.URL.Query().Get("page"); p != "" {
 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
@@ -41,37 +85,94 @@ func (h *ResourceBundleHandler) List(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	if s := r.URL.Query().Get("size"); s != "" {
-		if parsed, err := strconv.Atoi(s); err == nil && parsed > 0 {
-			size = parsed
-		}
+	size, err := pageSize(r, "size", h.pageLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
 	}
 
-	search := r.URL.Query().Get("search")
+	var supported func(string) bool
+	if h.searchFields != nil {
+		supported = h.searchFields.SearchFieldSupported
+	}
+	search, err := maestro.ValidateSearchWith(r.URL.Query().Get("search"), supported)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-search", err.Error())
+		return
+	}
+	search, err = consumerSearch(r.URL.Query().Get("cluster_id"), search)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", err.Error())
+		return
+	}
+	conds, err := parseBundleConditions(r.URL.Query()["condition"])
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-condition", err.Error())
+		return
+	}
 	orderBy := r.URL.Query().Get("orderBy")
 	fields := r.URL.Query().Get("fields")
+	format, err := listFormat(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-format", err.Error())
+		return
+	}
+	if format == formatNDJSON {
+		h.streamList(w, r, accountID, size, search, orderBy, fields, conds)
+		return
+	}
 
-	list, err := h.maestroClient.ListResourceBundles(ctx, page, size, search, orderBy, fields)
+	var list *maestro.ResourceBundleList
+	if len(conds) > 0 {
+		list, err = h.listByConditions(ctx, page, size, search, orderBy, fields, conds)
+	} else {
+		list, err = h.maestroClient.ListResourceBundles(ctx, page, size, search, orderBy, fields)
+	}
 	if err != nil {
-		h.logger.Error("failed to list resource bundles from Maestro", "error", err, "account_id", accountID)
-		if maestroErr, ok := err.(*maestro.Error); ok {
-			h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
-			return
-		}
-		h.writeError(w, http.StatusInternalServerError, "maestro-error", "Failed to list resource bundles")
+		h.writeListError(w, accountID, err)
 		return
 	}
 
+	list.Items = emptyIfNil(list.Items)
 	h.logger.Debug("resource bundles listed", "total", list.Total, "account_id", accountID)
 
-	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(list)
+	setNumberedLinks(w, r, page, size, list.Total)
+	writeListResponse(w, r, http.StatusOK, pageEnvelope{Kind: list.Kind, Page: list.Page, Size: list.Size, Total: list.Total}, list.Items)
+}
+
+// writeListError writes the response to a failed resource bundle listing
+func (h *ResourceBundleHandler) writeListError(w http.ResponseWriter, accountID string, err error) {
+	if errors.Is(err, errConditionScanLimit) {
+		h.writeError(w, http.StatusBadRequest, "condition-scan-limit", err.Error())
+		return
+	}
+	h.logger.Error("failed to list resource bundles from Maestro", "error", err, "account_id", accountID)
+	if maestroErr, ok := err.(*maestro.Error); ok {
+		h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
+		return
+	}
+	h.writeError(w, http.StatusInternalServerError, "maestro-error", "Failed to list resource bundles")
+}
+
+// consumerSearch narrows a Maestro search expression to the resource bundles
+// of the management cluster clusterID, Maestro's consumer name
+func consumerSearch(clusterID, search string) (string, error) {
+	if clusterID == "" {
+		return search, nil
+	}
+	if !consumerNamePattern.MatchString(clusterID) {
+		return "", fmt.Errorf("cluster_id %q may only contain letters, digits, '.', '_' and '-'", clusterID)
+	}
+	return maestro.AndSearch(maestro.SearchEquals("consumer_name", clusterID), search), nil
 }
 
 // Delete handles DELETE /api/v0/resource_bundles/{id}
 func (h *ResourceBundleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	vars := mux.Vars(r)
 	id := vars["id"]
@@ -84,7 +185,14 @@ func (h *ResourceBundleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	h.logger.Debug("deleting resource bundle", "id", id, "account_id", accountID)
 
-	err := h.maestroClient.DeleteResourceBundle(ctx, id)
+	// A dry run only looks the bundle up, answering as a delete would
+	var bundle *maestro.ResourceBundle
+	var err error
+	if middleware.IsDryRun(ctx) {
+		bundle, err = h.maestroClient.GetResourceBundle(ctx, id)
+	} else {
+		err = h.maestroClient.DeleteResourceBundle(ctx, id)
+	}
 	if err != nil {
 		h.logger.Error("failed to delete resource bundle from Maestro", "error", err, "id", id, "account_id", accountID)
 		if maestroErr, ok := err.(*maestro.Error); ok {
@@ -99,7 +207,16 @@ func (h *ResourceBundleHandler) Delete(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if writeDryRun(w, r, http.StatusNoContent, bundle) {
+		return
+	}
+
 	h.logger.Debug("resource bundle deleted", "id", id, "account_id", accountID)
+	if h.events != nil {
+		event := events.New(events.TypeWorkDeleted, accountID, id, map[string]string{"resourceBundleId": id})
+		event.TraceParent = middleware.GetTraceParent(ctx)
+		h.events.Publish(ctx, event)
+	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -108,11 +225,5 @@ func (h *ResourceBundleHandler) writeError(w http.ResponseWriter, status int, co
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
-	resp := map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+)
+
+// maxConditionScan bounds how many resource bundles List reads from Maestro
+// to filter them by condition
+const maxConditionScan = 5000
+
+// errConditionScanLimit is returned when more than maxConditionScan bundles
+// would have to be read to filter them by condition
+var errConditionScanLimit = fmt.Errorf("more than %d resource bundles match; narrow them down with search or cluster_id", maxConditionScan)
+
+// bundleCondition is a condition type and the status a resource bundle must
+// report for it
+type bundleCondition struct {
+	Type   string
+	Status string
+}
+
+// parseBundleConditions parses condition query parameters of the form
+// Type:Status, such as Applied:False. Status is True, False or Unknown.
+func parseBundleConditions(values []string) ([]bundleCondition, error) {
+	conds := make([]bundleCondition, 0, len(values))
+	for _, v := range values {
+		condType, status, ok := strings.Cut(v, ":")
+		if !ok || condType == "" {
+			return nil, fmt.Errorf("condition %q must be of the form Type:Status", v)
+		}
+		switch strings.ToLower(status) {
+		case "true":
+			status = "True"
+		case "false":
+			status = "False"
+		case "unknown":
+			status = "Unknown"
+		default:
+			return nil, fmt.Errorf("condition %q must have status True, False or Unknown", v)
+		}
+		conds = append(conds, bundleCondition{Type: condType, Status: status})
+	}
+	return conds, nil
+}
+
+// matchesConditions reports whether bundle reports every one of conds. A
+// condition the bundle does not report at all counts as Unknown.
+func matchesConditions(bundle maestro.ResourceBundle, conds []bundleCondition) bool {
+	reported := reportedConditions(bundle)
+	for _, cond := range conds {
+		status, ok := reported[cond.Type]
+		if !ok {
+			status = "Unknown"
+		}
+		if status != cond.Status {
+			return false
+		}
+	}
+	return true
+}
+
+// reportedConditions returns the status of each condition bundle reports,
+// by condition type
+func reportedConditions(bundle maestro.ResourceBundle) map[string]string {
+	reported := map[string]string{}
+	items, _ := bundle.Status["conditions"].([]interface{})
+	for _, item := range items {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := c["type"].(string)
+		status, _ := c["status"].(string)
+		reported[condType] = status
+	}
+	return reported
+}
+
+// listByConditions returns the requested page of the resource bundles
+// matching search that report every one of conds. Maestro cannot search on
+// status, so every matching bundle is read and filtered here.
+func (h *ResourceBundleHandler) listByConditions(ctx context.Context, page, size int, search, orderBy, fields string, conds []bundleCondition) (*maestro.ResourceBundleList, error) {
+	// The filter needs the status even if the caller did not ask for it
+	if fields != "" && !containsField(fields, "status") {
+		fields += ",status"
+	}
+
+	var matched []maestro.ResourceBundle
+	kind := "ResourceBundleList"
+	scanned := 0
+	for scanPage := 1; ; scanPage++ {
+		list, err := h.maestroClient.ListResourceBundles(ctx, scanPage, h.pageLimits.Max, search, orderBy, fields)
+		if err != nil {
+			return nil, err
+		}
+		if list.Kind != "" {
+			kind = list.Kind
+		}
+		if list.Total > maxConditionScan {
+			return nil, errConditionScanLimit
+		}
+		for _, bundle := range list.Items {
+			if matchesConditions(bundle, conds) {
+				matched = append(matched, bundle)
+			}
+		}
+		scanned += len(list.Items)
+		if len(list.Items) == 0 || scanned >= list.Total {
+			break
+		}
+	}
+
+	start := min((page-1)*size, len(matched))
+	end := min(start+size, len(matched))
+	return &maestro.ResourceBundleList{
+		Kind:  kind,
+		Page:  page,
+		Size:  end - start,
+		Total: len(matched),
+		Items: matched[start:end],
+	}, nil
+}
+
+// containsField reports whether the comma separated fields list includes field
+func containsField(fields, field string) bool {
+	for _, f := range strings.Split(fields, ",") {
+		if strings.TrimSpace(f) == field {
+			return true
+		}
+	}
+	return false
+}
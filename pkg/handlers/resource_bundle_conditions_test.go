@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+func bundleWithConditions(id string, conds map[string]string) maestro.ResourceBundle {
+	items := []interface{}{}
+	for condType, status := range conds {
+		items = append(items, map[string]interface{}{"type": condType, "status": status})
+	}
+	return maestro.ResourceBundle{ID: id, Status: map[string]interface{}{"conditions": items}}
+}
+
+func TestParseBundleConditions(t *testing.T) {
+	conds, err := parseBundleConditions([]string{"Applied:false", "Available:True"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conds) != 2 || conds[0] != (bundleCondition{Type: "Applied", Status: "False"}) || conds[1] != (bundleCondition{Type: "Available", Status: "True"}) {
+		t.Errorf("unexpected conditions: %+v", conds)
+	}
+
+	for _, v := range []string{"Applied", ":False", "Applied:Maybe"} {
+		if _, err := parseBundleConditions([]string{v}); err == nil {
+			t.Errorf("expected %q to be rejected", v)
+		}
+	}
+}
+
+func TestMatchesConditions(t *testing.T) {
+	bundle := bundleWithConditions("rb-1", map[string]string{"Applied": "False", "Available": "True"})
+
+	tests := []struct {
+		name  string
+		conds []bundleCondition
+		want  bool
+	}{
+		{name: "matching", conds: []bundleCondition{{Type: "Applied", Status: "False"}}, want: true},
+		{name: "all must match", conds: []bundleCondition{{Type: "Applied", Status: "False"}, {Type: "Available", Status: "False"}}, want: false},
+		{name: "missing counts as unknown", conds: []bundleCondition{{Type: "Degraded", Status: "Unknown"}}, want: true},
+		{name: "missing is not false", conds: []bundleCondition{{Type: "Degraded", Status: "False"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchesConditions(bundle, tt.conds); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResourceBundleHandler_List_Condition(t *testing.T) {
+	all := []maestro.ResourceBundle{
+		bundleWithConditions("rb-1", map[string]string{"Applied": "True"}),
+		bundleWithConditions("rb-2", map[string]string{"Applied": "False"}),
+		bundleWithConditions("rb-3", map[string]string{"Applied": "False"}),
+		bundleWithConditions("rb-4", map[string]string{"Applied": "True"}),
+		bundleWithConditions("rb-5", map[string]string{"Applied": "False"}),
+	}
+	var calls int
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			calls++
+			if fields != "id,status" {
+				t.Errorf("expected fields=id,status, got %q", fields)
+			}
+			start := min((page-1)*size, len(all))
+			end := min(start+size, len(all))
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page, Size: end - start, Total: len(all), Items: all[start:end]}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger).WithPageLimits(PageLimits{Default: 2, Max: 2})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles?condition=Applied:False&page=2&size=2&fields=id", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+	w := httptest.NewRecorder()
+	handler.List(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 Maestro pages to be read, got %d", calls)
+	}
+	var resp maestro.ResourceBundleList
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Total != 3 || resp.Page != 2 || len(resp.Items) != 1 || resp.Items[0].ID != "rb-5" {
+		t.Errorf("unexpected page: total=%d page=%d items=%+v", resp.Total, resp.Page, resp.Items)
+	}
+}
+
+func TestResourceBundleHandler_List_ConditionScanLimit(t *testing.T) {
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page, Total: maxConditionScan + 1}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles?condition=Applied:False", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+	w := httptest.NewRecorder()
+	handler.List(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var errResp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp["code"] != "condition-scan-limit" {
+		t.Errorf("expected code=condition-scan-limit, got %v", errResp["code"])
+	}
+}
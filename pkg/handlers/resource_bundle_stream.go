@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+)
+
+// streamList writes every resource bundle matching search, and conds when
+// set, as NDJSON, one bundle per line followed by a StreamEnd line. Bundles
+// are read from Maestro size at a time and each page is written before the
+// next is read, so memory stays flat however many bundles match and, unlike
+// a condition-filtered page, no scan limit applies. The page parameter is
+// ignored.
+func (h *ResourceBundleHandler) streamList(w http.ResponseWriter, r *http.Request, accountID string, size int, search, orderBy, fields string, conds []bundleCondition) {
+	ctx := r.Context()
+	// The filter needs the status even if the caller did not ask for it
+	if len(conds) > 0 && fields != "" && !containsField(fields, "status") {
+		fields += ",status"
+	}
+
+	// The first page is read before anything is written, so a request
+	// Maestro rejects outright still gets an error status
+	list, err := h.maestroClient.ListResourceBundles(ctx, 1, size, search, orderBy, fields)
+	if err != nil {
+		h.writeListError(w, accountID, err)
+		return
+	}
+	if r.Method == http.MethodHead {
+		w.Header().Set("Content-Type", mediaTypeNDJSON)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	stream := newNDJSONStream(w, r)
+	read := 0
+	for page := 1; ; page++ {
+		if page > 1 {
+			list, err = h.maestroClient.ListResourceBundles(ctx, page, size, search, orderBy, fields)
+			if err != nil {
+				h.logger.Error("failed to stream resource bundles from Maestro", "error", err, "account_id", accountID, "page", page)
+				stream.Fail(ctx, "maestro-error", "Failed to list resource bundles")
+				return
+			}
+		}
+		for _, bundle := range list.Items {
+			if len(conds) == 0 || matchesConditions(bundle, conds) {
+				_ = stream.Write(bundle)
+			}
+		}
+		if err := stream.Flush(); err != nil {
+			h.logger.Info("resource bundle stream abandoned by the client", "error", err, "account_id", accountID, "written", stream.total)
+			return
+		}
+		read += len(list.Items)
+		if len(list.Items) == 0 || read >= list.Total {
+			break
+		}
+	}
+	h.logger.Debug("resource bundles streamed", "total", stream.total, "account_id", accountID)
+	stream.End()
+}
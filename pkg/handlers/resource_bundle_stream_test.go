@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// ndjsonLines decodes each line of an NDJSON body
+func ndjsonLines(t *testing.T, body string) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestResourceBundleHandler_List_NDJSON(t *testing.T) {
+	all := []maestro.ResourceBundle{
+		bundleWithConditions("rb-1", map[string]string{"Applied": "True"}),
+		bundleWithConditions("rb-2", map[string]string{"Applied": "False"}),
+		bundleWithConditions("rb-3", map[string]string{"Applied": "False"}),
+		bundleWithConditions("rb-4", map[string]string{"Applied": "True"}),
+		bundleWithConditions("rb-5", map[string]string{"Applied": "False"}),
+	}
+	failPage := 0
+	var pages []int
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			pages = append(pages, page)
+			if page == failPage {
+				return nil, errors.New("connection reset")
+			}
+			start := min((page-1)*size, len(all))
+			end := min(start+size, len(all))
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page, Size: end - start, Total: len(all), Items: all[start:end]}, nil
+		},
+	}
+	handler := NewResourceBundleHandler(mockClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name      string
+		query     string
+		failPage  int
+		wantIDs   []string
+		wantPages int
+		wantLast  string
+	}{
+		{name: "every bundle", query: "?format=ndjson&size=2&page=3", wantIDs: []string{"rb-1", "rb-2", "rb-3", "rb-4", "rb-5"}, wantPages: 3, wantLast: "StreamEnd"},
+		{name: "filtered by condition", query: "?format=ndjson&size=2&condition=Applied:False", wantIDs: []string{"rb-2", "rb-3", "rb-5"}, wantPages: 3, wantLast: "StreamEnd"},
+		{name: "maestro fails mid-stream", query: "?format=ndjson&size=2", failPage: 2, wantIDs: []string{"rb-1", "rb-2"}, wantPages: 2, wantLast: "Error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			failPage, pages = tt.failPage, nil
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body.String())
+			}
+			if ct := w.Header().Get("Content-Type"); ct != mediaTypeNDJSON {
+				t.Errorf("Content-Type = %q, want %q", ct, mediaTypeNDJSON)
+			}
+			if !w.Flushed {
+				t.Error("expected the stream to be flushed")
+			}
+			if len(pages) != tt.wantPages {
+				t.Errorf("expected %d Maestro pages to be read, got %v", tt.wantPages, pages)
+			}
+
+			lines := ndjsonLines(t, w.Body.String())
+			if len(lines) != len(tt.wantIDs)+1 {
+				t.Fatalf("expected %d bundles and an end line, got %d lines", len(tt.wantIDs), len(lines))
+			}
+			for i, id := range tt.wantIDs {
+				if lines[i]["id"] != id {
+					t.Errorf("line %d id = %v, want %s", i, lines[i]["id"], id)
+				}
+			}
+			last := lines[len(lines)-1]
+			if last["kind"] != tt.wantLast {
+				t.Errorf("last line kind = %v, want %s", last["kind"], tt.wantLast)
+			}
+			if tt.wantLast == "StreamEnd" && last["total"] != float64(len(tt.wantIDs)) {
+				t.Errorf("end line total = %v, want %d", last["total"], len(tt.wantIDs))
+			}
+		})
+	}
+}
+
+func TestResourceBundleHandler_List_NDJSONErrors(t *testing.T) {
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			return nil, &maestro.Error{Code: "maestro-unavailable", Reason: "Maestro is down"}
+		},
+	}
+	handler := NewResourceBundleHandler(mockClient, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "unknown format", query: "?format=csv", wantStatus: http.StatusBadRequest, wantCode: "invalid-format"},
+		// The first page is read before the stream starts, so the status
+		// still reports the failure
+		{name: "maestro fails first", query: "?format=ndjson", wantStatus: http.StatusBadGateway, wantCode: "maestro-unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles"+tt.query, nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+			w := httptest.NewRecorder()
+
+			handler.List(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			var resp map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %s", resp["code"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestNDJSONStream_Fail_Timeout(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/audit?format=ndjson", nil)
+	ctx, cancel := context.WithTimeout(req.Context(), 0)
+	defer cancel()
+	<-ctx.Done()
+	w := httptest.NewRecorder()
+
+	stream := newNDJSONStream(w, req)
+	_ = stream.Write(map[string]string{"id": "a"})
+	stream.Fail(ctx, "internal-error", "Failed")
+
+	lines := ndjsonLines(t, w.Body.String())
+	if len(lines) != 2 || lines[1]["code"] != "stream-timeout" {
+		t.Errorf("expected the item and a stream-timeout line, got %+v", lines)
+	}
+}
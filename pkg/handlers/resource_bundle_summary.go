@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// summaryFields are the only resource bundle fields the summary reads
+const summaryFields = "id,consumer_name,status"
+
+// bundleSummaryCache holds the last computed summary. Computing it holds the
+// lock, so concurrent requests wait for one computation rather than each
+// paging through Maestro.
+type bundleSummaryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	summary *apiv0.ResourceBundleSummary
+	now     func() time.Time
+}
+
+// WithSummaryCache reuses a computed summary for ttl; 0 computes it on every
+// request
+func (h *ResourceBundleHandler) WithSummaryCache(ttl time.Duration) *ResourceBundleHandler {
+	h.summaryCache = &bundleSummaryCache{ttl: ttl, now: time.Now}
+	return h
+}
+
+// SetSummaryCacheTTL changes how long a computed summary is reused, when the
+// configuration is reloaded
+func (h *ResourceBundleHandler) SetSummaryCacheTTL(ttl time.Duration) {
+	c := h.summaryCache
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	c.ttl = ttl
+	c.mu.Unlock()
+}
+
+// Summary handles GET /api/v0/resource_bundles/summary
+func (h *ResourceBundleHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	h.logger.Debug("summarizing resource bundles", "account_id", accountID)
+
+	summary, cached, err := h.summary(r.Context())
+	if err != nil {
+		h.logger.Error("failed to summarize resource bundles from Maestro", "error", err, "account_id", accountID)
+		if maestroErr, ok := err.(*maestro.Error); ok {
+			h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
+			return
+		}
+		h.writeError(w, http.StatusInternalServerError, "maestro-error", "Failed to summarize resource bundles")
+		return
+	}
+
+	if cached {
+		w.Header().Set("X-Cache", cacheHit)
+	} else {
+		w.Header().Set("X-Cache", cacheMiss)
+	}
+	writeResponse(w, r, http.StatusOK, summary)
+}
+
+// summary returns the cached summary if it is fresh, computing it otherwise,
+// and reports whether it came from the cache
+func (h *ResourceBundleHandler) summary(ctx context.Context) (*apiv0.ResourceBundleSummary, bool, error) {
+	c := h.summaryCache
+	if c == nil {
+		s, err := h.computeSummary(ctx, time.Now())
+		return s, false, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	if c.summary != nil && now.Sub(c.summary.ComputedAt) < c.ttl {
+		return c.summary, true, nil
+	}
+	s, err := h.computeSummary(ctx, now)
+	if err != nil {
+		return nil, false, err
+	}
+	c.summary = s
+	return s, false, nil
+}
+
+// computeSummary pages through every resource bundle in Maestro and counts
+// them. Every condition type reported by any bundle is counted for every
+// bundle, a bundle not reporting it counting as Unknown, as in the List
+// condition filter.
+func (h *ResourceBundleHandler) computeSummary(ctx context.Context, now time.Time) (*apiv0.ResourceBundleSummary, error) {
+	var bundles []bundleStatuses
+	types := map[string]bool{}
+	scanned := 0
+	for page := 1; ; page++ {
+		list, err := h.maestroClient.ListResourceBundles(ctx, page, h.pageLimits.Max, "", "", summaryFields)
+		if err != nil {
+			return nil, err
+		}
+		for _, bundle := range list.Items {
+			statuses := reportedConditions(bundle)
+			for condType := range statuses {
+				types[condType] = true
+			}
+			bundles = append(bundles, bundleStatuses{cluster: bundle.ConsumerName, statuses: statuses})
+		}
+		scanned += len(list.Items)
+		if len(list.Items) == 0 || scanned >= list.Total {
+			break
+		}
+	}
+
+	condTypes := make([]string, 0, len(types))
+	for condType := range types {
+		condTypes = append(condTypes, condType)
+	}
+	sort.Strings(condTypes)
+
+	summary := &apiv0.ResourceBundleSummary{
+		Kind:       "ResourceBundleSummary",
+		Total:      len(bundles),
+		Conditions: map[string]apiv0.ConditionCounts{},
+		Clusters:   map[string]apiv0.ClusterBundleSummary{},
+		ComputedAt: clock.Stamp(now),
+	}
+	for _, b := range bundles {
+		cluster, ok := summary.Clusters[b.cluster]
+		if !ok {
+			cluster = apiv0.ClusterBundleSummary{Conditions: map[string]apiv0.ConditionCounts{}}
+		}
+		cluster.Total++
+		for _, condType := range condTypes {
+			summary.Conditions[condType] = countStatus(summary.Conditions[condType], b.statuses[condType])
+			cluster.Conditions[condType] = countStatus(cluster.Conditions[condType], b.statuses[condType])
+		}
+		summary.Clusters[b.cluster] = cluster
+	}
+	return summary, nil
+}
+
+// bundleStatuses is the management cluster of a resource bundle and the
+// status of each condition it reports
+type bundleStatuses struct {
+	cluster  string
+	statuses map[string]string
+}
+
+// countStatus adds a bundle with the given condition status to counts
+func countStatus(counts apiv0.ConditionCounts, status string) apiv0.ConditionCounts {
+	switch status {
+	case "True":
+		counts.True++
+	case "False":
+		counts.False++
+	default:
+		counts.Unknown++
+	}
+	return counts
+}
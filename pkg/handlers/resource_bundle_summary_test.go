@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+func TestResourceBundleHandler_Summary(t *testing.T) {
+	all := []maestro.ResourceBundle{
+		bundleWithConditions("rb-1", map[string]string{"Applied": "True", "Available": "True"}),
+		bundleWithConditions("rb-2", map[string]string{"Applied": "False"}),
+		bundleWithConditions("rb-3", map[string]string{"Applied": "True", "Available": "False"}),
+	}
+	all[0].ConsumerName = "mc01"
+	all[1].ConsumerName = "mc01"
+	all[2].ConsumerName = "mc02"
+
+	var calls int
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			calls++
+			start := min((page-1)*size, len(all))
+			end := min(start+size, len(all))
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page, Size: end - start, Total: len(all), Items: all[start:end]}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger).
+		WithPageLimits(PageLimits{Default: 2, Max: 2}).
+		WithSummaryCache(time.Minute)
+
+	get := func() (*httptest.ResponseRecorder, apiv0.ResourceBundleSummary) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles/summary", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+		w := httptest.NewRecorder()
+		handler.Summary(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var summary apiv0.ResourceBundleSummary
+		if err := json.NewDecoder(w.Body).Decode(&summary); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return w, summary
+	}
+
+	w, summary := get()
+	if w.Header().Get("X-Cache") != cacheMiss {
+		t.Errorf("expected X-Cache=%s, got %q", cacheMiss, w.Header().Get("X-Cache"))
+	}
+	if summary.Total != 3 {
+		t.Errorf("expected total 3, got %d", summary.Total)
+	}
+	if got := summary.Conditions["Applied"]; got != (apiv0.ConditionCounts{True: 2, False: 1}) {
+		t.Errorf("unexpected Applied counts: %+v", got)
+	}
+	if got := summary.Conditions["Available"]; got != (apiv0.ConditionCounts{True: 1, False: 1, Unknown: 1}) {
+		t.Errorf("unexpected Available counts: %+v", got)
+	}
+	mc01 := summary.Clusters["mc01"]
+	if mc01.Total != 2 || mc01.Conditions["Applied"] != (apiv0.ConditionCounts{True: 1, False: 1}) {
+		t.Errorf("unexpected mc01 summary: %+v", mc01)
+	}
+	if summary.Clusters["mc02"].Total != 1 {
+		t.Errorf("unexpected mc02 summary: %+v", summary.Clusters["mc02"])
+	}
+
+	w, _ = get()
+	if w.Header().Get("X-Cache") != cacheHit {
+		t.Errorf("expected X-Cache=%s, got %q", cacheHit, w.Header().Get("X-Cache"))
+	}
+	if calls != 2 {
+		t.Errorf("expected Maestro to be paged through once (2 calls), got %d calls", calls)
+	}
+}
+
+func TestResourceBundleHandler_Summary_MaestroError(t *testing.T) {
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			return nil, &maestro.Error{Code: "500", Reason: "Maestro unavailable"}
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger).WithSummaryCache(time.Minute)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles/summary", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+	w := httptest.NewRecorder()
+	handler.Summary(w, req)
+
+	if w.Code != http.StatusBadGateway {
+		t.Errorf("expected status 502, got %d", w.Code)
+	}
+	if handler.summaryCache.summary != nil {
+		t.Error("expected a failed summary not to be cached")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/gorilla/mux"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 	workv1 "open-cluster-management.io/api/work/v1"
 )
@@ -57,6 +59,10 @@ func (m *mockMaestroClient) GetConsumer(ctx context.Context, id string) (*maestr
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockMaestroClient) DeleteConsumer(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockMaestroClient) CreateManifestWork(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
 	return nil, errors.New("not implemented")
 }
@@ -232,6 +238,47 @@ func TestResourceBundleHandler_List_WithPagination(t *testing.T) {
 	}
 }
 
+func TestResourceBundleHandler_List_PageLimits(t *testing.T) {
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			if size != 25 {
+				t.Errorf("expected configured default size=25, got %d", size)
+			}
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: page, Size: size}, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger).WithPageLimits(PageLimits{Default: 25, Max: 40})
+
+	newRequest := func(target string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		return req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+	}
+
+	w := httptest.NewRecorder()
+	handler.List(w, newRequest("/api/v0/resource_bundles"))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	handler.List(w, newRequest("/api/v0/resource_bundles?size=41"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var errResp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp["code"] != "invalid-page-size" {
+		t.Errorf("expected code=invalid-page-size, got %v", errResp["code"])
+	}
+	if errResp["reason"] != "size must not exceed 40" {
+		t.Errorf("unexpected reason: %v", errResp["reason"])
+	}
+}
+
 func TestResourceBundleHandler_List_WithQueryParams(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -243,7 +290,7 @@ func TestResourceBundleHandler_List_WithQueryParams(t *testing.T) {
 		{
 			name:           "with search",
 			queryParams:    "?search=name%3D%27test%27",
-			expectedSearch: "name='test'",
+			expectedSearch: "name = 'test'",
 			expectedOrder:  "",
 			expectedFields: "",
 		},
@@ -264,10 +311,20 @@ func TestResourceBundleHandler_List_WithQueryParams(t *testing.T) {
 		{
 			name:           "with all query params",
 			queryParams:    "?page=2&size=50&search=name%3D%27test%27&orderBy=created_at%20desc&fields=id,name",
-			expectedSearch: "name='test'",
+			expectedSearch: "name = 'test'",
 			expectedOrder:  "created_at desc",
 			expectedFields: "id,name",
 		},
+		{
+			name:           "with cluster_id",
+			queryParams:    "?cluster_id=management-01",
+			expectedSearch: "consumer_name = 'management-01'",
+		},
+		{
+			name:           "with cluster_id and search",
+			queryParams:    "?cluster_id=management-01&search=name%3D%27test%27",
+			expectedSearch: "(consumer_name = 'management-01') and (name = 'test')",
+		},
 	}
 
 	for _, tt := range tests {
@@ -310,6 +367,110 @@ func TestResourceBundleHandler_List_WithQueryParams(t *testing.T) {
 	}
 }
 
+func TestResourceBundleHandler_List_InvalidSearch(t *testing.T) {
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			t.Error("expected Maestro not to be called")
+			return nil, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles?search=payload%3D%27x%27", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+	w := httptest.NewRecorder()
+	handler.List(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var errResp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp["code"] != "invalid-search" {
+		t.Errorf("expected code=invalid-search, got %v", errResp["code"])
+	}
+}
+
+type searchFieldSupport map[string]bool
+
+func (s searchFieldSupport) SearchFieldSupported(field string) bool { return s[field] }
+
+func TestResourceBundleHandler_List_OptionalSearchField(t *testing.T) {
+	var searched string
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			searched = search
+			return &maestro.ResourceBundleList{Kind: "ResourceBundleList", Items: []maestro.ResourceBundle{}}, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	tests := []struct {
+		name     string
+		support  SearchFieldSupport
+		wantCode int
+	}{
+		{name: "capabilities unknown", wantCode: http.StatusBadRequest},
+		{name: "maestro without the field", support: searchFieldSupport{}, wantCode: http.StatusBadRequest},
+		{name: "maestro with the field", support: searchFieldSupport{"deleted_at": true}, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			searched = ""
+			handler := NewResourceBundleHandler(mockClient, logger)
+			if tt.support != nil {
+				handler.WithSearchFields(tt.support)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles?search=deleted_at%3E%272026-01-01%27", nil)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+			w := httptest.NewRecorder()
+			handler.List(w, req)
+
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode == http.StatusOK && searched != "deleted_at > '2026-01-01'" {
+				t.Errorf("expected the search to reach Maestro, got %q", searched)
+			}
+		})
+	}
+}
+
+func TestResourceBundleHandler_List_InvalidClusterID(t *testing.T) {
+	mockClient := &mockMaestroClient{
+		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+			t.Error("expected Maestro not to be called")
+			return nil, nil
+		},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	handler := NewResourceBundleHandler(mockClient, logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles?cluster_id=a%27%20or%20%271%27%3D%271", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+	w := httptest.NewRecorder()
+	handler.List(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+	var errResp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&errResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if errResp["code"] != "invalid-request" {
+		t.Errorf("expected code=invalid-request, got %v", errResp["code"])
+	}
+}
+
 func TestResourceBundleHandler_List_MaestroError(t *testing.T) {
 	mockClient := &mockMaestroClient{
 		listResourceBundlesFunc: func(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
@@ -478,7 +639,8 @@ func TestResourceBundleHandler_Delete_Success(t *testing.T) {
 	}
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
-	handler := NewResourceBundleHandler(mockClient, logger)
+	publisher := &recordingPublisher{}
+	handler := NewResourceBundleHandler(mockClient, logger).WithEvents(publisher)
 
 	req := httptest.NewRequest(http.MethodDelete, "/api/v0/resource_bundles/rb-123", nil)
 	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
@@ -493,6 +655,18 @@ func TestResourceBundleHandler_Delete_Success(t *testing.T) {
 	if w.Code != http.StatusNoContent {
 		t.Errorf("expected status 204, got %d", w.Code)
 	}
+	if len(publisher.events) != 1 || publisher.events[0].Type != events.TypeWorkDeleted || publisher.events[0].Subject != "rb-123" {
+		t.Errorf("expected a work.deleted event for rb-123, got %+v", publisher.events)
+	}
+}
+
+// recordingPublisher records the events published to it
+type recordingPublisher struct {
+	events []events.Event
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) {
+	p.events = append(p.events, event)
 }
 
 func TestResourceBundleHandler_Delete_NotFound(t *testing.T) {
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// writeResponse writes body as JSON with the common response metadata.
+// Objects gain requestId and generatedAt, and GET responses without an href
+// gain a self link, so clients can correlate and navigate responses the same
+// way on every endpoint. Fields the body already sets are left alone.
+//
+// Successful GET and HEAD responses also carry an ETag of the body and, for
+// lists, an X-Total-Count header; HEAD responses stop there, so every GET
+// route answers HEAD with the same headers and status but no body.
+func writeResponse(w http.ResponseWriter, r *http.Request, status int, body any) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		writeEncodeError(w)
+		return
+	}
+
+	if isRead(r) && status < http.StatusMultipleChoices {
+		sum := sha256.Sum256(data)
+		setReadHeaders(w, sum[:], data)
+	}
+
+	data = addMetadata(data, responseMetadata(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(append(data, '\n'))
+}
+
+// writeListResponse writes a list like writeResponse, but encodes items one
+// at a time straight to w instead of building the whole body in memory
+// first. envelope holds the list's other fields and must encode as an object
+// without items; the body is the envelope with items appended, then the
+// metadata. For GET and HEAD the list is encoded twice, once to hash it for
+// the ETag, so memory stays bounded by the largest item however long the
+// list is.
+func writeListResponse[T any](w http.ResponseWriter, r *http.Request, status int, envelope any, items []T) {
+	head, err := json.Marshal(envelope)
+	if err != nil || len(head) < 2 || head[0] != '{' {
+		writeResponse(w, r, status, envelope)
+		return
+	}
+
+	if isRead(r) && status < http.StatusMultipleChoices {
+		hash := sha256.New()
+		if err := encodeList(hash, head, items, nil); err != nil {
+			writeEncodeError(w)
+			return
+		}
+		setReadHeaders(w, hash.Sum(nil), head)
+	}
+
+	// The metadata object, whose fields follow the items
+	tail := addMetadata([]byte("{}"), responseMetadata(r))
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	bw := bufio.NewWriter(w)
+	_ = encodeList(bw, head, items, tail)
+	_ = bw.Flush()
+}
+
+// encodeList writes the object in head with an items array appended and
+// then the fields of the object in tail, which may be nil. Without a tail
+// the output is byte for byte what json.Marshal gives for the whole list.
+// Items are encoded through one reused buffer, which only grows to the size
+// of the largest item.
+func encodeList[T any](w io.Writer, head []byte, items []T, tail []byte) error {
+	buf := make([]byte, 0, len(head)+16)
+	buf = append(buf, head[:len(head)-1]...)
+	if len(head) > 2 {
+		buf = append(buf, ',')
+	}
+	buf = append(buf, `"items":[`...)
+	if _, err := w.Write(buf); err != nil {
+		return err
+	}
+
+	var item bytes.Buffer
+	enc := json.NewEncoder(&item)
+	for i := range items {
+		item.Reset()
+		if i > 0 {
+			item.WriteByte(',')
+		}
+		if err := enc.Encode(items[i]); err != nil {
+			return err
+		}
+		// Drop the newline Encode adds after each value
+		if _, err := w.Write(item.Bytes()[:item.Len()-1]); err != nil {
+			return err
+		}
+	}
+
+	end := []byte{']'}
+	switch {
+	case tail == nil:
+		end = append(end, '}')
+	case len(tail) > 2:
+		end = append(append(end, ','), tail[1:]...)
+		end = append(end, '\n')
+	default:
+		end = append(end, '}', '\n')
+	}
+	_, err := w.Write(end)
+	return err
+}
+
+func writeEncodeError(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusInternalServerError)
+	_, _ = w.Write([]byte(`{"kind":"Error","code":"internal-error","reason":"Failed to encode response"}` + "\n"))
+}
+
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+// responseMetadata returns the metadata fields added to every response to r
+func responseMetadata(r *http.Request) [][2]string {
+	meta := [][2]string{
+		{"requestId", middleware.GetRequestID(r.Context())},
+		{"generatedAt", clock.Format(time.Now())},
+	}
+	if isRead(r) {
+		meta = append(meta, [2]string{"href", href(r, r.URL.Path)})
+	}
+	return meta
+}
+
+// href returns the link to path as clients reach it: absolute under the
+// API's external URL when one is configured, otherwise under its base path
+func href(r *http.Request, path string) string {
+	if base := middleware.GetExternalURL(r.Context()); base != "" {
+		return base + path
+	}
+	return middleware.GetBasePath(r.Context()) + path
+}
+
+// setReadHeaders sets the ETag from the hash of a response body and, when
+// the body has a numeric total, X-Total-Count. The ETag is weak because the
+// hash is taken before the per-response metadata is added.
+func setReadHeaders(w http.ResponseWriter, sum []byte, data []byte) {
+	w.Header().Set("ETag", `W/"`+hex.EncodeToString(sum[:16])+`"`)
+
+	if len(data) == 0 || data[0] != '{' {
+		return
+	}
+	var list struct {
+		Total *int `json:"total"`
+	}
+	if err := json.Unmarshal(data, &list); err == nil && list.Total != nil {
+		w.Header().Set("X-Total-Count", strconv.Itoa(*list.Total))
+	}
+}
+
+// addMetadata appends each key/value pair to the JSON object in data unless
+// the object already sets the key or the value is empty. Other JSON values
+// are returned unchanged. Fields are appended so the body's own field order
+// is kept.
+func addMetadata(data []byte, meta [][2]string) []byte {
+	if len(data) < 2 || data[0] != '{' {
+		return data
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return data
+	}
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)+128))
+	out.Write(data[:len(data)-1])
+	n := len(fields)
+	for _, m := range meta {
+		key, value := m[0], m[1]
+		if _, ok := fields[key]; ok || value == "" {
+			continue
+		}
+		if n > 0 {
+			out.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		v, _ := json.Marshal(value)
+		out.Write(k)
+		out.WriteByte(':')
+		out.Write(v)
+		n++
+	}
+	out.WriteByte('}')
+	return out.Bytes()
+}
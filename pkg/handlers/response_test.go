@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+func TestWriteResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       any
+		basePath   string
+		external   string
+		expectHref string
+	}{
+		{
+			name:       "GET adds self href",
+			method:     http.MethodGet,
+			body:       map[string]any{"kind": "Thing", "id": "abc"},
+			expectHref: "/api/v0/things/abc",
+		},
+		{
+			name:       "self href includes the base path",
+			method:     http.MethodGet,
+			body:       map[string]any{"kind": "Thing", "id": "abc"},
+			basePath:   "/prod",
+			expectHref: "/prod/api/v0/things/abc",
+		},
+		{
+			name:       "self href is absolute under the external URL",
+			method:     http.MethodGet,
+			body:       map[string]any{"kind": "Thing", "id": "abc"},
+			basePath:   "/prod",
+			external:   "https://api.example.com",
+			expectHref: "https://api.example.com/api/v0/things/abc",
+		},
+		{
+			name:       "existing href is kept",
+			method:     http.MethodGet,
+			body:       map[string]any{"kind": "Thing", "href": "/api/v0/things/canonical"},
+			expectHref: "/api/v0/things/canonical",
+		},
+		{
+			name:   "POST has no self href",
+			method: http.MethodPost,
+			body:   map[string]any{"kind": "Thing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v0/things/abc", nil)
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyRequestID, "req-123")
+			if tt.basePath != "" {
+				ctx = context.WithValue(ctx, middleware.ContextKeyBasePath, tt.basePath)
+			}
+			if tt.external != "" {
+				ctx = context.WithValue(ctx, middleware.ContextKeyExternalURL, tt.external)
+			}
+			req = req.WithContext(ctx)
+			w := httptest.NewRecorder()
+
+			writeResponse(w, req, http.StatusOK, tt.body)
+
+			if ct := w.Header().Get("Content-Type"); ct != "application/json" {
+				t.Errorf("expected Content-Type application/json, got %q", ct)
+			}
+
+			var got map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if got["kind"] != "Thing" {
+				t.Errorf("expected kind Thing, got %v", got["kind"])
+			}
+			if got["requestId"] != "req-123" {
+				t.Errorf("expected requestId req-123, got %v", got["requestId"])
+			}
+			generatedAt, _ := got["generatedAt"].(string)
+			if _, err := time.Parse(time.RFC3339Nano, generatedAt); err != nil {
+				t.Errorf("expected RFC3339 generatedAt, got %q", generatedAt)
+			}
+			href, _ := got["href"].(string)
+			if href != tt.expectHref {
+				t.Errorf("expected href %q, got %q", tt.expectHref, href)
+			}
+		})
+	}
+}
+
+func TestWriteResponse_ReadHeaders(t *testing.T) {
+	list := map[string]any{"kind": "ThingList", "items": []any{}, "total": 42}
+
+	get := httptest.NewRecorder()
+	writeResponse(get, httptest.NewRequest(http.MethodGet, "/api/v0/things", nil), http.StatusOK, list)
+	head := httptest.NewRecorder()
+	writeResponse(head, httptest.NewRequest(http.MethodHead, "/api/v0/things", nil), http.StatusOK, list)
+
+	etag := get.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected GET to set an ETag")
+	}
+	if got := head.Header().Get("ETag"); got != etag {
+		t.Errorf("expected HEAD ETag %q to match GET, got %q", etag, got)
+	}
+	for _, w := range []*httptest.ResponseRecorder{get, head} {
+		if got := w.Header().Get("X-Total-Count"); got != "42" {
+			t.Errorf("expected X-Total-Count 42, got %q", got)
+		}
+	}
+	if head.Code != http.StatusOK || head.Body.Len() != 0 {
+		t.Errorf("expected HEAD to return 200 without a body, got %d with %d bytes", head.Code, head.Body.Len())
+	}
+
+	post := httptest.NewRecorder()
+	writeResponse(post, httptest.NewRequest(http.MethodPost, "/api/v0/things", nil), http.StatusCreated, list)
+	if post.Header().Get("ETag") != "" || post.Header().Get("X-Total-Count") != "" {
+		t.Error("expected no read headers on POST")
+	}
+}
+
+func TestWriteListResponse(t *testing.T) {
+	list := maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: 1, Size: 2, Total: 7, Items: []maestro.ResourceBundle{
+		{ID: "rb-1", Name: "one", Metadata: map[string]interface{}{"<tag>": "a&b"}},
+		{ID: "rb-2", Name: "two"},
+	}}
+	envelope := pageEnvelope{Kind: list.Kind, Page: list.Page, Size: list.Size, Total: list.Total}
+
+	for _, items := range [][]maestro.ResourceBundle{list.Items, {}} {
+		list.Items = items
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles", nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyRequestID, "req-123"))
+
+		buffered := httptest.NewRecorder()
+		writeResponse(buffered, req, http.StatusOK, list)
+		streamed := httptest.NewRecorder()
+		writeListResponse(streamed, req, http.StatusOK, envelope, list.Items)
+
+		for _, header := range []string{"Content-Type", "ETag", "X-Total-Count"} {
+			if got, want := streamed.Header().Get(header), buffered.Header().Get(header); got != want {
+				t.Errorf("expected %s %q, got %q", header, want, got)
+			}
+		}
+
+		var want, got map[string]any
+		if err := json.Unmarshal(buffered.Body.Bytes(), &want); err != nil {
+			t.Fatalf("failed to decode buffered response: %v", err)
+		}
+		if err := json.Unmarshal(streamed.Body.Bytes(), &got); err != nil {
+			t.Fatalf("failed to decode streamed response: %v\n%s", err, streamed.Body.String())
+		}
+		delete(want, "generatedAt")
+		delete(got, "generatedAt")
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("expected streamed body %v, got %v", want, got)
+		}
+	}
+
+	head := httptest.NewRecorder()
+	writeListResponse(head, httptest.NewRequest(http.MethodHead, "/api/v0/resource_bundles", nil), http.StatusOK, envelope, list.Items)
+	if head.Body.Len() != 0 || head.Header().Get("ETag") == "" {
+		t.Errorf("expected HEAD to send an ETag without a body, got %d bytes", head.Body.Len())
+	}
+}
+
+// discardResponseWriter drops the body so that benchmarks measure only the
+// memory used to produce it
+type discardResponseWriter struct {
+	header http.Header
+	n      int
+}
+
+func (w *discardResponseWriter) Header() http.Header { return w.header }
+func (w *discardResponseWriter) WriteHeader(int)     {}
+func (w *discardResponseWriter) Write(b []byte) (int, error) {
+	w.n += len(b)
+	return len(b), nil
+}
+
+func largeResourceBundleList(n int) maestro.ResourceBundleList {
+	list := maestro.ResourceBundleList{Kind: "ResourceBundleList", Page: 1, Size: n, Total: n}
+	for i := range n {
+		list.Items = append(list.Items, maestro.ResourceBundle{
+			ID:           fmt.Sprintf("rb-%05d", i),
+			Name:         fmt.Sprintf("bundle-%05d", i),
+			ConsumerName: "mc-01",
+			Version:      3,
+			Metadata:     map[string]interface{}{"name": fmt.Sprintf("bundle-%05d", i), "namespace": "default"},
+			Manifests: []interface{}{map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "ConfigMap",
+				"metadata":   map[string]interface{}{"name": "config", "namespace": "default"},
+				"data":       map[string]interface{}{"key": strings.Repeat("x", 256)},
+			}},
+		})
+	}
+	return list
+}
+
+// The buffered and streamed list writers on a 10k-item list. The buffered
+// writer holds several body-sized buffers at once, so its B/op is a multiple
+// of body-bytes. The streamed writer allocates less in total, and only in
+// per-item pieces that can be collected as it goes, so what it holds at any
+// time is bounded by the largest item rather than by the list.
+func BenchmarkWriteResponse_10kItems(b *testing.B) {
+	list := largeResourceBundleList(10000)
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		w := &discardResponseWriter{header: make(http.Header)}
+		writeResponse(w, req, http.StatusOK, list)
+		b.ReportMetric(float64(w.n), "body-bytes")
+	}
+}
+
+func BenchmarkWriteListResponse_10kItems(b *testing.B) {
+	list := largeResourceBundleList(10000)
+	envelope := pageEnvelope{Kind: list.Kind, Page: list.Page, Size: list.Size, Total: list.Total}
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/resource_bundles", nil)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		w := &discardResponseWriter{header: make(http.Header)}
+		writeListResponse(w, req, http.StatusOK, envelope, list.Items)
+		b.ReportMetric(float64(w.n), "body-bytes")
+	}
+}
+
+func TestAddMetadata(t *testing.T) {
+	meta := [][2]string{{"requestId", "req-1"}, {"empty", ""}}
+
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{name: "appends to object", input: `{"kind":"Thing","id":"a"}`, expect: `{"kind":"Thing","id":"a","requestId":"req-1"}`},
+		{name: "empty object", input: `{}`, expect: `{"requestId":"req-1"}`},
+		{name: "existing key wins", input: `{"requestId":"gw"}`, expect: `{"requestId":"gw"}`},
+		{name: "array untouched", input: `[1,2]`, expect: `[1,2]`},
+		{name: "null untouched", input: `null`, expect: `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(addMetadata([]byte(tt.input), meta)); got != tt.expect {
+				t.Errorf("expected %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
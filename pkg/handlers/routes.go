@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+)
+
+// RouteLister lists the registered routes in the order they are matched.
+// With a path, it returns only the route a request for method and path is
+// routed to.
+type RouteLister func(method, path string) []apiv0.RouteInfo
+
+// RoutesHandler handles the admin endpoint reporting the route table
+type RoutesHandler struct {
+	profile string
+	routes  RouteLister
+	logger  *slog.Logger
+}
+
+// NewRoutesHandler creates a new RoutesHandler for a server serving the
+// routes of profile
+func NewRoutesHandler(profile string, routes RouteLister, logger *slog.Logger) *RoutesHandler {
+	return &RoutesHandler{profile: profile, routes: routes, logger: logger}
+}
+
+// List handles GET /api/v0/admin/routes. The path query parameter, a path
+// without the base path, returns only the route a request for it is routed
+// to, with method (default GET).
+func (h *RoutesHandler) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	method := query.Get("method")
+	if method == "" {
+		method = http.MethodGet
+	}
+
+	items := h.routes(method, query.Get("path"))
+	if items == nil {
+		items = []apiv0.RouteInfo{}
+	}
+	writeResponse(w, r, http.StatusOK, apiv0.RouteListResponse{
+		Kind:    "RouteList",
+		Profile: h.profile,
+		Items:   items,
+		Total:   len(items),
+	})
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// ShadowReporter reports the shadow authorization tally of each account
+type ShadowReporter interface {
+	Report() []middleware.ShadowAccount
+}
+
+// ShadowHandler handles the admin endpoint comparing the enforced allow-list
+// decisions with the Cedar decisions evaluated in shadow mode
+type ShadowHandler struct {
+	shadow ShadowReporter
+	logger *slog.Logger
+}
+
+// NewShadowHandler creates a new ShadowHandler
+func NewShadowHandler(shadow ShadowReporter, logger *slog.Logger) *ShadowHandler {
+	return &ShadowHandler{
+		shadow: shadow,
+		logger: logger,
+	}
+}
+
+// Report handles GET /api/v0/admin/authz_shadow, listing the accounts this
+// replica has seen, those with the most diverging requests first. With
+// diverged=true only accounts with a diverging request are listed.
+func (h *ShadowHandler) Report(w http.ResponseWriter, r *http.Request) {
+	divergedOnly := r.URL.Query().Get("diverged") == "true"
+
+	resp := apiv0.ShadowReportResponse{Kind: "ShadowAuthzReport", Items: []middleware.ShadowAccount{}}
+	for _, account := range h.shadow.Report() {
+		resp.Requests += account.Requests
+		resp.Diverged += account.Diverged()
+		if divergedOnly && account.Diverged() == 0 {
+			continue
+		}
+		resp.Items = append(resp.Items, account)
+	}
+	resp.Total = len(resp.Items)
+	writeResponse(w, r, http.StatusOK, resp)
+}
@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/slo"
+)
+
+// SLOReporter reports the state of the SLOs of each route class
+type SLOReporter interface {
+	Report() *slo.Report
+}
+
+// SLOHandler handles the admin endpoint reporting availability and latency
+// against their objectives
+type SLOHandler struct {
+	slo    SLOReporter
+	logger *slog.Logger
+}
+
+// NewSLOHandler creates a new SLOHandler
+func NewSLOHandler(slo SLOReporter, logger *slog.Logger) *SLOHandler {
+	return &SLOHandler{
+		slo:    slo,
+		logger: logger,
+	}
+}
+
+// Report handles GET /api/v0/admin/slo, reporting the SLIs, burn rates and
+// remaining error budget of every route class this replica has served. With
+// class set only that route class is reported.
+func (h *SLOHandler) Report(w http.ResponseWriter, r *http.Request) {
+	report := h.slo.Report()
+	if class := r.URL.Query().Get("class"); class != "" {
+		classes := []slo.ClassReport{}
+		for _, c := range report.Classes {
+			if c.Class == class {
+				classes = append(classes, c)
+			}
+		}
+		report.Classes = classes
+	}
+	writeResponse(w, r, http.StatusOK, report)
+}
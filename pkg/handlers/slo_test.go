@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/slo"
+)
+
+func TestSLOHandler_Report(t *testing.T) {
+	tracker := slo.NewTracker(slo.Config{Period: 24 * time.Hour, Availability: 0.999, Latency: 0.99})
+	tracker.Record("tenant", time.Second, time.Millisecond, http.StatusOK)
+	tracker.Record("authz", time.Second, time.Millisecond, http.StatusInternalServerError)
+	h := NewSLOHandler(tracker, slog.Default())
+
+	tests := []struct {
+		name    string
+		query   string
+		classes []string
+	}{
+		{name: "all classes", classes: []string{"authz", "tenant"}},
+		{name: "one class", query: "?class=tenant", classes: []string{"tenant"}},
+		{name: "unknown class", query: "?class=platform", classes: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.Report(w, httptest.NewRequest(http.MethodGet, "/api/v0/admin/slo"+tt.query, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var resp slo.Report
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "SLOReport" {
+				t.Errorf("expected kind SLOReport, got %q", resp.Kind)
+			}
+			got := []string{}
+			for _, c := range resp.Classes {
+				got = append(got, c.Class)
+			}
+			if len(got) != len(tt.classes) {
+				t.Fatalf("expected classes %v, got %v", tt.classes, got)
+			}
+			for i := range got {
+				if got[i] != tt.classes[i] {
+					t.Errorf("expected classes %v, got %v", tt.classes, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/status"
+)
+
+// StatusHandler handles the regional status endpoint
+type StatusHandler struct {
+	reporter *status.Reporter
+}
+
+// NewStatusHandler creates a new StatusHandler
+func NewStatusHandler(reporter *status.Reporter) *StatusHandler {
+	return &StatusHandler{reporter: reporter}
+}
+
+// Status handles GET /api/v0/status
+// Returns aggregate platform health for the global control plane's region
+// picker. The response is always 200 so callers can read the degraded or
+// unavailable status from the body.
+func (h *StatusHandler) Status(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, r, http.StatusOK, h.reporter.Report(r.Context()))
+}
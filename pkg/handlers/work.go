@@ -1,12 +1,38 @@
 package handlers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/envelope"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+	"github.com/openshift/rosa-regional-platform-api/pkg/residency"
+	"github.com/openshift/rosa-regional-platform-api/pkg/secretref"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workchart"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workhistory"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workpayload"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workqueue"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workrate"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workschedule"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/serializer"
 	workv1 "open-cluster-management.io/api/work/v1"
@@ -15,35 +41,149 @@ import (
 // WorkHandler handles work/manifestwork endpoints
 type WorkHandler struct {
 	maestroClient maestro.ClientInterface
+	encrypter     *envelope.Encrypter
+	resolver      *secretref.Resolver
+	charts        *workchart.Renderer
+	payloads      *workpayload.Fetcher
+	metadataStore workmeta.Store
+	schedules     workschedule.Store
+	queue         *workqueue.Queue
+	clusterLimit  *workrate.Limiter
+	operations    *operations.Registry
+	limits        WorkLimits
+	requiredTags  *RequiredTags
+	events        events.Publisher
+	capabilities  clustercaps.Store
+	compatibility string
+	history       workhistory.Store
+	residency     *residency.Checker
 	logger        *slog.Logger
+
+	// scheduleChecks authorize each scheduled submission, outermost first
+	scheduleChecks []mux.MiddlewareFunc
+}
+
+// WorkLimits caps the size of a single work request. Zero disables a limit.
+type WorkLimits struct {
+	// MaxManifests is the maximum number of manifests in one ManifestWork
+	MaxManifests int
+	// MaxPayloadBytes is the maximum JSON-encoded size of the ManifestWork
+	MaxPayloadBytes int
+	// MaxMessageBytes is the transport (MQTT) message size limit checked
+	// against the estimated CloudEvent size of the final ManifestWork
+	MaxMessageBytes int
+	// MaxChunks is the maximum number of ManifestWorks a chunked request may
+	// fan out to; it also scales MaxPayloadBytes for chunked requests
+	MaxChunks int
+}
+
+// WorkConfig holds optional dependencies for the work handler.
+type WorkConfig struct {
+	// Encrypter enables envelope encryption of Secret manifests; nil disables it
+	Encrypter *envelope.Encrypter
+	// Resolver enables resolution of secret reference placeholders; nil disables it
+	Resolver *secretref.Resolver
+	// Charts enables Helm chart work requests; nil rejects them
+	Charts *workchart.Renderer
+	// Payloads enables work requests referencing a stored payload; nil rejects them
+	Payloads *workpayload.Fetcher
+	// MetadataStore records submitted works and enables deduplication; nil disables both
+	MetadataStore workmeta.Store
+	// Schedules enables scheduled work requests; nil rejects them
+	Schedules workschedule.Store
+	// Queue limits concurrent submissions to Maestro; nil leaves them unlimited
+	Queue *workqueue.Queue
+	// ClusterLimit counts submissions per target management cluster and
+	// limits their rate; nil neither counts nor limits them
+	ClusterLimit *workrate.Limiter
+	// Operations tracks submissions in flight so operators can cancel them;
+	// nil tracks nothing
+	Operations *operations.Registry
+	Limits     WorkLimits
+	// RequiredTags rejects works created without the required tags; nil requires none
+	RequiredTags *RequiredTags
+	// Events publishes a work.created event for every work created; nil
+	// publishes nothing
+	Events events.Publisher
+	// Capabilities rejects works embedding manifests the target management
+	// cluster does not serve; nil, or a cluster with no recorded
+	// capabilities, skips the check
+	Capabilities clustercaps.Store
+	// AgentCompatibility is how works using features the target cluster's
+	// work agent is too old for are treated: clustercaps.CompatibilityWarn
+	// or CompatibilityBlock; empty, or a cluster with no recorded agent
+	// version, skips the check
+	AgentCompatibility string
+	// History serves the status history of works; nil disables it
+	History workhistory.Store
+	// Residency refuses works of accounts pinned to another region than
+	// this deployment's; nil refuses none
+	Residency *residency.Checker
 }
 
 // NewWorkHandler creates a new WorkHandler
-func NewWorkHandler(maestroClient maestro.ClientInterface, logger *slog.Logger) *WorkHandler {
+func NewWorkHandler(maestroClient maestro.ClientInterface, cfg WorkConfig, logger *slog.Logger) *WorkHandler {
 	return &WorkHandler{
 		maestroClient: maestroClient,
+		encrypter:     cfg.Encrypter,
+		resolver:      cfg.Resolver,
+		charts:        cfg.Charts,
+		payloads:      cfg.Payloads,
+		metadataStore: cfg.MetadataStore,
+		schedules:     cfg.Schedules,
+		queue:         cfg.Queue,
+		clusterLimit:  cfg.ClusterLimit,
+		operations:    cfg.Operations,
+		limits:        cfg.Limits,
+		requiredTags:  cfg.RequiredTags,
+		events:        cfg.Events,
+		capabilities:  cfg.Capabilities,
+		compatibility: cfg.AgentCompatibility,
+		history:       cfg.History,
+		residency:     cfg.Residency,
 		logger:        logger,
 	}
 }
 
-// WorkRequest represents the request payload for creating manifestwork
-type WorkRequest struct {
-	ClusterID string                 `json:"cluster_id"`
-	Data      map[string]interface{} `json:"data"`
-}
+// HeaderBackoff suggests, in milliseconds, how long a client turned away by a
+// saturated server should wait before retrying
+const HeaderBackoff = "X-Rosa-Backoff"
+
+var checksumPattern = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
 
 // Create handles POST /api/v0/work
 func (h *WorkHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
 	h.logger.Info("received work creation request", "account_id", accountID)
 
-	// Parse request body
-	var req WorkRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	// Keep the body as sent so a checksum can be verified against it
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.logger.Error("failed to read request body", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusBadRequest, "invalid-request", "Failed to read request body")
+		return
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	// Parse request body: a work request, or manifests uploaded as YAML or
+	// multipart files with the request's options in the query or form
+	var req apiv0.WorkRequest
+	if mediaType := uploadMediaType(r); mediaType != "" {
+		upload, err := decodeWorkUpload(r, mediaType)
+		if err != nil {
+			h.logger.Error("failed to decode work upload", "error", err, "media_type", mediaType, "account_id", accountID)
+			h.writeError(w, http.StatusBadRequest, "invalid-upload", err.Error())
+			return
+		}
+		req = *upload
+	} else if err := decodeJSON(r, &req); err != nil {
 		h.logger.Error("failed to decode request body", "error", err, "account_id", accountID)
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -54,6 +194,33 @@ func (h *WorkHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Chart != nil && req.PayloadRef != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", "chart cannot be combined with payload_ref")
+		return
+	}
+	if req.Chart != nil {
+		if req.Data != nil || req.Checksum != "" {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", "chart cannot be combined with data or checksum")
+			return
+		}
+		data, ok := h.renderChart(w, r, accountID, req.Chart)
+		if !ok {
+			return
+		}
+		req.Data = data
+	}
+	if req.PayloadRef != nil {
+		if req.Data != nil || req.Checksum != "" {
+			h.writeError(w, http.StatusBadRequest, "invalid-request", "payload_ref cannot be combined with data or checksum")
+			return
+		}
+		data, ok := h.fetchPayload(w, r, accountID, req.PayloadRef)
+		if !ok {
+			return
+		}
+		req.Data = data
+	}
+
 	// Validate data payload
 	if req.Data == nil {
 		h.logger.Error("missing data in request", "account_id", accountID)
@@ -61,10 +228,73 @@ func (h *WorkHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if req.Checksum != "" {
+		if !checksumPattern.MatchString(req.Checksum) {
+			h.writeError(w, http.StatusBadRequest, "invalid-checksum", "checksum must be a hex-encoded SHA-256 digest")
+			return
+		}
+		req.Checksum = strings.ToLower(req.Checksum)
+		if got := dataChecksum(body); got != req.Checksum {
+			h.logger.Warn("work payload checksum mismatch", "expected", req.Checksum, "got", got, "cluster_id", req.ClusterID, "account_id", accountID)
+			h.writeError(w, http.StatusBadRequest, "checksum-mismatch",
+				"checksum does not match the SHA-256 of data; the payload may have been truncated or corrupted in transit")
+			return
+		}
+	}
+
+	if req.Priority == "" {
+		req.Priority = workqueue.PriorityNormal
+	}
+	if !workqueue.ValidPriority(req.Priority) {
+		h.writeError(w, http.StatusBadRequest, "invalid-priority",
+			fmt.Sprintf("priority must be one of %s", strings.Join(workqueue.Priorities, ", ")))
+		return
+	}
+	if req.Priority == workqueue.PriorityCritical && !middleware.GetPrivileged(ctx) {
+		h.writeError(w, http.StatusForbidden, "priority-forbidden", "The platform-critical priority is reserved for privileged accounts")
+		return
+	}
+	if req.Requires != nil {
+		if problem := req.Requires.Validate(); problem != "" {
+			h.writeError(w, http.StatusBadRequest, "invalid-requirements", problem)
+			return
+		}
+	}
+
+	// Work submitted on behalf of a tenant is the tenant's, so it must carry
+	// the tenant's required tags
+	tenantAccountID := accountID
+	if req.OnBehalfOfAccount != "" {
+		if !h.checkOnBehalfOf(w, r, req) {
+			return
+		}
+		tenantAccountID = req.OnBehalfOfAccount
+	}
+	if !h.checkResidency(w, r, tenantAccountID) {
+		return
+	}
+
+	tags, err := middleware.ParseRequestTags(r)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request-tags", err.Error())
+		return
+	}
+	missing, err := h.requiredTags.missingWorkTags(ctx, tenantAccountID, tags)
+	if err != nil {
+		h.logger.Error("failed to get required tags", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "required-tags-unavailable", "Failed to check required tags")
+		return
+	}
+	if len(missing) > 0 {
+		h.writeError(w, http.StatusBadRequest, "missing-required-tags", missingTagsReason(missing))
+		return
+	}
+
 	// Log the received data
 	h.logger.Info("processing manifestwork creation",
 		"cluster_id", req.ClusterID,
 		"account_id", accountID,
+		"on_behalf_of_account", req.OnBehalfOfAccount,
 	)
 
 	// Convert the data map to JSON and then unmarshal into ManifestWork
@@ -95,13 +325,195 @@ func (h *WorkHandler) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	plan, hasPlan := middleware.GetPlan(r.Context())
+	if req.Chunk && hasPlan && !plan.BulkSubmission {
+		h.writeError(w, http.StatusForbidden, "plan-feature-unavailable",
+			fmt.Sprintf("Chunked work submission is not available on the %s plan", plan.Plan))
+		return
+	}
+	limits := h.limits
+	if hasPlan && plan.MaxManifests > 0 && (limits.MaxManifests == 0 || plan.MaxManifests < limits.MaxManifests) {
+		limits.MaxManifests = plan.MaxManifests
+	}
+	if reason := limits.check(len(manifestWork.Spec.Workload.Manifests), len(dataBytes), req.Chunk); reason != "" {
+		h.logger.Warn("work request exceeds limits", "reason", reason, "cluster_id", req.ClusterID, "account_id", accountID)
+		h.writeError(w, http.StatusRequestEntityTooLarge, "work-limit-exceeded", reason)
+		return
+	}
+	if !h.preflight(w, r, accountID, req.ClusterID, req.Requires, manifestWork) {
+		return
+	}
+
 	// Ensure the namespace matches the cluster_id
 	manifestWork.Namespace = req.ClusterID
 
+	if req.Schedule != nil {
+		h.createSchedule(w, r, accountID, req, tags)
+		return
+	}
+
+	// Hash the work as submitted, before secrets are resolved or encrypted,
+	// so identical retries map to the same hash
+	// Chunked works are tracked per chunk and are not deduplicated
+	var contentHash string
+	if h.metadataStore != nil && !req.Chunk {
+		contentHash, err = workmeta.ContentHash(manifestWork)
+		if err != nil {
+			h.logger.Error("failed to hash manifestwork", "error", err, "account_id", accountID)
+			h.writeError(w, http.StatusBadRequest, "invalid-manifestwork", "Failed to normalize ManifestWork manifests")
+			return
+		}
+
+		if existing := h.findDuplicate(r, tenantAccountID, req.ClusterID, contentHash); existing != nil {
+			h.logger.Info("returning existing manifestwork for duplicate submission",
+				"cluster_id", req.ClusterID,
+				"work_name", existing.Name,
+				"content_hash", contentHash,
+				"account_id", accountID,
+			)
+			response := workResponse(r, req.ClusterID, existing)
+			response.ContentHash = contentHash
+			response.Deduplicated = true
+			response.Checksum = req.Checksum
+
+			if writeDryRun(w, r, http.StatusOK, response) {
+				return
+			}
+			writeResponse(w, r, http.StatusOK, response)
+			return
+		}
+	}
+
+	// A dry run previews the payload as submitted: resolved secret values
+	// are never returned to the caller
+	var preview *workv1.ManifestWork
+	if middleware.IsDryRun(ctx) {
+		preview = manifestWork.DeepCopy()
+	}
+
+	// Resolve secret references before encryption so resolved values are protected too
+	if secretref.ContainsReference(manifestWork) {
+		if h.resolver == nil {
+			h.writeError(w, http.StatusBadRequest, "secret-references-unavailable", "Secret references are not enabled on this server")
+			return
+		}
+		count, err := h.resolver.ResolveManifestWork(ctx, secretref.Caller{
+			AccountID:  accountID,
+			CallerARN:  middleware.GetCallerARN(ctx),
+			Privileged: middleware.GetPrivileged(ctx),
+		}, manifestWork)
+		if err != nil {
+			h.logger.Error("failed to resolve secret references", "error", err, "cluster_id", req.ClusterID, "account_id", accountID)
+			switch {
+			case errors.Is(err, secretref.ErrForbidden):
+				h.writeError(w, http.StatusForbidden, "secret-reference-forbidden", "Not permitted to resolve a referenced secret")
+			case errors.Is(err, secretref.ErrInvalidReference):
+				h.writeError(w, http.StatusBadRequest, "invalid-secret-reference", err.Error())
+			default:
+				h.writeError(w, http.StatusBadGateway, "secret-resolution-failed", "Failed to resolve secret references")
+			}
+			return
+		}
+		h.logger.Info("resolved secret references", "cluster_id", req.ClusterID, "references", count, "account_id", accountID)
+	}
+
+	if req.EncryptSecrets {
+		if h.encrypter == nil {
+			h.writeError(w, http.StatusBadRequest, "encryption-unavailable", "Envelope encryption is not configured on this server")
+			return
+		}
+		count, err := h.encrypter.EncryptManifestWork(ctx, manifestWork)
+		if err != nil {
+			h.logger.Error("failed to encrypt manifestwork secrets", "error", err, "cluster_id", req.ClusterID, "account_id", accountID)
+			h.writeError(w, http.StatusInternalServerError, "encryption-failed", "Failed to encrypt Secret manifests")
+			return
+		}
+		h.logger.Info("envelope encrypted manifestwork secrets", "cluster_id", req.ClusterID, "secrets", count, "account_id", accountID)
+	}
+
+	// Wait for a submission slot; higher priorities are dispatched first.
+	// Dry runs submit nothing, so they take no slot.
+	if preview == nil {
+		if allowed, retryAfter := h.clusterLimit.Allow(ctx, req.ClusterID); !allowed {
+			h.logger.Warn("work submission rate limited", "cluster_id", req.ClusterID, "account_id", accountID)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			h.writeError(w, http.StatusTooManyRequests, "cluster-rate-limited",
+				fmt.Sprintf("Too many works are being submitted to management cluster %s; retry later", req.ClusterID))
+			return
+		}
+
+		// The submission is tracked from here on so operators can cancel it
+		var end func()
+		ctx, end = h.operations.Start(ctx, operations.Operation{
+			Type:      operations.TypeWorkSubmission,
+			State:     operations.StateQueued,
+			AccountID: accountID,
+			CallerARN: middleware.GetCallerARN(ctx),
+			ClusterID: req.ClusterID,
+			Name:      manifestWork.Name,
+			Priority:  req.Priority,
+		})
+		defer end()
+		r = r.WithContext(ctx)
+
+		release, err := h.queue.Acquire(ctx, req.Priority)
+		if err != nil {
+			h.logger.Warn("work submission not dispatched", "error", err, "priority", req.Priority, "cluster_id", req.ClusterID, "account_id", accountID)
+			if operations.Cancelled(ctx) {
+				h.writeCancelled(w)
+				return
+			}
+			h.setBackoff(w)
+			if errors.Is(err, workqueue.ErrQueueFull) {
+				h.writeError(w, http.StatusServiceUnavailable, "work-queue-full", "Too many work submissions are waiting; retry later")
+				return
+			}
+			h.writeError(w, http.StatusServiceUnavailable, "work-queue-timeout", "Timed out waiting for a work submission slot")
+			return
+		}
+		defer release()
+		h.operations.SetState(ctx, operations.StateRunning)
+	}
+
+	// Pre-flight the final payload against the transport message size limit;
+	// oversized events are otherwise dropped silently by the broker
+	if h.limits.MaxMessageBytes > 0 {
+		size, err := maestro.EstimateCloudEventSize(manifestWork)
+		if err != nil {
+			h.logger.Error("failed to estimate manifestwork cloudevent size", "error", err, "cluster_id", req.ClusterID, "account_id", accountID)
+			h.writeError(w, http.StatusBadRequest, "invalid-manifestwork", "Failed to encode ManifestWork for delivery")
+			return
+		}
+		if size > h.limits.MaxMessageBytes && req.Chunk {
+			h.createChunked(w, r, req.ClusterID, req.Checksum, req.OnBehalfOfAccount, manifestWork, preview)
+			return
+		}
+		if size > h.limits.MaxMessageBytes {
+			h.logger.Warn("manifestwork exceeds transport message size limit", "size", size, "limit", h.limits.MaxMessageBytes, "cluster_id", req.ClusterID, "account_id", accountID)
+			h.writeError(w, http.StatusRequestEntityTooLarge, "payload-exceeds-transport-limit",
+				fmt.Sprintf("Encoded ManifestWork is an estimated %d bytes, exceeding the transport message limit of %d (max_message_bytes); "+
+					"set chunk=true to split the manifests across multiple works, or move bulky content into templates", size, h.limits.MaxMessageBytes))
+			return
+		}
+	}
+
+	if preview != nil {
+		response := workPreview(req.ClusterID, preview)
+		response.ContentHash = contentHash
+		response.Checksum = req.Checksum
+		response.OnBehalfOfAccount = req.OnBehalfOfAccount
+		writeDryRun(w, r, http.StatusCreated, response)
+		return
+	}
+
 	// Create the ManifestWork via gRPC
 	result, err := h.maestroClient.CreateManifestWork(ctx, req.ClusterID, manifestWork)
 	if err != nil {
 		h.logger.Error("failed to create manifestwork", "error", err, "cluster_id", req.ClusterID, "account_id", accountID)
+		if operations.Cancelled(ctx) {
+			h.writeCancelled(w)
+			return
+		}
 		if maestroErr, ok := err.(*maestro.Error); ok {
 			h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
 			return
@@ -111,35 +523,198 @@ func (h *WorkHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Build response
-	response := map[string]interface{}{
-		"id":         string(result.UID),
-		"kind":       "ManifestWork",
-		"href":       "/api/v0/work/" + result.Name,
-		"cluster_id": req.ClusterID,
-		"name":       result.Name,
-		"status":     result.Status,
+	response := workResponse(r, req.ClusterID, result)
+	response.Checksum = req.Checksum
+	response.OnBehalfOfAccount = req.OnBehalfOfAccount
+
+	if h.metadataStore != nil {
+		response.ContentHash = contentHash
+		rec := &workmeta.Record{
+			ClusterID:         req.ClusterID,
+			WorkName:          result.Name,
+			WorkID:            string(result.UID),
+			AccountID:         accountID,
+			CallerARN:         middleware.GetCallerARN(ctx),
+			OnBehalfOfAccount: req.OnBehalfOfAccount,
+			ContentHash:       contentHash,
+			Checksum:          req.Checksum,
+		}
+		// The work exists in Maestro at this point, so a metadata failure only
+		// costs deduplication of later retries
+		if err := h.metadataStore.Put(ctx, rec); err != nil {
+			h.logger.Error("failed to record manifestwork metadata", "error", err, "cluster_id", req.ClusterID, "work_name", result.Name)
+		}
 	}
 
 	h.logger.Info("manifestwork created successfully",
 		"cluster_id", req.ClusterID,
 		"work_name", result.Name,
 		"account_id", accountID,
+		"on_behalf_of_account", req.OnBehalfOfAccount,
 	)
+	h.publishCreated(r, accountID, req.ClusterID, result.Name, map[string]string{"workId": string(result.UID)})
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
-	_ = json.NewEncoder(w).Encode(response)
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+// checkOnBehalfOf writes an error and returns false unless the caller may
+// submit req on behalf of its tenant account. The tenant is recorded in the
+// work metadata store, so submitting on behalf of another account needs one.
+func (h *WorkHandler) checkOnBehalfOf(w http.ResponseWriter, r *http.Request, req apiv0.WorkRequest) bool {
+	if !middleware.GetPrivileged(r.Context()) {
+		h.writeError(w, http.StatusForbidden, "on-behalf-of-forbidden", "Only privileged accounts may submit work on behalf of another account")
+		return false
+	}
+	if !accountIDPattern.MatchString(req.OnBehalfOfAccount) {
+		h.writeError(w, http.StatusBadRequest, "invalid-on-behalf-of-account", "on_behalf_of_account must be a 12-digit AWS account ID")
+		return false
+	}
+	if req.Schedule != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", "on_behalf_of_account cannot be combined with schedule")
+		return false
+	}
+	if h.metadataStore == nil {
+		h.writeError(w, http.StatusBadRequest, "work-metadata-unavailable", "Submitting work on behalf of another account requires the work metadata store")
+		return false
+	}
+	return true
+}
+
+// checkResidency writes an error and returns false when the tenant account
+// is pinned to another region than this deployment's. Failing to read the
+// account's residency refuses the work rather than risk it leaving the
+// pinned region.
+func (h *WorkHandler) checkResidency(w http.ResponseWriter, r *http.Request, tenantAccountID string) bool {
+	if h.residency == nil {
+		return true
+	}
+	err := h.residency.Check(r.Context(), tenantAccountID)
+	if residency.IsViolation(err) {
+		h.logger.Warn("refused work of an account pinned to another region", "error", err, "account_id", tenantAccountID)
+		h.writeError(w, http.StatusForbidden, "residency-violation", err.Error())
+		return false
+	}
+	if err != nil {
+		h.logger.Error("failed to check account residency", "error", err, "account_id", tenantAccountID)
+		h.writeError(w, http.StatusServiceUnavailable, "residency-unavailable", "Failed to check the account's data residency")
+		return false
+	}
+	return true
+}
+
+// check returns a description of the first limit exceeded, or "" if none is
+func (l WorkLimits) check(manifests, payloadBytes int, chunked bool) string {
+	if l.MaxManifests > 0 && manifests > l.MaxManifests {
+		return fmt.Sprintf("ManifestWork has %d manifests, exceeding the limit of %d (max_manifests)", manifests, l.MaxManifests)
+	}
+	maxPayload := l.MaxPayloadBytes
+	if chunked && l.MaxChunks > 0 {
+		maxPayload *= l.MaxChunks
+	}
+	if maxPayload > 0 && payloadBytes > maxPayload {
+		return fmt.Sprintf("ManifestWork payload is %d bytes, exceeding the limit of %d (max_payload_bytes)", payloadBytes, maxPayload)
+	}
+	return ""
+}
+
+// dataChecksum returns the hex SHA-256 of the data field of body exactly as
+// it appears there
+func dataChecksum(body []byte) string {
+	var raw struct {
+		Data json.RawMessage `json:"data"`
+	}
+	_ = json.Unmarshal(body, &raw)
+	sum := sha256.Sum256(raw.Data)
+	return hex.EncodeToString(sum[:])
+}
+
+// findDuplicate returns the tenant's live ManifestWork previously submitted
+// with the same content hash, or nil if there is none. Another tenant's work
+// is never returned, even for an identical spec on a shared cluster.
+func (h *WorkHandler) findDuplicate(r *http.Request, tenantAccountID, clusterID, contentHash string) *workv1.ManifestWork {
+	ctx := r.Context()
+
+	rec, err := h.metadataStore.FindByContentHash(ctx, tenantAccountID, clusterID, contentHash)
+	if err != nil {
+		h.logger.Error("failed to look up manifestwork by content hash", "error", err, "cluster_id", clusterID)
+		return nil
+	}
+	if rec == nil {
+		return nil
+	}
+	if rec.TenantAccountID != tenantAccountID {
+		h.logger.Warn("ignoring duplicate manifestwork of another tenant", "cluster_id", clusterID, "work_name", rec.WorkName)
+		return nil
+	}
+
+	// The recorded work may have been deleted since; only dedup against live works
+	existing, err := h.maestroClient.GetManifestWork(ctx, clusterID, rec.WorkName)
+	if err != nil {
+		h.logger.Debug("recorded manifestwork no longer available", "error", err, "cluster_id", clusterID, "work_name", rec.WorkName)
+		return nil
+	}
+
+	return existing
+}
+
+// workPreview describes the ManifestWork a dry run would have sent to Maestro
+func workPreview(clusterID string, mw *workv1.ManifestWork) apiv0.WorkPreview {
+	return apiv0.WorkPreview{
+		Kind:         "ManifestWork",
+		ClusterID:    clusterID,
+		Name:         mw.Name,
+		ManifestWork: mw,
+	}
+}
+
+func workResponse(r *http.Request, clusterID string, mw *workv1.ManifestWork) apiv0.Work {
+	return apiv0.Work{
+		ID:        string(mw.UID),
+		Kind:      "ManifestWork",
+		Href:      href(r, "/api/v0/work/"+mw.Name),
+		ClusterID: clusterID,
+		Name:      mw.Name,
+		Status:    mw.Status,
+	}
+}
+
+// QueueLoad returns the load of the submission queue, 0 without one
+func (h *WorkHandler) QueueLoad() float64 {
+	return h.queue.Load()
+}
+
+// publishCreated publishes a work.created event for the work named name on
+// clusterID, if events are published
+func (h *WorkHandler) publishCreated(r *http.Request, accountID, clusterID, name string, data map[string]string) {
+	if h.events == nil {
+		return
+	}
+	data["clusterId"] = clusterID
+	data["name"] = name
+	if callerARN := middleware.GetCallerARN(r.Context()); callerARN != "" {
+		data["callerArn"] = callerARN
+	}
+	event := events.New(events.TypeWorkCreated, accountID, clusterID+"/"+name, data)
+	event.TraceParent = middleware.GetTraceParent(r.Context())
+	h.events.Publish(r.Context(), event)
+}
+
+// setBackoff tells a submission turned away for lack of a slot when to retry,
+// from the current depth of the submission queue
+func (h *WorkHandler) setBackoff(w http.ResponseWriter) {
+	backoff := h.queue.Backoff()
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(backoff.Seconds()))))
+	w.Header().Set(HeaderBackoff, strconv.FormatInt(backoff.Milliseconds(), 10))
+}
+
+// writeCancelled answers a submission an operator cancelled
+func (h *WorkHandler) writeCancelled(w http.ResponseWriter) {
+	h.writeError(w, http.StatusConflict, "operation-cancelled", "The work submission was cancelled by an operator")
 }
 
 func (h *WorkHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 
-	resp := map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	}
-
-	_ = json.NewEncoder(w).Encode(resp)
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
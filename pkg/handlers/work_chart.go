@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workchart"
+)
+
+// renderChart renders chart into ManifestWork data, writing the error and
+// returning false if it cannot be
+func (h *WorkHandler) renderChart(w http.ResponseWriter, r *http.Request, accountID string, chart *apiv0.WorkChart) (map[string]interface{}, bool) {
+	if h.charts == nil {
+		h.writeError(w, http.StatusBadRequest, "charts-unavailable", "Helm chart rendering is not enabled on this server")
+		return nil, false
+	}
+
+	out, err := h.charts.Render(r.Context(), workchart.Chart{
+		Repository:  chart.Repository,
+		Version:     chart.Version,
+		Values:      chart.Values,
+		ReleaseName: chart.ReleaseName,
+		Namespace:   chart.Namespace,
+	})
+	if err != nil {
+		h.logger.Error("failed to render chart", "error", err, "repository", chart.Repository, "version", chart.Version, "account_id", accountID)
+		switch {
+		case errors.Is(err, workchart.ErrRegistryNotAllowed):
+			h.writeError(w, http.StatusForbidden, "chart-registry-forbidden", err.Error())
+		case errors.Is(err, workchart.ErrChartTooLarge):
+			h.writeError(w, http.StatusRequestEntityTooLarge, "chart-too-large", err.Error())
+		case errors.Is(err, workchart.ErrInvalidChart):
+			h.writeError(w, http.StatusBadRequest, "invalid-chart", err.Error())
+		default:
+			h.writeError(w, http.StatusBadGateway, "chart-pull-failed", "Failed to pull the chart from its registry")
+		}
+		return nil, false
+	}
+
+	docs, err := decodeManifests(strings.NewReader(out))
+	if err == nil && len(docs) == 0 {
+		err = errors.New("chart rendered no manifests")
+	}
+	var data map[string]interface{}
+	if err == nil {
+		data, err = wrapManifests(docs, chart.ReleaseName)
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-chart", err.Error())
+		return nil, false
+	}
+	return data, true
+}
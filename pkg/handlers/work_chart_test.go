@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workchart"
+)
+
+// fakeChartPuller serves a chart with one ConfigMap template
+type fakeChartPuller struct{}
+
+func (fakeChartPuller) Pull(ctx context.Context, ref string) ([]byte, error) {
+	files := map[string]string{
+		"app/Chart.yaml":               "apiVersion: v2\nname: app\nversion: 1.0.0\n",
+		"app/templates/configmap.yaml": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\ndata:\n  color: {{ .Values.color }}\n",
+	}
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content))})
+		_, _ = tw.Write([]byte(content))
+	}
+	_ = tw.Close()
+	_ = gz.Close()
+	return buf.Bytes(), nil
+}
+
+func TestWorkHandler_Create_Chart(t *testing.T) {
+	var submitted *workv1.ManifestWork
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			submitted = manifestWork
+			return manifestWork, nil
+		},
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	charts := workchart.NewRenderer(fakeChartPuller{}, workchart.Config{Registries: []string{"quay.io"}}, logger)
+
+	chart := map[string]interface{}{
+		"repository":   "oci://quay.io/example/app",
+		"version":      "1.0.0",
+		"values":       map[string]interface{}{"color": "blue"},
+		"release_name": "team-a",
+	}
+	tests := []struct {
+		name        string
+		charts      *workchart.Renderer
+		body        map[string]interface{}
+		expectCode  int
+		expectError string
+	}{
+		{
+			name:       "rendered",
+			charts:     charts,
+			body:       map[string]interface{}{"cluster_id": "test-cluster-123", "chart": chart},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:        "registry not allowed",
+			charts:      charts,
+			body:        map[string]interface{}{"cluster_id": "test-cluster-123", "chart": map[string]interface{}{"repository": "oci://docker.io/example/app", "version": "1.0.0"}},
+			expectCode:  http.StatusForbidden,
+			expectError: "chart-registry-forbidden",
+		},
+		{
+			name:        "chart and data",
+			charts:      charts,
+			body:        map[string]interface{}{"cluster_id": "test-cluster-123", "chart": chart, "data": map[string]interface{}{}},
+			expectCode:  http.StatusBadRequest,
+			expectError: "invalid-request",
+		},
+		{
+			name:        "charts not enabled",
+			body:        map[string]interface{}{"cluster_id": "test-cluster-123", "chart": chart},
+			expectCode:  http.StatusBadRequest,
+			expectError: "charts-unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted = nil
+			handler := NewWorkHandler(mockClient, WorkConfig{Charts: tt.charts}, logger)
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]interface{}
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("Expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+			if submitted == nil || submitted.Name != "team-a" || len(submitted.Spec.Workload.Manifests) != 1 {
+				t.Fatalf("Expected a ManifestWork team-a with the rendered ConfigMap, got %+v", submitted)
+			}
+			if raw := string(submitted.Spec.Workload.Manifests[0].Raw); !strings.Contains(raw, `"color":"blue"`) {
+				t.Errorf("Expected the chart values to be rendered, got %s", raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/fanout"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+// createChunked splits an oversized ManifestWork into a group of chunks that
+// each fit within the transport message limit and creates them in order.
+// If any chunk fails, the chunks already created are deleted so a group is
+// never left half-applied. A non-nil preview is the work as submitted for a
+// dry run, which previews its chunks instead. onBehalfOf is the tenant
+// account the chunks are recorded for, if any.
+func (h *WorkHandler) createChunked(w http.ResponseWriter, r *http.Request, clusterID, checksum, onBehalfOf string, manifestWork, preview *workv1.ManifestWork) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+
+	// Chunks are named after the group, so a generated name cannot be used
+	if manifestWork.Name == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-work-name", "metadata.name is required when chunk is set")
+		return
+	}
+
+	chunks, err := maestro.ChunkManifestWork(manifestWork, h.limits.MaxMessageBytes)
+	if err != nil {
+		if errors.Is(err, maestro.ErrManifestTooLarge) {
+			h.writeError(w, http.StatusRequestEntityTooLarge, "manifest-exceeds-transport-limit",
+				fmt.Sprintf("A single manifest cannot fit in one transport message (max_message_bytes): %v", err))
+			return
+		}
+		h.logger.Error("failed to chunk manifestwork", "error", err, "cluster_id", clusterID, "account_id", accountID)
+		h.writeError(w, http.StatusRequestEntityTooLarge, "work-limit-exceeded", err.Error())
+		return
+	}
+	if h.limits.MaxChunks > 0 && len(chunks) > h.limits.MaxChunks {
+		h.writeError(w, http.StatusRequestEntityTooLarge, "work-limit-exceeded",
+			fmt.Sprintf("ManifestWork would be split into %d chunks, exceeding the limit of %d (max_chunks)", len(chunks), h.limits.MaxChunks))
+		return
+	}
+
+	if preview != nil {
+		h.previewChunks(w, r, clusterID, checksum, preview)
+		return
+	}
+
+	created := make([]*workv1.ManifestWork, 0, len(chunks))
+	for _, chunk := range chunks {
+		result, err := h.maestroClient.CreateManifestWork(ctx, clusterID, chunk)
+		if err != nil {
+			h.logger.Error("failed to create manifestwork chunk", "error", err, "cluster_id", clusterID, "work_name", chunk.Name, "account_id", accountID)
+			h.rollbackChunks(r, clusterID, created)
+			if operations.Cancelled(ctx) {
+				h.writeCancelled(w)
+				return
+			}
+			if maestroErr, ok := err.(*maestro.Error); ok {
+				h.writeError(w, http.StatusBadGateway, maestroErr.Code, maestroErr.Reason)
+				return
+			}
+			h.writeError(w, http.StatusInternalServerError, "manifestwork-creation-failed", "Failed to create manifestwork")
+			return
+		}
+		created = append(created, result)
+	}
+
+	items := make([]apiv0.Work, 0, len(created))
+	for _, result := range created {
+		items = append(items, workResponse(r, clusterID, result))
+
+		if h.metadataStore != nil {
+			rec := &workmeta.Record{
+				ClusterID:         clusterID,
+				WorkName:          result.Name,
+				WorkID:            string(result.UID),
+				AccountID:         accountID,
+				CallerARN:         middleware.GetCallerARN(ctx),
+				OnBehalfOfAccount: onBehalfOf,
+				Checksum:          checksum,
+			}
+			if err := h.metadataStore.Put(ctx, rec); err != nil {
+				h.logger.Error("failed to record manifestwork metadata", "error", err, "cluster_id", clusterID, "work_name", result.Name)
+			}
+		}
+	}
+
+	h.logger.Info("chunked manifestwork created successfully",
+		"cluster_id", clusterID,
+		"work_group", manifestWork.Name,
+		"chunks", len(created),
+		"account_id", accountID,
+	)
+	h.publishCreated(r, accountID, clusterID, manifestWork.Name, map[string]string{"chunks": strconv.Itoa(len(created))})
+
+	response := apiv0.WorkGroup{
+		Kind:      "WorkGroup",
+		Name:      manifestWork.Name,
+		ClusterID: clusterID,
+		Href:      workGroupHref(r, manifestWork.Name, clusterID),
+		Items:     items,
+		Total:     len(items),
+		Checksum:  checksum,
+	}
+	writeResponse(w, r, http.StatusCreated, response)
+}
+
+// previewChunks answers a dry run of a chunked work with the chunks it splits
+// into. Like the preview of an unchunked work, the chunks hold the payload as
+// submitted, before secret references are resolved.
+func (h *WorkHandler) previewChunks(w http.ResponseWriter, r *http.Request, clusterID, checksum string, preview *workv1.ManifestWork) {
+	chunks, err := maestro.ChunkManifestWork(preview, h.limits.MaxMessageBytes)
+	if err != nil {
+		h.writeError(w, http.StatusRequestEntityTooLarge, "work-limit-exceeded", err.Error())
+		return
+	}
+	items := make([]apiv0.WorkPreview, 0, len(chunks))
+	for _, chunk := range chunks {
+		items = append(items, workPreview(clusterID, chunk))
+	}
+	response := apiv0.WorkGroupPreview{
+		Kind:      "WorkGroup",
+		Name:      preview.Name,
+		ClusterID: clusterID,
+		Items:     items,
+		Total:     len(items),
+		Checksum:  checksum,
+	}
+	writeDryRun(w, r, http.StatusCreated, response)
+}
+
+// rollbackChunks deletes the chunks of a group that failed part way through.
+// The deletes outlive a cancelled request so a cancelled group is still
+// rolled back.
+func (h *WorkHandler) rollbackChunks(r *http.Request, clusterID string, created []*workv1.ManifestWork) {
+	ctx := context.WithoutCancel(r.Context())
+	for _, chunk := range created {
+		if err := h.maestroClient.DeleteManifestWork(ctx, clusterID, chunk.Name); err != nil {
+			h.logger.Error("failed to roll back manifestwork chunk", "error", err, "cluster_id", clusterID, "work_name", chunk.Name)
+		}
+	}
+}
+
+// GetGroup handles GET /api/v0/work/groups/{id}. The group's owner is read
+// from the metadata of its first chunk; groups of other accounts are only
+// visible to privileged callers.
+func (h *WorkHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	group := mux.Vars(r)["id"]
+
+	clusterID := r.URL.Query().Get("cluster_id")
+	if clusterID == "" {
+		h.writeError(w, http.StatusBadRequest, "missing-cluster-id", "cluster_id query parameter is required")
+		return
+	}
+	if h.metadataStore == nil {
+		h.writeError(w, http.StatusNotFound, "work-metadata-unavailable", "The work metadata store is not enabled on this server")
+		return
+	}
+
+	rec, err := h.metadataStore.Get(ctx, clusterID, maestro.ChunkName(group, 0))
+	if err != nil {
+		h.logger.Error("failed to get work group metadata", "error", err, "cluster_id", clusterID, "work_group", group, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get work group")
+		return
+	}
+	// Groups of other accounts are indistinguishable from unknown ones
+	if rec == nil || (!middleware.GetPrivileged(ctx) && rec.TenantAccountID != accountID) {
+		h.writeError(w, http.StatusNotFound, "not-found", "Work group not found")
+		return
+	}
+
+	first, err := h.maestroClient.GetManifestWork(ctx, clusterID, maestro.ChunkName(group, 0))
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			h.writeError(w, http.StatusNotFound, "not-found", "Work group not found")
+			return
+		}
+		h.logger.Error("failed to get manifestwork chunk", "error", err, "cluster_id", clusterID, "work_group", group)
+		h.writeError(w, http.StatusInternalServerError, "manifestwork-get-failed", "Failed to get work group")
+		return
+	}
+
+	total, err := strconv.Atoi(first.Labels[maestro.LabelWorkGroupSize])
+	if err != nil || total < 1 || first.Labels[maestro.LabelWorkGroup] != group {
+		h.writeError(w, http.StatusNotFound, "not-found", "Work group not found")
+		return
+	}
+
+	indexes := make([]int, 0, total-1)
+	for i := 1; i < total; i++ {
+		indexes = append(indexes, i)
+	}
+	results := fanout.MapPartial(ctx, indexes, fanout.DefaultLimit, func(ctx context.Context, i int) (*workv1.ManifestWork, error) {
+		return h.maestroClient.GetManifestWork(ctx, clusterID, maestro.ChunkName(group, i))
+	})
+
+	chunks := []*workv1.ManifestWork{first}
+	for n, res := range results {
+		chunk := res.Value
+		if res.Err != nil {
+			// A missing chunk is reported rather than failing the whole group
+			h.logger.Warn("failed to get manifestwork chunk", "error", res.Err, "cluster_id", clusterID, "work_group", group, "index", indexes[n])
+			chunk = &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: maestro.ChunkName(group, indexes[n])}}
+		}
+		chunks = append(chunks, chunk)
+	}
+
+	items := make([]apiv0.WorkChunkStatus, 0, len(chunks))
+	for _, chunk := range chunks {
+		items = append(items, apiv0.WorkChunkStatus{
+			Name:   chunk.Name,
+			Status: chunk.Status,
+		})
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.WorkGroupStatus{
+		Kind:       "WorkGroupStatus",
+		Name:       group,
+		ClusterID:  clusterID,
+		Total:      total,
+		Items:      items,
+		Conditions: aggregateGroupConditions(chunks),
+	})
+}
+
+// aggregateGroupConditions combines chunk conditions into group conditions.
+// Applied and Available are True only when every chunk reports True, False if
+// any chunk reports False, and Unknown otherwise. Degraded is True if any
+// chunk is degraded.
+func aggregateGroupConditions(chunks []*workv1.ManifestWork) []metav1.Condition {
+	conditions := make([]metav1.Condition, 0, 3)
+	for _, condType := range []string{workv1.WorkApplied, workv1.WorkAvailable} {
+		status := metav1.ConditionTrue
+		for _, chunk := range chunks {
+			cond := meta.FindStatusCondition(chunk.Status.Conditions, condType)
+			switch {
+			case cond != nil && cond.Status == metav1.ConditionFalse:
+				status = metav1.ConditionFalse
+			case (cond == nil || cond.Status != metav1.ConditionTrue) && status == metav1.ConditionTrue:
+				status = metav1.ConditionUnknown
+			}
+		}
+		conditions = append(conditions, metav1.Condition{Type: condType, Status: status, Reason: "WorkGroup"})
+	}
+
+	degraded := metav1.ConditionFalse
+	for _, chunk := range chunks {
+		if meta.IsStatusConditionTrue(chunk.Status.Conditions, workv1.WorkDegraded) {
+			degraded = metav1.ConditionTrue
+			break
+		}
+	}
+	conditions = append(conditions, metav1.Condition{Type: workv1.WorkDegraded, Status: degraded, Reason: "WorkGroup"})
+
+	return conditions
+}
+
+func workGroupHref(r *http.Request, group, clusterID string) string {
+	return href(r, "/api/v0/work/groups/"+group+"?cluster_id="+url.QueryEscape(clusterID))
+}
@@ -0,0 +1,363 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+func chunkedWorkRequest(name string, manifests, blobSize int) *http.Request {
+	items := make([]map[string]interface{}, 0, manifests)
+	for i := 0; i < manifests; i++ {
+		items = append(items, map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]interface{}{"name": "cm-" + string(rune('a'+i))},
+			"data":       map[string]interface{}{"blob": strings.Repeat("x", blobSize)},
+		})
+	}
+
+	reqBody := map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"chunk":      true,
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": name},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": items,
+				},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+	return req.WithContext(ctx)
+}
+
+func TestWorkHandler_Create_Chunked(t *testing.T) {
+	var created []string
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			created = append(created, manifestWork.Name)
+			return manifestWork, nil
+		},
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{Limits: WorkLimits{MaxMessageBytes: 3000, MaxChunks: 16}}, logger)
+
+	w := httptest.NewRecorder()
+	handler.Create(w, chunkedWorkRequest("big-work", 3, 1500))
+
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp["kind"] != "WorkGroup" {
+		t.Errorf("Expected kind 'WorkGroup', got %v", resp["kind"])
+	}
+	if resp["href"] != "/api/v0/work/groups/big-work?cluster_id=test-cluster-123" {
+		t.Errorf("Unexpected href %v", resp["href"])
+	}
+	if len(created) != 3 {
+		t.Fatalf("Expected 3 chunks to be created, got %v", created)
+	}
+	if created[0] != "big-work-chunk-0" {
+		t.Errorf("Expected first chunk 'big-work-chunk-0', got %s", created[0])
+	}
+	if int(resp["total"].(float64)) != len(created) {
+		t.Errorf("Expected total %d, got %v", len(created), resp["total"])
+	}
+}
+
+func TestWorkHandler_Create_ChunkedRollback(t *testing.T) {
+	var deleted []string
+	calls := 0
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			calls++
+			if calls == 2 {
+				return nil, errors.New("connection reset")
+			}
+			return manifestWork, nil
+		},
+		deleteManifestWorkFunc: func(ctx context.Context, clusterName string, name string) error {
+			deleted = append(deleted, name)
+			return nil
+		},
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{Limits: WorkLimits{MaxMessageBytes: 3000}}, logger)
+
+	w := httptest.NewRecorder()
+	handler.Create(w, chunkedWorkRequest("big-work", 3, 1500))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected status code %d, got %d", http.StatusInternalServerError, w.Code)
+	}
+	if len(deleted) != 1 || deleted[0] != "big-work-chunk-0" {
+		t.Errorf("Expected the first chunk to be rolled back, got %v", deleted)
+	}
+}
+
+func TestWorkHandler_Create_ChunkedLimits(t *testing.T) {
+	tests := []struct {
+		name         string
+		workName     string
+		blobSize     int
+		maxChunks    int
+		expectedCode string
+		expectedHTTP int
+	}{
+		{
+			name:         "missing name",
+			workName:     "",
+			blobSize:     1500,
+			expectedCode: "missing-work-name",
+			expectedHTTP: http.StatusBadRequest,
+		},
+		{
+			name:         "too many chunks",
+			workName:     "big-work",
+			blobSize:     1500,
+			maxChunks:    2,
+			expectedCode: "work-limit-exceeded",
+			expectedHTTP: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "single manifest too large",
+			workName:     "big-work",
+			blobSize:     4000,
+			expectedCode: "manifest-exceeds-transport-limit",
+			expectedHTTP: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{Limits: WorkLimits{MaxMessageBytes: 3000, MaxChunks: tt.maxChunks}}, logger)
+
+			w := httptest.NewRecorder()
+			handler.Create(w, chunkedWorkRequest(tt.workName, 3, tt.blobSize))
+
+			if w.Code != tt.expectedHTTP {
+				t.Errorf("Expected status code %d, got %d", tt.expectedHTTP, w.Code)
+			}
+
+			var resp map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp["code"] != tt.expectedCode {
+				t.Errorf("Expected error code %q, got %v", tt.expectedCode, resp["code"])
+			}
+		})
+	}
+}
+
+func groupChunk(name string, index, size int, applied metav1.ConditionStatus) *workv1.ManifestWork {
+	return &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: maestro.ChunkName(name, index),
+			Labels: map[string]string{
+				maestro.LabelWorkGroup:     name,
+				maestro.LabelWorkGroupSize: strconv.Itoa(size),
+			},
+		},
+		Status: workv1.ManifestWorkStatus{
+			Conditions: []metav1.Condition{
+				{Type: workv1.WorkApplied, Status: applied},
+				{Type: workv1.WorkAvailable, Status: metav1.ConditionTrue},
+			},
+		},
+	}
+}
+
+// groupMetadata records the first chunk of a group as submitted by accountID
+func groupMetadata(group, accountID, onBehalfOf string) *mockWorkMetadataStore {
+	store := &mockWorkMetadataStore{}
+	_ = store.Put(context.Background(), &workmeta.Record{
+		ClusterID:         "test-cluster-123",
+		WorkName:          maestro.ChunkName(group, 0),
+		AccountID:         accountID,
+		OnBehalfOfAccount: onBehalfOf,
+	})
+	return store
+}
+
+func groupRequest(group, accountID string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/work/groups/"+group+"?cluster_id=test-cluster-123", nil)
+	req = mux.SetURLVars(req, map[string]string{"id": group})
+	return req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, accountID))
+}
+
+func TestWorkHandler_GetGroup(t *testing.T) {
+	chunks := map[string]*workv1.ManifestWork{
+		"big-work-chunk-0": groupChunk("big-work", 0, 2, metav1.ConditionTrue),
+		"big-work-chunk-1": groupChunk("big-work", 1, 2, metav1.ConditionFalse),
+	}
+	mockClient := &mockWorkMaestroClient{
+		getManifestWorkFunc: func(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+			if chunk, ok := chunks[name]; ok {
+				return chunk, nil
+			}
+			return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "manifestworks"}, name)
+		},
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{MetadataStore: groupMetadata("big-work", "123456789012", "")}, logger)
+
+	w := httptest.NewRecorder()
+	handler.GetGroup(w, groupRequest("big-work", "123456789012"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Kind       string             `json:"kind"`
+		Total      int                `json:"total"`
+		Conditions []metav1.Condition `json:"conditions"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Kind != "WorkGroupStatus" || resp.Total != 2 {
+		t.Errorf("Unexpected response kind=%s total=%d", resp.Kind, resp.Total)
+	}
+
+	expected := map[string]metav1.ConditionStatus{
+		workv1.WorkApplied:   metav1.ConditionFalse,
+		workv1.WorkAvailable: metav1.ConditionTrue,
+		workv1.WorkDegraded:  metav1.ConditionFalse,
+	}
+	for _, cond := range resp.Conditions {
+		if expected[cond.Type] != cond.Status {
+			t.Errorf("Expected condition %s=%s, got %s", cond.Type, expected[cond.Type], cond.Status)
+		}
+	}
+
+	// Unknown groups are reported as not found
+	w = httptest.NewRecorder()
+	handler.GetGroup(w, groupRequest("other", "123456789012"))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestWorkHandler_GetGroup_MissingChunk(t *testing.T) {
+	chunks := map[string]*workv1.ManifestWork{
+		"big-work-chunk-0": groupChunk("big-work", 0, 4, metav1.ConditionTrue),
+		"big-work-chunk-1": groupChunk("big-work", 1, 4, metav1.ConditionTrue),
+		"big-work-chunk-3": groupChunk("big-work", 3, 4, metav1.ConditionTrue),
+	}
+	mockClient := &mockWorkMaestroClient{
+		getManifestWorkFunc: func(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+			if chunk, ok := chunks[name]; ok {
+				return chunk, nil
+			}
+			return nil, apierrors.NewNotFound(schema.GroupResource{Resource: "manifestworks"}, name)
+		},
+	}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{MetadataStore: groupMetadata("big-work", "123456789012", "")}, logger)
+
+	w := httptest.NewRecorder()
+	handler.GetGroup(w, groupRequest("big-work", "123456789012"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Items []struct {
+			Name string `json:"name"`
+		} `json:"items"`
+		Conditions []metav1.Condition `json:"conditions"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Items) != 4 {
+		t.Fatalf("Expected 4 items, got %d", len(resp.Items))
+	}
+	for i, item := range resp.Items {
+		if want := maestro.ChunkName("big-work", i); item.Name != want {
+			t.Errorf("Expected item %d to be %s, got %s", i, want, item.Name)
+		}
+	}
+	for _, cond := range resp.Conditions {
+		if cond.Type == workv1.WorkApplied && cond.Status != metav1.ConditionUnknown {
+			t.Errorf("Expected Applied=Unknown with a missing chunk, got %s", cond.Status)
+		}
+	}
+}
+
+func TestWorkHandler_GetGroup_OtherAccount(t *testing.T) {
+	mockClient := &mockWorkMaestroClient{
+		getManifestWorkFunc: func(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+			return groupChunk("big-work", 0, 1, metav1.ConditionTrue), nil
+		},
+	}
+
+	tests := []struct {
+		name       string
+		owner      string
+		onBehalfOf string
+		accountID  string
+		privileged bool
+		wantStatus int
+	}{
+		{name: "owner", owner: "123456789012", accountID: "123456789012", wantStatus: http.StatusOK},
+		{name: "other account", owner: "123456789012", accountID: "210987654321", wantStatus: http.StatusNotFound},
+		{name: "tenant of work submitted on its behalf", owner: "000000000000", onBehalfOf: "123456789012", accountID: "123456789012", wantStatus: http.StatusOK},
+		{name: "privileged", owner: "123456789012", accountID: "000000000000", privileged: true, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewWorkHandler(mockClient, WorkConfig{MetadataStore: groupMetadata("big-work", tt.owner, tt.onBehalfOf)}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			req := groupRequest("big-work", tt.accountID)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyPrivileged, tt.privileged))
+			w := httptest.NewRecorder()
+			handler.GetGroup(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workhistory"
+)
+
+// History handles GET /api/v0/work/{id}/history, listing when the work's
+// conditions changed. With ?at=<RFC3339>, it also reports the status of each
+// condition at that time. Works of other accounts are only visible to
+// privileged callers.
+func (h *WorkHandler) History(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	if h.history == nil {
+		h.writeError(w, http.StatusNotFound, "work-history-unavailable", "The work status history is not enabled on this server")
+		return
+	}
+	workID := mux.Vars(r)["id"]
+
+	var at string
+	if v := r.URL.Query().Get("at"); v != "" {
+		t, err := clock.Parse(v)
+		if err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-parameter", "at must be an RFC 3339 time")
+			return
+		}
+		at = clock.Format(t)
+	}
+
+	transitions, err := h.history.List(r.Context(), workID)
+	if err != nil {
+		h.logger.Error("failed to list work transitions", "error", err, "work_id", workID, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get work history")
+		return
+	}
+	// Works of other accounts are indistinguishable from unknown ones
+	if len(transitions) == 0 || (!middleware.GetPrivileged(r.Context()) && transitions[0].TenantAccountID != accountID) {
+		h.writeError(w, http.StatusNotFound, "not-found", "No history is recorded for this work")
+		return
+	}
+
+	first := transitions[0]
+	resp := apiv0.WorkHistory{
+		Kind:      "WorkHistory",
+		ID:        workID,
+		ClusterID: first.ClusterID,
+		Name:      first.WorkName,
+		Items:     workTransitions(transitions),
+		Total:     len(transitions),
+	}
+	if at != "" {
+		resp.At = at
+		resp.Conditions = workTransitions(workhistory.At(transitions, at))
+	}
+	writeResponse(w, r, http.StatusOK, resp)
+}
+
+func workTransitions(transitions []*workhistory.Transition) []apiv0.WorkTransition {
+	out := make([]apiv0.WorkTransition, 0, len(transitions))
+	for _, t := range transitions {
+		out = append(out, apiv0.WorkTransition{
+			Type:           t.Type,
+			Status:         t.Status,
+			Reason:         t.Reason,
+			Message:        t.Message,
+			TransitionedAt: t.TransitionedAt,
+			ObservedAt:     t.ObservedAt,
+		})
+	}
+	return out
+}
@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workhistory"
+)
+
+type fakeWorkHistory struct {
+	transitions []*workhistory.Transition
+}
+
+func (f *fakeWorkHistory) Record(ctx context.Context, t *workhistory.Transition) error {
+	f.transitions = append(f.transitions, t)
+	return nil
+}
+
+func (f *fakeWorkHistory) List(ctx context.Context, workID string) ([]*workhistory.Transition, error) {
+	var out []*workhistory.Transition
+	for _, t := range f.transitions {
+		if t.WorkID == workID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeWorkHistory) Purge(ctx context.Context, workID string) (int, error) {
+	var kept []*workhistory.Transition
+	for _, t := range f.transitions {
+		if t.WorkID != workID {
+			kept = append(kept, t)
+		}
+	}
+	deleted := len(f.transitions) - len(kept)
+	f.transitions = kept
+	return deleted, nil
+}
+
+func TestWorkHandler_History(t *testing.T) {
+	history := &fakeWorkHistory{transitions: []*workhistory.Transition{
+		{WorkID: "uid-1", ClusterID: "mc-a", WorkName: "web", TenantAccountID: "111111111111", Type: "Applied", Status: "False", TransitionedAt: "2026-03-01T10:00:00Z", ObservedAt: "2026-03-01T10:00:20Z"},
+		{WorkID: "uid-1", ClusterID: "mc-a", WorkName: "web", TenantAccountID: "111111111111", Type: "Applied", Status: "True", TransitionedAt: "2026-03-01T11:00:00Z", ObservedAt: "2026-03-01T11:00:20Z"},
+	}}
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{History: history}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name       string
+		id         string
+		query      string
+		account    string
+		privileged bool
+		wantStatus int
+		wantAt     string
+		wantCond   string
+	}{
+		{name: "owner", id: "uid-1", account: "111111111111", wantStatus: http.StatusOK},
+		{name: "at a time", id: "uid-1", query: "?at=2026-03-01T11:30:00%2B01:00", account: "111111111111", wantStatus: http.StatusOK, wantAt: "2026-03-01T10:30:00Z", wantCond: "False"},
+		{name: "other account", id: "uid-1", account: "222222222222", wantStatus: http.StatusNotFound},
+		{name: "privileged", id: "uid-1", account: "222222222222", privileged: true, wantStatus: http.StatusOK},
+		{name: "unknown work", id: "uid-2", account: "111111111111", wantStatus: http.StatusNotFound},
+		{name: "invalid time", id: "uid-1", query: "?at=yesterday", account: "111111111111", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/work/"+tt.id+"/history"+tt.query, nil)
+			req = mux.SetURLVars(withAccount(req, tt.account, tt.privileged), map[string]string{"id": tt.id})
+			rec := httptest.NewRecorder()
+
+			handler.History(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var resp apiv0.WorkHistory
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Kind != "WorkHistory" || resp.ClusterID != "mc-a" || resp.Name != "web" || resp.Total != 2 {
+				t.Errorf("unexpected history %+v", resp)
+			}
+			if resp.At != tt.wantAt {
+				t.Errorf("at = %q, want %q", resp.At, tt.wantAt)
+			}
+			if tt.wantCond != "" && (len(resp.Conditions) != 1 || resp.Conditions[0].Status != tt.wantCond) {
+				t.Errorf("expected Applied to be %s at %s, got %+v", tt.wantCond, tt.wantAt, resp.Conditions)
+			}
+		})
+	}
+}
+
+func TestWorkHandler_History_Disabled(t *testing.T) {
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/work/uid-1/history", nil)
+	req = mux.SetURLVars(withAccount(req, "111111111111", false), map[string]string{"id": "uid-1"})
+	rec := httptest.NewRecorder()
+
+	handler.History(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
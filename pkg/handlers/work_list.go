@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+// List handles GET /api/v0/work, listing the works recorded in the work
+// metadata store as belonging to the caller's account: those it submitted
+// itself and those a privileged account submitted on its behalf
+func (h *WorkHandler) List(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
+	if h.metadataStore == nil {
+		h.writeError(w, http.StatusNotFound, "work-metadata-unavailable", "The work metadata store is not enabled on this server")
+		return
+	}
+
+	records, err := h.metadataStore.ListByTenant(r.Context(), accountID)
+	if err != nil {
+		h.logger.Error("failed to list work metadata", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list works")
+		return
+	}
+
+	clusterID := r.URL.Query().Get("cluster_id")
+	items := make([]apiv0.WorkRecord, 0, len(records))
+	for _, rec := range records {
+		if clusterID == "" || rec.ClusterID == clusterID {
+			items = append(items, workRecordResponse(r, rec))
+		}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.WorkList{
+		Kind:  "WorkList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+func workRecordResponse(r *http.Request, rec *workmeta.Record) apiv0.WorkRecord {
+	return apiv0.WorkRecord{
+		ID:                rec.WorkID,
+		Kind:              "ManifestWork",
+		Href:              href(r, "/api/v0/work/"+rec.WorkName),
+		ClusterID:         rec.ClusterID,
+		Name:              rec.WorkName,
+		SubmittedBy:       rec.CallerARN,
+		AccountID:         rec.AccountID,
+		CreatedAt:         rec.CreatedAt,
+		OnBehalfOfAccount: rec.OnBehalfOfAccount,
+		ContentHash:       rec.ContentHash,
+		Checksum:          rec.Checksum,
+	}
+}
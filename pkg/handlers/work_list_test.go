@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+const onBehalfOfWork = `{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"w1"},"spec":{"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}]}}}`
+
+func TestWorkHandler_Create_OnBehalfOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		privileged bool
+		tenant     string
+		store      bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "privileged", privileged: true, tenant: "222222222222", store: true, wantStatus: http.StatusCreated},
+		{name: "not privileged", tenant: "222222222222", store: true, wantStatus: http.StatusForbidden, wantCode: "on-behalf-of-forbidden"},
+		{name: "invalid account", privileged: true, tenant: "tenant", store: true, wantStatus: http.StatusBadRequest, wantCode: "invalid-on-behalf-of-account"},
+		{name: "no metadata store", privileged: true, tenant: "222222222222", wantStatus: http.StatusBadRequest, wantCode: "work-metadata-unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockWorkMaestroClient{
+				createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+					created := manifestWork.DeepCopy()
+					created.UID = "test-uid-123"
+					return created, nil
+				},
+			}
+			cfg := WorkConfig{}
+			store := &mockWorkMetadataStore{}
+			if tt.store {
+				cfg.MetadataStore = store
+			}
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			handler := NewWorkHandler(mockClient, cfg, logger)
+
+			body := `{"cluster_id":"c1","on_behalf_of_account":"` + tt.tenant + `","data":` + onBehalfOfWork + `}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", strings.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111111111111")
+			ctx = context.WithValue(ctx, middleware.ContextKeyPrivileged, tt.privileged)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if tt.wantCode != "" {
+				if resp["code"] != tt.wantCode {
+					t.Errorf("Expected error code %q, got %v", tt.wantCode, resp["code"])
+				}
+				return
+			}
+			if resp["on_behalf_of_account"] != tt.tenant {
+				t.Errorf("Expected on_behalf_of_account %s, got %v", tt.tenant, resp["on_behalf_of_account"])
+			}
+			if len(store.records) != 1 || store.records[0].AccountID != "111111111111" || store.records[0].OnBehalfOfAccount != tt.tenant {
+				t.Errorf("Unexpected metadata records: %+v", store.records)
+			}
+		})
+	}
+}
+
+func TestWorkHandler_List(t *testing.T) {
+	store := &mockWorkMetadataStore{records: []*workmeta.Record{
+		{ClusterID: "c1", WorkName: "own", AccountID: "222222222222"},
+		{ClusterID: "c2", WorkName: "managed", AccountID: "111111111111", OnBehalfOfAccount: "222222222222"},
+		{ClusterID: "c1", WorkName: "platform", AccountID: "111111111111"},
+	}}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{MetadataStore: store}, logger)
+
+	list := func(query string) map[string]interface{} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/work"+query, nil)
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "222222222222"))
+		w := httptest.NewRecorder()
+		handler.List(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+		var resp map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return resp
+	}
+
+	resp := list("")
+	items := resp["items"].([]interface{})
+	if len(items) != 2 {
+		t.Fatalf("Expected the tenant's 2 works, got %v", items)
+	}
+	managed := items[1].(map[string]interface{})
+	if managed["name"] != "managed" || managed["on_behalf_of_account"] != "222222222222" || managed["account_id"] != "111111111111" {
+		t.Errorf("Unexpected work submitted on behalf of the tenant: %v", managed)
+	}
+
+	resp = list("?cluster_id=c2")
+	if resp["total"] != float64(1) {
+		t.Errorf("Expected 1 work on c2, got %v", resp["total"])
+	}
+}
+
+func TestWorkHandler_List_MetadataStoreDisabled(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{}, logger)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/work", nil)
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "222222222222"))
+	w := httptest.NewRecorder()
+	handler.List(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
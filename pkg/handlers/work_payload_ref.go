@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workpayload"
+)
+
+// fetchPayload fetches ref into ManifestWork data, writing the error and
+// returning false if it cannot be
+func (h *WorkHandler) fetchPayload(w http.ResponseWriter, r *http.Request, accountID string, ref *apiv0.WorkPayloadRef) (map[string]interface{}, bool) {
+	if h.payloads == nil {
+		h.writeError(w, http.StatusBadRequest, "payload-refs-unavailable", "Payload references are not enabled on this server")
+		return nil, false
+	}
+
+	payload, err := h.payloads.Fetch(r.Context(), workpayload.Ref{URI: ref.URI, Digest: ref.Digest, SignatureBundle: ref.SignatureBundle})
+	if err != nil {
+		h.logger.Error("failed to fetch work payload", "error", err, "uri", ref.URI, "digest", ref.Digest, "account_id", accountID)
+		switch {
+		case errors.Is(err, workpayload.ErrSignatureRequired), errors.Is(err, workpayload.ErrSignatureInvalid):
+			h.logger.Warn("work payload signature verification", "result", "rejected", "error", err,
+				"uri", ref.URI, "digest", ref.Digest, "account_id", accountID)
+			code := "payload-signature-invalid"
+			if errors.Is(err, workpayload.ErrSignatureRequired) {
+				code = "payload-signature-required"
+			}
+			h.writeError(w, http.StatusForbidden, code, err.Error())
+		case errors.Is(err, workpayload.ErrSourceNotAllowed):
+			h.writeError(w, http.StatusForbidden, "payload-source-forbidden", err.Error())
+		case errors.Is(err, workpayload.ErrTooLarge):
+			h.writeError(w, http.StatusRequestEntityTooLarge, "payload-too-large", err.Error())
+		case errors.Is(err, workpayload.ErrDigestMismatch):
+			h.writeError(w, http.StatusBadRequest, "payload-digest-mismatch", err.Error())
+		case errors.Is(err, workpayload.ErrInvalidRef):
+			h.writeError(w, http.StatusBadRequest, "invalid-payload-ref", err.Error())
+		default:
+			h.writeError(w, http.StatusBadGateway, "payload-fetch-failed", "Failed to fetch the referenced payload")
+		}
+		return nil, false
+	}
+	if payload.Signer != nil {
+		h.logger.Info("work payload signature verification", "result", "verified",
+			"uri", ref.URI, "digest", ref.Digest, "signer_issuer", payload.Signer.Issuer,
+			"signer_subject", payload.Signer.Subject, "account_id", accountID)
+	}
+
+	docs, err := decodeManifests(bytes.NewReader(payload.Data))
+	if err == nil && len(docs) == 0 {
+		err = errors.New("payload contains no manifests")
+	}
+	var data map[string]interface{}
+	if err == nil {
+		data, err = wrapManifests(docs, ref.Name)
+	}
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-payload", err.Error())
+		return nil, false
+	}
+	return data, true
+}
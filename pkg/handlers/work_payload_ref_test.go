@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workpayload"
+)
+
+// fakePayloadS3 serves uploadManifests for every object
+type fakePayloadS3 struct{}
+
+func (fakePayloadS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{
+		Body:          io.NopCloser(bytes.NewBufferString(uploadManifests)),
+		ContentLength: aws.Int64(int64(len(uploadManifests))),
+	}, nil
+}
+
+// rejectingVerifier rejects every signature
+type rejectingVerifier struct{}
+
+func (rejectingVerifier) Verify(bundleJSON []byte, digest string) (*workpayload.Signer, error) {
+	return nil, workpayload.ErrSignatureInvalid
+}
+
+func TestWorkHandler_Create_PayloadRef(t *testing.T) {
+	var submitted *workv1.ManifestWork
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			submitted = manifestWork
+			return manifestWork, nil
+		},
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	payloads := workpayload.NewFetcher(nil, fakePayloadS3{}, workpayload.Config{
+		Registries: []string{"quay.io"},
+		Buckets:    []string{"bundles"},
+		Signatures: rejectingVerifier{},
+	}, logger)
+
+	sum := sha256.Sum256([]byte(uploadManifests))
+	ref := map[string]interface{}{
+		"uri":    "s3://bundles/team-a.yaml",
+		"digest": "sha256:" + hex.EncodeToString(sum[:]),
+		"name":   "team-a",
+	}
+	tests := []struct {
+		name        string
+		payloads    *workpayload.Fetcher
+		body        map[string]interface{}
+		expectCode  int
+		expectError string
+	}{
+		{
+			name:       "fetched",
+			payloads:   payloads,
+			body:       map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": ref},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:     "digest mismatch",
+			payloads: payloads,
+			body: map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": map[string]interface{}{
+				"uri": "s3://bundles/team-a.yaml", "digest": "sha256:" + hex.EncodeToString(make([]byte, 32)),
+			}},
+			expectCode:  http.StatusBadRequest,
+			expectError: "payload-digest-mismatch",
+		},
+		{
+			name:     "bucket not allowed",
+			payloads: payloads,
+			body: map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": map[string]interface{}{
+				"uri": "s3://other/team-a.yaml", "digest": ref["digest"],
+			}},
+			expectCode:  http.StatusForbidden,
+			expectError: "payload-source-forbidden",
+		},
+		{
+			name:     "unsigned OCI payload",
+			payloads: payloads,
+			body: map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": map[string]interface{}{
+				"uri": "oci://quay.io/example/bundles", "digest": ref["digest"],
+			}},
+			expectCode:  http.StatusForbidden,
+			expectError: "payload-signature-required",
+		},
+		{
+			name:     "invalid signature",
+			payloads: payloads,
+			body: map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": map[string]interface{}{
+				"uri": "oci://quay.io/example/bundles", "digest": ref["digest"], "signature_bundle": map[string]interface{}{},
+			}},
+			expectCode:  http.StatusForbidden,
+			expectError: "payload-signature-invalid",
+		},
+		{
+			name:        "payload_ref and data",
+			payloads:    payloads,
+			body:        map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": ref, "data": map[string]interface{}{}},
+			expectCode:  http.StatusBadRequest,
+			expectError: "invalid-request",
+		},
+		{
+			name:        "payload refs not enabled",
+			body:        map[string]interface{}{"cluster_id": "test-cluster-123", "payload_ref": ref},
+			expectCode:  http.StatusBadRequest,
+			expectError: "payload-refs-unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted = nil
+			handler := NewWorkHandler(mockClient, WorkConfig{Payloads: tt.payloads}, logger)
+			body, _ := json.Marshal(tt.body)
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]interface{}
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("Expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+			if submitted == nil || submitted.Name != "team-a" || len(submitted.Spec.Workload.Manifests) != 2 {
+				t.Fatalf("Expected a ManifestWork team-a wrapping the 2 fetched manifests, got %+v", submitted)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+)
+
+// HeaderWarning carries warnings about an accepted request, as
+// 299 - "<text>" like the Kubernetes API server
+const HeaderWarning = "Warning"
+
+// preflight checks a work against the recorded capabilities of clusterID
+// before it is submitted. A work embedding manifests whose types the cluster
+// is not known to serve, which would otherwise only fail once the agent
+// applies them, is rejected, as is one whose requirements the cluster does
+// not meet. Features the cluster's agent is too old for are warned about or
+// rejected, by the compatibility mode. Clusters with no recorded capabilities only meet empty
+// requirements, and failing to look them up only lets through works
+// without requirements. It reports whether the work may proceed, having
+// written the response when not.
+func (h *WorkHandler) preflight(w http.ResponseWriter, r *http.Request, accountID, clusterID string, requires *clustercaps.Requirements, mw *workv1.ManifestWork) bool {
+	if h.capabilities == nil {
+		if requires != nil {
+			h.writeError(w, http.StatusBadRequest, "capabilities-disabled", "Management cluster capabilities are not enabled, so requires cannot be checked")
+			return false
+		}
+		return true
+	}
+
+	caps, err := h.capabilities.Get(r.Context(), clusterID)
+	if err != nil {
+		if requires != nil {
+			h.logger.Error("failed to get management cluster capabilities", "error", err, "cluster_id", clusterID, "account_id", accountID)
+			h.writeError(w, http.StatusServiceUnavailable, "capabilities-unavailable", "Failed to check the management cluster against the work's requirements")
+			return false
+		}
+		h.logger.Warn("failed to get management cluster capabilities, skipping pre-flight check", "error", err, "cluster_id", clusterID, "account_id", accountID)
+		return true
+	}
+
+	if requires != nil {
+		if unmet := requires.Unmet(caps); len(unmet) > 0 {
+			h.logger.Info("work rejected by cluster requirements", "unmet", unmet, "cluster_id", clusterID, "account_id", accountID)
+			h.writeError(w, http.StatusUnprocessableEntity, "cluster-requirements-unmet",
+				fmt.Sprintf("Management cluster %s does not meet the work's requirements: %s", clusterID, strings.Join(unmet, "; ")))
+			return false
+		}
+	}
+	if caps == nil {
+		return true
+	}
+
+	unsupported, err := clustercaps.Unsupported(caps, mw)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-manifestwork", err.Error())
+		return false
+	}
+	if len(unsupported) > 0 {
+		h.logger.Info("work rejected by pre-flight check", "unsupported", unsupported, "cluster_id", clusterID, "account_id", accountID)
+		h.writeError(w, http.StatusUnprocessableEntity, "unsupported-manifest-kinds",
+			fmt.Sprintf("Management cluster %s does not serve %s", clusterID, strings.Join(unsupported, ", ")))
+		return false
+	}
+
+	if h.compatibility != clustercaps.CompatibilityWarn && h.compatibility != clustercaps.CompatibilityBlock {
+		return true
+	}
+	incompatible := clustercaps.Incompatible(caps, mw)
+	if len(incompatible) == 0 {
+		return true
+	}
+	if h.compatibility == clustercaps.CompatibilityBlock {
+		h.logger.Info("work rejected by agent compatibility check", "incompatible", incompatible, "agent_version", caps.AgentVersion, "cluster_id", clusterID, "account_id", accountID)
+		h.writeError(w, http.StatusUnprocessableEntity, "agent-incompatible",
+			fmt.Sprintf("Management cluster %s cannot apply this work: %s", clusterID, strings.Join(incompatible, "; ")))
+		return false
+	}
+	h.logger.Warn("work uses features the cluster's agent is too old for", "incompatible", incompatible, "agent_version", caps.AgentVersion, "cluster_id", clusterID, "account_id", accountID)
+	for _, warning := range incompatible {
+		w.Header().Add(HeaderWarning, fmt.Sprintf("299 - %q", warning))
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+// fakeCapabilities is an in-memory clustercaps.Store
+type fakeCapabilities struct {
+	caps map[string]*clustercaps.Capabilities
+	err  error
+}
+
+func (f *fakeCapabilities) Get(ctx context.Context, clusterID string) (*clustercaps.Capabilities, error) {
+	return f.caps[clusterID], f.err
+}
+
+func (f *fakeCapabilities) Put(ctx context.Context, caps *clustercaps.Capabilities) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.caps[caps.ClusterID] = caps
+	return nil
+}
+
+func TestWorkHandler_Create_Preflight(t *testing.T) {
+	known := &clustercaps.Capabilities{
+		ClusterID:         "mc-a",
+		APIResources:      []string{"v1/ConfigMap", "apps/v1/Deployment"},
+		KubernetesVersion: "1.29.4",
+		CRDs:              []string{"servicemonitors.monitoring.coreos.com"},
+	}
+	configMap := [][2]string{{"v1", "ConfigMap"}}
+
+	tests := []struct {
+		name       string
+		clusterID  string
+		kinds      [][2]string
+		requires   *clustercaps.Requirements
+		err        error
+		wantStatus int
+		wantReason string
+	}{
+		{name: "served kinds", clusterID: "mc-a", kinds: [][2]string{{"v1", "ConfigMap"}, {"apps/v1", "Deployment"}}, wantStatus: http.StatusCreated},
+		{name: "unserved kinds", clusterID: "mc-a", kinds: [][2]string{{"v1", "ConfigMap"}, {"monitoring.coreos.com/v1", "ServiceMonitor"}, {"apps/v1beta1", "Deployment"}},
+			wantStatus: http.StatusUnprocessableEntity, wantReason: "Management cluster mc-a does not serve apps/v1beta1/Deployment, monitoring.coreos.com/v1/ServiceMonitor"},
+		{name: "cluster without capabilities", clusterID: "mc-b", kinds: [][2]string{{"monitoring.coreos.com/v1", "ServiceMonitor"}}, wantStatus: http.StatusCreated},
+		{name: "store unavailable", clusterID: "mc-a", kinds: [][2]string{{"monitoring.coreos.com/v1", "ServiceMonitor"}}, err: errors.New("throttled"), wantStatus: http.StatusCreated},
+		{name: "requirements met", clusterID: "mc-a", kinds: configMap,
+			requires: &clustercaps.Requirements{KubernetesVersion: ">= 1.28", CRDs: []string{"servicemonitors.monitoring.coreos.com"}}, wantStatus: http.StatusCreated},
+		{name: "requirements unmet", clusterID: "mc-a", kinds: configMap,
+			requires:   &clustercaps.Requirements{KubernetesVersion: "^1.30", CRDs: []string{"prometheusrules.monitoring.coreos.com"}},
+			wantStatus: http.StatusUnprocessableEntity, wantReason: "kubernetes version 1.29.4 does not satisfy ^1.30; CRD prometheusrules.monitoring.coreos.com is not installed"},
+		{name: "requirements on a cluster without capabilities", clusterID: "mc-b", kinds: configMap,
+			requires: &clustercaps.Requirements{AgentVersion: ">= 0.5"}, wantStatus: http.StatusUnprocessableEntity, wantReason: "agent version is not recorded"},
+		{name: "requirements with the store unavailable", clusterID: "mc-a", kinds: configMap,
+			requires: &clustercaps.Requirements{KubernetesVersion: ">= 1.28"}, err: errors.New("throttled"), wantStatus: http.StatusServiceUnavailable},
+		{name: "invalid requirements", clusterID: "mc-a", kinds: configMap,
+			requires: &clustercaps.Requirements{KubernetesVersion: "newest"}, wantStatus: http.StatusBadRequest, wantReason: "is not a version constraint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			mockClient := &mockWorkMaestroClient{
+				createManifestWorkFunc: func(ctx context.Context, clusterName string, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+					created = true
+					return mw, nil
+				},
+			}
+			caps := &fakeCapabilities{caps: map[string]*clustercaps.Capabilities{"mc-a": known}, err: tt.err}
+			handler := NewWorkHandler(mockClient, WorkConfig{Capabilities: caps}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			var manifests []map[string]interface{}
+			for i, k := range tt.kinds {
+				manifests = append(manifests, map[string]interface{}{
+					"apiVersion": k[0],
+					"kind":       k[1],
+					"metadata":   map[string]interface{}{"name": "m" + string(rune('a'+i)), "namespace": "default"},
+				})
+			}
+			body, _ := json.Marshal(map[string]interface{}{
+				"cluster_id": tt.clusterID,
+				"requires":   tt.requires,
+				"data": map[string]interface{}{
+					"apiVersion": "work.open-cluster-management.io/v1",
+					"kind":       "ManifestWork",
+					"metadata":   map[string]interface{}{"name": "test-work"},
+					"spec":       map[string]interface{}{"workload": map[string]interface{}{"manifests": manifests}},
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if created != (tt.wantStatus == http.StatusCreated) {
+				t.Errorf("expected the work to be created only when it passes, created=%v", created)
+			}
+			if tt.wantReason != "" && !strings.Contains(w.Body.String(), tt.wantReason) {
+				t.Errorf("expected reason %q, got %s", tt.wantReason, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWorkHandler_Create_AgentCompatibility(t *testing.T) {
+	tests := []struct {
+		mode        string
+		wantStatus  int
+		wantWarning bool
+	}{
+		{mode: clustercaps.CompatibilityOff, wantStatus: http.StatusCreated},
+		{mode: clustercaps.CompatibilityWarn, wantStatus: http.StatusCreated, wantWarning: true},
+		{mode: clustercaps.CompatibilityBlock, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode, func(t *testing.T) {
+			mockClient := &mockWorkMaestroClient{
+				createManifestWorkFunc: func(ctx context.Context, clusterName string, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+					return mw, nil
+				},
+			}
+			caps := &fakeCapabilities{caps: map[string]*clustercaps.Capabilities{
+				"mc-a": {ClusterID: "mc-a", APIResources: []string{"v1/ConfigMap"}, AgentVersion: "0.8.0"},
+			}}
+			handler := NewWorkHandler(mockClient, WorkConfig{Capabilities: caps, AgentCompatibility: tt.mode}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			body := `{"cluster_id":"mc-a","data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"test-work"},"spec":{` +
+				`"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c","namespace":"default"}}]},` +
+				`"manifestConfigs":[{"resourceIdentifier":{"resource":"configmaps","name":"c","namespace":"default"},"updateStrategy":{"type":"ServerSideApply"}}]}}}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012"))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			warning := w.Header().Get(HeaderWarning)
+			if tt.wantWarning != (warning != "") {
+				t.Errorf("unexpected warning %q", warning)
+			}
+			if tt.wantWarning && !strings.Contains(warning, "ServerSideApply update strategy requires work agent 0.9.0") {
+				t.Errorf("expected the warning to name the feature, got %q", warning)
+			}
+		})
+	}
+}
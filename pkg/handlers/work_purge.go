@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+)
+
+// PurgeTargets returns the targets deleting an account's work records: the
+// status history of its works, then their metadata, then its schedules.
+// Works still applied on management clusters are left to the operator
+// offboarding the tenant.
+func (h *WorkHandler) PurgeTargets() []purge.Target {
+	var targets []purge.Target
+	if h.metadataStore != nil {
+		if h.history != nil {
+			targets = append(targets, purge.Target{Name: "work-history", Purge: h.purgeHistory})
+		}
+		targets = append(targets, purge.Target{Name: "work-metadata", Purge: h.purgeMetadata})
+	}
+	if h.schedules != nil {
+		targets = append(targets, purge.Target{Name: "work-schedules", Purge: h.schedules.Purge})
+	}
+	return targets
+}
+
+// purgeHistory deletes the status history of the account's works, which is
+// found through their metadata
+func (h *WorkHandler) purgeHistory(ctx context.Context, accountID string) (int, error) {
+	records, err := h.metadataStore.ListByTenant(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, rec := range records {
+		n, err := h.history.Purge(ctx, rec.WorkID)
+		deleted += n
+		if err != nil {
+			return deleted, err
+		}
+	}
+	return deleted, nil
+}
+
+func (h *WorkHandler) purgeMetadata(ctx context.Context, accountID string) (int, error) {
+	records, err := h.metadataStore.ListByTenant(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	for _, rec := range records {
+		if err := h.metadataStore.Delete(ctx, rec.ClusterID, rec.WorkName); err != nil {
+			return deleted, err
+		}
+		deleted++
+	}
+	return deleted, nil
+}
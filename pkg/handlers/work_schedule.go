@@ -0,0 +1,303 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workschedule"
+)
+
+// createSchedule stores a validated work request to be submitted by the
+// scheduler instead of creating it now
+func (h *WorkHandler) createSchedule(w http.ResponseWriter, r *http.Request, accountID string, req apiv0.WorkRequest, tags map[string]string) {
+	ctx := r.Context()
+
+	if h.schedules == nil {
+		h.writeError(w, http.StatusBadRequest, "schedules-unavailable", "Scheduled work is not enabled on this server")
+		return
+	}
+
+	schedule := req.Schedule
+	now := time.Now().UTC()
+	sched := &workschedule.Schedule{
+		AccountID: accountID,
+		ClusterID: req.ClusterID,
+		Tags:      tags,
+		CallerARN: middleware.GetCallerARN(ctx),
+	}
+	if delegate, ok := middleware.LookupDelegateAccountID(ctx); ok {
+		sched.DelegateAccountID = delegate
+	}
+	switch {
+	case (schedule.RunAt == nil) == (schedule.Cron == ""):
+		h.writeError(w, http.StatusBadRequest, "invalid-schedule", "Exactly one of schedule.run_at and schedule.cron is required")
+		return
+	case schedule.RunAt != nil:
+		if !schedule.RunAt.After(now) {
+			h.writeError(w, http.StatusBadRequest, "invalid-schedule", "schedule.run_at must be in the future")
+			return
+		}
+		sched.RunAt = clock.Format(*schedule.RunAt)
+		sched.NextRunAt = schedule.RunAt.Unix()
+	default:
+		if err := workschedule.ValidateCron(schedule.Cron); err != nil {
+			h.writeError(w, http.StatusBadRequest, "invalid-schedule", err.Error())
+			return
+		}
+		sched.Cron = schedule.Cron
+		sched.NextRunAt = sched.Next(now)
+	}
+
+	// Each run replays the request as submitted, without its schedule. The
+	// replayed data is re-encoded, so its checksum, verified now, is dropped.
+	req.Schedule = nil
+	req.Checksum = ""
+	// A chart is rendered and a payload reference fetched again on each run
+	// rather than replaying today's manifests
+	if req.Chart != nil || req.PayloadRef != nil {
+		req.Data = nil
+	}
+	body, err := json.Marshal(req)
+	if err != nil {
+		h.logger.Error("failed to marshal scheduled work request", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to schedule work")
+		return
+	}
+	sched.Request = string(body)
+
+	if writeDryRun(w, r, http.StatusAccepted, scheduleResponse(r, sched)) {
+		return
+	}
+
+	if err := h.schedules.Create(ctx, sched); err != nil {
+		h.logger.Error("failed to create work schedule", "error", err, "cluster_id", req.ClusterID, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "schedule-creation-failed", "Failed to schedule work")
+		return
+	}
+
+	h.logger.Info("work scheduled",
+		"schedule_id", sched.ScheduleID,
+		"cluster_id", req.ClusterID,
+		"run_at", sched.RunAt,
+		"cron", sched.Cron,
+		"account_id", accountID,
+	)
+
+	writeResponse(w, r, http.StatusAccepted, scheduleResponse(r, sched))
+}
+
+// ListSchedules handles GET /api/v0/work/schedules
+func (h *WorkHandler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok || !h.schedulesEnabled(w) {
+		return
+	}
+
+	schedules, err := h.schedules.List(r.Context(), accountID)
+	if err != nil {
+		h.logger.Error("failed to list work schedules", "error", err, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to list work schedules")
+		return
+	}
+
+	status := r.URL.Query().Get("status")
+	items := make([]apiv0.WorkSchedule, 0, len(schedules))
+	for _, sched := range schedules {
+		if status == "" || sched.Status == status {
+			items = append(items, scheduleResponse(r, sched))
+		}
+	}
+
+	writeResponse(w, r, http.StatusOK, apiv0.WorkScheduleList{
+		Kind:  "WorkScheduleList",
+		Items: items,
+		Total: len(items),
+	})
+}
+
+// GetSchedule handles GET /api/v0/work/schedules/{id}
+func (h *WorkHandler) GetSchedule(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok || !h.schedulesEnabled(w) {
+		return
+	}
+	scheduleID := mux.Vars(r)["id"]
+
+	sched, err := h.schedules.Get(r.Context(), accountID, scheduleID)
+	if err != nil {
+		h.logger.Error("failed to get work schedule", "error", err, "schedule_id", scheduleID, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to get work schedule")
+		return
+	}
+	if sched == nil {
+		h.writeError(w, http.StatusNotFound, "not-found", "Work schedule not found")
+		return
+	}
+
+	writeResponse(w, r, http.StatusOK, scheduleResponse(r, sched))
+}
+
+// CancelSchedule handles DELETE /api/v0/work/schedules/{id}
+// Works already submitted by the schedule are left in place.
+func (h *WorkHandler) CancelSchedule(w http.ResponseWriter, r *http.Request) {
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok || !h.schedulesEnabled(w) {
+		return
+	}
+	scheduleID := mux.Vars(r)["id"]
+
+	if middleware.IsDryRun(r.Context()) {
+		h.dryRunCancelSchedule(w, r, accountID, scheduleID)
+		return
+	}
+
+	_, err := h.schedules.Cancel(r.Context(), accountID, scheduleID)
+	switch {
+	case errors.Is(err, workschedule.ErrScheduleNotFound):
+		h.writeError(w, http.StatusNotFound, "not-found", "Work schedule not found")
+		return
+	case errors.Is(err, workschedule.ErrScheduleNotActive):
+		h.writeError(w, http.StatusConflict, "schedule-not-active", "Work schedule has already completed or been cancelled")
+		return
+	case err != nil:
+		h.logger.Error("failed to cancel work schedule", "error", err, "schedule_id", scheduleID, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to cancel work schedule")
+		return
+	}
+
+	h.logger.Info("work schedule cancelled", "schedule_id", scheduleID, "account_id", accountID, "caller_arn", middleware.GetCallerARN(r.Context()))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// dryRunCancelSchedule checks the schedule exists and is still active
+func (h *WorkHandler) dryRunCancelSchedule(w http.ResponseWriter, r *http.Request, accountID, scheduleID string) {
+	sched, err := h.schedules.Get(r.Context(), accountID, scheduleID)
+	switch {
+	case err != nil:
+		h.logger.Error("failed to get work schedule", "error", err, "schedule_id", scheduleID, "account_id", accountID)
+		h.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to cancel work schedule")
+	case sched == nil:
+		h.writeError(w, http.StatusNotFound, "not-found", "Work schedule not found")
+	case sched.Status != workschedule.StatusActive:
+		h.writeError(w, http.StatusConflict, "schedule-not-active", "Work schedule has already completed or been cancelled")
+	default:
+		sched.Status = workschedule.StatusCancelled
+		writeDryRun(w, r, http.StatusNoContent, scheduleResponse(r, sched))
+	}
+}
+
+// WithScheduleChecks sets the middleware authorizing the work routes, which
+// each scheduled submission then passes through as if the caller who
+// scheduled it sent it again. Identity, delegation, privileged status and
+// permissions are all checked at run time, not when the work was scheduled.
+func (h *WorkHandler) WithScheduleChecks(checks ...mux.MiddlewareFunc) *WorkHandler {
+	h.scheduleChecks = checks
+	return h
+}
+
+// CancelSchedules cancels every active schedule of an account, such as when
+// the account is disabled, logging the schedules it fails to cancel
+func (h *WorkHandler) CancelSchedules(ctx context.Context, accountID string) {
+	if h.schedules == nil {
+		return
+	}
+	schedules, err := h.schedules.List(ctx, accountID)
+	if err != nil {
+		h.logger.Warn("failed to list work schedules to cancel", "error", err, "account_id", accountID)
+		return
+	}
+	for _, sched := range schedules {
+		if sched.Status != workschedule.StatusActive {
+			continue
+		}
+		if _, err := h.schedules.Cancel(ctx, accountID, sched.ScheduleID); err != nil && !errors.Is(err, workschedule.ErrScheduleNotActive) {
+			h.logger.Warn("failed to cancel work schedule", "error", err, "account_id", accountID, "schedule_id", sched.ScheduleID)
+			continue
+		}
+		h.logger.Info("work schedule cancelled", "schedule_id", sched.ScheduleID, "account_id", accountID, "reason", "account-disabled")
+	}
+}
+
+// SubmitScheduled submits a schedule's work request through the schedule
+// checks and Create, with the caller and request tags it was scheduled with.
+// It implements workschedule.Submitter.
+func (h *WorkHandler) SubmitScheduled(ctx context.Context, sched *workschedule.Schedule) (string, error) {
+	// A delegated schedule is sent from the caller's own account and
+	// resolved to the target account again
+	accountID := sched.AccountID
+	if sched.DelegateAccountID != "" {
+		accountID = sched.DelegateAccountID
+	}
+	ctx = context.WithValue(ctx, middleware.ContextKeyAccountID, accountID)
+	ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, sched.CallerARN)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "/api/v0/work", strings.NewReader(sched.Request))
+	if err != nil {
+		return "", fmt.Errorf("failed to build scheduled work request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if sched.DelegateAccountID != "" {
+		req.Header.Set(middleware.HeaderTargetAccountID, sched.AccountID)
+	}
+	for key, value := range sched.Tags {
+		req.Header.Set(middleware.HeaderRequestTagPrefix+key, value)
+	}
+
+	var handler http.Handler = http.HandlerFunc(h.Create)
+	for i := len(h.scheduleChecks) - 1; i >= 0; i-- {
+		handler = h.scheduleChecks[i](handler)
+	}
+	rec := &replayRecorder{header: make(http.Header), status: http.StatusOK}
+	handler.ServeHTTP(rec, req)
+
+	var resp struct {
+		Name   string `json:"name"`
+		Code   string `json:"code"`
+		Reason string `json:"reason"`
+	}
+	_ = json.Unmarshal(rec.body.Bytes(), &resp)
+	if rec.status != http.StatusCreated && rec.status != http.StatusOK {
+		return "", fmt.Errorf("work creation failed with %d %s: %s", rec.status, resp.Code, resp.Reason)
+	}
+	return resp.Name, nil
+}
+
+func (h *WorkHandler) schedulesEnabled(w http.ResponseWriter) bool {
+	if h.schedules == nil {
+		h.writeError(w, http.StatusNotFound, "schedules-unavailable", "Scheduled work is not enabled on this server")
+		return false
+	}
+	return true
+}
+
+func scheduleResponse(r *http.Request, sched *workschedule.Schedule) apiv0.WorkSchedule {
+	resp := apiv0.WorkSchedule{
+		ID:           sched.ScheduleID,
+		Kind:         "WorkSchedule",
+		Href:         href(r, "/api/v0/work/schedules/"+sched.ScheduleID),
+		ClusterID:    sched.ClusterID,
+		Status:       sched.Status,
+		CreatedBy:    sched.CallerARN,
+		CreatedAt:    sched.CreatedAt,
+		RunCount:     sched.RunCount,
+		RunAt:        sched.RunAt,
+		Cron:         sched.Cron,
+		LastRunAt:    sched.LastRunAt,
+		LastWorkName: sched.LastWorkName,
+		LastError:    sched.LastError,
+	}
+	if sched.NextRunAt > 0 {
+		resp.NextRunAt = clock.Format(time.Unix(sched.NextRunAt, 0))
+	}
+	return resp
+}
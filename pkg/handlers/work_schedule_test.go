@@ -0,0 +1,253 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workschedule"
+)
+
+// mockScheduleStore keeps created schedules in memory
+type mockScheduleStore struct {
+	workschedule.Store
+	created   []*workschedule.Schedule
+	listed    []*workschedule.Schedule
+	cancelled []string
+}
+
+func (m *mockScheduleStore) Create(ctx context.Context, s *workschedule.Schedule) error {
+	s.ScheduleID = "schedule-1"
+	s.Status = workschedule.StatusActive
+	m.created = append(m.created, s)
+	return nil
+}
+
+func (m *mockScheduleStore) List(ctx context.Context, accountID string) ([]*workschedule.Schedule, error) {
+	return m.listed, nil
+}
+
+func (m *mockScheduleStore) Cancel(ctx context.Context, accountID, scheduleID string) (*workschedule.Schedule, error) {
+	m.cancelled = append(m.cancelled, scheduleID)
+	return &workschedule.Schedule{AccountID: accountID, ScheduleID: scheduleID, Status: workschedule.StatusCancelled}, nil
+}
+
+func scheduledWorkRequest(schedule map[string]interface{}) *http.Request {
+	body, _ := json.Marshal(map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"schedule":   schedule,
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "nightly-config"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []map[string]interface{}{
+						{"apiVersion": "v1", "kind": "ConfigMap", "metadata": map[string]interface{}{"name": "c", "namespace": "default"}},
+					},
+				},
+			},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(middleware.HeaderRequestTagPrefix+"team", "platform")
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "123456789012")
+	ctx = context.WithValue(ctx, middleware.ContextKeyCallerARN, "arn:aws:iam::123456789012:role/deployer")
+	return req.WithContext(ctx)
+}
+
+func TestWorkHandler_Create_Scheduled(t *testing.T) {
+	tests := []struct {
+		name       string
+		schedule   map[string]interface{}
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "run at", schedule: map[string]interface{}{"run_at": time.Now().Add(time.Hour).Format(time.RFC3339)}, wantStatus: http.StatusAccepted},
+		{name: "cron", schedule: map[string]interface{}{"cron": "0 2 * * *"}, wantStatus: http.StatusAccepted},
+		{name: "run at in the past", schedule: map[string]interface{}{"run_at": time.Now().Add(-time.Hour).Format(time.RFC3339)}, wantStatus: http.StatusBadRequest, wantCode: "invalid-schedule"},
+		{name: "invalid cron", schedule: map[string]interface{}{"cron": "every night"}, wantStatus: http.StatusBadRequest, wantCode: "invalid-schedule"},
+		{name: "both", schedule: map[string]interface{}{"cron": "@daily", "run_at": time.Now().Add(time.Hour).Format(time.RFC3339)}, wantStatus: http.StatusBadRequest, wantCode: "invalid-schedule"},
+		{name: "neither", schedule: map[string]interface{}{}, wantStatus: http.StatusBadRequest, wantCode: "invalid-schedule"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maestroClient := &mockWorkMaestroClient{}
+			schedules := &mockScheduleStore{}
+			handler := NewWorkHandler(maestroClient, WorkConfig{Schedules: schedules}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			w := httptest.NewRecorder()
+			handler.Create(w, scheduledWorkRequest(tt.schedule))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantCode != "" {
+				if resp["code"] != tt.wantCode {
+					t.Errorf("expected code %q, got %v", tt.wantCode, resp["code"])
+				}
+				return
+			}
+
+			if len(schedules.created) != 1 {
+				t.Fatalf("expected one schedule, got %d", len(schedules.created))
+			}
+			sched := schedules.created[0]
+			if sched.NextRunAt <= time.Now().Unix() || sched.Tags["team"] != "platform" || sched.CallerARN == "" {
+				t.Errorf("unexpected schedule %+v", sched)
+			}
+			if resp["kind"] != "WorkSchedule" || resp["id"] != "schedule-1" || resp["next_run_at"] == nil {
+				t.Errorf("unexpected response %v", resp)
+			}
+		})
+	}
+}
+
+func TestWorkHandler_Create_ScheduledUnavailable(t *testing.T) {
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	handler.Create(w, scheduledWorkRequest(map[string]interface{}{"cron": "@daily"}))
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected status 400, got %d", w.Code)
+	}
+}
+
+func TestWorkHandler_SubmitScheduled(t *testing.T) {
+	var submitted *workv1.ManifestWork
+	maestroClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			submitted = manifestWork
+			return &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: manifestWork.Name, Namespace: clusterName, UID: "uid-1"}}, nil
+		},
+	}
+	schedules := &mockScheduleStore{}
+	handler := NewWorkHandler(maestroClient, WorkConfig{
+		Schedules:    schedules,
+		RequiredTags: &RequiredTags{Work: []string{"team"}},
+	}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	w := httptest.NewRecorder()
+	handler.Create(w, scheduledWorkRequest(map[string]interface{}{"cron": "@daily"}))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	if submitted != nil {
+		t.Fatal("scheduled work was submitted immediately")
+	}
+
+	name, err := handler.SubmitScheduled(context.Background(), schedules.created[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "nightly-config" || submitted == nil || submitted.Namespace != "test-cluster-123" {
+		t.Errorf("unexpected submission: name=%q work=%+v", name, submitted)
+	}
+
+	// Each run is checked like a new request
+	sched := *schedules.created[0]
+	sched.Tags = nil
+	if _, err := handler.SubmitScheduled(context.Background(), &sched); err == nil {
+		t.Error("expected the run without its required tags to fail")
+	}
+}
+
+// scheduleChecker allows work while allowed is set, as Cedar policies would
+type scheduleChecker struct {
+	authz.Checker
+	allowed    bool
+	privileged bool
+	authorized int
+}
+
+func (c *scheduleChecker) IsPrivileged(ctx context.Context, accountID string) (bool, error) {
+	return c.privileged, nil
+}
+
+func (c *scheduleChecker) Authorize(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+	c.authorized++
+	return c.allowed, nil
+}
+
+func TestWorkHandler_SubmitScheduled_Reauthorizes(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	submitted := 0
+	maestroClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			submitted++
+			return &workv1.ManifestWork{ObjectMeta: metav1.ObjectMeta{Name: manifestWork.Name, Namespace: clusterName, UID: "uid-1"}}, nil
+		},
+	}
+	schedules := &mockScheduleStore{}
+	checker := &scheduleChecker{allowed: true, privileged: true}
+	handler := NewWorkHandler(maestroClient, WorkConfig{Schedules: schedules}, logger).
+		WithScheduleChecks(middleware.NewPrivileged(checker, logger).CheckPrivileged, middleware.NewAuthz(checker, true, "", logger).Authorize)
+
+	w := httptest.NewRecorder()
+	handler.Create(w, scheduledWorkRequest(map[string]interface{}{"cron": "@daily"}))
+	if w.Code != http.StatusAccepted {
+		t.Fatalf("expected status 202, got %d: %s", w.Code, w.Body.String())
+	}
+	sched := schedules.created[0]
+
+	// A privileged account is not authorized by its policies
+	if _, err := handler.SubmitScheduled(context.Background(), sched); err != nil {
+		t.Fatal(err)
+	}
+	if checker.authorized != 0 {
+		t.Errorf("expected the privileged run to skip policies, got %d checks", checker.authorized)
+	}
+
+	// Once demoted, its policies decide each run
+	checker.privileged = false
+	if _, err := handler.SubmitScheduled(context.Background(), sched); err != nil {
+		t.Fatal(err)
+	}
+	if checker.authorized != 1 {
+		t.Errorf("expected the run to be authorized, got %d checks", checker.authorized)
+	}
+
+	// and a revoked permission stops the schedule's next run
+	checker.allowed = false
+	if _, err := handler.SubmitScheduled(context.Background(), sched); err == nil || !strings.Contains(err.Error(), "access-denied") {
+		t.Errorf("expected the run to be denied, got %v", err)
+	}
+	if submitted != 2 {
+		t.Errorf("expected 2 works submitted, got %d", submitted)
+	}
+}
+
+func TestWorkHandler_CancelSchedules(t *testing.T) {
+	schedules := &mockScheduleStore{listed: []*workschedule.Schedule{
+		{AccountID: "123456789012", ScheduleID: "schedule-1", Status: workschedule.StatusActive},
+		{AccountID: "123456789012", ScheduleID: "schedule-2", Status: workschedule.StatusCompleted},
+		{AccountID: "123456789012", ScheduleID: "schedule-3", Status: workschedule.StatusActive},
+	}}
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{Schedules: schedules}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	handler.CancelSchedules(context.Background(), "123456789012")
+
+	if want := []string{"schedule-1", "schedule-3"}; !slices.Equal(schedules.cancelled, want) {
+		t.Errorf("cancelled %v, want %v", schedules.cancelled, want)
+	}
+}
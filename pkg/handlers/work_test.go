@@ -3,16 +3,28 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"io"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+	"github.com/openshift/rosa-regional-platform-api/pkg/residency"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workqueue"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workrate"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	workv1 "open-cluster-management.io/api/work/v1"
 )
@@ -20,6 +32,8 @@ import (
 // mockWorkMaestroClient is a mock implementation for work tests
 type mockWorkMaestroClient struct {
 	createManifestWorkFunc func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error)
+	getManifestWorkFunc    func(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error)
+	deleteManifestWorkFunc func(ctx context.Context, clusterName string, name string) error
 }
 
 func (m *mockWorkMaestroClient) CreateManifestWork(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
@@ -41,6 +55,10 @@ func (m *mockWorkMaestroClient) GetConsumer(ctx context.Context, id string) (*ma
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockWorkMaestroClient) DeleteConsumer(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+
 func (m *mockWorkMaestroClient) ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
 	return nil, errors.New("not implemented")
 }
@@ -50,6 +68,9 @@ func (m *mockWorkMaestroClient) GetResourceBundle(ctx context.Context, id string
 }
 
 func (m *mockWorkMaestroClient) GetManifestWork(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+	if m.getManifestWorkFunc != nil {
+		return m.getManifestWorkFunc(ctx, clusterName, name)
+	}
 	return nil, errors.New("not implemented")
 }
 
@@ -58,6 +79,9 @@ func (m *mockWorkMaestroClient) DeleteResourceBundle(ctx context.Context, id str
 }
 
 func (m *mockWorkMaestroClient) DeleteManifestWork(ctx context.Context, clusterName string, name string) error {
+	if m.deleteManifestWorkFunc != nil {
+		return m.deleteManifestWorkFunc(ctx, clusterName, name)
+	}
 	return nil
 }
 
@@ -80,7 +104,7 @@ func TestWorkHandler_Create_Success(t *testing.T) {
 	}
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	// Create request body
 	reqBody := map[string]interface{}{
@@ -150,7 +174,7 @@ func TestWorkHandler_Create_Success(t *testing.T) {
 func TestWorkHandler_Create_MissingClusterID(t *testing.T) {
 	mockClient := &mockWorkMaestroClient{}
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	reqBody := map[string]interface{}{
 		"data": map[string]interface{}{
@@ -186,7 +210,7 @@ func TestWorkHandler_Create_MissingClusterID(t *testing.T) {
 func TestWorkHandler_Create_MissingData(t *testing.T) {
 	mockClient := &mockWorkMaestroClient{}
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	reqBody := map[string]interface{}{
 		"cluster_id": "test-cluster-123",
@@ -219,7 +243,7 @@ func TestWorkHandler_Create_MissingData(t *testing.T) {
 func TestWorkHandler_Create_InvalidManifestWork(t *testing.T) {
 	mockClient := &mockWorkMaestroClient{}
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	reqBody := map[string]interface{}{
 		"cluster_id": "test-cluster-123",
@@ -255,7 +279,7 @@ func TestWorkHandler_Create_InvalidManifestWork(t *testing.T) {
 func TestWorkHandler_Create_WrongKind(t *testing.T) {
 	mockClient := &mockWorkMaestroClient{}
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	// Send a valid Deployment instead of ManifestWork
 	reqBody := map[string]interface{}{
@@ -306,7 +330,7 @@ func TestWorkHandler_Create_MaestroError(t *testing.T) {
 	}
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	reqBody := map[string]interface{}{
 		"cluster_id": "test-cluster-123",
@@ -356,7 +380,7 @@ func TestWorkHandler_Create_GenericError(t *testing.T) {
 	}
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	reqBody := map[string]interface{}{
 		"cluster_id": "test-cluster-123",
@@ -401,7 +425,7 @@ func TestWorkHandler_Create_GenericError(t *testing.T) {
 func TestWorkHandler_Create_InvalidJSON(t *testing.T) {
 	mockClient := &mockWorkMaestroClient{}
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader([]byte("invalid json")))
 	req.Header.Set("Content-Type", "application/json")
@@ -444,7 +468,7 @@ func TestWorkHandler_Create_WithDeployment(t *testing.T) {
 	}
 
 	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
-	handler := NewWorkHandler(mockClient, logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
 
 	reqBody := map[string]interface{}{
 		"cluster_id": "test-cluster-456",
@@ -520,3 +544,779 @@ func TestWorkHandler_Create_WithDeployment(t *testing.T) {
 		t.Errorf("Expected name to be nginx-work, got %v", resp["name"])
 	}
 }
+
+func TestWorkHandler_Create_EncryptSecretsUnavailable(t *testing.T) {
+	mockClient := &mockWorkMaestroClient{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
+
+	reqBody := map[string]interface{}{
+		"cluster_id":      "test-cluster-123",
+		"encrypt_secrets": true,
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata": map[string]interface{}{
+				"name": "test-work",
+			},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	handler.Create(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp["code"] != "encryption-unavailable" {
+		t.Errorf("Expected error code 'encryption-unavailable', got %v", resp["code"])
+	}
+}
+
+func TestWorkHandler_Create_SecretReferencesUnavailable(t *testing.T) {
+	mockClient := &mockWorkMaestroClient{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
+
+	reqBody := map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata": map[string]interface{}{
+				"name": "test-work",
+			},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []map[string]interface{}{
+						{
+							"apiVersion": "v1",
+							"kind":       "Secret",
+							"metadata": map[string]interface{}{
+								"name": "creds",
+							},
+							"stringData": map[string]interface{}{
+								"token": "{{resolve:ssm:arn:aws:ssm:us-east-1:111122223333:parameter/token}}",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	handler.Create(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status code %d, got %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if resp["code"] != "secret-references-unavailable" {
+		t.Errorf("Expected error code 'secret-references-unavailable', got %v", resp["code"])
+	}
+}
+
+// mockWorkMetadataStore is an in-memory workmeta.Store. FindByContentHash
+// returns the newest match of any tenant, like records written before dedup
+// was scoped to one, so the handler's own tenant check is exercised.
+type mockWorkMetadataStore struct {
+	records []*workmeta.Record
+}
+
+func (m *mockWorkMetadataStore) Put(ctx context.Context, rec *workmeta.Record) error {
+	rec.TenantAccountID = rec.AccountID
+	if rec.OnBehalfOfAccount != "" {
+		rec.TenantAccountID = rec.OnBehalfOfAccount
+	}
+	m.records = append(m.records, rec)
+	return nil
+}
+
+func (m *mockWorkMetadataStore) Get(ctx context.Context, clusterID, workName string) (*workmeta.Record, error) {
+	for _, rec := range m.records {
+		if rec.ClusterID == clusterID && rec.WorkName == workName {
+			return rec, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockWorkMetadataStore) FindByContentHash(ctx context.Context, tenantAccountID, clusterID, contentHash string) (*workmeta.Record, error) {
+	for i := len(m.records) - 1; i >= 0; i-- {
+		if rec := m.records[i]; rec.ClusterID == clusterID && rec.ContentHash == contentHash {
+			return rec, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *mockWorkMetadataStore) ListByTenant(ctx context.Context, accountID string) ([]*workmeta.Record, error) {
+	var records []*workmeta.Record
+	for _, rec := range m.records {
+		tenant := rec.AccountID
+		if rec.OnBehalfOfAccount != "" {
+			tenant = rec.OnBehalfOfAccount
+		}
+		if tenant == accountID {
+			records = append(records, rec)
+		}
+	}
+	return records, nil
+}
+
+func (m *mockWorkMetadataStore) Delete(ctx context.Context, clusterID, workName string) error {
+	return nil
+}
+
+func TestWorkHandler_Create_Deduplicates(t *testing.T) {
+	works := map[string]*workv1.ManifestWork{}
+	createCalls := 0
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			createCalls++
+			created := manifestWork.DeepCopy()
+			created.UID = "test-uid-123"
+			works[created.Name] = created
+			return created, nil
+		},
+		getManifestWorkFunc: func(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+			if mw, ok := works[name]; ok {
+				return mw, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+	store := &mockWorkMetadataStore{}
+
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{MetadataStore: store}, logger)
+
+	submit := func(body string) (int, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader([]byte(body)))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+		req = req.WithContext(ctx)
+
+		w := httptest.NewRecorder()
+		handler.Create(w, req)
+
+		var resp map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return w.Code, resp
+	}
+
+	// Same work with different key order and whitespace
+	first := `{"cluster_id":"c1","data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"w1"},"spec":{"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"},"data":{"a":"1","b":"2"}}]}}}}`
+	retry := `{"cluster_id":"c1","data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"w1"},"spec":{"workload":{"manifests":[{"kind":"ConfigMap", "apiVersion":"v1","metadata":{"name":"cm"},"data":{"b":"2","a":"1"}}]}}}}`
+
+	code, resp := submit(first)
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d", http.StatusCreated, code)
+	}
+	if resp["content_hash"] == "" || resp["deduplicated"] != nil {
+		t.Errorf("Unexpected first response: %v", resp)
+	}
+
+	code, resp = submit(retry)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+	}
+	if resp["deduplicated"] != true || resp["name"] != "w1" {
+		t.Errorf("Expected deduplicated response for w1, got %v", resp)
+	}
+	if createCalls != 1 {
+		t.Errorf("Expected 1 create call, got %d", createCalls)
+	}
+	if len(store.records) != 1 || store.records[0].AccountID != "test-account-123" {
+		t.Errorf("Unexpected metadata records: %+v", store.records)
+	}
+}
+
+func TestWorkHandler_Create_DeduplicatesPerTenant(t *testing.T) {
+	works := map[string]*workv1.ManifestWork{}
+	createCalls := 0
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			createCalls++
+			created := manifestWork.DeepCopy()
+			works[created.Name] = created
+			return created, nil
+		},
+		getManifestWorkFunc: func(ctx context.Context, clusterName string, name string) (*workv1.ManifestWork, error) {
+			if mw, ok := works[name]; ok {
+				return mw, nil
+			}
+			return nil, errors.New("not found")
+		},
+	}
+	store := &mockWorkMetadataStore{}
+	handler := NewWorkHandler(mockClient, WorkConfig{MetadataStore: store}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	submit := func(accountID, name string) (int, map[string]interface{}) {
+		body := `{"cluster_id":"shared","data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"` + name + `"},"spec":{"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}]}}}}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v0/work", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, accountID))
+
+		w := httptest.NewRecorder()
+		handler.Create(w, req)
+
+		var resp map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return w.Code, resp
+	}
+
+	code, resp := submit("111111111111", "tenant-a")
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %v", http.StatusCreated, code, resp)
+	}
+
+	// The same spec from another account is that account's own work
+	code, resp = submit("222222222222", "tenant-b")
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %v", http.StatusCreated, code, resp)
+	}
+	if resp["deduplicated"] != nil || resp["name"] != "tenant-b" {
+		t.Errorf("Second tenant got another tenant's work: %v", resp)
+	}
+	if createCalls != 2 {
+		t.Errorf("Expected 2 create calls, got %d", createCalls)
+	}
+
+	// Each tenant still deduplicates its own retries
+	code, resp = submit("222222222222", "tenant-b")
+	if code != http.StatusOK || resp["deduplicated"] != true || resp["name"] != "tenant-b" {
+		t.Errorf("Expected deduplicated tenant-b, got %d: %v", code, resp)
+	}
+}
+
+func TestWorkHandler_Create_Checksum(t *testing.T) {
+	createCalls := 0
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			createCalls++
+			return manifestWork, nil
+		},
+	}
+	store := &mockWorkMetadataStore{}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{MetadataStore: store}, logger)
+
+	data := `{"apiVersion":"work.open-cluster-management.io/v1", "kind":"ManifestWork","metadata":{"name":"w1"},"spec":{"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"cm"}}]}}}`
+	sum := sha256.Sum256([]byte(data))
+	checksum := hex.EncodeToString(sum[:])
+
+	submit := func(checksum string) (int, map[string]interface{}) {
+		body := `{"cluster_id":"c1","checksum":"` + checksum + `","data":` + data + `}`
+		req := httptest.NewRequest(http.MethodPost, "/api/v0/work", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+		w := httptest.NewRecorder()
+		handler.Create(w, req)
+
+		var resp map[string]interface{}
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return w.Code, resp
+	}
+
+	code, resp := submit(strings.Repeat("0", 64))
+	if code != http.StatusBadRequest || resp["code"] != "checksum-mismatch" {
+		t.Errorf("Expected 400 checksum-mismatch, got %d %v", code, resp["code"])
+	}
+	code, resp = submit("not-a-digest")
+	if code != http.StatusBadRequest || resp["code"] != "invalid-checksum" {
+		t.Errorf("Expected 400 invalid-checksum, got %d %v", code, resp["code"])
+	}
+	if createCalls != 0 {
+		t.Fatalf("Expected no create calls for rejected payloads, got %d", createCalls)
+	}
+
+	code, resp = submit(strings.ToUpper(checksum))
+	if code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %v", http.StatusCreated, code, resp)
+	}
+	if resp["checksum"] != checksum {
+		t.Errorf("Expected the checksum to be echoed, got %v", resp["checksum"])
+	}
+	if len(store.records) != 1 || store.records[0].Checksum != checksum {
+		t.Errorf("Expected the checksum in the work metadata, got %+v", store.records)
+	}
+}
+
+func TestWorkHandler_Create_Limits(t *testing.T) {
+	manifest := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm"},
+	}
+
+	tests := []struct {
+		name       string
+		limits     WorkLimits
+		manifests  int
+		wantReason string
+	}{
+		{
+			name:       "too many manifests",
+			limits:     WorkLimits{MaxManifests: 2},
+			manifests:  3,
+			wantReason: "max_manifests",
+		},
+		{
+			name:       "payload too large",
+			limits:     WorkLimits{MaxPayloadBytes: 100},
+			manifests:  1,
+			wantReason: "max_payload_bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{Limits: tt.limits}, logger)
+
+			manifests := make([]map[string]interface{}, tt.manifests)
+			for i := range manifests {
+				manifests[i] = manifest
+			}
+			reqBody := map[string]interface{}{
+				"cluster_id": "test-cluster-123",
+				"data": map[string]interface{}{
+					"apiVersion": "work.open-cluster-management.io/v1",
+					"kind":       "ManifestWork",
+					"metadata":   map[string]interface{}{"name": "test-work"},
+					"spec": map[string]interface{}{
+						"workload": map[string]interface{}{"manifests": manifests},
+					},
+				},
+			}
+
+			body, _ := json.Marshal(reqBody)
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req)
+
+			if w.Code != http.StatusRequestEntityTooLarge {
+				t.Errorf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+			}
+
+			var resp map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp["code"] != "work-limit-exceeded" {
+				t.Errorf("Expected error code 'work-limit-exceeded', got %v", resp["code"])
+			}
+			if reason, _ := resp["reason"].(string); !strings.Contains(reason, tt.wantReason) {
+				t.Errorf("Expected reason to name %s, got %q", tt.wantReason, reason)
+			}
+		})
+	}
+}
+
+func TestWorkHandler_Create_ExceedsTransportLimit(t *testing.T) {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{Limits: WorkLimits{MaxMessageBytes: 1024}}, logger)
+
+	reqBody := map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-work"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []map[string]interface{}{
+						{
+							"apiVersion": "v1",
+							"kind":       "ConfigMap",
+							"metadata":   map[string]interface{}{"name": "cm"},
+							"data":       map[string]interface{}{"blob": strings.Repeat("x", 2048)},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+	req = req.WithContext(ctx)
+
+	w := httptest.NewRecorder()
+	handler.Create(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("Expected status code %d, got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp["code"] != "payload-exceeds-transport-limit" {
+		t.Errorf("Expected error code 'payload-exceeds-transport-limit', got %v", resp["code"])
+	}
+}
+
+func TestWorkHandler_Create_DryRun(t *testing.T) {
+	created := false
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			created = true
+			return manifestWork, nil
+		},
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
+
+	reqBody := map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-work"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []map[string]interface{}{
+						{
+							"apiVersion": "v1",
+							"kind":       "ConfigMap",
+							"metadata":   map[string]interface{}{"name": "cm", "namespace": "default"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	body, _ := json.Marshal(reqBody)
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+	req = req.WithContext(middleware.WithDryRun(ctx))
+
+	w := httptest.NewRecorder()
+	handler.Create(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+	}
+	if created {
+		t.Error("Dry run created the manifestwork")
+	}
+
+	var resp struct {
+		Kind    string `json:"kind"`
+		Status  int    `json:"status"`
+		Preview struct {
+			ClusterID    string              `json:"cluster_id"`
+			ManifestWork workv1.ManifestWork `json:"manifestwork"`
+		} `json:"preview"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if resp.Kind != "DryRun" || resp.Status != http.StatusCreated {
+		t.Errorf("Expected a dry run answered with 201, got %s %d", resp.Kind, resp.Status)
+	}
+	mw := resp.Preview.ManifestWork
+	if resp.Preview.ClusterID != "test-cluster-123" || mw.Name != "test-work" || mw.Namespace != "test-cluster-123" || len(mw.Spec.Workload.Manifests) != 1 {
+		t.Errorf("Unexpected preview %+v", resp.Preview)
+	}
+}
+
+func TestWorkHandler_Create_Priority(t *testing.T) {
+	tests := []struct {
+		name       string
+		priority   string
+		privileged bool
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "default", wantStatus: http.StatusCreated},
+		{name: "batch", priority: "batch", wantStatus: http.StatusCreated},
+		{name: "critical from a privileged account", priority: "platform-critical", privileged: true, wantStatus: http.StatusCreated},
+		{name: "critical from a tenant", priority: "platform-critical", wantStatus: http.StatusForbidden, wantCode: "priority-forbidden"},
+		{name: "unknown", priority: "urgent", wantStatus: http.StatusBadRequest, wantCode: "invalid-priority"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockWorkMaestroClient{
+				createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+					return manifestWork, nil
+				},
+			}
+			handler := NewWorkHandler(mockClient, WorkConfig{Queue: workqueue.New(1, 0)}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			body, _ := json.Marshal(map[string]interface{}{
+				"cluster_id": "test-cluster-123",
+				"priority":   tt.priority,
+				"data": map[string]interface{}{
+					"apiVersion": "work.open-cluster-management.io/v1",
+					"kind":       "ManifestWork",
+					"metadata":   map[string]interface{}{"name": "test-work"},
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+			ctx = context.WithValue(ctx, middleware.ContextKeyPrivileged, tt.privileged)
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req.WithContext(ctx))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), tt.wantCode) {
+				t.Errorf("expected code %q, got %s", tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestWorkHandler_Create_QueueBackoff(t *testing.T) {
+	queue := workqueue.New(1, 0)
+	release, err := queue.Acquire(context.Background(), workqueue.PriorityNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer release()
+	handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{Queue: queue}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"cluster_id": "test-cluster-123",
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "test-work"},
+		},
+	})
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+	ctx, cancel := context.WithTimeout(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"), 10*time.Millisecond)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	handler.Create(w, req.WithContext(ctx))
+
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "work-queue-timeout") {
+		t.Fatalf("expected 503 work-queue-timeout, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") != "1" || w.Header().Get(HeaderBackoff) != "1000" {
+		t.Errorf("expected a one second backoff, got Retry-After %q and %s %q",
+			w.Header().Get("Retry-After"), HeaderBackoff, w.Header().Get(HeaderBackoff))
+	}
+}
+
+func TestWorkHandler_Create_ClusterRateLimit(t *testing.T) {
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			return mw, nil
+		},
+	}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	limit := workrate.NewLimiter(ratelimit.NewLocal(1, time.Hour), logger)
+	handler := NewWorkHandler(mockClient, WorkConfig{ClusterLimit: limit}, logger)
+
+	create := func(clusterID string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(map[string]interface{}{
+			"cluster_id": clusterID,
+			"data": map[string]interface{}{
+				"apiVersion": "work.open-cluster-management.io/v1",
+				"kind":       "ManifestWork",
+				"metadata":   map[string]interface{}{"name": "test-work"},
+			},
+		})
+		req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+		req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+		w := httptest.NewRecorder()
+		handler.Create(w, req)
+		return w
+	}
+
+	if w := create("busy-cluster"); w.Code != http.StatusCreated {
+		t.Fatalf("expected first submission to be created, got %d: %s", w.Code, w.Body.String())
+	}
+	w := create("busy-cluster")
+	if w.Code != http.StatusTooManyRequests || !strings.Contains(w.Body.String(), "cluster-rate-limited") {
+		t.Fatalf("expected 429 cluster-rate-limited, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	// Other clusters have their own limit
+	if w := create("quiet-cluster"); w.Code != http.StatusCreated {
+		t.Errorf("expected submission to another cluster to be created, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestWorkHandler_Create_PlanLimits(t *testing.T) {
+	manifest := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "cm"},
+	}
+
+	tests := []struct {
+		name       string
+		plan       plans.Limits
+		manifests  int
+		chunk      bool
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "plan caps manifests below the platform limit",
+			plan:       plans.Limits{Plan: plans.Free, MaxManifests: 2},
+			manifests:  3,
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   "work-limit-exceeded",
+		},
+		{
+			name:       "bulk submission unavailable",
+			plan:       plans.Limits{Plan: plans.Free},
+			manifests:  1,
+			chunk:      true,
+			wantStatus: http.StatusForbidden,
+			wantCode:   "plan-feature-unavailable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+			handler := NewWorkHandler(&mockWorkMaestroClient{}, WorkConfig{Limits: WorkLimits{MaxManifests: 10}}, logger)
+
+			manifests := make([]map[string]interface{}, tt.manifests)
+			for i := range manifests {
+				manifests[i] = manifest
+			}
+			body, _ := json.Marshal(map[string]interface{}{
+				"cluster_id": "test-cluster-123",
+				"chunk":      tt.chunk,
+				"data": map[string]interface{}{
+					"apiVersion": "work.open-cluster-management.io/v1",
+					"kind":       "ManifestWork",
+					"metadata":   map[string]interface{}{"name": "test-work"},
+					"spec": map[string]interface{}{
+						"workload": map[string]interface{}{"manifests": manifests},
+					},
+				},
+			})
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			ctx := context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123")
+			ctx = context.WithValue(ctx, middleware.ContextKeyPlan, tt.plan)
+			req = req.WithContext(ctx)
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status code %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			var resp map[string]interface{}
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if resp["code"] != tt.wantCode {
+				t.Errorf("Expected error code %q, got %v", tt.wantCode, resp["code"])
+			}
+		})
+	}
+}
+
+type residencyAccounts map[string]*store.Account
+
+func (a residencyAccounts) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	if accountID == "999999999999" {
+		return nil, errors.New("dynamodb unavailable")
+	}
+	return a[accountID], nil
+}
+
+func TestWorkHandler_Create_Residency(t *testing.T) {
+	accounts := residencyAccounts{
+		"111111111111": {AccountID: "111111111111", PinnedRegion: "eu-west-1"},
+		"222222222222": {AccountID: "222222222222", PinnedRegion: "us-east-2"},
+	}
+	tests := []struct {
+		name       string
+		accountID  string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "pinned elsewhere", accountID: "111111111111", wantStatus: http.StatusForbidden, wantCode: "residency-violation"},
+		{name: "pinned elsewhere, chunked", accountID: "111111111111", body: `"chunk":true,`, wantStatus: http.StatusForbidden, wantCode: "residency-violation"},
+		{name: "pinned here", accountID: "222222222222", wantStatus: http.StatusCreated},
+		{name: "not pinned", accountID: "333333333333", wantStatus: http.StatusCreated},
+		{name: "lookup failure", accountID: "999999999999", wantStatus: http.StatusServiceUnavailable, wantCode: "residency-unavailable"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockClient := &mockWorkMaestroClient{
+				createManifestWorkFunc: func(ctx context.Context, clusterName string, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+					return mw, nil
+				},
+			}
+			handler := NewWorkHandler(mockClient, WorkConfig{Residency: residency.NewChecker(accounts, "us-east-2")}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			body := `{"cluster_id":"mc-a",` + tt.body + `"data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"test-work"},"spec":{` +
+				`"workload":{"manifests":[{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"c","namespace":"default"}}]}}}}`
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", strings.NewReader(body))
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, tt.accountID))
+			w := httptest.NewRecorder()
+
+			handler.Create(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), tt.wantCode) {
+				t.Errorf("expected code %s, got %s", tt.wantCode, w.Body.String())
+			}
+		})
+	}
+}
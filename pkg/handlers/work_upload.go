@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+)
+
+// Upload media types accepted by POST /api/v0/work besides JSON
+const (
+	mediaTypeYAML      = "application/yaml"
+	mediaTypeMultipart = "multipart/form-data"
+)
+
+// maxUploadOptionBytes bounds the value of a multipart form field
+const maxUploadOptionBytes = 1024
+
+// workUploadOptions are the WorkRequest fields an upload sets through query
+// parameters or, in a multipart upload, form fields
+var workUploadOptions = map[string]bool{
+	"cluster_id":           true,
+	"name":                 true,
+	"encrypt_secrets":      true,
+	"chunk":                true,
+	"priority":             true,
+	"on_behalf_of_account": true,
+}
+
+// uploadMediaType returns the upload media type of r's body, or "" when it
+// is JSON
+func uploadMediaType(r *http.Request) string {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	switch mediaType {
+	case mediaTypeYAML, "application/x-yaml", "text/yaml":
+		return mediaTypeYAML
+	case mediaTypeMultipart:
+		return mediaTypeMultipart
+	}
+	return ""
+}
+
+// decodeWorkUpload builds a work request from manifests uploaded as a YAML
+// stream or as the files of a multipart form. A lone ManifestWork is used as
+// is; other manifests are wrapped into one, named by the name option.
+func decodeWorkUpload(r *http.Request, mediaType string) (*apiv0.WorkRequest, error) {
+	options := make(map[string]string)
+	for key, values := range r.URL.Query() {
+		if workUploadOptions[key] && len(values) > 0 {
+			options[key] = values[0]
+		}
+	}
+
+	var docs []map[string]interface{}
+	switch mediaType {
+	case mediaTypeYAML:
+		var err error
+		if docs, err = decodeManifests(r.Body); err != nil {
+			return nil, err
+		}
+	case mediaTypeMultipart:
+		mr, err := r.MultipartReader()
+		if err != nil {
+			return nil, fmt.Errorf("malformed multipart body: %w", err)
+		}
+		for {
+			part, err := mr.NextPart()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("malformed multipart body: %w", err)
+			}
+			if part.FileName() != "" {
+				partDocs, err := decodeManifests(part)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", part.FileName(), err)
+				}
+				docs = append(docs, partDocs...)
+				continue
+			}
+			name := part.FormName()
+			if !workUploadOptions[name] {
+				return nil, fmt.Errorf("unknown form field %q", name)
+			}
+			value, err := io.ReadAll(io.LimitReader(part, maxUploadOptionBytes+1))
+			if err != nil {
+				return nil, fmt.Errorf("malformed multipart body: %w", err)
+			}
+			if len(value) > maxUploadOptionBytes {
+				return nil, fmt.Errorf("form field %q is too long", name)
+			}
+			options[name] = string(value)
+		}
+	}
+	if len(docs) == 0 {
+		return nil, errors.New("upload contains no manifests")
+	}
+
+	req := &apiv0.WorkRequest{
+		ClusterID:         options["cluster_id"],
+		Priority:          options["priority"],
+		OnBehalfOfAccount: options["on_behalf_of_account"],
+	}
+	for key, field := range map[string]*bool{"encrypt_secrets": &req.EncryptSecrets, "chunk": &req.Chunk} {
+		if v := options[key]; v != "" {
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be true or false", key)
+			}
+			*field = b
+		}
+	}
+	data, err := wrapManifests(docs, options["name"])
+	if err != nil {
+		return nil, err
+	}
+	req.Data = data
+	return req, nil
+}
+
+// decodeManifests reads every non-empty YAML or JSON document from r
+func decodeManifests(r io.Reader) ([]map[string]interface{}, error) {
+	dec := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	var docs []map[string]interface{}
+	for i := 1; ; i++ {
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				return docs, nil
+			}
+			return nil, fmt.Errorf("document %d is not valid YAML or JSON: %w", i, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+		if doc["apiVersion"] == nil || doc["kind"] == nil {
+			return nil, fmt.Errorf("document %d is not a Kubernetes manifest: apiVersion and kind are required", i)
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// wrapManifests returns the ManifestWork data for uploaded documents
+func wrapManifests(docs []map[string]interface{}, name string) (map[string]interface{}, error) {
+	for _, doc := range docs {
+		if doc["kind"] != "ManifestWork" {
+			continue
+		}
+		if len(docs) > 1 {
+			return nil, errors.New("a ManifestWork must be uploaded on its own")
+		}
+		if name != "" {
+			return nil, errors.New("name only applies to manifests wrapped into a ManifestWork")
+		}
+		return doc, nil
+	}
+
+	manifests := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		manifests = append(manifests, doc)
+	}
+	metadata := map[string]interface{}{}
+	if name != "" {
+		metadata["name"] = name
+	}
+	return map[string]interface{}{
+		"apiVersion": workv1.GroupVersion.String(),
+		"kind":       "ManifestWork",
+		"metadata":   metadata,
+		"spec": map[string]interface{}{
+			"workload": map[string]interface{}{
+				"manifests": manifests,
+			},
+		},
+	}, nil
+}
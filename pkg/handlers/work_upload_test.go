@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+const uploadManifests = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+data:
+  replicas: "3"
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: team-a
+`
+
+func TestWorkHandler_Create_Upload(t *testing.T) {
+	var submitted *workv1.ManifestWork
+	mockClient := &mockWorkMaestroClient{
+		createManifestWorkFunc: func(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+			submitted = manifestWork
+			return manifestWork, nil
+		},
+	}
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	handler := NewWorkHandler(mockClient, WorkConfig{}, logger)
+
+	multipartBody := func() (*bytes.Buffer, string) {
+		var buf bytes.Buffer
+		mw := multipart.NewWriter(&buf)
+		_ = mw.WriteField("cluster_id", "test-cluster-123")
+		_ = mw.WriteField("name", "team-a")
+		part, _ := mw.CreateFormFile("file", "configmap.yaml")
+		_, _ = part.Write([]byte(strings.SplitN(uploadManifests, "---\n", 2)[0]))
+		part, _ = mw.CreateFormFile("file", "namespace.json")
+		_, _ = part.Write([]byte(`{"apiVersion": "v1", "kind": "Namespace", "metadata": {"name": "team-a"}}`))
+		_ = mw.Close()
+		return &buf, mw.FormDataContentType()
+	}
+
+	tests := []struct {
+		name        string
+		target      string
+		body        func() (*bytes.Buffer, string)
+		expectCode  int
+		expectError string
+	}{
+		{
+			name:   "YAML stream",
+			target: "/api/v0/work?cluster_id=test-cluster-123&name=team-a",
+			body: func() (*bytes.Buffer, string) {
+				return bytes.NewBufferString(uploadManifests), "application/yaml"
+			},
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:       "multipart files",
+			target:     "/api/v0/work",
+			body:       multipartBody,
+			expectCode: http.StatusCreated,
+		},
+		{
+			name:   "not a manifest",
+			target: "/api/v0/work?cluster_id=test-cluster-123",
+			body: func() (*bytes.Buffer, string) {
+				return bytes.NewBufferString("name: team-a\n"), "application/yaml"
+			},
+			expectCode:  http.StatusBadRequest,
+			expectError: "invalid-upload",
+		},
+		{
+			name:   "missing cluster_id",
+			target: "/api/v0/work?name=team-a",
+			body: func() (*bytes.Buffer, string) {
+				return bytes.NewBufferString(uploadManifests), "application/yaml"
+			},
+			expectCode:  http.StatusBadRequest,
+			expectError: "missing-cluster-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			submitted = nil
+			body, contentType := tt.body()
+			req := httptest.NewRequest(http.MethodPost, tt.target, body)
+			req.Header.Set("Content-Type", contentType)
+			req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "test-account-123"))
+
+			w := httptest.NewRecorder()
+			handler.Create(w, req)
+
+			if w.Code != tt.expectCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectCode, w.Code, w.Body.String())
+			}
+			if tt.expectError != "" {
+				var resp map[string]interface{}
+				_ = json.NewDecoder(w.Body).Decode(&resp)
+				if resp["code"] != tt.expectError {
+					t.Errorf("Expected code %s, got %v", tt.expectError, resp["code"])
+				}
+				return
+			}
+			if submitted == nil {
+				t.Fatal("Expected the manifests to be submitted")
+			}
+			if submitted.Name != "team-a" || submitted.Namespace != "test-cluster-123" || len(submitted.Spec.Workload.Manifests) != 2 {
+				t.Errorf("Expected a ManifestWork team-a for test-cluster-123 wrapping 2 manifests, got %s/%s with %d",
+					submitted.Namespace, submitted.Name, len(submitted.Spec.Workload.Manifests))
+			}
+		})
+	}
+}
+
+func TestWrapManifests_ManifestWork(t *testing.T) {
+	mw := map[string]interface{}{"apiVersion": "work.open-cluster-management.io/v1", "kind": "ManifestWork"}
+	cm := map[string]interface{}{"apiVersion": "v1", "kind": "ConfigMap"}
+
+	data, err := wrapManifests([]map[string]interface{}{mw}, "")
+	if err != nil || data["kind"] != "ManifestWork" || data["spec"] != nil {
+		t.Errorf("Expected a lone ManifestWork to be used as is, got %v, %v", data, err)
+	}
+	if _, err := wrapManifests([]map[string]interface{}{mw, cm}, ""); err == nil {
+		t.Error("Expected an error for a ManifestWork uploaded with other manifests")
+	}
+	if _, err := wrapManifests([]map[string]interface{}{mw}, "renamed"); err == nil {
+		t.Error("Expected an error for a name given with a ManifestWork")
+	}
+}
+
+// FuzzDecodeWorkPayload decodes arbitrary bodies the way Create does, as a
+// JSON work request or as an upload, and checks that whatever is accepted is
+// usable as a work request
+func FuzzDecodeWorkPayload(f *testing.F) {
+	var multipartBody bytes.Buffer
+	mw := multipart.NewWriter(&multipartBody)
+	_ = mw.SetBoundary("fuzz-boundary")
+	_ = mw.WriteField("cluster_id", "cluster-1")
+	part, _ := mw.CreateFormFile("manifests", "app.yaml")
+	_, _ = part.Write([]byte(uploadManifests))
+	_ = mw.Close()
+
+	f.Add("application/json", []byte(`{"cluster_id":"cluster-1","data":{"apiVersion":"work.open-cluster-management.io/v1","kind":"ManifestWork","metadata":{"name":"w"}}}`))
+	f.Add("application/json", []byte(`{"cluster_id":"c","data":{}} {}`))
+	f.Add("application/yaml", []byte(uploadManifests))
+	f.Add("application/yaml", []byte("apiVersion: v1\nkind: ManifestWork\nmetadata: &a {name: *a}\n"))
+	f.Add("text/yaml", []byte("---\n---\n{}\n"))
+	f.Add("multipart/form-data; boundary=fuzz-boundary", multipartBody.Bytes())
+	f.Add("multipart/form-data; boundary=fuzz-boundary", []byte("--fuzz-boundary\r\nContent-Disposition: form-data; name=\"payload\"\r\n\r\nx\r\n--fuzz-boundary--\r\n"))
+
+	f.Fuzz(func(t *testing.T, contentType string, body []byte) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v0/work?cluster_id=cluster-1", bytes.NewReader(body))
+		r.Header.Set("Content-Type", contentType)
+
+		var req *apiv0.WorkRequest
+		if mediaType := uploadMediaType(r); mediaType != "" {
+			upload, err := decodeWorkUpload(r, mediaType)
+			if err != nil {
+				return
+			}
+			if upload.Data["kind"] != "ManifestWork" {
+				t.Fatalf("upload decoded to data of kind %v", upload.Data["kind"])
+			}
+			req = upload
+		} else {
+			req = &apiv0.WorkRequest{}
+			if err := decodeJSON(r, req); err != nil {
+				return
+			}
+		}
+
+		// An accepted request must survive the round trip the handler and
+		// the work store make through JSON
+		encoded, err := json.Marshal(req)
+		if err != nil {
+			t.Fatalf("decoded work request does not encode: %v", err)
+		}
+		var decoded apiv0.WorkRequest
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("decoded work request does not round trip: %v", err)
+		}
+	})
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/gorilla/mux"
 
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 	"github.com/openshift/rosa-regional-platform-api/pkg/zoa"
@@ -32,6 +33,8 @@ type ZoaHandler struct {
 	s3Client      S3Client
 	bucketName    string
 	jobConfig     *zoa.JobConfig
+	runLimits     PageLimits
+	auditLimits   PageLimits
 	logger        *slog.Logger
 }
 
@@ -45,6 +48,10 @@ type ZoaConfig struct {
 	BucketName string
 	JobConfig  *zoa.JobConfig
 	AuditStore zoa.AuditStore
+	// RunPageLimits and AuditPageLimits bound the run and audit list page
+	// sizes; zero values use the defaults
+	RunPageLimits   PageLimits
+	AuditPageLimits PageLimits
 }
 
 // NewZoaHandler creates a new ZoaHandler.
@@ -56,6 +63,12 @@ func NewZoaHandler(
 	cfg ZoaConfig,
 	logger *slog.Logger,
 ) *ZoaHandler {
+	if cfg.RunPageLimits == (PageLimits{}) {
+		cfg.RunPageLimits = DefaultTrustedActionPageLimits
+	}
+	if cfg.AuditPageLimits == (PageLimits{}) {
+		cfg.AuditPageLimits = DefaultAuditPageLimits
+	}
 	return &ZoaHandler{
 		store:         store,
 		auditStore:    cfg.AuditStore,
@@ -64,6 +77,8 @@ func NewZoaHandler(
 		s3Client:      s3Client,
 		bucketName:    cfg.BucketName,
 		jobConfig:     cfg.JobConfig,
+		runLimits:     cfg.RunPageLimits,
+		auditLimits:   cfg.AuditPageLimits,
 		logger:        logger,
 	}
 }
@@ -71,7 +86,10 @@ func NewZoaHandler(
 // Create handles POST /api/v0/trusted-actions/{action}/run
 func (h *ZoaHandler) Create(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	callerARN := middleware.GetCallerARN(ctx)
 	action := mux.Vars(r)["action"]
 
@@ -82,8 +100,8 @@ func (h *ZoaHandler) Create(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var req zoa.CreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		h.writeError(w, http.StatusBadRequest, "invalid-request", "Invalid request body")
+	if err := decodeJSON(r, &req); err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-request", invalidBodyReason(err))
 		return
 	}
 
@@ -182,12 +200,6 @@ func (h *ZoaHandler) Create(w http.ResponseWriter, r *http.Request) {
 		OutputPath:     "s3://" + h.bucketName + "/" + execID + "/output.json",
 	}
 
-	if err := h.store.Create(ctx, exec); err != nil {
-		h.logger.Error("failed to create execution record", "error", err, "execution_id", execID)
-		h.writeError(w, http.StatusInternalServerError, "store-error", "Failed to create execution")
-		return
-	}
-
 	renderCtx := zoa.RenderContext{
 		ExecID:        execID,
 		ActionName:    action,
@@ -202,6 +214,29 @@ func (h *ZoaHandler) Create(w http.ResponseWriter, r *http.Request) {
 		Config:        *h.jobConfig,
 	}
 
+	// A dry run of the request, as opposed to a run of the action's dry-run
+	// variant, records and dispatches nothing
+	if middleware.IsDryRun(ctx) {
+		mw, err := zoa.BuildManifestWork(tmpl, renderCtx)
+		if err != nil {
+			h.logger.Error("failed to build manifestwork", "error", err, "execution_id", execID)
+			h.writeError(w, http.StatusInternalServerError, "render-error", "Failed to build trusted action manifest")
+			return
+		}
+		writeDryRun(w, r, http.StatusAccepted, apiv0.TrustedActionRunPreview{
+			Kind:         "TrustedActionRun",
+			Execution:    exec,
+			ManifestWork: mw,
+		})
+		return
+	}
+
+	if err := h.store.Create(ctx, exec); err != nil {
+		h.logger.Error("failed to create execution record", "error", err, "execution_id", execID)
+		h.writeError(w, http.StatusInternalServerError, "store-error", "Failed to create execution")
+		return
+	}
+
 	mw, err := zoa.BuildManifestWork(tmpl, renderCtx)
 	if err != nil {
 		h.logger.Error("failed to build manifestwork", "error", err, "execution_id", execID)
@@ -235,15 +270,16 @@ func (h *ZoaHandler) Create(w http.ResponseWriter, r *http.Request) {
 
 	h.recordAudit(ctx, r, accountID, callerARN, operator, http.StatusAccepted, originalAction, req.TargetCluster, execID, req.Jira, string(exec.ApprovalState))
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusAccepted)
-	_ = json.NewEncoder(w).Encode(exec)
+	writeResponse(w, r, http.StatusAccepted, exec)
 }
 
 // Get handles GET /api/v0/trusted-actions/runs/{id}
 func (h *ZoaHandler) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	callerARN := middleware.GetCallerARN(ctx)
 	operator := extractOperator(callerARN)
 	execID := mux.Vars(r)["id"]
@@ -302,22 +338,22 @@ func (h *ZoaHandler) Get(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(response)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
 // List handles GET /api/v0/trusted-actions/runs
 func (h *ZoaHandler) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 	query := r.URL.Query()
 
-	limit := 20
-	if v := query.Get("limit"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 && n <= 100 {
-			limit = n
-		}
+	limit, err := pageSize(r, "limit", h.runLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
 	}
 
 	filter := &zoa.ListFilter{
@@ -359,7 +395,8 @@ func (h *ZoaHandler) List(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := &zoa.ExecutionList{
-		Items:   executions,
+		Kind:    "ExecutionList",
+		Items:   emptyIfNil(executions),
 		Total:   len(executions),
 		Page:    1,
 		Limit:   limit,
@@ -370,9 +407,7 @@ func (h *ZoaHandler) List(w http.ResponseWriter, r *http.Request) {
 	operator := extractOperator(callerARN)
 	h.recordAudit(ctx, r, accountID, callerARN, operator, http.StatusOK, "", "", "", "", "")
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(response)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
 // parseSince converts a duration shorthand (e.g. "1h", "24h", "7d") or RFC3339 timestamp
@@ -428,11 +463,10 @@ func (h *ZoaHandler) Catalog(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"items": items,
-		"total": len(items),
+	writeResponse(w, r, http.StatusOK, apiv0.TrustedActionList{
+		Kind:  "TrustedActionList",
+		Items: items,
+		Total: len(items),
 	})
 }
 
@@ -458,9 +492,7 @@ func (h *ZoaHandler) Describe(w http.ResponseWriter, r *http.Request) {
 		RequiredFields:       []string{"target_cluster", "jira"},
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(response)
+	writeResponse(w, r, http.StatusOK, response)
 }
 
 func (h *ZoaHandler) fetchS3Content(ctx context.Context, s3URI string) ([]byte, error) {
@@ -582,11 +614,7 @@ func extractOperator(callerARN string) string {
 func (h *ZoaHandler) writeError(w http.ResponseWriter, status int, code, reason string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"kind":   "Error",
-		"code":   code,
-		"reason": reason,
-	})
+	_ = json.NewEncoder(w).Encode(apiv0.NewError(code, reason))
 }
 
 func (h *ZoaHandler) checkWriteCooldown(ctx context.Context, accountID, action, targetCluster string, cooldownSeconds int) error {
@@ -643,6 +671,7 @@ func (h *ZoaHandler) recordAudit(ctx context.Context, r *http.Request, accountID
 		AccountID:     accountID,
 		CallerARN:     callerARN,
 		Operator:      operator,
+		SourceIP:      middleware.GetClientIP(ctx),
 		Method:        r.Method,
 		Path:          r.URL.RequestURI(),
 		Action:        action,
@@ -665,13 +694,15 @@ func (h *ZoaHandler) AuditList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
-	accountID := middleware.GetAccountID(ctx)
+	accountID, ok := middleware.MustGetAccountID(w, r)
+	if !ok {
+		return
+	}
 
-	limit := 50
-	if l := r.URL.Query().Get("limit"); l != "" {
-		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 200 {
-			limit = parsed
-		}
+	limit, err := pageSize(r, "limit", h.auditLimits)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, "invalid-page-size", err.Error())
+		return
 	}
 
 	sinceStr := ""
@@ -702,12 +733,9 @@ func (h *ZoaHandler) AuditList(w http.ResponseWriter, r *http.Request) {
 	operator := extractOperator(callerARN)
 	h.recordAudit(ctx, r, accountID, callerARN, operator, http.StatusOK, "", "", "", "", "")
 
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]interface{}{
-		"kind":  "AuditList",
-		"items": entries,
-		"total": len(entries),
+	writeResponse(w, r, http.StatusOK, apiv0.TrustedActionAuditList{
+		Kind:  "AuditList",
+		Items: emptyIfNil(entries),
+		Total: len(entries),
 	})
 }
-
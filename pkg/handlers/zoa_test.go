@@ -94,6 +94,10 @@ func (m *zoaMockMaestroClient) GetConsumer(ctx context.Context, id string) (*mae
 	return nil, nil
 }
 
+func (m *zoaMockMaestroClient) DeleteConsumer(ctx context.Context, id string) error {
+	return nil
+}
+
 func (m *zoaMockMaestroClient) ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
 	return nil, nil
 }
@@ -283,6 +287,7 @@ func TestZoaHandler_Get_Found(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v0/trusted-actions/runs/exec-123?include=output", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "exec-123"})
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111222333444"))
 
 	rr := httptest.NewRecorder()
 	handler.Get(rr, req)
@@ -306,6 +311,7 @@ func TestZoaHandler_Get_NotFound(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v0/trusted-actions/runs/nonexistent", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "nonexistent"})
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111222333444"))
 
 	rr := httptest.NewRecorder()
 	handler.Get(rr, req)
@@ -584,6 +590,7 @@ func TestZoaHandler_Get_IncludeOutput(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v0/trusted-actions/runs/exec-123?include=output", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "exec-123"})
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111222333444"))
 
 	rr := httptest.NewRecorder()
 	handler.Get(rr, req)
@@ -611,6 +618,7 @@ func TestZoaHandler_Get_NoInclude_NoOutput(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v0/trusted-actions/runs/exec-123", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "exec-123"})
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111222333444"))
 
 	rr := httptest.NewRecorder()
 	handler.Get(rr, req)
@@ -644,6 +652,7 @@ func TestZoaHandler_Get_IncludeLogs(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v0/trusted-actions/runs/exec-123?include=logs", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "exec-123"})
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111222333444"))
 
 	rr := httptest.NewRecorder()
 	handler.Get(rr, req)
@@ -672,6 +681,7 @@ func TestZoaHandler_Get_IncludeOutputAndLogs(t *testing.T) {
 
 	req := httptest.NewRequest(http.MethodGet, "/api/v0/trusted-actions/runs/exec-123?include=output,logs", nil)
 	req = mux.SetURLVars(req, map[string]string{"id": "exec-123"})
+	req = req.WithContext(context.WithValue(req.Context(), middleware.ContextKeyAccountID, "111222333444"))
 
 	rr := httptest.NewRecorder()
 	handler.Get(rr, req)
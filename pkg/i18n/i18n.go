@@ -0,0 +1,86 @@
+// Package i18n translates the human-readable reasons of error responses into
+// the caller's preferred language. Error codes are never translated, so
+// clients keep matching on them whatever language they ask for.
+//
+// Catalogs are JSON files in catalogs/, one per language and named by its
+// BCP 47 tag, that map an English reason exactly as the API writes it to its
+// translation. Reasons missing from a catalog, including those that embed
+// request details, are returned in English.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed catalogs/*.json
+var catalogFS embed.FS
+
+// Catalog holds the translations of error reasons for each supported language
+type Catalog struct {
+	tags     []language.Tag
+	messages map[language.Tag]map[string]string
+	matcher  language.Matcher
+}
+
+// Load reads the embedded catalogs
+func Load() (*Catalog, error) {
+	entries, err := catalogFS.ReadDir("catalogs")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read catalogs: %w", err)
+	}
+
+	// English is first so that it is the fallback when nothing matches
+	c := &Catalog{
+		tags:     []language.Tag{language.English},
+		messages: make(map[language.Tag]map[string]string, len(entries)),
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		tag, err := language.Parse(strings.TrimSuffix(name, path.Ext(name)))
+		if err != nil {
+			return nil, fmt.Errorf("catalog %s is not named by a language tag: %w", name, err)
+		}
+		data, err := catalogFS.ReadFile(path.Join("catalogs", name))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read catalog %s: %w", name, err)
+		}
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("failed to parse catalog %s: %w", name, err)
+		}
+		c.tags = append(c.tags, tag)
+		c.messages[tag] = messages
+	}
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// Negotiate returns the supported language that best matches an
+// Accept-Language header value, or English when none does
+func (c *Catalog) Negotiate(acceptLanguage string) language.Tag {
+	preferred, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(preferred) == 0 {
+		return language.English
+	}
+	_, index, confidence := c.matcher.Match(preferred...)
+	if confidence == language.No {
+		return language.English
+	}
+	return c.tags[index]
+}
+
+// Translate returns reason in the language tag, and whether a translation
+// was found
+func (c *Catalog) Translate(tag language.Tag, reason string) (string, bool) {
+	translated, ok := c.messages[tag][reason]
+	if !ok || translated == "" {
+		return reason, false
+	}
+	return translated, true
+}
@@ -0,0 +1,79 @@
+package i18n
+
+import (
+	"testing"
+
+	"golang.org/x/text/language"
+)
+
+func TestCatalog_Negotiate(t *testing.T) {
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+
+	tests := []struct {
+		accept string
+		expect language.Tag
+	}{
+		{accept: "", expect: language.English},
+		{accept: "ja", expect: language.Japanese},
+		{accept: "es-MX,es;q=0.9,en;q=0.8", expect: language.Spanish},
+		{accept: "fr-CA", expect: language.French},
+		{accept: "en-US,ja;q=0.5", expect: language.English},
+		{accept: "de", expect: language.English},
+		{accept: "not a header;;", expect: language.English},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			if got := c.Negotiate(tt.accept); got != tt.expect {
+				t.Errorf("expected %s, got %s", tt.expect, got)
+			}
+		})
+	}
+}
+
+func TestCatalog_Translate(t *testing.T) {
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+
+	got, ok := c.Translate(language.Spanish, "Cluster not found")
+	if !ok || got != "Clúster no encontrado" {
+		t.Errorf("expected Spanish translation, got %q (%v)", got, ok)
+	}
+
+	reason := "Account 123456789012 has not delegated access to account 210987654321"
+	if got, ok := c.Translate(language.Spanish, reason); ok || got != reason {
+		t.Errorf("expected untranslated reason to be returned as is, got %q (%v)", got, ok)
+	}
+}
+
+// Every catalog must translate the same reasons, so no language silently
+// falls back to English where the others do not
+func TestCatalogs_SameReasons(t *testing.T) {
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+
+	var first language.Tag
+	for tag, messages := range c.messages {
+		if first == language.Und {
+			first = tag
+			continue
+		}
+		for reason := range c.messages[first] {
+			if _, ok := messages[reason]; !ok {
+				t.Errorf("catalog %s does not translate %q", tag, reason)
+			}
+		}
+		for reason := range messages {
+			if _, ok := c.messages[first][reason]; !ok {
+				t.Errorf("catalog %s translates %q, which catalog %s does not", tag, reason, first)
+			}
+		}
+	}
+}
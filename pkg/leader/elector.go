@@ -0,0 +1,212 @@
+// Package leader elects the one replica that runs each background worker in
+// a multi-replica deployment. Leadership is a lease per worker held in a
+// DynamoDB table: the leader renews it well before it expires, and the other
+// replicas take it over once it does.
+package leader
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// releaseTimeout bounds giving up a lease during shutdown
+const releaseTimeout = 5 * time.Second
+
+var (
+	isLeader = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_leader_is_leader",
+		Help: "1 when this replica holds the worker's leadership lease.",
+	}, []string{"worker"})
+
+	leaderTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_leader_transitions_total",
+		Help: "Times this replica acquired or lost a worker's leadership lease.",
+	}, []string{"worker", "transition"})
+
+	leaseErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_leader_lease_errors_total",
+		Help: "Lease acquisitions and renewals that failed with an error other than the lease being held.",
+	}, []string{"worker"})
+)
+
+// Status is the leadership of one worker as seen by this replica
+type Status struct {
+	Worker    string    `json:"worker"`
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expiresAt"`
+	Expired   bool      `json:"expired"`
+	// Self is true when this replica is the holder
+	Self bool `json:"self"`
+}
+
+// Elector runs workers only while this replica holds their lease. A nil
+// Elector runs every worker unconditionally.
+type Elector struct {
+	store    Store
+	identity string
+	ttl      time.Duration
+	renew    time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewElector creates an elector that holds leases as identity, normally the
+// pod name, for ttl and renews them every third of it
+func NewElector(store Store, identity string, ttl time.Duration, logger *slog.Logger) *Elector {
+	return &Elector{
+		store:    store,
+		identity: identity,
+		ttl:      ttl,
+		renew:    ttl / 3,
+		logger:   logger.With("identity", identity),
+		now:      time.Now,
+	}
+}
+
+// Identity returns the holder name this replica uses
+func (e *Elector) Identity() string {
+	if e == nil {
+		return ""
+	}
+	return e.identity
+}
+
+// Run calls run for worker whenever this replica becomes its leader and
+// cancels run's context when leadership is lost. It returns when ctx is
+// cancelled or run returns on its own, releasing the lease if it is held.
+func (e *Elector) Run(ctx context.Context, worker string, run func(ctx context.Context)) {
+	if e == nil {
+		run(ctx)
+		return
+	}
+	logger := e.logger.With("worker", worker)
+
+	for {
+		if !e.campaign(ctx, worker, logger) {
+			return
+		}
+		if finished := e.lead(ctx, worker, logger, run); finished {
+			return
+		}
+	}
+}
+
+// campaign tries to acquire the lease every renewal interval until it does
+// or ctx is cancelled
+func (e *Elector) campaign(ctx context.Context, worker string, logger *slog.Logger) bool {
+	for {
+		err := e.store.Acquire(ctx, worker, e.identity, e.now(), e.ttl)
+		if err == nil {
+			return true
+		}
+		if !errors.Is(err, ErrLeaseHeld) && ctx.Err() == nil {
+			leaseErrors.WithLabelValues(worker).Inc()
+			logger.Warn("failed to acquire leadership lease", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(e.renew):
+		}
+	}
+}
+
+// lead runs the worker while renewing the lease. It reports whether the
+// elector is finished, because ctx was cancelled or the worker returned,
+// rather than having lost the lease.
+func (e *Elector) lead(ctx context.Context, worker string, logger *slog.Logger, run func(ctx context.Context)) bool {
+	isLeader.WithLabelValues(worker).Set(1)
+	leaderTransitions.WithLabelValues(worker, "acquired").Inc()
+	logger.Info("acquired leadership")
+
+	workCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		run(workCtx)
+	}()
+
+	finished := true
+	renewed := e.now()
+	ticker := time.NewTicker(e.renew)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-done:
+			break loop
+		case <-ticker.C:
+			now := e.now()
+			err := e.store.Acquire(ctx, worker, e.identity, now, e.ttl)
+			if err == nil {
+				renewed = now
+				continue
+			}
+			if ctx.Err() != nil {
+				break loop
+			}
+			if !errors.Is(err, ErrLeaseHeld) {
+				leaseErrors.WithLabelValues(worker).Inc()
+				logger.Warn("failed to renew leadership lease", "error", err)
+				// Keep leading through transient errors, but stop a renewal
+				// interval before the lease could be taken over
+				if now.Sub(renewed) < e.ttl-e.renew {
+					continue
+				}
+			}
+			logger.Warn("lost leadership", "error", err)
+			leaderTransitions.WithLabelValues(worker, "lost").Inc()
+			finished = false
+			break loop
+		}
+	}
+
+	cancel()
+	<-done
+	isLeader.WithLabelValues(worker).Set(0)
+
+	if finished {
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), releaseTimeout)
+		defer cancelRelease()
+		if err := e.store.Release(releaseCtx, worker, e.identity); err != nil {
+			logger.Warn("failed to release leadership lease", "error", err)
+		} else {
+			logger.Info("released leadership")
+		}
+	}
+	return finished
+}
+
+// Leaders returns the leadership of every worker that has a lease
+func (e *Elector) Leaders(ctx context.Context) ([]Status, error) {
+	if e == nil {
+		return nil, nil
+	}
+	leases, err := e.store.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := e.now()
+	statuses := make([]Status, len(leases))
+	for i, lease := range leases {
+		expiresAt := time.UnixMilli(lease.ExpiresAt).UTC()
+		statuses[i] = Status{
+			Worker:    lease.Worker,
+			Holder:    lease.Holder,
+			ExpiresAt: expiresAt,
+			Expired:   !now.Before(expiresAt),
+			Self:      lease.Holder == e.identity,
+		}
+	}
+	return statuses, nil
+}
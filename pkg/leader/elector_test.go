@@ -0,0 +1,171 @@
+package leader
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// memStore is an in-memory Store with the same conditions as DynamoStore
+type memStore struct {
+	mu     sync.Mutex
+	leases map[string]*Lease
+}
+
+func newMemStore() *memStore {
+	return &memStore{leases: make(map[string]*Lease)}
+}
+
+func (s *memStore) Acquire(ctx context.Context, worker, holder string, now time.Time, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lease, ok := s.leases[worker]; ok && lease.Holder != holder && lease.ExpiresAt >= now.UnixMilli() {
+		return ErrLeaseHeld
+	}
+	s.leases[worker] = &Lease{Worker: worker, Holder: holder, ExpiresAt: now.Add(ttl).UnixMilli()}
+	return nil
+}
+
+func (s *memStore) Release(ctx context.Context, worker, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lease, ok := s.leases[worker]; ok && lease.Holder == holder {
+		delete(s.leases, worker)
+	}
+	return nil
+}
+
+func (s *memStore) List(ctx context.Context) ([]*Lease, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var leases []*Lease
+	for _, lease := range s.leases {
+		copied := *lease
+		leases = append(leases, &copied)
+	}
+	return leases, nil
+}
+
+// steal hands the lease to another holder, as if this replica had stalled
+func (s *memStore) steal(worker, holder string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases[worker] = &Lease{Worker: worker, Holder: holder, ExpiresAt: time.Now().Add(time.Hour).UnixMilli()}
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// leading runs a worker that counts how many replicas are running it at once
+type leading struct {
+	active  atomic.Int32
+	maxSeen atomic.Int32
+	starts  atomic.Int32
+}
+
+func (l *leading) run(ctx context.Context) {
+	l.starts.Add(1)
+	n := l.active.Add(1)
+	for {
+		seen := l.maxSeen.Load()
+		if n <= seen || l.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	<-ctx.Done()
+	l.active.Add(-1)
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestElector_OneLeaderAndHandover(t *testing.T) {
+	store := newMemStore()
+	worker := &leading{}
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	ctxB, cancelB := context.WithCancel(context.Background())
+	defer cancelB()
+
+	a := NewElector(store, "replica-a", 30*time.Millisecond, testLogger())
+	b := NewElector(store, "replica-b", 30*time.Millisecond, testLogger())
+	doneA := make(chan struct{})
+	go func() { defer close(doneA); a.Run(ctxA, "work-scheduler", worker.run) }()
+	waitFor(t, func() bool { return worker.active.Load() == 1 })
+	go b.Run(ctxB, "work-scheduler", worker.run)
+
+	// b keeps campaigning while a holds the lease
+	time.Sleep(100 * time.Millisecond)
+	if worker.maxSeen.Load() != 1 || worker.starts.Load() != 1 {
+		t.Fatalf("expected exactly one replica to run the worker, saw %d at once", worker.maxSeen.Load())
+	}
+
+	// a shutting down releases the lease and b takes over
+	cancelA()
+	<-doneA
+	waitFor(t, func() bool { return worker.starts.Load() == 2 && worker.active.Load() == 1 })
+
+	leaders, err := b.Leaders(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(leaders) != 1 || leaders[0].Holder != "replica-b" || !leaders[0].Self || leaders[0].Expired {
+		t.Errorf("expected replica-b to hold the lease, got %+v", leaders)
+	}
+	if worker.maxSeen.Load() != 1 {
+		t.Errorf("expected at most one replica to run the worker at once, saw %d", worker.maxSeen.Load())
+	}
+}
+
+func TestElector_LostLeaseStopsWorker(t *testing.T) {
+	store := newMemStore()
+	worker := &leading{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	e := NewElector(store, "replica-a", 30*time.Millisecond, testLogger())
+	go e.Run(ctx, "zoa-reconciler", worker.run)
+	waitFor(t, func() bool { return worker.active.Load() == 1 })
+
+	store.steal("zoa-reconciler", "replica-b")
+	waitFor(t, func() bool { return worker.active.Load() == 0 })
+}
+
+func TestElector_FinishedWorker(t *testing.T) {
+	store := newMemStore()
+	e := NewElector(store, "replica-a", 30*time.Millisecond, testLogger())
+
+	ran := false
+	e.Run(context.Background(), "authz-recovery", func(ctx context.Context) { ran = true })
+	if !ran {
+		t.Fatal("expected the worker to run")
+	}
+	if leases, _ := store.List(context.Background()); len(leases) != 0 {
+		t.Errorf("expected the lease to be released, got %+v", leases)
+	}
+}
+
+func TestElector_Nil(t *testing.T) {
+	var e *Elector
+	ran := false
+	e.Run(context.Background(), "policy-backup", func(ctx context.Context) { ran = true })
+	if !ran {
+		t.Error("expected a nil elector to run the worker")
+	}
+	if leaders, err := e.Leaders(context.Background()); err != nil || leaders != nil {
+		t.Errorf("expected no leaders, got %v (%v)", leaders, err)
+	}
+}
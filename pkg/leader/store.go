@@ -0,0 +1,136 @@
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// ErrLeaseHeld is returned when another replica holds an unexpired lease
+var ErrLeaseHeld = errors.New("lease held by another replica")
+
+// Lease is a worker's leadership lease
+type Lease struct {
+	Worker string `dynamodbav:"worker"`
+	Holder string `dynamodbav:"holder"`
+	// ExpiresAt is in Unix milliseconds
+	ExpiresAt int64  `dynamodbav:"expiresAt"`
+	RenewedAt string `dynamodbav:"renewedAt"`
+}
+
+// Store persists leases
+type Store interface {
+	// Acquire takes the worker's lease for holder, or renews it if holder
+	// already has it, until now+ttl. It fails with ErrLeaseHeld while another
+	// holder's lease is unexpired.
+	Acquire(ctx context.Context, worker, holder string, now time.Time, ttl time.Duration) error
+	// Release gives up holder's lease so another replica can take it at once
+	Release(ctx context.Context, worker, holder string) error
+	// List returns every lease, ordered by worker
+	List(ctx context.Context) ([]*Lease, error)
+}
+
+// DynamoStore implements Store backed by DynamoDB
+type DynamoStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+}
+
+// NewDynamoStore creates a new DynamoDB-backed lease store
+func NewDynamoStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DynamoStore {
+	return &DynamoStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+	}
+}
+
+// Acquire writes the lease on the condition that it is free, expired or
+// already holder's
+func (st *DynamoStore) Acquire(ctx context.Context, worker, holder string, now time.Time, ttl time.Duration) error {
+	item, err := attributevalue.MarshalMap(&Lease{
+		Worker:    worker,
+		Holder:    holder,
+		ExpiresAt: now.Add(ttl).UnixMilli(),
+		RenewedAt: now.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal lease: %w", err)
+	}
+
+	_, err = st.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(st.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(worker) OR expiresAt < :now OR holder = :holder"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":now":    &types.AttributeValueMemberN{Value: strconv.FormatInt(now.UnixMilli(), 10)},
+			":holder": &types.AttributeValueMemberS{Value: holder},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return ErrLeaseHeld
+	}
+	if err != nil {
+		return fmt.Errorf("failed to put lease: %w", err)
+	}
+	return nil
+}
+
+// Release deletes the lease if holder still has it
+func (st *DynamoStore) Release(ctx context.Context, worker, holder string) error {
+	_, err := st.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(st.tableName),
+		Key: map[string]types.AttributeValue{
+			"worker": &types.AttributeValueMemberS{Value: worker},
+		},
+		ConditionExpression: aws.String("holder = :holder"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":holder": &types.AttributeValueMemberS{Value: holder},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete lease: %w", err)
+	}
+	return nil
+}
+
+// List scans the lease table, which holds one item per worker
+func (st *DynamoStore) List(ctx context.Context) ([]*Lease, error) {
+	var leases []*Lease
+	paginator := dynamodb.NewScanPaginator(st.dynamoClient, &dynamodb.ScanInput{
+		TableName: aws.String(st.tableName),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan leases: %w", err)
+		}
+		var items []*Lease
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal leases: %w", err)
+		}
+		leases = append(leases, items...)
+	}
+
+	sort.Slice(leases, func(i, j int) bool {
+		return leases[i].Worker < leases[j].Worker
+	})
+	return leases, nil
+}
@@ -0,0 +1,147 @@
+// Package lifecycle runs the server's long-running components together. They
+// start in the order they were added and stop in reverse order, and when one
+// of them fails the rest are stopped and the failure is returned.
+package lifecycle
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// Health receives the state of each component
+type Health interface {
+	SetComponent(name string, critical bool, err error)
+}
+
+// Component is a long-running part of the server
+type Component struct {
+	Name string
+	// Critical components fail readiness if they stop unexpectedly
+	Critical bool
+	// Run runs the component until its context is canceled. A non-nil error
+	// returned before then stops the server; returning nil early means the
+	// component has finished its work.
+	Run func(ctx context.Context) error
+	// Stop, when set, is called before the component's context is canceled
+	// so it can drain; its context carries the shutdown deadline
+	Stop func(ctx context.Context) error
+}
+
+// Manager starts and stops a set of components
+type Manager struct {
+	health          Health
+	shutdownTimeout time.Duration
+	logger          *slog.Logger
+	components      []Component
+	onStarted       []func()
+	onStopping      []func()
+}
+
+// New creates a manager that reports component states to health and gives
+// shutdown at most shutdownTimeout
+func New(health Health, shutdownTimeout time.Duration, logger *slog.Logger) *Manager {
+	return &Manager{health: health, shutdownTimeout: shutdownTimeout, logger: logger}
+}
+
+// Add appends a component; components start in the order they are added
+func (m *Manager) Add(c Component) *Manager {
+	m.components = append(m.components, c)
+	return m
+}
+
+// OnStarted registers a hook called once every component has been started
+func (m *Manager) OnStarted(fn func()) *Manager {
+	m.onStarted = append(m.onStarted, fn)
+	return m
+}
+
+// OnStopping registers a hook called before any component is stopped, such
+// as failing readiness so load balancers stop sending traffic
+func (m *Manager) OnStopping(fn func()) *Manager {
+	m.onStopping = append(m.onStopping, fn)
+	return m
+}
+
+// running is a started component
+type running struct {
+	Component
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Run starts every component and blocks until ctx is canceled or a component
+// fails, then stops the components in reverse order. It returns the first
+// component failure, if any.
+func (m *Manager) Run(ctx context.Context) error {
+	g, gctx := errgroup.WithContext(ctx)
+
+	started := make([]*running, 0, len(m.components))
+	for _, c := range m.components {
+		// Components are stopped one at a time during shutdown, not all at
+		// once when ctx is canceled
+		cctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		r := &running{Component: c, cancel: cancel, done: make(chan struct{})}
+		started = append(started, r)
+
+		m.health.SetComponent(c.Name, c.Critical, nil)
+		m.logger.Info("starting component", "component", c.Name)
+		g.Go(func() error {
+			defer close(r.done)
+			if err := c.Run(cctx); err != nil && cctx.Err() == nil {
+				m.health.SetComponent(c.Name, c.Critical, err)
+				return fmt.Errorf("%s: %w", c.Name, err)
+			}
+			return nil
+		})
+	}
+	for _, fn := range m.onStarted {
+		fn()
+	}
+
+	<-gctx.Done()
+	if ctx.Err() != nil {
+		m.logger.Info("shutting down")
+	} else {
+		m.logger.Error("component failed, shutting down", "error", context.Cause(gctx))
+	}
+	for _, fn := range m.onStopping {
+		fn()
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), m.shutdownTimeout)
+	defer cancel()
+	for i := len(started) - 1; i >= 0; i-- {
+		m.stop(stopCtx, started[i])
+	}
+
+	waitErr := make(chan error, 1)
+	go func() { waitErr <- g.Wait() }()
+	select {
+	case err := <-waitErr:
+		m.logger.Info("all components stopped")
+		return err
+	case <-stopCtx.Done():
+		return fmt.Errorf("components still running after %s", m.shutdownTimeout)
+	}
+}
+
+// stop stops one component and waits for it until the shutdown deadline
+func (m *Manager) stop(ctx context.Context, r *running) {
+	m.logger.Info("stopping component", "component", r.Name)
+	if r.Stop != nil {
+		if err := r.Stop(ctx); err != nil {
+			m.logger.Error("failed to stop component", "component", r.Name, "error", err)
+		}
+	}
+	r.cancel()
+
+	select {
+	case <-r.done:
+	case <-ctx.Done():
+		m.logger.Warn("component did not stop before the shutdown deadline", "component", r.Name)
+	}
+}
@@ -0,0 +1,176 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder collects component states and lifecycle events in order
+type recorder struct {
+	mu     sync.Mutex
+	events []string
+	states map[string]error
+}
+
+func newRecorder() *recorder {
+	return &recorder{states: make(map[string]error)}
+}
+
+func (r *recorder) SetComponent(name string, critical bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states[name] = err
+}
+
+func (r *recorder) record(event string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recorder) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.events)
+}
+
+// worker runs until its context is cancelled, recording when it stops
+func worker(rec *recorder, name string) Component {
+	return Component{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			rec.record("stopped " + name)
+			return nil
+		},
+	}
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestManager_OrderedShutdown(t *testing.T) {
+	rec := newRecorder()
+	started := make(chan struct{})
+
+	drained := Component{
+		Name: "server",
+		Run: func(ctx context.Context) error {
+			<-ctx.Done()
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			rec.record("drained server")
+			return nil
+		},
+	}
+	m := New(rec, time.Second, testLogger()).
+		Add(worker(rec, "first")).
+		Add(worker(rec, "second")).
+		Add(drained).
+		OnStarted(func() { close(started) }).
+		OnStopping(func() { rec.record("stopping") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	<-started
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := []string{"stopping", "drained server", "stopped second", "stopped first"}
+	if got := rec.snapshot(); !slices.Equal(got, expected) {
+		t.Errorf("expected events %v, got %v", expected, got)
+	}
+	for _, name := range []string{"first", "second", "server"} {
+		if err, ok := rec.states[name]; !ok || err != nil {
+			t.Errorf("expected %s to be reported healthy, got %v (reported %v)", name, err, ok)
+		}
+	}
+}
+
+func TestManager_ComponentFailure(t *testing.T) {
+	rec := newRecorder()
+	boom := errors.New("address already in use")
+
+	m := New(rec, time.Second, testLogger()).
+		Add(worker(rec, "worker")).
+		Add(Component{
+			Name:     "server",
+			Critical: true,
+			Run:      func(ctx context.Context) error { return boom },
+		})
+
+	err := m.Run(context.Background())
+	if !errors.Is(err, boom) {
+		t.Fatalf("expected the component's error, got %v", err)
+	}
+	if !errors.Is(rec.states["server"], boom) {
+		t.Errorf("expected server to be reported unhealthy, got %v", rec.states["server"])
+	}
+	if got := rec.snapshot(); !slices.Equal(got, []string{"stopped worker"}) {
+		t.Errorf("expected the other components to be stopped, got %v", got)
+	}
+}
+
+func TestManager_FinishedComponent(t *testing.T) {
+	rec := newRecorder()
+	finished := make(chan struct{})
+
+	m := New(rec, time.Second, testLogger()).
+		Add(Component{
+			Name: "recovery",
+			Run: func(ctx context.Context) error {
+				close(finished)
+				return nil
+			},
+		}).
+		Add(worker(rec, "worker"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- m.Run(ctx) }()
+
+	<-finished
+	select {
+	case err := <-done:
+		t.Fatalf("expected a finished component not to stop the others, got %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestManager_ShutdownDeadline(t *testing.T) {
+	rec := newRecorder()
+	release := make(chan struct{})
+	defer close(release)
+
+	m := New(rec, 20*time.Millisecond, testLogger()).
+		Add(Component{
+			Name: "stuck",
+			Run: func(ctx context.Context) error {
+				<-release
+				return nil
+			},
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := m.Run(ctx); err == nil {
+		t.Fatal("expected an error when a component outlives the shutdown deadline")
+	}
+}
@@ -0,0 +1,87 @@
+// Package loadshed gives API routes the priorities that decide which
+// requests are shed while the server is degraded.
+package loadshed
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Request priorities. Low-priority requests are shed while the server is
+// degraded; normal and critical ones are always served.
+const (
+	PriorityCritical = "critical"
+	PriorityNormal   = "normal"
+	PriorityLow      = "low"
+)
+
+// Priorities lists the request priorities
+var Priorities = []string{PriorityCritical, PriorityNormal, PriorityLow}
+
+// DefaultRules makes the list endpoints and decision analytics low priority
+const DefaultRules = "GET /api/v0/work=low,GET /api/v0/work/schedules=low,GET /api/v0/resource_bundles=low," +
+	"GET /api/v0/resource_bundles/summary=low,GET /api/v0/management_clusters=low,GET /api/v0/clusters=low," +
+	"GET /api/v0/nodepools=low,GET /api/v0/authz/analytics=low,GET /api/v0/authz/principals=low,GET /api/v0/audit=low"
+
+// Rule gives the requests for a route a priority. Path is a route template,
+// such as /api/v0/work/{id}, or a prefix of templates ending in /*. An empty
+// Method matches every method.
+type Rule struct {
+	Method   string
+	Path     string
+	Priority string
+}
+
+// matches reports whether the rule covers a request for method to the route
+// with template
+func (r Rule) matches(method, template string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "/*"); ok {
+		return template == prefix || strings.HasPrefix(template, prefix+"/")
+	}
+	return template == r.Path
+}
+
+// Rules are matched in order; a request takes the priority of the first rule
+// covering it
+type Rules []Rule
+
+// ParseRules parses a comma-separated list of [METHOD ]path=priority rules,
+// such as "GET /api/v0/work=low,/api/v0/authz/analytics=low"
+func ParseRules(list string) (Rules, error) {
+	var rules Rules
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		route, priority, ok := strings.Cut(entry, "=")
+		if !ok || !slices.Contains(Priorities, strings.TrimSpace(priority)) {
+			return nil, fmt.Errorf("invalid load shedding rule %q: must be [METHOD ]path=priority, with priority one of %s",
+				entry, strings.Join(Priorities, ", "))
+		}
+		rule := Rule{Path: strings.TrimSpace(route), Priority: strings.TrimSpace(priority)}
+		if method, path, ok := strings.Cut(rule.Path, " "); ok {
+			rule.Method, rule.Path = strings.ToUpper(method), strings.TrimSpace(path)
+		}
+		if !strings.HasPrefix(rule.Path, "/") {
+			return nil, fmt.Errorf("invalid load shedding rule %q: path must start with /", entry)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Priority returns the priority of a request for method to the route with
+// template, normal when no rule covers it
+func (rs Rules) Priority(method, template string) string {
+	for _, rule := range rs {
+		if rule.matches(method, template) {
+			return rule.Priority
+		}
+	}
+	return PriorityNormal
+}
@@ -0,0 +1,37 @@
+package loadshed
+
+import "testing"
+
+func TestParseRules(t *testing.T) {
+	rules, err := ParseRules("GET /api/v0/work/{id}=critical, get /api/v0/work/*=low,/api/v0/authz/analytics=low")
+	if err != nil {
+		t.Fatalf("ParseRules: %v", err)
+	}
+
+	tests := []struct {
+		method, template string
+		want             string
+	}{
+		{"GET", "/api/v0/work/{id}", PriorityCritical},
+		{"GET", "/api/v0/work", PriorityLow},
+		{"GET", "/api/v0/work/schedules", PriorityLow},
+		{"POST", "/api/v0/work", PriorityNormal},
+		{"GET", "/api/v0/workloads", PriorityNormal},
+		{"DELETE", "/api/v0/authz/analytics", PriorityLow},
+		{"GET", "/api/v0/live", PriorityNormal},
+	}
+	for _, tt := range tests {
+		if got := rules.Priority(tt.method, tt.template); got != tt.want {
+			t.Errorf("%s %s: expected %s, got %s", tt.method, tt.template, tt.want, got)
+		}
+	}
+
+	if _, err := ParseRules(DefaultRules); err != nil {
+		t.Errorf("expected the default rules to parse: %v", err)
+	}
+	for _, list := range []string{"GET /api/v0/work", "GET /api/v0/work=urgent", "GET api/v0/work=low"} {
+		if _, err := ParseRules(list); err == nil {
+			t.Errorf("expected %q to be rejected", list)
+		}
+	}
+}
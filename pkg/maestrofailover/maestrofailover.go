@@ -0,0 +1,252 @@
+// Package maestrofailover checks the health of the primary and secondary
+// Maestro endpoints and switches calls away from the active one when it
+// keeps failing, so a Maestro upgrade or incident can be mitigated without
+// redeploying the API. Operators can also switch endpoints by hand.
+//
+// Calls only move away from an unhealthy endpoint: once the primary recovers
+// they stay on the secondary until switched back, so a flapping endpoint
+// does not bounce traffic between the two.
+package maestrofailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Reasons an endpoint became active
+const (
+	ReasonFailover = "failover"
+	ReasonManual   = "manual"
+	ReasonReload   = "reload"
+)
+
+var (
+	activeGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_maestro_endpoint_active",
+		Help: "1 for the Maestro endpoint calls go to, 0 for the others.",
+	}, []string{"endpoint"})
+
+	healthyGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_maestro_endpoint_healthy",
+		Help: "1 when the Maestro endpoint passed its last health check, 0 when it failed it.",
+	}, []string{"endpoint"})
+
+	switches = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_maestro_endpoint_switches_total",
+		Help: "Switches of calls to a Maestro endpoint, by endpoint and reason (failover, manual or reload).",
+	}, []string{"endpoint", "reason"})
+)
+
+// ErrUnhealthy is returned when switching by hand to an endpoint whose last
+// health check failed
+var ErrUnhealthy = errors.New("maestro endpoint is unhealthy")
+
+// Switcher is the part of the Maestro client the failover uses
+type Switcher interface {
+	Endpoints() []string
+	ActiveEndpoint() string
+	SetActiveEndpoint(name string) error
+	ProbeEndpoint(ctx context.Context, name string) error
+}
+
+// EndpointStatus is the health of a Maestro endpoint
+type EndpointStatus struct {
+	Name   string `json:"name"`
+	Active bool   `json:"active"`
+	// Healthy is whether the last health check passed; endpoints not checked
+	// yet are assumed healthy
+	Healthy             bool       `json:"healthy"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	LastError           string     `json:"last_error,omitempty"`
+	CheckedAt           *time.Time `json:"checked_at,omitempty"`
+}
+
+// Switch is a change of the active endpoint
+type Switch struct {
+	From   string    `json:"from"`
+	To     string    `json:"to"`
+	Reason string    `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+type health struct {
+	failures  int
+	lastError string
+	checkedAt time.Time
+}
+
+// Failover checks every endpoint on an interval and switches calls to a
+// healthy endpoint once the active one has failed threshold checks in a row
+type Failover struct {
+	switcher  Switcher
+	interval  time.Duration
+	threshold int
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu     sync.Mutex
+	health map[string]*health
+	last   *Switch
+}
+
+// New creates a Failover for the endpoints of switcher
+func New(switcher Switcher, interval time.Duration, threshold int, logger *slog.Logger) *Failover {
+	f := &Failover{
+		switcher:  switcher,
+		interval:  interval,
+		threshold: threshold,
+		logger:    logger,
+		now:       time.Now,
+		health:    make(map[string]*health),
+	}
+	for _, name := range switcher.Endpoints() {
+		f.health[name] = &health{}
+		healthyGauge.WithLabelValues(name).Set(1)
+	}
+	f.setActiveGauge()
+	return f
+}
+
+// Run checks immediately and then on every interval until ctx is done
+func (f *Failover) Run(ctx context.Context) {
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		f.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check probes every endpoint, and fails over when the active endpoint has
+// reached the threshold and another endpoint is healthy
+func (f *Failover) Check(ctx context.Context) {
+	results := make(map[string]error)
+	for _, name := range f.switcher.Endpoints() {
+		results[name] = f.switcher.ProbeEndpoint(ctx, name)
+	}
+	if ctx.Err() != nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := f.now().UTC()
+	for name, err := range results {
+		h := f.health[name]
+		h.checkedAt = now
+		if err != nil {
+			h.failures++
+			h.lastError = err.Error()
+			healthyGauge.WithLabelValues(name).Set(0)
+			f.logger.Warn("maestro endpoint health check failed", "endpoint", name, "consecutive_failures", h.failures, "error", err)
+			continue
+		}
+		if h.failures > 0 {
+			f.logger.Info("maestro endpoint recovered", "endpoint", name, "after_failures", h.failures)
+		}
+		h.failures = 0
+		h.lastError = ""
+		healthyGauge.WithLabelValues(name).Set(1)
+	}
+
+	active := f.switcher.ActiveEndpoint()
+	if f.health[active].failures < f.threshold {
+		return
+	}
+	for _, name := range f.switcher.Endpoints() {
+		if name != active && f.health[name].failures == 0 {
+			if err := f.activate(name, ReasonFailover); err != nil {
+				f.logger.Error("failed to fail over to maestro endpoint", "endpoint", name, "error", err)
+			}
+			return
+		}
+	}
+	f.logger.Error("active maestro endpoint is unhealthy and no other endpoint is healthy to fail over to", "endpoint", active)
+}
+
+// SwitchTo sends calls to the named endpoint for reason. Unless force is set,
+// an endpoint whose last health check failed is refused with ErrUnhealthy.
+// Switching to the active endpoint changes nothing.
+func (f *Failover) SwitchTo(name, reason string, force bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	h, ok := f.health[name]
+	if !ok {
+		// Let the switcher name the unknown endpoint
+		return f.switcher.SetActiveEndpoint(name)
+	}
+	if h.failures > 0 && !force {
+		return fmt.Errorf("%w: %s", ErrUnhealthy, h.lastError)
+	}
+	if name == f.switcher.ActiveEndpoint() {
+		return nil
+	}
+	return f.activate(name, reason)
+}
+
+// activate switches to name; f.mu must be held
+func (f *Failover) activate(name, reason string) error {
+	from := f.switcher.ActiveEndpoint()
+	if err := f.switcher.SetActiveEndpoint(name); err != nil {
+		return err
+	}
+	f.last = &Switch{From: from, To: name, Reason: reason, At: clock.Stamp(f.now())}
+	switches.WithLabelValues(name, reason).Inc()
+	f.setActiveGauge()
+	f.logger.Warn("switched maestro endpoint", "from", from, "to", name, "reason", reason)
+	return nil
+}
+
+func (f *Failover) setActiveGauge() {
+	active := f.switcher.ActiveEndpoint()
+	for _, name := range f.switcher.Endpoints() {
+		if name == active {
+			activeGauge.WithLabelValues(name).Set(1)
+		} else {
+			activeGauge.WithLabelValues(name).Set(0)
+		}
+	}
+}
+
+// Status returns the health of every endpoint, the primary first, and the
+// last switch, nil when calls never switched
+func (f *Failover) Status() ([]EndpointStatus, *Switch) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	active := f.switcher.ActiveEndpoint()
+	statuses := make([]EndpointStatus, 0, len(f.health))
+	for _, name := range f.switcher.Endpoints() {
+		h := f.health[name]
+		s := EndpointStatus{
+			Name:                name,
+			Active:              name == active,
+			Healthy:             h.failures == 0,
+			ConsecutiveFailures: h.failures,
+			LastError:           h.lastError,
+		}
+		if !h.checkedAt.IsZero() {
+			checkedAt := clock.Stamp(h.checkedAt)
+			s.CheckedAt = &checkedAt
+		}
+		statuses = append(statuses, s)
+	}
+	return statuses, f.last
+}
@@ -0,0 +1,130 @@
+package maestrofailover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type fakeSwitcher struct {
+	active string
+	down   map[string]bool
+}
+
+func newFakeSwitcher() *fakeSwitcher {
+	return &fakeSwitcher{active: "primary", down: map[string]bool{}}
+}
+
+func (s *fakeSwitcher) Endpoints() []string    { return []string{"primary", "secondary"} }
+func (s *fakeSwitcher) ActiveEndpoint() string { return s.active }
+
+func (s *fakeSwitcher) SetActiveEndpoint(name string) error {
+	if name != "primary" && name != "secondary" {
+		return fmt.Errorf("unknown Maestro endpoint %q", name)
+	}
+	s.active = name
+	return nil
+}
+
+func (s *fakeSwitcher) ProbeEndpoint(_ context.Context, name string) error {
+	if s.down[name] {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func newTestFailover(s *fakeSwitcher) *Failover {
+	return New(s, time.Second, 2, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestFailover_Check_FailsOverAtThreshold(t *testing.T) {
+	s := newFakeSwitcher()
+	f := newTestFailover(s)
+	before := testutil.ToFloat64(switches.WithLabelValues("secondary", ReasonFailover))
+
+	s.down["primary"] = true
+	f.Check(context.Background())
+	if s.active != "primary" {
+		t.Fatalf("active = %q after one failure, want primary", s.active)
+	}
+
+	f.Check(context.Background())
+	if s.active != "secondary" {
+		t.Fatalf("active = %q after reaching the threshold, want secondary", s.active)
+	}
+	if got := testutil.ToFloat64(switches.WithLabelValues("secondary", ReasonFailover)) - before; got != 1 {
+		t.Errorf("failover switches = %v, want 1", got)
+	}
+	if testutil.ToFloat64(activeGauge.WithLabelValues("secondary")) != 1 || testutil.ToFloat64(activeGauge.WithLabelValues("primary")) != 0 {
+		t.Error("active gauge does not follow the switch")
+	}
+
+	statuses, last := f.Status()
+	if statuses[0].Healthy || statuses[0].ConsecutiveFailures != 2 || statuses[0].LastError != "connection refused" {
+		t.Errorf("primary status = %+v", statuses[0])
+	}
+	if !statuses[1].Active || !statuses[1].Healthy || statuses[1].CheckedAt == nil {
+		t.Errorf("secondary status = %+v", statuses[1])
+	}
+	if last == nil || last.From != "primary" || last.To != "secondary" || last.Reason != ReasonFailover {
+		t.Errorf("last switch = %+v", last)
+	}
+
+	// A recovered primary does not take calls back
+	s.down["primary"] = false
+	f.Check(context.Background())
+	if s.active != "secondary" {
+		t.Errorf("active = %q after the primary recovered, want secondary", s.active)
+	}
+}
+
+func TestFailover_Check_StaysWithoutHealthyEndpoint(t *testing.T) {
+	s := newFakeSwitcher()
+	f := newTestFailover(s)
+
+	s.down["primary"] = true
+	s.down["secondary"] = true
+	for range 3 {
+		f.Check(context.Background())
+	}
+	if s.active != "primary" {
+		t.Errorf("active = %q with both endpoints down, want primary", s.active)
+	}
+}
+
+func TestFailover_SwitchTo(t *testing.T) {
+	s := newFakeSwitcher()
+	f := newTestFailover(s)
+
+	if err := f.SwitchTo("secondary", ReasonManual, false); err != nil {
+		t.Fatalf("SwitchTo: %v", err)
+	}
+	if s.active != "secondary" {
+		t.Fatalf("active = %q, want secondary", s.active)
+	}
+
+	s.down["primary"] = true
+	f.Check(context.Background())
+	if err := f.SwitchTo("primary", ReasonManual, false); !errors.Is(err, ErrUnhealthy) {
+		t.Errorf("switching to an unhealthy endpoint: err = %v, want ErrUnhealthy", err)
+	}
+	if s.active != "secondary" {
+		t.Errorf("active = %q after a refused switch, want secondary", s.active)
+	}
+	if err := f.SwitchTo("primary", ReasonManual, true); err != nil {
+		t.Errorf("forced switch: %v", err)
+	}
+	if s.active != "primary" {
+		t.Errorf("active = %q after a forced switch, want primary", s.active)
+	}
+
+	if err := f.SwitchTo("tertiary", ReasonManual, false); err == nil {
+		t.Error("switching to an unknown endpoint succeeded")
+	}
+}
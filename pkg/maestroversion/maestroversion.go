@@ -0,0 +1,171 @@
+// Package maestroversion detects the API versions and optional capabilities
+// of the Maestro deployment the API talks to, at startup and then on an
+// interval, so features that only newer Maestro releases offer are used once
+// the deployment supports them. During a mixed-version rollout those features
+// are turned down with a clear error instead of failing upstream.
+package maestroversion
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+var (
+	compatibleGauge = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rosa_maestro_api_compatible",
+		Help: "1 when Maestro last reported serving the API version this server calls, 0 when it did not.",
+	})
+
+	searchFieldGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_maestro_search_field_supported",
+		Help: "1 when Maestro last accepted the optional resource bundle search field, 0 when it did not.",
+	}, []string{"field"})
+
+	checkFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rosa_maestro_version_check_failures_total",
+		Help: "Maestro version checks that failed, after which the last detected capabilities are kept.",
+	})
+)
+
+// Prober is the part of the Maestro client the detector uses
+type Prober interface {
+	GetAPIVersions(ctx context.Context) ([]string, error)
+	SupportsSearchField(ctx context.Context, field string) (bool, error)
+}
+
+// Capabilities are what the Maestro deployment was last found to support
+type Capabilities struct {
+	// APIVersions are the API versions Maestro reports serving
+	APIVersions []string `json:"api_versions"`
+	// Compatible is whether they include the version this server calls
+	Compatible bool `json:"compatible"`
+	// SearchFields are the optional resource bundle search fields Maestro
+	// accepts
+	SearchFields []string  `json:"search_fields"`
+	CheckedAt    time.Time `json:"checked_at"`
+}
+
+// Detector checks Maestro's capabilities on an interval and remembers the
+// last successful check
+type Detector struct {
+	prober   Prober
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+
+	mu      sync.RWMutex
+	current *Capabilities
+}
+
+// New creates a Detector checking prober every interval
+func New(prober Prober, interval time.Duration, logger *slog.Logger) *Detector {
+	return &Detector{
+		prober:   prober,
+		interval: interval,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run checks immediately and then on every interval until ctx is done
+func (d *Detector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		_ = d.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check detects Maestro's capabilities. When Maestro cannot be reached the
+// last detected capabilities are kept and the error is returned.
+func (d *Detector) Check(ctx context.Context) error {
+	versions, err := d.prober.GetAPIVersions(ctx)
+	if err != nil {
+		return d.failed(ctx, err)
+	}
+	caps := &Capabilities{
+		APIVersions:  versions,
+		Compatible:   slices.Contains(versions, maestro.APIVersion),
+		SearchFields: []string{},
+		CheckedAt:    clock.Stamp(d.now()),
+	}
+
+	fields := make([]string, 0, len(maestro.OptionalSearchFields))
+	for field := range maestro.OptionalSearchFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		ok, err := d.prober.SupportsSearchField(ctx, field)
+		if err != nil {
+			return d.failed(ctx, err)
+		}
+		if ok {
+			caps.SearchFields = append(caps.SearchFields, field)
+			searchFieldGauge.WithLabelValues(field).Set(1)
+		} else {
+			searchFieldGauge.WithLabelValues(field).Set(0)
+		}
+	}
+
+	if caps.Compatible {
+		compatibleGauge.Set(1)
+	} else {
+		compatibleGauge.Set(0)
+	}
+
+	d.mu.Lock()
+	previous := d.current
+	d.current = caps
+	d.mu.Unlock()
+
+	if !caps.Compatible {
+		d.logger.Error("Maestro does not report serving the API version this server calls",
+			"api_version", maestro.APIVersion, "maestro_api_versions", versions)
+	} else if previous == nil || !slices.Equal(previous.SearchFields, caps.SearchFields) || !previous.Compatible {
+		d.logger.Info("detected Maestro capabilities", "maestro_api_versions", versions, "search_fields", caps.SearchFields)
+	}
+	return nil
+}
+
+func (d *Detector) failed(ctx context.Context, err error) error {
+	if ctx.Err() != nil {
+		return err
+	}
+	checkFailures.Inc()
+	d.logger.Warn("failed to check Maestro version, keeping the last detected capabilities", "error", err)
+	return err
+}
+
+// Capabilities returns the last detected capabilities, or nil before the
+// first successful check
+func (d *Detector) Capabilities() *Capabilities {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.current
+}
+
+// SearchFieldSupported reports whether Maestro was last found to accept the
+// optional search field. Before the first successful check no optional field
+// is supported.
+func (d *Detector) SearchFieldSupported(field string) bool {
+	caps := d.Capabilities()
+	return caps != nil && slices.Contains(caps.SearchFields, field)
+}
@@ -0,0 +1,70 @@
+package maestroversion
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+)
+
+type fakeProber struct {
+	versions []string
+	fields   map[string]bool
+	err      error
+}
+
+func (f *fakeProber) GetAPIVersions(ctx context.Context) ([]string, error) {
+	return f.versions, f.err
+}
+
+func (f *fakeProber) SupportsSearchField(ctx context.Context, field string) (bool, error) {
+	return f.fields[field], f.err
+}
+
+func TestDetector_Check(t *testing.T) {
+	prober := &fakeProber{versions: []string{"v1"}}
+	d := New(prober, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if d.Capabilities() != nil || d.SearchFieldSupported("deleted_at") {
+		t.Fatal("expected no capabilities before the first check")
+	}
+
+	if err := d.Check(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	caps := d.Capabilities()
+	if !caps.Compatible || !slices.Equal(caps.APIVersions, []string{"v1"}) {
+		t.Errorf("expected a compatible v1 Maestro, got %+v", caps)
+	}
+	if d.SearchFieldSupported("deleted_at") {
+		t.Error("expected deleted_at to be unsupported")
+	}
+
+	// Maestro is upgraded mid-rollout
+	prober.fields = map[string]bool{"deleted_at": true}
+	_ = d.Check(context.Background())
+	if !d.SearchFieldSupported("deleted_at") {
+		t.Error("expected deleted_at to be supported after the upgrade")
+	}
+
+	// An unreachable Maestro keeps what was detected last
+	prober.err = errors.New("connection refused")
+	if err := d.Check(context.Background()); err == nil {
+		t.Fatal("expected the check to fail")
+	}
+	if !d.SearchFieldSupported("deleted_at") {
+		t.Error("expected the last detected capabilities to be kept")
+	}
+}
+
+func TestDetector_Incompatible(t *testing.T) {
+	d := New(&fakeProber{versions: []string{"v2"}}, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err := d.Check(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if d.Capabilities().Compatible {
+		t.Error("expected a Maestro without v1 to be incompatible")
+	}
+}
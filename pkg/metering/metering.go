@@ -0,0 +1,252 @@
+// Package metering emits a record of every billable API call to a sink the
+// billing side consumes. Records are batched and delivered at least once:
+// failed batches are retried until they are delivered, so consumers must
+// deduplicate on the record ID. Records still buffered when the process
+// dies, or arriving while the buffer is full, are lost and counted.
+package metering
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Sinks
+const (
+	SinkFile    = "file"
+	SinkSQS     = "sqs"
+	SinkKinesis = "kinesis"
+)
+
+// finalFlushTimeout bounds delivering the last batch on shutdown
+const finalFlushTimeout = 10 * time.Second
+
+// maxRetryInterval caps the backoff between delivery attempts
+const maxRetryInterval = 30 * time.Second
+
+var (
+	recordsEmitted = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rosa_metering_records_emitted_total",
+		Help: "Metering records accepted for delivery.",
+	})
+
+	recordsDelivered = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rosa_metering_records_delivered_total",
+		Help: "Metering records delivered to the sink.",
+	})
+
+	recordsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_metering_records_dropped_total",
+		Help: "Metering records lost, by reason (buffer-full, shutdown).",
+	}, []string{"reason"})
+
+	deliveryFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rosa_metering_delivery_failures_total",
+		Help: "Failed attempts to deliver a batch of metering records, each retried.",
+	})
+
+	bufferedRecords = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rosa_metering_buffered_records",
+		Help: "Metering records waiting to be delivered.",
+	})
+)
+
+// Record is one billable operation
+type Record struct {
+	// ID is unique per record; consumers deduplicate retried deliveries on it
+	ID        string    `json:"id"`
+	AccountID string    `json:"accountId"`
+	Operation string    `json:"operation"`
+	Units     int       `json:"units"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"requestId,omitempty"`
+}
+
+// Sink delivers batches of records
+type Sink interface {
+	// Send delivers records. It returns a *PartialError when only some were
+	// delivered, so only the rest are sent again.
+	Send(ctx context.Context, records []Record) error
+}
+
+// PartialError reports the records of a batch a sink failed to deliver
+type PartialError struct {
+	Failed []Record
+	Err    error
+}
+
+func (e *PartialError) Error() string {
+	return fmt.Sprintf("%d records not delivered: %v", len(e.Failed), e.Err)
+}
+
+func (e *PartialError) Unwrap() error { return e.Err }
+
+// Config configures batching
+type Config struct {
+	// BatchSize is the most records sent at once; a full batch is sent
+	// without waiting for FlushInterval
+	BatchSize int
+	// FlushInterval is the longest a record waits to be sent
+	FlushInterval time.Duration
+	// BufferSize is how many records may wait for delivery; records emitted
+	// while it is full are dropped
+	BufferSize int
+}
+
+// Emitter batches records and delivers them to a sink
+type Emitter struct {
+	cfg    Config
+	sink   Sink
+	queue  chan Record
+	logger *slog.Logger
+	// retryInterval is the first backoff after a failed delivery
+	retryInterval time.Duration
+}
+
+// NewEmitter creates an emitter delivering to sink
+func NewEmitter(cfg Config, sink Sink, logger *slog.Logger) *Emitter {
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = 10000
+	}
+	return &Emitter{
+		cfg:           cfg,
+		sink:          sink,
+		queue:         make(chan Record, cfg.BufferSize),
+		logger:        logger,
+		retryInterval: time.Second,
+	}
+}
+
+// Emit queues rec for delivery without blocking, filling in its ID and
+// timestamp when unset
+func (e *Emitter) Emit(rec Record) {
+	if rec.ID == "" {
+		rec.ID = uuid.NewString()
+	}
+	if rec.Timestamp.IsZero() {
+		rec.Timestamp = time.Now().UTC()
+	}
+	select {
+	case e.queue <- rec:
+		recordsEmitted.Inc()
+		bufferedRecords.Inc()
+	default:
+		recordsDropped.WithLabelValues("buffer-full").Inc()
+		e.logger.Error("metering buffer full, dropping record",
+			"account_id", rec.AccountID, "operation", rec.Operation, "record_id", rec.ID)
+	}
+}
+
+// Run delivers batches until ctx is done, then delivers what is left
+func (e *Emitter) Run(ctx context.Context) {
+	e.logger.Info("metering started", "batch_size", e.cfg.BatchSize, "flush_interval", e.cfg.FlushInterval)
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Record, 0, e.cfg.BatchSize)
+	for {
+		select {
+		case <-ctx.Done():
+			e.shutdown(ctx, batch)
+			return
+		case rec := <-e.queue:
+			batch = append(batch, rec)
+			if len(batch) < e.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		}
+		if rest := e.deliver(ctx, batch); rest != nil {
+			e.shutdown(ctx, rest)
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// deliver sends batch until all of it is delivered, backing off between
+// attempts. If ctx ends first it returns the records not delivered.
+func (e *Emitter) deliver(ctx context.Context, batch []Record) []Record {
+	pending := batch
+	interval := e.retryInterval
+	for attempt := 1; ; attempt++ {
+		err := e.sink.Send(ctx, pending)
+		if err == nil {
+			e.delivered(len(pending))
+			return nil
+		}
+		deliveryFailures.Inc()
+		var partial *PartialError
+		if errors.As(err, &partial) {
+			e.delivered(len(pending) - len(partial.Failed))
+			pending = partial.Failed
+		}
+		e.logger.Warn("failed to deliver metering records, retrying",
+			"error", err, "records", len(pending), "attempt", attempt, "retry_in", interval)
+
+		select {
+		case <-ctx.Done():
+			return pending
+		case <-time.After(interval):
+		}
+		interval = min(interval*2, maxRetryInterval)
+	}
+}
+
+// shutdown makes one last attempt to deliver batch and the buffered records
+func (e *Emitter) shutdown(ctx context.Context, batch []Record) {
+drain:
+	for {
+		select {
+		case rec := <-e.queue:
+			batch = append(batch, rec)
+		default:
+			break drain
+		}
+	}
+
+	flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), finalFlushTimeout)
+	defer cancel()
+	for len(batch) > 0 {
+		n := min(len(batch), e.cfg.BatchSize)
+		err := e.sink.Send(flushCtx, batch[:n])
+		var partial *PartialError
+		switch {
+		case err == nil:
+			e.delivered(n)
+		case errors.As(err, &partial):
+			e.delivered(n - len(partial.Failed))
+			e.dropped(len(partial.Failed), err)
+		default:
+			e.dropped(n, err)
+		}
+		batch = batch[n:]
+	}
+	e.logger.Info("metering stopped")
+}
+
+func (e *Emitter) delivered(n int) {
+	recordsDelivered.Add(float64(n))
+	bufferedRecords.Sub(float64(n))
+}
+
+func (e *Emitter) dropped(n int, err error) {
+	recordsDropped.WithLabelValues("shutdown").Add(float64(n))
+	bufferedRecords.Sub(float64(n))
+	e.logger.Error("failed to deliver metering records on shutdown", "error", err, "records", n)
+}
@@ -0,0 +1,146 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// recordingSink keeps the batches it receives, failing the first failures
+// calls and, when partial is set, every odd record of the next call
+type recordingSink struct {
+	mu       sync.Mutex
+	batches  [][]Record
+	failures int
+	partial  bool
+	sent     chan struct{}
+}
+
+func (s *recordingSink) Send(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer func() {
+		if s.sent != nil {
+			s.sent <- struct{}{}
+		}
+	}()
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("throttled")
+	}
+	if s.partial {
+		s.partial = false
+		var ok, failed []Record
+		for i, rec := range records {
+			if i%2 == 1 {
+				failed = append(failed, rec)
+			} else {
+				ok = append(ok, rec)
+			}
+		}
+		s.batches = append(s.batches, ok)
+		return &PartialError{Failed: failed, Err: errors.New("throttled")}
+	}
+	s.batches = append(s.batches, append([]Record(nil), records...))
+	return nil
+}
+
+func (s *recordingSink) delivered() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := map[string]int{}
+	for _, b := range s.batches {
+		for _, rec := range b {
+			ids[rec.ID]++
+		}
+	}
+	return ids
+}
+
+func TestEmitter_BatchesFullBatches(t *testing.T) {
+	sink := &recordingSink{sent: make(chan struct{}, 10)}
+	e := NewEmitter(Config{BatchSize: 3, FlushInterval: time.Hour}, sink, testLogger())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		e.Run(ctx)
+	}()
+
+	for i := 0; i < 3; i++ {
+		e.Emit(Record{AccountID: "123456789012", Operation: "POST /api/v0/clusters", Units: 1})
+	}
+	select {
+	case <-sink.sent:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a full batch to be sent without waiting for the flush interval")
+	}
+	cancel()
+	<-done
+
+	if len(sink.batches) != 1 || len(sink.batches[0]) != 3 {
+		t.Fatalf("expected one batch of 3, got %v", sink.batches)
+	}
+	if rec := sink.batches[0][0]; rec.ID == "" || rec.Timestamp.IsZero() {
+		t.Errorf("expected the ID and timestamp to be filled in, got %+v", rec)
+	}
+}
+
+func TestEmitter_RetriesUntilDelivered(t *testing.T) {
+	sink := &recordingSink{failures: 1, partial: true}
+	e := NewEmitter(Config{BatchSize: 4, FlushInterval: time.Hour}, sink, testLogger())
+	e.retryInterval = time.Millisecond
+
+	batch := make([]Record, 4)
+	for i := range batch {
+		batch[i] = Record{ID: string(rune('a' + i))}
+	}
+	if rest := e.deliver(context.Background(), batch); rest != nil {
+		t.Fatalf("expected the batch to be delivered, got %d left", len(rest))
+	}
+
+	ids := sink.delivered()
+	if len(ids) != 4 {
+		t.Fatalf("expected every record delivered, got %v", ids)
+	}
+	for id, n := range ids {
+		if n != 1 {
+			t.Errorf("expected %s to be delivered once after a partial failure, got %d", id, n)
+		}
+	}
+}
+
+func TestEmitter_FlushesOnShutdown(t *testing.T) {
+	sink := &recordingSink{}
+	e := NewEmitter(Config{BatchSize: 100, FlushInterval: time.Hour}, sink, testLogger())
+	for i := 0; i < 5; i++ {
+		e.Emit(Record{AccountID: "123456789012"})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	e.Run(ctx)
+
+	if got := len(sink.delivered()); got != 5 {
+		t.Errorf("expected the buffered records to be delivered on shutdown, got %d", got)
+	}
+}
+
+func TestEmitter_DropsWhenBufferFull(t *testing.T) {
+	sink := &recordingSink{}
+	e := NewEmitter(Config{BatchSize: 10, BufferSize: 2}, sink, testLogger())
+	for i := 0; i < 3; i++ {
+		e.Emit(Record{AccountID: "123456789012"})
+	}
+	if len(e.queue) != 2 {
+		t.Errorf("expected the buffer to hold 2 records, got %d", len(e.queue))
+	}
+}
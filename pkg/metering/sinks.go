@@ -0,0 +1,188 @@
+package metering
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// Batch limits of the AWS sinks
+const (
+	sqsMaxBatch     = 10
+	kinesisMaxBatch = 500
+)
+
+// FileSink appends records to a file as JSON lines, for local development
+// and for a log shipper to forward
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens path for appending, creating it if needed
+func NewFileSink(path string) (*FileSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metering file: %w", err)
+	}
+	return &FileSink{file: f}, nil
+}
+
+// Send writes records and syncs them to disk before returning
+func (s *FileSink) Send(ctx context.Context, records []Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	w := bufio.NewWriter(s.file)
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to encode metering record: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("failed to write metering records: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync metering file: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// SQSClient provides the SQS operation used by SQSSink
+type SQSClient interface {
+	SendMessageBatch(ctx context.Context, params *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
+}
+
+// SQSSink sends records to an SQS queue, one message per record
+type SQSSink struct {
+	queueURL string
+	client   SQSClient
+}
+
+// NewSQSSink creates a sink sending to the queue at queueURL
+func NewSQSSink(queueURL string, client SQSClient) *SQSSink {
+	return &SQSSink{queueURL: queueURL, client: client}
+}
+
+// Send sends records in batches of up to ten messages
+func (s *SQSSink) Send(ctx context.Context, records []Record) error {
+	var failed []Record
+	var errs []error
+	for start := 0; start < len(records); start += sqsMaxBatch {
+		chunk := records[start:min(start+sqsMaxBatch, len(records))]
+
+		entries := make([]sqstypes.SendMessageBatchRequestEntry, len(chunk))
+		for i, rec := range chunk {
+			body, err := json.Marshal(rec)
+			if err != nil {
+				return fmt.Errorf("failed to encode metering record: %w", err)
+			}
+			entries[i] = sqstypes.SendMessageBatchRequestEntry{
+				Id:          aws.String(strconv.Itoa(i)),
+				MessageBody: aws.String(string(body)),
+			}
+		}
+
+		out, err := s.client.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{
+			QueueUrl: aws.String(s.queueURL),
+			Entries:  entries,
+		})
+		if err != nil {
+			failed = append(failed, chunk...)
+			errs = append(errs, err)
+			continue
+		}
+		for _, entry := range out.Failed {
+			i, _ := strconv.Atoi(aws.ToString(entry.Id))
+			failed = append(failed, chunk[i])
+			errs = append(errs, fmt.Errorf("%s: %s", aws.ToString(entry.Code), aws.ToString(entry.Message)))
+		}
+	}
+	return partial(records, failed, errs)
+}
+
+// KinesisClient provides the Kinesis operation used by KinesisSink
+type KinesisClient interface {
+	PutRecords(ctx context.Context, params *kinesis.PutRecordsInput, optFns ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error)
+}
+
+// KinesisSink puts records on a Kinesis stream, partitioned by account so
+// each account's records stay in order
+type KinesisSink struct {
+	stream string
+	client KinesisClient
+}
+
+// NewKinesisSink creates a sink putting records on stream
+func NewKinesisSink(stream string, client KinesisClient) *KinesisSink {
+	return &KinesisSink{stream: stream, client: client}
+}
+
+// Send puts records in batches of up to 500
+func (s *KinesisSink) Send(ctx context.Context, records []Record) error {
+	var failed []Record
+	var errs []error
+	for start := 0; start < len(records); start += kinesisMaxBatch {
+		chunk := records[start:min(start+kinesisMaxBatch, len(records))]
+
+		entries := make([]kinesistypes.PutRecordsRequestEntry, len(chunk))
+		for i, rec := range chunk {
+			data, err := json.Marshal(rec)
+			if err != nil {
+				return fmt.Errorf("failed to encode metering record: %w", err)
+			}
+			entries[i] = kinesistypes.PutRecordsRequestEntry{
+				Data:         data,
+				PartitionKey: aws.String(rec.AccountID),
+			}
+		}
+
+		out, err := s.client.PutRecords(ctx, &kinesis.PutRecordsInput{
+			StreamName: aws.String(s.stream),
+			Records:    entries,
+		})
+		if err != nil {
+			failed = append(failed, chunk...)
+			errs = append(errs, err)
+			continue
+		}
+		// Results are in request order; failed ones carry an error code
+		for i, result := range out.Records {
+			if result.ErrorCode != nil {
+				failed = append(failed, chunk[i])
+				errs = append(errs, fmt.Errorf("%s: %s", aws.ToString(result.ErrorCode), aws.ToString(result.ErrorMessage)))
+			}
+		}
+	}
+	return partial(records, failed, errs)
+}
+
+// partial returns nil when nothing failed, the errors when everything did,
+// and a *PartialError otherwise
+func partial(records, failed []Record, errs []error) error {
+	switch {
+	case len(failed) == 0:
+		return nil
+	case len(failed) == len(records):
+		return errors.Join(errs...)
+	default:
+		return &PartialError{Failed: failed, Err: errors.Join(errs...)}
+	}
+}
@@ -0,0 +1,134 @@
+package metering
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	kinesistypes "github.com/aws/aws-sdk-go-v2/service/kinesis/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+func records(n int) []Record {
+	recs := make([]Record, n)
+	for i := range recs {
+		recs[i] = Record{ID: string(rune('a' + i%26)), AccountID: "123456789012", Operation: "POST /api/v0/work", Units: 1}
+	}
+	return recs
+}
+
+// fakeSQS fails the entries with the IDs in fail
+type fakeSQS struct {
+	calls   int
+	entries int
+	fail    map[string]bool
+	err     error
+}
+
+func (f *fakeSQS) SendMessageBatch(ctx context.Context, in *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	out := &sqs.SendMessageBatchOutput{}
+	for _, e := range in.Entries {
+		f.entries++
+		if f.fail[aws.ToString(e.Id)] {
+			out.Failed = append(out.Failed, sqstypes.BatchResultErrorEntry{Id: e.Id, Code: aws.String("Throttled")})
+		}
+	}
+	return out, nil
+}
+
+func TestSQSSink_Send(t *testing.T) {
+	client := &fakeSQS{}
+	if err := NewSQSSink("https://sqs.example/queue", client).Send(context.Background(), records(25)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if client.calls != 3 || client.entries != 25 {
+		t.Errorf("expected 25 records in 3 batches, got %d in %d", client.entries, client.calls)
+	}
+
+	client = &fakeSQS{fail: map[string]bool{"1": true}}
+	err := NewSQSSink("https://sqs.example/queue", client).Send(context.Background(), records(3))
+	var partial *PartialError
+	if !errors.As(err, &partial) || len(partial.Failed) != 1 || partial.Failed[0].ID != "b" {
+		t.Errorf("expected the second record to be reported failed, got %v", err)
+	}
+
+	client = &fakeSQS{err: errors.New("access denied")}
+	err = NewSQSSink("https://sqs.example/queue", client).Send(context.Background(), records(3))
+	if err == nil || errors.As(err, &partial) {
+		t.Errorf("expected a failed call to fail the whole batch, got %v", err)
+	}
+}
+
+// fakeKinesis fails the records at the indexes in fail
+type fakeKinesis struct {
+	fail map[int]bool
+	keys []string
+}
+
+func (f *fakeKinesis) PutRecords(ctx context.Context, in *kinesis.PutRecordsInput, _ ...func(*kinesis.Options)) (*kinesis.PutRecordsOutput, error) {
+	out := &kinesis.PutRecordsOutput{}
+	for i, r := range in.Records {
+		f.keys = append(f.keys, aws.ToString(r.PartitionKey))
+		result := kinesistypes.PutRecordsResultEntry{SequenceNumber: aws.String("1")}
+		if f.fail[i] {
+			result = kinesistypes.PutRecordsResultEntry{ErrorCode: aws.String("ProvisionedThroughputExceededException")}
+		}
+		out.Records = append(out.Records, result)
+	}
+	return out, nil
+}
+
+func TestKinesisSink_Send(t *testing.T) {
+	client := &fakeKinesis{fail: map[int]bool{2: true}}
+	err := NewKinesisSink("metering", client).Send(context.Background(), records(3))
+
+	var partial *PartialError
+	if !errors.As(err, &partial) || len(partial.Failed) != 1 || partial.Failed[0].ID != "c" {
+		t.Errorf("expected the third record to be reported failed, got %v", err)
+	}
+	if client.keys[0] != "123456789012" {
+		t.Errorf("expected records to be partitioned by account, got %q", client.keys[0])
+	}
+}
+
+func TestFileSink_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metering.jsonl")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer sink.Close()
+
+	for i := 0; i < 2; i++ {
+		if err := sink.Send(context.Background(), records(2)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	lines := 0
+	for scanner := bufio.NewScanner(f); scanner.Scan(); lines++ {
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil || rec.AccountID != "123456789012" {
+			t.Errorf("unexpected line %q", scanner.Text())
+		}
+	}
+	if lines != 4 {
+		t.Errorf("expected 4 appended records, got %d", lines)
+	}
+}
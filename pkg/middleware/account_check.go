@@ -44,6 +44,10 @@ func (a *AccountCheck) RequireProvisioned(next http.Handler) http.Handler {
 		provisioned, err := a.authorizer.IsAccountProvisioned(ctx, accountID)
 		if err != nil {
 			a.logger.Error("failed to check account provisioning status", "error", err, "account_id", accountID)
+			if authzTimedOut(err) {
+				a.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+				return
+			}
 			a.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check account status")
 			return
 		}
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// AccountLabelOther is the account label of requests from accounts outside
+// the busiest ones
+const AccountLabelOther = "other"
+
+var accountRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_account_requests_total",
+	Help: "API requests by caller account and status class. Only the busiest accounts are labeled; the rest are counted under account=\"other\".",
+}, []string{"account", "code"})
+
+// AccountMetrics counts requests per account while bounding the number of
+// account label values: only the top N busiest accounts keep their own
+// label, and the rest share AccountLabelOther. Request counts decay every
+// rank interval, when the top accounts are re-ranked, so the labels follow
+// the current load rather than all-time totals. The series of accounts that
+// drop out of the top are deleted, which resets their counters if they
+// return.
+type AccountMetrics struct {
+	mu       sync.Mutex
+	topN     int
+	interval time.Duration
+	counts   map[string]float64
+	top      map[string]bool
+	ranked   time.Time
+	now      func() time.Time
+}
+
+// NewAccountMetrics creates a new AccountMetrics middleware labeling the
+// topN busiest accounts, re-ranked every interval. A topN of 0 counts every
+// account under AccountLabelOther.
+func NewAccountMetrics(topN int, interval time.Duration) *AccountMetrics {
+	return &AccountMetrics{
+		topN:     topN,
+		interval: interval,
+		counts:   make(map[string]float64),
+		top:      make(map[string]bool),
+		now:      time.Now,
+	}
+}
+
+// Track counts each request with an account ID once it completes
+func (m *AccountMetrics) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID := GetAccountID(r.Context())
+		if accountID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		accountRequests.WithLabelValues(m.label(accountID), strconv.Itoa(rec.status/100)+"xx").Inc()
+	})
+}
+
+// label returns the account label of a request from accountID. Free top
+// slots go to accounts as they are first seen, so accounts are labeled
+// before the first ranking.
+func (m *AccountMetrics) label(accountID string) string {
+	if m.topN <= 0 {
+		return AccountLabelOther
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counts[accountID]++
+	now := m.now()
+	if m.ranked.IsZero() {
+		m.ranked = now
+	}
+	if now.Sub(m.ranked) >= m.interval {
+		m.rank()
+		m.ranked = now
+	} else if !m.top[accountID] && len(m.top) < m.topN {
+		m.top[accountID] = true
+	}
+	if m.top[accountID] {
+		return accountID
+	}
+	return AccountLabelOther
+}
+
+// rank replaces the top accounts with the busiest ones, deleting the series
+// of accounts that dropped out, and halves every count. Must be called with
+// mu held.
+func (m *AccountMetrics) rank() {
+	accounts := make([]string, 0, len(m.counts))
+	for id := range m.counts {
+		accounts = append(accounts, id)
+	}
+	sort.Slice(accounts, func(i, j int) bool {
+		if m.counts[accounts[i]] != m.counts[accounts[j]] {
+			return m.counts[accounts[i]] > m.counts[accounts[j]]
+		}
+		return accounts[i] < accounts[j]
+	})
+	if len(accounts) > m.topN {
+		accounts = accounts[:m.topN]
+	}
+
+	top := make(map[string]bool, len(accounts))
+	for _, id := range accounts {
+		top[id] = true
+	}
+	for id := range m.top {
+		if !top[id] {
+			accountRequests.DeletePartialMatch(prometheus.Labels{"account": id})
+		}
+	}
+	m.top = top
+
+	// Halving lets the ranking follow recent load; accounts that go quiet
+	// fall out of the map once their count decays below one request
+	for id, n := range m.counts {
+		if n /= 2; n < 1 {
+			delete(m.counts, id)
+		} else {
+			m.counts[id] = n
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAccountMetrics_Label(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	m := NewAccountMetrics(2, time.Minute)
+	m.now = func() time.Time { return now }
+
+	// Free slots go to the first accounts seen
+	for _, id := range []string{"111111111111", "222222222222"} {
+		if got := m.label(id); got != id {
+			t.Errorf("expected %s to be labeled, got %q", id, got)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		if got := m.label("333333333333"); got != AccountLabelOther {
+			t.Fatalf("expected a third account to be counted as other, got %q", got)
+		}
+	}
+
+	// Re-ranking keeps the busiest accounts
+	now = now.Add(time.Minute)
+	if got := m.label("333333333333"); got != "333333333333" {
+		t.Errorf("expected the busiest account to be labeled after re-ranking, got %q", got)
+	}
+	if got := m.label("111111111111"); got != "111111111111" {
+		t.Errorf("expected the next busiest account to keep its label, got %q", got)
+	}
+	if got := m.label("222222222222"); got != AccountLabelOther {
+		t.Errorf("expected the quietest account to be counted as other, got %q", got)
+	}
+}
+
+func TestAccountMetrics_Track(t *testing.T) {
+	m := NewAccountMetrics(1, time.Hour)
+	handler := m.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+
+	send := func(accountID string) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/work", nil)
+		if accountID != "" {
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, accountID))
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	labeled := accountRequests.WithLabelValues("444444444444", "4xx")
+	other := accountRequests.WithLabelValues(AccountLabelOther, "4xx")
+	beforeLabeled, beforeOther := testutil.ToFloat64(labeled), testutil.ToFloat64(other)
+
+	send("444444444444")
+	send("555555555555")
+	send("")
+
+	if got := testutil.ToFloat64(labeled) - beforeLabeled; got != 1 {
+		t.Errorf("expected 1 request counted for the top account, got %v", got)
+	}
+	if got := testutil.ToFloat64(other) - beforeOther; got != 1 {
+		t.Errorf("expected 1 request counted as other, got %v", got)
+	}
+}
+
+func TestAccountMetrics_Disabled(t *testing.T) {
+	m := NewAccountMetrics(0, time.Minute)
+	if got := m.label("111111111111"); got != AccountLabelOther {
+		t.Errorf("expected every account to be counted as other, got %q", got)
+	}
+}
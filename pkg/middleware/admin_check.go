@@ -49,6 +49,10 @@ func (a *AdminCheck) RequireAdmin(next http.Handler) http.Handler {
 		isAdmin, err := a.authorizer.IsAdmin(ctx, accountID, callerARN)
 		if err != nil {
 			a.logger.Error("failed to check admin status", "error", err, "account_id", accountID, "caller_arn", callerARN)
+			if authzTimedOut(err) {
+				a.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+				return
+			}
 			a.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check admin status")
 			return
 		}
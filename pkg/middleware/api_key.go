@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// ContextKeyAPIKey is the context key for the API key a request was made with
+const ContextKeyAPIKey contextKey = "api_key"
+
+// APIKeyResolver looks up the active API key a request presents
+type APIKeyResolver interface {
+	ResolveAPIKey(ctx context.Context, token string) (*store.APIKey, error)
+}
+
+// APIKeys authenticates machine clients presenting an API key as a bearer
+// token in the Authorization header
+type APIKeys struct {
+	resolver APIKeyResolver
+	logger   *slog.Logger
+}
+
+// NewAPIKeys creates a new APIKeys middleware, which rejects every key until
+// a resolver is set
+func NewAPIKeys(logger *slog.Logger) *APIKeys {
+	return &APIKeys{logger: logger}
+}
+
+// WithResolver looks keys up with resolver, once authz is available
+func (k *APIKeys) WithResolver(resolver APIKeyResolver) *APIKeys {
+	k.resolver = resolver
+	return k
+}
+
+// Authenticate sets the account and caller ARN of requests bearing an API key
+// to the key's account and synthetic principal, so they are authorized like
+// any other caller, and adds the key to the context, where the Authz
+// middleware limits the request to the key's actions. The Authorization
+// header is removed once the key is verified. Requests also carrying gateway
+// identity are rejected, as are unknown, revoked and expired keys.
+// This middleware should run after Identity middleware.
+func (k *APIKeys) Authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerAPIKey(r)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		if _, ok := LookupAccountID(ctx); ok {
+			k.writeError(w, http.StatusBadRequest, "conflicting-identity", "Requests cannot carry both an API key and AWS identity")
+			return
+		}
+		if k.resolver == nil {
+			k.writeUnauthorized(w, "API keys are not enabled")
+			return
+		}
+
+		key, err := k.resolver.ResolveAPIKey(ctx, token)
+		switch {
+		case errors.Is(err, authz.ErrInvalidAPIKey):
+			k.logger.Warn("rejected API key", "error", err)
+			k.writeUnauthorized(w, "The API key is invalid, revoked or expired")
+			return
+		case err != nil:
+			k.logger.Error("failed to resolve API key", "error", err)
+			if authzTimedOut(err) {
+				k.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+				return
+			}
+			k.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check API key")
+			return
+		}
+
+		r.Header.Del("Authorization")
+		ctx = context.WithValue(ctx, ContextKeyAccountID, key.AccountID)
+		ctx = context.WithValue(ctx, ContextKeyCallerARN, key.PrincipalARN)
+		ctx = context.WithValue(ctx, ContextKeyAPIKey, key)
+		// The key's principal acts, not whoever the gateway authenticated
+		ctx = context.WithValue(ctx, ContextKeySuperAdmin, false)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// bearerAPIKey returns the API key in the Authorization header, if any.
+// Other bearer tokens and schemes are left to the gateway.
+func bearerAPIKey(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, strings.HasPrefix(token, authz.APIKeyPrefix)
+}
+
+func (k *APIKeys) writeUnauthorized(w http.ResponseWriter, reason string) {
+	w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	k.writeError(w, http.StatusUnauthorized, "invalid-api-key", reason)
+}
+
+func (k *APIKeys) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// GetAPIKey returns the API key a request was made with, or nil for a request
+// carrying AWS identity
+func GetAPIKey(ctx context.Context) *store.APIKey {
+	key, _ := ctx.Value(ContextKeyAPIKey).(*store.APIKey)
+	return key
+}
@@ -4,26 +4,48 @@ import (
 	"encoding/json"
 	"log/slog"
 	"net/http"
+	"sync"
 )
 
 // Authorization provides account allowlist-based authorization middleware
 type Authorization struct {
+	mu              sync.RWMutex
 	allowedAccounts map[string]struct{}
 	logger          *slog.Logger
 }
 
 // NewAuthorization creates a new Authorization middleware
 func NewAuthorization(allowedAccounts []string, logger *slog.Logger) *Authorization {
-	allowed := make(map[string]struct{}, len(allowedAccounts))
-	for _, acc := range allowedAccounts {
-		allowed[acc] = struct{}{}
-	}
 	return &Authorization{
-		allowedAccounts: allowed,
+		allowedAccounts: accountSet(allowedAccounts),
 		logger:          logger,
 	}
 }
 
+// SetAllowedAccounts replaces the allowlist, when the configuration is reloaded
+func (a *Authorization) SetAllowedAccounts(allowedAccounts []string) {
+	allowed := accountSet(allowedAccounts)
+	a.mu.Lock()
+	a.allowedAccounts = allowed
+	a.mu.Unlock()
+}
+
+func accountSet(accounts []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(accounts))
+	for _, acc := range accounts {
+		set[acc] = struct{}{}
+	}
+	return set
+}
+
+// Allowed reports whether an account is in the allowlist
+func (a *Authorization) Allowed(accountID string) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	_, allowed := a.allowedAccounts[accountID]
+	return allowed
+}
+
 // RequireAllowedAccount verifies that the AWS account is in the allowlist
 func (a *Authorization) RequireAllowedAccount(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -36,7 +58,7 @@ func (a *Authorization) RequireAllowedAccount(next http.Handler) http.Handler {
 			return
 		}
 
-		if _, allowed := a.allowedAccounts[accountID]; !allowed {
+		if !a.Allowed(accountID) {
 			a.logger.Warn("account not allowed", "account_id", accountID)
 			a.writeError(w, http.StatusForbidden, "account-not-allowed", "account not allowed")
 			return
@@ -458,3 +458,26 @@ func TestAuthorization_WriteError(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthorization_SetAllowedAccounts(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	auth := NewAuthorization([]string{"111111111111"}, logger)
+	handler := auth.RequireAllowedAccount(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	auth.SetAllowedAccounts([]string{"222222222222"})
+
+	for accountID, expected := range map[string]int{
+		"111111111111": http.StatusForbidden,
+		"222222222222": http.StatusOK,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, accountID))
+		rr := httptest.NewRecorder()
+		handler.ServeHTTP(rr, req)
+		if rr.Code != expected {
+			t.Errorf("account %s: expected status %d, got %d", accountID, expected, rr.Code)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -10,6 +11,7 @@ import (
 	"github.com/gorilla/mux"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
 )
 
 // Authz provides Cedar/AVP-based authorization middleware
@@ -18,6 +20,9 @@ type Authz struct {
 	logger     *slog.Logger
 	enabled    bool
 	region     string
+	guardrails authz.GuardrailChecker
+	bypass     []authz.BypassRule
+	audit      *authz.DecisionAudit
 }
 
 // NewAuthz creates a new Authz middleware
@@ -33,6 +38,23 @@ func NewAuthz(authorizer authz.Checker, enabled bool, region string, logger *slo
 	}
 }
 
+// WithGuardrails applies platform guardrails to privileged callers, who
+// otherwise bypass authorization. Other callers are checked against the
+// guardrails by the Checker itself.
+func (a *Authz) WithGuardrails(guardrails authz.GuardrailChecker) *Authz {
+	a.guardrails = guardrails
+	return a
+}
+
+// WithBypass exempts privileged callers from authorization on the routes the
+// rules cover, though not from guardrails, and refuses other callers there.
+// Every bypass is logged and recorded in audit, which may be nil.
+func (a *Authz) WithBypass(rules []authz.BypassRule, audit *authz.DecisionAudit) *Authz {
+	a.bypass = rules
+	a.audit = audit
+	return a
+}
+
 // Authorize performs AVP-based authorization
 // This middleware should run after Identity and Privileged middleware
 func (a *Authz) Authorize(next http.Handler) http.Handler {
@@ -56,26 +78,79 @@ func (a *Authz) Authorize(next http.Handler) http.Handler {
 			return
 		}
 
-		// Privileged accounts bypass authorization
-		if GetPrivileged(ctx) {
-			next.ServeHTTP(w, r)
+		requestTags, err := ParseRequestTags(r)
+		if err != nil {
+			a.writeError(w, http.StatusBadRequest, "invalid-request-tags", err.Error())
 			return
 		}
 
 		// Build authorization request
 		req := a.buildAuthzRequest(r, accountID, callerARN)
+		req.RequestTags = requestTags
+
+		if rule, ok := a.bypassRule(r); ok {
+			if !GetPrivileged(ctx) {
+				a.logger.Warn("bypass route denied: account is not privileged",
+					"account_id", accountID,
+					"caller_arn", callerARN,
+					"rule", rule.String(),
+				)
+				a.writeError(w, http.StatusForbidden, "not-privileged", "This operation requires a privileged account")
+				return
+			}
+			// A bypass skips authorization, but not platform guardrails
+			if !a.passesGuardrails(ctx, w, req, accountID) {
+				return
+			}
+			a.logger.Info("authorization bypassed",
+				"account_id", accountID,
+				"caller_arn", callerARN,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"action", req.Action,
+				"rule", rule.String(),
+			)
+			a.audit.Record(req, true, authz.BasisBypass)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// Privileged accounts bypass authorization, but not platform guardrails
+		if GetPrivileged(ctx) {
+			if a.passesGuardrails(ctx, w, req, accountID) {
+				next.ServeHTTP(w, r)
+			}
+			return
+		}
+
+		// Delegated requests are limited to the delegated actions
+		if delegation := GetDelegation(ctx); delegation != nil && !delegation.Allows(req.Action) {
+			a.logger.Warn("delegated request denied: action not delegated",
+				"account_id", accountID,
+				"caller_arn", callerARN,
+				"action", req.Action,
+			)
+			a.writeError(w, http.StatusForbidden, "delegation-action-denied",
+				"Action "+req.Action+" is not delegated to the caller's account")
+			return
+		}
+
+		// Requests made with an API key are limited to the key's actions
+		if key := GetAPIKey(ctx); key != nil && !key.Allows(req.Action) {
+			a.logger.Warn("API key request denied: action not allowed",
+				"account_id", accountID,
+				"key_id", key.KeyID,
+				"action", req.Action,
+			)
+			a.writeError(w, http.StatusForbidden, "api-key-action-denied",
+				"Action "+req.Action+" is not allowed for the API key")
+			return
+		}
 
 		// Perform authorization check
 		allowed, err := a.authorizer.Authorize(ctx, req)
 		if err != nil {
-			a.logger.Error("authorization check failed", "error", err, "account_id", accountID, "action", req.Action)
-			// Check if it's a "not provisioned" error
-			if strings.Contains(err.Error(), "not provisioned") {
-				a.writeError(w, http.StatusForbidden, "account-not-provisioned",
-					"Account is not provisioned for ROSA authorization")
-				return
-			}
-			a.writeError(w, http.StatusInternalServerError, "authorization-error", "Authorization check failed")
+			a.writeAuthorizeError(w, err, accountID, req.Action)
 			return
 		}
 
@@ -95,22 +170,111 @@ func (a *Authz) Authorize(next http.Handler) http.Handler {
 	})
 }
 
+// passesGuardrails checks a privileged caller's request against the platform
+// guardrails, answering the request when they deny it
+func (a *Authz) passesGuardrails(ctx context.Context, w http.ResponseWriter, req *authz.AuthzRequest, accountID string) bool {
+	if a.guardrails == nil {
+		return true
+	}
+	if err := a.guardrails.CheckGuardrails(ctx, req); err != nil {
+		a.writeAuthorizeError(w, err, accountID, req.Action)
+		return false
+	}
+	return true
+}
+
+// bypassRule returns the first bypass rule covering the request's route
+func (a *Authz) bypassRule(r *http.Request) (authz.BypassRule, bool) {
+	if len(a.bypass) == 0 {
+		return authz.BypassRule{}, false
+	}
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return authz.BypassRule{}, false
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return authz.BypassRule{}, false
+	}
+	for _, rule := range a.bypass {
+		if rule.Matches(r.Method, template) {
+			return rule, true
+		}
+	}
+	return authz.BypassRule{}, false
+}
+
+// writeAuthorizeError writes the response for a failed authorization check
+func (a *Authz) writeAuthorizeError(w http.ResponseWriter, err error, accountID, action string) {
+	if errors.Is(err, authz.ErrGuardrailDenied) {
+		a.writeError(w, http.StatusForbidden, "guardrail-denied",
+			"This action is forbidden by a platform guardrail")
+		return
+	}
+
+	a.logger.Error("authorization check failed", "error", err, "account_id", accountID, "action", action)
+	// Check if it's a "not provisioned" error
+	if strings.Contains(err.Error(), "not provisioned") {
+		a.writeError(w, http.StatusForbidden, "account-not-provisioned",
+			"Account is not provisioned for ROSA authorization")
+		return
+	}
+	if errors.Is(err, authz.ErrResourceAccountMismatch) {
+		a.writeError(w, http.StatusForbidden, "resource-account-mismatch",
+			"Resource does not belong to the caller's account")
+		return
+	}
+	if authzTimedOut(err) {
+		a.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+		return
+	}
+	a.writeError(w, http.StatusInternalServerError, "authorization-error", "Authorization check failed")
+}
+
 // buildAuthzRequest creates an authorization request from the HTTP request
 func (a *Authz) buildAuthzRequest(r *http.Request, accountID, callerARN string) *authz.AuthzRequest {
 	action := a.deriveAction(r)
 	resource := a.deriveResource(r)
 
 	return &authz.AuthzRequest{
-		AccountID:    accountID,
-		CallerARN:    callerARN,
-		Action:       action,
-		Resource:     resource,
-		ResourceTags: make(map[string]string), // Populated from the actual resource when available
-		RequestTags:  make(map[string]string), // Populated from the request body when available
-		Context:      make(map[string]any),
+		AccountID:       accountID,
+		CallerARN:       callerARN,
+		Action:          action,
+		Resource:        resource,
+		ResourceTags:    make(map[string]string), // Populated from the actual resource when available
+		RequestTags:     make(map[string]string), // Populated from the request tag headers and query parameters
+		Context:         make(map[string]any),
+		ResourceParents: a.deriveResourceParents(r, accountID),
 	}
 }
 
+// resourceActions are the schema actions of the typed resources, by verb.
+// Routes of other resources are authorized with verb+Resource actions, such
+// as CreateResource, which the schema does not declare.
+var resourceActions = map[string]map[string]string{
+	"Cluster": {
+		"Create":   schema.ActionCreateCluster,
+		"Describe": schema.ActionDescribeCluster,
+		"List":     schema.ActionListClusters,
+		"Update":   schema.ActionUpdateCluster,
+		"Delete":   schema.ActionDeleteCluster,
+	},
+	"NodePool": {
+		"Create":   schema.ActionCreateNodePool,
+		"Describe": schema.ActionDescribeNodePool,
+		"List":     schema.ActionListNodePools,
+		"Update":   schema.ActionUpdateNodePool,
+		"Delete":   schema.ActionDeleteNodePool,
+	},
+	"AccessEntry": {
+		"Create":   schema.ActionCreateAccessEntry,
+		"Describe": schema.ActionDescribeAccessEntry,
+		"List":     schema.ActionListAccessEntries,
+		"Update":   schema.ActionUpdateAccessEntry,
+		"Delete":   schema.ActionDeleteAccessEntry,
+	},
+}
+
 // deriveAction derives the ROSA action from the HTTP request
 func (a *Authz) deriveAction(r *http.Request) string {
 	// Map HTTP method + path to ROSA action
@@ -123,18 +287,18 @@ func (a *Authz) deriveAction(r *http.Request) string {
 	parts := strings.Split(strings.Trim(path, "/"), "/")
 
 	// Default action based on method
-	var actionPrefix string
+	var verb string
 	switch method {
 	case http.MethodPost:
-		actionPrefix = "Create"
-	case http.MethodGet:
-		actionPrefix = "Describe"
+		verb = "Create"
+	case http.MethodGet, http.MethodHead:
+		verb = "Describe"
 	case http.MethodPut, http.MethodPatch:
-		actionPrefix = "Update"
+		verb = "Update"
 	case http.MethodDelete:
-		actionPrefix = "Delete"
+		verb = "Delete"
 	default:
-		actionPrefix = "Unknown"
+		verb = "Unknown"
 	}
 
 	// Determine resource type
@@ -160,16 +324,20 @@ func (a *Authz) deriveAction(r *http.Request) string {
 	}
 
 	// Special case: List operations
-	if method == http.MethodGet {
+	if method == http.MethodGet || method == http.MethodHead {
 		vars := mux.Vars(r)
 		if _, hasID := vars["id"]; !hasID {
-			actionPrefix = "List"
-			// Pluralize for list operations
-			resourceType = resourceType + "s"
+			verb = "List"
 		}
 	}
 
-	return actionPrefix + resourceType
+	if action, ok := resourceActions[resourceType][verb]; ok {
+		return action
+	}
+	if verb == "List" {
+		return verb + resourceType + "s"
+	}
+	return verb + resourceType
 }
 
 // deriveResource derives the ROSA resource ARN from the HTTP request
@@ -187,6 +355,9 @@ func (a *Authz) deriveResource(r *http.Request) string {
 		if strings.Contains(path, "/access_entries/") {
 			return a.buildARN(accountID, "accessentry", id)
 		}
+		if strings.Contains(path, "/machines/") {
+			return a.buildARN(accountID, "machine", id)
+		}
 		if strings.Contains(path, "/clusters/") || strings.Contains(path, "/clusters") {
 			return a.buildARN(accountID, "cluster", id)
 		}
@@ -197,6 +368,30 @@ func (a *Authz) deriveResource(r *http.Request) string {
 	return "*"
 }
 
+// resourceParentVars are the route variables that name the resources a
+// nested route's resource belongs to, outermost first
+var resourceParentVars = []struct {
+	name         string
+	resourceType string
+}{
+	{"cluster_id", "cluster"},
+	{"nodepool_id", "nodepool"},
+}
+
+// deriveResourceParents returns the ARNs of the resources the request's
+// resource belongs to, e.g. the cluster of
+// /clusters/{cluster_id}/nodepools/{id}
+func (a *Authz) deriveResourceParents(r *http.Request, accountID string) []string {
+	vars := mux.Vars(r)
+	var parents []string
+	for _, v := range resourceParentVars {
+		if id := vars[v.name]; id != "" {
+			parents = append(parents, a.buildARN(accountID, v.resourceType, id))
+		}
+	}
+	return parents
+}
+
 // buildARN creates a ROSA ARN using the configured region
 func (a *Authz) buildARN(accountID, resourceType, resourceID string) string {
 	return "arn:aws:rosa:" + a.region + ":" + accountID + ":" + resourceType + "/" + resourceID
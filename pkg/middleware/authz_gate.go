@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+)
+
+// AuthzGate holds back authz-protected routes while authorization is
+// unavailable, such as after a degraded start with DynamoDB unreachable.
+// While unhealthy, requests get 503, except reads when readOnly is set.
+type AuthzGate struct {
+	healthy  atomic.Bool
+	readOnly bool
+	logger   *slog.Logger
+}
+
+// NewAuthzGate creates a new AuthzGate, initially healthy
+func NewAuthzGate(readOnly bool, logger *slog.Logger) *AuthzGate {
+	g := &AuthzGate{
+		readOnly: readOnly,
+		logger:   logger,
+	}
+	g.healthy.Store(true)
+	return g
+}
+
+// SetHealthy records whether authorization is available
+func (g *AuthzGate) SetHealthy(healthy bool) {
+	g.healthy.Store(healthy)
+}
+
+// Healthy reports whether authorization is available
+func (g *AuthzGate) Healthy() bool {
+	return g.healthy.Load()
+}
+
+// Gate rejects requests while authorization is unavailable
+func (g *AuthzGate) Gate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if g.healthy.Load() || (g.readOnly && isRead(r)) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		g.logger.Warn("rejecting request while authz is unavailable", "method", r.Method, "path", r.URL.Path)
+		w.Header().Set("Retry-After", "30")
+		g.writeError(w, http.StatusServiceUnavailable, "authz-unavailable", "Authorization is temporarily unavailable")
+	})
+}
+
+func isRead(r *http.Request) bool {
+	return r.Method == http.MethodGet || r.Method == http.MethodHead
+}
+
+func (g *AuthzGate) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
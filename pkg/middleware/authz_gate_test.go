@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+func TestAuthzGate(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	tests := []struct {
+		name           string
+		readOnly       bool
+		healthy        bool
+		method         string
+		expectedStatus int
+	}{
+		{name: "healthy passes writes", healthy: true, method: http.MethodPost, expectedStatus: http.StatusOK},
+		{name: "deny-all rejects reads", method: http.MethodGet, expectedStatus: http.StatusServiceUnavailable},
+		{name: "deny-all rejects writes", method: http.MethodPost, expectedStatus: http.StatusServiceUnavailable},
+		{name: "read-only passes reads", readOnly: true, method: http.MethodGet, expectedStatus: http.StatusOK},
+		{name: "read-only rejects writes", readOnly: true, method: http.MethodDelete, expectedStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gate := NewAuthzGate(tt.readOnly, logger)
+			gate.SetHealthy(tt.healthy)
+
+			handler := gate.Gate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(tt.method, "/api/v0/test", nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Code == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+				t.Error("expected Retry-After header on 503")
+			}
+		})
+	}
+}
@@ -0,0 +1,284 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+)
+
+// guardrailFunc implements authz.GuardrailChecker for testing
+type guardrailFunc func(ctx context.Context, req *authz.AuthzRequest) error
+
+func (f guardrailFunc) CheckGuardrails(ctx context.Context, req *authz.AuthzRequest) error {
+	return f(ctx, req)
+}
+
+func TestAuthz_Guardrails(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	denyDelete := guardrailFunc(func(ctx context.Context, req *authz.AuthzRequest) error {
+		if strings.HasPrefix(req.Action, "Delete") {
+			return fmt.Errorf("%w: %s", authz.ErrGuardrailDenied, req.Action)
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name           string
+		method         string
+		privileged     bool
+		authorizeErr   error
+		expectedStatus int
+		expectedCode   string
+	}{
+		{
+			name:           "privileged caller passes guardrails",
+			method:         http.MethodGet,
+			privileged:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "privileged caller denied by guardrail",
+			method:         http.MethodDelete,
+			privileged:     true,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "guardrail-denied",
+		},
+		{
+			name:           "checker guardrail denial",
+			method:         http.MethodGet,
+			authorizeErr:   fmt.Errorf("%w: DescribeResource", authz.ErrGuardrailDenied),
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "guardrail-denied",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &mockChecker{
+				authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+					return tt.authorizeErr == nil, tt.authorizeErr
+				},
+			}
+			handler := NewAuthz(checker, true, "us-east-1", logger).WithGuardrails(denyDelete).Authorize(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}))
+
+			req := httptest.NewRequest(tt.method, "/api/v0/resource_bundles", nil)
+			ctx := context.WithValue(req.Context(), ContextKeyAccountID, "123456789012")
+			ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::123456789012:role/operator")
+			ctx = context.WithValue(ctx, ContextKeyPrivileged, tt.privileged)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req.WithContext(ctx))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedCode != "" {
+				var resp map[string]interface{}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp["code"] != tt.expectedCode {
+					t.Errorf("expected code %q, got %v", tt.expectedCode, resp["code"])
+				}
+			}
+		})
+	}
+}
+
+func TestAuthz_Bypass(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	rules, err := authz.ParseBypassRules([]string{"POST /api/v0/work", "/api/v0/management_clusters/*"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	denyDelete := guardrailFunc(func(ctx context.Context, req *authz.AuthzRequest) error {
+		if strings.HasPrefix(req.Action, "Delete") {
+			return fmt.Errorf("%w: %s", authz.ErrGuardrailDenied, req.Action)
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name            string
+		method          string
+		path            string
+		privileged      bool
+		expectAuthorize bool
+		expectedStatus  int
+		expectedCode    string
+	}{
+		{
+			name:           "privileged caller skips authorization",
+			method:         http.MethodPost,
+			path:           "/api/v0/work",
+			privileged:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "prefix rule covers nested routes",
+			method:         http.MethodGet,
+			path:           "/api/v0/management_clusters/mc1",
+			privileged:     true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bypassed route still checks guardrails",
+			method:         http.MethodDelete,
+			path:           "/api/v0/management_clusters/mc1",
+			privileged:     true,
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "guardrail-denied",
+		},
+		{
+			name:           "tenant refused on a bypass route",
+			method:         http.MethodPost,
+			path:           "/api/v0/work",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "not-privileged",
+		},
+		{
+			name:            "other methods are authorized as usual",
+			method:          http.MethodGet,
+			path:            "/api/v0/work",
+			expectAuthorize: true,
+			expectedStatus:  http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &mockChecker{
+				authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+					if !tt.expectAuthorize {
+						t.Error("expected authorization to be skipped")
+					}
+					return true, nil
+				},
+			}
+			a := NewAuthz(checker, true, "us-east-1", logger).WithGuardrails(denyDelete).WithBypass(rules, nil)
+			ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+			router := mux.NewRouter()
+			router.Use(a.Authorize)
+			router.Handle("/api/v0/work", ok)
+			router.Handle("/api/v0/management_clusters/{id}", ok)
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			ctx := context.WithValue(req.Context(), ContextKeyAccountID, "123456789012")
+			ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::123456789012:role/automation")
+			ctx = context.WithValue(ctx, ContextKeyPrivileged, tt.privileged)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req.WithContext(ctx))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedCode != "" {
+				var resp map[string]interface{}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp["code"] != tt.expectedCode {
+					t.Errorf("expected code %q, got %v", tt.expectedCode, resp["code"])
+				}
+			}
+		})
+	}
+}
+
+func TestAuthz_ResourceParents(t *testing.T) {
+	a := NewAuthz(&mockChecker{}, true, "us-east-1", slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	tests := []struct {
+		name string
+		path string
+		vars map[string]string
+		want []string
+	}{
+		{
+			name: "top-level resource",
+			path: "/api/v0/clusters/c1",
+			vars: map[string]string{"id": "c1"},
+		},
+		{
+			name: "nodepool of a cluster",
+			path: "/api/v0/clusters/c1/nodepools/np1",
+			vars: map[string]string{"cluster_id": "c1", "id": "np1"},
+			want: []string{"arn:aws:rosa:us-east-1:123456789012:cluster/c1"},
+		},
+		{
+			name: "machine of a nodepool",
+			path: "/api/v0/clusters/c1/nodepools/np1/machines/m1",
+			vars: map[string]string{"cluster_id": "c1", "nodepool_id": "np1", "id": "m1"},
+			want: []string{
+				"arn:aws:rosa:us-east-1:123456789012:cluster/c1",
+				"arn:aws:rosa:us-east-1:123456789012:nodepool/np1",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req = mux.SetURLVars(req, tt.vars)
+
+			got := a.buildAuthzRequest(req, "123456789012", "arn:aws:iam::123456789012:user/alice")
+			if strings.Join(got.ResourceParents, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("ResourceParents = %v, want %v", got.ResourceParents, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthz_DeriveAction(t *testing.T) {
+	a := NewAuthz(&mockChecker{}, true, "us-east-1", slog.New(slog.NewTextHandler(os.Stdout, nil)))
+
+	tests := []struct {
+		method string
+		path   string
+		vars   map[string]string
+		want   string
+	}{
+		{method: http.MethodGet, path: "/api/v0/clusters", want: schema.ActionListClusters},
+		{method: http.MethodPost, path: "/api/v0/clusters", want: schema.ActionCreateCluster},
+		{method: http.MethodGet, path: "/api/v0/clusters/c1", vars: map[string]string{"id": "c1"}, want: schema.ActionDescribeCluster},
+		{method: http.MethodPatch, path: "/api/v0/clusters/c1", vars: map[string]string{"id": "c1"}, want: schema.ActionUpdateCluster},
+		{method: http.MethodGet, path: "/api/v0/nodepools", want: schema.ActionListNodePools},
+		{method: http.MethodDelete, path: "/api/v0/nodepools/np1", vars: map[string]string{"id": "np1"}, want: schema.ActionDeleteNodePool},
+		{method: http.MethodGet, path: "/api/v0/clusters/c1/access_entries", want: schema.ActionListAccessEntries},
+		// Resources the schema has no actions for
+		{method: http.MethodPost, path: "/api/v0/work", want: "CreateResource"},
+		{method: http.MethodGet, path: "/api/v0/work", want: "ListResources"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req := mux.SetURLVars(httptest.NewRequest(tt.method, tt.path, nil), tt.vars)
+			got := a.deriveAction(req)
+			if got != tt.want {
+				t.Errorf("deriveAction() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	for resourceType, actions := range resourceActions {
+		for verb, action := range actions {
+			if !schema.IsAction(action) {
+				t.Errorf("%s %s derives %q, which the schema does not declare", verb, resourceType, action)
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ContextKeyBasePath is the context key for the external base path of the API
+const ContextKeyBasePath contextKey = "base_path"
+
+// BasePath serves the API under the external base path clients reach it
+// at, such as an API Gateway stage (/prod), and records that path so
+// generated hrefs include it
+type BasePath struct {
+	prefix string
+}
+
+// NewBasePath creates a new BasePath middleware for prefix, which is
+// normalized to a leading slash and no trailing slash. An empty prefix
+// serves the API at the root.
+func NewBasePath(prefix string) *BasePath {
+	prefix = strings.TrimRight(prefix, "/")
+	if prefix != "" && !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	return &BasePath{prefix: prefix}
+}
+
+// Strip removes the base path from request paths before routing. Requests
+// without it are routed as they are, so gateways that strip the stage and
+// in-cluster probes keep working. This middleware must wrap the router,
+// since routes are matched before router middleware runs.
+func (b *BasePath) Strip(next http.Handler) http.Handler {
+	if b.prefix == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path, ok := strings.CutPrefix(r.URL.Path, b.prefix); ok && (path == "" || strings.HasPrefix(path, "/")) {
+			r2 := r.Clone(r.Context())
+			r2.URL.Path = path
+			if r2.URL.Path == "" {
+				r2.URL.Path = "/"
+			}
+			r2.URL.RawPath = strings.TrimPrefix(r2.URL.RawPath, b.prefix)
+			r = r2
+		}
+		ctx := context.WithValue(r.Context(), ContextKeyBasePath, b.prefix)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetBasePath returns the external base path of the API from the request
+// context, or "" when the API is served at the root
+func GetBasePath(ctx context.Context) string {
+	v, _ := ctx.Value(ContextKeyBasePath).(string)
+	return v
+}
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBasePath_Strip(t *testing.T) {
+	tests := []struct {
+		name     string
+		prefix   string
+		path     string
+		wantPath string
+		wantBase string
+	}{
+		{name: "prefixed", prefix: "/prod", path: "/prod/api/v0/work", wantPath: "/api/v0/work", wantBase: "/prod"},
+		{name: "prefix only", prefix: "/prod", path: "/prod", wantPath: "/", wantBase: "/prod"},
+		{name: "unprefixed", prefix: "/prod", path: "/api/v0/work", wantPath: "/api/v0/work", wantBase: "/prod"},
+		{name: "prefix of a segment", prefix: "/prod", path: "/production/api/v0/work", wantPath: "/production/api/v0/work", wantBase: "/prod"},
+		{name: "normalized", prefix: "stage/v1/", path: "/stage/v1/api/v0/live", wantPath: "/api/v0/live", wantBase: "/stage/v1"},
+		{name: "root", path: "/api/v0/work", wantPath: "/api/v0/work"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotBase string
+			handler := NewBasePath(tt.prefix).Strip(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				gotBase = GetBasePath(r.Context())
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if gotPath != tt.wantPath || gotBase != tt.wantBase {
+				t.Errorf("expected path %q and base path %q, got %q and %q", tt.wantPath, tt.wantBase, gotPath, gotBase)
+			}
+		})
+	}
+}
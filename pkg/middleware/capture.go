@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/capture"
+)
+
+// CaptureWriter stores captured request envelopes
+type CaptureWriter interface {
+	Write(env capture.Envelope) error
+}
+
+// Capture records an envelope of each request for later replay. Only
+// requests from the listed accounts are captured when any are listed, and
+// request bodies are kept only for the accounts that opted in; other bodies
+// are recorded as a hash.
+type Capture struct {
+	writer       CaptureWriter
+	accounts     map[string]bool
+	bodyAccounts map[string]bool
+	logger       *slog.Logger
+}
+
+// NewCapture creates a new Capture middleware
+func NewCapture(writer CaptureWriter, accounts, bodyAccounts []string, logger *slog.Logger) *Capture {
+	return &Capture{
+		writer:       writer,
+		accounts:     toSet(accounts),
+		bodyAccounts: toSet(bodyAccounts),
+		logger:       logger,
+	}
+}
+
+// Record captures the request once it has been answered. A failure to
+// capture is logged and never fails the request.
+// This middleware should run after Identity middleware.
+func (c *Capture) Record(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		accountID := GetAccountID(ctx)
+		if len(c.accounts) > 0 && !c.accounts[accountID] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = io.ReadAll(r.Body)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid-request-body", "Failed to read request body")
+				return
+			}
+			_ = r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		env := capture.NewEnvelope(r, body, accountID != "" && c.bodyAccounts[accountID])
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		env.RequestID = GetRequestID(ctx)
+		env.AccountID = accountID
+		env.CallerARN = GetCallerARN(ctx)
+		env.UserID = GetUserID(ctx)
+		env.Route = routeTemplate(r)
+		env.Status = rec.status
+		if err := c.writer.Write(env); err != nil {
+			c.logger.Warn("failed to capture request", "error", err, "request_id", env.RequestID)
+		}
+	})
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[v] = true
+	}
+	return set
+}
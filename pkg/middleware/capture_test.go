@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/capture"
+)
+
+type fakeCaptureWriter struct {
+	envs []capture.Envelope
+}
+
+func (f *fakeCaptureWriter) Write(env capture.Envelope) error {
+	f.envs = append(f.envs, env)
+	return nil
+}
+
+func TestCapture_Record(t *testing.T) {
+	tests := []struct {
+		name         string
+		accounts     []string
+		bodyAccounts []string
+		accountID    string
+		expectCount  int
+		expectBody   bool
+	}{
+		{
+			name:        "captures every account by default",
+			accountID:   "123456789012",
+			expectCount: 1,
+		},
+		{
+			name:         "keeps bodies of opted-in accounts",
+			bodyAccounts: []string{"123456789012"},
+			accountID:    "123456789012",
+			expectCount:  1,
+			expectBody:   true,
+		},
+		{
+			name:        "skips accounts not listed",
+			accounts:    []string{"210987654321"},
+			accountID:   "123456789012",
+			expectCount: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writer := &fakeCaptureWriter{}
+			var gotBody string
+			router := mux.NewRouter()
+			router.Use(NewCapture(writer, tt.accounts, tt.bodyAccounts, slog.Default()).Record)
+			router.HandleFunc("/api/v0/clusters/{id}", func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+				w.WriteHeader(http.StatusConflict)
+			}).Methods(http.MethodPut)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/v0/clusters/c1", strings.NewReader(`{"spec":{}}`))
+			req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, tt.accountID))
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotBody != `{"spec":{}}` {
+				t.Errorf("expected the handler to read the body, got %q", gotBody)
+			}
+			if len(writer.envs) != tt.expectCount {
+				t.Fatalf("expected %d envelopes, got %d", tt.expectCount, len(writer.envs))
+			}
+			if tt.expectCount == 0 {
+				return
+			}
+			env := writer.envs[0]
+			if env.Route != "/api/v0/clusters/{id}" || env.Status != http.StatusConflict || env.AccountID != tt.accountID {
+				t.Errorf("unexpected envelope %+v", env)
+			}
+			if (env.Body != nil) != tt.expectBody {
+				t.Errorf("expected body kept %v, got %q", tt.expectBody, env.Body)
+			}
+		})
+	}
+}
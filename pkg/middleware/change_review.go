@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// maxStagedBodyBytes caps the body of a request staged for review
+const maxStagedBodyBytes = 1 << 20
+
+// ChangeStager stages mutations of accounts in change review mode
+type ChangeStager interface {
+	ChangeReviewEnabled(ctx context.Context, accountID string) (bool, error)
+	StageChangeRequest(ctx context.Context, cr *store.ChangeRequest) error
+}
+
+type changeApprovedKey struct{}
+
+// WithChangeApproved marks a request as the replay of an approved change
+// request, which ChangeReview lets through
+func WithChangeApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, changeApprovedKey{}, true)
+}
+
+func changeApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(changeApprovedKey{}).(bool)
+	return approved
+}
+
+// ChangeReview provides middleware staging the mutations of accounts in
+// change review mode
+type ChangeReview struct {
+	stager ChangeStager
+	exempt []string
+	logger *slog.Logger
+}
+
+// NewChangeReview creates a new ChangeReview middleware. Requests whose path
+// starts with one of the exempt prefixes, such as the review endpoints
+// themselves, are never staged.
+func NewChangeReview(stager ChangeStager, logger *slog.Logger, exempt ...string) *ChangeReview {
+	return &ChangeReview{
+		stager: stager,
+		exempt: exempt,
+		logger: logger,
+	}
+}
+
+// Stage records POST, PUT, PATCH and DELETE requests of accounts in change
+// review mode as change requests and answers 202 Accepted with the change
+// request instead of passing them on. Approving the change request replays
+// the request.
+func (c *ChangeReview) Stage(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		if !c.stageable(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		accountID := GetAccountID(ctx)
+		enabled, err := c.stager.ChangeReviewEnabled(ctx, accountID)
+		if err != nil {
+			c.logger.Error("failed to check change review mode", "error", err, "account_id", accountID)
+			if authzTimedOut(err) {
+				c.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+				return
+			}
+			c.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check change review mode")
+			return
+		}
+		if !enabled {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxStagedBodyBytes+1))
+		if err != nil {
+			c.writeError(w, http.StatusBadRequest, "invalid-request", "Failed to read request body")
+			return
+		}
+		if len(body) > maxStagedBodyBytes {
+			c.writeError(w, http.StatusRequestEntityTooLarge, "request-too-large", "Request body is too large to stage for review")
+			return
+		}
+
+		cr := &store.ChangeRequest{
+			AccountID:   accountID,
+			Method:      r.Method,
+			Path:        r.URL.RequestURI(),
+			Body:        string(body),
+			RequestedBy: GetCallerARN(ctx),
+		}
+		if err := c.stager.StageChangeRequest(ctx, cr); err != nil {
+			c.logger.Error("failed to stage change request", "error", err, "account_id", accountID)
+			c.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to stage change request")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(struct {
+			Kind string `json:"kind"`
+			*store.ChangeRequest
+		}{"ChangeRequest", cr})
+	})
+}
+
+// stageable reports whether r is a mutation that change review applies to
+func (c *ChangeReview) stageable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return false
+	}
+	// Dry runs change nothing, so there is nothing to review
+	if changeApproved(r.Context()) || IsDryRun(r.Context()) {
+		return false
+	}
+	for _, prefix := range c.exempt {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *ChangeReview) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
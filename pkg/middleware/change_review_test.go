@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// fakeStager reviews changes for one account and keeps what it staged
+type fakeStager struct {
+	reviewed string
+	staged   []*store.ChangeRequest
+}
+
+func (f *fakeStager) ChangeReviewEnabled(ctx context.Context, accountID string) (bool, error) {
+	return accountID == f.reviewed, nil
+}
+
+func (f *fakeStager) StageChangeRequest(ctx context.Context, cr *store.ChangeRequest) error {
+	cr.ChangeID = "change-1"
+	cr.Status = store.ChangeRequestPending
+	f.staged = append(f.staged, cr)
+	return nil
+}
+
+func TestChangeReview_Stage(t *testing.T) {
+	const (
+		reviewed   = "123456789012"
+		unreviewed = "210987654321"
+		callerARN  = "arn:aws:iam::123456789012:user/alice"
+	)
+
+	tests := []struct {
+		name      string
+		method    string
+		target    string
+		accountID string
+		approved  bool
+		dryRun    bool
+		wantStage bool
+	}{
+		{name: "mutation of a reviewed account", method: http.MethodDelete, target: "/api/v0/authz/policies/p1?wait=true", accountID: reviewed, wantStage: true},
+		{name: "read of a reviewed account", method: http.MethodGet, target: "/api/v0/authz/policies", accountID: reviewed},
+		{name: "mutation of an unreviewed account", method: http.MethodPost, target: "/api/v0/authz/policies", accountID: unreviewed},
+		{name: "review endpoint", method: http.MethodPost, target: "/api/v0/authz/changes/change-1/approve", accountID: reviewed},
+		{name: "replay of an approved change", method: http.MethodDelete, target: "/api/v0/authz/policies/p1", accountID: reviewed, approved: true},
+		{name: "dry run", method: http.MethodDelete, target: "/api/v0/authz/policies/p1", accountID: reviewed, dryRun: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stager := &fakeStager{reviewed: reviewed}
+			cr := NewChangeReview(stager, slog.New(slog.NewTextHandler(io.Discard, nil)), "/api/v0/authz/changes")
+
+			served := false
+			handler := cr.Stage(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served = true
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(`{"name":"p"}`))
+			ctx := context.WithValue(req.Context(), ContextKeyAccountID, tt.accountID)
+			ctx = context.WithValue(ctx, ContextKeyCallerARN, callerARN)
+			if tt.approved {
+				ctx = WithChangeApproved(ctx)
+			}
+			if tt.dryRun {
+				ctx = WithDryRun(ctx)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req.WithContext(ctx))
+
+			if !tt.wantStage {
+				if !served || len(stager.staged) != 0 {
+					t.Fatalf("expected request to pass through, served=%v staged=%d", served, len(stager.staged))
+				}
+				return
+			}
+
+			if served {
+				t.Fatal("staged request reached the handler")
+			}
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("expected 202, got %d", rec.Code)
+			}
+			if len(stager.staged) != 1 {
+				t.Fatalf("expected one staged change request, got %d", len(stager.staged))
+			}
+			staged := stager.staged[0]
+			if staged.Method != tt.method || staged.Path != tt.target || staged.Body != `{"name":"p"}` || staged.RequestedBy != callerARN {
+				t.Errorf("unexpected change request %+v", staged)
+			}
+
+			var body map[string]any
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body["kind"] != "ChangeRequest" || body["changeId"] != "change-1" || body["status"] != store.ChangeRequestPending {
+				t.Errorf("unexpected response %v", body)
+			}
+		})
+	}
+}
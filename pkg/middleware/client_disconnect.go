@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// StatusClientClosedRequest is recorded for requests whose client went away
+// before the response was written. The client never sees it; it keeps
+// abandoned requests out of the 5xx error rate.
+const StatusClientClosedRequest = 499
+
+var canceledRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_canceled_requests_total",
+	Help: "API requests abandoned by the client before the response was written.",
+}, []string{"method"})
+
+// ClientDisconnect records requests whose client disconnected mid-request.
+// Handlers and upstream clients stop work through the canceled request
+// context; this middleware turns the error response they then write into a
+// 499, counts the request and logs it at info level.
+type ClientDisconnect struct {
+	logger *slog.Logger
+}
+
+// NewClientDisconnect creates a new ClientDisconnect middleware
+func NewClientDisconnect(logger *slog.Logger) *ClientDisconnect {
+	return &ClientDisconnect{logger: logger}
+}
+
+// Track watches each request for its client going away
+func (d *ClientDisconnect) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		dw := &disconnectWriter{ResponseWriter: w, ctx: r.Context()}
+
+		next.ServeHTTP(dw, r)
+
+		if !dw.gone && !clientGone(r.Context()) {
+			return
+		}
+		canceledRequests.WithLabelValues(r.Method).Inc()
+		d.logger.Info("client disconnected",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"request_id", GetRequestID(r.Context()),
+		)
+	})
+}
+
+// clientGone reports whether ctx was canceled rather than timed out, which
+// for a request context means the client disconnected
+func clientGone(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// disconnectWriter replaces the status of responses written after the
+// client went away with StatusClientClosedRequest
+type disconnectWriter struct {
+	http.ResponseWriter
+	ctx  context.Context
+	gone bool
+}
+
+func (w *disconnectWriter) WriteHeader(status int) {
+	if clientGone(w.ctx) {
+		w.gone = true
+		status = StatusClientClosedRequest
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap returns the wrapped writer, for http.ResponseController
+func (w *disconnectWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewCanceledLogHandler wraps next so that warnings and errors caused only
+// by a canceled context, such as an upstream call abandoned because the
+// client disconnected, are logged at info level and flagged with canceled.
+// It belongs outside any handler that reports errors elsewhere.
+func NewCanceledLogHandler(next slog.Handler) slog.Handler {
+	return &canceledLogHandler{next: next}
+}
+
+type canceledLogHandler struct {
+	next slog.Handler
+}
+
+func (h *canceledLogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *canceledLogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if record.Level < slog.LevelWarn || !canceledRecord(record) {
+		return h.next.Handle(ctx, record)
+	}
+	if !h.next.Enabled(ctx, slog.LevelInfo) {
+		return nil
+	}
+	demoted := slog.NewRecord(record.Time, slog.LevelInfo, record.Message, record.PC)
+	record.Attrs(func(a slog.Attr) bool {
+		demoted.AddAttrs(a)
+		return true
+	})
+	demoted.AddAttrs(slog.Bool("canceled", true))
+	return h.next.Handle(ctx, demoted)
+}
+
+func (h *canceledLogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &canceledLogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *canceledLogHandler) WithGroup(name string) slog.Handler {
+	return &canceledLogHandler{next: h.next.WithGroup(name)}
+}
+
+// canceledRecord reports whether an error attribute of record wraps
+// context.Canceled
+func canceledRecord(record slog.Record) bool {
+	canceled := false
+	record.Attrs(func(a slog.Attr) bool {
+		if err, ok := a.Value.Any().(error); ok && errors.Is(err, context.Canceled) {
+			canceled = true
+			return false
+		}
+		return true
+	})
+	return canceled
+}
@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestClientDisconnect_Track(t *testing.T) {
+	tests := []struct {
+		name         string
+		ctx          func() (context.Context, context.CancelFunc)
+		expectStatus int
+		expectLogged bool
+	}{
+		{
+			name:         "client still connected",
+			ctx:          func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+			expectStatus: http.StatusInternalServerError,
+		},
+		{
+			name: "client disconnected",
+			ctx: func() (context.Context, context.CancelFunc) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				return ctx, cancel
+			},
+			expectStatus: StatusClientClosedRequest,
+			expectLogged: true,
+		},
+		{
+			name: "request timed out",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			},
+			expectStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			mw := NewClientDisconnect(slog.New(slog.NewJSONHandler(&buf, nil)))
+			before := testutil.ToFloat64(canceledRequests.WithLabelValues(http.MethodPost))
+
+			ctx, cancel := tt.ctx()
+			defer cancel()
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/work", nil).WithContext(ctx)
+			w := httptest.NewRecorder()
+			mw.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})).ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+			counted := testutil.ToFloat64(canceledRequests.WithLabelValues(http.MethodPost)) - before
+			if tt.expectLogged {
+				if counted != 1 {
+					t.Errorf("expected the request to be counted once, got %v", counted)
+				}
+				if !bytes.Contains(buf.Bytes(), []byte(`"msg":"client disconnected"`)) {
+					t.Errorf("expected a disconnect log entry, got %s", buf.String())
+				}
+				return
+			}
+			if counted != 0 {
+				t.Errorf("expected the request not to be counted, got %v", counted)
+			}
+			if buf.Len() != 0 {
+				t.Errorf("expected no log output, got %s", buf.String())
+			}
+		})
+	}
+}
+
+func TestClientDisconnect_Track_Flush(t *testing.T) {
+	d := NewClientDisconnect(slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil)))
+	handler := d.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("expected Flush to reach the recorder, got %v", err)
+		}
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v0/audit", nil))
+
+	if !rec.Flushed {
+		t.Error("expected the response to be flushed")
+	}
+}
+
+func TestCanceledLogHandler(t *testing.T) {
+	tests := []struct {
+		name           string
+		level          slog.Level
+		err            error
+		expectLevel    string
+		expectCanceled bool
+	}{
+		{name: "canceled error", level: slog.LevelError, err: fmt.Errorf("failed to send request: %w", context.Canceled), expectLevel: "INFO", expectCanceled: true},
+		{name: "canceled warning", level: slog.LevelWarn, err: context.Canceled, expectLevel: "INFO", expectCanceled: true},
+		{name: "other error", level: slog.LevelError, err: fmt.Errorf("connection refused"), expectLevel: "ERROR"},
+		{name: "deadline exceeded", level: slog.LevelError, err: context.DeadlineExceeded, expectLevel: "ERROR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(NewCanceledLogHandler(slog.NewJSONHandler(&buf, nil))).With("component", "test")
+
+			logger.Log(context.Background(), tt.level, "failed to list clusters", "error", tt.err)
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("failed to parse log output %q: %v", buf.String(), err)
+			}
+			if entry["level"] != tt.expectLevel {
+				t.Errorf("expected level %s, got %v", tt.expectLevel, entry["level"])
+			}
+			if entry["component"] != "test" {
+				t.Errorf("expected attributes to be kept, got %v", entry)
+			}
+			if canceled, _ := entry["canceled"].(bool); canceled != tt.expectCanceled {
+				t.Errorf("expected canceled=%v, got %v", tt.expectCanceled, entry["canceled"])
+			}
+		})
+	}
+}
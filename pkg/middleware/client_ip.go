@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ContextKeyClientIP is the context key for the resolved client IP
+const ContextKeyClientIP contextKey = "client_ip"
+
+// Forwarding headers set by load balancers and ingress proxies
+const (
+	HeaderForwardedFor = "X-Forwarded-For"
+	HeaderRealIP       = "X-Real-Ip"
+)
+
+// ClientIP resolves the real client IP of requests arriving through proxies
+type ClientIP struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewClientIP creates a new ClientIP middleware. Forwarding headers are only
+// honoured when the peer is one of trustedProxies.
+func NewClientIP(trustedProxies []*net.IPNet) *ClientIP {
+	return &ClientIP{trustedProxies: trustedProxies}
+}
+
+// Resolve adds the client IP to the request context. The source IP asserted
+// by API Gateway wins; otherwise X-Forwarded-For is walked from the right,
+// skipping trusted proxies, and the first untrusted address is the client.
+// X-Real-Ip is used when a trusted peer sends no X-Forwarded-For.
+// This middleware should run after Identity middleware.
+func (c *ClientIP) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ContextKeyClientIP, c.clientIP(r))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (c *ClientIP) clientIP(r *http.Request) string {
+	if v, ok := r.Context().Value(ContextKeySourceIP).(string); ok && net.ParseIP(v) != nil {
+		return v
+	}
+
+	peer := remoteIP(r)
+	if peer == nil {
+		return ""
+	}
+	if !containsIP(c.trustedProxies, peer) {
+		return peer.String()
+	}
+
+	if values := r.Header.Values(HeaderForwardedFor); len(values) > 0 {
+		hops := strings.Split(strings.Join(values, ","), ",")
+		client := peer
+		for i := len(hops) - 1; i >= 0; i-- {
+			ip := net.ParseIP(strings.TrimSpace(hops[i]))
+			if ip == nil {
+				// A malformed hop could have been written by anyone; stop at
+				// the last address we can vouch for
+				break
+			}
+			client = ip
+			if !containsIP(c.trustedProxies, ip) {
+				break
+			}
+		}
+		return client.String()
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get(HeaderRealIP))); ip != nil {
+		return ip.String()
+	}
+	return peer.String()
+}
+
+// remoteIP returns the IP of the connection peer
+func remoteIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// GetClientIP retrieves the resolved client IP from context
+func GetClientIP(ctx context.Context) string {
+	if v := ctx.Value(ContextKeyClientIP); v != nil {
+		return v.(string)
+	}
+	return ""
+}
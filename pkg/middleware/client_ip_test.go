@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP_Resolve(t *testing.T) {
+	proxies, err := ParseCIDRs([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		headers      map[string][]string
+		gatewayIP    string
+		expectClient string
+	}{
+		{
+			name:         "direct connection",
+			remoteAddr:   "203.0.113.5:443",
+			expectClient: "203.0.113.5",
+		},
+		{
+			name:         "forwarding headers from untrusted peer are ignored",
+			remoteAddr:   "203.0.113.5:443",
+			headers:      map[string][]string{HeaderForwardedFor: {"198.51.100.1"}},
+			expectClient: "203.0.113.5",
+		},
+		{
+			name:         "single trusted proxy",
+			remoteAddr:   "10.1.2.3:443",
+			headers:      map[string][]string{HeaderForwardedFor: {"198.51.100.1"}},
+			expectClient: "198.51.100.1",
+		},
+		{
+			name:         "spoofed leftmost entry is skipped",
+			remoteAddr:   "10.1.2.3:443",
+			headers:      map[string][]string{HeaderForwardedFor: {"1.2.3.4, 198.51.100.1, 10.9.9.9"}},
+			expectClient: "198.51.100.1",
+		},
+		{
+			name:         "multiple header lines",
+			remoteAddr:   "10.1.2.3:443",
+			headers:      map[string][]string{HeaderForwardedFor: {"198.51.100.1", "10.9.9.9"}},
+			expectClient: "198.51.100.1",
+		},
+		{
+			name:         "malformed hop stops the walk",
+			remoteAddr:   "10.1.2.3:443",
+			headers:      map[string][]string{HeaderForwardedFor: {"198.51.100.1, garbage, 10.9.9.9"}},
+			expectClient: "10.9.9.9",
+		},
+		{
+			name:         "X-Real-Ip from trusted proxy",
+			remoteAddr:   "10.1.2.3:443",
+			headers:      map[string][]string{HeaderRealIP: {"198.51.100.7"}},
+			expectClient: "198.51.100.7",
+		},
+		{
+			name:         "API Gateway source IP wins",
+			remoteAddr:   "10.1.2.3:443",
+			headers:      map[string][]string{HeaderForwardedFor: {"198.51.100.1"}},
+			gatewayIP:    "192.0.2.10",
+			expectClient: "192.0.2.10",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := NewClientIP(proxies).Resolve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = GetClientIP(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for name, values := range tt.headers {
+				for _, v := range values {
+					req.Header.Add(name, v)
+				}
+			}
+			if tt.gatewayIP != "" {
+				req = req.WithContext(context.WithValue(req.Context(), ContextKeySourceIP, tt.gatewayIP))
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.expectClient {
+				t.Errorf("expected client IP %s, got %s", tt.expectClient, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// UploadMediaTypes are the request body types accepted besides JSON on
+// routes that take file uploads
+var UploadMediaTypes = []string{"application/yaml", "application/x-yaml", "text/yaml", "multipart/form-data"}
+
+// ContentNegotiator rejects request bodies and Accept headers the API cannot
+// handle
+type ContentNegotiator struct {
+	uploadPaths map[string]bool
+}
+
+// NewContentNegotiation creates a new ContentNegotiator. POST requests to
+// uploadPaths may also send a body of one of UploadMediaTypes.
+func NewContentNegotiation(uploadPaths ...string) *ContentNegotiator {
+	c := &ContentNegotiator{uploadPaths: make(map[string]bool, len(uploadPaths))}
+	for _, path := range uploadPaths {
+		c.uploadPaths[path] = true
+	}
+	return c
+}
+
+// ContentNegotiation rejects mutating requests whose body is not JSON (415)
+// and requests whose Accept header excludes JSON (406). Requests without a
+// body or an Accept header are let through.
+func ContentNegotiation(next http.Handler) http.Handler {
+	return NewContentNegotiation().Negotiate(next)
+}
+
+// Negotiate rejects mutating requests whose body is not JSON, or an upload
+// on an upload path (415), and requests whose Accept header excludes JSON
+// (406). Requests without a body or an Accept header are let through.
+func (c *ContentNegotiator) Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hasBody(r) && !isJSONContentType(r.Header.Get("Content-Type")) {
+			if !c.uploadPaths[r.URL.Path] || r.Method != http.MethodPost {
+				writeJSONError(w, http.StatusUnsupportedMediaType, "unsupported-media-type",
+					"Content-Type must be application/json")
+				return
+			}
+			if !isUploadContentType(r.Header.Get("Content-Type")) {
+				writeJSONError(w, http.StatusUnsupportedMediaType, "unsupported-media-type",
+					"Content-Type must be application/json, application/yaml or multipart/form-data")
+				return
+			}
+		}
+
+		if accept := r.Header.Values("Accept"); len(accept) > 0 && !acceptsJSON(strings.Join(accept, ",")) {
+			writeJSONError(w, http.StatusNotAcceptable, "not-acceptable",
+				"Responses are only available as application/json")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hasBody reports whether r is a mutating request that carries a body
+func hasBody(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+	default:
+		return false
+	}
+	return r.ContentLength > 0 || (r.ContentLength < 0 && r.Body != nil && r.Body != http.NoBody)
+}
+
+func isJSONContentType(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || (strings.HasPrefix(mediaType, "application/") && strings.HasSuffix(mediaType, "+json"))
+}
+
+func isUploadContentType(value string) bool {
+	mediaType, _, err := mime.ParseMediaType(value)
+	if err != nil {
+		return false
+	}
+	for _, upload := range UploadMediaTypes {
+		if mediaType == upload {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsJSON reports whether an Accept header value admits application/json
+func acceptsJSON(accept string) bool {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		switch mediaType {
+		case "application/json", "application/*", "*/*":
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContentNegotiation(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		body         string
+		contentType  string
+		accept       string
+		expectStatus int
+	}{
+		{name: "JSON body", method: http.MethodPost, body: `{}`, contentType: "application/json", expectStatus: http.StatusOK},
+		{name: "JSON body with charset", method: http.MethodPut, body: `{}`, contentType: "application/json; charset=utf-8", expectStatus: http.StatusOK},
+		{name: "structured JSON suffix", method: http.MethodPatch, body: `{}`, contentType: "application/merge-patch+json", expectStatus: http.StatusOK},
+		{name: "missing content type", method: http.MethodPost, body: `{}`, expectStatus: http.StatusUnsupportedMediaType},
+		{name: "form body", method: http.MethodPost, body: `a=b`, contentType: "application/x-www-form-urlencoded", expectStatus: http.StatusUnsupportedMediaType},
+		{name: "empty POST", method: http.MethodPost, expectStatus: http.StatusOK},
+		{name: "GET ignores content type", method: http.MethodGet, contentType: "text/plain", expectStatus: http.StatusOK},
+		{name: "accept JSON", method: http.MethodGet, accept: "application/json", expectStatus: http.StatusOK},
+		{name: "accept wildcard", method: http.MethodGet, accept: "text/html, */*;q=0.8", expectStatus: http.StatusOK},
+		{name: "accept only HTML", method: http.MethodGet, accept: "text/html", expectStatus: http.StatusNotAcceptable},
+		{name: "accept JSON refused", method: http.MethodGet, accept: "application/json;q=0, text/html", expectStatus: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := ContentNegotiation(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var req *http.Request
+			if tt.body != "" {
+				req = httptest.NewRequest(tt.method, "/test", strings.NewReader(tt.body))
+			} else {
+				req = httptest.NewRequest(tt.method, "/test", nil)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestContentNegotiator_Uploads(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		contentType  string
+		expectStatus int
+	}{
+		{name: "YAML upload", method: http.MethodPost, path: "/api/v0/work", contentType: "application/yaml", expectStatus: http.StatusOK},
+		{name: "multipart upload", method: http.MethodPost, path: "/api/v0/work", contentType: "multipart/form-data; boundary=x", expectStatus: http.StatusOK},
+		{name: "JSON on an upload path", method: http.MethodPost, path: "/api/v0/work", contentType: "application/json", expectStatus: http.StatusOK},
+		{name: "other type on an upload path", method: http.MethodPost, path: "/api/v0/work", contentType: "text/plain", expectStatus: http.StatusUnsupportedMediaType},
+		{name: "YAML elsewhere", method: http.MethodPost, path: "/api/v0/clusters", contentType: "application/yaml", expectStatus: http.StatusUnsupportedMediaType},
+		{name: "YAML PUT on an upload path", method: http.MethodPut, path: "/api/v0/work", contentType: "application/yaml", expectStatus: http.StatusUnsupportedMediaType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewContentNegotiation("/api/v0/work").Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader("kind: ConfigMap"))
+			req.Header.Set("Content-Type", tt.contentType)
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+		})
+	}
+}
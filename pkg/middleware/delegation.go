@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// HeaderTargetAccountID names the account a delegated caller acts on
+const HeaderTargetAccountID = "X-Rosa-Target-Account-Id"
+
+const (
+	// ContextKeyDelegateAccountID is the context key for the caller's own
+	// account on a delegated request
+	ContextKeyDelegateAccountID contextKey = "delegate_account_id"
+	// ContextKeyDelegation is the context key for the delegation in effect
+	ContextKeyDelegation contextKey = "delegation"
+)
+
+// Delegation lets principals of one account act on another account's
+// resources when the target account has delegated to the caller's account
+type Delegation struct {
+	resolver authz.DelegationResolver
+	logger   *slog.Logger
+}
+
+// NewDelegation creates a new Delegation middleware
+func NewDelegation(resolver authz.DelegationResolver, logger *slog.Logger) *Delegation {
+	return &Delegation{
+		resolver: resolver,
+		logger:   logger,
+	}
+}
+
+// Resolve switches the request to the account in HeaderTargetAccountID when
+// a live delegation from that account to the caller's account exists. The
+// caller ARN is kept, so the target account's policies decide each request,
+// and the delegation's actions are enforced by the Authz middleware. Every
+// delegated request is logged for audit. This middleware should run after
+// Identity and before Privileged: delegated requests never inherit the
+// target account's privileged status.
+func (d *Delegation) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target := r.Header.Get(HeaderTargetAccountID)
+		if target == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := r.Context()
+		accountID, ok := LookupAccountID(ctx)
+		if !ok {
+			d.writeError(w, http.StatusForbidden, "missing-account-id", "Account ID header is required")
+			return
+		}
+		if target == accountID {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !accountIDPattern.MatchString(target) {
+			d.writeError(w, http.StatusBadRequest, "invalid-target-account-id",
+				HeaderTargetAccountID+" must be a 12-digit AWS account ID")
+			return
+		}
+
+		callerARN := GetCallerARN(ctx)
+		delegation, err := d.resolver.GetDelegation(ctx, target, accountID)
+		if err != nil {
+			d.logger.Error("failed to get delegation", "error", err, "account_id", target, "delegate_account_id", accountID)
+			if authzTimedOut(err) {
+				d.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+				return
+			}
+			d.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check delegation")
+			return
+		}
+		if delegation == nil || delegation.Expired(time.Now()) {
+			d.logger.Warn("delegated request denied: no delegation",
+				"account_id", target,
+				"delegate_account_id", accountID,
+				"caller_arn", callerARN,
+				"method", r.Method,
+				"path", r.URL.Path,
+			)
+			d.writeError(w, http.StatusForbidden, "delegation-not-found",
+				"Account "+target+" has not delegated access to account "+accountID)
+			return
+		}
+
+		d.logger.Info("delegated request",
+			"account_id", target,
+			"delegate_account_id", accountID,
+			"caller_arn", callerARN,
+			"actions", delegation.Actions,
+			"method", r.Method,
+			"path", r.URL.Path,
+			"request_id", GetRequestID(ctx),
+		)
+
+		ctx = context.WithValue(ctx, ContextKeyAccountID, target)
+		ctx = context.WithValue(ctx, ContextKeyDelegateAccountID, accountID)
+		ctx = context.WithValue(ctx, ContextKeyDelegation, delegation)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func (d *Delegation) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// GetDelegation returns the delegation a request is acting under, or nil for
+// a request on the caller's own account
+func GetDelegation(ctx context.Context) *store.Delegation {
+	delegation, _ := ctx.Value(ContextKeyDelegation).(*store.Delegation)
+	return delegation
+}
+
+// LookupDelegateAccountID returns the caller's own account on a delegated
+// request and whether the request is delegated
+func LookupDelegateAccountID(ctx context.Context) (string, bool) {
+	return lookupString(ctx, ContextKeyDelegateAccountID)
+}
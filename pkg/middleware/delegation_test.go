@@ -0,0 +1,209 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// mockDelegationResolver implements authz.DelegationResolver for testing
+type mockDelegationResolver struct {
+	delegations map[string]*store.Delegation
+}
+
+func (m *mockDelegationResolver) GetDelegation(ctx context.Context, accountID, delegateAccountID string) (*store.Delegation, error) {
+	return m.delegations[accountID+"/"+delegateAccountID], nil
+}
+
+func delegatedRequest(target string) *http.Request {
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+	if target != "" {
+		req.Header.Set(HeaderTargetAccountID, target)
+	}
+	ctx := context.WithValue(req.Context(), ContextKeyAccountID, "111111111111")
+	ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::111111111111:role/operator")
+	return req.WithContext(ctx)
+}
+
+func TestDelegation_Resolve(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	resolver := &mockDelegationResolver{delegations: map[string]*store.Delegation{
+		"222222222222/111111111111": {
+			AccountID:         "222222222222",
+			DelegateAccountID: "111111111111",
+			Actions:           []string{"ListClusters"},
+		},
+		"333333333333/111111111111": {
+			AccountID:         "333333333333",
+			DelegateAccountID: "111111111111",
+			Actions:           []string{store.AllActions},
+			ExpiresAt:         time.Now().Add(-time.Hour).UTC().Format(time.RFC3339),
+		},
+	}}
+	d := NewDelegation(resolver, logger)
+
+	tests := []struct {
+		name            string
+		target          string
+		expectedStatus  int
+		expectedCode    string
+		expectedAccount string
+		expectDelegated bool
+	}{
+		{
+			name:            "no target header passes through",
+			expectedStatus:  http.StatusOK,
+			expectedAccount: "111111111111",
+		},
+		{
+			name:            "own account passes through",
+			target:          "111111111111",
+			expectedStatus:  http.StatusOK,
+			expectedAccount: "111111111111",
+		},
+		{
+			name:            "delegated account is switched in",
+			target:          "222222222222",
+			expectedStatus:  http.StatusOK,
+			expectedAccount: "222222222222",
+			expectDelegated: true,
+		},
+		{
+			name:           "missing delegation is rejected",
+			target:         "444444444444",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "delegation-not-found",
+		},
+		{
+			name:           "expired delegation is rejected",
+			target:         "333333333333",
+			expectedStatus: http.StatusForbidden,
+			expectedCode:   "delegation-not-found",
+		},
+		{
+			name:           "malformed target is rejected",
+			target:         "not-an-account",
+			expectedStatus: http.StatusBadRequest,
+			expectedCode:   "invalid-target-account-id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAccount string
+			var gotDelegated bool
+			handler := d.Resolve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAccount = GetAccountID(r.Context())
+				_, gotDelegated = LookupDelegateAccountID(r.Context())
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, delegatedRequest(tt.target))
+
+			if w.Code != tt.expectedStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if tt.expectedCode != "" {
+				var resp map[string]interface{}
+				if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if resp["code"] != tt.expectedCode {
+					t.Errorf("expected code %q, got %v", tt.expectedCode, resp["code"])
+				}
+				return
+			}
+			if gotAccount != tt.expectedAccount {
+				t.Errorf("expected account %q, got %q", tt.expectedAccount, gotAccount)
+			}
+			if gotDelegated != tt.expectDelegated {
+				t.Errorf("expected delegated=%v, got %v", tt.expectDelegated, gotDelegated)
+			}
+		})
+	}
+}
+
+func TestDelegation_PrivilegedNotInherited(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	resolver := &mockDelegationResolver{delegations: map[string]*store.Delegation{
+		"222222222222/111111111111": {
+			AccountID:         "222222222222",
+			DelegateAccountID: "111111111111",
+			Actions:           []string{store.AllActions},
+		},
+	}}
+	checker := &mockChecker{
+		isPrivilegedFn: func(ctx context.Context, accountID string) (bool, error) {
+			return true, nil
+		},
+	}
+
+	var privileged bool
+	handler := NewDelegation(resolver, logger).Resolve(
+		NewPrivileged(checker, logger).CheckPrivileged(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				privileged = GetPrivileged(r.Context())
+				w.WriteHeader(http.StatusOK)
+			})))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, delegatedRequest("222222222222"))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if privileged {
+		t.Error("expected delegated request not to inherit privileged status")
+	}
+}
+
+func TestDelegation_AuthorizeLimitsActions(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	resolver := &mockDelegationResolver{delegations: map[string]*store.Delegation{
+		"222222222222/111111111111": {
+			AccountID:         "222222222222",
+			DelegateAccountID: "111111111111",
+			Actions:           []string{"ListClusters"},
+		},
+	}}
+	checker := &mockChecker{
+		authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+			return req.AccountID == "222222222222", nil
+		},
+	}
+	handler := NewDelegation(resolver, logger).Resolve(
+		NewAuthz(checker, true, "us-east-1", logger).Authorize(
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			})))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, delegatedRequest("222222222222"))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected delegated action to be allowed, got %d", w.Code)
+	}
+
+	req := delegatedRequest("222222222222")
+	req.Method = http.MethodPost
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+	var resp map[string]interface{}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp["code"] != "delegation-action-denied" {
+		t.Errorf("expected code delegation-action-denied, got %v", resp["code"])
+	}
+}
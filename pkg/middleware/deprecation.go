@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Deprecation headers: Deprecation (RFC 9745) holds when the route was
+// deprecated, Sunset (RFC 8594) when it will stop being served
+const (
+	HeaderDeprecation = "Deprecation"
+	HeaderSunset      = "Sunset"
+	HeaderLink        = "Link"
+)
+
+// maxDeprecatedCallers bounds the account and route pairs Deprecation
+// remembers having logged
+const maxDeprecatedCallers = 10000
+
+var deprecatedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_deprecated_requests_total",
+	Help: "Requests served by deprecated routes, by route template and method.",
+}, []string{"route", "method"})
+
+// Deprecation marks the routes it wraps as deprecated. Their responses carry
+// Deprecation and, once one is planned, Sunset headers, plus Link headers to
+// the successor route and the migration notes, and their use is counted per
+// route. The first call from each account to each deprecated route is logged,
+// so the accounts still to migrate can be found.
+type Deprecation struct {
+	since     time.Time
+	sunset    time.Time
+	successor string
+	docs      string
+	logger    *slog.Logger
+
+	mu      sync.Mutex
+	callers map[string]bool
+}
+
+// NewDeprecation creates a Deprecation for routes deprecated since since. A
+// zero sunset sends no Sunset header.
+func NewDeprecation(since, sunset time.Time, logger *slog.Logger) *Deprecation {
+	return &Deprecation{since: since, sunset: sunset, logger: logger, callers: make(map[string]bool)}
+}
+
+// WithSuccessor links responses to the route that replaces the deprecated
+// one, such as /api/v1/clusters
+func (d *Deprecation) WithSuccessor(path string) *Deprecation {
+	d.successor = path
+	return d
+}
+
+// WithDocs links responses to the migration notes at url
+func (d *Deprecation) WithDocs(url string) *Deprecation {
+	d.docs = url
+	return d
+}
+
+// Wrap marks every response of next as deprecated. Use it on a single route
+// or, with Router.Use, on every route of a subrouter.
+func (d *Deprecation) Wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set(HeaderDeprecation, fmt.Sprintf("@%d", d.since.Unix()))
+		if !d.sunset.IsZero() {
+			h.Set(HeaderSunset, d.sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.successor != "" {
+			h.Add(HeaderLink, fmt.Sprintf("<%s>; rel=\"successor-version\"", successorURL(r, d.successor)))
+		}
+		if d.docs != "" {
+			h.Add(HeaderLink, fmt.Sprintf("<%s>; rel=\"deprecation\"; type=\"text/html\"", d.docs))
+		}
+
+		route := routeTemplate(r)
+		deprecatedRequests.WithLabelValues(route, r.Method).Inc()
+		if accountID := GetAccountID(r.Context()); accountID != "" && d.firstCall(accountID, route) {
+			d.logger.Info("deprecated route called", "route", route, "method", r.Method,
+				"account_id", accountID, "sunset", d.sunset, "request_id", GetRequestID(r.Context()))
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// firstCall reports whether accountID has not called route before, as far as
+// this replica remembers
+func (d *Deprecation) firstCall(accountID, route string) bool {
+	key := accountID + " " + route
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.callers[key] {
+		return false
+	}
+	// Start over rather than grow without bound; callers are logged again
+	if len(d.callers) >= maxDeprecatedCallers {
+		d.callers = make(map[string]bool)
+	}
+	d.callers[key] = true
+	return true
+}
+
+// successorURL returns path as clients reach it, like the hrefs in responses
+func successorURL(r *http.Request, path string) string {
+	if base := GetExternalURL(r.Context()); base != "" {
+		return base + path
+	}
+	return GetBasePath(r.Context()) + path
+}
+
+// routeTemplate returns the path template of the route r matched, such as
+// /api/v0/clusters/{id}, keeping the metric's label values bounded
+func routeTemplate(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil {
+		if tmpl, err := route.GetPathTemplate(); err == nil {
+			return tmpl
+		}
+	}
+	return "unknown"
+}
@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestDeprecation_Headers(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	dep := NewDeprecation(since, sunset, logger).
+		WithSuccessor("/api/v1/clusters/{id}").
+		WithDocs("https://docs.example.com/v1-migration")
+
+	router := mux.NewRouter()
+	router.Handle("/api/v0/clusters/{id}", dep.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))).Methods(http.MethodGet)
+	router.HandleFunc("/api/v1/clusters/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}).Methods(http.MethodGet)
+
+	counter := deprecatedRequests.WithLabelValues("/api/v0/clusters/{id}", http.MethodGet)
+	before := testutil.ToFloat64(counter)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters/c1", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, "123456789012"))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get(HeaderDeprecation); got != "@1767225600" {
+		t.Errorf("expected Deprecation @1767225600, got %q", got)
+	}
+	if got := rec.Header().Get(HeaderSunset); got != "Wed, 01 Jul 2026 00:00:00 GMT" {
+		t.Errorf("unexpected Sunset %q", got)
+	}
+	links := rec.Header().Values(HeaderLink)
+	if len(links) != 2 ||
+		links[0] != `</api/v1/clusters/{id}>; rel="successor-version"` ||
+		links[1] != `<https://docs.example.com/v1-migration>; rel="deprecation"; type="text/html"` {
+		t.Errorf("unexpected Link headers %q", links)
+	}
+	if got := testutil.ToFloat64(counter) - before; got != 1 {
+		t.Errorf("expected the deprecated route to be counted once, got %v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/clusters/c1", nil))
+	if rec.Header().Get(HeaderDeprecation) != "" {
+		t.Error("expected routes that are not deprecated to carry no Deprecation header")
+	}
+}
+
+func TestDeprecation_NoSunset(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	dep := NewDeprecation(time.Unix(1700000000, 0), time.Time{}, logger)
+
+	rec := httptest.NewRecorder()
+	dep.Wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})).
+		ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v0/old", nil))
+
+	if rec.Header().Get(HeaderDeprecation) != "@1700000000" {
+		t.Errorf("unexpected Deprecation %q", rec.Header().Get(HeaderDeprecation))
+	}
+	if rec.Header().Get(HeaderSunset) != "" || rec.Header().Get(HeaderLink) != "" {
+		t.Error("expected no Sunset or Link headers")
+	}
+}
+
+func TestDeprecation_FirstCallPerAccount(t *testing.T) {
+	dep := NewDeprecation(time.Now(), time.Time{}, slog.Default())
+
+	if !dep.firstCall("111111111111", "/api/v0/old") {
+		t.Error("expected the first call to be reported")
+	}
+	if dep.firstCall("111111111111", "/api/v0/old") {
+		t.Error("expected a repeated call not to be reported")
+	}
+	if !dep.firstCall("222222222222", "/api/v0/old") {
+		t.Error("expected another account's first call to be reported")
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Query parameters the dev identity injector reads identity from
+const (
+	DevQueryAccountID = "accountId"
+	DevQueryCallerARN = "callerArn"
+)
+
+// DevIdentity fabricates the identity headers API Gateway would send, so
+// developers can exercise authorization from a browser or plain curl. It
+// must only be enabled for local development: anyone who can reach the
+// server can claim any account.
+type DevIdentity struct {
+	cfg IdentityConfig
+}
+
+// NewDevIdentity creates a new DevIdentity middleware writing the headers
+// and shared secret the Identity middleware configured by cfg expects
+func NewDevIdentity(cfg IdentityConfig) *DevIdentity {
+	return &DevIdentity{cfg: cfg}
+}
+
+// Inject sets the account ID and caller ARN headers from basic auth (the
+// username is the account ID, the password the caller ARN) or from the
+// accountId and callerArn query parameters, which are removed from the
+// request. Headers already on the request win. The caller ARN defaults to a
+// dev user in the account. Browsers navigating without any identity are
+// challenged for basic auth.
+// This middleware should run before Identity middleware.
+func (d *DevIdentity) Inject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID, callerARN, ok := r.BasicAuth()
+		if ok {
+			r.Header.Del("Authorization")
+		} else {
+			accountID, callerARN = d.fromQuery(r)
+		}
+
+		if r.Header.Get(d.cfg.Headers.AccountID) == "" {
+			if accountID == "" {
+				if acceptsHTML(r) {
+					w.Header().Set("WWW-Authenticate", `Basic realm="rosa-regional-platform-api (dev)"`)
+					writeJSONError(w, http.StatusUnauthorized, "missing-account-id",
+						"Sign in with the account ID as username and, optionally, the caller ARN as password")
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			if callerARN == "" {
+				callerARN = "arn:aws:iam::" + accountID + ":user/dev"
+			}
+			r.Header.Set(d.cfg.Headers.AccountID, accountID)
+			if d.cfg.Headers.CallerARN != "" && r.Header.Get(d.cfg.Headers.CallerARN) == "" {
+				r.Header.Set(d.cfg.Headers.CallerARN, callerARN)
+			}
+		}
+		if secret := d.cfg.sharedSecret(); secret != "" {
+			r.Header.Set(d.cfg.SharedSecretHeader, secret)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// fromQuery reads and removes the identity query parameters
+func (d *DevIdentity) fromQuery(r *http.Request) (accountID, callerARN string) {
+	q := r.URL.Query()
+	accountID, callerARN = q.Get(DevQueryAccountID), q.Get(DevQueryCallerARN)
+	if !q.Has(DevQueryAccountID) && !q.Has(DevQueryCallerARN) {
+		return accountID, callerARN
+	}
+	q.Del(DevQueryAccountID)
+	q.Del(DevQueryCallerARN)
+	r.URL.RawQuery = q.Encode()
+	return accountID, callerARN
+}
+
+// acceptsHTML reports whether r looks like a browser navigation
+func acceptsHTML(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/html")
+}
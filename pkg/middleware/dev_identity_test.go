@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDevIdentity_Inject(t *testing.T) {
+	tests := []struct {
+		name         string
+		target       string
+		basicAuth    []string
+		headers      map[string]string
+		expectStatus int
+		expectAcct   string
+		expectARN    string
+		expectQuery  string
+	}{
+		{
+			name:         "basic auth with caller ARN",
+			target:       "/api/v0/clusters",
+			basicAuth:    []string{"123456789012", "arn:aws:iam::123456789012:role/admin"},
+			expectStatus: http.StatusOK,
+			expectAcct:   "123456789012",
+			expectARN:    "arn:aws:iam::123456789012:role/admin",
+		},
+		{
+			name:         "basic auth defaults the caller ARN",
+			target:       "/api/v0/clusters",
+			basicAuth:    []string{"123456789012", ""},
+			expectStatus: http.StatusOK,
+			expectAcct:   "123456789012",
+			expectARN:    "arn:aws:iam::123456789012:user/dev",
+		},
+		{
+			name:         "query parameters are consumed",
+			target:       "/api/v0/clusters?accountId=123456789012&callerArn=arn:aws:iam::123456789012:user/alice&limit=5",
+			expectStatus: http.StatusOK,
+			expectAcct:   "123456789012",
+			expectARN:    "arn:aws:iam::123456789012:user/alice",
+			expectQuery:  "limit=5",
+		},
+		{
+			name:         "headers win over basic auth",
+			target:       "/api/v0/clusters",
+			basicAuth:    []string{"123456789012", ""},
+			headers:      map[string]string{HeaderAccountID: "210987654321", HeaderCallerARN: "arn:aws:iam::210987654321:user/bob"},
+			expectStatus: http.StatusOK,
+			expectAcct:   "210987654321",
+			expectARN:    "arn:aws:iam::210987654321:user/bob",
+		},
+		{
+			name:         "API clients without identity pass through",
+			target:       "/api/v0/clusters",
+			expectStatus: http.StatusForbidden,
+		},
+		{
+			name:         "browsers without identity are challenged",
+			target:       "/api/v0/clusters",
+			headers:      map[string]string{"Accept": "text/html,application/xhtml+xml"},
+			expectStatus: http.StatusUnauthorized,
+		},
+		{
+			name:         "invalid account IDs are still rejected",
+			target:       "/api/v0/clusters?accountId=123",
+			expectStatus: http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultIdentityConfig()
+			var gotAcct, gotARN, gotQuery string
+			final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				accountID, ok := MustGetAccountID(w, r)
+				if !ok {
+					return
+				}
+				gotAcct, gotARN, gotQuery = accountID, GetCallerARN(r.Context()), r.URL.RawQuery
+				if r.Header.Get("Authorization") != "" {
+					t.Error("expected the Authorization header to be removed")
+				}
+			})
+			handler := NewDevIdentity(cfg).Inject(NewIdentity(cfg, slog.Default()).Extract(final))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.basicAuth != nil {
+				req.SetBasicAuth(tt.basicAuth[0], tt.basicAuth[1])
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.expectStatus, rr.Code, rr.Body.String())
+			}
+			if tt.expectStatus == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a basic auth challenge")
+			}
+			if gotAcct != tt.expectAcct {
+				t.Errorf("expected account ID %q, got %q", tt.expectAcct, gotAcct)
+			}
+			if gotARN != tt.expectARN {
+				t.Errorf("expected caller ARN %q, got %q", tt.expectARN, gotARN)
+			}
+			if gotQuery != tt.expectQuery {
+				t.Errorf("expected query %q, got %q", tt.expectQuery, gotQuery)
+			}
+		})
+	}
+}
+
+func TestDevIdentity_Inject_SharedSecret(t *testing.T) {
+	cfg := DefaultIdentityConfig()
+	cfg.SharedSecret = "s3cret"
+
+	var gotAcct string
+	final := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAcct = GetAccountID(r.Context())
+	})
+	handler := NewDevIdentity(cfg).Inject(NewIdentity(cfg, slog.Default()).Extract(final))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters?accountId=123456789012", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+	if gotAcct != "123456789012" {
+		t.Errorf("expected account ID 123456789012, got %q", gotAcct)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+)
+
+// HeaderDryRun requests a dry run like the dryRun query parameter, and is
+// echoed on responses to dry runs
+const HeaderDryRun = "X-Dry-Run"
+
+type dryRunKey struct{}
+
+// WithDryRun marks a request as a dry run
+func WithDryRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// IsDryRun reports whether the request is a dry run: its handler validates
+// and authorizes it as usual but reports what it would do instead of doing it
+func IsDryRun(ctx context.Context) bool {
+	dryRun, _ := ctx.Value(dryRunKey{}).(bool)
+	return dryRun
+}
+
+// DryRun marks POST, PUT, PATCH and DELETE requests with ?dryRun=true or an
+// X-Dry-Run: true header as dry runs, rejecting values that are not booleans
+// (400). Reads are never dry runs.
+func DryRun(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		value := r.URL.Query().Get("dryRun")
+		if value == "" {
+			value = r.Header.Get(HeaderDryRun)
+		}
+		if value == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		dryRun, err := strconv.ParseBool(value)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, "invalid-dry-run", "dryRun must be true or false")
+			return
+		}
+		if dryRun {
+			w.Header().Set(HeaderDryRun, "true")
+			r = r.WithContext(WithDryRun(r.Context()))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		target     string
+		header     string
+		wantDryRun bool
+		wantStatus int
+	}{
+		{name: "query parameter", method: http.MethodPost, target: "/api/v0/work?dryRun=true", wantDryRun: true, wantStatus: http.StatusNoContent},
+		{name: "header", method: http.MethodDelete, target: "/api/v0/clusters/c1", header: "true", wantDryRun: true, wantStatus: http.StatusNoContent},
+		{name: "query parameter overrides header", method: http.MethodPut, target: "/api/v0/authz/policies/p1?dryRun=false", header: "true", wantStatus: http.StatusNoContent},
+		{name: "absent", method: http.MethodPatch, target: "/api/v0/clusters/c1", wantStatus: http.StatusNoContent},
+		{name: "read", method: http.MethodGet, target: "/api/v0/clusters?dryRun=true", wantStatus: http.StatusNoContent},
+		{name: "invalid value", method: http.MethodPost, target: "/api/v0/work?dryRun=maybe", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			served, dryRun := false, false
+			handler := DryRun(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				served, dryRun = true, IsDryRun(r.Context())
+				w.WriteHeader(http.StatusNoContent)
+			}))
+
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderDryRun, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d", tt.wantStatus, rec.Code)
+			}
+			if served != (tt.wantStatus != http.StatusBadRequest) {
+				t.Fatalf("expected served=%v", !served)
+			}
+			if dryRun != tt.wantDryRun {
+				t.Errorf("expected dry run %v, got %v", tt.wantDryRun, dryRun)
+			}
+			if got := rec.Header().Get(HeaderDryRun); (got == "true") != tt.wantDryRun {
+				t.Errorf("unexpected %s response header %q", HeaderDryRun, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// ContextKeyExternalURL is the context key for the external URL of the API
+const ContextKeyExternalURL contextKey = "external_url"
+
+// ExternalURL records the absolute URL clients reach the API at, such as a
+// custom domain or an API Gateway stage URL, so generated links are absolute
+// and clients can follow them as they are
+type ExternalURL struct {
+	base string
+}
+
+// NewExternalURL creates a new ExternalURL middleware for base, an absolute
+// URL without a trailing slash; any trailing slash is dropped. An empty base
+// leaves links relative to the server's base path.
+func NewExternalURL(base string) *ExternalURL {
+	return &ExternalURL{base: strings.TrimRight(base, "/")}
+}
+
+// Apply records the external URL in the request context
+func (e *ExternalURL) Apply(next http.Handler) http.Handler {
+	if e.base == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), ContextKeyExternalURL, e.base)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetExternalURL returns the external URL of the API from the request
+// context, or "" when none is configured
+func GetExternalURL(ctx context.Context) string {
+	v, _ := ctx.Value(ContextKeyExternalURL).(string)
+	return v
+}
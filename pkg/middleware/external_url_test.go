@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestExternalURL_Apply(t *testing.T) {
+	tests := []struct {
+		name string
+		base string
+		want string
+	}{
+		{name: "custom domain", base: "https://api.example.com", want: "https://api.example.com"},
+		{name: "trailing slash dropped", base: "https://abc123.execute-api.us-east-1.amazonaws.com/prod/", want: "https://abc123.execute-api.us-east-1.amazonaws.com/prod"},
+		{name: "unset", base: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := NewExternalURL(tt.base).Apply(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = GetExternalURL(r.Context())
+			}))
+
+			handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v0/work", nil))
+
+			if got != tt.want {
+				t.Errorf("expected external URL %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -2,7 +2,14 @@ package middleware
 
 import (
 	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"regexp"
+	"strings"
 )
 
 type contextKey string
@@ -29,63 +36,384 @@ const (
 	HeaderRequestID = "X-Amz-Request-Id"
 )
 
+// HeaderRequestContext carries the JSON-encoded API Gateway v2 request
+// context when the API is fronted by an HTTP API and a Lambda adapter
+const HeaderRequestContext = "X-Amzn-Request-Context"
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// IdentityHeaders names the request headers identity is read from. Lambda
+// authorizers that inject identity under their own header names can be
+// supported by overriding them.
+type IdentityHeaders struct {
+	AccountID string
+	CallerARN string
+	UserID    string
+	SourceIP  string
+	RequestID string
+}
+
+// IdentityConfig configures where the Identity middleware reads identity from
+type IdentityConfig struct {
+	Headers IdentityHeaders
+	// RequestContext reads identity from the API Gateway v2 request context
+	// header, which takes precedence over Headers for any field it sets
+	RequestContext bool
+	// AuthorizerAccountIDKey and AuthorizerCallerARNKey name the Lambda
+	// authorizer context keys (requestContext.authorizer.lambda) holding the
+	// account ID and caller ARN; they are used when IAM authorization is not
+	AuthorizerAccountIDKey string
+	AuthorizerCallerARNKey string
+	// SharedSecret, when set, must be presented in SharedSecretHeader by the
+	// gateway on every request that carries identity
+	SharedSecret       string
+	SharedSecretHeader string
+	// SharedSecretFunc, when set, returns the current shared secret in place
+	// of SharedSecret, so a rotated secret takes effect without a restart
+	SharedSecretFunc func() string
+	// TrustedProxies, when set, restricts the peers whose identity headers
+	// are accepted
+	TrustedProxies []*net.IPNet
+	// SuperAdmins lists the IAM user and role ARNs trusted to bootstrap the
+	// first privileged account, whether or not their account is privileged
+	SuperAdmins []string
+}
+
+// DefaultSharedSecretHeader carries the gateway shared secret
+const DefaultSharedSecretHeader = "X-Rosa-Gateway-Secret"
+
+// DefaultIdentityConfig reads identity from the X-Amz-* headers
+func DefaultIdentityConfig() IdentityConfig {
+	return IdentityConfig{
+		Headers: IdentityHeaders{
+			AccountID: HeaderAccountID,
+			CallerARN: HeaderCallerARN,
+			UserID:    HeaderUserID,
+			SourceIP:  HeaderSourceIP,
+			RequestID: HeaderRequestID,
+		},
+		AuthorizerAccountIDKey: "accountId",
+		AuthorizerCallerARNKey: "callerArn",
+		SharedSecretHeader:     DefaultSharedSecretHeader,
+	}
+}
+
+// ParseCIDRs parses a list of CIDRs or bare IP addresses
+func ParseCIDRs(values []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if !strings.Contains(v, "/") {
+			ip := net.ParseIP(v)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid IP address %q", v)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", v, err)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// apiGatewayV2RequestContext is the subset of the API Gateway v2 request
+// context that carries caller identity
+type apiGatewayV2RequestContext struct {
+	RequestID string `json:"requestId"`
+	HTTP      struct {
+		SourceIP string `json:"sourceIp"`
+	} `json:"http"`
+	Authorizer struct {
+		IAM *struct {
+			AccountID string `json:"accountId"`
+			UserARN   string `json:"userArn"`
+			UserID    string `json:"userId"`
+		} `json:"iam"`
+		Lambda map[string]any `json:"lambda"`
+	} `json:"authorizer"`
+}
+
+// requestIdentity is the identity extracted from one request
+type requestIdentity struct {
+	accountID, callerARN, userID, sourceIP, requestID string
+}
+
+// IdentityExtractor provides middleware that adds the caller's identity to
+// the request context
+type IdentityExtractor struct {
+	cfg    IdentityConfig
+	logger *slog.Logger
+}
+
+// NewIdentity creates a new IdentityExtractor
+func NewIdentity(cfg IdentityConfig, logger *slog.Logger) *IdentityExtractor {
+	return &IdentityExtractor{
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
 // Identity extracts AWS identity headers and adds them to the request context
 func Identity(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		ctx := r.Context()
+	return NewIdentity(DefaultIdentityConfig(), slog.Default()).Extract(next)
+}
 
-		if accountID := r.Header.Get(HeaderAccountID); accountID != "" {
-			ctx = context.WithValue(ctx, ContextKeyAccountID, accountID)
+// Extract adds the caller's identity to the request context. Requests with
+// an account ID that is not 12 digits are rejected, as are requests carrying
+// identity from a peer that is not a trusted proxy or that lacks the shared
+// secret. Callers matching a configured super admin are marked as such.
+func (i *IdentityExtractor) Extract(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if i.carriesIdentity(r) {
+			if reason := i.untrusted(r); reason != "" {
+				i.logger.Warn("rejected identity from untrusted hop", "reason", reason, "remote_addr", r.RemoteAddr)
+				i.writeError(w, http.StatusForbidden, "untrusted-identity", "Identity headers are only accepted from the API gateway")
+				return
+			}
 		}
+		r.Header.Del(i.cfg.SharedSecretHeader)
 
-		if callerARN := r.Header.Get(HeaderCallerARN); callerARN != "" {
-			ctx = context.WithValue(ctx, ContextKeyCallerARN, callerARN)
+		id := i.fromHeaders(r)
+		if i.cfg.RequestContext {
+			if raw := r.Header.Get(HeaderRequestContext); raw != "" {
+				rc, err := i.fromRequestContext(raw)
+				if err != nil {
+					i.logger.Warn("invalid API Gateway request context", "error", err)
+					i.writeError(w, http.StatusBadRequest, "invalid-request-context", "Invalid API Gateway request context")
+					return
+				}
+				id = rc.merge(id)
+			}
 		}
 
-		if userID := r.Header.Get(HeaderUserID); userID != "" {
-			ctx = context.WithValue(ctx, ContextKeyUserID, userID)
+		if id.accountID != "" && !accountIDPattern.MatchString(id.accountID) {
+			i.logger.Warn("invalid account ID in request identity", "account_id", id.accountID)
+			i.writeError(w, http.StatusBadRequest, "invalid-account-id", "Account ID must be 12 digits")
+			return
 		}
 
-		if sourceIP := r.Header.Get(HeaderSourceIP); sourceIP != "" {
-			ctx = context.WithValue(ctx, ContextKeySourceIP, sourceIP)
+		ctx := r.Context()
+		if id.accountID != "" {
+			ctx = context.WithValue(ctx, ContextKeyAccountID, id.accountID)
 		}
-
-		if requestID := r.Header.Get(HeaderRequestID); requestID != "" {
-			ctx = context.WithValue(ctx, ContextKeyRequestID, requestID)
+		if id.callerARN != "" {
+			ctx = context.WithValue(ctx, ContextKeyCallerARN, id.callerARN)
+		}
+		if id.userID != "" {
+			ctx = context.WithValue(ctx, ContextKeyUserID, id.userID)
+		}
+		if id.sourceIP != "" {
+			ctx = context.WithValue(ctx, ContextKeySourceIP, id.sourceIP)
+		}
+		if id.requestID != "" {
+			ctx = context.WithValue(ctx, ContextKeyRequestID, id.requestID)
+		}
+		if i.superAdmin(id) {
+			ctx = context.WithValue(ctx, ContextKeySuperAdmin, true)
 		}
 
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
-// GetAccountID retrieves the AWS account ID from context
-func GetAccountID(ctx context.Context) string {
-	if v := ctx.Value(ContextKeyAccountID); v != nil {
-		return v.(string)
+// carriesIdentity reports whether the request has any identity header
+func (i *IdentityExtractor) carriesIdentity(r *http.Request) bool {
+	names := []string{
+		i.cfg.Headers.AccountID,
+		i.cfg.Headers.CallerARN,
+		i.cfg.Headers.UserID,
 	}
-	return ""
+	if i.cfg.RequestContext {
+		names = append(names, HeaderRequestContext)
+	}
+	for _, name := range names {
+		if name != "" && r.Header.Get(name) != "" {
+			return true
+		}
+	}
+	return false
 }
 
-// GetCallerARN retrieves the AWS caller ARN from context
-func GetCallerARN(ctx context.Context) string {
-	if v := ctx.Value(ContextKeyCallerARN); v != nil {
-		return v.(string)
+// untrusted returns why the request's hop is not trusted to assert
+// identity, or "" if it is
+func (i *IdentityExtractor) untrusted(r *http.Request) string {
+	if len(i.cfg.TrustedProxies) > 0 {
+		ip := remoteIP(r)
+		if ip == nil || !containsIP(i.cfg.TrustedProxies, ip) {
+			return "peer is not a trusted proxy"
+		}
+	}
+	if secret := i.cfg.sharedSecret(); secret != "" {
+		got := r.Header.Get(i.cfg.SharedSecretHeader)
+		if subtle.ConstantTimeCompare([]byte(got), []byte(secret)) != 1 {
+			return "missing or invalid shared secret"
+		}
 	}
 	return ""
 }
 
-// GetUserID retrieves the AWS user ID from context
-func GetUserID(ctx context.Context) string {
-	if v := ctx.Value(ContextKeyUserID); v != nil {
-		return v.(string)
+// sharedSecret returns the secret the gateway must present, or "" if none
+func (c IdentityConfig) sharedSecret() string {
+	if c.SharedSecretFunc != nil {
+		return c.SharedSecretFunc()
 	}
-	return ""
+	return c.SharedSecret
 }
 
-// GetRequestID retrieves the request ID from context
-func GetRequestID(ctx context.Context) string {
-	if v := ctx.Value(ContextKeyRequestID); v != nil {
-		return v.(string)
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	for _, n := range nets {
+		if n.Contains(ip) {
+			return true
+		}
 	}
-	return ""
+	return false
+}
+
+func (i *IdentityExtractor) fromHeaders(r *http.Request) requestIdentity {
+	get := func(name string) string {
+		if name == "" {
+			return ""
+		}
+		return r.Header.Get(name)
+	}
+	return requestIdentity{
+		accountID: get(i.cfg.Headers.AccountID),
+		callerARN: get(i.cfg.Headers.CallerARN),
+		userID:    get(i.cfg.Headers.UserID),
+		sourceIP:  get(i.cfg.Headers.SourceIP),
+		requestID: get(i.cfg.Headers.RequestID),
+	}
+}
+
+// fromRequestContext reads identity from IAM authorization if present,
+// otherwise from the configured Lambda authorizer context keys
+func (i *IdentityExtractor) fromRequestContext(raw string) (requestIdentity, error) {
+	var rc apiGatewayV2RequestContext
+	if err := json.Unmarshal([]byte(raw), &rc); err != nil {
+		return requestIdentity{}, err
+	}
+
+	id := requestIdentity{
+		sourceIP:  rc.HTTP.SourceIP,
+		requestID: rc.RequestID,
+	}
+	if iam := rc.Authorizer.IAM; iam != nil {
+		id.accountID = iam.AccountID
+		id.callerARN = iam.UserARN
+		id.userID = iam.UserID
+		return id, nil
+	}
+
+	lambdaString := func(key string) string {
+		if key == "" {
+			return ""
+		}
+		v, _ := rc.Authorizer.Lambda[key].(string)
+		return v
+	}
+	id.accountID = lambdaString(i.cfg.AuthorizerAccountIDKey)
+	id.callerARN = lambdaString(i.cfg.AuthorizerCallerARNKey)
+	return id, nil
+}
+
+// merge returns id with empty fields filled from fallback
+func (id requestIdentity) merge(fallback requestIdentity) requestIdentity {
+	pick := func(a, b string) string {
+		if a != "" {
+			return a
+		}
+		return b
+	}
+	return requestIdentity{
+		accountID: pick(id.accountID, fallback.accountID),
+		callerARN: pick(id.callerARN, fallback.callerARN),
+		userID:    pick(id.userID, fallback.userID),
+		sourceIP:  pick(id.sourceIP, fallback.sourceIP),
+		requestID: pick(id.requestID, fallback.requestID),
+	}
+}
+
+func (i *IdentityExtractor) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+// LookupAccountID returns the AWS account ID from context and whether it is set
+func LookupAccountID(ctx context.Context) (string, bool) {
+	return lookupString(ctx, ContextKeyAccountID)
+}
+
+// LookupCallerARN returns the AWS caller ARN from context and whether it is set
+func LookupCallerARN(ctx context.Context) (string, bool) {
+	return lookupString(ctx, ContextKeyCallerARN)
+}
+
+// LookupUserID returns the AWS user ID from context and whether it is set
+func LookupUserID(ctx context.Context) (string, bool) {
+	return lookupString(ctx, ContextKeyUserID)
+}
+
+// LookupRequestID returns the request ID from context and whether it is set
+func LookupRequestID(ctx context.Context) (string, bool) {
+	return lookupString(ctx, ContextKeyRequestID)
+}
+
+// MustGetAccountID returns the caller's account ID. When it is absent the
+// request is failed with the same 403 missing-account-id response the
+// middleware chain returns, and the caller must return without writing.
+func MustGetAccountID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	accountID, ok := LookupAccountID(r.Context())
+	if !ok {
+		writeJSONError(w, http.StatusForbidden, "missing-account-id", "Account ID header is required")
+	}
+	return accountID, ok
+}
+
+// lookupString returns a non-empty string value stored under key
+func lookupString(ctx context.Context, key contextKey) (string, bool) {
+	v, ok := ctx.Value(key).(string)
+	return v, ok && v != ""
+}
+
+// GetAccountID retrieves the AWS account ID from context, or "" when absent
+func GetAccountID(ctx context.Context) string {
+	v, _ := LookupAccountID(ctx)
+	return v
+}
+
+// GetCallerARN retrieves the AWS caller ARN from context, or "" when absent
+func GetCallerARN(ctx context.Context) string {
+	v, _ := LookupCallerARN(ctx)
+	return v
+}
+
+// GetUserID retrieves the AWS user ID from context, or "" when absent
+func GetUserID(ctx context.Context) string {
+	v, _ := LookupUserID(ctx)
+	return v
+}
+
+// GetRequestID retrieves the request ID from context, or "" when absent
+func GetRequestID(ctx context.Context) string {
+	v, _ := LookupRequestID(ctx)
+	return v
 }
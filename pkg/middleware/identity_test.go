@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"context"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -256,3 +258,247 @@ func TestGetRequestID_WithValue(t *testing.T) {
 		t.Errorf("expected request_id=req-abc-123, got %s", requestID)
 	}
 }
+
+func TestIdentity_InvalidAccountID(t *testing.T) {
+	for _, accountID := range []string{"12345", "12345678901a", "1234567890123"} {
+		t.Run(accountID, func(t *testing.T) {
+			handler := Identity(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				t.Error("handler should not be called")
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(HeaderAccountID, accountID)
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestIdentity_CustomHeaders(t *testing.T) {
+	cfg := DefaultIdentityConfig()
+	cfg.Headers.AccountID = "X-Authorizer-Account-Id"
+	cfg.Headers.CallerARN = "X-Authorizer-Principal"
+
+	handler := NewIdentity(cfg, slog.Default()).Extract(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := GetAccountID(r.Context()); got != "123456789012" {
+			t.Errorf("expected account_id=123456789012, got %s", got)
+		}
+		if got := GetCallerARN(r.Context()); got != "arn:aws:iam::123456789012:role/dev" {
+			t.Errorf("expected caller_arn from custom header, got %s", got)
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Header.Set("X-Authorizer-Account-Id", "123456789012")
+	req.Header.Set("X-Authorizer-Principal", "arn:aws:iam::123456789012:role/dev")
+	req.Header.Set(HeaderAccountID, "999999999999")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+}
+
+func TestIdentity_RequestContext(t *testing.T) {
+	tests := []struct {
+		name            string
+		requestContext  string
+		expectStatus    int
+		expectAccountID string
+		expectCallerARN string
+		expectSourceIP  string
+	}{
+		{
+			name:            "IAM authorizer",
+			requestContext:  `{"requestId":"req-1","http":{"sourceIp":"10.0.0.1"},"authorizer":{"iam":{"accountId":"123456789012","userArn":"arn:aws:iam::123456789012:user/alice","userId":"AIDA1"}}}`,
+			expectStatus:    http.StatusOK,
+			expectAccountID: "123456789012",
+			expectCallerARN: "arn:aws:iam::123456789012:user/alice",
+			expectSourceIP:  "10.0.0.1",
+		},
+		{
+			name:            "Lambda authorizer",
+			requestContext:  `{"requestId":"req-2","authorizer":{"lambda":{"accountId":"210987654321","callerArn":"arn:aws:iam::210987654321:role/ops"}}}`,
+			expectStatus:    http.StatusOK,
+			expectAccountID: "210987654321",
+			expectCallerARN: "arn:aws:iam::210987654321:role/ops",
+			expectSourceIP:  "192.168.1.1",
+		},
+		{
+			name:           "invalid account ID",
+			requestContext: `{"authorizer":{"lambda":{"accountId":"not-an-account"}}}`,
+			expectStatus:   http.StatusBadRequest,
+		},
+		{
+			name:           "malformed",
+			requestContext: `{"authorizer":`,
+			expectStatus:   http.StatusBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultIdentityConfig()
+			cfg.RequestContext = true
+
+			handler := NewIdentity(cfg, slog.Default()).Extract(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				ctx := r.Context()
+				if got := GetAccountID(ctx); got != tt.expectAccountID {
+					t.Errorf("expected account_id=%s, got %s", tt.expectAccountID, got)
+				}
+				if got := GetCallerARN(ctx); got != tt.expectCallerARN {
+					t.Errorf("expected caller_arn=%s, got %s", tt.expectCallerARN, got)
+				}
+				if got := ctx.Value(ContextKeySourceIP); got != tt.expectSourceIP {
+					t.Errorf("expected source_ip=%s, got %v", tt.expectSourceIP, got)
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(HeaderRequestContext, tt.requestContext)
+			req.Header.Set(HeaderSourceIP, "192.168.1.1")
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestIdentity_TrustedHops(t *testing.T) {
+	proxies, err := ParseCIDRs([]string{"10.0.0.0/16", "192.0.2.7"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		secret       string
+		identity     bool
+		expectStatus int
+	}{
+		{name: "trusted proxy with secret", remoteAddr: "10.0.3.4:5555", secret: "s3cret", identity: true, expectStatus: http.StatusOK},
+		{name: "trusted single IP", remoteAddr: "192.0.2.7:5555", secret: "s3cret", identity: true, expectStatus: http.StatusOK},
+		{name: "untrusted peer", remoteAddr: "203.0.113.9:5555", secret: "s3cret", identity: true, expectStatus: http.StatusForbidden},
+		{name: "wrong secret", remoteAddr: "10.0.3.4:5555", secret: "guess", identity: true, expectStatus: http.StatusForbidden},
+		{name: "missing secret", remoteAddr: "10.0.3.4:5555", identity: true, expectStatus: http.StatusForbidden},
+		{name: "no identity from untrusted peer", remoteAddr: "203.0.113.9:5555", expectStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultIdentityConfig()
+			cfg.TrustedProxies = proxies
+			cfg.SharedSecret = "s3cret"
+
+			handler := NewIdentity(cfg, slog.Default()).Extract(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get(DefaultSharedSecretHeader) != "" {
+					t.Error("shared secret header should be stripped")
+				}
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.identity {
+				req.Header.Set(HeaderAccountID, "123456789012")
+			}
+			if tt.secret != "" {
+				req.Header.Set(DefaultSharedSecretHeader, tt.secret)
+			}
+
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Errorf("expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestIdentity_SharedSecretFunc(t *testing.T) {
+	secret := "old"
+	cfg := DefaultIdentityConfig()
+	cfg.SharedSecret = "ignored"
+	cfg.SharedSecretFunc = func() string { return secret }
+	handler := NewIdentity(cfg, slog.Default()).Extract(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	send := func(presented string) int {
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(HeaderAccountID, "123456789012")
+		req.Header.Set(DefaultSharedSecretHeader, presented)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send("old"); code != http.StatusOK {
+		t.Errorf("expected the current secret to be accepted, got %d", code)
+	}
+	secret = "new"
+	if code := send("old"); code != http.StatusForbidden {
+		t.Errorf("expected the rotated-out secret to be rejected, got %d", code)
+	}
+	if code := send("new"); code != http.StatusOK {
+		t.Errorf("expected the rotated secret to be accepted, got %d", code)
+	}
+}
+
+func TestParseCIDRs_Invalid(t *testing.T) {
+	for _, v := range []string{"10.0.0.0/33", "not-an-ip"} {
+		if _, err := ParseCIDRs([]string{v}); err == nil {
+			t.Errorf("expected error for %q", v)
+		}
+	}
+}
+
+func TestLookupAccountID(t *testing.T) {
+	if _, ok := LookupAccountID(context.Background()); ok {
+		t.Error("expected no account ID in an empty context")
+	}
+	if _, ok := LookupAccountID(context.WithValue(context.Background(), ContextKeyAccountID, "")); ok {
+		t.Error("expected an empty account ID to be reported absent")
+	}
+	if _, ok := LookupAccountID(context.WithValue(context.Background(), ContextKeyAccountID, 42)); ok {
+		t.Error("expected a non-string account ID to be reported absent")
+	}
+	got, ok := LookupAccountID(context.WithValue(context.Background(), ContextKeyAccountID, "123456789012"))
+	if !ok || got != "123456789012" {
+		t.Errorf("expected 123456789012, got %q (ok=%v)", got, ok)
+	}
+}
+
+func TestMustGetAccountID(t *testing.T) {
+	w := httptest.NewRecorder()
+	if _, ok := MustGetAccountID(w, httptest.NewRequest(http.MethodGet, "/test", nil)); ok {
+		t.Fatal("expected missing account ID to fail")
+	}
+	if w.Code != http.StatusForbidden {
+		t.Errorf("expected status 403, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"code":"missing-account-id"`) {
+		t.Errorf("expected missing-account-id error, got %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, "123456789012"))
+	accountID, ok := MustGetAccountID(w, req)
+	if !ok || accountID != "123456789012" {
+		t.Errorf("expected 123456789012, got %q (ok=%v)", accountID, ok)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("expected nothing written, got %s", w.Body.String())
+	}
+}
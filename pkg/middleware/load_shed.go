@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/loadshed"
+)
+
+// shedRetryAfter is the Retry-After, in seconds, of shed requests
+const shedRetryAfter = "10"
+
+var shedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_shed_requests_total",
+	Help: "Low-priority API requests rejected with 503 while the server was degraded, by reason.",
+}, []string{"reason"})
+
+// shedSignal is a condition under which the server is degraded
+type shedSignal struct {
+	reason   string
+	degraded func() bool
+}
+
+// LoadShedder rejects low-priority requests with 503 while any of the
+// signals it watches reports the server degraded, such as authorization
+// being unavailable or the work queue being saturated, so the capacity left
+// goes to the requests that matter most.
+type LoadShedder struct {
+	rules  loadshed.Rules
+	logger *slog.Logger
+
+	mu      sync.RWMutex
+	signals []shedSignal
+}
+
+// NewLoadShedder creates a new LoadShedder, prioritizing requests by rules
+func NewLoadShedder(rules loadshed.Rules, logger *slog.Logger) *LoadShedder {
+	return &LoadShedder{rules: rules, logger: logger}
+}
+
+// Watch sheds low-priority requests, reported with reason, while degraded
+// returns true
+func (s *LoadShedder) Watch(reason string, degraded func() bool) *LoadShedder {
+	s.mu.Lock()
+	s.signals = append(s.signals, shedSignal{reason: reason, degraded: degraded})
+	s.mu.Unlock()
+	return s
+}
+
+// Degraded returns the reason of the first watched signal reporting the
+// server degraded, or an empty string while it is healthy
+func (s *LoadShedder) Degraded() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, signal := range s.signals {
+		if signal.degraded() {
+			return signal.reason
+		}
+	}
+	return ""
+}
+
+// priority returns the priority of a request by its matched route
+func (s *LoadShedder) priority(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return loadshed.PriorityNormal
+	}
+	template, err := route.GetPathTemplate()
+	if err != nil {
+		return loadshed.PriorityNormal
+	}
+	return s.rules.Priority(r.Method, template)
+}
+
+// Shed rejects low-priority requests while the server is degraded
+func (s *LoadShedder) Shed(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.priority(r) != loadshed.PriorityLow {
+			next.ServeHTTP(w, r)
+			return
+		}
+		reason := s.Degraded()
+		if reason == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		shedRequests.WithLabelValues(reason).Inc()
+		s.logger.Debug("shedding low-priority request", "method", r.Method, "path", r.URL.Path, "reason", reason)
+		w.Header().Set("Retry-After", shedRetryAfter)
+		s.writeError(w, http.StatusServiceUnavailable, "load-shed", "The server is degraded and is not serving low-priority requests, retry later")
+	})
+}
+
+func (s *LoadShedder) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
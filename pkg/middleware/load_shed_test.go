@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/loadshed"
+)
+
+func TestLoadShedder(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	rules, err := loadshed.ParseRules("GET /api/v0/work=low")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	degraded := false
+	shedder := NewLoadShedder(rules, logger).
+		Watch("healthy", func() bool { return false }).
+		Watch("work-queue", func() bool { return degraded })
+
+	router := mux.NewRouter()
+	router.Use(shedder.Shed)
+	ok := func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) }
+	router.HandleFunc("/api/v0/work", ok).Methods(http.MethodGet, http.MethodPost)
+	router.HandleFunc("/api/v0/work/{id}", ok).Methods(http.MethodGet)
+
+	tests := []struct {
+		name           string
+		degraded       bool
+		method, path   string
+		expectedStatus int
+	}{
+		{name: "healthy serves low priority", method: http.MethodGet, path: "/api/v0/work", expectedStatus: http.StatusOK},
+		{name: "degraded sheds low priority", degraded: true, method: http.MethodGet, path: "/api/v0/work", expectedStatus: http.StatusServiceUnavailable},
+		{name: "degraded serves other methods", degraded: true, method: http.MethodPost, path: "/api/v0/work", expectedStatus: http.StatusOK},
+		{name: "degraded serves other routes", degraded: true, method: http.MethodGet, path: "/api/v0/work/w1", expectedStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			degraded = tt.degraded
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("expected status %d, got %d", tt.expectedStatus, w.Code)
+			}
+			if w.Code == http.StatusServiceUnavailable && w.Header().Get("Retry-After") == "" {
+				t.Error("expected Retry-After header on 503")
+			}
+		})
+	}
+
+	degraded = true
+	if got := shedder.Degraded(); got != "work-queue" {
+		t.Errorf("expected the degraded signal's reason, got %q", got)
+	}
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"golang.org/x/text/language"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/i18n"
+)
+
+// Localize translates the reason of error responses into the language the
+// caller asks for with Accept-Language. Codes and successful responses are
+// left alone, and reasons without a translation stay in English.
+type Localize struct {
+	catalog *i18n.Catalog
+}
+
+// NewLocalize creates a new Localize middleware
+func NewLocalize(catalog *i18n.Catalog) *Localize {
+	return &Localize{catalog: catalog}
+}
+
+// Translate buffers error responses to requests that prefer a language other
+// than English and rewrites their reason from the catalog
+func (l *Localize) Translate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Vary", "Accept-Language")
+
+		accept := r.Header.Get("Accept-Language")
+		if accept == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		tag := l.catalog.Negotiate(accept)
+		if tag == language.English {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		lw := &localizedWriter{ResponseWriter: w}
+		next.ServeHTTP(lw, r)
+		if lw.buffering {
+			lw.flush(l.catalog, tag)
+		}
+	})
+}
+
+// localizedWriter passes successful responses through and holds back error
+// responses until their reason has been translated
+type localizedWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	buffering   bool
+	status      int
+	body        bytes.Buffer
+}
+
+func (w *localizedWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	if status >= http.StatusBadRequest {
+		w.buffering = true
+		w.status = status
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *localizedWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buffering {
+		return w.body.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer. Only
+// successful responses, which are not held back, are streamed.
+func (w *localizedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// flush writes the held-back error response, translating its reason
+func (w *localizedWriter) flush(catalog *i18n.Catalog, tag language.Tag) {
+	body := w.body.Bytes()
+
+	var resp map[string]json.RawMessage
+	var kind, reason string
+	if json.Unmarshal(body, &resp) == nil &&
+		json.Unmarshal(resp["kind"], &kind) == nil && kind == "Error" &&
+		json.Unmarshal(resp["reason"], &reason) == nil {
+		if translated, ok := catalog.Translate(tag, reason); ok {
+			resp["reason"], _ = json.Marshal(translated)
+			if data, err := json.Marshal(resp); err == nil {
+				body = append(data, '\n')
+				w.Header().Set("Content-Language", tag.String())
+				w.Header().Del("Content-Length")
+			}
+		}
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(body)
+}
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/i18n"
+)
+
+func TestLocalize_Translate(t *testing.T) {
+	catalog, err := i18n.Load()
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		accept         string
+		status         int
+		reason         string
+		expectReason   string
+		expectLanguage string
+	}{
+		{
+			name:         "no Accept-Language",
+			status:       http.StatusNotFound,
+			reason:       "Cluster not found",
+			expectReason: "Cluster not found",
+		},
+		{
+			name:           "translated error",
+			accept:         "ja-JP,ja;q=0.9",
+			status:         http.StatusNotFound,
+			reason:         "Cluster not found",
+			expectReason:   "クラスターが見つかりません",
+			expectLanguage: "ja",
+		},
+		{
+			name:         "reason without translation",
+			accept:       "es",
+			status:       http.StatusBadRequest,
+			reason:       "limit must not exceed 100",
+			expectReason: "limit must not exceed 100",
+		},
+		{
+			name:         "unsupported language",
+			accept:       "de",
+			status:       http.StatusNotFound,
+			reason:       "Cluster not found",
+			expectReason: "Cluster not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := NewLocalize(catalog).Translate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				writeJSONError(w, tt.status, "not-found", tt.reason)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters/abc", nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept-Language", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, rec.Code)
+			}
+			var body map[string]string
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if body["code"] != "not-found" {
+				t.Errorf("expected code to be kept, got %q", body["code"])
+			}
+			if body["reason"] != tt.expectReason {
+				t.Errorf("expected reason %q, got %q", tt.expectReason, body["reason"])
+			}
+			if got := rec.Header().Get("Content-Language"); got != tt.expectLanguage {
+				t.Errorf("expected Content-Language %q, got %q", tt.expectLanguage, got)
+			}
+		})
+	}
+}
+
+func TestLocalize_SuccessPassesThrough(t *testing.T) {
+	catalog, err := i18n.Load()
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+	handler := NewLocalize(catalog).Translate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"kind":"Cluster","reason":"Cluster not found"}`))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/clusters", nil)
+	req.Header.Set("Accept-Language", "fr")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected status 201, got %d", rec.Code)
+	}
+	if got := rec.Body.String(); got != `{"kind":"Cluster","reason":"Cluster not found"}` {
+		t.Errorf("expected body to be unchanged, got %s", got)
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/metering"
+)
+
+// MeteringEmitter queues metering records for delivery
+type MeteringEmitter interface {
+	Emit(rec metering.Record)
+}
+
+// Metering records a metering record for every billable API call: a
+// successful request from an account that changes something. Reads, dry
+// runs and failed requests are not billed.
+type Metering struct {
+	emitter MeteringEmitter
+}
+
+// NewMetering creates a new Metering middleware
+func NewMetering(emitter MeteringEmitter) *Metering {
+	return &Metering{emitter: emitter}
+}
+
+// Meter emits a record, one unit per call, named by method and route
+// template, such as "POST /api/v0/clusters", once the request succeeds
+func (m *Metering) Meter(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID := GetAccountID(r.Context())
+		if accountID == "" || !billable(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		if rec.status < 200 || rec.status >= 300 {
+			return
+		}
+
+		m.emitter.Emit(metering.Record{
+			AccountID: accountID,
+			Operation: r.Method + " " + routeTemplate(r),
+			Units:     1,
+			RequestID: GetRequestID(r.Context()),
+		})
+	})
+}
+
+// billable reports whether r would be billed if it succeeds
+func billable(r *http.Request) bool {
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return !IsDryRun(r.Context())
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/metering"
+)
+
+// collectingEmitter keeps the records it is given
+type collectingEmitter struct {
+	records []metering.Record
+}
+
+func (c *collectingEmitter) Emit(rec metering.Record) {
+	c.records = append(c.records, rec)
+}
+
+func TestMetering_Meter(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		status    int
+		accountID string
+		dryRun    bool
+		billed    bool
+	}{
+		{name: "successful create", method: http.MethodPost, status: http.StatusCreated, accountID: "123456789012", billed: true},
+		{name: "successful delete", method: http.MethodDelete, status: http.StatusAccepted, accountID: "123456789012", billed: true},
+		{name: "read", method: http.MethodGet, status: http.StatusOK, accountID: "123456789012"},
+		{name: "failed create", method: http.MethodPost, status: http.StatusBadRequest, accountID: "123456789012"},
+		{name: "dry run", method: http.MethodPost, status: http.StatusOK, accountID: "123456789012", dryRun: true},
+		{name: "no account", method: http.MethodPost, status: http.StatusCreated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			emitter := &collectingEmitter{}
+			router := mux.NewRouter()
+			router.Use(NewMetering(emitter).Meter)
+			router.HandleFunc("/api/v0/clusters/{id}", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			})
+
+			req := httptest.NewRequest(tt.method, "/api/v0/clusters/c1", nil)
+			ctx := context.WithValue(req.Context(), ContextKeyRequestID, "req-1")
+			if tt.accountID != "" {
+				ctx = context.WithValue(ctx, ContextKeyAccountID, tt.accountID)
+			}
+			if tt.dryRun {
+				ctx = WithDryRun(ctx)
+			}
+			router.ServeHTTP(httptest.NewRecorder(), req.WithContext(ctx))
+
+			if !tt.billed {
+				if len(emitter.records) != 0 {
+					t.Errorf("expected no record, got %+v", emitter.records)
+				}
+				return
+			}
+			if len(emitter.records) != 1 {
+				t.Fatalf("expected one record, got %d", len(emitter.records))
+			}
+			rec := emitter.records[0]
+			if rec.AccountID != tt.accountID || rec.Operation != tt.method+" /api/v0/clusters/{id}" || rec.Units != 1 || rec.RequestID != "req-1" {
+				t.Errorf("unexpected record %+v", rec)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/mirror"
+)
+
+// RequestMirror sends copies of requests to a secondary deployment
+type RequestMirror interface {
+	Send(req mirror.Request, primary mirror.Result)
+}
+
+// Mirror copies a sample of requests to a secondary deployment once they
+// have been answered. Reads are mirrored, and writes only when they are dry
+// runs and dryRunWrites is set, so the secondary never changes anything.
+// Requests a mirror sent are never mirrored again.
+// This middleware should run after DryRun middleware.
+type Mirror struct {
+	mirror       RequestMirror
+	percent      float64
+	dryRunWrites bool
+	sample       func() float64
+}
+
+// NewMirror creates a new Mirror middleware sending percent of the eligible
+// requests to mirror
+func NewMirror(m RequestMirror, percent float64, dryRunWrites bool) *Mirror {
+	return &Mirror{
+		mirror:       m,
+		percent:      percent,
+		dryRunWrites: dryRunWrites,
+		sample:       func() float64 { return rand.Float64() * 100 },
+	}
+}
+
+// Track mirrors the request after it has been served
+func (m *Mirror) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.eligible(r) || m.sample() >= m.percent {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var body []byte
+		if r.Body != nil {
+			var err error
+			body, err = io.ReadAll(r.Body)
+			if err != nil {
+				writeJSONError(w, http.StatusBadRequest, "invalid-request-body", "Failed to read request body")
+				return
+			}
+			_ = r.Body.Close()
+			r.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		req := mirror.Request{
+			Method:    r.Method,
+			Path:      r.URL.Path,
+			RawQuery:  r.URL.RawQuery,
+			Header:    r.Header.Clone(),
+			Body:      body,
+			Route:     routeTemplate(r),
+			RequestID: GetRequestID(r.Context()),
+		}
+
+		started := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		m.mirror.Send(req, mirror.Result{Status: rec.status, Elapsed: time.Since(started)})
+	})
+}
+
+func (m *Mirror) eligible(r *http.Request) bool {
+	if r.Header.Get(mirror.HeaderMirrored) != "" {
+		return false
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		return true
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return m.dryRunWrites && IsDryRun(r.Context())
+	default:
+		return false
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/mirror"
+)
+
+type fakeRequestMirror struct {
+	requests []mirror.Request
+	results  []mirror.Result
+}
+
+func (f *fakeRequestMirror) Send(req mirror.Request, primary mirror.Result) {
+	f.requests = append(f.requests, req)
+	f.results = append(f.results, primary)
+}
+
+func TestMirror_Track(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		target       string
+		header       string
+		dryRunWrites bool
+		sample       float64
+		expectSent   bool
+	}{
+		{name: "read within the sample", method: http.MethodGet, target: "/api/v0/clusters/c1", sample: 5, expectSent: true},
+		{name: "read outside the sample", method: http.MethodGet, target: "/api/v0/clusters/c1", sample: 50},
+		{name: "write", method: http.MethodPost, target: "/api/v0/clusters/c1", dryRunWrites: true, sample: 5},
+		{name: "dry run write", method: http.MethodPost, target: "/api/v0/clusters/c1?dryRun=true", dryRunWrites: true, sample: 5, expectSent: true},
+		{name: "dry run write without dry run writes", method: http.MethodPost, target: "/api/v0/clusters/c1?dryRun=true", sample: 5},
+		{name: "already mirrored", method: http.MethodGet, target: "/api/v0/clusters/c1", header: mirror.HeaderMirrored, sample: 5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sent := &fakeRequestMirror{}
+			m := NewMirror(sent, 10, tt.dryRunWrites)
+			m.sample = func() float64 { return tt.sample }
+
+			var gotBody string
+			router := mux.NewRouter()
+			router.Use(DryRun)
+			router.Use(m.Track)
+			router.HandleFunc("/api/v0/clusters/{id}", func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotBody = string(body)
+				w.WriteHeader(http.StatusTeapot)
+			})
+
+			req := httptest.NewRequest(tt.method, tt.target, strings.NewReader(`{"name":"c1"}`))
+			if tt.header != "" {
+				req.Header.Set(tt.header, "true")
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if gotBody != `{"name":"c1"}` {
+				t.Errorf("expected the handler to read the body, got %q", gotBody)
+			}
+			if !tt.expectSent {
+				if len(sent.requests) != 0 {
+					t.Errorf("expected no mirrored request, got %+v", sent.requests)
+				}
+				return
+			}
+			if len(sent.requests) != 1 {
+				t.Fatalf("expected one mirrored request, got %d", len(sent.requests))
+			}
+			got := sent.requests[0]
+			if got.Route != "/api/v0/clusters/{id}" || got.Path != "/api/v0/clusters/c1" || string(got.Body) != `{"name":"c1"}` {
+				t.Errorf("unexpected mirrored request %+v", got)
+			}
+			if sent.results[0].Status != http.StatusTeapot {
+				t.Errorf("expected the primary status to be recorded, got %d", sent.results[0].Status)
+			}
+		})
+	}
+}
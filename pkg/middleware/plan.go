@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+)
+
+// ContextKeyPlan is the context key for the limits of the caller's plan
+const ContextKeyPlan contextKey = "plan"
+
+// PlanResolver looks up the plan an account is on
+type PlanResolver interface {
+	AccountPlan(ctx context.Context, accountID string) (string, error)
+}
+
+// Plan resolves the plan of the caller's account and adds its limits to the
+// request context, where the rate limiter and handlers enforce them.
+type Plan struct {
+	mu          sync.RWMutex
+	tiers       plans.Tiers
+	defaultPlan string
+	resolver    PlanResolver
+	logger      *slog.Logger
+}
+
+// NewPlan creates a new Plan middleware, which puts every account on
+// defaultPlan until a resolver is set
+func NewPlan(tiers plans.Tiers, defaultPlan string, logger *slog.Logger) *Plan {
+	return &Plan{tiers: tiers, defaultPlan: defaultPlan, logger: logger}
+}
+
+// WithResolver looks plans up with resolver, once accounts are available
+func (p *Plan) WithResolver(resolver PlanResolver) *Plan {
+	p.resolver = resolver
+	return p
+}
+
+// SetTiers replaces the limits of each plan, when the configuration is
+// reloaded
+func (p *Plan) SetTiers(tiers plans.Tiers) {
+	p.mu.Lock()
+	p.tiers = tiers
+	p.mu.Unlock()
+}
+
+// Resolve adds the plan limits of the caller's account to the context.
+// Requests without an account ID get none; a failed lookup uses the default
+// plan rather than failing the request.
+// This middleware should run after Identity middleware.
+func (p *Plan) Resolve(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID := GetAccountID(r.Context())
+		if accountID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		plan := p.defaultPlan
+		if p.resolver != nil {
+			resolved, err := p.resolver.AccountPlan(r.Context(), accountID)
+			if err != nil {
+				p.logger.Warn("failed to resolve account plan, using the default plan",
+					"error", err, "account_id", accountID, "plan", plan)
+			} else {
+				plan = resolved
+			}
+		}
+		p.mu.RLock()
+		limits, ok := p.tiers[plan]
+		if !ok {
+			limits = p.tiers[p.defaultPlan]
+		}
+		p.mu.RUnlock()
+
+		ctx := context.WithValue(r.Context(), ContextKeyPlan, limits)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// GetPlan returns the limits of the caller's plan, and false when no plan was
+// resolved for the request
+func GetPlan(ctx context.Context) (plans.Limits, bool) {
+	limits, ok := ctx.Value(ContextKeyPlan).(plans.Limits)
+	return limits, ok
+}
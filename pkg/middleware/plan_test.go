@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+)
+
+// staticPlans resolves accounts from a map
+type staticPlans struct {
+	plans map[string]string
+	err   error
+}
+
+func (s staticPlans) AccountPlan(ctx context.Context, accountID string) (string, error) {
+	return s.plans[accountID], s.err
+}
+
+func TestPlan_Resolve(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	tiers := plans.NewTiers(100, 500)
+
+	tests := []struct {
+		name      string
+		accountID string
+		resolver  PlanResolver
+		wantPlan  string
+		wantNone  bool
+	}{
+		{name: "no resolver uses the default plan", accountID: "111111111111", wantPlan: plans.Standard},
+		{
+			name:      "resolved plan",
+			accountID: "111111111111",
+			resolver:  staticPlans{plans: map[string]string{"111111111111": plans.Premium}},
+			wantPlan:  plans.Premium,
+		},
+		{
+			name:      "failed lookup uses the default plan",
+			accountID: "111111111111",
+			resolver:  staticPlans{err: errors.New("throttled")},
+			wantPlan:  plans.Standard,
+		},
+		{
+			name:      "unknown plan uses the default plan",
+			accountID: "111111111111",
+			resolver:  staticPlans{plans: map[string]string{"111111111111": "enterprise"}},
+			wantPlan:  plans.Standard,
+		},
+		{name: "no account ID", wantNone: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw := NewPlan(tiers, plans.Standard, logger)
+			if tt.resolver != nil {
+				mw.WithResolver(tt.resolver)
+			}
+
+			var got plans.Limits
+			var ok bool
+			handler := mw.Resolve(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, ok = GetPlan(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+			if tt.accountID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, tt.accountID))
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if tt.wantNone {
+				if ok {
+					t.Errorf("expected no plan, got %+v", got)
+				}
+				return
+			}
+			if !ok || got.Plan != tt.wantPlan {
+				t.Errorf("expected plan %s, got %+v", tt.wantPlan, got)
+			}
+		})
+	}
+}
@@ -38,6 +38,12 @@ func (p *Privileged) CheckPrivileged(next http.Handler) http.Handler {
 			return
 		}
 
+		// Delegated callers never act with the target account's privileges
+		if _, delegated := LookupDelegateAccountID(ctx); delegated {
+			next.ServeHTTP(w, r)
+			return
+		}
+
 		isPrivileged, err := p.authorizer.IsPrivileged(ctx, accountID)
 		if err != nil {
 			p.logger.Error("failed to check privileged status", "error", err, "account_id", accountID)
@@ -69,6 +75,10 @@ func (p *Privileged) RequirePrivileged(next http.Handler) http.Handler {
 			isPrivileged, err = p.authorizer.IsPrivileged(ctx, accountID)
 			if err != nil {
 				p.logger.Error("failed to check privileged status", "error", err, "account_id", accountID)
+				if authzTimedOut(err) {
+					p.writeError(w, http.StatusGatewayTimeout, "authz-timeout", authzTimeoutReason)
+					return
+				}
 				p.writeError(w, http.StatusInternalServerError, "internal-error", "Failed to check account status")
 				return
 			}
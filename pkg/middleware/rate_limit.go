@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+var rateLimitedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_rate_limited_requests_total",
+	Help: "API requests rejected with 429 because the account exceeded its rate limit.",
+}, []string{"method"})
+
+// RateLimit rejects requests from accounts that exceed their request rate.
+// Requests without an account ID, such as health checks, are not limited.
+type RateLimit struct {
+	mu      sync.RWMutex
+	limiter ratelimit.Limiter
+	plans   map[string]ratelimit.Limiter
+	logger  *slog.Logger
+}
+
+// NewRateLimit creates a new RateLimit middleware
+func NewRateLimit(limiter ratelimit.Limiter, logger *slog.Logger) *RateLimit {
+	return &RateLimit{limiter: limiter, logger: logger}
+}
+
+// WithPlans limits accounts with the limiter of the plan the Plan middleware
+// resolved for them. Accounts on plans without a limiter use the default one.
+func (rl *RateLimit) WithPlans(limiters map[string]ratelimit.Limiter) *RateLimit {
+	rl.plans = limiters
+	return rl
+}
+
+// SetLimiters replaces the default and per-plan limiters, when the
+// configuration is reloaded. Requests counted by the old limiters are
+// forgotten.
+func (rl *RateLimit) SetLimiters(limiter ratelimit.Limiter, plans map[string]ratelimit.Limiter) {
+	rl.mu.Lock()
+	rl.limiter, rl.plans = limiter, plans
+	rl.mu.Unlock()
+}
+
+// limiterFor returns the limiter for the caller's plan
+func (rl *RateLimit) limiterFor(ctx context.Context) (string, ratelimit.Limiter) {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+	if limits, ok := GetPlan(ctx); ok {
+		if limiter, ok := rl.plans[limits.Plan]; ok {
+			return limits.Plan, limiter
+		}
+	}
+	return "", rl.limiter
+}
+
+// Used returns how many requests the account has made in the current window
+// of plan's limiter, when its backend counts them
+func (rl *RateLimit) Used(ctx context.Context, plan, accountID string) (int, error) {
+	rl.mu.RLock()
+	limiter, ok := rl.plans[plan]
+	if !ok {
+		limiter = rl.limiter
+	}
+	rl.mu.RUnlock()
+	counter, ok := limiter.(ratelimit.Counter)
+	if !ok {
+		return 0, ratelimit.ErrNotCounted
+	}
+	return counter.Used(ctx, accountID)
+}
+
+// Limit returns 429 with Retry-After once the caller's account is over its
+// limit. A limiter error lets the request through rather than failing it.
+func (rl *RateLimit) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accountID := GetAccountID(r.Context())
+		if accountID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		plan, limiter := rl.limiterFor(r.Context())
+		allowed, retryAfter, err := limiter.Allow(r.Context(), accountID)
+		if err != nil {
+			rl.logger.Warn("failed to check rate limit, allowing request", "error", err, "account_id", accountID)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !allowed {
+			rateLimitedRequests.WithLabelValues(r.Method).Inc()
+			rl.logger.Info("rate limit exceeded", "account_id", accountID, "plan", plan, "retry_after", retryAfter)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			rl.writeError(w, http.StatusTooManyRequests, "rate-limited", "Rate limit exceeded, retry later")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (rl *RateLimit) writeError(w http.ResponseWriter, status int, code, reason string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	resp := map[string]interface{}{
+		"kind":   "Error",
+		"code":   code,
+		"reason": reason,
+	}
+
+	_ = json.NewEncoder(w).Encode(resp)
+}
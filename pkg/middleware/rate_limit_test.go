@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+// fixedLimiter answers every request the same way
+type fixedLimiter struct {
+	allowed    bool
+	retryAfter time.Duration
+	err        error
+	calls      int
+}
+
+func (l *fixedLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.calls++
+	return l.allowed, l.retryAfter, l.err
+}
+
+func TestRateLimit_Limit(t *testing.T) {
+	tests := []struct {
+		name         string
+		accountID    string
+		limiter      *fixedLimiter
+		expectStatus int
+		expectRetry  string
+	}{
+		{
+			name:         "within limit",
+			accountID:    "123456789012",
+			limiter:      &fixedLimiter{allowed: true},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "over limit",
+			accountID:    "123456789012",
+			limiter:      &fixedLimiter{retryAfter: 1500 * time.Millisecond},
+			expectStatus: http.StatusTooManyRequests,
+			expectRetry:  "2",
+		},
+		{
+			name:         "limiter error lets the request through",
+			accountID:    "123456789012",
+			limiter:      &fixedLimiter{err: errors.New("unavailable")},
+			expectStatus: http.StatusOK,
+		},
+		{
+			name:         "no account ID",
+			limiter:      &fixedLimiter{},
+			expectStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+			handler := NewRateLimit(tt.limiter, logger).Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+			if tt.accountID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, tt.accountID))
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.expectStatus {
+				t.Fatalf("expected status %d, got %d", tt.expectStatus, w.Code)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.expectRetry {
+				t.Errorf("expected Retry-After %q, got %q", tt.expectRetry, got)
+			}
+			if tt.accountID == "" && tt.limiter.calls != 0 {
+				t.Error("expected requests without an account ID not to be limited")
+			}
+			if tt.expectStatus == http.StatusTooManyRequests {
+				var body map[string]string
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if body["code"] != "rate-limited" {
+					t.Errorf("expected code rate-limited, got %q", body["code"])
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimit_UsesPlanLimiter(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	defaultLimiter := &fixedLimiter{allowed: true}
+	freeLimiter := &fixedLimiter{retryAfter: time.Second}
+	rl := NewRateLimit(defaultLimiter, logger).WithPlans(map[string]ratelimit.Limiter{plans.Free: freeLimiter})
+	handler := rl.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	send := func(plan string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+		ctx := context.WithValue(req.Context(), ContextKeyAccountID, "123456789012")
+		ctx = context.WithValue(ctx, ContextKeyPlan, plans.Limits{Plan: plan})
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+		return w.Code
+	}
+
+	if got := send(plans.Free); got != http.StatusTooManyRequests || freeLimiter.calls != 1 {
+		t.Errorf("expected the free plan's limiter to limit, got %d", got)
+	}
+	if got := send(plans.Premium); got != http.StatusOK || defaultLimiter.calls != 1 {
+		t.Errorf("expected a plan without a limiter to use the default one, got %d", got)
+	}
+	if _, err := rl.Used(context.Background(), plans.Free, "123456789012"); !errors.Is(err, ratelimit.ErrNotCounted) {
+		t.Errorf("expected a limiter that does not count to say so, got %v", err)
+	}
+}
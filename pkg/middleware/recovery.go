@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/errtrack"
+)
+
+// Recovery turns panics in handlers into 500 responses, logging them with a
+// stack trace and reporting them to the error tracker
+type Recovery struct {
+	logger *slog.Logger
+}
+
+// NewRecovery creates a new Recovery middleware
+func NewRecovery(logger *slog.Logger) *Recovery {
+	return &Recovery{
+		logger: logger,
+	}
+}
+
+// Recover recovers panics raised by next. http.ErrAbortHandler is re-raised
+// so net/http can abort the response as intended.
+func (rc *Recovery) Recover(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			errtrack.CapturePanic(r, recovered)
+			// Already reported with request context above
+			rc.logger.ErrorContext(errtrack.SkipReport(r.Context()), "panic while serving request",
+				"panic", fmt.Sprint(recovered),
+				"method", r.Method,
+				"path", r.URL.Path,
+				"stack", string(debug.Stack()),
+			)
+			writeJSONError(w, http.StatusInternalServerError, "internal-error", "Internal server error")
+		}()
+
+		next.ServeHTTP(w, r)
+	})
+}
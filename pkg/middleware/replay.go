@@ -0,0 +1,229 @@
+package middleware
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Headers an upstream authorizer signs alongside the identity headers so a
+// captured request cannot be replayed while its signature is still valid
+const (
+	HeaderRequestTimestamp = "X-Rosa-Request-Timestamp"
+	HeaderRequestNonce     = "X-Rosa-Request-Nonce"
+)
+
+// maxNonceLength bounds the memory one remembered nonce can take
+const maxNonceLength = 128
+
+var replayRejectedRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_replay_rejected_requests_total",
+	Help: "Mutating API requests rejected by replay protection, by reason (missing, invalid, stale, replayed, cache_full).",
+}, []string{"reason"})
+
+// ReplayProtection rejects mutating requests whose timestamp is outside the
+// accepted window or whose nonce was already seen within it. Nonces are
+// remembered by each replica, so it relies on the gateway or authorizer
+// pinning a nonce to the request it signed rather than on replicas sharing
+// what they have seen. Each account's nonces are remembered and capped
+// separately, so one account filling its cache does not turn away the
+// others.
+type ReplayProtection struct {
+	window    time.Duration
+	maxNonces int
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu       sync.Mutex
+	accounts map[string]*nonceCache
+	// nextSweep is when the caches of accounts whose nonces have all
+	// expired are next dropped
+	nextSweep time.Time
+}
+
+// NewReplayProtection creates a new ReplayProtection middleware accepting
+// timestamps up to window either side of now and remembering at most
+// maxNonces nonces per account
+func NewReplayProtection(window time.Duration, maxNonces int, logger *slog.Logger) *ReplayProtection {
+	return &ReplayProtection{
+		window:    window,
+		maxNonces: maxNonces,
+		logger:    logger,
+		now:       time.Now,
+		accounts:  make(map[string]*nonceCache),
+	}
+}
+
+// Check requires POST, PUT, PATCH and DELETE requests carrying identity to
+// send a Unix timestamp in X-Rosa-Request-Timestamp and a nonce in
+// X-Rosa-Request-Nonce. Missing or malformed headers are rejected with 400,
+// stale timestamps and reused nonces with 403. When the cache is full of
+// nonces that may still be replayed, the account's requests are rejected with
+// 503 rather than forgetting one early.
+func (rp *ReplayProtection) Check(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			next.ServeHTTP(w, r)
+			return
+		}
+		accountID := GetAccountID(r.Context())
+		if accountID == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rawTimestamp := r.Header.Get(HeaderRequestTimestamp)
+		nonce := r.Header.Get(HeaderRequestNonce)
+		if rawTimestamp == "" || nonce == "" {
+			replayRejectedRequests.WithLabelValues("missing").Inc()
+			writeJSONError(w, http.StatusBadRequest, "missing-replay-headers", "X-Rosa-Request-Timestamp and X-Rosa-Request-Nonce are required")
+			return
+		}
+		seconds, err := strconv.ParseInt(rawTimestamp, 10, 64)
+		if err != nil {
+			replayRejectedRequests.WithLabelValues("invalid").Inc()
+			writeJSONError(w, http.StatusBadRequest, "invalid-request-timestamp", "X-Rosa-Request-Timestamp must be a Unix time in seconds")
+			return
+		}
+		if len(nonce) > maxNonceLength {
+			replayRejectedRequests.WithLabelValues("invalid").Inc()
+			writeJSONError(w, http.StatusBadRequest, "invalid-request-nonce", "X-Rosa-Request-Nonce must be at most 128 characters")
+			return
+		}
+
+		now := rp.now()
+		if skew := now.Sub(time.Unix(seconds, 0)); skew > rp.window || skew < -rp.window {
+			replayRejectedRequests.WithLabelValues("stale").Inc()
+			rp.logger.Warn("rejected request outside the replay window", "account_id", accountID, "skew", skew)
+			writeJSONError(w, http.StatusForbidden, "stale-request", "Request timestamp is outside the accepted window")
+			return
+		}
+
+		// A nonce has to be remembered for as long as a timestamp sent with it
+		// can still be accepted, which is at most twice the window from now
+		switch result, retryAfter := rp.remember(accountID, nonce, now, now.Add(2*rp.window)); result {
+		case nonceReplayed:
+			replayRejectedRequests.WithLabelValues("replayed").Inc()
+			rp.logger.Warn("rejected replayed request", "account_id", accountID, "method", r.Method, "path", r.URL.Path)
+			writeJSONError(w, http.StatusForbidden, "replayed-request", "Request nonce has already been used")
+			return
+		case nonceCacheFull:
+			replayRejectedRequests.WithLabelValues("cache_full").Inc()
+			rp.logger.Warn("replay cache is full, rejecting request", "account_id", accountID)
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			writeJSONError(w, http.StatusServiceUnavailable, "replay-cache-full", "Too many recent requests to check for replay, retry later")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+type nonceResult int
+
+const (
+	nonceAccepted nonceResult = iota
+	nonceReplayed
+	nonceCacheFull
+)
+
+// remember records an account's nonce until expiresAt. When the account's
+// cache is full it also returns how long until its oldest nonce is forgotten.
+func (rp *ReplayProtection) remember(accountID, nonce string, now, expiresAt time.Time) (nonceResult, time.Duration) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if !now.Before(rp.nextSweep) {
+		for id, cache := range rp.accounts {
+			cache.expire(now)
+			if cache.len() == 0 {
+				delete(rp.accounts, id)
+			}
+		}
+		rp.nextSweep = now.Add(rp.window)
+	}
+
+	cache, ok := rp.accounts[accountID]
+	if !ok {
+		cache = newNonceCache(rp.maxNonces)
+		rp.accounts[accountID] = cache
+	}
+	result := cache.add(nonce, now, expiresAt)
+	if result == nonceCacheFull {
+		return result, cache.nextExpiry(now)
+	}
+	return result, 0
+}
+
+type nonceEntry struct {
+	nonce     string
+	expiresAt time.Time
+}
+
+// nonceCache remembers nonces until they expire. Every nonce is kept for the
+// same duration, so the insertion order is also the expiry order: expired
+// nonces are dropped from the head of order, which is compacted once most of
+// it has expired.
+type nonceCache struct {
+	max   int
+	seen  map[string]struct{}
+	order []nonceEntry
+	head  int
+}
+
+func newNonceCache(max int) *nonceCache {
+	return &nonceCache{max: max, seen: make(map[string]struct{})}
+}
+
+// add remembers nonce until expiresAt unless it is already remembered or the
+// cache is full
+func (c *nonceCache) add(nonce string, now, expiresAt time.Time) nonceResult {
+	c.expire(now)
+	if _, ok := c.seen[nonce]; ok {
+		return nonceReplayed
+	}
+	if c.len() >= c.max {
+		return nonceCacheFull
+	}
+	c.seen[nonce] = struct{}{}
+	c.order = append(c.order, nonceEntry{nonce: nonce, expiresAt: expiresAt})
+	return nonceAccepted
+}
+
+func (c *nonceCache) len() int {
+	return len(c.order) - c.head
+}
+
+// nextExpiry returns how long until the oldest nonce is forgotten
+func (c *nonceCache) nextExpiry(now time.Time) time.Duration {
+	if c.len() == 0 {
+		return 0
+	}
+	return c.order[c.head].expiresAt.Sub(now)
+}
+
+func (c *nonceCache) expire(now time.Time) {
+	for c.head < len(c.order) && !c.order[c.head].expiresAt.After(now) {
+		delete(c.seen, c.order[c.head].nonce)
+		c.order[c.head] = nonceEntry{}
+		c.head++
+	}
+	switch {
+	case c.head == len(c.order):
+		c.order = c.order[:0]
+		c.head = 0
+	case c.head > len(c.order)/2:
+		n := copy(c.order, c.order[c.head:])
+		clear(c.order[n:])
+		c.order = c.order[:n]
+		c.head = 0
+	}
+}
@@ -0,0 +1,129 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestReplayProtection_Check(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	timestamp := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+
+	rp := NewReplayProtection(5*time.Minute, 2, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	rp.now = func() time.Time { return now }
+	handler := rp.Check(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := []struct {
+		name       string
+		method     string
+		accountID  string
+		timestamp  string
+		nonce      string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "fresh request", method: http.MethodPost, accountID: "123456789012", timestamp: timestamp(0), nonce: "n1", wantStatus: http.StatusNoContent},
+		{name: "replayed nonce", method: http.MethodDelete, accountID: "123456789012", timestamp: timestamp(time.Minute), nonce: "n1", wantStatus: http.StatusForbidden, wantCode: "replayed-request"},
+		{name: "read without headers", method: http.MethodGet, accountID: "123456789012", wantStatus: http.StatusNoContent},
+		{name: "no identity", method: http.MethodPost, wantStatus: http.StatusNoContent},
+		{name: "missing nonce", method: http.MethodPut, accountID: "123456789012", timestamp: timestamp(0), wantStatus: http.StatusBadRequest, wantCode: "missing-replay-headers"},
+		{name: "timestamp not Unix seconds", method: http.MethodPost, accountID: "123456789012", timestamp: now.Format(time.RFC3339), nonce: "n2", wantStatus: http.StatusBadRequest, wantCode: "invalid-request-timestamp"},
+		{name: "stale timestamp", method: http.MethodPost, accountID: "123456789012", timestamp: timestamp(-6 * time.Minute), nonce: "n2", wantStatus: http.StatusForbidden, wantCode: "stale-request"},
+		{name: "future timestamp", method: http.MethodPost, accountID: "123456789012", timestamp: timestamp(6 * time.Minute), nonce: "n2", wantStatus: http.StatusForbidden, wantCode: "stale-request"},
+		{name: "second nonce", method: http.MethodPatch, accountID: "123456789012", timestamp: timestamp(-time.Minute), nonce: "n2", wantStatus: http.StatusNoContent},
+		{name: "cache full", method: http.MethodPost, accountID: "123456789012", timestamp: timestamp(0), nonce: "n3", wantStatus: http.StatusServiceUnavailable, wantCode: "replay-cache-full"},
+		{name: "other account unaffected by a full cache", method: http.MethodPost, accountID: "210987654321", timestamp: timestamp(0), nonce: "n3", wantStatus: http.StatusNoContent},
+		{name: "other account replayed nonce", method: http.MethodPost, accountID: "210987654321", timestamp: timestamp(0), nonce: "n3", wantStatus: http.StatusForbidden, wantCode: "replayed-request"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v0/clusters", nil)
+			if tt.accountID != "" {
+				req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, tt.accountID))
+			}
+			if tt.timestamp != "" {
+				req.Header.Set(HeaderRequestTimestamp, tt.timestamp)
+			}
+			if tt.nonce != "" {
+				req.Header.Set(HeaderRequestNonce, tt.nonce)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rr.Code, rr.Body.String())
+			}
+			if tt.wantCode != "" {
+				var errorResp map[string]interface{}
+				if err := json.Unmarshal(rr.Body.Bytes(), &errorResp); err != nil {
+					t.Fatalf("failed to decode error response: %v", err)
+				}
+				if errorResp["code"] != tt.wantCode {
+					t.Errorf("expected code=%s, got %v", tt.wantCode, errorResp["code"])
+				}
+			}
+		})
+	}
+
+	// Once the first nonces expire they can be used again and the cache has room
+	now = now.Add(10*time.Minute + time.Second)
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/clusters", nil)
+	req = req.WithContext(context.WithValue(req.Context(), ContextKeyAccountID, "123456789012"))
+	req.Header.Set(HeaderRequestTimestamp, timestamp(0))
+	req.Header.Set(HeaderRequestNonce, "n1")
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	if rr.Code != http.StatusNoContent {
+		t.Errorf("expected an expired nonce to be accepted, got %d: %s", rr.Code, rr.Body.String())
+	}
+}
+
+func TestNonceCache_Expire(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	c := newNonceCache(10)
+	for i := 0; i < 10; i++ {
+		c.add(strconv.Itoa(i), now, now.Add(time.Duration(i+1)*time.Second))
+	}
+
+	// Expired nonces are dropped from the head without moving the rest
+	c.expire(now.Add(3 * time.Second))
+	if c.len() != 7 || c.head != 3 {
+		t.Fatalf("expected 7 nonces after a head of 3, got %d after %d", c.len(), c.head)
+	}
+	if c.add("0", now, now.Add(time.Minute)) != nonceAccepted {
+		t.Error("expected an expired nonce to be accepted again")
+	}
+	if c.add("5", now, now.Add(time.Minute)) != nonceReplayed {
+		t.Error("expected a remembered nonce to be replayed")
+	}
+
+	// Once most have expired the rest move to the front
+	c.expire(now.Add(7 * time.Second))
+	if c.head != 0 || c.len() != 4 || c.order[0].nonce != "7" {
+		t.Errorf("expected the 4 remaining nonces compacted, got head %d and %v", c.head, c.order)
+	}
+	if got := c.nextExpiry(now.Add(7 * time.Second)); got != time.Second {
+		t.Errorf("expected the next expiry in 1s, got %s", got)
+	}
+}
+
+func TestReplayProtection_DropsIdleAccounts(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	rp := NewReplayProtection(time.Minute, 10, slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	rp.remember("123456789012", "n1", now, now.Add(2*time.Minute))
+	rp.remember("210987654321", "n1", now.Add(2*time.Minute), now.Add(4*time.Minute))
+	if len(rp.accounts) != 1 {
+		t.Errorf("expected the idle account's expired cache to be dropped, got %d accounts", len(rp.accounts))
+	}
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+// HeaderResponseRequestID is the response header carrying the request ID
+const HeaderResponseRequestID = "X-Request-Id"
+
+// RequestIDs assigns every request an ID for correlation. The ID API Gateway
+// assigned the request is kept, so the ID a customer reports matches both the
+// gateway's access logs and the service logs; otherwise a UUIDv7 is
+// generated, which sorts by the time the request arrived.
+type RequestIDs struct {
+	header         string
+	requestContext bool
+}
+
+// NewRequestIDs creates a new RequestIDs reading the gateway request ID from
+// the header named in cfg and, when cfg enables it, the API Gateway v2
+// request context
+func NewRequestIDs(cfg IdentityConfig) *RequestIDs {
+	return &RequestIDs{header: cfg.Headers.RequestID, requestContext: cfg.RequestContext}
+}
+
+// RequestID assigns request IDs with the default identity headers
+func RequestID(next http.Handler) http.Handler {
+	return NewRequestIDs(DefaultIdentityConfig()).Assign(next)
+}
+
+// Assign adds the request ID to the request context and echoes it in the
+// X-Request-Id response header. It runs before any middleware that can
+// reject the request, so error responses carry the header too.
+func (g *RequestIDs) Assign(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := GetRequestID(r.Context())
+		if id == "" {
+			id = g.gatewayID(r)
+			if id == "" {
+				id = newRequestID()
+			}
+			r = r.WithContext(context.WithValue(r.Context(), ContextKeyRequestID, id))
+		}
+		w.Header().Set(HeaderResponseRequestID, id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// gatewayID returns the ID API Gateway assigned the request, or "". The
+// request context takes precedence over the header, as it does for identity.
+func (g *RequestIDs) gatewayID(r *http.Request) string {
+	if g.requestContext {
+		if raw := r.Header.Get(HeaderRequestContext); raw != "" {
+			var rc apiGatewayV2RequestContext
+			// A malformed request context is rejected by the identity
+			// middleware, with a generated ID
+			if err := json.Unmarshal([]byte(raw), &rc); err == nil && rc.RequestID != "" {
+				return rc.RequestID
+			}
+		}
+	}
+	if g.header == "" {
+		return ""
+	}
+	return r.Header.Get(g.header)
+}
+
+// newRequestID generates a UUIDv7, falling back to a random UUID should the
+// clock or random source fail
+func newRequestID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	t.Run("keeps gateway request ID", func(t *testing.T) {
+		var got string
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = GetRequestID(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req = req.WithContext(context.WithValue(req.Context(), ContextKeyRequestID, "gw-123"))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		if got != "gw-123" {
+			t.Errorf("expected request ID gw-123, got %q", got)
+		}
+		if h := w.Header().Get(HeaderResponseRequestID); h != "gw-123" {
+			t.Errorf("expected response header gw-123, got %q", h)
+		}
+	})
+
+	t.Run("generates request ID when missing", func(t *testing.T) {
+		var got string
+		handler := RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = GetRequestID(r.Context())
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		if got == "" {
+			t.Fatal("expected a generated request ID")
+		}
+		if h := w.Header().Get(HeaderResponseRequestID); h != got {
+			t.Errorf("expected response header %q, got %q", got, h)
+		}
+	})
+
+	t.Run("generates a UUIDv7", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		RequestID(http.NotFoundHandler()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		id, err := uuid.Parse(w.Header().Get(HeaderResponseRequestID))
+		if err != nil {
+			t.Fatalf("expected a UUID request ID: %v", err)
+		}
+		if id.Version() != 7 {
+			t.Errorf("expected a version 7 UUID, got version %d", id.Version())
+		}
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected the handler's status, got %d", w.Code)
+		}
+	})
+}
+
+func TestRequestIDs_GatewayID(t *testing.T) {
+	cfg := DefaultIdentityConfig()
+	cfg.RequestContext = true
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    string
+	}{
+		{
+			name:    "request ID header",
+			headers: map[string]string{HeaderRequestID: "hdr-1"},
+			want:    "hdr-1",
+		},
+		{
+			name: "request context takes precedence",
+			headers: map[string]string{
+				HeaderRequestID:      "hdr-1",
+				HeaderRequestContext: `{"requestId":"ctx-1"}`,
+			},
+			want: "ctx-1",
+		},
+		{
+			name: "malformed request context falls back to the header",
+			headers: map[string]string{
+				HeaderRequestID:      "hdr-1",
+				HeaderRequestContext: `{`,
+			},
+			want: "hdr-1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := NewRequestIDs(cfg).Assign(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = GetRequestID(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if got != tt.want {
+				t.Errorf("expected request ID %q, got %q", tt.want, got)
+			}
+			if h := w.Header().Get(HeaderResponseRequestID); h != tt.want {
+				t.Errorf("expected response header %q, got %q", tt.want, h)
+			}
+		})
+	}
+}
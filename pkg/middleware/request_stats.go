@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/status"
+)
+
+// RequestStats records the outcome of every API request into a status window
+type RequestStats struct {
+	window *status.Window
+}
+
+// NewRequestStats creates a new RequestStats middleware
+func NewRequestStats(window *status.Window) *RequestStats {
+	return &RequestStats{window: window}
+}
+
+// Track records the duration of each request and counts 5xx responses as errors
+func (s *RequestStats) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		s.window.Record(time.Since(start), rec.status >= http.StatusInternalServerError)
+	})
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, so
+// streaming handlers can flush through the wrapper
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/status"
+)
+
+func TestRequestStats_Track(t *testing.T) {
+	window := status.NewWindow(time.Minute)
+	stats := NewRequestStats(window)
+
+	codes := []int{http.StatusOK, http.StatusNotFound, http.StatusInternalServerError, http.StatusBadGateway}
+	for _, code := range codes {
+		handler := stats.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(code)
+		}))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		if w.Code != code {
+			t.Errorf("expected status %d to be passed through, got %d", code, w.Code)
+		}
+	}
+
+	// Handlers that never call WriteHeader respond 200
+	handler := stats.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	got := window.Stats()
+	if got.Count != 5 {
+		t.Errorf("expected 5 requests recorded, got %d", got.Count)
+	}
+	if got.Errors != 2 {
+		t.Errorf("expected 2 errors recorded, got %d", got.Errors)
+	}
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Request tags are the tags a request asks to put on the resource it
+// creates or changes. They are sent as one header or query parameter per tag:
+//
+//	X-Rosa-Request-Tag-Cost-Center: eng
+//	?tag.cost-center=eng
+//
+// HTTP header names are case-insensitive, so keys taken from headers are
+// lowercased; a query parameter keeps the key exactly as written.
+const (
+	HeaderRequestTagPrefix = "X-Rosa-Request-Tag-"
+	QueryRequestTagPrefix  = "tag."
+)
+
+// Limits on request tags, matching AWS resource tags
+const (
+	maxRequestTags        = 50
+	maxRequestTagKeyLen   = 128
+	maxRequestTagValueLen = 256
+)
+
+var requestTagChars = regexp.MustCompile(`^[\p{L}\p{Z}\p{N}_.:/=+\-@]*$`)
+
+// ParseRequestTags collects and validates the request's tags. A key given
+// twice must have the same value both times. The Authz middleware has
+// already rejected requests with invalid tags when authorization is enabled.
+func ParseRequestTags(r *http.Request) (map[string]string, error) {
+	tags := make(map[string]string)
+	add := func(key, value string) error {
+		if err := validateRequestTag(key, value); err != nil {
+			return err
+		}
+		if existing, ok := tags[key]; ok && existing != value {
+			return fmt.Errorf("request tag %q is given conflicting values", key)
+		}
+		tags[key] = value
+		return nil
+	}
+
+	for name, values := range r.Header {
+		if len(name) <= len(HeaderRequestTagPrefix) || !strings.EqualFold(name[:len(HeaderRequestTagPrefix)], HeaderRequestTagPrefix) {
+			continue
+		}
+		key := strings.ToLower(name[len(HeaderRequestTagPrefix):])
+		for _, value := range values {
+			if err := add(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for name, values := range r.URL.Query() {
+		if !strings.HasPrefix(name, QueryRequestTagPrefix) {
+			continue
+		}
+		key := strings.TrimPrefix(name, QueryRequestTagPrefix)
+		for _, value := range values {
+			if err := add(key, value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(tags) > maxRequestTags {
+		return nil, fmt.Errorf("at most %d request tags are allowed, got %d", maxRequestTags, len(tags))
+	}
+	return tags, nil
+}
+
+// ValidateRequestTagKey checks that key is usable as a request tag key
+func ValidateRequestTagKey(key string) error {
+	return validateRequestTag(key, "")
+}
+
+func validateRequestTag(key, value string) error {
+	switch {
+	case key == "":
+		return fmt.Errorf("request tag key must not be empty")
+	case utf8.RuneCountInString(key) > maxRequestTagKeyLen:
+		return fmt.Errorf("request tag key %q is longer than %d characters", key, maxRequestTagKeyLen)
+	case strings.HasPrefix(strings.ToLower(key), "aws:"):
+		return fmt.Errorf("request tag key %q uses the reserved aws: prefix", key)
+	case !requestTagChars.MatchString(key):
+		return fmt.Errorf("request tag key %q contains invalid characters", key)
+	case utf8.RuneCountInString(value) > maxRequestTagValueLen:
+		return fmt.Errorf("value of request tag %q is longer than %d characters", key, maxRequestTagValueLen)
+	case !requestTagChars.MatchString(value):
+		return fmt.Errorf("value of request tag %q contains invalid characters", key)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+func TestParseRequestTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "no tags",
+			target: "/api/v0/clusters?limit=10",
+			want:   map[string]string{},
+		},
+		{
+			name:    "header keys are lowercased",
+			target:  "/api/v0/clusters",
+			headers: map[string]string{"X-Rosa-Request-Tag-Cost-Center": "eng"},
+			want:    map[string]string{"cost-center": "eng"},
+		},
+		{
+			name:   "query keys keep their case",
+			target: "/api/v0/clusters?tag.CostCenter=eng&tag.env=",
+			want:   map[string]string{"CostCenter": "eng", "env": ""},
+		},
+		{
+			name:    "header and query agree",
+			target:  "/api/v0/clusters?tag.env=dev",
+			headers: map[string]string{"X-Rosa-Request-Tag-Env": "dev"},
+			want:    map[string]string{"env": "dev"},
+		},
+		{
+			name:    "conflicting values",
+			target:  "/api/v0/clusters?tag.env=prod",
+			headers: map[string]string{"X-Rosa-Request-Tag-Env": "dev"},
+			wantErr: true,
+		},
+		{
+			name:    "reserved prefix",
+			target:  "/api/v0/clusters?tag.aws:createdBy=me",
+			wantErr: true,
+		},
+		{
+			name:    "invalid characters",
+			target:  "/api/v0/clusters?tag.env=%3Cscript%3E",
+			wantErr: true,
+		},
+		{
+			name:    "empty key",
+			target:  "/api/v0/clusters?tag.=x",
+			wantErr: true,
+		},
+		{
+			name:    "value too long",
+			target:  "/api/v0/clusters?tag.env=" + strings.Repeat("a", 257),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			got, err := ParseRequestTags(req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRequestTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("tag %q = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthz_RequestTags(t *testing.T) {
+	var gotTags map[string]string
+	checker := &mockChecker{
+		authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+			gotTags = req.RequestTags
+			return true, nil
+		},
+	}
+	handler := NewAuthz(checker, true, "us-east-1", slog.New(slog.NewTextHandler(os.Stdout, nil))).Authorize(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+
+	send := func(target string) int {
+		req := httptest.NewRequest(http.MethodPost, target, nil)
+		ctx := context.WithValue(req.Context(), ContextKeyAccountID, "123456789012")
+		ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::123456789012:user/alice")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+		return w.Code
+	}
+
+	if code := send("/api/v0/clusters?tag.team=payments"); code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", code)
+	}
+	if gotTags["team"] != "payments" {
+		t.Errorf("expected the team request tag to reach the checker, got %v", gotTags)
+	}
+
+	if code := send("/api/v0/clusters?tag.aws:team=payments"); code != http.StatusBadRequest {
+		t.Errorf("expected status 400 for an invalid tag, got %d", code)
+	}
+}
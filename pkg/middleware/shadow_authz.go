@@ -0,0 +1,264 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+const (
+	// shadowConcurrency bounds the Cedar decisions evaluated at once; requests
+	// beyond it are not evaluated rather than queued
+	shadowConcurrency = 64
+	// shadowTimeout bounds one Cedar decision, which outlives its request
+	shadowTimeout = 10 * time.Second
+	// maxShadowAccounts bounds the accounts tallied in memory; decisions of
+	// accounts beyond it are only logged and counted in the metrics
+	maxShadowAccounts = 10000
+)
+
+// Outcomes of a shadow decision, comparing the enforced allow-list decision
+// with the Cedar decision
+const (
+	ShadowAgreed = "agreed"
+	// ShadowCedarDenied is a request the allow-list allowed and Cedar would
+	// deny
+	ShadowCedarDenied = "cedar_denied"
+	// ShadowCedarAllowed is a request the allow-list denied and Cedar would
+	// allow
+	ShadowCedarAllowed = "cedar_allowed"
+	// ShadowNotProvisioned is a request the allow-list allowed from an
+	// account not provisioned for Cedar authorization
+	ShadowNotProvisioned = "not_provisioned"
+	// ShadowError is a request whose Cedar decision could not be made
+	ShadowError = "error"
+)
+
+var (
+	shadowDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_authz_shadow_decisions_total",
+		Help: "Requests evaluated by Cedar in shadow mode, by outcome (agreed, cedar_denied, cedar_allowed, not_provisioned, error).",
+	}, []string{"outcome"})
+	shadowSkipped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "rosa_authz_shadow_skipped_total",
+		Help: "Requests not evaluated by Cedar in shadow mode because too many evaluations were in flight.",
+	})
+)
+
+// ShadowAccount tallies the shadow decisions of an account's requests
+type ShadowAccount struct {
+	AccountID      string `json:"accountId"`
+	Requests       int64  `json:"requests"`
+	Agreed         int64  `json:"agreed"`
+	CedarDenied    int64  `json:"cedarDenied"`
+	CedarAllowed   int64  `json:"cedarAllowed"`
+	NotProvisioned int64  `json:"notProvisioned"`
+	Errors         int64  `json:"errors"`
+	// LastDivergence is when a request last diverged, in RFC 3339
+	LastDivergence string `json:"lastDivergence,omitempty"`
+}
+
+// Diverged counts the requests whose decisions differed
+func (s *ShadowAccount) Diverged() int64 {
+	return s.CedarDenied + s.CedarAllowed + s.NotProvisioned
+}
+
+// ShadowAuthz evaluates the Cedar decision of requests whose enforced
+// decision is the legacy allow-list's, logging and counting where the two
+// differ. The Cedar decision is made after the request has moved on, so it
+// adds no latency and cannot change the response.
+type ShadowAuthz struct {
+	allowlist *Authorization
+	authz     *Authz
+	logger    *slog.Logger
+	inFlight  chan struct{}
+	wg        sync.WaitGroup
+	now       func() time.Time
+
+	mu       sync.Mutex
+	accounts map[string]*ShadowAccount
+}
+
+// NewShadowAuthz creates a ShadowAuthz middleware comparing the allow-list
+// with the decisions of authz
+func NewShadowAuthz(allowlist *Authorization, authz *Authz, logger *slog.Logger) *ShadowAuthz {
+	return &ShadowAuthz{
+		allowlist: allowlist,
+		authz:     authz,
+		logger:    logger,
+		inFlight:  make(chan struct{}, shadowConcurrency),
+		now:       time.Now,
+		accounts:  make(map[string]*ShadowAccount),
+	}
+}
+
+// Evaluate starts the Cedar decision of the request and passes it on. It
+// enforces nothing: it runs before RequireAllowedAccount, so the requests the
+// allow-list denies are compared too.
+func (s *ShadowAuthz) Evaluate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		accountID := GetAccountID(ctx)
+		callerARN := GetCallerARN(ctx)
+		if accountID == "" || callerARN == "" {
+			// Both deny these
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		legacy := s.allowlist.Allowed(accountID)
+		req := s.authz.buildAuthzRequest(r, accountID, callerARN)
+		requestTags, tagsErr := ParseRequestTags(r)
+		req.RequestTags = requestTags
+
+		select {
+		case s.inFlight <- struct{}{}:
+			s.wg.Add(1)
+			go func() {
+				defer s.wg.Done()
+				defer func() { <-s.inFlight }()
+				ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shadowTimeout)
+				defer cancel()
+				err := tagsErr
+				var cedar bool
+				if err == nil {
+					cedar, err = s.decide(ctx, req)
+				}
+				s.record(req, legacy, cedar, err)
+			}()
+		default:
+			shadowSkipped.Inc()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// decide makes the Cedar decision Authz.Authorize would, for callers
+// without a delegation or API key, which the allow-list does not know
+func (s *ShadowAuthz) decide(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+	privileged, err := s.authz.authorizer.IsPrivileged(ctx, req.AccountID)
+	if err != nil {
+		return false, err
+	}
+	if privileged {
+		if s.authz.guardrails != nil {
+			if err := s.authz.guardrails.CheckGuardrails(ctx, req); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	}
+	return s.authz.authorizer.Authorize(ctx, req)
+}
+
+// record compares the decisions of a request, and logs and counts the
+// outcome
+func (s *ShadowAuthz) record(req *authz.AuthzRequest, legacy, cedar bool, err error) {
+	// Errors Authorize turns into denials are decisions
+	if errors.Is(err, authz.ErrGuardrailDenied) || errors.Is(err, authz.ErrResourceAccountMismatch) {
+		cedar, err = false, nil
+	}
+	outcome := shadowOutcome(legacy, cedar, err)
+	shadowDecisions.WithLabelValues(outcome).Inc()
+
+	switch outcome {
+	case ShadowAgreed:
+	case ShadowError:
+		s.logger.Warn("shadow authorization check failed",
+			"error", err, "account_id", req.AccountID, "caller_arn", req.CallerARN, "action", req.Action)
+	default:
+		s.logger.Info("shadow authorization diverged",
+			"outcome", outcome,
+			"account_id", req.AccountID,
+			"caller_arn", req.CallerARN,
+			"action", req.Action,
+			"resource", req.Resource,
+			"legacy_allowed", legacy,
+			"cedar_allowed", cedar,
+		)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	account, ok := s.accounts[req.AccountID]
+	if !ok {
+		if len(s.accounts) >= maxShadowAccounts {
+			return
+		}
+		account = &ShadowAccount{AccountID: req.AccountID}
+		s.accounts[req.AccountID] = account
+	}
+	account.Requests++
+	switch outcome {
+	case ShadowAgreed:
+		account.Agreed++
+	case ShadowCedarDenied:
+		account.CedarDenied++
+	case ShadowCedarAllowed:
+		account.CedarAllowed++
+	case ShadowNotProvisioned:
+		account.NotProvisioned++
+	case ShadowError:
+		account.Errors++
+	}
+	if outcome != ShadowAgreed && outcome != ShadowError {
+		account.LastDivergence = clock.Format(s.now())
+	}
+}
+
+// shadowOutcome compares the allow-list decision with the Cedar decision or
+// the error that prevented it
+func shadowOutcome(legacy, cedar bool, err error) string {
+	switch {
+	case err != nil && strings.Contains(err.Error(), "not provisioned"):
+		// Cedar denies unprovisioned accounts, as the allow-list denies
+		// unlisted ones
+		if legacy {
+			return ShadowNotProvisioned
+		}
+		return ShadowAgreed
+	case err != nil:
+		return ShadowError
+	case legacy == cedar:
+		return ShadowAgreed
+	case legacy:
+		return ShadowCedarDenied
+	default:
+		return ShadowCedarAllowed
+	}
+}
+
+// Report returns the tally of each account, those with the most diverging
+// requests first
+func (s *ShadowAuthz) Report() []ShadowAccount {
+	s.mu.Lock()
+	report := make([]ShadowAccount, 0, len(s.accounts))
+	for _, account := range s.accounts {
+		report = append(report, *account)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(report, func(i, j int) bool {
+		if di, dj := report[i].Diverged(), report[j].Diverged(); di != dj {
+			return di > dj
+		}
+		return report[i].AccountID < report[j].AccountID
+	})
+	return report
+}
+
+// Wait waits for the Cedar decisions in flight
+func (s *ShadowAuthz) Wait() {
+	s.wg.Wait()
+}
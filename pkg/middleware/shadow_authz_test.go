@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+func TestShadowAuthz(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	// Cedar allows 111111111111 and 333333333333, denies 222222222222 and
+	// has not provisioned 444444444444 and 555555555555; 666666666666 is
+	// privileged
+	checker := &mockChecker{
+		isPrivilegedFn: func(ctx context.Context, accountID string) (bool, error) {
+			return accountID == "666666666666", nil
+		},
+		authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+			switch req.AccountID {
+			case "111111111111", "333333333333":
+				return true, nil
+			case "444444444444", "555555555555":
+				return false, fmt.Errorf("account %s not provisioned", req.AccountID)
+			case "777777777777":
+				return false, errors.New("AVP unavailable")
+			}
+			return false, nil
+		},
+	}
+	allowlist := NewAuthorization([]string{"111111111111", "222222222222", "444444444444", "666666666666", "777777777777"}, logger)
+	shadow := NewShadowAuthz(allowlist, NewAuthz(checker, true, "us-east-1", logger), logger)
+	handler := shadow.Evaluate(allowlist.RequireAllowedAccount(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		accountID string
+		status    int
+		outcome   string
+	}{
+		{"111111111111", http.StatusOK, ShadowAgreed},
+		{"222222222222", http.StatusOK, ShadowCedarDenied},
+		{"333333333333", http.StatusForbidden, ShadowCedarAllowed},
+		{"444444444444", http.StatusOK, ShadowNotProvisioned},
+		{"555555555555", http.StatusForbidden, ShadowAgreed},
+		{"666666666666", http.StatusOK, ShadowAgreed},
+		{"777777777777", http.StatusOK, ShadowError},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+		ctx := context.WithValue(req.Context(), ContextKeyAccountID, tt.accountID)
+		ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::"+tt.accountID+":role/operator")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req.WithContext(ctx))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected the allow-list to answer %d, got %d", tt.accountID, tt.status, w.Code)
+		}
+	}
+	shadow.Wait()
+
+	report := shadow.Report()
+	if len(report) != len(tests) {
+		t.Fatalf("expected %d accounts in the report, got %+v", len(tests), report)
+	}
+	byAccount := make(map[string]ShadowAccount, len(report))
+	for _, account := range report {
+		byAccount[account.AccountID] = account
+	}
+	for _, tt := range tests {
+		account := byAccount[tt.accountID]
+		counts := map[string]int64{
+			ShadowAgreed:         account.Agreed,
+			ShadowCedarDenied:    account.CedarDenied,
+			ShadowCedarAllowed:   account.CedarAllowed,
+			ShadowNotProvisioned: account.NotProvisioned,
+			ShadowError:          account.Errors,
+		}
+		if account.Requests != 1 || counts[tt.outcome] != 1 {
+			t.Errorf("%s: expected one %s request, got %+v", tt.accountID, tt.outcome, account)
+		}
+		if diverged := account.LastDivergence != ""; diverged != (account.Diverged() > 0) {
+			t.Errorf("%s: unexpected last divergence %q", tt.accountID, account.LastDivergence)
+		}
+	}
+	// Diverging accounts come first
+	for i, account := range report[:3] {
+		if account.Diverged() == 0 {
+			t.Errorf("report[%d]: expected a diverging account, got %+v", i, account)
+		}
+	}
+}
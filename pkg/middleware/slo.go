@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/slo"
+)
+
+// SLO records the outcome of every API request against the SLOs of its route
+// class. Classes are those of SlowRequests, whose thresholds double as the
+// latency SLI thresholds.
+type SLO struct {
+	tracker  *slo.Tracker
+	classes  []RouteClass
+	fallback time.Duration
+}
+
+// NewSLO creates a new SLO middleware. Requests matching no class use the
+// fallback threshold.
+func NewSLO(tracker *slo.Tracker, classes []RouteClass, fallback time.Duration) *SLO {
+	return &SLO{tracker: tracker, classes: classes, fallback: fallback}
+}
+
+// Track records the status and duration of each request
+func (s *SLO) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		class, threshold := classifyRoute(s.classes, s.fallback, r.URL.Path)
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		s.tracker.Record(class, threshold, time.Since(start), rec.status)
+	})
+}
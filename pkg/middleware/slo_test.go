@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/slo"
+)
+
+func TestSLO_Track(t *testing.T) {
+	tracker := slo.NewTracker(slo.Config{Period: 24 * time.Hour, Availability: 0.999, Latency: 0.99})
+	mw := NewSLO(tracker, []RouteClass{
+		{Name: "tenant", Prefixes: []string{"/api/v0/clusters"}, Threshold: time.Hour},
+	}, 0)
+
+	for _, tc := range []struct {
+		path string
+		code int
+	}{
+		{path: "/api/v0/clusters", code: http.StatusOK},
+		{path: "/api/v0/clusters/abc", code: http.StatusBadGateway},
+		{path: "/api/v0/live", code: http.StatusInternalServerError},
+	} {
+		handler := mw.Track(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.code)
+		}))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.path, nil))
+		if w.Code != tc.code {
+			t.Errorf("expected status %d to be passed through, got %d", tc.code, w.Code)
+		}
+	}
+
+	report := tracker.Report()
+	if len(report.Classes) != 2 {
+		t.Fatalf("expected 2 classes, got %+v", report.Classes)
+	}
+	byName := map[string]slo.ClassReport{}
+	for _, c := range report.Classes {
+		byName[c.Class] = c
+	}
+
+	tenant := byName["tenant"]
+	period := tenant.Availability.Windows[len(tenant.Availability.Windows)-1]
+	if period.Total != 2 || period.Bad != 1 {
+		t.Errorf("expected 1 of 2 tenant requests bad, got %d of %d", period.Bad, period.Total)
+	}
+	if tenant.Latency == nil || tenant.Latency.Windows[0].Bad != 0 {
+		t.Errorf("expected tenant requests within their latency threshold, got %+v", tenant.Latency)
+	}
+
+	// The default class has no threshold, so it has no latency SLI
+	def := byName[RouteClassDefault]
+	if def.Latency != nil {
+		t.Errorf("expected no latency SLI without a threshold, got %+v", def.Latency)
+	}
+	if def.Availability.Windows[0].Bad != 1 {
+		t.Errorf("expected the 500 to count against availability, got %+v", def.Availability.Windows[0])
+	}
+}
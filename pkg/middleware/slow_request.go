@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
+)
+
+// RouteClassDefault is the class of requests that match no RouteClass
+const RouteClassDefault = "default"
+
+var slowRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_api_slow_requests_total",
+	Help: "API requests that exceeded the slow-request threshold of their route class.",
+}, []string{"class"})
+
+// RouteClass groups the routes under a set of path prefixes that share a
+// slow-request threshold
+type RouteClass struct {
+	Name     string
+	Prefixes []string
+	// Threshold is the latency above which a request is slow; 0 disables
+	// slow-request reporting for the class
+	Threshold time.Duration
+}
+
+// SlowRequests logs a warning and counts a metric for each request that takes
+// longer than the threshold of its route class. The warning breaks the
+// latency down by upstream so the slow dependency can be identified.
+type SlowRequests struct {
+	classes  []RouteClass
+	fallback time.Duration
+	logger   *slog.Logger
+}
+
+// NewSlowRequests creates a new SlowRequests middleware. Requests matching no
+// class use the fallback threshold. Classes are matched in order, so more
+// specific prefixes must come first.
+func NewSlowRequests(classes []RouteClass, fallback time.Duration, logger *slog.Logger) *SlowRequests {
+	return &SlowRequests{classes: classes, fallback: fallback, logger: logger}
+}
+
+// Track times each request, collecting upstream call durations in its context
+func (s *SlowRequests) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		class, threshold := classifyRoute(s.classes, s.fallback, r.URL.Path)
+		if threshold <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, timings := upstream.WithTimings(r.Context())
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		elapsed := time.Since(start)
+		if elapsed <= threshold {
+			return
+		}
+
+		slowRequests.WithLabelValues(class).Inc()
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"route_class", class,
+			"duration_ms", elapsed.Milliseconds(),
+			"threshold_ms", threshold.Milliseconds(),
+			"request_id", GetRequestID(ctx),
+		}
+		for _, name := range []string{upstream.Maestro, upstream.AVP, upstream.DynamoDB} {
+			d, calls := timings.Total(name)
+			attrs = append(attrs, name+"_ms", d.Milliseconds(), name+"_calls", calls)
+		}
+		s.logger.Warn("slow request", attrs...)
+	})
+}
+
+// classifyRoute returns the class and threshold for a request path
+func classifyRoute(classes []RouteClass, fallback time.Duration, path string) (string, time.Duration) {
+	for _, class := range classes {
+		for _, prefix := range class.Prefixes {
+			if strings.HasPrefix(path, prefix) {
+				return class.Name, class.Threshold
+			}
+		}
+	}
+	return RouteClassDefault, fallback
+}
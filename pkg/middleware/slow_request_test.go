@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
+)
+
+func TestSlowRequests_Track(t *testing.T) {
+	classes := []RouteClass{
+		{Name: "tenant", Prefixes: []string{"/api/v0/clusters"}, Threshold: 10 * time.Millisecond},
+		{Name: "authz", Prefixes: []string{"/api/v0/authz"}, Threshold: time.Hour},
+		{Name: "disabled", Prefixes: []string{"/api/v0/work"}},
+	}
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		upstream.Observe(r.Context(), upstream.Maestro, 15*time.Millisecond)
+		upstream.Observe(r.Context(), upstream.DynamoDB, 3*time.Millisecond)
+		upstream.Observe(r.Context(), upstream.DynamoDB, 2*time.Millisecond)
+		time.Sleep(20 * time.Millisecond)
+		w.WriteHeader(http.StatusAccepted)
+	}
+
+	tests := []struct {
+		name       string
+		path       string
+		expectSlow bool
+	}{
+		{name: "over class threshold", path: "/api/v0/clusters/abc", expectSlow: true},
+		{name: "under class threshold", path: "/api/v0/authz/policies"},
+		{name: "class disabled", path: "/api/v0/work"},
+		{name: "fallback threshold", path: "/api/v0/live", expectSlow: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewJSONHandler(&buf, nil))
+			mw := NewSlowRequests(classes, 10*time.Millisecond, logger)
+
+			w := httptest.NewRecorder()
+			mw.Track(http.HandlerFunc(handler)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if w.Code != http.StatusAccepted {
+				t.Errorf("expected status 202 to be passed through, got %d", w.Code)
+			}
+			if !tt.expectSlow {
+				if buf.Len() != 0 {
+					t.Errorf("expected no log output, got %s", buf.String())
+				}
+				return
+			}
+
+			var entry map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+				t.Fatalf("expected one JSON log entry, got %q: %v", buf.String(), err)
+			}
+			if entry["level"] != "WARN" || entry["msg"] != "slow request" {
+				t.Errorf("expected slow request warning, got %v", entry)
+			}
+			if entry["status"] != float64(http.StatusAccepted) {
+				t.Errorf("expected status 202, got %v", entry["status"])
+			}
+			if entry["maestro_ms"] != float64(15) || entry["maestro_calls"] != float64(1) {
+				t.Errorf("expected maestro 15ms over 1 call, got %v over %v", entry["maestro_ms"], entry["maestro_calls"])
+			}
+			if entry["dynamodb_ms"] != float64(5) || entry["dynamodb_calls"] != float64(2) {
+				t.Errorf("expected dynamodb 5ms over 2 calls, got %v over %v", entry["dynamodb_ms"], entry["dynamodb_calls"])
+			}
+			if entry["avp_ms"] != float64(0) {
+				t.Errorf("expected avp 0ms, got %v", entry["avp_ms"])
+			}
+		})
+	}
+}
+
+func TestClassifyRoute(t *testing.T) {
+	classes := []RouteClass{
+		{Name: "tenant", Prefixes: []string{"/api/v0/clusters", "/api/v0/nodepools"}, Threshold: time.Second},
+	}
+
+	if class, threshold := classifyRoute(classes, 2*time.Second, "/api/v0/nodepools/np-1"); class != "tenant" || threshold != time.Second {
+		t.Errorf("expected tenant/1s, got %s/%s", class, threshold)
+	}
+	if class, threshold := classifyRoute(classes, 2*time.Second, "/api/v0/status"); class != RouteClassDefault || threshold != 2*time.Second {
+		t.Errorf("expected default/2s, got %s/%s", class, threshold)
+	}
+}
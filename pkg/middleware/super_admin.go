@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+// ContextKeySuperAdmin is the context key for whether the caller is one of
+// the configured super-admin principals
+const ContextKeySuperAdmin contextKey = "super_admin"
+
+// ParseSuperAdmins validates super-admin principal ARNs, which must be IAM
+// user or role ARNs. A role covers every assumed-role session of it.
+func ParseSuperAdmins(values []string) ([]string, error) {
+	arns := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 6)
+		if len(parts) != 6 || parts[0] != "arn" || parts[2] != "iam" || !accountIDPattern.MatchString(parts[4]) {
+			return nil, fmt.Errorf("invalid super-admin ARN %q: not an IAM ARN with an account ID", v)
+		}
+		if kind, name, _ := strings.Cut(parts[5], "/"); (kind != "user" && kind != "role") || name == "" {
+			return nil, fmt.Errorf("invalid super-admin ARN %q: not an IAM user or role", v)
+		}
+		arns = append(arns, v)
+	}
+	return arns, nil
+}
+
+// superAdmin reports whether id is a configured super admin. The caller ARN
+// must be in the account the request was authenticated for, so a gateway
+// that sends the two separately cannot be used to claim another account's
+// principal.
+func (i *IdentityExtractor) superAdmin(id requestIdentity) bool {
+	if len(i.cfg.SuperAdmins) == 0 || id.accountID == "" {
+		return false
+	}
+	if owner, _ := authz.ResourceAccountID(id.callerARN); owner != id.accountID {
+		return false
+	}
+	if slices.Contains(i.cfg.SuperAdmins, id.callerARN) {
+		return true
+	}
+	role, ok := authz.SessionRoleARN(id.callerARN)
+	return ok && slices.Contains(i.cfg.SuperAdmins, role)
+}
+
+// RequireSuperAdmin returns 403 unless the caller is a configured super
+// admin acting with its own AWS identity, not through an API key or a
+// delegation
+func (p *Privileged) RequireSuperAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		_, delegated := LookupDelegateAccountID(ctx)
+		if !IsSuperAdmin(ctx) || GetAPIKey(ctx) != nil || delegated {
+			p.logger.Warn("super-admin access denied", "account_id", GetAccountID(ctx), "caller_arn", GetCallerARN(ctx))
+			p.writeError(w, http.StatusForbidden, "not-super-admin", "This operation requires a super-admin principal")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsSuperAdmin reports whether the Identity middleware matched the caller to
+// a configured super-admin principal
+func IsSuperAdmin(ctx context.Context) bool {
+	v, _ := ctx.Value(ContextKeySuperAdmin).(bool)
+	return v
+}
@@ -0,0 +1,125 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func TestParseSuperAdmins(t *testing.T) {
+	arns, err := ParseSuperAdmins([]string{" arn:aws:iam::123456789012:role/platform-admin ", "", "arn:aws:iam::123456789012:user/alice"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(arns) != 2 || arns[0] != "arn:aws:iam::123456789012:role/platform-admin" {
+		t.Errorf("unexpected super admins %v", arns)
+	}
+
+	for _, arn := range []string{
+		"platform-admin",
+		"arn:aws:sts::123456789012:assumed-role/platform-admin/alice",
+		"arn:aws:iam::123456789012:group/admins",
+		"arn:aws:iam::1234:role/platform-admin",
+		"arn:aws:iam::123456789012:role/",
+	} {
+		if _, err := ParseSuperAdmins([]string{arn}); err == nil {
+			t.Errorf("expected %s to be rejected", arn)
+		}
+	}
+}
+
+func TestIdentity_SuperAdmin(t *testing.T) {
+	cfg := DefaultIdentityConfig()
+	cfg.SuperAdmins = []string{"arn:aws:iam::123456789012:role/platform-admin", "arn:aws:iam::123456789012:user/alice"}
+	extractor := NewIdentity(cfg, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name      string
+		accountID string
+		callerARN string
+		want      bool
+	}{
+		{name: "user", accountID: "123456789012", callerARN: "arn:aws:iam::123456789012:user/alice", want: true},
+		{name: "role session", accountID: "123456789012", callerARN: "arn:aws:sts::123456789012:assumed-role/platform-admin/bob", want: true},
+		{name: "other role", accountID: "123456789012", callerARN: "arn:aws:sts::123456789012:assumed-role/developer/bob"},
+		{name: "account mismatch", accountID: "210987654321", callerARN: "arn:aws:iam::123456789012:user/alice"},
+		{name: "no account", callerARN: "arn:aws:iam::123456789012:user/alice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got bool
+			handler := extractor.Extract(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = IsSuperAdmin(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/bootstrap/privileged_account", nil)
+			if tt.accountID != "" {
+				req.Header.Set(HeaderAccountID, tt.accountID)
+			}
+			req.Header.Set(HeaderCallerARN, tt.callerARN)
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got != tt.want {
+				t.Errorf("IsSuperAdmin = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrivileged_RequireSuperAdmin(t *testing.T) {
+	p := NewPrivileged(nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name       string
+		ctx        func(context.Context) context.Context
+		wantStatus int
+	}{
+		{
+			name:       "super admin",
+			ctx:        func(ctx context.Context) context.Context { return context.WithValue(ctx, ContextKeySuperAdmin, true) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "not super admin",
+			ctx:        func(ctx context.Context) context.Context { return ctx },
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "api key",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = context.WithValue(ctx, ContextKeySuperAdmin, true)
+				return context.WithValue(ctx, ContextKeyAPIKey, &store.APIKey{AccountID: "123456789012"})
+			},
+			wantStatus: http.StatusForbidden,
+		},
+		{
+			name: "delegated",
+			ctx: func(ctx context.Context) context.Context {
+				ctx = context.WithValue(ctx, ContextKeySuperAdmin, true)
+				return context.WithValue(ctx, ContextKeyDelegateAccountID, "123456789012")
+			},
+			wantStatus: http.StatusForbidden,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := p.RequireSuperAdmin(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v0/bootstrap/privileged_account", nil)
+			req = req.WithContext(tt.ctx(req.Context()))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/upstream"
+)
+
+// authzTimeoutReason is returned when authorization exhausts its share of the
+// request timeout budget
+const authzTimeoutReason = "Authorization checks did not complete within their time budget"
+
+// TimeoutBudget bounds each request by a total timeout and reserves a share
+// of it for authorization, so a slow AVP or DynamoDB call fails as an authz
+// timeout instead of leaving Maestro calls to time out with no budget left
+type TimeoutBudget struct {
+	total time.Duration
+	authz time.Duration
+}
+
+// NewTimeoutBudget creates a new TimeoutBudget middleware. A non-positive
+// total leaves requests without an overall deadline and a non-positive authz
+// share leaves authz bounded only by the total.
+func NewTimeoutBudget(total, authz time.Duration) *TimeoutBudget {
+	return &TimeoutBudget{total: total, authz: authz}
+}
+
+// Apply sets the request's budget; authz checks observe it through
+// authz.NewBudgetedChecker
+func (b *TimeoutBudget) Apply(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := upstream.WithBudget(r.Context(), b.total, map[string]time.Duration{upstream.Authz: b.authz})
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// authzTimedOut reports whether an authz check failed by running out of budget
+func authzTimedOut(err error) bool {
+	return errors.Is(err, context.DeadlineExceeded)
+}
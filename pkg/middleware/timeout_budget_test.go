@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+func TestTimeoutBudget_AuthzExhausted(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// A stalled AVP call holds the check until its context expires
+	checker := authz.NewBudgetedChecker(&mockChecker{
+		authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+			<-ctx.Done()
+			return false, ctx.Err()
+		},
+	})
+	authzMiddleware := NewAuthz(checker, true, "us-east-1", logger)
+
+	nextCalled := false
+	handler := NewTimeoutBudget(time.Minute, 20*time.Millisecond).Apply(authzMiddleware.Authorize(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nextCalled = true
+		})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+	ctx := context.WithValue(req.Context(), ContextKeyAccountID, "123456789012")
+	ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::123456789012:user/test")
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the authz budget to bound the check, took %s", elapsed)
+	}
+	if nextCalled {
+		t.Error("expected next handler not to be called")
+	}
+	if w.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status 504, got %d", w.Code)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["code"] != "authz-timeout" {
+		t.Errorf("expected code authz-timeout, got %q", body["code"])
+	}
+}
+
+func TestTimeoutBudget_RemainderLeftToHandler(t *testing.T) {
+	checker := authz.NewBudgetedChecker(&mockChecker{
+		authorizeFn: func(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+			deadline, ok := ctx.Deadline()
+			if !ok || time.Until(deadline) > time.Second {
+				t.Errorf("expected authz check bounded by its 1s share, deadline set=%v", ok)
+			}
+			return true, nil
+		},
+	})
+	authzMiddleware := NewAuthz(checker, true, "us-east-1", slog.Default())
+
+	var handlerDeadline time.Time
+	handler := NewTimeoutBudget(time.Minute, time.Second).Apply(authzMiddleware.Authorize(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			handlerDeadline, _ = r.Context().Deadline()
+		})))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/clusters", nil)
+	ctx := context.WithValue(req.Context(), ContextKeyAccountID, "123456789012")
+	ctx = context.WithValue(ctx, ContextKeyCallerARN, "arn:aws:iam::123456789012:user/test")
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req.WithContext(ctx))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Code)
+	}
+	if remaining := time.Until(handlerDeadline); remaining < 30*time.Second {
+		t.Errorf("expected the handler to keep the request budget, %s remaining", remaining)
+	}
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/tracecontext"
+)
+
+// W3C Trace Context headers
+const (
+	HeaderTraceParent = tracecontext.HeaderTraceParent
+	HeaderTraceState  = tracecontext.HeaderTraceState
+)
+
+// maxTraceStateMembers is the most list members a tracestate may carry
+const maxTraceStateMembers = 32
+
+// traceParentPattern matches a version 00 traceparent: version, trace ID,
+// parent ID and flags
+var traceParentPattern = regexp.MustCompile(`^00-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})$`)
+
+// TraceContext gives every request a W3C traceparent, so the platform events
+// and the Maestro and AWS calls it causes can be correlated with the caller's
+// trace. A request carrying a valid traceparent continues its trace under a
+// new parent ID, with its tracestate passed on; otherwise a new, unsampled
+// trace is started.
+func TraceContext(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceID, flags, traceState := "", "00", ""
+		if m := traceParentPattern.FindStringSubmatch(r.Header.Get(HeaderTraceParent)); m != nil && !allZero(m[1]) && !allZero(m[2]) {
+			traceID, flags = m[1], m[3]
+			traceState = parseTraceState(r.Header.Values(HeaderTraceState))
+		} else {
+			traceID = randomHex(16)
+		}
+		tc := tracecontext.TraceContext{
+			TraceParent: "00-" + traceID + "-" + randomHex(8) + "-" + flags,
+			TraceState:  traceState,
+		}
+		next.ServeHTTP(w, r.WithContext(tracecontext.WithTraceContext(r.Context(), tc)))
+	})
+}
+
+// GetTraceParent retrieves the request's traceparent from context, or ""
+// when absent
+func GetTraceParent(ctx context.Context) string {
+	tc, _ := tracecontext.FromContext(ctx)
+	return tc.TraceParent
+}
+
+// parseTraceState joins the tracestate header lines into one list, dropping
+// empty members. A list that is malformed or too long is discarded whole, as
+// Trace Context allows.
+func parseTraceState(values []string) string {
+	var members []string
+	for _, value := range values {
+		for _, member := range strings.Split(value, ",") {
+			member = strings.TrimSpace(member)
+			if member == "" {
+				continue
+			}
+			if key, val, ok := strings.Cut(member, "="); !ok || key == "" || val == "" {
+				return ""
+			}
+			members = append(members, member)
+		}
+	}
+	if len(members) > maxTraceStateMembers {
+		return ""
+	}
+	return strings.Join(members, ",")
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// allZero reports whether a hex ID is all zeros, which Trace Context forbids
+func allZero(id string) bool {
+	for _, c := range id {
+		if c != '0' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/tracecontext"
+)
+
+func TestTraceContext(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		// wantPrefix is the expected start of the traceparent, empty for a
+		// new trace
+		wantPrefix string
+	}{
+		{
+			name:       "continues a valid trace",
+			header:     "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			wantPrefix: "00-4bf92f3577b34da6a3ce929d0e0e4736-",
+		},
+		{name: "starts a trace without a header"},
+		{name: "starts a trace for a malformed header", header: "00-4bf92f3577b34da6-00f067aa0ba902b7-01"},
+		{name: "starts a trace for an all-zero trace ID", header: "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			handler := TraceContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = GetTraceParent(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.header != "" {
+				req.Header.Set(HeaderTraceParent, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if !traceParentPattern.MatchString(got) {
+				t.Fatalf("expected a valid traceparent, got %q", got)
+			}
+			if tt.wantPrefix != "" {
+				if !strings.HasPrefix(got, tt.wantPrefix) || !strings.HasSuffix(got, "-01") {
+					t.Errorf("expected the caller's trace and flags, got %q", got)
+				}
+				if got == tt.header {
+					t.Error("expected a new parent ID")
+				}
+			} else if !strings.HasSuffix(got, "-00") || strings.Contains(got, "4bf92f3577b34da6") {
+				t.Errorf("expected a new unsampled trace, got %q", got)
+			}
+		})
+	}
+}
+
+func TestTraceContext_TraceState(t *testing.T) {
+	tests := []struct {
+		name        string
+		traceParent string
+		traceState  []string
+		want        string
+	}{
+		{
+			name:        "passes the trace state on",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			traceState:  []string{"rojo=00f067aa0ba902b7", " congo=t61rcWkgMzE,"},
+			want:        "rojo=00f067aa0ba902b7,congo=t61rcWkgMzE",
+		},
+		{
+			name:        "drops a malformed trace state",
+			traceParent: "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+			traceState:  []string{"rojo"},
+		},
+		{
+			name:       "drops the trace state of a new trace",
+			traceState: []string{"rojo=00f067aa0ba902b7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got tracecontext.TraceContext
+			handler := TraceContext(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = tracecontext.FromContext(r.Context())
+			}))
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.traceParent != "" {
+				req.Header.Set(HeaderTraceParent, tt.traceParent)
+			}
+			for _, v := range tt.traceState {
+				req.Header.Add(HeaderTraceState, v)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if got.TraceState != tt.want {
+				t.Errorf("expected trace state %q, got %q", tt.want, got.TraceState)
+			}
+		})
+	}
+}
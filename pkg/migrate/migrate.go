@@ -0,0 +1,242 @@
+// Package migrate converts the legacy --allowed-accounts allow-list into
+// authz accounts. Every allowed account is enabled with a default admin and a
+// permissive policy attached to that admin, so the admin keeps the access the
+// allow-list gave and can grant it to other principals. Migrating only adds
+// what is missing, so it can be re-run after a partial failure or as the
+// allow-list grows.
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+const (
+	// CreatedBy is recorded as the creator of the accounts and admins a
+	// migration adds
+	CreatedBy = "allowed-accounts-migration"
+	// PolicyName names the permissive policy in each account. An existing
+	// policy of that name is kept as is.
+	PolicyName = "allowed-accounts"
+	// PermissivePolicy allows its principal every action on every resource,
+	// as the allow-list did
+	PermissivePolicy = "permit(principal in ?principal, action, resource);"
+	// AccountIDPlaceholder is replaced by each account's ID in the admin ARN
+	AccountIDPlaceholder = "{accountId}"
+)
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// Action is a kind of change a migration makes
+type Action string
+
+const (
+	ActionEnableAccount   Action = "enable-account"
+	ActionRetryOnboarding Action = "retry-onboarding"
+	ActionAddAdmin        Action = "add-admin"
+	ActionCreatePolicy    Action = "create-policy"
+	ActionAttachPolicy    Action = "attach-policy"
+)
+
+// Service is the subset of authz.Service used to migrate accounts
+type Service interface {
+	GetAccount(ctx context.Context, accountID string) (*store.Account, error)
+	EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error)
+	RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error)
+	ListAdmins(ctx context.Context, accountID string) ([]string, error)
+	AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) (admin *store.Admin, created bool, err error)
+	ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error)
+	CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error)
+	ListAttachments(ctx context.Context, accountID string, filter authz.AttachmentFilter) ([]*authz.Attachment, error)
+	AttachPolicy(ctx context.Context, accountID, policyID string, targetType authz.TargetType, targetID, name, description string) (attachment *authz.Attachment, created bool, err error)
+}
+
+// Options configure a migration
+type Options struct {
+	// AdminARN is the IAM principal made admin of every account, with
+	// AccountIDPlaceholder standing for the account's ID
+	AdminARN string
+	// DryRun reports the changes without making them
+	DryRun bool
+}
+
+// Change is a change made to an account, or that would be made in a dry run
+type Change struct {
+	AccountID string `json:"accountId"`
+	Action    Action `json:"action"`
+	Target    string `json:"target,omitempty"`
+}
+
+// Result lists what a migration changed
+type Result struct {
+	// Accounts counts the accounts migrated, including those that needed
+	// no change
+	Accounts int      `json:"accounts"`
+	Changes  []Change `json:"changes"`
+	// Skipped are the privileged accounts, which are allowed everything
+	// already and have no policy store
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ParseAccounts validates an allow-list and drops duplicates, keeping the
+// first occurrence's position
+func ParseAccounts(accountIDs []string) ([]string, error) {
+	var accounts []string
+	for _, accountID := range accountIDs {
+		if !accountIDPattern.MatchString(accountID) {
+			return nil, fmt.Errorf("allowed account %q is not a 12-digit AWS account ID", accountID)
+		}
+		if !slices.Contains(accounts, accountID) {
+			accounts = append(accounts, accountID)
+		}
+	}
+	return accounts, nil
+}
+
+// AdminARN returns the admin of an account for the admin ARN template
+func AdminARN(template, accountID string) string {
+	return strings.ReplaceAll(template, AccountIDPlaceholder, accountID)
+}
+
+// Run migrates the allowed accounts in order, stopping at the first that
+// fails. The result lists the changes made up to then.
+func Run(ctx context.Context, svc Service, accountIDs []string, opts Options, logger *slog.Logger) (*Result, error) {
+	result := &Result{Changes: []Change{}}
+	for _, accountID := range accountIDs {
+		if err := migrateAccount(ctx, svc, accountID, opts, result, logger); err != nil {
+			return result, fmt.Errorf("account %s: %w", accountID, err)
+		}
+		result.Accounts++
+	}
+	return result, nil
+}
+
+func migrateAccount(ctx context.Context, svc Service, accountID string, opts Options, result *Result, logger *slog.Logger) error {
+	change := func(action Action, target string) {
+		result.Changes = append(result.Changes, Change{AccountID: accountID, Action: action, Target: target})
+		logger.Info("allowed account migration change", "account_id", accountID, "action", action, "target", target, "dry_run", opts.DryRun)
+	}
+
+	account, err := svc.GetAccount(ctx, accountID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case account == nil:
+		if !opts.DryRun {
+			if _, err := svc.EnableAccount(ctx, accountID, CreatedBy, false); err != nil {
+				return err
+			}
+		}
+		change(ActionEnableAccount, "")
+	case account.Privileged:
+		result.Skipped = append(result.Skipped, accountID)
+		return nil
+	case account.OnboardingStatus().State == store.OnboardingFailed:
+		if !opts.DryRun {
+			if _, err := svc.RetryOnboarding(ctx, accountID); err != nil {
+				return err
+			}
+		}
+		change(ActionRetryOnboarding, "")
+	case !account.Onboarded():
+		return fmt.Errorf("account onboarding is %s, re-run once it is Ready", account.OnboardingStatus().State)
+	}
+	// A dry run has no policy store to read from an account whose onboarding
+	// it would run
+	planned := opts.DryRun && (account == nil || !account.Onboarded())
+
+	adminARN := AdminARN(opts.AdminARN, accountID)
+	if opts.DryRun {
+		admins := []string{}
+		if account != nil {
+			if admins, err = svc.ListAdmins(ctx, accountID); err != nil {
+				return err
+			}
+		}
+		if !slices.Contains(admins, adminARN) {
+			change(ActionAddAdmin, adminARN)
+		}
+	} else {
+		_, created, err := svc.AddAdmin(ctx, accountID, adminARN, CreatedBy)
+		if err != nil {
+			return err
+		}
+		if created {
+			change(ActionAddAdmin, adminARN)
+		}
+	}
+
+	var policy *store.Policy
+	if !planned {
+		policies, err := svc.ListPolicies(ctx, accountID)
+		if err != nil {
+			return err
+		}
+		for _, p := range policies {
+			if p.Name == PolicyName {
+				policy = p
+				break
+			}
+		}
+	}
+	if policy == nil {
+		if opts.DryRun {
+			change(ActionCreatePolicy, PolicyName)
+			change(ActionAttachPolicy, adminARN)
+			return nil
+		}
+		if policy, err = svc.CreatePolicy(ctx, accountID, PolicyName, "Access of the legacy allowed-accounts allow-list", PermissivePolicy, nil); err != nil {
+			return fmt.Errorf("policy %q: %w", PolicyName, err)
+		}
+		change(ActionCreatePolicy, PolicyName)
+	}
+
+	targetType := authz.NormalizeTarget(authz.TargetTypeUser, adminARN)
+	attachments, err := svc.ListAttachments(ctx, accountID, authz.AttachmentFilter{PolicyID: policy.PolicyID, TargetType: targetType, TargetID: adminARN})
+	if err != nil {
+		return err
+	}
+	if len(attachments) > 0 {
+		return nil
+	}
+	if !opts.DryRun {
+		if _, _, err := svc.AttachPolicy(ctx, accountID, policy.PolicyID, targetType, adminARN, PolicyName, ""); err != nil {
+			return fmt.Errorf("policy %q: %w", PolicyName, err)
+		}
+	}
+	change(ActionAttachPolicy, adminARN)
+	return nil
+}
+
+// WriteReport writes the changes of a migration, one per line, and a summary
+func WriteReport(w io.Writer, result *Result, dryRun bool) {
+	prefix := ""
+	if dryRun {
+		prefix = "[DRY RUN] "
+	}
+	for _, c := range result.Changes {
+		if c.Target != "" {
+			fmt.Fprintf(w, "%s%s: %s %s\n", prefix, c.AccountID, c.Action, c.Target)
+		} else {
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, c.AccountID, c.Action)
+		}
+	}
+	for _, accountID := range result.Skipped {
+		fmt.Fprintf(w, "%s%s: skipped, the account is privileged\n", prefix, accountID)
+	}
+	changed := make(map[string]bool)
+	for _, c := range result.Changes {
+		changed[c.AccountID] = true
+	}
+	fmt.Fprintf(w, "%d accounts: %d changed, %d already migrated, %d skipped\n",
+		result.Accounts, len(changed), result.Accounts-len(changed)-len(result.Skipped), len(result.Skipped))
+}
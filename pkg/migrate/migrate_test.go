@@ -0,0 +1,208 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeService keeps accounts, admins, policies and attachments in memory and
+// counts the changes made to them
+type fakeService struct {
+	accounts    map[string]*store.Account
+	admins      map[string][]string
+	policies    map[string][]*store.Policy
+	attachments map[string][]*authz.Attachment
+	writes      int
+}
+
+func newFakeService() *fakeService {
+	return &fakeService{
+		accounts:    make(map[string]*store.Account),
+		admins:      make(map[string][]string),
+		policies:    make(map[string][]*store.Policy),
+		attachments: make(map[string][]*authz.Attachment),
+	}
+}
+
+func (f *fakeService) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	return f.accounts[accountID], nil
+}
+
+func (f *fakeService) EnableAccount(ctx context.Context, accountID, createdBy string, isPrivileged bool) (*store.Account, error) {
+	f.writes++
+	account := &store.Account{AccountID: accountID, Privileged: isPrivileged, CreatedBy: createdBy}
+	f.accounts[accountID] = account
+	return account, nil
+}
+
+func (f *fakeService) RetryOnboarding(ctx context.Context, accountID string) (*store.Account, error) {
+	f.writes++
+	account := f.accounts[accountID]
+	account.Onboarding = &store.Onboarding{State: store.OnboardingReady}
+	return account, nil
+}
+
+func (f *fakeService) ListAdmins(ctx context.Context, accountID string) ([]string, error) {
+	return f.admins[accountID], nil
+}
+
+func (f *fakeService) AddAdmin(ctx context.Context, accountID, principalARN, createdBy string) (*store.Admin, bool, error) {
+	admin := &store.Admin{AccountID: accountID, PrincipalARN: principalARN, CreatedBy: createdBy}
+	if slices.Contains(f.admins[accountID], principalARN) {
+		return admin, false, nil
+	}
+	f.writes++
+	f.admins[accountID] = append(f.admins[accountID], principalARN)
+	return admin, true, nil
+}
+
+func (f *fakeService) ListPolicies(ctx context.Context, accountID string) ([]*store.Policy, error) {
+	if !f.accounts[accountID].Onboarded() {
+		return nil, fmt.Errorf("account %s has no policy store", accountID)
+	}
+	return f.policies[accountID], nil
+}
+
+func (f *fakeService) CreatePolicy(ctx context.Context, accountID, name, description, cedarPolicy string, tags map[string]string) (*store.Policy, error) {
+	f.writes++
+	policy := &store.Policy{AccountID: accountID, PolicyID: fmt.Sprintf("tpl-%d", f.writes), Name: name, Description: description, CedarPolicy: cedarPolicy}
+	f.policies[accountID] = append(f.policies[accountID], policy)
+	return policy, nil
+}
+
+func (f *fakeService) ListAttachments(ctx context.Context, accountID string, filter authz.AttachmentFilter) ([]*authz.Attachment, error) {
+	var matched []*authz.Attachment
+	for _, att := range f.attachments[accountID] {
+		if att.PolicyID == filter.PolicyID && att.TargetType == filter.TargetType && att.TargetID == filter.TargetID {
+			matched = append(matched, att)
+		}
+	}
+	return matched, nil
+}
+
+func (f *fakeService) AttachPolicy(ctx context.Context, accountID, policyID string, targetType authz.TargetType, targetID, name, description string) (*authz.Attachment, bool, error) {
+	f.writes++
+	att := &authz.Attachment{AttachmentID: fmt.Sprintf("att-%d", f.writes), PolicyID: policyID, TargetType: targetType, TargetID: targetID, Name: name}
+	f.attachments[accountID] = append(f.attachments[accountID], att)
+	return att, true, nil
+}
+
+const adminTemplate = "arn:aws:iam::{accountId}:role/platform-admin"
+
+func TestRun_MigratesAndIsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	svc := newFakeService()
+	accounts := []string{"111111111111", "222222222222"}
+	opts := Options{AdminARN: adminTemplate}
+
+	result, err := Run(ctx, svc, accounts, opts, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Accounts != 2 || len(result.Changes) != 8 {
+		t.Fatalf("expected 4 changes to each of 2 accounts, got %+v", result)
+	}
+	for _, accountID := range accounts {
+		adminARN := "arn:aws:iam::" + accountID + ":role/platform-admin"
+		if !slices.Contains(svc.admins[accountID], adminARN) {
+			t.Errorf("expected %s to be an admin of %s", adminARN, accountID)
+		}
+		policies := svc.policies[accountID]
+		if len(policies) != 1 || policies[0].Name != PolicyName || policies[0].CedarPolicy != PermissivePolicy {
+			t.Fatalf("expected the permissive policy in %s, got %+v", accountID, policies)
+		}
+		atts := svc.attachments[accountID]
+		if len(atts) != 1 || atts[0].PolicyID != policies[0].PolicyID || atts[0].TargetType != authz.TargetTypeRole || atts[0].TargetID != adminARN {
+			t.Errorf("expected the policy attached to the admin role in %s, got %+v", accountID, atts)
+		}
+	}
+
+	writes := svc.writes
+	result, err = Run(ctx, svc, accounts, opts, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Changes) != 0 || svc.writes != writes {
+		t.Errorf("expected a re-run to change nothing, got %+v and %d writes", result.Changes, svc.writes-writes)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	ctx := context.Background()
+	svc := newFakeService()
+	// Migrated but for the attachment
+	svc.accounts["111111111111"] = &store.Account{AccountID: "111111111111"}
+	svc.admins["111111111111"] = []string{"arn:aws:iam::111111111111:role/platform-admin"}
+	svc.policies["111111111111"] = []*store.Policy{{PolicyID: "tpl-1", Name: PolicyName}}
+	svc.accounts["222222222222"] = &store.Account{AccountID: "222222222222", Onboarding: &store.Onboarding{State: store.OnboardingFailed}}
+	svc.accounts["333333333333"] = &store.Account{AccountID: "333333333333", Privileged: true}
+
+	result, err := Run(ctx, svc, []string{"111111111111", "222222222222", "333333333333", "444444444444"}, Options{AdminARN: adminTemplate, DryRun: true}, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if svc.writes != 0 {
+		t.Errorf("expected a dry run to change nothing, got %d writes", svc.writes)
+	}
+
+	var got []string
+	for _, c := range result.Changes {
+		got = append(got, c.AccountID+" "+string(c.Action))
+	}
+	want := []string{
+		"111111111111 attach-policy",
+		"222222222222 retry-onboarding", "222222222222 add-admin", "222222222222 create-policy", "222222222222 attach-policy",
+		"444444444444 enable-account", "444444444444 add-admin", "444444444444 create-policy", "444444444444 attach-policy",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("unexpected changes:\n got %v\nwant %v", got, want)
+	}
+	if !slices.Equal(result.Skipped, []string{"333333333333"}) {
+		t.Errorf("expected the privileged account to be skipped, got %v", result.Skipped)
+	}
+
+	var out strings.Builder
+	WriteReport(&out, result, true)
+	if !strings.Contains(out.String(), "[DRY RUN] 111111111111: attach-policy arn:aws:iam::111111111111:role/platform-admin\n") ||
+		!strings.HasSuffix(out.String(), "4 accounts: 3 changed, 0 already migrated, 1 skipped\n") {
+		t.Errorf("unexpected report:\n%s", out.String())
+	}
+}
+
+func TestRun_StopsAtOnboardingInProgress(t *testing.T) {
+	svc := newFakeService()
+	svc.accounts["111111111111"] = &store.Account{AccountID: "111111111111", Onboarding: &store.Onboarding{State: store.OnboardingPending}}
+
+	result, err := Run(context.Background(), svc, []string{"111111111111", "222222222222"}, Options{AdminARN: adminTemplate}, testLogger())
+	if err == nil || !strings.Contains(err.Error(), "account 111111111111") {
+		t.Fatalf("expected the pending account to stop the migration, got %v", err)
+	}
+	if result.Accounts != 0 || svc.accounts["222222222222"] != nil {
+		t.Errorf("expected no account to be migrated, got %+v", result)
+	}
+}
+
+func TestParseAccounts(t *testing.T) {
+	accounts, err := ParseAccounts([]string{"111111111111", "022222222222", "111111111111"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(accounts, []string{"111111111111", "022222222222"}) {
+		t.Errorf("unexpected accounts %v", accounts)
+	}
+	if _, err := ParseAccounts([]string{"12345"}); err == nil {
+		t.Error("expected an invalid account ID to be rejected")
+	}
+}
@@ -0,0 +1,186 @@
+// Package mirror sends copies of API requests to a secondary deployment,
+// typically a new version under test, and compares its answers with the
+// ones clients were given. Mirrored requests are sent in the background
+// after the client was answered, so the secondary never slows down or fails
+// a request; its answers are only counted and logged.
+package mirror
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// HeaderMirrored marks requests sent by a mirror, so a secondary that
+// mirrors its own traffic does not send them on
+const HeaderMirrored = "X-Rosa-Mirrored"
+
+// Outcomes of a mirrored request
+const (
+	OutcomeMatch    = "match"
+	OutcomeMismatch = "status_mismatch"
+	OutcomeError    = "error"
+	OutcomeDropped  = "dropped"
+)
+
+// Deployments whose latency is compared
+const (
+	DeploymentPrimary   = "primary"
+	DeploymentSecondary = "secondary"
+)
+
+// maxDrainBytes bounds how much of a secondary's response is read so its
+// connection can be reused
+const maxDrainBytes = 1 << 20
+
+var (
+	mirroredRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_api_mirror_requests_total",
+		Help: "Requests mirrored to the secondary deployment, by route and outcome (match, status_mismatch, error or dropped).",
+	}, []string{"route", "outcome"})
+
+	mirrorLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rosa_api_mirror_duration_seconds",
+		Help:    "Duration of mirrored requests on the primary and on the secondary deployment, by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "deployment"})
+)
+
+// Request is a request to mirror, as the client sent it
+type Request struct {
+	Method string
+	// Path and RawQuery are those the request was routed with; a base path
+	// the client sent has already been stripped
+	Path     string
+	RawQuery string
+	Header   http.Header
+	Body     []byte
+	// Route is the route template, for metrics
+	Route     string
+	RequestID string
+}
+
+// Result is how the primary answered a request
+type Result struct {
+	Status  int
+	Elapsed time.Duration
+}
+
+// Config configures where and how many requests are mirrored
+type Config struct {
+	// Target is the base URL of the secondary deployment
+	Target string
+	// Timeout bounds each mirrored request
+	Timeout time.Duration
+	// MaxInFlight bounds the mirrored requests outstanding at once; more are
+	// dropped rather than queued
+	MaxInFlight int
+}
+
+// Mirror sends requests to the secondary deployment and compares answers
+type Mirror struct {
+	target *url.URL
+	client *http.Client
+	slots  chan struct{}
+	logger *slog.Logger
+}
+
+// New creates a Mirror sending to cfg.Target
+func New(cfg Config, logger *slog.Logger) (*Mirror, error) {
+	target, err := url.Parse(cfg.Target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mirror target: %w", err)
+	}
+	return &Mirror{
+		target: target,
+		client: &http.Client{
+			Timeout: cfg.Timeout,
+			// The secondary's redirects are part of its answer
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+		slots:  make(chan struct{}, cfg.MaxInFlight),
+		logger: logger,
+	}, nil
+}
+
+// Send mirrors req in the background and compares the secondary's answer
+// with primary. When MaxInFlight requests are already outstanding req is
+// dropped.
+func (m *Mirror) Send(req Request, primary Result) {
+	select {
+	case m.slots <- struct{}{}:
+	default:
+		mirroredRequests.WithLabelValues(req.Route, OutcomeDropped).Inc()
+		return
+	}
+	go func() {
+		defer func() { <-m.slots }()
+		m.compare(req, primary)
+	}()
+}
+
+// compare sends req to the secondary and records how its answer differs
+func (m *Mirror) compare(req Request, primary Result) {
+	status, elapsed, err := m.send(req)
+	if err != nil {
+		mirroredRequests.WithLabelValues(req.Route, OutcomeError).Inc()
+		m.logger.Warn("mirrored request failed",
+			"method", req.Method,
+			"route", req.Route,
+			"request_id", req.RequestID,
+			"error", err,
+		)
+		return
+	}
+
+	mirrorLatency.WithLabelValues(req.Route, DeploymentPrimary).Observe(primary.Elapsed.Seconds())
+	mirrorLatency.WithLabelValues(req.Route, DeploymentSecondary).Observe(elapsed.Seconds())
+	if status == primary.Status {
+		mirroredRequests.WithLabelValues(req.Route, OutcomeMatch).Inc()
+		return
+	}
+	mirroredRequests.WithLabelValues(req.Route, OutcomeMismatch).Inc()
+	m.logger.Info("mirrored request diverged",
+		"method", req.Method,
+		"route", req.Route,
+		"request_id", req.RequestID,
+		"primary_status", primary.Status,
+		"secondary_status", status,
+		"primary_elapsed", primary.Elapsed,
+		"secondary_elapsed", elapsed,
+	)
+}
+
+// send sends req to the secondary and returns its status and how long it
+// took to answer
+func (m *Mirror) send(req Request) (int, time.Duration, error) {
+	u := *m.target
+	u.Path = m.target.Path + req.Path
+	u.RawQuery = req.RawQuery
+
+	ctx := context.Background()
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, u.String(), bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, 0, err
+	}
+	httpReq.Header = req.Header.Clone()
+	httpReq.Header.Set(HeaderMirrored, "true")
+
+	started := time.Now()
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return 0, 0, err
+	}
+	elapsed := time.Since(started)
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrainBytes))
+	_ = resp.Body.Close()
+	return resp.StatusCode, elapsed, nil
+}
@@ -0,0 +1,84 @@
+package mirror
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func newTestMirror(t *testing.T, target string, maxInFlight int) *Mirror {
+	t.Helper()
+	m, err := New(Config{Target: target, Timeout: 5 * time.Second, MaxInFlight: maxInFlight}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return m
+}
+
+func TestMirror_Compare(t *testing.T) {
+	var gotPath, gotQuery, gotMirrored, gotAuth, gotBody string
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath, gotQuery = r.URL.Path, r.URL.RawQuery
+		gotMirrored = r.Header.Get(HeaderMirrored)
+		gotAuth = r.Header.Get("X-Amz-Account-Id")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		if r.URL.Path == "/stage/api/v0/clusters/c2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer secondary.Close()
+
+	m := newTestMirror(t, secondary.URL+"/stage", 1)
+	route := "/api/v0/clusters/{id}"
+	matches := testutil.ToFloat64(mirroredRequests.WithLabelValues(route, OutcomeMatch))
+	mismatches := testutil.ToFloat64(mirroredRequests.WithLabelValues(route, OutcomeMismatch))
+
+	header := http.Header{"X-Amz-Account-Id": {"123456789012"}}
+	m.compare(Request{Method: http.MethodPost, Path: "/api/v0/clusters/c1", RawQuery: "dryRun=true", Header: header, Body: []byte(`{}`), Route: route}, Result{Status: http.StatusOK})
+	if gotPath != "/stage/api/v0/clusters/c1" || gotQuery != "dryRun=true" {
+		t.Errorf("unexpected mirrored URL %s?%s", gotPath, gotQuery)
+	}
+	if gotMirrored != "true" || gotAuth != "123456789012" || gotBody != `{}` {
+		t.Errorf("expected headers and body to be forwarded, got mirrored=%q account=%q body=%q", gotMirrored, gotAuth, gotBody)
+	}
+	if header.Get(HeaderMirrored) != "" {
+		t.Error("expected the request's own headers to be left alone")
+	}
+
+	m.compare(Request{Method: http.MethodGet, Path: "/api/v0/clusters/c2", Header: http.Header{}, Route: route}, Result{Status: http.StatusOK})
+
+	if got := testutil.ToFloat64(mirroredRequests.WithLabelValues(route, OutcomeMatch)) - matches; got != 1 {
+		t.Errorf("expected 1 match, got %v", got)
+	}
+	if got := testutil.ToFloat64(mirroredRequests.WithLabelValues(route, OutcomeMismatch)) - mismatches; got != 1 {
+		t.Errorf("expected 1 mismatch, got %v", got)
+	}
+}
+
+func TestMirror_Send_DropsWhenFull(t *testing.T) {
+	release := make(chan struct{})
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer secondary.Close()
+	defer close(release)
+
+	m := newTestMirror(t, secondary.URL, 1)
+	route := "/api/v0/dropped"
+	dropped := testutil.ToFloat64(mirroredRequests.WithLabelValues(route, OutcomeDropped))
+
+	m.Send(Request{Method: http.MethodGet, Path: route, Header: http.Header{}, Route: route}, Result{Status: http.StatusOK})
+	m.Send(Request{Method: http.MethodGet, Path: route, Header: http.Header{}, Route: route}, Result{Status: http.StatusOK})
+
+	if got := testutil.ToFloat64(mirroredRequests.WithLabelValues(route, OutcomeDropped)) - dropped; got != 1 {
+		t.Errorf("expected the second request to be dropped, got %v drops", got)
+	}
+}
@@ -0,0 +1,212 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	sestypes "github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+)
+
+// Webhook request headers
+const (
+	// EventHeader carries the event type
+	EventHeader = "X-Rosa-Event"
+	// SignatureHeader carries "sha256=" and the hex HMAC-SHA256 of the body,
+	// keyed with the webhook secret
+	SignatureHeader = "X-Rosa-Signature"
+	// PreviousSignatureHeader carries the signature keyed with the previous
+	// webhook secret, while it overlaps a rotated secret
+	PreviousSignatureHeader = "X-Rosa-Signature-Previous"
+)
+
+// Channel names reported in deliveries
+const (
+	ChannelEmail   = "email"
+	ChannelSNS     = "sns"
+	ChannelWebhook = "webhook"
+)
+
+// ErrEmailUnavailable is returned for settings with an email channel when no
+// SES sender address is configured
+var ErrEmailUnavailable = errors.New("email notifications are not configured on this platform")
+
+// SettingsReader returns an account's notification settings
+type SettingsReader interface {
+	Get(ctx context.Context, accountID string) (*Settings, error)
+}
+
+// SESClient provides the SES operation used for email
+type SESClient interface {
+	SendEmail(ctx context.Context, params *sesv2.SendEmailInput, optFns ...func(*sesv2.Options)) (*sesv2.SendEmailOutput, error)
+}
+
+// SNSClient provides the SNS operation used for topics
+type SNSClient interface {
+	Publish(ctx context.Context, params *sns.PublishInput, optFns ...func(*sns.Options)) (*sns.PublishOutput, error)
+}
+
+// Delivery is the outcome of sending an event to one channel
+type Delivery struct {
+	Channel string `json:"channel"`
+	Error   string `json:"error,omitempty"`
+}
+
+// Notifier sends events to the channels accounts configure
+type Notifier struct {
+	settings SettingsReader
+	ses      SESClient
+	sns      SNSClient
+	http     *http.Client
+	// sender is the SES From address; empty disables email
+	sender string
+	logger *slog.Logger
+}
+
+// NewNotifier creates a new notifier. Email is only delivered when sender,
+// an address verified in SES, is set.
+func NewNotifier(settings SettingsReader, ses SESClient, sns SNSClient, sender string, logger *slog.Logger) *Notifier {
+	return &Notifier{
+		settings: settings,
+		ses:      ses,
+		sns:      sns,
+		http:     &http.Client{Timeout: 10 * time.Second},
+		sender:   sender,
+		logger:   logger,
+	}
+}
+
+// Validate checks settings before they are saved, including that the
+// channels they name can be delivered to from this platform
+func (n *Notifier) Validate(settings *Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	if settings.Email != nil && n.sender == "" {
+		return ErrEmailUnavailable
+	}
+	return nil
+}
+
+// Notify sends an event to every channel of the event's account, if the
+// account subscribes to it, and returns the outcome per channel. Channel
+// failures are reported in the deliveries and logged; the error is only set
+// when the account's settings cannot be read.
+func (n *Notifier) Notify(ctx context.Context, event *Event) ([]Delivery, error) {
+	settings, err := n.settings.Get(ctx, event.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil || !settings.Subscribed(event.Type) {
+		return []Delivery{}, nil
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	deliveries := []Delivery{}
+	deliver := func(channel string, err error) {
+		d := Delivery{Channel: channel}
+		if err != nil {
+			d.Error = err.Error()
+			n.logger.Warn("notification delivery failed", "error", err, "account_id", event.AccountID, "event", event.Type, "channel", channel)
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	if settings.Email != nil {
+		deliver(ChannelEmail, n.sendEmail(ctx, settings.Email, event))
+	}
+	if settings.SNS != nil {
+		deliver(ChannelSNS, n.publish(ctx, settings.SNS, event))
+	}
+	if settings.Webhook != nil {
+		deliver(ChannelWebhook, n.postWebhook(ctx, settings.Webhook, event))
+	}
+	return deliveries, nil
+}
+
+func (n *Notifier) sendEmail(ctx context.Context, channel *EmailChannel, event *Event) error {
+	if n.sender == "" {
+		return ErrEmailUnavailable
+	}
+	_, err := n.ses.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(n.sender),
+		Destination:      &sestypes.Destination{ToAddresses: channel.Addresses},
+		Content: &sestypes.EmailContent{
+			Simple: &sestypes.Message{
+				Subject: &sestypes.Content{Data: aws.String(event.Subject)},
+				Body:    &sestypes.Body{Text: &sestypes.Content{Data: aws.String(event.Message)}},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (n *Notifier) publish(ctx context.Context, channel *SNSChannel, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	_, err = n.sns.Publish(ctx, &sns.PublishInput{
+		TopicArn: aws.String(channel.TopicARN),
+		Subject:  aws.String(event.Subject),
+		Message:  aws.String(string(body)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish to SNS: %w", err)
+	}
+	return nil
+}
+
+func (n *Notifier) postWebhook(ctx context.Context, channel *WebhookChannel, event *Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, channel.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event.Type)
+	if channel.Secret != "" {
+		req.Header.Set(SignatureHeader, Sign(channel.Secret, body))
+	}
+	if previous := channel.previousSecret(time.Now()); previous != "" {
+		req.Header.Set(PreviousSignatureHeader, Sign(previous, body))
+	}
+
+	resp, err := n.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value for a webhook body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
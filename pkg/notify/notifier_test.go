@@ -0,0 +1,111 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// staticSettings returns the same settings for every account
+type staticSettings struct {
+	settings *Settings
+}
+
+func (s staticSettings) Get(ctx context.Context, accountID string) (*Settings, error) {
+	return s.settings, nil
+}
+
+func TestNotifier_Webhook(t *testing.T) {
+	const secret = "s3cret"
+
+	var got Event
+	var signature, eventHeader string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		eventHeader = r.Header.Get(EventHeader)
+		if signature != Sign(secret, body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_ = json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	settings := &Settings{
+		AccountID: "123456789012",
+		Webhook:   &WebhookChannel{URL: server.URL, Secret: secret},
+		Events:    []string{EventQuotaWarning},
+	}
+	n := NewNotifier(staticSettings{settings}, nil, nil, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	n.http = server.Client()
+
+	deliveries, err := n.Notify(context.Background(), &Event{Type: EventQuotaWarning, AccountID: "123456789012", Subject: "Quota", Message: "90% used"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Channel != ChannelWebhook || deliveries[0].Error != "" {
+		t.Fatalf("unexpected deliveries %+v", deliveries)
+	}
+	if eventHeader != EventQuotaWarning || got.Type != EventQuotaWarning || got.Time.IsZero() {
+		t.Errorf("unexpected webhook request: header=%q event=%+v", eventHeader, got)
+	}
+
+	deliveries, err = n.Notify(context.Background(), &Event{Type: EventBreakGlass, AccountID: "123456789012"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 0 {
+		t.Errorf("expected an unsubscribed event not to be delivered, got %+v", deliveries)
+	}
+}
+
+func TestNotifier_WebhookRotatedSecret(t *testing.T) {
+	tests := []struct {
+		name           string
+		overlap        time.Duration
+		expectPrevious bool
+	}{
+		{name: "overlap", overlap: time.Hour, expectPrevious: true},
+		{name: "retired at once", overlap: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var current, previous bool
+			server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				current = r.Header.Get(SignatureHeader) == Sign("new", body)
+				previous = r.Header.Get(PreviousSignatureHeader) == Sign("old", body)
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer server.Close()
+
+			webhook := &WebhookChannel{URL: server.URL, Secret: "old"}
+			webhook.RotateSecret("new", tt.overlap, time.Now())
+			n := NewNotifier(staticSettings{&Settings{AccountID: "123456789012", Webhook: webhook}}, nil, nil, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+			n.http = server.Client()
+
+			if _, err := n.Notify(context.Background(), &Event{Type: EventQuotaWarning, AccountID: "123456789012"}); err != nil {
+				t.Fatal(err)
+			}
+			if !current || previous != tt.expectPrevious {
+				t.Errorf("signed with new=%v old=%v, want new=true old=%v", current, previous, tt.expectPrevious)
+			}
+		})
+	}
+}
+
+func TestNotifier_ValidateEmailWithoutSender(t *testing.T) {
+	n := NewNotifier(staticSettings{}, nil, nil, "", slog.New(slog.NewTextHandler(io.Discard, nil)))
+	err := n.Validate(&Settings{Email: &EmailChannel{Addresses: []string{"ops@example.com"}}})
+	if err != ErrEmailUnavailable {
+		t.Errorf("expected ErrEmailUnavailable, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+// Package notify delivers platform events, such as quota warnings, failed
+// fleet rollouts and break-glass usage, to the channels each account
+// configures: email through SES, an SNS topic, or a webhook.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Event types
+const (
+	EventQuotaWarning  = "quota-warning"
+	EventRolloutFailed = "fleet-rollout-failed"
+	EventBreakGlass    = "break-glass-used"
+	// EventAuthzChanged is sent for every change to an account's authz
+	// tables. It is only delivered to accounts that list it in their event
+	// filter.
+	EventAuthzChanged = "authz-changed"
+	// EventTest is sent on request to check an account's channels; it is
+	// delivered regardless of the account's event filter
+	EventTest = "test"
+)
+
+// EventTypes lists the event types accounts can subscribe to
+var EventTypes = []string{EventQuotaWarning, EventRolloutFailed, EventBreakGlass, EventAuthzChanged}
+
+// maxEmailAddresses caps the recipients of the email channel
+const maxEmailAddresses = 50
+
+var snsTopicARN = regexp.MustCompile(`^arn:aws[a-z-]*:sns:[a-z0-9-]+:\d{12}:[A-Za-z0-9_-]{1,256}(\.fifo)?$`)
+
+// Event is something the platform tells an account about
+type Event struct {
+	Type      string            `json:"type"`
+	AccountID string            `json:"accountId"`
+	Subject   string            `json:"subject"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// EmailChannel sends events to email addresses through SES
+type EmailChannel struct {
+	Addresses []string `dynamodbav:"addresses" json:"addresses"`
+}
+
+// SNSChannel publishes events to an SNS topic. The topic policy must allow
+// the platform to publish.
+type SNSChannel struct {
+	TopicARN string `dynamodbav:"topicArn" json:"topicArn"`
+}
+
+// MaxSecretOverlap caps how long a rotated webhook secret keeps signing
+// requests next to the secret that replaced it
+const MaxSecretOverlap = 7 * 24 * time.Hour
+
+// WebhookChannel posts events as JSON to an HTTPS URL. With a secret, each
+// request carries an HMAC-SHA256 signature of the body (see SignatureHeader).
+// After a rotation, requests are also signed with the previous secret until
+// PreviousSecretExpiresAt (see PreviousSignatureHeader), so receivers can
+// switch secrets without dropping events.
+type WebhookChannel struct {
+	URL    string `dynamodbav:"url" json:"url"`
+	Secret string `dynamodbav:"secret,omitempty" json:"secret,omitempty"`
+	// PreviousSecret is only set by RotateSecret and is never returned
+	PreviousSecret          string `dynamodbav:"previousSecret,omitempty" json:"-"`
+	PreviousSecretExpiresAt string `dynamodbav:"previousSecretExpiresAt,omitempty" json:"previousSecretExpiresAt,omitempty"`
+}
+
+// RotateSecret replaces the secret, keeping the old one signing requests for
+// overlap, capped at MaxSecretOverlap. A zero overlap, or a channel without
+// a secret, retires the old secret at once.
+func (c *WebhookChannel) RotateSecret(secret string, overlap time.Duration, now time.Time) {
+	c.PreviousSecret, c.PreviousSecretExpiresAt = "", ""
+	if overlap = min(overlap, MaxSecretOverlap); overlap > 0 && c.Secret != "" {
+		c.PreviousSecret = c.Secret
+		c.PreviousSecretExpiresAt = clock.Format(now.Add(overlap))
+	}
+	c.Secret = secret
+}
+
+// previousSecret returns the previous secret while it still signs requests
+func (c *WebhookChannel) previousSecret(now time.Time) string {
+	if c.PreviousSecret == "" {
+		return ""
+	}
+	expiresAt, err := time.Parse(time.RFC3339, c.PreviousSecretExpiresAt)
+	if err != nil || !now.Before(expiresAt) {
+		return ""
+	}
+	return c.PreviousSecret
+}
+
+// Settings are an account's notification channels
+type Settings struct {
+	AccountID string          `dynamodbav:"accountId" json:"accountId"`
+	Email     *EmailChannel   `dynamodbav:"email,omitempty" json:"email,omitempty"`
+	SNS       *SNSChannel     `dynamodbav:"sns,omitempty" json:"sns,omitempty"`
+	Webhook   *WebhookChannel `dynamodbav:"webhook,omitempty" json:"webhook,omitempty"`
+	// Events limits delivery to these event types; empty delivers all
+	Events    []string `dynamodbav:"events,omitempty" json:"events,omitempty"`
+	UpdatedAt string   `dynamodbav:"updatedAt" json:"updatedAt"`
+	UpdatedBy string   `dynamodbav:"updatedBy" json:"updatedBy"`
+}
+
+// Validate checks that the settings name at least one channel and that every
+// channel and event type is well formed
+func (s *Settings) Validate() error {
+	if s.Email == nil && s.SNS == nil && s.Webhook == nil {
+		return errors.New("at least one of email, sns or webhook is required")
+	}
+
+	if s.Email != nil {
+		if len(s.Email.Addresses) == 0 {
+			return errors.New("email.addresses must not be empty")
+		}
+		if len(s.Email.Addresses) > maxEmailAddresses {
+			return fmt.Errorf("email.addresses allows at most %d addresses", maxEmailAddresses)
+		}
+		for _, address := range s.Email.Addresses {
+			if _, err := mail.ParseAddress(address); err != nil {
+				return fmt.Errorf("invalid email address %q", address)
+			}
+		}
+	}
+
+	if s.SNS != nil && !snsTopicARN.MatchString(s.SNS.TopicARN) {
+		return fmt.Errorf("invalid SNS topic ARN %q", s.SNS.TopicARN)
+	}
+
+	if s.Webhook != nil {
+		u, err := url.Parse(s.Webhook.URL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("webhook.url must be an https URL")
+		}
+	}
+
+	for _, event := range s.Events {
+		if !slices.Contains(EventTypes, event) {
+			return fmt.Errorf("unknown event type %q", event)
+		}
+	}
+	return nil
+}
+
+// Subscribed reports whether the account receives events of eventType
+func (s *Settings) Subscribed(eventType string) bool {
+	if eventType == EventTest || slices.Contains(s.Events, eventType) {
+		return true
+	}
+	return len(s.Events) == 0 && eventType != EventAuthzChanged
+}
@@ -0,0 +1,55 @@
+package notify
+
+import "testing"
+
+func TestSettings_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		wantErr  bool
+	}{
+		{name: "no channels", settings: Settings{}, wantErr: true},
+		{name: "email", settings: Settings{Email: &EmailChannel{Addresses: []string{"ops@example.com"}}}},
+		{name: "empty email list", settings: Settings{Email: &EmailChannel{}}, wantErr: true},
+		{name: "invalid email", settings: Settings{Email: &EmailChannel{Addresses: []string{"not-an-address"}}}, wantErr: true},
+		{name: "sns", settings: Settings{SNS: &SNSChannel{TopicARN: "arn:aws:sns:us-east-1:123456789012:rosa-events"}}},
+		{name: "invalid sns arn", settings: Settings{SNS: &SNSChannel{TopicARN: "arn:aws:sqs:us-east-1:123456789012:queue"}}, wantErr: true},
+		{name: "https webhook", settings: Settings{Webhook: &WebhookChannel{URL: "https://hooks.example.com/rosa"}}},
+		{name: "http webhook", settings: Settings{Webhook: &WebhookChannel{URL: "http://hooks.example.com/rosa"}}, wantErr: true},
+		{name: "known events", settings: Settings{Webhook: &WebhookChannel{URL: "https://hooks.example.com"}, Events: []string{EventQuotaWarning, EventBreakGlass}}},
+		{name: "unknown event", settings: Settings{Webhook: &WebhookChannel{URL: "https://hooks.example.com"}, Events: []string{"cluster-created"}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSettings_Subscribed(t *testing.T) {
+	all := Settings{}
+	if !all.Subscribed(EventRolloutFailed) {
+		t.Error("expected settings without an event filter to receive every event")
+	}
+	if all.Subscribed(EventAuthzChanged) {
+		t.Error("expected authz changes to be delivered only when subscribed to")
+	}
+
+	filtered := Settings{Events: []string{EventQuotaWarning}}
+	if !filtered.Subscribed(EventQuotaWarning) {
+		t.Error("expected a subscribed event to be delivered")
+	}
+	if filtered.Subscribed(EventBreakGlass) {
+		t.Error("expected an unsubscribed event to be filtered")
+	}
+	if !(&Settings{Events: []string{EventAuthzChanged}}).Subscribed(EventAuthzChanged) {
+		t.Error("expected authz changes to be delivered when subscribed to")
+	}
+	if !filtered.Subscribed(EventTest) {
+		t.Error("expected test events to bypass the filter")
+	}
+}
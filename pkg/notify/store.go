@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// Store keeps notification settings, one item per account
+type Store struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewStore creates a new DynamoDB-backed notification settings store
+func NewStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *Store {
+	return &Store{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *Store) WithClock(now clock.Clock) *Store {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Get returns an account's settings, or nil if it has none
+func (s *Store) Get(ctx context.Context, accountID string) (*Settings, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification settings: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var settings Settings
+	if err := attributevalue.UnmarshalMap(result.Item, &settings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal notification settings: %w", err)
+	}
+	return &settings, nil
+}
+
+// Put replaces an account's settings
+func (s *Store) Put(ctx context.Context, settings *Settings) error {
+	settings.UpdatedAt = clock.Format(s.now())
+
+	item, err := attributevalue.MarshalMap(settings)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification settings: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put notification settings: %w", err)
+	}
+
+	s.logger.Info("notification settings updated", "account_id", settings.AccountID, "updated_by", settings.UpdatedBy)
+	return nil
+}
+
+// Delete removes an account's settings, reporting whether it had any
+func (s *Store) Delete(ctx context.Context, accountID string) (bool, error) {
+	result, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key: map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+		ReturnValues: types.ReturnValueAllOld,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete notification settings: %w", err)
+	}
+
+	s.logger.Info("notification settings deleted", "account_id", accountID)
+	return result.Attributes != nil, nil
+}
@@ -0,0 +1,41 @@
+package opensearch
+
+import (
+	"context"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// DecisionSink indexes decision audit entries, one document per decision
+// with the same fields the audit API returns, plus accountId. Documents go
+// to the index of the decision's time.
+type DecisionSink struct {
+	client *Client
+}
+
+// NewDecisionSink creates a new DecisionSink indexing through client
+func NewDecisionSink(client *Client) *DecisionSink {
+	return &DecisionSink{client: client}
+}
+
+// Send indexes entries and returns those that were not indexed, with the
+// last error. Entry IDs are only unique within an account, so documents are
+// identified by accountId#entryId.
+func (s *DecisionSink) Send(ctx context.Context, entries []*store.DecisionAuditEntry) ([]*store.DecisionAuditEntry, error) {
+	docs := make([]Document, len(entries))
+	byID := make(map[string]*store.DecisionAuditEntry, len(entries))
+	for i, entry := range entries {
+		id := entry.AccountID + "#" + entry.EntryID
+		at, _ := time.Parse(time.RFC3339Nano, entry.Time)
+		docs[i] = Document{ID: id, Time: at, Body: entry}
+		byID[id] = entry
+	}
+
+	unsent, err := s.client.Index(ctx, docs)
+	undelivered := make([]*store.DecisionAuditEntry, len(unsent))
+	for i, doc := range unsent {
+		undelivered[i] = byID[doc.ID]
+	}
+	return undelivered, err
+}
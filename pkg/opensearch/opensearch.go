@@ -0,0 +1,243 @@
+// Package opensearch indexes documents into OpenSearch or Elasticsearch
+// through the bulk API. Documents go to time-based indices named after a
+// prefix, so an index lifecycle policy on the cluster can roll them over and
+// delete old ones, and a Kibana or OpenSearch Dashboards index pattern of
+// prefix-* covers them all. Documents carry their own IDs, so delivering one
+// again overwrites it rather than duplicating it.
+package opensearch
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Index rollovers: how often documents move to a new index
+const (
+	RolloverDaily   = "daily"
+	RolloverMonthly = "monthly"
+	RolloverNone    = "none"
+)
+
+// Rollovers lists the valid Config.Rollover values
+var Rollovers = []string{RolloverDaily, RolloverMonthly, RolloverNone}
+
+// maxRetryInterval caps the backoff between bulk request attempts
+const maxRetryInterval = 10 * time.Second
+
+var (
+	documentsIndexed = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_opensearch_documents_indexed_total",
+		Help: "Documents indexed into OpenSearch, by index prefix.",
+	}, []string{"prefix"})
+
+	documentsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_opensearch_documents_rejected_total",
+		Help: "Documents OpenSearch rejected permanently, such as for a mapping conflict, by index prefix. They are not retried.",
+	}, []string{"prefix"})
+
+	bulkRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_opensearch_bulk_retries_total",
+		Help: "Bulk requests sent again after a throttled, failed or partly failed attempt, by index prefix.",
+	}, []string{"prefix"})
+)
+
+// Config configures a Client
+type Config struct {
+	// Endpoint is the cluster URL, such as
+	// https://search-audit-abc123.us-east-1.es.amazonaws.com
+	Endpoint string
+	// IndexPrefix names the indices: prefix-2026.10.15 with daily rollover
+	IndexPrefix string
+	// Rollover is daily, monthly or none, which writes to IndexPrefix alone
+	Rollover string
+	// AWSRegion and AWSService sign requests with SigV4 for Amazon
+	// OpenSearch Service (es) or OpenSearch Serverless (aoss). Without a
+	// service, requests are not signed.
+	AWSRegion  string
+	AWSService string
+	// MaxRetries is how often a bulk request is sent again for the
+	// documents that were throttled or failed transiently
+	MaxRetries    int
+	RetryInterval time.Duration
+}
+
+// Document is one document to index
+type Document struct {
+	// ID is the document's _id; indexing the same ID again overwrites it
+	ID string
+	// Time picks the index the document goes to
+	Time time.Time
+	Body any
+}
+
+// Client indexes documents through the bulk API
+type Client struct {
+	cfg         Config
+	http        *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+	logger      *slog.Logger
+}
+
+// NewClient creates a client for cfg. credentials sign the requests when
+// cfg.AWSService is set.
+func NewClient(cfg Config, credentials aws.CredentialsProvider, logger *slog.Logger) *Client {
+	return &Client{
+		cfg:         cfg,
+		http:        &http.Client{Timeout: 30 * time.Second},
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+		logger:      logger,
+	}
+}
+
+// IndexName returns the index a document made at t goes to
+func (c *Client) IndexName(t time.Time) string {
+	t = t.UTC()
+	switch c.cfg.Rollover {
+	case RolloverDaily:
+		return c.cfg.IndexPrefix + "-" + t.Format("2006.01.02")
+	case RolloverMonthly:
+		return c.cfg.IndexPrefix + "-" + t.Format("2006.01")
+	default:
+		return c.cfg.IndexPrefix
+	}
+}
+
+// bulkResponse is the part of a bulk API response Index reads
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// Index indexes docs, sending the documents that were throttled or failed
+// transiently again up to MaxRetries times. It returns the documents still
+// not indexed, which the caller may send again later, with the last error.
+// Documents rejected for good, such as for a mapping conflict, are logged
+// and dropped.
+func (c *Client) Index(ctx context.Context, docs []Document) ([]Document, error) {
+	pending := docs
+	interval := c.cfg.RetryInterval
+	var lastErr error
+	for attempt := 0; len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			if attempt > c.cfg.MaxRetries {
+				break
+			}
+			bulkRetries.WithLabelValues(c.cfg.IndexPrefix).Inc()
+			select {
+			case <-ctx.Done():
+				return pending, ctx.Err()
+			case <-time.After(interval):
+			}
+			interval = min(interval*2, maxRetryInterval)
+		}
+
+		pending, lastErr = c.bulk(ctx, pending)
+	}
+	if len(pending) > 0 && lastErr == nil {
+		lastErr = errors.New("documents were throttled")
+	}
+	return pending, lastErr
+}
+
+// bulk sends one bulk request and returns the documents to send again
+func (c *Client) bulk(ctx context.Context, docs []Document) ([]Document, error) {
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, doc := range docs {
+		action := map[string]map[string]string{"index": {"_index": c.IndexName(doc.Time), "_id": doc.ID}}
+		if err := enc.Encode(action); err != nil {
+			return nil, fmt.Errorf("failed to encode bulk action: %w", err)
+		}
+		if err := enc.Encode(doc.Body); err != nil {
+			return nil, fmt.Errorf("failed to encode document %s: %w", doc.ID, err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(c.cfg.Endpoint, "/")+"/_bulk", bytes.NewReader(body.Bytes()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build bulk request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	if err := c.sign(ctx, req, body.Bytes()); err != nil {
+		return nil, err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return docs, fmt.Errorf("bulk request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return docs, fmt.Errorf("bulk request returned %s", resp.Status)
+	}
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		documentsRejected.WithLabelValues(c.cfg.IndexPrefix).Add(float64(len(docs)))
+		return nil, fmt.Errorf("bulk request returned %s: %s", resp.Status, msg)
+	}
+
+	var result bulkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return docs, fmt.Errorf("failed to decode bulk response: %w", err)
+	}
+	if len(result.Items) != len(docs) {
+		return docs, fmt.Errorf("bulk response has %d items for %d documents", len(result.Items), len(docs))
+	}
+
+	// Items are in request order, each keyed by its action
+	var retry []Document
+	indexed := 0
+	for i, item := range result.Items {
+		status := item["index"]
+		switch {
+		case status.Error == nil:
+			indexed++
+		case status.Status == http.StatusTooManyRequests || status.Status >= http.StatusInternalServerError:
+			retry = append(retry, docs[i])
+		default:
+			documentsRejected.WithLabelValues(c.cfg.IndexPrefix).Inc()
+			c.logger.Error("document rejected by OpenSearch", "id", docs[i].ID, "index", c.IndexName(docs[i].Time), "status", status.Status, "type", status.Error.Type, "reason", status.Error.Reason)
+		}
+	}
+	documentsIndexed.WithLabelValues(c.cfg.IndexPrefix).Add(float64(indexed))
+	return retry, nil
+}
+
+// sign signs req with SigV4 when an AWS service is configured
+func (c *Client) sign(ctx context.Context, req *http.Request, body []byte) error {
+	if c.cfg.AWSService == "" {
+		return nil
+	}
+	creds, err := c.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials for OpenSearch: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), c.cfg.AWSService, c.cfg.AWSRegion, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign OpenSearch request: %w", err)
+	}
+	return nil
+}
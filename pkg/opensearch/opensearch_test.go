@@ -0,0 +1,167 @@
+package opensearch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// bulkServer is a fake bulk API that answers each request with the next of
+// statuses, a per-document status list, and records the indexed documents
+type bulkServer struct {
+	mu       sync.Mutex
+	statuses [][]int
+	indexed  map[string]string
+	requests int
+	auth     []string
+}
+
+func (b *bulkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.requests++
+	b.auth = append(b.auth, r.Header.Get("Authorization"))
+	if r.URL.Path != "/_bulk" || r.Header.Get("Content-Type") != "application/x-ndjson" {
+		http.Error(w, "unexpected request", http.StatusBadRequest)
+		return
+	}
+
+	var statuses []int
+	if len(b.statuses) > 0 {
+		statuses, b.statuses = b.statuses[0], b.statuses[1:]
+	}
+	if len(statuses) == 1 && statuses[0] == http.StatusServiceUnavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	var items []map[string]any
+	scanner := bufio.NewScanner(r.Body)
+	for i := 0; scanner.Scan(); i++ {
+		var action map[string]map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &action); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		scanner.Scan()
+		status := http.StatusCreated
+		if len(items) < len(statuses) {
+			status = statuses[len(items)]
+		}
+		item := map[string]any{"status": status}
+		if status >= 300 {
+			item["error"] = map[string]string{"type": "error", "reason": fmt.Sprintf("status %d", status)}
+		} else {
+			b.indexed[action["index"]["_id"]] = action["index"]["_index"]
+		}
+		items = append(items, map[string]any{"index": item})
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"errors": true, "items": items})
+}
+
+func newTestClient(t *testing.T, statuses [][]int, service string) (*Client, *bulkServer) {
+	t.Helper()
+	b := &bulkServer{statuses: statuses, indexed: map[string]string{}}
+	srv := httptest.NewServer(b)
+	t.Cleanup(srv.Close)
+	c := NewClient(Config{
+		Endpoint:      srv.URL + "/",
+		IndexPrefix:   "decisions",
+		Rollover:      RolloverDaily,
+		AWSRegion:     "us-east-1",
+		AWSService:    service,
+		MaxRetries:    2,
+		RetryInterval: time.Millisecond,
+	}, aws.NewCredentialsCache(credentials.NewStaticCredentialsProvider("AKID", "SECRET", "")), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return c, b
+}
+
+func TestClient_IndexName(t *testing.T) {
+	at := time.Date(2026, 10, 15, 23, 30, 0, 0, time.FixedZone("UTC-2", -2*60*60))
+	tests := []struct {
+		rollover string
+		want     string
+	}{
+		{RolloverDaily, "decisions-2026.10.16"},
+		{RolloverMonthly, "decisions-2026.10"},
+		{RolloverNone, "decisions"},
+	}
+	for _, tt := range tests {
+		c := &Client{cfg: Config{IndexPrefix: "decisions", Rollover: tt.rollover}}
+		if got := c.IndexName(at); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.rollover, tt.want, got)
+		}
+	}
+}
+
+func TestClient_IndexRetries(t *testing.T) {
+	// The whole request fails, then the second document is throttled and
+	// the third rejected for good, then the second is indexed
+	c, b := newTestClient(t, [][]int{
+		{http.StatusServiceUnavailable},
+		{http.StatusCreated, http.StatusTooManyRequests, http.StatusBadRequest},
+	}, "")
+	at := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	docs := []Document{{ID: "a", Time: at, Body: "{}"}, {ID: "b", Time: at, Body: "{}"}, {ID: "c", Time: at, Body: "{}"}}
+
+	unsent, err := c.Index(context.Background(), docs)
+	if err != nil || len(unsent) != 0 {
+		t.Fatalf("expected every document delivered, got %d left: %v", len(unsent), err)
+	}
+	if b.requests != 3 {
+		t.Errorf("expected 3 bulk requests, got %d", b.requests)
+	}
+	if len(b.indexed) != 2 || b.indexed["b"] != "decisions-2026.10.15" {
+		t.Errorf("unexpected indexed documents: %v", b.indexed)
+	}
+	if b.auth[0] != "" {
+		t.Errorf("expected unsigned requests, got Authorization %q", b.auth[0])
+	}
+}
+
+func TestClient_IndexGivesUp(t *testing.T) {
+	c, b := newTestClient(t, [][]int{{http.StatusServiceUnavailable}, {http.StatusServiceUnavailable}, {http.StatusServiceUnavailable}}, "es")
+	docs := []Document{{ID: "a", Time: time.Now(), Body: "{}"}}
+
+	unsent, err := c.Index(context.Background(), docs)
+	if err == nil || len(unsent) != 1 {
+		t.Fatalf("expected the document back with an error, got %d: %v", len(unsent), err)
+	}
+	if b.requests != 3 {
+		t.Errorf("expected 3 bulk requests, got %d", b.requests)
+	}
+	if !strings.HasPrefix(b.auth[0], "AWS4-HMAC-SHA256 Credential=AKID/") || !strings.Contains(b.auth[0], "/us-east-1/es/aws4_request") {
+		t.Errorf("expected a SigV4 signature, got Authorization %q", b.auth[0])
+	}
+}
+
+func TestDecisionSink_Send(t *testing.T) {
+	c, b := newTestClient(t, [][]int{{http.StatusCreated, http.StatusTooManyRequests}, {http.StatusTooManyRequests}, {http.StatusTooManyRequests}}, "")
+	at := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	entries := []*store.DecisionAuditEntry{
+		store.NewDecisionAuditEntry("123456789012", "arn:aws:iam::123456789012:role/dev", "rosa:CreateCluster", "", true, "policy", at, time.Hour),
+		store.NewDecisionAuditEntry("123456789012", "arn:aws:iam::123456789012:role/ops", "rosa:DeleteCluster", "", false, "organization", at, time.Hour),
+	}
+
+	undelivered, err := NewDecisionSink(c).Send(context.Background(), entries)
+	if err == nil || len(undelivered) != 1 || undelivered[0] != entries[1] {
+		t.Fatalf("expected the throttled entry back with an error, got %v: %v", undelivered, err)
+	}
+	if index := b.indexed["123456789012#"+entries[0].EntryID]; index != "decisions-2026.10.15" {
+		t.Errorf("expected the first entry in decisions-2026.10.15, got %q", index)
+	}
+}
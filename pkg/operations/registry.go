@@ -0,0 +1,304 @@
+// Package operations tracks the long-running requests on this replica, so
+// callers can follow their progress and cancel them, and operators can see
+// what is being pushed to Maestro and stop runaway submissions. Finished
+// operations keep their outcome for the registry's result TTL. Operations
+// are held in memory only: each replica lists and cancels its own.
+package operations
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// ErrCancelled is the cause of an operation's context once an operator
+// cancels it
+var ErrCancelled = errors.New("operation cancelled by an operator")
+
+// Operation types
+const (
+	TypeWorkSubmission = "work-submission"
+	// TypeClusterDeregistration drains a management cluster's resource
+	// bundles and deletes its Maestro consumer
+	TypeClusterDeregistration = "cluster-deregistration"
+	// TypeAccountPurge deletes everything held about an account
+	TypeAccountPurge = "account-purge"
+)
+
+// Operation states
+const (
+	// StateQueued operations are waiting for a work submission slot
+	StateQueued = "queued"
+	// StateRunning operations are being submitted to Maestro
+	StateRunning = "running"
+	// StateCancelling operations have been cancelled and are winding down
+	StateCancelling = "cancelling"
+	// StateSucceeded, StateFailed and StateCancelled operations have
+	// finished and are kept until their results expire
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+	StateCancelled = "cancelled"
+)
+
+// Finished reports whether state is a state operations end in
+func Finished(state string) bool {
+	return state == StateSucceeded || state == StateFailed || state == StateCancelled
+}
+
+// Operation is a request in flight
+type Operation struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	State     string    `json:"state"`
+	AccountID string    `json:"account_id"`
+	CallerARN string    `json:"caller_arn,omitempty"`
+	ClusterID string    `json:"cluster_id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Priority  string    `json:"priority,omitempty"`
+	Progress  *Progress `json:"progress,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// CompletedAt, ExpiresAt, Error and Result are set once the operation
+	// has finished
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	Result      any        `json:"result,omitempty"`
+}
+
+// Progress is how far an operation made of several steps has got
+type Progress struct {
+	// Step names what the operation is doing
+	Step string `json:"step"`
+	// Done of Total items of the step have been processed
+	Done  int `json:"done"`
+	Total int `json:"total"`
+	// Percent is Done of Total, rounded down
+	Percent int `json:"percent"`
+}
+
+type entry struct {
+	op     Operation
+	cancel context.CancelCauseFunc
+	// finished is set, with err and result, when Finish records the
+	// outcome
+	finished bool
+	err      error
+	result   any
+}
+
+type contextKey struct{}
+
+// Registry holds the operations in flight and, for the result TTL, those
+// that finished with an outcome. A nil Registry tracks nothing.
+type Registry struct {
+	mu        sync.Mutex
+	ops       map[string]*entry
+	resultTTL time.Duration
+	now       func() time.Time
+}
+
+// NewRegistry creates an empty Registry that forgets operations as soon as
+// they end
+func NewRegistry() *Registry {
+	return &Registry{ops: make(map[string]*entry), now: time.Now}
+}
+
+// WithResultTTL keeps finished operations, with their outcome, for ttl
+func (r *Registry) WithResultTTL(ttl time.Duration) *Registry {
+	r.resultTTL = ttl
+	return r
+}
+
+// Start registers op, assigning its ID and start time, and returns the
+// context to run it with, which is cancelled with ErrCancelled when an
+// operator cancels it, and the function that ends it
+func (r *Registry) Start(ctx context.Context, op Operation) (context.Context, func()) {
+	if r == nil {
+		return ctx, func() {}
+	}
+	op.ID = uuid.New().String()
+	op.StartedAt = clock.Stamp(r.now())
+	ctx, cancel := context.WithCancelCause(ctx)
+
+	r.mu.Lock()
+	r.prune()
+	r.ops[op.ID] = &entry{op: op, cancel: cancel}
+	r.mu.Unlock()
+
+	var once sync.Once
+	return context.WithValue(ctx, contextKey{}, op.ID), func() {
+		once.Do(func() {
+			r.end(op.ID, context.Cause(ctx))
+			cancel(nil)
+		})
+	}
+}
+
+// Go runs fn as an operation outliving the request ctx belongs to, and
+// returns the operation as started. fn's result and error are kept as the
+// operation's outcome.
+func (r *Registry) Go(ctx context.Context, op Operation, fn func(ctx context.Context) (any, error)) Operation {
+	opCtx, end := r.Start(context.WithoutCancel(ctx), op)
+	started, _ := r.Get(ID(opCtx))
+	go func() {
+		defer end()
+		result, err := fn(opCtx)
+		r.Finish(opCtx, result, err)
+	}()
+	return started
+}
+
+// Finish records the outcome of the operation ctx runs, if any, to be kept
+// for the result TTL once it ends. Operations ending without an outcome are
+// forgotten.
+func (r *Registry) Finish(ctx context.Context, result any, err error) {
+	if r == nil {
+		return
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.ops[id]; ok {
+		e.finished, e.result, e.err = true, result, err
+	}
+}
+
+// end settles the operation id, keeping it for the result TTL
+func (r *Registry) end(id string, cause error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e, ok := r.ops[id]
+	if !ok {
+		return
+	}
+	if r.resultTTL <= 0 || !e.finished {
+		delete(r.ops, id)
+		return
+	}
+
+	now := clock.Stamp(r.now())
+	expires := now.Add(r.resultTTL)
+	e.op.CompletedAt, e.op.ExpiresAt = &now, &expires
+	e.op.Result = e.result
+	switch {
+	case e.op.State == StateCancelling || errors.Is(cause, ErrCancelled):
+		e.op.State = StateCancelled
+	case e.err != nil:
+		e.op.State = StateFailed
+	default:
+		e.op.State = StateSucceeded
+	}
+	if e.err != nil {
+		e.op.Error = e.err.Error()
+	}
+}
+
+// prune forgets the operations whose results have expired; r.mu must be held
+func (r *Registry) prune() {
+	now := r.now()
+	for id, e := range r.ops {
+		if e.op.ExpiresAt != nil && !now.Before(*e.op.ExpiresAt) {
+			delete(r.ops, id)
+		}
+	}
+}
+
+// SetState records the state of the operation ctx runs, if any
+func (r *Registry) SetState(ctx context.Context, state string) {
+	if r == nil {
+		return
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.ops[id]; ok && e.op.State != StateCancelling && !Finished(e.op.State) {
+		e.op.State = state
+	}
+}
+
+// ID returns the ID of the operation ctx runs, or "" if it runs none
+func ID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// SetProgress records the progress of the operation ctx runs, if any
+func (r *Registry) SetProgress(ctx context.Context, progress Progress) {
+	if r == nil {
+		return
+	}
+	id, _ := ctx.Value(contextKey{}).(string)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.ops[id]; ok {
+		if progress.Total > 0 {
+			progress.Percent = min(100, progress.Done*100/progress.Total)
+		}
+		e.op.Progress = &progress
+	}
+}
+
+// List returns the operations in flight and those whose results have not
+// expired, oldest first
+func (r *Registry) List() []Operation {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	r.prune()
+	ops := make([]Operation, 0, len(r.ops))
+	for _, e := range r.ops {
+		ops = append(ops, e.op)
+	}
+	r.mu.Unlock()
+	slices.SortFunc(ops, func(a, b Operation) int { return a.StartedAt.Compare(b.StartedAt) })
+	return ops
+}
+
+// Get returns the operation with id
+func (r *Registry) Get(id string) (Operation, bool) {
+	if r == nil {
+		return Operation{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune()
+	e, ok := r.ops[id]
+	if !ok {
+		return Operation{}, false
+	}
+	return e.op, true
+}
+
+// Cancel cancels the operation with id, returning it as cancelled.
+// Operations that have finished are returned unchanged.
+func (r *Registry) Cancel(id string) (Operation, bool) {
+	if r == nil {
+		return Operation{}, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.prune()
+	e, ok := r.ops[id]
+	if !ok {
+		return Operation{}, false
+	}
+	if Finished(e.op.State) {
+		return e.op, true
+	}
+	e.op.State = StateCancelling
+	e.cancel(ErrCancelled)
+	return e.op, true
+}
+
+// Cancelled reports whether ctx ended because an operator cancelled its
+// operation
+func Cancelled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrCancelled)
+}
@@ -0,0 +1,154 @@
+package operations
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Cancel(t *testing.T) {
+	r := NewRegistry()
+
+	ctx, end := r.Start(context.Background(), Operation{Type: TypeWorkSubmission, State: StateQueued, AccountID: "123456789012"})
+	_, endOther := r.Start(context.Background(), Operation{Type: TypeWorkSubmission, State: StateQueued, AccountID: "210987654321"})
+	defer endOther()
+
+	ops := r.List()
+	if len(ops) != 2 || ops[0].AccountID != "123456789012" {
+		t.Fatalf("expected 2 operations oldest first, got %+v", ops)
+	}
+	r.SetState(ctx, StateRunning)
+	if op, _ := r.Get(ops[0].ID); op.State != StateRunning {
+		t.Errorf("expected state %s, got %s", StateRunning, op.State)
+	}
+
+	op, ok := r.Cancel(ops[0].ID)
+	if !ok || op.State != StateCancelling {
+		t.Fatalf("expected the operation to be cancelling, got %+v", op)
+	}
+	if ctx.Err() == nil || !Cancelled(ctx) {
+		t.Errorf("expected the operation's context to be cancelled by an operator, got %v", context.Cause(ctx))
+	}
+	r.SetState(ctx, StateRunning)
+	if op, _ := r.Get(ops[0].ID); op.State != StateCancelling {
+		t.Errorf("expected a cancelled operation to stay cancelling, got %s", op.State)
+	}
+
+	end()
+	if _, ok := r.Get(ops[0].ID); ok {
+		t.Error("expected an ended operation to be removed")
+	}
+	if _, ok := r.Cancel(ops[0].ID); ok {
+		t.Error("expected cancelling an ended operation to fail")
+	}
+	if Cancelled(context.Background()) {
+		t.Error("expected an uncancelled context not to report cancellation")
+	}
+}
+
+func TestRegistry_SetProgress(t *testing.T) {
+	r := NewRegistry()
+	ctx, end := r.Start(context.Background(), Operation{Type: TypeClusterDeregistration, State: StateRunning, AccountID: "123456789012"})
+	defer end()
+
+	id := r.List()[0].ID
+	if op, _ := r.Get(id); op.Progress != nil {
+		t.Errorf("expected no progress before any is set, got %+v", op.Progress)
+	}
+	r.SetProgress(ctx, Progress{Step: "draining-bundles", Done: 1, Total: 3})
+	before, _ := r.Get(id)
+	r.SetProgress(ctx, Progress{Step: "draining-bundles", Done: 2, Total: 3})
+	after, _ := r.Get(id)
+	if before.Progress.Done != 1 {
+		t.Errorf("expected a returned operation not to change with later progress, got %+v", before.Progress)
+	}
+	if after.Progress == nil || after.Progress.Done != 2 || after.Progress.Total != 3 || after.Progress.Percent != 66 {
+		t.Errorf("unexpected progress: %+v", after.Progress)
+	}
+}
+
+func TestRegistry_Nil(t *testing.T) {
+	var r *Registry
+	ctx, end := r.Start(context.Background(), Operation{})
+	defer end()
+	r.SetState(ctx, StateRunning)
+	r.SetProgress(ctx, Progress{Step: "step"})
+	if len(r.List()) != 0 {
+		t.Error("expected a nil registry to track nothing")
+	}
+	if _, ok := r.Cancel("missing"); ok {
+		t.Error("expected a nil registry to cancel nothing")
+	}
+}
+
+// wait returns the operation once it has finished
+func wait(t *testing.T, r *Registry, id string) Operation {
+	t.Helper()
+	for range 100 {
+		if op, ok := r.Get(id); ok && Finished(op.State) {
+			return op
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("operation %s did not finish", id)
+	return Operation{}
+}
+
+func TestRegistry_Go(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRegistry().WithResultTTL(time.Hour)
+	r.now = func() time.Time { return now }
+
+	ctx, cancelRequest := context.WithCancel(context.Background())
+	started := r.Go(ctx, Operation{Type: TypeAccountPurge, State: StateRunning, AccountID: "123456789012"}, func(ctx context.Context) (any, error) {
+		return "report", nil
+	})
+	// The operation outlives its request
+	cancelRequest()
+	if started.ID == "" || started.State != StateRunning {
+		t.Fatalf("unexpected started operation %+v", started)
+	}
+	op := wait(t, r, started.ID)
+	if op.State != StateSucceeded || op.Result != "report" || op.ExpiresAt == nil || !op.ExpiresAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("unexpected finished operation %+v", op)
+	}
+	if _, ok := r.Cancel(op.ID); !ok {
+		t.Error("expected a finished operation to be found")
+	} else if op, _ := r.Get(op.ID); op.State != StateSucceeded {
+		t.Errorf("expected cancelling a finished operation to change nothing, got %s", op.State)
+	}
+
+	failed := r.Go(context.Background(), Operation{Type: TypeAccountPurge, State: StateRunning}, func(ctx context.Context) (any, error) {
+		return nil, errors.New("throttled")
+	})
+	if op := wait(t, r, failed.ID); op.State != StateFailed || op.Error != "throttled" {
+		t.Errorf("expected a failed operation, got %+v", op)
+	}
+
+	release := make(chan struct{})
+	cancelled := r.Go(context.Background(), Operation{Type: TypeAccountPurge, State: StateRunning}, func(ctx context.Context) (any, error) {
+		<-ctx.Done()
+		close(release)
+		return nil, context.Cause(ctx)
+	})
+	r.Cancel(cancelled.ID)
+	<-release
+	if op := wait(t, r, cancelled.ID); op.State != StateCancelled {
+		t.Errorf("expected a cancelled operation, got %+v", op)
+	}
+
+	now = now.Add(time.Hour)
+	if ops := r.List(); len(ops) != 0 {
+		t.Errorf("expected expired results to be forgotten, got %+v", ops)
+	}
+}
+
+func TestRegistry_EndWithoutOutcome(t *testing.T) {
+	r := NewRegistry().WithResultTTL(time.Hour)
+	_, end := r.Start(context.Background(), Operation{Type: TypeWorkSubmission, State: StateRunning})
+	end()
+	if ops := r.List(); len(ops) != 0 {
+		t.Errorf("expected an operation without an outcome to be forgotten, got %+v", ops)
+	}
+}
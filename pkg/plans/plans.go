@@ -0,0 +1,168 @@
+// Package plans defines the plan tiers accounts are on and what each allows:
+// how many requests the account may make per rate limit window, how many
+// manifests one work may hold and whether bulk (chunked) work submission is
+// available. An account without a plan is on the default plan.
+package plans
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// Plans
+const (
+	Free     = "free"
+	Standard = "standard"
+	Premium  = "premium"
+)
+
+// Names lists the plans from the smallest to the largest
+var Names = []string{Free, Standard, Premium}
+
+// Valid reports whether name is a known plan
+func Valid(name string) bool {
+	for _, n := range Names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Limits is what a plan allows. Zero limits are unlimited.
+type Limits struct {
+	Plan string `json:"plan"`
+	// RateLimit is the requests the account may make per rate limit window
+	RateLimit int `json:"rateLimit,omitempty"`
+	// MaxManifests is the manifests one work may hold
+	MaxManifests int `json:"maxManifests,omitempty"`
+	// BulkSubmission allows chunked works, split across several ManifestWorks
+	BulkSubmission bool `json:"bulkSubmission"`
+}
+
+// Tiers holds the limits of every plan
+type Tiers map[string]Limits
+
+// NewTiers derives the plans from the platform limits: standard gets them
+// as configured, free a quarter of the request rate and a fifth of the
+// manifests without bulk submission, and premium four times the request
+// rate. No plan exceeds maxManifests, which protects Maestro rather than
+// metering accounts. A zero limit stays unlimited on every plan.
+func NewTiers(rateLimit, maxManifests int) Tiers {
+	return Tiers{
+		Free: {
+			Plan:         Free,
+			RateLimit:    fraction(rateLimit, 4),
+			MaxManifests: fraction(maxManifests, 5),
+		},
+		Standard: {
+			Plan:           Standard,
+			RateLimit:      rateLimit,
+			MaxManifests:   maxManifests,
+			BulkSubmission: true,
+		},
+		Premium: {
+			Plan:           Premium,
+			RateLimit:      rateLimit * 4,
+			MaxManifests:   maxManifests,
+			BulkSubmission: true,
+		},
+	}
+}
+
+// fraction divides limit by n without turning a limit into no limit
+func fraction(limit, n int) int {
+	if limit > 0 && limit < n {
+		return 1
+	}
+	return limit / n
+}
+
+// AccountGetter reads accounts, such as the authorizer
+type AccountGetter interface {
+	GetAccount(ctx context.Context, accountID string) (*store.Account, error)
+}
+
+// Resolver looks up the plan each account is on. Plans are cached for a TTL,
+// so a plan change takes up to that long to reach every replica.
+type Resolver struct {
+	accounts    AccountGetter
+	defaultPlan string
+	ttl         time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]resolved
+	lastSweep time.Time
+}
+
+type resolved struct {
+	plan    string
+	expires time.Time
+}
+
+// NewResolver creates a resolver reading accounts, which puts accounts
+// without a plan, or that are not enabled, on defaultPlan
+func NewResolver(accounts AccountGetter, defaultPlan string, ttl time.Duration) *Resolver {
+	return &Resolver{
+		accounts:    accounts,
+		defaultPlan: defaultPlan,
+		ttl:         ttl,
+		now:         time.Now,
+		entries:     make(map[string]resolved),
+	}
+}
+
+// AccountPlan returns the plan accountID is on
+func (r *Resolver) AccountPlan(ctx context.Context, accountID string) (string, error) {
+	now := r.now()
+	r.mu.Lock()
+	entry, ok := r.entries[accountID]
+	r.mu.Unlock()
+	if ok && now.Before(entry.expires) {
+		return entry.plan, nil
+	}
+
+	account, err := r.accounts.GetAccount(ctx, accountID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get account plan: %w", err)
+	}
+	plan := r.defaultPlan
+	if account != nil && account.Plan != "" {
+		plan = account.Plan
+	}
+
+	r.mu.Lock()
+	// Drop expired entries, at most once a TTL, so idle accounts do not
+	// accumulate
+	if now.Sub(r.lastSweep) >= r.ttl {
+		r.lastSweep = now
+		for id, e := range r.entries {
+			if !now.Before(e.expires) {
+				delete(r.entries, id)
+			}
+		}
+	}
+	r.entries[accountID] = resolved{plan: plan, expires: now.Add(r.ttl)}
+	r.mu.Unlock()
+	return plan, nil
+}
+
+// SetTTL changes how long plans are cached; plans already cached keep their
+// expiry
+func (r *Resolver) SetTTL(ttl time.Duration) {
+	r.mu.Lock()
+	r.ttl = ttl
+	r.mu.Unlock()
+}
+
+// Invalidate forgets accountID's cached plan, after it was changed here
+func (r *Resolver) Invalidate(accountID string) {
+	r.mu.Lock()
+	delete(r.entries, accountID)
+	r.mu.Unlock()
+}
@@ -0,0 +1,95 @@
+package plans
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// fakeAccounts counts the account lookups it answers
+type fakeAccounts struct {
+	accounts map[string]*store.Account
+	err      error
+	gets     int
+}
+
+func (f *fakeAccounts) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	f.gets++
+	return f.accounts[accountID], f.err
+}
+
+func TestNewTiers(t *testing.T) {
+	tiers := NewTiers(100, 500)
+
+	if got := tiers[Free]; got.RateLimit != 25 || got.MaxManifests != 100 || got.BulkSubmission {
+		t.Errorf("unexpected free plan %+v", got)
+	}
+	if got := tiers[Standard]; got.RateLimit != 100 || got.MaxManifests != 500 || !got.BulkSubmission {
+		t.Errorf("unexpected standard plan %+v", got)
+	}
+	if got := tiers[Premium]; got.RateLimit != 400 || got.MaxManifests != 500 || !got.BulkSubmission {
+		t.Errorf("unexpected premium plan %+v", got)
+	}
+
+	// Small limits stay limits and zero limits stay unlimited
+	tiers = NewTiers(2, 0)
+	if got := tiers[Free]; got.RateLimit != 1 || got.MaxManifests != 0 {
+		t.Errorf("unexpected free plan %+v", got)
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, name := range []string{Free, Standard, Premium} {
+		if !Valid(name) {
+			t.Errorf("expected %q to be valid", name)
+		}
+	}
+	if Valid("enterprise") || Valid("") {
+		t.Error("expected unknown plans to be invalid")
+	}
+}
+
+func TestResolver_AccountPlan(t *testing.T) {
+	accounts := &fakeAccounts{accounts: map[string]*store.Account{
+		"111111111111": {AccountID: "111111111111", Plan: Premium},
+		"222222222222": {AccountID: "222222222222"},
+	}}
+	now := time.Unix(1700000000, 0)
+	r := NewResolver(accounts, Standard, time.Minute)
+	r.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for id, want := range map[string]string{
+		"111111111111": Premium,
+		"222222222222": Standard,
+		"333333333333": Standard,
+	} {
+		if got, err := r.AccountPlan(ctx, id); err != nil || got != want {
+			t.Errorf("expected %s on %s, got %q (%v)", id, want, got, err)
+		}
+	}
+
+	// Cached until the TTL passes or the plan is invalidated
+	gets := accounts.gets
+	accounts.accounts["111111111111"].Plan = Free
+	if got, _ := r.AccountPlan(ctx, "111111111111"); got != Premium || accounts.gets != gets {
+		t.Errorf("expected the cached plan, got %q", got)
+	}
+	r.Invalidate("111111111111")
+	if got, _ := r.AccountPlan(ctx, "111111111111"); got != Free {
+		t.Errorf("expected the changed plan once invalidated, got %q", got)
+	}
+	now = now.Add(2 * time.Minute)
+	accounts.accounts["222222222222"].Plan = Premium
+	if got, _ := r.AccountPlan(ctx, "222222222222"); got != Premium {
+		t.Errorf("expected the changed plan once the TTL passed, got %q", got)
+	}
+
+	accounts.err = errors.New("throttled")
+	if _, err := r.AccountPlan(ctx, "444444444444"); err == nil {
+		t.Error("expected a failed lookup to return an error")
+	}
+}
@@ -0,0 +1,294 @@
+// Package policybackup periodically exports each account's AVP policy store to
+// S3 and reads those backups back for restore, since AVP has no native
+// point-in-time recovery.
+package policybackup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// keyTimeFormat names backups so that lexical and chronological order agree
+const keyTimeFormat = "20060102T150405Z"
+
+// Exporter lists accounts and exports their policy stores
+type Exporter interface {
+	ListAccounts(ctx context.Context) ([]*store.Account, error)
+	ExportPolicyStore(ctx context.Context, accountID string) (*authz.PolicyStoreExport, error)
+}
+
+// S3Client provides the S3 operations used for backups
+type S3Client interface {
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error)
+	DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error)
+}
+
+// Config configures the backup worker
+type Config struct {
+	Bucket string
+	// Prefix is prepended to every backup key
+	Prefix   string
+	Interval time.Duration
+	// Retention is how long backups are kept; the newest backup of each
+	// account is always kept regardless of age. 0 keeps everything.
+	Retention time.Duration
+}
+
+// Backup identifies one stored export
+type Backup struct {
+	Key       string    `json:"key"`
+	AccountID string    `json:"accountId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Worker exports every account's policy store on an interval and prunes
+// backups past their retention
+type Worker struct {
+	exporter Exporter
+	s3Client S3Client
+	cfg      Config
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// NewWorker creates a new backup worker
+func NewWorker(exporter Exporter, s3Client S3Client, cfg Config, logger *slog.Logger) *Worker {
+	return &Worker{
+		exporter: exporter,
+		s3Client: s3Client,
+		cfg:      cfg,
+		logger:   logger,
+		now:      time.Now,
+	}
+}
+
+// Run backs up all accounts immediately and then on every interval until ctx is done
+func (w *Worker) Run(ctx context.Context) {
+	w.logger.Info("policy store backup worker started", "interval", w.cfg.Interval, "bucket", w.cfg.Bucket, "retention", w.cfg.Retention)
+	ticker := time.NewTicker(w.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.BackupAll(ctx); err != nil {
+			w.logger.Error("policy store backup failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			w.logger.Info("policy store backup worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// BackupAll exports and stores the policy store of every non-privileged
+// account, then prunes expired backups. A failure for one account does not
+// stop the others; the number of failures is reported in the returned error.
+func (w *Worker) BackupAll(ctx context.Context) error {
+	accounts, err := w.exporter.ListAccounts(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	failed := 0
+	for _, account := range accounts {
+		if account.Privileged || account.PolicyStoreID == "" {
+			continue
+		}
+		if _, err := w.BackupAccount(ctx, account.AccountID); err != nil {
+			w.logger.Error("failed to back up policy store", "error", err, "account_id", account.AccountID)
+			failed++
+			continue
+		}
+		if err := w.prune(ctx, account.AccountID); err != nil {
+			w.logger.Warn("failed to prune policy store backups", "error", err, "account_id", account.AccountID)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to back up %d of %d accounts", failed, len(accounts))
+	}
+	return nil
+}
+
+// BackupAccount exports one account's policy store and writes it to S3
+func (w *Worker) BackupAccount(ctx context.Context, accountID string) (*Backup, error) {
+	export, err := w.exporter.ExportPolicyStore(ctx, accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export policy store: %w", err)
+	}
+
+	body, err := json.Marshal(export)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal policy store export: %w", err)
+	}
+
+	createdAt := w.now().UTC()
+	key := w.key(accountID, createdAt)
+	_, err = w.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(w.cfg.Bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(body),
+		ContentType: aws.String("application/json"),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to write policy store backup: %w", err)
+	}
+
+	w.logger.Info("policy store backed up",
+		"account_id", accountID,
+		"key", key,
+		"policies", len(export.Policies),
+		"attachments", len(export.Attachments),
+	)
+	return &Backup{Key: key, AccountID: accountID, CreatedAt: createdAt}, nil
+}
+
+// List returns the backups of an account, oldest first
+func (w *Worker) List(ctx context.Context, accountID string) ([]Backup, error) {
+	prefix := w.accountPrefix(accountID)
+
+	var backups []Backup
+	var token *string
+	for {
+		resp, err := w.s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(w.cfg.Bucket),
+			Prefix:            aws.String(prefix),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list policy store backups: %w", err)
+		}
+
+		for _, obj := range resp.Contents {
+			key := aws.ToString(obj.Key)
+			createdAt, err := time.Parse(keyTimeFormat, strings.TrimSuffix(path.Base(key), ".json"))
+			if err != nil {
+				// Not written by this worker
+				continue
+			}
+			backups = append(backups, Backup{Key: key, AccountID: accountID, CreatedAt: createdAt})
+		}
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+		token = resp.NextContinuationToken
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.Before(backups[j].CreatedAt) })
+	return backups, nil
+}
+
+// Get reads a backup by key. The key must belong to accountID.
+func (w *Worker) Get(ctx context.Context, accountID, key string) (*authz.PolicyStoreExport, error) {
+	if !strings.HasPrefix(key, w.accountPrefix(accountID)) {
+		return nil, fmt.Errorf("backup %s does not belong to account %s", key, accountID)
+	}
+
+	resp, err := w.s3Client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(w.cfg.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy store backup: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read policy store backup: %w", err)
+	}
+
+	var export authz.PolicyStoreExport
+	if err := json.Unmarshal(body, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse policy store backup: %w", err)
+	}
+	return &export, nil
+}
+
+// Purge deletes every object under an account's prefix, including objects
+// not written by this worker, and returns how many it deleted
+func (w *Worker) Purge(ctx context.Context, accountID string) (int, error) {
+	deleted := 0
+	var token *string
+	for {
+		resp, err := w.s3Client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+			Bucket:            aws.String(w.cfg.Bucket),
+			Prefix:            aws.String(w.accountPrefix(accountID)),
+			ContinuationToken: token,
+		})
+		if err != nil {
+			return deleted, fmt.Errorf("failed to list policy store backups: %w", err)
+		}
+
+		for _, obj := range resp.Contents {
+			if _, err := w.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+				Bucket: aws.String(w.cfg.Bucket),
+				Key:    obj.Key,
+			}); err != nil {
+				return deleted, fmt.Errorf("failed to delete backup %s: %w", aws.ToString(obj.Key), err)
+			}
+			deleted++
+		}
+
+		if !aws.ToBool(resp.IsTruncated) {
+			break
+		}
+		token = resp.NextContinuationToken
+	}
+	return deleted, nil
+}
+
+// prune deletes an account's backups older than the retention period, always
+// keeping the newest one
+func (w *Worker) prune(ctx context.Context, accountID string) error {
+	if w.cfg.Retention <= 0 {
+		return nil
+	}
+
+	backups, err := w.List(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	cutoff := w.now().UTC().Add(-w.cfg.Retention)
+	for _, b := range backups[:max(len(backups)-1, 0)] {
+		if !b.CreatedAt.Before(cutoff) {
+			break
+		}
+		if _, err := w.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+			Bucket: aws.String(w.cfg.Bucket),
+			Key:    aws.String(b.Key),
+		}); err != nil {
+			return fmt.Errorf("failed to delete expired backup %s: %w", b.Key, err)
+		}
+		w.logger.Debug("expired policy store backup deleted", "account_id", accountID, "key", b.Key)
+	}
+	return nil
+}
+
+func (w *Worker) accountPrefix(accountID string) string {
+	return path.Join(w.cfg.Prefix, accountID) + "/"
+}
+
+func (w *Worker) key(accountID string, t time.Time) string {
+	return w.accountPrefix(accountID) + t.Format(keyTimeFormat) + ".json"
+}
@@ -0,0 +1,181 @@
+package policybackup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+type fakeExporter struct {
+	accounts []*store.Account
+	failFor  string
+}
+
+func (f *fakeExporter) ListAccounts(ctx context.Context) ([]*store.Account, error) {
+	return f.accounts, nil
+}
+
+func (f *fakeExporter) ExportPolicyStore(ctx context.Context, accountID string) (*authz.PolicyStoreExport, error) {
+	if accountID == f.failFor {
+		return nil, errors.New("policy store unavailable")
+	}
+	return &authz.PolicyStoreExport{
+		AccountID:   accountID,
+		Policies:    []authz.ExportedPolicy{{PolicyID: "p1", Name: "admins", CedarPolicy: "permit(principal, action, resource);"}},
+		Attachments: []authz.ExportedAttachment{{PolicyID: "p1", TargetType: authz.TargetTypeGroup, TargetID: "g1"}},
+	}, nil
+}
+
+// fakeS3 is an in-memory bucket that pages ListObjectsV2 one object at a time
+type fakeS3 struct {
+	objects map[string][]byte
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}}
+}
+
+func (f *fakeS3) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	body, err := io.ReadAll(params.Body)
+	if err != nil {
+		return nil, err
+	}
+	f.objects[aws.ToString(params.Key)] = body
+	return &s3.PutObjectOutput{}, nil
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	body, ok := f.objects[aws.ToString(params.Key)]
+	if !ok {
+		return nil, &s3types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(body))}, nil
+}
+
+func (f *fakeS3) ListObjectsV2(ctx context.Context, params *s3.ListObjectsV2Input, optFns ...func(*s3.Options)) (*s3.ListObjectsV2Output, error) {
+	var keys []string
+	for k := range f.objects {
+		if strings.HasPrefix(k, aws.ToString(params.Prefix)) && k > aws.ToString(params.ContinuationToken) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if len(keys) == 0 {
+		return &s3.ListObjectsV2Output{IsTruncated: aws.Bool(false)}, nil
+	}
+	return &s3.ListObjectsV2Output{
+		Contents:              []s3types.Object{{Key: aws.String(keys[0])}},
+		IsTruncated:           aws.Bool(len(keys) > 1),
+		NextContinuationToken: aws.String(keys[0]),
+	}, nil
+}
+
+func (f *fakeS3) DeleteObject(ctx context.Context, params *s3.DeleteObjectInput, optFns ...func(*s3.Options)) (*s3.DeleteObjectOutput, error) {
+	delete(f.objects, aws.ToString(params.Key))
+	return &s3.DeleteObjectOutput{}, nil
+}
+
+func TestWorker_BackupAll(t *testing.T) {
+	s3Client := newFakeS3()
+	exporter := &fakeExporter{accounts: []*store.Account{
+		{AccountID: "111111111111", PolicyStoreID: "ps-1"},
+		{AccountID: "222222222222", Privileged: true},
+		{AccountID: "333333333333", PolicyStoreID: "ps-3"},
+	}}
+	w := NewWorker(exporter, s3Client, Config{Bucket: "backups", Prefix: "policy-stores"}, testLogger())
+	w.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	require.NoError(t, w.BackupAll(context.Background()))
+
+	assert.Len(t, s3Client.objects, 2)
+	assert.Contains(t, s3Client.objects, "policy-stores/111111111111/20260102T030405Z.json")
+	assert.Contains(t, s3Client.objects, "policy-stores/333333333333/20260102T030405Z.json")
+
+	export, err := w.Get(context.Background(), "111111111111", "policy-stores/111111111111/20260102T030405Z.json")
+	require.NoError(t, err)
+	assert.Equal(t, "111111111111", export.AccountID)
+	require.Len(t, export.Attachments, 1)
+	assert.Equal(t, authz.TargetTypeGroup, export.Attachments[0].TargetType)
+}
+
+func TestWorker_BackupAllContinuesPastFailures(t *testing.T) {
+	s3Client := newFakeS3()
+	exporter := &fakeExporter{
+		accounts: []*store.Account{
+			{AccountID: "111111111111", PolicyStoreID: "ps-1"},
+			{AccountID: "333333333333", PolicyStoreID: "ps-3"},
+		},
+		failFor: "111111111111",
+	}
+	w := NewWorker(exporter, s3Client, Config{Bucket: "backups"}, testLogger())
+
+	err := w.BackupAll(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "1 of 2")
+	assert.Len(t, s3Client.objects, 1)
+}
+
+func TestWorker_Retention(t *testing.T) {
+	s3Client := newFakeS3()
+	exporter := &fakeExporter{accounts: []*store.Account{{AccountID: "111111111111", PolicyStoreID: "ps-1"}}}
+	w := NewWorker(exporter, s3Client, Config{Bucket: "backups", Retention: 48 * time.Hour}, testLogger())
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	for i := 0; i < 4; i++ {
+		require.NoError(t, w.BackupAll(context.Background()))
+		now = now.Add(24 * time.Hour)
+	}
+
+	backups, err := w.List(context.Background(), "111111111111")
+	require.NoError(t, err)
+	require.Len(t, backups, 3)
+	assert.Equal(t, time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC), backups[0].CreatedAt)
+	assert.Equal(t, time.Date(2026, 1, 4, 0, 0, 0, 0, time.UTC), backups[2].CreatedAt)
+}
+
+func TestWorker_RetentionKeepsNewest(t *testing.T) {
+	s3Client := newFakeS3()
+	exporter := &fakeExporter{accounts: []*store.Account{{AccountID: "111111111111", PolicyStoreID: "ps-1"}}}
+	w := NewWorker(exporter, s3Client, Config{Bucket: "backups", Retention: time.Hour}, testLogger())
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+	_, err := w.BackupAccount(context.Background(), "111111111111")
+	require.NoError(t, err)
+
+	// Backups have stopped (e.g. the store was deleted); the last one survives
+	now = now.Add(30 * 24 * time.Hour)
+	require.NoError(t, w.prune(context.Background(), "111111111111"))
+
+	backups, err := w.List(context.Background(), "111111111111")
+	require.NoError(t, err)
+	assert.Len(t, backups, 1)
+}
+
+func TestWorker_GetRejectsOtherAccounts(t *testing.T) {
+	w := NewWorker(&fakeExporter{}, newFakeS3(), Config{Bucket: "backups"}, testLogger())
+
+	_, err := w.Get(context.Background(), "111111111111", "222222222222/20260101T000000Z.json")
+	assert.Error(t, err)
+}
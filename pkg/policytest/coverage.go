@@ -0,0 +1,146 @@
+package policytest
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+// maxStatementText is the longest statement text a coverage report shows
+const maxStatementText = 100
+
+// StatementCoverage is whether one statement of a file's policy decided
+// any of its cases
+type StatementCoverage struct {
+	// Statement counts from one, in the order the policy lists them
+	Statement int    `json:"statement"`
+	Effect    string `json:"effect"`
+	Text      string `json:"text"`
+	// Cases are the cases, counted from one, the statement decided
+	Cases []int `json:"cases,omitempty"`
+}
+
+// Tested reports whether a case exercised the statement
+func (s *StatementCoverage) Tested() bool {
+	return len(s.Cases) > 0
+}
+
+// FileCoverage is the statement coverage of one test file
+type FileCoverage struct {
+	File       string              `json:"file"`
+	ID         string              `json:"id,omitempty"`
+	Statements []StatementCoverage `json:"statements"`
+}
+
+// Coverage reports which statements of each file's policy decided at least
+// one of its cases. A permit is exercised by an allow it grants and a
+// forbid by a deny it causes; a statement that never decided a case is not
+// tested, even when cases expecting its effect pass. results are those Run
+// returned for files, and must come from an engine reporting determining
+// statements, such as LocalEngine.
+func Coverage(files []PolicyTestFile, results []Result) []FileCoverage {
+	coverage := make([]FileCoverage, 0, len(files))
+	next := 0
+	for _, file := range files {
+		fc := FileCoverage{File: file.Name, ID: file.ID}
+		for i, statement := range authz.NormalizePolicy(file.Policy) {
+			fc.Statements = append(fc.Statements, StatementCoverage{
+				Statement: i + 1,
+				Effect:    statementEffect(statement),
+				Text:      truncate(statement, maxStatementText),
+			})
+		}
+		for range file.TestCases {
+			if next >= len(results) {
+				break
+			}
+			r := &results[next]
+			next++
+			for _, n := range r.Determining {
+				if n >= 1 && n <= len(fc.Statements) {
+					fc.Statements[n-1].Cases = append(fc.Statements[n-1].Cases, r.Case)
+				}
+			}
+		}
+		coverage = append(coverage, fc)
+	}
+	return coverage
+}
+
+// CoverageSummary counts statements across files
+type CoverageSummary struct {
+	Statements int `json:"statements"`
+	Tested     int `json:"tested"`
+}
+
+// Untested returns the number of statements no case exercised
+func (s CoverageSummary) Untested() int {
+	return s.Statements - s.Tested
+}
+
+// SummarizeCoverage counts statements across files
+func SummarizeCoverage(coverage []FileCoverage) CoverageSummary {
+	var s CoverageSummary
+	for _, fc := range coverage {
+		for i := range fc.Statements {
+			s.Statements++
+			if fc.Statements[i].Tested() {
+				s.Tested++
+			}
+		}
+	}
+	return s
+}
+
+// WriteCoverage writes the coverage of each file, one line per untested
+// statement, and a summary line
+func WriteCoverage(w io.Writer, coverage []FileCoverage) {
+	for _, fc := range coverage {
+		name := fc.File
+		if fc.ID != "" {
+			name = fc.ID
+		}
+		tested := 0
+		for i := range fc.Statements {
+			if fc.Statements[i].Tested() {
+				tested++
+			}
+		}
+		fmt.Fprintf(w, "%s: %d/%d statements exercised\n", name, tested, len(fc.Statements))
+		for i := range fc.Statements {
+			if s := &fc.Statements[i]; !s.Tested() {
+				fmt.Fprintf(w, "  [UNTESTED] %s statement %d: %s\n", s.Effect, s.Statement, s.Text)
+			}
+		}
+	}
+	s := SummarizeCoverage(coverage)
+	percent := 100.0
+	if s.Statements > 0 {
+		percent = float64(s.Tested) * 100 / float64(s.Statements)
+	}
+	fmt.Fprintf(w, "\n%d of %d statements exercised (%.0f%%)\n", s.Tested, s.Statements, percent)
+}
+
+// statementEffect returns permit or forbid for a statement normalized by
+// authz.NormalizePolicy, skipping its annotations
+func statementEffect(statement string) string {
+	for strings.HasPrefix(statement, "@") {
+		_, rest, ok := strings.Cut(statement, ")")
+		if !ok {
+			break
+		}
+		statement = strings.TrimSpace(rest)
+	}
+	effect, _, _ := strings.Cut(statement, " ")
+	return effect
+}
+
+// truncate shortens s to at most n bytes, marking the cut
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-3] + "..."
+}
@@ -0,0 +1,41 @@
+package policytest
+
+import (
+	"context"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+// LocalEngine decides checks with an authz.PolicyEvaluator, typically over
+// the cedar-agent mock, without a running API
+type LocalEngine struct {
+	evaluator *authz.PolicyEvaluator
+}
+
+// NewLocalEngine creates a new LocalEngine
+func NewLocalEngine(evaluator *authz.PolicyEvaluator) *LocalEngine {
+	return &LocalEngine{evaluator: evaluator}
+}
+
+// Evaluate decides check against policies
+func (e *LocalEngine) Evaluate(ctx context.Context, policies []string, check *Check) (*Decision, error) {
+	evaluated, err := e.evaluator.Evaluate(ctx, policies, &authz.AuthzRequest{
+		AccountID:    check.AccountID,
+		CallerARN:    check.Principal,
+		Action:       check.Action,
+		Resource:     check.Resource,
+		Context:      check.Context,
+		ResourceTags: check.ResourceTags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	decision := &Decision{Result: ResultDeny}
+	if evaluated.Allowed {
+		decision.Result = ResultAllow
+	}
+	for _, s := range evaluated.Determining {
+		decision.Determining = append(decision.Determining, StatementRef{Policy: s.Policy, Statement: s.Statement})
+	}
+	return decision, nil
+}
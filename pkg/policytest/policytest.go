@@ -0,0 +1,171 @@
+// Package policytest runs declarative Cedar policy tests: files naming a
+// policy and the authorization requests it must allow or deny. The same
+// files run against a live API, where each case creates the policies in an
+// account and asks /api/v0/authz/check, or locally against the cedar-agent
+// mock, so tenants can test their policies in CI before applying them.
+package policytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// Expected results of a test case
+const (
+	ResultAllow = "ALLOW"
+	ResultDeny  = "DENY"
+)
+
+// PolicyTestFile is a policy and the test cases it must pass. It is read
+// from JSON or YAML.
+type PolicyTestFile struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Policy      string `json:"policy,omitempty"`
+	// PolicyFile names a .cedar file holding the policy, relative to the
+	// test file; Policy wins when both are set
+	PolicyFile string     `json:"policyFile,omitempty"`
+	TestCases  []TestCase `json:"testCases"`
+	Notes      string     `json:"notes,omitempty"`
+}
+
+// TestCase is a single authorization request and its expected result
+type TestCase struct {
+	Description    string         `json:"description"`
+	Principal      *TestPrincipal `json:"principal,omitempty"`
+	Request        TestRequest    `json:"request"`
+	ExpectedResult string         `json:"expectedResult"` // "ALLOW" or "DENY"
+	// AdditionalPolicies are attached next to the file's policy for this
+	// case only
+	AdditionalPolicies []string `json:"additionalPolicies,omitempty"`
+}
+
+// TestPrincipal is the caller of a test case
+type TestPrincipal struct {
+	// Username names the IAM user making the request; empty is testuser
+	Username string            `json:"username,omitempty"`
+	Tags     map[string]string `json:"tags,omitempty"`
+}
+
+// TestRequest is the authorization request of a test case
+type TestRequest struct {
+	Action       string         `json:"action"`
+	Resource     string         `json:"resource"`
+	Context      map[string]any `json:"context,omitempty"`
+	ResourceTags map[string]any `json:"resourceTags,omitempty"`
+}
+
+// Load reads the test files at paths. A directory is walked for .json,
+// .yaml and .yml files. Files without a name are named by their path
+// relative to the directory they were found in.
+func Load(paths ...string) ([]PolicyTestFile, error) {
+	var files []PolicyTestFile
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			file, err := LoadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			if file.Name == "" {
+				file.Name = filepath.Base(path)
+			}
+			files = append(files, *file)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(name string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || !isTestFile(name) {
+				return nil
+			}
+			file, err := LoadFile(name)
+			if err != nil {
+				return err
+			}
+			if file.Name == "" {
+				file.Name, _ = filepath.Rel(path, name)
+			}
+			files = append(files, *file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// LoadFile reads one test file and the policy file it names
+func LoadFile(path string) (*PolicyTestFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	data, err = utilyaml.ToJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	var file PolicyTestFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.loadPolicyFile(filepath.Dir(path)); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := file.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &file, nil
+}
+
+// Validate checks that the file has a policy and that every case names an
+// action, a resource and an expected result
+func (f *PolicyTestFile) Validate() error {
+	if f.Policy == "" {
+		return fmt.Errorf("policy or policyFile is required")
+	}
+	for i, tc := range f.TestCases {
+		if tc.Request.Action == "" || tc.Request.Resource == "" {
+			return fmt.Errorf("test case %d: request.action and request.resource are required", i+1)
+		}
+		if tc.ExpectedResult != ResultAllow && tc.ExpectedResult != ResultDeny {
+			return fmt.Errorf("test case %d: expectedResult must be %s or %s, got %q", i+1, ResultAllow, ResultDeny, tc.ExpectedResult)
+		}
+	}
+	return nil
+}
+
+// loadPolicyFile reads the Cedar policy from the companion .cedar file if
+// policyFile is set
+func (f *PolicyTestFile) loadPolicyFile(dir string) error {
+	if f.PolicyFile == "" || f.Policy != "" {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(dir, f.PolicyFile))
+	if err != nil {
+		return fmt.Errorf("failed to read policy file %s: %w", f.PolicyFile, err)
+	}
+	f.Policy = string(data)
+	return nil
+}
+
+func isTestFile(name string) bool {
+	switch filepath.Ext(name) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
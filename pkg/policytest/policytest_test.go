@@ -0,0 +1,304 @@
+package policytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a/allow.cedar", "permit(principal, action, resource);")
+	writeFile(t, dir, "a/allow.json", `{
+		"id": "allow-all",
+		"policyFile": "allow.cedar",
+		"testCases": [{"description": "any", "request": {"action": "rosa:ListClusters", "resource": "*"}, "expectedResult": "ALLOW"}]
+	}`)
+	writeFile(t, dir, "b/deny.yaml", `
+policy: forbid(principal, action, resource);
+testCases:
+  - description: tagged
+    principal:
+      username: alice
+    request:
+      action: rosa:DescribeCluster
+      resource: arn:aws:rosa:us-east-1:123456789012:cluster/c1
+      resourceTags:
+        replicas: 3
+    expectedResult: DENY
+`)
+	writeFile(t, dir, "b/notes.txt", "ignored")
+
+	files, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(files) != 2 {
+		t.Fatalf("Load() returned %d files, want 2", len(files))
+	}
+	if files[0].Policy != "permit(principal, action, resource);" {
+		t.Errorf("policy from policyFile = %q", files[0].Policy)
+	}
+	if files[1].Name != filepath.Join("b", "deny.yaml") {
+		t.Errorf("default name = %q, want b/deny.yaml", files[1].Name)
+	}
+	tc := files[1].TestCases[0]
+	if tc.Principal == nil || tc.Principal.Username != "alice" || tc.ExpectedResult != ResultDeny {
+		t.Errorf("YAML test case = %+v", tc)
+	}
+	if got := newCheck("111111111111", &tc).ResourceTags["replicas"]; got != "3" {
+		t.Errorf("resource tag = %q, want 3", got)
+	}
+
+	single, err := Load(filepath.Join(dir, "b", "deny.yaml"))
+	if err != nil {
+		t.Fatalf("Load(file) error = %v", err)
+	}
+	if single[0].Name != "deny.yaml" {
+		t.Errorf("single file name = %q, want deny.yaml", single[0].Name)
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		problem string
+	}{
+		{"no policy", `{"testCases": []}`, "policy"},
+		{"no action", `{"policy": "permit(principal, action, resource);", "testCases": [{"request": {"resource": "*"}, "expectedResult": "ALLOW"}]}`, "action"},
+		{"bad result", `{"policy": "permit(principal, action, resource);", "testCases": [{"request": {"action": "rosa:ListClusters", "resource": "*"}, "expectedResult": "MAYBE"}]}`, "expectedResult"},
+		{"missing policy file", `{"policyFile": "missing.cedar", "testCases": []}`, "missing.cedar"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeFile(t, t.TempDir(), "test.json", tt.content)
+			_, err := Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.problem) {
+				t.Errorf("Load() error = %v, want it to mention %q", err, tt.problem)
+			}
+		})
+	}
+}
+
+// TestLoad_Testdata keeps the files the e2e suite runs loadable
+func TestLoad_Testdata(t *testing.T) {
+	files, err := Load("../authz/testdata/policies")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if len(files) == 0 {
+		t.Fatal("Load() found no test files")
+	}
+}
+
+// fakeEngine allows checks whose action is in allowed, and reports the
+// statements in determining as deciding checks of their action
+type fakeEngine struct {
+	allowed     map[string]bool
+	determining map[string][]StatementRef
+	failing     string
+	policies    [][]string
+}
+
+func (e *fakeEngine) Evaluate(ctx context.Context, policies []string, check *Check) (*Decision, error) {
+	e.policies = append(e.policies, policies)
+	if check.Action == e.failing {
+		return nil, errors.New("engine failed")
+	}
+	decision := &Decision{Result: ResultDeny, Determining: e.determining[check.Action]}
+	if e.allowed[check.Action] {
+		decision.Result = ResultAllow
+	}
+	return decision, nil
+}
+
+func TestRun(t *testing.T) {
+	files := []PolicyTestFile{{
+		Name:   "f.json",
+		Policy: "p1",
+		TestCases: []TestCase{
+			{Description: "pass", Request: TestRequest{Action: "a"}, ExpectedResult: ResultAllow, AdditionalPolicies: []string{"p2"}},
+			{Description: "fail", Request: TestRequest{Action: "b"}, ExpectedResult: ResultAllow},
+			{Description: "error", Request: TestRequest{Action: "c"}, ExpectedResult: ResultDeny},
+		},
+	}}
+	engine := &fakeEngine{allowed: map[string]bool{"a": true}, failing: "c"}
+
+	results := Run(context.Background(), engine, "123456789012", files)
+	if len(results) != 3 {
+		t.Fatalf("Run() returned %d results, want 3", len(results))
+	}
+	if got := engine.policies[0]; len(got) != 2 || got[0] != "p1" || got[1] != "p2" {
+		t.Errorf("first case policies = %v, want [p1 p2]", got)
+	}
+	if s := Summarize(results); s != (Summary{Passed: 1, Failed: 1, Errors: 1}) {
+		t.Errorf("Summarize() = %+v", s)
+	}
+
+	var buf bytes.Buffer
+	WriteText(&buf, results)
+	for _, want := range []string{
+		"[PASS] f.json/1 pass: ALLOW",
+		"[FAIL] f.json/2 fail: got DENY, expected ALLOW",
+		"[ERROR] f.json/3 error: engine failed",
+		"1 passed, 1 failed, 1 errors",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteText() output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	files := []PolicyTestFile{{
+		Name: "f.json",
+		Policy: `permit (principal, action == ROSA::Action::"ListClusters", resource);
+@id("no-delete")
+forbid (principal, action == ROSA::Action::"DeleteCluster", resource);
+permit (principal, action == ROSA::Action::"CreateCluster", resource);`,
+		TestCases: []TestCase{
+			{Request: TestRequest{Action: "list"}, ExpectedResult: ResultAllow},
+			{Request: TestRequest{Action: "delete"}, ExpectedResult: ResultDeny},
+			{Request: TestRequest{Action: "other"}, ExpectedResult: ResultDeny},
+		},
+	}}
+	engine := &fakeEngine{
+		allowed: map[string]bool{"list": true},
+		determining: map[string][]StatementRef{
+			"list": {{Policy: 0, Statement: 0}},
+			// Statements of additional policies are not covered
+			"delete": {{Policy: 0, Statement: 1}, {Policy: 1, Statement: 0}},
+		},
+	}
+
+	results := Run(context.Background(), engine, "123456789012", files)
+	coverage := Coverage(files, results)
+	if len(coverage) != 1 || len(coverage[0].Statements) != 3 {
+		t.Fatalf("Coverage() = %+v, want one file of 3 statements", coverage)
+	}
+	statements := coverage[0].Statements
+	if got := statements[1]; got.Effect != "forbid" || len(got.Cases) != 1 || got.Cases[0] != 2 {
+		t.Errorf("forbid coverage = %+v, want decided case 2", got)
+	}
+	if statements[2].Tested() {
+		t.Errorf("CreateCluster permit is tested, want untested")
+	}
+	if s := SummarizeCoverage(coverage); s != (CoverageSummary{Statements: 3, Tested: 2}) || s.Untested() != 1 {
+		t.Errorf("SummarizeCoverage() = %+v", s)
+	}
+
+	var buf bytes.Buffer
+	WriteCoverage(&buf, coverage)
+	for _, want := range []string{
+		"f.json: 2/3 statements exercised",
+		`[UNTESTED] permit statement 3: permit (principal, action == ROSA::Action::"CreateCluster", resource);`,
+		"2 of 3 statements exercised (67%)",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("WriteCoverage() output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestRemoteEngine(t *testing.T) {
+	var calls []string
+	var check map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		if r.Header.Get("X-Amz-Account-Id") != "123456789012" {
+			t.Errorf("%s %s missing account header", r.Method, r.URL.Path)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "POST /api/v0/authz/policies":
+			_, _ = w.Write([]byte(`{"policyId":"pol-1"}`))
+		case "POST /api/v0/authz/groups":
+			_, _ = w.Write([]byte(`{"groupId":"grp-1"}`))
+		case "POST /api/v0/authz/attachments":
+			if r.URL.Query().Get("wait") != "true" {
+				t.Error("attachment created without wait=true")
+			}
+			_, _ = w.Write([]byte(`{"attachmentId":"att-1"}`))
+		case "POST /api/v0/authz/check":
+			_ = json.NewDecoder(r.Body).Decode(&check)
+			_, _ = w.Write([]byte(`{"decision":"ALLOW"}`))
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	engine := NewRemoteEngine(RemoteConfig{BaseURL: srv.URL + "/", AccountID: "123456789012"}, nil)
+	got, err := engine.Evaluate(context.Background(), []string{"permit(principal, action, resource);"}, &Check{
+		Principal: "arn:aws:iam::123456789012:user/alice",
+		Action:    "rosa:ListClusters",
+		Resource:  "*",
+	})
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+	if got.Result != ResultAllow || got.Determining != nil {
+		t.Errorf("Evaluate() = %+v, want ALLOW without determining statements", got)
+	}
+	if check["principal"] != "arn:aws:iam::123456789012:user/alice" {
+		t.Errorf("check principal = %v", check["principal"])
+	}
+
+	want := []string{
+		"POST /api/v0/authz/policies",
+		"POST /api/v0/authz/groups",
+		"PUT /api/v0/authz/groups/grp-1/members",
+		"POST /api/v0/authz/attachments",
+		"POST /api/v0/authz/check",
+		"DELETE /api/v0/authz/attachments/att-1",
+		"DELETE /api/v0/authz/groups/grp-1",
+		"DELETE /api/v0/authz/policies/pol-1",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls =\n%s\nwant\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestRemoteEngine_CleansUpOnFailure(t *testing.T) {
+	var deleted []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v0/authz/policies":
+			_, _ = w.Write([]byte(`{"policyId":"pol-1"}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v0/authz/groups":
+			http.Error(w, `{"code":"Forbidden"}`, http.StatusForbidden)
+		case r.Method == http.MethodDelete:
+			deleted = append(deleted, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	engine := NewRemoteEngine(RemoteConfig{BaseURL: srv.URL}, nil)
+	_, err := engine.Evaluate(context.Background(), []string{"permit(principal, action, resource);"}, &Check{Principal: "p"})
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Evaluate() error = %v, want the 403", err)
+	}
+	if len(deleted) != 1 || deleted[0] != "/api/v0/authz/policies/pol-1" {
+		t.Errorf("deleted = %v, want the created policy", deleted)
+	}
+}
@@ -0,0 +1,199 @@
+package policytest
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// RemoteConfig is the API a RemoteEngine runs checks against
+type RemoteConfig struct {
+	// BaseURL is the API's base URL, such as https://api.example.com
+	BaseURL string
+	// AccountID and CallerARN are sent as X-Amz-Account-Id and
+	// X-Amz-Caller-Arn, the identity headers of servers running without
+	// API Gateway. Behind API Gateway, the signing identity is used instead.
+	AccountID string
+	CallerARN string
+	// AWSRegion signs requests with SigV4 for execute-api. Without it,
+	// requests are not signed.
+	AWSRegion string
+	Timeout   time.Duration
+}
+
+// RemoteEngine decides checks with a running API. Each check creates the
+// policies in the caller's account, attaches them to a new group holding
+// the check's principal, asks /api/v0/authz/check and removes what it
+// created. The caller must be an admin of the account. The check API does
+// not report determining policies, so its decisions carry none.
+type RemoteEngine struct {
+	cfg         RemoteConfig
+	http        *http.Client
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+}
+
+// NewRemoteEngine creates a new RemoteEngine. credentials sign the requests
+// when cfg.AWSRegion is set.
+func NewRemoteEngine(cfg RemoteConfig, credentials aws.CredentialsProvider) *RemoteEngine {
+	cfg.BaseURL = strings.TrimSuffix(cfg.BaseURL, "/")
+	return &RemoteEngine{
+		cfg:         cfg,
+		http:        &http.Client{Timeout: cfg.Timeout},
+		credentials: credentials,
+		signer:      v4.NewSigner(),
+	}
+}
+
+// Evaluate decides check against policies
+func (e *RemoteEngine) Evaluate(ctx context.Context, policies []string, check *Check) (decision *Decision, err error) {
+	// Cleanup runs even when ctx is cancelled, so interrupted runs do not
+	// leave test policies behind
+	cleanupCtx := context.WithoutCancel(ctx)
+	name := fmt.Sprintf("policy-test-%d", time.Now().UnixNano())
+
+	var policyIDs, attachmentIDs []string
+	var group struct {
+		GroupID string `json:"groupId"`
+	}
+	defer func() {
+		for _, id := range attachmentIDs {
+			err = joinCleanup(err, e.do(cleanupCtx, http.MethodDelete, "/api/v0/authz/attachments/"+id, nil, nil))
+		}
+		if group.GroupID != "" {
+			err = joinCleanup(err, e.do(cleanupCtx, http.MethodDelete, "/api/v0/authz/groups/"+group.GroupID, nil, nil))
+		}
+		for _, id := range policyIDs {
+			err = joinCleanup(err, e.do(cleanupCtx, http.MethodDelete, "/api/v0/authz/policies/"+id, nil, nil))
+		}
+	}()
+
+	for i, policy := range policies {
+		var created struct {
+			PolicyID string `json:"policyId"`
+		}
+		body := map[string]string{
+			"name":        fmt.Sprintf("%s-%d", name, i+1),
+			"description": "Created by policy-test",
+			"policy":      policy,
+		}
+		if err := e.do(ctx, http.MethodPost, "/api/v0/authz/policies", body, &created); err != nil {
+			return nil, fmt.Errorf("failed to create policy %d: %w", i+1, err)
+		}
+		policyIDs = append(policyIDs, created.PolicyID)
+	}
+
+	if err := e.do(ctx, http.MethodPost, "/api/v0/authz/groups", map[string]string{"name": name, "description": "Created by policy-test"}, &group); err != nil {
+		return nil, fmt.Errorf("failed to create group: %w", err)
+	}
+	if err := e.do(ctx, http.MethodPut, "/api/v0/authz/groups/"+group.GroupID+"/members", map[string][]string{"add": {check.Principal}}, nil); err != nil {
+		return nil, fmt.Errorf("failed to add the principal to the group: %w", err)
+	}
+
+	for i, policyID := range policyIDs {
+		var attachment struct {
+			AttachmentID string `json:"attachmentId"`
+		}
+		body := map[string]string{"policyId": policyID, "targetType": "group", "targetId": group.GroupID}
+		// Checks made right after must see the attachment
+		if err := e.do(ctx, http.MethodPost, "/api/v0/authz/attachments?wait=true", body, &attachment); err != nil {
+			return nil, fmt.Errorf("failed to attach policy %d: %w", i+1, err)
+		}
+		attachmentIDs = append(attachmentIDs, attachment.AttachmentID)
+	}
+
+	var resp struct {
+		Decision string `json:"decision"`
+	}
+	req := map[string]any{
+		"principal":    check.Principal,
+		"action":       check.Action,
+		"resource":     check.Resource,
+		"context":      check.Context,
+		"resourceTags": check.ResourceTags,
+	}
+	if err := e.do(ctx, http.MethodPost, "/api/v0/authz/check", req, &resp); err != nil {
+		return nil, fmt.Errorf("authorization check failed: %w", err)
+	}
+	return &Decision{Result: resp.Decision}, nil
+}
+
+// do sends a request and decodes a successful response into out
+func (e *RemoteEngine) do(ctx context.Context, method, path string, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, e.cfg.BaseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.cfg.AccountID != "" {
+		req.Header.Set("X-Amz-Account-Id", e.cfg.AccountID)
+	}
+	if e.cfg.CallerARN != "" {
+		req.Header.Set("X-Amz-Caller-Arn", e.cfg.CallerARN)
+	}
+	if err := e.sign(ctx, req, payload); err != nil {
+		return err
+	}
+
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s returned %s: %s", method, path, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to decode response of %s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// sign signs req with SigV4 when an AWS region is configured
+func (e *RemoteEngine) sign(ctx context.Context, req *http.Request, body []byte) error {
+	if e.cfg.AWSRegion == "" {
+		return nil
+	}
+	creds, err := e.credentials.Retrieve(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	sum := sha256.Sum256(body)
+	if err := e.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(sum[:]), "execute-api", e.cfg.AWSRegion, time.Now()); err != nil {
+		return fmt.Errorf("failed to sign request: %w", err)
+	}
+	return nil
+}
+
+// joinCleanup reports a cleanup failure unless the check already failed
+func joinCleanup(err, cleanupErr error) error {
+	if err != nil || cleanupErr == nil {
+		return err
+	}
+	return fmt.Errorf("cleanup failed: %w", cleanupErr)
+}
@@ -0,0 +1,162 @@
+package policytest
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Check is the authorization request a test case makes
+type Check struct {
+	AccountID    string
+	Principal    string
+	Action       string
+	Resource     string
+	Context      map[string]any
+	ResourceTags map[string]string
+}
+
+// StatementRef is statement Statement of policy Policy of an evaluation,
+// both counted from zero
+type StatementRef struct {
+	Policy    int
+	Statement int
+}
+
+// Decision is an engine's answer to a check
+type Decision struct {
+	// Result is ResultAllow or ResultDeny
+	Result string
+	// Determining are the statements that decided the check: the permits
+	// of an allow, or the forbids of a deny. Engines that cannot tell leave
+	// it empty.
+	Determining []StatementRef
+}
+
+// Engine decides a check against a set of policies attached to the
+// check's principal
+type Engine interface {
+	Evaluate(ctx context.Context, policies []string, check *Check) (*Decision, error)
+}
+
+// Result is the outcome of one test case
+type Result struct {
+	File        string `json:"file"`
+	ID          string `json:"id,omitempty"`
+	Case        int    `json:"case"`
+	Description string `json:"description"`
+	Expected    string `json:"expected"`
+	Got         string `json:"got,omitempty"`
+	Error       string `json:"error,omitempty"`
+	// Determining are the statements of the file's policy, counted from
+	// one, that decided the case
+	Determining []int `json:"determining,omitempty"`
+}
+
+// Passed reports whether the case got its expected result
+func (r *Result) Passed() bool {
+	return r.Error == "" && r.Got == r.Expected
+}
+
+// Run evaluates every case of files with engine, as principals of
+// accountID, and returns their results in order
+func Run(ctx context.Context, engine Engine, accountID string, files []PolicyTestFile) []Result {
+	var results []Result
+	for _, file := range files {
+		for i, tc := range file.TestCases {
+			result := Result{
+				File:        file.Name,
+				ID:          file.ID,
+				Case:        i + 1,
+				Description: tc.Description,
+				Expected:    tc.ExpectedResult,
+			}
+			policies := append([]string{file.Policy}, tc.AdditionalPolicies...)
+			decision, err := engine.Evaluate(ctx, policies, newCheck(accountID, &tc))
+			if err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Got = decision.Result
+				for _, ref := range decision.Determining {
+					// Additional policies only set the scene for the case
+					if ref.Policy == 0 {
+						result.Determining = append(result.Determining, ref.Statement+1)
+					}
+				}
+			}
+			results = append(results, result)
+			if ctx.Err() != nil {
+				return results
+			}
+		}
+	}
+	return results
+}
+
+// newCheck builds the check of a test case. Resource tag values are
+// formatted as strings, as the API only takes string tags.
+func newCheck(accountID string, tc *TestCase) *Check {
+	username := "testuser"
+	if tc.Principal != nil && tc.Principal.Username != "" {
+		username = tc.Principal.Username
+	}
+	var tags map[string]string
+	if len(tc.Request.ResourceTags) > 0 {
+		tags = make(map[string]string, len(tc.Request.ResourceTags))
+		for k, v := range tc.Request.ResourceTags {
+			tags[k] = fmt.Sprintf("%v", v)
+		}
+	}
+	return &Check{
+		AccountID:    accountID,
+		Principal:    fmt.Sprintf("arn:aws:iam::%s:user/%s", accountID, username),
+		Action:       tc.Request.Action,
+		Resource:     tc.Request.Resource,
+		Context:      tc.Request.Context,
+		ResourceTags: tags,
+	}
+}
+
+// Summary counts results by outcome
+type Summary struct {
+	Passed int `json:"passed"`
+	Failed int `json:"failed"`
+	Errors int `json:"errors"`
+}
+
+// Summarize counts results by outcome
+func Summarize(results []Result) Summary {
+	var s Summary
+	for i := range results {
+		switch {
+		case results[i].Error != "":
+			s.Errors++
+		case results[i].Passed():
+			s.Passed++
+		default:
+			s.Failed++
+		}
+	}
+	return s
+}
+
+// WriteText writes one line per result and a summary line
+func WriteText(w io.Writer, results []Result) {
+	for i := range results {
+		r := &results[i]
+		name := r.File
+		if r.ID != "" {
+			name = r.ID
+		}
+		switch {
+		case r.Error != "":
+			fmt.Fprintf(w, "[ERROR] %s/%d %s: %s\n", name, r.Case, r.Description, r.Error)
+		case r.Passed():
+			fmt.Fprintf(w, "[PASS] %s/%d %s: %s\n", name, r.Case, r.Description, r.Got)
+		default:
+			fmt.Fprintf(w, "[FAIL] %s/%d %s: got %s, expected %s\n", name, r.Case, r.Description, r.Got, r.Expected)
+		}
+	}
+	s := Summarize(results)
+	fmt.Fprintf(w, "\n%d passed, %d failed, %d errors\n", s.Passed, s.Failed, s.Errors)
+}
@@ -0,0 +1,104 @@
+package purge
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// batchSize is the most deletes one BatchWriteItem call takes
+const batchSize = 25
+
+// maxUnprocessedRetries bounds how often deletes DynamoDB did not process
+// are sent again before the purge gives up
+const maxUnprocessedRetries = 5
+
+// Partition names the DynamoDB items holding an account's data: those whose
+// PartitionKey, in the table or in IndexName, is the account ID
+type Partition struct {
+	Table string
+	// IndexName queries a secondary index keyed on the account instead of
+	// the table
+	IndexName    string
+	PartitionKey string
+	// KeyAttributes are the table's primary key attributes
+	KeyAttributes []string
+}
+
+// DynamoTarget returns a target deleting the account's items of p
+func DynamoTarget(name string, dynamoClient client.DynamoDBClient, p Partition) Target {
+	return Target{
+		Name: name,
+		Purge: func(ctx context.Context, accountID string) (int, error) {
+			return DeletePartition(ctx, dynamoClient, p, accountID)
+		},
+	}
+}
+
+// DeletePartition deletes every item of p whose partition key is value and
+// returns how many it deleted
+func DeletePartition(ctx context.Context, dynamoClient client.DynamoDBClient, p Partition, value string) (int, error) {
+	names := map[string]string{"#pk": p.PartitionKey}
+	projection := make([]string, len(p.KeyAttributes))
+	for i, attr := range p.KeyAttributes {
+		placeholder := fmt.Sprintf("#k%d", i)
+		names[placeholder] = attr
+		projection[i] = placeholder
+	}
+	input := &dynamodb.QueryInput{
+		TableName:                aws.String(p.Table),
+		KeyConditionExpression:   aws.String("#pk = :pk"),
+		ProjectionExpression:     aws.String(strings.Join(projection, ", ")),
+		ExpressionAttributeNames: names,
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":pk": &types.AttributeValueMemberS{Value: value},
+		},
+	}
+	if p.IndexName != "" {
+		input.IndexName = aws.String(p.IndexName)
+	}
+
+	deleted := 0
+	paginator := dynamodb.NewQueryPaginator(dynamoClient, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return deleted, fmt.Errorf("failed to query %s: %w", p.Table, err)
+		}
+		for start := 0; start < len(page.Items); start += batchSize {
+			batch := page.Items[start:min(start+batchSize, len(page.Items))]
+			if err := deleteBatch(ctx, dynamoClient, p, batch); err != nil {
+				return deleted, err
+			}
+			deleted += len(batch)
+		}
+	}
+	return deleted, nil
+}
+
+func deleteBatch(ctx context.Context, dynamoClient client.DynamoDBClient, p Partition, keys []map[string]types.AttributeValue) error {
+	requests := make([]types.WriteRequest, len(keys))
+	for i, key := range keys {
+		requests[i] = types.WriteRequest{DeleteRequest: &types.DeleteRequest{Key: key}}
+	}
+
+	for attempt := 0; len(requests) > 0; attempt++ {
+		if attempt > maxUnprocessedRetries {
+			return fmt.Errorf("failed to delete %d items from %s: DynamoDB left them unprocessed", len(requests), p.Table)
+		}
+		out, err := dynamoClient.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: map[string][]types.WriteRequest{p.Table: requests},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete items from %s: %w", p.Table, err)
+		}
+		requests = out.UnprocessedItems[p.Table]
+	}
+	return nil
+}
@@ -0,0 +1,202 @@
+// Package purge deletes everything the platform holds about an account when
+// the tenant is offboarded: its authorization records, policy store, work
+// records and backups. Each purge produces a deletion report signed with a
+// KMS key, so the tenant can be shown what was deleted and when.
+package purge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// SigningAlgorithm is the KMS algorithm deletion reports are signed with;
+// the signing key must be an ECC_NIST_P256 SIGN_VERIFY key
+const SigningAlgorithm = string(kmstypes.SigningAlgorithmSpecEcdsaSha256)
+
+var purgedItems = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_account_purge_items_total",
+	Help: "Items deleted by account purges, by target",
+}, []string{"target"})
+
+// Target is one kind of data held about an account
+type Target struct {
+	// Name identifies the data in the deletion report, such as
+	// authz-groups or work-metadata
+	Name string
+	// Purge deletes the account's data and returns how many items it
+	// deleted
+	Purge func(ctx context.Context, accountID string) (int, error)
+}
+
+// Item is the outcome of purging one target
+type Item struct {
+	Target  string `json:"target"`
+	Deleted int    `json:"deleted"`
+	// Error is set when the target could not be purged completely; purging
+	// the account again retries it
+	Error string `json:"error,omitempty"`
+}
+
+// Signature signs a report's digest
+type Signature struct {
+	KeyID     string `json:"keyId"`
+	Algorithm string `json:"algorithm"`
+	// Digest is the hex SHA-256 of the report without its signature, as
+	// compact JSON
+	Digest string `json:"digest"`
+	// Value is the base64 signature of the digest
+	Value string `json:"value"`
+}
+
+// Report records what a purge deleted
+type Report struct {
+	Kind        string `json:"kind"`
+	AccountID   string `json:"accountId"`
+	PurgedBy    string `json:"purgedBy"`
+	StartedAt   string `json:"startedAt"`
+	CompletedAt string `json:"completedAt"`
+	// Complete is true when every target was purged
+	Complete  bool       `json:"complete"`
+	Items     []Item     `json:"items"`
+	Signature *Signature `json:"signature,omitempty"`
+}
+
+// Digest returns the hex SHA-256 a report's signature covers
+func (r *Report) Digest() (string, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	payload, err := json.Marshal(unsigned)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal deletion report: %w", err)
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// KMSClient provides the KMS operation used to sign reports
+type KMSClient interface {
+	Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error)
+}
+
+// Purger purges accounts from a list of targets, in order
+type Purger struct {
+	targets   []Target
+	kmsClient KMSClient
+	keyID     string
+	logger    *slog.Logger
+	now       func() time.Time
+	progress  func(ctx context.Context, target string, done, total int)
+}
+
+// NewPurger creates a purger signing its reports with the KMS key keyID
+func NewPurger(kmsClient KMSClient, keyID string, logger *slog.Logger) *Purger {
+	return &Purger{
+		kmsClient: kmsClient,
+		keyID:     keyID,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// WithTargets adds targets, purged in the order they are added. The account
+// record, which identifies the account to later purges, should come last.
+func (p *Purger) WithTargets(targets ...Target) *Purger {
+	p.targets = append(p.targets, targets...)
+	return p
+}
+
+// WithProgress calls progress before each target is purged, and once all
+// are, with how many targets are done
+func (p *Purger) WithProgress(progress func(ctx context.Context, target string, done, total int)) *Purger {
+	p.progress = progress
+	return p
+}
+
+// Targets returns the names of the targets a purge deletes, in order
+func (p *Purger) Targets() []string {
+	names := make([]string, len(p.targets))
+	for i, t := range p.targets {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// Purge deletes the account's data from every target and returns the signed
+// report. A target that fails does not stop the others; the report is then
+// incomplete. An error is returned only when the report cannot be signed.
+func (p *Purger) Purge(ctx context.Context, accountID, purgedBy string) (*Report, error) {
+	report := &Report{
+		Kind:      "AccountPurgeReport",
+		AccountID: accountID,
+		PurgedBy:  purgedBy,
+		StartedAt: clock.Format(p.now()),
+		Complete:  true,
+		Items:     make([]Item, 0, len(p.targets)),
+	}
+
+	for i, t := range p.targets {
+		p.reportProgress(ctx, t.Name, i)
+		deleted, err := t.Purge(ctx, accountID)
+		item := Item{Target: t.Name, Deleted: deleted}
+		if err != nil {
+			p.logger.Error("failed to purge account data", "error", err, "account_id", accountID, "target", t.Name, "deleted", deleted)
+			item.Error = err.Error()
+			report.Complete = false
+		}
+		purgedItems.WithLabelValues(t.Name).Add(float64(deleted))
+		report.Items = append(report.Items, item)
+	}
+	p.reportProgress(ctx, "signing", len(p.targets))
+	report.CompletedAt = clock.Format(p.now())
+
+	if err := p.sign(ctx, report); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+func (p *Purger) reportProgress(ctx context.Context, target string, done int) {
+	if p.progress != nil {
+		p.progress(ctx, target, done, len(p.targets))
+	}
+}
+
+func (p *Purger) sign(ctx context.Context, report *Report) error {
+	digest, err := report.Digest()
+	if err != nil {
+		return err
+	}
+	sum, _ := hex.DecodeString(digest)
+
+	out, err := p.kmsClient.Sign(ctx, &kms.SignInput{
+		KeyId:            aws.String(p.keyID),
+		Message:          sum,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpecEcdsaSha256,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to sign deletion report: %w", err)
+	}
+
+	report.Signature = &Signature{
+		KeyID:     aws.ToString(out.KeyId),
+		Algorithm: SigningAlgorithm,
+		Digest:    digest,
+		Value:     base64.StdEncoding.EncodeToString(out.Signature),
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package purge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// fakeKMS signs digests with a local P-256 key like an ECC_NIST_P256 KMS key
+type fakeKMS struct {
+	key *ecdsa.PrivateKey
+	err error
+}
+
+func (f *fakeKMS) Sign(ctx context.Context, params *kms.SignInput, optFns ...func(*kms.Options)) (*kms.SignOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	sig, err := ecdsa.SignASN1(rand.Reader, f.key, params.Message)
+	if err != nil {
+		return nil, err
+	}
+	return &kms.SignOutput{KeyId: params.KeyId, Signature: sig}, nil
+}
+
+func countTarget(name string, deleted int, err error) Target {
+	return Target{Name: name, Purge: func(ctx context.Context, accountID string) (int, error) {
+		return deleted, err
+	}}
+}
+
+func TestPurger_Purge(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order []string
+	record := func(name string) Target {
+		return Target{Name: name, Purge: func(ctx context.Context, accountID string) (int, error) {
+			order = append(order, name)
+			return 2, nil
+		}}
+	}
+	purger := NewPurger(&fakeKMS{key: key}, "alias/purge", slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithTargets(record("work-metadata")).
+		WithTargets(record("authz-groups"), record("authz-account"))
+	purger.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) }
+	var progress []string
+	purger.WithProgress(func(ctx context.Context, target string, done, total int) {
+		progress = append(progress, fmt.Sprintf("%s %d/%d", target, done, total))
+	})
+
+	report, err := purger.Purge(context.Background(), "111111111111", "arn:aws:iam::000000000000:role/admin")
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if fmt.Sprint(order) != "[work-metadata authz-groups authz-account]" {
+		t.Errorf("targets purged in order %v", order)
+	}
+	if fmt.Sprint(progress) != "[work-metadata 0/3 authz-groups 1/3 authz-account 2/3 signing 3/3]" {
+		t.Errorf("unexpected progress %v", progress)
+	}
+	if !report.Complete || len(report.Items) != 3 || report.StartedAt != "2026-03-01T12:00:00Z" {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	sig := report.Signature
+	if sig == nil || sig.KeyID != "alias/purge" || sig.Algorithm != SigningAlgorithm {
+		t.Fatalf("unexpected signature %+v", sig)
+	}
+	digest, err := report.Digest()
+	if err != nil || digest != sig.Digest {
+		t.Fatalf("signed digest %s does not match the report's %s (%v)", sig.Digest, digest, err)
+	}
+	sum, _ := hex.DecodeString(digest)
+	value, _ := base64.StdEncoding.DecodeString(sig.Value)
+	if !ecdsa.VerifyASN1(&key.PublicKey, sum, value) {
+		t.Error("signature does not verify with the signing key")
+	}
+
+	// Changing the report invalidates the signature
+	report.Items[0].Deleted = 0
+	if tampered, _ := report.Digest(); tampered == sig.Digest {
+		t.Error("expected the digest to cover the items")
+	}
+}
+
+func TestPurger_Purge_FailedTarget(t *testing.T) {
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	purger := NewPurger(&fakeKMS{key: key}, "alias/purge", slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithTargets(countTarget("work-metadata", 1, errors.New("throttled")), countTarget("authz-account", 1, nil))
+
+	report, err := purger.Purge(context.Background(), "111111111111", "admin")
+	if err != nil {
+		t.Fatalf("Purge() error = %v", err)
+	}
+	if report.Complete {
+		t.Error("expected an incomplete report")
+	}
+	if report.Items[0].Error != "throttled" || report.Items[1].Deleted != 1 {
+		t.Errorf("expected the later target to be purged despite the failure, got %+v", report.Items)
+	}
+	if report.Signature == nil {
+		t.Error("expected incomplete reports to be signed")
+	}
+}
+
+func TestPurger_Purge_SigningFails(t *testing.T) {
+	purger := NewPurger(&fakeKMS{err: errors.New("access denied")}, "alias/purge", slog.New(slog.NewTextHandler(io.Discard, nil))).
+		WithTargets(countTarget("authz-account", 1, nil))
+
+	report, err := purger.Purge(context.Background(), "111111111111", "admin")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if report == nil || report.Signature != nil || report.Items[0].Deleted != 1 {
+		t.Errorf("expected the unsigned report, got %+v", report)
+	}
+}
+
+type fakeDynamo struct {
+	client.DynamoDBClient
+	items       []map[string]types.AttributeValue
+	query       *dynamodb.QueryInput
+	batches     [][]types.WriteRequest
+	unprocessed int
+}
+
+func (f *fakeDynamo) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.query = params
+	return &dynamodb.QueryOutput{Items: f.items}, nil
+}
+
+func (f *fakeDynamo) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	for table, requests := range params.RequestItems {
+		f.batches = append(f.batches, requests)
+		// Leave the last request unprocessed the first times
+		if f.unprocessed > 0 {
+			f.unprocessed--
+			return &dynamodb.BatchWriteItemOutput{UnprocessedItems: map[string][]types.WriteRequest{table: requests[len(requests)-1:]}}, nil
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func TestDeletePartition(t *testing.T) {
+	dynamo := &fakeDynamo{unprocessed: 1}
+	for i := range 30 {
+		dynamo.items = append(dynamo.items, map[string]types.AttributeValue{
+			"accountId": &types.AttributeValueMemberS{Value: "111111111111"},
+			"groupId":   &types.AttributeValueMemberS{Value: fmt.Sprintf("g-%d", i)},
+		})
+	}
+
+	deleted, err := DeletePartition(context.Background(), dynamo, Partition{
+		Table:         "groups",
+		PartitionKey:  "accountId",
+		KeyAttributes: []string{"accountId", "groupId"},
+	}, "111111111111")
+	if err != nil {
+		t.Fatalf("DeletePartition() error = %v", err)
+	}
+	if deleted != 30 {
+		t.Errorf("deleted = %d, want 30", deleted)
+	}
+	if aws.ToString(dynamo.query.ProjectionExpression) != "#k0, #k1" || dynamo.query.IndexName != nil {
+		t.Errorf("unexpected query %+v", dynamo.query)
+	}
+	// 25 and 5, with the unprocessed delete of the first batch sent again
+	if len(dynamo.batches) != 3 || len(dynamo.batches[0]) != 25 || len(dynamo.batches[1]) != 1 || len(dynamo.batches[2]) != 5 {
+		t.Errorf("expected batches of 25, 1 and 5 deletes, got %d batches", len(dynamo.batches))
+	}
+}
+
+func TestDeletePartition_Unprocessed(t *testing.T) {
+	dynamo := &fakeDynamo{unprocessed: maxUnprocessedRetries + 1, items: []map[string]types.AttributeValue{
+		{"keyId": &types.AttributeValueMemberS{Value: "k-1"}},
+	}}
+
+	_, err := DeletePartition(context.Background(), dynamo, Partition{
+		Table:         "api-keys",
+		IndexName:     "account-index",
+		PartitionKey:  "accountId",
+		KeyAttributes: []string{"keyId"},
+	}, "111111111111")
+	if err == nil {
+		t.Fatal("expected an error when DynamoDB keeps items unprocessed")
+	}
+	if aws.ToString(dynamo.query.IndexName) != "account-index" {
+		t.Errorf("expected the account index to be queried, got %+v", dynamo.query)
+	}
+}
@@ -0,0 +1,91 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+// DynamoLimiter counts requests per key in fixed windows, one item per key
+// and window, shared by every replica. Items expire through the table's TTL
+// on expiresAt once their window has passed.
+type DynamoLimiter struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	limit        int
+	window       time.Duration
+	logger       *slog.Logger
+	now          func() time.Time
+}
+
+// NewDynamoLimiter creates a DynamoDB-backed limiter allowing limit requests
+// per window and key
+func NewDynamoLimiter(tableName string, dynamoClient client.DynamoDBClient, limit int, window time.Duration, logger *slog.Logger) *DynamoLimiter {
+	return &DynamoLimiter{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		limit:        limit,
+		window:       window,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Allow increments the key's counter for the current window on the condition
+// that it is below the limit
+func (l *DynamoLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := l.now()
+	start := now.Truncate(l.window)
+	end := start.Add(l.window)
+
+	_, err := l.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: key + "#" + strconv.FormatInt(start.Unix(), 10)},
+		},
+		UpdateExpression:    aws.String("ADD hits :one SET expiresAt = :expiresAt"),
+		ConditionExpression: aws.String("attribute_not_exists(hits) OR hits < :limit"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":one":       &types.AttributeValueMemberN{Value: "1"},
+			":limit":     &types.AttributeValueMemberN{Value: strconv.Itoa(l.limit)},
+			":expiresAt": &types.AttributeValueMemberN{Value: strconv.FormatInt(end.Add(l.window).Unix(), 10)},
+		},
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return false, end.Sub(now), nil
+	}
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to count request: %w", err)
+	}
+	return true, 0, nil
+}
+
+// Used reads the key's counter for the current window
+func (l *DynamoLimiter) Used(ctx context.Context, key string) (int, error) {
+	start := l.now().Truncate(l.window)
+	out, err := l.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(l.tableName),
+		Key: map[string]types.AttributeValue{
+			"key": &types.AttributeValueMemberS{Value: key + "#" + strconv.FormatInt(start.Unix(), 10)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to read request count: %w", err)
+	}
+	hits, ok := out.Item["hits"].(*types.AttributeValueMemberN)
+	if !ok {
+		return 0, nil
+	}
+	return strconv.Atoi(hits.Value)
+}
@@ -0,0 +1,209 @@
+// Package ratelimit limits how many API requests each account makes per
+// window. The local limiter only counts the requests this replica serves; the
+// DynamoDB limiter shares one counter per account between every replica, and
+// falls back to local limiting while DynamoDB is unavailable.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"golang.org/x/time/rate"
+)
+
+// Backends
+const (
+	BackendLocal    = "local"
+	BackendDynamoDB = "dynamodb"
+)
+
+var (
+	limitDecisions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_rate_limit_decisions_total",
+		Help: "Rate limit decisions, by the backend that made them and whether the request was allowed.",
+	}, []string{"backend", "allowed"})
+
+	backendErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_rate_limit_backend_errors_total",
+		Help: "Shared rate limit backend calls that failed, after which requests are limited locally.",
+	}, []string{"backend"})
+)
+
+// Limiter decides whether a request is within its key's limit
+type Limiter interface {
+	// Allow counts a request for key. When the limit is reached it returns
+	// false and how long until a request would be allowed again.
+	Allow(ctx context.Context, key string) (bool, time.Duration, error)
+}
+
+// ErrNotCounted is returned by limiters that cannot tell how many requests a
+// key has made
+var ErrNotCounted = errors.New("rate limiter does not count requests")
+
+// Counter is implemented by limiters that can report a key's usage
+type Counter interface {
+	// Used returns the requests key has made in the current window
+	Used(ctx context.Context, key string) (int, error)
+}
+
+// Local limits requests per key with an in-memory token bucket per key. The
+// bucket holds limit tokens and refills at limit per window, so a key that
+// has been idle can burst up to limit requests at once.
+type Local struct {
+	limit  int
+	every  rate.Limit
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	buckets   map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+// NewLocal creates a local limiter allowing limit requests per window and key
+func NewLocal(limit int, window time.Duration) *Local {
+	return &Local{
+		limit:   limit,
+		every:   rate.Every(window / time.Duration(limit)),
+		window:  window,
+		now:     time.Now,
+		buckets: make(map[string]*rate.Limiter),
+	}
+}
+
+// Allow takes a token from key's bucket
+func (l *Local) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	now := l.now()
+
+	l.mu.Lock()
+	l.sweep(now)
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = rate.NewLimiter(l.every, l.limit)
+		l.buckets[key] = bucket
+	}
+	l.mu.Unlock()
+
+	reservation := bucket.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		limitDecisions.WithLabelValues(BackendLocal, "false").Inc()
+		return false, delay, nil
+	}
+	limitDecisions.WithLabelValues(BackendLocal, "true").Inc()
+	return true, 0, nil
+}
+
+// Used returns the tokens key's bucket is missing, which are the requests it
+// made over the last window as far as the bucket remembers
+func (l *Local) Used(ctx context.Context, key string) (int, error) {
+	now := l.now()
+	l.mu.Lock()
+	bucket, ok := l.buckets[key]
+	l.mu.Unlock()
+	if !ok {
+		return 0, nil
+	}
+	return max(0, l.limit-int(bucket.TokensAt(now))), nil
+}
+
+// sweep drops the buckets that have refilled, which behave the same as a new
+// bucket, at most once a window so idle keys do not accumulate
+func (l *Local) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	l.lastSweep = now
+	for key, bucket := range l.buckets {
+		if bucket.TokensAt(now) >= float64(l.limit) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// Fallback limits with a shared backend, and with a local limiter while the
+// shared backend fails. After a failure the shared backend is left alone for
+// retryAfter so every request does not wait on it.
+type Fallback struct {
+	backend    string
+	shared     Limiter
+	local      *Local
+	timeout    time.Duration
+	retryAfter time.Duration
+	logger     *slog.Logger
+	now        func() time.Time
+
+	mu        sync.Mutex
+	downUntil time.Time
+}
+
+// NewFallback creates a limiter that calls shared, named backend in metrics,
+// with timeout and uses local for retryAfter whenever it fails
+func NewFallback(backend string, shared Limiter, local *Local, timeout, retryAfter time.Duration, logger *slog.Logger) *Fallback {
+	return &Fallback{
+		backend:    backend,
+		shared:     shared,
+		local:      local,
+		timeout:    timeout,
+		retryAfter: retryAfter,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Allow asks the shared backend unless it recently failed. It never returns
+// an error: failures are logged and the local limiter decides instead.
+func (f *Fallback) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	f.mu.Lock()
+	down := f.now().Before(f.downUntil)
+	f.mu.Unlock()
+	if down {
+		return f.local.Allow(ctx, key)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	allowed, retry, err := f.shared.Allow(callCtx, key)
+	if err == nil {
+		limitDecisions.WithLabelValues(f.backend, strconv.FormatBool(allowed)).Inc()
+		return allowed, retry, nil
+	}
+	if ctx.Err() != nil {
+		// The caller went away; no reason to distrust the backend
+		return f.local.Allow(ctx, key)
+	}
+
+	backendErrors.WithLabelValues(f.backend).Inc()
+	f.mu.Lock()
+	f.downUntil = f.now().Add(f.retryAfter)
+	f.mu.Unlock()
+	f.logger.Warn("shared rate limit backend failed, limiting locally",
+		"backend", f.backend, "retry_after", f.retryAfter, "error", err)
+	return f.local.Allow(ctx, key)
+}
+
+// Used asks the shared backend, when it counts requests, unless it recently
+// failed, and the local limiter otherwise
+func (f *Fallback) Used(ctx context.Context, key string) (int, error) {
+	f.mu.Lock()
+	down := f.now().Before(f.downUntil)
+	f.mu.Unlock()
+	counter, ok := f.shared.(Counter)
+	if down || !ok {
+		return f.local.Used(ctx, key)
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+	used, err := counter.Used(callCtx, key)
+	if err != nil {
+		return f.local.Used(ctx, key)
+	}
+	return used, nil
+}
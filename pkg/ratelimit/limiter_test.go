@@ -0,0 +1,191 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// fakeClock is a settable time source
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time { return c.t }
+
+func TestLocal_Allow(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLocal(3, time.Second)
+	l.now = clock.now
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := l.Allow(ctx, "a"); !ok {
+			t.Fatalf("expected request %d to be allowed", i+1)
+		}
+	}
+	ok, retry, err := l.Allow(ctx, "a")
+	if err != nil || ok {
+		t.Fatalf("expected the fourth request to be limited, got %v (%v)", ok, err)
+	}
+	if retry <= 0 || retry > time.Second/3 {
+		t.Errorf("expected a retry within a third of the window, got %s", retry)
+	}
+
+	// Keys are limited separately
+	if ok, _, _ := l.Allow(ctx, "b"); !ok {
+		t.Error("expected another key to be allowed")
+	}
+
+	// A rejected request does not use up a token
+	clock.t = clock.t.Add(time.Second / 3)
+	if ok, _, _ := l.Allow(ctx, "a"); !ok {
+		t.Error("expected a request to be allowed once a token refilled")
+	}
+}
+
+func TestLocal_SweepsRefilledBuckets(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLocal(2, time.Second)
+	l.now = clock.now
+	ctx := context.Background()
+
+	_, _, _ = l.Allow(ctx, "a")
+	clock.t = clock.t.Add(2 * time.Second)
+	_, _, _ = l.Allow(ctx, "b")
+
+	if _, ok := l.buckets["a"]; ok {
+		t.Error("expected the refilled bucket to be dropped")
+	}
+	if _, ok := l.buckets["b"]; !ok {
+		t.Error("expected the bucket in use to be kept")
+	}
+}
+
+// counterTable is a DynamoDB fake that keeps hit counters like the
+// conditional update in DynamoLimiter
+type counterTable struct {
+	client.DynamoDBClient
+	mu   sync.Mutex
+	hits map[string]int
+	err  error
+}
+
+func (c *counterTable) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	key := params.Key["key"].(*types.AttributeValueMemberS).Value
+	limit, _ := strconv.Atoi(params.ExpressionAttributeValues[":limit"].(*types.AttributeValueMemberN).Value)
+	if c.hits[key] >= limit {
+		return nil, &types.ConditionalCheckFailedException{Message: aws.String("limit reached")}
+	}
+	c.hits[key]++
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func TestDynamoLimiter_SharedBetweenReplicas(t *testing.T) {
+	table := &counterTable{hits: make(map[string]int)}
+	clock := &fakeClock{t: time.Unix(1700000004, 0)}
+	replicas := []*DynamoLimiter{
+		NewDynamoLimiter("rosa-rate-limits", table, 3, 10*time.Second, testLogger()),
+		NewDynamoLimiter("rosa-rate-limits", table, 3, 10*time.Second, testLogger()),
+	}
+	for _, r := range replicas {
+		r.now = clock.now
+	}
+	ctx := context.Background()
+
+	allowed := 0
+	for i := 0; i < 4; i++ {
+		ok, retry, err := replicas[i%2].Allow(ctx, "123456789012")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if ok {
+			allowed++
+		} else if retry != 6*time.Second {
+			t.Errorf("expected a retry at the end of the window, got %s", retry)
+		}
+	}
+	if allowed != 3 {
+		t.Errorf("expected 3 requests allowed across replicas, got %d", allowed)
+	}
+
+	// The next window starts a new counter
+	clock.t = clock.t.Add(6 * time.Second)
+	if ok, _, _ := replicas[0].Allow(ctx, "123456789012"); !ok {
+		t.Error("expected a request in the next window to be allowed")
+	}
+}
+
+func TestFallback_LimitsLocallyWhileSharedFails(t *testing.T) {
+	table := &counterTable{hits: make(map[string]int), err: errors.New("connection refused")}
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	shared := NewDynamoLimiter("rosa-rate-limits", table, 2, time.Minute, testLogger())
+	local := NewLocal(2, time.Minute)
+	local.now = clock.now
+	f := NewFallback(BackendDynamoDB, shared, local, time.Second, 30*time.Second, testLogger())
+	f.now = clock.now
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if ok, _, err := f.Allow(ctx, "a"); !ok || err != nil {
+			t.Fatalf("expected request %d to be allowed locally, got %v (%v)", i+1, ok, err)
+		}
+	}
+	if ok, _, _ := f.Allow(ctx, "a"); ok {
+		t.Fatal("expected the local limit to apply while the shared backend fails")
+	}
+
+	// The shared backend is not called again until the retry interval passes
+	table.mu.Lock()
+	table.err = nil
+	table.mu.Unlock()
+	if ok, _, _ := f.Allow(ctx, "b"); !ok || len(table.hits) != 0 {
+		t.Fatalf("expected the local limiter to decide, shared hits %v", table.hits)
+	}
+
+	clock.t = clock.t.Add(30 * time.Second)
+	if ok, _, _ := f.Allow(ctx, "a"); !ok || len(table.hits) != 1 {
+		t.Errorf("expected the shared backend to decide again, shared hits %v", table.hits)
+	}
+}
+
+func TestLocal_Used(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(1700000000, 0)}
+	l := NewLocal(4, time.Second)
+	l.now = clock.now
+	ctx := context.Background()
+
+	if used, _ := l.Used(ctx, "a"); used != 0 {
+		t.Errorf("expected an unseen key to have used nothing, got %d", used)
+	}
+	for i := 0; i < 3; i++ {
+		_, _, _ = l.Allow(ctx, "a")
+	}
+	if used, _ := l.Used(ctx, "a"); used != 3 {
+		t.Errorf("expected 3 requests used, got %d", used)
+	}
+	clock.t = clock.t.Add(time.Second / 2)
+	if used, _ := l.Used(ctx, "a"); used != 1 {
+		t.Errorf("expected refilled tokens to be available again, got %d used", used)
+	}
+}
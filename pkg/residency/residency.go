@@ -0,0 +1,98 @@
+// Package residency validates and enforces the data residency metadata of
+// accounts: the region an account's data is pinned to and its export-control
+// classification. Accounts pinned to a region may not submit work through a
+// deployment serving another region, so their payloads never leave it.
+package residency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+// Export-control classifications that are not ECCNs
+const (
+	EAR99 = "EAR99"
+	ITAR  = "ITAR"
+)
+
+var (
+	// regionPattern matches AWS region names, such as us-east-2 and
+	// us-gov-west-1
+	regionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-[a-z]+-[0-9]$`)
+	// eccnPattern matches Export Control Classification Numbers, such as
+	// 5D002 or 5D992.c
+	eccnPattern = regexp.MustCompile(`^[0-9][A-E][0-9]{3}(\.[a-z0-9.]+)?$`)
+)
+
+// ValidateRegion returns why region is not an AWS region name, or ""
+func ValidateRegion(region string) string {
+	if region != "" && !regionPattern.MatchString(region) {
+		return fmt.Sprintf("pinned region %q must be an AWS region, such as us-east-2", region)
+	}
+	return ""
+}
+
+// ValidateExportControl returns why classification is not EAR99, ITAR or an
+// ECCN, or ""
+func ValidateExportControl(classification string) string {
+	if classification == "" || classification == EAR99 || classification == ITAR || eccnPattern.MatchString(classification) {
+		return ""
+	}
+	return fmt.Sprintf("export control classification %q must be %s, %s or an ECCN, such as 5D002", classification, EAR99, ITAR)
+}
+
+// AccountGetter reads accounts, such as the authorizer
+type AccountGetter interface {
+	GetAccount(ctx context.Context, accountID string) (*store.Account, error)
+}
+
+// Violation is an account pinned to a region acting through a deployment
+// serving another one
+type Violation struct {
+	AccountID    string
+	PinnedRegion string
+	Region       string
+}
+
+func (v *Violation) Error() string {
+	return fmt.Sprintf("account %s is pinned to %s and may not submit work in %s", v.AccountID, v.PinnedRegion, v.Region)
+}
+
+// Checker enforces region pinning for the deployment serving region
+type Checker struct {
+	accounts AccountGetter
+	region   string
+}
+
+// NewChecker creates a checker for the deployment serving region
+func NewChecker(accounts AccountGetter, region string) *Checker {
+	return &Checker{accounts: accounts, region: region}
+}
+
+// Region returns the region the deployment serves
+func (c *Checker) Region() string {
+	return c.region
+}
+
+// Check returns a *Violation when accountID is pinned to another region
+// than the deployment's. Accounts that are not enabled are not pinned.
+func (c *Checker) Check(ctx context.Context, accountID string) error {
+	account, err := c.accounts.GetAccount(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("failed to get account residency: %w", err)
+	}
+	if account == nil || account.PinnedRegion == "" || account.PinnedRegion == c.region {
+		return nil
+	}
+	return &Violation{AccountID: accountID, PinnedRegion: account.PinnedRegion, Region: c.region}
+}
+
+// IsViolation reports whether err is a *Violation
+func IsViolation(err error) bool {
+	var v *Violation
+	return errors.As(err, &v)
+}
@@ -0,0 +1,65 @@
+package residency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/store"
+)
+
+type fakeAccounts map[string]*store.Account
+
+func (f fakeAccounts) GetAccount(ctx context.Context, accountID string) (*store.Account, error) {
+	if accountID == "error" {
+		return nil, errors.New("dynamodb unavailable")
+	}
+	return f[accountID], nil
+}
+
+func TestValidate(t *testing.T) {
+	for _, region := range []string{"", "us-east-2", "eu-central-1", "us-gov-west-1"} {
+		if problem := ValidateRegion(region); problem != "" {
+			t.Errorf("ValidateRegion(%q) = %q, want valid", region, problem)
+		}
+	}
+	for _, region := range []string{"US-EAST-2", "us-east", "useast2"} {
+		if ValidateRegion(region) == "" {
+			t.Errorf("ValidateRegion(%q) = valid, want a problem", region)
+		}
+	}
+
+	for _, classification := range []string{"", EAR99, ITAR, "5D002", "5D992.c"} {
+		if problem := ValidateExportControl(classification); problem != "" {
+			t.Errorf("ValidateExportControl(%q) = %q, want valid", classification, problem)
+		}
+	}
+	for _, classification := range []string{"ear99", "5Z002", "secret"} {
+		if ValidateExportControl(classification) == "" {
+			t.Errorf("ValidateExportControl(%q) = valid, want a problem", classification)
+		}
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	checker := NewChecker(fakeAccounts{
+		"111111111111": {AccountID: "111111111111", PinnedRegion: "eu-west-1"},
+		"222222222222": {AccountID: "222222222222", PinnedRegion: "us-east-2"},
+		"333333333333": {AccountID: "333333333333"},
+	}, "us-east-2")
+	ctx := context.Background()
+
+	err := checker.Check(ctx, "111111111111")
+	var violation *Violation
+	if !errors.As(err, &violation) || violation.PinnedRegion != "eu-west-1" || violation.Region != "us-east-2" {
+		t.Errorf("expected a violation for an account pinned elsewhere, got %v", err)
+	}
+	for _, accountID := range []string{"222222222222", "333333333333", "444444444444"} {
+		if err := checker.Check(ctx, accountID); err != nil {
+			t.Errorf("Check(%s) = %v, want nil", accountID, err)
+		}
+	}
+	if err := checker.Check(ctx, "error"); err == nil || IsViolation(err) {
+		t.Errorf("expected the lookup error, got %v", err)
+	}
+}
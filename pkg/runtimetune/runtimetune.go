@@ -0,0 +1,177 @@
+// Package runtimetune applies the Go runtime settings the server is
+// configured with: GOMAXPROCS, the GC target and a soft memory limit taken
+// from the container's cgroup. The runtime already sizes GOMAXPROCS from the
+// cgroup CPU limit, so by default only the memory limit is derived here.
+package runtimetune
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
+
+// DefaultCgroupRoot is where the container's cgroup filesystem is mounted
+const DefaultCgroupRoot = "/sys/fs/cgroup"
+
+// Config configures the runtime. Zero values leave the runtime's own
+// setting, including any GOMAXPROCS, GOGC or GOMEMLIMIT environment variable,
+// in place.
+type Config struct {
+	// MaxProcs overrides GOMAXPROCS
+	MaxProcs int
+	// GCPercent overrides GOGC; negative turns the collector off, which is
+	// only sensible together with a memory limit
+	GCPercent int
+	// MemoryLimit is the soft memory limit in bytes
+	MemoryLimit int64
+	// MemoryLimitRatio sets the soft memory limit to this fraction of the
+	// cgroup memory limit when MemoryLimit and GOMEMLIMIT are unset
+	MemoryLimitRatio float64
+	// Ballast is the size in bytes of a never-used allocation that raises the
+	// heap the GC target is computed from. A memory limit does the same job
+	// without the extra address space and is preferred.
+	Ballast int64
+	// CgroupRoot is where the cgroup filesystem is read from; empty uses
+	// DefaultCgroupRoot
+	CgroupRoot string
+}
+
+// Settings are the runtime settings in effect after Apply
+type Settings struct {
+	MaxProcs  int
+	NumCPU    int
+	GCPercent int
+	// MemoryLimit is math.MaxInt64 when there is no limit
+	MemoryLimit int64
+	// CgroupMemoryLimit is 0 when the cgroup has no memory limit
+	CgroupMemoryLimit int64
+	Ballast           int64
+}
+
+// LogArgs lists the settings as log attributes
+func (s Settings) LogArgs() []any {
+	limit := "none"
+	if s.MemoryLimit != math.MaxInt64 {
+		limit = strconv.FormatInt(s.MemoryLimit, 10)
+	}
+	return []any{
+		"gomaxprocs", s.MaxProcs,
+		"num_cpu", s.NumCPU,
+		"gogc", s.GCPercent,
+		"memory_limit_bytes", limit,
+		"cgroup_memory_limit_bytes", s.CgroupMemoryLimit,
+		"ballast_bytes", s.Ballast,
+	}
+}
+
+// ballast is kept reachable for the life of the process and never touched,
+// so its pages are never faulted in
+var ballast []byte
+
+// Apply applies cfg to the runtime and returns the settings in effect
+func Apply(cfg Config) (Settings, error) {
+	if cfg.MemoryLimitRatio < 0 || cfg.MemoryLimitRatio > 1 {
+		return Settings{}, fmt.Errorf("memory limit ratio must be between 0 and 1, got %v", cfg.MemoryLimitRatio)
+	}
+	if cfg.MemoryLimit < 0 || cfg.Ballast < 0 || cfg.MaxProcs < 0 {
+		return Settings{}, errors.New("GOMAXPROCS, memory limit and ballast must not be negative")
+	}
+	root := cfg.CgroupRoot
+	if root == "" {
+		root = DefaultCgroupRoot
+	}
+
+	if cfg.MaxProcs > 0 {
+		runtime.GOMAXPROCS(cfg.MaxProcs)
+	}
+	if cfg.GCPercent != 0 {
+		debug.SetGCPercent(cfg.GCPercent)
+	}
+
+	// The cgroup limit is only needed, and only fatal to miss, when the
+	// memory limit is derived from it
+	cgroupLimit, err := CgroupMemoryLimit(root)
+	switch {
+	case cfg.MemoryLimit > 0:
+		debug.SetMemoryLimit(cfg.MemoryLimit)
+	case cfg.MemoryLimitRatio > 0 && os.Getenv("GOMEMLIMIT") == "":
+		if err != nil {
+			return Settings{}, err
+		}
+		if cgroupLimit > 0 {
+			debug.SetMemoryLimit(int64(float64(cgroupLimit) * cfg.MemoryLimitRatio))
+		}
+	}
+
+	if cfg.Ballast > 0 && ballast == nil {
+		ballast = make([]byte, cfg.Ballast)
+	}
+
+	return Settings{
+		MaxProcs:          runtime.GOMAXPROCS(0),
+		NumCPU:            runtime.NumCPU(),
+		GCPercent:         gcPercent(),
+		MemoryLimit:       debug.SetMemoryLimit(-1),
+		CgroupMemoryLimit: cgroupLimit,
+		Ballast:           int64(len(ballast)),
+	}, nil
+}
+
+// gcPercent returns the current GC target, which the runtime only exposes
+// by setting it
+func gcPercent() int {
+	p := debug.SetGCPercent(100)
+	debug.SetGCPercent(p)
+	return p
+}
+
+// CgroupMemoryLimit returns the memory limit of the cgroup mounted at root,
+// reading cgroup v2's memory.max or, failing that, cgroup v1's
+// memory.limit_in_bytes. It returns 0 when neither exists or no limit is set.
+func CgroupMemoryLimit(root string) (int64, error) {
+	for _, name := range []string{"memory.max", filepath.Join("memory", "memory.limit_in_bytes")} {
+		limit, err := readLimit(filepath.Join(root, name))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		return limit, err
+	}
+	return 0, nil
+}
+
+func readLimit(path string) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = f.Close() }()
+
+	s := bufio.NewScanner(f)
+	if !s.Scan() {
+		if err := s.Err(); err != nil {
+			return 0, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		return 0, fmt.Errorf("%s is empty", path)
+	}
+	value := strings.TrimSpace(s.Text())
+	if value == "max" {
+		return 0, nil
+	}
+	limit, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	// cgroup v1 reports no limit as a huge page-aligned number
+	if limit >= math.MaxInt64/2 {
+		return 0, nil
+	}
+	return limit, nil
+}
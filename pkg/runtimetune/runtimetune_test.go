@@ -0,0 +1,169 @@
+package runtimetune
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func writeCgroupFile(t *testing.T, root, name, value string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(value), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCgroupMemoryLimit(t *testing.T) {
+	tests := []struct {
+		name      string
+		files     map[string]string
+		expected  int64
+		expectErr bool
+	}{
+		{name: "no cgroup files"},
+		{
+			name:     "v2 limit",
+			files:    map[string]string{"memory.max": "536870912\n"},
+			expected: 536870912,
+		},
+		{
+			name:  "v2 unlimited",
+			files: map[string]string{"memory.max": "max\n"},
+		},
+		{
+			name:     "v1 limit",
+			files:    map[string]string{"memory/memory.limit_in_bytes": "1073741824\n"},
+			expected: 1073741824,
+		},
+		{
+			name:  "v1 unlimited",
+			files: map[string]string{"memory/memory.limit_in_bytes": "9223372036854771712\n"},
+		},
+		{
+			name:      "unparseable",
+			files:     map[string]string{"memory.max": "lots\n"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, value := range tt.files {
+				writeCgroupFile(t, root, name, value)
+			}
+
+			limit, err := CgroupMemoryLimit(root)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if limit != tt.expected {
+				t.Errorf("expected limit %d, got %d", tt.expected, limit)
+			}
+		})
+	}
+}
+
+// restoreRuntime puts back the process-wide settings Apply changes
+func restoreRuntime(t *testing.T) {
+	procs := runtime.GOMAXPROCS(0)
+	gc := gcPercent()
+	limit := debug.SetMemoryLimit(-1)
+	t.Cleanup(func() {
+		runtime.GOMAXPROCS(procs)
+		debug.SetGCPercent(gc)
+		debug.SetMemoryLimit(limit)
+	})
+}
+
+func TestApply(t *testing.T) {
+	t.Setenv("GOMEMLIMIT", "")
+
+	t.Run("defaults leave the runtime alone", func(t *testing.T) {
+		restoreRuntime(t)
+		procs := runtime.GOMAXPROCS(0)
+
+		settings, err := Apply(Config{CgroupRoot: t.TempDir()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.MaxProcs != procs {
+			t.Errorf("expected GOMAXPROCS %d, got %d", procs, settings.MaxProcs)
+		}
+		if settings.MemoryLimit != math.MaxInt64 {
+			t.Errorf("expected no memory limit, got %d", settings.MemoryLimit)
+		}
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		restoreRuntime(t)
+
+		settings, err := Apply(Config{MaxProcs: 3, GCPercent: 200, MemoryLimit: 256 << 20, CgroupRoot: t.TempDir()})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.MaxProcs != 3 || runtime.GOMAXPROCS(0) != 3 {
+			t.Errorf("expected GOMAXPROCS 3, got %d", settings.MaxProcs)
+		}
+		if settings.GCPercent != 200 {
+			t.Errorf("expected GOGC 200, got %d", settings.GCPercent)
+		}
+		if settings.MemoryLimit != 256<<20 {
+			t.Errorf("expected memory limit %d, got %d", 256<<20, settings.MemoryLimit)
+		}
+	})
+
+	t.Run("memory limit from cgroup", func(t *testing.T) {
+		restoreRuntime(t)
+		root := t.TempDir()
+		writeCgroupFile(t, root, "memory.max", "1000000000\n")
+
+		settings, err := Apply(Config{MemoryLimitRatio: 0.9, CgroupRoot: root})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.CgroupMemoryLimit != 1000000000 {
+			t.Errorf("expected cgroup limit 1000000000, got %d", settings.CgroupMemoryLimit)
+		}
+		if settings.MemoryLimit != 900000000 {
+			t.Errorf("expected memory limit 900000000, got %d", settings.MemoryLimit)
+		}
+	})
+
+	t.Run("GOMEMLIMIT wins over the ratio", func(t *testing.T) {
+		restoreRuntime(t)
+		t.Setenv("GOMEMLIMIT", "512MiB")
+		root := t.TempDir()
+		writeCgroupFile(t, root, "memory.max", "1000000000\n")
+		before := debug.SetMemoryLimit(-1)
+
+		settings, err := Apply(Config{MemoryLimitRatio: 0.9, CgroupRoot: root})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if settings.MemoryLimit != before {
+			t.Errorf("expected memory limit to stay %d, got %d", before, settings.MemoryLimit)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, cfg := range []Config{{MemoryLimitRatio: 1.5}, {MaxProcs: -1}, {Ballast: -1}} {
+			if _, err := Apply(cfg); err == nil {
+				t.Errorf("expected error for %+v", cfg)
+			}
+		}
+	})
+}
@@ -0,0 +1,340 @@
+// Package secretref resolves placeholders in ManifestWork manifests that
+// reference AWS Secrets Manager secrets or SSM parameters, so tenants can
+// distribute secret material without embedding it in request bodies.
+//
+// Placeholders follow the CloudFormation dynamic reference style:
+//
+//	{{resolve:secretsmanager:<secret-arn>}}
+//	{{resolve:secretsmanager:<secret-arn>#<json-key>}}
+//	{{resolve:ssm:<parameter-arn>}}
+//
+// Only full ARNs are accepted so the owning account can be checked before
+// any secret is read.
+package secretref
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/schema"
+)
+
+// ActionResolveSecretReference is the authz action checked for every referenced ARN
+const ActionResolveSecretReference = schema.ActionResolveSecretReference
+
+var (
+	// ErrInvalidReference is returned for malformed references or missing values
+	ErrInvalidReference = errors.New("invalid secret reference")
+	// ErrForbidden is returned when the caller may not resolve a referenced ARN
+	ErrForbidden = errors.New("secret reference not permitted")
+)
+
+var accountIDPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+var placeholderPattern = regexp.MustCompile(`\{\{resolve:(secretsmanager|ssm):([^}#]+)(?:#([^}]+))?\}\}`)
+
+// SecretsManagerClient defines the Secrets Manager operations used by the resolver
+type SecretsManagerClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SSMClient defines the SSM operations used by the resolver
+type SSMClient interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// Caller identifies who is resolving references
+type Caller struct {
+	AccountID  string
+	CallerARN  string
+	Privileged bool
+}
+
+// Resolver replaces secret placeholders with their values
+type Resolver struct {
+	secretsClient    SecretsManagerClient
+	ssmClient        SSMClient
+	checker          authz.Checker
+	platformPrefixes []string
+	logger           *slog.Logger
+}
+
+// NewResolver creates a new Resolver. checker may be nil, in which case only
+// the same-account rule is enforced.
+func NewResolver(secretsClient SecretsManagerClient, ssmClient SSMClient, checker authz.Checker, logger *slog.Logger) *Resolver {
+	return &Resolver{
+		secretsClient: secretsClient,
+		ssmClient:     ssmClient,
+		checker:       checker,
+		logger:        logger,
+	}
+}
+
+// WithPlatformSecrets lets privileged callers resolve the platform's own
+// secrets and parameters, those whose ARN starts with one of prefixes, in any
+// account and without an authz check. Other references of privileged callers
+// are checked like anyone else's.
+func (r *Resolver) WithPlatformSecrets(prefixes []string) *Resolver {
+	r.platformPrefixes = prefixes
+	return r
+}
+
+// ParsePlatformPrefixes validates platform secret ARN prefixes, which must
+// name a Secrets Manager or SSM resource in one account
+func ParsePlatformPrefixes(values []string) ([]string, error) {
+	prefixes := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		parts := strings.SplitN(v, ":", 6)
+		if len(parts) != 6 || parts[0] != "arn" || (parts[2] != "secretsmanager" && parts[2] != "ssm") || !accountIDPattern.MatchString(parts[4]) {
+			return nil, fmt.Errorf("invalid platform secret prefix %q: not a secretsmanager or ssm ARN with an account ID", v)
+		}
+		if parts[5] == "" || parts[5] == "secret:" || parts[5] == "parameter/" {
+			return nil, fmt.Errorf("invalid platform secret prefix %q: it must name a secret or parameter path, not the whole account", v)
+		}
+		prefixes = append(prefixes, v)
+	}
+	return prefixes, nil
+}
+
+// ContainsReference reports whether any manifest in the ManifestWork contains a placeholder
+func ContainsReference(mw *workv1.ManifestWork) bool {
+	for _, m := range mw.Spec.Workload.Manifests {
+		if placeholderPattern.Match(m.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveManifestWork replaces every placeholder in the ManifestWork's
+// manifests in place and returns the number of references resolved.
+// Resolved values are never logged.
+func (r *Resolver) ResolveManifestWork(ctx context.Context, caller Caller, mw *workv1.ManifestWork) (int, error) {
+	cache := make(map[string]string)
+	count := 0
+
+	for i := range mw.Spec.Workload.Manifests {
+		manifest := &mw.Spec.Workload.Manifests[i]
+		if !placeholderPattern.Match(manifest.Raw) {
+			continue
+		}
+
+		var obj interface{}
+		if err := json.Unmarshal(manifest.Raw, &obj); err != nil {
+			return count, fmt.Errorf("failed to unmarshal manifest %d: %w", i, err)
+		}
+
+		resolved, n, err := r.resolveValue(ctx, caller, obj, cache)
+		if err != nil {
+			return count, err
+		}
+		count += n
+
+		b, err := json.Marshal(resolved)
+		if err != nil {
+			return count, fmt.Errorf("failed to marshal manifest %d: %w", i, err)
+		}
+		manifest.Raw = b
+		manifest.Object = nil
+	}
+
+	return count, nil
+}
+
+// resolveValue walks a decoded JSON value and substitutes placeholders in strings
+func (r *Resolver) resolveValue(ctx context.Context, caller Caller, v interface{}, cache map[string]string) (interface{}, int, error) {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		total := 0
+		for k, child := range val {
+			resolved, n, err := r.resolveValue(ctx, caller, child, cache)
+			if err != nil {
+				return nil, 0, err
+			}
+			val[k] = resolved
+			total += n
+		}
+		return val, total, nil
+	case []interface{}:
+		total := 0
+		for i, child := range val {
+			resolved, n, err := r.resolveValue(ctx, caller, child, cache)
+			if err != nil {
+				return nil, 0, err
+			}
+			val[i] = resolved
+			total += n
+		}
+		return val, total, nil
+	case string:
+		return r.resolveString(ctx, caller, val, cache)
+	default:
+		return v, 0, nil
+	}
+}
+
+func (r *Resolver) resolveString(ctx context.Context, caller Caller, s string, cache map[string]string) (string, int, error) {
+	matches := placeholderPattern.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s, 0, nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		placeholder := s[m[0]:m[1]]
+		value, ok := cache[placeholder]
+		if !ok {
+			service := s[m[2]:m[3]]
+			ref := s[m[4]:m[5]]
+			jsonKey := ""
+			if m[6] >= 0 {
+				jsonKey = s[m[6]:m[7]]
+			}
+
+			var err error
+			value, err = r.resolve(ctx, caller, service, ref, jsonKey)
+			if err != nil {
+				return "", 0, err
+			}
+			cache[placeholder] = value
+		}
+
+		b.WriteString(s[last:m[0]])
+		b.WriteString(value)
+		last = m[1]
+	}
+	b.WriteString(s[last:])
+
+	return b.String(), len(matches), nil
+}
+
+func (r *Resolver) resolve(ctx context.Context, caller Caller, service, ref, jsonKey string) (string, error) {
+	parsed, err := arn.Parse(ref)
+	if err != nil || parsed.Service != service {
+		return "", fmt.Errorf("%w: %q is not a %s ARN", ErrInvalidReference, ref, service)
+	}
+
+	if err := r.authorize(ctx, caller, parsed, ref); err != nil {
+		return "", err
+	}
+
+	r.logger.Info("resolving secret reference", "service", service, "arn", ref, "account_id", caller.AccountID)
+
+	switch service {
+	case "secretsmanager":
+		return r.resolveSecret(ctx, ref, jsonKey)
+	default:
+		if jsonKey != "" {
+			return "", fmt.Errorf("%w: json keys are only supported for secretsmanager references", ErrInvalidReference)
+		}
+		return r.resolveParameter(ctx, ref)
+	}
+}
+
+// authorize requires the referenced ARN to live in the caller's account and,
+// when an authz checker is configured, the caller to be allowed to resolve it.
+// Privileged callers skip both checks for the platform's own secrets only, so
+// they cannot read a tenant's secrets through a work request.
+func (r *Resolver) authorize(ctx context.Context, caller Caller, parsed arn.ARN, ref string) error {
+	if caller.Privileged && r.platformSecret(ref) {
+		return nil
+	}
+
+	if parsed.AccountID != caller.AccountID {
+		return fmt.Errorf("%w: %s belongs to another account", ErrForbidden, ref)
+	}
+
+	if r.checker == nil {
+		return nil
+	}
+
+	allowed, err := r.checker.Authorize(ctx, &authz.AuthzRequest{
+		AccountID:    caller.AccountID,
+		CallerARN:    caller.CallerARN,
+		Action:       ActionResolveSecretReference,
+		Resource:     ref,
+		ResourceTags: make(map[string]string),
+		RequestTags:  make(map[string]string),
+		Context:      make(map[string]any),
+	})
+	if errors.Is(err, authz.ErrGuardrailDenied) {
+		return fmt.Errorf("%w: %s", ErrForbidden, ref)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to authorize secret reference: %w", err)
+	}
+	if !allowed {
+		return fmt.Errorf("%w: %s", ErrForbidden, ref)
+	}
+
+	return nil
+}
+
+// platformSecret reports whether ref is one of the platform's own secrets
+func (r *Resolver) platformSecret(ref string) bool {
+	for _, prefix := range r.platformPrefixes {
+		if strings.HasPrefix(ref, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Resolver) resolveSecret(ctx context.Context, ref, jsonKey string) (string, error) {
+	out, err := r.secretsClient.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret value: %w", err)
+	}
+
+	value := aws.ToString(out.SecretString)
+	if jsonKey == "" {
+		return value, nil
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("%w: secret is not a JSON object", ErrInvalidReference)
+	}
+
+	field, ok := fields[jsonKey].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: secret has no string key %q", ErrInvalidReference, jsonKey)
+	}
+
+	return field, nil
+}
+
+func (r *Resolver) resolveParameter(ctx context.Context, ref string) (string, error) {
+	out, err := r.ssmClient.GetParameter(ctx, &ssm.GetParameterInput{
+		Name:           aws.String(ref),
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get parameter: %w", err)
+	}
+
+	if out.Parameter == nil {
+		return "", fmt.Errorf("%w: parameter %s has no value", ErrInvalidReference, ref)
+	}
+
+	return aws.ToString(out.Parameter.Value), nil
+}
@@ -0,0 +1,262 @@
+package secretref
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+)
+
+const (
+	testSecretARN = "arn:aws:secretsmanager:us-east-1:111122223333:secret:db-creds-AbCdEf"
+	testParamARN  = "arn:aws:ssm:us-east-1:111122223333:parameter/app/token"
+)
+
+type mockSecretsClient struct {
+	values map[string]string
+}
+
+func (m *mockSecretsClient) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	v, ok := m.values[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, errors.New("ResourceNotFoundException")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(v)}, nil
+}
+
+type mockSSMClient struct {
+	values map[string]string
+}
+
+func (m *mockSSMClient) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	v, ok := m.values[aws.ToString(params.Name)]
+	if !ok {
+		return nil, errors.New("ParameterNotFound")
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String(v)}}, nil
+}
+
+type mockChecker struct {
+	allowed  bool
+	requests []*authz.AuthzRequest
+}
+
+func (m *mockChecker) Authorize(ctx context.Context, req *authz.AuthzRequest) (bool, error) {
+	m.requests = append(m.requests, req)
+	return m.allowed, nil
+}
+
+func (m *mockChecker) IsPrivileged(ctx context.Context, accountID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockChecker) IsAdmin(ctx context.Context, accountID, principalARN string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockChecker) IsAccountProvisioned(ctx context.Context, accountID string) (bool, error) {
+	return true, nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func newTestResolver(checker authz.Checker) *Resolver {
+	return NewResolver(
+		&mockSecretsClient{values: map[string]string{testSecretARN: `{"username":"admin","password":"s3cret"}`}},
+		&mockSSMClient{values: map[string]string{testParamARN: "tok-123"}},
+		checker,
+		testLogger(),
+	)
+}
+
+func manifestWorkWith(t *testing.T, obj map[string]interface{}) *workv1.ManifestWork {
+	t.Helper()
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("failed to marshal manifest: %v", err)
+	}
+	mw := &workv1.ManifestWork{}
+	mw.Spec.Workload.Manifests = []workv1.Manifest{{RawExtension: runtime.RawExtension{Raw: raw}}}
+	return mw
+}
+
+func secretWith(stringData map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   map[string]interface{}{"name": "app", "namespace": "default"},
+		"stringData": stringData,
+	}
+}
+
+func TestResolveManifestWork(t *testing.T) {
+	checker := &mockChecker{allowed: true}
+	resolver := newTestResolver(checker)
+	caller := Caller{AccountID: "111122223333", CallerARN: "arn:aws:iam::111122223333:role/dev"}
+
+	mw := manifestWorkWith(t, secretWith(map[string]interface{}{
+		"password": "{{resolve:secretsmanager:" + testSecretARN + "#password}}",
+		"dsn":      "postgres://{{resolve:secretsmanager:" + testSecretARN + "#username}}@db",
+		"token":    "{{resolve:ssm:" + testParamARN + "}}",
+	}))
+
+	if !ContainsReference(mw) {
+		t.Fatal("expected manifest work to contain references")
+	}
+
+	count, err := resolver.ResolveManifestWork(context.Background(), caller, mw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 references resolved, got %d", count)
+	}
+	if ContainsReference(mw) {
+		t.Error("expected no references left after resolution")
+	}
+
+	var obj map[string]interface{}
+	_ = json.Unmarshal(mw.Spec.Workload.Manifests[0].Raw, &obj)
+	stringData := obj["stringData"].(map[string]interface{})
+	for key, want := range map[string]string{"password": "s3cret", "dsn": "postgres://admin@db", "token": "tok-123"} {
+		if stringData[key] != want {
+			t.Errorf("expected %s=%q, got %q", key, want, stringData[key])
+		}
+	}
+
+	// One authz check per distinct placeholder
+	if len(checker.requests) != 3 {
+		t.Errorf("expected 3 authz checks, got %d", len(checker.requests))
+	}
+	if checker.requests[0].Action != ActionResolveSecretReference {
+		t.Errorf("unexpected action %q", checker.requests[0].Action)
+	}
+}
+
+func TestResolveManifestWork_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		caller  Caller
+		allowed bool
+		wantErr error
+	}{
+		{
+			name:    "cross-account reference",
+			value:   "{{resolve:secretsmanager:arn:aws:secretsmanager:us-east-1:999999999999:secret:other}}",
+			caller:  Caller{AccountID: "111122223333"},
+			allowed: true,
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "authz denied",
+			value:   "{{resolve:ssm:" + testParamARN + "}}",
+			caller:  Caller{AccountID: "111122223333"},
+			allowed: false,
+			wantErr: ErrForbidden,
+		},
+		{
+			name:    "not an ARN",
+			value:   "{{resolve:ssm:/app/token}}",
+			caller:  Caller{AccountID: "111122223333"},
+			allowed: true,
+			wantErr: ErrInvalidReference,
+		},
+		{
+			name:    "missing json key",
+			value:   "{{resolve:secretsmanager:" + testSecretARN + "#missing}}",
+			caller:  Caller{AccountID: "111122223333"},
+			allowed: true,
+			wantErr: ErrInvalidReference,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := newTestResolver(&mockChecker{allowed: tt.allowed})
+			mw := manifestWorkWith(t, secretWith(map[string]interface{}{"value": tt.value}))
+
+			_, err := resolver.ResolveManifestWork(context.Background(), tt.caller, mw)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveManifestWork_Privileged(t *testing.T) {
+	platformPrefix := "arn:aws:ssm:us-east-1:111122223333:parameter/app/"
+
+	tests := []struct {
+		name          string
+		accountID     string
+		ref           string
+		allowed       bool
+		expectErr     error
+		expectChecked int
+	}{
+		{name: "platform secret in another account", accountID: "000000000000", ref: "{{resolve:ssm:" + testParamARN + "}}"},
+		{name: "tenant secret", accountID: "000000000000", ref: "{{resolve:secretsmanager:" + testSecretARN + "#password}}", expectErr: ErrForbidden},
+		{name: "own secret is authorized", accountID: "111122223333", ref: "{{resolve:secretsmanager:" + testSecretARN + "#password}}", allowed: true, expectChecked: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := &mockChecker{allowed: tt.allowed}
+			resolver := newTestResolver(checker).WithPlatformSecrets([]string{platformPrefix})
+			mw := manifestWorkWith(t, secretWith(map[string]interface{}{"value": tt.ref}))
+
+			_, err := resolver.ResolveManifestWork(context.Background(), Caller{AccountID: tt.accountID, Privileged: true}, mw)
+			if tt.expectErr != nil {
+				if !errors.Is(err, tt.expectErr) {
+					t.Fatalf("expected %v, got %v", tt.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(checker.requests) != tt.expectChecked {
+				t.Errorf("expected %d authz checks, got %d", tt.expectChecked, len(checker.requests))
+			}
+		})
+	}
+}
+
+func TestParsePlatformPrefixes(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    int
+		wantErr bool
+	}{
+		{name: "secret and parameter paths", values: []string{"arn:aws:secretsmanager:us-east-1:111122223333:secret:rosa-platform/", " arn:aws:ssm:us-east-1:111122223333:parameter/rosa/ ", ""}, want: 2},
+		{name: "none", want: 0},
+		{name: "not an ARN", values: []string{"rosa-platform/"}, wantErr: true},
+		{name: "other service", values: []string{"arn:aws:s3:::bucket/"}, wantErr: true},
+		{name: "whole account", values: []string{"arn:aws:secretsmanager:us-east-1:111122223333:secret:"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePlatformPrefixes(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && len(got) != tt.want {
+				t.Errorf("expected %d prefixes, got %v", tt.want, got)
+			}
+		})
+	}
+}
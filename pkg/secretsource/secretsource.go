@@ -0,0 +1,250 @@
+// Package secretsource resolves configuration values given as references to
+// AWS Secrets Manager secrets or SSM parameters, so secrets need not be
+// passed in plaintext through flags or environment variables:
+//
+//	secretsmanager://<secret-id>
+//	secretsmanager://<secret-id>#<json-key>
+//	ssm://<parameter-name>
+//
+// Secret IDs may be names or ARNs; parameter names are paths such as
+// ssm:///rosa/redis-password. Values that are not references are used as
+// given. Resolved values can be refreshed periodically, so a rotated secret
+// is picked up without a restart.
+package secretsource
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Reference schemes
+const (
+	SchemeSecretsManager = "secretsmanager://"
+	SchemeSSM            = "ssm://"
+)
+
+// ErrInvalidReference is returned for malformed references
+var ErrInvalidReference = errors.New("invalid secret reference")
+
+var refreshFailures = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "rosa_config_secret_refresh_failures_total",
+	Help: "Failed refreshes of configuration values resolved from Secrets Manager or SSM; the last value is kept.",
+})
+
+// SecretsManagerClient provides the Secrets Manager operation used by Source
+type SecretsManagerClient interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+}
+
+// SSMClient provides the SSM operation used by Source
+type SSMClient interface {
+	GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error)
+}
+
+// IsReference reports whether value is a secretsmanager:// or ssm:// URI
+func IsReference(value string) bool {
+	return strings.HasPrefix(value, SchemeSecretsManager) || strings.HasPrefix(value, SchemeSSM)
+}
+
+// reference is a parsed secret reference
+type reference struct {
+	raw     string
+	scheme  string
+	id      string
+	jsonKey string
+}
+
+// Validate checks that value, if it is a reference, is well formed
+func Validate(value string) error {
+	if !IsReference(value) {
+		return nil
+	}
+	_, err := parse(value)
+	return err
+}
+
+func parse(raw string) (reference, error) {
+	ref := reference{raw: raw}
+	switch {
+	case strings.HasPrefix(raw, SchemeSecretsManager):
+		ref.scheme = SchemeSecretsManager
+		ref.id, ref.jsonKey, _ = strings.Cut(strings.TrimPrefix(raw, SchemeSecretsManager), "#")
+	case strings.HasPrefix(raw, SchemeSSM):
+		ref.scheme = SchemeSSM
+		ref.id = strings.TrimPrefix(raw, SchemeSSM)
+		if strings.Contains(ref.id, "#") {
+			return ref, fmt.Errorf("%w: json keys are only supported for secretsmanager references", ErrInvalidReference)
+		}
+	default:
+		return ref, fmt.Errorf("%w: %q is not a secretsmanager:// or ssm:// URI", ErrInvalidReference, raw)
+	}
+	if ref.id == "" {
+		return ref, fmt.Errorf("%w: %q names no secret", ErrInvalidReference, raw)
+	}
+	return ref, nil
+}
+
+// Value is a configuration value, refreshed in place when it was resolved
+// from a reference
+type Value struct {
+	ref     *reference
+	current atomic.Pointer[string]
+}
+
+// Static returns a Value that never changes
+func Static(value string) *Value {
+	v := &Value{}
+	v.current.Store(&value)
+	return v
+}
+
+// Get returns the current value
+func (v *Value) Get() string {
+	return *v.current.Load()
+}
+
+// Source resolves references and keeps the values it resolved fresh
+type Source struct {
+	secrets  SecretsManagerClient
+	ssm      SSMClient
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu     sync.Mutex
+	values []*Value
+}
+
+// New creates a Source that refreshes resolved values every interval; 0
+// resolves them only once
+func New(secrets SecretsManagerClient, ssmClient SSMClient, interval time.Duration, logger *slog.Logger) *Source {
+	return &Source{secrets: secrets, ssm: ssmClient, interval: interval, logger: logger}
+}
+
+// NewAWS creates a Source with clients for region
+func NewAWS(ctx context.Context, region string, interval time.Duration, logger *slog.Logger) (*Source, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for secrets: %w", err)
+	}
+	return New(secretsmanager.NewFromConfig(awsCfg), ssm.NewFromConfig(awsCfg), interval, logger), nil
+}
+
+// Resolve returns the value of raw, reading it from Secrets Manager or SSM
+// when it is a reference. Referenced values are refreshed by Run.
+func (s *Source) Resolve(ctx context.Context, raw string) (*Value, error) {
+	if !IsReference(raw) {
+		return Static(raw), nil
+	}
+	ref, err := parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	resolved, err := s.fetch(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &Value{ref: &ref}
+	v.current.Store(&resolved)
+	s.mu.Lock()
+	s.values = append(s.values, v)
+	s.mu.Unlock()
+	return v, nil
+}
+
+// Refreshes reports whether Run has anything to do
+func (s *Source) Refreshes() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.interval > 0 && len(s.values) > 0
+}
+
+// Run refreshes resolved values every interval until ctx is done. A failed
+// refresh keeps the last value.
+func (s *Source) Run(ctx context.Context) {
+	if s.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Refresh(ctx)
+		}
+	}
+}
+
+// Refresh re-reads every resolved value
+func (s *Source) Refresh(ctx context.Context) {
+	s.mu.Lock()
+	values := append([]*Value(nil), s.values...)
+	s.mu.Unlock()
+
+	for _, v := range values {
+		resolved, err := s.fetch(ctx, *v.ref)
+		if err != nil {
+			refreshFailures.Inc()
+			// The reference names the secret, never its value
+			s.logger.Warn("failed to refresh secret, keeping the last value", "reference", v.ref.raw, "error", err)
+			continue
+		}
+		if resolved != v.Get() {
+			v.current.Store(&resolved)
+			s.logger.Info("secret rotated", "reference", v.ref.raw)
+		}
+	}
+}
+
+func (s *Source) fetch(ctx context.Context, ref reference) (string, error) {
+	if ref.scheme == SchemeSSM {
+		out, err := s.ssm.GetParameter(ctx, &ssm.GetParameterInput{
+			Name:           aws.String(ref.id),
+			WithDecryption: aws.Bool(true),
+		})
+		if err != nil {
+			return "", fmt.Errorf("failed to get parameter %s: %w", ref.id, err)
+		}
+		if out.Parameter == nil {
+			return "", fmt.Errorf("parameter %s has no value", ref.id)
+		}
+		return aws.ToString(out.Parameter.Value), nil
+	}
+
+	out, err := s.secrets.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(ref.id),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to get secret %s: %w", ref.id, err)
+	}
+	value := aws.ToString(out.SecretString)
+	if ref.jsonKey == "" {
+		return value, nil
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(value), &fields); err != nil {
+		return "", fmt.Errorf("%w: secret %s is not a JSON object", ErrInvalidReference, ref.id)
+	}
+	field, ok := fields[ref.jsonKey].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: secret %s has no string key %q", ErrInvalidReference, ref.id, ref.jsonKey)
+	}
+	return field, nil
+}
@@ -0,0 +1,126 @@
+package secretsource
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	ssmtypes "github.com/aws/aws-sdk-go-v2/service/ssm/types"
+)
+
+type fakeSecrets struct {
+	values map[string]string
+	err    error
+}
+
+func (f *fakeSecrets) GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	v, ok := f.values[aws.ToString(params.SecretId)]
+	if !ok {
+		return nil, errors.New("secret not found")
+	}
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(v)}, nil
+}
+
+type fakeSSM struct {
+	values map[string]string
+}
+
+func (f *fakeSSM) GetParameter(ctx context.Context, params *ssm.GetParameterInput, optFns ...func(*ssm.Options)) (*ssm.GetParameterOutput, error) {
+	v, ok := f.values[aws.ToString(params.Name)]
+	if !ok {
+		return nil, errors.New("parameter not found")
+	}
+	return &ssm.GetParameterOutput{Parameter: &ssmtypes.Parameter{Value: aws.String(v)}}, nil
+}
+
+func newTestSource(secrets *fakeSecrets) *Source {
+	params := &fakeSSM{values: map[string]string{"/rosa/redis-password": "hunter2"}}
+	return New(secrets, params, 0, slog.Default())
+}
+
+func TestSource_Resolve(t *testing.T) {
+	secrets := &fakeSecrets{values: map[string]string{
+		"rosa/gateway": "s3cret",
+		"rosa/json":    `{"password":"p4ss","port":6379}`,
+	}}
+	source := newTestSource(secrets)
+
+	tests := []struct {
+		name      string
+		raw       string
+		expect    string
+		expectErr bool
+	}{
+		{name: "plain value", raw: "plaintext", expect: "plaintext"},
+		{name: "empty value", raw: "", expect: ""},
+		{name: "secretsmanager", raw: "secretsmanager://rosa/gateway", expect: "s3cret"},
+		{name: "secretsmanager json key", raw: "secretsmanager://rosa/json#password", expect: "p4ss"},
+		{name: "ssm", raw: "ssm:///rosa/redis-password", expect: "hunter2"},
+		{name: "non-string json key", raw: "secretsmanager://rosa/json#port", expectErr: true},
+		{name: "missing secret", raw: "secretsmanager://rosa/missing", expectErr: true},
+		{name: "ssm json key", raw: "ssm:///rosa/redis-password#key", expectErr: true},
+		{name: "empty reference", raw: "ssm://", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := source.Resolve(context.Background(), tt.raw)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("expected an error, got value %q", v.Get())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if v.Get() != tt.expect {
+				t.Errorf("expected %q, got %q", tt.expect, v.Get())
+			}
+		})
+	}
+}
+
+func TestSource_Refresh(t *testing.T) {
+	secrets := &fakeSecrets{values: map[string]string{"rosa/gateway": "old"}}
+	source := newTestSource(secrets)
+
+	v, err := source.Resolve(context.Background(), "secretsmanager://rosa/gateway")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	secrets.values["rosa/gateway"] = "new"
+	source.Refresh(context.Background())
+	if v.Get() != "new" {
+		t.Errorf("expected the rotated value, got %q", v.Get())
+	}
+
+	// A failed refresh keeps the last value
+	secrets.err = errors.New("throttled")
+	source.Refresh(context.Background())
+	if v.Get() != "new" {
+		t.Errorf("expected the last value to be kept, got %q", v.Get())
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for raw, valid := range map[string]bool{
+		"plaintext":                     true,
+		"secretsmanager://rosa/gateway": true,
+		"ssm:///rosa/param":             true,
+		"secretsmanager://":             false,
+		"ssm:///rosa/param#key":         false,
+	} {
+		if err := Validate(raw); (err == nil) != valid {
+			t.Errorf("Validate(%q) = %v, expected valid %v", raw, err, valid)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+// Components reported to the health handler
+const (
+	componentAuthz              = "authz"
+	componentZoaReconciler      = "zoa-reconciler"
+	componentAPIServer          = "api-server"
+	componentHealthServer       = "health-server"
+	componentMetricsServer      = "metrics-server"
+	componentPolicyBackup       = "policy-backup"
+	componentWorkScheduler      = "work-scheduler"
+	componentAuthzRecovery      = "authz-recovery"
+	componentCacheInvalidations = "cache-invalidations"
+	componentAuthzStreams       = "authz-streams"
+	componentDecisionAnalytics  = "decision-analytics"
+	componentPatternMatcher     = "pattern-matcher"
+	componentDecisionAudit      = "decision-audit"
+	componentAPIKeyCache        = "api-key-cache"
+	componentProvisioner        = "account-provisioner"
+	componentOnboardingSweep    = "onboarding-sweep"
+	componentDeliveryCanary     = "delivery-canary"
+	componentMetering           = "metering"
+	componentEvents             = "events"
+	componentSecretRefresh      = "secret-refresh"
+	componentMaestroVersion     = "maestro-version"
+	componentMaestroFailover    = "maestro-failover"
+	componentWorkHistory        = "work-history"
+)
+
+// authzInitTimeout bounds each DynamoDB reachability check made during a
+// degraded start and its retries
+const authzInitTimeout = 5 * time.Second
+
+// maxAuthzRetryInterval caps the backoff between authz initialization retries
+const maxAuthzRetryInterval = 2 * time.Minute
+
+// authzRecovery retries the authz dependency check after a degraded start and
+// restores authz and readiness once it passes
+type authzRecovery struct {
+	check    func(ctx context.Context) error
+	gate     *middleware.AuthzGate
+	health   *apphandlers.HealthHandler
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// Run retries the check with exponential backoff until it passes or ctx is cancelled
+func (a *authzRecovery) Run(ctx context.Context) {
+	interval := a.interval
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+
+		checkCtx, cancel := context.WithTimeout(ctx, authzInitTimeout)
+		err := a.check(checkCtx)
+		cancel()
+		if err == nil {
+			a.gate.SetHealthy(true)
+			a.health.SetComponent(componentAuthz, true, nil)
+			a.logger.Info("authz recovered from degraded start", "attempts", attempt)
+			return
+		}
+
+		interval = min(interval*2, maxAuthzRetryInterval)
+		a.health.SetComponent(componentAuthz, true, err)
+		a.logger.Warn("authz still unavailable", "error", err, "attempt", attempt, "next_retry", interval)
+	}
+}
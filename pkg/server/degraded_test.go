@@ -0,0 +1,95 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+func TestNew_DegradedStart(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	// Nothing listens here, so the startup DynamoDB check fails
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	unreachable.Close()
+
+	cfg := config.NewConfig()
+	cfg.Authz.DynamoDBEndpoint = unreachable.URL
+	cfg.Authz.CedarAgentEndpoint = unreachable.URL
+	cfg.Authz.DegradedStart = true
+	cfg.Authz.DegradedMode = authz.DegradedReadOnly
+
+	server, err := New(cfg, logger)
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+	if server.authzRecovery == nil {
+		t.Fatal("expected authz recovery to be scheduled")
+	}
+
+	w := httptest.NewRecorder()
+	server.apiServer.Handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v0/ready", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected readiness 503 while degraded, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v0/accounts", nil)
+	req.Header.Set(middleware.HeaderAccountID, "123456789012")
+	req.Header.Set(middleware.HeaderCallerARN, "arn:aws:iam::123456789012:user/test")
+	w = httptest.NewRecorder()
+	server.apiServer.Handler.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected writes to get 503 in read-only mode, got %d", w.Code)
+	}
+}
+
+func TestAuthzRecovery_Run(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	gate := middleware.NewAuthzGate(false, logger)
+	gate.SetHealthy(false)
+	health := apphandlers.NewHealthHandler()
+	health.SetComponent(componentAuthz, true, errors.New("unreachable"))
+
+	var calls atomic.Int32
+	recovery := &authzRecovery{
+		check: func(ctx context.Context) error {
+			if calls.Add(1) < 3 {
+				return errors.New("unreachable")
+			}
+			return nil
+		},
+		gate:     gate,
+		health:   health,
+		interval: time.Millisecond,
+		logger:   logger,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	recovery.Run(ctx)
+
+	if calls.Load() != 3 {
+		t.Errorf("expected 3 checks, got %d", calls.Load())
+	}
+	if !gate.Healthy() {
+		t.Error("expected authz gate to be healthy after recovery")
+	}
+
+	w := httptest.NewRecorder()
+	health.Readiness(w, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("expected readiness 200 after recovery, got %d", w.Code)
+	}
+}
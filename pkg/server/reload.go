@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestrofailover"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+)
+
+// reloadTargets are the components reloaded settings are applied to
+type reloadTargets struct {
+	accounts *middleware.Authorization
+	// rateLimit is nil while rate limiting is disabled
+	rateLimit *middleware.RateLimit
+	plans     *middleware.Plan
+	// planResolver is nil while authz is disabled
+	planResolver    *plans.Resolver
+	quota           *apphandlers.QuotaHandler
+	mgmtClusters    *apphandlers.ManagementClusterHandler
+	resourceBundles *apphandlers.ResourceBundleHandler
+	config          *apphandlers.ConfigHandler
+	maxManifests    int
+	logger          *slog.Logger
+	// maestro is nil without a secondary Maestro
+	maestro *maestrofailover.Failover
+}
+
+// SetReloader serves configuration reloads through the admin routes with
+// reload. It must be called before Run.
+func (s *Server) SetReloader(reload apphandlers.ConfigReloader) {
+	s.reload.config.SetReloader(reload)
+}
+
+// Reload applies the changed settings of cfg, which must be among
+// config.ReloadableSettings, and returns why any of them could not be.
+// The log level belongs to the caller's logger and is left to it.
+func (s *Server) Reload(ctx context.Context, cfg *config.Config, changed []string) map[string]error {
+	t := s.reload
+	failed := map[string]error{}
+	rateLimitChanged := false
+	for _, setting := range changed {
+		switch setting {
+		case config.SettingAllowedAccounts:
+			t.accounts.SetAllowedAccounts(cfg.AllowedAccounts)
+		case config.SettingRateLimit, config.SettingRateLimitWindow:
+			rateLimitChanged = true
+		case config.SettingManagementClusterTTL:
+			if err := t.mgmtClusters.SetListCacheTTL(cfg.MgmtClusters.ListCacheTTL); err != nil {
+				failed[setting] = err
+			}
+		case config.SettingResourceBundleSummaryTTL:
+			t.resourceBundles.SetSummaryCacheTTL(cfg.ResourceBundles.SummaryCacheTTL)
+		case config.SettingPlanCacheTTL:
+			if t.planResolver != nil {
+				t.planResolver.SetTTL(cfg.Plans.CacheTTL)
+			}
+		case config.SettingMaestroActiveEndpoint:
+			// Validation only accepts the secondary when one is configured.
+			// An operator choosing an endpoint overrides its health.
+			if t.maestro != nil {
+				if err := t.maestro.SwitchTo(cfg.Maestro.ActiveEndpoint, maestrofailover.ReasonReload, true); err != nil {
+					failed[setting] = err
+				}
+			}
+		}
+	}
+
+	if rateLimitChanged {
+		if err := t.reloadRateLimit(ctx, cfg.RateLimit); err != nil {
+			for _, setting := range changed {
+				if setting == config.SettingRateLimit || setting == config.SettingRateLimitWindow {
+					failed[setting] = err
+				}
+			}
+		}
+	}
+	return failed
+}
+
+// reloadRateLimit replaces the limiters and plan tiers for the new limit
+// and window. Rate limiting cannot be enabled or disabled this way, as the
+// middleware is only installed when it is enabled at startup.
+func (t *reloadTargets) reloadRateLimit(ctx context.Context, cfg config.RateLimitConfig) error {
+	if t.rateLimit == nil && cfg.Limit > 0 {
+		return errors.New("enabling rate limiting requires a restart")
+	}
+	if t.rateLimit != nil && cfg.Limit <= 0 {
+		return errors.New("disabling rate limiting requires a restart")
+	}
+	tiers := plans.NewTiers(cfg.Limit, t.maxManifests)
+	if t.rateLimit != nil {
+		limiter, planLimiters, err := newRateLimiter(ctx, cfg, tiers, t.logger)
+		if err != nil {
+			return err
+		}
+		t.rateLimit.SetLimiters(limiter, planLimiters)
+	}
+	t.plans.SetTiers(tiers)
+	t.quota.SetWindow(cfg.Window)
+	return nil
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+
+	apiv0 "github.com/openshift/rosa-regional-platform-api/pkg/api/v0"
+)
+
+// routeTable records the middleware each router applies, which mux does not
+// expose, so the admin routes endpoint can report the chain of every route
+type routeTable struct {
+	root    *mux.Router
+	outer   []string
+	chains  map[*mux.Router][]string
+	parents map[*mux.Router]*mux.Router
+}
+
+func newRouteTable(root *mux.Router) *routeTable {
+	return &routeTable{
+		root:    root,
+		chains:  map[*mux.Router][]string{},
+		parents: map[*mux.Router]*mux.Router{},
+	}
+}
+
+// use adds mw to router
+func (t *routeTable) use(router *mux.Router, mw mux.MiddlewareFunc) {
+	router.Use(mw)
+	t.chains[router] = append(t.chains[router], funcName(mw))
+}
+
+// subrouter creates a router for the routes under prefix
+func (t *routeTable) subrouter(parent *mux.Router, prefix string) *mux.Router {
+	router := parent.PathPrefix(prefix).Subrouter()
+	t.parents[router] = parent
+	return router
+}
+
+// wrap returns the root router behind mws, which run before the router
+// matches a route, the first outermost
+func (t *routeTable) wrap(mws ...mux.MiddlewareFunc) http.Handler {
+	var handler http.Handler = t.root
+	for _, mw := range slices.Backward(mws) {
+		handler = mw(handler)
+	}
+	for _, mw := range mws {
+		t.outer = append(t.outer, funcName(mw))
+	}
+	return handler
+}
+
+// chain returns the middleware that runs before the routes of router
+func (t *routeTable) chain(router *mux.Router) []string {
+	var chain []string
+	if parent, ok := t.parents[router]; ok {
+		chain = t.chain(parent)
+	} else {
+		chain = slices.Clone(t.outer)
+	}
+	return append(chain, t.chains[router]...)
+}
+
+// routes lists the routes in the order they are matched, or with a path only
+// the route a request for method and path is routed to
+func (t *routeTable) routes(method, path string) []apiv0.RouteInfo {
+	var matched *mux.Route
+	if path != "" {
+		req, err := http.NewRequest(method, path, nil)
+		if err != nil {
+			return nil
+		}
+		var match mux.RouteMatch
+		if !t.root.Match(req, &match) || match.MatchErr != nil {
+			return nil
+		}
+		matched = match.Route
+	}
+
+	var routes []apiv0.RouteInfo
+	_ = t.root.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		handler := route.GetHandler()
+		if handler == nil || (matched != nil && route != matched) {
+			return nil
+		}
+		info := apiv0.RouteInfo{
+			Name:       route.GetName(),
+			Handler:    funcName(handler),
+			Middleware: t.chain(router),
+		}
+		info.Path, _ = route.GetPathTemplate()
+		info.Methods, _ = route.GetMethods()
+		routes = append(routes, info)
+		return nil
+	})
+	return routes
+}
+
+// funcName names a middleware or handler after its function, such as
+// middleware.Privileged.CheckPrivileged
+func funcName(fn any) string {
+	v := reflect.ValueOf(fn)
+	if v.Kind() != reflect.Func {
+		return fmt.Sprintf("%T", fn)
+	}
+	f := runtime.FuncForPC(v.Pointer())
+	if f == nil {
+		return fmt.Sprintf("%T", fn)
+	}
+	name := f.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/gorilla/mux"
+
+	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+)
+
+func TestRouteTable(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	router := mux.NewRouter()
+	routes := newRouteTable(router)
+	routes.wrap(middleware.NewRecovery(logger).Recover)
+	routes.use(router, middleware.RequestID)
+
+	health := apphandlers.NewHealthHandler()
+	router.HandleFunc("/api/v0/live", health.Liveness).Methods(http.MethodGet)
+	admin := routes.subrouter(router, "/api/v0/admin")
+	routes.use(admin, middleware.DryRun)
+	admin.HandleFunc("/things/{id}", health.Readiness).Methods(http.MethodDelete)
+
+	all := routes.routes(http.MethodGet, "")
+	if len(all) != 2 {
+		t.Fatalf("expected 2 routes, got %+v", all)
+	}
+	if all[0].Path != "/api/v0/live" || all[0].Handler != "handlers.HealthHandler.Liveness" {
+		t.Errorf("unexpected route: %+v", all[0])
+	}
+	expected := []string{"middleware.Recovery.Recover", "middleware.RequestID", "middleware.DryRun"}
+	if all[1].Path != "/api/v0/admin/things/{id}" || !slices.Equal(all[1].Middleware, expected) {
+		t.Errorf("expected the admin route behind %v, got %+v", expected, all[1])
+	}
+	if !slices.Equal(all[1].Methods, []string{http.MethodDelete}) {
+		t.Errorf("expected DELETE, got %v", all[1].Methods)
+	}
+
+	// A path returns only the route it is routed to
+	matched := routes.routes(http.MethodDelete, "/api/v0/admin/things/123")
+	if len(matched) != 1 || matched[0].Path != "/api/v0/admin/things/{id}" {
+		t.Errorf("expected the admin route to match, got %+v", matched)
+	}
+	if matched := routes.routes(http.MethodGet, "/api/v0/admin/things/123"); len(matched) != 0 {
+		t.Errorf("expected no route for the wrong method, got %+v", matched)
+	}
+}
@@ -2,67 +2,404 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/ssm"
+	smithymiddleware "github.com/aws/smithy-go/middleware"
 	"github.com/gorilla/mux"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sigstore/sigstore-go/pkg/root"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz"
 	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/stream"
+	"github.com/openshift/rosa-regional-platform-api/pkg/bootstrap"
+	"github.com/openshift/rosa-regional-platform-api/pkg/cache"
+	"github.com/openshift/rosa-regional-platform-api/pkg/canary"
+	"github.com/openshift/rosa-regional-platform-api/pkg/capture"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/hyperfleet"
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clustercaps"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clusterregistry"
 	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/doctor"
+	"github.com/openshift/rosa-regional-platform-api/pkg/envelope"
+	"github.com/openshift/rosa-regional-platform-api/pkg/events"
 	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+	"github.com/openshift/rosa-regional-platform-api/pkg/i18n"
+	"github.com/openshift/rosa-regional-platform-api/pkg/leader"
+	"github.com/openshift/rosa-regional-platform-api/pkg/lifecycle"
+	"github.com/openshift/rosa-regional-platform-api/pkg/loadshed"
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestrofailover"
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestroversion"
+	"github.com/openshift/rosa-regional-platform-api/pkg/metering"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
+	"github.com/openshift/rosa-regional-platform-api/pkg/mirror"
+	"github.com/openshift/rosa-regional-platform-api/pkg/notify"
+	"github.com/openshift/rosa-regional-platform-api/pkg/opensearch"
+	"github.com/openshift/rosa-regional-platform-api/pkg/operations"
+	"github.com/openshift/rosa-regional-platform-api/pkg/plans"
+	"github.com/openshift/rosa-regional-platform-api/pkg/policybackup"
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+	"github.com/openshift/rosa-regional-platform-api/pkg/residency"
+	"github.com/openshift/rosa-regional-platform-api/pkg/secretref"
+	"github.com/openshift/rosa-regional-platform-api/pkg/secretsource"
+	"github.com/openshift/rosa-regional-platform-api/pkg/slo"
+	"github.com/openshift/rosa-regional-platform-api/pkg/status"
+	"github.com/openshift/rosa-regional-platform-api/pkg/tracecontext"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workchart"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workhistory"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workpayload"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workqueue"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workrate"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workschedule"
 	"github.com/openshift/rosa-regional-platform-api/pkg/zoa"
 )
 
+// readMethods are the methods list and get routes answer. HEAD runs the GET
+// handler and sends its headers without the body.
+var readMethods = []string{http.MethodGet, http.MethodHead}
+
+// traceContextAPIOptions make the AWS calls a request causes carry its trace
+// context
+var traceContextAPIOptions = []func(*smithymiddleware.Stack) error{tracecontext.AWSMiddleware()}
+
 // Server represents the API server
 type Server struct {
-	cfg            *config.Config
-	logger         *slog.Logger
-	apiServer      *http.Server
-	healthServer   *http.Server
-	metricsServer  *http.Server
-	healthHandler  *apphandlers.HealthHandler
-	zoaReconciler  *zoa.Reconciler
+	cfg           *config.Config
+	logger        *slog.Logger
+	apiServer     *http.Server
+	apiRouter     *mux.Router
+	healthServer  *http.Server
+	metricsServer *http.Server
+	healthHandler *apphandlers.HealthHandler
+	zoaReconciler *zoa.Reconciler
+	backupWorker  *policybackup.Worker
+	workScheduler *workschedule.Scheduler
+	workHistory   *workhistory.Recorder
+	canary        *canary.Canary
+	maestroVer    *maestroversion.Detector
+	metering      *metering.Emitter
+	// events is nil unless platform events are published to Kafka
+	events *events.KafkaPublisher
+	// maestroFail is nil without a secondary Maestro
+	maestroFail *maestrofailover.Failover
+	// secrets is nil unless a secret is given as a reference
+	secrets       *secretsource.Source
+	authzRecovery *authzRecovery
+	// warmup is nil when readiness passes as soon as the server starts
+	warmup       *warmup
+	elector      *leader.Elector
+	redisCache   *cache.Redis
+	authzStreams *stream.Consumer
+	// decisionAnalytics is nil unless authz decision analytics is enabled
+	decisionAnalytics *authz.DecisionAnalytics
+	// patternMatcher is nil unless authz principal patterns are enabled
+	patternMatcher *authz.PatternMatcher
+	// decisionAudit is nil unless the authz decision audit log is enabled
+	decisionAudit *authz.DecisionAudit
+	// apiKeyCache is nil unless authz API keys are cached
+	apiKeyCache *authz.APIKeyCache
+	// provisioner is nil unless accounts can be enabled asynchronously
+	provisioner *authz.AccountProvisioner
+	reload      *reloadTargets
 }
 
 // New creates a new Server instance
 func New(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 	ctx := context.Background()
 
+	// Secrets given as Secrets Manager or SSM references, refreshed in place
+	secrets, err := newSecretSource(ctx, cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+	sharedSecret, err := resolveSecret(ctx, secrets, cfg.Identity.SharedSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the identity shared secret: %w", err)
+	}
+	redisPassword, err := resolveSecret(ctx, secrets, cfg.Cache.Redis.Password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve the Redis password: %w", err)
+	}
+
 	// Create Maestro client
 	maestroClient := maestro.NewClient(cfg.Maestro, logger)
 
+	// Optional Maestro features are used once a version check finds them
+	var maestroVersion *maestroversion.Detector
+	if cfg.Maestro.VersionCheckInterval > 0 {
+		maestroVersion = maestroversion.New(maestroClient, cfg.Maestro.VersionCheckInterval, logger)
+	}
+
+	// With a secondary Maestro, calls can be switched between the two
+	var maestroFailover *maestrofailover.Failover
+	if cfg.Maestro.SecondaryBaseURL != "" {
+		maestroFailover = maestrofailover.New(maestroClient, cfg.Maestro.FailoverCheckInterval, cfg.Maestro.FailoverThreshold, logger)
+	}
+
 	// Create Hyperfleet client
 	hyperfleetClient := hyperfleet.NewClient(cfg.Hyperfleet, logger)
 
 	// Create handlers
 	healthHandler := apphandlers.NewHealthHandler()
 	infoHandler := apphandlers.NewInfoHandler()
-	mgmtClusterHandler := apphandlers.NewManagementClusterHandler(maestroClient, logger)
-	resourceBundleHandler := apphandlers.NewResourceBundleHandler(maestroClient, logger)
-	workHandler := apphandlers.NewWorkHandler(maestroClient, logger)
-	clusterHandler := apphandlers.NewClusterHandler(hyperfleetClient, maestroClient, logger)
-	nodePoolHandler := apphandlers.NewNodePoolHandler(maestroClient, logger)
+	var mgmtRegistry clusterregistry.Registry
+	if cfg.MgmtClusters.RegistryEnabled {
+		registryDynamoClient, err := client.NewDynamoDBClient(ctx, cfg.MgmtClusters.AWSRegion, cfg.MgmtClusters.DynamoDBEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create management cluster registry DynamoDB client: %w", err)
+		}
+		mgmtRegistry = clusterregistry.NewDynamoRegistry(cfg.MgmtClusters.RegistryTableName, registryDynamoClient, logger)
+		logger.Info("management cluster registry enabled", "table", cfg.MgmtClusters.RegistryTableName)
+	}
+	var clusterCaps clustercaps.Store
+	if cfg.MgmtClusters.CapabilitiesTableName != "" {
+		capsDynamoClient, err := client.NewDynamoDBClient(ctx, cfg.MgmtClusters.AWSRegion, cfg.MgmtClusters.DynamoDBEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cluster capabilities DynamoDB client: %w", err)
+		}
+		clusterCaps = clustercaps.NewDynamoStore(cfg.MgmtClusters.CapabilitiesTableName, capsDynamoClient, logger)
+		logger.Info("management cluster capability checks enabled", "table", cfg.MgmtClusters.CapabilitiesTableName)
+	}
+	// Caches are kept per replica unless a shared backend is configured
+	var sharedCache cache.Cache
+	var redisCache *cache.Redis
+	if cfg.Cache.Backend == cache.BackendRedis {
+		redisCfg := cfg.Cache.Redis
+		redisCfg.PasswordFunc = redisPassword.Get
+		redisCache = cache.NewRedis(redisCfg, logger)
+		sharedCache = redisCache
+		logger.Info("shared Redis cache enabled", "addrs", cfg.Cache.Redis.Addrs, "cluster_mode", cfg.Cache.Redis.ClusterMode, "tls", cfg.Cache.Redis.TLS)
+	}
+	tenantPages := pageLimits(cfg.Pagination.Tenant)
+	platformPages := pageLimits(cfg.Pagination.Platform)
+	mgmtClusterHandler := apphandlers.NewManagementClusterHandler(maestroClient, mgmtRegistry, logger).
+		WithPageLimits(platformPages).
+		WithListCache(cfg.MgmtClusters.ListCacheTTL, cfg.MgmtClusters.ListCacheStale, sharedCache)
+	if clusterCaps != nil {
+		mgmtClusterHandler.WithCapabilities(clusterCaps)
+	}
+	resourceBundleHandler := apphandlers.NewResourceBundleHandler(maestroClient, logger).
+		WithPageLimits(platformPages).
+		WithSummaryCache(cfg.ResourceBundles.SummaryCacheTTL)
+	if maestroVersion != nil {
+		resourceBundleHandler.WithSearchFields(maestroVersion)
+	}
+	// Accounts add their own required tags once authz is set up
+	requiredTags := &apphandlers.RequiredTags{
+		Cluster: cfg.RequiredTags.Cluster,
+		Work:    cfg.RequiredTags.Work,
+	}
+	clusterHandler := apphandlers.NewClusterHandler(hyperfleetClient, maestroClient, logger).
+		WithPageLimits(tenantPages).
+		WithRequiredTags(requiredTags)
+	nodePoolHandler := apphandlers.NewNodePoolHandler(maestroClient, logger).
+		WithPageLimits(tenantPages)
+	// Connections and caches primed before readiness passes
+	warmupSteps := []warmupStep{
+		{name: "management-clusters", run: mgmtClusterHandler.Warm},
+		doctorWarmup(doctor.GRPC(cfg.Maestro.GRPCBaseURL)),
+	}
 
 	// Create legacy authorization middleware (for non-authz routes)
 	authMiddleware := middleware.NewAuthorization(cfg.AllowedAccounts, logger)
 
-	// Create API router
+	// Request outcomes and work delivery lag feed the regional status endpoint
+	requestWindow := status.NewWindow(cfg.Status.Window)
+	deliveryLagWindow := status.NewWindow(cfg.Status.Window)
+	// Request outcomes per route class feed the admin SLO report
+	sloTracker := slo.NewTracker(slo.Config{
+		Period:       cfg.SLO.Period,
+		Availability: cfg.SLO.Availability,
+		Latency:      cfg.SLO.Latency,
+	})
+	statusProbes := []status.Probe{status.MaestroProbe(maestroClient)}
+
+	// Create API router; its middleware is recorded for the admin routes
+	// endpoint
 	apiRouter := mux.NewRouter()
-	apiRouter.Use(middleware.Identity)
+	routeTable := newRouteTable(apiRouter)
+
+	// Caller identity from gateway headers or the API Gateway v2 request
+	// context, and the client IP behind trusted proxies
+	identityCfg := middleware.DefaultIdentityConfig()
+	identityCfg.RequestContext = cfg.Identity.RequestContext
+	identityCfg.Headers.AccountID = cfg.Identity.AccountIDHeader
+	identityCfg.Headers.CallerARN = cfg.Identity.CallerARNHeader
+	identityCfg.AuthorizerAccountIDKey = cfg.Identity.AuthorizerAccountIDKey
+	identityCfg.AuthorizerCallerARNKey = cfg.Identity.AuthorizerCallerARNKey
+	identityCfg.SharedSecretFunc = sharedSecret.Get
+	identityCfg.SharedSecretHeader = cfg.Identity.SharedSecretHeader
+	trustedProxies, err := middleware.ParseCIDRs(cfg.Identity.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	identityCfg.TrustedProxies = trustedProxies
+	superAdmins, err := middleware.ParseSuperAdmins(cfg.Identity.SuperAdmins)
+	if err != nil {
+		return nil, fmt.Errorf("invalid super admins: %w", err)
+	}
+	identityCfg.SuperAdmins = superAdmins
+	// Error reasons follow Accept-Language; codes are never translated
+	catalog, err := i18n.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load error reason catalogs: %w", err)
+	}
+	routeTable.use(apiRouter, middleware.NewLocalize(catalog).Translate)
+	if cfg.Server.Dev {
+		// Identity from basic auth or query parameters, for local development
+		routeTable.use(apiRouter, middleware.NewDevIdentity(identityCfg).Inject)
+		logger.Warn("dev mode enabled: caller identity is taken from basic auth or query parameters without verification")
+	}
+	routeTable.use(apiRouter, middleware.NewIdentity(identityCfg, logger).Extract)
+	// Machine clients authenticate with API keys, looked up once authz is
+	// set up; until then keys are rejected
+	apiKeys := middleware.NewAPIKeys(logger)
+	routeTable.use(apiRouter, apiKeys.Authenticate)
+	routeTable.use(apiRouter, middleware.TraceContext)
+	routeTable.use(apiRouter, middleware.NewClientIP(trustedProxies).Resolve)
+	if cfg.Identity.ReplayWindow > 0 {
+		routeTable.use(apiRouter, middleware.NewReplayProtection(cfg.Identity.ReplayWindow, cfg.Identity.ReplayCacheSize, logger).Check)
+		logger.Info("request replay protection enabled", "window", cfg.Identity.ReplayWindow, "cache_size", cfg.Identity.ReplayCacheSize)
+	}
+	if cfg.Capture.File != "" {
+		// Envelopes of the requests as clients sent them, for the replay command
+		captureWriter, err := capture.NewWriter(cfg.Capture.File)
+		if err != nil {
+			return nil, err
+		}
+		routeTable.use(apiRouter, middleware.NewCapture(captureWriter, cfg.Capture.Accounts, cfg.Capture.BodyAccounts, logger).Record)
+		logger.Info("request capture enabled", "file", cfg.Capture.File, "accounts", cfg.Capture.Accounts, "body_accounts", cfg.Capture.BodyAccounts)
+	}
+	// Low-priority routes are shed while authz, the work queue or a watched
+	// component is degraded
+	var loadShedder *middleware.LoadShedder
+	if cfg.LoadShed.Rules != "" {
+		rules, err := loadshed.ParseRules(cfg.LoadShed.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("invalid load shedding rules: %w", err)
+		}
+		loadShedder = middleware.NewLoadShedder(rules, logger)
+		for _, name := range append([]string{componentAuthz}, cfg.LoadShed.Components...) {
+			loadShedder.Watch(name, func() bool {
+				state, ok := healthHandler.Component(name)
+				return ok && !state.Healthy
+			})
+		}
+		routeTable.use(apiRouter, loadShedder.Shed)
+	}
+	// Work creation also takes manifests uploaded as YAML or multipart files
+	routeTable.use(apiRouter, middleware.NewContentNegotiation("/api/v0/work").Negotiate)
+	routeTable.use(apiRouter, middleware.DryRun)
+	if cfg.Mirror.Target != "" {
+		// A version under test sees a sample of the requests answered here
+		requestMirror, err := mirror.New(mirror.Config{
+			Target:      cfg.Mirror.Target,
+			Timeout:     cfg.Mirror.Timeout,
+			MaxInFlight: cfg.Mirror.MaxInFlight,
+		}, logger)
+		if err != nil {
+			return nil, err
+		}
+		routeTable.use(apiRouter, middleware.NewMirror(requestMirror, cfg.Mirror.Percent, cfg.Mirror.DryRunWrites).Track)
+		logger.Info("request mirroring enabled", "target", cfg.Mirror.Target, "percent", cfg.Mirror.Percent, "dry_run_writes", cfg.Mirror.DryRunWrites)
+	}
+	routeTable.use(apiRouter, middleware.NewRequestStats(requestWindow).Track)
+	routeTable.use(apiRouter, middleware.NewSlowRequests(slowRequestClasses(cfg.SlowRequests), cfg.SlowRequests.Default, logger).Track)
+	routeTable.use(apiRouter, middleware.NewSLO(sloTracker, slowRequestClasses(cfg.SlowRequests), cfg.SlowRequests.Default).Track)
+	routeTable.use(apiRouter, middleware.NewAccountMetrics(cfg.AccountMetrics.TopAccounts, cfg.AccountMetrics.RankInterval).Track)
+	var meteringEmitter *metering.Emitter
+	if cfg.Metering.Sink != "" {
+		sink, err := newMeteringSink(ctx, cfg.Metering)
+		if err != nil {
+			return nil, err
+		}
+		meteringEmitter = metering.NewEmitter(metering.Config{
+			BatchSize:     cfg.Metering.BatchSize,
+			FlushInterval: cfg.Metering.FlushInterval,
+			BufferSize:    cfg.Metering.BufferSize,
+		}, sink, logger)
+		routeTable.use(apiRouter, middleware.NewMetering(meteringEmitter).Meter)
+		logger.Info("metering enabled", "sink", cfg.Metering.Sink, "target", cfg.Metering.Target)
+	}
+	var eventPublisher *events.KafkaPublisher
+	if len(cfg.Events.KafkaBrokers) > 0 {
+		publisher, err := newEventPublisher(ctx, cfg.Events, logger)
+		if err != nil {
+			return nil, err
+		}
+		eventPublisher = publisher
+		resourceBundleHandler.WithEvents(eventPublisher)
+		logger.Info("platform events enabled", "brokers", cfg.Events.KafkaBrokers, "encoding", cfg.Events.Encoding, "msk_iam", cfg.Events.MSKIAM)
+	}
+	// Each account's plan scales its rate limit, quotas and features; plans
+	// are looked up once authz is set up, until then accounts are on the
+	// default plan
+	planTiers := plans.NewTiers(cfg.RateLimit.Limit, cfg.Work.MaxManifests)
+	planMiddleware := middleware.NewPlan(planTiers, cfg.Plans.Default, logger)
+	routeTable.use(apiRouter, planMiddleware.Resolve)
+	var rateLimit *middleware.RateLimit
+	if cfg.RateLimit.Limit > 0 {
+		limiter, planLimiters, err := newRateLimiter(ctx, cfg.RateLimit, planTiers, logger)
+		if err != nil {
+			return nil, err
+		}
+		rateLimit = middleware.NewRateLimit(limiter, logger).WithPlans(planLimiters)
+		routeTable.use(apiRouter, rateLimit.Limit)
+	}
+	routeTable.use(apiRouter, middleware.NewTimeoutBudget(cfg.Server.RequestTimeout, cfg.Server.AuthzBudget).Apply)
+	// Innermost, so the request stats and slow-request log see the 499
+	routeTable.use(apiRouter, middleware.NewClientDisconnect(logger).Track)
 
 	// Initialize authz components if enabled
 	var privilegedMiddleware *middleware.Privileged
 	var accountCheckMiddleware *middleware.AccountCheck
 	var authzMiddleware *middleware.Authz
+	var shadowAuthz *middleware.ShadowAuthz
+	var authzChecker authz.Checker
+	var backupWorker *policybackup.Worker
+	var authzGate *middleware.AuthzGate
+	var delegationMiddleware *middleware.Delegation
+	var recovery *authzRecovery
+	var authzStreams *stream.Consumer
+	var decisionAnalytics *authz.DecisionAnalytics
+	var patternMatcher *authz.PatternMatcher
+	var decisionAudit *authz.DecisionAudit
+	var apiKeyCache *authz.APIKeyCache
+	var provisioner *authz.AccountProvisioner
+	var planResolver *plans.Resolver
+	var residencyChecker *residency.Checker
+	var accountPurger *purge.Purger
+	// Purge targets deleted after the work records, ending with the account
+	var accountPurgeTargets []purge.Target
+	// Adds what to stop when an account is disabled, once the work handler
+	// exists
+	var onAccountDisabled func(hooks ...authz.DisableHook)
+	// Reloads of the configuration file, once the caller sets a reloader
+	configHandler := apphandlers.NewConfigHandler(logger)
+
+	// Long-running operations, such as work submissions, cluster
+	// deregistrations and account purges, followed and cancelled through the
+	// operations routes
+	operationsRegistry := operations.NewRegistry().WithResultTTL(cfg.Operations.ResultTTL)
+	operationsHandler := apphandlers.NewOperationsHandler(operationsRegistry, logger)
+	mgmtClusterHandler.WithDeregistration(cfg.MgmtClusters.DrainBundles, cfg.MgmtClusters.DrainTimeout, operationsRegistry)
 
 	if cfg.Authz != nil && cfg.Authz.Enabled {
 		// Create DynamoDB client
@@ -86,132 +423,530 @@ func New(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 
 		// Create authorizer (implements both Checker and Service)
 		authorizer := authz.New(cfg.Authz, dynamoClient, avpClient, logger)
+		authzChecker = authz.NewBudgetedChecker(authorizer)
+		requiredTags.Accounts = authorizer
+		planResolver = plans.NewResolver(authorizer, cfg.Plans.Default, cfg.Plans.CacheTTL)
+		planMiddleware.WithResolver(planResolver)
+		if cfg.Server.Region != "" {
+			residencyChecker = residency.NewChecker(authorizer, cfg.Server.Region)
+		}
+		decisionAnalytics = authorizer.DecisionAnalytics()
+		patternMatcher = authorizer.PatternMatcher()
+		decisionAudit = authorizer.DecisionAudit()
+		apiKeyCache = authorizer.APIKeyCache()
+		provisioner = authorizer.Provisioner()
+		onAccountDisabled = authorizer.OnDisable
+		if decisionAudit != nil && cfg.Authz.AuditOpenSearchEndpoint != "" {
+			sink, err := newDecisionSink(ctx, cfg.Authz, logger)
+			if err != nil {
+				return nil, err
+			}
+			decisionAudit.WithSink(sink)
+			logger.Info("indexing audited decisions into OpenSearch", "endpoint", cfg.Authz.AuditOpenSearchEndpoint, "index", cfg.Authz.AuditOpenSearchIndex, "rollover", cfg.Authz.AuditOpenSearchRollover)
+		}
+
+		dynamoProbe := status.DynamoDBProbe("dynamodb", cfg.Authz.AccountsTableName, "accountId", dynamoClient)
+		statusProbes = append(statusProbes, dynamoProbe, status.AVPProbe(avpClient))
+
+		authzGate = middleware.NewAuthzGate(cfg.Authz.DegradedMode == authz.DegradedReadOnly, logger)
+		healthHandler.SetComponent(componentAuthz, true, nil)
+		if cfg.Authz.DegradedStart {
+			initCtx, cancel := context.WithTimeout(ctx, authzInitTimeout)
+			err := dynamoProbe.Check(initCtx)
+			cancel()
+			if err != nil {
+				logger.Warn("DynamoDB unreachable at startup, starting with authz degraded",
+					"error", err, "mode", cfg.Authz.DegradedMode)
+				authzGate.SetHealthy(false)
+				healthHandler.SetComponent(componentAuthz, true, err)
+				recovery = &authzRecovery{
+					check:    dynamoProbe.Check,
+					gate:     authzGate,
+					health:   healthHandler,
+					interval: cfg.Authz.InitRetryInterval,
+					logger:   logger,
+				}
+			}
+		}
+
+		// Declared accounts, admins and policies are added or updated on
+		// every start, replacing per-environment seeding scripts
+		if cfg.Bootstrap != nil {
+			if recovery != nil {
+				logger.Warn("skipping bootstrap manifest while authz is degraded")
+			} else {
+				result, err := bootstrap.Apply(ctx, authorizer, cfg.Bootstrap, logger)
+				if err != nil {
+					return nil, fmt.Errorf("failed to apply bootstrap manifest: %w", err)
+				}
+				logger.Info("bootstrap manifest applied",
+					"accounts_enabled", result.AccountsEnabled,
+					"admins_added", result.AdminsAdded,
+					"policies_created", result.PoliciesCreated,
+					"policies_updated", result.PoliciesUpdated)
+			}
+		}
+
+		if recovery == nil {
+			warmupSteps = append(warmupSteps,
+				warmupStep{name: "dynamodb", run: dynamoProbe.Check},
+				warmupStep{name: "avp", run: status.AVPProbe(avpClient).Check},
+				privilegedWarmup(privilegedAccounts(cfg.Bootstrap), authorizer.IsPrivileged, planResolver.AccountPlan))
+		}
 
 		// Create authz middleware
-		privilegedMiddleware = middleware.NewPrivileged(authorizer, logger)
-		accountCheckMiddleware = middleware.NewAccountCheck(authorizer, logger)
-		adminCheckMiddleware := middleware.NewAdminCheck(authorizer, logger)
-		authzMiddleware = middleware.NewAuthz(authorizer, cfg.Authz.Enabled, cfg.Authz.AWSRegion, logger)
+		privilegedMiddleware = middleware.NewPrivileged(authzChecker, logger)
+		accountCheckMiddleware = middleware.NewAccountCheck(authzChecker, logger)
+		adminCheckMiddleware := middleware.NewAdminCheck(authzChecker, logger)
+		bypassRules, err := authz.ParseBypassRules(cfg.Authz.Bypass)
+		if err != nil {
+			return nil, err
+		}
+		authzMiddleware = middleware.NewAuthz(authzChecker, cfg.Authz.Enabled, cfg.Authz.AWSRegion, logger).
+			WithGuardrails(authz.NewBudgetedGuardrails(authorizer)).
+			WithBypass(bypassRules, decisionAudit)
+		if cfg.Authz.ShadowMode {
+			shadowAuthz = middleware.NewShadowAuthz(authMiddleware, authzMiddleware, logger)
+		}
+		delegationMiddleware = middleware.NewDelegation(authorizer, logger)
+		apiKeys.WithResolver(authorizer)
 
 		// Create authz handlers
-		accountsHandler := apphandlers.NewAccountsHandler(authorizer, logger)
-		authzHandler := apphandlers.NewAuthzHandler(authorizer, authorizer, logger)
-
-		// Account management routes (privileged only)
-		accountsRouter := apiRouter.PathPrefix("/api/v0/accounts").Subrouter()
-		accountsRouter.Use(privilegedMiddleware.CheckPrivileged)
-		accountsRouter.Use(privilegedMiddleware.RequirePrivileged)
-		accountsRouter.HandleFunc("", accountsHandler.Create).Methods(http.MethodPost)
-		accountsRouter.HandleFunc("", accountsHandler.List).Methods(http.MethodGet)
-		accountsRouter.HandleFunc("/{id}", accountsHandler.Get).Methods(http.MethodGet)
-		accountsRouter.HandleFunc("/{id}", accountsHandler.Delete).Methods(http.MethodDelete)
-
-		// Authorization check route (requires provisioned account, open to all users)
-		checkRouter := apiRouter.PathPrefix("/api/v0/authz/check").Subrouter()
-		checkRouter.Use(privilegedMiddleware.CheckPrivileged)
-		checkRouter.Use(accountCheckMiddleware.RequireProvisioned)
-		checkRouter.HandleFunc("", authzHandler.CheckAuthorization).Methods(http.MethodPost)
-
-		// Authorization management routes (require provisioned account + admin)
-		authzRouter := apiRouter.PathPrefix("/api/v0/authz").Subrouter()
-		authzRouter.Use(privilegedMiddleware.CheckPrivileged)
-		authzRouter.Use(accountCheckMiddleware.RequireProvisioned)
-		authzRouter.Use(adminCheckMiddleware.RequireAdmin)
-
-		// Policy routes
-		authzRouter.HandleFunc("/policies", authzHandler.CreatePolicy).Methods(http.MethodPost)
-		authzRouter.HandleFunc("/policies", authzHandler.ListPolicies).Methods(http.MethodGet)
-		authzRouter.HandleFunc("/policies/{id}", authzHandler.GetPolicy).Methods(http.MethodGet)
-		authzRouter.HandleFunc("/policies/{id}", authzHandler.UpdatePolicy).Methods(http.MethodPut)
-		authzRouter.HandleFunc("/policies/{id}", authzHandler.DeletePolicy).Methods(http.MethodDelete)
-
-		// Group routes
-		authzRouter.HandleFunc("/groups", authzHandler.CreateGroup).Methods(http.MethodPost)
-		authzRouter.HandleFunc("/groups", authzHandler.ListGroups).Methods(http.MethodGet)
-		authzRouter.HandleFunc("/groups/{id}", authzHandler.GetGroup).Methods(http.MethodGet)
-		authzRouter.HandleFunc("/groups/{id}", authzHandler.DeleteGroup).Methods(http.MethodDelete)
-		authzRouter.HandleFunc("/groups/{id}/members", authzHandler.UpdateGroupMembers).Methods(http.MethodPut)
-		authzRouter.HandleFunc("/groups/{id}/members", authzHandler.ListGroupMembers).Methods(http.MethodGet)
-
-		// Attachment routes
-		authzRouter.HandleFunc("/attachments", authzHandler.CreateAttachment).Methods(http.MethodPost)
-		authzRouter.HandleFunc("/attachments", authzHandler.ListAttachments).Methods(http.MethodGet)
-		authzRouter.HandleFunc("/attachments/{id}", authzHandler.DeleteAttachment).Methods(http.MethodDelete)
-
-		// Admin routes
-		authzRouter.HandleFunc("/admins", authzHandler.AddAdmin).Methods(http.MethodPost)
-		authzRouter.HandleFunc("/admins", authzHandler.ListAdmins).Methods(http.MethodGet)
-		authzRouter.HandleFunc("/admins/{arn:.*}", authzHandler.RemoveAdmin).Methods(http.MethodDelete)
+		accountsHandler := apphandlers.NewAccountsHandler(authorizer, logger).
+			WithPageLimits(platformPages).
+			WithPlanCache(planResolver)
+		organizationsHandler := apphandlers.NewOrganizationsHandler(authorizer, logger)
+		guardrailsHandler := apphandlers.NewGuardrailsHandler(authorizer, logger)
+
+		// Scheduled policy store backups, restorable through the rebuild endpoint
+		if cfg.PolicyBackup.Bucket != "" && cfg.Server.ServesFrontend() {
+			backupAWSCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.PolicyBackup.AWSRegion))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load AWS config for policy backups: %w", err)
+			}
+			backupWorker = policybackup.NewWorker(authorizer, s3.NewFromConfig(backupAWSCfg), policybackup.Config{
+				Bucket:    cfg.PolicyBackup.Bucket,
+				Prefix:    cfg.PolicyBackup.Prefix,
+				Interval:  cfg.PolicyBackup.Interval,
+				Retention: cfg.PolicyBackup.Retention,
+			}, logger)
+			accountsHandler.WithPolicyBackups(backupWorker)
+			logger.Info("policy store backups enabled", "bucket", cfg.PolicyBackup.Bucket, "interval", cfg.PolicyBackup.Interval)
+		}
+
+		// Per-account notification settings and event delivery
+		var notifier *notify.Notifier
+		var notifySettings *notify.Store
+		if cfg.Notifications.Enabled && cfg.Server.ServesFrontend() {
+			notifyDynamoClient, err := client.NewDynamoDBClient(ctx, cfg.Notifications.AWSRegion, cfg.Notifications.DynamoDBEndpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create DynamoDB client for notifications: %w", err)
+			}
+			notifyAWSCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Notifications.AWSRegion))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load AWS config for notifications: %w", err)
+			}
+			notifySettings = notify.NewStore(cfg.Notifications.TableName, notifyDynamoClient, logger)
+			notifier = notify.NewNotifier(notifySettings, sesv2.NewFromConfig(notifyAWSCfg), sns.NewFromConfig(notifyAWSCfg),
+				cfg.Notifications.EmailSender, logger)
+			accountsHandler.WithNotifications(notifySettings, notifier)
+			logger.Info("notifications enabled", "table", cfg.Notifications.TableName, "email", cfg.Notifications.EmailSender != "")
+		}
+
+		// Tenant offboarding: the account's notification settings, backups
+		// and authz records are purged after its work records, which are
+		// added once the work handler exists
+		if cfg.AccountPurge.SigningKeyID != "" && cfg.Server.ServesFrontend() {
+			purgeAWSCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AccountPurge.AWSRegion))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load AWS config for account purges: %w", err)
+			}
+			accountPurger = purge.NewPurger(kms.NewFromConfig(purgeAWSCfg), cfg.AccountPurge.SigningKeyID, logger).
+				WithProgress(func(ctx context.Context, target string, done, total int) {
+					operationsRegistry.SetProgress(ctx, operations.Progress{Step: target, Done: done, Total: total})
+				})
+			if notifySettings != nil {
+				accountPurgeTargets = append(accountPurgeTargets, purge.Target{
+					Name: "notification-settings",
+					Purge: func(ctx context.Context, accountID string) (int, error) {
+						deleted, err := notifySettings.Delete(ctx, accountID)
+						if deleted {
+							return 1, err
+						}
+						return 0, err
+					},
+				})
+			}
+			if backupWorker != nil {
+				accountPurgeTargets = append(accountPurgeTargets, purge.Target{Name: "policy-backups", Purge: backupWorker.Purge})
+			}
+			accountPurgeTargets = append(accountPurgeTargets, authorizer.PurgeTargets()...)
+			accountsHandler.WithPurger(accountPurger, operationsRegistry)
+			logger.Info("account purges enabled", "signing_key", cfg.AccountPurge.SigningKeyID)
+		}
+
+		// Changes to the authz tables, including out-of-band edits, are
+		// audited, invalidate shared caches and notify subscribed accounts
+		if cfg.AuthzStreams.Enabled {
+			streamsClient, err := client.NewDynamoDBStreamsClient(ctx, cfg.Authz.AWSRegion, cfg.Authz.DynamoDBEndpoint)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create DynamoDB Streams client: %w", err)
+			}
+			tables := []string{
+				cfg.Authz.AccountsTableName,
+				cfg.Authz.AdminsTableName,
+				cfg.Authz.GroupsTableName,
+				cfg.Authz.MembersTableName,
+				cfg.Authz.DelegationsTableName,
+				cfg.Authz.AttachmentsTableName,
+			}
+			authzStreams = stream.NewConsumer(streamsClient, tables, cfg.AuthzStreams.PollInterval, logger).
+				WithHandler(stream.AuditLog(logger))
+			if sharedCache != nil {
+				authzStreams.WithHandler(stream.InvalidateCache(sharedCache, logger))
+			}
+			if notifier != nil {
+				authzStreams.WithHandler(stream.Notify(notifier, logger))
+			}
+			if eventPublisher != nil {
+				authzStreams.WithHandler(stream.Publish(eventPublisher, cfg.Authz.AccountsTableName))
+			}
+			logger.Info("authz table streams enabled", "tables", tables, "poll_interval", cfg.AuthzStreams.PollInterval)
+		}
+		authzHandler := apphandlers.NewAuthzHandler(authzChecker, authorizer, logger)
+
+		// Tenant-facing authz management routes
+		if cfg.Server.ServesFrontend() {
+			// Account management routes (privileged only)
+			accountsRouter := routeTable.subrouter(apiRouter, "/api/v0/accounts")
+			routeTable.use(accountsRouter, authzGate.Gate)
+			routeTable.use(accountsRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(accountsRouter, privilegedMiddleware.RequirePrivileged)
+			accountsRouter.HandleFunc("", accountsHandler.Create).Methods(http.MethodPost)
+			accountsRouter.HandleFunc("", accountsHandler.List).Methods(readMethods...)
+			accountsRouter.HandleFunc("/count", accountsHandler.Count).Methods(readMethods...)
+			accountsRouter.HandleFunc("/{id}", accountsHandler.Get).Methods(readMethods...)
+			accountsRouter.HandleFunc("/{id}", accountsHandler.Delete).Methods(http.MethodDelete)
+			accountsRouter.HandleFunc("/{id}/onboarding/retry", accountsHandler.RetryOnboarding).Methods(http.MethodPost)
+			accountsRouter.HandleFunc("/{id}/delegations", accountsHandler.CreateDelegation).Methods(http.MethodPost)
+			accountsRouter.HandleFunc("/{id}/delegations", accountsHandler.ListDelegations).Methods(readMethods...)
+			accountsRouter.HandleFunc("/{id}/delegations/{delegateAccountId}", accountsHandler.DeleteDelegation).Methods(http.MethodDelete)
+			accountsRouter.HandleFunc("/{id}/organization", organizationsHandler.SetAccountOrganization).Methods(http.MethodPut)
+			accountsRouter.HandleFunc("/{id}/required_tags", accountsHandler.SetRequiredTags).Methods(http.MethodPut)
+			accountsRouter.HandleFunc("/{id}/change_review", accountsHandler.SetChangeReview).Methods(http.MethodPut)
+			accountsRouter.HandleFunc("/{id}/plan", accountsHandler.SetPlan).Methods(http.MethodPut)
+			accountsRouter.HandleFunc("/{id}/residency", accountsHandler.SetResidency).Methods(http.MethodPut)
+			accountsRouter.HandleFunc("/{id}/pending_changes", accountsHandler.ListPendingChanges).Methods(readMethods...)
+			accountsRouter.HandleFunc("/{id}/pending_changes/{changeId}/approve", accountsHandler.ApprovePendingChange).Methods(http.MethodPost)
+			accountsRouter.HandleFunc("/{id}/pending_changes/{changeId}/reject", accountsHandler.RejectPendingChange).Methods(http.MethodPost)
+			accountsRouter.HandleFunc("/{id}/organization", organizationsHandler.RemoveAccountOrganization).Methods(http.MethodDelete)
+			accountsRouter.HandleFunc("/{id}/notifications", accountsHandler.GetNotifications).Methods(readMethods...)
+			accountsRouter.HandleFunc("/{id}/notifications", accountsHandler.PutNotifications).Methods(http.MethodPut)
+			accountsRouter.HandleFunc("/{id}/notifications", accountsHandler.DeleteNotifications).Methods(http.MethodDelete)
+			accountsRouter.HandleFunc("/{id}/notifications/test", accountsHandler.TestNotifications).Methods(http.MethodPost)
+			accountsRouter.HandleFunc("/{id}/notifications/webhook/rotate_secret", accountsHandler.RotateWebhookSecret).Methods(http.MethodPost)
+
+			// First privileged account, minted by a configured super admin
+			if len(superAdmins) > 0 {
+				bootstrapRouter := routeTable.subrouter(apiRouter, "/api/v0/bootstrap")
+				routeTable.use(bootstrapRouter, authzGate.Gate)
+				routeTable.use(bootstrapRouter, privilegedMiddleware.RequireSuperAdmin)
+				bootstrapRouter.HandleFunc("/privileged_account", accountsHandler.Bootstrap).Methods(http.MethodPost)
+				logger.Info("super-admin bootstrap enabled", "super_admins", superAdmins)
+			}
+
+			// Organization management routes (privileged only)
+			orgsRouter := routeTable.subrouter(apiRouter, "/api/v0/organizations")
+			routeTable.use(orgsRouter, authzGate.Gate)
+			routeTable.use(orgsRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(orgsRouter, privilegedMiddleware.RequirePrivileged)
+			orgsRouter.HandleFunc("", organizationsHandler.Create).Methods(http.MethodPost)
+			orgsRouter.HandleFunc("", organizationsHandler.List).Methods(readMethods...)
+			orgsRouter.HandleFunc("/{id}", organizationsHandler.Get).Methods(readMethods...)
+			orgsRouter.HandleFunc("/{id}", organizationsHandler.Delete).Methods(http.MethodDelete)
+			orgsRouter.HandleFunc("/{id}/policies", organizationsHandler.CreatePolicy).Methods(http.MethodPost)
+			orgsRouter.HandleFunc("/{id}/policies", organizationsHandler.ListPolicies).Methods(readMethods...)
+			orgsRouter.HandleFunc("/{id}/policies/{policyId}", organizationsHandler.DeletePolicy).Methods(http.MethodDelete)
+
+			// Admin recovery routes (privileged only)
+			adminRouter := routeTable.subrouter(apiRouter, "/api/v0/admin")
+			routeTable.use(adminRouter, authzGate.Gate)
+			routeTable.use(adminRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(adminRouter, privilegedMiddleware.RequirePrivileged)
+			adminRouter.HandleFunc("/accounts", accountsHandler.SearchByPrincipal).Methods(readMethods...)
+			adminRouter.HandleFunc("/accounts/{id}/rebuild_policy_store", accountsHandler.RebuildPolicyStore).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/accounts/{id}/migrate_schema", accountsHandler.MigrateSchema).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/accounts/{id}/policy_backups", accountsHandler.ListPolicyBackups).Methods(readMethods...)
+			adminRouter.HandleFunc("/accounts/{id}/deletions", accountsHandler.ListDeletions).Methods(readMethods...)
+			adminRouter.HandleFunc("/accounts/{id}/purge", accountsHandler.Purge).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/guardrails", guardrailsHandler.Create).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/guardrails", guardrailsHandler.List).Methods(readMethods...)
+			adminRouter.HandleFunc("/guardrails/{id}", guardrailsHandler.Delete).Methods(http.MethodDelete)
+			adminRouter.HandleFunc("/operations", operationsHandler.List).Methods(readMethods...)
+			adminRouter.HandleFunc("/operations/{id}", operationsHandler.Get).Methods(readMethods...)
+			adminRouter.HandleFunc("/operations/{id}", operationsHandler.Cancel).Methods(http.MethodDelete)
+			adminRouter.HandleFunc("/config/reload", configHandler.Reload).Methods(http.MethodPost)
+			adminRouter.HandleFunc("/routes", apphandlers.NewRoutesHandler(cfg.Server.Profile, routeTable.routes, logger).List).Methods(readMethods...)
+			adminRouter.HandleFunc("/slo", apphandlers.NewSLOHandler(sloTracker, logger).Report).Methods(readMethods...)
+			if maestroFailover != nil {
+				maestroEndpointsHandler := apphandlers.NewMaestroEndpointsHandler(maestroFailover, logger)
+				adminRouter.HandleFunc("/maestro/endpoints", maestroEndpointsHandler.List).Methods(readMethods...)
+				adminRouter.HandleFunc("/maestro/endpoints/{name}/activate", maestroEndpointsHandler.Activate).Methods(http.MethodPost)
+			}
+			if shadowAuthz != nil {
+				adminRouter.HandleFunc("/authz_shadow", apphandlers.NewShadowHandler(shadowAuthz, logger).Report).Methods(readMethods...)
+			}
+
+			// Authorization check route (requires provisioned account, open to all users)
+			checkRouter := routeTable.subrouter(apiRouter, "/api/v0/authz/check")
+			routeTable.use(checkRouter, authzGate.Gate)
+			routeTable.use(checkRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(checkRouter, accountCheckMiddleware.RequireProvisioned)
+			checkRouter.HandleFunc("", authzHandler.CheckAuthorization).Methods(http.MethodPost)
+
+			// Authorization management routes (require provisioned account + admin)
+			authzRouter := routeTable.subrouter(apiRouter, "/api/v0/authz")
+			routeTable.use(authzRouter, authzGate.Gate)
+			routeTable.use(authzRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(authzRouter, accountCheckMiddleware.RequireProvisioned)
+			routeTable.use(authzRouter, adminCheckMiddleware.RequireAdmin)
+			// Accounts in change review mode have their mutations staged
+			// rather than applied; approvals replay them through this router.
+			// Minted API keys are only ever shown to their minter, so key
+			// management is exempt.
+			changeReview := middleware.NewChangeReview(authorizer, logger, "/api/v0/authz/changes", "/api/v0/authz/pending_changes", "/api/v0/authz/policies/format", "/api/v0/authz/api_keys")
+			routeTable.use(authzRouter, changeReview.Stage)
+			changeRequestsHandler := apphandlers.NewChangeRequestsHandler(authorizer, authzRouter, logger)
+
+			// Policy routes
+			authzRouter.HandleFunc("/policies", authzHandler.CreatePolicy).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/policies", authzHandler.ListPolicies).Methods(readMethods...)
+			// Formatting changes nothing, so it is exempt from change review
+			authzRouter.HandleFunc("/policies/format", authzHandler.FormatPolicy).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/policies/{id}", authzHandler.GetPolicy).Methods(readMethods...)
+			authzRouter.HandleFunc("/policies/{id}", authzHandler.UpdatePolicy).Methods(http.MethodPut)
+			authzRouter.HandleFunc("/policies/{id}", authzHandler.DeletePolicy).Methods(http.MethodDelete)
+			authzRouter.HandleFunc("/policies/{id}/tags", authzHandler.SetPolicyTags).Methods(http.MethodPut)
+			// Read-only, so approvers can diff a staged update without it being staged itself
+			authzRouter.HandleFunc("/policies/{id}/diff", authzHandler.GetPolicyDiff).Methods(readMethods...)
+
+			// Group routes
+			authzRouter.HandleFunc("/groups", authzHandler.CreateGroup).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/groups", authzHandler.ListGroups).Methods(readMethods...)
+			authzRouter.HandleFunc("/groups/{id}", authzHandler.GetGroup).Methods(readMethods...)
+			authzRouter.HandleFunc("/groups/{id}", authzHandler.DeleteGroup).Methods(http.MethodDelete)
+			authzRouter.HandleFunc("/groups/{id}/tags", authzHandler.SetGroupTags).Methods(http.MethodPut)
+			authzRouter.HandleFunc("/groups/{id}/members", authzHandler.UpdateGroupMembers).Methods(http.MethodPut)
+			authzRouter.HandleFunc("/groups/{id}/members", authzHandler.ListGroupMembers).Methods(readMethods...)
+
+			// Attachment routes
+			authzRouter.HandleFunc("/attachments", authzHandler.CreateAttachment).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/attachments", authzHandler.ListAttachments).Methods(readMethods...)
+			authzRouter.HandleFunc("/attachments/{id}", authzHandler.GetAttachment).Methods(readMethods...)
+			authzRouter.HandleFunc("/attachments/{id}", authzHandler.UpdateAttachment).Methods(http.MethodPut)
+			authzRouter.HandleFunc("/attachments/{id}", authzHandler.DeleteAttachment).Methods(http.MethodDelete)
+
+			// Hourly allow and deny counts per action
+			authzRouter.HandleFunc("/analytics", authzHandler.GetDecisionAnalytics).Methods(readMethods...)
+			// Caller ARNs seen in decisions, to copy into groups
+			authzRouter.HandleFunc("/principals", authzHandler.ListObservedPrincipals).Methods(readMethods...)
+			// Unused policies and stale groups worth pruning
+			authzRouter.HandleFunc("/recommendations", authzHandler.GetRecommendations).Methods(readMethods...)
+
+			// Principal access report
+			authzRouter.HandleFunc("/principals/{arn:.+}/access", authzHandler.GetPrincipalAccess).Methods(readMethods...)
+
+			// Admin routes
+			authzRouter.HandleFunc("/admins", authzHandler.AddAdmin).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/admins", authzHandler.ListAdmins).Methods(readMethods...)
+			authzRouter.HandleFunc("/admins/{arn:.*}", authzHandler.RemoveAdmin).Methods(http.MethodDelete)
+
+			// API keys of machine clients
+			authzRouter.HandleFunc("/api_keys", authzHandler.CreateAPIKey).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/api_keys", authzHandler.ListAPIKeys).Methods(readMethods...)
+			authzRouter.HandleFunc("/api_keys/{id}", authzHandler.GetAPIKey).Methods(readMethods...)
+			authzRouter.HandleFunc("/api_keys/{id}", authzHandler.RevokeAPIKey).Methods(http.MethodDelete)
+			authzRouter.HandleFunc("/api_keys/{id}/rotate", authzHandler.RotateAPIKey).Methods(http.MethodPost)
+
+			// Changes waiting for a second admin's approval
+			authzRouter.HandleFunc("/pending_changes", authzHandler.ListPendingChanges).Methods(readMethods...)
+			authzRouter.HandleFunc("/pending_changes/{changeId}/approve", authzHandler.ApprovePendingChange).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/pending_changes/{changeId}/reject", authzHandler.RejectPendingChange).Methods(http.MethodPost)
+
+			// Change requests staged in change review mode
+			authzRouter.HandleFunc("/changes", changeRequestsHandler.List).Methods(readMethods...)
+			authzRouter.HandleFunc("/changes/{changeId}", changeRequestsHandler.Get).Methods(readMethods...)
+			authzRouter.HandleFunc("/changes/{changeId}/approve", changeRequestsHandler.Approve).Methods(http.MethodPost)
+			authzRouter.HandleFunc("/changes/{changeId}/reject", changeRequestsHandler.Reject).Methods(http.MethodPost)
+
+			// Decision audit log queries (require provisioned account + admin)
+			auditHandler := apphandlers.NewAuditHandler(authorizer, logger).WithPageLimits(tenantPages)
+			if cfg.Authz.AuditQueryRateLimit > 0 {
+				auditHandler.WithRateLimit(ratelimit.NewLocal(cfg.Authz.AuditQueryRateLimit, time.Minute))
+			}
+			auditRouter := routeTable.subrouter(apiRouter, "/api/v0/audit")
+			routeTable.use(auditRouter, authzGate.Gate)
+			routeTable.use(auditRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(auditRouter, accountCheckMiddleware.RequireProvisioned)
+			routeTable.use(auditRouter, adminCheckMiddleware.RequireAdmin)
+			auditRouter.HandleFunc("", auditHandler.Query).Methods(readMethods...)
+		}
 
 		logger.Info("Cedar/AVP authorization enabled")
 	}
 
-	// Management cluster routes (require allowed account)
-	mgmtRouter := apiRouter.PathPrefix("/api/v0/management_clusters").Subrouter()
-	if authzMiddleware != nil {
-		mgmtRouter.Use(privilegedMiddleware.CheckPrivileged)
-		mgmtRouter.Use(authzMiddleware.Authorize)
+	// The caller's plan, its limits and usage
+	quotaHandler := apphandlers.NewQuotaHandler(cfg.RateLimit.Window, logger)
+	if rateLimit != nil {
+		quotaHandler.WithRequestCounter(rateLimit)
+	}
+	quotaRouter := routeTable.subrouter(apiRouter, "/api/v0/quota")
+	// Cedar only requires a provisioned account here, so there is no
+	// decision to shadow
+	if authzMiddleware != nil && shadowAuthz == nil {
+		routeTable.use(quotaRouter, authzGate.Gate)
+		routeTable.use(quotaRouter, privilegedMiddleware.CheckPrivileged)
+		routeTable.use(quotaRouter, accountCheckMiddleware.RequireProvisioned)
 	} else {
-		mgmtRouter.Use(authMiddleware.RequireAllowedAccount)
+		routeTable.use(quotaRouter, authMiddleware.RequireAllowedAccount)
 	}
-	mgmtRouter.HandleFunc("", mgmtClusterHandler.Create).Methods(http.MethodPost)
-	mgmtRouter.HandleFunc("", mgmtClusterHandler.List).Methods(http.MethodGet)
-	mgmtRouter.HandleFunc("/{id}", mgmtClusterHandler.Get).Methods(http.MethodGet)
+	quotaRouter.HandleFunc("", quotaHandler.Get).Methods(readMethods...)
 
-	// Resource bundle routes (require allowed account)
-	rbRouter := apiRouter.PathPrefix("/api/v0/resource_bundles").Subrouter()
-	if authzMiddleware != nil {
-		rbRouter.Use(privilegedMiddleware.CheckPrivileged)
-		rbRouter.Use(authzMiddleware.Authorize)
+	// Callers follow and cancel their account's operations; privileged
+	// callers see every operation on this replica
+	operationsRouter := routeTable.subrouter(apiRouter, "/api/v0/operations")
+	if authzMiddleware != nil && shadowAuthz == nil {
+		routeTable.use(operationsRouter, authzGate.Gate)
+		routeTable.use(operationsRouter, privilegedMiddleware.CheckPrivileged)
+		routeTable.use(operationsRouter, accountCheckMiddleware.RequireProvisioned)
 	} else {
-		rbRouter.Use(authMiddleware.RequireAllowedAccount)
+		routeTable.use(operationsRouter, authMiddleware.RequireAllowedAccount)
 	}
-	rbRouter.HandleFunc("", resourceBundleHandler.List).Methods(http.MethodGet)
-	rbRouter.HandleFunc("/{id}", resourceBundleHandler.Delete).Methods(http.MethodDelete)
+	operationsRouter.HandleFunc("", operationsHandler.List).Methods(readMethods...)
+	operationsRouter.HandleFunc("/{id}", operationsHandler.Get).Methods(readMethods...)
+	operationsRouter.HandleFunc("/{id}", operationsHandler.Cancel).Methods(http.MethodDelete)
 
-	// Work routes (require allowed account)
-	workRouter := apiRouter.PathPrefix("/api/v0/work").Subrouter()
-	if authzMiddleware != nil {
-		workRouter.Use(privilegedMiddleware.CheckPrivileged)
-		workRouter.Use(authzMiddleware.Authorize)
-	} else {
-		workRouter.Use(authMiddleware.RequireAllowedAccount)
+	workHandler, workScheduler, workHistory, err := newWorkHandler(ctx, cfg, maestroClient, authzChecker, requiredTags, operationsRegistry, eventPublisher, clusterCaps, residencyChecker, logger)
+	if err != nil {
+		return nil, err
+	}
+	if accountPurger != nil {
+		accountPurger.WithTargets(workHandler.PurgeTargets()...).WithTargets(accountPurgeTargets...)
+	}
+	if onAccountDisabled != nil {
+		onAccountDisabled(workHandler.CancelSchedules)
+	}
+	if loadShedder != nil && cfg.LoadShed.QueueLoad > 0 && cfg.Work.MaxConcurrent > 0 {
+		loadShedder.Watch("work-queue", func() bool { return workHandler.QueueLoad() >= cfg.LoadShed.QueueLoad })
 	}
-	workRouter.HandleFunc("", workHandler.Create).Methods(http.MethodPost)
 
-	// Cluster routes (user-facing, require authz)
-	clusterRouter := apiRouter.PathPrefix("/api/v0/clusters").Subrouter()
-	if authzMiddleware != nil {
-		clusterRouter.Use(privilegedMiddleware.CheckPrivileged)
-		clusterRouter.Use(authzMiddleware.Authorize)
-	} else {
-		clusterRouter.Use(authMiddleware.RequireAllowedAccount)
-	}
-	clusterRouter.HandleFunc("", clusterHandler.List).Methods(http.MethodGet)
-	clusterRouter.HandleFunc("", clusterHandler.Create).Methods(http.MethodPost)
-	clusterRouter.HandleFunc("/{id}", clusterHandler.Get).Methods(http.MethodGet)
-	clusterRouter.HandleFunc("/{id}", clusterHandler.Update).Methods(http.MethodPatch, http.MethodPut)
-	clusterRouter.HandleFunc("/{id}", clusterHandler.Delete).Methods(http.MethodDelete)
-	clusterRouter.HandleFunc("/{id}/statuses", clusterHandler.GetStatus).Methods(http.MethodGet)
-
-	// NodePool routes (user-facing, require authz)
-	nodePoolRouter := apiRouter.PathPrefix("/api/v0/nodepools").Subrouter()
-	if authzMiddleware != nil {
-		nodePoolRouter.Use(privilegedMiddleware.CheckPrivileged)
-		nodePoolRouter.Use(authzMiddleware.Authorize)
-	} else {
-		nodePoolRouter.Use(authMiddleware.RequireAllowedAccount)
+	if cfg.Server.ServesPlatform() {
+		// Management cluster routes (require allowed account)
+		mgmtRouter := routeTable.subrouter(apiRouter, "/api/v0/management_clusters")
+		if shadowAuthz != nil {
+			routeTable.use(mgmtRouter, shadowAuthz.Evaluate)
+			routeTable.use(mgmtRouter, authMiddleware.RequireAllowedAccount)
+		} else if authzMiddleware != nil {
+			routeTable.use(mgmtRouter, authzGate.Gate)
+			routeTable.use(mgmtRouter, delegationMiddleware.Resolve)
+			routeTable.use(mgmtRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(mgmtRouter, authzMiddleware.Authorize)
+		} else {
+			routeTable.use(mgmtRouter, authMiddleware.RequireAllowedAccount)
+		}
+		mgmtRouter.HandleFunc("", mgmtClusterHandler.Create).Methods(http.MethodPost)
+		mgmtRouter.HandleFunc("", mgmtClusterHandler.List).Methods(readMethods...)
+		mgmtRouter.HandleFunc("/{id}", mgmtClusterHandler.Get).Methods(readMethods...)
+		mgmtRouter.HandleFunc("/{id}", mgmtClusterHandler.Deregister).Methods(http.MethodDelete)
+		mgmtRouter.HandleFunc("/{id}/capabilities", mgmtClusterHandler.GetCapabilities).Methods(readMethods...)
+		mgmtRouter.HandleFunc("/{id}/capabilities", mgmtClusterHandler.PutCapabilities).Methods(http.MethodPut)
+
+		// Resource bundle routes (require allowed account)
+		rbRouter := routeTable.subrouter(apiRouter, "/api/v0/resource_bundles")
+		if shadowAuthz != nil {
+			routeTable.use(rbRouter, shadowAuthz.Evaluate)
+			routeTable.use(rbRouter, authMiddleware.RequireAllowedAccount)
+		} else if authzMiddleware != nil {
+			routeTable.use(rbRouter, authzGate.Gate)
+			routeTable.use(rbRouter, delegationMiddleware.Resolve)
+			routeTable.use(rbRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(rbRouter, authzMiddleware.Authorize)
+		} else {
+			routeTable.use(rbRouter, authMiddleware.RequireAllowedAccount)
+		}
+		rbRouter.HandleFunc("", resourceBundleHandler.List).Methods(readMethods...)
+		rbRouter.HandleFunc("/summary", resourceBundleHandler.Summary).Methods(readMethods...)
+		rbRouter.HandleFunc("/{id}", resourceBundleHandler.Delete).Methods(http.MethodDelete)
+
+		// Work routes (require allowed account)
+		workRouter := routeTable.subrouter(apiRouter, "/api/v0/work")
+		var workChecks []mux.MiddlewareFunc
+		if shadowAuthz != nil {
+			workChecks = []mux.MiddlewareFunc{shadowAuthz.Evaluate, authMiddleware.RequireAllowedAccount}
+		} else if authzMiddleware != nil {
+			workChecks = []mux.MiddlewareFunc{authzGate.Gate, delegationMiddleware.Resolve, privilegedMiddleware.CheckPrivileged, authzMiddleware.Authorize}
+		} else {
+			workChecks = []mux.MiddlewareFunc{authMiddleware.RequireAllowedAccount}
+		}
+		for _, check := range workChecks {
+			routeTable.use(workRouter, check)
+		}
+		// Scheduled works are authorized again on every run
+		workHandler.WithScheduleChecks(workChecks...)
+		workRouter.HandleFunc("", workHandler.Create).Methods(http.MethodPost)
+		workRouter.HandleFunc("", workHandler.List).Methods(readMethods...)
+		workRouter.HandleFunc("/groups/{id}", workHandler.GetGroup).Methods(readMethods...)
+		workRouter.HandleFunc("/{id}/history", workHandler.History).Methods(readMethods...)
+		workRouter.HandleFunc("/schedules", workHandler.ListSchedules).Methods(readMethods...)
+		workRouter.HandleFunc("/schedules/{id}", workHandler.GetSchedule).Methods(readMethods...)
+		workRouter.HandleFunc("/schedules/{id}", workHandler.CancelSchedule).Methods(http.MethodDelete)
+	}
+
+	if cfg.Server.ServesFrontend() {
+		// Cluster routes (user-facing, require authz)
+		clusterRouter := routeTable.subrouter(apiRouter, "/api/v0/clusters")
+		if shadowAuthz != nil {
+			routeTable.use(clusterRouter, shadowAuthz.Evaluate)
+			routeTable.use(clusterRouter, authMiddleware.RequireAllowedAccount)
+		} else if authzMiddleware != nil {
+			routeTable.use(clusterRouter, authzGate.Gate)
+			routeTable.use(clusterRouter, delegationMiddleware.Resolve)
+			routeTable.use(clusterRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(clusterRouter, authzMiddleware.Authorize)
+		} else {
+			routeTable.use(clusterRouter, authMiddleware.RequireAllowedAccount)
+		}
+		clusterRouter.HandleFunc("", clusterHandler.List).Methods(readMethods...)
+		clusterRouter.HandleFunc("", clusterHandler.Create).Methods(http.MethodPost)
+		clusterRouter.HandleFunc("/{id}", clusterHandler.Get).Methods(readMethods...)
+		clusterRouter.HandleFunc("/{id}", clusterHandler.Update).Methods(http.MethodPatch, http.MethodPut)
+		clusterRouter.HandleFunc("/{id}", clusterHandler.Delete).Methods(http.MethodDelete)
+		clusterRouter.HandleFunc("/{id}/statuses", clusterHandler.GetStatus).Methods(readMethods...)
+
+		// NodePool routes (user-facing, require authz)
+		nodePoolRouter := routeTable.subrouter(apiRouter, "/api/v0/nodepools")
+		if shadowAuthz != nil {
+			routeTable.use(nodePoolRouter, shadowAuthz.Evaluate)
+			routeTable.use(nodePoolRouter, authMiddleware.RequireAllowedAccount)
+		} else if authzMiddleware != nil {
+			routeTable.use(nodePoolRouter, authzGate.Gate)
+			routeTable.use(nodePoolRouter, delegationMiddleware.Resolve)
+			routeTable.use(nodePoolRouter, privilegedMiddleware.CheckPrivileged)
+			routeTable.use(nodePoolRouter, authzMiddleware.Authorize)
+		} else {
+			routeTable.use(nodePoolRouter, authMiddleware.RequireAllowedAccount)
+		}
+		nodePoolRouter.HandleFunc("", nodePoolHandler.List).Methods(readMethods...)
+		nodePoolRouter.HandleFunc("", nodePoolHandler.Create).Methods(http.MethodPost)
+		nodePoolRouter.HandleFunc("/{id}", nodePoolHandler.Get).Methods(readMethods...)
+		nodePoolRouter.HandleFunc("/{id}", nodePoolHandler.Update).Methods(http.MethodPut)
+		nodePoolRouter.HandleFunc("/{id}", nodePoolHandler.Delete).Methods(http.MethodDelete)
+		nodePoolRouter.HandleFunc("/{id}/status", nodePoolHandler.GetStatus).Methods(readMethods...)
 	}
-	nodePoolRouter.HandleFunc("", nodePoolHandler.List).Methods(http.MethodGet)
-	nodePoolRouter.HandleFunc("", nodePoolHandler.Create).Methods(http.MethodPost)
-	nodePoolRouter.HandleFunc("/{id}", nodePoolHandler.Get).Methods(http.MethodGet)
-	nodePoolRouter.HandleFunc("/{id}", nodePoolHandler.Update).Methods(http.MethodPut)
-	nodePoolRouter.HandleFunc("/{id}", nodePoolHandler.Delete).Methods(http.MethodDelete)
-	nodePoolRouter.HandleFunc("/{id}/status", nodePoolHandler.GetStatus).Methods(http.MethodGet)
 
 	// ZOA Trusted Actions routes (privileged)
 	var zoaReconciler *zoa.Reconciler
-	if cfg.Zoa.Enabled {
+	if cfg.Zoa.Enabled && cfg.Server.ServesPlatform() {
 		jobConfig, err := zoa.LoadJobConfig(cfg.Zoa.JobConfigDir)
 		if err != nil {
 			return nil, fmt.Errorf("failed to load ZOA job config from %s: %w", cfg.Zoa.JobConfigDir, err)
@@ -242,16 +977,19 @@ func New(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 		s3Client := s3.NewFromConfig(awsCfg)
 
 		zoaHandler := apphandlers.NewZoaHandler(zoaStore, zoaRegistry, maestroClient, s3Client, apphandlers.ZoaConfig{
-			BucketName: cfg.Zoa.BucketName,
-			JobConfig:  jobConfig,
-			AuditStore: auditStore,
+			BucketName:      cfg.Zoa.BucketName,
+			JobConfig:       jobConfig,
+			AuditStore:      auditStore,
+			RunPageLimits:   pageLimits(cfg.Pagination.TrustedActions),
+			AuditPageLimits: pageLimits(cfg.Pagination.Audit),
 		}, logger)
 
-		zoaRouter := apiRouter.PathPrefix("/api/v0/trusted-actions").Subrouter()
+		zoaRouter := routeTable.subrouter(apiRouter, "/api/v0/trusted-actions")
 		if privilegedMiddleware != nil {
-			zoaRouter.Use(privilegedMiddleware.CheckPrivileged)
+			routeTable.use(zoaRouter, authzGate.Gate)
+			routeTable.use(zoaRouter, privilegedMiddleware.CheckPrivileged)
 		} else {
-			zoaRouter.Use(authMiddleware.RequireAllowedAccount)
+			routeTable.use(zoaRouter, authMiddleware.RequireAllowedAccount)
 		}
 		zoaRouter.HandleFunc("/audit", zoaHandler.AuditList).Methods(http.MethodGet)
 		zoaRouter.HandleFunc("/runs", zoaHandler.List).Methods(http.MethodGet)
@@ -260,15 +998,66 @@ func New(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 		zoaRouter.HandleFunc("/{action}", zoaHandler.Describe).Methods(http.MethodGet)
 		zoaRouter.HandleFunc("", zoaHandler.Catalog).Methods(http.MethodGet)
 
-		zoaReconciler = zoa.NewReconciler(zoaStore, zoaRegistry, maestroClient, jobConfig, cfg.Zoa.PollInterval, logger)
+		zoaReconciler = zoa.NewReconciler(zoaStore, zoaRegistry, maestroClient, jobConfig, cfg.Zoa.PollInterval, logger).
+			WithDeliveryLag(deliveryLagWindow).
+			WithHealth(func(err error) {
+				healthHandler.SetComponent(componentZoaReconciler, false, err)
+			})
 		logger.Info("ZOA trusted actions enabled", "table", cfg.Zoa.TableName, "bucket", cfg.Zoa.BucketName)
 	}
 
+	// Every replica probes delivery through its own Maestro connection
+	var deliveryCanary *canary.Canary
+	if cfg.Canary.ClusterName != "" {
+		deliveryCanary = canary.New(canary.Config{
+			ClusterName: cfg.Canary.ClusterName,
+			Namespace:   cfg.Canary.Namespace,
+			Interval:    cfg.Canary.Interval,
+			Timeout:     cfg.Canary.Timeout,
+		}, maestroClient, logger).
+			WithHealth(func(err error) {
+				healthHandler.SetComponent(componentDeliveryCanary, false, err)
+			})
+	}
+
 	// Health and info routes on API server (no auth required)
 	apiRouter.HandleFunc("/api/v0/live", healthHandler.Liveness).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/api/v0/ready", healthHandler.Readiness).Methods(http.MethodGet)
+	apiRouter.HandleFunc("/api/v0/startup", healthHandler.Startup).Methods(http.MethodGet)
 	apiRouter.HandleFunc("/api/v0/info", infoHandler.Info).Methods(http.MethodGet)
 
+	// Regional status for the global control plane (no auth required).
+	// Delivery lag is only sampled from trusted action works, so it is
+	// omitted when ZOA is disabled.
+	var lagWindow *status.Window
+	if zoaReconciler != nil {
+		lagWindow = deliveryLagWindow
+	}
+	statusReporter := status.NewReporter(status.Config{
+		ProbeTimeout:         cfg.Status.ProbeTimeout,
+		CacheTTL:             cfg.Status.CacheTTL,
+		ErrorRateThreshold:   cfg.Status.ErrorRateThreshold,
+		DeliveryLagThreshold: cfg.Status.DeliveryLagThreshold,
+	}, statusProbes, requestWindow, lagWindow, logger)
+	if maestroVersion != nil {
+		statusReporter.WithMaestroVersion(maestroVersion)
+	}
+	apiRouter.HandleFunc("/api/v0/status", apphandlers.NewStatusHandler(statusReporter).Status).Methods(http.MethodGet)
+
+	// Background workers run on one replica at a time when leader election
+	// is enabled; the leases show which replica runs each of them
+	var elector *leader.Elector
+	if cfg.LeaderElection.Enabled {
+		leaseDynamoClient, err := client.NewDynamoDBClient(ctx, cfg.LeaderElection.AWSRegion, cfg.LeaderElection.DynamoDBEndpoint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create leader election DynamoDB client: %w", err)
+		}
+		leaseStore := leader.NewDynamoStore(cfg.LeaderElection.TableName, leaseDynamoClient, logger)
+		elector = leader.NewElector(leaseStore, cfg.LeaderElection.Identity, cfg.LeaderElection.LeaseDuration, logger)
+		logger.Info("leader election enabled", "table", cfg.LeaderElection.TableName, "identity", cfg.LeaderElection.Identity)
+	}
+	apiRouter.HandleFunc("/api/v0/status/leaders", apphandlers.NewLeadersHandler(elector, logger).List).Methods(http.MethodGet)
+
 	// ROSAENG-1236: CORS disabled for machine-to-machine API
 	// Previous wildcard CORS was a security vulnerability
 	// apiHandler := handlers.CORS(
@@ -276,21 +1065,64 @@ func New(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 	// 	handlers.AllowedMethods([]string{http.MethodGet, http.MethodPost, http.MethodPatch, http.MethodDelete, http.MethodPut}),
 	// 	handlers.AllowedHeaders([]string{"Content-Type", "Authorization"}),
 	// )(apiRouter)
-	apiHandler := apiRouter
+	// The base path is stripped before the router matches routes. The request
+	// ID is assigned first, so every response carries it, including panics,
+	// unmatched routes and requests rejected by the identity middleware.
+	apiHandler := routeTable.wrap(
+		middleware.NewRequestIDs(identityCfg).Assign,
+		middleware.NewRecovery(logger).Recover,
+		middleware.NewExternalURL(cfg.Server.ExternalURL).Apply,
+		middleware.NewBasePath(cfg.Server.BasePath).Strip)
+	if cfg.Server.BasePath != "" {
+		logger.Info("serving the API under a base path", "base_path", cfg.Server.BasePath)
+	}
 
 	// Create health router
 	healthRouter := mux.NewRouter()
 	healthRouter.HandleFunc("/healthz", healthHandler.Liveness).Methods(http.MethodGet)
 	healthRouter.HandleFunc("/readyz", healthHandler.Readiness).Methods(http.MethodGet)
+	healthRouter.HandleFunc("/startupz", healthHandler.Startup).Methods(http.MethodGet)
 
 	// Create metrics router
 	metricsRouter := mux.NewRouter()
 	metricsRouter.Handle("/metrics", promhttp.Handler()).Methods(http.MethodGet)
 
+	var warm *warmup
+	if cfg.Server.WarmupTimeout > 0 {
+		healthHandler.SetReady(false)
+		warm = &warmup{steps: warmupSteps, timeout: cfg.Server.WarmupTimeout, health: healthHandler, logger: logger}
+	}
+
 	return &Server{
 		cfg:           cfg,
 		logger:        logger,
 		zoaReconciler: zoaReconciler,
+		backupWorker:  backupWorker,
+		workScheduler: workScheduler,
+		workHistory:   workHistory,
+		canary:        deliveryCanary,
+		maestroVer:    maestroVersion,
+		maestroFail:   maestroFailover,
+		metering:      meteringEmitter,
+		events:        eventPublisher,
+		secrets:       secrets,
+		elector:       elector,
+		redisCache:    redisCache,
+		authzStreams:  authzStreams,
+		apiRouter:     apiRouter,
+		reload: &reloadTargets{
+			accounts:        authMiddleware,
+			rateLimit:       rateLimit,
+			plans:           planMiddleware,
+			planResolver:    planResolver,
+			quota:           quotaHandler,
+			mgmtClusters:    mgmtClusterHandler,
+			resourceBundles: resourceBundleHandler,
+			config:          configHandler,
+			maestro:         maestroFailover,
+			maxManifests:    cfg.Work.MaxManifests,
+			logger:          logger,
+		},
 		apiServer: &http.Server{
 			Addr:         fmt.Sprintf("%s:%d", cfg.Server.APIBindAddress, cfg.Server.APIPort),
 			Handler:      apiHandler,
@@ -310,75 +1142,455 @@ func New(cfg *config.Config, logger *slog.Logger) (*Server, error) {
 			WriteTimeout: 10 * time.Second,
 		},
 		healthHandler: healthHandler,
+		authzRecovery: recovery,
+		warmup:        warm,
+
+		decisionAnalytics: decisionAnalytics,
+		patternMatcher:    patternMatcher,
+		decisionAudit:     decisionAudit,
+		apiKeyCache:       apiKeyCache,
+		provisioner:       provisioner,
 	}, nil
 }
 
-// Run starts all servers and blocks until context is cancelled
+// drainDelay is how long readiness fails before the servers stop, so load
+// balancers stop sending traffic first
+const drainDelay = 5 * time.Second
+
+// Run starts all servers and background workers and blocks until ctx is
+// cancelled or one of them fails. The health and metrics servers start first
+// and stop last; the API server starts last and stops first.
 func (s *Server) Run(ctx context.Context) error {
-	errCh := make(chan error, 3)
+	m := lifecycle.New(s.healthHandler, s.cfg.Server.ShutdownTimeout, s.logger).
+		Add(httpComponent(componentHealthServer, false, s.healthServer)).
+		Add(httpComponent(componentMetricsServer, false, s.metricsServer))
 
-	// Start ZOA reconciler if enabled
+	if s.secrets != nil && s.secrets.Refreshes() {
+		m.Add(workerComponent(componentSecretRefresh, s.secrets.Run))
+	}
+	// Every replica receives the shared cache's invalidations
+	if s.redisCache != nil {
+		m.Add(workerComponent(componentCacheInvalidations, s.redisCache.Run))
+	}
 	if s.zoaReconciler != nil {
-		go s.zoaReconciler.Run(ctx)
+		m.Add(s.leaderComponent(componentZoaReconciler, s.zoaReconciler.Run))
+	}
+	if s.backupWorker != nil {
+		m.Add(s.leaderComponent(componentPolicyBackup, s.backupWorker.Run))
+	}
+	if s.workScheduler != nil {
+		m.Add(s.leaderComponent(componentWorkScheduler, s.workScheduler.Run))
+	}
+	if s.workHistory != nil {
+		m.Add(s.leaderComponent(componentWorkHistory, s.workHistory.Run))
+	}
+	if s.canary != nil {
+		m.Add(workerComponent(componentDeliveryCanary, s.canary.Run))
+	}
+	// Every replica checks the Maestro deployment it talks to
+	if s.maestroVer != nil {
+		m.Add(workerComponent(componentMaestroVersion, s.maestroVer.Run))
+	}
+	// and the health of each Maestro endpoint it can switch its calls to
+	if s.maestroFail != nil && s.cfg.Maestro.FailoverCheckInterval > 0 {
+		m.Add(workerComponent(componentMaestroFailover, s.maestroFail.Run))
+	}
+	// Added before the API server so it stops after the last request is
+	// served and delivers that request's record
+	if s.metering != nil {
+		m.Add(workerComponent(componentMetering, s.metering.Run))
+	}
+	if s.events != nil {
+		m.Add(workerComponent(componentEvents, s.events.Run))
+	}
+	if s.authzStreams != nil {
+		m.Add(s.leaderComponent(componentAuthzStreams, s.authzStreams.Run))
+	}
+	// Every replica counts its own decisions, so every replica flushes them
+	if s.decisionAnalytics != nil {
+		m.Add(workerComponent(componentDecisionAnalytics, s.decisionAnalytics.Run))
+	}
+	// Every replica matches the principals it saw
+	if s.patternMatcher != nil {
+		m.Add(workerComponent(componentPatternMatcher, s.patternMatcher.Run))
+	}
+	// Every replica writes the decisions it made
+	if s.decisionAudit != nil {
+		m.Add(workerComponent(componentDecisionAudit, s.decisionAudit.Run))
+	}
+	// Every replica evicts the revoked keys from its own cache
+	if s.apiKeyCache != nil {
+		m.Add(workerComponent(componentAPIKeyCache, s.apiKeyCache.Run))
+	}
+	// Every replica onboards the accounts it queued; one requeues the
+	// onboardings that stalled, including those queued on stopped replicas
+	if s.provisioner != nil {
+		m.Add(workerComponent(componentProvisioner, s.provisioner.Run))
+		m.Add(s.leaderComponent(componentOnboardingSweep, s.provisioner.RunSweeper))
 	}
+	// Retries authz initialization after a degraded start. Every replica
+	// initializes its own authorizer, so this is not leader elected.
+	if s.authzRecovery != nil {
+		m.Add(workerComponent(componentAuthzRecovery, s.authzRecovery.Run))
+	}
+	m.Add(httpComponent(componentAPIServer, true, s.apiServer))
 
-	// Start health server
-	go func() {
-		s.logger.Info("starting health server", "addr", s.healthServer.Addr)
-		if err := s.healthServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- fmt.Errorf("health server error: %w", err)
-		}
-	}()
+	// Readiness passes once the warm-up is done. It holds off shutdown for
+	// at most the warm-up timeout.
+	if s.warmup != nil {
+		m.OnStarted(func() { s.warmup.Run(ctx) })
+	}
+	// Initialization is complete once New has returned, the components are
+	// running and the warm-up is done; a degraded authz start shows in
+	// readiness instead
+	m.OnStarted(s.healthHandler.MarkStarted)
+	m.OnStopping(func() {
+		s.healthHandler.SetReady(false)
+		time.Sleep(drainDelay)
+	})
 
-	// Start metrics server
-	go func() {
-		s.logger.Info("starting metrics server", "addr", s.metricsServer.Addr)
-		if err := s.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- fmt.Errorf("metrics server error: %w", err)
-		}
-	}()
+	return m.Run(ctx)
+}
 
-	// Start API server
-	go func() {
-		s.logger.Info("starting API server", "addr", s.apiServer.Addr)
-		if err := s.apiServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			errCh <- fmt.Errorf("API server error: %w", err)
+// httpComponent serves srv until it is shut down, draining in-flight requests
+func httpComponent(name string, critical bool, srv *http.Server) lifecycle.Component {
+	return lifecycle.Component{
+		Name:     name,
+		Critical: critical,
+		Run: func(ctx context.Context) error {
+			if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return fmt.Errorf("listening on %s: %w", srv.Addr, err)
+			}
+			return nil
+		},
+		Stop: srv.Shutdown,
+	}
+}
+
+// workerComponent runs a background worker until its context is cancelled
+func workerComponent(name string, run func(ctx context.Context)) lifecycle.Component {
+	return lifecycle.Component{
+		Name: name,
+		Run: func(ctx context.Context) error {
+			run(ctx)
+			return nil
+		},
+	}
+}
+
+// leaderComponent runs a background worker only while this replica is its
+// elected leader
+func (s *Server) leaderComponent(name string, run func(ctx context.Context)) lifecycle.Component {
+	return workerComponent(name, func(ctx context.Context) {
+		s.elector.Run(ctx, name, run)
+	})
+}
+
+// A shared rate limit call that takes longer than rateLimitTimeout is limited
+// locally instead, as is every request for rateLimitRetry afterwards
+const (
+	rateLimitTimeout = 200 * time.Millisecond
+	rateLimitRetry   = 30 * time.Second
+)
+
+// newRateLimiter creates the per-account limiter for the configured backend,
+// and one limiter per plan allowing that plan's rate
+func newRateLimiter(ctx context.Context, cfg config.RateLimitConfig, tiers plans.Tiers, logger *slog.Logger) (ratelimit.Limiter, map[string]ratelimit.Limiter, error) {
+	newLimiter := func(limit int) ratelimit.Limiter { return ratelimit.NewLocal(limit, cfg.Window) }
+	if cfg.Backend == ratelimit.BackendDynamoDB {
+		dynamoClient, err := client.NewDynamoDBClient(ctx, cfg.AWSRegion, cfg.DynamoDBEndpoint)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to create rate limit DynamoDB client: %w", err)
 		}
-	}()
+		newLimiter = func(limit int) ratelimit.Limiter {
+			shared := ratelimit.NewDynamoLimiter(cfg.TableName, dynamoClient, limit, cfg.Window, logger)
+			local := ratelimit.NewLocal(limit, cfg.Window)
+			return ratelimit.NewFallback(ratelimit.BackendDynamoDB, shared, local, rateLimitTimeout, rateLimitRetry, logger)
+		}
+	}
+
+	byPlan := make(map[string]ratelimit.Limiter, len(tiers))
+	for plan, limits := range tiers {
+		byPlan[plan] = newLimiter(limits.RateLimit)
+	}
+	logger.Info("rate limiting enabled", "limit", cfg.Limit, "window", cfg.Window, "backend", cfg.Backend, "table", cfg.TableName)
+	return newLimiter(cfg.Limit), byPlan, nil
+}
+
+// newSecretSource creates the source secret references are resolved from,
+// or returns nil when no secret is given as a reference
+func newSecretSource(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*secretsource.Source, error) {
+	if !secretsource.IsReference(cfg.Identity.SharedSecret) && !secretsource.IsReference(cfg.Cache.Redis.Password) {
+		return nil, nil
+	}
+	source, err := secretsource.NewAWS(ctx, cfg.Secrets.AWSRegion, cfg.Secrets.RefreshInterval, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("resolving secrets from Secrets Manager and SSM", "refresh_interval", cfg.Secrets.RefreshInterval)
+	return source, nil
+}
 
-	// Wait for context cancellation or error
-	select {
-	case <-ctx.Done():
-		s.logger.Info("shutting down servers")
-		return s.shutdown()
-	case err := <-errCh:
-		return err
+// resolveSecret resolves value through secrets, which is nil when value is
+// not a reference
+func resolveSecret(ctx context.Context, secrets *secretsource.Source, value string) (*secretsource.Value, error) {
+	if secrets == nil {
+		return secretsource.Static(value), nil
 	}
+	return secrets.Resolve(ctx, value)
 }
 
-func (s *Server) shutdown() error {
-	// Mark as not ready to stop receiving traffic
-	s.healthHandler.SetReady(false)
+// newMeteringSink creates the sink metering records are delivered to
+func newMeteringSink(ctx context.Context, cfg config.MeteringConfig) (metering.Sink, error) {
+	if cfg.Sink == metering.SinkFile {
+		return metering.NewFileSink(cfg.Target)
+	}
 
-	// Give load balancers time to detect we're not ready
-	time.Sleep(5 * time.Second)
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config for metering: %w", err)
+	}
+	if cfg.Sink == metering.SinkKinesis {
+		return metering.NewKinesisSink(cfg.Target, kinesis.NewFromConfig(awsCfg)), nil
+	}
+	return metering.NewSQSSink(cfg.Target, sqs.NewFromConfig(awsCfg)), nil
+}
 
-	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.cfg.Server.ShutdownTimeout)
+// A bulk request to OpenSearch is retried up to openSearchMaxRetries times
+// within a flush, backing off from openSearchRetryInterval; what is still
+// not indexed waits for the next flush
+const (
+	openSearchMaxRetries    = 3
+	openSearchRetryInterval = 500 * time.Millisecond
+)
+
+// newEventPublisher creates the Kafka publisher of platform events, with AWS
+// credentials for MSK IAM when it is enabled
+func newEventPublisher(ctx context.Context, cfg config.EventsConfig, logger *slog.Logger) (*events.KafkaPublisher, error) {
+	topics, err := events.ParseTopics(cfg.Topics, cfg.DefaultTopic)
+	if err != nil {
+		return nil, err
+	}
+	var credentials aws.CredentialsProvider
+	if cfg.MSKIAM {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config for MSK: %w", err)
+		}
+		credentials = awsCfg.Credentials
+	}
+	registerCtx, cancel := context.WithTimeout(ctx, schemaRegistrationTimeout)
 	defer cancel()
+	return events.NewKafkaPublisher(registerCtx, events.KafkaConfig{
+		Brokers:           cfg.KafkaBrokers,
+		Topics:            topics,
+		Source:            cfg.Source,
+		Encoding:          cfg.Encoding,
+		SchemaRegistryURL: cfg.SchemaRegistryURL,
+		TLS:               cfg.TLS,
+		MSKIAM:            cfg.MSKIAM,
+		BufferSize:        cfg.BufferSize,
+	}, credentials, logger)
+}
+
+// schemaRegistrationTimeout bounds registering the events' schema at startup
+const schemaRegistrationTimeout = 30 * time.Second
 
-	// Shutdown servers in order
-	if err := s.apiServer.Shutdown(shutdownCtx); err != nil {
-		s.logger.Error("failed to shutdown API server", "error", err)
+// newDecisionSink creates the sink indexing audited decisions into
+// OpenSearch, with AWS credentials to sign its requests when an AWS service
+// is configured
+func newDecisionSink(ctx context.Context, cfg *authz.Config, logger *slog.Logger) (*opensearch.DecisionSink, error) {
+	osCfg := opensearch.Config{
+		Endpoint:      cfg.AuditOpenSearchEndpoint,
+		IndexPrefix:   cfg.AuditOpenSearchIndex,
+		Rollover:      cfg.AuditOpenSearchRollover,
+		AWSRegion:     cfg.AWSRegion,
+		AWSService:    cfg.AuditOpenSearchService,
+		MaxRetries:    openSearchMaxRetries,
+		RetryInterval: openSearchRetryInterval,
+	}
+	var credentials aws.CredentialsProvider
+	if cfg.AuditOpenSearchService != "" {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWSRegion))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config for OpenSearch: %w", err)
+		}
+		credentials = awsCfg.Credentials
+	}
+	return opensearch.NewDecisionSink(opensearch.NewClient(osCfg, credentials, logger)), nil
+}
+
+// newWorkHandler creates the work handler with the optional envelope
+// encryption, secret reference resolution and scheduling features configured,
+// and the scheduler that submits its scheduled works
+func newWorkHandler(ctx context.Context, cfg *config.Config, maestroClient maestro.ClientInterface, checker authz.Checker, requiredTags *apphandlers.RequiredTags, ops *operations.Registry, publisher *events.KafkaPublisher, caps clustercaps.Store, pinning *residency.Checker, logger *slog.Logger) (*apphandlers.WorkHandler, *workschedule.Scheduler, *workhistory.Recorder, error) {
+	workCfg := apphandlers.WorkConfig{
+		RequiredTags: requiredTags,
+		Operations:   ops,
+		Capabilities: caps,
+		Residency:    pinning,
+		// Only clusters with recorded capabilities are checked
+		AgentCompatibility: cfg.MgmtClusters.AgentCompatibility,
+		Limits: apphandlers.WorkLimits{
+			MaxManifests:    cfg.Work.MaxManifests,
+			MaxPayloadBytes: cfg.Work.MaxPayloadBytes,
+			MaxMessageBytes: cfg.Work.MaxMessageBytes,
+			MaxChunks:       cfg.Work.MaxChunks,
+		},
+	}
+
+	if publisher != nil {
+		workCfg.Events = publisher
 	}
 
-	if err := s.metricsServer.Shutdown(shutdownCtx); err != nil {
-		s.logger.Error("failed to shutdown metrics server", "error", err)
+	if cfg.Work.MaxConcurrent > 0 {
+		workCfg.Queue = workqueue.New(cfg.Work.MaxConcurrent, cfg.Work.MaxQueued)
+		logger.Info("work submission queue enabled", "max_concurrent", cfg.Work.MaxConcurrent, "max_queued", cfg.Work.MaxQueued)
 	}
 
-	if err := s.healthServer.Shutdown(shutdownCtx); err != nil {
-		s.logger.Error("failed to shutdown health server", "error", err)
+	var clusterLimiter ratelimit.Limiter
+	if cfg.Work.ClusterRateLimit > 0 {
+		clusterLimiter = ratelimit.NewLocal(cfg.Work.ClusterRateLimit, cfg.Work.ClusterRateWindow)
+		logger.Info("work cluster rate limit enabled", "limit", cfg.Work.ClusterRateLimit, "window", cfg.Work.ClusterRateWindow)
+	}
+	workCfg.ClusterLimit = workrate.NewLimiter(clusterLimiter, logger)
+
+	if cfg.Work.MetadataTableName != "" || cfg.Work.SchedulesTableName != "" || cfg.Work.HistoryTableName != "" {
+		dynamoClient, err := client.NewDynamoDBClient(ctx, cfg.Work.AWSRegion, cfg.Work.DynamoDBEndpoint)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to create work DynamoDB client: %w", err)
+		}
+		if cfg.Work.MetadataTableName != "" {
+			workCfg.MetadataStore = workmeta.NewDynamoStore(cfg.Work.MetadataTableName, dynamoClient, logger)
+			logger.Info("work metadata store enabled", "table", cfg.Work.MetadataTableName)
+		}
+		if cfg.Work.SchedulesTableName != "" {
+			workCfg.Schedules = workschedule.NewDynamoStore(cfg.Work.SchedulesTableName, dynamoClient, logger)
+			logger.Info("work schedules enabled", "table", cfg.Work.SchedulesTableName, "interval", cfg.Work.ScheduleInterval)
+		}
+		if cfg.Work.HistoryTableName != "" {
+			workCfg.History = workhistory.NewDynamoStore(cfg.Work.HistoryTableName, dynamoClient, logger)
+			logger.Info("work status history enabled", "table", cfg.Work.HistoryTableName, "interval", cfg.Work.HistoryInterval, "retention", cfg.Work.HistoryRetention)
+		}
+	}
+
+	if cfg.Work.EnvelopeKMSKeyID != "" || cfg.Work.SecretRefsEnabled {
+		// KMS and secret lookups carry the request's trace context
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Work.AWSRegion),
+			awsconfig.WithAPIOptions(traceContextAPIOptions))
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to load AWS config for work handler: %w", err)
+		}
+
+		if cfg.Work.EnvelopeKMSKeyID != "" {
+			workCfg.Encrypter = envelope.NewEncrypter(kms.NewFromConfig(awsCfg), cfg.Work.EnvelopeKMSKeyID, logger)
+			logger.Info("work envelope encryption enabled", "kms_key_id", cfg.Work.EnvelopeKMSKeyID)
+		}
+
+		if cfg.Work.SecretRefsEnabled {
+			platformPrefixes, err := secretref.ParsePlatformPrefixes(cfg.Work.SecretRefsPlatformPrefixes)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			workCfg.Resolver = secretref.NewResolver(secretsmanager.NewFromConfig(awsCfg), ssm.NewFromConfig(awsCfg), checker, logger).
+				WithPlatformSecrets(platformPrefixes)
+			logger.Info("work secret reference resolution enabled", "platform_prefixes", platformPrefixes)
+		}
 	}
 
-	s.logger.Info("all servers stopped")
-	return nil
+	if len(cfg.Work.ChartRegistries) > 0 {
+		puller, err := workchart.NewRegistryPuller(cfg.Work.ChartPullTimeout)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		workCfg.Charts = workchart.NewRenderer(puller, workchart.Config{
+			Registries:    cfg.Work.ChartRegistries,
+			MaxChartBytes: cfg.Work.MaxChartBytes,
+		}, logger)
+		logger.Info("work chart rendering enabled", "registries", cfg.Work.ChartRegistries, "max_chart_bytes", cfg.Work.MaxChartBytes)
+	}
+
+	if len(cfg.Work.PayloadRegistries) > 0 || len(cfg.Work.PayloadBuckets) > 0 {
+		var (
+			ociClient workpayload.OCIClient
+			s3Client  workpayload.S3Client
+		)
+		if len(cfg.Work.PayloadRegistries) > 0 {
+			registryClient, err := workpayload.NewRegistryClient(cfg.Work.PayloadFetchTimeout)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			ociClient = registryClient
+		}
+		if len(cfg.Work.PayloadBuckets) > 0 {
+			awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.Work.AWSRegion),
+				awsconfig.WithAPIOptions(traceContextAPIOptions))
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("failed to load AWS config for work payloads: %w", err)
+			}
+			s3Client = s3.NewFromConfig(awsCfg)
+		}
+		payloadCfg := workpayload.Config{
+			Registries: cfg.Work.PayloadRegistries,
+			Buckets:    cfg.Work.PayloadBuckets,
+			MaxBytes:   cfg.Work.MaxPayloadRefBytes,
+		}
+		if len(cfg.Work.PayloadSigners) > 0 {
+			verifier, err := newPayloadVerifier(cfg.Work.PayloadTrustedRoot, cfg.Work.PayloadSigners)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			payloadCfg.Signatures = verifier
+			logger.Info("work payload signature verification enabled", "signers", cfg.Work.PayloadSigners, "trusted_root", cfg.Work.PayloadTrustedRoot)
+		}
+		workCfg.Payloads = workpayload.NewFetcher(ociClient, s3Client, payloadCfg, logger)
+		logger.Info("work payload references enabled", "registries", cfg.Work.PayloadRegistries, "buckets", cfg.Work.PayloadBuckets, "max_bytes", cfg.Work.MaxPayloadRefBytes)
+	}
+
+	// Bundles created and deleted through the work API are counted per cluster
+	workHandler := apphandlers.NewWorkHandler(workrate.NewClient(maestroClient), workCfg, logger)
+
+	// Scheduled works are only accepted where the work routes are served
+	var scheduler *workschedule.Scheduler
+	if workCfg.Schedules != nil && cfg.Server.ServesPlatform() {
+		scheduler = workschedule.NewScheduler(workCfg.Schedules, workHandler, cfg.Work.ScheduleInterval, logger)
+	}
+	var recorder *workhistory.Recorder
+	if workCfg.History != nil && workCfg.MetadataStore != nil && cfg.Server.ServesPlatform() {
+		recorder = workhistory.NewRecorder(maestroClient, workCfg.MetadataStore, workCfg.History, cfg.Work.HistoryInterval, cfg.Work.HistoryRetention, logger)
+	}
+
+	return workHandler, scheduler, recorder, nil
+}
+
+// newPayloadVerifier creates the verifier of work payload signatures by signers
+func newPayloadVerifier(trustedRootPath string, signers []string) (*workpayload.SigstoreVerifier, error) {
+	trustedRoot, err := root.NewTrustedRootFromPath(trustedRootPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load Sigstore trusted root: %w", err)
+	}
+	identities := make([]workpayload.Identity, 0, len(signers))
+	for _, signer := range signers {
+		identity, err := workpayload.ParseIdentity(signer)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, identity)
+	}
+	return workpayload.NewSigstoreVerifier(trustedRoot, identities)
+}
+
+// slowRequestClasses maps the configured thresholds onto the API route prefixes
+func slowRequestClasses(c config.SlowRequestConfig) []middleware.RouteClass {
+	return []middleware.RouteClass{
+		{Name: "tenant", Prefixes: []string{"/api/v0/clusters", "/api/v0/nodepools"}, Threshold: c.Tenant},
+		{Name: "platform", Prefixes: []string{"/api/v0/management_clusters", "/api/v0/resource_bundles", "/api/v0/work"}, Threshold: c.Platform},
+		{Name: "trusted-actions", Prefixes: []string{"/api/v0/trusted-actions"}, Threshold: c.TrustedActions},
+		{Name: "authz", Prefixes: []string{"/api/v0/authz", "/api/v0/accounts", "/api/v0/organizations", "/api/v0/admin"}, Threshold: c.Authz},
+	}
+}
+
+// pageLimits maps configured page limits onto the handler type
+func pageLimits(l config.PageLimits) apphandlers.PageLimits {
+	return apphandlers.PageLimits{Default: l.Default, Max: l.Max}
 }
@@ -2,13 +2,17 @@ package server
 
 import (
 	"context"
+	"encoding/json"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"regexp"
 	"testing"
 	"time"
 
+	"github.com/gorilla/mux"
+
 	"github.com/openshift/rosa-regional-platform-api/pkg/config"
 	"github.com/openshift/rosa-regional-platform-api/pkg/middleware"
 )
@@ -95,6 +99,7 @@ func TestNew_WithCustomConfig(t *testing.T) {
 func TestServer_HealthRoutes(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := config.NewConfig()
+	cfg.Server.WarmupTimeout = 0
 
 	server, err := New(cfg, logger)
 	if err != nil {
@@ -170,6 +175,13 @@ func TestServer_ManagementClusterRoutes_Unauthorized(t *testing.T) {
 			accountID:      "",
 			expectedStatus: http.StatusForbidden,
 		},
+		{
+			name:           "HEAD without account ID",
+			method:         http.MethodHead,
+			path:           "/api/v0/management_clusters",
+			accountID:      "",
+			expectedStatus: http.StatusForbidden,
+		},
 		{
 			name:           "POST with unauthorized account",
 			method:         http.MethodPost,
@@ -271,6 +283,41 @@ func TestServer_IdentityMiddleware(t *testing.T) {
 	if w.Code != http.StatusOK {
 		t.Errorf("expected status 200, got %d", w.Code)
 	}
+	if got := w.Header().Get(middleware.HeaderResponseRequestID); got != "test-request-123" {
+		t.Errorf("expected the gateway request ID to be echoed, got %q", got)
+	}
+}
+
+func TestServer_RequestIDOnErrors(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := config.NewConfig()
+
+	server, err := New(cfg, logger)
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	// Unmatched routes and requests the identity middleware rejects
+	paths := map[string]string{
+		"/api/v0/nonexistent": "",
+		"/api/v0/live":        "12345",
+	}
+	for path, accountID := range paths {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(middleware.HeaderRequestID, "gw-req-1")
+		if accountID != "" {
+			req.Header.Set(middleware.HeaderAccountID, accountID)
+		}
+		w := httptest.NewRecorder()
+		server.apiServer.Handler.ServeHTTP(w, req)
+
+		if w.Code < http.StatusBadRequest {
+			t.Errorf("%s: expected an error status, got %d", path, w.Code)
+		}
+		if got := w.Header().Get(middleware.HeaderResponseRequestID); got != "gw-req-1" {
+			t.Errorf("%s: expected request ID gw-req-1 on the error response, got %q", path, got)
+		}
+	}
 }
 
 func TestServer_MetricsRoute(t *testing.T) {
@@ -301,6 +348,7 @@ func TestServer_MetricsRoute(t *testing.T) {
 func TestServer_HealthServerRoutes(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := config.NewConfig()
+	cfg.Server.WarmupTimeout = 0
 
 	server, err := New(cfg, logger)
 	if err != nil {
@@ -322,6 +370,11 @@ func TestServer_HealthServerRoutes(t *testing.T) {
 			path:           "/readyz",
 			expectedStatus: http.StatusOK,
 		},
+		{
+			name:           "startupz endpoint before Run",
+			path:           "/startupz",
+			expectedStatus: http.StatusServiceUnavailable,
+		},
 	}
 
 	for _, tt := range tests {
@@ -386,6 +439,7 @@ func TestServer_InvalidRoutes(t *testing.T) {
 func TestServer_ReadinessToggle(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := config.NewConfig()
+	cfg.Server.WarmupTimeout = 0
 
 	server, err := New(cfg, logger)
 	if err != nil {
@@ -424,6 +478,27 @@ func TestServer_ReadinessToggle(t *testing.T) {
 	}
 }
 
+func TestServer_NotReadyUntilWarmedUp(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := config.NewConfig()
+
+	server, err := New(cfg, logger)
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+	if server.warmup == nil {
+		t.Fatal("expected a warm-up with the default configuration")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v0/ready", nil)
+	w := httptest.NewRecorder()
+	server.apiServer.Handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 before the warm-up, got %d", w.Code)
+	}
+}
+
 func TestServer_ServerAddresses(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
 	cfg := &config.Config{
@@ -481,3 +556,111 @@ func TestServer_ServerAddresses(t *testing.T) {
 		t.Errorf("expected healthServer.WriteTimeout=10s, got %v", server.healthServer.WriteTimeout)
 	}
 }
+
+func TestServer_Profiles(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	tests := []struct {
+		name         string
+		profile      string
+		path         string
+		wantNotFound bool
+	}{
+		{name: "all serves platform routes", profile: config.ProfileAll, path: "/api/v0/management_clusters"},
+		{name: "all serves frontend routes", profile: config.ProfileAll, path: "/api/v0/clusters"},
+		{name: "frontend serves clusters", profile: config.ProfileFrontend, path: "/api/v0/clusters"},
+		{name: "frontend omits management clusters", profile: config.ProfileFrontend, path: "/api/v0/management_clusters", wantNotFound: true},
+		{name: "platform serves resource bundles", profile: config.ProfilePlatform, path: "/api/v0/resource_bundles"},
+		{name: "platform omits clusters", profile: config.ProfilePlatform, path: "/api/v0/clusters", wantNotFound: true},
+		{name: "platform omits authz", profile: config.ProfilePlatform, path: "/api/v0/authz/policies", wantNotFound: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.NewConfig()
+			cfg.Server.Profile = tt.profile
+
+			server, err := New(cfg, logger)
+			if err != nil {
+				t.Fatalf("unexpected error creating server: %v", err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			w := httptest.NewRecorder()
+			server.apiServer.Handler.ServeHTTP(w, req)
+
+			if tt.wantNotFound && w.Code != http.StatusNotFound {
+				t.Errorf("expected 404 for %s under profile %s, got %d", tt.path, tt.profile, w.Code)
+			}
+			if !tt.wantNotFound && w.Code == http.StatusNotFound {
+				t.Errorf("expected %s to be served under profile %s", tt.path, tt.profile)
+			}
+		})
+	}
+}
+
+// publicRoutes are the API routes served without caller identity
+var publicRoutes = map[string]bool{
+	"/api/v0/live":           true,
+	"/api/v0/ready":          true,
+	"/api/v0/startup":        true,
+	"/api/v0/info":           true,
+	"/api/v0/status":         true,
+	"/api/v0/status/leaders": true,
+}
+
+// TestServer_ProtectedRoutesRequireAccountID walks every registered API route
+// and checks that all routes outside publicRoutes reject a request without an
+// account ID with 403 missing-account-id, so a newly added route cannot
+// silently run with an empty account
+func TestServer_ProtectedRoutesRequireAccountID(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	cfg := config.NewConfig()
+	cfg.AllowedAccounts = []string{"123456789012"}
+
+	server, err := New(cfg, logger)
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	pathVar := regexp.MustCompile(`\{[^}]+\}`)
+	checked := 0
+	err = server.apiRouter.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil || publicRoutes[tmpl] {
+			return nil
+		}
+		methods, err := route.GetMethods()
+		if err != nil {
+			// Path prefixes of subrouters carry no methods
+			return nil
+		}
+		path := pathVar.ReplaceAllString(tmpl, "test-id")
+
+		for _, method := range methods {
+			t.Run(method+" "+tmpl, func(t *testing.T) {
+				w := httptest.NewRecorder()
+				server.apiServer.Handler.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+
+				if w.Code != http.StatusForbidden {
+					t.Fatalf("expected status 403, got %d: %s", w.Code, w.Body.String())
+				}
+				var body map[string]string
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("failed to decode response: %v", err)
+				}
+				if body["code"] != "missing-account-id" {
+					t.Errorf("expected code missing-account-id, got %q", body["code"])
+				}
+			})
+			checked++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("failed to walk routes: %v", err)
+	}
+	if checked == 0 {
+		t.Fatal("expected protected routes to be checked")
+	}
+}
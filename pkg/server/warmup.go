@@ -0,0 +1,111 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/bootstrap"
+	"github.com/openshift/rosa-regional-platform-api/pkg/doctor"
+	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+)
+
+// warmupStep primes one dependency before the server reports ready
+type warmupStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// warmup runs the warm-up steps once the servers have started and then marks
+// the server ready, so the first requests a replica receives do not pay for
+// connection setup and cold caches. Steps run concurrently, together bounded
+// by timeout. A failing step is logged and does not hold readiness back: the
+// requests that need the dependency report its failure as they would anyway.
+type warmup struct {
+	steps   []warmupStep
+	timeout time.Duration
+	health  *apphandlers.HealthHandler
+	logger  *slog.Logger
+}
+
+// Run runs the steps and then sets the server ready
+func (w *warmup) Run(ctx context.Context) {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(ctx, w.timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, step := range w.steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stepStart := time.Now()
+			err := step.run(ctx)
+			switch {
+			case errors.Is(err, doctor.ErrSkipped):
+				w.logger.Debug("warm-up step skipped", "step", step.name, "reason", err)
+			case err != nil:
+				w.logger.Warn("warm-up step failed", "step", step.name, "error", err, "duration_ms", time.Since(stepStart).Milliseconds())
+			default:
+				w.logger.Debug("warm-up step done", "step", step.name, "duration_ms", time.Since(stepStart).Milliseconds())
+			}
+		}()
+	}
+	wg.Wait()
+
+	w.health.SetReady(true)
+	w.logger.Info("warm-up complete", "steps", len(w.steps), "duration_ms", time.Since(start).Milliseconds())
+}
+
+// privilegedWarmup checks the privileged status and resolves the plan of each
+// of accounts, warming the DynamoDB connection and the plan cache the first
+// requests of those accounts go through
+func privilegedWarmup(accounts []string, isPrivileged func(ctx context.Context, accountID string) (bool, error), accountPlan func(ctx context.Context, accountID string) (string, error)) warmupStep {
+	return warmupStep{
+		name: "privileged-accounts",
+		run: func(ctx context.Context) error {
+			if len(accounts) == 0 {
+				return doctor.Skipped("no privileged accounts declared")
+			}
+			var errs []error
+			for _, accountID := range accounts {
+				if _, err := isPrivileged(ctx, accountID); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if _, err := accountPlan(ctx, accountID); err != nil {
+					errs = append(errs, err)
+				}
+			}
+			return errors.Join(errs...)
+		},
+	}
+}
+
+// privilegedAccounts lists the privileged accounts of manifest, which may be
+// nil
+func privilegedAccounts(manifest *bootstrap.Manifest) []string {
+	if manifest == nil {
+		return nil
+	}
+	var accounts []string
+	for _, account := range manifest.Accounts {
+		if account.Privileged {
+			accounts = append(accounts, account.AccountID)
+		}
+	}
+	return accounts
+}
+
+// doctorWarmup runs a dependency self-test check as a warm-up step
+func doctorWarmup(check doctor.Check) warmupStep {
+	return warmupStep{
+		name: check.Name,
+		run: func(ctx context.Context) error {
+			_, err := check.Run(ctx)
+			return err
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/bootstrap"
+	apphandlers "github.com/openshift/rosa-regional-platform-api/pkg/handlers"
+)
+
+func TestWarmup(t *testing.T) {
+	health := apphandlers.NewHealthHandler()
+	health.SetReady(false)
+
+	var ran atomic.Int32
+	w := &warmup{
+		steps: []warmupStep{
+			{name: "ok", run: func(ctx context.Context) error { ran.Add(1); return nil }},
+			{name: "failing", run: func(ctx context.Context) error { ran.Add(1); return errors.New("unreachable") }},
+			{name: "slow", run: func(ctx context.Context) error {
+				ran.Add(1)
+				<-ctx.Done()
+				return ctx.Err()
+			}},
+		},
+		timeout: 50 * time.Millisecond,
+		health:  health,
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	}
+
+	ready := func() int {
+		rec := httptest.NewRecorder()
+		health.Readiness(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+		return rec.Code
+	}
+	if ready() != http.StatusServiceUnavailable {
+		t.Fatal("expected readiness to fail before the warm-up")
+	}
+	w.Run(context.Background())
+	if ran.Load() != 3 {
+		t.Errorf("expected every step to run, got %d", ran.Load())
+	}
+	if ready() != http.StatusOK {
+		t.Error("expected readiness to pass after the warm-up, despite failed and timed out steps")
+	}
+}
+
+func TestPrivilegedWarmup(t *testing.T) {
+	manifest := &bootstrap.Manifest{Accounts: []bootstrap.Account{
+		{AccountID: "111111111111", Privileged: true},
+		{AccountID: "222222222222"},
+		{AccountID: "333333333333", Privileged: true},
+	}}
+	var checked, resolved []string
+	step := privilegedWarmup(privilegedAccounts(manifest),
+		func(ctx context.Context, accountID string) (bool, error) {
+			checked = append(checked, accountID)
+			if accountID == "333333333333" {
+				return false, errors.New("throttled")
+			}
+			return true, nil
+		},
+		func(ctx context.Context, accountID string) (string, error) {
+			resolved = append(resolved, accountID)
+			return "standard", nil
+		})
+
+	if err := step.run(context.Background()); err == nil {
+		t.Error("expected the failed privileged check to be reported")
+	}
+	if len(checked) != 2 || len(resolved) != 1 || resolved[0] != "111111111111" {
+		t.Errorf("expected the privileged accounts to be checked and resolved, got %v and %v", checked, resolved)
+	}
+	if privilegedAccounts(nil) != nil {
+		t.Error("expected no accounts without a manifest")
+	}
+}
@@ -0,0 +1,285 @@
+// Package slo measures the API's availability and latency against its
+// objectives in-process, so each replica can report its error budget without
+// an external aggregator.
+//
+// A request is good for availability unless answered with a 5xx, and good
+// for latency unless it takes longer than the latency threshold of its route
+// class. Outcomes are kept per route class in minute buckets for the short
+// windows used to judge the burn rate, and in hour buckets over the SLO
+// period. They are also exported as Prometheus counters, from which the same
+// ratios can be recorded across replicas.
+package slo
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// SLIs
+const (
+	Availability = "availability"
+	Latency      = "latency"
+)
+
+// BurnWindows are the short windows reported besides the SLO period
+var BurnWindows = []time.Duration{5 * time.Minute, time.Hour, 6 * time.Hour}
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_api_slo_requests_total",
+		Help: "API requests counted towards the SLOs, by route class.",
+	}, []string{"class"})
+	badRequestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_api_slo_bad_requests_total",
+		Help: "API requests that failed an SLI, by route class and SLI (availability or latency).",
+	}, []string{"class", "sli"})
+	objectiveGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_api_slo_objective",
+		Help: "Target fraction of good requests, by SLI.",
+	}, []string{"sli"})
+	latencyThresholdGauge = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_api_slo_latency_threshold_seconds",
+		Help: "Latency above which a request fails the latency SLI, by route class.",
+	}, []string{"class"})
+)
+
+// Config sets the objectives and the period of the error budget
+type Config struct {
+	// Period is the rolling window the error budget covers
+	Period time.Duration
+	// Availability is the target fraction of requests answered without a 5xx
+	Availability float64
+	// Latency is the target fraction of requests answered within their
+	// route class's latency threshold
+	Latency float64
+}
+
+// counts are the outcomes of the requests in a bucket or window. Timed
+// requests are those of a class with a latency threshold.
+type counts struct {
+	total  int64
+	errors int64
+	timed  int64
+	slow   int64
+}
+
+func (c *counts) add(o counts) {
+	c.total += o.total
+	c.errors += o.errors
+	c.timed += o.timed
+	c.slow += o.slow
+}
+
+type bucket struct {
+	index int64
+	counts
+}
+
+// ring keeps the counts of the last len(buckets) periods of width
+type ring struct {
+	width   time.Duration
+	buckets []bucket
+}
+
+func newRing(width time.Duration, n int) *ring {
+	return &ring{width: width, buckets: make([]bucket, n)}
+}
+
+func (r *ring) add(at time.Time, c counts) {
+	index := at.UnixNano() / int64(r.width)
+	b := &r.buckets[index%int64(len(r.buckets))]
+	if b.index != index {
+		*b = bucket{index: index}
+	}
+	b.add(c)
+}
+
+// sum adds up the buckets overlapping the span ending at at
+func (r *ring) sum(at time.Time, span time.Duration) counts {
+	last := at.UnixNano() / int64(r.width)
+	first := last - int64((span+r.width-1)/r.width) + 1
+	var total counts
+	for _, b := range r.buckets {
+		if b.index >= first && b.index <= last && b.total > 0 {
+			total.add(b.counts)
+		}
+	}
+	return total
+}
+
+type class struct {
+	threshold time.Duration
+	minutes   *ring
+	hours     *ring
+}
+
+// Tracker records request outcomes per route class
+type Tracker struct {
+	cfg     Config
+	started time.Time
+	now     func() time.Time
+
+	mu      sync.Mutex
+	classes map[string]*class
+}
+
+// NewTracker creates a Tracker for cfg
+func NewTracker(cfg Config) *Tracker {
+	objectiveGauge.WithLabelValues(Availability).Set(cfg.Availability)
+	objectiveGauge.WithLabelValues(Latency).Set(cfg.Latency)
+	return &Tracker{
+		cfg:     cfg,
+		started: time.Now(),
+		now:     time.Now,
+		classes: make(map[string]*class),
+	}
+}
+
+// Record counts a request of a route class that took elapsed and was
+// answered with status. A zero threshold leaves the class out of the
+// latency SLI.
+func (t *Tracker) Record(className string, threshold, elapsed time.Duration, status int) {
+	c := counts{total: 1}
+	if status >= 500 {
+		c.errors = 1
+		badRequestsTotal.WithLabelValues(className, Availability).Inc()
+	}
+	if threshold > 0 {
+		c.timed = 1
+		if elapsed > threshold {
+			c.slow = 1
+			badRequestsTotal.WithLabelValues(className, Latency).Inc()
+		}
+	}
+	requestsTotal.WithLabelValues(className).Inc()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cl, ok := t.classes[className]
+	if !ok {
+		cl = &class{
+			minutes: newRing(time.Minute, int(BurnWindows[len(BurnWindows)-1]/time.Minute)+1),
+			hours:   newRing(time.Hour, int(t.cfg.Period/time.Hour)+1),
+		}
+		t.classes[className] = cl
+	}
+	if cl.threshold != threshold {
+		cl.threshold = threshold
+		latencyThresholdGauge.WithLabelValues(className).Set(threshold.Seconds())
+	}
+	now := t.now()
+	cl.minutes.add(now, c)
+	cl.hours.add(now, c)
+}
+
+// Report is the state of the SLOs of every route class seen
+type Report struct {
+	Kind string `json:"kind"`
+	// PeriodSeconds is the rolling window the error budget covers
+	PeriodSeconds int `json:"period_seconds"`
+	// CoveredSeconds is how much of the period this replica has observed,
+	// shorter than the period after a restart
+	CoveredSeconds int           `json:"covered_seconds"`
+	Objectives     Objectives    `json:"objectives"`
+	Classes        []ClassReport `json:"classes"`
+}
+
+// Objectives are the target fractions of good requests
+type Objectives struct {
+	Availability float64 `json:"availability"`
+	Latency      float64 `json:"latency"`
+}
+
+// ClassReport is the state of the SLOs of a route class
+type ClassReport struct {
+	Class                   string     `json:"class"`
+	LatencyThresholdSeconds float64    `json:"latency_threshold_seconds,omitempty"`
+	Availability            SLIReport  `json:"availability"`
+	Latency                 *SLIReport `json:"latency,omitempty"`
+}
+
+// SLIReport is an SLI over the burn windows and the SLO period
+type SLIReport struct {
+	// ErrorBudgetRemaining is the fraction of the period's error budget
+	// left; negative once the objective is missed
+	ErrorBudgetRemaining float64        `json:"error_budget_remaining"`
+	Windows              []WindowReport `json:"windows"`
+}
+
+// WindowReport is an SLI over one window
+type WindowReport struct {
+	WindowSeconds int   `json:"window_seconds"`
+	Total         int64 `json:"total"`
+	Bad           int64 `json:"bad"`
+	// Ratio is the fraction of good requests, 1 without requests
+	Ratio float64 `json:"ratio"`
+	// BurnRate is how many times faster than allowed the error budget is
+	// spent; 1 spends exactly the budget over the period
+	BurnRate float64 `json:"burn_rate"`
+}
+
+// Report returns the state of every route class's SLOs, by class name
+func (t *Tracker) Report() *Report {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	report := &Report{
+		Kind:           "SLOReport",
+		PeriodSeconds:  int(t.cfg.Period.Seconds()),
+		CoveredSeconds: int(min(now.Sub(t.started), t.cfg.Period).Seconds()),
+		Objectives:     Objectives{Availability: t.cfg.Availability, Latency: t.cfg.Latency},
+		Classes:        []ClassReport{},
+	}
+	for name, cl := range t.classes {
+		windows := make([]counts, 0, len(BurnWindows)+1)
+		spans := make([]time.Duration, 0, len(BurnWindows)+1)
+		for _, span := range BurnWindows {
+			windows = append(windows, cl.minutes.sum(now, span))
+			spans = append(spans, span)
+		}
+		windows = append(windows, cl.hours.sum(now, t.cfg.Period))
+		spans = append(spans, t.cfg.Period)
+
+		cr := ClassReport{
+			Class: name,
+			Availability: sliReport(t.cfg.Availability, spans, windows, func(c counts) (int64, int64) {
+				return c.total, c.errors
+			}),
+		}
+		if cl.threshold > 0 {
+			cr.LatencyThresholdSeconds = cl.threshold.Seconds()
+			latency := sliReport(t.cfg.Latency, spans, windows, func(c counts) (int64, int64) {
+				return c.timed, c.slow
+			})
+			cr.Latency = &latency
+		}
+		report.Classes = append(report.Classes, cr)
+	}
+	sort.Slice(report.Classes, func(i, j int) bool { return report.Classes[i].Class < report.Classes[j].Class })
+	return report
+}
+
+// sliReport reports an SLI over windows, the last of which is the period
+func sliReport(objective float64, spans []time.Duration, windows []counts, split func(counts) (int64, int64)) SLIReport {
+	budget := 1 - objective
+	r := SLIReport{ErrorBudgetRemaining: 1}
+	for i, w := range windows {
+		total, bad := split(w)
+		wr := WindowReport{WindowSeconds: int(spans[i].Seconds()), Total: total, Bad: bad, Ratio: 1}
+		if total > 0 {
+			badRatio := float64(bad) / float64(total)
+			wr.Ratio = 1 - badRatio
+			if budget > 0 {
+				wr.BurnRate = badRatio / budget
+			}
+		}
+		r.Windows = append(r.Windows, wr)
+	}
+	r.ErrorBudgetRemaining = 1 - r.Windows[len(r.Windows)-1].BurnRate
+	return r
+}
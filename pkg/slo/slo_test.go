@@ -0,0 +1,105 @@
+package slo
+
+import (
+	"math"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestTracker(now *time.Time) *Tracker {
+	t := NewTracker(Config{Period: 24 * time.Hour, Availability: 0.99, Latency: 0.9})
+	t.now = func() time.Time { return *now }
+	t.started = *now
+	return t
+}
+
+func TestTracker_Report(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	// Two hours ago: 100 requests, 2 failed and 20 slow
+	now = now.Add(-2 * time.Hour)
+	for i := 0; i < 100; i++ {
+		status, elapsed := http.StatusOK, 10*time.Millisecond
+		if i < 2 {
+			status = http.StatusServiceUnavailable
+		}
+		if i < 20 {
+			elapsed = 2 * time.Second
+		}
+		tracker.Record("tenant", time.Second, elapsed, status)
+	}
+	// Now: 100 good requests
+	now = now.Add(2 * time.Hour)
+	for i := 0; i < 100; i++ {
+		tracker.Record("tenant", time.Second, 10*time.Millisecond, http.StatusOK)
+	}
+
+	report := tracker.Report()
+	if report.Kind != "SLOReport" || report.PeriodSeconds != 86400 {
+		t.Errorf("unexpected report header: %+v", report)
+	}
+	if len(report.Classes) != 1 {
+		t.Fatalf("expected 1 class, got %d", len(report.Classes))
+	}
+	tenant := report.Classes[0]
+
+	avail := tenant.Availability.Windows
+	if len(avail) != len(BurnWindows)+1 {
+		t.Fatalf("expected %d windows, got %d", len(BurnWindows)+1, len(avail))
+	}
+	// The 5m and 1h windows only see the good requests
+	for _, w := range avail[:2] {
+		if w.Total != 100 || w.Bad != 0 || w.Ratio != 1 || w.BurnRate != 0 {
+			t.Errorf("expected %ds window to be all good, got %+v", w.WindowSeconds, w)
+		}
+	}
+	// The 6h window and the period see both: 2 of 200 failed, a burn rate of 1
+	for _, w := range avail[2:] {
+		if w.Total != 200 || w.Bad != 2 || !approx(w.BurnRate, 1) {
+			t.Errorf("expected %ds window to burn at 1, got %+v", w.WindowSeconds, w)
+		}
+	}
+	if !approx(tenant.Availability.ErrorBudgetRemaining, 0) {
+		t.Errorf("expected availability budget spent, got %v", tenant.Availability.ErrorBudgetRemaining)
+	}
+
+	// 20 of 200 slow against a 10% budget, again a burn rate of 1
+	if tenant.Latency == nil {
+		t.Fatal("expected a latency SLI")
+	}
+	period := tenant.Latency.Windows[len(tenant.Latency.Windows)-1]
+	if period.Bad != 20 || !approx(period.Ratio, 0.9) {
+		t.Errorf("expected 20 slow requests, got %+v", period)
+	}
+	if tenant.LatencyThresholdSeconds != 1 {
+		t.Errorf("expected 1s threshold, got %v", tenant.LatencyThresholdSeconds)
+	}
+}
+
+func TestTracker_ForgetsOutsidePeriod(t *testing.T) {
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	tracker := newTestTracker(&now)
+
+	tracker.Record("authz", 0, time.Minute, http.StatusInternalServerError)
+	now = now.Add(25 * time.Hour)
+	tracker.Record("authz", 0, time.Millisecond, http.StatusOK)
+
+	c := tracker.Report().Classes[0]
+	if c.Latency != nil {
+		t.Errorf("expected no latency SLI without a threshold, got %+v", c.Latency)
+	}
+	for _, w := range c.Availability.Windows {
+		if w.Total != 1 || w.Bad != 0 {
+			t.Errorf("expected only the recent request in the %ds window, got %+v", w.WindowSeconds, w)
+		}
+	}
+	if c.Availability.ErrorBudgetRemaining != 1 {
+		t.Errorf("expected full budget, got %v", c.Availability.ErrorBudgetRemaining)
+	}
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
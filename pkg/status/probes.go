@@ -0,0 +1,67 @@
+package status
+
+import (
+	"context"
+	"errors"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/aws-sdk-go-v2/service/verifiedpermissions"
+	avptypes "github.com/aws/aws-sdk-go-v2/service/verifiedpermissions/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+)
+
+// probeKey is looked up by the DynamoDB and AVP probes; it never exists, so a
+// successful miss proves the service is reachable
+const probeKey = "rosa-status-probe"
+
+// MaestroProbe checks that the Maestro REST API answers a minimal consumer list
+func MaestroProbe(maestroClient maestro.ClientInterface) Probe {
+	return Probe{
+		Name:     "maestro",
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			_, err := maestroClient.ListConsumers(ctx, 1, 1)
+			return err
+		},
+	}
+}
+
+// DynamoDBProbe checks that the table keyed by keyAttribute can be read
+func DynamoDBProbe(name, tableName, keyAttribute string, dynamoClient client.DynamoDBClient) Probe {
+	return Probe{
+		Name:     name,
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			_, err := dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+				TableName: aws.String(tableName),
+				Key: map[string]types.AttributeValue{
+					keyAttribute: &types.AttributeValueMemberS{Value: probeKey},
+				},
+			})
+			return err
+		},
+	}
+}
+
+// AVPProbe checks that Amazon Verified Permissions answers a policy store
+// lookup; a not-found response counts as healthy
+func AVPProbe(avpClient client.AVPClient) Probe {
+	return Probe{
+		Name:     "avp",
+		Critical: true,
+		Check: func(ctx context.Context) error {
+			_, err := avpClient.GetPolicyStore(ctx, &verifiedpermissions.GetPolicyStoreInput{
+				PolicyStoreId: aws.String(probeKey),
+			})
+			var notFound *avptypes.ResourceNotFoundException
+			if errors.As(err, &notFound) {
+				return nil
+			}
+			return err
+		},
+	}
+}
@@ -0,0 +1,239 @@
+// Package status aggregates the health of the regional platform and its
+// dependencies for consumption by the global control plane's region picker.
+package status
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestroversion"
+)
+
+// Overall and per-dependency status values
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// Probe checks the reachability of one dependency
+type Probe struct {
+	Name string
+	// Critical probes mark the region unavailable when they fail; other
+	// failures only degrade it
+	Critical bool
+	Check    func(ctx context.Context) error
+}
+
+// Config holds the thresholds used to derive the overall status
+type Config struct {
+	// ProbeTimeout bounds each dependency check
+	ProbeTimeout time.Duration
+	// CacheTTL is how long a report is reused before dependencies are probed again
+	CacheTTL time.Duration
+	// ErrorRateThreshold is the fraction of 5xx responses above which the region is degraded
+	ErrorRateThreshold float64
+	// DeliveryLagThreshold is the p95 delivery lag above which the region is degraded
+	DeliveryLagThreshold time.Duration
+}
+
+// DependencyStatus is the result of one probe
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	Critical  bool   `json:"critical"`
+	LatencyMs int64  `json:"latency_ms"`
+}
+
+// DeliveryLag summarizes the time from ManifestWork creation to the agent
+// reporting it applied
+type DeliveryLag struct {
+	P95Seconds    float64 `json:"p95_seconds"`
+	Samples       int     `json:"samples"`
+	WindowSeconds int     `json:"window_seconds"`
+}
+
+// RequestStats summarizes API responses over the trailing window
+type RequestStats struct {
+	Total         int     `json:"total"`
+	Errors        int     `json:"errors"`
+	ErrorRate     float64 `json:"error_rate"`
+	WindowSeconds int     `json:"window_seconds"`
+}
+
+// Report is the aggregate status of the region
+type Report struct {
+	Kind         string             `json:"kind"`
+	Status       string             `json:"status"`
+	CheckedAt    time.Time          `json:"checked_at"`
+	Dependencies []DependencyStatus `json:"dependencies"`
+	DeliveryLag  *DeliveryLag       `json:"delivery_lag,omitempty"`
+	Requests     *RequestStats      `json:"requests,omitempty"`
+	// Maestro is the Maestro API version and capabilities last detected;
+	// omitted before the first successful check
+	Maestro *maestroversion.Capabilities `json:"maestro,omitempty"`
+}
+
+// Reporter probes dependencies and combines them with the request and
+// delivery lag windows into a Report
+type Reporter struct {
+	cfg         Config
+	probes      []Probe
+	requests    *Window
+	deliveryLag *Window
+	maestro     *maestroversion.Detector
+	logger      *slog.Logger
+
+	mu     sync.Mutex
+	cached *Report
+}
+
+// NewReporter creates a Reporter. requests and deliveryLag may be nil, in
+// which case the corresponding sections are omitted from the report.
+func NewReporter(cfg Config, probes []Probe, requests, deliveryLag *Window, logger *slog.Logger) *Reporter {
+	return &Reporter{
+		cfg:         cfg,
+		probes:      probes,
+		requests:    requests,
+		deliveryLag: deliveryLag,
+		logger:      logger,
+	}
+}
+
+// WithMaestroVersion reports the capabilities detected by maestro, and
+// degrades the region while Maestro does not serve the API version this
+// server calls
+func (r *Reporter) WithMaestroVersion(maestro *maestroversion.Detector) *Reporter {
+	r.maestro = maestro
+	return r
+}
+
+// Report returns the current aggregate status, probing dependencies at most
+// once per CacheTTL
+func (r *Reporter) Report(ctx context.Context) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.cached != nil && time.Since(r.cached.CheckedAt) < r.cfg.CacheTTL {
+		return r.cached
+	}
+
+	report := &Report{
+		Kind:         "RegionStatus",
+		Status:       StatusOK,
+		CheckedAt:    clock.Stamp(time.Now()),
+		Dependencies: r.probe(ctx),
+	}
+
+	for _, dep := range report.Dependencies {
+		if dep.Status == StatusOK {
+			continue
+		}
+		if dep.Critical {
+			report.Status = StatusUnavailable
+		} else if report.Status == StatusOK {
+			report.Status = StatusDegraded
+		}
+	}
+
+	if r.requests != nil {
+		stats := r.requests.Stats()
+		report.Requests = &RequestStats{
+			Total:         stats.Count,
+			Errors:        stats.Errors,
+			ErrorRate:     stats.ErrorRate(),
+			WindowSeconds: int(r.requests.Span().Seconds()),
+		}
+		if r.cfg.ErrorRateThreshold > 0 && stats.ErrorRate() > r.cfg.ErrorRateThreshold {
+			report.degrade()
+		}
+	}
+
+	if r.deliveryLag != nil {
+		stats := r.deliveryLag.Stats()
+		report.DeliveryLag = &DeliveryLag{
+			P95Seconds:    stats.P95.Seconds(),
+			Samples:       stats.Count,
+			WindowSeconds: int(r.deliveryLag.Span().Seconds()),
+		}
+		if r.cfg.DeliveryLagThreshold > 0 && stats.P95 > r.cfg.DeliveryLagThreshold {
+			report.degrade()
+		}
+	}
+
+	if r.maestro != nil {
+		report.Maestro = r.maestro.Capabilities()
+		if report.Maestro != nil && !report.Maestro.Compatible {
+			report.degrade()
+		}
+	}
+
+	r.cached = report
+	return report
+}
+
+// probe runs all probes concurrently, each bounded by ProbeTimeout
+func (r *Reporter) probe(ctx context.Context) []DependencyStatus {
+	results := make([]DependencyStatus, len(r.probes))
+
+	var wg sync.WaitGroup
+	for i, p := range r.probes {
+		wg.Add(1)
+		go func(i int, p Probe) {
+			defer wg.Done()
+
+			probeCtx := ctx
+			if r.cfg.ProbeTimeout > 0 {
+				var cancel context.CancelFunc
+				probeCtx, cancel = context.WithTimeout(ctx, r.cfg.ProbeTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+			err := p.Check(probeCtx)
+			result := DependencyStatus{
+				Name:      p.Name,
+				Status:    StatusOK,
+				Critical:  p.Critical,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				// Dependency errors are logged rather than returned to callers
+				r.logger.Warn("dependency probe failed", "dependency", p.Name, "error", err)
+				result.Status = StatusUnavailable
+			}
+			results[i] = result
+		}(i, p)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func (r *Report) degrade() {
+	if r.Status == StatusOK {
+		r.Status = StatusDegraded
+	}
+}
+
+// DeliveryLagOf returns the time from a ManifestWork's creation to its Applied
+// condition turning True, i.e. the time for the work to reach and be applied
+// by the agent over the transport
+func DeliveryLagOf(mw *workv1.ManifestWork) (time.Duration, bool) {
+	applied := meta.FindStatusCondition(mw.Status.Conditions, workv1.WorkApplied)
+	if applied == nil || applied.Status != metav1.ConditionTrue || mw.CreationTimestamp.IsZero() {
+		return 0, false
+	}
+	lag := applied.LastTransitionTime.Sub(mw.CreationTimestamp.Time)
+	if lag < 0 {
+		return 0, false
+	}
+	return lag, true
+}
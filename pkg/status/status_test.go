@@ -0,0 +1,187 @@
+package status
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/maestroversion"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func okProbe(name string, critical bool) Probe {
+	return Probe{Name: name, Critical: critical, Check: func(ctx context.Context) error { return nil }}
+}
+
+func failingProbe(name string, critical bool) Probe {
+	return Probe{Name: name, Critical: critical, Check: func(ctx context.Context) error { return errors.New("unreachable") }}
+}
+
+func TestWindow_Stats(t *testing.T) {
+	w := NewWindow(time.Minute)
+	for i := 1; i <= 100; i++ {
+		w.Record(time.Duration(i)*time.Millisecond, i%10 == 0)
+	}
+
+	stats := w.Stats()
+	assert.Equal(t, 100, stats.Count)
+	assert.Equal(t, 10, stats.Errors)
+	assert.InDelta(t, 0.1, stats.ErrorRate(), 0.0001)
+	assert.Equal(t, 95*time.Millisecond, stats.P95)
+}
+
+func TestWindow_PrunesOldSamples(t *testing.T) {
+	now := time.Now()
+	w := NewWindow(time.Minute)
+	w.now = func() time.Time { return now }
+
+	w.Record(time.Second, true)
+	now = now.Add(2 * time.Minute)
+	w.Record(2*time.Second, false)
+
+	stats := w.Stats()
+	assert.Equal(t, 1, stats.Count)
+	assert.Equal(t, 0, stats.Errors)
+	assert.Equal(t, 2*time.Second, stats.P95)
+}
+
+func TestReporter_Report(t *testing.T) {
+	tests := []struct {
+		name     string
+		probes   []Probe
+		errors   int
+		lag      time.Duration
+		expected string
+	}{
+		{
+			name:     "all healthy",
+			probes:   []Probe{okProbe("maestro", true), okProbe("dynamodb", true)},
+			expected: StatusOK,
+		},
+		{
+			name:     "critical dependency down",
+			probes:   []Probe{failingProbe("maestro", true), okProbe("dynamodb", true)},
+			expected: StatusUnavailable,
+		},
+		{
+			name:     "non-critical dependency down",
+			probes:   []Probe{okProbe("maestro", true), failingProbe("cache", false)},
+			expected: StatusDegraded,
+		},
+		{
+			name:     "error rate above threshold",
+			probes:   []Probe{okProbe("maestro", true)},
+			errors:   2,
+			expected: StatusDegraded,
+		},
+		{
+			name:     "delivery lag above threshold",
+			probes:   []Probe{okProbe("maestro", true)},
+			lag:      2 * time.Minute,
+			expected: StatusDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := NewWindow(time.Minute)
+			for i := 0; i < 10; i++ {
+				requests.Record(time.Millisecond, i < tt.errors)
+			}
+			lag := NewWindow(time.Minute)
+			lag.Record(tt.lag, false)
+
+			reporter := NewReporter(Config{
+				ErrorRateThreshold:   0.1,
+				DeliveryLagThreshold: time.Minute,
+			}, tt.probes, requests, lag, testLogger())
+
+			report := reporter.Report(context.Background())
+			assert.Equal(t, tt.expected, report.Status)
+			assert.Equal(t, "RegionStatus", report.Kind)
+			assert.Len(t, report.Dependencies, len(tt.probes))
+			require.NotNil(t, report.Requests)
+			assert.Equal(t, 10, report.Requests.Total)
+			require.NotNil(t, report.DeliveryLag)
+			assert.Equal(t, tt.lag.Seconds(), report.DeliveryLag.P95Seconds)
+		})
+	}
+}
+
+func TestReporter_CachesReport(t *testing.T) {
+	calls := 0
+	probe := Probe{Name: "maestro", Check: func(ctx context.Context) error {
+		calls++
+		return nil
+	}}
+
+	reporter := NewReporter(Config{CacheTTL: time.Minute}, []Probe{probe}, nil, nil, testLogger())
+	first := reporter.Report(context.Background())
+	second := reporter.Report(context.Background())
+
+	assert.Equal(t, 1, calls)
+	assert.Same(t, first, second)
+	assert.Nil(t, first.Requests)
+	assert.Nil(t, first.DeliveryLag)
+}
+
+type versionProber []string
+
+func (v versionProber) GetAPIVersions(ctx context.Context) ([]string, error) { return v, nil }
+
+func (v versionProber) SupportsSearchField(ctx context.Context, field string) (bool, error) {
+	return false, nil
+}
+
+func TestReporter_MaestroVersion(t *testing.T) {
+	for _, tt := range []struct {
+		versions versionProber
+		expected string
+	}{
+		{versions: versionProber{"v1"}, expected: StatusOK},
+		{versions: versionProber{"v2"}, expected: StatusDegraded},
+	} {
+		detector := maestroversion.New(tt.versions, time.Minute, testLogger())
+		reporter := NewReporter(Config{}, []Probe{okProbe("maestro", true)}, nil, nil, testLogger()).
+			WithMaestroVersion(detector)
+
+		// Nothing is reported before the first check
+		assert.Nil(t, reporter.Report(context.Background()).Maestro)
+
+		require.NoError(t, detector.Check(context.Background()))
+		report := reporter.Report(context.Background())
+		require.NotNil(t, report.Maestro)
+		assert.Equal(t, []string(tt.versions), report.Maestro.APIVersions)
+		assert.Equal(t, tt.expected, report.Status)
+	}
+}
+
+func TestDeliveryLagOf(t *testing.T) {
+	created := time.Now().Add(-time.Hour)
+	mw := &workv1.ManifestWork{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(created)},
+	}
+
+	_, ok := DeliveryLagOf(mw)
+	assert.False(t, ok)
+
+	mw.Status.Conditions = []metav1.Condition{{
+		Type:               workv1.WorkApplied,
+		Status:             metav1.ConditionTrue,
+		LastTransitionTime: metav1.NewTime(created.Add(3 * time.Second)),
+	}}
+	lag, ok := DeliveryLagOf(mw)
+	require.True(t, ok)
+	assert.Equal(t, 3*time.Second, lag)
+}
@@ -0,0 +1,101 @@
+package status
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxWindowSamples bounds the memory held by a Window under heavy load; once
+// reached, the oldest samples are dropped first
+const maxWindowSamples = 10000
+
+type sample struct {
+	at     time.Time
+	value  time.Duration
+	failed bool
+}
+
+// Window keeps the samples observed over a trailing time window
+type Window struct {
+	mu      sync.Mutex
+	span    time.Duration
+	samples []sample
+	now     func() time.Time
+}
+
+// WindowStats summarizes the samples currently in a Window
+type WindowStats struct {
+	Count  int
+	Errors int
+	P95    time.Duration
+}
+
+// ErrorRate returns the fraction of failed samples, or 0 when there are none
+func (s WindowStats) ErrorRate() float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	return float64(s.Errors) / float64(s.Count)
+}
+
+// NewWindow creates a Window covering the trailing span
+func NewWindow(span time.Duration) *Window {
+	return &Window{span: span, now: time.Now}
+}
+
+// Span returns the length of the trailing window
+func (w *Window) Span() time.Duration {
+	return w.span
+}
+
+// Record adds a sample with the given value and outcome
+func (w *Window) Record(value time.Duration, failed bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.prune()
+	if len(w.samples) >= maxWindowSamples {
+		w.samples = w.samples[1:]
+	}
+	w.samples = append(w.samples, sample{at: w.now(), value: value, failed: failed})
+}
+
+// Stats returns the count, error count and p95 value of the samples in the window
+func (w *Window) Stats() WindowStats {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.prune()
+	stats := WindowStats{Count: len(w.samples)}
+	if stats.Count == 0 {
+		return stats
+	}
+
+	values := make([]time.Duration, 0, len(w.samples))
+	for _, s := range w.samples {
+		if s.failed {
+			stats.Errors++
+		}
+		values = append(values, s.value)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	// Nearest-rank percentile
+	rank := (95*len(values) + 99) / 100
+	stats.P95 = values[rank-1]
+
+	return stats
+}
+
+// prune drops samples older than the window; callers must hold mu
+func (w *Window) prune() {
+	cutoff := w.now().Add(-w.span)
+	i := 0
+	for i < len(w.samples) && w.samples[i].at.Before(cutoff) {
+		i++
+	}
+	if i > 0 {
+		w.samples = append(w.samples[:0], w.samples[i:]...)
+	}
+}
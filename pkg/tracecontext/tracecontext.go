@@ -0,0 +1,109 @@
+// Package tracecontext carries a request's W3C Trace Context and propagates
+// it to the calls the request makes to Maestro and AWS, so a trace started by
+// the global control plane continues through the regional API instead of
+// starting new roots in every service behind it.
+package tracecontext
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"google.golang.org/grpc/metadata"
+)
+
+// W3C Trace Context headers, which are also the gRPC metadata keys
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+type contextKey struct{}
+
+// TraceContext is the trace position of a request
+type TraceContext struct {
+	// TraceParent is the request's span in version 00 traceparent format
+	TraceParent string
+	// TraceState is the vendor trace state passed on unchanged, or ""
+	TraceState string
+}
+
+// WithTraceContext returns a context carrying tc
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, contextKey{}, tc)
+}
+
+// FromContext returns the trace context of ctx and whether it has one
+func FromContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(contextKey{}).(TraceContext)
+	return tc, ok && tc.TraceParent != ""
+}
+
+// Inject sets the trace context headers of ctx on h. It is a no-op when ctx
+// carries no trace context, e.g. for background work.
+func Inject(ctx context.Context, h http.Header) {
+	tc, ok := FromContext(ctx)
+	if !ok {
+		return
+	}
+	h.Set(HeaderTraceParent, tc.TraceParent)
+	if tc.TraceState != "" {
+		h.Set(HeaderTraceState, tc.TraceState)
+	} else {
+		h.Del(HeaderTraceState)
+	}
+}
+
+// Transport wraps base so every HTTP request carries the trace context of its
+// context. A nil base uses http.DefaultTransport.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if _, ok := FromContext(req.Context()); ok {
+			// RoundTrippers must not modify the request
+			req = req.Clone(req.Context())
+			Inject(req.Context(), req.Header)
+		}
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// OutgoingGRPC returns a context whose gRPC calls send the trace context of
+// ctx as metadata
+func OutgoingGRPC(ctx context.Context) context.Context {
+	tc, ok := FromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	pairs := []string{HeaderTraceParent, tc.TraceParent}
+	if tc.TraceState != "" {
+		pairs = append(pairs, HeaderTraceState, tc.TraceState)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// AWSMiddleware returns an AWS SDK API option that sends the trace context of
+// each operation's context with every attempt. Add it to a service client's
+// Options.APIOptions, or to aws.Config.APIOptions for every client of a
+// config.
+func AWSMiddleware() func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		// Added at build, before the request is signed
+		return stack.Build.Add(middleware.BuildMiddlewareFunc("TraceContext",
+			func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+				if req, ok := in.Request.(*smithyhttp.Request); ok {
+					Inject(ctx, req.Header)
+				}
+				return next.HandleBuild(ctx, in)
+			}), middleware.After)
+	}
+}
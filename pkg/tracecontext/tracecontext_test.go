@@ -0,0 +1,84 @@
+package tracecontext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"google.golang.org/grpc/metadata"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func traced() context.Context {
+	return WithTraceContext(context.Background(), TraceContext{TraceParent: testTraceParent, TraceState: "rojo=00f067aa0ba902b7"})
+}
+
+func TestTransport(t *testing.T) {
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+	client := &http.Client{Transport: Transport(nil)}
+
+	req, _ := http.NewRequestWithContext(traced(), http.MethodGet, srv.URL, nil)
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if got.Get(HeaderTraceParent) != testTraceParent || got.Get(HeaderTraceState) != "rojo=00f067aa0ba902b7" {
+		t.Errorf("expected the trace context headers, got %v", got)
+	}
+	if req.Header.Get(HeaderTraceParent) != "" {
+		t.Error("expected the caller's request to be left unmodified")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err = client.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if got.Get(HeaderTraceParent) != "" {
+		t.Errorf("expected no traceparent without a trace context, got %q", got.Get(HeaderTraceParent))
+	}
+}
+
+func TestOutgoingGRPC(t *testing.T) {
+	md, _ := metadata.FromOutgoingContext(OutgoingGRPC(traced()))
+	if v := md.Get(HeaderTraceParent); len(v) != 1 || v[0] != testTraceParent {
+		t.Errorf("expected traceparent metadata, got %v", md)
+	}
+	if v := md.Get(HeaderTraceState); len(v) != 1 || v[0] != "rojo=00f067aa0ba902b7" {
+		t.Errorf("expected tracestate metadata, got %v", md)
+	}
+
+	ctx := context.Background()
+	if OutgoingGRPC(ctx) != ctx {
+		t.Error("expected the context unchanged without a trace context")
+	}
+}
+
+func TestAWSMiddleware(t *testing.T) {
+	stack := middleware.NewStack("test", smithyhttp.NewStackRequest)
+	if err := AWSMiddleware()(stack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got http.Header
+	handler := middleware.DecorateHandler(middleware.HandlerFunc(func(ctx context.Context, in interface{}) (interface{}, middleware.Metadata, error) {
+		got = in.(*smithyhttp.Request).Header
+		return nil, middleware.Metadata{}, nil
+	}), stack)
+	if _, _, err := handler.Handle(traced(), struct{}{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Get(HeaderTraceParent) != testTraceParent {
+		t.Errorf("expected the traceparent on the AWS request, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const apiGolden = "testdata/api.golden"
+
+// apiTypes are the types whose JSON shape is the stable API
+var apiTypes = []any{
+	Cluster{},
+	ClusterCreateRequest{},
+	ClusterUpdateRequest{},
+	ClusterStatusInfo{},
+	Condition{},
+	ClusterControllerStatus{},
+	ClusterStatusResponse{},
+	NodePool{},
+	NodePoolCreateRequest{},
+	NodePoolUpdateRequest{},
+	NodePoolSpec{},
+	NodePoolStatusInfo{},
+	NodePoolControllerStatus{},
+	NodePoolStatusResponse{},
+}
+
+// describeAPI lists the JSON fields of the API types, one "Type.field goType"
+// line each
+func describeAPI() []string {
+	var lines []string
+	for _, v := range apiTypes {
+		t := reflect.TypeOf(v)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("%s.%s %s", t.Name(), tag, f.Type))
+		}
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestAPICompatibility(t *testing.T) {
+	got := describeAPI()
+	if os.Getenv("UPDATE_GOLDEN") != "" {
+		if err := os.WriteFile(apiGolden, []byte(strings.Join(got, "\n")+"\n"), 0o644); err != nil {
+			t.Fatalf("failed to update %s: %v", apiGolden, err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(apiGolden)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", apiGolden, err)
+	}
+	current := make(map[string]bool, len(got))
+	for _, line := range got {
+		current[line] = true
+	}
+	recorded := make(map[string]bool)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		recorded[line] = true
+		if !current[line] {
+			t.Errorf("incompatible API change: %s was removed or changed", line)
+		}
+	}
+	for _, line := range got {
+		if !recorded[line] {
+			t.Errorf("%s is not recorded in %s; run the test with UPDATE_GOLDEN=1", line, apiGolden)
+		}
+	}
+}
@@ -0,0 +1,6 @@
+// Package types holds the cluster and nodepool resources exchanged with the
+// API. It is the module's stable surface: its JSON shape is the wire format
+// the console and CLI depend on, so fields are only ever added. A field
+// renamed or removed fails TestAPICompatibility; a field added is recorded
+// by regenerating testdata/api.golden with UPDATE_GOLDEN=1.
+package types
@@ -0,0 +1,132 @@
+// Package upstream accumulates the time a request spends in calls to the
+// services behind the API (Maestro, Amazon Verified Permissions, DynamoDB) so
+// slow requests can be attributed to the dependency that made them slow.
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/aws/smithy-go/middleware"
+)
+
+// Upstream names recorded in Timings
+const (
+	Maestro  = "maestro"
+	AVP      = "avp"
+	DynamoDB = "dynamodb"
+
+	// Authz is the budget group for the AVP and DynamoDB calls made to
+	// authorize a request
+	Authz = "authz"
+)
+
+type (
+	contextKey struct{}
+	budgetKey  struct{}
+)
+
+// Timings holds the cumulative duration and number of calls per upstream for
+// one request. It is safe for concurrent use, as fan-out handlers call
+// upstreams from several goroutines.
+type Timings struct {
+	mu    sync.Mutex
+	total map[string]time.Duration
+	calls map[string]int
+}
+
+// WithTimings returns a context that accumulates upstream call durations
+func WithTimings(ctx context.Context) (context.Context, *Timings) {
+	t := &Timings{total: make(map[string]time.Duration), calls: make(map[string]int)}
+	return context.WithValue(ctx, contextKey{}, t), t
+}
+
+// Observe adds a call of duration d to the named upstream. It is a no-op when
+// ctx carries no Timings, e.g. for background work.
+func Observe(ctx context.Context, name string, d time.Duration) {
+	t, ok := ctx.Value(contextKey{}).(*Timings)
+	if !ok {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total[name] += d
+	t.calls[name]++
+}
+
+// Track starts timing a call to the named upstream; call the returned
+// function when the call completes
+func Track(ctx context.Context, name string) func() {
+	start := time.Now()
+	return func() { Observe(ctx, name, time.Since(start)) }
+}
+
+// Total returns the cumulative duration and call count for the named upstream
+func (t *Timings) Total(name string) (time.Duration, int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.total[name], t.calls[name]
+}
+
+// Transport wraps base so every HTTP round trip is recorded against the named
+// upstream. A nil base uses http.DefaultTransport.
+func Transport(name string, base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		defer Track(req.Context(), name)()
+		return base.RoundTrip(req)
+	})
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// AWSMiddleware returns an AWS SDK API option that records each operation,
+// including retries, against the named upstream. Add it to a service client's
+// Options.APIOptions.
+func AWSMiddleware(name string) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc("UpstreamTiming",
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				defer Track(ctx, name)()
+				return next.HandleInitialize(ctx, in)
+			}), middleware.Before)
+	}
+}
+
+// WithBudget bounds ctx by total and reserves a share of it for the named
+// upstream groups: calls made under WithDeadline(ctx, name) must finish
+// within limits[name] of now, so a slow group cannot consume the whole
+// request and the remainder of total is left to the other upstreams. A
+// non-positive total leaves ctx without an overall deadline.
+func WithBudget(ctx context.Context, total time.Duration, limits map[string]time.Duration) (context.Context, context.CancelFunc) {
+	now := time.Now()
+	deadlines := make(map[string]time.Time, len(limits))
+	for name, limit := range limits {
+		if limit > 0 {
+			deadlines[name] = now.Add(limit)
+		}
+	}
+	ctx = context.WithValue(ctx, budgetKey{}, deadlines)
+	if total <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, total)
+}
+
+// WithDeadline returns a context bounded by the named group's share of the
+// request budget. Without a budget for name, ctx keeps its own deadline.
+func WithDeadline(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	deadlines, _ := ctx.Value(budgetKey{}).(map[string]time.Time)
+	if deadline, ok := deadlines[name]; ok {
+		return context.WithDeadline(ctx, deadline)
+	}
+	return context.WithCancel(ctx)
+}
@@ -0,0 +1,104 @@
+package upstream
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObserve_AccumulatesPerUpstream(t *testing.T) {
+	ctx, timings := WithTimings(context.Background())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Observe(ctx, Maestro, time.Millisecond)
+		}()
+	}
+	wg.Wait()
+	Observe(ctx, AVP, 4*time.Millisecond)
+
+	d, calls := timings.Total(Maestro)
+	assert.Equal(t, 10*time.Millisecond, d)
+	assert.Equal(t, 10, calls)
+
+	d, calls = timings.Total(AVP)
+	assert.Equal(t, 4*time.Millisecond, d)
+	assert.Equal(t, 1, calls)
+
+	d, calls = timings.Total(DynamoDB)
+	assert.Zero(t, d)
+	assert.Zero(t, calls)
+}
+
+func TestObserve_WithoutTimings(t *testing.T) {
+	assert.NotPanics(t, func() {
+		Observe(context.Background(), Maestro, time.Second)
+		Track(context.Background(), AVP)()
+	})
+}
+
+func TestTransport_RecordsRoundTrips(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: Transport(Maestro, nil)}
+	ctx, timings := WithTimings(context.Background())
+
+	for i := 0; i < 2; i++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+	}
+
+	d, calls := timings.Total(Maestro)
+	assert.Equal(t, 2, calls)
+	assert.GreaterOrEqual(t, d, 10*time.Millisecond)
+}
+
+func TestWithBudget_SplitsDeadline(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), time.Minute, map[string]time.Duration{Authz: time.Second})
+	defer cancel()
+
+	total, ok := ctx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), total, time.Second)
+
+	authzCtx, authzCancel := WithDeadline(ctx, Authz)
+	defer authzCancel()
+	deadline, ok := authzCtx.Deadline()
+	require.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Second), deadline, 500*time.Millisecond)
+
+	// Upstreams without a share get the remainder of the request budget
+	maestroCtx, maestroCancel := WithDeadline(ctx, Maestro)
+	defer maestroCancel()
+	deadline, ok = maestroCtx.Deadline()
+	require.True(t, ok)
+	assert.Equal(t, total, deadline)
+}
+
+func TestWithBudget_NoTotal(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), 0, map[string]time.Duration{Authz: 0})
+	defer cancel()
+
+	_, ok := ctx.Deadline()
+	assert.False(t, ok)
+
+	authzCtx, authzCancel := WithDeadline(ctx, Authz)
+	defer authzCancel()
+	_, ok = authzCtx.Deadline()
+	assert.False(t, ok)
+}
@@ -0,0 +1,195 @@
+// Package workchart renders Helm charts pulled from allow-listed OCI
+// registries into manifests for a ManifestWork. Charts are rendered like
+// `helm template`: client-side, with default capabilities and without hooks,
+// since the manifests are applied by the work agent rather than by Helm.
+package workchart
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/registry"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// OCIScheme prefixes chart repositories
+const OCIScheme = "oci://"
+
+var (
+	// ErrInvalidChart is returned for malformed chart references, values the
+	// chart rejects and charts that fail to render
+	ErrInvalidChart = errors.New("invalid chart")
+	// ErrRegistryNotAllowed is returned for charts outside the allowed registries
+	ErrRegistryNotAllowed = errors.New("chart registry not allowed")
+	// ErrChartTooLarge is returned for chart archives over the size limit
+	ErrChartTooLarge = errors.New("chart too large")
+)
+
+// Chart identifies a chart and the values to render it with
+type Chart struct {
+	// Repository is the chart's OCI reference without a tag, such as
+	// oci://quay.io/example/charts/app
+	Repository string
+	Version    string
+	Values     map[string]interface{}
+	// ReleaseName is the release the chart is rendered as; empty uses the
+	// chart's name
+	ReleaseName string
+	Namespace   string
+}
+
+// Puller fetches a chart archive by its reference (host/path:version)
+type Puller interface {
+	Pull(ctx context.Context, ref string) ([]byte, error)
+}
+
+// Config configures chart rendering
+type Config struct {
+	// Registries are the registry hosts (host or host:port) charts may be
+	// pulled from
+	Registries []string
+	// MaxChartBytes caps the size of a chart archive; 0 disables the limit
+	MaxChartBytes int
+}
+
+// Renderer renders charts into manifests
+type Renderer struct {
+	puller        Puller
+	registries    map[string]bool
+	maxChartBytes int
+	logger        *slog.Logger
+}
+
+// NewRenderer creates a new Renderer
+func NewRenderer(puller Puller, cfg Config, logger *slog.Logger) *Renderer {
+	registries := make(map[string]bool, len(cfg.Registries))
+	for _, host := range cfg.Registries {
+		if host = strings.TrimSpace(host); host != "" {
+			registries[host] = true
+		}
+	}
+	return &Renderer{
+		puller:        puller,
+		registries:    registries,
+		maxChartBytes: cfg.MaxChartBytes,
+		logger:        logger,
+	}
+}
+
+// Render pulls chart and renders it, returning its CRDs and templates as a
+// YAML stream in install order. Hooks and NOTES.txt are left out.
+func (r *Renderer) Render(ctx context.Context, chart Chart) (string, error) {
+	ref, err := r.reference(chart)
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := r.puller.Pull(ctx, ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to pull chart %s: %w", ref, err)
+	}
+	if r.maxChartBytes > 0 && len(archive) > r.maxChartBytes {
+		return "", fmt.Errorf("%w: chart %s is %d bytes, exceeding the limit of %d", ErrChartTooLarge, ref, len(archive), r.maxChartBytes)
+	}
+
+	ch, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	if ch.Metadata.Type == "library" {
+		return "", fmt.Errorf("%w: %s is a library chart", ErrInvalidChart, ch.Name())
+	}
+
+	releaseName := chart.ReleaseName
+	if releaseName == "" {
+		releaseName = ch.Name()
+	}
+	values, err := chartutil.ToRenderValues(ch, chart.Values, chartutil.ReleaseOptions{
+		Name:      releaseName,
+		Namespace: chart.Namespace,
+		Revision:  1,
+		IsInstall: true,
+	}, chartutil.DefaultCapabilities)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	files, err := engine.Render(ch, values)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	for name := range files {
+		if strings.HasSuffix(name, "NOTES.txt") {
+			delete(files, name)
+		}
+	}
+	hooks, manifests, err := releaseutil.SortManifests(files, chartutil.DefaultCapabilities.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidChart, err)
+	}
+	if len(hooks) > 0 {
+		r.logger.Info("left chart hooks out of rendered manifests", "chart", ref, "hooks", len(hooks))
+	}
+
+	var out strings.Builder
+	for _, crd := range ch.CRDObjects() {
+		fmt.Fprintf(&out, "---\n%s\n", crd.File.Data)
+	}
+	for _, m := range manifests {
+		fmt.Fprintf(&out, "---\n%s\n", m.Content)
+	}
+	return out.String(), nil
+}
+
+// reference returns chart's pull reference once its registry is allowed
+func (r *Renderer) reference(chart Chart) (string, error) {
+	if !strings.HasPrefix(chart.Repository, OCIScheme) {
+		return "", fmt.Errorf("%w: repository must be an %s reference", ErrInvalidChart, OCIScheme)
+	}
+	repository := strings.TrimPrefix(chart.Repository, OCIScheme)
+	host, path, _ := strings.Cut(repository, "/")
+	if host == "" || path == "" || strings.ContainsAny(path, ":@") {
+		return "", fmt.Errorf("%w: repository must be %shost/path without a tag or digest", ErrInvalidChart, OCIScheme)
+	}
+	if chart.Version == "" {
+		return "", fmt.Errorf("%w: version is required", ErrInvalidChart)
+	}
+	if !r.registries[host] {
+		return "", fmt.Errorf("%w: %s", ErrRegistryNotAllowed, host)
+	}
+	return repository + ":" + chart.Version, nil
+}
+
+// RegistryPuller pulls charts with the Helm registry client, using the
+// registry credentials of the server's Docker config
+type RegistryPuller struct {
+	client *registry.Client
+}
+
+// NewRegistryPuller creates a RegistryPuller whose registry requests time
+// out after timeout
+func NewRegistryPuller(timeout time.Duration) (*RegistryPuller, error) {
+	client, err := registry.NewClient(registry.ClientOptHTTPClient(&http.Client{Timeout: timeout}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create chart registry client: %w", err)
+	}
+	return &RegistryPuller{client: client}, nil
+}
+
+// Pull fetches the chart archive at ref. The Helm registry client does not
+// take a context, so the request is bounded by the client timeout instead.
+func (p *RegistryPuller) Pull(ctx context.Context, ref string) ([]byte, error) {
+	result, err := p.client.Pull(ref)
+	if err != nil {
+		return nil, err
+	}
+	return result.Chart.Data, nil
+}
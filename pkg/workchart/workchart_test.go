@@ -0,0 +1,142 @@
+package workchart
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+// chartArchive packages files under the chart directory "app" like helm package
+func chartArchive(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: "app/" + name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			t.Fatalf("failed to write chart archive: %v", err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatalf("failed to write chart archive: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to write chart archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("failed to write chart archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// fakePuller serves one archive and records the references pulled
+type fakePuller struct {
+	archive []byte
+	refs    []string
+}
+
+func (p *fakePuller) Pull(ctx context.Context, ref string) ([]byte, error) {
+	p.refs = append(p.refs, ref)
+	return p.archive, nil
+}
+
+var appChart = map[string]string{
+	"Chart.yaml":  "apiVersion: v2\nname: app\nversion: 1.2.3\n",
+	"values.yaml": "replicas: 1\n",
+	"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}-config
+  namespace: {{ .Release.Namespace }}
+data:
+  replicas: "{{ .Values.replicas }}"
+`,
+	"templates/namespace.yaml": "apiVersion: v1\nkind: Namespace\nmetadata:\n  name: {{ .Release.Namespace }}\n",
+	"templates/hook.yaml": `apiVersion: batch/v1
+kind: Job
+metadata:
+  name: migrate
+  annotations:
+    helm.sh/hook: pre-install
+`,
+	"templates/NOTES.txt": "Installed {{ .Release.Name }}\n",
+	"crds/widget.yaml":    "apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: widgets.example.com\n",
+}
+
+func TestRenderer_Render(t *testing.T) {
+	puller := &fakePuller{archive: chartArchive(t, appChart)}
+	renderer := NewRenderer(puller, Config{Registries: []string{"quay.io"}}, testLogger())
+
+	out, err := renderer.Render(context.Background(), Chart{
+		Repository:  "oci://quay.io/example/charts/app",
+		Version:     "1.2.3",
+		Values:      map[string]interface{}{"replicas": 3},
+		ReleaseName: "team-a",
+		Namespace:   "team-a-ns",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(puller.refs) != 1 || puller.refs[0] != "quay.io/example/charts/app:1.2.3" {
+		t.Errorf("expected the chart to be pulled by tag, got %v", puller.refs)
+	}
+	for _, want := range []string{"name: team-a-config", "replicas: \"3\"", "namespace: team-a-ns", "widgets.example.com"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the rendered manifests to contain %q, got:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"kind: Job", "Installed"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("expected hooks and notes to be left out, got:\n%s", out)
+		}
+	}
+	// CRDs first, then namespaces before the resources in them
+	crd, ns, cm := strings.Index(out, "CustomResourceDefinition"), strings.Index(out, "kind: Namespace"), strings.Index(out, "kind: ConfigMap")
+	if !(crd < ns && ns < cm) {
+		t.Errorf("expected manifests in install order, got:\n%s", out)
+	}
+}
+
+func TestRenderer_Render_Rejects(t *testing.T) {
+	library := map[string]string{"Chart.yaml": "apiVersion: v2\nname: lib\nversion: 0.1.0\ntype: library\n"}
+	broken := map[string]string{
+		"Chart.yaml":           "apiVersion: v2\nname: app\nversion: 0.1.0\n",
+		"templates/broken.yml": "{{ .Values.missing.field }}\n",
+	}
+
+	tests := []struct {
+		name     string
+		chart    Chart
+		files    map[string]string
+		maxBytes int
+		wantErr  error
+	}{
+		{name: "registry not allowed", chart: Chart{Repository: "oci://docker.io/example/app", Version: "1.0.0"}, files: appChart, wantErr: ErrRegistryNotAllowed},
+		{name: "not an OCI reference", chart: Chart{Repository: "https://charts.example.com/app", Version: "1.0.0"}, files: appChart, wantErr: ErrInvalidChart},
+		{name: "tag in repository", chart: Chart{Repository: "oci://quay.io/example/app:1.0.0", Version: "1.0.0"}, files: appChart, wantErr: ErrInvalidChart},
+		{name: "missing version", chart: Chart{Repository: "oci://quay.io/example/app"}, files: appChart, wantErr: ErrInvalidChart},
+		{name: "too large", chart: Chart{Repository: "oci://quay.io/example/app", Version: "1.0.0"}, files: appChart, maxBytes: 10, wantErr: ErrChartTooLarge},
+		{name: "library chart", chart: Chart{Repository: "oci://quay.io/example/lib", Version: "0.1.0"}, files: library, wantErr: ErrInvalidChart},
+		{name: "render error", chart: Chart{Repository: "oci://quay.io/example/app", Version: "0.1.0"}, files: broken, wantErr: ErrInvalidChart},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			puller := &fakePuller{archive: chartArchive(t, tt.files)}
+			renderer := NewRenderer(puller, Config{Registries: []string{"quay.io"}, MaxChartBytes: tt.maxBytes}, testLogger())
+			if _, err := renderer.Render(context.Background(), tt.chart); !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
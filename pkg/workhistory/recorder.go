@@ -0,0 +1,211 @@
+package workhistory
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+// bundleFields are the only resource bundle fields the recorder reads
+const bundleFields = "id,name,consumer_name,status"
+
+var recordedTransitions = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "rosa_work_history_transitions_total",
+	Help: "Work condition transitions recorded in the status history, by condition type",
+}, []string{"type"})
+
+// BundleLister lists Maestro resource bundles
+type BundleLister interface {
+	ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error)
+}
+
+// WorkLookup finds the metadata recorded for a submitted work
+type WorkLookup interface {
+	Get(ctx context.Context, clusterID, workName string) (*workmeta.Record, error)
+}
+
+// owned is what the recorder remembers of a resource bundle
+type owned struct {
+	record *workmeta.Record // nil when the API did not submit the bundle
+	// conditions holds the transition key last recorded per condition type
+	conditions map[string]string
+}
+
+// Recorder polls Maestro and records the condition transitions of the works
+// the work metadata store knows the API submitted
+type Recorder struct {
+	lister    BundleLister
+	works     WorkLookup
+	store     Store
+	interval  time.Duration
+	retention time.Duration
+	pageSize  int
+	logger    *slog.Logger
+	now       func() time.Time
+
+	mu      sync.Mutex
+	bundles map[string]*owned
+}
+
+// NewRecorder creates a new recorder polling every interval and keeping
+// transitions for retention; a retention of 0 keeps them forever
+func NewRecorder(lister BundleLister, works WorkLookup, store Store, interval, retention time.Duration, logger *slog.Logger) *Recorder {
+	return &Recorder{
+		lister:    lister,
+		works:     works,
+		store:     store,
+		interval:  interval,
+		retention: retention,
+		pageSize:  400,
+		logger:    logger,
+		now:       time.Now,
+		bundles:   map[string]*owned{},
+	}
+}
+
+// Run polls immediately and then on every interval until ctx is done
+func (r *Recorder) Run(ctx context.Context) {
+	r.logger.Info("work history recorder started", "interval", r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Poll(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("work history poll failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info("work history recorder stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll reads every resource bundle and records the condition changes of
+// the owned ones since the last poll. Owned bundles that are gone from
+// Maestro are recorded as deleted.
+func (r *Recorder) Poll(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now().UTC()
+	seen := map[string]bool{}
+	scanned := 0
+	for page := 1; ; page++ {
+		list, err := r.lister.ListResourceBundles(ctx, page, r.pageSize, "", "", bundleFields)
+		if err != nil {
+			return err
+		}
+		for _, bundle := range list.Items {
+			seen[bundle.ID] = true
+			if err := r.observe(ctx, bundle, now); err != nil {
+				r.logger.Warn("failed to record work transitions", "error", err, "work_id", bundle.ID, "cluster_id", bundle.ConsumerName)
+			}
+		}
+		scanned += len(list.Items)
+		if len(list.Items) == 0 || scanned >= list.Total {
+			break
+		}
+	}
+
+	// Only a complete scan tells which bundles are gone
+	for id, b := range r.bundles {
+		if seen[id] {
+			continue
+		}
+		if b.record != nil {
+			at := clock.Format(now)
+			if err := r.record(ctx, b.record, ConditionDeleted, "True", "ResourceBundleDeleted", "", at, now); err != nil {
+				r.logger.Warn("failed to record work deletion", "error", err, "work_id", id)
+				continue
+			}
+		}
+		delete(r.bundles, id)
+	}
+	return nil
+}
+
+// observe records the conditions of bundle that changed since it was last
+// seen
+func (r *Recorder) observe(ctx context.Context, bundle maestro.ResourceBundle, now time.Time) error {
+	b, ok := r.bundles[bundle.ID]
+	if !ok {
+		rec, err := r.works.Get(ctx, bundle.ConsumerName, bundle.Name)
+		if err != nil {
+			return err
+		}
+		if rec != nil && rec.WorkID != bundle.ID {
+			rec = nil
+		}
+		b = &owned{record: rec, conditions: map[string]string{}}
+		r.bundles[bundle.ID] = b
+	}
+	if b.record == nil {
+		return nil
+	}
+
+	items, _ := bundle.Status["conditions"].([]interface{})
+	for _, item := range items {
+		c, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _ := c["type"].(string)
+		status, _ := c["status"].(string)
+		reason, _ := c["reason"].(string)
+		message, _ := c["message"].(string)
+		at, _ := c["lastTransitionTime"].(string)
+		if condType == "" {
+			continue
+		}
+		// Transitions are compared as strings, so every time is kept as an
+		// API timestamp
+		if t, err := clock.Parse(at); err == nil {
+			at = clock.Format(t)
+		} else {
+			at = clock.Format(now)
+		}
+		if b.conditions[condType] == TransitionKey(at, condType, status) {
+			continue
+		}
+		if err := r.record(ctx, b.record, condType, status, reason, message, at, now); err != nil {
+			return err
+		}
+		b.conditions[condType] = TransitionKey(at, condType, status)
+	}
+	return nil
+}
+
+func (r *Recorder) record(ctx context.Context, rec *workmeta.Record, condType, status, reason, message, at string, now time.Time) error {
+	t := &Transition{
+		WorkID:          rec.WorkID,
+		Key:             TransitionKey(at, condType, status),
+		ClusterID:       rec.ClusterID,
+		WorkName:        rec.WorkName,
+		TenantAccountID: rec.TenantAccountID,
+		Type:            condType,
+		Status:          status,
+		Reason:          reason,
+		Message:         message,
+		TransitionedAt:  at,
+		ObservedAt:      clock.Format(now),
+	}
+	if r.retention > 0 {
+		t.ExpiresAt = now.Add(r.retention).Unix()
+	}
+	if err := r.store.Record(ctx, t); err != nil {
+		return err
+	}
+	recordedTransitions.WithLabelValues(condType).Inc()
+	return nil
+}
@@ -0,0 +1,208 @@
+package workhistory
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/workmeta"
+)
+
+type fakeLister struct {
+	bundles []maestro.ResourceBundle
+}
+
+func (f *fakeLister) ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
+	start := (page - 1) * size
+	end := min(start+size, len(f.bundles))
+	if start > end {
+		start = end
+	}
+	return &maestro.ResourceBundleList{Page: page, Size: size, Total: len(f.bundles), Items: f.bundles[start:end]}, nil
+}
+
+type fakeLookup struct {
+	records map[string]*workmeta.Record
+	lookups int
+}
+
+func (f *fakeLookup) Get(ctx context.Context, clusterID, workName string) (*workmeta.Record, error) {
+	f.lookups++
+	return f.records[clusterID+"/"+workName], nil
+}
+
+// memoryStore keeps transitions in memory and ignores repeats like
+// DynamoStore
+type memoryStore struct {
+	transitions []*Transition
+}
+
+func (m *memoryStore) Record(ctx context.Context, t *Transition) error {
+	for _, existing := range m.transitions {
+		if existing.WorkID == t.WorkID && existing.Key == t.Key {
+			return nil
+		}
+	}
+	m.transitions = append(m.transitions, t)
+	return nil
+}
+
+func (m *memoryStore) List(ctx context.Context, workID string) ([]*Transition, error) {
+	var out []*Transition
+	for _, t := range m.transitions {
+		if t.WorkID == workID {
+			out = append(out, t)
+		}
+	}
+	return out, nil
+}
+
+func (m *memoryStore) Purge(ctx context.Context, workID string) (int, error) {
+	kept := m.transitions[:0]
+	for _, t := range m.transitions {
+		if t.WorkID != workID {
+			kept = append(kept, t)
+		}
+	}
+	deleted := len(m.transitions) - len(kept)
+	m.transitions = kept
+	return deleted, nil
+}
+
+func bundle(id, name, applied, at string) maestro.ResourceBundle {
+	return maestro.ResourceBundle{
+		ID:           id,
+		Name:         name,
+		ConsumerName: "mc-a",
+		Status: map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Applied", "status": applied, "reason": "AppliedManifestWorkComplete", "lastTransitionTime": at},
+			},
+		},
+	}
+}
+
+func TestRecorder_Poll(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	lister := &fakeLister{bundles: []maestro.ResourceBundle{
+		bundle("uid-1", "web", "False", "2026-03-01T11:00:00Z"),
+		bundle("uid-2", "unowned", "True", "2026-03-01T11:00:00Z"),
+	}}
+	lookup := &fakeLookup{records: map[string]*workmeta.Record{
+		"mc-a/web": {ClusterID: "mc-a", WorkName: "web", WorkID: "uid-1", TenantAccountID: "111111111111"},
+	}}
+	store := &memoryStore{}
+	recorder := NewRecorder(lister, lookup, store, time.Minute, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	recorder.pageSize = 1
+	recorder.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if err := recorder.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(store.transitions) != 1 {
+		t.Fatalf("expected one transition for the owned bundle, got %d", len(store.transitions))
+	}
+	first := store.transitions[0]
+	if first.WorkID != "uid-1" || first.TenantAccountID != "111111111111" || first.Status != "False" {
+		t.Errorf("unexpected transition %+v", first)
+	}
+	if first.ExpiresAt != now.Add(time.Hour).Unix() {
+		t.Errorf("expected the transition to expire after the retention, got %d", first.ExpiresAt)
+	}
+
+	// An unchanged status records nothing and the ownership is not looked
+	// up again
+	if err := recorder.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(store.transitions) != 1 || lookup.lookups != 2 {
+		t.Fatalf("expected no new transition or lookup, got %d transitions and %d lookups", len(store.transitions), lookup.lookups)
+	}
+
+	lister.bundles[0] = bundle("uid-1", "web", "True", "2026-03-01T11:30:00Z")
+	if err := recorder.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(store.transitions) != 2 || store.transitions[1].Status != "True" {
+		t.Fatalf("expected the Applied change to be recorded, got %+v", store.transitions)
+	}
+
+	lister.bundles = lister.bundles[1:]
+	if err := recorder.Poll(ctx); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(store.transitions) != 3 || store.transitions[2].Type != ConditionDeleted {
+		t.Fatalf("expected the deletion to be recorded, got %+v", store.transitions)
+	}
+	if _, ok := recorder.bundles["uid-1"]; ok {
+		t.Error("expected the deleted bundle to be forgotten")
+	}
+}
+
+func TestRecorder_Poll_RecreatedWork(t *testing.T) {
+	// A bundle whose name matches a work recorded with another ID was not
+	// submitted through the API
+	lister := &fakeLister{bundles: []maestro.ResourceBundle{bundle("uid-other", "web", "True", "2026-03-01T11:00:00Z")}}
+	lookup := &fakeLookup{records: map[string]*workmeta.Record{
+		"mc-a/web": {ClusterID: "mc-a", WorkName: "web", WorkID: "uid-1"},
+	}}
+	store := &memoryStore{}
+	recorder := NewRecorder(lister, lookup, store, time.Minute, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	if err := recorder.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(store.transitions) != 0 {
+		t.Errorf("expected nothing recorded, got %+v", store.transitions)
+	}
+}
+
+func TestRecorder_Poll_NormalizesTimes(t *testing.T) {
+	now := time.Date(2026, 3, 1, 13, 0, 0, 500, time.FixedZone("CET", 60*60))
+	lister := &fakeLister{bundles: []maestro.ResourceBundle{bundle("uid-1", "web", "True", "2026-03-01T12:30:00+01:00")}}
+	lookup := &fakeLookup{records: map[string]*workmeta.Record{
+		"mc-a/web": {ClusterID: "mc-a", WorkName: "web", WorkID: "uid-1"},
+	}}
+	store := &memoryStore{}
+	recorder := NewRecorder(lister, lookup, store, time.Minute, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	recorder.now = func() time.Time { return now }
+
+	if err := recorder.Poll(context.Background()); err != nil {
+		t.Fatalf("Poll() error = %v", err)
+	}
+	if len(store.transitions) != 1 {
+		t.Fatalf("expected one transition, got %d", len(store.transitions))
+	}
+	got := store.transitions[0]
+	if got.TransitionedAt != "2026-03-01T11:30:00Z" || got.ObservedAt != "2026-03-01T12:00:00Z" {
+		t.Errorf("expected UTC timestamps, got transitioned %s and observed %s", got.TransitionedAt, got.ObservedAt)
+	}
+}
+
+func TestAt(t *testing.T) {
+	transitions := []*Transition{
+		{Type: "Applied", Status: "False", TransitionedAt: "2026-03-01T10:00:00Z"},
+		{Type: "Available", Status: "False", TransitionedAt: "2026-03-01T10:05:00Z"},
+		{Type: "Applied", Status: "True", TransitionedAt: "2026-03-01T11:00:00Z"},
+		{Type: "Available", Status: "True", TransitionedAt: "2026-03-01T12:00:00Z"},
+	}
+
+	got := At(transitions, "2026-03-01T11:30:00Z")
+	if len(got) != 2 {
+		t.Fatalf("expected both conditions, got %+v", got)
+	}
+	if got[0].Type != "Applied" || got[0].Status != "True" {
+		t.Errorf("expected Applied to be True, got %+v", got[0])
+	}
+	if got[1].Type != "Available" || got[1].Status != "False" {
+		t.Errorf("expected Available to be False, got %+v", got[1])
+	}
+
+	if got := At(transitions, "2026-03-01T09:00:00Z"); len(got) != 0 {
+		t.Errorf("expected no conditions before the first transition, got %+v", got)
+	}
+}
@@ -0,0 +1,156 @@
+// Package workhistory records when the conditions of works submitted through
+// the work API changed, so incident reviews can tell exactly when a payload
+// was applied, degraded and recovered. Maestro only keeps the latest status.
+package workhistory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+)
+
+// ConditionDeleted is the condition type recorded when a work's resource
+// bundle is no longer in Maestro
+const ConditionDeleted = "Deleted"
+
+// Transition is a condition of a work taking a new status
+type Transition struct {
+	WorkID string `dynamodbav:"workId" json:"-"`
+	// Key orders a work's transitions; a transition recorded again, from
+	// another replica or after a restart, keeps the first record
+	Key       string `dynamodbav:"transitionKey" json:"-"`
+	ClusterID string `dynamodbav:"clusterId" json:"-"`
+	WorkName  string `dynamodbav:"workName" json:"-"`
+	// TenantAccountID is the account the work belongs to
+	TenantAccountID string `dynamodbav:"tenantAccountId" json:"-"`
+	Type            string `dynamodbav:"type" json:"type"`
+	Status          string `dynamodbav:"status" json:"status"`
+	Reason          string `dynamodbav:"reason,omitempty" json:"reason,omitempty"`
+	Message         string `dynamodbav:"message,omitempty" json:"message,omitempty"`
+	// TransitionedAt is when the condition changed, as the agent reported it
+	TransitionedAt string `dynamodbav:"transitionedAt" json:"transitioned_at"`
+	// ObservedAt is when the API first saw the change
+	ObservedAt string `dynamodbav:"observedAt" json:"observed_at"`
+	ExpiresAt  int64  `dynamodbav:"expiresAt,omitempty" json:"-"`
+}
+
+// TransitionKey returns the sort key of a transition
+func TransitionKey(transitionedAt, condType, status string) string {
+	return transitionedAt + "#" + condType + "#" + status
+}
+
+// Store persists work condition transitions
+type Store interface {
+	Record(ctx context.Context, t *Transition) error
+	// List returns a work's transitions, oldest first
+	List(ctx context.Context, workID string) ([]*Transition, error)
+	// Purge deletes a work's transitions and returns how many it deleted
+	Purge(ctx context.Context, workID string) (int, error)
+}
+
+// DynamoStore implements Store backed by DynamoDB
+type DynamoStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+}
+
+// NewDynamoStore creates a new DynamoDB-backed work history store
+func NewDynamoStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DynamoStore {
+	return &DynamoStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+	}
+}
+
+// Record stores a transition, unless it was recorded already
+func (s *DynamoStore) Record(ctx context.Context, t *Transition) error {
+	item, err := attributevalue.MarshalMap(t)
+	if err != nil {
+		return fmt.Errorf("failed to marshal work transition: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName:           aws.String(s.tableName),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_not_exists(transitionKey)"),
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to put work transition: %w", err)
+	}
+
+	return nil
+}
+
+// List returns a work's transitions, oldest first
+func (s *DynamoStore) List(ctx context.Context, workID string) ([]*Transition, error) {
+	var transitions []*Transition
+	paginator := dynamodb.NewQueryPaginator(s.dynamoClient, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		KeyConditionExpression: aws.String("workId = :work"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":work": &types.AttributeValueMemberS{Value: workID},
+		},
+		ScanIndexForward: aws.Bool(true),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query work transitions: %w", err)
+		}
+		var items []*Transition
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal work transitions: %w", err)
+		}
+		transitions = append(transitions, items...)
+	}
+	return transitions, nil
+}
+
+// Purge deletes a work's transitions
+func (s *DynamoStore) Purge(ctx context.Context, workID string) (int, error) {
+	return purge.DeletePartition(ctx, s.dynamoClient, purge.Partition{
+		Table:         s.tableName,
+		PartitionKey:  "workId",
+		KeyAttributes: []string{"workId", "transitionKey"},
+	}, workID)
+}
+
+// At returns the latest transition of each condition type at or before at,
+// an RFC 3339 time, in the order the types first appear in transitions
+func At(transitions []*Transition, at string) []*Transition {
+	latest := map[string]*Transition{}
+	var order []string
+	for _, t := range transitions {
+		if t.TransitionedAt > at {
+			continue
+		}
+		prev, ok := latest[t.Type]
+		if !ok {
+			order = append(order, t.Type)
+		}
+		if !ok || t.TransitionedAt >= prev.TransitionedAt {
+			latest[t.Type] = t
+		}
+	}
+
+	out := make([]*Transition, 0, len(order))
+	for _, condType := range order {
+		out = append(out, latest[condType])
+	}
+	return out
+}
@@ -0,0 +1,249 @@
+// Package workmeta records metadata about ManifestWorks submitted through the
+// work API, independently of Maestro, so the API can answer questions such as
+// "who submitted this work" or "has this exact work already been submitted".
+package workmeta
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+)
+
+// dedupIndexName is the GSI keyed on dedupKey
+// (tenantAccountId#clusterId#contentHash)
+const dedupIndexName = "dedup-index"
+
+// tenantIndexName is the GSI keyed on tenantAccountId and sorted by createdAt
+const tenantIndexName = "tenant-index"
+
+// Record is the metadata stored for each submitted ManifestWork
+type Record struct {
+	ClusterID string `dynamodbav:"clusterId" json:"clusterId"`
+	WorkName  string `dynamodbav:"workName" json:"workName"`
+	WorkID    string `dynamodbav:"workId" json:"workId"`
+	AccountID string `dynamodbav:"accountId" json:"accountId"`
+	CallerARN string `dynamodbav:"callerArn" json:"callerArn"`
+	// OnBehalfOfAccount is the tenant account a privileged account submitted
+	// the work for, if any
+	OnBehalfOfAccount string `dynamodbav:"onBehalfOfAccount,omitempty" json:"onBehalfOfAccount,omitempty"`
+	// TenantAccountID is the account the work belongs to: OnBehalfOfAccount
+	// if set, AccountID otherwise
+	TenantAccountID string `dynamodbav:"tenantAccountId" json:"-"`
+	ContentHash     string `dynamodbav:"contentHash" json:"contentHash"`
+	// Checksum is the SHA-256 of the data payload the caller sent, if any
+	Checksum  string `dynamodbav:"checksum,omitempty" json:"checksum,omitempty"`
+	DedupKey  string `dynamodbav:"dedupKey" json:"-"`
+	CreatedAt string `dynamodbav:"createdAt" json:"createdAt"`
+}
+
+// Store persists work metadata
+type Store interface {
+	Put(ctx context.Context, rec *Record) error
+	Get(ctx context.Context, clusterID, workName string) (*Record, error)
+	// FindByContentHash returns the tenant's most recent work on the cluster
+	// with the content hash; other tenants' identical works never match
+	FindByContentHash(ctx context.Context, tenantAccountID, clusterID, contentHash string) (*Record, error)
+	// ListByTenant returns the records of the works belonging to an account,
+	// including those submitted on its behalf, newest first
+	ListByTenant(ctx context.Context, accountID string) ([]*Record, error)
+	Delete(ctx context.Context, clusterID, workName string) error
+}
+
+// ContentHash returns the SHA-256 of the normalized ManifestWork spec.
+// Manifests are re-encoded so formatting and key order do not affect the hash.
+func ContentHash(mw *workv1.ManifestWork) (string, error) {
+	spec := mw.Spec.DeepCopy()
+	for i := range spec.Workload.Manifests {
+		manifest := &spec.Workload.Manifests[i]
+		if manifest.Raw == nil {
+			continue
+		}
+		var obj interface{}
+		if err := json.Unmarshal(manifest.Raw, &obj); err != nil {
+			return "", fmt.Errorf("failed to normalize manifest %d: %w", i, err)
+		}
+		normalized, err := json.Marshal(obj)
+		if err != nil {
+			return "", fmt.Errorf("failed to normalize manifest %d: %w", i, err)
+		}
+		manifest.Raw = normalized
+	}
+
+	b, err := json.Marshal(spec)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal manifestwork spec: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// dedupKey scopes deduplication to one tenant, so an identical spec
+// submitted by two accounts to a shared cluster yields two works
+func dedupKey(tenantAccountID, clusterID, contentHash string) string {
+	return tenantAccountID + "#" + clusterID + "#" + contentHash
+}
+
+// DynamoStore implements Store backed by DynamoDB
+type DynamoStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewDynamoStore creates a new DynamoDB-backed work metadata store
+func NewDynamoStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DynamoStore {
+	return &DynamoStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (s *DynamoStore) WithClock(now clock.Clock) *DynamoStore {
+	s.now = clock.Or(now)
+	return s
+}
+
+// Put stores a work metadata record
+func (s *DynamoStore) Put(ctx context.Context, rec *Record) error {
+	if rec.CreatedAt == "" {
+		rec.CreatedAt = clock.Format(s.now())
+	}
+	rec.TenantAccountID = rec.AccountID
+	if rec.OnBehalfOfAccount != "" {
+		rec.TenantAccountID = rec.OnBehalfOfAccount
+	}
+	if rec.ContentHash != "" {
+		rec.DedupKey = dedupKey(rec.TenantAccountID, rec.ClusterID, rec.ContentHash)
+	}
+
+	item, err := attributevalue.MarshalMap(rec)
+	if err != nil {
+		return fmt.Errorf("failed to marshal work metadata: %w", err)
+	}
+
+	_, err = s.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(s.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put work metadata: %w", err)
+	}
+
+	return nil
+}
+
+// Get retrieves a work metadata record, returning nil if none exists
+func (s *DynamoStore) Get(ctx context.Context, clusterID, workName string) (*Record, error) {
+	result, err := s.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       recordKey(clusterID, workName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work metadata: %w", err)
+	}
+
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var rec Record
+	if err := attributevalue.UnmarshalMap(result.Item, &rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal work metadata: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// FindByContentHash returns the most recent record of the tenant for the
+// cluster with the given content hash, or nil if none exists
+func (s *DynamoStore) FindByContentHash(ctx context.Context, tenantAccountID, clusterID, contentHash string) (*Record, error) {
+	result, err := s.dynamoClient.Query(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String(dedupIndexName),
+		KeyConditionExpression: aws.String("dedupKey = :dk"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":dk": &types.AttributeValueMemberS{Value: dedupKey(tenantAccountID, clusterID, contentHash)},
+		},
+		ScanIndexForward: aws.Bool(false),
+		Limit:            aws.Int32(1),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query work metadata by content hash: %w", err)
+	}
+
+	if len(result.Items) == 0 {
+		return nil, nil
+	}
+
+	var rec Record
+	if err := attributevalue.UnmarshalMap(result.Items[0], &rec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal work metadata: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// ListByTenant returns the records of the works belonging to accountID,
+// newest first
+func (s *DynamoStore) ListByTenant(ctx context.Context, accountID string) ([]*Record, error) {
+	var records []*Record
+	paginator := dynamodb.NewQueryPaginator(s.dynamoClient, &dynamodb.QueryInput{
+		TableName:              aws.String(s.tableName),
+		IndexName:              aws.String(tenantIndexName),
+		KeyConditionExpression: aws.String("tenantAccountId = :tenant"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":tenant": &types.AttributeValueMemberS{Value: accountID},
+		},
+		ScanIndexForward: aws.Bool(false),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query work metadata by tenant: %w", err)
+		}
+		var items []*Record
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal work metadata: %w", err)
+		}
+		records = append(records, items...)
+	}
+	return records, nil
+}
+
+// Delete removes a work metadata record
+func (s *DynamoStore) Delete(ctx context.Context, clusterID, workName string) error {
+	_, err := s.dynamoClient.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(s.tableName),
+		Key:       recordKey(clusterID, workName),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete work metadata: %w", err)
+	}
+
+	return nil
+}
+
+func recordKey(clusterID, workName string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"clusterId": &types.AttributeValueMemberS{Value: clusterID},
+		"workName":  &types.AttributeValueMemberS{Value: workName},
+	}
+}
@@ -0,0 +1,196 @@
+package workmeta
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	workv1 "open-cluster-management.io/api/work/v1"
+)
+
+type mockDynamoClient struct {
+	putItemFunc func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	getItemFunc func(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	queryFunc   func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+func (m *mockDynamoClient) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	if m.putItemFunc != nil {
+		return m.putItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	if m.getItemFunc != nil {
+		return m.getItemFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.GetItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	if m.queryFunc != nil {
+		return m.queryFunc(ctx, params, optFns...)
+	}
+	return &dynamodb.QueryOutput{}, nil
+}
+
+func (m *mockDynamoClient) Scan(ctx context.Context, params *dynamodb.ScanInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ScanOutput, error) {
+	return &dynamodb.ScanOutput{}, nil
+}
+
+func (m *mockDynamoClient) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func (m *mockDynamoClient) TransactWriteItems(ctx context.Context, params *dynamodb.TransactWriteItemsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.TransactWriteItemsOutput, error) {
+	return &dynamodb.TransactWriteItemsOutput{}, nil
+}
+
+func (m *mockDynamoClient) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+}
+
+func manifestWorkFromJSON(t *testing.T, manifests ...string) *workv1.ManifestWork {
+	t.Helper()
+	mw := &workv1.ManifestWork{}
+	for _, m := range manifests {
+		mw.Spec.Workload.Manifests = append(mw.Spec.Workload.Manifests, workv1.Manifest{RawExtension: runtime.RawExtension{Raw: []byte(m)}})
+	}
+	return mw
+}
+
+func TestContentHash_Normalizes(t *testing.T) {
+	a := manifestWorkFromJSON(t, `{"kind":"ConfigMap","apiVersion":"v1","data":{"a":"1","b":"2"}}`)
+	b := manifestWorkFromJSON(t, `{ "apiVersion": "v1", "data": {"b": "2", "a": "1"}, "kind": "ConfigMap" }`)
+	c := manifestWorkFromJSON(t, `{"kind":"ConfigMap","apiVersion":"v1","data":{"a":"1","b":"3"}}`)
+
+	hashA, err := ContentHash(a)
+	require.NoError(t, err)
+	hashB, err := ContentHash(b)
+	require.NoError(t, err)
+	hashC, err := ContentHash(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, hashA, hashB)
+	assert.NotEqual(t, hashA, hashC)
+	assert.Len(t, hashA, 64)
+}
+
+func TestDynamoStore_Put(t *testing.T) {
+	var capturedInput *dynamodb.PutItemInput
+	client := &mockDynamoClient{
+		putItemFunc: func(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+			capturedInput = params
+			return &dynamodb.PutItemOutput{}, nil
+		},
+	}
+
+	store := NewDynamoStore("test-table", client, testLogger())
+
+	rec := &Record{ClusterID: "c1", WorkName: "w1", AccountID: "111111111111", ContentHash: "abc"}
+	require.NoError(t, store.Put(context.Background(), rec))
+	assert.Equal(t, "test-table", *capturedInput.TableName)
+	assert.Equal(t, "111111111111#c1#abc", rec.DedupKey)
+
+	delegated := &Record{ClusterID: "c1", WorkName: "w2", AccountID: "111111111111", OnBehalfOfAccount: "222222222222", ContentHash: "abc"}
+	require.NoError(t, store.Put(context.Background(), delegated))
+	assert.Equal(t, "222222222222#c1#abc", delegated.DedupKey)
+	assert.NotEmpty(t, rec.CreatedAt)
+}
+
+func TestDynamoStore_Put_TenantAccount(t *testing.T) {
+	store := NewDynamoStore("test-table", &mockDynamoClient{}, testLogger())
+
+	own := &Record{ClusterID: "c1", WorkName: "w1", AccountID: "111111111111"}
+	require.NoError(t, store.Put(context.Background(), own))
+	assert.Equal(t, "111111111111", own.TenantAccountID)
+
+	delegated := &Record{ClusterID: "c1", WorkName: "w2", AccountID: "111111111111", OnBehalfOfAccount: "222222222222"}
+	require.NoError(t, store.Put(context.Background(), delegated))
+	assert.Equal(t, "222222222222", delegated.TenantAccountID)
+}
+
+func TestDynamoStore_ListByTenant(t *testing.T) {
+	var capturedInput *dynamodb.QueryInput
+	client := &mockDynamoClient{
+		queryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			capturedInput = params
+			return &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"clusterId":         &types.AttributeValueMemberS{Value: "c1"},
+						"workName":          &types.AttributeValueMemberS{Value: "w2"},
+						"accountId":         &types.AttributeValueMemberS{Value: "111111111111"},
+						"onBehalfOfAccount": &types.AttributeValueMemberS{Value: "222222222222"},
+					},
+					{
+						"clusterId": &types.AttributeValueMemberS{Value: "c1"},
+						"workName":  &types.AttributeValueMemberS{Value: "w1"},
+						"accountId": &types.AttributeValueMemberS{Value: "222222222222"},
+					},
+				},
+			}, nil
+		},
+	}
+
+	store := NewDynamoStore("test-table", client, testLogger())
+
+	records, err := store.ListByTenant(context.Background(), "222222222222")
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "111111111111", records[0].AccountID)
+	assert.Equal(t, "222222222222", records[0].OnBehalfOfAccount)
+	assert.Equal(t, tenantIndexName, *capturedInput.IndexName)
+	assert.False(t, *capturedInput.ScanIndexForward)
+	assert.Equal(t, "222222222222", capturedInput.ExpressionAttributeValues[":tenant"].(*types.AttributeValueMemberS).Value)
+}
+
+func TestDynamoStore_FindByContentHash(t *testing.T) {
+	var capturedInput *dynamodb.QueryInput
+	client := &mockDynamoClient{
+		queryFunc: func(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+			capturedInput = params
+			return &dynamodb.QueryOutput{
+				Items: []map[string]types.AttributeValue{
+					{
+						"clusterId":   &types.AttributeValueMemberS{Value: "c1"},
+						"workName":    &types.AttributeValueMemberS{Value: "w1"},
+						"contentHash": &types.AttributeValueMemberS{Value: "abc"},
+					},
+				},
+			}, nil
+		},
+	}
+
+	store := NewDynamoStore("test-table", client, testLogger())
+
+	rec, err := store.FindByContentHash(context.Background(), "111111111111", "c1", "abc")
+	require.NoError(t, err)
+	require.NotNil(t, rec)
+	assert.Equal(t, "w1", rec.WorkName)
+	assert.Equal(t, dedupIndexName, *capturedInput.IndexName)
+	assert.Equal(t, "111111111111#c1#abc", capturedInput.ExpressionAttributeValues[":dk"].(*types.AttributeValueMemberS).Value)
+}
+
+func TestDynamoStore_FindByContentHash_NotFound(t *testing.T) {
+	store := NewDynamoStore("test-table", &mockDynamoClient{}, testLogger())
+
+	rec, err := store.FindByContentHash(context.Background(), "111111111111", "c1", "abc")
+	require.NoError(t, err)
+	assert.Nil(t, rec)
+}
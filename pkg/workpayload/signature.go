@@ -0,0 +1,105 @@
+package workpayload
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/root"
+	"github.com/sigstore/sigstore-go/pkg/verify"
+)
+
+var (
+	// ErrSignatureRequired is returned for OCI references without a signature
+	// when signatures are required
+	ErrSignatureRequired = errors.New("payload signature required")
+	// ErrSignatureInvalid is returned for signatures that do not verify or
+	// are not from an allowed signer
+	ErrSignatureInvalid = errors.New("payload signature invalid")
+)
+
+// Identity is a keyless signer payloads may be signed by: the OIDC issuer of
+// the signing certificate and a regular expression its subject (the
+// certificate's SAN, such as a workflow URI or email address) must match
+type Identity struct {
+	Issuer        string
+	SubjectRegexp string
+}
+
+// ParseIdentity parses an identity written as issuer=subject-regexp
+func ParseIdentity(s string) (Identity, error) {
+	issuer, subject, ok := strings.Cut(s, "=")
+	if !ok || issuer == "" || subject == "" {
+		return Identity{}, fmt.Errorf("signer %q must be issuer=subject-regexp", s)
+	}
+	return Identity{Issuer: issuer, SubjectRegexp: subject}, nil
+}
+
+// Signer identifies who signed a payload
+type Signer struct {
+	Issuer  string
+	Subject string
+}
+
+// SignatureVerifier verifies that a Sigstore bundle signs a payload digest
+type SignatureVerifier interface {
+	Verify(bundleJSON []byte, digest string) (*Signer, error)
+}
+
+// SigstoreVerifier verifies cosign signatures (cosign sign-blob
+// --new-bundle-format) against a Sigstore trusted root, requiring a
+// transparency log entry and a certificate from one of the allowed signers
+type SigstoreVerifier struct {
+	verifier   *verify.Verifier
+	identities []verify.PolicyOption
+}
+
+// NewSigstoreVerifier creates a SigstoreVerifier accepting signatures by
+// identities, whose certificates chain to trustedRoot
+func NewSigstoreVerifier(trustedRoot root.TrustedMaterial, identities []Identity) (*SigstoreVerifier, error) {
+	if len(identities) == 0 {
+		return nil, errors.New("at least one payload signer is required")
+	}
+	verifier, err := verify.NewVerifier(trustedRoot, verify.WithTransparencyLog(1), verify.WithObserverTimestamps(1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create signature verifier: %w", err)
+	}
+	v := &SigstoreVerifier{verifier: verifier}
+	for _, id := range identities {
+		certID, err := verify.NewShortCertificateIdentity(id.Issuer, "", "", id.SubjectRegexp)
+		if err != nil {
+			return nil, fmt.Errorf("invalid payload signer %s=%s: %w", id.Issuer, id.SubjectRegexp, err)
+		}
+		v.identities = append(v.identities, verify.WithCertificateIdentity(certID))
+	}
+	return v, nil
+}
+
+// Verify verifies bundleJSON signs digest
+func (v *SigstoreVerifier) Verify(bundleJSON []byte, digest string) (*Signer, error) {
+	var b bundle.Bundle
+	if err := b.UnmarshalJSON(bundleJSON); err != nil {
+		return nil, fmt.Errorf("%w: malformed bundle: %v", ErrSignatureInvalid, err)
+	}
+	return v.verify(&b, digest)
+}
+
+func (v *SigstoreVerifier) verify(entity verify.SignedEntity, digest string) (*Signer, error) {
+	sum, err := hex.DecodeString(strings.TrimPrefix(digest, "sha256:"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRef, err)
+	}
+	policy := verify.NewPolicy(verify.WithArtifactDigest("sha256", sum), v.identities...)
+	result, err := v.verifier.Verify(entity, policy)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSignatureInvalid, err)
+	}
+	signer := &Signer{}
+	if result.Signature != nil && result.Signature.Certificate != nil {
+		signer.Issuer = result.Signature.Certificate.Issuer
+		signer.Subject = result.Signature.Certificate.SubjectAlternativeName
+	}
+	return signer, nil
+}
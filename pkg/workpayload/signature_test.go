@@ -0,0 +1,95 @@
+package workpayload
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sigstore/sigstore-go/pkg/testing/ca"
+)
+
+const (
+	testIssuer  = "https://token.actions.githubusercontent.com"
+	testSubject = "https://github.com/example/bundles/.github/workflows/release.yml@refs/heads/main"
+)
+
+func TestSigstoreVerifier_Verify(t *testing.T) {
+	const payload = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"
+	sigstore, err := ca.NewVirtualSigstore()
+	if err != nil {
+		t.Fatalf("failed to create virtual sigstore: %v", err)
+	}
+	verifier, err := NewSigstoreVerifier(sigstore, []Identity{{Issuer: testIssuer, SubjectRegexp: `^https://github\.com/example/bundles/`}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	signed, err := sigstore.Sign(testSubject, testIssuer, []byte(payload))
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+	signer, err := verifier.verify(signed, digestOf(payload))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signer.Issuer != testIssuer || signer.Subject != testSubject {
+		t.Errorf("unexpected signer %+v", signer)
+	}
+
+	if _, err := verifier.verify(signed, digestOf(payload+"# tampered\n")); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected a signature over another digest to be rejected, got %v", err)
+	}
+
+	other, err := sigstore.Sign("https://github.com/attacker/bundles/.github/workflows/release.yml@refs/heads/main", testIssuer, []byte(payload))
+	if err != nil {
+		t.Fatalf("failed to sign payload: %v", err)
+	}
+	if _, err := verifier.verify(other, digestOf(payload)); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected a signature from another signer to be rejected, got %v", err)
+	}
+
+	if _, err := verifier.Verify([]byte(`{"mediaType": "bogus"}`), digestOf(payload)); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected a malformed bundle to be rejected, got %v", err)
+	}
+}
+
+// fakeVerifier accepts one bundle
+type fakeVerifier struct {
+	bundle string
+}
+
+func (f fakeVerifier) Verify(bundleJSON []byte, digest string) (*Signer, error) {
+	if string(bundleJSON) != f.bundle {
+		return nil, ErrSignatureInvalid
+	}
+	return &Signer{Issuer: testIssuer, Subject: testSubject}, nil
+}
+
+func TestFetcher_Fetch_Signatures(t *testing.T) {
+	const payload = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"
+	oci := &fakeOCI{blobs: map[string]string{"quay.io/example/bundles@" + digestOf(payload): payload}}
+	s3Client := &fakeS3{objects: map[string]string{"bundles/app.yaml": payload}}
+	fetcher := NewFetcher(oci, s3Client, Config{
+		Registries: []string{"quay.io"},
+		Buckets:    []string{"bundles"},
+		Signatures: fakeVerifier{bundle: "signed"},
+	}, testLogger())
+
+	got, err := fetcher.Fetch(context.Background(), Ref{URI: "oci://quay.io/example/bundles", Digest: digestOf(payload), SignatureBundle: []byte("signed")})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Signer == nil || got.Signer.Subject != testSubject {
+		t.Errorf("expected the signer to be returned, got %+v", got.Signer)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), Ref{URI: "oci://quay.io/example/bundles", Digest: digestOf(payload)}); !errors.Is(err, ErrSignatureRequired) {
+		t.Errorf("expected an unsigned OCI payload to be rejected, got %v", err)
+	}
+	if _, err := fetcher.Fetch(context.Background(), Ref{URI: "oci://quay.io/example/bundles", Digest: digestOf(payload), SignatureBundle: []byte("forged")}); !errors.Is(err, ErrSignatureInvalid) {
+		t.Errorf("expected an invalid signature to be rejected, got %v", err)
+	}
+	if got, err := fetcher.Fetch(context.Background(), Ref{URI: "s3://bundles/app.yaml", Digest: digestOf(payload)}); err != nil || got.Signer != nil {
+		t.Errorf("expected an unsigned S3 payload to be fetched, got %+v, %v", got, err)
+	}
+}
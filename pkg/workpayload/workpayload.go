@@ -0,0 +1,240 @@
+// Package workpayload fetches work payloads stored as OCI artifacts or S3
+// objects, so bundles too large for an API Gateway request body can be
+// submitted by reference. References are pinned by the SHA-256 digest of the
+// payload, which is verified before the payload is used.
+package workpayload
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/credentials"
+)
+
+// Reference schemes
+const (
+	SchemeOCI = "oci://"
+	SchemeS3  = "s3://"
+)
+
+var (
+	// ErrInvalidRef is returned for malformed references and digests
+	ErrInvalidRef = errors.New("invalid payload reference")
+	// ErrSourceNotAllowed is returned for registries and buckets that are not allowed
+	ErrSourceNotAllowed = errors.New("payload source not allowed")
+	// ErrTooLarge is returned for payloads over the size limit
+	ErrTooLarge = errors.New("payload too large")
+	// ErrDigestMismatch is returned when the fetched payload does not match its digest
+	ErrDigestMismatch = errors.New("payload digest mismatch")
+)
+
+var digestPattern = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// Ref points at a payload: a blob of an OCI repository
+// (oci://host/repository) or an S3 object (s3://bucket/key)
+type Ref struct {
+	URI string
+	// Digest is the payload's sha256:<hex> digest
+	Digest string
+	// SignatureBundle is a Sigstore bundle signing Digest
+	SignatureBundle []byte
+}
+
+// Payload is a fetched payload and, when its signature was verified, its signer
+type Payload struct {
+	Data   []byte
+	Signer *Signer
+}
+
+// OCIClient fetches blobs by digest from OCI repositories
+type OCIClient interface {
+	// FetchBlob returns the blob and its size; repository is host/path
+	FetchBlob(ctx context.Context, repository, digest string) (io.ReadCloser, int64, error)
+}
+
+// S3Client provides the S3 operations used to fetch payloads
+type S3Client interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+}
+
+// Config configures where payloads may be fetched from
+type Config struct {
+	// Registries are the OCI registry hosts (host or host:port) payloads may
+	// be fetched from
+	Registries []string
+	// Buckets are the S3 buckets payloads may be fetched from
+	Buckets []string
+	// MaxBytes caps the size of a payload; 0 disables the limit
+	MaxBytes int64
+	// Signatures, when set, requires OCI payloads to be signed by an allowed
+	// signer
+	Signatures SignatureVerifier
+}
+
+// Fetcher fetches payloads by reference. Either client may be nil, which
+// disables references of that scheme.
+type Fetcher struct {
+	oci        OCIClient
+	s3         S3Client
+	registries map[string]bool
+	buckets    map[string]bool
+	maxBytes   int64
+	signatures SignatureVerifier
+	logger     *slog.Logger
+}
+
+// NewFetcher creates a new Fetcher
+func NewFetcher(oci OCIClient, s3Client S3Client, cfg Config, logger *slog.Logger) *Fetcher {
+	return &Fetcher{
+		oci:        oci,
+		s3:         s3Client,
+		registries: toSet(cfg.Registries),
+		buckets:    toSet(cfg.Buckets),
+		maxBytes:   cfg.MaxBytes,
+		signatures: cfg.Signatures,
+		logger:     logger,
+	}
+}
+
+func toSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// Fetch returns the payload ref points at once its size and digest check
+// out. A signature is verified before the payload is fetched, since it signs
+// the digest the payload must match.
+func (f *Fetcher) Fetch(ctx context.Context, ref Ref) (*Payload, error) {
+	if !digestPattern.MatchString(ref.Digest) {
+		return nil, fmt.Errorf("%w: digest must be sha256:<64 lowercase hex digits>", ErrInvalidRef)
+	}
+
+	var (
+		signer *Signer
+		body   io.ReadCloser
+		size   int64
+		err    error
+	)
+	if len(ref.SignatureBundle) > 0 || (f.signatures != nil && strings.HasPrefix(ref.URI, SchemeOCI)) {
+		if signer, err = f.verifySignature(ref); err != nil {
+			return nil, err
+		}
+	}
+	switch {
+	case strings.HasPrefix(ref.URI, SchemeOCI) && f.oci != nil:
+		repository := strings.TrimPrefix(ref.URI, SchemeOCI)
+		host, path, _ := strings.Cut(repository, "/")
+		if host == "" || path == "" || strings.ContainsAny(path, ":@") {
+			return nil, fmt.Errorf("%w: uri must be %shost/repository without a tag or digest", ErrInvalidRef, SchemeOCI)
+		}
+		if !f.registries[host] {
+			return nil, fmt.Errorf("%w: registry %s", ErrSourceNotAllowed, host)
+		}
+		body, size, err = f.oci.FetchBlob(ctx, repository, ref.Digest)
+	case strings.HasPrefix(ref.URI, SchemeS3) && f.s3 != nil:
+		bucket, key, _ := strings.Cut(strings.TrimPrefix(ref.URI, SchemeS3), "/")
+		if bucket == "" || key == "" {
+			return nil, fmt.Errorf("%w: uri must be %sbucket/key", ErrInvalidRef, SchemeS3)
+		}
+		if !f.buckets[bucket] {
+			return nil, fmt.Errorf("%w: bucket %s", ErrSourceNotAllowed, bucket)
+		}
+		var out *s3.GetObjectOutput
+		out, err = f.s3.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+		if err == nil {
+			body, size = out.Body, aws.ToInt64(out.ContentLength)
+		}
+	default:
+		return nil, fmt.Errorf("%w: uri must be an enabled %s or %s reference", ErrInvalidRef, SchemeOCI, SchemeS3)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payload %s: %w", ref.URI, err)
+	}
+	defer func() { _ = body.Close() }()
+
+	if f.maxBytes > 0 && size > f.maxBytes {
+		return nil, fmt.Errorf("%w: %s is %d bytes, exceeding the limit of %d", ErrTooLarge, ref.URI, size, f.maxBytes)
+	}
+	var reader io.Reader = body
+	if f.maxBytes > 0 {
+		// The reported size is not trusted: read at most one byte past the limit
+		reader = io.LimitReader(body, f.maxBytes+1)
+	}
+	payload, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch payload %s: %w", ref.URI, err)
+	}
+	if f.maxBytes > 0 && int64(len(payload)) > f.maxBytes {
+		return nil, fmt.Errorf("%w: %s exceeds the limit of %d bytes", ErrTooLarge, ref.URI, f.maxBytes)
+	}
+
+	sum := sha256.Sum256(payload)
+	if got := "sha256:" + hex.EncodeToString(sum[:]); got != ref.Digest {
+		f.logger.Warn("payload digest mismatch", "uri", ref.URI, "expected", ref.Digest, "got", got)
+		return nil, fmt.Errorf("%w: %s has digest %s", ErrDigestMismatch, ref.URI, got)
+	}
+	return &Payload{Data: payload, Signer: signer}, nil
+}
+
+// verifySignature verifies ref's signature bundle signs its digest
+func (f *Fetcher) verifySignature(ref Ref) (*Signer, error) {
+	if f.signatures == nil {
+		return nil, fmt.Errorf("%w: signature verification is not enabled", ErrInvalidRef)
+	}
+	if len(ref.SignatureBundle) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrSignatureRequired, ref.URI)
+	}
+	return f.signatures.Verify(ref.SignatureBundle, ref.Digest)
+}
+
+// RegistryClient fetches blobs from OCI registries, using the registry
+// credentials of the server's Docker config
+type RegistryClient struct {
+	client *auth.Client
+}
+
+// NewRegistryClient creates a RegistryClient whose registry requests time
+// out after timeout
+func NewRegistryClient(timeout time.Duration) (*RegistryClient, error) {
+	store, err := credentials.NewStoreFromDocker(credentials.StoreOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load registry credentials: %w", err)
+	}
+	return &RegistryClient{client: &auth.Client{
+		Client:     &http.Client{Timeout: timeout},
+		Cache:      auth.NewCache(),
+		Credential: credentials.Credential(store),
+	}}, nil
+}
+
+// FetchBlob fetches the blob with digest from repository
+func (c *RegistryClient) FetchBlob(ctx context.Context, repository, digest string) (io.ReadCloser, int64, error) {
+	repo, err := remote.NewRepository(repository)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %v", ErrInvalidRef, err)
+	}
+	repo.Client = c.client
+	desc, rc, err := repo.Blobs().FetchReference(ctx, digest)
+	if err != nil {
+		return nil, 0, err
+	}
+	return rc, desc.Size, nil
+}
@@ -0,0 +1,109 @@
+package workpayload
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func digestOf(payload string) string {
+	sum := sha256.Sum256([]byte(payload))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// fakeOCI serves blobs by repository and digest
+type fakeOCI struct {
+	blobs map[string]string
+}
+
+func (f *fakeOCI) FetchBlob(ctx context.Context, repository, digest string) (io.ReadCloser, int64, error) {
+	blob, ok := f.blobs[repository+"@"+digest]
+	if !ok {
+		return nil, 0, errors.New("blob not found")
+	}
+	return io.NopCloser(strings.NewReader(blob)), int64(len(blob)), nil
+}
+
+// fakeS3 serves objects by bucket and key
+type fakeS3 struct {
+	objects map[string]string
+}
+
+func (f *fakeS3) GetObject(ctx context.Context, in *s3.GetObjectInput, _ ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	object, ok := f.objects[aws.ToString(in.Bucket)+"/"+aws.ToString(in.Key)]
+	if !ok {
+		return nil, errors.New("NoSuchKey")
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader([]byte(object))), ContentLength: aws.Int64(int64(len(object)))}, nil
+}
+
+func TestFetcher_Fetch(t *testing.T) {
+	const payload = "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: cm\n"
+	oci := &fakeOCI{blobs: map[string]string{"quay.io/example/bundles@" + digestOf(payload): payload}}
+	s3Client := &fakeS3{objects: map[string]string{
+		"bundles/app.yaml":      payload,
+		"bundles/tampered.yaml": payload + "# tampered\n",
+	}}
+	fetcher := NewFetcher(oci, s3Client, Config{Registries: []string{"quay.io"}, Buckets: []string{"bundles"}, MaxBytes: 1024}, testLogger())
+
+	tests := []struct {
+		name    string
+		ref     Ref
+		wantErr error
+	}{
+		{name: "OCI blob", ref: Ref{URI: "oci://quay.io/example/bundles", Digest: digestOf(payload)}},
+		{name: "S3 object", ref: Ref{URI: "s3://bundles/app.yaml", Digest: digestOf(payload)}},
+		{name: "digest mismatch", ref: Ref{URI: "s3://bundles/tampered.yaml", Digest: digestOf(payload)}, wantErr: ErrDigestMismatch},
+		{name: "registry not allowed", ref: Ref{URI: "oci://docker.io/example/bundles", Digest: digestOf(payload)}, wantErr: ErrSourceNotAllowed},
+		{name: "bucket not allowed", ref: Ref{URI: "s3://other/app.yaml", Digest: digestOf(payload)}, wantErr: ErrSourceNotAllowed},
+		{name: "tag in repository", ref: Ref{URI: "oci://quay.io/example/bundles:latest", Digest: digestOf(payload)}, wantErr: ErrInvalidRef},
+		{name: "unpinned", ref: Ref{URI: "s3://bundles/app.yaml"}, wantErr: ErrInvalidRef},
+		{name: "unsupported scheme", ref: Ref{URI: "https://example.com/app.yaml", Digest: digestOf(payload)}, wantErr: ErrInvalidRef},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetcher.Fetch(context.Background(), tt.ref)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("expected %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got.Data) != payload {
+				t.Errorf("unexpected payload %q", got.Data)
+			}
+		})
+	}
+}
+
+func TestFetcher_Fetch_TooLarge(t *testing.T) {
+	payload := strings.Repeat("x", 100)
+	s3Client := &fakeS3{objects: map[string]string{"bundles/big.yaml": payload}}
+	fetcher := NewFetcher(nil, s3Client, Config{Buckets: []string{"bundles"}, MaxBytes: 10}, testLogger())
+
+	_, err := fetcher.Fetch(context.Background(), Ref{URI: "s3://bundles/big.yaml", Digest: digestOf(payload)})
+	if !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected %v, got %v", ErrTooLarge, err)
+	}
+
+	if _, err := fetcher.Fetch(context.Background(), Ref{URI: "oci://quay.io/example/bundles", Digest: digestOf(payload)}); !errors.Is(err, ErrInvalidRef) {
+		t.Errorf("expected OCI references to be rejected without an OCI client, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+// Package workqueue limits how many work submissions are sent to Maestro at
+// once and, while the limit is reached, dispatches waiting submissions by
+// priority class.
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Priority classes, highest first
+const (
+	PriorityCritical = "platform-critical"
+	PriorityNormal   = "normal"
+	PriorityBatch    = "batch"
+)
+
+// Priorities lists the priority classes in dispatch order
+var Priorities = []string{PriorityCritical, PriorityNormal, PriorityBatch}
+
+// ErrQueueFull is returned when a submission would wait behind more than the
+// configured number of queued submissions
+var ErrQueueFull = errors.New("work queue is full")
+
+var (
+	queueDepth = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "rosa_work_queue_depth",
+		Help: "Work submissions waiting for a submission slot, by priority class.",
+	}, []string{"priority"})
+
+	queueWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "rosa_work_queue_wait_seconds",
+		Help:    "Time work submissions waited for a submission slot, by priority class.",
+		Buckets: []float64{0.001, 0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"priority"})
+
+	queueInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rosa_work_in_flight",
+		Help: "Work submissions holding a submission slot.",
+	})
+
+	queueLoad = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "rosa_work_queue_load",
+		Help: "Work submissions holding or waiting for a slot per submission slot; above 1, submissions queue.",
+	})
+
+	queueRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_work_queue_rejected_total",
+		Help: "Work submissions turned away without a slot, by reason (full, timeout or canceled).",
+	}, []string{"reason"})
+)
+
+// Bounds of the backoff suggested to submissions turned away
+const (
+	minBackoff = time.Second
+	maxBackoff = time.Minute
+)
+
+// holdWeight is the weight of the latest slot hold time in the moving average
+const holdWeight = 0.2
+
+// ValidPriority reports whether p names a priority class
+func ValidPriority(p string) bool {
+	return slices.Contains(Priorities, p)
+}
+
+// waiter is a queued submission; ready is closed when it is handed a slot
+type waiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// Queue hands out up to limit submission slots. Submissions that find every
+// slot taken wait in a queue per priority class; a released slot goes to the
+// oldest waiter of the highest class. A nil Queue never waits.
+type Queue struct {
+	mu        sync.Mutex
+	limit     int
+	maxQueued int
+	active    int
+	queued    int
+	waiting   map[string][]*waiter
+	// avgHold is the moving average time a submission holds a slot
+	avgHold time.Duration
+}
+
+// New creates a queue with limit slots. maxQueued caps the submissions
+// waiting across all classes; 0 leaves it unbounded.
+func New(limit, maxQueued int) *Queue {
+	return &Queue{
+		limit:     limit,
+		maxQueued: maxQueued,
+		waiting:   make(map[string][]*waiter, len(Priorities)),
+	}
+}
+
+// Acquire waits for a submission slot and returns the function that releases
+// it. It fails with ErrQueueFull when the queue is full, or with the
+// context's error if ctx is done first. An unknown priority is treated as
+// normal.
+func (q *Queue) Acquire(ctx context.Context, priority string) (func(), error) {
+	if q == nil {
+		return func() {}, nil
+	}
+	if !ValidPriority(priority) {
+		priority = PriorityNormal
+	}
+	start := time.Now()
+
+	q.mu.Lock()
+	if q.active < q.limit {
+		q.active++
+		q.observeLoad()
+		q.mu.Unlock()
+		return q.granted(priority, start), nil
+	}
+	if q.maxQueued > 0 && q.queued >= q.maxQueued {
+		q.mu.Unlock()
+		queueRejected.WithLabelValues("full").Inc()
+		return nil, ErrQueueFull
+	}
+	w := &waiter{ready: make(chan struct{})}
+	q.waiting[priority] = append(q.waiting[priority], w)
+	q.queued++
+	queueDepth.WithLabelValues(priority).Inc()
+	q.observeLoad()
+	q.mu.Unlock()
+
+	select {
+	case <-w.ready:
+		return q.granted(priority, start), nil
+	case <-ctx.Done():
+		reason := "canceled"
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			reason = "timeout"
+		}
+		queueRejected.WithLabelValues(reason).Inc()
+		q.mu.Lock()
+		if w.granted {
+			// The slot was handed over as ctx ended; pass it on
+			q.mu.Unlock()
+			q.release()
+			return nil, ctx.Err()
+		}
+		q.waiting[priority] = slices.DeleteFunc(q.waiting[priority], func(x *waiter) bool { return x == w })
+		q.queued--
+		queueDepth.WithLabelValues(priority).Dec()
+		q.observeLoad()
+		q.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+// Backoff suggests how long a submission turned away now should wait before
+// retrying: the time for the submissions queued ahead of it to drain through
+// the slots, at the average time a submission holds a slot. It is bounded to
+// between one second and one minute.
+func (q *Queue) Backoff() time.Duration {
+	if q == nil {
+		return minBackoff
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	hold := q.avgHold
+	if hold == 0 {
+		hold = minBackoff
+	}
+	rounds := q.queued/q.limit + 1
+	return min(max(time.Duration(rounds)*hold, minBackoff), maxBackoff)
+}
+
+// Load returns the submissions holding or waiting for a slot per slot; above
+// 1, submissions queue. A nil Queue has no load.
+func (q *Queue) Load() float64 {
+	if q == nil {
+		return 0
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return float64(q.active+q.queued) / float64(q.limit)
+}
+
+// observeLoad publishes the queue load; q.mu must be held
+func (q *Queue) observeLoad() {
+	queueLoad.Set(float64(q.active+q.queued) / float64(q.limit))
+}
+
+// granted records a slot handed to a submission of priority and returns its
+// release function, which is safe to call more than once
+func (q *Queue) granted(priority string, start time.Time) func() {
+	queueWait.WithLabelValues(priority).Observe(time.Since(start).Seconds())
+	queueInFlight.Inc()
+	held := time.Now()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			queueInFlight.Dec()
+			q.mu.Lock()
+			if q.avgHold == 0 {
+				q.avgHold = time.Since(held)
+			} else {
+				q.avgHold += time.Duration(holdWeight * float64(time.Since(held)-q.avgHold))
+			}
+			q.mu.Unlock()
+			q.release()
+		})
+	}
+}
+
+// release hands a slot to the next waiter, or frees it if none are waiting
+func (q *Queue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, priority := range Priorities {
+		if len(q.waiting[priority]) == 0 {
+			continue
+		}
+		w := q.waiting[priority][0]
+		q.waiting[priority] = q.waiting[priority][1:]
+		q.queued--
+		queueDepth.WithLabelValues(priority).Dec()
+		w.granted = true
+		close(w.ready)
+		q.observeLoad()
+		return
+	}
+	q.active--
+	q.observeLoad()
+}
@@ -0,0 +1,141 @@
+package workqueue
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// waitQueued waits until n submissions are queued
+func waitQueued(t *testing.T, q *Queue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		q.mu.Lock()
+		queued := q.queued
+		q.mu.Unlock()
+		if queued == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("expected %d queued submissions", n)
+}
+
+func TestQueue_DispatchesByPriority(t *testing.T) {
+	q := New(1, 0)
+	release, err := q.Acquire(context.Background(), PriorityNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	order := make(chan string, 4)
+	var wg sync.WaitGroup
+	for i, priority := range []string{PriorityBatch, PriorityNormal, PriorityCritical, PriorityNormal} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, err := q.Acquire(context.Background(), priority)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			order <- priority
+			r()
+		}()
+		waitQueued(t, q, i+1)
+	}
+
+	release()
+	want := []string{PriorityCritical, PriorityNormal, PriorityNormal, PriorityBatch}
+	for i, w := range want {
+		if got := <-order; got != w {
+			t.Fatalf("dispatch %d: expected %s, got %s", i, w, got)
+		}
+	}
+
+	wg.Wait()
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.active != 0 || q.queued != 0 {
+		t.Errorf("expected an idle queue, got active=%d queued=%d", q.active, q.queued)
+	}
+}
+
+func TestQueue_ContextDone(t *testing.T) {
+	q := New(1, 0)
+	release, _ := q.Acquire(context.Background(), PriorityNormal)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Acquire(ctx, PriorityBatch); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	// The timed-out submission leaves the queue
+	waitQueued(t, q, 0)
+
+	// The slot is free for the next submission once released
+	release()
+	r, err := q.Acquire(context.Background(), PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r()
+}
+
+func TestQueue_Full(t *testing.T) {
+	q := New(1, 1)
+	release, _ := q.Acquire(context.Background(), PriorityNormal)
+	defer release()
+
+	go func() {
+		if r, err := q.Acquire(context.Background(), PriorityNormal); err == nil {
+			r()
+		}
+	}()
+	waitQueued(t, q, 1)
+
+	if _, err := q.Acquire(context.Background(), PriorityCritical); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("expected ErrQueueFull, got %v", err)
+	}
+}
+
+func TestQueue_Backoff(t *testing.T) {
+	q := New(2, 0)
+	if got := q.Backoff(); got != minBackoff {
+		t.Errorf("expected the minimum backoff for an idle queue, got %v", got)
+	}
+
+	q.avgHold = 10 * time.Second
+	q.queued = 5
+	// Five queued submissions drain through two slots in three rounds
+	if got := q.Backoff(); got != 30*time.Second {
+		t.Errorf("expected 30s, got %v", got)
+	}
+	q.queued = 50
+	if got := q.Backoff(); got != maxBackoff {
+		t.Errorf("expected the maximum backoff, got %v", got)
+	}
+}
+
+func TestQueue_Load(t *testing.T) {
+	q := New(2, 0)
+	q.active, q.queued = 2, 3
+	if got := q.Load(); got != 2.5 {
+		t.Errorf("expected a load of 2.5, got %v", got)
+	}
+}
+
+func TestQueue_Nil(t *testing.T) {
+	var q *Queue
+	if q.Load() != 0 {
+		t.Error("expected a nil queue to have no load")
+	}
+	release, err := q.Acquire(context.Background(), PriorityBatch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	release()
+}
@@ -0,0 +1,99 @@
+// Package workrate tracks how many works are submitted to each management
+// cluster and how many of its bundles are created and deleted, and limits how
+// fast works may be submitted to any one cluster, so a single busy tenant
+// cannot overwhelm the agent of the cluster it targets.
+package workrate
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+// Bundle changes
+const (
+	ChangeCreated = "created"
+	ChangeDeleted = "deleted"
+)
+
+var (
+	submissions = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_work_cluster_submissions_total",
+		Help: "Work submissions, by target management cluster and whether the cluster's rate limit allowed them.",
+	}, []string{"cluster", "allowed"})
+
+	bundleChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rosa_work_cluster_bundle_changes_total",
+		Help: "Resource bundles created or deleted through the work API, by management cluster and change.",
+	}, []string{"cluster", "change"})
+)
+
+// Limiter limits work submissions per target management cluster
+type Limiter struct {
+	limiter ratelimit.Limiter
+	logger  *slog.Logger
+}
+
+// NewLimiter creates a Limiter taking a token from limiter, keyed by cluster,
+// for every submission. A nil limiter allows every submission and only
+// counts it.
+func NewLimiter(limiter ratelimit.Limiter, logger *slog.Logger) *Limiter {
+	return &Limiter{limiter: limiter, logger: logger}
+}
+
+// Allow counts a submission to clusterID. When the cluster's limit is reached
+// it returns false and how long until a submission would be allowed again. A
+// limiter that fails allows the submission.
+func (l *Limiter) Allow(ctx context.Context, clusterID string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+	allowed, retryAfter := true, time.Duration(0)
+	if l.limiter != nil {
+		var err error
+		allowed, retryAfter, err = l.limiter.Allow(ctx, clusterID)
+		if err != nil {
+			l.logger.Warn("failed to check cluster work rate limit, allowing submission", "error", err, "cluster_id", clusterID)
+			allowed, retryAfter = true, 0
+		}
+	}
+	submissions.WithLabelValues(clusterID, strconv.FormatBool(allowed)).Inc()
+	return allowed, retryAfter
+}
+
+// Client counts the bundles created and deleted through a Maestro client,
+// per management cluster
+type Client struct {
+	maestro.ClientInterface
+}
+
+// NewClient wraps client to count bundle changes
+func NewClient(client maestro.ClientInterface) *Client {
+	return &Client{ClientInterface: client}
+}
+
+// CreateManifestWork creates a work and counts its bundle
+func (c *Client) CreateManifestWork(ctx context.Context, clusterName string, manifestWork *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	result, err := c.ClientInterface.CreateManifestWork(ctx, clusterName, manifestWork)
+	if err == nil {
+		bundleChanges.WithLabelValues(clusterName, ChangeCreated).Inc()
+	}
+	return result, err
+}
+
+// DeleteManifestWork deletes a work and counts its bundle
+func (c *Client) DeleteManifestWork(ctx context.Context, clusterName string, name string) error {
+	err := c.ClientInterface.DeleteManifestWork(ctx, clusterName, name)
+	if err == nil {
+		bundleChanges.WithLabelValues(clusterName, ChangeDeleted).Inc()
+	}
+	return err
+}
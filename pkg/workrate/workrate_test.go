@@ -0,0 +1,94 @@
+package workrate
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	workv1 "open-cluster-management.io/api/work/v1"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/ratelimit"
+)
+
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	return false, 0, errors.New("backend down")
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	ctx := context.Background()
+	l := NewLimiter(ratelimit.NewLocal(2, time.Minute), slog.Default())
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := l.Allow(ctx, "mc-limited"); !allowed {
+			t.Fatalf("expected submission %d to be allowed", i+1)
+		}
+	}
+	allowed, retryAfter := l.Allow(ctx, "mc-limited")
+	if allowed || retryAfter <= 0 {
+		t.Errorf("expected third submission to be limited with a retry delay, got %v, %s", allowed, retryAfter)
+	}
+	// Clusters are limited independently
+	if allowed, _ := l.Allow(ctx, "mc-other"); !allowed {
+		t.Error("expected another cluster's submission to be allowed")
+	}
+
+	if got := testutil.ToFloat64(submissions.WithLabelValues("mc-limited", "true")); got != 2 {
+		t.Errorf("expected 2 allowed submissions counted, got %v", got)
+	}
+	if got := testutil.ToFloat64(submissions.WithLabelValues("mc-limited", "false")); got != 1 {
+		t.Errorf("expected 1 limited submission counted, got %v", got)
+	}
+}
+
+func TestLimiter_AllowsWithoutLimit(t *testing.T) {
+	ctx := context.Background()
+	for name, l := range map[string]*Limiter{
+		"nil":       nil,
+		"unlimited": NewLimiter(nil, slog.Default()),
+		"failing":   NewLimiter(failingLimiter{}, slog.Default()),
+	} {
+		if allowed, retryAfter := l.Allow(ctx, "mc-"+name); !allowed || retryAfter != 0 {
+			t.Errorf("%s: expected submission to be allowed, got %v, %s", name, allowed, retryAfter)
+		}
+	}
+}
+
+type stubMaestro struct {
+	maestro.ClientInterface
+	err error
+}
+
+func (s *stubMaestro) CreateManifestWork(ctx context.Context, clusterName string, mw *workv1.ManifestWork) (*workv1.ManifestWork, error) {
+	return mw, s.err
+}
+
+func (s *stubMaestro) DeleteManifestWork(ctx context.Context, clusterName string, name string) error {
+	return s.err
+}
+
+func TestClient_CountsBundleChanges(t *testing.T) {
+	ctx := context.Background()
+	stub := &stubMaestro{}
+	c := NewClient(stub)
+
+	_, _ = c.CreateManifestWork(ctx, "mc-churn", &workv1.ManifestWork{})
+	_, _ = c.CreateManifestWork(ctx, "mc-churn", &workv1.ManifestWork{})
+	_ = c.DeleteManifestWork(ctx, "mc-churn", "work-1")
+	// Failed calls change nothing
+	stub.err = errors.New("maestro unavailable")
+	_, _ = c.CreateManifestWork(ctx, "mc-churn", &workv1.ManifestWork{})
+	_ = c.DeleteManifestWork(ctx, "mc-churn", "work-2")
+
+	if got := testutil.ToFloat64(bundleChanges.WithLabelValues("mc-churn", ChangeCreated)); got != 2 {
+		t.Errorf("expected 2 bundles created, got %v", got)
+	}
+	if got := testutil.ToFloat64(bundleChanges.WithLabelValues("mc-churn", ChangeDeleted)); got != 1 {
+		t.Errorf("expected 1 bundle deleted, got %v", got)
+	}
+}
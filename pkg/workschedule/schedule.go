@@ -0,0 +1,79 @@
+// Package workschedule persists work requests to be submitted later, once at
+// a given time or repeatedly on a cron schedule, and submits them when due.
+package workschedule
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule statuses
+const (
+	StatusActive    = "active"
+	StatusCompleted = "completed"
+	StatusCancelled = "cancelled"
+)
+
+var (
+	// ErrScheduleNotFound is returned when a schedule does not exist
+	ErrScheduleNotFound = errors.New("schedule not found")
+	// ErrScheduleNotActive is returned when a schedule has completed or been
+	// cancelled, or its due run was already claimed
+	ErrScheduleNotActive = errors.New("schedule is not active")
+)
+
+// cronParser accepts standard five-field expressions and descriptors such as
+// @hourly and @daily
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Schedule is a work request to submit at RunAt or on every Cron tick. The
+// request is kept as submitted, so secret references are resolved and
+// secrets encrypted at each run. The caller is kept too, but not its
+// permissions: each run is authorized again.
+type Schedule struct {
+	AccountID  string `dynamodbav:"accountId"`
+	ScheduleID string `dynamodbav:"scheduleId"`
+	ClusterID  string `dynamodbav:"clusterId"`
+	// Request is the JSON work request, without its schedule
+	Request string `dynamodbav:"request"`
+	// Tags are the request tags the work was created with
+	Tags      map[string]string `dynamodbav:"tags,omitempty"`
+	CallerARN string            `dynamodbav:"callerArn"`
+	// DelegateAccountID is the caller's own account when the work was
+	// scheduled under a delegation from AccountID
+	DelegateAccountID string `dynamodbav:"delegateAccountId,omitempty"`
+	RunAt             string `dynamodbav:"runAt,omitempty"`
+	Cron              string `dynamodbav:"cron,omitempty"`
+	Status            string `dynamodbav:"status"`
+	// NextRunAt is the Unix time of the next submission
+	NextRunAt    int64  `dynamodbav:"nextRunAt"`
+	LastRunAt    string `dynamodbav:"lastRunAt,omitempty"`
+	LastWorkName string `dynamodbav:"lastWorkName,omitempty"`
+	LastError    string `dynamodbav:"lastError,omitempty"`
+	RunCount     int    `dynamodbav:"runCount"`
+	CreatedAt    string `dynamodbav:"createdAt"`
+}
+
+// ValidateCron checks a cron expression
+func ValidateCron(expr string) error {
+	if _, err := cronParser.Parse(expr); err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", expr, err)
+	}
+	return nil
+}
+
+// Next returns the Unix time of the run after t, or 0 if the schedule does
+// not run again
+func (s *Schedule) Next(t time.Time) int64 {
+	if s.Cron == "" {
+		return 0
+	}
+	sched, err := cronParser.Parse(s.Cron)
+	if err != nil {
+		return 0
+	}
+	return sched.Next(t.UTC()).Unix()
+}
@@ -0,0 +1,96 @@
+package workschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Submitter submits a schedule's work request and returns the name of the
+// ManifestWork it created
+type Submitter interface {
+	SubmitScheduled(ctx context.Context, s *Schedule) (string, error)
+}
+
+// Scheduler submits due schedules on an interval
+type Scheduler struct {
+	store     Store
+	submitter Submitter
+	interval  time.Duration
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+// NewScheduler creates a new scheduler. Schedules run up to interval late.
+func NewScheduler(store Store, submitter Submitter, interval time.Duration, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		store:     store,
+		submitter: submitter,
+		interval:  interval,
+		logger:    logger,
+		now:       time.Now,
+	}
+}
+
+// Run submits due schedules immediately and then on every interval until ctx is done
+func (s *Scheduler) Run(ctx context.Context) {
+	s.logger.Info("work scheduler started", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.RunDue(ctx); err != nil {
+			s.logger.Error("work schedule run failed", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info("work scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue claims and submits every due schedule. A failed submission is
+// recorded on its schedule and does not stop the others; a recurring
+// schedule runs again at its next tick. Runs missed while no scheduler was
+// running are submitted once, not once per missed tick.
+func (s *Scheduler) RunDue(ctx context.Context) error {
+	now := s.now()
+	due, err := s.store.Due(ctx, now)
+	if err != nil {
+		return fmt.Errorf("failed to list due work schedules: %w", err)
+	}
+
+	failed := 0
+	for _, sched := range due {
+		if err := s.store.Claim(ctx, sched, now); err != nil {
+			if !errors.Is(err, ErrScheduleNotActive) {
+				s.logger.Error("failed to claim work schedule", "error", err, "account_id", sched.AccountID, "schedule_id", sched.ScheduleID)
+				failed++
+			}
+			continue
+		}
+
+		workName, runErr := s.submitter.SubmitScheduled(ctx, sched)
+		if runErr != nil {
+			s.logger.Error("scheduled work submission failed", "error", runErr,
+				"account_id", sched.AccountID, "schedule_id", sched.ScheduleID, "cluster_id", sched.ClusterID)
+			failed++
+		} else {
+			s.logger.Info("scheduled work submitted",
+				"account_id", sched.AccountID, "schedule_id", sched.ScheduleID, "cluster_id", sched.ClusterID, "work_name", workName)
+		}
+		if err := s.store.RecordRun(ctx, sched, workName, runErr); err != nil {
+			s.logger.Warn("failed to record work schedule run", "error", err, "account_id", sched.AccountID, "schedule_id", sched.ScheduleID)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to submit %d of %d due work schedules", failed, len(due))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package workschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// memoryStore keeps schedules in memory and claims them like DynamoStore
+type memoryStore struct {
+	Store
+	schedules map[string]*Schedule
+	runs      map[string][]string
+}
+
+func (m *memoryStore) Due(ctx context.Context, now time.Time) ([]*Schedule, error) {
+	var due []*Schedule
+	for _, s := range m.schedules {
+		if s.Status == StatusActive && s.NextRunAt <= now.Unix() {
+			copied := *s
+			due = append(due, &copied)
+		}
+	}
+	return due, nil
+}
+
+func (m *memoryStore) Claim(ctx context.Context, s *Schedule, now time.Time) error {
+	stored := m.schedules[s.ScheduleID]
+	if stored.Status != StatusActive || stored.NextRunAt != s.NextRunAt {
+		return fmt.Errorf("%w: %s", ErrScheduleNotActive, s.ScheduleID)
+	}
+	if next := s.Next(now); next == 0 {
+		stored.Status = StatusCompleted
+		stored.NextRunAt = 0
+	} else {
+		stored.NextRunAt = next
+	}
+	return nil
+}
+
+func (m *memoryStore) RecordRun(ctx context.Context, s *Schedule, workName string, runErr error) error {
+	result := workName
+	if runErr != nil {
+		result = "error: " + runErr.Error()
+	}
+	m.runs[s.ScheduleID] = append(m.runs[s.ScheduleID], result)
+	return nil
+}
+
+type fakeSubmitter struct {
+	fail map[string]bool
+}
+
+func (f *fakeSubmitter) SubmitScheduled(ctx context.Context, s *Schedule) (string, error) {
+	if f.fail[s.ScheduleID] {
+		return "", errors.New("maestro unavailable")
+	}
+	return s.ScheduleID + "-work", nil
+}
+
+func TestScheduler_RunDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 30, 0, time.UTC)
+	store := &memoryStore{
+		schedules: map[string]*Schedule{
+			"once":    {ScheduleID: "once", Status: StatusActive, RunAt: "2026-03-01T12:00:00Z", NextRunAt: now.Add(-30 * time.Second).Unix()},
+			"hourly":  {ScheduleID: "hourly", Status: StatusActive, Cron: "@hourly", NextRunAt: now.Add(-30 * time.Second).Unix()},
+			"later":   {ScheduleID: "later", Status: StatusActive, RunAt: "2026-03-02T00:00:00Z", NextRunAt: now.Add(12 * time.Hour).Unix()},
+			"failing": {ScheduleID: "failing", Status: StatusActive, RunAt: "2026-03-01T11:00:00Z", NextRunAt: now.Add(-time.Hour).Unix()},
+		},
+		runs: map[string][]string{},
+	}
+	scheduler := NewScheduler(store, &fakeSubmitter{fail: map[string]bool{"failing": true}}, time.Minute, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	scheduler.now = func() time.Time { return now }
+
+	if err := scheduler.RunDue(context.Background()); err == nil {
+		t.Error("expected the failed submission to be reported")
+	}
+
+	if got := store.runs["once"]; len(got) != 1 || got[0] != "once-work" {
+		t.Errorf("expected the one-time schedule to run once, got %v", got)
+	}
+	if store.schedules["once"].Status != StatusCompleted {
+		t.Errorf("expected the one-time schedule to complete, got %s", store.schedules["once"].Status)
+	}
+	if want := time.Date(2026, 3, 1, 13, 0, 0, 0, time.UTC).Unix(); store.schedules["hourly"].NextRunAt != want {
+		t.Errorf("expected the hourly schedule to move to 13:00, got %v", time.Unix(store.schedules["hourly"].NextRunAt, 0).UTC())
+	}
+	if len(store.runs["later"]) != 0 {
+		t.Error("expected a schedule that is not due to be left alone")
+	}
+	if got := store.runs["failing"]; len(got) != 1 || got[0] != "error: maestro unavailable" {
+		t.Errorf("expected the failure to be recorded, got %v", got)
+	}
+
+	// A second pass in the same minute finds nothing due
+	if err := scheduler.RunDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.runs["once"]) != 1 || len(store.runs["hourly"]) != 1 {
+		t.Errorf("expected no repeated submissions, got %v", store.runs)
+	}
+}
+
+func TestValidateCron(t *testing.T) {
+	for _, expr := range []string{"*/15 * * * *", "0 3 * * 1-5", "@daily"} {
+		if err := ValidateCron(expr); err != nil {
+			t.Errorf("ValidateCron(%q) = %v", expr, err)
+		}
+	}
+	for _, expr := range []string{"", "* * *", "0 0 0 * * *", "61 * * * *"} {
+		if err := ValidateCron(expr); err == nil {
+			t.Errorf("ValidateCron(%q) expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package workschedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/authz/client"
+	"github.com/openshift/rosa-regional-platform-api/pkg/clock"
+	"github.com/openshift/rosa-regional-platform-api/pkg/purge"
+)
+
+// Store persists work schedules
+type Store interface {
+	Create(ctx context.Context, s *Schedule) error
+	Get(ctx context.Context, accountID, scheduleID string) (*Schedule, error)
+	List(ctx context.Context, accountID string) ([]*Schedule, error)
+	Cancel(ctx context.Context, accountID, scheduleID string) (*Schedule, error)
+	// Due returns the active schedules whose next run is at or before now
+	Due(ctx context.Context, now time.Time) ([]*Schedule, error)
+	// Claim moves a due schedule on to its next run, or completes it, so
+	// that only one caller submits the run. It fails with
+	// ErrScheduleNotActive if the run was already claimed.
+	Claim(ctx context.Context, s *Schedule, now time.Time) error
+	// RecordRun stores the outcome of a claimed run
+	RecordRun(ctx context.Context, s *Schedule, workName string, runErr error) error
+	// Purge deletes every schedule of an account and returns how many it
+	// deleted
+	Purge(ctx context.Context, accountID string) (int, error)
+}
+
+// DynamoStore implements Store backed by DynamoDB
+type DynamoStore struct {
+	tableName    string
+	dynamoClient client.DynamoDBClient
+	logger       *slog.Logger
+	now          clock.Clock
+}
+
+// NewDynamoStore creates a new DynamoDB-backed work schedule store
+func NewDynamoStore(tableName string, dynamoClient client.DynamoDBClient, logger *slog.Logger) *DynamoStore {
+	return &DynamoStore{
+		tableName:    tableName,
+		dynamoClient: dynamoClient,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WithClock sets the clock the store stamps times with
+func (st *DynamoStore) WithClock(now clock.Clock) *DynamoStore {
+	st.now = clock.Or(now)
+	return st
+}
+
+// Create stores a new active schedule, assigning its ID
+func (st *DynamoStore) Create(ctx context.Context, s *Schedule) error {
+	s.ScheduleID = uuid.New().String()
+	s.Status = StatusActive
+	s.CreatedAt = clock.Format(st.now())
+
+	item, err := attributevalue.MarshalMap(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal work schedule: %w", err)
+	}
+
+	_, err = st.dynamoClient.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(st.tableName),
+		Item:      item,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put work schedule: %w", err)
+	}
+
+	st.logger.Info("work schedule created", "account_id", s.AccountID, "schedule_id", s.ScheduleID, "cluster_id", s.ClusterID)
+	return nil
+}
+
+// Get returns a schedule, or nil if it does not exist
+func (st *DynamoStore) Get(ctx context.Context, accountID, scheduleID string) (*Schedule, error) {
+	result, err := st.dynamoClient.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(st.tableName),
+		Key:       scheduleKey(accountID, scheduleID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get work schedule: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var s Schedule
+	if err := attributevalue.UnmarshalMap(result.Item, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal work schedule: %w", err)
+	}
+	return &s, nil
+}
+
+// List returns an account's schedules, oldest first
+func (st *DynamoStore) List(ctx context.Context, accountID string) ([]*Schedule, error) {
+	var schedules []*Schedule
+	paginator := dynamodb.NewQueryPaginator(st.dynamoClient, &dynamodb.QueryInput{
+		TableName:              aws.String(st.tableName),
+		KeyConditionExpression: aws.String("accountId = :accountId"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":accountId": &types.AttributeValueMemberS{Value: accountID},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query work schedules: %w", err)
+		}
+		var items []*Schedule
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal work schedules: %w", err)
+		}
+		schedules = append(schedules, items...)
+	}
+
+	sort.SliceStable(schedules, func(i, j int) bool {
+		return schedules[i].CreatedAt < schedules[j].CreatedAt
+	})
+	return schedules, nil
+}
+
+// Cancel stops an active schedule and returns it
+func (st *DynamoStore) Cancel(ctx context.Context, accountID, scheduleID string) (*Schedule, error) {
+	result, err := st.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(st.tableName),
+		Key:                 scheduleKey(accountID, scheduleID),
+		UpdateExpression:    aws.String("SET #status = :cancelled REMOVE nextRunAt"),
+		ConditionExpression: aws.String("attribute_exists(scheduleId) AND #status = :active"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":cancelled": &types.AttributeValueMemberS{Value: StatusCancelled},
+			":active":    &types.AttributeValueMemberS{Value: StatusActive},
+		},
+		ReturnValues: types.ReturnValueAllNew,
+	})
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		existing, getErr := st.Get(ctx, accountID, scheduleID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		if existing == nil {
+			return nil, fmt.Errorf("%w: %s", ErrScheduleNotFound, scheduleID)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrScheduleNotActive, scheduleID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel work schedule: %w", err)
+	}
+
+	var s Schedule
+	if err := attributevalue.UnmarshalMap(result.Attributes, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal work schedule: %w", err)
+	}
+
+	st.logger.Info("work schedule cancelled", "account_id", accountID, "schedule_id", scheduleID)
+	return &s, nil
+}
+
+// Due scans for active schedules whose next run is at or before now
+func (st *DynamoStore) Due(ctx context.Context, now time.Time) ([]*Schedule, error) {
+	var schedules []*Schedule
+	paginator := dynamodb.NewScanPaginator(st.dynamoClient, &dynamodb.ScanInput{
+		TableName:        aws.String(st.tableName),
+		FilterExpression: aws.String("#status = :active AND nextRunAt <= :now"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":active": &types.AttributeValueMemberS{Value: StatusActive},
+			":now":    &types.AttributeValueMemberN{Value: strconv.FormatInt(now.Unix(), 10)},
+		},
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan work schedules: %w", err)
+		}
+		var items []*Schedule
+		if err := attributevalue.UnmarshalListOfMaps(page.Items, &items); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal work schedules: %w", err)
+		}
+		schedules = append(schedules, items...)
+	}
+	return schedules, nil
+}
+
+// Claim advances a recurring schedule to its next run after now, or
+// completes a one-time schedule, on the condition that its next run is still
+// the one that was found due
+func (st *DynamoStore) Claim(ctx context.Context, s *Schedule, now time.Time) error {
+	input := &dynamodb.UpdateItemInput{
+		TableName:           aws.String(st.tableName),
+		Key:                 scheduleKey(s.AccountID, s.ScheduleID),
+		ConditionExpression: aws.String("#status = :active AND nextRunAt = :due"),
+		ExpressionAttributeNames: map[string]string{
+			"#status": "status",
+		},
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":active": &types.AttributeValueMemberS{Value: StatusActive},
+			":due":    &types.AttributeValueMemberN{Value: strconv.FormatInt(s.NextRunAt, 10)},
+		},
+	}
+
+	next := s.Next(now)
+	if next == 0 {
+		input.UpdateExpression = aws.String("SET #status = :completed REMOVE nextRunAt")
+		input.ExpressionAttributeValues[":completed"] = &types.AttributeValueMemberS{Value: StatusCompleted}
+	} else {
+		input.UpdateExpression = aws.String("SET nextRunAt = :next")
+		input.ExpressionAttributeValues[":next"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(next, 10)}
+	}
+
+	_, err := st.dynamoClient.UpdateItem(ctx, input)
+	var condErr *types.ConditionalCheckFailedException
+	if errors.As(err, &condErr) {
+		return fmt.Errorf("%w: %s", ErrScheduleNotActive, s.ScheduleID)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to claim work schedule: %w", err)
+	}
+	return nil
+}
+
+// RecordRun stores the work a run created, or why it failed
+func (st *DynamoStore) RecordRun(ctx context.Context, s *Schedule, workName string, runErr error) error {
+	update := "SET lastRunAt = :now, runCount = runCount + :one"
+	values := map[string]types.AttributeValue{
+		":now": &types.AttributeValueMemberS{Value: clock.Format(st.now())},
+		":one": &types.AttributeValueMemberN{Value: "1"},
+	}
+	if runErr != nil {
+		update += ", lastError = :error"
+		values[":error"] = &types.AttributeValueMemberS{Value: runErr.Error()}
+	} else {
+		update += ", lastWorkName = :work REMOVE lastError"
+		values[":work"] = &types.AttributeValueMemberS{Value: workName}
+	}
+
+	_, err := st.dynamoClient.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:                 aws.String(st.tableName),
+		Key:                       scheduleKey(s.AccountID, s.ScheduleID),
+		UpdateExpression:          aws.String(update),
+		ExpressionAttributeValues: values,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record work schedule run: %w", err)
+	}
+	return nil
+}
+
+// Purge deletes every schedule of an account, whatever its status
+func (s *DynamoStore) Purge(ctx context.Context, accountID string) (int, error) {
+	return purge.DeletePartition(ctx, s.dynamoClient, purge.Partition{
+		Table:         s.tableName,
+		PartitionKey:  "accountId",
+		KeyAttributes: []string{"accountId", "scheduleId"},
+	}, accountID)
+}
+
+func scheduleKey(accountID, scheduleID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"accountId":  &types.AttributeValueMemberS{Value: accountID},
+		"scheduleId": &types.AttributeValueMemberS{Value: scheduleID},
+	}
+}
@@ -25,6 +25,7 @@ type AuditEntry struct {
 	AccountID     string `dynamodbav:"accountId" json:"account_id"`
 	CallerARN     string `dynamodbav:"callerArn" json:"caller_arn"`
 	Operator      string `dynamodbav:"operator" json:"operator"`
+	SourceIP      string `dynamodbav:"sourceIp,omitempty" json:"source_ip,omitempty"`
 	Method        string `dynamodbav:"method" json:"method"`
 	Path          string `dynamodbav:"path" json:"path"`
 	Action        string `dynamodbav:"action" json:"action"`
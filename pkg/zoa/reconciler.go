@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/openshift/rosa-regional-platform-api/pkg/clients/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/status"
 	workv1 "open-cluster-management.io/api/work/v1"
 )
 
@@ -20,6 +21,8 @@ type Reconciler struct {
 	jobConfig     *JobConfig
 	logger        *slog.Logger
 	interval      time.Duration
+	deliveryLag   *status.Window
+	reportHealth  func(error)
 }
 
 func NewReconciler(
@@ -40,6 +43,20 @@ func NewReconciler(
 	}
 }
 
+// WithDeliveryLag records the delivery lag of each execution's ManifestWork,
+// observed when it is first seen applied, into window
+func (r *Reconciler) WithDeliveryLag(window *status.Window) *Reconciler {
+	r.deliveryLag = window
+	return r
+}
+
+// WithHealth calls report after each reconcile pass with the error that
+// stopped it, or nil when pending executions could be listed
+func (r *Reconciler) WithHealth(report func(error)) *Reconciler {
+	r.reportHealth = report
+	return r
+}
+
 func (r *Reconciler) Run(ctx context.Context) {
 	r.logger.Info("ZOA reconciler started", "interval", r.interval, "default_timeout_seconds", r.jobConfig.ExecutionTimeoutSeconds)
 	ticker := time.NewTicker(r.interval)
@@ -58,6 +75,9 @@ func (r *Reconciler) Run(ctx context.Context) {
 
 func (r *Reconciler) reconcilePending(ctx context.Context) {
 	executions, err := r.store.ListPending(ctx)
+	if r.reportHealth != nil {
+		r.reportHealth(err)
+	}
 	if err != nil {
 		r.logger.Error("failed to list pending executions", "error", err)
 		return
@@ -107,6 +127,11 @@ func (r *Reconciler) reconcileExecution(ctx context.Context, exec *Execution) {
 	}
 
 	if result.applied && exec.Status == StatusPending {
+		if r.deliveryLag != nil {
+			if lag, ok := status.DeliveryLagOf(mw); ok {
+				r.deliveryLag.Record(lag, false)
+			}
+		}
 		if err := r.store.UpdateStatus(ctx, exec.ExecutionID, StatusRunning, "", 0); err != nil {
 			r.logger.Error("failed to update execution status to running",
 				"execution_id", exec.ExecutionID,
@@ -30,6 +30,10 @@ func (m *mockMaestroClient) ListConsumers(ctx context.Context, page, size int) (
 func (m *mockMaestroClient) GetConsumer(ctx context.Context, id string) (*maestro.Consumer, error) {
 	return nil, nil
 }
+
+func (m *mockMaestroClient) DeleteConsumer(ctx context.Context, id string) error {
+	return nil
+}
 func (m *mockMaestroClient) ListResourceBundles(ctx context.Context, page, size int, search, orderBy, fields string) (*maestro.ResourceBundleList, error) {
 	return nil, nil
 }
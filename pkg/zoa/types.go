@@ -91,6 +91,7 @@ type ExecutionResponse struct {
 
 // ExecutionList wraps a paginated list response.
 type ExecutionList struct {
+	Kind    string       `json:"kind"`
 	Items   []*Execution `json:"items"`
 	Total   int          `json:"total"`
 	Page    int          `json:"page"`
@@ -28,6 +28,9 @@ for i in {1..30}; do
     sleep 1
 done
 
+# Streams read by --authz-streams
+AUTHZ_STREAM="StreamEnabled=true,StreamViewType=NEW_AND_OLD_IMAGES"
+
 # Function to create table if it doesn't exist
 create_table() {
     local table_name=$1
@@ -45,20 +48,32 @@ create_table() {
 
 # 1. Accounts table (PK: accountId)
 create_table "rosa-authz-accounts" \
+    --stream-specification "$AUTHZ_STREAM" \
     --attribute-definitions AttributeName=accountId,AttributeType=S \
     --key-schema AttributeName=accountId,KeyType=HASH
 
-# 2. Admins table (PK: accountId, SK: principalArn)
+# 2. Admins table (PK: accountId, SK: principalArn, GSI: principal-accounts-index)
 create_table "rosa-authz-admins" \
+    --stream-specification "$AUTHZ_STREAM" \
     --attribute-definitions \
         AttributeName=accountId,AttributeType=S \
         AttributeName=principalArn,AttributeType=S \
     --key-schema \
         AttributeName=accountId,KeyType=HASH \
-        AttributeName=principalArn,KeyType=RANGE
+        AttributeName=principalArn,KeyType=RANGE \
+    --global-secondary-indexes \
+        '[{
+            "IndexName": "principal-accounts-index",
+            "KeySchema": [
+                {"AttributeName": "principalArn", "KeyType": "HASH"},
+                {"AttributeName": "accountId", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
+        }]'
 
 # 3. Groups table (PK: accountId, SK: groupId)
 create_table "rosa-authz-groups" \
+    --stream-specification "$AUTHZ_STREAM" \
     --attribute-definitions \
         AttributeName=accountId,AttributeType=S \
         AttributeName=groupId,AttributeType=S \
@@ -66,13 +81,15 @@ create_table "rosa-authz-groups" \
         AttributeName=accountId,KeyType=HASH \
         AttributeName=groupId,KeyType=RANGE
 
-# 4. Members table (PK: accountId, SK: groupId#memberArn, GSI: member-groups-index)
+# 4. Members table (PK: accountId, SK: groupId#memberArn, GSIs: member-groups-index, member-accounts-index)
 create_table "rosa-authz-group-members" \
+    --stream-specification "$AUTHZ_STREAM" \
     --attribute-definitions \
         AttributeName=accountId,AttributeType=S \
         'AttributeName=groupId#memberArn,AttributeType=S' \
         'AttributeName=accountId#memberArn,AttributeType=S' \
         AttributeName=groupId,AttributeType=S \
+        AttributeName=memberArn,AttributeType=S \
     --key-schema \
         AttributeName=accountId,KeyType=HASH \
         'AttributeName=groupId#memberArn,KeyType=RANGE' \
@@ -84,6 +101,203 @@ create_table "rosa-authz-group-members" \
                 {"AttributeName": "groupId", "KeyType": "RANGE"}
             ],
             "Projection": {"ProjectionType": "ALL"}
+        },
+        {
+            "IndexName": "member-accounts-index",
+            "KeySchema": [
+                {"AttributeName": "memberArn", "KeyType": "HASH"},
+                {"AttributeName": "accountId", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
+        }]'
+
+# 5. Management cluster registry (PK: consumerId, GSI: account-index)
+create_table "rosa-management-clusters" \
+    --attribute-definitions \
+        AttributeName=consumerId,AttributeType=S \
+        AttributeName=accountId,AttributeType=S \
+    --key-schema AttributeName=consumerId,KeyType=HASH \
+    --global-secondary-indexes \
+        '[{
+            "IndexName": "account-index",
+            "KeySchema": [
+                {"AttributeName": "accountId", "KeyType": "HASH"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
+        }]'
+
+# 6. Work metadata (PK: clusterId, SK: workName, GSIs: dedup-index on
+#    tenantAccountId#clusterId#contentHash, tenant-index)
+create_table "rosa-work-metadata" \
+    --attribute-definitions \
+        AttributeName=clusterId,AttributeType=S \
+        AttributeName=workName,AttributeType=S \
+        AttributeName=dedupKey,AttributeType=S \
+        AttributeName=tenantAccountId,AttributeType=S \
+        AttributeName=createdAt,AttributeType=S \
+    --key-schema \
+        AttributeName=clusterId,KeyType=HASH \
+        AttributeName=workName,KeyType=RANGE \
+    --global-secondary-indexes \
+        '[{
+            "IndexName": "dedup-index",
+            "KeySchema": [
+                {"AttributeName": "dedupKey", "KeyType": "HASH"},
+                {"AttributeName": "createdAt", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
+        }, {
+            "IndexName": "tenant-index",
+            "KeySchema": [
+                {"AttributeName": "tenantAccountId", "KeyType": "HASH"},
+                {"AttributeName": "createdAt", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
+        }]'
+
+# 7. Delegations (PK: accountId, SK: delegateAccountId)
+create_table "rosa-authz-delegations" \
+    --stream-specification "$AUTHZ_STREAM" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=delegateAccountId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=delegateAccountId,KeyType=RANGE
+
+# 7a. API keys (PK: keyId, GSI: account-index)
+create_table "rosa-authz-api-keys" \
+    --attribute-definitions \
+        AttributeName=keyId,AttributeType=S \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=createdAt,AttributeType=S \
+        AttributeName=changeDay,AttributeType=S \
+        AttributeName=changedAt,AttributeType=S \
+    --key-schema AttributeName=keyId,KeyType=HASH \
+    --global-secondary-indexes \
+        '[{
+            "IndexName": "account-index",
+            "KeySchema": [
+                {"AttributeName": "accountId", "KeyType": "HASH"},
+                {"AttributeName": "createdAt", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
+        }, {
+            "IndexName": "changes-index",
+            "KeySchema": [
+                {"AttributeName": "changeDay", "KeyType": "HASH"},
+                {"AttributeName": "changedAt", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "KEYS_ONLY"}
+        }]'
+
+# 8. Organizations (PK: organizationId)
+create_table "rosa-authz-organizations" \
+    --attribute-definitions AttributeName=organizationId,AttributeType=S \
+    --key-schema AttributeName=organizationId,KeyType=HASH
+
+# 9. Attachment metadata (PK: accountId, SK: attachmentId)
+create_table "rosa-authz-attachments" \
+    --stream-specification "$AUTHZ_STREAM" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=attachmentId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=attachmentId,KeyType=RANGE
+
+# 10. Deletion tombstones (PK: accountId, SK: deletionId, TTL: expiresAt)
+create_table "rosa-authz-deletions" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=deletionId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=deletionId,KeyType=RANGE
+
+# 11. Pending changes (PK: accountId, SK: changeId, TTL: expiresAt)
+create_table "rosa-authz-pending-changes" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=changeId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=changeId,KeyType=RANGE
+
+# 12. Change requests (PK: accountId, SK: changeId, TTL: expiresAt)
+create_table "rosa-authz-change-requests" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=changeId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=changeId,KeyType=RANGE
+
+# 13. Notification settings (PK: accountId)
+create_table "rosa-notification-settings" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH
+
+# 14. Work schedules (PK: accountId, SK: scheduleId)
+create_table "rosa-work-schedules" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=scheduleId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=scheduleId,KeyType=RANGE
+
+# 15. Leader election leases (PK: worker)
+create_table "rosa-leases" \
+    --attribute-definitions \
+        AttributeName=worker,AttributeType=S \
+    --key-schema \
+        AttributeName=worker,KeyType=HASH
+
+# 16. Rate limit counters (PK: key, TTL: expiresAt)
+create_table "rosa-rate-limits" \
+    --attribute-definitions \
+        AttributeName=key,AttributeType=S \
+    --key-schema \
+        AttributeName=key,KeyType=HASH
+
+# 17. Hourly authorization decision counts (PK: accountId, SK: bucketId, TTL: expiresAt)
+create_table "rosa-authz-decision-counts" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=bucketId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=bucketId,KeyType=RANGE
+
+# 18. Policy tags (PK: accountId, SK: policyId)
+create_table "rosa-authz-policy-tags" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=policyId,AttributeType=S \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=policyId,KeyType=RANGE
+
+# 19. Decision audit log (PK: accountId, SK: entryId, GSI: principal-index, TTL: expiresAt)
+create_table "rosa-authz-decision-audit" \
+    --attribute-definitions \
+        AttributeName=accountId,AttributeType=S \
+        AttributeName=entryId,AttributeType=S \
+        'AttributeName=accountId#principalArn,AttributeType=S' \
+    --key-schema \
+        AttributeName=accountId,KeyType=HASH \
+        AttributeName=entryId,KeyType=RANGE \
+    --global-secondary-indexes \
+        '[{
+            "IndexName": "principal-index",
+            "KeySchema": [
+                {"AttributeName": "accountId#principalArn", "KeyType": "HASH"},
+                {"AttributeName": "entryId", "KeyType": "RANGE"}
+            ],
+            "Projection": {"ProjectionType": "ALL"}
         }]'
 
 # Seed privileged account for e2e testing
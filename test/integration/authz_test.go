@@ -0,0 +1,61 @@
+//go:build integration
+
+package integration
+
+import (
+	"os"
+	"testing"
+
+	awstest "github.com/openshift/rosa-regional-platform-api/internal/test/aws"
+)
+
+func TestAuthz_GroupPolicyDecisions(t *testing.T) {
+	accountID, client := newTenant(t)
+
+	policy, err := os.ReadFile("../../pkg/authz/testdata/policies/01-basic-access/list-and-describe-all.cedar")
+	if err != nil {
+		t.Fatalf("failed to read policy: %v", err)
+	}
+	policyID, err := client.CreatePolicy(accountID, "list-and-describe", "List and describe all clusters", string(policy))
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	groupID, err := client.CreateGroup(accountID, "viewers", "Cluster viewers")
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	member := "arn:aws:iam::" + accountID + ":user/viewer"
+	if err := client.AddGroupMembers(accountID, groupID, []string{member}); err != nil {
+		t.Fatalf("failed to add group member: %v", err)
+	}
+	if _, err := client.CreateAttachment(accountID, policyID, "group", groupID); err != nil {
+		t.Fatalf("failed to attach policy: %v", err)
+	}
+
+	cluster := "arn:aws:rosa:us-east-1:" + accountID + ":cluster/my-cluster"
+	tests := []struct {
+		name      string
+		principal string
+		action    string
+		expected  string
+	}{
+		{"member can describe", member, "DescribeCluster", "ALLOW"},
+		{"member cannot create", member, "CreateCluster", "DENY"},
+		{"outsider cannot describe", "arn:aws:iam::" + accountID + ":user/outsider", "DescribeCluster", "DENY"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := client.CheckAuthorization(accountID, awstest.CheckAuthorizationRequest{
+				Principal: tt.principal,
+				Action:    tt.action,
+				Resource:  cluster,
+			})
+			if err != nil {
+				t.Fatalf("failed to check authorization: %v", err)
+			}
+			if decision != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, decision)
+			}
+		})
+	}
+}
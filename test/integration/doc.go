@@ -0,0 +1,8 @@
+// Package integration runs the server in-process against DynamoDB Local and
+// cedar-agent containers and a Maestro stub, and exercises the authz and
+// work flows through the API. Unlike the e2e suites it needs no cloud
+// account, only a container runtime testcontainers-go can reach. The tests
+// are behind the integration build tag:
+//
+//	make test-integration
+package integration
@@ -0,0 +1,281 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	dynamodbtypes "github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/network"
+	"github.com/testcontainers/testcontainers-go/wait"
+
+	awstest "github.com/openshift/rosa-regional-platform-api/internal/test/aws"
+	maestrotest "github.com/openshift/rosa-regional-platform-api/internal/test/maestro"
+	"github.com/openshift/rosa-regional-platform-api/pkg/config"
+	"github.com/openshift/rosa-regional-platform-api/pkg/server"
+)
+
+const (
+	dynamoDBImage   = "docker.io/amazon/dynamodb-local:latest"
+	awsCLIImage     = "docker.io/amazon/aws-cli:latest"
+	cedarAgentImage = "docker.io/permitio/cedar-agent:latest"
+
+	// privilegedAccountID is seeded as privileged by the table init script
+	privilegedAccountID = "000000000000"
+	region              = "us-east-1"
+)
+
+var (
+	// baseURL is the URL of the API server under test
+	baseURL string
+	// dynamoDBEndpoint is the URL of DynamoDB Local
+	dynamoDBEndpoint string
+	// maestroStub receives the works the server submits
+	maestroStub *maestrotest.Stub
+)
+
+func TestMain(m *testing.M) {
+	os.Exit(run(m))
+}
+
+// run starts the dependencies and the server, runs the tests and tears
+// everything down again
+func run(m *testing.M) int {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// DynamoDB Local credentials only need to be present
+	os.Setenv("AWS_ACCESS_KEY_ID", "dummy")
+	os.Setenv("AWS_SECRET_ACCESS_KEY", "dummy")
+	os.Setenv("AWS_REGION", region)
+
+	cedarAgentEndpoint, cleanup, err := startContainers(ctx)
+	defer cleanup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start containers: %v\n", err)
+		return 1
+	}
+
+	maestroStub, err = maestrotest.NewStub()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start the Maestro stub: %v\n", err)
+		return 1
+	}
+	defer maestroStub.Close()
+
+	stop, err := startServer(ctx, cedarAgentEndpoint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start the server: %v\n", err)
+		return 1
+	}
+	defer stop()
+
+	return m.Run()
+}
+
+// startContainers starts DynamoDB Local, creates its tables with the e2e init
+// script, and starts cedar-agent. It returns the cedar-agent endpoint and a
+// function terminating whatever was started.
+func startContainers(ctx context.Context) (string, func(), error) {
+	var containers []testcontainers.Container
+	var nw *testcontainers.DockerNetwork
+	cleanup := func() {
+		for _, c := range containers {
+			_ = testcontainers.TerminateContainer(c)
+		}
+		if nw != nil {
+			_ = nw.Remove(context.Background())
+		}
+	}
+
+	nw, err := network.New(ctx)
+	if err != nil {
+		return "", cleanup, fmt.Errorf("failed to create network: %w", err)
+	}
+
+	dynamo, err := testcontainers.Run(ctx, dynamoDBImage,
+		testcontainers.WithCmd("-jar", "DynamoDBLocal.jar", "-sharedDb", "-inMemory"),
+		testcontainers.WithExposedPorts("8000/tcp"),
+		network.WithNetwork([]string{"dynamodb"}, nw),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("8000/tcp")),
+	)
+	if dynamo != nil {
+		containers = append(containers, dynamo)
+	}
+	if err != nil {
+		return "", cleanup, fmt.Errorf("failed to start DynamoDB Local: %w", err)
+	}
+	dynamoDBEndpoint, err = dynamo.PortEndpoint(ctx, "8000/tcp", "http")
+	if err != nil {
+		return "", cleanup, err
+	}
+
+	// The tables are those the e2e suites use, so the script stays their
+	// single definition
+	initTables, err := testcontainers.Run(ctx, awsCLIImage,
+		testcontainers.WithEntrypoint("bash", "/init-dynamodb.sh"),
+		testcontainers.WithEnv(map[string]string{
+			"DYNAMODB_ENDPOINT": "http://dynamodb:8000",
+			"AWS_REGION":        region,
+		}),
+		testcontainers.WithFiles(testcontainers.ContainerFile{
+			HostFilePath:      "../../scripts/e2e-init-dynamodb.sh",
+			ContainerFilePath: "/init-dynamodb.sh",
+			FileMode:          0o755,
+		}),
+		network.WithNetwork(nil, nw),
+		testcontainers.WithWaitStrategy(wait.ForExit().WithExitTimeout(2*time.Minute)),
+	)
+	if initTables != nil {
+		containers = append(containers, initTables)
+	}
+	if err != nil {
+		return "", cleanup, fmt.Errorf("failed to create DynamoDB tables: %w", err)
+	}
+	state, err := initTables.State(ctx)
+	if err != nil {
+		return "", cleanup, err
+	}
+	if state.ExitCode != 0 {
+		return "", cleanup, fmt.Errorf("DynamoDB table init script exited with %d", state.ExitCode)
+	}
+
+	cedarAgent, err := testcontainers.Run(ctx, cedarAgentImage,
+		testcontainers.WithExposedPorts("8180/tcp"),
+		testcontainers.WithWaitStrategy(wait.ForListeningPort("8180/tcp")),
+	)
+	if cedarAgent != nil {
+		containers = append(containers, cedarAgent)
+	}
+	if err != nil {
+		return "", cleanup, fmt.Errorf("failed to start cedar-agent: %w", err)
+	}
+	cedarAgentEndpoint, err := cedarAgent.PortEndpoint(ctx, "8180/tcp", "http")
+	if err != nil {
+		return "", cleanup, err
+	}
+
+	return cedarAgentEndpoint, cleanup, nil
+}
+
+// startServer runs the server on free loopback ports until the returned
+// function is called, and waits for it to be ready
+func startServer(ctx context.Context, cedarAgentEndpoint string) (func(), error) {
+	cfg := config.NewConfig()
+	for _, port := range []*int{&cfg.Server.APIPort, &cfg.Server.HealthPort, &cfg.Server.MetricsPort, &cfg.Server.GRPCPort} {
+		p, err := freePort()
+		if err != nil {
+			return nil, err
+		}
+		*port = p
+	}
+	cfg.Server.APIBindAddress = "127.0.0.1"
+	cfg.Server.HealthBindAddress = "127.0.0.1"
+	cfg.Server.MetricsBindAddress = "127.0.0.1"
+	cfg.Server.GRPCBindAddress = "127.0.0.1"
+	cfg.Maestro.BaseURL = maestroStub.BaseURL()
+	cfg.Maestro.GRPCBaseURL = maestroStub.GRPCBaseURL()
+	cfg.Authz.AWSRegion = region
+	cfg.Authz.DynamoDBEndpoint = dynamoDBEndpoint
+	cfg.Authz.CedarAgentEndpoint = cedarAgentEndpoint
+	cfg.Work.AWSRegion = region
+	cfg.Work.DynamoDBEndpoint = dynamoDBEndpoint
+	cfg.Work.MetadataTableName = "rosa-work-metadata"
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	srv, err := server.New(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan error, 1)
+	go func() { done <- srv.Run(ctx) }()
+	stop := func() {
+		cancel()
+		<-done
+	}
+
+	baseURL = fmt.Sprintf("http://127.0.0.1:%d", cfg.Server.APIPort)
+	client := awstest.NewAPIClient(baseURL)
+	deadline := time.Now().Add(time.Minute)
+	for {
+		err := client.CheckReady()
+		if err == nil {
+			return stop, nil
+		}
+		select {
+		case err := <-done:
+			cancel()
+			return nil, fmt.Errorf("server exited: %w", err)
+		case <-time.After(250 * time.Millisecond):
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return nil, fmt.Errorf("server not ready: %w", err)
+		}
+	}
+}
+
+func freePort() (int, error) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer lis.Close()
+	return lis.Addr().(*net.TCPAddr).Port, nil
+}
+
+// newTenant enables a new account and makes adminARN its admin, returning the
+// account ID and a client calling as the admin
+func newTenant(t *testing.T) (string, *awstest.APIClient) {
+	t.Helper()
+	accountID := fmt.Sprintf("1%011d", rand.Int64N(1e11))
+	adminARN := "arn:aws:iam::" + accountID + ":role/integration-admin"
+
+	client := awstest.NewAPIClient(baseURL)
+	if _, err := client.CreateAccount(privilegedAccountID, accountID, false); err != nil {
+		t.Fatalf("failed to enable account %s: %v", accountID, err)
+	}
+	// The first admin cannot be added through the API, which only admins
+	// may call
+	if err := seedAdmin(context.Background(), accountID, adminARN); err != nil {
+		t.Fatalf("failed to seed admin of %s: %v", accountID, err)
+	}
+	client.CallerARN = adminARN
+	return accountID, client
+}
+
+func seedAdmin(ctx context.Context, accountID, principalARN string) error {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return err
+	}
+	client := dynamodb.NewFromConfig(cfg, func(o *dynamodb.Options) {
+		o.BaseEndpoint = aws.String(dynamoDBEndpoint)
+	})
+	_, err = client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String("rosa-authz-admins"),
+		Item: map[string]dynamodbtypes.AttributeValue{
+			"accountId":    &dynamodbtypes.AttributeValueMemberS{Value: accountID},
+			"principalArn": &dynamodbtypes.AttributeValueMemberS{Value: principalARN},
+			"createdAt":    &dynamodbtypes.AttributeValueMemberS{Value: time.Now().UTC().Format(time.RFC3339)},
+			"createdBy":    &dynamodbtypes.AttributeValueMemberS{Value: "integration-test-seed"},
+		},
+	})
+	return err
+}
@@ -0,0 +1,95 @@
+//go:build integration
+
+package integration
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/openshift/rosa-regional-platform-api/pkg/migrate"
+)
+
+func TestWork_SubmitAndList(t *testing.T) {
+	accountID, client := newTenant(t)
+	const cluster = "mc-integration"
+	maestroStub.AddConsumer(cluster)
+
+	policyID, err := client.CreatePolicy(accountID, "deployers", "Submit any work", migrate.PermissivePolicy)
+	if err != nil {
+		t.Fatalf("failed to create policy: %v", err)
+	}
+	groupID, err := client.CreateGroup(accountID, "deployers", "Work deployers")
+	if err != nil {
+		t.Fatalf("failed to create group: %v", err)
+	}
+	deployer := "arn:aws:iam::" + accountID + ":role/deployer"
+	if err := client.AddGroupMembers(accountID, groupID, []string{deployer}); err != nil {
+		t.Fatalf("failed to add group member: %v", err)
+	}
+	if _, err := client.CreateAttachment(accountID, policyID, "group", groupID); err != nil {
+		t.Fatalf("failed to attach policy: %v", err)
+	}
+
+	work := map[string]interface{}{
+		"cluster_id": cluster,
+		"data": map[string]interface{}{
+			"apiVersion": "work.open-cluster-management.io/v1",
+			"kind":       "ManifestWork",
+			"metadata":   map[string]interface{}{"name": "integration-app"},
+			"spec": map[string]interface{}{
+				"workload": map[string]interface{}{
+					"manifests": []interface{}{map[string]interface{}{
+						"apiVersion": "v1",
+						"kind":       "ConfigMap",
+						"metadata":   map[string]interface{}{"name": "integration-app", "namespace": "default"},
+						"data":       map[string]interface{}{"key": "value"},
+					}},
+				},
+			},
+		},
+	}
+
+	t.Run("outsider is forbidden", func(t *testing.T) {
+		client.CallerARN = "arn:aws:iam::" + accountID + ":role/outsider"
+		resp, err := client.Post("/api/v0/work", work, accountID)
+		if err != nil {
+			t.Fatalf("failed to submit work: %v", err)
+		}
+		if resp.StatusCode != http.StatusForbidden {
+			t.Errorf("expected status 403, got %d: %s", resp.StatusCode, resp.Body)
+		}
+		if bundles := maestroStub.ResourceBundles(cluster); len(bundles) != 0 {
+			t.Errorf("expected nothing published, got %+v", bundles)
+		}
+	})
+
+	t.Run("deployer submits", func(t *testing.T) {
+		client.CallerARN = deployer
+		resp, err := client.Post("/api/v0/work", work, accountID)
+		if err != nil {
+			t.Fatalf("failed to submit work: %v", err)
+		}
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected status 201, got %d: %s", resp.StatusCode, resp.Body)
+		}
+		bundles := maestroStub.ResourceBundles(cluster)
+		if len(bundles) != 1 || bundles[0].Name != "integration-app" {
+			t.Fatalf("expected the work published to Maestro, got %+v", bundles)
+		}
+	})
+
+	t.Run("deployer lists", func(t *testing.T) {
+		client.CallerARN = deployer
+		resp, err := client.Get("/api/v0/work", accountID)
+		if err != nil {
+			t.Fatalf("failed to list work: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status 200, got %d: %s", resp.StatusCode, resp.Body)
+		}
+		if !strings.Contains(string(resp.Body), "integration-app") {
+			t.Errorf("expected the submitted work listed, got %s", resp.Body)
+		}
+	})
+}